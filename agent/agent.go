@@ -1,5 +1,7 @@
 package agent
 
+import "time"
+
 type (
 	// ClusterMember is the representation of an agent inside a cluster.
 	ClusterMember struct {
@@ -39,6 +41,13 @@ type (
 		PhysicalDisks []PhysicalDisk
 	}
 
+	// HostActionResult is the representation of the output and of the exit code of a command
+	// run on the host by a host action
+	HostActionResult struct {
+		Output   string `json:"Output"`
+		ExitCode int    `json:"ExitCode"`
+	}
+
 	// KubernetesRuntimeConfiguration represents the runtime configuration of an agent running on the Kubernetes platform
 	KubernetesRuntimeConfiguration struct{}
 
@@ -168,6 +177,11 @@ type (
 		GetPciDevices() ([]PciDevice, error)
 	}
 
+	// HostActionService is used to run the commands of the host actions on the host
+	HostActionService interface {
+		Run(command []string, timeout time.Duration) (*HostActionResult, error)
+	}
+
 	// TLSService is used to create TLS certificates to use enable HTTPS.
 	TLSService interface {
 		GenerateCertsForHost(host string) error
@@ -218,6 +232,9 @@ const (
 	HTTPResponseAgentHeaderName = "Portainer-Agent"
 	// HTTPKubernetesSATokenHeaderName represent the name of the header containing a Kubernetes SA token
 	HTTPKubernetesSATokenHeaderName = "X-PortainerAgent-SA-Token"
+	// HTTPHostBrowserPathsHeaderName is the name of the header containing the comma separated list
+	// of the host paths that can be browsed.
+	HTTPHostBrowserPathsHeaderName = "X-PortainerAgent-HostBrowserPaths"
 	// HTTPResponseAgentApiVersion is the name of the header that will have the
 	// Portainer Agent API Version.
 	HTTPResponseAgentApiVersion = "Portainer-Agent-API-Version"
@@ -237,6 +254,12 @@ const (
 	HostRoot = "/host"
 	// DataDirectory is the folder where the data associated to the agent is persisted.
 	DataDirectory = "/data"
+	// DefaultHostActionTimeout is the default timeout of a host action, used when no timeout is specified.
+	DefaultHostActionTimeout = 30 * time.Second
+	// MaxHostActionTimeout is the maximum timeout of a host action. It is kept below the write timeout of the API server.
+	MaxHostActionTimeout = 100 * time.Second
+	// HostActionOutputLimit is the maximum size (in bytes) of the output of a host action, the output is truncated after it.
+	HostActionOutputLimit = 1024 * 1024
 	// ScheduleScriptDirectory is the folder where schedules are saved on the host
 	ScheduleScriptDirectory = "/opt/portainer/scripts"
 	// EdgeKeyFile is the name of the file used to persist the Edge key associated to the agent.
@@ -8,6 +8,7 @@ import (
 	"github.com/portainer/agent"
 	"github.com/portainer/agent/crypto"
 	"github.com/portainer/agent/docker"
+	"github.com/portainer/agent/exec"
 	"github.com/portainer/agent/filesystem"
 	"github.com/portainer/agent/ghw"
 	"github.com/portainer/agent/http"
@@ -31,6 +32,7 @@ func main() {
 	logutils.SetupLogger(options.LogLevel)
 
 	systemService := ghw.NewSystemService(agent.HostRoot)
+	hostActionService := exec.NewHostActionService(agent.HostRoot, agent.HostActionOutputLimit)
 	containerPlatform := os.DetermineContainerPlatform()
 	runtimeConfiguration := &agent.RuntimeConfiguration{
 		AgentPort: options.AgentServerPort,
@@ -208,6 +210,7 @@ func main() {
 		Addr:                 options.AgentServerAddr,
 		Port:                 options.AgentServerPort,
 		SystemService:        systemService,
+		HostActionService:    hostActionService,
 		ClusterService:       clusterService,
 		EdgeManager:          edgeManager,
 		SignatureService:     signatureService,
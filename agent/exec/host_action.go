@@ -0,0 +1,104 @@
+// +build !windows
+
+package exec
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"syscall"
+	"time"
+
+	"github.com/portainer/agent"
+)
+
+// HostActionService represents a service for running the commands of the host actions.
+// The commands are run inside a chroot of the host filesystem mounted in the agent container.
+type HostActionService struct {
+	rootPath    string
+	outputLimit int
+}
+
+// NewHostActionService initializes a new HostActionService service.
+// The commands are run without chroot when rootPath is empty.
+func NewHostActionService(rootPath string, outputLimit int) *HostActionService {
+	return &HostActionService{
+		rootPath:    rootPath,
+		outputLimit: outputLimit,
+	}
+}
+
+// Run executes the command without interpreting it with a shell and returns its combined output.
+// The command and the processes it started are killed when the timeout expires and the output
+// is truncated after the output limit of the service.
+func (service *HostActionService) Run(command []string, timeout time.Duration) (*agent.HostActionResult, error) {
+	if len(command) == 0 || command[0] == "" {
+		return nil, errors.New("Empty host action command")
+	}
+
+	name, args := command[0], command[1:]
+	if service.rootPath != "" {
+		name, args = "chroot", append([]string{service.rootPath}, command...)
+	}
+
+	output := &limitedBuffer{limit: service.outputLimit}
+	cmd := exec.Command(name, args...)
+	cmd.Stdout = output
+	cmd.Stderr = output
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+
+	err := cmd.Start()
+	if err != nil {
+		return nil, err
+	}
+
+	killed := make(chan struct{})
+	timer := time.AfterFunc(timeout, func() {
+		syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+		close(killed)
+	})
+
+	err = cmd.Wait()
+	if !timer.Stop() {
+		<-killed
+		return nil, fmt.Errorf("The host action did not complete within %s", timeout)
+	}
+
+	exitCode := 0
+	if err != nil {
+		exitErr, ok := err.(*exec.ExitError)
+		if !ok {
+			return nil, err
+		}
+		exitCode = exitErr.ExitCode()
+	}
+
+	return &agent.HostActionResult{
+		Output:   output.String(),
+		ExitCode: exitCode,
+	}, nil
+}
+
+// limitedBuffer is a buffer that discards the data written after its limit.
+// The writes never fail so that the command is not interrupted by a broken pipe.
+type limitedBuffer struct {
+	buffer bytes.Buffer
+	limit  int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	remaining := b.limit - b.buffer.Len()
+	if remaining > 0 {
+		if len(p) > remaining {
+			b.buffer.Write(p[:remaining])
+		} else {
+			b.buffer.Write(p)
+		}
+	}
+	return len(p), nil
+}
+
+func (b *limitedBuffer) String() string {
+	return b.buffer.String()
+}
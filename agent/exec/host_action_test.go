@@ -0,0 +1,59 @@
+// +build !windows
+
+package exec
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHostActionServiceRun(t *testing.T) {
+	service := NewHostActionService("", 1024)
+
+	result, err := service.Run([]string{"echo", "hello", "$HOME;", "id"}, time.Second)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if result.Output != "hello $HOME; id\n" || result.ExitCode != 0 {
+		t.Errorf("the command must run without shell interpretation, got %q (exit code %d)", result.Output, result.ExitCode)
+	}
+
+	result, err = service.Run([]string{"false"}, time.Second)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if result.ExitCode != 1 {
+		t.Errorf("expected exit code 1, got %d", result.ExitCode)
+	}
+
+	_, err = service.Run([]string{}, time.Second)
+	if err == nil {
+		t.Error("expected an error for an empty command")
+	}
+}
+
+func TestHostActionServiceRunTimeout(t *testing.T) {
+	service := NewHostActionService("", 1024)
+
+	start := time.Now()
+	_, err := service.Run([]string{"sleep", "10"}, 100*time.Millisecond)
+	if err == nil {
+		t.Fatal("expected an error for a command exceeding the timeout")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("the command was not killed after the timeout, it ran for %s", elapsed)
+	}
+}
+
+func TestHostActionServiceRunOutputLimit(t *testing.T) {
+	service := NewHostActionService("", 10)
+
+	result, err := service.Run([]string{"seq", "1", "100000"}, 5*time.Second)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(result.Output) != 10 || !strings.HasPrefix(result.Output, "1\n2\n") {
+		t.Errorf("expected the output to be truncated to 10 bytes, got %q", result.Output)
+	}
+}
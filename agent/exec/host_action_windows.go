@@ -0,0 +1,24 @@
+// +build windows
+
+package exec
+
+import (
+	"errors"
+	"time"
+
+	"github.com/portainer/agent"
+)
+
+// HostActionService represents a service for running the commands of the host actions.
+// Host actions are not supported on Windows.
+type HostActionService struct{}
+
+// NewHostActionService initializes a new HostActionService service.
+func NewHostActionService(rootPath string, outputLimit int) *HostActionService {
+	return &HostActionService{}
+}
+
+// Run always returns an error as host actions are not supported on Windows.
+func (service *HostActionService) Run(command []string, timeout time.Duration) (*agent.HostActionResult, error) {
+	return nil, errors.New("Host actions are not supported on Windows")
+}
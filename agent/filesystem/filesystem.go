@@ -5,6 +5,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -126,6 +127,54 @@ func BuildPathToFileInsideVolume(volumeID, filePath string) (string, error) {
 	return path.Join(constants.SystemVolumePath, volumeID, "_data", filePath), nil
 }
 
+// ResolvePathInsideDirectories resolves the symbolic links of a path and ensures that the resolved path
+// is one of the allowed directories or is located under one of them. It returns the resolved path.
+func ResolvePathInsideDirectories(filePath string, allowedPaths []string) (string, error) {
+	resolvedPath, err := filepath.EvalSymlinks(filePath)
+	if err != nil {
+		return "", err
+	}
+
+	resolvedPath, err = filepath.Abs(resolvedPath)
+	if err != nil {
+		return "", err
+	}
+
+	for _, allowedPath := range allowedPaths {
+		if strings.TrimSpace(allowedPath) == "" {
+			continue
+		}
+
+		resolvedAllowedPath, err := filepath.EvalSymlinks(strings.TrimSpace(allowedPath))
+		if err != nil {
+			continue
+		}
+
+		resolvedAllowedPath, err = filepath.Abs(resolvedAllowedPath)
+		if err != nil {
+			continue
+		}
+
+		if isPathInsideDirectory(resolvedPath, resolvedAllowedPath) {
+			return resolvedPath, nil
+		}
+	}
+
+	return "", errors.New("Access denied. The path is not located inside an allowed host path")
+}
+
+func isPathInsideDirectory(filePath, directory string) bool {
+	if filePath == directory {
+		return true
+	}
+
+	if !strings.HasSuffix(directory, string(filepath.Separator)) {
+		directory += string(filepath.Separator)
+	}
+
+	return strings.HasPrefix(filePath, directory)
+}
+
 func isValidPath(path string) bool {
 	if containsDotDot(path) {
 		return false
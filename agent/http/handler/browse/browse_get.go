@@ -26,6 +26,11 @@ func (handler *Handler) browseGet(rw http.ResponseWriter, r *http.Request) *http
 		if err != nil {
 			return &httperror.HandlerError{http.StatusBadRequest, "Invalid volume", err}
 		}
+	} else {
+		path, err = handler.resolveHostPath(r, path)
+		if err != nil {
+			return &httperror.HandlerError{http.StatusForbidden, "Access denied to the specified host path", err}
+		}
 	}
 
 	fileDetails, err := filesystem.OpenFile(path)
@@ -27,6 +27,11 @@ func (handler *Handler) browseList(rw http.ResponseWriter, r *http.Request) *htt
 		if err != nil {
 			return &httperror.HandlerError{http.StatusBadRequest, "Invalid volume", err}
 		}
+	} else {
+		path, err = handler.resolveHostPath(r, path)
+		if err != nil {
+			return &httperror.HandlerError{http.StatusForbidden, "Access denied to the specified host path", err}
+		}
 	}
 
 	files, err := filesystem.ListFilesInsideDirectory(path)
@@ -1,10 +1,13 @@
 package browse
 
 import (
+	"errors"
 	"net/http"
+	"strings"
 
 	"github.com/gorilla/mux"
 	"github.com/portainer/agent"
+	"github.com/portainer/agent/filesystem"
 	"github.com/portainer/agent/http/proxy"
 	"github.com/portainer/agent/http/security"
 	httperror "github.com/portainer/libhttp/error"
@@ -56,3 +59,14 @@ func NewHandlerV1(agentProxy *proxy.AgentProxy, notaryService *security.NotarySe
 		notaryService.DigitalSignatureVerification(agentProxy.Redirect(httperror.LoggerHandler(h.browsePutV1)))).Methods(http.MethodPost)
 	return h
 }
+
+// resolveHostPath resolves the symbolic links of a host path and ensures that the resolved path is located
+// inside one of the host paths sent by the Portainer instance. The request is rejected when no path is sent.
+func (handler *Handler) resolveHostPath(r *http.Request, hostPath string) (string, error) {
+	allowedPaths := r.Header.Get(agent.HTTPHostBrowserPathsHeaderName)
+	if allowedPaths == "" {
+		return "", errors.New("No allowed host path specified")
+	}
+
+	return filesystem.ResolvePathInsideDirectories(hostPath, strings.Split(allowedPaths, ","))
+}
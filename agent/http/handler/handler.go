@@ -44,6 +44,7 @@ type Handler struct {
 // used to create a new handler
 type Config struct {
 	SystemService        agent.SystemService
+	HostActionService    agent.HostActionService
 	ClusterService       agent.ClusterService
 	SignatureService     agent.DigitalSignatureService
 	KubeClient           *kubecli.KubeClient
@@ -69,7 +70,7 @@ func NewHandler(config *Config) *Handler {
 		keyHandler:             key.NewHandler(notaryService, config.EdgeManager),
 		kubernetesProxyHandler: kubernetes.NewHandler(notaryService),
 		webSocketHandler:       websocket.NewHandler(config.ClusterService, config.RuntimeConfiguration, notaryService, config.KubeClient),
-		hostHandler:            host.NewHandler(config.SystemService, agentProxy, notaryService),
+		hostHandler:            host.NewHandler(config.SystemService, config.HostActionService, agentProxy, notaryService, config.AgentOptions),
 		pingHandler:            ping.NewHandler(),
 		securedProtocol:        config.Secured,
 		edgeManager:            config.EdgeManager,
@@ -14,18 +14,24 @@ import (
 // Handler represents an HTTP API Handler for host specific actions
 type Handler struct {
 	*mux.Router
-	systemService agent.SystemService
+	systemService     agent.SystemService
+	hostActionService agent.HostActionService
+	agentOptions      *agent.Options
 }
 
 // NewHandler returns a new instance of Handler
-func NewHandler(systemService agent.SystemService, agentProxy *proxy.AgentProxy, notaryService *security.NotaryService) *Handler {
+func NewHandler(systemService agent.SystemService, hostActionService agent.HostActionService, agentProxy *proxy.AgentProxy, notaryService *security.NotaryService, agentOptions *agent.Options) *Handler {
 	h := &Handler{
-		Router:        mux.NewRouter(),
-		systemService: systemService,
+		Router:            mux.NewRouter(),
+		systemService:     systemService,
+		hostActionService: hostActionService,
+		agentOptions:      agentOptions,
 	}
 
 	h.Handle("/host/info",
 		agentProxy.Redirect(notaryService.DigitalSignatureVerification(httperror.LoggerHandler(h.hostInfo)))).Methods(http.MethodGet)
+	h.Handle("/host/actions/run",
+		notaryService.DigitalSignatureVerification(agentProxy.Redirect(httperror.LoggerHandler(h.hostActionRun)))).Methods(http.MethodPost)
 
 	return h
 }
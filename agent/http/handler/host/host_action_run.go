@@ -0,0 +1,69 @@
+package host
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/portainer/agent"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+)
+
+type hostActionRunPayload struct {
+	// Command is the executable and its arguments, it is not interpreted by a shell
+	Command []string
+	// Timeout is the number of seconds after which the command is killed
+	Timeout int
+}
+
+func (payload *hostActionRunPayload) Validate(r *http.Request) error {
+	if len(payload.Command) == 0 || payload.Command[0] == "" {
+		return errors.New("Invalid command")
+	}
+	if payload.Timeout < 0 {
+		return errors.New("Invalid timeout")
+	}
+	return nil
+}
+
+// POST request on /host/actions/run
+//
+// Runs the command of a host action on the host and returns its output, truncated after
+// agent.HostActionOutputLimit bytes. The command is killed when the timeout expires.
+func (handler *Handler) hostActionRun(rw http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	if !handler.agentOptions.HostManagementEnabled {
+		return &httperror.HandlerError{http.StatusServiceUnavailable, "Host management capability disabled", errors.New("This agent feature is not enabled")}
+	}
+
+	var payload hostActionRunPayload
+	err := request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	result, err := handler.hostActionService.Run(payload.Command, hostActionTimeout(payload.Timeout))
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to run the host action", err}
+	}
+
+	log.Printf("[INFO] [http,host] [message: Host action executed] [command: %s] [exit_code: %d]", payload.Command[0], result.ExitCode)
+
+	return response.JSON(rw, result)
+}
+
+// hostActionTimeout returns the timeout of a host action, the default timeout is used when no timeout is specified
+// and the timeout cannot exceed agent.MaxHostActionTimeout
+func hostActionTimeout(seconds int) time.Duration {
+	if seconds == 0 {
+		return agent.DefaultHostActionTimeout
+	}
+
+	timeout := time.Duration(seconds) * time.Second
+	if timeout > agent.MaxHostActionTimeout {
+		return agent.MaxHostActionTimeout
+	}
+	return timeout
+}
@@ -0,0 +1,99 @@
+package host
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/portainer/agent"
+)
+
+type testHostActionService struct {
+	command []string
+	timeout time.Duration
+}
+
+func (service *testHostActionService) Run(command []string, timeout time.Duration) (*agent.HostActionResult, error) {
+	service.command = command
+	service.timeout = timeout
+	return &agent.HostActionResult{Output: "ok\n", ExitCode: 3}, nil
+}
+
+func TestHostActionRun(t *testing.T) {
+	service := &testHostActionService{}
+	handler := &Handler{
+		hostActionService: service,
+		agentOptions:      &agent.Options{HostManagementEnabled: true},
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/host/actions/run", strings.NewReader(`{"Command":["systemctl","restart","docker"],"Timeout":10}`))
+	rw := httptest.NewRecorder()
+	if handlerErr := handler.hostActionRun(rw, r); handlerErr != nil {
+		t.Fatalf("unexpected error: %s", handlerErr.Message)
+	}
+
+	if strings.Join(service.command, " ") != "systemctl restart docker" {
+		t.Errorf("expected the received command to be run, got %v", service.command)
+	}
+	if service.timeout != 10*time.Second {
+		t.Errorf("expected a timeout of 10s, got %s", service.timeout)
+	}
+
+	var result agent.HostActionResult
+	err := json.NewDecoder(rw.Body).Decode(&result)
+	if err != nil {
+		t.Fatalf("unable to decode the response: %s", err)
+	}
+	if result.Output != "ok\n" || result.ExitCode != 3 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+}
+
+func TestHostActionRunRejectedRequests(t *testing.T) {
+	cases := []struct {
+		hostManagementEnabled bool
+		body                  string
+		expectedStatus        int
+	}{
+		{false, `{"Command":["uptime"]}`, http.StatusServiceUnavailable},
+		{true, `{"Command":[]}`, http.StatusBadRequest},
+		{true, `{"Command":["uptime"],"Timeout":-1}`, http.StatusBadRequest},
+	}
+
+	for _, c := range cases {
+		service := &testHostActionService{}
+		handler := &Handler{
+			hostActionService: service,
+			agentOptions:      &agent.Options{HostManagementEnabled: c.hostManagementEnabled},
+		}
+
+		r := httptest.NewRequest(http.MethodPost, "/host/actions/run", strings.NewReader(c.body))
+		handlerErr := handler.hostActionRun(httptest.NewRecorder(), r)
+		if handlerErr == nil || handlerErr.StatusCode != c.expectedStatus {
+			t.Errorf("expected status %d for %s, got %+v", c.expectedStatus, c.body, handlerErr)
+		}
+		if service.command != nil {
+			t.Errorf("the command of a rejected request must not be run: %s", c.body)
+		}
+	}
+}
+
+func TestHostActionTimeout(t *testing.T) {
+	cases := []struct {
+		seconds  int
+		expected time.Duration
+	}{
+		{0, agent.DefaultHostActionTimeout},
+		{5, 5 * time.Second},
+		{3600, agent.MaxHostActionTimeout},
+	}
+
+	for _, c := range cases {
+		if timeout := hostActionTimeout(c.seconds); timeout != c.expected {
+			t.Errorf("hostActionTimeout(%d): got %s, want %s", c.seconds, timeout, c.expected)
+		}
+	}
+}
@@ -17,6 +17,7 @@ type APIServer struct {
 	addr              string
 	port              string
 	systemService     agent.SystemService
+	hostActionService agent.HostActionService
 	clusterService    agent.ClusterService
 	signatureService  agent.DigitalSignatureService
 	edgeManager       *edge.Manager
@@ -32,6 +33,7 @@ type APIServerConfig struct {
 	Addr                 string
 	Port                 string
 	SystemService        agent.SystemService
+	HostActionService    agent.HostActionService
 	ClusterService       agent.ClusterService
 	SignatureService     agent.DigitalSignatureService
 	EdgeManager          *edge.Manager
@@ -47,6 +49,7 @@ func NewAPIServer(config *APIServerConfig) *APIServer {
 		addr:              config.Addr,
 		port:              config.Port,
 		systemService:     config.SystemService,
+		hostActionService: config.HostActionService,
 		clusterService:    config.ClusterService,
 		signatureService:  config.SignatureService,
 		edgeManager:       config.EdgeManager,
@@ -61,6 +64,7 @@ func NewAPIServer(config *APIServerConfig) *APIServer {
 func (server *APIServer) StartUnsecured() error {
 	config := &handler.Config{
 		SystemService:        server.systemService,
+		HostActionService:    server.hostActionService,
 		ClusterService:       server.clusterService,
 		RuntimeConfiguration: server.agentTags,
 		AgentOptions:         server.agentOptions,
@@ -89,6 +93,7 @@ func (server *APIServer) StartUnsecured() error {
 func (server *APIServer) StartSecured() error {
 	config := &handler.Config{
 		SystemService:        server.systemService,
+		HostActionService:    server.hostActionService,
 		ClusterService:       server.clusterService,
 		SignatureService:     server.signatureService,
 		RuntimeConfiguration: server.agentTags,
@@ -0,0 +1,167 @@
+package azure
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/http/proxy/factory/azure"
+)
+
+const (
+	managementAPIBaseURL     = "https://management.azure.com"
+	containerInstanceVersion = "2018-10-01"
+	requestTimeout           = 60 * time.Second
+)
+
+type (
+	// ClientFactory is used to create clients targeting the Azure management API.
+	// Clients are cached per endpoint so that the authentication token is re-used between requests.
+	ClientFactory struct {
+		mutex   sync.Mutex
+		clients map[portainer.EndpointID]*Client
+	}
+
+	// Client is used to execute Azure Container Instances operations on behalf of an endpoint
+	Client struct {
+		credentials portainer.AzureCredentials
+		httpClient  *http.Client
+	}
+
+	// Error represents an error returned by the Azure management API
+	Error struct {
+		StatusCode int
+		Code       string `json:"code"`
+		Message    string `json:"message"`
+	}
+
+	errorResponse struct {
+		Error Error `json:"error"`
+	}
+)
+
+// NewClientFactory returns a new instance of a ClientFactory
+func NewClientFactory() *ClientFactory {
+	return &ClientFactory{
+		clients: make(map[portainer.EndpointID]*Client),
+	}
+}
+
+// GetClient returns the client associated to an Azure endpoint. A new client is created when
+// no client is registered for the endpoint or when the credentials of the endpoint were updated.
+func (factory *ClientFactory) GetClient(endpoint *portainer.Endpoint) (*Client, error) {
+	if endpoint.Type != portainer.AzureEnvironment {
+		return nil, fmt.Errorf("endpoint %d is not an Azure endpoint", endpoint.ID)
+	}
+
+	factory.mutex.Lock()
+	defer factory.mutex.Unlock()
+
+	client, ok := factory.clients[endpoint.ID]
+	if ok && client.credentials == endpoint.AzureCredentials {
+		return client, nil
+	}
+
+	client = &Client{
+		credentials: endpoint.AzureCredentials,
+	}
+	client.httpClient = &http.Client{
+		Transport: azure.NewTransport(&client.credentials),
+		Timeout:   requestTimeout,
+	}
+
+	factory.clients[endpoint.ID] = client
+	return client, nil
+}
+
+// RemoveClient removes the client associated to an endpoint
+func (factory *ClientFactory) RemoveClient(endpointID portainer.EndpointID) {
+	factory.mutex.Lock()
+	defer factory.mutex.Unlock()
+
+	delete(factory.clients, endpointID)
+}
+
+// Error implements the error interface
+func (err *Error) Error() string {
+	if err.Code == "" {
+		return fmt.Sprintf("Azure API error (status: %d)", err.StatusCode)
+	}
+	return fmt.Sprintf("%s: %s", err.Code, err.Message)
+}
+
+// IsNotFound returns true when the error is an Azure API error reporting a missing resource
+func IsNotFound(err error) bool {
+	azureErr, ok := err.(*Error)
+	return ok && azureErr.StatusCode == http.StatusNotFound
+}
+
+func (client *Client) do(method, path string, payload, result interface{}) error {
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, managementAPIBaseURL+path, body)
+	if err != nil {
+		return err
+	}
+
+	query := req.URL.Query()
+	if query.Get("api-version") == "" {
+		query.Set("api-version", containerInstanceVersion)
+		req.URL.RawQuery = query.Encode()
+	}
+
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := client.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return decodeError(resp)
+	}
+
+	if result == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+func decodeError(resp *http.Response) error {
+	apiErr := &Error{StatusCode: resp.StatusCode}
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil || len(data) == 0 {
+		return apiErr
+	}
+
+	var errResp errorResponse
+	err = json.Unmarshal(data, &errResp)
+	if err != nil || errResp.Error.Code == "" {
+		apiErr.Message = string(data)
+		apiErr.Code = strconv.Itoa(resp.StatusCode)
+		return apiErr
+	}
+
+	apiErr.Code = errResp.Error.Code
+	apiErr.Message = errResp.Error.Message
+	return apiErr
+}
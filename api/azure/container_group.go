@@ -0,0 +1,214 @@
+package azure
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+type (
+	// ContainerGroup represents an Azure Container Instances container group
+	ContainerGroup struct {
+		ID         string                   `json:"id,omitempty"`
+		Name       string                   `json:"name,omitempty"`
+		Type       string                   `json:"type,omitempty"`
+		Location   string                   `json:"location"`
+		Tags       map[string]string        `json:"tags,omitempty"`
+		Properties ContainerGroupProperties `json:"properties"`
+	}
+
+	// ContainerGroupProperties represents the properties of a container group
+	ContainerGroupProperties struct {
+		ProvisioningState string                      `json:"provisioningState,omitempty"`
+		Containers        []Container                 `json:"containers"`
+		OSType            string                      `json:"osType"`
+		RestartPolicy     string                      `json:"restartPolicy,omitempty"`
+		IPAddress         *IPAddress                  `json:"ipAddress,omitempty"`
+		Volumes           []Volume                    `json:"volumes,omitempty"`
+		InstanceView      *ContainerGroupInstanceView `json:"instanceView,omitempty"`
+	}
+
+	// ContainerGroupInstanceView represents the runtime state of a container group
+	ContainerGroupInstanceView struct {
+		State string `json:"state,omitempty"`
+	}
+
+	// Container represents a container inside a container group
+	Container struct {
+		Name       string              `json:"name"`
+		Properties ContainerProperties `json:"properties"`
+	}
+
+	// ContainerProperties represents the properties of a container
+	ContainerProperties struct {
+		Image                string                 `json:"image"`
+		Command              []string               `json:"command,omitempty"`
+		Ports                []ContainerPort        `json:"ports,omitempty"`
+		EnvironmentVariables []EnvironmentVariable  `json:"environmentVariables,omitempty"`
+		Resources            ResourceRequirements   `json:"resources"`
+		VolumeMounts         []VolumeMount          `json:"volumeMounts,omitempty"`
+		InstanceView         *ContainerInstanceView `json:"instanceView,omitempty"`
+	}
+
+	// ContainerInstanceView represents the runtime state of a container
+	ContainerInstanceView struct {
+		RestartCount int             `json:"restartCount"`
+		CurrentState *ContainerState `json:"currentState,omitempty"`
+	}
+
+	// ContainerState represents the state of a container
+	ContainerState struct {
+		State        string `json:"state,omitempty"`
+		DetailStatus string `json:"detailStatus,omitempty"`
+		ExitCode     *int   `json:"exitCode,omitempty"`
+	}
+
+	// ContainerPort represents a port exposed by a container
+	ContainerPort struct {
+		Protocol string `json:"protocol,omitempty"`
+		Port     int    `json:"port"`
+	}
+
+	// EnvironmentVariable represents an environment variable of a container.
+	// Secure values are never returned by the Azure API.
+	EnvironmentVariable struct {
+		Name        string `json:"name"`
+		Value       string `json:"value,omitempty"`
+		SecureValue string `json:"secureValue,omitempty"`
+	}
+
+	// ResourceRequirements represents the resources requested by a container
+	ResourceRequirements struct {
+		Requests ResourceRequests `json:"requests"`
+	}
+
+	// ResourceRequests represents the CPU and memory requested by a container
+	ResourceRequests struct {
+		CPU        float64 `json:"cpu"`
+		MemoryInGB float64 `json:"memoryInGB"`
+	}
+
+	// VolumeMount represents a volume mounted inside a container
+	VolumeMount struct {
+		Name      string `json:"name"`
+		MountPath string `json:"mountPath"`
+		ReadOnly  bool   `json:"readOnly,omitempty"`
+	}
+
+	// Volume represents a volume available to the containers of a container group
+	Volume struct {
+		Name      string           `json:"name"`
+		AzureFile *AzureFileVolume `json:"azureFile,omitempty"`
+	}
+
+	// AzureFileVolume represents an Azure File share mounted as a volume.
+	// The storage account key is never returned by the Azure API.
+	AzureFileVolume struct {
+		ShareName          string `json:"shareName"`
+		StorageAccountName string `json:"storageAccountName"`
+		StorageAccountKey  string `json:"storageAccountKey,omitempty"`
+		ReadOnly           bool   `json:"readOnly,omitempty"`
+	}
+
+	// IPAddress represents the IP address configuration of a container group
+	IPAddress struct {
+		Type         string `json:"type"`
+		IP           string `json:"ip,omitempty"`
+		Ports        []Port `json:"ports"`
+		DNSNameLabel string `json:"dnsNameLabel,omitempty"`
+		FQDN         string `json:"fqdn,omitempty"`
+	}
+
+	// Port represents a port exposed on the IP address of a container group
+	Port struct {
+		Protocol string `json:"protocol,omitempty"`
+		Port     int    `json:"port"`
+	}
+
+	// ContainerLogs represents the logs of a container
+	ContainerLogs struct {
+		Content string `json:"content"`
+	}
+
+	containerGroupList struct {
+		Value    []ContainerGroup `json:"value"`
+		NextLink string           `json:"nextLink"`
+	}
+)
+
+// ContainerGroups returns the container groups of a subscription. Every page of results is retrieved.
+func (client *Client) ContainerGroups(subscriptionID string) ([]ContainerGroup, error) {
+	path := fmt.Sprintf("/subscriptions/%s/providers/Microsoft.ContainerInstance/containerGroups", url.PathEscape(subscriptionID))
+
+	groups := make([]ContainerGroup, 0)
+	for path != "" {
+		var list containerGroupList
+		err := client.do(http.MethodGet, path, nil, &list)
+		if err != nil {
+			return nil, err
+		}
+
+		groups = append(groups, list.Value...)
+
+		path = ""
+		if list.NextLink != "" {
+			nextLink, err := url.Parse(list.NextLink)
+			if err != nil {
+				return nil, err
+			}
+			path = nextLink.RequestURI()
+		}
+	}
+
+	return groups, nil
+}
+
+// ContainerGroup returns a container group
+func (client *Client) ContainerGroup(subscriptionID, resourceGroup, name string) (*ContainerGroup, error) {
+	var group ContainerGroup
+	err := client.do(http.MethodGet, containerGroupPath(subscriptionID, resourceGroup, name), nil, &group)
+	if err != nil {
+		return nil, err
+	}
+
+	return &group, nil
+}
+
+// CreateContainerGroup creates or replaces a container group
+func (client *Client) CreateContainerGroup(subscriptionID, resourceGroup string, group *ContainerGroup) (*ContainerGroup, error) {
+	var created ContainerGroup
+	err := client.do(http.MethodPut, containerGroupPath(subscriptionID, resourceGroup, group.Name), group, &created)
+	if err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+// DeleteContainerGroup deletes a container group
+func (client *Client) DeleteContainerGroup(subscriptionID, resourceGroup, name string) error {
+	return client.do(http.MethodDelete, containerGroupPath(subscriptionID, resourceGroup, name), nil, nil)
+}
+
+// ContainerLogs returns the logs of a container inside a container group.
+// All the logs are returned when tail is lower or equal to zero.
+func (client *Client) ContainerLogs(subscriptionID, resourceGroup, groupName, containerName string, tail int) (*ContainerLogs, error) {
+	path := fmt.Sprintf("%s/containers/%s/logs", containerGroupPath(subscriptionID, resourceGroup, groupName), url.PathEscape(containerName))
+	if tail > 0 {
+		path += "?tail=" + strconv.Itoa(tail) + "&api-version=" + containerInstanceVersion
+	}
+
+	var logs ContainerLogs
+	err := client.do(http.MethodGet, path, nil, &logs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &logs, nil
+}
+
+func containerGroupPath(subscriptionID, resourceGroup, name string) string {
+	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.ContainerInstance/containerGroups/%s",
+		url.PathEscape(subscriptionID), url.PathEscape(resourceGroup), url.PathEscape(name))
+}
@@ -0,0 +1,101 @@
+package application
+
+import (
+	"github.com/boltdb/bolt"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/bolt/internal"
+)
+
+const (
+	// BucketName represents the name of the bucket where this service stores data.
+	BucketName = "applications"
+)
+
+// Service represents a service for managing composite application data.
+type Service struct {
+	db *bolt.DB
+}
+
+// NewService creates a new instance of a service.
+func NewService(db *bolt.DB) (*Service, error) {
+	err := internal.CreateBucket(db, BucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{
+		db: db,
+	}, nil
+}
+
+// Applications returns an array containing all composite applications
+func (service *Service) Applications() ([]portainer.Application, error) {
+	var applications = make([]portainer.Application, 0)
+
+	err := service.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(BucketName))
+
+		cursor := bucket.Cursor()
+		for k, v := cursor.First(); k != nil; k, v = cursor.Next() {
+			var application portainer.Application
+			err := internal.UnmarshalObject(v, &application)
+			if err != nil {
+				return err
+			}
+			applications = append(applications, application)
+		}
+
+		return nil
+	})
+
+	return applications, err
+}
+
+// Application returns a composite application by ID.
+func (service *Service) Application(ID portainer.ApplicationID) (*portainer.Application, error) {
+	var application portainer.Application
+	identifier := internal.Itob(int(ID))
+
+	err := internal.GetObject(service.db, BucketName, identifier, &application)
+	if err != nil {
+		return nil, err
+	}
+
+	return &application, nil
+}
+
+// CreateApplication assign an ID to a new composite application and saves it.
+func (service *Service) CreateApplication(application *portainer.Application) error {
+	return service.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(BucketName))
+
+		if application.ID == 0 {
+			id, _ := bucket.NextSequence()
+			application.ID = portainer.ApplicationID(id)
+		}
+
+		data, err := internal.MarshalObject(application)
+		if err != nil {
+			return err
+		}
+
+		return bucket.Put(internal.Itob(int(application.ID)), data)
+	})
+}
+
+// UpdateApplication updates a composite application.
+func (service *Service) UpdateApplication(ID portainer.ApplicationID, application *portainer.Application) error {
+	identifier := internal.Itob(int(ID))
+	return internal.UpdateObject(service.db, BucketName, identifier, application)
+}
+
+// DeleteApplication deletes a composite application.
+func (service *Service) DeleteApplication(ID portainer.ApplicationID) error {
+	identifier := internal.Itob(int(ID))
+	return internal.DeleteObject(service.db, BucketName, identifier)
+}
+
+// GetNextIdentifier returns the next identifier for a composite application.
+func (service *Service) GetNextIdentifier() int {
+	return internal.GetNextIdentifier(service.db, BucketName)
+}
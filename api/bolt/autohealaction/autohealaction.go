@@ -0,0 +1,89 @@
+package autohealaction
+
+import (
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/bolt/internal"
+
+	"github.com/boltdb/bolt"
+)
+
+const (
+	// BucketName represents the name of the bucket where this service stores data.
+	BucketName = "autoheal_actions"
+)
+
+// Service represents a service for managing the history of the auto-heal actions.
+type Service struct {
+	db *bolt.DB
+}
+
+// NewService creates a new instance of a service.
+func NewService(db *bolt.DB) (*Service, error) {
+	err := internal.CreateBucket(db, BucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{
+		db: db,
+	}, nil
+}
+
+// AutoHealActions returns the auto-heal actions matching the filter, the most recent action first.
+// At most limit actions are returned, all the actions are returned when limit is 0.
+func (service *Service) AutoHealActions(filter func(action *portainer.AutoHealAction) bool, limit int) ([]portainer.AutoHealAction, error) {
+	var actions = make([]portainer.AutoHealAction, 0)
+
+	err := service.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(BucketName))
+
+		cursor := bucket.Cursor()
+		for k, v := cursor.Last(); k != nil; k, v = cursor.Prev() {
+			var action portainer.AutoHealAction
+			err := internal.UnmarshalObject(v, &action)
+			if err != nil {
+				return err
+			}
+
+			if filter != nil && !filter(&action) {
+				continue
+			}
+
+			actions = append(actions, action)
+			if limit > 0 && len(actions) == limit {
+				break
+			}
+		}
+
+		return nil
+	})
+
+	return actions, err
+}
+
+// CreateAutoHealAction records a new auto-heal action.
+func (service *Service) CreateAutoHealAction(action *portainer.AutoHealAction) error {
+	return service.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(BucketName))
+
+		id, _ := bucket.NextSequence()
+		action.ID = portainer.AutoHealActionID(id)
+
+		data, err := internal.MarshalObject(action)
+		if err != nil {
+			return err
+		}
+
+		return bucket.Put(internal.Itob(int(action.ID)), data)
+	})
+}
+
+// DeleteAutoHealActionsBefore deletes the auto-heal actions executed before the specified unix timestamp.
+// It returns the number of deleted actions and their size in bytes.
+func (service *Service) DeleteAutoHealActionsBefore(time int64) (int, int64, error) {
+	return internal.DeleteObjects(service.db, BucketName, func(data []byte) (bool, error) {
+		var action portainer.AutoHealAction
+		err := internal.UnmarshalObject(data, &action)
+		return action.Time < time, err
+	})
+}
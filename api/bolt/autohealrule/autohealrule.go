@@ -0,0 +1,95 @@
+package autohealrule
+
+import (
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/bolt/internal"
+
+	"github.com/boltdb/bolt"
+)
+
+const (
+	// BucketName represents the name of the bucket where this service stores data.
+	BucketName = "autoheal_rules"
+)
+
+// Service represents a service for managing auto-heal rule data.
+type Service struct {
+	db *bolt.DB
+}
+
+// NewService creates a new instance of a service.
+func NewService(db *bolt.DB) (*Service, error) {
+	err := internal.CreateBucket(db, BucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{
+		db: db,
+	}, nil
+}
+
+// AutoHealRules returns an array containing all the auto-heal rules.
+func (service *Service) AutoHealRules() ([]portainer.AutoHealRule, error) {
+	var rules = make([]portainer.AutoHealRule, 0)
+
+	err := service.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(BucketName))
+
+		cursor := bucket.Cursor()
+		for k, v := cursor.First(); k != nil; k, v = cursor.Next() {
+			var rule portainer.AutoHealRule
+			err := internal.UnmarshalObject(v, &rule)
+			if err != nil {
+				return err
+			}
+			rules = append(rules, rule)
+		}
+
+		return nil
+	})
+
+	return rules, err
+}
+
+// AutoHealRule returns an auto-heal rule by ID.
+func (service *Service) AutoHealRule(ID portainer.AutoHealRuleID) (*portainer.AutoHealRule, error) {
+	var rule portainer.AutoHealRule
+	identifier := internal.Itob(int(ID))
+
+	err := internal.GetObject(service.db, BucketName, identifier, &rule)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rule, nil
+}
+
+// CreateAutoHealRule creates a new auto-heal rule.
+func (service *Service) CreateAutoHealRule(rule *portainer.AutoHealRule) error {
+	return service.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(BucketName))
+
+		id, _ := bucket.NextSequence()
+		rule.ID = portainer.AutoHealRuleID(id)
+
+		data, err := internal.MarshalObject(rule)
+		if err != nil {
+			return err
+		}
+
+		return bucket.Put(internal.Itob(int(rule.ID)), data)
+	})
+}
+
+// UpdateAutoHealRule updates an auto-heal rule.
+func (service *Service) UpdateAutoHealRule(ID portainer.AutoHealRuleID, rule *portainer.AutoHealRule) error {
+	identifier := internal.Itob(int(ID))
+	return internal.UpdateObject(service.db, BucketName, identifier, rule)
+}
+
+// DeleteAutoHealRule deletes an auto-heal rule.
+func (service *Service) DeleteAutoHealRule(ID portainer.AutoHealRuleID) error {
+	identifier := internal.Itob(int(ID))
+	return internal.DeleteObject(service.db, BucketName, identifier)
+}
@@ -0,0 +1,95 @@
+package changerequest
+
+import (
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/bolt/internal"
+
+	"github.com/boltdb/bolt"
+)
+
+const (
+	// BucketName represents the name of the bucket where this service stores data.
+	BucketName = "change_requests"
+)
+
+// Service represents a service for managing change request data.
+type Service struct {
+	db *bolt.DB
+}
+
+// NewService creates a new instance of a service.
+func NewService(db *bolt.DB) (*Service, error) {
+	err := internal.CreateBucket(db, BucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{
+		db: db,
+	}, nil
+}
+
+// ChangeRequests returns an array containing all the change requests.
+func (service *Service) ChangeRequests() ([]portainer.ChangeRequest, error) {
+	var changeRequests = make([]portainer.ChangeRequest, 0)
+
+	err := service.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(BucketName))
+
+		cursor := bucket.Cursor()
+		for k, v := cursor.First(); k != nil; k, v = cursor.Next() {
+			var changeRequest portainer.ChangeRequest
+			err := internal.UnmarshalObject(v, &changeRequest)
+			if err != nil {
+				return err
+			}
+			changeRequests = append(changeRequests, changeRequest)
+		}
+
+		return nil
+	})
+
+	return changeRequests, err
+}
+
+// ChangeRequest returns a change request by ID.
+func (service *Service) ChangeRequest(ID portainer.ChangeRequestID) (*portainer.ChangeRequest, error) {
+	var changeRequest portainer.ChangeRequest
+	identifier := internal.Itob(int(ID))
+
+	err := internal.GetObject(service.db, BucketName, identifier, &changeRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	return &changeRequest, nil
+}
+
+// CreateChangeRequest creates a new change request.
+func (service *Service) CreateChangeRequest(changeRequest *portainer.ChangeRequest) error {
+	return service.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(BucketName))
+
+		id, _ := bucket.NextSequence()
+		changeRequest.ID = portainer.ChangeRequestID(id)
+
+		data, err := internal.MarshalObject(changeRequest)
+		if err != nil {
+			return err
+		}
+
+		return bucket.Put(internal.Itob(int(changeRequest.ID)), data)
+	})
+}
+
+// UpdateChangeRequest updates a change request.
+func (service *Service) UpdateChangeRequest(ID portainer.ChangeRequestID, changeRequest *portainer.ChangeRequest) error {
+	identifier := internal.Itob(int(ID))
+	return internal.UpdateObject(service.db, BucketName, identifier, changeRequest)
+}
+
+// DeleteChangeRequest deletes a change request.
+func (service *Service) DeleteChangeRequest(ID portainer.ChangeRequestID) error {
+	identifier := internal.Itob(int(ID))
+	return internal.DeleteObject(service.db, BucketName, identifier)
+}
@@ -0,0 +1,102 @@
+package customtemplaterevision
+
+import (
+	"github.com/boltdb/bolt"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/bolt/internal"
+)
+
+const (
+	// BucketName represents the name of the bucket where this service stores data.
+	BucketName = "custom_template_revisions"
+)
+
+// Service represents a service for managing the revisions of the custom templates.
+type Service struct {
+	db *bolt.DB
+}
+
+// NewService creates a new instance of a service.
+func NewService(db *bolt.DB) (*Service, error) {
+	err := internal.CreateBucket(db, BucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{
+		db: db,
+	}, nil
+}
+
+// CustomTemplateRevisions returns the revisions of a custom template, the most recent revision first.
+func (service *Service) CustomTemplateRevisions(templateID portainer.CustomTemplateID) ([]portainer.CustomTemplateRevision, error) {
+	var revisions = make([]portainer.CustomTemplateRevision, 0)
+
+	err := service.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(BucketName))
+
+		cursor := bucket.Cursor()
+		for k, v := cursor.Last(); k != nil; k, v = cursor.Prev() {
+			var revision portainer.CustomTemplateRevision
+			err := internal.UnmarshalObject(v, &revision)
+			if err != nil {
+				return err
+			}
+
+			if revision.CustomTemplateID == templateID {
+				revisions = append(revisions, revision)
+			}
+		}
+
+		return nil
+	})
+
+	return revisions, err
+}
+
+// CreateCustomTemplateRevision records a new revision of a custom template.
+func (service *Service) CreateCustomTemplateRevision(revision *portainer.CustomTemplateRevision) error {
+	return service.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(BucketName))
+
+		id, _ := bucket.NextSequence()
+		revision.ID = portainer.CustomTemplateRevisionID(id)
+
+		data, err := internal.MarshalObject(revision)
+		if err != nil {
+			return err
+		}
+
+		return bucket.Put(internal.Itob(int(revision.ID)), data)
+	})
+}
+
+// DeleteCustomTemplateRevisions deletes all the revisions of a custom template.
+func (service *Service) DeleteCustomTemplateRevisions(templateID portainer.CustomTemplateID) error {
+	return service.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(BucketName))
+
+		keys := make([][]byte, 0)
+		cursor := bucket.Cursor()
+		for k, v := cursor.First(); k != nil; k, v = cursor.Next() {
+			var revision portainer.CustomTemplateRevision
+			err := internal.UnmarshalObject(v, &revision)
+			if err != nil {
+				return err
+			}
+
+			if revision.CustomTemplateID == templateID {
+				keys = append(keys, k)
+			}
+		}
+
+		for _, k := range keys {
+			err := bucket.Delete(k)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
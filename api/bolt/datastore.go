@@ -7,7 +7,12 @@ import (
 
 	"github.com/boltdb/bolt"
 	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/bolt/application"
+	"github.com/portainer/portainer/api/bolt/autohealaction"
+	"github.com/portainer/portainer/api/bolt/autohealrule"
+	"github.com/portainer/portainer/api/bolt/changerequest"
 	"github.com/portainer/portainer/api/bolt/customtemplate"
+	"github.com/portainer/portainer/api/bolt/customtemplaterevision"
 	"github.com/portainer/portainer/api/bolt/dockerhub"
 	"github.com/portainer/portainer/api/bolt/edgegroup"
 	"github.com/portainer/portainer/api/bolt/edgejob"
@@ -17,18 +22,34 @@ import (
 	"github.com/portainer/portainer/api/bolt/endpointrelation"
 	"github.com/portainer/portainer/api/bolt/errors"
 	"github.com/portainer/portainer/api/bolt/extension"
+	"github.com/portainer/portainer/api/bolt/hostaction"
+	"github.com/portainer/portainer/api/bolt/loadbalancerintegration"
+	"github.com/portainer/portainer/api/bolt/maintenancewindow"
 	"github.com/portainer/portainer/api/bolt/migrator"
+	"github.com/portainer/portainer/api/bolt/multiendpointstack"
+	"github.com/portainer/portainer/api/bolt/notificationchannel"
+	"github.com/portainer/portainer/api/bolt/organization"
 	"github.com/portainer/portainer/api/bolt/registry"
+	"github.com/portainer/portainer/api/bolt/reportsubscription"
 	"github.com/portainer/portainer/api/bolt/resourcecontrol"
+	"github.com/portainer/portainer/api/bolt/resourcesample"
 	"github.com/portainer/portainer/api/bolt/role"
+	"github.com/portainer/portainer/api/bolt/scalingschedule"
 	"github.com/portainer/portainer/api/bolt/schedule"
 	"github.com/portainer/portainer/api/bolt/settings"
+	"github.com/portainer/portainer/api/bolt/settingshistory"
+	"github.com/portainer/portainer/api/bolt/shadowuser"
 	"github.com/portainer/portainer/api/bolt/stack"
 	"github.com/portainer/portainer/api/bolt/tag"
+	"github.com/portainer/portainer/api/bolt/task"
 	"github.com/portainer/portainer/api/bolt/team"
 	"github.com/portainer/portainer/api/bolt/teammembership"
+	"github.com/portainer/portainer/api/bolt/telemetry"
 	"github.com/portainer/portainer/api/bolt/tunnelserver"
 	"github.com/portainer/portainer/api/bolt/user"
+	"github.com/portainer/portainer/api/bolt/useractivity"
+	"github.com/portainer/portainer/api/bolt/usernotification"
+	"github.com/portainer/portainer/api/bolt/userpreferences"
 	"github.com/portainer/portainer/api/bolt/version"
 	"github.com/portainer/portainer/api/bolt/webhook"
 	"github.com/portainer/portainer/api/internal/authorization"
@@ -41,32 +62,53 @@ const (
 // Store defines the implementation of portainer.DataStore using
 // BoltDB as the storage system.
 type Store struct {
-	path                    string
-	db                      *bolt.DB
-	isNew                   bool
-	fileService             portainer.FileService
-	CustomTemplateService   *customtemplate.Service
-	DockerHubService        *dockerhub.Service
-	EdgeGroupService        *edgegroup.Service
-	EdgeJobService          *edgejob.Service
-	EdgeStackService        *edgestack.Service
-	EndpointGroupService    *endpointgroup.Service
-	EndpointService         *endpoint.Service
-	EndpointRelationService *endpointrelation.Service
-	ExtensionService        *extension.Service
-	RegistryService         *registry.Service
-	ResourceControlService  *resourcecontrol.Service
-	RoleService             *role.Service
-	ScheduleService         *schedule.Service
-	SettingsService         *settings.Service
-	StackService            *stack.Service
-	TagService              *tag.Service
-	TeamMembershipService   *teammembership.Service
-	TeamService             *team.Service
-	TunnelServerService     *tunnelserver.Service
-	UserService             *user.Service
-	VersionService          *version.Service
-	WebhookService          *webhook.Service
+	path                           string
+	db                             *bolt.DB
+	isNew                          bool
+	fileService                    portainer.FileService
+	CustomTemplateService          *customtemplate.Service
+	ApplicationService             *application.Service
+	AutoHealActionService          *autohealaction.Service
+	AutoHealRuleService            *autohealrule.Service
+	ChangeRequestService           *changerequest.Service
+	DockerHubService               *dockerhub.Service
+	CustomTemplateRevisionService  *customtemplaterevision.Service
+	EdgeGroupService               *edgegroup.Service
+	EdgeJobService                 *edgejob.Service
+	EdgeStackService               *edgestack.Service
+	EndpointGroupService           *endpointgroup.Service
+	EndpointService                *endpoint.Service
+	EndpointRelationService        *endpointrelation.Service
+	HostActionService              *hostaction.Service
+	LoadBalancerIntegrationService *loadbalancerintegration.Service
+	MaintenanceWindowService       *maintenancewindow.Service
+	ExtensionService               *extension.Service
+	MultiEndpointStackService      *multiendpointstack.Service
+	NotificationChannelService     *notificationchannel.Service
+	OrganizationService            *organization.Service
+	RegistryService                *registry.Service
+	ReportSubscriptionService      *reportsubscription.Service
+	ResourceControlService         *resourcecontrol.Service
+	ResourceSampleService          *resourcesample.Service
+	RoleService                    *role.Service
+	ScalingScheduleService         *scalingschedule.Service
+	ScheduleService                *schedule.Service
+	ShadowUserService              *shadowuser.Service
+	SettingsService                *settings.Service
+	SettingsHistoryService         *settingshistory.Service
+	StackService                   *stack.Service
+	TelemetryCounterService        *telemetry.Service
+	TagService                     *tag.Service
+	TaskService                    *task.Service
+	TeamMembershipService          *teammembership.Service
+	TeamService                    *team.Service
+	TunnelServerService            *tunnelserver.Service
+	UserService                    *user.Service
+	UserActivityService            *useractivity.Service
+	UserNotificationService        *usernotification.Service
+	UserPreferencesService         *userpreferences.Service
+	VersionService                 *version.Service
+	WebhookService                 *webhook.Service
 }
 
 // NewStore initializes a new Store and the associated services
@@ -110,6 +152,34 @@ func (store *Store) Close() error {
 	return nil
 }
 
+// Statistics returns the size of the database file, the number of objects stored in each bucket
+// and the page and transaction statistics of the database.
+func (store *Store) Statistics() (*portainer.DatabaseStatistics, error) {
+	stats := store.db.Stats()
+
+	statistics := &portainer.DatabaseStatistics{
+		Buckets:      make(map[string]int),
+		FreePages:    stats.FreePageN,
+		PendingPages: stats.PendingPageN,
+		ReadTxs:      stats.TxN,
+		OpenReadTxs:  stats.OpenTxN,
+	}
+
+	err := store.db.View(func(tx *bolt.Tx) error {
+		statistics.Size = tx.Size()
+
+		return tx.ForEach(func(name []byte, bucket *bolt.Bucket) error {
+			statistics.Buckets[string(name)] = bucket.Stats().KeyN
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return statistics, nil
+}
+
 // IsNew returns true if the database was just created and false if it is re-using
 // existing data.
 func (store *Store) IsNew() bool {
@@ -177,6 +247,30 @@ func (store *Store) initServices() error {
 	}
 	store.CustomTemplateService = customTemplateService
 
+	applicationService, err := application.NewService(store.db)
+	if err != nil {
+		return err
+	}
+	store.ApplicationService = applicationService
+
+	autoHealActionService, err := autohealaction.NewService(store.db)
+	if err != nil {
+		return err
+	}
+	store.AutoHealActionService = autoHealActionService
+
+	autoHealRuleService, err := autohealrule.NewService(store.db)
+	if err != nil {
+		return err
+	}
+	store.AutoHealRuleService = autoHealRuleService
+
+	changeRequestService, err := changerequest.NewService(store.db)
+	if err != nil {
+		return err
+	}
+	store.ChangeRequestService = changeRequestService
+
 	dockerhubService, err := dockerhub.NewService(store.db)
 	if err != nil {
 		return err
@@ -189,6 +283,12 @@ func (store *Store) initServices() error {
 	}
 	store.EdgeStackService = edgeStackService
 
+	customTemplateRevisionService, err := customtemplaterevision.NewService(store.db)
+	if err != nil {
+		return err
+	}
+	store.CustomTemplateRevisionService = customTemplateRevisionService
+
 	edgeGroupService, err := edgegroup.NewService(store.db)
 	if err != nil {
 		return err
@@ -219,42 +319,120 @@ func (store *Store) initServices() error {
 	}
 	store.EndpointRelationService = endpointRelationService
 
+	hostActionService, err := hostaction.NewService(store.db)
+	if err != nil {
+		return err
+	}
+	store.HostActionService = hostActionService
+
+	loadBalancerIntegrationService, err := loadbalancerintegration.NewService(store.db)
+	if err != nil {
+		return err
+	}
+	store.LoadBalancerIntegrationService = loadBalancerIntegrationService
+
+	maintenanceWindowService, err := maintenancewindow.NewService(store.db)
+	if err != nil {
+		return err
+	}
+	store.MaintenanceWindowService = maintenanceWindowService
+
 	extensionService, err := extension.NewService(store.db)
 	if err != nil {
 		return err
 	}
 	store.ExtensionService = extensionService
 
+	multiEndpointStackService, err := multiendpointstack.NewService(store.db)
+	if err != nil {
+		return err
+	}
+	store.MultiEndpointStackService = multiEndpointStackService
+
+	notificationChannelService, err := notificationchannel.NewService(store.db)
+	if err != nil {
+		return err
+	}
+	store.NotificationChannelService = notificationChannelService
+
+	organizationService, err := organization.NewService(store.db)
+	if err != nil {
+		return err
+	}
+	store.OrganizationService = organizationService
+
 	registryService, err := registry.NewService(store.db)
 	if err != nil {
 		return err
 	}
 	store.RegistryService = registryService
 
+	reportSubscriptionService, err := reportsubscription.NewService(store.db)
+	if err != nil {
+		return err
+	}
+	store.ReportSubscriptionService = reportSubscriptionService
+
 	resourcecontrolService, err := resourcecontrol.NewService(store.db)
 	if err != nil {
 		return err
 	}
 	store.ResourceControlService = resourcecontrolService
 
+	resourceSampleService, err := resourcesample.NewService(store.db)
+	if err != nil {
+		return err
+	}
+	store.ResourceSampleService = resourceSampleService
+
+	scalingScheduleService, err := scalingschedule.NewService(store.db)
+	if err != nil {
+		return err
+	}
+	store.ScalingScheduleService = scalingScheduleService
+
+	shadowUserService, err := shadowuser.NewService(store.db)
+	if err != nil {
+		return err
+	}
+	store.ShadowUserService = shadowUserService
+
 	settingsService, err := settings.NewService(store.db)
 	if err != nil {
 		return err
 	}
 	store.SettingsService = settingsService
 
+	settingsHistoryService, err := settingshistory.NewService(store.db)
+	if err != nil {
+		return err
+	}
+	store.SettingsHistoryService = settingsHistoryService
+
 	stackService, err := stack.NewService(store.db)
 	if err != nil {
 		return err
 	}
 	store.StackService = stackService
 
+	telemetryCounterService, err := telemetry.NewService(store.db)
+	if err != nil {
+		return err
+	}
+	store.TelemetryCounterService = telemetryCounterService
+
 	tagService, err := tag.NewService(store.db)
 	if err != nil {
 		return err
 	}
 	store.TagService = tagService
 
+	taskService, err := task.NewService(store.db)
+	if err != nil {
+		return err
+	}
+	store.TaskService = taskService
+
 	teammembershipService, err := teammembership.NewService(store.db)
 	if err != nil {
 		return err
@@ -279,6 +457,24 @@ func (store *Store) initServices() error {
 	}
 	store.UserService = userService
 
+	userActivityService, err := useractivity.NewService(store.db)
+	if err != nil {
+		return err
+	}
+	store.UserActivityService = userActivityService
+
+	userNotificationService, err := usernotification.NewService(store.db)
+	if err != nil {
+		return err
+	}
+	store.UserNotificationService = userNotificationService
+
+	userPreferencesService, err := userpreferences.NewService(store.db)
+	if err != nil {
+		return err
+	}
+	store.UserPreferencesService = userPreferencesService
+
 	versionService, err := version.NewService(store.db)
 	if err != nil {
 		return err
@@ -305,11 +501,36 @@ func (store *Store) CustomTemplate() portainer.CustomTemplateService {
 	return store.CustomTemplateService
 }
 
+// Application gives access to the Application data management layer
+func (store *Store) Application() portainer.ApplicationService {
+	return store.ApplicationService
+}
+
+// AutoHealAction gives access to the AutoHealAction data management layer
+func (store *Store) AutoHealAction() portainer.AutoHealActionService {
+	return store.AutoHealActionService
+}
+
+// AutoHealRule gives access to the AutoHealRule data management layer
+func (store *Store) AutoHealRule() portainer.AutoHealRuleService {
+	return store.AutoHealRuleService
+}
+
+// ChangeRequest gives access to the ChangeRequest data management layer
+func (store *Store) ChangeRequest() portainer.ChangeRequestService {
+	return store.ChangeRequestService
+}
+
 // DockerHub gives access to the DockerHub data management layer
 func (store *Store) DockerHub() portainer.DockerHubService {
 	return store.DockerHubService
 }
 
+// CustomTemplateRevision gives access to the CustomTemplateRevision data management layer
+func (store *Store) CustomTemplateRevision() portainer.CustomTemplateRevisionService {
+	return store.CustomTemplateRevisionService
+}
+
 // EdgeGroup gives access to the EdgeGroup data management layer
 func (store *Store) EdgeGroup() portainer.EdgeGroupService {
 	return store.EdgeGroupService
@@ -340,36 +561,101 @@ func (store *Store) EndpointRelation() portainer.EndpointRelationService {
 	return store.EndpointRelationService
 }
 
+// HostAction gives access to the HostAction data management layer
+func (store *Store) HostAction() portainer.HostActionService {
+	return store.HostActionService
+}
+
+// LoadBalancerIntegration gives access to the LoadBalancerIntegration data management layer
+func (store *Store) LoadBalancerIntegration() portainer.LoadBalancerIntegrationService {
+	return store.LoadBalancerIntegrationService
+}
+
+// MaintenanceWindow gives access to the MaintenanceWindow data management layer
+func (store *Store) MaintenanceWindow() portainer.MaintenanceWindowService {
+	return store.MaintenanceWindowService
+}
+
+// MultiEndpointStack gives access to the MultiEndpointStack data management layer
+func (store *Store) MultiEndpointStack() portainer.MultiEndpointStackService {
+	return store.MultiEndpointStackService
+}
+
+// NotificationChannel gives access to the NotificationChannel data management layer
+func (store *Store) NotificationChannel() portainer.NotificationChannelService {
+	return store.NotificationChannelService
+}
+
+// Organization gives access to the Organization data management layer
+func (store *Store) Organization() portainer.OrganizationService {
+	return store.OrganizationService
+}
+
 // Registry gives access to the Registry data management layer
 func (store *Store) Registry() portainer.RegistryService {
 	return store.RegistryService
 }
 
+// ReportSubscription gives access to the ReportSubscription data management layer
+func (store *Store) ReportSubscription() portainer.ReportSubscriptionService {
+	return store.ReportSubscriptionService
+}
+
 // ResourceControl gives access to the ResourceControl data management layer
 func (store *Store) ResourceControl() portainer.ResourceControlService {
 	return store.ResourceControlService
 }
 
+// ResourceSample gives access to the ResourceSample data management layer
+func (store *Store) ResourceSample() portainer.ResourceSampleService {
+	return store.ResourceSampleService
+}
+
 // Role gives access to the Role data management layer
 func (store *Store) Role() portainer.RoleService {
 	return store.RoleService
 }
 
+// ScalingSchedule gives access to the ScalingSchedule data management layer
+func (store *Store) ScalingSchedule() portainer.ScalingScheduleService {
+	return store.ScalingScheduleService
+}
+
+// ShadowUser gives access to the ShadowUser data management layer
+func (store *Store) ShadowUser() portainer.ShadowUserService {
+	return store.ShadowUserService
+}
+
 // Settings gives access to the Settings data management layer
 func (store *Store) Settings() portainer.SettingsService {
 	return store.SettingsService
 }
 
+// SettingsHistory gives access to the SettingsHistory data management layer
+func (store *Store) SettingsHistory() portainer.SettingsHistoryService {
+	return store.SettingsHistoryService
+}
+
 // Stack gives access to the Stack data management layer
 func (store *Store) Stack() portainer.StackService {
 	return store.StackService
 }
 
+// TelemetryCounter gives access to the TelemetryCounter data management layer
+func (store *Store) TelemetryCounter() portainer.TelemetryCounterService {
+	return store.TelemetryCounterService
+}
+
 // Tag gives access to the Tag data management layer
 func (store *Store) Tag() portainer.TagService {
 	return store.TagService
 }
 
+// Task gives access to the Task data management layer
+func (store *Store) Task() portainer.TaskService {
+	return store.TaskService
+}
+
 // TeamMembership gives access to the TeamMembership data management layer
 func (store *Store) TeamMembership() portainer.TeamMembershipService {
 	return store.TeamMembershipService
@@ -390,6 +676,21 @@ func (store *Store) User() portainer.UserService {
 	return store.UserService
 }
 
+// UserActivity gives access to the UserActivity data management layer
+func (store *Store) UserActivity() portainer.UserActivityService {
+	return store.UserActivityService
+}
+
+// UserNotification gives access to the UserNotification data management layer
+func (store *Store) UserNotification() portainer.UserNotificationService {
+	return store.UserNotificationService
+}
+
+// UserPreferences gives access to the UserPreferences data management layer
+func (store *Store) UserPreferences() portainer.UserPreferencesService {
+	return store.UserPreferencesService
+}
+
 // Version gives access to the Version data management layer
 func (store *Store) Version() portainer.VersionService {
 	return store.VersionService
@@ -3,28 +3,41 @@ package endpoint
 import (
 	"github.com/boltdb/bolt"
 	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/bolt/errors"
 	"github.com/portainer/portainer/api/bolt/internal"
 )
 
 const (
 	// BucketName represents the name of the bucket where this service stores data.
 	BucketName = "endpoints"
+	// GroupIndexName represents the name of the bucket indexing the endpoints by group.
+	GroupIndexName = "endpoints_by_group"
+	// TagIndexName represents the name of the bucket indexing the endpoints by tag.
+	TagIndexName = "endpoints_by_tag"
 )
 
 // Service represents a service for managing endpoint data.
 type Service struct {
-	db *bolt.DB
+	db    *bolt.DB
+	cache *internal.BucketCache
 }
 
 // NewService creates a new instance of a service.
+// The secondary indexes are rebuilt from the endpoints bucket.
 func NewService(db *bolt.DB) (*Service, error) {
 	err := internal.CreateBucket(db, BucketName)
 	if err != nil {
 		return nil, err
 	}
 
+	err = db.Update(rebuildIndexes)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Service{
-		db: db,
+		db:    db,
+		cache: internal.NewBucketCache(db, BucketName),
 	}, nil
 }
 
@@ -33,7 +46,12 @@ func (service *Service) Endpoint(ID portainer.EndpointID) (*portainer.Endpoint,
 	var endpoint portainer.Endpoint
 	identifier := internal.Itob(int(ID))
 
-	err := internal.GetObject(service.db, BucketName, identifier, &endpoint)
+	data, err := service.cache.Get(identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	err = internal.UnmarshalObjectWithJsoniter(data, &endpoint)
 	if err != nil {
 		return nil, err
 	}
@@ -43,41 +61,81 @@ func (service *Service) Endpoint(ID portainer.EndpointID) (*portainer.Endpoint,
 
 // UpdateEndpoint updates an endpoint.
 func (service *Service) UpdateEndpoint(ID portainer.EndpointID, endpoint *portainer.Endpoint) error {
-	identifier := internal.Itob(int(ID))
-	return internal.UpdateObject(service.db, BucketName, identifier, endpoint)
+	defer service.cache.Invalidate(internal.Itob(int(ID)))
+	return service.db.Update(func(tx *bolt.Tx) error {
+		return putEndpoint(tx, ID, endpoint)
+	})
 }
 
 // DeleteEndpoint deletes an endpoint.
 func (service *Service) DeleteEndpoint(ID portainer.EndpointID) error {
-	identifier := internal.Itob(int(ID))
-	return internal.DeleteObject(service.db, BucketName, identifier)
+	defer service.cache.Invalidate(internal.Itob(int(ID)))
+	return service.db.Update(func(tx *bolt.Tx) error {
+		return deleteEndpoint(tx, ID)
+	})
 }
 
 // Endpoints return an array containing all the endpoints.
 func (service *Service) Endpoints() ([]portainer.Endpoint, error) {
 	var endpoints = make([]portainer.Endpoint, 0)
 
-	err := service.db.View(func(tx *bolt.Tx) error {
-		bucket := tx.Bucket([]byte(BucketName))
-
-		cursor := bucket.Cursor()
-		for k, v := cursor.First(); k != nil; k, v = cursor.Next() {
-			var endpoint portainer.Endpoint
-			err := internal.UnmarshalObjectWithJsoniter(v, &endpoint)
-			if err != nil {
-				return err
-			}
-			endpoints = append(endpoints, endpoint)
+	err := service.cache.ForEach(func(key, data []byte) error {
+		var endpoint portainer.Endpoint
+		err := internal.UnmarshalObjectWithJsoniter(data, &endpoint)
+		if err != nil {
+			return err
 		}
-
+		endpoints = append(endpoints, endpoint)
 		return nil
 	})
 
 	return endpoints, err
 }
 
+// EndpointsByGroupID returns an array containing all the endpoints associated to the specified group.
+func (service *Service) EndpointsByGroupID(groupID portainer.EndpointGroupID) ([]portainer.Endpoint, error) {
+	return service.endpointsByIndex(GroupIndexName, internal.Itob(int(groupID)))
+}
+
+// EndpointsByTagID returns an array containing all the endpoints associated to the specified tag.
+func (service *Service) EndpointsByTagID(tagID portainer.TagID) ([]portainer.Endpoint, error) {
+	return service.endpointsByIndex(TagIndexName, internal.Itob(int(tagID)))
+}
+
+func (service *Service) endpointsByIndex(indexName string, value []byte) ([]portainer.Endpoint, error) {
+	var endpoints = make([]portainer.Endpoint, 0)
+	var identifiers []int
+
+	err := service.db.View(func(tx *bolt.Tx) error {
+		identifiers = internal.IndexLookup(tx, indexName, value)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ID := range identifiers {
+		data, err := service.cache.Get(internal.Itob(ID))
+		if err == errors.ErrObjectNotFound {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		var endpoint portainer.Endpoint
+		err = internal.UnmarshalObjectWithJsoniter(data, &endpoint)
+		if err != nil {
+			return nil, err
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+
+	return endpoints, nil
+}
+
 // CreateEndpoint assign an ID to a new endpoint and saves it.
 func (service *Service) CreateEndpoint(endpoint *portainer.Endpoint) error {
+	defer service.cache.Invalidate(internal.Itob(int(endpoint.ID)))
 	return service.db.Update(func(tx *bolt.Tx) error {
 		bucket := tx.Bucket([]byte(BucketName))
 
@@ -87,12 +145,7 @@ func (service *Service) CreateEndpoint(endpoint *portainer.Endpoint) error {
 			return err
 		}
 
-		data, err := internal.MarshalObject(endpoint)
-		if err != nil {
-			return err
-		}
-
-		return bucket.Put(internal.Itob(int(endpoint.ID)), data)
+		return putEndpoint(tx, endpoint.ID, endpoint)
 	})
 }
 
@@ -103,6 +156,7 @@ func (service *Service) GetNextIdentifier() int {
 
 // Synchronize creates, updates and deletes endpoints inside a single transaction.
 func (service *Service) Synchronize(toCreate, toUpdate, toDelete []*portainer.Endpoint) error {
+	defer service.cache.Reset()
 	return service.db.Update(func(tx *bolt.Tx) error {
 		bucket := tx.Bucket([]byte(BucketName))
 
@@ -110,31 +164,21 @@ func (service *Service) Synchronize(toCreate, toUpdate, toDelete []*portainer.En
 			id, _ := bucket.NextSequence()
 			endpoint.ID = portainer.EndpointID(id)
 
-			data, err := internal.MarshalObject(endpoint)
-			if err != nil {
-				return err
-			}
-
-			err = bucket.Put(internal.Itob(int(endpoint.ID)), data)
+			err := putEndpoint(tx, endpoint.ID, endpoint)
 			if err != nil {
 				return err
 			}
 		}
 
 		for _, endpoint := range toUpdate {
-			data, err := internal.MarshalObject(endpoint)
-			if err != nil {
-				return err
-			}
-
-			err = bucket.Put(internal.Itob(int(endpoint.ID)), data)
+			err := putEndpoint(tx, endpoint.ID, endpoint)
 			if err != nil {
 				return err
 			}
 		}
 
 		for _, endpoint := range toDelete {
-			err := bucket.Delete(internal.Itob(int(endpoint.ID)))
+			err := deleteEndpoint(tx, endpoint.ID)
 			if err != nil {
 				return err
 			}
@@ -143,3 +187,111 @@ func (service *Service) Synchronize(toCreate, toUpdate, toDelete []*portainer.En
 		return nil
 	})
 }
+
+// putEndpoint saves an endpoint and replaces its entries inside the secondary indexes.
+func putEndpoint(tx *bolt.Tx, ID portainer.EndpointID, endpoint *portainer.Endpoint) error {
+	err := unindexEndpoint(tx, ID)
+	if err != nil {
+		return err
+	}
+
+	data, err := internal.MarshalObject(endpoint)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Bucket([]byte(BucketName)).Put(internal.Itob(int(ID)), data)
+	if err != nil {
+		return err
+	}
+
+	return indexEndpoint(tx, ID, endpoint)
+}
+
+func deleteEndpoint(tx *bolt.Tx, ID portainer.EndpointID) error {
+	err := unindexEndpoint(tx, ID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Bucket([]byte(BucketName)).Delete(internal.Itob(int(ID)))
+}
+
+func indexEndpoint(tx *bolt.Tx, ID portainer.EndpointID, endpoint *portainer.Endpoint) error {
+	err := internal.AddToIndex(tx, GroupIndexName, internal.Itob(int(endpoint.GroupID)), int(ID))
+	if err != nil {
+		return err
+	}
+
+	for _, tagID := range endpoint.TagIDs {
+		err = internal.AddToIndex(tx, TagIndexName, internal.Itob(int(tagID)), int(ID))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// unindexEndpoint removes the index entries of the currently stored version of an endpoint.
+func unindexEndpoint(tx *bolt.Tx, ID portainer.EndpointID) error {
+	data := tx.Bucket([]byte(BucketName)).Get(internal.Itob(int(ID)))
+	if data == nil {
+		return nil
+	}
+
+	var indexed indexedFields
+	err := internal.UnmarshalObject(data, &indexed)
+	if err != nil {
+		return err
+	}
+
+	err = internal.RemoveFromIndex(tx, GroupIndexName, internal.Itob(int(indexed.GroupID)), int(ID))
+	if err != nil {
+		return err
+	}
+
+	for _, tagID := range indexed.TagIDs {
+		err = internal.RemoveFromIndex(tx, TagIndexName, internal.Itob(int(tagID)), int(ID))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func rebuildIndexes(tx *bolt.Tx) error {
+	err := internal.ResetIndex(tx, GroupIndexName)
+	if err != nil {
+		return err
+	}
+
+	err = internal.ResetIndex(tx, TagIndexName)
+	if err != nil {
+		return err
+	}
+
+	cursor := tx.Bucket([]byte(BucketName)).Cursor()
+	for k, v := cursor.First(); k != nil; k, v = cursor.Next() {
+		var indexed indexedFields
+		err := internal.UnmarshalObject(v, &indexed)
+		if err != nil {
+			return err
+		}
+
+		err = indexEndpoint(tx, indexed.ID, &portainer.Endpoint{GroupID: indexed.GroupID, TagIDs: indexed.TagIDs})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// indexedFields is a partial representation of an endpoint, decoding only the indexed fields.
+type indexedFields struct {
+	ID      portainer.EndpointID      `json:"Id"`
+	GroupID portainer.EndpointGroupID `json:"GroupId"`
+	TagIDs  []portainer.TagID         `json:"TagIds"`
+}
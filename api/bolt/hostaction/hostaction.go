@@ -0,0 +1,95 @@
+package hostaction
+
+import (
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/bolt/internal"
+
+	"github.com/boltdb/bolt"
+)
+
+const (
+	// BucketName represents the name of the bucket where this service stores data.
+	BucketName = "host_actions"
+)
+
+// Service represents a service for managing host action data.
+type Service struct {
+	db *bolt.DB
+}
+
+// NewService creates a new instance of a service.
+func NewService(db *bolt.DB) (*Service, error) {
+	err := internal.CreateBucket(db, BucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{
+		db: db,
+	}, nil
+}
+
+// HostActions returns an array containing all the host actions.
+func (service *Service) HostActions() ([]portainer.HostAction, error) {
+	var actions = make([]portainer.HostAction, 0)
+
+	err := service.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(BucketName))
+
+		cursor := bucket.Cursor()
+		for k, v := cursor.First(); k != nil; k, v = cursor.Next() {
+			var action portainer.HostAction
+			err := internal.UnmarshalObject(v, &action)
+			if err != nil {
+				return err
+			}
+			actions = append(actions, action)
+		}
+
+		return nil
+	})
+
+	return actions, err
+}
+
+// HostAction returns a host action by ID.
+func (service *Service) HostAction(ID portainer.HostActionID) (*portainer.HostAction, error) {
+	var action portainer.HostAction
+	identifier := internal.Itob(int(ID))
+
+	err := internal.GetObject(service.db, BucketName, identifier, &action)
+	if err != nil {
+		return nil, err
+	}
+
+	return &action, nil
+}
+
+// CreateHostAction creates a new host action.
+func (service *Service) CreateHostAction(action *portainer.HostAction) error {
+	return service.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(BucketName))
+
+		id, _ := bucket.NextSequence()
+		action.ID = portainer.HostActionID(id)
+
+		data, err := internal.MarshalObject(action)
+		if err != nil {
+			return err
+		}
+
+		return bucket.Put(internal.Itob(int(action.ID)), data)
+	})
+}
+
+// UpdateHostAction updates a host action.
+func (service *Service) UpdateHostAction(ID portainer.HostActionID, action *portainer.HostAction) error {
+	identifier := internal.Itob(int(ID))
+	return internal.UpdateObject(service.db, BucketName, identifier, action)
+}
+
+// DeleteHostAction deletes a host action.
+func (service *Service) DeleteHostAction(ID portainer.HostActionID) error {
+	identifier := internal.Itob(int(ID))
+	return internal.DeleteObject(service.db, BucketName, identifier)
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/gofrs/uuid"
 	portainer "github.com/portainer/portainer/api"
 	"github.com/portainer/portainer/api/bolt/errors"
+	"github.com/portainer/portainer/api/internal/i18n"
 )
 
 // Init creates the default data set.
@@ -27,8 +28,11 @@ func (store *Store) Init() error {
 	_, err = store.SettingsService.Settings()
 	if err == errors.ErrObjectNotFound {
 		defaultSettings := &portainer.Settings{
-			AuthenticationMethod: portainer.AuthenticationInternal,
-			BlackListedLabels:    make([]portainer.Pair, 0),
+			AuthenticationMethod:  portainer.AuthenticationInternal,
+			BlackListedLabels:     make([]portainer.Pair, 0),
+			HiddenContainerNames:  make([]string, 0),
+			HiddenComposeProjects: make([]string, 0),
+			FeatureFlags:          make(map[portainer.FeatureFlag]bool),
 			LDAPSettings: portainer.LDAPSettings{
 				AnonymousMode:   true,
 				AutoCreateUsers: true,
@@ -52,6 +56,32 @@ func (store *Store) Init() error {
 			EdgeAgentCheckinInterval:                  portainer.DefaultEdgeAgentCheckinIntervalInSeconds,
 			TemplatesURL:                              portainer.DefaultTemplatesURL,
 			UserSessionTimeout:                        portainer.DefaultUserSessionTimeout,
+			DefaultResourceOwnershipPolicy:            portainer.ResourceOwnershipPolicyPrivate,
+			DefaultLocale:                             i18n.DefaultLocale,
+			Telemetry: portainer.TelemetrySettings{
+				Mode: portainer.TelemetryDisabled,
+			},
+			ContainerAlerting: portainer.ContainerAlertingSettings{
+				Enabled:               true,
+				DeduplicationInterval: portainer.DefaultContainerAlertingDeduplicationInterval,
+				Silences:              make([]portainer.AlertingSilence, 0),
+			},
+			HostAlerting: portainer.HostAlertingSettings{
+				Enabled:              true,
+				DiskUsageThreshold:   90,
+				MemoryUsageThreshold: 90,
+				LoadAverageThreshold: 2,
+			},
+			ContainerLabelPolicy: portainer.ContainerLabelPolicy{
+				Prefix:         portainer.DefaultContainerLabelPrefix,
+				Labels:         make([]portainer.Pair, 0),
+				RequiredLabels: make([]string, 0),
+			},
+			EmailNotifications: portainer.EmailNotificationSettings{
+				Recipients: make([]string, 0),
+				Types:      make([]portainer.NotificationType, 0),
+				Templates:  make([]portainer.NotificationTemplate, 0),
+			},
 		}
 
 		err = store.SettingsService.UpdateSettings(defaultSettings)
@@ -0,0 +1,163 @@
+package internal
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/boltdb/bolt"
+	"github.com/portainer/portainer/api/bolt/errors"
+)
+
+// BucketCache keeps an in-memory copy of the encoded objects of a bucket. It is used by the services
+// managing frequently read and rarely written objects to avoid opening a read transaction on each access.
+//
+// The services must invalidate the keys they write to once the write transaction is over, the
+// invalidated keys are then reloaded from the database on the next access. Database reads performed
+// by the cache are done while holding the cache lock so that an invalidation can never be overwritten
+// by a value read before the associated write.
+type BucketCache struct {
+	db         *bolt.DB
+	bucketName string
+
+	mu      sync.Mutex
+	loaded  bool
+	objects map[string][]byte
+	keys    []string
+	stale   map[string]bool
+}
+
+// NewBucketCache returns a cache of the specified bucket, the bucket is loaded on first access.
+func NewBucketCache(db *bolt.DB, bucketName string) *BucketCache {
+	return &BucketCache{
+		db:         db,
+		bucketName: bucketName,
+		objects:    make(map[string][]byte),
+		stale:      make(map[string]bool),
+	}
+}
+
+// Get returns the encoded object stored under the specified key.
+func (cache *BucketCache) Get(key []byte) ([]byte, error) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	err := cache.refresh()
+	if err != nil {
+		return nil, err
+	}
+
+	data, ok := cache.objects[string(key)]
+	if !ok {
+		return nil, errors.ErrObjectNotFound
+	}
+
+	return data, nil
+}
+
+// ForEach calls fn for each encoded object of the bucket, in key order. The iteration is performed on a
+// snapshot of the cache, fn can safely use the services.
+func (cache *BucketCache) ForEach(fn func(key, data []byte) error) error {
+	cache.mu.Lock()
+	err := cache.refresh()
+	if err != nil {
+		cache.mu.Unlock()
+		return err
+	}
+
+	keys := make([]string, len(cache.keys))
+	copy(keys, cache.keys)
+	objects := make([][]byte, len(keys))
+	for idx, key := range keys {
+		objects[idx] = cache.objects[key]
+	}
+	cache.mu.Unlock()
+
+	for idx, key := range keys {
+		err := fn([]byte(key), objects[idx])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Invalidate marks the objects stored under the specified keys as outdated.
+func (cache *BucketCache) Invalidate(keys ...[]byte) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	for _, key := range keys {
+		cache.stale[string(key)] = true
+	}
+}
+
+// Reset drops the whole content of the cache. It must be used after writing to the bucket without
+// knowing the modified keys.
+func (cache *BucketCache) Reset() {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	cache.loaded = false
+	cache.objects = make(map[string][]byte)
+	cache.keys = nil
+	cache.stale = make(map[string]bool)
+}
+
+// refresh loads the bucket or the invalidated keys. It must be called while holding the cache lock.
+func (cache *BucketCache) refresh() error {
+	if cache.loaded && len(cache.stale) == 0 {
+		return nil
+	}
+
+	return cache.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(cache.bucketName))
+
+		if !cache.loaded {
+			cursor := bucket.Cursor()
+			for k, v := cursor.First(); k != nil; k, v = cursor.Next() {
+				cache.set(string(k), v)
+			}
+
+			cache.loaded = true
+			cache.stale = make(map[string]bool)
+			return nil
+		}
+
+		for key := range cache.stale {
+			value := bucket.Get([]byte(key))
+			if value == nil {
+				cache.remove(key)
+			} else {
+				cache.set(key, value)
+			}
+		}
+
+		cache.stale = make(map[string]bool)
+		return nil
+	})
+}
+
+func (cache *BucketCache) set(key string, value []byte) {
+	data := make([]byte, len(value))
+	copy(data, value)
+
+	if _, ok := cache.objects[key]; !ok {
+		idx := sort.SearchStrings(cache.keys, key)
+		cache.keys = append(cache.keys, "")
+		copy(cache.keys[idx+1:], cache.keys[idx:])
+		cache.keys[idx] = key
+	}
+
+	cache.objects[key] = data
+}
+
+func (cache *BucketCache) remove(key string) {
+	if _, ok := cache.objects[key]; !ok {
+		return
+	}
+
+	delete(cache.objects, key)
+	idx := sort.SearchStrings(cache.keys, key)
+	cache.keys = append(cache.keys[:idx], cache.keys[idx+1:]...)
+}
@@ -78,6 +78,46 @@ func DeleteObject(db *bolt.DB, bucketName string, key []byte) error {
 	})
 }
 
+// DeleteObjects is a generic function used to delete the objects of a bucket matched by a function.
+// It returns the number of deleted objects and their size in bytes.
+func DeleteObjects(db *bolt.DB, bucketName string, match func(data []byte) (bool, error)) (int, int64, error) {
+	var count int
+	var size int64
+
+	err := db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(bucketName))
+
+		keys := make([][]byte, 0)
+		cursor := bucket.Cursor()
+		for k, v := cursor.First(); k != nil; k, v = cursor.Next() {
+			matched, err := match(v)
+			if err != nil {
+				return err
+			}
+
+			if matched {
+				keys = append(keys, k)
+				size += int64(len(k) + len(v))
+			}
+		}
+
+		for _, k := range keys {
+			err := bucket.Delete(k)
+			if err != nil {
+				return err
+			}
+		}
+
+		count = len(keys)
+		return nil
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return count, size, nil
+}
+
 // GetNextIdentifier is a generic function that returns the specified bucket identifier incremented by 1.
 func GetNextIdentifier(db *bolt.DB, bucketName string) int {
 	var identifier int
@@ -0,0 +1,61 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/boltdb/bolt"
+)
+
+// Secondary indexes are stored in dedicated buckets where each key is the concatenation of the indexed
+// value, a zero byte separator and the identifier of the indexed object. Looking up the identifiers
+// matching a value is a prefix scan using a cursor, it never requires to unmarshal the indexed objects.
+
+func indexPrefix(value []byte) []byte {
+	prefix := make([]byte, len(value)+1)
+	copy(prefix, value)
+	return prefix
+}
+
+func indexKey(value []byte, ID int) []byte {
+	return append(indexPrefix(value), Itob(ID)...)
+}
+
+// ResetIndex removes all the entries of an index bucket, creating the bucket if needed.
+func ResetIndex(tx *bolt.Tx, indexName string) error {
+	if tx.Bucket([]byte(indexName)) != nil {
+		err := tx.DeleteBucket([]byte(indexName))
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err := tx.CreateBucket([]byte(indexName))
+	return err
+}
+
+// AddToIndex references the object identifier under the specified value inside an index bucket.
+func AddToIndex(tx *bolt.Tx, indexName string, value []byte, ID int) error {
+	return tx.Bucket([]byte(indexName)).Put(indexKey(value, ID), []byte{})
+}
+
+// RemoveFromIndex removes the reference to the object identifier under the specified value inside an index bucket.
+func RemoveFromIndex(tx *bolt.Tx, indexName string, value []byte, ID int) error {
+	return tx.Bucket([]byte(indexName)).Delete(indexKey(value, ID))
+}
+
+// IndexLookup returns the identifiers of the objects referenced under the specified value inside an index bucket.
+func IndexLookup(tx *bolt.Tx, indexName string, value []byte) []int {
+	identifiers := make([]int, 0)
+	prefix := indexPrefix(value)
+
+	cursor := tx.Bucket([]byte(indexName)).Cursor()
+	for k, _ := cursor.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = cursor.Next() {
+		if len(k) != len(prefix)+8 {
+			continue
+		}
+		identifiers = append(identifiers, int(binary.BigEndian.Uint64(k[len(prefix):])))
+	}
+
+	return identifiers
+}
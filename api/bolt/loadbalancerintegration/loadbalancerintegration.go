@@ -0,0 +1,86 @@
+package loadbalancerintegration
+
+import (
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/bolt/internal"
+
+	"github.com/boltdb/bolt"
+)
+
+const (
+	// BucketName represents the name of the bucket where this service stores data.
+	BucketName = "load_balancer_integrations"
+)
+
+// Service represents a service for managing load balancer integration data.
+type Service struct {
+	db *bolt.DB
+}
+
+// NewService creates a new instance of a service.
+func NewService(db *bolt.DB) (*Service, error) {
+	err := internal.CreateBucket(db, BucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{
+		db: db,
+	}, nil
+}
+
+// LoadBalancerIntegrations returns an array containing the load balancer integrations of all the endpoints.
+func (service *Service) LoadBalancerIntegrations() ([]portainer.LoadBalancerIntegration, error) {
+	var integrations = make([]portainer.LoadBalancerIntegration, 0)
+
+	err := service.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(BucketName))
+
+		cursor := bucket.Cursor()
+		for k, v := cursor.First(); k != nil; k, v = cursor.Next() {
+			var integration portainer.LoadBalancerIntegration
+			err := internal.UnmarshalObject(v, &integration)
+			if err != nil {
+				return err
+			}
+			integrations = append(integrations, integration)
+		}
+
+		return nil
+	})
+
+	return integrations, err
+}
+
+// LoadBalancerIntegration returns the load balancer integration of an endpoint.
+func (service *Service) LoadBalancerIntegration(endpointID portainer.EndpointID) (*portainer.LoadBalancerIntegration, error) {
+	var integration portainer.LoadBalancerIntegration
+	identifier := internal.Itob(int(endpointID))
+
+	err := internal.GetObject(service.db, BucketName, identifier, &integration)
+	if err != nil {
+		return nil, err
+	}
+
+	return &integration, nil
+}
+
+// UpdateLoadBalancerIntegration saves the load balancer integration of an endpoint.
+func (service *Service) UpdateLoadBalancerIntegration(endpointID portainer.EndpointID, integration *portainer.LoadBalancerIntegration) error {
+	return service.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(BucketName))
+
+		data, err := internal.MarshalObject(integration)
+		if err != nil {
+			return err
+		}
+
+		return bucket.Put(internal.Itob(int(endpointID)), data)
+	})
+}
+
+// DeleteLoadBalancerIntegration deletes the load balancer integration of an endpoint.
+func (service *Service) DeleteLoadBalancerIntegration(endpointID portainer.EndpointID) error {
+	identifier := internal.Itob(int(endpointID))
+	return internal.DeleteObject(service.db, BucketName, identifier)
+}
@@ -0,0 +1,95 @@
+package maintenancewindow
+
+import (
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/bolt/internal"
+
+	"github.com/boltdb/bolt"
+)
+
+const (
+	// BucketName represents the name of the bucket where this service stores data.
+	BucketName = "maintenance_windows"
+)
+
+// Service represents a service for managing maintenance window data.
+type Service struct {
+	db *bolt.DB
+}
+
+// NewService creates a new instance of a service.
+func NewService(db *bolt.DB) (*Service, error) {
+	err := internal.CreateBucket(db, BucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{
+		db: db,
+	}, nil
+}
+
+// MaintenanceWindows returns an array containing all the maintenance windows.
+func (service *Service) MaintenanceWindows() ([]portainer.MaintenanceWindow, error) {
+	var windows = make([]portainer.MaintenanceWindow, 0)
+
+	err := service.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(BucketName))
+
+		cursor := bucket.Cursor()
+		for k, v := cursor.First(); k != nil; k, v = cursor.Next() {
+			var window portainer.MaintenanceWindow
+			err := internal.UnmarshalObject(v, &window)
+			if err != nil {
+				return err
+			}
+			windows = append(windows, window)
+		}
+
+		return nil
+	})
+
+	return windows, err
+}
+
+// MaintenanceWindow returns a maintenance window by ID.
+func (service *Service) MaintenanceWindow(ID portainer.MaintenanceWindowID) (*portainer.MaintenanceWindow, error) {
+	var window portainer.MaintenanceWindow
+	identifier := internal.Itob(int(ID))
+
+	err := internal.GetObject(service.db, BucketName, identifier, &window)
+	if err != nil {
+		return nil, err
+	}
+
+	return &window, nil
+}
+
+// CreateMaintenanceWindow creates a new maintenance window.
+func (service *Service) CreateMaintenanceWindow(window *portainer.MaintenanceWindow) error {
+	return service.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(BucketName))
+
+		id, _ := bucket.NextSequence()
+		window.ID = portainer.MaintenanceWindowID(id)
+
+		data, err := internal.MarshalObject(window)
+		if err != nil {
+			return err
+		}
+
+		return bucket.Put(internal.Itob(int(window.ID)), data)
+	})
+}
+
+// UpdateMaintenanceWindow updates a maintenance window.
+func (service *Service) UpdateMaintenanceWindow(ID portainer.MaintenanceWindowID, window *portainer.MaintenanceWindow) error {
+	identifier := internal.Itob(int(ID))
+	return internal.UpdateObject(service.db, BucketName, identifier, window)
+}
+
+// DeleteMaintenanceWindow deletes a maintenance window.
+func (service *Service) DeleteMaintenanceWindow(ID portainer.MaintenanceWindowID) error {
+	identifier := internal.Itob(int(ID))
+	return internal.DeleteObject(service.db, BucketName, identifier)
+}
@@ -30,6 +30,7 @@ func (m *Migrator) updateAdminUserToDBVersion1() error {
 }
 
 func (m *Migrator) removeLegacyAdminUser() error {
+	defer m.userService.ResetCache()
 	return m.db.Update(func(tx *bolt.Tx) error {
 		bucket := tx.Bucket([]byte(user.BucketName))
 		return bucket.Delete([]byte("admin"))
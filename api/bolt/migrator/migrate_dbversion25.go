@@ -0,0 +1,21 @@
+package migrator
+
+import (
+	"github.com/portainer/portainer/api"
+)
+
+func (m *Migrator) updateSettingsToDBVersion26() error {
+	legacySettings, err := m.settingsService.Settings()
+	if err != nil {
+		return err
+	}
+
+	// the activity log was kept forever when no retention was defined
+	legacySettings.Retention.UserActivityDays = portainer.RetentionForever
+	if legacySettings.UserActivity.RetentionDays > 0 {
+		legacySettings.Retention.UserActivityDays = legacySettings.UserActivity.RetentionDays
+	}
+	legacySettings.UserActivity.RetentionDays = 0
+
+	return m.settingsService.UpdateSettings(legacySettings)
+}
@@ -0,0 +1,101 @@
+package multiendpointstack
+
+import (
+	"github.com/boltdb/bolt"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/bolt/internal"
+)
+
+const (
+	// BucketName represents the name of the bucket where this service stores data.
+	BucketName = "multi_endpoint_stacks"
+)
+
+// Service represents a service for managing multi-endpoint stack data.
+type Service struct {
+	db *bolt.DB
+}
+
+// NewService creates a new instance of a service.
+func NewService(db *bolt.DB) (*Service, error) {
+	err := internal.CreateBucket(db, BucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{
+		db: db,
+	}, nil
+}
+
+// MultiEndpointStacks returns an array containing all multi-endpoint stacks
+func (service *Service) MultiEndpointStacks() ([]portainer.MultiEndpointStack, error) {
+	var stacks = make([]portainer.MultiEndpointStack, 0)
+
+	err := service.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(BucketName))
+
+		cursor := bucket.Cursor()
+		for k, v := cursor.First(); k != nil; k, v = cursor.Next() {
+			var stack portainer.MultiEndpointStack
+			err := internal.UnmarshalObject(v, &stack)
+			if err != nil {
+				return err
+			}
+			stacks = append(stacks, stack)
+		}
+
+		return nil
+	})
+
+	return stacks, err
+}
+
+// MultiEndpointStack returns a multi-endpoint stack by ID.
+func (service *Service) MultiEndpointStack(ID portainer.MultiEndpointStackID) (*portainer.MultiEndpointStack, error) {
+	var stack portainer.MultiEndpointStack
+	identifier := internal.Itob(int(ID))
+
+	err := internal.GetObject(service.db, BucketName, identifier, &stack)
+	if err != nil {
+		return nil, err
+	}
+
+	return &stack, nil
+}
+
+// CreateMultiEndpointStack assign an ID to a new multi-endpoint stack and saves it.
+func (service *Service) CreateMultiEndpointStack(stack *portainer.MultiEndpointStack) error {
+	return service.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(BucketName))
+
+		if stack.ID == 0 {
+			id, _ := bucket.NextSequence()
+			stack.ID = portainer.MultiEndpointStackID(id)
+		}
+
+		data, err := internal.MarshalObject(stack)
+		if err != nil {
+			return err
+		}
+
+		return bucket.Put(internal.Itob(int(stack.ID)), data)
+	})
+}
+
+// UpdateMultiEndpointStack updates a multi-endpoint stack.
+func (service *Service) UpdateMultiEndpointStack(ID portainer.MultiEndpointStackID, stack *portainer.MultiEndpointStack) error {
+	identifier := internal.Itob(int(ID))
+	return internal.UpdateObject(service.db, BucketName, identifier, stack)
+}
+
+// DeleteMultiEndpointStack deletes a multi-endpoint stack.
+func (service *Service) DeleteMultiEndpointStack(ID portainer.MultiEndpointStackID) error {
+	identifier := internal.Itob(int(ID))
+	return internal.DeleteObject(service.db, BucketName, identifier)
+}
+
+// GetNextIdentifier returns the next identifier for a multi-endpoint stack.
+func (service *Service) GetNextIdentifier() int {
+	return internal.GetNextIdentifier(service.db, BucketName)
+}
@@ -0,0 +1,95 @@
+package organization
+
+import (
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/bolt/internal"
+
+	"github.com/boltdb/bolt"
+)
+
+const (
+	// BucketName represents the name of the bucket where this service stores data.
+	BucketName = "organizations"
+)
+
+// Service represents a service for managing organization data.
+type Service struct {
+	db *bolt.DB
+}
+
+// NewService creates a new instance of a service.
+func NewService(db *bolt.DB) (*Service, error) {
+	err := internal.CreateBucket(db, BucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{
+		db: db,
+	}, nil
+}
+
+// Organizations returns an array containing all the organizations.
+func (service *Service) Organizations() ([]portainer.Organization, error) {
+	var organizations = make([]portainer.Organization, 0)
+
+	err := service.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(BucketName))
+
+		cursor := bucket.Cursor()
+		for k, v := cursor.First(); k != nil; k, v = cursor.Next() {
+			var organization portainer.Organization
+			err := internal.UnmarshalObject(v, &organization)
+			if err != nil {
+				return err
+			}
+			organizations = append(organizations, organization)
+		}
+
+		return nil
+	})
+
+	return organizations, err
+}
+
+// Organization returns an organization by ID.
+func (service *Service) Organization(ID portainer.OrganizationID) (*portainer.Organization, error) {
+	var organization portainer.Organization
+	identifier := internal.Itob(int(ID))
+
+	err := internal.GetObject(service.db, BucketName, identifier, &organization)
+	if err != nil {
+		return nil, err
+	}
+
+	return &organization, nil
+}
+
+// CreateOrganization creates a new organization.
+func (service *Service) CreateOrganization(organization *portainer.Organization) error {
+	return service.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(BucketName))
+
+		id, _ := bucket.NextSequence()
+		organization.ID = portainer.OrganizationID(id)
+
+		data, err := internal.MarshalObject(organization)
+		if err != nil {
+			return err
+		}
+
+		return bucket.Put(internal.Itob(int(organization.ID)), data)
+	})
+}
+
+// UpdateOrganization updates an organization.
+func (service *Service) UpdateOrganization(ID portainer.OrganizationID, organization *portainer.Organization) error {
+	identifier := internal.Itob(int(ID))
+	return internal.UpdateObject(service.db, BucketName, identifier, organization)
+}
+
+// DeleteOrganization deletes an organization.
+func (service *Service) DeleteOrganization(ID portainer.OrganizationID) error {
+	identifier := internal.Itob(int(ID))
+	return internal.DeleteObject(service.db, BucketName, identifier)
+}
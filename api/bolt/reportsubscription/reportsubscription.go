@@ -0,0 +1,78 @@
+package reportsubscription
+
+import (
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/bolt/internal"
+
+	"github.com/boltdb/bolt"
+)
+
+const (
+	// BucketName represents the name of the bucket where this service stores data.
+	BucketName = "report_subscriptions"
+)
+
+// Service represents a service for managing the report subscriptions.
+type Service struct {
+	db *bolt.DB
+}
+
+// NewService creates a new instance of a service.
+func NewService(db *bolt.DB) (*Service, error) {
+	err := internal.CreateBucket(db, BucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{
+		db: db,
+	}, nil
+}
+
+// ReportSubscription returns the report subscription of a user.
+func (service *Service) ReportSubscription(userID portainer.UserID) (*portainer.ReportSubscription, error) {
+	var subscription portainer.ReportSubscription
+	identifier := internal.Itob(int(userID))
+
+	err := internal.GetObject(service.db, BucketName, identifier, &subscription)
+	if err != nil {
+		return nil, err
+	}
+
+	return &subscription, nil
+}
+
+// ReportSubscriptions returns an array containing all the report subscriptions.
+func (service *Service) ReportSubscriptions() ([]portainer.ReportSubscription, error) {
+	var subscriptions = make([]portainer.ReportSubscription, 0)
+
+	err := service.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(BucketName))
+
+		cursor := bucket.Cursor()
+		for k, v := cursor.First(); k != nil; k, v = cursor.Next() {
+			var subscription portainer.ReportSubscription
+			err := internal.UnmarshalObject(v, &subscription)
+			if err != nil {
+				return err
+			}
+			subscriptions = append(subscriptions, subscription)
+		}
+
+		return nil
+	})
+
+	return subscriptions, err
+}
+
+// UpdateReportSubscription saves the report subscription of a user.
+func (service *Service) UpdateReportSubscription(userID portainer.UserID, subscription *portainer.ReportSubscription) error {
+	identifier := internal.Itob(int(userID))
+	return internal.UpdateObject(service.db, BucketName, identifier, subscription)
+}
+
+// DeleteReportSubscription deletes the report subscription of a user.
+func (service *Service) DeleteReportSubscription(userID portainer.UserID) error {
+	identifier := internal.Itob(int(userID))
+	return internal.DeleteObject(service.db, BucketName, identifier)
+}
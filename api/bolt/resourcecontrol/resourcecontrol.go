@@ -10,6 +10,8 @@ import (
 const (
 	// BucketName represents the name of the bucket where this service stores data.
 	BucketName = "resource_control"
+	// ResourceIndexName represents the name of the bucket indexing the resource controls by resource and sub-resource identifiers.
+	ResourceIndexName = "resource_control_by_resource"
 )
 
 // Service represents a service for managing endpoint data.
@@ -18,12 +20,18 @@ type Service struct {
 }
 
 // NewService creates a new instance of a service.
+// The resource index is rebuilt from the resource controls bucket.
 func NewService(db *bolt.DB) (*Service, error) {
 	err := internal.CreateBucket(db, BucketName)
 	if err != nil {
 		return nil, err
 	}
 
+	err = db.Update(rebuildIndex)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Service{
 		db: db,
 	}, nil
@@ -45,29 +53,34 @@ func (service *Service) ResourceControl(ID portainer.ResourceControlID) (*portai
 // ResourceControlByResourceIDAndType returns a ResourceControl object by checking if the resourceID is equal
 // to the main ResourceID or in SubResourceIDs. It also performs a check on the resource type. Return nil
 // if no ResourceControl was found.
+// The lookup relies on the resource index and only decodes the candidate ResourceControl objects.
 func (service *Service) ResourceControlByResourceIDAndType(resourceID string, resourceType portainer.ResourceControlType) (*portainer.ResourceControl, error) {
 	var resourceControl *portainer.ResourceControl
 
 	err := service.db.View(func(tx *bolt.Tx) error {
 		bucket := tx.Bucket([]byte(BucketName))
-		cursor := bucket.Cursor()
 
-		for k, v := cursor.First(); k != nil; k, v = cursor.Next() {
+		for _, ID := range internal.IndexLookup(tx, ResourceIndexName, []byte(resourceID)) {
+			data := bucket.Get(internal.Itob(ID))
+			if data == nil {
+				continue
+			}
+
 			var rc portainer.ResourceControl
-			err := internal.UnmarshalObject(v, &rc)
+			err := internal.UnmarshalObject(data, &rc)
 			if err != nil {
 				return err
 			}
 
 			if rc.ResourceID == resourceID && rc.Type == resourceType {
 				resourceControl = &rc
-				break
+				return nil
 			}
 
 			for _, subResourceID := range rc.SubResourceIDs {
 				if subResourceID == resourceID {
 					resourceControl = &rc
-					break
+					return nil
 				}
 			}
 		}
@@ -109,23 +122,119 @@ func (service *Service) CreateResourceControl(resourceControl *portainer.Resourc
 		id, _ := bucket.NextSequence()
 		resourceControl.ID = portainer.ResourceControlID(id)
 
-		data, err := internal.MarshalObject(resourceControl)
-		if err != nil {
-			return err
-		}
-
-		return bucket.Put(internal.Itob(int(resourceControl.ID)), data)
+		return putResourceControl(tx, resourceControl.ID, resourceControl)
 	})
 }
 
 // UpdateResourceControl saves a ResourceControl object.
 func (service *Service) UpdateResourceControl(ID portainer.ResourceControlID, resourceControl *portainer.ResourceControl) error {
-	identifier := internal.Itob(int(ID))
-	return internal.UpdateObject(service.db, BucketName, identifier, resourceControl)
+	return service.db.Update(func(tx *bolt.Tx) error {
+		return putResourceControl(tx, ID, resourceControl)
+	})
 }
 
 // DeleteResourceControl deletes a ResourceControl object by ID
 func (service *Service) DeleteResourceControl(ID portainer.ResourceControlID) error {
-	identifier := internal.Itob(int(ID))
-	return internal.DeleteObject(service.db, BucketName, identifier)
+	return service.db.Update(func(tx *bolt.Tx) error {
+		err := unindexResourceControl(tx, ID)
+		if err != nil {
+			return err
+		}
+
+		return tx.Bucket([]byte(BucketName)).Delete(internal.Itob(int(ID)))
+	})
+}
+
+// putResourceControl saves a ResourceControl object and replaces its entries inside the resource index.
+func putResourceControl(tx *bolt.Tx, ID portainer.ResourceControlID, resourceControl *portainer.ResourceControl) error {
+	err := unindexResourceControl(tx, ID)
+	if err != nil {
+		return err
+	}
+
+	data, err := internal.MarshalObject(resourceControl)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Bucket([]byte(BucketName)).Put(internal.Itob(int(ID)), data)
+	if err != nil {
+		return err
+	}
+
+	return indexResourceControl(tx, ID, resourceControl.ResourceID, resourceControl.SubResourceIDs)
+}
+
+func indexResourceControl(tx *bolt.Tx, ID portainer.ResourceControlID, resourceID string, subResourceIDs []string) error {
+	err := internal.AddToIndex(tx, ResourceIndexName, []byte(resourceID), int(ID))
+	if err != nil {
+		return err
+	}
+
+	for _, subResourceID := range subResourceIDs {
+		err = internal.AddToIndex(tx, ResourceIndexName, []byte(subResourceID), int(ID))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// unindexResourceControl removes the index entries of the currently stored version of a ResourceControl object.
+func unindexResourceControl(tx *bolt.Tx, ID portainer.ResourceControlID) error {
+	data := tx.Bucket([]byte(BucketName)).Get(internal.Itob(int(ID)))
+	if data == nil {
+		return nil
+	}
+
+	var indexed indexedFields
+	err := internal.UnmarshalObject(data, &indexed)
+	if err != nil {
+		return err
+	}
+
+	err = internal.RemoveFromIndex(tx, ResourceIndexName, []byte(indexed.ResourceID), int(ID))
+	if err != nil {
+		return err
+	}
+
+	for _, subResourceID := range indexed.SubResourceIDs {
+		err = internal.RemoveFromIndex(tx, ResourceIndexName, []byte(subResourceID), int(ID))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func rebuildIndex(tx *bolt.Tx) error {
+	err := internal.ResetIndex(tx, ResourceIndexName)
+	if err != nil {
+		return err
+	}
+
+	cursor := tx.Bucket([]byte(BucketName)).Cursor()
+	for k, v := cursor.First(); k != nil; k, v = cursor.Next() {
+		var indexed indexedFields
+		err := internal.UnmarshalObject(v, &indexed)
+		if err != nil {
+			return err
+		}
+
+		err = indexResourceControl(tx, indexed.ID, indexed.ResourceID, indexed.SubResourceIDs)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// indexedFields is a partial representation of a ResourceControl object, decoding only the indexed fields.
+type indexedFields struct {
+	ID             portainer.ResourceControlID `json:"Id"`
+	ResourceID     string                      `json:"ResourceId"`
+	SubResourceIDs []string                    `json:"SubResourceIds"`
 }
@@ -0,0 +1,85 @@
+package resourcesample
+
+import (
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/bolt/internal"
+
+	"github.com/boltdb/bolt"
+)
+
+const (
+	// BucketName represents the name of the bucket where this service stores data.
+	BucketName = "resource_samples"
+)
+
+// Service represents a service for managing the resource reservation samples.
+type Service struct {
+	db *bolt.DB
+}
+
+// NewService creates a new instance of a service.
+func NewService(db *bolt.DB) (*Service, error) {
+	err := internal.CreateBucket(db, BucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{
+		db: db,
+	}, nil
+}
+
+// ResourceSamples returns the samples recorded between from and to (unix timestamps, inclusive).
+func (service *Service) ResourceSamples(from, to int64) ([]portainer.ResourceSample, error) {
+	var samples = make([]portainer.ResourceSample, 0)
+
+	err := service.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(BucketName))
+
+		cursor := bucket.Cursor()
+		for k, v := cursor.First(); k != nil; k, v = cursor.Next() {
+			var sample portainer.ResourceSample
+			err := internal.UnmarshalObject(v, &sample)
+			if err != nil {
+				return err
+			}
+
+			if sample.Time < from || sample.Time > to {
+				continue
+			}
+
+			samples = append(samples, sample)
+		}
+
+		return nil
+	})
+
+	return samples, err
+}
+
+// CreateResourceSample records a new sample.
+func (service *Service) CreateResourceSample(sample *portainer.ResourceSample) error {
+	return service.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(BucketName))
+
+		id, _ := bucket.NextSequence()
+		sample.ID = portainer.ResourceSampleID(id)
+
+		data, err := internal.MarshalObject(sample)
+		if err != nil {
+			return err
+		}
+
+		return bucket.Put(internal.Itob(int(sample.ID)), data)
+	})
+}
+
+// DeleteResourceSamplesBefore deletes the samples recorded before the specified unix timestamp.
+// It returns the number of deleted samples and their size in bytes.
+func (service *Service) DeleteResourceSamplesBefore(time int64) (int, int64, error) {
+	return internal.DeleteObjects(service.db, BucketName, func(data []byte) (bool, error) {
+		var sample portainer.ResourceSample
+		err := internal.UnmarshalObject(data, &sample)
+		return sample.Time < time, err
+	})
+}
@@ -0,0 +1,95 @@
+package scalingschedule
+
+import (
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/bolt/internal"
+
+	"github.com/boltdb/bolt"
+)
+
+const (
+	// BucketName represents the name of the bucket where this service stores data.
+	BucketName = "scaling_schedules"
+)
+
+// Service represents a service for managing scaling schedule data.
+type Service struct {
+	db *bolt.DB
+}
+
+// NewService creates a new instance of a service.
+func NewService(db *bolt.DB) (*Service, error) {
+	err := internal.CreateBucket(db, BucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{
+		db: db,
+	}, nil
+}
+
+// ScalingSchedules returns an array containing all the scaling schedules.
+func (service *Service) ScalingSchedules() ([]portainer.ScalingSchedule, error) {
+	var schedules = make([]portainer.ScalingSchedule, 0)
+
+	err := service.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(BucketName))
+
+		cursor := bucket.Cursor()
+		for k, v := cursor.First(); k != nil; k, v = cursor.Next() {
+			var schedule portainer.ScalingSchedule
+			err := internal.UnmarshalObject(v, &schedule)
+			if err != nil {
+				return err
+			}
+			schedules = append(schedules, schedule)
+		}
+
+		return nil
+	})
+
+	return schedules, err
+}
+
+// ScalingSchedule returns a scaling schedule by ID.
+func (service *Service) ScalingSchedule(ID portainer.ScalingScheduleID) (*portainer.ScalingSchedule, error) {
+	var schedule portainer.ScalingSchedule
+	identifier := internal.Itob(int(ID))
+
+	err := internal.GetObject(service.db, BucketName, identifier, &schedule)
+	if err != nil {
+		return nil, err
+	}
+
+	return &schedule, nil
+}
+
+// CreateScalingSchedule creates a new scaling schedule.
+func (service *Service) CreateScalingSchedule(schedule *portainer.ScalingSchedule) error {
+	return service.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(BucketName))
+
+		id, _ := bucket.NextSequence()
+		schedule.ID = portainer.ScalingScheduleID(id)
+
+		data, err := internal.MarshalObject(schedule)
+		if err != nil {
+			return err
+		}
+
+		return bucket.Put(internal.Itob(int(schedule.ID)), data)
+	})
+}
+
+// UpdateScalingSchedule updates a scaling schedule.
+func (service *Service) UpdateScalingSchedule(ID portainer.ScalingScheduleID, schedule *portainer.ScalingSchedule) error {
+	identifier := internal.Itob(int(ID))
+	return internal.UpdateObject(service.db, BucketName, identifier, schedule)
+}
+
+// DeleteScalingSchedule deletes a scaling schedule.
+func (service *Service) DeleteScalingSchedule(ID portainer.ScalingScheduleID) error {
+	identifier := internal.Itob(int(ID))
+	return internal.DeleteObject(service.db, BucketName, identifier)
+}
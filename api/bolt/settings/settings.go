@@ -15,7 +15,8 @@ const (
 
 // Service represents a service for managing endpoint data.
 type Service struct {
-	db *bolt.DB
+	db    *bolt.DB
+	cache *internal.BucketCache
 }
 
 // NewService creates a new instance of a service.
@@ -26,7 +27,8 @@ func NewService(db *bolt.DB) (*Service, error) {
 	}
 
 	return &Service{
-		db: db,
+		db:    db,
+		cache: internal.NewBucketCache(db, BucketName),
 	}, nil
 }
 
@@ -34,7 +36,12 @@ func NewService(db *bolt.DB) (*Service, error) {
 func (service *Service) Settings() (*portainer.Settings, error) {
 	var settings portainer.Settings
 
-	err := internal.GetObject(service.db, BucketName, []byte(settingsKey), &settings)
+	data, err := service.cache.Get([]byte(settingsKey))
+	if err != nil {
+		return nil, err
+	}
+
+	err = internal.UnmarshalObject(data, &settings)
 	if err != nil {
 		return nil, err
 	}
@@ -44,5 +51,6 @@ func (service *Service) Settings() (*portainer.Settings, error) {
 
 // UpdateSettings persists a Settings object.
 func (service *Service) UpdateSettings(settings *portainer.Settings) error {
+	defer service.cache.Invalidate([]byte(settingsKey))
 	return internal.UpdateObject(service.db, BucketName, []byte(settingsKey), settings)
 }
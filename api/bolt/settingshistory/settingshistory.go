@@ -0,0 +1,93 @@
+package settingshistory
+
+import (
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/bolt/internal"
+
+	"github.com/boltdb/bolt"
+)
+
+const (
+	// BucketName represents the name of the bucket where this service stores data.
+	BucketName = "settings_history"
+)
+
+// Service represents a service for managing the history of the settings changes.
+type Service struct {
+	db *bolt.DB
+}
+
+// NewService creates a new instance of a service.
+func NewService(db *bolt.DB) (*Service, error) {
+	err := internal.CreateBucket(db, BucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{
+		db: db,
+	}, nil
+}
+
+// SettingsChanges returns an array containing all the settings changes, the most recent change first.
+func (service *Service) SettingsChanges() ([]portainer.SettingsChange, error) {
+	var changes = make([]portainer.SettingsChange, 0)
+
+	err := service.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(BucketName))
+
+		cursor := bucket.Cursor()
+		for k, v := cursor.Last(); k != nil; k, v = cursor.Prev() {
+			var change portainer.SettingsChange
+			err := internal.UnmarshalObject(v, &change)
+			if err != nil {
+				return err
+			}
+			changes = append(changes, change)
+		}
+
+		return nil
+	})
+
+	return changes, err
+}
+
+// SettingsChange returns a settings change by ID.
+func (service *Service) SettingsChange(ID portainer.SettingsChangeID) (*portainer.SettingsChange, error) {
+	var change portainer.SettingsChange
+	identifier := internal.Itob(int(ID))
+
+	err := internal.GetObject(service.db, BucketName, identifier, &change)
+	if err != nil {
+		return nil, err
+	}
+
+	return &change, nil
+}
+
+// CreateSettingsChange records a new settings change.
+func (service *Service) CreateSettingsChange(change *portainer.SettingsChange) error {
+	return service.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(BucketName))
+
+		id, _ := bucket.NextSequence()
+		change.ID = portainer.SettingsChangeID(id)
+
+		data, err := internal.MarshalObject(change)
+		if err != nil {
+			return err
+		}
+
+		return bucket.Put(internal.Itob(int(change.ID)), data)
+	})
+}
+
+// DeleteSettingsChangesBefore deletes the settings changes made before the specified unix timestamp.
+// It returns the number of deleted changes and their size in bytes.
+func (service *Service) DeleteSettingsChangesBefore(time int64) (int, int64, error) {
+	return internal.DeleteObjects(service.db, BucketName, func(data []byte) (bool, error) {
+		var change portainer.SettingsChange
+		err := internal.UnmarshalObject(data, &change)
+		return change.Time < time, err
+	})
+}
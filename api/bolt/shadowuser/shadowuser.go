@@ -0,0 +1,128 @@
+package shadowuser
+
+import (
+	"strings"
+
+	"github.com/boltdb/bolt"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/bolt/errors"
+	"github.com/portainer/portainer/api/bolt/internal"
+)
+
+const (
+	// BucketName represents the name of the bucket where this service stores data.
+	BucketName = "shadow_users"
+)
+
+// Service represents a service for managing shadow user data.
+type Service struct {
+	db *bolt.DB
+}
+
+// NewService creates a new instance of a service.
+func NewService(db *bolt.DB) (*Service, error) {
+	err := internal.CreateBucket(db, BucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{
+		db: db,
+	}, nil
+}
+
+// ShadowUsers returns an array containing all the shadow users.
+func (service *Service) ShadowUsers() ([]portainer.ShadowUser, error) {
+	var shadowUsers = make([]portainer.ShadowUser, 0)
+
+	err := service.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(BucketName))
+
+		cursor := bucket.Cursor()
+		for k, v := cursor.First(); k != nil; k, v = cursor.Next() {
+			var shadowUser portainer.ShadowUser
+			err := internal.UnmarshalObject(v, &shadowUser)
+			if err != nil {
+				return err
+			}
+			shadowUsers = append(shadowUsers, shadowUser)
+		}
+
+		return nil
+	})
+
+	return shadowUsers, err
+}
+
+// ShadowUser returns a shadow user by ID.
+func (service *Service) ShadowUser(ID portainer.ShadowUserID) (*portainer.ShadowUser, error) {
+	var shadowUser portainer.ShadowUser
+	identifier := internal.Itob(int(ID))
+
+	err := internal.GetObject(service.db, BucketName, identifier, &shadowUser)
+	if err != nil {
+		return nil, err
+	}
+
+	return &shadowUser, nil
+}
+
+// ShadowUserByUsername returns a shadow user by username, the comparison is case insensitive.
+func (service *Service) ShadowUserByUsername(username string) (*portainer.ShadowUser, error) {
+	var shadowUser *portainer.ShadowUser
+
+	err := service.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(BucketName))
+		cursor := bucket.Cursor()
+
+		for k, v := cursor.First(); k != nil; k, v = cursor.Next() {
+			var s portainer.ShadowUser
+			err := internal.UnmarshalObject(v, &s)
+			if err != nil {
+				return err
+			}
+
+			if strings.EqualFold(s.Username, username) {
+				shadowUser = &s
+				break
+			}
+		}
+
+		if shadowUser == nil {
+			return errors.ErrObjectNotFound
+		}
+
+		return nil
+	})
+
+	return shadowUser, err
+}
+
+// CreateShadowUser assigns an ID to a new shadow user and saves it.
+func (service *Service) CreateShadowUser(shadowUser *portainer.ShadowUser) error {
+	return service.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(BucketName))
+
+		id, _ := bucket.NextSequence()
+		shadowUser.ID = portainer.ShadowUserID(id)
+
+		data, err := internal.MarshalObject(shadowUser)
+		if err != nil {
+			return err
+		}
+
+		return bucket.Put(internal.Itob(int(shadowUser.ID)), data)
+	})
+}
+
+// UpdateShadowUser saves a shadow user.
+func (service *Service) UpdateShadowUser(ID portainer.ShadowUserID, shadowUser *portainer.ShadowUser) error {
+	identifier := internal.Itob(int(ID))
+	return internal.UpdateObject(service.db, BucketName, identifier, shadowUser)
+}
+
+// DeleteShadowUser deletes a shadow user.
+func (service *Service) DeleteShadowUser(ID portainer.ShadowUserID) error {
+	identifier := internal.Itob(int(ID))
+	return internal.DeleteObject(service.db, BucketName, identifier)
+}
@@ -0,0 +1,105 @@
+package task
+
+import (
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/bolt/internal"
+
+	"github.com/boltdb/bolt"
+)
+
+const (
+	// BucketName represents the name of the bucket where this service stores data.
+	BucketName = "tasks"
+)
+
+// Service represents a service for managing task data.
+type Service struct {
+	db *bolt.DB
+}
+
+// NewService creates a new instance of a service.
+func NewService(db *bolt.DB) (*Service, error) {
+	err := internal.CreateBucket(db, BucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{
+		db: db,
+	}, nil
+}
+
+// Tasks returns an array containing all the tasks.
+func (service *Service) Tasks() ([]portainer.Task, error) {
+	var tasks = make([]portainer.Task, 0)
+
+	err := service.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(BucketName))
+
+		cursor := bucket.Cursor()
+		for k, v := cursor.First(); k != nil; k, v = cursor.Next() {
+			var task portainer.Task
+			err := internal.UnmarshalObject(v, &task)
+			if err != nil {
+				return err
+			}
+			tasks = append(tasks, task)
+		}
+
+		return nil
+	})
+
+	return tasks, err
+}
+
+// Task returns a task by ID.
+func (service *Service) Task(ID portainer.TaskID) (*portainer.Task, error) {
+	var task portainer.Task
+	identifier := internal.Itob(int(ID))
+
+	err := internal.GetObject(service.db, BucketName, identifier, &task)
+	if err != nil {
+		return nil, err
+	}
+
+	return &task, nil
+}
+
+// CreateTask creates a new task.
+func (service *Service) CreateTask(task *portainer.Task) error {
+	return service.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(BucketName))
+
+		id, _ := bucket.NextSequence()
+		task.ID = portainer.TaskID(id)
+
+		data, err := internal.MarshalObject(task)
+		if err != nil {
+			return err
+		}
+
+		return bucket.Put(internal.Itob(int(task.ID)), data)
+	})
+}
+
+// UpdateTask updates a task.
+func (service *Service) UpdateTask(ID portainer.TaskID, task *portainer.Task) error {
+	identifier := internal.Itob(int(ID))
+	return internal.UpdateObject(service.db, BucketName, identifier, task)
+}
+
+// DeleteTask deletes a task.
+func (service *Service) DeleteTask(ID portainer.TaskID) error {
+	identifier := internal.Itob(int(ID))
+	return internal.DeleteObject(service.db, BucketName, identifier)
+}
+
+// DeleteTasksFinishedBefore deletes the tasks finished before the specified unix timestamp.
+// It returns the number of deleted tasks and their size in bytes.
+func (service *Service) DeleteTasksFinishedBefore(time int64) (int, int64, error) {
+	return internal.DeleteObjects(service.db, BucketName, func(data []byte) (bool, error) {
+		var task portainer.Task
+		err := internal.UnmarshalObject(data, &task)
+		return task.Finished != 0 && task.Finished < time, err
+	})
+}
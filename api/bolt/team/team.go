@@ -15,7 +15,8 @@ const (
 
 // Service represents a service for managing endpoint data.
 type Service struct {
-	db *bolt.DB
+	db    *bolt.DB
+	cache *internal.BucketCache
 }
 
 // NewService creates a new instance of a service.
@@ -26,7 +27,8 @@ func NewService(db *bolt.DB) (*Service, error) {
 	}
 
 	return &Service{
-		db: db,
+		db:    db,
+		cache: internal.NewBucketCache(db, BucketName),
 	}, nil
 }
 
@@ -35,7 +37,12 @@ func (service *Service) Team(ID portainer.TeamID) (*portainer.Team, error) {
 	var team portainer.Team
 	identifier := internal.Itob(int(ID))
 
-	err := internal.GetObject(service.db, BucketName, identifier, &team)
+	data, err := service.cache.Get(identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	err = internal.UnmarshalObject(data, &team)
 	if err != nil {
 		return nil, err
 	}
@@ -47,50 +54,44 @@ func (service *Service) Team(ID portainer.TeamID) (*portainer.Team, error) {
 func (service *Service) TeamByName(name string) (*portainer.Team, error) {
 	var team *portainer.Team
 
-	err := service.db.View(func(tx *bolt.Tx) error {
-		bucket := tx.Bucket([]byte(BucketName))
-
-		cursor := bucket.Cursor()
-		for k, v := cursor.First(); k != nil; k, v = cursor.Next() {
-			var t portainer.Team
-			err := internal.UnmarshalObject(v, &t)
-			if err != nil {
-				return err
-			}
-
-			if t.Name == name {
-				team = &t
-				break
-			}
+	err := service.cache.ForEach(func(key, data []byte) error {
+		if team != nil {
+			return nil
 		}
 
-		if team == nil {
-			return errors.ErrObjectNotFound
+		var t portainer.Team
+		err := internal.UnmarshalObject(data, &t)
+		if err != nil {
+			return err
 		}
 
+		if t.Name == name {
+			team = &t
+		}
 		return nil
 	})
+	if err != nil {
+		return nil, err
+	}
 
-	return team, err
+	if team == nil {
+		return nil, errors.ErrObjectNotFound
+	}
+
+	return team, nil
 }
 
 // Teams return an array containing all the teams.
 func (service *Service) Teams() ([]portainer.Team, error) {
 	var teams = make([]portainer.Team, 0)
 
-	err := service.db.View(func(tx *bolt.Tx) error {
-		bucket := tx.Bucket([]byte(BucketName))
-
-		cursor := bucket.Cursor()
-		for k, v := cursor.First(); k != nil; k, v = cursor.Next() {
-			var team portainer.Team
-			err := internal.UnmarshalObject(v, &team)
-			if err != nil {
-				return err
-			}
-			teams = append(teams, team)
+	err := service.cache.ForEach(func(key, data []byte) error {
+		var team portainer.Team
+		err := internal.UnmarshalObject(data, &team)
+		if err != nil {
+			return err
 		}
-
+		teams = append(teams, team)
 		return nil
 	})
 
@@ -100,12 +101,13 @@ func (service *Service) Teams() ([]portainer.Team, error) {
 // UpdateTeam saves a Team.
 func (service *Service) UpdateTeam(ID portainer.TeamID, team *portainer.Team) error {
 	identifier := internal.Itob(int(ID))
+	defer service.cache.Invalidate(identifier)
 	return internal.UpdateObject(service.db, BucketName, identifier, team)
 }
 
 // CreateTeam creates a new Team.
 func (service *Service) CreateTeam(team *portainer.Team) error {
-	return service.db.Update(func(tx *bolt.Tx) error {
+	err := service.db.Update(func(tx *bolt.Tx) error {
 		bucket := tx.Bucket([]byte(BucketName))
 
 		id, _ := bucket.NextSequence()
@@ -118,10 +120,14 @@ func (service *Service) CreateTeam(team *portainer.Team) error {
 
 		return bucket.Put(internal.Itob(int(team.ID)), data)
 	})
+
+	service.cache.Invalidate(internal.Itob(int(team.ID)))
+	return err
 }
 
 // DeleteTeam deletes a Team.
 func (service *Service) DeleteTeam(ID portainer.TeamID) error {
 	identifier := internal.Itob(int(ID))
+	defer service.cache.Invalidate(identifier)
 	return internal.DeleteObject(service.db, BucketName, identifier)
 }
@@ -0,0 +1,48 @@
+package telemetry
+
+import (
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/bolt/internal"
+
+	"github.com/boltdb/bolt"
+)
+
+const (
+	// BucketName represents the name of the bucket where this service stores data.
+	BucketName  = "telemetry"
+	countersKey = "COUNTERS"
+)
+
+// Service represents a service for managing the telemetry counters.
+type Service struct {
+	db *bolt.DB
+}
+
+// NewService creates a new instance of a service.
+func NewService(db *bolt.DB) (*Service, error) {
+	err := internal.CreateBucket(db, BucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{
+		db: db,
+	}, nil
+}
+
+// Counters retrieves the telemetry counters.
+func (service *Service) Counters() (*portainer.TelemetryCounters, error) {
+	var counters portainer.TelemetryCounters
+
+	err := internal.GetObject(service.db, BucketName, []byte(countersKey), &counters)
+	if err != nil {
+		return nil, err
+	}
+
+	return &counters, nil
+}
+
+// UpdateCounters persists the telemetry counters.
+func (service *Service) UpdateCounters(counters *portainer.TelemetryCounters) error {
+	return internal.UpdateObject(service.db, BucketName, []byte(countersKey), counters)
+}
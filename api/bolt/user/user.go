@@ -15,7 +15,8 @@ const (
 
 // Service represents a service for managing endpoint data.
 type Service struct {
-	db *bolt.DB
+	db    *bolt.DB
+	cache *internal.BucketCache
 }
 
 // NewService creates a new instance of a service.
@@ -26,16 +27,28 @@ func NewService(db *bolt.DB) (*Service, error) {
 	}
 
 	return &Service{
-		db: db,
+		db:    db,
+		cache: internal.NewBucketCache(db, BucketName),
 	}, nil
 }
 
+// ResetCache drops the cached users. It must be called after writing to the users bucket
+// without using the service.
+func (service *Service) ResetCache() {
+	service.cache.Reset()
+}
+
 // User returns a user by ID
 func (service *Service) User(ID portainer.UserID) (*portainer.User, error) {
 	var user portainer.User
 	identifier := internal.Itob(int(ID))
 
-	err := internal.GetObject(service.db, BucketName, identifier, &user)
+	data, err := service.cache.Get(identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	err = internal.UnmarshalObject(data, &user)
 	if err != nil {
 		return nil, err
 	}
@@ -47,49 +60,50 @@ func (service *Service) User(ID portainer.UserID) (*portainer.User, error) {
 func (service *Service) UserByUsername(username string) (*portainer.User, error) {
 	var user *portainer.User
 
-	err := service.db.View(func(tx *bolt.Tx) error {
-		bucket := tx.Bucket([]byte(BucketName))
-		cursor := bucket.Cursor()
-
-		for k, v := cursor.First(); k != nil; k, v = cursor.Next() {
-			var u portainer.User
-			err := internal.UnmarshalObject(v, &u)
-			if err != nil {
-				return err
-			}
-
-			if u.Username == username {
-				user = &u
-				break
-			}
+	err := service.cache.ForEach(func(key, data []byte) error {
+		var candidate struct {
+			Username string `json:"Username"`
+		}
+		err := internal.UnmarshalObject(data, &candidate)
+		if err != nil {
+			return err
+		}
+
+		if user != nil || candidate.Username != username {
+			return nil
 		}
 
-		if user == nil {
-			return errors.ErrObjectNotFound
+		var u portainer.User
+		err = internal.UnmarshalObject(data, &u)
+		if err != nil {
+			return err
 		}
+
+		user = &u
 		return nil
 	})
+	if err != nil {
+		return nil, err
+	}
 
-	return user, err
+	if user == nil {
+		return nil, errors.ErrObjectNotFound
+	}
+
+	return user, nil
 }
 
 // Users return an array containing all the users.
 func (service *Service) Users() ([]portainer.User, error) {
 	var users = make([]portainer.User, 0)
 
-	err := service.db.View(func(tx *bolt.Tx) error {
-		bucket := tx.Bucket([]byte(BucketName))
-
-		cursor := bucket.Cursor()
-		for k, v := cursor.First(); k != nil; k, v = cursor.Next() {
-			var user portainer.User
-			err := internal.UnmarshalObject(v, &user)
-			if err != nil {
-				return err
-			}
-			users = append(users, user)
+	err := service.cache.ForEach(func(key, data []byte) error {
+		var user portainer.User
+		err := internal.UnmarshalObject(data, &user)
+		if err != nil {
+			return err
 		}
-
+		users = append(users, user)
 		return nil
 	})
 
@@ -99,21 +113,26 @@ func (service *Service) Users() ([]portainer.User, error) {
 // UsersByRole return an array containing all the users with the specified role.
 func (service *Service) UsersByRole(role portainer.UserRole) ([]portainer.User, error) {
 	var users = make([]portainer.User, 0)
-	err := service.db.View(func(tx *bolt.Tx) error {
-		bucket := tx.Bucket([]byte(BucketName))
 
-		cursor := bucket.Cursor()
-		for k, v := cursor.First(); k != nil; k, v = cursor.Next() {
-			var user portainer.User
-			err := internal.UnmarshalObject(v, &user)
-			if err != nil {
-				return err
-			}
-
-			if user.Role == role {
-				users = append(users, user)
-			}
+	err := service.cache.ForEach(func(key, data []byte) error {
+		var candidate struct {
+			Role portainer.UserRole `json:"Role"`
+		}
+		err := internal.UnmarshalObject(data, &candidate)
+		if err != nil {
+			return err
+		}
+
+		if candidate.Role != role {
+			return nil
 		}
+
+		var user portainer.User
+		err = internal.UnmarshalObject(data, &user)
+		if err != nil {
+			return err
+		}
+		users = append(users, user)
 		return nil
 	})
 
@@ -123,12 +142,13 @@ func (service *Service) UsersByRole(role portainer.UserRole) ([]portainer.User,
 // UpdateUser saves a user.
 func (service *Service) UpdateUser(ID portainer.UserID, user *portainer.User) error {
 	identifier := internal.Itob(int(ID))
+	defer service.cache.Invalidate(identifier)
 	return internal.UpdateObject(service.db, BucketName, identifier, user)
 }
 
 // CreateUser creates a new user.
 func (service *Service) CreateUser(user *portainer.User) error {
-	return service.db.Update(func(tx *bolt.Tx) error {
+	err := service.db.Update(func(tx *bolt.Tx) error {
 		bucket := tx.Bucket([]byte(BucketName))
 
 		id, _ := bucket.NextSequence()
@@ -141,10 +161,14 @@ func (service *Service) CreateUser(user *portainer.User) error {
 
 		return bucket.Put(internal.Itob(int(user.ID)), data)
 	})
+
+	service.cache.Invalidate(internal.Itob(int(user.ID)))
+	return err
 }
 
 // DeleteUser deletes a user.
 func (service *Service) DeleteUser(ID portainer.UserID) error {
 	identifier := internal.Itob(int(ID))
+	defer service.cache.Invalidate(identifier)
 	return internal.DeleteObject(service.db, BucketName, identifier)
 }
@@ -0,0 +1,89 @@
+package useractivity
+
+import (
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/bolt/internal"
+
+	"github.com/boltdb/bolt"
+)
+
+const (
+	// BucketName represents the name of the bucket where this service stores data.
+	BucketName = "user_activity"
+)
+
+// Service represents a service for managing the activity log of the users.
+type Service struct {
+	db *bolt.DB
+}
+
+// NewService creates a new instance of a service.
+func NewService(db *bolt.DB) (*Service, error) {
+	err := internal.CreateBucket(db, BucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{
+		db: db,
+	}, nil
+}
+
+// UserActivities returns the activity of a user, the most recent entry first.
+// At most limit entries are returned, all the entries are returned when limit is 0.
+func (service *Service) UserActivities(userID portainer.UserID, limit int) ([]portainer.UserActivity, error) {
+	var activities = make([]portainer.UserActivity, 0)
+
+	err := service.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(BucketName))
+
+		cursor := bucket.Cursor()
+		for k, v := cursor.Last(); k != nil; k, v = cursor.Prev() {
+			var activity portainer.UserActivity
+			err := internal.UnmarshalObject(v, &activity)
+			if err != nil {
+				return err
+			}
+
+			if activity.UserID != userID {
+				continue
+			}
+
+			activities = append(activities, activity)
+			if limit > 0 && len(activities) == limit {
+				break
+			}
+		}
+
+		return nil
+	})
+
+	return activities, err
+}
+
+// CreateUserActivity records a new activity entry.
+func (service *Service) CreateUserActivity(activity *portainer.UserActivity) error {
+	return service.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(BucketName))
+
+		id, _ := bucket.NextSequence()
+		activity.ID = portainer.UserActivityID(id)
+
+		data, err := internal.MarshalObject(activity)
+		if err != nil {
+			return err
+		}
+
+		return bucket.Put(internal.Itob(int(activity.ID)), data)
+	})
+}
+
+// DeleteUserActivitiesBefore deletes the activity entries recorded before the specified unix timestamp.
+// It returns the number of deleted entries and their size in bytes.
+func (service *Service) DeleteUserActivitiesBefore(time int64) (int, int64, error) {
+	return internal.DeleteObjects(service.db, BucketName, func(data []byte) (bool, error) {
+		var activity portainer.UserActivity
+		err := internal.UnmarshalObject(data, &activity)
+		return activity.Date < time, err
+	})
+}
@@ -0,0 +1,138 @@
+package usernotification
+
+import (
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/bolt/internal"
+
+	"github.com/boltdb/bolt"
+)
+
+const (
+	// BucketName represents the name of the bucket where this service stores data.
+	BucketName = "user_notifications"
+)
+
+// Service represents a service for managing user notification data.
+type Service struct {
+	db *bolt.DB
+}
+
+// NewService creates a new instance of a service.
+func NewService(db *bolt.DB) (*Service, error) {
+	err := internal.CreateBucket(db, BucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{
+		db: db,
+	}, nil
+}
+
+// UserNotification returns a user notification by ID.
+func (service *Service) UserNotification(ID portainer.UserNotificationID) (*portainer.UserNotification, error) {
+	var notification portainer.UserNotification
+	identifier := internal.Itob(int(ID))
+
+	err := internal.GetObject(service.db, BucketName, identifier, &notification)
+	if err != nil {
+		return nil, err
+	}
+
+	return &notification, nil
+}
+
+// UserNotificationsByUserID returns an array containing all the notifications of a user.
+func (service *Service) UserNotificationsByUserID(userID portainer.UserID) ([]portainer.UserNotification, error) {
+	var notifications = make([]portainer.UserNotification, 0)
+
+	err := service.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(BucketName))
+
+		cursor := bucket.Cursor()
+		for k, v := cursor.First(); k != nil; k, v = cursor.Next() {
+			var notification portainer.UserNotification
+			err := internal.UnmarshalObject(v, &notification)
+			if err != nil {
+				return err
+			}
+
+			if notification.UserID == userID {
+				notifications = append(notifications, notification)
+			}
+		}
+
+		return nil
+	})
+
+	return notifications, err
+}
+
+// CreateUserNotification creates a new user notification.
+func (service *Service) CreateUserNotification(notification *portainer.UserNotification) error {
+	return service.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(BucketName))
+
+		id, _ := bucket.NextSequence()
+		notification.ID = portainer.UserNotificationID(id)
+
+		data, err := internal.MarshalObject(notification)
+		if err != nil {
+			return err
+		}
+
+		return bucket.Put(internal.Itob(int(notification.ID)), data)
+	})
+}
+
+// UpdateUserNotification updates a user notification.
+func (service *Service) UpdateUserNotification(ID portainer.UserNotificationID, notification *portainer.UserNotification) error {
+	identifier := internal.Itob(int(ID))
+	return internal.UpdateObject(service.db, BucketName, identifier, notification)
+}
+
+// DeleteUserNotification deletes a user notification.
+func (service *Service) DeleteUserNotification(ID portainer.UserNotificationID) error {
+	identifier := internal.Itob(int(ID))
+	return internal.DeleteObject(service.db, BucketName, identifier)
+}
+
+// DeleteUserNotificationsByUserID deletes all the notifications of a user.
+func (service *Service) DeleteUserNotificationsByUserID(userID portainer.UserID) error {
+	return service.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(BucketName))
+
+		keys := make([][]byte, 0)
+		cursor := bucket.Cursor()
+		for k, v := cursor.First(); k != nil; k, v = cursor.Next() {
+			var notification portainer.UserNotification
+			err := internal.UnmarshalObject(v, &notification)
+			if err != nil {
+				return err
+			}
+
+			if notification.UserID == userID {
+				keys = append(keys, k)
+			}
+		}
+
+		for _, k := range keys {
+			err := bucket.Delete(k)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// DeleteUserNotificationsBefore deletes the user notifications sent before the specified unix timestamp.
+// It returns the number of deleted notifications and their size in bytes.
+func (service *Service) DeleteUserNotificationsBefore(time int64) (int, int64, error) {
+	return internal.DeleteObjects(service.db, BucketName, func(data []byte) (bool, error) {
+		var notification portainer.UserNotification
+		err := internal.UnmarshalObject(data, &notification)
+		return notification.Time < time, err
+	})
+}
@@ -0,0 +1,54 @@
+package userpreferences
+
+import (
+	"github.com/boltdb/bolt"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/bolt/internal"
+)
+
+const (
+	// BucketName represents the name of the bucket where this service stores data.
+	BucketName = "user_preferences"
+)
+
+// Service represents a service for managing the preferences of the users.
+type Service struct {
+	db *bolt.DB
+}
+
+// NewService creates a new instance of a service.
+func NewService(db *bolt.DB) (*Service, error) {
+	err := internal.CreateBucket(db, BucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{
+		db: db,
+	}, nil
+}
+
+// UserPreferences returns the preferences of a user.
+func (service *Service) UserPreferences(userID portainer.UserID) (*portainer.UserPreferences, error) {
+	var preferences portainer.UserPreferences
+	identifier := internal.Itob(int(userID))
+
+	err := internal.GetObject(service.db, BucketName, identifier, &preferences)
+	if err != nil {
+		return nil, err
+	}
+
+	return &preferences, nil
+}
+
+// UpdateUserPreferences saves the preferences of a user.
+func (service *Service) UpdateUserPreferences(userID portainer.UserID, preferences *portainer.UserPreferences) error {
+	identifier := internal.Itob(int(userID))
+	return internal.UpdateObject(service.db, BucketName, identifier, preferences)
+}
+
+// DeleteUserPreferences deletes the preferences of a user.
+func (service *Service) DeleteUserPreferences(userID portainer.UserID) error {
+	identifier := internal.Itob(int(userID))
+	return internal.DeleteObject(service.db, BucketName, identifier)
+}
@@ -0,0 +1,9 @@
+package chisel
+
+import "errors"
+
+var (
+	errTunnelServerNotStarted = errors.New("Tunnel server is not started")
+	errTunnelServerStopped    = errors.New("Tunnel server stopped")
+	errNoTunnelPortAvailable  = errors.New("No port available in the tunnel port range")
+)
@@ -3,6 +3,7 @@ package chisel
 import (
 	"encoding/base64"
 	"fmt"
+	"net"
 	"strconv"
 	"strings"
 )
@@ -10,11 +11,17 @@ import (
 // GenerateEdgeKey will generate a key that can be used by an Edge agent to register with a Portainer instance.
 // The key represents the following data in this particular format:
 // portainer_instance_url|tunnel_server_addr|tunnel_server_fingerprint|endpoint_ID
+// The host can include a port when the tunnel server is advertised on another port than the one it is served on.
 // The key returned by this function is a base64 encoded version of the data.
 func (service *Service) GenerateEdgeKey(url, host string, endpointIdentifier int) string {
+	tunnelServerAddr := host
+	if _, _, err := net.SplitHostPort(host); err != nil {
+		tunnelServerAddr = fmt.Sprintf("%s:%s", host, service.serverPort)
+	}
+
 	keyInformation := []string{
 		url,
-		fmt.Sprintf("%s:%s", host, service.serverPort),
+		tunnelServerAddr,
 		service.serverFingerprint,
 		strconv.Itoa(endpointIdentifier),
 	}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/dchest/uniuri"
@@ -14,9 +15,9 @@ import (
 )
 
 const (
-	tunnelCleanupInterval = 10 * time.Second
-	requiredTimeout       = 15 * time.Second
-	activeTimeout         = 4*time.Minute + 30*time.Second
+	tunnelCleanupInterval  = 10 * time.Second
+	defaultRequiredTimeout = 15 * time.Second
+	defaultActiveTimeout   = 4*time.Minute + 30*time.Second
 )
 
 // Service represents a service to manage the state of multiple reverse tunnels.
@@ -25,17 +26,27 @@ const (
 type Service struct {
 	serverFingerprint string
 	serverPort        string
+	minPort           int
+	maxPort           int
 	tunnelDetailsMap  cmap.ConcurrentMap
+	tunnelStatsMap    cmap.ConcurrentMap
 	dataStore         portainer.DataStore
 	snapshotService   portainer.SnapshotService
 	chiselServer      *chserver.Server
+	serverMutex       sync.Mutex
+	// serverErr is the error returned by the tunnel server when it stopped
+	serverErr error
 }
 
-// NewService returns a pointer to a new instance of Service
-func NewService(dataStore portainer.DataStore) *Service {
+// NewService returns a pointer to a new instance of Service.
+// The ports of the tunnels are allocated between minPort and maxPort.
+func NewService(dataStore portainer.DataStore, minPort, maxPort int) *Service {
 	return &Service{
 		tunnelDetailsMap: cmap.New(),
+		tunnelStatsMap:   cmap.New(),
 		dataStore:        dataStore,
+		minPort:          minPort,
+		maxPort:          maxPort,
 	}
 }
 
@@ -69,6 +80,18 @@ func (service *Service) StartTunnelServer(addr, port string, snapshotService por
 	}
 	service.chiselServer = chiselServer
 
+	go func() {
+		err := chiselServer.Wait()
+		if err == nil {
+			err = errTunnelServerStopped
+		}
+		log.Printf("[ERROR] [chisel] [message: tunnel server stopped] [error: %s]", err)
+
+		service.serverMutex.Lock()
+		service.serverErr = err
+		service.serverMutex.Unlock()
+	}()
+
 	// TODO: work-around Chisel default behavior.
 	// By default, Chisel will allow anyone to connect if no user exists.
 	username, password := generateRandomCredentials()
@@ -83,6 +106,17 @@ func (service *Service) StartTunnelServer(addr, port string, snapshotService por
 	return nil
 }
 
+// TunnelServerStatus returns an error when the tunnel server is not started or has stopped
+func (service *Service) TunnelServerStatus() error {
+	if service.chiselServer == nil {
+		return errTunnelServerNotStarted
+	}
+
+	service.serverMutex.Lock()
+	defer service.serverMutex.Unlock()
+	return service.serverErr
+}
+
 func (service *Service) retrievePrivateKeySeed() (string, error) {
 	var serverInfo *portainer.TunnelServerInfo
 
@@ -122,6 +156,8 @@ func (service *Service) startTunnelVerificationLoop() {
 }
 
 func (service *Service) checkTunnels() {
+	requiredTimeout, activeTimeout := service.tunnelTimeouts()
+
 	for item := range service.tunnelDetailsMap.IterBuffered() {
 		tunnel := item.Val.(*portainer.TunnelDetails)
 
@@ -138,29 +174,29 @@ func (service *Service) checkTunnels() {
 			log.Printf("[DEBUG] [chisel,monitoring] [endpoint_id: %s] [status: %s] [status_time_seconds: %f] [timeout_seconds: %f] [message: REQUIRED state timeout exceeded]", item.Key, tunnel.Status, elapsed.Seconds(), requiredTimeout.Seconds())
 		}
 
+		endpointID, err := strconv.Atoi(item.Key)
+		if err != nil {
+			log.Printf("[ERROR] [chisel,conversion] Invalid endpoint identifier (id: %s): %s", item.Key, err)
+			continue
+		}
+
 		if tunnel.Status == portainer.EdgeAgentActive && elapsed.Seconds() < activeTimeout.Seconds() {
 			continue
 		} else if tunnel.Status == portainer.EdgeAgentActive && elapsed.Seconds() > activeTimeout.Seconds() {
 			log.Printf("[DEBUG] [chisel,monitoring] [endpoint_id: %s] [status: %s] [status_time_seconds: %f] [timeout_seconds: %f] [message: ACTIVE state timeout exceeded]", item.Key, tunnel.Status, elapsed.Seconds(), activeTimeout.Seconds())
 
-			endpointID, err := strconv.Atoi(item.Key)
-			if err != nil {
-				log.Printf("[ERROR] [chisel,snapshot,conversion] Invalid endpoint identifier (id: %s): %s", item.Key, err)
-			}
-
 			err = service.snapshotEnvironment(portainer.EndpointID(endpointID), tunnel.Port)
 			if err != nil {
 				log.Printf("[ERROR] [snapshot] Unable to snapshot Edge endpoint (id: %s): %s", item.Key, err)
+				service.SetTunnelError(portainer.EndpointID(endpointID), "Unable to snapshot the endpoint through the tunnel: "+err.Error())
 			}
 		}
 
-		if len(tunnel.Jobs) > 0 {
-			endpointID, err := strconv.Atoi(item.Key)
-			if err != nil {
-				log.Printf("[ERROR] [chisel,conversion] Invalid endpoint identifier (id: %s): %s", item.Key, err)
-				continue
-			}
+		if tunnel.Port != 0 {
+			service.recordTunnelDisconnection(portainer.EndpointID(endpointID), tunnel.Status == portainer.EdgeAgentManagementRequired)
+		}
 
+		if len(tunnel.Jobs) > 0 {
 			service.SetTunnelStatusToIdle(portainer.EndpointID(endpointID))
 		} else {
 			service.tunnelDetailsMap.Remove(item.Key)
@@ -169,6 +205,27 @@ func (service *Service) checkTunnels() {
 	}
 }
 
+// tunnelTimeouts returns the REQUIRED and ACTIVE state timeouts of the tunnels defined in the settings,
+// the default timeouts are used when they are not defined
+func (service *Service) tunnelTimeouts() (time.Duration, time.Duration) {
+	required, active := defaultRequiredTimeout, defaultActiveTimeout
+
+	settings, err := service.dataStore.Settings().Settings()
+	if err != nil {
+		log.Printf("[WARN] [chisel,monitoring] [message: unable to retrieve the tunnel timeouts from the settings] [error: %s]", err)
+		return required, active
+	}
+
+	if timeout, err := time.ParseDuration(settings.EdgeTunnel.RequiredTimeout); err == nil && timeout > 0 {
+		required = timeout
+	}
+	if timeout, err := time.ParseDuration(settings.EdgeTunnel.InactivityTimeout); err == nil && timeout > 0 {
+		active = timeout
+	}
+
+	return required, active
+}
+
 func (service *Service) snapshotEnvironment(endpointID portainer.EndpointID, tunnelPort int) error {
 	endpoint, err := service.dataStore.Endpoint().Endpoint(endpointID)
 	if err != nil {
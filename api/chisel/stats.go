@@ -0,0 +1,72 @@
+package chisel
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/portainer/portainer/api"
+)
+
+// reconnectWindow is the duration after the closing of a tunnel during which a new tunnel
+// requested to the same agent is counted as a reconnection
+const reconnectWindow = 5 * time.Minute
+
+// GetTunnelStats returns the connection statistics of the tunnel associated to an endpoint.
+func (service *Service) GetTunnelStats(endpointID portainer.EndpointID) portainer.EdgeTunnelStats {
+	key := strconv.Itoa(int(endpointID))
+
+	if item, ok := service.tunnelStatsMap.Get(key); ok {
+		return *item.(*portainer.EdgeTunnelStats)
+	}
+
+	return portainer.EdgeTunnelStats{}
+}
+
+// SetTunnelError records the last error of the tunnel associated to an endpoint.
+func (service *Service) SetTunnelError(endpointID portainer.EndpointID, message string) {
+	service.updateTunnelStats(endpointID, func(stats *portainer.EdgeTunnelStats) {
+		stats.LastError = message
+		stats.LastErrorDate = time.Now().Unix()
+	})
+}
+
+// updateTunnelStats applies an update to a copy of the statistics of a tunnel, so that the
+// statistics returned by GetTunnelStats are never modified
+func (service *Service) updateTunnelStats(endpointID portainer.EndpointID, update func(stats *portainer.EdgeTunnelStats)) {
+	key := strconv.Itoa(int(endpointID))
+
+	service.tunnelStatsMap.Upsert(key, nil, func(exist bool, valueInMap interface{}, newValue interface{}) interface{} {
+		stats := portainer.EdgeTunnelStats{}
+		if exist {
+			stats = *valueInMap.(*portainer.EdgeTunnelStats)
+		}
+
+		update(&stats)
+		return &stats
+	})
+}
+
+func (service *Service) recordTunnelConnection(endpointID portainer.EndpointID) {
+	service.updateTunnelStats(endpointID, func(stats *portainer.EdgeTunnelStats) {
+		now := time.Now()
+
+		stats.Connections++
+		if stats.LastDisconnectionDate != 0 && now.Sub(time.Unix(stats.LastDisconnectionDate, 0)) < reconnectWindow {
+			stats.Reconnections++
+		}
+		stats.LastConnectionDate = now.Unix()
+	})
+}
+
+func (service *Service) recordTunnelDisconnection(endpointID portainer.EndpointID, timeout bool) {
+	service.updateTunnelStats(endpointID, func(stats *portainer.EdgeTunnelStats) {
+		now := time.Now().Unix()
+
+		stats.LastDisconnectionDate = now
+		if timeout {
+			stats.Timeouts++
+			stats.LastError = "The agent did not open the tunnel before the timeout"
+			stats.LastErrorDate = now
+		}
+	})
+}
@@ -14,28 +14,32 @@ import (
 	portainer "github.com/portainer/portainer/api"
 )
 
-const (
-	minAvailablePort = 49152
-	maxAvailablePort = 65535
-)
-
-// getUnusedPort is used to generate an unused random port in the dynamic port range.
-// Dynamic ports (also called private ports) are 49152 to 65535.
-func (service *Service) getUnusedPort() int {
-	port := randomInt(minAvailablePort, maxAvailablePort)
-
+// getUnusedPort is used to generate an unused random port in the tunnel port range.
+// The range defaults to the dynamic ports (also called private ports), 49152 to 65535.
+func (service *Service) getUnusedPort() (int, error) {
+	usedPorts := make(map[int]bool)
 	for item := range service.tunnelDetailsMap.IterBuffered() {
 		tunnel := item.Val.(*portainer.TunnelDetails)
-		if tunnel.Port == port {
-			return service.getUnusedPort()
+		if tunnel.Port >= service.minPort && tunnel.Port <= service.maxPort {
+			usedPorts[tunnel.Port] = true
 		}
 	}
 
-	return port
+	if len(usedPorts) > service.maxPort-service.minPort {
+		return 0, errNoTunnelPortAvailable
+	}
+
+	port := randomInt(service.minPort, service.maxPort)
+	for usedPorts[port] {
+		port = randomInt(service.minPort, service.maxPort)
+	}
+
+	return port, nil
 }
 
+// randomInt returns a random integer between min and max, both included
 func randomInt(min, max int) int {
-	return min + rand.Intn(max-min)
+	return min + rand.Intn(max-min+1)
 }
 
 // GetTunnelDetails returns information about the tunnel associated to an endpoint.
@@ -102,8 +106,14 @@ func (service *Service) SetTunnelStatusToRequired(endpointID portainer.EndpointI
 			return err
 		}
 
+		port, err := service.getUnusedPort()
+		if err != nil {
+			service.SetTunnelError(endpointID, err.Error())
+			return err
+		}
+
 		tunnel.Status = portainer.EdgeAgentManagementRequired
-		tunnel.Port = service.getUnusedPort()
+		tunnel.Port = port
 		tunnel.LastActivity = time.Now()
 
 		username, password := generateRandomCredentials()
@@ -121,6 +131,8 @@ func (service *Service) SetTunnelStatusToRequired(endpointID portainer.EndpointI
 
 		key := strconv.Itoa(int(endpointID))
 		service.tunnelDetailsMap.Set(key, tunnel)
+
+		service.recordTunnelConnection(endpointID)
 	}
 
 	return nil
@@ -2,13 +2,14 @@ package cli
 
 import (
 	"errors"
-	"log"
 	"time"
 
 	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/internal/featureflag"
 
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/spf13/viper"
@@ -23,6 +24,11 @@ var (
 	errSocketOrNamedPipeNotFound     = errors.New("Unable to locate Unix socket or named pipe")
 	errInvalidSnapshotInterval       = errors.New("Invalid snapshot interval")
 	errAdminPassExcludeAdminPassFile = errors.New("Cannot use --admin-password with --admin-password-file")
+	errGenerateAdminPassWithoutFile  = errors.New("Cannot use --generate-admin-password without --admin-password-file")
+	errInvalidAdminInitTimeout       = errors.New("Invalid admin init timeout")
+	errInvalidRequestSizeLimit       = errors.New("Invalid request size limit, the value must be positive")
+	errInvalidAPIRateLimit           = errors.New("Invalid API rate limit, the rate must be positive and the burst at least 1")
+	errInvalidTunnelPortRange        = errors.New("Invalid tunnel port range, the range must be formatted as min-max with ports between 1 and 65535")
 )
 
 // ParseFlags parse the CLI flags and return a portainer.Flags struct
@@ -33,11 +39,13 @@ func (*Service) ParseFlags(version string) (*portainer.CLIFlags, error) {
 		Addr:                      kingpin.Flag("bind", "Address and port to serve Portainer").Default(defaultBindAddress).Short('p').String(),
 		TunnelAddr:                kingpin.Flag("tunnel-addr", "Address to serve the tunnel server").Default(defaultTunnelServerAddress).String(),
 		TunnelPort:                kingpin.Flag("tunnel-port", "Port to serve the tunnel server").Default(defaultTunnelServerPort).String(),
+		TunnelPortRange:           kingpin.Flag("tunnel-port-range", "Range of the ports allocated to the Edge agent tunnels, as min-max").Default(defaultTunnelPortRange).String(),
 		Assets:                    kingpin.Flag("assets", "Path to the assets").Default(defaultAssetsDirectory).Short('a').String(),
 		Data:                      kingpin.Flag("data", "Path to the folder where the data is stored").Default(defaultDataDirectory).Short('d').String(),
 		EndpointURL:               kingpin.Flag("host", "Endpoint URL").Short('H').String(),
+		EnableDebugEndpoints:      kingpin.Flag("enable-debug-endpoints", "Expose the pprof and expvar endpoints under /debug to administrators").Bool(),
 		EnableEdgeComputeFeatures: kingpin.Flag("edge-compute", "Enable Edge Compute features").Bool(),
-		NoAnalytics:               kingpin.Flag("no-analytics", "Disable Analytics in app (deprecated)").Bool(),
+		NoAnalytics:               kingpin.Flag("no-analytics", "Disable the telemetry, overriding the telemetry settings").Bool(),
 		TLS:                       kingpin.Flag("tlsverify", "TLS support").Default(defaultTLS).Bool(),
 		TLSSkipVerify:             kingpin.Flag("tlsskipverify", "Disable TLS server verification").Default(defaultTLSSkipVerify).Bool(),
 		TLSCacert:                 kingpin.Flag("tlscacert", "Path to the CA").Default(defaultTLSCACertPath).String(),
@@ -49,7 +57,21 @@ func (*Service) ParseFlags(version string) (*portainer.CLIFlags, error) {
 		SnapshotInterval:          kingpin.Flag("snapshot-interval", "Duration between each endpoint snapshot job").Default(defaultSnapshotInterval).String(),
 		AdminPassword:             kingpin.Flag("admin-password", "Hashed admin password").String(),
 		AdminPasswordFile:         kingpin.Flag("admin-password-file", "Path to the file containing the password for the admin user").String(),
-		Labels:                    pairs(kingpin.Flag("hide-label", "Hide containers with a specific label in the UI").Short('l')),
+		AdminPasswordOneTime:      kingpin.Flag("admin-password-one-time", "The password of the admin user is a one-time secret that must be changed on the first login").Bool(),
+		GenerateAdminPassword:     kingpin.Flag("generate-admin-password", "Generate a one-time password for the admin user in the file specified with --admin-password-file when this file does not exist").Bool(),
+		AdminInitTimeout:          kingpin.Flag("admin-init-timeout", "Duration after which the initial administrator creation is disabled").Default(defaultAdminInitTimeout).String(),
+		InitLockdown:              kingpin.Flag("init-lockdown", "Lock an instance without administrator after --admin-init-timeout instead of shutting it down, a token issued with --generate-init-token is then required to create the administrator").Bool(),
+		GenerateInitToken:         kingpin.Flag("generate-init-token", "Issue a token unlocking the initialization of a locked instance and exit").Bool(),
+		ImportDockerContexts:      kingpin.Flag("import-docker-contexts", "Path to a Docker CLI configuration or contexts directory, its contexts are imported as endpoints on startup").String(),
+		MaxRequestSize:            kingpin.Flag("max-request-size", "Maximum size in MB of the API request bodies, 0 to disable the limit").Default(defaultMaxRequestSize).Int(),
+		MaxStackFileSize:          kingpin.Flag("max-stack-file-size", "Maximum size in MB of the stack, custom template and Edge job file uploads, 0 to disable the limit").Default(defaultMaxStackFileSize).Int(),
+		MaxTLSFileSize:            kingpin.Flag("max-tls-file-size", "Maximum size in MB of the TLS file uploads, 0 to disable the limit").Default(defaultMaxTLSFileSize).Int(),
+		APIRateLimit:              kingpin.Flag("api-rate-limit", "Maximum number of API requests per second of each user or IP address, 0 to disable the limit").Default(defaultAPIRateLimit).Float64(),
+		APIRateLimitBurst:         kingpin.Flag("api-rate-limit-burst", "Maximum number of API requests of each user or IP address in a burst").Default(defaultAPIRateLimitBurst).Int(),
+		Labels:                    pairs(kingpin.Flag("hide-label", "Hide containers with a specific label from non administrator users").Short('l')),
+		HiddenContainerNames:      kingpin.Flag("hide-container-name", "Hide containers with a name matching a pattern from non administrator users").Strings(),
+		HiddenComposeProjects:     kingpin.Flag("hide-compose-project", "Hide the containers of a compose project from non administrator users").Strings(),
+		FeatureFlags:              kingpin.Flag("feature", "Enable an experimental feature").Strings(),
 		Logo:                      kingpin.Flag("logo", "URL for the logo displayed in the UI").String(),
 		Templates:                 kingpin.Flag("templates", "URL to the templates definitions.").Short('t').String(),
 		OauthAuthorizationUrl:     kingpin.Flag("authorization-url", "Oauth2 authorization url.").String(),
@@ -88,9 +110,6 @@ func getEnv(flag *portainer.CLIFlags) {
 
 // ValidateFlags validates the values of the flags.
 func (*Service) ValidateFlags(flags *portainer.CLIFlags) error {
-
-	displayDeprecationWarnings(flags)
-
 	err := validateEndpointURL(*flags.EndpointURL)
 	if err != nil {
 		return err
@@ -105,13 +124,36 @@ func (*Service) ValidateFlags(flags *portainer.CLIFlags) error {
 		return errAdminPassExcludeAdminPassFile
 	}
 
-	return nil
-}
+	if *flags.GenerateAdminPassword && *flags.AdminPasswordFile == "" {
+		return errGenerateAdminPassWithoutFile
+	}
+
+	timeout, err := time.ParseDuration(*flags.AdminInitTimeout)
+	if err != nil || timeout <= 0 {
+		return errInvalidAdminInitTimeout
+	}
+
+	if *flags.MaxRequestSize < 0 || *flags.MaxStackFileSize < 0 || *flags.MaxTLSFileSize < 0 {
+		return errInvalidRequestSizeLimit
+	}
+
+	if *flags.APIRateLimit < 0 || *flags.APIRateLimitBurst < 1 {
+		return errInvalidAPIRateLimit
+	}
 
-func displayDeprecationWarnings(flags *portainer.CLIFlags) {
-	if *flags.NoAnalytics {
-		log.Println("Warning: The --no-analytics flag has been kept to allow migration of instances running a previous version of Portainer with this flag enabled, to version 2.0 where enabling this flag will have no effect.")
+	_, _, err = ParsePortRange(*flags.TunnelPortRange)
+	if err != nil {
+		return err
 	}
+
+	for _, flag := range *flags.FeatureFlags {
+		err = featureflag.ValidateFlag(flag)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 func validateEndpointURL(endpointURL string) error {
@@ -134,6 +176,30 @@ func validateEndpointURL(endpointURL string) error {
 	return nil
 }
 
+// ParsePortRange parses a port range formatted as min-max and returns its bounds
+func ParsePortRange(portRange string) (int, int, error) {
+	bounds := strings.SplitN(portRange, "-", 2)
+	if len(bounds) != 2 {
+		return 0, 0, errInvalidTunnelPortRange
+	}
+
+	minPort, err := strconv.Atoi(strings.TrimSpace(bounds[0]))
+	if err != nil {
+		return 0, 0, errInvalidTunnelPortRange
+	}
+
+	maxPort, err := strconv.Atoi(strings.TrimSpace(bounds[1]))
+	if err != nil {
+		return 0, 0, errInvalidTunnelPortRange
+	}
+
+	if minPort < 1 || maxPort > 65535 || minPort > maxPort {
+		return 0, 0, errInvalidTunnelPortRange
+	}
+
+	return minPort, maxPort, nil
+}
+
 func validateSnapshotInterval(snapshotInterval string) error {
 	if snapshotInterval != defaultSnapshotInterval {
 		_, err := time.ParseDuration(snapshotInterval)
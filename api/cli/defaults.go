@@ -6,6 +6,7 @@ const (
 	defaultBindAddress         = ":9000"
 	defaultTunnelServerAddress = "0.0.0.0"
 	defaultTunnelServerPort    = "8000"
+	defaultTunnelPortRange     = "49152-65535"
 	defaultDataDirectory       = "/data"
 	defaultAssetsDirectory     = "./"
 	defaultTLS                 = "false"
@@ -17,4 +18,10 @@ const (
 	defaultSSLCertPath         = "/certs/portainer.crt"
 	defaultSSLKeyPath          = "/certs/portainer.key"
 	defaultSnapshotInterval    = "5m"
+	defaultAdminInitTimeout    = "5m"
+	defaultMaxRequestSize      = "10"
+	defaultMaxStackFileSize    = "10"
+	defaultMaxTLSFileSize      = "1"
+	defaultAPIRateLimit        = "20"
+	defaultAPIRateLimitBurst   = "200"
 )
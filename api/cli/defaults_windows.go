@@ -4,6 +4,7 @@ const (
 	defaultBindAddress         = ":9000"
 	defaultTunnelServerAddress = "0.0.0.0"
 	defaultTunnelServerPort    = "8000"
+	defaultTunnelPortRange     = "49152-65535"
 	defaultDataDirectory       = "C:\\data"
 	defaultAssetsDirectory     = "./"
 	defaultTLS                 = "false"
@@ -15,4 +16,10 @@ const (
 	defaultSSLCertPath         = "C:\\certs\\portainer.crt"
 	defaultSSLKeyPath          = "C:\\certs\\portainer.key"
 	defaultSnapshotInterval    = "5m"
+	defaultAdminInitTimeout    = "5m"
+	defaultMaxRequestSize      = "10"
+	defaultMaxStackFileSize    = "10"
+	defaultMaxTLSFileSize      = "1"
+	defaultAPIRateLimit        = "20"
+	defaultAPIRateLimitBurst   = "200"
 )
@@ -1,6 +1,11 @@
 package main
 
 import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"io/ioutil"
 	"log"
 	"os"
 	"strings"
@@ -17,12 +22,28 @@ import (
 	"github.com/portainer/portainer/api/git"
 	"github.com/portainer/portainer/api/http"
 	"github.com/portainer/portainer/api/http/client"
+	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/alerting"
+	"github.com/portainer/portainer/api/internal/autoheal"
+	"github.com/portainer/portainer/api/internal/cleanup"
+	"github.com/portainer/portainer/api/internal/diagnostics"
+	"github.com/portainer/portainer/api/internal/dockercontext"
+	"github.com/portainer/portainer/api/internal/featureflag"
+	"github.com/portainer/portainer/api/internal/loadbalancer"
+	"github.com/portainer/portainer/api/internal/notification"
+	"github.com/portainer/portainer/api/internal/report"
+	"github.com/portainer/portainer/api/internal/retention"
+	"github.com/portainer/portainer/api/internal/scaling"
 	"github.com/portainer/portainer/api/internal/snapshot"
+	"github.com/portainer/portainer/api/internal/task"
+	"github.com/portainer/portainer/api/internal/telemetry"
+	"github.com/portainer/portainer/api/internal/useractivity"
 	"github.com/portainer/portainer/api/jwt"
 	"github.com/portainer/portainer/api/kubernetes"
 	kubecli "github.com/portainer/portainer/api/kubernetes/cli"
 	"github.com/portainer/portainer/api/ldap"
 	"github.com/portainer/portainer/api/libcompose"
+	"github.com/portainer/portainer/api/mail"
 	"github.com/portainer/portainer/api/oauth"
 )
 
@@ -124,11 +145,51 @@ func initKubernetesClientFactory(signatureService portainer.DigitalSignatureServ
 	return kubecli.NewClientFactory(signatureService, reverseTunnelService, instanceID)
 }
 
-func initSnapshotService(snapshotInterval string, dataStore portainer.DataStore, dockerClientFactory *docker.ClientFactory, kubernetesClientFactory *kubecli.ClientFactory) (portainer.SnapshotService, error) {
+func initFeatureFlagService(dataStore portainer.DataStore, flags *portainer.CLIFlags) portainer.FeatureFlagService {
+	return featureflag.NewService(dataStore, *flags.FeatureFlags)
+}
+
+func initMailService() portainer.MailService {
+	return mail.NewService()
+}
+
+func initReportService(dataStore portainer.DataStore, mailService portainer.MailService) portainer.ReportService {
+	return report.NewService(dataStore, mailService)
+}
+
+func initTelemetryService(dataStore portainer.DataStore, flags *portainer.CLIFlags) (portainer.TelemetryService, error) {
+	if *flags.NoAnalytics {
+		settings, err := dataStore.Settings().Settings()
+		if err != nil {
+			return nil, err
+		}
+
+		if settings.Telemetry.Mode != portainer.TelemetryDisabled {
+			log.Println("Telemetry disabled by the --no-analytics flag")
+			settings.Telemetry.Mode = portainer.TelemetryDisabled
+			err = dataStore.Settings().UpdateSettings(settings)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return telemetry.NewService(dataStore), nil
+}
+
+func initNotificationService(dataStore portainer.DataStore, mailService portainer.MailService) portainer.NotificationService {
+	return notification.NewService(dataStore, mailService)
+}
+
+func initAlertService(dataStore portainer.DataStore, notificationService portainer.NotificationService) portainer.AlertService {
+	return alerting.NewService(dataStore, notificationService)
+}
+
+func initSnapshotService(snapshotInterval string, dataStore portainer.DataStore, dockerClientFactory *docker.ClientFactory, kubernetesClientFactory *kubecli.ClientFactory, alertService portainer.AlertService) (portainer.SnapshotService, error) {
 	dockerSnapshotter := docker.NewSnapshotter(dockerClientFactory)
 	kubernetesSnapshotter := kubernetes.NewSnapshotter(kubernetesClientFactory)
 
-	snapshotService, err := snapshot.NewService(snapshotInterval, dataStore, dockerSnapshotter, kubernetesSnapshotter)
+	snapshotService, err := snapshot.NewService(snapshotInterval, dataStore, dockerSnapshotter, kubernetesSnapshotter, alertService)
 	if err != nil {
 		return nil, err
 	}
@@ -190,6 +251,14 @@ func updateSettingsFromFlags(dataStore portainer.DataStore, flags *portainer.CLI
 		settings.BlackListedLabels = *flags.Labels
 	}
 
+	if len(*flags.HiddenContainerNames) > 0 {
+		settings.HiddenContainerNames = *flags.HiddenContainerNames
+	}
+
+	if len(*flags.HiddenComposeProjects) > 0 {
+		settings.HiddenComposeProjects = *flags.HiddenComposeProjects
+	}
+
 	return dataStore.Settings().UpdateSettings(settings)
 }
 
@@ -331,8 +400,70 @@ func initEndpoint(flags *portainer.CLIFlags, dataStore portainer.DataStore, snap
 	return createUnsecuredEndpoint(*flags.EndpointURL, dataStore, snapshotService)
 }
 
-func terminateIfNoAdminCreated(dataStore portainer.DataStore) {
-	timer1 := time.NewTimer(5 * time.Minute)
+// importDockerContexts creates an endpoint for each context of a Docker CLI contexts directory.
+// The contexts matching the name of an existing endpoint are skipped, as well as the contexts using SSH
+// as they require a private key that is not part of the context.
+func importDockerContexts(directory string, dataStore portainer.DataStore, fileService portainer.FileService, snapshotService portainer.SnapshotService) error {
+	contexts, err := dockercontext.LoadDirectory(directory)
+	if err != nil {
+		return err
+	}
+
+	endpoints, err := dataStore.Endpoint().Endpoints()
+	if err != nil {
+		return err
+	}
+
+	names := make(map[string]bool)
+	for _, endpoint := range endpoints {
+		names[endpoint.Name] = true
+	}
+
+	for _, context := range contexts {
+		if names[context.Name] {
+			log.Printf("An endpoint named %s already exists. Skipping the Docker context.", context.Name)
+			continue
+		}
+
+		if strings.HasPrefix(context.Host, "ssh://") {
+			log.Printf("The Docker context %s uses SSH and requires a private key. Skipping the Docker context, import it through the API instead.", context.Name)
+			continue
+		}
+
+		endpoint, err := dockercontext.NewEndpoint(&context, dataStore.Endpoint().GetNextIdentifier(), nil, fileService)
+		if err != nil {
+			log.Printf("Unable to import the Docker context %s (err=%s)", context.Name, err)
+			continue
+		}
+
+		err = snapshotService.SnapshotEndpoint(endpoint)
+		if err != nil {
+			log.Printf("http error: endpoint snapshot error (endpoint=%s, URL=%s) (err=%s)\n", endpoint.Name, endpoint.URL, err)
+		}
+
+		err = dataStore.Endpoint().CreateEndpoint(endpoint)
+		if err != nil {
+			dockercontext.DeleteEndpointFiles(endpoint, fileService)
+			return err
+		}
+
+		err = dataStore.EndpointRelation().CreateEndpointRelation(&portainer.EndpointRelation{
+			EndpointID: endpoint.ID,
+			EdgeStacks: map[portainer.EdgeStackID]bool{},
+		})
+		if err != nil {
+			return err
+		}
+
+		names[context.Name] = true
+		log.Printf("Imported the Docker context %s as endpoint %d.", context.Name, endpoint.ID)
+	}
+
+	return nil
+}
+
+func terminateIfNoAdminCreated(dataStore portainer.DataStore, timeout time.Duration) {
+	timer1 := time.NewTimer(timeout)
 	<-timer1.C
 
 	users, err := dataStore.User().UsersByRole(portainer.AdministratorRole)
@@ -341,16 +472,127 @@ func terminateIfNoAdminCreated(dataStore portainer.DataStore) {
 	}
 
 	if len(users) == 0 {
-		log.Fatal("No administrator account was created after 5 min. Shutting down the Portainer instance for security reasons.")
+		log.Fatalf("No administrator account was created after %s. Shutting down the Portainer instance for security reasons.", timeout)
 		return
 	}
 }
 
+// lockdownIfNoAdminCreated locks the initialization of the instance when no administrator was created
+// before the timeout. The lock is persisted in the data directory so that a restart does not reopen
+// the initialization window, it is removed once the administrator is created with an init token.
+func lockdownIfNoAdminCreated(dataStore portainer.DataStore, fileService portainer.FileService, timeout time.Duration) {
+	locked, err := fileService.InitLocked()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if !locked {
+		timer1 := time.NewTimer(timeout)
+		<-timer1.C
+	}
+
+	users, err := dataStore.User().UsersByRole(portainer.AdministratorRole)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if len(users) == 0 {
+		err = fileService.StoreInitLock()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		log.Printf("No administrator account was created after %s. The initialization of the Portainer instance is locked for security reasons, run portainer --generate-init-token to issue a token unlocking it.", timeout)
+	}
+}
+
+// generateInitToken issues a token unlocking the initialization of a locked instance and prints it
+func generateInitToken(fileService portainer.FileService) {
+	secret := make([]byte, 24)
+	_, err := rand.Read(secret)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(secret)
+
+	err = fileService.StoreInitToken(token)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println(token)
+}
+
+// initAdminPassword returns the hash of the admin password specified through the CLI flags and whether
+// it is a one-time password that must be changed on the first login. When the admin password file
+// does not exist, a one-time password is generated and written to this file if --generate-admin-password
+// is specified, otherwise an error is returned.
+func initAdminPassword(flags *portainer.CLIFlags, fileService portainer.FileService, cryptoService portainer.CryptoService) (string, bool, error) {
+	if *flags.AdminPassword != "" {
+		return *flags.AdminPassword, *flags.AdminPasswordOneTime, nil
+	}
+
+	exists, err := fileService.FileExists(*flags.AdminPasswordFile)
+	if err != nil {
+		return "", false, err
+	}
+
+	if !exists {
+		if !*flags.GenerateAdminPassword {
+			return "", false, fmt.Errorf("Unable to find the admin password file %s, use --generate-admin-password to generate a one-time password in this file", *flags.AdminPasswordFile)
+		}
+
+		password, err := generateOneTimePassword(*flags.AdminPasswordFile)
+		if err != nil {
+			return "", false, err
+		}
+		log.Printf("Generated a one-time password for the admin user in %s, it must be changed on the first login.", *flags.AdminPasswordFile)
+
+		hash, err := cryptoService.Hash(password)
+		return hash, true, err
+	}
+
+	content, err := fileService.GetFileContent(*flags.AdminPasswordFile)
+	if err != nil {
+		return "", false, err
+	}
+
+	hash, err := cryptoService.Hash(strings.TrimSuffix(string(content), "\n"))
+	return hash, *flags.AdminPasswordOneTime, err
+}
+
+// generateOneTimePassword generates a random password and writes it to a file only readable by its owner
+func generateOneTimePassword(path string) (string, error) {
+	secret := make([]byte, 24)
+	_, err := rand.Read(secret)
+	if err != nil {
+		return "", err
+	}
+
+	password := base64.RawURLEncoding.EncodeToString(secret)
+
+	err = ioutil.WriteFile(path, []byte(password+"\n"), 0600)
+	if err != nil {
+		return "", err
+	}
+
+	return password, nil
+}
+
 func main() {
+	logBuffer := diagnostics.NewLogBuffer(1000)
+	log.SetOutput(io.MultiWriter(os.Stderr, logBuffer))
+
 	flags := initCLI()
 
 	fileService := initFileService(*flags.Data)
 
+	if *flags.GenerateInitToken {
+		generateInitToken(fileService)
+		return
+	}
+
 	dataStore := initDataStore(*flags.Data, fileService)
 	defer dataStore.Close()
 
@@ -374,7 +616,12 @@ func main() {
 		log.Fatal(err)
 	}
 
-	reverseTunnelService := chisel.NewService(dataStore)
+	tunnelMinPort, tunnelMaxPort, err := cli.ParsePortRange(*flags.TunnelPortRange)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	reverseTunnelService := chisel.NewService(dataStore, tunnelMinPort, tunnelMaxPort)
 
 	instanceID, err := dataStore.Version().InstanceID()
 	if err != nil {
@@ -384,12 +631,52 @@ func main() {
 	dockerClientFactory := initDockerClientFactory(digitalSignatureService, reverseTunnelService)
 	kubernetesClientFactory := initKubernetesClientFactory(digitalSignatureService, reverseTunnelService, instanceID)
 
-	snapshotService, err := initSnapshotService(*flags.SnapshotInterval, dataStore, dockerClientFactory, kubernetesClientFactory)
+	featureFlagService := initFeatureFlagService(dataStore, flags)
+
+	mailService := initMailService()
+
+	notificationService := initNotificationService(dataStore, mailService)
+
+	alertService := initAlertService(dataStore, notificationService)
+
+	snapshotService, err := initSnapshotService(*flags.SnapshotInterval, dataStore, dockerClientFactory, kubernetesClientFactory, alertService)
 	if err != nil {
 		log.Fatal(err)
 	}
 	snapshotService.Start()
 
+	reportService := initReportService(dataStore, mailService)
+	reportService.Start()
+
+	telemetryService, err := initTelemetryService(dataStore, flags)
+	if err != nil {
+		log.Fatal(err)
+	}
+	telemetryService.Start()
+
+	userActivityLogger := useractivity.NewService(dataStore)
+
+	orphanCleanupService := cleanup.NewService(dataStore, dockerClientFactory)
+	orphanCleanupService.Start()
+
+	retentionService := retention.NewService(dataStore)
+	retentionService.Start()
+
+	scalingScheduler := scaling.NewScheduler(dataStore, dockerClientFactory, kubernetesClientFactory, notificationService)
+	scalingScheduler.Start()
+
+	autoHealService := autoheal.NewService(dataStore, dockerClientFactory, notificationService)
+	autoHealService.Start()
+
+	loadBalancerService := loadbalancer.NewService(dataStore, dockerClientFactory)
+	loadBalancerService.Start()
+
+	taskManager := task.NewManager(dataStore)
+	err = taskManager.Start()
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	swarmStackManager, err := initSwarmStackManager(*flags.Assets, *flags.Data, digitalSignatureService, fileService, reverseTunnelService)
 	if err != nil {
 		log.Fatal(err)
@@ -418,34 +705,34 @@ func main() {
 		log.Fatal(err)
 	}
 
-	adminPasswordHash := ""
-	if *flags.AdminPasswordFile != "" {
-		content, err := fileService.GetFileContent(*flags.AdminPasswordFile)
+	if *flags.ImportDockerContexts != "" {
+		err = importDockerContexts(*flags.ImportDockerContexts, dataStore, fileService, snapshotService)
 		if err != nil {
 			log.Fatal(err)
 		}
-		adminPasswordHash, err = cryptoService.Hash(strings.TrimSuffix(string(content), "\n"))
-		if err != nil {
-			log.Fatal(err)
-		}
-	} else if *flags.AdminPassword != "" {
-		adminPasswordHash = *flags.AdminPassword
 	}
 
-	if adminPasswordHash != "" {
+	if *flags.AdminPassword != "" || *flags.AdminPasswordFile != "" {
 		users, err := dataStore.User().UsersByRole(portainer.AdministratorRole)
 		if err != nil {
 			log.Fatal(err)
 		}
 
 		if len(users) == 0 {
+			adminPasswordHash, oneTime, err := initAdminPassword(flags, fileService, cryptoService)
+			if err != nil {
+				log.Fatal(err)
+			}
+
 			log.Println("Created admin user with the given password.")
 			user := &portainer.User{
-				Username: "admin",
-				Role:     portainer.AdministratorRole,
-				Password: adminPasswordHash,
+				Username:               "admin",
+				Role:                   portainer.AdministratorRole,
+				Password:               adminPasswordHash,
+				PasswordChangedDate:    time.Now().Unix(),
+				PasswordChangeRequired: oneTime,
 			}
-			err := dataStore.User().CreateUser(user)
+			err = dataStore.User().CreateUser(user)
 			if err != nil {
 				log.Fatal(err)
 			}
@@ -454,7 +741,14 @@ func main() {
 		}
 	}
 
-	go terminateIfNoAdminCreated(dataStore)
+	adminInitTimeout, _ := time.ParseDuration(*flags.AdminInitTimeout)
+	adminInitLockdown := *flags.InitLockdown
+
+	if adminInitLockdown {
+		go lockdownIfNoAdminCreated(dataStore, fileService, adminInitTimeout)
+	} else {
+		go terminateIfNoAdminCreated(dataStore, adminInitTimeout)
+	}
 
 	err = reverseTunnelService.StartTunnelServer(*flags.TunnelAddr, *flags.TunnelPort, snapshotService)
 	if err != nil {
@@ -462,22 +756,45 @@ func main() {
 	}
 
 	var server portainer.Server = &http.Server{
-		ReverseTunnelService:    reverseTunnelService,
-		Status:                  applicationStatus,
-		BindAddress:             *flags.Addr,
-		AssetsPath:              *flags.Assets,
+		ReverseTunnelService: reverseTunnelService,
+		Status:               applicationStatus,
+		BindAddress:          *flags.Addr,
+		AssetsPath:           *flags.Assets,
+		DataPath:             *flags.Data,
+		LogBuffer:            logBuffer,
+		EnableDebugEndpoints: *flags.EnableDebugEndpoints,
+		AdminInitTimeout:     adminInitTimeout,
+		AdminInitLockdown:    adminInitLockdown,
+		RequestSizeLimits: security.RequestSizeLimits{
+			Default:   int64(*flags.MaxRequestSize) << 20,
+			StackFile: int64(*flags.MaxStackFileSize) << 20,
+			TLSFile:   int64(*flags.MaxTLSFileSize) << 20,
+		},
+		APIRateLimit:            *flags.APIRateLimit,
+		APIRateLimitBurst:       *flags.APIRateLimitBurst,
 		DataStore:               dataStore,
 		SwarmStackManager:       swarmStackManager,
 		ComposeStackManager:     composeStackManager,
 		KubernetesDeployer:      kubernetesDeployer,
 		CryptoService:           cryptoService,
 		JWTService:              jwtService,
+		FeatureFlagService:      featureFlagService,
 		FileService:             fileService,
 		LDAPService:             ldapService,
+		LoadBalancerService:     loadBalancerService,
+		MailService:             mailService,
+		NotificationService:     notificationService,
 		OAuthService:            oauthService,
+		ReportService:           reportService,
 		GitService:              gitService,
 		SignatureService:        digitalSignatureService,
 		SnapshotService:         snapshotService,
+		TelemetryService:        telemetryService,
+		UserActivityLogger:      userActivityLogger,
+		RetentionService:        retentionService,
+		OrphanCleanupService:    orphanCleanupService,
+		ScalingScheduler:        scalingScheduler,
+		TaskManager:             taskManager,
 		SSL:                     *flags.SSL,
 		SSLCert:                 *flags.SSLCert,
 		SSLKey:                  *flags.SSLKey,
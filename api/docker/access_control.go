@@ -0,0 +1,36 @@
+package docker
+
+import (
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/internal/authorization"
+)
+
+// ResourceControlForResource returns the resource control associated to a Docker resource. When no resource control
+// is directly associated to the resource, the resource control of the service or stack referenced by the resource
+// labels is returned instead. Returns nil if no resource control can be found.
+func ResourceControlForResource(resourceID string, resourceType portainer.ResourceControlType, labels map[string]string, resourceControls []portainer.ResourceControl) *portainer.ResourceControl {
+	resourceControl := authorization.GetResourceControlByResourceIDAndType(resourceID, resourceType, resourceControls)
+	if resourceControl != nil {
+		return resourceControl
+	}
+
+	if serviceID, ok := labels[ServiceIDLabel]; ok {
+		resourceControl = authorization.GetResourceControlByResourceIDAndType(serviceID, portainer.ServiceResourceControl, resourceControls)
+		if resourceControl != nil {
+			return resourceControl
+		}
+	}
+
+	if stackName, ok := labels[SwarmStackNameLabel]; ok {
+		resourceControl = authorization.GetResourceControlByResourceIDAndType(stackName, portainer.StackResourceControl, resourceControls)
+		if resourceControl != nil {
+			return resourceControl
+		}
+	}
+
+	if stackName, ok := labels[ComposeStackNameLabel]; ok {
+		return authorization.GetResourceControlByResourceIDAndType(stackName, portainer.StackResourceControl, resourceControls)
+	}
+
+	return nil
+}
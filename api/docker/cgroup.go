@@ -0,0 +1,120 @@
+package docker
+
+import (
+	"fmt"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	portainer "github.com/portainer/portainer/api"
+)
+
+const (
+	securityOptionRootless         = "name=rootless"
+	securityOptionCgroupNamespaces = "name=cgroupns"
+)
+
+// RuntimeCapabilities returns the cgroup version and the resource limits supported by a Docker daemon.
+// The CgroupVersion field of the Docker API 1.41 is not decoded by the Docker client, the cgroup
+// namespaces only enabled by default on cgroup v2 hosts are used to detect the cgroup version instead.
+func RuntimeCapabilities(info *types.Info) portainer.DockerRuntimeCapabilities {
+	if info.OSType == OSTypeWindows {
+		return portainer.DockerRuntimeCapabilities{
+			MemoryLimit: true,
+			CPUQuota:    true,
+			CPUShares:   true,
+			Stats:       true,
+		}
+	}
+
+	capabilities := portainer.DockerRuntimeCapabilities{
+		CgroupVersion:  1,
+		MemoryLimit:    info.MemoryLimit,
+		SwapLimit:      info.SwapLimit,
+		KernelMemory:   info.KernelMemory,
+		CPUQuota:       info.CPUCfsQuota && info.CPUCfsPeriod,
+		CPUShares:      info.CPUShares,
+		CPUSet:         info.CPUSet,
+		PidsLimit:      info.PidsLimit,
+		OomKillDisable: info.OomKillDisable,
+		Stats:          true,
+	}
+
+	for _, option := range info.SecurityOptions {
+		switch option {
+		case securityOptionRootless:
+			capabilities.Rootless = true
+		case securityOptionCgroupNamespaces:
+			capabilities.CgroupVersion = 2
+		}
+	}
+
+	// the kernel memory is not accounted separately on cgroup v2 hosts
+	if capabilities.CgroupVersion == 2 {
+		capabilities.KernelMemory = false
+	}
+
+	// rootless daemons can only delegate the resource controllers of cgroup v2
+	if capabilities.Rootless && capabilities.CgroupVersion == 1 {
+		return portainer.DockerRuntimeCapabilities{
+			CgroupVersion: 1,
+			Rootless:      true,
+		}
+	}
+
+	return capabilities
+}
+
+// statsCgroupVersion returns the cgroup version of the memory statistics of a Linux container, 0 when unknown.
+// The cgroup v1 statistics include the hierarchical total_* values which are not available with cgroup v2.
+func statsCgroupVersion(stats *types.StatsJSON) int {
+	if _, ok := stats.MemoryStats.Stats["total_inactive_file"]; ok {
+		return 1
+	}
+	if _, ok := stats.MemoryStats.Stats["inactive_file"]; ok {
+		return 2
+	}
+	return 0
+}
+
+// ValidateResourceLimits ensures that the resource limits of a container are supported by the Docker daemon of an endpoint.
+// The limits are not validated when the capabilities of the daemon are unknown.
+func ValidateResourceLimits(resources *container.Resources, capabilities portainer.DockerRuntimeCapabilities) error {
+	if capabilities.CgroupVersion == 0 {
+		return nil
+	}
+
+	unsupported := ""
+	switch {
+	case (resources.Memory > 0 || resources.MemoryReservation > 0) && !capabilities.MemoryLimit:
+		unsupported = "memory limits"
+	case resources.MemorySwap > 0 && !capabilities.SwapLimit:
+		unsupported = "swap limits"
+	case resources.KernelMemory > 0 && !capabilities.KernelMemory:
+		unsupported = "kernel memory limits"
+	case (resources.NanoCPUs > 0 || resources.CPUQuota > 0) && !capabilities.CPUQuota:
+		unsupported = "CPU limits"
+	case resources.CPUShares > 0 && !capabilities.CPUShares:
+		unsupported = "CPU shares"
+	case (resources.CpusetCpus != "" || resources.CpusetMems != "") && !capabilities.CPUSet:
+		unsupported = "CPU sets"
+	case resources.PidsLimit != nil && *resources.PidsLimit > 0 && !capabilities.PidsLimit:
+		unsupported = "PIDs limits"
+	case resources.OomKillDisable != nil && *resources.OomKillDisable && !capabilities.OomKillDisable:
+		unsupported = "disabling the OOM killer"
+	default:
+		return nil
+	}
+
+	if capabilities.Rootless && capabilities.CgroupVersion == 1 {
+		return fmt.Errorf("%s are not supported by the rootless Docker daemon of this endpoint, cgroup v2 is required", unsupported)
+	}
+	return fmt.Errorf("%s are not supported by the Docker daemon of this endpoint (cgroup v%d)", unsupported, capabilities.CgroupVersion)
+}
+
+// EndpointRuntimeCapabilities returns the runtime capabilities of the Docker daemon of an endpoint, based on its last snapshot
+func EndpointRuntimeCapabilities(endpoint *portainer.Endpoint) portainer.DockerRuntimeCapabilities {
+	if len(endpoint.Snapshots) == 0 {
+		return portainer.DockerRuntimeCapabilities{}
+	}
+	return endpoint.Snapshots[0].Runtime
+}
@@ -0,0 +1,56 @@
+package docker
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types"
+)
+
+func TestRuntimeCapabilities(t *testing.T) {
+	info := &types.Info{OSType: OSTypeLinux, MemoryLimit: true, KernelMemory: true, CPUCfsQuota: true, CPUCfsPeriod: true, PidsLimit: true}
+
+	capabilities := RuntimeCapabilities(info)
+	if capabilities.CgroupVersion != 1 || capabilities.Rootless || !capabilities.MemoryLimit || !capabilities.KernelMemory || !capabilities.CPUQuota {
+		t.Errorf("unexpected cgroup v1 capabilities: %+v", capabilities)
+	}
+
+	info.SecurityOptions = []string{"name=seccomp,profile=default", "name=cgroupns"}
+	capabilities = RuntimeCapabilities(info)
+	if capabilities.CgroupVersion != 2 || capabilities.KernelMemory || !capabilities.MemoryLimit {
+		t.Errorf("unexpected cgroup v2 capabilities: %+v", capabilities)
+	}
+
+	info.SecurityOptions = []string{"name=rootless", "name=cgroupns"}
+	capabilities = RuntimeCapabilities(info)
+	if !capabilities.Rootless || !capabilities.MemoryLimit || !capabilities.Stats {
+		t.Errorf("expected a rootless daemon on cgroup v2 to support resource limits: %+v", capabilities)
+	}
+
+	info.SecurityOptions = []string{"name=rootless"}
+	capabilities = RuntimeCapabilities(info)
+	if !capabilities.Rootless || capabilities.MemoryLimit || capabilities.PidsLimit || capabilities.Stats {
+		t.Errorf("expected a rootless daemon on cgroup v1 to support no resource limit: %+v", capabilities)
+	}
+}
+
+func TestParseContainerStatsCgroupVersion(t *testing.T) {
+	stats := &types.StatsJSON{}
+	stats.MemoryStats.Usage = 1000
+	stats.MemoryStats.Limit = 1<<64 - 1
+	stats.MemoryStats.Stats = map[string]uint64{"inactive_file": 200, "anon": 800}
+
+	result := ParseContainerStats(stats, OSTypeLinux)
+	if result.CgroupVersion != 2 {
+		t.Errorf("expected cgroup v2 statistics, got version %d", result.CgroupVersion)
+	}
+	if result.MemoryLimit != 0 || result.MemoryPercent != 0 {
+		t.Errorf("expected the unlimited memory to be reported without limit, got %d (%f%%)", result.MemoryLimit, result.MemoryPercent)
+	}
+
+	stats.MemoryStats.Limit = 9223372036854771712
+	stats.MemoryStats.Stats = map[string]uint64{"inactive_file": 200, "total_inactive_file": 200}
+	result = ParseContainerStats(stats, OSTypeLinux)
+	if result.CgroupVersion != 1 || result.MemoryLimit != 0 {
+		t.Errorf("unexpected cgroup v1 statistics: %+v", result)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/docker/docker/client"
@@ -23,6 +24,11 @@ const (
 type ClientFactory struct {
 	signatureService     portainer.DigitalSignatureService
 	reverseTunnelService portainer.ReverseTunnelService
+	sshTunnels           map[portainer.EndpointID]*sshTunnel
+	sshTunnelsMutex      sync.Mutex
+	// apiVersions contains the highest API version supported by the Docker daemon of each endpoint
+	apiVersions      map[portainer.EndpointID]string
+	apiVersionsMutex sync.Mutex
 }
 
 // NewClientFactory returns a new instance of a ClientFactory
@@ -30,35 +36,63 @@ func NewClientFactory(signatureService portainer.DigitalSignatureService, revers
 	return &ClientFactory{
 		signatureService:     signatureService,
 		reverseTunnelService: reverseTunnelService,
+		sshTunnels:           make(map[portainer.EndpointID]*sshTunnel),
+		apiVersions:          make(map[portainer.EndpointID]string),
 	}
 }
 
 // createClient is a generic function to create a Docker client based on
 // a specific endpoint configuration. The nodeName parameter can be used
 // with an agent enabled endpoint to target a specific node in an agent cluster.
+// The client uses the API version negotiated with the Docker daemon of the endpoint.
 func (factory *ClientFactory) CreateClient(endpoint *portainer.Endpoint, nodeName string) (*client.Client, error) {
+	return factory.createClient(endpoint, nodeName, factory.clientAPIVersion(endpoint))
+}
+
+func (factory *ClientFactory) createClient(endpoint *portainer.Endpoint, nodeName, version string) (*client.Client, error) {
 	if endpoint.Type == portainer.AzureEnvironment {
 		return nil, errUnsupportedEnvironmentType
 	} else if endpoint.Type == portainer.AgentOnDockerEnvironment {
-		return createAgentClient(endpoint, factory.signatureService, nodeName)
+		return createAgentClient(endpoint, factory.signatureService, nodeName, version)
 	} else if endpoint.Type == portainer.EdgeAgentOnDockerEnvironment {
-		return createEdgeClient(endpoint, factory.reverseTunnelService, nodeName)
+		return createEdgeClient(endpoint, factory.reverseTunnelService, nodeName, version)
 	}
 
 	if strings.HasPrefix(endpoint.URL, "unix://") || strings.HasPrefix(endpoint.URL, "npipe://") {
-		return createLocalClient(endpoint)
+		return createLocalClient(endpoint, version)
+	} else if strings.HasPrefix(endpoint.URL, "ssh://") {
+		return factory.createSSHClient(endpoint, version)
+	}
+	return createTCPClient(endpoint, version)
+}
+
+func (factory *ClientFactory) createSSHClient(endpoint *portainer.Endpoint, version string) (*client.Client, error) {
+	transport, err := factory.SSHTransport(endpoint)
+	if err != nil {
+		return nil, err
 	}
-	return createTCPClient(endpoint)
+
+	httpCli := &http.Client{
+		Transport: transport,
+		Timeout:   defaultDockerRequestTimeout * time.Second,
+	}
+
+	// the host is only used to build the request URLs, connections are opened through the SSH tunnel
+	return client.NewClientWithOpts(
+		client.WithHost("http://docker"),
+		client.WithVersion(version),
+		client.WithHTTPClient(httpCli),
+	)
 }
 
-func createLocalClient(endpoint *portainer.Endpoint) (*client.Client, error) {
+func createLocalClient(endpoint *portainer.Endpoint, version string) (*client.Client, error) {
 	return client.NewClientWithOpts(
 		client.WithHost(endpoint.URL),
-		client.WithVersion(dockerClientVersion),
+		client.WithVersion(version),
 	)
 }
 
-func createTCPClient(endpoint *portainer.Endpoint) (*client.Client, error) {
+func createTCPClient(endpoint *portainer.Endpoint, version string) (*client.Client, error) {
 	httpCli, err := httpClient(endpoint)
 	if err != nil {
 		return nil, err
@@ -66,12 +100,12 @@ func createTCPClient(endpoint *portainer.Endpoint) (*client.Client, error) {
 
 	return client.NewClientWithOpts(
 		client.WithHost(endpoint.URL),
-		client.WithVersion(dockerClientVersion),
+		client.WithVersion(version),
 		client.WithHTTPClient(httpCli),
 	)
 }
 
-func createEdgeClient(endpoint *portainer.Endpoint, reverseTunnelService portainer.ReverseTunnelService, nodeName string) (*client.Client, error) {
+func createEdgeClient(endpoint *portainer.Endpoint, reverseTunnelService portainer.ReverseTunnelService, nodeName, version string) (*client.Client, error) {
 	httpCli, err := httpClient(endpoint)
 	if err != nil {
 		return nil, err
@@ -87,13 +121,13 @@ func createEdgeClient(endpoint *portainer.Endpoint, reverseTunnelService portain
 
 	return client.NewClientWithOpts(
 		client.WithHost(endpointURL),
-		client.WithVersion(dockerClientVersion),
+		client.WithVersion(version),
 		client.WithHTTPClient(httpCli),
 		client.WithHTTPHeaders(headers),
 	)
 }
 
-func createAgentClient(endpoint *portainer.Endpoint, signatureService portainer.DigitalSignatureService, nodeName string) (*client.Client, error) {
+func createAgentClient(endpoint *portainer.Endpoint, signatureService portainer.DigitalSignatureService, nodeName, version string) (*client.Client, error) {
 	httpCli, err := httpClient(endpoint)
 	if err != nil {
 		return nil, err
@@ -115,7 +149,7 @@ func createAgentClient(endpoint *portainer.Endpoint, signatureService portainer.
 
 	return client.NewClientWithOpts(
 		client.WithHost(endpoint.URL),
-		client.WithVersion(dockerClientVersion),
+		client.WithVersion(version),
 		client.WithHTTPClient(httpCli),
 		client.WithHTTPHeaders(headers),
 	)
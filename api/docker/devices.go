@@ -0,0 +1,65 @@
+package docker
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+// gpuDeviceKeywords are the words found in the names of the GPUs reported by the agents
+var gpuDeviceKeywords = []string{"gpu", "vga", "3d controller", "display controller", "graphics", "tesla", "quadro", "geforce", "radeon"}
+
+// ValidateDeviceRequests ensures that the device requests of a container, used to request GPUs with --gpus,
+// are supported by the Docker API version of the endpoint and can be resolved by a device driver.
+// The API version support is not checked when the version is unknown.
+func ValidateDeviceRequests(requests []container.DeviceRequest, apiVersion string) error {
+	if len(requests) == 0 {
+		return nil
+	}
+
+	if apiVersion != "" && !Capabilities(apiVersion).DeviceRequests {
+		return fmt.Errorf("device requests (--gpus) are not supported by the Docker API version %s of this endpoint, version 1.40 or later is required", apiVersion)
+	}
+
+	for _, request := range requests {
+		if request.Count < -1 {
+			return errors.New("invalid device request count, use -1 to request all the devices")
+		}
+
+		if request.Count != 0 && len(request.DeviceIDs) > 0 {
+			return errors.New("a device request cannot define both a count and device IDs")
+		}
+
+		if request.Driver == "" && !hasCapabilities(request.Capabilities) {
+			return errors.New("a device request must define a driver or capabilities, such as gpu")
+		}
+	}
+
+	return nil
+}
+
+func hasCapabilities(capabilities [][]string) bool {
+	for _, set := range capabilities {
+		if len(set) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// IsGPUDevice returns true when a PCI device of the host is a GPU, based on its vendor and device names
+func IsGPUDevice(vendor, device string) bool {
+	if strings.Contains(strings.ToLower(vendor), "nvidia") {
+		return true
+	}
+
+	device = strings.ToLower(device)
+	for _, keyword := range gpuDeviceKeywords {
+		if strings.Contains(device, keyword) {
+			return true
+		}
+	}
+	return false
+}
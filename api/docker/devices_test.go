@@ -0,0 +1,34 @@
+package docker
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+func TestValidateDeviceRequests(t *testing.T) {
+	gpus := [][]string{{"gpu"}}
+
+	tests := []struct {
+		name       string
+		requests   []container.DeviceRequest
+		apiVersion string
+		valid      bool
+	}{
+		{"all GPUs", []container.DeviceRequest{{Count: -1, Capabilities: gpus}}, "1.40", true},
+		{"GPUs by ID", []container.DeviceRequest{{DeviceIDs: []string{"0", "1"}, Capabilities: gpus}}, "1.41", true},
+		{"driver only", []container.DeviceRequest{{Driver: "nvidia", Count: 1}}, "1.40", true},
+		{"unknown API version", []container.DeviceRequest{{Count: 1, Capabilities: gpus}}, "", true},
+		{"unsupported API version", []container.DeviceRequest{{Count: 1, Capabilities: gpus}}, "1.39", false},
+		{"count and IDs", []container.DeviceRequest{{Count: 1, DeviceIDs: []string{"0"}, Capabilities: gpus}}, "1.40", false},
+		{"invalid count", []container.DeviceRequest{{Count: -2, Capabilities: gpus}}, "1.40", false},
+		{"no driver nor capabilities", []container.DeviceRequest{{Count: 1, Capabilities: [][]string{{}}}}, "1.40", false},
+	}
+
+	for _, test := range tests {
+		err := ValidateDeviceRequests(test.requests, test.apiVersion)
+		if (err == nil) != test.valid {
+			t.Errorf("%s: got error %v, want valid %t", test.name, err, test.valid)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package docker
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+const (
+	healthcheckNone     = "NONE"
+	healthcheckCmd      = "CMD"
+	healthcheckCmdShell = "CMD-SHELL"
+
+	// minimumHealthcheckDuration is the lowest interval, timeout and start period accepted by the Docker daemon
+	minimumHealthcheckDuration = time.Millisecond
+)
+
+// HealthcheckDefinition represents a healthcheck using durations such as 30s instead of nanoseconds.
+// An empty test inherits the test of the image, a test of NONE disables the healthcheck of the image.
+type HealthcheckDefinition struct {
+	Test        []string `json:"Test"`
+	Interval    string   `json:"Interval,omitempty"`
+	Timeout     string   `json:"Timeout,omitempty"`
+	StartPeriod string   `json:"StartPeriod,omitempty"`
+	Retries     int      `json:"Retries"`
+}
+
+// HealthConfig converts a healthcheck definition to the healthcheck configuration of a container
+func (definition *HealthcheckDefinition) HealthConfig() (*container.HealthConfig, error) {
+	config := &container.HealthConfig{
+		Test:    definition.Test,
+		Retries: definition.Retries,
+	}
+
+	durations := []struct {
+		name  string
+		value string
+		field *time.Duration
+	}{
+		{"interval", definition.Interval, &config.Interval},
+		{"timeout", definition.Timeout, &config.Timeout},
+		{"start period", definition.StartPeriod, &config.StartPeriod},
+	}
+
+	for _, duration := range durations {
+		if duration.value == "" {
+			continue
+		}
+
+		value, err := time.ParseDuration(duration.value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid healthcheck %s: %s", duration.name, err)
+		}
+		*duration.field = value
+	}
+
+	return config, ValidateHealthConfig(config)
+}
+
+// ValidateHealthConfig ensures that the healthcheck configuration of a container is accepted by the Docker daemon
+func ValidateHealthConfig(config *container.HealthConfig) error {
+	if config == nil {
+		return nil
+	}
+
+	if len(config.Test) > 0 {
+		switch config.Test[0] {
+		case healthcheckNone:
+			if len(config.Test) > 1 {
+				return errors.New("a healthcheck test of NONE cannot have arguments")
+			}
+		case healthcheckCmd, healthcheckCmdShell:
+			if len(config.Test) < 2 {
+				return fmt.Errorf("a healthcheck test of %s requires a command", config.Test[0])
+			}
+		default:
+			return errors.New("invalid healthcheck test, the first element must be one of: NONE, CMD or CMD-SHELL")
+		}
+	}
+
+	for name, duration := range map[string]time.Duration{"interval": config.Interval, "timeout": config.Timeout, "start period": config.StartPeriod} {
+		if duration != 0 && duration < minimumHealthcheckDuration {
+			return fmt.Errorf("the healthcheck %s cannot be less than 1ms", name)
+		}
+	}
+
+	if config.Retries < 0 {
+		return errors.New("the healthcheck retries cannot be negative")
+	}
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package docker
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHealthcheckDefinition(t *testing.T) {
+	definition := &HealthcheckDefinition{
+		Test:        []string{"CMD-SHELL", "curl -f http://localhost/ || exit 1"},
+		Interval:    "30s",
+		Timeout:     "5s",
+		StartPeriod: "1m",
+		Retries:     3,
+	}
+
+	config, err := definition.HealthConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Interval != 30*time.Second || config.Timeout != 5*time.Second || config.StartPeriod != time.Minute || config.Retries != 3 {
+		t.Errorf("unexpected healthcheck configuration: %+v", config)
+	}
+
+	invalid := []*HealthcheckDefinition{
+		{Test: []string{"curl", "-f", "http://localhost"}},
+		{Test: []string{"CMD"}},
+		{Test: []string{"NONE", "true"}},
+		{Test: []string{"CMD", "true"}, Interval: "30"},
+		{Test: []string{"CMD", "true"}, Timeout: "10us"},
+		{Test: []string{"CMD", "true"}, Retries: -1},
+	}
+
+	for _, definition := range invalid {
+		_, err := definition.HealthConfig()
+		if err == nil {
+			t.Errorf("expected the healthcheck definition %+v to be rejected", definition)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package docker
+
+import (
+	"path"
+	"strings"
+
+	"github.com/portainer/portainer/api"
+)
+
+// HiddenContainerRules represents the criteria used to hide containers from non administrator users:
+// the labels defined with --hide-label, name patterns and compose projects.
+type HiddenContainerRules struct {
+	Labels          []portainer.Pair
+	NamePatterns    []string
+	ComposeProjects []string
+}
+
+// NewHiddenContainerRules returns the rules hiding containers defined in the settings
+func NewHiddenContainerRules(settings *portainer.Settings) *HiddenContainerRules {
+	return &HiddenContainerRules{
+		Labels:          settings.BlackListedLabels,
+		NamePatterns:    settings.HiddenContainerNames,
+		ComposeProjects: settings.HiddenComposeProjects,
+	}
+}
+
+// Empty returns true when no container is hidden
+func (rules *HiddenContainerRules) Empty() bool {
+	return len(rules.Labels) == 0 && len(rules.NamePatterns) == 0 && len(rules.ComposeProjects) == 0
+}
+
+// IsHidden returns true when a container matches one of the rules. Names are matched against
+// shell patterns, e.g. portainer_agent*.
+func (rules *HiddenContainerRules) IsHidden(names []string, labels map[string]string) bool {
+	for _, name := range names {
+		name = strings.TrimPrefix(name, "/")
+		for _, pattern := range rules.NamePatterns {
+			if match, _ := path.Match(pattern, name); match {
+				return true
+			}
+		}
+	}
+
+	composeProject := labels[ComposeStackNameLabel]
+	for _, project := range rules.ComposeProjects {
+		if composeProject != "" && composeProject == project {
+			return true
+		}
+	}
+
+	for key, value := range labels {
+		for _, hiddenLabel := range rules.Labels {
+			if hiddenLabel.Name == key && hiddenLabel.Value == value {
+				return true
+			}
+		}
+	}
+
+	return false
+}
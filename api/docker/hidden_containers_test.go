@@ -0,0 +1,37 @@
+package docker
+
+import (
+	"testing"
+
+	"github.com/portainer/portainer/api"
+)
+
+func TestHiddenContainerRules(t *testing.T) {
+	rules := &HiddenContainerRules{
+		Labels:          []portainer.Pair{{Name: "io.portainer.hidden", Value: "true"}},
+		NamePatterns:    []string{"portainer_agent*"},
+		ComposeProjects: []string{"monitoring"},
+	}
+
+	cases := []struct {
+		names    []string
+		labels   map[string]string
+		expected bool
+	}{
+		{[]string{"/portainer_agent.1"}, nil, true},
+		{[]string{"/web"}, map[string]string{"io.portainer.hidden": "true"}, true},
+		{[]string{"/web"}, map[string]string{"io.portainer.hidden": "false"}, false},
+		{[]string{"/grafana"}, map[string]string{ComposeStackNameLabel: "monitoring"}, true},
+		{[]string{"/web"}, map[string]string{ComposeStackNameLabel: "shop"}, false},
+	}
+
+	for _, c := range cases {
+		if hidden := rules.IsHidden(c.names, c.labels); hidden != c.expected {
+			t.Errorf("IsHidden(%v, %v): got %t, want %t", c.names, c.labels, hidden, c.expected)
+		}
+	}
+
+	if !(&HiddenContainerRules{}).Empty() {
+		t.Error("expected rules without criteria to be empty")
+	}
+}
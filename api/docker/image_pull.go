@@ -0,0 +1,183 @@
+package docker
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/jsonmessage"
+	"github.com/portainer/portainer/api"
+)
+
+const dockerHubServerAddress = "docker.io"
+
+// the download of a layer accounts for most of the pull duration, the extraction covers the rest
+const layerDownloadWeight = 0.7
+
+// registryAuthenticationHeader represents the content of the X-Registry-Auth header
+type registryAuthenticationHeader struct {
+	Username      string `json:"username"`
+	Password      string `json:"password"`
+	Serveraddress string `json:"serveraddress"`
+}
+
+// RegistryServerAddress returns the address of the registry hosting an image.
+// It follows the Docker naming rules: the first component of the image name is a registry address
+// when it contains a dot or a port, or when it is localhost, otherwise the image is hosted on the DockerHub.
+func RegistryServerAddress(image string) string {
+	idx := strings.Index(image, "/")
+	if idx == -1 {
+		return dockerHubServerAddress
+	}
+
+	domain := image[:idx]
+	if strings.ContainsAny(domain, ".:") || domain == "localhost" {
+		return domain
+	}
+
+	return dockerHubServerAddress
+}
+
+// RegistryAuthentication returns the encoded credentials used to pull an image from one of the specified registries
+// or from the DockerHub. An empty string is returned when no credentials are defined for the registry of the image.
+func RegistryAuthentication(image string, registries []portainer.Registry, dockerHub *portainer.DockerHub) (string, error) {
+	var header *registryAuthenticationHeader
+
+	serverAddress := RegistryServerAddress(image)
+	if serverAddress == dockerHubServerAddress {
+		if dockerHub != nil && dockerHub.Authentication {
+			header = &registryAuthenticationHeader{
+				Username:      dockerHub.Username,
+				Password:      dockerHub.Password,
+				Serveraddress: dockerHubServerAddress,
+			}
+		}
+	} else {
+		for _, registry := range registries {
+			if registry.URL == serverAddress && registry.Authentication {
+				header = &registryAuthenticationHeader{
+					Username:      registry.Username,
+					Password:      registry.Password,
+					Serveraddress: registry.URL,
+				}
+				break
+			}
+		}
+	}
+
+	if header == nil {
+		return "", nil
+	}
+
+	data, err := json.Marshal(header)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.URLEncoding.EncodeToString(data), nil
+}
+
+// PullImage pulls an image and reports the progress of each of its layers
+func PullImage(cli *client.Client, image, registryAuth string, reporter portainer.TaskReporter) error {
+	reader, err := cli.ImagePull(context.Background(), image, types.ImagePullOptions{RegistryAuth: registryAuth})
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	reporter.Log(fmt.Sprintf("Pulling image %s", image))
+
+	layers := make(map[string]*portainer.TaskLayer)
+	order := make([]string, 0)
+
+	decoder := json.NewDecoder(reader)
+	for {
+		var message jsonmessage.JSONMessage
+		err := decoder.Decode(&message)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+
+		if message.Error != nil {
+			return message.Error
+		}
+
+		// messages without identifier or reporting the tag being pulled are reported as is
+		if message.ID == "" || message.Progress == nil && !isLayerStatus(message.Status) {
+			if message.Status != "" {
+				reporter.Log(strings.TrimSpace(message.Status + " " + message.ID))
+			}
+			continue
+		}
+
+		layer, ok := layers[message.ID]
+		if !ok {
+			layer = &portainer.TaskLayer{ID: message.ID}
+			layers[message.ID] = layer
+			order = append(order, message.ID)
+		}
+
+		if layer.Status != message.Status {
+			reporter.Log(fmt.Sprintf("%s: %s", message.ID, message.Status))
+		}
+
+		layer.Status = message.Status
+		if message.Progress != nil {
+			layer.Current = message.Progress.Current
+			layer.Total = message.Progress.Total
+		}
+
+		reporter.SetLayer(*layer)
+		reporter.SetProgress(pullProgress(layers, order), "Pulling image "+image)
+	}
+
+	return nil
+}
+
+func isLayerStatus(status string) bool {
+	switch status {
+	case "Pulling fs layer", "Waiting", "Downloading", "Verifying Checksum", "Download complete", "Extracting", "Pull complete", "Already exists":
+		return true
+	}
+	return false
+}
+
+// pullProgress returns the overall progress of a pull as a percentage, each layer having the same weight
+func pullProgress(layers map[string]*portainer.TaskLayer, order []string) int {
+	if len(order) == 0 {
+		return 0
+	}
+
+	var total float64
+	for _, id := range order {
+		total += layerProgress(layers[id])
+	}
+
+	return int(total / float64(len(order)) * 100)
+}
+
+func layerProgress(layer *portainer.TaskLayer) float64 {
+	switch layer.Status {
+	case "Downloading":
+		if layer.Total > 0 {
+			return layerDownloadWeight * float64(layer.Current) / float64(layer.Total)
+		}
+	case "Verifying Checksum", "Download complete":
+		return layerDownloadWeight
+	case "Extracting":
+		if layer.Total > 0 {
+			return layerDownloadWeight + (1-layerDownloadWeight)*float64(layer.Current)/float64(layer.Total)
+		}
+		return layerDownloadWeight
+	case "Pull complete", "Already exists":
+		return 1
+	}
+	return 0
+}
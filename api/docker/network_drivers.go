@@ -0,0 +1,138 @@
+package docker
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+
+	"github.com/docker/docker/api/types/network"
+)
+
+const (
+	// MacvlanNetworkDriver is the driver of the networks assigning a MAC address to each container,
+	// the containers are attached to the physical network of the parent interface of the host
+	MacvlanNetworkDriver = "macvlan"
+	// IpvlanNetworkDriver is the driver of the networks sharing the MAC address of the parent interface of the host
+	IpvlanNetworkDriver = "ipvlan"
+)
+
+var (
+	// parentInterfaceRe matches the names of the Linux network interfaces, optionally followed by a VLAN identifier
+	parentInterfaceRe = regexp.MustCompile(`^[a-zA-Z0-9_-][a-zA-Z0-9_.-]{0,14}$`)
+	macvlanModes      = []string{"bridge", "private", "vepa", "passthru"}
+	ipvlanModes       = []string{"l2", "l3"}
+)
+
+// HostNetworkDefinition represents a macvlan or ipvlan network attached to a parent interface of the host
+type HostNetworkDefinition struct {
+	// Driver is one of macvlan or ipvlan
+	Driver string `json:"Driver"`
+	// Parent is the interface of the host the network is attached to, such as eth0 or eth0.10 for a VLAN
+	Parent string `json:"Parent"`
+	// Mode is one of bridge, private, vepa or passthru for macvlan and one of l2 or l3 for ipvlan,
+	// the default mode of the driver is used when it is not specified
+	Mode string `json:"Mode,omitempty"`
+	// Subnet is the subnet of the physical network in CIDR notation
+	Subnet string `json:"Subnet"`
+	// Gateway is the gateway of the physical network, it must be part of the subnet
+	Gateway string `json:"Gateway"`
+	// IPRange is the range of the subnet the addresses of the containers are allocated from, it must not
+	// overlap with the addresses allocated by the DHCP server of the physical network
+	IPRange string `json:"IPRange,omitempty"`
+}
+
+// IsHostNetworkDriver returns true when the driver attaches the networks to a parent interface of the host
+func IsHostNetworkDriver(driver string) bool {
+	return driver == MacvlanNetworkDriver || driver == IpvlanNetworkDriver
+}
+
+// Validate ensures that the definition describes a valid network, the gateway and the IP range must be
+// part of the subnet
+func (definition *HostNetworkDefinition) Validate() error {
+	modes := macvlanModes
+	switch definition.Driver {
+	case MacvlanNetworkDriver:
+	case IpvlanNetworkDriver:
+		modes = ipvlanModes
+	default:
+		return errors.New("invalid network driver: value must be one of macvlan or ipvlan")
+	}
+
+	if !parentInterfaceRe.MatchString(definition.Parent) {
+		return errors.New("invalid parent interface name")
+	}
+
+	if definition.Mode != "" && !contains(modes, definition.Mode) {
+		return fmt.Errorf("invalid %s mode: value must be one of %s", definition.Driver, strings.Join(modes, ", "))
+	}
+
+	_, subnet, err := net.ParseCIDR(definition.Subnet)
+	if err != nil {
+		return errors.New("invalid subnet: value must be in CIDR notation")
+	}
+	subnetSize, _ := subnet.Mask.Size()
+
+	gateway := net.ParseIP(definition.Gateway)
+	if gateway == nil {
+		return errors.New("invalid gateway: value must be an IP address")
+	}
+	if !subnet.Contains(gateway) || gateway.Equal(subnet.IP) {
+		return errors.New("invalid gateway: address must be a host address of the subnet")
+	}
+
+	if definition.IPRange != "" {
+		_, ipRange, err := net.ParseCIDR(definition.IPRange)
+		if err != nil {
+			return errors.New("invalid IP range: value must be in CIDR notation")
+		}
+
+		ipRangeSize, _ := ipRange.Mask.Size()
+		if !subnet.Contains(ipRange.IP) || ipRangeSize < subnetSize {
+			return errors.New("invalid IP range: range must be part of the subnet")
+		}
+	}
+
+	return nil
+}
+
+// DriverOptions returns the options of the driver attaching the network to the parent interface
+func (definition *HostNetworkDefinition) DriverOptions() map[string]string {
+	options := map[string]string{
+		"parent": definition.Parent,
+	}
+	if definition.Mode != "" {
+		options[definition.Driver+"_mode"] = definition.Mode
+	}
+	return options
+}
+
+// IPAM returns the IP address management configuration of the network
+func (definition *HostNetworkDefinition) IPAM() *network.IPAM {
+	return &network.IPAM{
+		Driver: "default",
+		Config: []network.IPAMConfig{
+			{
+				Subnet:  definition.Subnet,
+				Gateway: definition.Gateway,
+				IPRange: definition.IPRange,
+			},
+		},
+	}
+}
+
+// SubnetsOverlap returns true when the two subnets in CIDR notation share addresses
+func SubnetsOverlap(first, second string) bool {
+	_, firstNetwork, err := net.ParseCIDR(first)
+	if err != nil {
+		return false
+	}
+
+	_, secondNetwork, err := net.ParseCIDR(second)
+	if err != nil {
+		return false
+	}
+
+	return firstNetwork.Contains(secondNetwork.IP) || secondNetwork.Contains(firstNetwork.IP)
+}
@@ -0,0 +1,41 @@
+package docker
+
+import "testing"
+
+func TestHostNetworkDefinitionValidate(t *testing.T) {
+	tests := []struct {
+		name       string
+		definition HostNetworkDefinition
+		valid      bool
+	}{
+		{"macvlan", HostNetworkDefinition{Driver: "macvlan", Parent: "eth0", Mode: "bridge", Subnet: "192.168.1.0/24", Gateway: "192.168.1.1", IPRange: "192.168.1.192/27"}, true},
+		{"ipvlan on a vlan", HostNetworkDefinition{Driver: "ipvlan", Parent: "eth0.10", Mode: "l2", Subnet: "10.10.0.0/16", Gateway: "10.10.0.1"}, true},
+		{"ipv6", HostNetworkDefinition{Driver: "macvlan", Parent: "ens3", Subnet: "2001:db8::/64", Gateway: "2001:db8::1"}, true},
+		{"unknown driver", HostNetworkDefinition{Driver: "bridge", Parent: "eth0", Subnet: "192.168.1.0/24", Gateway: "192.168.1.1"}, false},
+		{"invalid parent", HostNetworkDefinition{Driver: "macvlan", Parent: "eth0 eth1", Subnet: "192.168.1.0/24", Gateway: "192.168.1.1"}, false},
+		{"mode of the other driver", HostNetworkDefinition{Driver: "ipvlan", Parent: "eth0", Mode: "bridge", Subnet: "192.168.1.0/24", Gateway: "192.168.1.1"}, false},
+		{"gateway outside of the subnet", HostNetworkDefinition{Driver: "macvlan", Parent: "eth0", Subnet: "192.168.1.0/24", Gateway: "192.168.2.1"}, false},
+		{"gateway on the network address", HostNetworkDefinition{Driver: "macvlan", Parent: "eth0", Subnet: "192.168.1.0/24", Gateway: "192.168.1.0"}, false},
+		{"range outside of the subnet", HostNetworkDefinition{Driver: "macvlan", Parent: "eth0", Subnet: "192.168.1.0/24", Gateway: "192.168.1.1", IPRange: "192.168.2.0/27"}, false},
+		{"range larger than the subnet", HostNetworkDefinition{Driver: "macvlan", Parent: "eth0", Subnet: "192.168.1.0/24", Gateway: "192.168.1.1", IPRange: "192.168.0.0/16"}, false},
+	}
+
+	for _, test := range tests {
+		err := test.definition.Validate()
+		if test.valid && err != nil {
+			t.Errorf("%s: unexpected error: %s", test.name, err)
+		}
+		if !test.valid && err == nil {
+			t.Errorf("%s: expected an error", test.name)
+		}
+	}
+}
+
+func TestSubnetsOverlap(t *testing.T) {
+	if !SubnetsOverlap("10.0.0.0/16", "10.0.5.0/24") {
+		t.Error("expected 10.0.0.0/16 to overlap with 10.0.5.0/24")
+	}
+	if SubnetsOverlap("10.0.0.0/24", "10.0.1.0/24") {
+		t.Error("expected 10.0.0.0/24 not to overlap with 10.0.1.0/24")
+	}
+}
@@ -0,0 +1,87 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/docker/docker/client"
+)
+
+// Platform represents the operating system and the architecture of a Docker host or of an image
+type Platform struct {
+	OS           string `json:"OS"`
+	Architecture string `json:"Architecture"`
+}
+
+func (platform Platform) String() string {
+	return fmt.Sprintf("%s/%s", platform.OS, platform.Architecture)
+}
+
+// HostPlatform returns the platform of the Docker host of an endpoint, using the architecture names of the image manifests
+func HostPlatform(cli *client.Client) (*Platform, error) {
+	info, err := cli.Info(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	return &Platform{
+		OS:           info.OSType,
+		Architecture: normalizeArchitecture(info.Architecture),
+	}, nil
+}
+
+// ImagePlatforms returns the platforms an image is available for. The image is inspected on the host
+// when it is already available, the registry is queried otherwise.
+func ImagePlatforms(cli *client.Client, image, registryAuth string) ([]Platform, error) {
+	local, _, err := cli.ImageInspectWithRaw(context.Background(), image)
+	if err == nil {
+		return []Platform{{OS: local.Os, Architecture: local.Architecture}}, nil
+	}
+
+	distribution, err := cli.DistributionInspect(context.Background(), image, registryAuth)
+	if err != nil {
+		return nil, err
+	}
+
+	platforms := make([]Platform, 0, len(distribution.Platforms))
+	for _, platform := range distribution.Platforms {
+		platforms = append(platforms, Platform{OS: platform.OS, Architecture: platform.Architecture})
+	}
+
+	return platforms, nil
+}
+
+// PlatformSupported returns true when one of the platforms of an image can run on the platform of a host.
+// Images without platform information are considered supported.
+func PlatformSupported(host *Platform, platforms []Platform) bool {
+	if len(platforms) == 0 {
+		return true
+	}
+
+	for _, platform := range platforms {
+		if platform.OS != "" && !strings.EqualFold(platform.OS, host.OS) {
+			continue
+		}
+		if platform.Architecture == "" || strings.EqualFold(normalizeArchitecture(platform.Architecture), host.Architecture) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// normalizeArchitecture converts the architecture names reported by the hosts (uname) to the names used by the image manifests
+func normalizeArchitecture(architecture string) string {
+	switch strings.ToLower(architecture) {
+	case "x86_64", "x86-64", "amd64":
+		return "amd64"
+	case "aarch64", "arm64":
+		return "arm64"
+	case "armhf", "armel", "armv6l", "armv7l", "arm":
+		return "arm"
+	case "i386", "i686", "386":
+		return "386"
+	}
+	return strings.ToLower(architecture)
+}
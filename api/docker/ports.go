@@ -0,0 +1,144 @@
+package docker
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/client"
+)
+
+const (
+	// PortOwnerContainer represents a port published by a container
+	PortOwnerContainer = "container"
+	// PortOwnerService represents a port published by a Swarm service
+	PortOwnerService = "service"
+
+	// PortModeHost represents a port published on the host running the container
+	PortModeHost = "host"
+	// PortModeIngress represents a port published on every node of the Swarm routing mesh
+	PortModeIngress = "ingress"
+)
+
+// PublishedPort represents a host port published by a container or a Swarm service
+type PublishedPort struct {
+	HostIP        string `json:"HostIP"`
+	PublishedPort uint16 `json:"PublishedPort"`
+	TargetPort    uint16 `json:"TargetPort"`
+	Protocol      string `json:"Protocol"`
+	Mode          string `json:"Mode"`
+	OwnerType     string `json:"OwnerType"`
+	OwnerID       string `json:"OwnerId,omitempty"`
+	OwnerName     string `json:"OwnerName,omitempty"`
+	Stack         string `json:"Stack,omitempty"`
+}
+
+// PublishedPorts returns all the host ports published by the containers and Swarm services of an endpoint,
+// ordered by port number.
+func PublishedPorts(cli *client.Client) ([]PublishedPort, error) {
+	ports := make([]PublishedPort, 0)
+
+	containers, err := cli.ContainerList(context.Background(), types.ContainerListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, container := range containers {
+		name := container.ID
+		if len(container.Names) > 0 {
+			name = strings.TrimPrefix(container.Names[0], "/")
+		}
+
+		for _, port := range container.Ports {
+			if port.PublicPort == 0 {
+				continue
+			}
+
+			ports = append(ports, PublishedPort{
+				HostIP:        port.IP,
+				PublishedPort: port.PublicPort,
+				TargetPort:    port.PrivatePort,
+				Protocol:      port.Type,
+				Mode:          PortModeHost,
+				OwnerType:     PortOwnerContainer,
+				OwnerID:       container.ID,
+				OwnerName:     name,
+				Stack:         stackName(container.Labels),
+			})
+		}
+	}
+
+	info, err := cli.Info(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	if info.Swarm.ControlAvailable {
+		services, err := cli.ServiceList(context.Background(), types.ServiceListOptions{})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, service := range services {
+			for _, port := range service.Endpoint.Ports {
+				if port.PublishedPort == 0 || port.PublishMode != swarm.PortConfigPublishModeIngress {
+					continue
+				}
+
+				ports = append(ports, PublishedPort{
+					PublishedPort: uint16(port.PublishedPort),
+					TargetPort:    uint16(port.TargetPort),
+					Protocol:      string(port.Protocol),
+					Mode:          PortModeIngress,
+					OwnerType:     PortOwnerService,
+					OwnerID:       service.ID,
+					OwnerName:     service.Spec.Name,
+					Stack:         stackName(service.Spec.Labels),
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(ports, func(i, j int) bool {
+		if ports[i].PublishedPort != ports[j].PublishedPort {
+			return ports[i].PublishedPort < ports[j].PublishedPort
+		}
+		return ports[i].Protocol < ports[j].Protocol
+	})
+
+	return ports, nil
+}
+
+// PortsConflict returns true when two published ports cannot be bound at the same time.
+// Ports published on the routing mesh or on all the interfaces conflict with any address.
+func PortsConflict(left, right *PublishedPort) bool {
+	if left.PublishedPort != right.PublishedPort || !strings.EqualFold(protocolOrDefault(left.Protocol), protocolOrDefault(right.Protocol)) {
+		return false
+	}
+
+	if left.Mode == PortModeIngress || right.Mode == PortModeIngress {
+		return true
+	}
+
+	return isWildcardAddress(left.HostIP) || isWildcardAddress(right.HostIP) || left.HostIP == right.HostIP
+}
+
+func protocolOrDefault(protocol string) string {
+	if protocol == "" {
+		return "tcp"
+	}
+	return protocol
+}
+
+func isWildcardAddress(address string) bool {
+	return address == "" || address == "0.0.0.0" || address == "::"
+}
+
+func stackName(labels map[string]string) string {
+	if labels["com.docker.compose.project"] != "" {
+		return labels["com.docker.compose.project"]
+	}
+	return labels["com.docker.stack.namespace"]
+}
@@ -26,6 +26,11 @@ func NewSnapshotter(clientFactory *ClientFactory) *Snapshotter {
 
 // CreateSnapshot creates a snapshot of a specific Docker endpoint
 func (snapshotter *Snapshotter) CreateSnapshot(endpoint *portainer.Endpoint) (*portainer.DockerSnapshot, error) {
+	_, err := snapshotter.clientFactory.NegotiateAPIVersion(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
 	cli, err := snapshotter.clientFactory.CreateClient(endpoint, "")
 	if err != nil {
 		return nil, err
@@ -36,13 +41,15 @@ func (snapshotter *Snapshotter) CreateSnapshot(endpoint *portainer.Endpoint) (*p
 }
 
 func snapshot(cli *client.Client, endpoint *portainer.Endpoint) (*portainer.DockerSnapshot, error) {
-	_, err := cli.Ping(context.Background())
+	ping, err := cli.Ping(context.Background())
 	if err != nil {
 		return nil, err
 	}
 
 	snapshot := &portainer.DockerSnapshot{
-		StackCount: 0,
+		StackCount:   0,
+		APIVersion:   ping.APIVersion,
+		Capabilities: Capabilities(ping.APIVersion),
 	}
 
 	err = snapshotInfo(snapshot, cli)
@@ -77,11 +84,26 @@ func snapshot(cli *client.Client, endpoint *portainer.Endpoint) (*portainer.Dock
 		log.Printf("[WARN] [docker,snapshot] [message: unable to snapshot volumes] [endpoint: %s] [err: %s]", endpoint.Name, err)
 	}
 
+	err = snapshotVolumeUsage(snapshot, cli)
+	if err != nil {
+		log.Printf("[WARN] [docker,snapshot] [message: unable to snapshot volume disk usage] [endpoint: %s] [err: %s]", endpoint.Name, err)
+	}
+
 	err = snapshotNetworks(snapshot, cli)
 	if err != nil {
 		log.Printf("[WARN] [docker,snapshot] [message: unable to snapshot networks] [endpoint: %s] [err: %s]", endpoint.Name, err)
 	}
 
+	err = snapshotReservations(snapshot, cli)
+	if err != nil {
+		log.Printf("[WARN] [docker,snapshot] [message: unable to snapshot resource reservations] [endpoint: %s] [err: %s]", endpoint.Name, err)
+	}
+
+	err = snapshotHost(snapshot, cli, endpoint)
+	if err != nil {
+		log.Printf("[WARN] [docker,snapshot] [message: unable to snapshot host resource usage] [endpoint: %s] [err: %s]", endpoint.Name, err)
+	}
+
 	err = snapshotVersion(snapshot, cli)
 	if err != nil {
 		log.Printf("[WARN] [docker,snapshot] [message: unable to snapshot engine version] [endpoint: %s] [err: %s]", endpoint.Name, err)
@@ -99,6 +121,9 @@ func snapshotInfo(snapshot *portainer.DockerSnapshot, cli *client.Client) error
 
 	snapshot.Swarm = info.Swarm.ControlAvailable
 	snapshot.DockerVersion = info.ServerVersion
+	snapshot.OSType = info.OSType
+	snapshot.Isolation = string(info.Isolation)
+	snapshot.Runtime = RuntimeCapabilities(&info)
 	snapshot.TotalCPU = info.NCPU
 	snapshot.TotalMemory = info.MemTotal
 	snapshot.SnapshotRaw.Info = info
@@ -112,12 +137,27 @@ func snapshotNodes(snapshot *portainer.DockerSnapshot, cli *client.Client) error
 	}
 	var nanoCpus int64
 	var totalMem int64
+	nodeStatuses := make([]portainer.DockerNodeStatus, 0)
 	for _, node := range nodes {
 		nanoCpus += node.Description.Resources.NanoCPUs
 		totalMem += node.Description.Resources.MemoryBytes
+
+		nodeStatus := portainer.DockerNodeStatus{
+			ID:           node.ID,
+			Hostname:     node.Description.Hostname,
+			Role:         string(node.Spec.Role),
+			Availability: string(node.Spec.Availability),
+			State:        string(node.Status.State),
+		}
+		if node.ManagerStatus != nil {
+			nodeStatus.Leader = node.ManagerStatus.Leader
+		}
+		nodeStatuses = append(nodeStatuses, nodeStatus)
 	}
 	snapshot.TotalCPU = int(nanoCpus / 1e9)
 	snapshot.TotalMemory = totalMem
+	snapshot.NodeCount = len(nodes)
+	snapshot.Nodes = nodeStatuses
 	return nil
 }
 
@@ -219,5 +259,8 @@ func snapshotVersion(snapshot *portainer.DockerSnapshot, cli *client.Client) err
 		return err
 	}
 	snapshot.SnapshotRaw.Version = version
+	snapshot.APIVersion = version.APIVersion
+	snapshot.MinAPIVersion = version.MinAPIVersion
+	snapshot.Capabilities = Capabilities(version.APIVersion)
 	return nil
 }
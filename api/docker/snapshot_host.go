@@ -0,0 +1,59 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+
+	"github.com/docker/docker/client"
+	"github.com/portainer/portainer/api"
+)
+
+var errHostMetricsUnsupported = errors.New("host metrics are not supported on this platform")
+
+func snapshotHost(snapshot *portainer.DockerSnapshot, cli *client.Client, endpoint *portainer.Endpoint) error {
+	diskUsage, err := cli.DiskUsage(context.Background())
+	if err != nil {
+		return err
+	}
+
+	host := &portainer.DockerHostSnapshot{
+		DockerDiskUsage: portainer.DockerDiskUsage{
+			Images: diskUsage.LayersSize,
+		},
+	}
+
+	for _, container := range diskUsage.Containers {
+		host.DockerDiskUsage.Containers += container.SizeRw
+	}
+
+	for _, volume := range diskUsage.Volumes {
+		if volume.UsageData != nil && volume.UsageData.Size > 0 {
+			host.DockerDiskUsage.Volumes += volume.UsageData.Size
+		}
+	}
+
+	for _, cache := range diskUsage.BuildCache {
+		host.DockerDiskUsage.BuildCache += cache.Size
+	}
+
+	if isLocalEndpoint(endpoint) {
+		err = snapshotHostMetrics(host)
+		if err != nil && err != errHostMetricsUnsupported {
+			log.Printf("[WARN] [docker,snapshot] [message: unable to snapshot host metrics] [endpoint: %s] [err: %s]", endpoint.Name, err)
+		}
+	}
+
+	snapshot.Host = host
+	return nil
+}
+
+// isLocalEndpoint returns true when the Docker environment runs on the same host as Portainer,
+// in which case the host metrics can be read directly.
+func isLocalEndpoint(endpoint *portainer.Endpoint) bool {
+	if endpoint.Type != portainer.DockerEnvironment {
+		return false
+	}
+	return strings.HasPrefix(endpoint.URL, "unix://") || strings.HasPrefix(endpoint.URL, "npipe://")
+}
@@ -0,0 +1,134 @@
+package docker
+
+import (
+	"bufio"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/portainer/portainer/api"
+)
+
+// filesystems that do not store data on a disk
+var virtualFilesystemTypes = map[string]bool{
+	"autofs": true, "binfmt_misc": true, "bpf": true, "cgroup": true, "cgroup2": true,
+	"configfs": true, "debugfs": true, "devpts": true, "devtmpfs": true, "fusectl": true,
+	"hugetlbfs": true, "mqueue": true, "nsfs": true, "overlay": true, "proc": true,
+	"pstore": true, "securityfs": true, "shm": true, "squashfs": true, "sysfs": true,
+	"tmpfs": true, "tracefs": true,
+}
+
+func snapshotHostMetrics(host *portainer.DockerHostSnapshot) error {
+	err := snapshotHostMemory(host)
+	if err != nil {
+		return err
+	}
+
+	err = snapshotHostLoadAverage(host)
+	if err != nil {
+		return err
+	}
+
+	return snapshotHostFilesystems(host)
+}
+
+func snapshotHostMemory(host *portainer.DockerHostSnapshot) error {
+	file, err := os.Open("/proc/meminfo")
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+
+		value, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+
+		switch fields[0] {
+		case "MemTotal:":
+			host.MemoryTotal = value * 1024
+		case "MemAvailable:":
+			host.MemoryAvailable = value * 1024
+		}
+	}
+
+	return scanner.Err()
+}
+
+func snapshotHostLoadAverage(host *portainer.DockerHostSnapshot) error {
+	content, err := ioutil.ReadFile("/proc/loadavg")
+	if err != nil {
+		return err
+	}
+
+	fields := strings.Fields(string(content))
+	if len(fields) < 3 {
+		return nil
+	}
+
+	loadAverage := make([]float64, 0, 3)
+	for _, field := range fields[:3] {
+		value, err := strconv.ParseFloat(field, 64)
+		if err != nil {
+			return err
+		}
+		loadAverage = append(loadAverage, value)
+	}
+
+	host.LoadAverage = loadAverage
+	return nil
+}
+
+func snapshotHostFilesystems(host *portainer.DockerHostSnapshot) error {
+	file, err := os.Open("/proc/mounts")
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	devices := make(map[string]bool)
+	filesystems := make([]portainer.HostFilesystemUsage, 0)
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 3 {
+			continue
+		}
+
+		device, mountPoint, filesystemType := fields[0], fields[1], fields[2]
+		if virtualFilesystemTypes[filesystemType] || devices[device] {
+			continue
+		}
+
+		var stat syscall.Statfs_t
+		err := syscall.Statfs(mountPoint, &stat)
+		if err != nil || stat.Blocks == 0 {
+			continue
+		}
+		devices[device] = true
+
+		blockSize := int64(stat.Bsize)
+		total := int64(stat.Blocks) * blockSize
+		filesystems = append(filesystems, portainer.HostFilesystemUsage{
+			Device:     device,
+			MountPoint: mountPoint,
+			Type:       filesystemType,
+			Total:      total,
+			Used:       total - int64(stat.Bfree)*blockSize,
+			Available:  int64(stat.Bavail) * blockSize,
+		})
+	}
+
+	host.Filesystems = filesystems
+	return scanner.Err()
+}
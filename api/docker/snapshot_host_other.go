@@ -0,0 +1,9 @@
+// +build !linux
+
+package docker
+
+import "github.com/portainer/portainer/api"
+
+func snapshotHostMetrics(host *portainer.DockerHostSnapshot) error {
+	return errHostMetricsUnsupported
+}
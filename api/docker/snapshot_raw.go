@@ -0,0 +1,61 @@
+package docker
+
+import (
+	"encoding/json"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/volume"
+	portainer "github.com/portainer/portainer/api"
+)
+
+// The raw data of a snapshot is stored as returned by the Docker API. Once a snapshot has been
+// persisted, the raw data is decoded as generic JSON objects; the following functions convert it
+// back to the Docker API types.
+
+// SnapshotContainers returns the containers stored in the raw data of a snapshot
+func SnapshotContainers(snapshot *portainer.DockerSnapshot) ([]types.Container, error) {
+	containers := make([]types.Container, 0)
+	err := decodeSnapshotRawData(snapshot.SnapshotRaw.Containers, &containers)
+	return containers, err
+}
+
+// SnapshotImages returns the images stored in the raw data of a snapshot
+func SnapshotImages(snapshot *portainer.DockerSnapshot) ([]types.ImageSummary, error) {
+	images := make([]types.ImageSummary, 0)
+	err := decodeSnapshotRawData(snapshot.SnapshotRaw.Images, &images)
+	return images, err
+}
+
+// SnapshotVolumes returns the volumes stored in the raw data of a snapshot
+func SnapshotVolumes(snapshot *portainer.DockerSnapshot) ([]*types.Volume, error) {
+	var volumes volume.VolumeListOKBody
+	err := decodeSnapshotRawData(snapshot.SnapshotRaw.Volumes, &volumes)
+	if err != nil {
+		return nil, err
+	}
+
+	if volumes.Volumes == nil {
+		return make([]*types.Volume, 0), nil
+	}
+	return volumes.Volumes, nil
+}
+
+// SnapshotNetworks returns the networks stored in the raw data of a snapshot
+func SnapshotNetworks(snapshot *portainer.DockerSnapshot) ([]types.NetworkResource, error) {
+	networks := make([]types.NetworkResource, 0)
+	err := decodeSnapshotRawData(snapshot.SnapshotRaw.Networks, &networks)
+	return networks, err
+}
+
+func decodeSnapshotRawData(data interface{}, target interface{}) error {
+	if data == nil {
+		return nil
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(raw, target)
+}
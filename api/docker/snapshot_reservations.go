@@ -0,0 +1,123 @@
+package docker
+
+import (
+	"context"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/client"
+	"github.com/portainer/portainer/api"
+)
+
+// snapshotReservations records the CPU and memory reserved by the running standalone containers
+// and by the Swarm services. When no reservation is defined, the limits are used instead.
+// The containers of a Swarm service are accounted for in the reservation of the service.
+func snapshotReservations(snapshot *portainer.DockerSnapshot, cli *client.Client) error {
+	reservations := make([]portainer.ResourceReservation, 0)
+
+	containers, err := SnapshotContainers(snapshot)
+	if err != nil {
+		return err
+	}
+
+	for _, container := range containers {
+		if container.State != "running" || container.Labels["com.docker.swarm.service.id"] != "" {
+			continue
+		}
+
+		containerJSON, err := cli.ContainerInspect(context.Background(), container.ID)
+		if err != nil {
+			return err
+		}
+
+		reservation := portainer.ResourceReservation{
+			Type:       portainer.ContainerResourceReservation,
+			ResourceID: container.ID,
+			Name:       strings.TrimPrefix(containerJSON.Name, "/"),
+			Stack:      stackName(container.Labels),
+			Replicas:   1,
+			Labels:     container.Labels,
+		}
+
+		if containerJSON.HostConfig != nil {
+			resources := containerJSON.HostConfig.Resources
+			reservation.CPU = ContainerCPULimit(resources, snapshot.TotalCPU)
+			reservation.Memory = resources.MemoryReservation
+			if reservation.Memory == 0 {
+				reservation.Memory = resources.Memory
+			}
+		}
+
+		reservations = append(reservations, reservation)
+	}
+
+	if snapshot.Swarm {
+		services, err := cli.ServiceList(context.Background(), types.ServiceListOptions{})
+		if err != nil {
+			return err
+		}
+
+		for _, service := range services {
+			reservations = append(reservations, serviceReservation(service, snapshot.NodeCount))
+		}
+	}
+
+	snapshot.Reservations = reservations
+	return nil
+}
+
+// ContainerCPULimit returns the number of CPUs a container is limited to. On Windows hosts the limit
+// can also be expressed as a number of CPUs or as a percentage of the host CPUs.
+func ContainerCPULimit(resources container.Resources, hostCPU int) float64 {
+	switch {
+	case resources.NanoCPUs > 0:
+		return float64(resources.NanoCPUs) / 1e9
+	case resources.CPUPeriod > 0 && resources.CPUQuota > 0:
+		return float64(resources.CPUQuota) / float64(resources.CPUPeriod)
+	case resources.CPUCount > 0:
+		return float64(resources.CPUCount)
+	case resources.CPUPercent > 0:
+		return float64(resources.CPUPercent) / 100 * float64(hostCPU)
+	}
+	return 0
+}
+
+func serviceReservation(service swarm.Service, nodeCount int) portainer.ResourceReservation {
+	reservation := portainer.ResourceReservation{
+		Type:       portainer.ServiceResourceReservation,
+		ResourceID: service.ID,
+		Name:       service.Spec.Name,
+		Stack:      stackName(service.Spec.Labels),
+		Labels:     service.Spec.Labels,
+	}
+
+	switch {
+	case service.Spec.Mode.Replicated != nil && service.Spec.Mode.Replicated.Replicas != nil:
+		reservation.Replicas = int(*service.Spec.Mode.Replicated.Replicas)
+	case service.Spec.Mode.Global != nil:
+		reservation.Replicas = nodeCount
+	}
+
+	resources := service.Spec.TaskTemplate.Resources
+	if resources == nil {
+		return reservation
+	}
+
+	if resources.Reservations != nil {
+		reservation.CPU = float64(resources.Reservations.NanoCPUs) / 1e9
+		reservation.Memory = resources.Reservations.MemoryBytes
+	}
+
+	if resources.Limits != nil {
+		if reservation.CPU == 0 {
+			reservation.CPU = float64(resources.Limits.NanoCPUs) / 1e9
+		}
+		if reservation.Memory == 0 {
+			reservation.Memory = resources.Limits.MemoryBytes
+		}
+	}
+
+	return reservation
+}
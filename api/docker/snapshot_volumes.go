@@ -0,0 +1,97 @@
+package docker
+
+import (
+	"context"
+	"time"
+
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/client"
+	"github.com/portainer/portainer/api"
+)
+
+// snapshotVolumeUsage records the size of the volumes and the containers mounting them. The size is computed
+// by the daemon, it is unknown for the volumes of the drivers that do not report it. The volumes mounted by
+// a running container are marked as used at the time of the snapshot.
+func snapshotVolumeUsage(snapshot *portainer.DockerSnapshot, cli *client.Client) error {
+	volumes, err := SnapshotVolumes(snapshot)
+	if err != nil {
+		return err
+	}
+
+	containers, err := SnapshotContainers(snapshot)
+	if err != nil {
+		return err
+	}
+
+	refCounts := make(map[string]int)
+	inUse := make(map[string]bool)
+	for _, container := range containers {
+		for _, mountPoint := range container.Mounts {
+			if mountPoint.Type != mount.TypeVolume {
+				continue
+			}
+
+			refCounts[mountPoint.Name]++
+			if container.State == "running" {
+				inUse[mountPoint.Name] = true
+			}
+		}
+	}
+
+	sizes := make(map[string]int64)
+	diskUsage, err := cli.DiskUsage(context.Background())
+	if err == nil {
+		for _, volume := range diskUsage.Volumes {
+			if volume.UsageData != nil {
+				sizes[volume.Name] = volume.UsageData.Size
+			}
+		}
+	}
+
+	now := time.Now().Unix()
+	usage := make([]portainer.DockerVolumeUsage, 0, len(volumes))
+	for _, volume := range volumes {
+		volumeUsage := portainer.DockerVolumeUsage{
+			Name:      volume.Name,
+			Driver:    volume.Driver,
+			CreatedAt: volume.CreatedAt,
+			Size:      -1,
+			RefCount:  refCounts[volume.Name],
+		}
+
+		if size, ok := sizes[volume.Name]; ok {
+			volumeUsage.Size = size
+		}
+
+		if inUse[volume.Name] {
+			volumeUsage.LastUsed = now
+		}
+
+		usage = append(usage, volumeUsage)
+	}
+
+	// the usage is recorded without the sizes when the daemon cannot compute the disk usage
+	snapshot.VolumeUsage = usage
+	return err
+}
+
+// UnusedVolumes returns the volumes that are not mounted by any container and were not used since the
+// specified unix timestamp, along with the disk space that removing them would reclaim.
+// The volumes of unknown size are not accounted for in the reclaimable space.
+func UnusedVolumes(snapshot *portainer.DockerSnapshot, unusedSince int64) ([]portainer.DockerVolumeUsage, int64) {
+	unused := make([]portainer.DockerVolumeUsage, 0)
+	var reclaimable int64
+
+	for _, volume := range snapshot.VolumeUsage {
+		if volume.RefCount > 0 || volume.LastUsed > unusedSince {
+			continue
+		}
+
+		unused = append(unused, volume)
+		if volume.Size > 0 {
+			reclaimable += volume.Size
+		}
+	}
+
+	return unused, reclaimable
+}
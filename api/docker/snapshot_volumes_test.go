@@ -0,0 +1,40 @@
+package docker
+
+import (
+	"testing"
+
+	"github.com/portainer/portainer/api"
+)
+
+func TestUnusedVolumes(t *testing.T) {
+	snapshot := &portainer.DockerSnapshot{
+		VolumeUsage: []portainer.DockerVolumeUsage{
+			{Name: "mounted", Size: 100, RefCount: 1, LastUsed: 1000},
+			{Name: "recent", Size: 200, LastUsed: 900},
+			{Name: "old", Size: 300, LastUsed: 100},
+			{Name: "never-used", Size: 400},
+			{Name: "unknown-size", Size: -1},
+		},
+	}
+
+	volumes, reclaimable := UnusedVolumes(snapshot, 500)
+
+	names := make([]string, 0)
+	for _, volume := range volumes {
+		names = append(names, volume.Name)
+	}
+
+	expected := []string{"old", "never-used", "unknown-size"}
+	if len(names) != len(expected) {
+		t.Fatalf("got unused volumes %v, want %v", names, expected)
+	}
+	for idx := range expected {
+		if names[idx] != expected[idx] {
+			t.Fatalf("got unused volumes %v, want %v", names, expected)
+		}
+	}
+
+	if reclaimable != 700 {
+		t.Errorf("got reclaimable size %d, want 700", reclaimable)
+	}
+}
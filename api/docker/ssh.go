@@ -0,0 +1,230 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	// DefaultSSHSocketPath is the path of the Docker socket used on the remote host when none is specified
+	DefaultSSHSocketPath = "/var/run/docker.sock"
+	defaultSSHPort       = "22"
+	sshDialTimeout       = 10 * time.Second
+)
+
+var errMissingSSHConfiguration = errors.New("Missing SSH configuration for endpoint")
+
+// sshTunnel represents an SSH connection to a remote host used to reach its Docker socket.
+// The connection is shared by every Docker client and proxy targeting the same endpoint and is
+// re-established when it is broken.
+type sshTunnel struct {
+	mutex       sync.Mutex
+	endpointURL string
+	config      portainer.SSHConfiguration
+	client      *ssh.Client
+}
+
+// ValidateSSHConnection connects to the host referenced by an ssh:// URL using the specified private key
+// and returns the public key presented by the host (authorized_keys format) so that it can be verified
+// on subsequent connections.
+func ValidateSSHConnection(endpointURL string, privateKey []byte) (string, error) {
+	signer, err := ssh.ParsePrivateKey(privateKey)
+	if err != nil {
+		return "", err
+	}
+
+	var hostKey ssh.PublicKey
+	callback := func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		hostKey = key
+		return nil
+	}
+
+	client, err := dialSSH(endpointURL, signer, callback)
+	if err != nil {
+		return "", err
+	}
+	defer client.Close()
+
+	return string(ssh.MarshalAuthorizedKey(hostKey)), nil
+}
+
+// ParseSSHURL validates an ssh:// endpoint URL and returns the user and the address of the host
+func ParseSSHURL(endpointURL string) (string, string, error) {
+	u, err := url.Parse(endpointURL)
+	if err != nil {
+		return "", "", err
+	}
+
+	if u.Scheme != "ssh" {
+		return "", "", errors.New("Invalid URL scheme, expecting ssh://")
+	}
+
+	if u.User == nil || u.User.Username() == "" {
+		return "", "", errors.New("Missing user in SSH URL, expecting ssh://user@host[:port]")
+	}
+
+	if _, hasPassword := u.User.Password(); hasPassword {
+		return "", "", errors.New("Passwords are not supported in SSH URLs, use a private key instead")
+	}
+
+	if u.Hostname() == "" {
+		return "", "", errors.New("Missing host in SSH URL")
+	}
+
+	if u.Path != "" && u.Path != "/" {
+		return "", "", errors.New("Paths are not supported in SSH URLs, specify the remote Docker socket path instead")
+	}
+
+	port := u.Port()
+	if port == "" {
+		port = defaultSSHPort
+	}
+
+	return u.User.Username(), net.JoinHostPort(u.Hostname(), port), nil
+}
+
+func dialSSH(endpointURL string, signer ssh.Signer, hostKeyCallback ssh.HostKeyCallback) (*ssh.Client, error) {
+	user, address, err := ParseSSHURL(endpointURL)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &ssh.ClientConfig{
+		User:            user,
+		Auth:            []ssh.AuthMethod{ssh.PublicKeys(signer)},
+		HostKeyCallback: hostKeyCallback,
+		Timeout:         sshDialTimeout,
+	}
+
+	return ssh.Dial("tcp", address, config)
+}
+
+func (tunnel *sshTunnel) connect() (*ssh.Client, error) {
+	privateKey, err := ioutil.ReadFile(tunnel.config.PrivateKeyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	signer, err := ssh.ParsePrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	hostKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(tunnel.config.HostKey))
+	if err != nil {
+		return nil, err
+	}
+
+	return dialSSH(tunnel.endpointURL, signer, ssh.FixedHostKey(hostKey))
+}
+
+// dial opens a connection to the remote Docker socket, the SSH connection is
+// established or re-established when required.
+func (tunnel *sshTunnel) dial() (net.Conn, error) {
+	tunnel.mutex.Lock()
+	defer tunnel.mutex.Unlock()
+
+	socketPath := tunnel.config.SocketPath
+	if socketPath == "" {
+		socketPath = DefaultSSHSocketPath
+	}
+
+	if tunnel.client != nil {
+		conn, err := tunnel.client.Dial("unix", socketPath)
+		if err == nil {
+			return conn, nil
+		}
+
+		tunnel.client.Close()
+		tunnel.client = nil
+	}
+
+	client, err := tunnel.connect()
+	if err != nil {
+		return nil, err
+	}
+	tunnel.client = client
+
+	return client.Dial("unix", socketPath)
+}
+
+func (tunnel *sshTunnel) close() {
+	tunnel.mutex.Lock()
+	defer tunnel.mutex.Unlock()
+
+	if tunnel.client != nil {
+		tunnel.client.Close()
+		tunnel.client = nil
+	}
+}
+
+func (factory *ClientFactory) getSSHTunnel(endpoint *portainer.Endpoint) (*sshTunnel, error) {
+	if endpoint.SSHConfig == nil {
+		return nil, errMissingSSHConfiguration
+	}
+
+	factory.sshTunnelsMutex.Lock()
+	defer factory.sshTunnelsMutex.Unlock()
+
+	tunnel, ok := factory.sshTunnels[endpoint.ID]
+	if ok && tunnel.endpointURL == endpoint.URL && tunnel.config == *endpoint.SSHConfig {
+		return tunnel, nil
+	}
+
+	if ok {
+		tunnel.close()
+	}
+
+	tunnel = &sshTunnel{
+		endpointURL: endpoint.URL,
+		config:      *endpoint.SSHConfig,
+	}
+	factory.sshTunnels[endpoint.ID] = tunnel
+
+	return tunnel, nil
+}
+
+// DialSSH opens a connection to the Docker socket of an endpoint reached over SSH
+func (factory *ClientFactory) DialSSH(endpoint *portainer.Endpoint) (net.Conn, error) {
+	tunnel, err := factory.getSSHTunnel(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return tunnel.dial()
+}
+
+// SSHTransport returns an HTTP transport sending requests to the Docker socket of an endpoint reached over SSH
+func (factory *ClientFactory) SSHTransport(endpoint *portainer.Endpoint) (*http.Transport, error) {
+	tunnel, err := factory.getSSHTunnel(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Transport{
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			return tunnel.dial()
+		},
+	}, nil
+}
+
+// RemoveSSHTunnel closes and removes the SSH connection associated to an endpoint
+func (factory *ClientFactory) RemoveSSHTunnel(endpointID portainer.EndpointID) {
+	factory.sshTunnelsMutex.Lock()
+	defer factory.sshTunnelsMutex.Unlock()
+
+	tunnel, ok := factory.sshTunnels[endpointID]
+	if ok {
+		tunnel.close()
+		delete(factory.sshTunnels, endpointID)
+	}
+}
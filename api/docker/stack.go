@@ -0,0 +1,113 @@
+package docker
+
+import (
+	"context"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+	portainer "github.com/portainer/portainer/api"
+)
+
+const (
+	// SwarmStackNameLabel is the label used by Docker to associate a resource to a Swarm stack
+	SwarmStackNameLabel = "com.docker.stack.namespace"
+	// ComposeStackNameLabel is the label used by Docker Compose to associate a resource to a Compose stack
+	ComposeStackNameLabel = "com.docker.compose.project"
+	// ComposeServiceLabel is the label used by Docker Compose to associate a container to a service of a Compose stack
+	ComposeServiceLabel = "com.docker.compose.service"
+	// ServiceIDLabel is the label used by Docker to associate a container to a Swarm service
+	ServiceIDLabel = "com.docker.swarm.service.id"
+)
+
+// StackResource represents a Docker resource that was created as part of a stack
+type StackResource struct {
+	ID   string                        `json:"Id"`
+	Name string                        `json:"Name"`
+	Type portainer.ResourceControlType `json:"Type"`
+}
+
+// StackResources returns the list of Docker resources (services, containers, networks, volumes, configs and secrets)
+// that are associated to the specified stack via the Swarm or Compose stack labels.
+// The identifier of each resource matches the one used to reference the resource inside a resource control.
+func StackResources(cli *client.Client, stack *portainer.Stack) ([]StackResource, error) {
+	switch stack.Type {
+	case portainer.DockerSwarmStack:
+		return listStackResources(cli, SwarmStackNameLabel, stack.Name, true)
+	case portainer.DockerComposeStack:
+		return listStackResources(cli, ComposeStackNameLabel, stack.Name, false)
+	}
+
+	return []StackResource{}, nil
+}
+
+func listStackResources(cli *client.Client, label, stackName string, swarm bool) ([]StackResource, error) {
+	resources := make([]StackResource, 0)
+
+	labelFilter := filters.NewArgs()
+	labelFilter.Add("label", label+"="+stackName)
+
+	if swarm {
+		services, err := cli.ServiceList(context.Background(), types.ServiceListOptions{Filters: labelFilter})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, service := range services {
+			resources = append(resources, StackResource{ID: service.ID, Name: service.Spec.Name, Type: portainer.ServiceResourceControl})
+		}
+	}
+
+	containers, err := cli.ContainerList(context.Background(), types.ContainerListOptions{All: true, Filters: labelFilter})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, container := range containers {
+		name := ""
+		if len(container.Names) > 0 {
+			name = container.Names[0]
+		}
+		resources = append(resources, StackResource{ID: container.ID, Name: name, Type: portainer.ContainerResourceControl})
+	}
+
+	networks, err := cli.NetworkList(context.Background(), types.NetworkListOptions{Filters: labelFilter})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, network := range networks {
+		resources = append(resources, StackResource{ID: network.ID, Name: network.Name, Type: portainer.NetworkResourceControl})
+	}
+
+	volumes, err := cli.VolumeList(context.Background(), labelFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, volume := range volumes.Volumes {
+		resources = append(resources, StackResource{ID: volume.Name + volume.CreatedAt, Name: volume.Name, Type: portainer.VolumeResourceControl})
+	}
+
+	if swarm {
+		configs, err := cli.ConfigList(context.Background(), types.ConfigListOptions{Filters: labelFilter})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, config := range configs {
+			resources = append(resources, StackResource{ID: config.ID, Name: config.Spec.Name, Type: portainer.ConfigResourceControl})
+		}
+
+		secrets, err := cli.SecretList(context.Background(), types.SecretListOptions{Filters: labelFilter})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, secret := range secrets {
+			resources = append(resources, StackResource{ID: secret.ID, Name: secret.Spec.Name, Type: portainer.SecretResourceControl})
+		}
+	}
+
+	return resources, nil
+}
@@ -0,0 +1,232 @@
+package docker
+
+import (
+	"context"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/client"
+	portainer "github.com/portainer/portainer/api"
+)
+
+const (
+	// StackHealthy is the status of a stack whose containers are all running and healthy
+	StackHealthy = "healthy"
+	// StackStarting is the status of a stack whose containers are still being created or started
+	StackStarting = "starting"
+	// StackUnhealthy is the status of a stack with failed, restarting or unhealthy containers
+	StackUnhealthy = "unhealthy"
+	// StackDown is the status of a stack without any running container
+	StackDown = "down"
+)
+
+type (
+	// StackHealth represents the aggregated state of the containers and services of a stack
+	StackHealth struct {
+		Status     string               `json:"Status"`
+		Containers StackContainerCounts `json:"Containers"`
+		Services   []StackServiceHealth `json:"Services"`
+		Restarts   int                  `json:"Restarts"`
+	}
+
+	// StackContainerCounts represents the number of containers of a stack in each state.
+	// Failed counts the exited containers with a non-zero exit code.
+	StackContainerCounts struct {
+		Total      int `json:"Total"`
+		Created    int `json:"Created"`
+		Running    int `json:"Running"`
+		Paused     int `json:"Paused"`
+		Restarting int `json:"Restarting"`
+		Exited     int `json:"Exited"`
+		Failed     int `json:"Failed"`
+		Dead       int `json:"Dead"`
+		Healthy    int `json:"Healthy"`
+		Unhealthy  int `json:"Unhealthy"`
+		Starting   int `json:"Starting"`
+	}
+
+	// StackServiceHealth represents the state of the tasks of a Swarm service.
+	// Restarts counts the tasks of the service that failed or were rejected.
+	StackServiceHealth struct {
+		ID           string `json:"Id"`
+		Name         string `json:"Name"`
+		DesiredTasks int    `json:"DesiredTasks"`
+		RunningTasks int    `json:"RunningTasks"`
+		PendingTasks int    `json:"PendingTasks"`
+		Restarts     int    `json:"Restarts"`
+		UpdateState  string `json:"UpdateState,omitempty"`
+	}
+)
+
+// StackStatus aggregates the state of the containers of a stack and, for Swarm stacks, of its services
+func StackStatus(cli *client.Client, stack *portainer.Stack) (*StackHealth, error) {
+	label := ComposeStackNameLabel
+	if stack.Type == portainer.DockerSwarmStack {
+		label = SwarmStackNameLabel
+	}
+
+	labelFilter := filters.NewArgs()
+	labelFilter.Add("label", label+"="+stack.Name)
+
+	health := &StackHealth{Services: make([]StackServiceHealth, 0)}
+
+	err := countStackContainers(cli, labelFilter, health)
+	if err != nil {
+		return nil, err
+	}
+
+	if stack.Type == portainer.DockerSwarmStack {
+		err = collectStackServices(cli, labelFilter, health)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	health.Status = stackHealthStatus(health)
+	return health, nil
+}
+
+func countStackContainers(cli *client.Client, labelFilter filters.Args, health *StackHealth) error {
+	containers, err := cli.ContainerList(context.Background(), types.ContainerListOptions{All: true, Filters: labelFilter})
+	if err != nil {
+		return err
+	}
+
+	counts := &health.Containers
+	for _, container := range containers {
+		counts.Total++
+
+		switch container.State {
+		case "created":
+			counts.Created++
+		case "running":
+			counts.Running++
+		case "paused":
+			counts.Paused++
+		case "restarting":
+			counts.Restarting++
+		case "exited":
+			counts.Exited++
+		case "dead":
+			counts.Dead++
+		}
+
+		switch {
+		case strings.Contains(container.Status, "(unhealthy)"):
+			counts.Unhealthy++
+		case strings.Contains(container.Status, "(health: starting)"):
+			counts.Starting++
+		case strings.Contains(container.Status, "(healthy)"):
+			counts.Healthy++
+		}
+
+		details, err := cli.ContainerInspect(context.Background(), container.ID)
+		if err != nil {
+			if client.IsErrNotFound(err) {
+				continue
+			}
+			return err
+		}
+
+		health.Restarts += details.RestartCount
+		if container.State == "exited" && details.State != nil && details.State.ExitCode != 0 {
+			counts.Failed++
+		}
+	}
+
+	return nil
+}
+
+func collectStackServices(cli *client.Client, labelFilter filters.Args, health *StackHealth) error {
+	services, err := cli.ServiceList(context.Background(), types.ServiceListOptions{Filters: labelFilter})
+	if err != nil {
+		return err
+	}
+
+	for _, service := range services {
+		serviceFilter := filters.NewArgs()
+		serviceFilter.Add("service", service.ID)
+
+		tasks, err := cli.TaskList(context.Background(), types.TaskListOptions{Filters: serviceFilter})
+		if err != nil {
+			return err
+		}
+
+		serviceHealth := StackServiceHealth{ID: service.ID, Name: service.Spec.Name}
+		if service.UpdateStatus != nil {
+			serviceHealth.UpdateState = string(service.UpdateStatus.State)
+		}
+
+		for _, task := range tasks {
+			switch task.Status.State {
+			case swarm.TaskStateFailed, swarm.TaskStateRejected:
+				serviceHealth.Restarts++
+			}
+
+			if task.DesiredState != swarm.TaskStateRunning {
+				continue
+			}
+
+			if service.Spec.Mode.Global != nil {
+				serviceHealth.DesiredTasks++
+			}
+
+			switch task.Status.State {
+			case swarm.TaskStateRunning:
+				serviceHealth.RunningTasks++
+			case swarm.TaskStateNew, swarm.TaskStatePending, swarm.TaskStateAssigned, swarm.TaskStateAccepted,
+				swarm.TaskStatePreparing, swarm.TaskStateReady, swarm.TaskStateStarting:
+				serviceHealth.PendingTasks++
+			}
+		}
+
+		if service.Spec.Mode.Replicated != nil && service.Spec.Mode.Replicated.Replicas != nil {
+			serviceHealth.DesiredTasks = int(*service.Spec.Mode.Replicated.Replicas)
+		}
+
+		health.Restarts += serviceHealth.Restarts
+		health.Services = append(health.Services, serviceHealth)
+	}
+
+	return nil
+}
+
+// stackHealthStatus summarizes the state of a stack. For Swarm stacks, the services must run all their
+// desired tasks, for Compose stacks, the containers exiting with a zero exit code are considered as completed.
+func stackHealthStatus(health *StackHealth) string {
+	counts := health.Containers
+
+	starting := counts.Created > 0 || counts.Starting > 0
+	unhealthy := counts.Unhealthy > 0 || counts.Restarting > 0 || counts.Dead > 0
+	running := counts.Running > 0
+
+	if len(health.Services) > 0 {
+		running = false
+		for _, service := range health.Services {
+			if service.RunningTasks > 0 {
+				running = true
+			}
+			if service.RunningTasks < service.DesiredTasks {
+				if service.PendingTasks > 0 {
+					starting = true
+				} else {
+					unhealthy = true
+				}
+			}
+		}
+	} else if counts.Failed > 0 {
+		unhealthy = true
+	}
+
+	switch {
+	case unhealthy:
+		return StackUnhealthy
+	case starting:
+		return StackStarting
+	case !running:
+		return StackDown
+	}
+	return StackHealthy
+}
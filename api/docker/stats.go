@@ -0,0 +1,147 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+)
+
+// unlimitedMemory is the lowest memory limit considered as no limit, cgroup v1 reports the highest
+// page aligned 64 bits value and cgroup v2 reports the highest 64 bits value
+const unlimitedMemory = 1 << 62
+
+// ContainerStats represents the resource usage of a container. The Linux and Windows daemons
+// report the usage using different structures, both are normalized to this representation.
+type ContainerStats struct {
+	OSType     string  `json:"OSType"`
+	CPUPercent float64 `json:"CPUPercent"`
+	// MemoryUsage is the memory used by the container, excluding the page cache on Linux
+	// and corresponding to the private working set on Windows
+	MemoryUsage uint64 `json:"MemoryUsage"`
+	// MemoryLimit is not reported by Windows hosts
+	MemoryLimit   uint64  `json:"MemoryLimit"`
+	MemoryPercent float64 `json:"MemoryPercent"`
+	NetworkRx     uint64  `json:"NetworkRx"`
+	NetworkTx     uint64  `json:"NetworkTx"`
+	BlockRead     uint64  `json:"BlockRead"`
+	BlockWrite    uint64  `json:"BlockWrite"`
+	// Processes is the number of processes, or of PIDs on Linux hosts
+	Processes uint64 `json:"Processes"`
+	// CgroupVersion is the cgroup version of the statistics of a Linux container, 0 when unknown
+	CgroupVersion int `json:"CgroupVersion,omitempty"`
+}
+
+// RetrieveContainerStats retrieves a single sample of the resource usage of a container
+func RetrieveContainerStats(cli *client.Client, containerID string) (*ContainerStats, error) {
+	response, err := cli.ContainerStats(context.Background(), containerID, false)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	var stats types.StatsJSON
+	err = json.NewDecoder(response.Body).Decode(&stats)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseContainerStats(&stats, response.OSType), nil
+}
+
+// ParseContainerStats normalizes the statistics reported by a Docker host running the specified operating system
+func ParseContainerStats(stats *types.StatsJSON, osType string) *ContainerStats {
+	result := &ContainerStats{
+		OSType: osType,
+	}
+
+	for _, network := range stats.Networks {
+		result.NetworkRx += network.RxBytes
+		result.NetworkTx += network.TxBytes
+	}
+
+	if osType == OSTypeWindows {
+		result.CPUPercent = windowsCPUPercent(stats)
+		result.MemoryUsage = stats.MemoryStats.PrivateWorkingSet
+		result.BlockRead = stats.StorageStats.ReadSizeBytes
+		result.BlockWrite = stats.StorageStats.WriteSizeBytes
+		result.Processes = uint64(stats.NumProcs)
+		return result
+	}
+
+	result.CgroupVersion = statsCgroupVersion(stats)
+	result.CPUPercent = linuxCPUPercent(stats)
+	result.MemoryUsage = linuxMemoryUsage(stats)
+	// the containers without memory limit report the maximum value of the cgroup version instead of the host memory
+	if stats.MemoryStats.Limit < unlimitedMemory {
+		result.MemoryLimit = stats.MemoryStats.Limit
+	}
+	if result.MemoryLimit > 0 {
+		result.MemoryPercent = float64(result.MemoryUsage) / float64(result.MemoryLimit) * 100
+	}
+	result.Processes = stats.PidsStats.Current
+
+	for _, entry := range stats.BlkioStats.IoServiceBytesRecursive {
+		switch strings.ToLower(entry.Op) {
+		case "read":
+			result.BlockRead += entry.Value
+		case "write":
+			result.BlockWrite += entry.Value
+		}
+	}
+
+	return result
+}
+
+// linuxCPUPercent compares the container and host CPU usage between the previous and the current sample
+func linuxCPUPercent(stats *types.StatsJSON) float64 {
+	cpuDelta := float64(stats.CPUStats.CPUUsage.TotalUsage) - float64(stats.PreCPUStats.CPUUsage.TotalUsage)
+	systemDelta := float64(stats.CPUStats.SystemUsage) - float64(stats.PreCPUStats.SystemUsage)
+
+	onlineCPUs := float64(stats.CPUStats.OnlineCPUs)
+	if onlineCPUs == 0 {
+		onlineCPUs = float64(len(stats.CPUStats.CPUUsage.PercpuUsage))
+	}
+
+	if cpuDelta <= 0 || systemDelta <= 0 {
+		return 0
+	}
+
+	return cpuDelta / systemDelta * onlineCPUs * 100
+}
+
+// windowsCPUPercent compares the CPU usage of the container, expressed in 100ns intervals,
+// with the intervals available to its processors between the previous and the current sample
+func windowsCPUPercent(stats *types.StatsJSON) float64 {
+	if stats.PreRead.IsZero() || !stats.Read.After(stats.PreRead) {
+		return 0
+	}
+
+	possibleIntervals := float64(stats.Read.Sub(stats.PreRead).Nanoseconds()) / 100 * float64(stats.NumProcs)
+	usedIntervals := float64(stats.CPUStats.CPUUsage.TotalUsage) - float64(stats.PreCPUStats.CPUUsage.TotalUsage)
+
+	if possibleIntervals <= 0 || usedIntervals <= 0 {
+		return 0
+	}
+
+	return usedIntervals / possibleIntervals * 100
+}
+
+// linuxMemoryUsage excludes the inactive page cache from the memory usage, the name of
+// the statistic depends on the cgroup version of the host
+func linuxMemoryUsage(stats *types.StatsJSON) uint64 {
+	usage := stats.MemoryStats.Usage
+
+	for _, key := range []string{"total_inactive_file", "inactive_file"} {
+		if value, ok := stats.MemoryStats.Stats[key]; ok {
+			if value < usage {
+				return usage - value
+			}
+			return usage
+		}
+	}
+
+	return usage
+}
@@ -0,0 +1,105 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/docker/docker/api/types/versions"
+	portainer "github.com/portainer/portainer/api"
+)
+
+const (
+	// minDockerAPIVersion is the lowest Docker API version supported by Portainer (Docker 1.12)
+	minDockerAPIVersion = "1.24"
+	// apiVersionNegotiationTimeout is the maximum duration of the ping used to negotiate the API version
+	apiVersionNegotiationTimeout = 10 * time.Second
+)
+
+// NegotiateAPIVersion pings the Docker daemon of an endpoint to retrieve the highest API version it supports
+// and records it so that the clients created for this endpoint use a compatible version.
+// It returns the API version that will be used by the clients.
+func (factory *ClientFactory) NegotiateAPIVersion(endpoint *portainer.Endpoint) (string, error) {
+	cli, err := factory.createClient(endpoint, "", dockerClientVersion)
+	if err != nil {
+		return "", err
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), apiVersionNegotiationTimeout)
+	defer cancel()
+
+	// the ping request is not versioned and is answered by any daemon version
+	ping, err := cli.Ping(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if ping.APIVersion == "" {
+		return dockerClientVersion, nil
+	}
+
+	err = checkAPIVersion(ping.APIVersion)
+	if err != nil {
+		return "", err
+	}
+
+	factory.apiVersionsMutex.Lock()
+	factory.apiVersions[endpoint.ID] = ping.APIVersion
+	factory.apiVersionsMutex.Unlock()
+
+	return clientAPIVersion(ping.APIVersion), nil
+}
+
+// ServerAPIVersion returns the highest API version supported by the Docker daemon of an endpoint.
+// It relies on the last negotiation and falls back to the last snapshot of the endpoint,
+// an empty string is returned when the version is unknown.
+func (factory *ClientFactory) ServerAPIVersion(endpoint *portainer.Endpoint) string {
+	factory.apiVersionsMutex.Lock()
+	version, ok := factory.apiVersions[endpoint.ID]
+	factory.apiVersionsMutex.Unlock()
+
+	if ok {
+		return version
+	}
+
+	if len(endpoint.Snapshots) > 0 {
+		return endpoint.Snapshots[0].APIVersion
+	}
+
+	return ""
+}
+
+func (factory *ClientFactory) clientAPIVersion(endpoint *portainer.Endpoint) string {
+	return clientAPIVersion(factory.ServerAPIVersion(endpoint))
+}
+
+// clientAPIVersion returns the API version to use to communicate with a daemon supporting
+// up to serverVersion, Portainer never uses a version higher than dockerClientVersion.
+func clientAPIVersion(serverVersion string) string {
+	if serverVersion == "" || versions.GreaterThan(serverVersion, dockerClientVersion) {
+		return dockerClientVersion
+	}
+	return serverVersion
+}
+
+func checkAPIVersion(serverVersion string) error {
+	if versions.LessThan(serverVersion, minDockerAPIVersion) {
+		return fmt.Errorf("Docker API version %s is not supported, the minimum supported version is %s", serverVersion, minDockerAPIVersion)
+	}
+	return nil
+}
+
+// Capabilities returns the optional features supported by a daemon supporting up to the specified API version
+func Capabilities(apiVersion string) portainer.DockerCapabilities {
+	if apiVersion == "" {
+		return portainer.DockerCapabilities{}
+	}
+
+	return portainer.DockerCapabilities{
+		Secrets:         versions.GreaterThanOrEqualTo(apiVersion, "1.25"),
+		Configs:         versions.GreaterThanOrEqualTo(apiVersion, "1.30"),
+		ServiceRollback: versions.GreaterThanOrEqualTo(apiVersion, "1.28"),
+		DeviceRequests:  versions.GreaterThanOrEqualTo(apiVersion, "1.40"),
+	}
+}
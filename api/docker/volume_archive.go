@@ -0,0 +1,145 @@
+package docker
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/client"
+)
+
+const (
+	// VolumeHelperImage is the image of the containers used to copy the content of the volumes
+	VolumeHelperImage = "busybox:latest"
+	// VolumeHelperLabel identifies the containers used to copy the content of the volumes
+	VolumeHelperLabel = "io.portainer.volume.helper"
+
+	volumeHelperMountPoint = "/volume"
+)
+
+// VolumeHelper represents a container mounting a volume, used to export or import the content of the volume.
+// The container is never started: the archives are copied through the Docker API.
+type VolumeHelper struct {
+	cli         *client.Client
+	containerID string
+}
+
+// NewVolumeHelper creates a helper container mounting the specified volume, the helper image is pulled
+// when it is not available on the host. The helper must be removed once the copy is over.
+func NewVolumeHelper(cli *client.Client, volumeName string, readOnly bool) (*VolumeHelper, error) {
+	err := ensureVolumeHelperImage(cli)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &container.Config{
+		Image:  VolumeHelperImage,
+		Labels: map[string]string{VolumeHelperLabel: "true"},
+	}
+
+	hostConfig := &container.HostConfig{
+		NetworkMode: "none",
+		Mounts: []mount.Mount{
+			{
+				Type:     mount.TypeVolume,
+				Source:   volumeName,
+				Target:   volumeHelperMountPoint,
+				ReadOnly: readOnly,
+			},
+		},
+	}
+
+	created, err := cli.ContainerCreate(context.Background(), config, hostConfig, nil, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return &VolumeHelper{
+		cli:         cli,
+		containerID: created.ID,
+	}, nil
+}
+
+func ensureVolumeHelperImage(cli *client.Client) error {
+	_, _, err := cli.ImageInspectWithRaw(context.Background(), VolumeHelperImage)
+	if err == nil || !client.IsErrNotFound(err) {
+		return err
+	}
+
+	reader, err := cli.ImagePull(context.Background(), VolumeHelperImage, types.ImagePullOptions{})
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	_, err = io.Copy(ioutil.Discard, reader)
+	return err
+}
+
+// Export returns a tar archive of the content of the volume, the entries are located under the volume directory
+func (helper *VolumeHelper) Export() (io.ReadCloser, error) {
+	reader, _, err := helper.cli.CopyFromContainer(context.Background(), helper.containerID, volumeHelperMountPoint)
+	return reader, err
+}
+
+// Import extracts an archive into the volume, over its existing content.
+// The archive can be compressed with gzip, bzip2 or xz.
+func (helper *VolumeHelper) Import(archive io.Reader) error {
+	return helper.cli.CopyToContainer(context.Background(), helper.containerID, volumeHelperMountPoint, archive, types.CopyToContainerOptions{})
+}
+
+// Remove removes the helper container
+func (helper *VolumeHelper) Remove() error {
+	return helper.cli.ContainerRemove(context.Background(), helper.containerID, types.ContainerRemoveOptions{Force: true})
+}
+
+// CompressVolumeArchive writes an archive exported by a volume helper as a tar.gz archive whose entries
+// are relative to the root of the volume, so that it can be extracted with tar -xzf into any directory.
+func CompressVolumeArchive(archive io.Reader, w io.Writer) error {
+	gzipWriter := gzip.NewWriter(w)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	prefix := strings.TrimPrefix(volumeHelperMountPoint, "/") + "/"
+
+	tarReader := tar.NewReader(archive)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+
+		name := strings.TrimPrefix(header.Name, prefix)
+		if name == "" || name == header.Name {
+			continue
+		}
+		header.Name = name
+		if header.Typeflag == tar.TypeLink {
+			header.Linkname = strings.TrimPrefix(header.Linkname, prefix)
+		}
+
+		err = tarWriter.WriteHeader(header)
+		if err != nil {
+			return err
+		}
+
+		_, err = io.Copy(tarWriter, tarReader)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := tarWriter.Close()
+	if err != nil {
+		return err
+	}
+
+	return gzipWriter.Close()
+}
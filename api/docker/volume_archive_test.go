@@ -0,0 +1,72 @@
+package docker
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"testing"
+)
+
+func TestCompressVolumeArchive(t *testing.T) {
+	var archive bytes.Buffer
+	tarWriter := tar.NewWriter(&archive)
+
+	entries := []struct {
+		header  tar.Header
+		content string
+	}{
+		{tar.Header{Name: "volume/", Typeflag: tar.TypeDir, Mode: 0755}, ""},
+		{tar.Header{Name: "volume/data/", Typeflag: tar.TypeDir, Mode: 0755}, ""},
+		{tar.Header{Name: "volume/data/db.sqlite", Typeflag: tar.TypeReg, Mode: 0644, Size: 5}, "hello"},
+		{tar.Header{Name: "volume/data/link", Typeflag: tar.TypeLink, Linkname: "volume/data/db.sqlite"}, ""},
+	}
+	for _, entry := range entries {
+		header := entry.header
+		if err := tarWriter.WriteHeader(&header); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tarWriter.Write([]byte(entry.content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tarWriter.Close()
+
+	var compressed bytes.Buffer
+	if err := CompressVolumeArchive(&archive, &compressed); err != nil {
+		t.Fatal(err)
+	}
+
+	gzipReader, err := gzip.NewReader(&compressed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tarReader := tar.NewReader(gzipReader)
+	expected := []string{"data/", "data/db.sqlite", "data/link"}
+	for _, name := range expected {
+		header, err := tarReader.Next()
+		if err != nil {
+			t.Fatalf("missing entry %s: %s", name, err)
+		}
+		if header.Name != name {
+			t.Errorf("got entry %s, want %s", header.Name, name)
+		}
+
+		switch header.Typeflag {
+		case tar.TypeReg:
+			content, _ := ioutil.ReadAll(tarReader)
+			if string(content) != "hello" {
+				t.Errorf("got content %q for %s", content, name)
+			}
+		case tar.TypeLink:
+			if header.Linkname != "data/db.sqlite" {
+				t.Errorf("got link target %s for %s", header.Linkname, name)
+			}
+		}
+	}
+
+	if _, err := tarReader.Next(); err == nil {
+		t.Error("unexpected additional entry in the archive")
+	}
+}
@@ -0,0 +1,211 @@
+package docker
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+const (
+	// LocalVolumeDriver is the default volume driver of the Docker daemon, it mounts remote shares and devices
+	// using the mount options of the host
+	LocalVolumeDriver = "local"
+
+	// NFSVolumeType represents a volume mounting an NFS export
+	NFSVolumeType = "nfs"
+	// CIFSVolumeType represents a volume mounting a CIFS/SMB share
+	CIFSVolumeType = "cifs"
+	// DeviceVolumeType represents a volume mounting a block device of the host
+	DeviceVolumeType = "device"
+
+	maskedCredential = "********"
+)
+
+var (
+	mountHostRe      = regexp.MustCompile(`^[a-zA-Z0-9.:\[\]_-]+$`)
+	mountVersionRe   = regexp.MustCompile(`^[0-9]+(\.[0-9]+)*$`)
+	mountFSTypeRe    = regexp.MustCompile(`^[a-z0-9_.-]+$`)
+	mountOptionRe    = regexp.MustCompile(`^[a-zA-Z0-9_.-]+(=[^,\s]*)?$`)
+	mountPasswordRe  = regexp.MustCompile(`((?:^|,)(?:password|pass)=)[^,]*`)
+	nfsVersions      = []string{"3", "4", "4.0", "4.1", "4.2"}
+	nfsManagedKeys   = []string{"addr", "nfsvers", "vers"}
+	cifsManagedKeys  = []string{"addr", "username", "user", "password", "pass", "domain", "vers", "guest"}
+	errMountPassword = errors.New("invalid password: commas are not supported in the mount options")
+)
+
+// NFSVolumeOptions represents a volume of the local driver mounting an NFS export
+type NFSVolumeOptions struct {
+	// Server is the hostname or the IP address of the NFS server
+	Server string `json:"Server"`
+	// Path is the absolute path of the export on the server
+	Path string `json:"Path"`
+	// Version is the NFS protocol version, the version is negotiated when it is not specified
+	Version  string   `json:"Version,omitempty"`
+	ReadOnly bool     `json:"ReadOnly"`
+	Options  []string `json:"Options,omitempty"`
+}
+
+// CIFSVolumeOptions represents a volume of the local driver mounting a CIFS/SMB share.
+// The share is mounted as guest when no username is specified.
+type CIFSVolumeOptions struct {
+	Server string `json:"Server"`
+	// Share is the name of the share, optionally followed by a path inside the share
+	Share    string   `json:"Share"`
+	Username string   `json:"Username,omitempty"`
+	Password string   `json:"Password,omitempty"`
+	Domain   string   `json:"Domain,omitempty"`
+	Version  string   `json:"Version,omitempty"`
+	ReadOnly bool     `json:"ReadOnly"`
+	Options  []string `json:"Options,omitempty"`
+}
+
+// DeviceVolumeOptions represents a volume of the local driver mounting a block device of the host
+type DeviceVolumeOptions struct {
+	// Device is the absolute path of the device on the host, such as /dev/sdb1
+	Device string `json:"Device"`
+	// FSType is the type of the filesystem of the device, such as ext4 or xfs
+	FSType  string   `json:"FSType"`
+	Options []string `json:"Options,omitempty"`
+}
+
+// DriverOptions returns the options of the local driver mounting the NFS export
+func (options *NFSVolumeOptions) DriverOptions() (map[string]string, error) {
+	if !mountHostRe.MatchString(options.Server) {
+		return nil, errors.New("invalid NFS server address")
+	}
+
+	if !path.IsAbs(options.Path) || strings.ContainsAny(options.Path, ", ") {
+		return nil, errors.New("invalid NFS export path: value must be an absolute path")
+	}
+
+	mountOptions := []string{"addr=" + options.Server}
+	if options.Version != "" {
+		if !contains(nfsVersions, options.Version) {
+			return nil, fmt.Errorf("invalid NFS version: value must be one of %s", strings.Join(nfsVersions, ", "))
+		}
+		mountOptions = append(mountOptions, "nfsvers="+options.Version)
+	}
+
+	mountOptions, err := appendMountOptions(mountOptions, options.ReadOnly, options.Options, nfsManagedKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]string{
+		"type":   "nfs",
+		"device": ":" + options.Path,
+		"o":      strings.Join(mountOptions, ","),
+	}, nil
+}
+
+// DriverOptions returns the options of the local driver mounting the CIFS share
+func (options *CIFSVolumeOptions) DriverOptions() (map[string]string, error) {
+	if !mountHostRe.MatchString(options.Server) {
+		return nil, errors.New("invalid CIFS server address")
+	}
+
+	share := strings.Trim(options.Share, "/")
+	if share == "" || strings.ContainsAny(share, ", ") {
+		return nil, errors.New("invalid CIFS share name")
+	}
+
+	mountOptions := []string{"addr=" + options.Server}
+	if options.Username == "" {
+		mountOptions = append(mountOptions, "guest")
+	} else {
+		if strings.ContainsAny(options.Username, ",=") || strings.ContainsAny(options.Domain, ",=") {
+			return nil, errors.New("invalid CIFS username or domain")
+		}
+		if strings.Contains(options.Password, ",") {
+			return nil, errMountPassword
+		}
+
+		mountOptions = append(mountOptions, "username="+options.Username, "password="+options.Password)
+		if options.Domain != "" {
+			mountOptions = append(mountOptions, "domain="+options.Domain)
+		}
+	}
+
+	if options.Version != "" {
+		if !mountVersionRe.MatchString(options.Version) {
+			return nil, errors.New("invalid CIFS protocol version")
+		}
+		mountOptions = append(mountOptions, "vers="+options.Version)
+	}
+
+	mountOptions, err := appendMountOptions(mountOptions, options.ReadOnly, options.Options, cifsManagedKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]string{
+		"type":   "cifs",
+		"device": "//" + options.Server + "/" + share,
+		"o":      strings.Join(mountOptions, ","),
+	}, nil
+}
+
+// DriverOptions returns the options of the local driver mounting the device
+func (options *DeviceVolumeOptions) DriverOptions() (map[string]string, error) {
+	if !path.IsAbs(options.Device) || strings.ContainsAny(options.Device, ", ") {
+		return nil, errors.New("invalid device path: value must be an absolute path")
+	}
+
+	if !mountFSTypeRe.MatchString(options.FSType) {
+		return nil, errors.New("invalid filesystem type")
+	}
+
+	driverOptions := map[string]string{
+		"type":   options.FSType,
+		"device": options.Device,
+	}
+
+	mountOptions, err := appendMountOptions([]string{}, false, options.Options, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(mountOptions) > 0 {
+		driverOptions["o"] = strings.Join(mountOptions, ",")
+	}
+
+	return driverOptions, nil
+}
+
+// appendMountOptions validates the additional mount options, the options managed by the typed
+// volume definitions cannot be overridden
+func appendMountOptions(mountOptions []string, readOnly bool, additionalOptions []string, managedKeys []string) ([]string, error) {
+	if readOnly {
+		mountOptions = append(mountOptions, "ro")
+	}
+
+	for _, option := range additionalOptions {
+		if !mountOptionRe.MatchString(option) {
+			return nil, fmt.Errorf("invalid mount option: %s", option)
+		}
+
+		key := strings.SplitN(option, "=", 2)[0]
+		if contains(managedKeys, key) {
+			return nil, fmt.Errorf("invalid mount option: %s is defined by the volume definition", key)
+		}
+
+		mountOptions = append(mountOptions, option)
+	}
+
+	return mountOptions, nil
+}
+
+// MaskMountCredentials replaces the passwords of the mount options of a volume
+func MaskMountCredentials(mountOptions string) string {
+	return mountPasswordRe.ReplaceAllString(mountOptions, "${1}"+maskedCredential)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,81 @@
+package docker
+
+import (
+	"reflect"
+	"testing"
+)
+
+type volumeDefinition interface {
+	DriverOptions() (map[string]string, error)
+}
+
+func TestVolumeDriverOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  volumeDefinition
+		expected map[string]string
+	}{
+		{
+			"nfs",
+			&NFSVolumeOptions{Server: "10.0.0.5", Path: "/exports/data", Version: "4.1", ReadOnly: true, Options: []string{"soft", "timeo=30"}},
+			map[string]string{"type": "nfs", "device": ":/exports/data", "o": "addr=10.0.0.5,nfsvers=4.1,ro,soft,timeo=30"},
+		},
+		{
+			"cifs with credentials",
+			&CIFSVolumeOptions{Server: "fileserver", Share: "/backups/app/", Username: "svc", Password: "s3cr=t", Domain: "CORP", Version: "3.0"},
+			map[string]string{"type": "cifs", "device": "//fileserver/backups/app", "o": "addr=fileserver,username=svc,password=s3cr=t,domain=CORP,vers=3.0"},
+		},
+		{
+			"cifs as guest",
+			&CIFSVolumeOptions{Server: "fileserver", Share: "public"},
+			map[string]string{"type": "cifs", "device": "//fileserver/public", "o": "addr=fileserver,guest"},
+		},
+		{
+			"device",
+			&DeviceVolumeOptions{Device: "/dev/sdb1", FSType: "ext4"},
+			map[string]string{"type": "ext4", "device": "/dev/sdb1"},
+		},
+	}
+
+	for _, test := range tests {
+		driverOptions, err := test.options.DriverOptions()
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", test.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(driverOptions, test.expected) {
+			t.Errorf("%s: got %v, want %v", test.name, driverOptions, test.expected)
+		}
+	}
+}
+
+func TestVolumeDriverOptionsValidation(t *testing.T) {
+	invalid := []volumeDefinition{
+		&NFSVolumeOptions{Server: "10.0.0.5", Path: "exports"},
+		&NFSVolumeOptions{Server: "10.0.0.5,addr=evil", Path: "/exports"},
+		&NFSVolumeOptions{Server: "10.0.0.5", Path: "/exports", Version: "5"},
+		&NFSVolumeOptions{Server: "10.0.0.5", Path: "/exports", Options: []string{"addr=10.0.0.6"}},
+		&CIFSVolumeOptions{Server: "fileserver", Share: "data", Username: "svc", Password: "a,b"},
+		&CIFSVolumeOptions{Server: "fileserver", Share: ""},
+		&DeviceVolumeOptions{Device: "sdb1", FSType: "ext4"},
+		&DeviceVolumeOptions{Device: "/dev/sdb1", FSType: "ext4", Options: []string{"rw,exec"}},
+	}
+
+	for _, options := range invalid {
+		if _, err := options.DriverOptions(); err == nil {
+			t.Errorf("invalid definition accepted: %+v", options)
+		}
+	}
+}
+
+func TestMaskMountCredentials(t *testing.T) {
+	masked := MaskMountCredentials("addr=fileserver,username=svc,password=s3cr=t,vers=3.0")
+	if masked != "addr=fileserver,username=svc,password=********,vers=3.0" {
+		t.Errorf("got %s", masked)
+	}
+
+	masked = MaskMountCredentials("pass=secret")
+	if masked != "pass=********" {
+		t.Errorf("got %s", masked)
+	}
+}
@@ -0,0 +1,114 @@
+package docker
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/docker/docker/api/types/container"
+	portainer "github.com/portainer/portainer/api"
+)
+
+const (
+	// OSTypeLinux is the operating system reported by Linux Docker hosts
+	OSTypeLinux = "linux"
+	// OSTypeWindows is the operating system reported by Windows Docker hosts
+	OSTypeWindows = "windows"
+)
+
+// windowsBindPath matches a host or container path of a Windows bind mount: a drive letter optionally followed
+// by an absolute path, or a named pipe
+const windowsBindPath = `[a-zA-Z]:(?:[\\/][^:]*)?|\\\\\.\\pipe\\[^:]+`
+
+var windowsBindRe = regexp.MustCompile(`^(` + windowsBindPath + `|[^\\/:]+):(` + windowsBindPath + `)(?::([a-zA-Z,]+))?$`)
+
+// BindMount represents a parsed bind mount specification of a container
+type BindMount struct {
+	Source      string
+	Destination string
+	Mode        string
+	// HostPath is true when the source is a path of the host, false when it is a named volume
+	HostPath bool
+}
+
+// EndpointOSType returns the operating system of the Docker host of an endpoint, based on its last snapshot.
+// An endpoint using a named pipe is a Windows host, otherwise Linux is assumed.
+func EndpointOSType(endpoint *portainer.Endpoint) string {
+	if len(endpoint.Snapshots) > 0 && endpoint.Snapshots[0].OSType != "" {
+		return endpoint.Snapshots[0].OSType
+	}
+
+	if strings.HasPrefix(endpoint.URL, "npipe://") {
+		return OSTypeWindows
+	}
+
+	return OSTypeLinux
+}
+
+// ValidateIsolation ensures that an isolation technology is supported by a Docker host running the specified operating system
+func ValidateIsolation(isolation container.Isolation, osType string) error {
+	if isolation.IsDefault() {
+		return nil
+	}
+
+	if osType != OSTypeWindows {
+		return fmt.Errorf("isolation %s is only supported on Windows hosts", isolation)
+	}
+
+	if !isolation.IsHyperV() && !isolation.IsProcess() {
+		return fmt.Errorf("invalid isolation %s, supported values are default, process and hyperv", isolation)
+	}
+
+	return nil
+}
+
+// ParseBind parses a bind mount specification (source:destination[:mode]) using the path conventions
+// of a Docker host running the specified operating system.
+func ParseBind(bind, osType string) (*BindMount, error) {
+	if osType == OSTypeWindows {
+		return parseWindowsBind(bind)
+	}
+	return parseLinuxBind(bind)
+}
+
+func parseWindowsBind(bind string) (*BindMount, error) {
+	match := windowsBindRe.FindStringSubmatch(bind)
+	if match == nil {
+		return nil, fmt.Errorf("invalid bind mount %s, Windows hosts expect absolute paths such as C:\\data or named pipes", bind)
+	}
+
+	mount := &BindMount{
+		Source:      match[1],
+		Destination: match[2],
+		Mode:        match[3],
+	}
+	mount.HostPath = strings.ContainsAny(mount.Source, `:\/`)
+
+	if strings.EqualFold(strings.TrimRight(mount.Destination, `\/`), "c:") {
+		return nil, fmt.Errorf("invalid bind mount %s, the destination cannot be the root of the C: drive", bind)
+	}
+
+	return mount, nil
+}
+
+func parseLinuxBind(bind string) (*BindMount, error) {
+	parts := strings.Split(bind, ":")
+	if len(parts) < 2 || len(parts) > 3 || parts[0] == "" {
+		return nil, fmt.Errorf("invalid bind mount %s, expected source:destination[:mode]", bind)
+	}
+
+	mount := &BindMount{
+		Source:      parts[0],
+		Destination: parts[1],
+		HostPath:    strings.HasPrefix(parts[0], "/"),
+	}
+	if len(parts) == 3 {
+		mount.Mode = parts[2]
+	}
+
+	if !strings.HasPrefix(mount.Destination, "/") {
+		return nil, fmt.Errorf("invalid bind mount %s, the destination must be an absolute path", bind)
+	}
+
+	return mount, nil
+}
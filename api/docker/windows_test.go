@@ -0,0 +1,158 @@
+package docker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	portainer "github.com/portainer/portainer/api"
+)
+
+func TestParseBind(t *testing.T) {
+	tests := []struct {
+		bind     string
+		osType   string
+		expected *BindMount
+	}{
+		{`C:\data:C:\app`, OSTypeWindows, &BindMount{Source: `C:\data`, Destination: `C:\app`, HostPath: true}},
+		{`c:/data:c:/app:ro`, OSTypeWindows, &BindMount{Source: `c:/data`, Destination: `c:/app`, Mode: "ro", HostPath: true}},
+		{`appdata:C:\app`, OSTypeWindows, &BindMount{Source: "appdata", Destination: `C:\app`}},
+		{`D:\:D:`, OSTypeWindows, &BindMount{Source: `D:\`, Destination: "D:", HostPath: true}},
+		{`\\.\pipe\docker_engine:\\.\pipe\docker_engine`, OSTypeWindows, &BindMount{Source: `\\.\pipe\docker_engine`, Destination: `\\.\pipe\docker_engine`, HostPath: true}},
+		{`/data:/data`, OSTypeWindows, nil},
+		{`C:\data:C:\`, OSTypeWindows, nil},
+		{`C:\data`, OSTypeWindows, nil},
+		{`/data:/app:ro`, OSTypeLinux, &BindMount{Source: "/data", Destination: "/app", Mode: "ro", HostPath: true}},
+		{`appdata:/app`, OSTypeLinux, &BindMount{Source: "appdata", Destination: "/app"}},
+		{`/data:app`, OSTypeLinux, nil},
+		{`/data`, OSTypeLinux, nil},
+	}
+
+	for _, test := range tests {
+		mount, err := ParseBind(test.bind, test.osType)
+		if test.expected == nil {
+			if err == nil {
+				t.Errorf("ParseBind(%q, %s): expected an error, got %+v", test.bind, test.osType, mount)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("ParseBind(%q, %s): unexpected error: %s", test.bind, test.osType, err)
+			continue
+		}
+
+		if *mount != *test.expected {
+			t.Errorf("ParseBind(%q, %s): expected %+v, got %+v", test.bind, test.osType, test.expected, mount)
+		}
+	}
+}
+
+func TestValidateIsolation(t *testing.T) {
+	tests := []struct {
+		isolation container.Isolation
+		osType    string
+		valid     bool
+	}{
+		{"", OSTypeLinux, true},
+		{"default", OSTypeLinux, true},
+		{"hyperv", OSTypeLinux, false},
+		{"process", OSTypeWindows, true},
+		{"HyperV", OSTypeWindows, true},
+		{"sandbox", OSTypeWindows, false},
+	}
+
+	for _, test := range tests {
+		err := ValidateIsolation(test.isolation, test.osType)
+		if (err == nil) != test.valid {
+			t.Errorf("ValidateIsolation(%s, %s): expected valid=%t, got error %v", test.isolation, test.osType, test.valid, err)
+		}
+	}
+}
+
+func TestEndpointOSType(t *testing.T) {
+	endpoint := &portainer.Endpoint{URL: "npipe:////./pipe/docker_engine"}
+	if osType := EndpointOSType(endpoint); osType != OSTypeWindows {
+		t.Errorf("expected a named pipe endpoint to be a Windows host, got %s", osType)
+	}
+
+	endpoint = &portainer.Endpoint{URL: "tcp://10.0.0.1:2375", Snapshots: []portainer.DockerSnapshot{{OSType: OSTypeWindows}}}
+	if osType := EndpointOSType(endpoint); osType != OSTypeWindows {
+		t.Errorf("expected the operating system of the snapshot to be used, got %s", osType)
+	}
+
+	endpoint = &portainer.Endpoint{URL: "tcp://10.0.0.1:2375"}
+	if osType := EndpointOSType(endpoint); osType != OSTypeLinux {
+		t.Errorf("expected Linux to be assumed, got %s", osType)
+	}
+}
+
+func TestParseContainerStats(t *testing.T) {
+	now := time.Now()
+
+	windowsStats := &types.StatsJSON{}
+	windowsStats.Read = now
+	windowsStats.PreRead = now.Add(-time.Second)
+	windowsStats.NumProcs = 2
+	// 1 second on 2 processors represents 2e7 intervals of 100ns, half of them are used
+	windowsStats.CPUStats.CPUUsage.TotalUsage = 15000000
+	windowsStats.PreCPUStats.CPUUsage.TotalUsage = 5000000
+	windowsStats.MemoryStats.PrivateWorkingSet = 1024
+	windowsStats.StorageStats.ReadSizeBytes = 10
+	windowsStats.StorageStats.WriteSizeBytes = 20
+	windowsStats.Networks = map[string]types.NetworkStats{"nat": {RxBytes: 5, TxBytes: 7}}
+
+	stats := ParseContainerStats(windowsStats, OSTypeWindows)
+	if stats.CPUPercent != 50 {
+		t.Errorf("expected a Windows CPU usage of 50%%, got %f", stats.CPUPercent)
+	}
+	if stats.MemoryUsage != 1024 || stats.MemoryLimit != 0 {
+		t.Errorf("expected the private working set to be used as memory usage, got %d/%d", stats.MemoryUsage, stats.MemoryLimit)
+	}
+	if stats.BlockRead != 10 || stats.BlockWrite != 20 || stats.NetworkRx != 5 || stats.NetworkTx != 7 {
+		t.Errorf("unexpected Windows I/O statistics: %+v", stats)
+	}
+
+	linuxStats := &types.StatsJSON{}
+	linuxStats.CPUStats.CPUUsage.TotalUsage = 300
+	linuxStats.PreCPUStats.CPUUsage.TotalUsage = 100
+	linuxStats.CPUStats.SystemUsage = 2000
+	linuxStats.PreCPUStats.SystemUsage = 1000
+	linuxStats.CPUStats.OnlineCPUs = 4
+	linuxStats.MemoryStats.Usage = 1000
+	linuxStats.MemoryStats.Limit = 2000
+	linuxStats.MemoryStats.Stats = map[string]uint64{"inactive_file": 200}
+	linuxStats.BlkioStats.IoServiceBytesRecursive = []types.BlkioStatEntry{{Op: "Read", Value: 3}, {Op: "write", Value: 4}}
+
+	stats = ParseContainerStats(linuxStats, OSTypeLinux)
+	if stats.CPUPercent != 80 {
+		t.Errorf("expected a Linux CPU usage of 80%%, got %f", stats.CPUPercent)
+	}
+	if stats.MemoryUsage != 800 || stats.MemoryPercent != 40 {
+		t.Errorf("expected the inactive page cache to be excluded from the memory usage, got %d (%f%%)", stats.MemoryUsage, stats.MemoryPercent)
+	}
+	if stats.BlockRead != 3 || stats.BlockWrite != 4 {
+		t.Errorf("unexpected Linux block I/O statistics: %+v", stats)
+	}
+}
+
+func TestContainerCPULimit(t *testing.T) {
+	tests := []struct {
+		resources container.Resources
+		expected  float64
+	}{
+		{container.Resources{NanoCPUs: 1500000000}, 1.5},
+		{container.Resources{CPUPeriod: 100000, CPUQuota: 50000}, 0.5},
+		{container.Resources{CPUCount: 2}, 2},
+		{container.Resources{CPUPercent: 25}, 1},
+		{container.Resources{}, 0},
+	}
+
+	for _, test := range tests {
+		limit := ContainerCPULimit(test.resources, 4)
+		if limit != test.expected {
+			t.Errorf("ContainerCPULimit(%+v): expected %f, got %f", test.resources, test.expected, limit)
+		}
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"runtime"
 
 	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/internal/stackfile"
 )
 
 // SwarmStackManager represents a service for managing stacks.
@@ -75,8 +76,13 @@ func (manager *SwarmStackManager) Deploy(stack *portainer.Stack, prune bool, end
 		args = append(args, "stack", "deploy", "--with-registry-auth", "--compose-file", stackFilePath, stack.Name)
 	}
 
+	stackEnv, err := stackfile.Environment(stack)
+	if err != nil {
+		return err
+	}
+
 	env := make([]string, 0)
-	for _, envvar := range stack.Env {
+	for _, envvar := range stackEnv {
 		env = append(env, envvar.Name+"="+envvar.Value)
 	}
 
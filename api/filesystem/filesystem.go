@@ -31,12 +31,18 @@ const (
 	ComposeStorePath = "compose"
 	// ComposeFileDefaultName represents the default name of a compose file.
 	ComposeFileDefaultName = "docker-compose.yml"
+	// StackEnvFileStorePath represents the subfolder where the variable files of the stacks are stored in the file store folder.
+	StackEnvFileStorePath = "stack_env"
 	// EdgeStackStorePath represents the subfolder where edge stack files are stored in the file store folder.
 	EdgeStackStorePath = "edge_stacks"
 	// PrivateKeyFile represents the name on disk of the file containing the private key.
 	PrivateKeyFile = "portainer.key"
 	// PublicKeyFile represents the name on disk of the file containing the public key.
 	PublicKeyFile = "portainer.pub"
+	// InitTokenFile represents the name on disk of the file containing the token used to unlock the initialization of a locked instance.
+	InitTokenFile = "init.token"
+	// InitLockFile represents the name on disk of the file marking the initialization of the instance as locked.
+	InitLockFile = "init.lock"
 	// BinaryStorePath represents the subfolder where binaries are stored in the file store folder.
 	BinaryStorePath = "bin"
 	// EdgeJobStorePath represents the subfolder where schedule files are stored.
@@ -48,6 +54,14 @@ const (
 	CustomTemplateStorePath = "custom_templates"
 	// TempPath represent the subfolder where temporary files are saved
 	TempPath = "tmp"
+	// KubeconfigStorePath represents the subfolder where the kubeconfig files of Kubernetes endpoints are stored.
+	KubeconfigStorePath = "kubeconfig"
+	// KubeconfigFileName represents the name on disk of a kubeconfig file.
+	KubeconfigFileName = "config"
+	// SSHKeyStorePath represents the subfolder where the SSH private keys of Docker endpoints are stored.
+	SSHKeyStorePath = "ssh"
+	// SSHKeyFileName represents the name on disk of an SSH private key.
+	SSHKeyFileName = "id_key"
 )
 
 // ErrUndefinedTLSFileType represents an error returned on undefined TLS file type
@@ -126,6 +140,43 @@ func (service *Service) StoreStackFileFromBytes(stackIdentifier, fileName string
 	return path.Join(service.fileStorePath, stackStorePath), nil
 }
 
+// StoreStackEnvFileFromBytes creates a subfolder in the StackEnvFileStorePath and stores a new variable file from bytes.
+// The variable files are kept outside of the project folder so that they cannot be overwritten by a Git repository.
+// It returns the path to the newly created file.
+func (service *Service) StoreStackEnvFileFromBytes(stackIdentifier, name string, data []byte) (string, error) {
+	storePath := path.Join(StackEnvFileStorePath, stackIdentifier)
+	err := service.createDirectoryInStore(storePath)
+	if err != nil {
+		return "", err
+	}
+
+	envFilePath := path.Join(storePath, name+".env")
+	r := bytes.NewReader(data)
+
+	err = service.createFileInStore(envFilePath, r)
+	if err != nil {
+		return "", err
+	}
+
+	return path.Join(service.fileStorePath, envFilePath), nil
+}
+
+// DeleteStackEnvFile deletes a variable file of a stack.
+func (service *Service) DeleteStackEnvFile(stackIdentifier, name string) error {
+	envFilePath := path.Join(service.fileStorePath, StackEnvFileStorePath, stackIdentifier, name+".env")
+	err := os.Remove(envFilePath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// DeleteStackEnvFiles deletes the folder containing the variable files of a stack.
+func (service *Service) DeleteStackEnvFiles(stackIdentifier string) error {
+	storePath := path.Join(service.fileStorePath, StackEnvFileStorePath, stackIdentifier)
+	return os.RemoveAll(storePath)
+}
+
 // GetEdgeStackProjectPath returns the absolute path on the FS for a edge stack based
 // on its identifier.
 func (service *Service) GetEdgeStackProjectPath(edgeStackIdentifier string) string {
@@ -173,6 +224,58 @@ func (service *Service) StoreRegistryManagementFileFromBytes(folder, fileName st
 	return path.Join(service.fileStorePath, file), nil
 }
 
+// StoreKubeconfigFileFromBytes creates a subfolder in the KubeconfigStorePath and stores a new kubeconfig file from bytes.
+// It returns the path to the newly created file.
+func (service *Service) StoreKubeconfigFileFromBytes(folder string, data []byte) (string, error) {
+	storePath := path.Join(KubeconfigStorePath, folder)
+	err := service.createDirectoryInStore(storePath)
+	if err != nil {
+		return "", err
+	}
+
+	kubeconfigFilePath := path.Join(storePath, KubeconfigFileName)
+	r := bytes.NewReader(data)
+
+	err = service.createFileInStore(kubeconfigFilePath, r)
+	if err != nil {
+		return "", err
+	}
+
+	return path.Join(service.fileStorePath, kubeconfigFilePath), nil
+}
+
+// DeleteKubeconfigFile deletes the folder containing the kubeconfig file of an endpoint.
+func (service *Service) DeleteKubeconfigFile(folder string) error {
+	storePath := path.Join(service.fileStorePath, KubeconfigStorePath, folder)
+	return os.RemoveAll(storePath)
+}
+
+// StoreSSHKeyFileFromBytes creates a subfolder in the SSHKeyStorePath and stores a new SSH private key from bytes.
+// It returns the path to the newly created file.
+func (service *Service) StoreSSHKeyFileFromBytes(folder string, data []byte) (string, error) {
+	storePath := path.Join(SSHKeyStorePath, folder)
+	err := service.createDirectoryInStore(storePath)
+	if err != nil {
+		return "", err
+	}
+
+	keyFilePath := path.Join(storePath, SSHKeyFileName)
+	r := bytes.NewReader(data)
+
+	err = service.createFileInStore(keyFilePath, r)
+	if err != nil {
+		return "", err
+	}
+
+	return path.Join(service.fileStorePath, keyFilePath), nil
+}
+
+// DeleteSSHKeyFile deletes the folder containing the SSH private key of an endpoint.
+func (service *Service) DeleteSSHKeyFile(folder string) error {
+	storePath := path.Join(service.fileStorePath, SSHKeyStorePath, folder)
+	return os.RemoveAll(storePath)
+}
+
 // StoreTLSFileFromBytes creates a folder in the TLSStorePath and stores a new file from bytes.
 // It returns the path to the newly created file.
 func (service *Service) StoreTLSFileFromBytes(folder string, fileType portainer.TLSFileType, data []byte) (string, error) {
@@ -326,6 +429,57 @@ func (service *Service) StoreKeyPair(private, public []byte, privatePEMHeader, p
 	return nil
 }
 
+// StoreInitToken stores the token used to unlock the initialization of a locked instance, replacing any previous token.
+func (service *Service) StoreInitToken(token string) error {
+	tokenPath := path.Join(service.dataStorePath, InitTokenFile)
+	return ioutil.WriteFile(tokenPath, []byte(token), 0600)
+}
+
+// GetInitToken returns the token used to unlock the initialization of a locked instance.
+// An empty token is returned when no token was issued.
+func (service *Service) GetInitToken() (string, error) {
+	tokenPath := path.Join(service.dataStorePath, InitTokenFile)
+	token, err := ioutil.ReadFile(tokenPath)
+	if os.IsNotExist(err) {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+
+	return string(token), nil
+}
+
+// DeleteInitToken removes the token used to unlock the initialization of a locked instance.
+func (service *Service) DeleteInitToken() error {
+	tokenPath := path.Join(service.dataStorePath, InitTokenFile)
+	err := os.Remove(tokenPath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// StoreInitLock marks the initialization of the instance as locked so that the lock survives a restart.
+func (service *Service) StoreInitLock() error {
+	lockPath := path.Join(service.dataStorePath, InitLockFile)
+	return ioutil.WriteFile(lockPath, []byte{}, 0600)
+}
+
+// InitLocked returns true when the initialization of the instance was locked.
+func (service *Service) InitLocked() (bool, error) {
+	return service.FileExists(path.Join(service.dataStorePath, InitLockFile))
+}
+
+// DeleteInitLock removes the lock on the initialization of the instance.
+func (service *Service) DeleteInitLock() error {
+	lockPath := path.Join(service.dataStorePath, InitLockFile)
+	err := os.Remove(lockPath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
 // LoadKeyPair retrieve the content of both key files on disk.
 func (service *Service) LoadKeyPair() ([]byte, []byte, error) {
 	privateKey, err := service.getContentFromPEMFile(PrivateKeyFile)
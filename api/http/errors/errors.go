@@ -9,4 +9,6 @@ var (
 	ErrUnauthorized = errors.New("Unauthorized")
 	// ErrResourceAccessDenied Access denied to resource error
 	ErrResourceAccessDenied = errors.New("Access denied to resource")
+	// ErrEdgeEndpointPendingApproval Edge endpoint waiting for approval error
+	ErrEdgeEndpointPendingApproval = errors.New("The Edge endpoint is waiting for approval")
 )
@@ -5,6 +5,7 @@ import (
 	"log"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/asaskevich/govalidator"
 	httperror "github.com/portainer/libhttp/error"
@@ -13,6 +14,8 @@ import (
 	"github.com/portainer/portainer/api"
 	bolterrors "github.com/portainer/portainer/api/bolt/errors"
 	httperrors "github.com/portainer/portainer/api/http/errors"
+	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/passwordpolicy"
 )
 
 type authenticatePayload struct {
@@ -22,6 +25,8 @@ type authenticatePayload struct {
 
 type authenticateResponse struct {
 	JWT string `json:"jwt"`
+	// PasswordChangeRequired is set when the user must change its password before using the API
+	PasswordChangeRequired bool `json:"passwordChangeRequired,omitempty"`
 }
 
 func (payload *authenticatePayload) Validate(r *http.Request) error {
@@ -56,21 +61,27 @@ func (handler *Handler) authenticate(w http.ResponseWriter, r *http.Request) *ht
 	}
 
 	if settings.AuthenticationMethod == portainer.AuthenticationLDAP {
-		if u == nil && settings.LDAPSettings.AutoCreateUsers {
-			return handler.authenticateLDAPAndCreateUser(w, payload.Username, payload.Password, &settings.LDAPSettings)
-		} else if u == nil && !settings.LDAPSettings.AutoCreateUsers {
-			return &httperror.HandlerError{http.StatusUnprocessableEntity, "Invalid credentials", httperrors.ErrUnauthorized}
+		if u == nil {
+			shadowUser, err := handler.shadowUser(payload.Username, settings)
+			if err != nil {
+				return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve a shadow user with the specified username from the database", err}
+			}
+
+			if shadowUser == nil && !settings.LDAPSettings.AutoCreateUsers {
+				return &httperror.HandlerError{http.StatusUnprocessableEntity, "Invalid credentials", httperrors.ErrUnauthorized}
+			}
+			return handler.authenticateLDAPAndCreateUser(w, r, payload.Username, payload.Password, &settings.LDAPSettings, shadowUser)
 		}
-		return handler.authenticateLDAP(w, u, payload.Password, &settings.LDAPSettings)
+		return handler.authenticateLDAP(w, r, u, payload.Password, &settings.LDAPSettings)
 	}
 
-	return handler.authenticateInternal(w, u, payload.Password)
+	return handler.authenticateInternal(w, r, u, payload.Password)
 }
 
-func (handler *Handler) authenticateLDAP(w http.ResponseWriter, user *portainer.User, password string, ldapSettings *portainer.LDAPSettings) *httperror.HandlerError {
+func (handler *Handler) authenticateLDAP(w http.ResponseWriter, r *http.Request, user *portainer.User, password string, ldapSettings *portainer.LDAPSettings) *httperror.HandlerError {
 	err := handler.LDAPService.AuthenticateUser(user.Username, password, ldapSettings)
 	if err != nil {
-		return handler.authenticateInternal(w, user, password)
+		return handler.authenticateInternal(w, r, user, password)
 	}
 
 	err = handler.addUserIntoTeams(user, ldapSettings)
@@ -78,19 +89,50 @@ func (handler *Handler) authenticateLDAP(w http.ResponseWriter, user *portainer.
 		log.Printf("Warning: unable to automatically add user into teams: %s\n", err.Error())
 	}
 
-	return handler.writeToken(w, user)
+	return handler.writeToken(w, r, user, portainer.AuthenticationLDAP)
 }
 
-func (handler *Handler) authenticateInternal(w http.ResponseWriter, user *portainer.User, password string) *httperror.HandlerError {
+func (handler *Handler) authenticateInternal(w http.ResponseWriter, r *http.Request, user *portainer.User, password string) *httperror.HandlerError {
 	err := handler.CryptoService.CompareHashAndData(user.Password, password)
 	if err != nil {
+		handler.UserActivityLogger.RecordLoginFailure(user, security.StripAddrPort(r.RemoteAddr), portainer.AuthenticationInternal)
 		return &httperror.HandlerError{http.StatusUnprocessableEntity, "Invalid credentials", httperrors.ErrUnauthorized}
 	}
 
-	return handler.writeToken(w, user)
+	handlerErr := handler.checkPasswordExpiry(user)
+	if handlerErr != nil {
+		return handlerErr
+	}
+
+	return handler.writeToken(w, r, user, portainer.AuthenticationInternal)
 }
 
-func (handler *Handler) authenticateLDAPAndCreateUser(w http.ResponseWriter, username, password string, ldapSettings *portainer.LDAPSettings) *httperror.HandlerError {
+// checkPasswordExpiry requires the user to change its password when it is older than the maximum age
+// of the password policy. The passwords set before the introduction of the policy are considered set on the first login.
+func (handler *Handler) checkPasswordExpiry(user *portainer.User) *httperror.HandlerError {
+	settings, err := handler.DataStore.Settings().Settings()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve settings from the database", err}
+	}
+
+	switch {
+	case user.PasswordChangedDate == 0:
+		user.PasswordChangedDate = time.Now().Unix()
+	case !user.PasswordChangeRequired && passwordpolicy.IsExpired(user, &settings.PasswordPolicy):
+		user.PasswordChangeRequired = true
+	default:
+		return nil
+	}
+
+	err = handler.DataStore.User().UpdateUser(user.ID, user)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist user changes inside the database", err}
+	}
+
+	return nil
+}
+
+func (handler *Handler) authenticateLDAPAndCreateUser(w http.ResponseWriter, r *http.Request, username, password string, ldapSettings *portainer.LDAPSettings, shadowUser *portainer.ShadowUser) *httperror.HandlerError {
 	err := handler.LDAPService.AuthenticateUser(username, password, ldapSettings)
 	if err != nil {
 		return &httperror.HandlerError{http.StatusUnprocessableEntity, "Invalid credentials", err}
@@ -101,7 +143,11 @@ func (handler *Handler) authenticateLDAPAndCreateUser(w http.ResponseWriter, use
 		Role:     portainer.StandardUserRole,
 	}
 
-	err = handler.DataStore.User().CreateUser(user)
+	if shadowUser != nil {
+		user, err = handler.createUserFromShadowUser(username, shadowUser)
+	} else {
+		err = handler.DataStore.User().CreateUser(user)
+	}
 	if err != nil {
 		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist user inside the database", err}
 	}
@@ -111,26 +157,32 @@ func (handler *Handler) authenticateLDAPAndCreateUser(w http.ResponseWriter, use
 		log.Printf("Warning: unable to automatically add user into teams: %s\n", err.Error())
 	}
 
-	return handler.writeToken(w, user)
+	return handler.writeToken(w, r, user, portainer.AuthenticationLDAP)
 }
 
-func (handler *Handler) writeToken(w http.ResponseWriter, user *portainer.User) *httperror.HandlerError {
+// writeToken records the login of the user and writes a new token for the user
+func (handler *Handler) writeToken(w http.ResponseWriter, r *http.Request, user *portainer.User, method portainer.AuthenticationMethod) *httperror.HandlerError {
+	err := handler.UserActivityLogger.RecordLogin(user, security.StripAddrPort(r.RemoteAddr), method)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to record the login of the user", err}
+	}
+
 	tokenData := &portainer.TokenData{
 		ID:       user.ID,
 		Username: user.Username,
 		Role:     user.Role,
 	}
 
-	return handler.persistAndWriteToken(w, tokenData)
+	return handler.persistAndWriteToken(w, tokenData, user.PasswordChangeRequired)
 }
 
-func (handler *Handler) persistAndWriteToken(w http.ResponseWriter, tokenData *portainer.TokenData) *httperror.HandlerError {
+func (handler *Handler) persistAndWriteToken(w http.ResponseWriter, tokenData *portainer.TokenData, passwordChangeRequired bool) *httperror.HandlerError {
 	token, err := handler.JWTService.GenerateToken(tokenData)
 	if err != nil {
 		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to generate JWT token", err}
 	}
 
-	return response.JSON(w, &authenticateResponse{JWT: token})
+	return response.JSON(w, &authenticateResponse{JWT: token, PasswordChangeRequired: passwordChangeRequired})
 }
 
 func (handler *Handler) addUserIntoTeams(user *portainer.User, settings *portainer.LDAPSettings) error {
@@ -68,10 +68,25 @@ func (handler *Handler) validateOAuth(w http.ResponseWriter, r *http.Request) *h
 		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve a user with the specified username from the database", err}
 	}
 
-	if user == nil && !settings.OAuthSettings.OAuthAutoCreateUsers {
+	var shadowUser *portainer.ShadowUser
+	if user == nil {
+		shadowUser, err = handler.shadowUser(username, settings)
+		if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve a shadow user with the specified username from the database", err}
+		}
+	}
+
+	if user == nil && shadowUser == nil && !settings.OAuthSettings.OAuthAutoCreateUsers {
 		return &httperror.HandlerError{http.StatusForbidden, "Account not created beforehand in Portainer and automatic user provisioning not enabled", httperrors.ErrUnauthorized}
 	}
 
+	if user == nil && shadowUser != nil {
+		user, err = handler.createUserFromShadowUser(username, shadowUser)
+		if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist user inside the database", err}
+		}
+	}
+
 	if user == nil {
 		user = &portainer.User{
 			Username: username,
@@ -98,5 +113,5 @@ func (handler *Handler) validateOAuth(w http.ResponseWriter, r *http.Request) *h
 
 	}
 
-	return handler.writeToken(w, user)
+	return handler.writeToken(w, r, user, portainer.AuthenticationOAuth)
 }
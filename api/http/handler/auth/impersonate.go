@@ -0,0 +1,95 @@
+package auth
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+	httperrors "github.com/portainer/portainer/api/http/errors"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+type impersonatePayload struct {
+	UserID portainer.UserID
+}
+
+type impersonateResponse struct {
+	JWT                  string           `json:"jwt"`
+	ImpersonatedUserID   portainer.UserID `json:"impersonatedUserId"`
+	ImpersonatedUsername string           `json:"impersonatedUsername"`
+}
+
+func (payload *impersonatePayload) Validate(r *http.Request) error {
+	if payload.UserID == 0 {
+		return errors.New("Invalid user identifier")
+	}
+	return nil
+}
+
+// POST request on /api/auth/impersonate
+//
+// Issues a token acting as another user, so that the administrators can reproduce the permission problems
+// reported by the users without their password. The token is marked with the identity of the administrator,
+// it expires after an hour at most and all the requests made with it are audited.
+func (handler *Handler) impersonate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	var payload impersonatePayload
+	err := request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	tokenData, err := security.RetrieveTokenData(r)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve user details from authentication token", err}
+	}
+
+	if security.IsImpersonation(tokenData) {
+		return &httperror.HandlerError{http.StatusForbidden, "Cannot impersonate a user with an impersonation token", httperrors.ErrResourceAccessDenied}
+	}
+
+	if payload.UserID == tokenData.ID {
+		return &httperror.HandlerError{http.StatusBadRequest, "Cannot impersonate your own user account", errors.New("Cannot impersonate your own user account")}
+	}
+
+	securityContext, err := security.RetrieveRestrictedRequestContext(r)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve info from request context", err}
+	}
+
+	user, err := handler.DataStore.User().User(payload.UserID)
+	if err == bolterrors.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find a user with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a user with the specified identifier inside the database", err}
+	}
+
+	if !security.AuthorizedOrganizationAccess(user.OrganizationID, securityContext.OrganizationID, securityContext.IsAdmin) {
+		return &httperror.HandlerError{http.StatusForbidden, "Permission denied to impersonate a user of another organization", httperrors.ErrResourceAccessDenied}
+	}
+
+	if user.Role == portainer.AdministratorRole {
+		return &httperror.HandlerError{http.StatusForbidden, "Administrators cannot be impersonated", httperrors.ErrResourceAccessDenied}
+	}
+
+	impersonationTokenData := &portainer.TokenData{
+		ID:                   user.ID,
+		Username:             user.Username,
+		Role:                 user.Role,
+		ImpersonatorID:       tokenData.ID,
+		ImpersonatorUsername: tokenData.Username,
+	}
+
+	token, err := handler.JWTService.GenerateToken(impersonationTokenData)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to generate JWT token", err}
+	}
+
+	log.Printf("[INFO] [http,auth,impersonation] [message: impersonation started] [impersonator: %s] [impersonator_id: %d] [user: %s] [user_id: %d]", tokenData.Username, tokenData.ID, user.Username, user.ID)
+
+	return response.JSON(w, &impersonateResponse{JWT: token, ImpersonatedUserID: user.ID, ImpersonatedUsername: user.Username})
+}
@@ -0,0 +1,103 @@
+package auth
+
+import (
+	"log"
+
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+)
+
+// shadowUser returns the shadow user associated to the specified username when the user directory
+// read-through mode is enabled, nil is returned when there is no such shadow user.
+func (handler *Handler) shadowUser(username string, settings *portainer.Settings) (*portainer.ShadowUser, error) {
+	if !settings.EnableUserDirectoryReadThrough {
+		return nil, nil
+	}
+
+	shadowUser, err := handler.DataStore.ShadowUser().ShadowUserByUsername(username)
+	if err == bolterrors.ErrObjectNotFound {
+		return nil, nil
+	}
+
+	return shadowUser, err
+}
+
+// createUserFromShadowUser creates the user authenticated for the first time through LDAP/OAuth using the
+// role of its shadow user, grants it the accesses and team memberships of the shadow user and removes the shadow user.
+// The grants referencing endpoints, endpoint groups or teams removed since the creation of the shadow user are ignored.
+func (handler *Handler) createUserFromShadowUser(username string, shadowUser *portainer.ShadowUser) (*portainer.User, error) {
+	user := &portainer.User{
+		Username: username,
+		Role:     shadowUser.Role,
+	}
+
+	err := handler.DataStore.User().CreateUser(user)
+	if err != nil {
+		return nil, err
+	}
+
+	for endpointID, policy := range shadowUser.EndpointAccesses {
+		endpoint, err := handler.DataStore.Endpoint().Endpoint(endpointID)
+		if err == bolterrors.ErrObjectNotFound {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		if endpoint.UserAccessPolicies == nil {
+			endpoint.UserAccessPolicies = portainer.UserAccessPolicies{}
+		}
+		endpoint.UserAccessPolicies[user.ID] = policy
+
+		err = handler.DataStore.Endpoint().UpdateEndpoint(endpoint.ID, endpoint)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for endpointGroupID, policy := range shadowUser.EndpointGroupAccesses {
+		endpointGroup, err := handler.DataStore.EndpointGroup().EndpointGroup(endpointGroupID)
+		if err == bolterrors.ErrObjectNotFound {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		if endpointGroup.UserAccessPolicies == nil {
+			endpointGroup.UserAccessPolicies = portainer.UserAccessPolicies{}
+		}
+		endpointGroup.UserAccessPolicies[user.ID] = policy
+
+		err = handler.DataStore.EndpointGroup().UpdateEndpointGroup(endpointGroup.ID, endpointGroup)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, shadowMembership := range shadowUser.TeamMemberships {
+		_, err := handler.DataStore.Team().Team(shadowMembership.TeamID)
+		if err == bolterrors.ErrObjectNotFound {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		membership := &portainer.TeamMembership{
+			UserID: user.ID,
+			TeamID: shadowMembership.TeamID,
+			Role:   shadowMembership.Role,
+		}
+
+		err = handler.DataStore.TeamMembership().CreateTeamMembership(membership)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = handler.DataStore.ShadowUser().DeleteShadowUser(shadowUser.ID)
+	if err != nil {
+		log.Printf("[WARN] [http,auth] [message: unable to remove shadow user] [username: %s] [error: %s]", username, err)
+	}
+
+	return user, nil
+}
@@ -0,0 +1,32 @@
+package autohealrules
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+)
+
+// GET request on /api/autoheal_actions?ruleId=<ruleId>&endpointId=<endpointId>&limit=<limit>
+//
+// Returns the history of the restarts executed by the auto-heal rules, the most recent action first.
+// The history can be filtered by rule and by endpoint, all the actions are returned when no limit is specified.
+func (handler *Handler) autoHealActionList(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	ruleID, _ := request.RetrieveNumericQueryParameter(r, "ruleId", true)
+	endpointID, _ := request.RetrieveNumericQueryParameter(r, "endpointId", true)
+	limit, _ := request.RetrieveNumericQueryParameter(r, "limit", true)
+
+	actions, err := handler.DataStore.AutoHealAction().AutoHealActions(func(action *portainer.AutoHealAction) bool {
+		if ruleID != 0 && action.RuleID != portainer.AutoHealRuleID(ruleID) {
+			return false
+		}
+		return endpointID == 0 || action.EndpointID == portainer.EndpointID(endpointID)
+	}, limit)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve auto-heal actions from the database", err}
+	}
+
+	return response.JSON(w, actions)
+}
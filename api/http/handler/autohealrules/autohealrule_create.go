@@ -0,0 +1,84 @@
+package autohealrules
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/asaskevich/govalidator"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+	"github.com/portainer/portainer/api/internal/autoheal"
+)
+
+var errUnsupportedEndpoint = errors.New("Auto-heal rules are only supported on Docker endpoints")
+
+type autoHealRuleCreatePayload struct {
+	Name          string
+	EndpointID    int
+	StackName     string
+	FailingStreak int
+	Cooldown      int
+	ExcludeLabels []string
+	Notify        bool
+	Enabled       bool
+}
+
+func (payload *autoHealRuleCreatePayload) Validate(r *http.Request) error {
+	if govalidator.IsNull(payload.Name) {
+		return errors.New("Invalid auto-heal rule name")
+	}
+	if payload.EndpointID == 0 {
+		return errors.New("Invalid endpoint identifier")
+	}
+	return nil
+}
+
+// POST request on /api/autoheal_rules
+func (handler *Handler) autoHealRuleCreate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	var payload autoHealRuleCreatePayload
+	err := request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	endpoint, err := handler.DataStore.Endpoint().Endpoint(portainer.EndpointID(payload.EndpointID))
+	if err == bolterrors.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find an endpoint with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find an endpoint with the specified identifier inside the database", err}
+	}
+
+	if !autoheal.SupportAutoHeal(endpoint) {
+		return &httperror.HandlerError{http.StatusBadRequest, "Auto-heal rules are only supported on Docker endpoints", errUnsupportedEndpoint}
+	}
+
+	rule := &portainer.AutoHealRule{
+		Name:          payload.Name,
+		EndpointID:    endpoint.ID,
+		StackName:     payload.StackName,
+		FailingStreak: payload.FailingStreak,
+		Cooldown:      payload.Cooldown,
+		ExcludeLabels: payload.ExcludeLabels,
+		Notify:        payload.Notify,
+		Enabled:       payload.Enabled,
+	}
+
+	if rule.ExcludeLabels == nil {
+		rule.ExcludeLabels = []string{}
+	}
+
+	err = autoheal.ValidateRule(rule)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	err = handler.DataStore.AutoHealRule().CreateAutoHealRule(rule)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist the auto-heal rule inside the database", err}
+	}
+
+	return response.JSON(w, rule)
+}
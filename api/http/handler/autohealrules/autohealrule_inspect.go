@@ -0,0 +1,28 @@
+package autohealrules
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+)
+
+// GET request on /api/autoheal_rules/:id
+func (handler *Handler) autoHealRuleInspect(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	ruleID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid auto-heal rule identifier route variable", err}
+	}
+
+	rule, err := handler.DataStore.AutoHealRule().AutoHealRule(portainer.AutoHealRuleID(ruleID))
+	if err == bolterrors.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find a auto-heal rule with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a auto-heal rule with the specified identifier inside the database", err}
+	}
+
+	return response.JSON(w, rule)
+}
@@ -0,0 +1,18 @@
+package autohealrules
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/response"
+)
+
+// GET request on /api/autoheal_rules
+func (handler *Handler) autoHealRuleList(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	rules, err := handler.DataStore.AutoHealRule().AutoHealRules()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve auto-heal rules from the database", err}
+	}
+
+	return response.JSON(w, rules)
+}
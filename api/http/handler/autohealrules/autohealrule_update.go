@@ -0,0 +1,91 @@
+package autohealrules
+
+import (
+	"errors"
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+	"github.com/portainer/portainer/api/internal/autoheal"
+)
+
+type autoHealRuleUpdatePayload struct {
+	Name          *string
+	StackName     *string
+	FailingStreak *int
+	Cooldown      *int
+	ExcludeLabels []string
+	Notify        *bool
+	Enabled       *bool
+}
+
+func (payload *autoHealRuleUpdatePayload) Validate(r *http.Request) error {
+	if payload.Name != nil && *payload.Name == "" {
+		return errors.New("Invalid auto-heal rule name")
+	}
+	return nil
+}
+
+// PUT request on /api/autoheal_rules/:id
+func (handler *Handler) autoHealRuleUpdate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	ruleID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid auto-heal rule identifier route variable", err}
+	}
+
+	var payload autoHealRuleUpdatePayload
+	err = request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	rule, err := handler.DataStore.AutoHealRule().AutoHealRule(portainer.AutoHealRuleID(ruleID))
+	if err == bolterrors.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find an auto-heal rule with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find an auto-heal rule with the specified identifier inside the database", err}
+	}
+
+	if payload.Name != nil {
+		rule.Name = *payload.Name
+	}
+
+	if payload.StackName != nil {
+		rule.StackName = *payload.StackName
+	}
+
+	if payload.FailingStreak != nil {
+		rule.FailingStreak = *payload.FailingStreak
+	}
+
+	if payload.Cooldown != nil {
+		rule.Cooldown = *payload.Cooldown
+	}
+
+	if payload.ExcludeLabels != nil {
+		rule.ExcludeLabels = payload.ExcludeLabels
+	}
+
+	if payload.Notify != nil {
+		rule.Notify = *payload.Notify
+	}
+
+	if payload.Enabled != nil {
+		rule.Enabled = *payload.Enabled
+	}
+
+	err = autoheal.ValidateRule(rule)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	err = handler.DataStore.AutoHealRule().UpdateAutoHealRule(rule.ID, rule)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist the auto-heal rule changes inside the database", err}
+	}
+
+	return response.JSON(w, rule)
+}
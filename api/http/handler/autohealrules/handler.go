@@ -0,0 +1,36 @@
+package autohealrules
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+// Handler is the HTTP handler used to handle auto-heal rule operations.
+type Handler struct {
+	*mux.Router
+	DataStore portainer.DataStore
+}
+
+// NewHandler creates a handler to manage auto-heal rule operations.
+func NewHandler(bouncer *security.RequestBouncer) *Handler {
+	h := &Handler{
+		Router: mux.NewRouter(),
+	}
+	h.Handle("/autoheal_rules",
+		bouncer.InstanceAdminAccess(httperror.LoggerHandler(h.autoHealRuleCreate))).Methods(http.MethodPost)
+	h.Handle("/autoheal_rules",
+		bouncer.InstanceAdminAccess(httperror.LoggerHandler(h.autoHealRuleList))).Methods(http.MethodGet)
+	h.Handle("/autoheal_rules/{id}",
+		bouncer.InstanceAdminAccess(httperror.LoggerHandler(h.autoHealRuleInspect))).Methods(http.MethodGet)
+	h.Handle("/autoheal_rules/{id}",
+		bouncer.InstanceAdminAccess(httperror.LoggerHandler(h.autoHealRuleUpdate))).Methods(http.MethodPut)
+	h.Handle("/autoheal_rules/{id}",
+		bouncer.InstanceAdminAccess(httperror.LoggerHandler(h.autoHealRuleDelete))).Methods(http.MethodDelete)
+	h.Handle("/autoheal_actions",
+		bouncer.InstanceAdminAccess(httperror.LoggerHandler(h.autoHealActionList))).Methods(http.MethodGet)
+	return h
+}
@@ -0,0 +1,304 @@
+package azure
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	azureapi "github.com/portainer/portainer/api/azure"
+)
+
+const (
+	defaultContainerCPU    = 1
+	defaultContainerMemory = 1.5
+)
+
+var resourceNamePattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+type (
+	containerGroupCreatePayload struct {
+		Name     string
+		Location string
+		// OSType is one of Linux or Windows, defaults to Linux
+		OSType string
+		// RestartPolicy is one of Always, OnFailure or Never, defaults to Always
+		RestartPolicy string
+		// PublicIP exposes the ports of the containers on a public IP address
+		PublicIP     bool
+		DNSNameLabel string
+		Containers   []containerPayload
+		Volumes      []volumePayload
+	}
+
+	containerPayload struct {
+		Name    string
+		Image   string
+		Command []string
+		// CPU is the number of cores requested by the container, defaults to 1
+		CPU float64
+		// Memory is the amount of memory in GB requested by the container, defaults to 1.5
+		Memory       float64
+		Ports        []portPayload
+		Env          []envPayload
+		VolumeMounts []volumeMountPayload
+	}
+
+	portPayload struct {
+		Port int
+		// Protocol is one of TCP or UDP, defaults to TCP
+		Protocol string
+	}
+
+	envPayload struct {
+		Name  string
+		Value string
+		// Secure values are not returned when the container group is inspected
+		Secure bool
+	}
+
+	volumeMountPayload struct {
+		Volume    string
+		MountPath string
+		ReadOnly  bool
+	}
+
+	volumePayload struct {
+		Name               string
+		ShareName          string
+		StorageAccountName string
+		StorageAccountKey  string
+		ReadOnly           bool
+	}
+)
+
+func (payload *containerGroupCreatePayload) Validate(r *http.Request) error {
+	if !resourceNamePattern.MatchString(payload.Name) || len(payload.Name) > 63 {
+		return errors.New("Invalid container group name. Name must contain lowercase letters, numbers and hyphens and must not exceed 63 characters")
+	}
+
+	if payload.Location == "" {
+		return errors.New("Invalid location")
+	}
+
+	if payload.OSType == "" {
+		payload.OSType = "Linux"
+	}
+	if payload.OSType != "Linux" && payload.OSType != "Windows" {
+		return errors.New("Invalid OS type. Value must be one of: Linux or Windows")
+	}
+
+	if payload.RestartPolicy == "" {
+		payload.RestartPolicy = "Always"
+	}
+	if payload.RestartPolicy != "Always" && payload.RestartPolicy != "OnFailure" && payload.RestartPolicy != "Never" {
+		return errors.New("Invalid restart policy. Value must be one of: Always, OnFailure or Never")
+	}
+
+	if payload.DNSNameLabel != "" && !payload.PublicIP {
+		return errors.New("A DNS name label can only be specified for a container group with a public IP address")
+	}
+
+	if payload.OSType == "Windows" && len(payload.Volumes) > 0 {
+		return errors.New("Azure File volumes are not supported on Windows container groups")
+	}
+
+	volumes := make(map[string]bool)
+	for _, volume := range payload.Volumes {
+		if !resourceNamePattern.MatchString(volume.Name) {
+			return errors.New("Invalid volume name. Name must contain lowercase letters, numbers and hyphens")
+		}
+		if volumes[volume.Name] {
+			return fmt.Errorf("Duplicate volume name: %s", volume.Name)
+		}
+		if volume.ShareName == "" || volume.StorageAccountName == "" || volume.StorageAccountKey == "" {
+			return fmt.Errorf("Invalid volume %s. The share name, storage account name and storage account key are required", volume.Name)
+		}
+		volumes[volume.Name] = true
+	}
+
+	if len(payload.Containers) == 0 {
+		return errors.New("A container group must contain at least one container")
+	}
+
+	containers := make(map[string]bool)
+	ports := make(map[int]bool)
+	for idx := range payload.Containers {
+		container := &payload.Containers[idx]
+
+		if !resourceNamePattern.MatchString(container.Name) || len(container.Name) > 63 {
+			return errors.New("Invalid container name. Name must contain lowercase letters, numbers and hyphens and must not exceed 63 characters")
+		}
+		if containers[container.Name] {
+			return fmt.Errorf("Duplicate container name: %s", container.Name)
+		}
+		containers[container.Name] = true
+
+		if container.Image == "" {
+			return fmt.Errorf("Invalid image for container %s", container.Name)
+		}
+
+		if container.CPU == 0 {
+			container.CPU = defaultContainerCPU
+		}
+		if container.Memory == 0 {
+			container.Memory = defaultContainerMemory
+		}
+		if container.CPU < 0 || container.Memory < 0 {
+			return fmt.Errorf("Invalid resources for container %s. CPU and memory must be positive numbers", container.Name)
+		}
+
+		for portIdx := range container.Ports {
+			port := &container.Ports[portIdx]
+			if port.Port < 1 || port.Port > 65535 {
+				return fmt.Errorf("Invalid port for container %s. Value must be between 1 and 65535", container.Name)
+			}
+
+			port.Protocol = strings.ToUpper(port.Protocol)
+			if port.Protocol == "" {
+				port.Protocol = "TCP"
+			}
+			if port.Protocol != "TCP" && port.Protocol != "UDP" {
+				return fmt.Errorf("Invalid port protocol for container %s. Value must be one of: TCP or UDP", container.Name)
+			}
+
+			if ports[port.Port] {
+				return fmt.Errorf("Port %d is exposed by multiple containers", port.Port)
+			}
+			ports[port.Port] = true
+		}
+
+		for _, env := range container.Env {
+			if env.Name == "" {
+				return fmt.Errorf("Invalid environment variable name for container %s", container.Name)
+			}
+		}
+
+		for _, mount := range container.VolumeMounts {
+			if !volumes[mount.Volume] {
+				return fmt.Errorf("Container %s references an undefined volume: %s", container.Name, mount.Volume)
+			}
+			if !strings.HasPrefix(mount.MountPath, "/") {
+				return fmt.Errorf("Invalid mount path for container %s. Value must be an absolute path", container.Name)
+			}
+		}
+	}
+
+	if payload.PublicIP && len(ports) == 0 {
+		return errors.New("A container group with a public IP address must expose at least one port")
+	}
+
+	return nil
+}
+
+// POST request on /api/azure/:id/subscriptions/:subscriptionId/resource_groups/:resourceGroup/container_groups
+func (handler *Handler) containerGroupCreate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	subscriptionID, resourceGroup, httpErr := retrieveResourceGroupReference(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	var payload containerGroupCreatePayload
+	err := request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	client, httpErr := handler.newAzureClient(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	_, err = client.ContainerGroup(subscriptionID, resourceGroup, payload.Name)
+	if err == nil {
+		return &httperror.HandlerError{http.StatusConflict, "A container group with the same name already exists in this resource group", errors.New("Container group already exists")}
+	} else if !azureapi.IsNotFound(err) {
+		return azureError("Unable to verify container group name", err)
+	}
+
+	group, err := client.CreateContainerGroup(subscriptionID, resourceGroup, payload.containerGroup())
+	if err != nil {
+		return azureError("Unable to create container group", err)
+	}
+
+	return response.JSON(w, group)
+}
+
+func (payload *containerGroupCreatePayload) containerGroup() *azureapi.ContainerGroup {
+	group := &azureapi.ContainerGroup{
+		Name:     payload.Name,
+		Location: payload.Location,
+		Properties: azureapi.ContainerGroupProperties{
+			OSType:        payload.OSType,
+			RestartPolicy: payload.RestartPolicy,
+			Containers:    make([]azureapi.Container, 0),
+			Volumes:       make([]azureapi.Volume, 0),
+		},
+	}
+
+	publicPorts := make([]azureapi.Port, 0)
+	for _, container := range payload.Containers {
+		properties := azureapi.ContainerProperties{
+			Image:   container.Image,
+			Command: container.Command,
+			Resources: azureapi.ResourceRequirements{
+				Requests: azureapi.ResourceRequests{
+					CPU:        container.CPU,
+					MemoryInGB: container.Memory,
+				},
+			},
+		}
+
+		for _, port := range container.Ports {
+			properties.Ports = append(properties.Ports, azureapi.ContainerPort{Port: port.Port, Protocol: port.Protocol})
+			publicPorts = append(publicPorts, azureapi.Port{Port: port.Port, Protocol: port.Protocol})
+		}
+
+		for _, env := range container.Env {
+			variable := azureapi.EnvironmentVariable{Name: env.Name, Value: env.Value}
+			if env.Secure {
+				variable = azureapi.EnvironmentVariable{Name: env.Name, SecureValue: env.Value}
+			}
+			properties.EnvironmentVariables = append(properties.EnvironmentVariables, variable)
+		}
+
+		for _, mount := range container.VolumeMounts {
+			properties.VolumeMounts = append(properties.VolumeMounts, azureapi.VolumeMount{
+				Name:      mount.Volume,
+				MountPath: mount.MountPath,
+				ReadOnly:  mount.ReadOnly,
+			})
+		}
+
+		group.Properties.Containers = append(group.Properties.Containers, azureapi.Container{
+			Name:       container.Name,
+			Properties: properties,
+		})
+	}
+
+	for _, volume := range payload.Volumes {
+		group.Properties.Volumes = append(group.Properties.Volumes, azureapi.Volume{
+			Name: volume.Name,
+			AzureFile: &azureapi.AzureFileVolume{
+				ShareName:          volume.ShareName,
+				StorageAccountName: volume.StorageAccountName,
+				StorageAccountKey:  volume.StorageAccountKey,
+				ReadOnly:           volume.ReadOnly,
+			},
+		})
+	}
+
+	if payload.PublicIP {
+		group.Properties.IPAddress = &azureapi.IPAddress{
+			Type:         "Public",
+			Ports:        publicPorts,
+			DNSNameLabel: payload.DNSNameLabel,
+		}
+	}
+
+	return group
+}
@@ -0,0 +1,37 @@
+package azure
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	azureapi "github.com/portainer/portainer/api/azure"
+)
+
+// GET request on /api/azure/:id/subscriptions/:subscriptionId/resource_groups/:resourceGroup/container_groups/:name
+func (handler *Handler) containerGroupInspect(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	subscriptionID, resourceGroup, httpErr := retrieveResourceGroupReference(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	name, err := request.RetrieveRouteVariableValue(r, "name")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid container group name route variable", err}
+	}
+
+	client, httpErr := handler.newAzureClient(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	group, err := client.ContainerGroup(subscriptionID, resourceGroup, name)
+	if azureapi.IsNotFound(err) {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find a container group with the specified name", err}
+	} else if err != nil {
+		return azureError("Unable to retrieve container group", err)
+	}
+
+	return response.JSON(w, group)
+}
@@ -0,0 +1,29 @@
+package azure
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+)
+
+// GET request on /api/azure/:id/subscriptions/:subscriptionId/container_groups
+func (handler *Handler) containerGroupList(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	subscriptionID, err := request.RetrieveRouteVariableValue(r, "subscriptionId")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid subscription identifier route variable", err}
+	}
+
+	client, httpErr := handler.newAzureClient(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	groups, err := client.ContainerGroups(subscriptionID)
+	if err != nil {
+		return azureError("Unable to retrieve container groups", err)
+	}
+
+	return response.JSON(w, groups)
+}
@@ -0,0 +1,44 @@
+package azure
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	azureapi "github.com/portainer/portainer/api/azure"
+)
+
+// GET request on /api/azure/:id/subscriptions/:subscriptionId/resource_groups/:resourceGroup/container_groups/:name/containers/:containerName/logs?tail=<tail>
+func (handler *Handler) containerLogs(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	subscriptionID, resourceGroup, httpErr := retrieveResourceGroupReference(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	name, err := request.RetrieveRouteVariableValue(r, "name")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid container group name route variable", err}
+	}
+
+	containerName, err := request.RetrieveRouteVariableValue(r, "containerName")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid container name route variable", err}
+	}
+
+	tail, _ := request.RetrieveNumericQueryParameter(r, "tail", true)
+
+	client, httpErr := handler.newAzureClient(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	logs, err := client.ContainerLogs(subscriptionID, resourceGroup, name, containerName, tail)
+	if azureapi.IsNotFound(err) {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find a container with the specified name", err}
+	} else if err != nil {
+		return azureError("Unable to retrieve container logs", err)
+	}
+
+	return response.JSON(w, logs)
+}
@@ -0,0 +1,102 @@
+package azure
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/portainer/api"
+	azureapi "github.com/portainer/portainer/api/azure"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+// Handler is the HTTP handler used to manage Azure Container Instances container groups.
+type Handler struct {
+	*mux.Router
+	requestBouncer     *security.RequestBouncer
+	DataStore          portainer.DataStore
+	AzureClientFactory *azureapi.ClientFactory
+}
+
+// NewHandler creates a handler to manage Azure container groups.
+func NewHandler(bouncer *security.RequestBouncer) *Handler {
+	h := &Handler{
+		Router:         mux.NewRouter(),
+		requestBouncer: bouncer,
+	}
+	h.Handle("/azure/{id}/subscriptions/{subscriptionId}/container_groups",
+		bouncer.RestrictedAccess(httperror.LoggerHandler(h.containerGroupList))).Methods(http.MethodGet)
+	h.Handle("/azure/{id}/subscriptions/{subscriptionId}/resource_groups/{resourceGroup}/container_groups",
+		bouncer.RestrictedAccess(httperror.LoggerHandler(h.containerGroupCreate))).Methods(http.MethodPost)
+	h.Handle("/azure/{id}/subscriptions/{subscriptionId}/resource_groups/{resourceGroup}/container_groups/{name}",
+		bouncer.RestrictedAccess(httperror.LoggerHandler(h.containerGroupInspect))).Methods(http.MethodGet)
+	h.Handle("/azure/{id}/subscriptions/{subscriptionId}/resource_groups/{resourceGroup}/container_groups/{name}",
+		bouncer.RestrictedAccess(httperror.LoggerHandler(h.containerGroupDelete))).Methods(http.MethodDelete)
+	h.Handle("/azure/{id}/subscriptions/{subscriptionId}/resource_groups/{resourceGroup}/container_groups/{name}/containers/{containerName}/logs",
+		bouncer.RestrictedAccess(httperror.LoggerHandler(h.containerLogs))).Methods(http.MethodGet)
+	return h
+}
+
+// newAzureClient retrieves the Azure endpoint referenced by the request, ensures that the user
+// can access it and returns a client targeting the Azure management API.
+func (handler *Handler) newAzureClient(r *http.Request) (*azureapi.Client, *httperror.HandlerError) {
+	endpointID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return nil, &httperror.HandlerError{http.StatusBadRequest, "Invalid endpoint identifier route variable", err}
+	}
+
+	endpoint, err := handler.DataStore.Endpoint().Endpoint(portainer.EndpointID(endpointID))
+	if err == bolterrors.ErrObjectNotFound {
+		return nil, &httperror.HandlerError{http.StatusNotFound, "Unable to find an endpoint with the specified identifier inside the database", err}
+	} else if err != nil {
+		return nil, &httperror.HandlerError{http.StatusInternalServerError, "Unable to find an endpoint with the specified identifier inside the database", err}
+	}
+
+	if endpoint.Type != portainer.AzureEnvironment {
+		return nil, &httperror.HandlerError{http.StatusBadRequest, "Invalid endpoint type", errors.New("The endpoint is not an Azure endpoint")}
+	}
+
+	err = handler.requestBouncer.AuthorizedEndpointOperation(r, endpoint)
+	if err != nil {
+		return nil, &httperror.HandlerError{http.StatusForbidden, "Permission denied to access endpoint", err}
+	}
+
+	client, err := handler.AzureClientFactory.GetClient(endpoint)
+	if err != nil {
+		return nil, &httperror.HandlerError{http.StatusInternalServerError, "Unable to create Azure client", err}
+	}
+
+	return client, nil
+}
+
+// azureError converts an error returned by the Azure API to a handler error. Authentication
+// errors are reported as internal errors so that they are not mistaken for a Portainer session expiry.
+func azureError(message string, err error) *httperror.HandlerError {
+	status := http.StatusInternalServerError
+	if apiErr, ok := err.(*azureapi.Error); ok {
+		switch apiErr.StatusCode {
+		case http.StatusBadRequest, http.StatusNotFound, http.StatusConflict:
+			status = apiErr.StatusCode
+		}
+	}
+
+	return &httperror.HandlerError{status, message, err}
+}
+
+// retrieveResourceGroupReference returns the subscription and the resource group referenced by the request
+func retrieveResourceGroupReference(r *http.Request) (string, string, *httperror.HandlerError) {
+	subscriptionID, err := request.RetrieveRouteVariableValue(r, "subscriptionId")
+	if err != nil {
+		return "", "", &httperror.HandlerError{http.StatusBadRequest, "Invalid subscription identifier route variable", err}
+	}
+
+	resourceGroup, err := request.RetrieveRouteVariableValue(r, "resourceGroup")
+	if err != nil {
+		return "", "", &httperror.HandlerError{http.StatusBadRequest, "Invalid resource group route variable", err}
+	}
+
+	return subscriptionID, resourceGroup, nil
+}
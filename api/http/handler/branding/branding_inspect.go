@@ -0,0 +1,29 @@
+package branding
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+)
+
+type brandingResponse struct {
+	LogoURL string `json:"LogoURL"`
+	portainer.BrandingSettings
+}
+
+// GET request on /api/branding
+// The branding is public as it is required to render the login page.
+// It is updated with the Branding field of the settings.
+func (handler *Handler) brandingInspect(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	settings, err := handler.DataStore.Settings().Settings()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve the settings from the database", err}
+	}
+
+	return response.JSON(w, &brandingResponse{
+		LogoURL:          settings.LogoURL,
+		BrandingSettings: settings.Branding,
+	})
+}
@@ -0,0 +1,47 @@
+package changerequests
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	httperrors "github.com/portainer/portainer/api/http/errors"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+// DELETE request on /api/change_requests/:id
+//
+// Withdraws a change request waiting for a review, only its author and the administrators can withdraw it.
+func (handler *Handler) changeRequestDelete(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	changeRequestID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid change request identifier route variable", err}
+	}
+
+	changeRequest, handlerErr := handler.retrieveAccessibleChangeRequest(r, portainer.ChangeRequestID(changeRequestID))
+	if handlerErr != nil {
+		return handlerErr
+	}
+
+	tokenData, err := security.RetrieveTokenData(r)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve user details from authentication token", err}
+	}
+
+	if changeRequest.RequestedBy != tokenData.ID && tokenData.Role != portainer.AdministratorRole {
+		return &httperror.HandlerError{http.StatusForbidden, "Permission denied to withdraw the change request", httperrors.ErrResourceAccessDenied}
+	}
+
+	if changeRequest.Status != portainer.ChangeRequestPending {
+		return &httperror.HandlerError{http.StatusConflict, "The change request is not waiting for a review", errChangeRequestNotPending}
+	}
+
+	err = handler.DataStore.ChangeRequest().DeleteChangeRequest(changeRequest.ID)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to remove the change request from the database", err}
+	}
+
+	return response.Empty(w)
+}
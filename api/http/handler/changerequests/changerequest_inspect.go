@@ -0,0 +1,25 @@
+package changerequests
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+)
+
+// GET request on /api/change_requests/:id
+func (handler *Handler) changeRequestInspect(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	changeRequestID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid change request identifier route variable", err}
+	}
+
+	changeRequest, handlerErr := handler.retrieveAccessibleChangeRequest(r, portainer.ChangeRequestID(changeRequestID))
+	if handlerErr != nil {
+		return handlerErr
+	}
+
+	return response.JSON(w, changeRequest)
+}
@@ -0,0 +1,43 @@
+package changerequests
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+)
+
+// GET request on /api/change_requests?status=<status>
+//
+// Returns the change requests of the endpoints the user can access, optionally filtered by status.
+func (handler *Handler) changeRequestList(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	status, _ := request.RetrieveNumericQueryParameter(r, "status", true)
+
+	changeRequests, err := handler.DataStore.ChangeRequest().ChangeRequests()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve change requests from the database", err}
+	}
+
+	access := make(map[portainer.EndpointID]bool)
+	filtered := make([]portainer.ChangeRequest, 0)
+	for _, changeRequest := range changeRequests {
+		if status != 0 && changeRequest.Status != portainer.ChangeRequestStatus(status) {
+			continue
+		}
+
+		authorized, ok := access[changeRequest.EndpointID]
+		if !ok {
+			endpoint, err := handler.DataStore.Endpoint().Endpoint(changeRequest.EndpointID)
+			authorized = err == nil && handler.requestBouncer.AuthorizedEndpointOperation(r, endpoint) == nil
+			access[changeRequest.EndpointID] = authorized
+		}
+
+		if authorized {
+			filtered = append(filtered, changeRequest)
+		}
+	}
+
+	return response.JSON(w, filtered)
+}
@@ -0,0 +1,117 @@
+package changerequests
+
+import (
+	"net/http"
+	"time"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+type changeRequestReviewPayload struct {
+	Approved bool
+	Comment  string
+}
+
+func (payload *changeRequestReviewPayload) Validate(r *http.Request) error {
+	return nil
+}
+
+// PUT request on /api/change_requests/:id/review
+//
+// Approves or rejects a change request waiting for a review. The reviewer must be able to access the endpoint
+// of the change and cannot be its author. An approved change is executed on behalf of its author before the
+// response is returned, the outcome of the execution is recorded in the change request.
+func (handler *Handler) changeRequestReview(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	changeRequestID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid change request identifier route variable", err}
+	}
+
+	var payload changeRequestReviewPayload
+	err = request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	changeRequest, handlerErr := handler.retrieveAccessibleChangeRequest(r, portainer.ChangeRequestID(changeRequestID))
+	if handlerErr != nil {
+		return handlerErr
+	}
+
+	if changeRequest.Status != portainer.ChangeRequestPending {
+		return &httperror.HandlerError{http.StatusConflict, "The change request is not waiting for a review", errChangeRequestNotPending}
+	}
+
+	tokenData, err := security.RetrieveTokenData(r)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve user details from authentication token", err}
+	}
+
+	if isChangeRequestAuthor(changeRequest, tokenData.ID) || isChangeRequestAuthor(changeRequest, tokenData.ImpersonatorID) {
+		return &httperror.HandlerError{http.StatusForbidden, "A change request must be reviewed by another user than its author", errSelfReview}
+	}
+
+	changeRequest.ReviewedBy = tokenData.ID
+	changeRequest.ReviewerName = tokenData.Username
+	changeRequest.ReviewDate = time.Now().Unix()
+	changeRequest.ReviewComment = payload.Comment
+
+	if !payload.Approved {
+		changeRequest.Status = portainer.ChangeRequestRejected
+
+		err = handler.DataStore.ChangeRequest().UpdateChangeRequest(changeRequest.ID, changeRequest)
+		if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist the change request changes inside the database", err}
+		}
+
+		return response.JSON(w, changeRequest)
+	}
+
+	author, err := handler.DataStore.User().User(changeRequest.RequestedBy)
+	if err == bolterrors.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusConflict, "The author of the change request does not exist anymore", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find the author of the change request inside the database", err}
+	}
+
+	changeRequest.Status = portainer.ChangeRequestApproved
+
+	err = handler.DataStore.ChangeRequest().UpdateChangeRequest(changeRequest.ID, changeRequest)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist the change request changes inside the database", err}
+	}
+
+	statusCode, executionErr := handler.executeChangeRequest(changeRequest, author)
+
+	changeRequest, err = handler.DataStore.ChangeRequest().ChangeRequest(changeRequest.ID)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a change request with the specified identifier inside the database", err}
+	}
+
+	changeRequest.Status = portainer.ChangeRequestExecuted
+	changeRequest.ExecutionStatusCode = statusCode
+	if executionErr != nil {
+		changeRequest.Status = portainer.ChangeRequestFailed
+		changeRequest.ExecutionError = executionErr.Error()
+	}
+
+	err = handler.DataStore.ChangeRequest().UpdateChangeRequest(changeRequest.ID, changeRequest)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist the change request changes inside the database", err}
+	}
+
+	return response.JSON(w, changeRequest)
+}
+
+// isChangeRequestAuthor returns true when the user filed the change request, directly or by impersonating its author
+func isChangeRequestAuthor(changeRequest *portainer.ChangeRequest, userID portainer.UserID) bool {
+	if userID == 0 {
+		return false
+	}
+	return userID == changeRequest.RequestedBy || userID == changeRequest.RequestedByImpersonator
+}
@@ -0,0 +1,31 @@
+package changerequests
+
+import (
+	"testing"
+
+	"github.com/portainer/portainer/api"
+)
+
+func TestIsChangeRequestAuthor(t *testing.T) {
+	changeRequest := &portainer.ChangeRequest{RequestedBy: 2, RequestedByImpersonator: 1}
+
+	cases := []struct {
+		userID   portainer.UserID
+		expected bool
+	}{
+		{0, false},
+		{1, true},
+		{2, true},
+		{3, false},
+	}
+
+	for _, c := range cases {
+		if author := isChangeRequestAuthor(changeRequest, c.userID); author != c.expected {
+			t.Errorf("isChangeRequestAuthor(%d): got %t, want %t", c.userID, author, c.expected)
+		}
+	}
+
+	if isChangeRequestAuthor(&portainer.ChangeRequest{RequestedBy: 2}, 0) {
+		t.Error("a user without impersonator must not be considered as the impersonator of the author")
+	}
+}
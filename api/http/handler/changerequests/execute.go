@@ -0,0 +1,109 @@
+package changerequests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+// maxExecutionErrorLength is the maximum length of the response body recorded when an execution fails
+const maxExecutionErrorLength = 1024
+
+// executionRecorder records the status code and the beginning of the body of the response to an approved request
+type executionRecorder struct {
+	header     http.Header
+	statusCode int
+	body       bytes.Buffer
+}
+
+// executeChangeRequest replays the request of an approved change request with a token of its author and
+// returns the status code of the response, and an error when the request failed
+func (handler *Handler) executeChangeRequest(changeRequest *portainer.ChangeRequest, author *portainer.User) (int, error) {
+	token, err := handler.JWTService.GenerateToken(&portainer.TokenData{
+		ID:       author.ID,
+		Username: author.Username,
+		Role:     author.Role,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	ctx := security.ChangeRequestExecutionContext(context.Background(), changeRequest.ID)
+	r, err := http.NewRequestWithContext(ctx, changeRequest.Method, changeRequest.RequestURI, bytes.NewReader(changeRequest.Body))
+	if err != nil {
+		return 0, err
+	}
+	r.RequestURI = changeRequest.RequestURI
+	r.Header.Set("Authorization", "Bearer "+token)
+	if changeRequest.ContentType != "" {
+		r.Header.Set("Content-Type", changeRequest.ContentType)
+	}
+
+	recorder := &executionRecorder{header: make(http.Header)}
+	handler.Executor.ServeHTTP(recorder, r)
+
+	if recorder.statusCode == 0 {
+		recorder.statusCode = http.StatusOK
+	}
+
+	if recorder.statusCode >= http.StatusBadRequest {
+		return recorder.statusCode, recorder.err()
+	}
+
+	return recorder.statusCode, nil
+}
+
+func (recorder *executionRecorder) Header() http.Header {
+	return recorder.header
+}
+
+func (recorder *executionRecorder) Write(data []byte) (int, error) {
+	if recorder.statusCode == 0 {
+		recorder.statusCode = http.StatusOK
+	}
+
+	if remaining := maxExecutionErrorLength - recorder.body.Len(); remaining > 0 {
+		if len(data) > remaining {
+			recorder.body.Write(data[:remaining])
+		} else {
+			recorder.body.Write(data)
+		}
+	}
+
+	return len(data), nil
+}
+
+func (recorder *executionRecorder) WriteHeader(statusCode int) {
+	if recorder.statusCode == 0 {
+		recorder.statusCode = statusCode
+	}
+}
+
+// err returns the error of a failed response, the API errors are reported with their message and details
+func (recorder *executionRecorder) err() error {
+	var response struct {
+		Message string `json:"message"`
+		Details string `json:"details"`
+	}
+
+	err := json.Unmarshal(recorder.body.Bytes(), &response)
+	if err == nil && response.Message != "" {
+		if response.Details != "" {
+			return fmt.Errorf("%s: %s", response.Message, response.Details)
+		}
+		return errors.New(response.Message)
+	}
+
+	message := strings.TrimSpace(recorder.body.String())
+	if message == "" {
+		message = http.StatusText(recorder.statusCode)
+	}
+	return errors.New(message)
+}
@@ -0,0 +1,69 @@
+package changerequests
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+var (
+	errChangeRequestNotPending = errors.New("The change request is not waiting for a review")
+	errSelfReview              = errors.New("A change request must be reviewed by another user than its author")
+)
+
+// Handler is the HTTP handler used to handle change request operations.
+type Handler struct {
+	*mux.Router
+	requestBouncer *security.RequestBouncer
+	DataStore      portainer.DataStore
+	JWTService     portainer.JWTService
+	// Executor serves the approved requests on behalf of their authors
+	Executor http.Handler
+}
+
+// NewHandler creates a handler to manage change request operations.
+func NewHandler(bouncer *security.RequestBouncer) *Handler {
+	h := &Handler{
+		Router:         mux.NewRouter(),
+		requestBouncer: bouncer,
+	}
+	h.Handle("/change_requests",
+		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.changeRequestList))).Methods(http.MethodGet)
+	h.Handle("/change_requests/{id}",
+		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.changeRequestInspect))).Methods(http.MethodGet)
+	h.Handle("/change_requests/{id}",
+		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.changeRequestDelete))).Methods(http.MethodDelete)
+	h.Handle("/change_requests/{id}/review",
+		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.changeRequestReview))).Methods(http.MethodPut)
+	return h
+}
+
+// retrieveAccessibleChangeRequest returns the change request of the request, the user must be able to access
+// the endpoint of the change request
+func (handler *Handler) retrieveAccessibleChangeRequest(r *http.Request, changeRequestID portainer.ChangeRequestID) (*portainer.ChangeRequest, *httperror.HandlerError) {
+	changeRequest, err := handler.DataStore.ChangeRequest().ChangeRequest(changeRequestID)
+	if err == bolterrors.ErrObjectNotFound {
+		return nil, &httperror.HandlerError{http.StatusNotFound, "Unable to find a change request with the specified identifier inside the database", err}
+	} else if err != nil {
+		return nil, &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a change request with the specified identifier inside the database", err}
+	}
+
+	endpoint, err := handler.DataStore.Endpoint().Endpoint(changeRequest.EndpointID)
+	if err == bolterrors.ErrObjectNotFound {
+		return nil, &httperror.HandlerError{http.StatusNotFound, "Unable to find the endpoint associated to the change request inside the database", err}
+	} else if err != nil {
+		return nil, &httperror.HandlerError{http.StatusInternalServerError, "Unable to find the endpoint associated to the change request inside the database", err}
+	}
+
+	err = handler.requestBouncer.AuthorizedEndpointOperation(r, endpoint)
+	if err != nil {
+		return nil, &httperror.HandlerError{http.StatusForbidden, "Permission denied to access endpoint", err}
+	}
+
+	return changeRequest, nil
+}
@@ -0,0 +1,246 @@
+package containers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+	"github.com/portainer/portainer/api/docker"
+	httperrors "github.com/portainer/portainer/api/http/errors"
+	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/authorization"
+)
+
+const (
+	containerBulkActionStart   = "start"
+	containerBulkActionStop    = "stop"
+	containerBulkActionRestart = "restart"
+	containerBulkActionRemove  = "remove"
+)
+
+type containerBulkPayload struct {
+	// Action is one of start, stop, restart or remove
+	Action string
+	// ContainerIDs is the list of containers targeted by the operation
+	ContainerIDs []string
+	// Filters can be used instead of ContainerIDs to target all the containers matching
+	// a set of Docker filters (e.g. {"label": ["com.example.tier=frontend"]})
+	Filters map[string][]string
+	// NodeName can be used to target the containers of a specific node in an agent cluster
+	NodeName string
+	// Timeout is the number of seconds to wait before killing the containers when stopping or restarting them
+	Timeout *int
+	// Force kills running containers before removing them
+	Force bool
+	// RemoveVolumes removes the anonymous volumes associated to the containers being removed
+	RemoveVolumes bool
+}
+
+type containerBulkResult struct {
+	ID      string `json:"Id"`
+	Name    string `json:"Name"`
+	Success bool   `json:"Success"`
+	Error   string `json:"Error,omitempty"`
+}
+
+func (payload *containerBulkPayload) Validate(r *http.Request) error {
+	switch payload.Action {
+	case containerBulkActionStart, containerBulkActionStop, containerBulkActionRestart, containerBulkActionRemove:
+	default:
+		return errors.New("Invalid action. Value must be one of: start, stop, restart or remove")
+	}
+	if len(payload.ContainerIDs) == 0 && len(payload.Filters) == 0 {
+		return errors.New("Either a list of container identifiers or a set of filters must be specified")
+	}
+	if len(payload.ContainerIDs) > 0 && len(payload.Filters) > 0 {
+		return errors.New("A list of container identifiers and a set of filters cannot be specified at the same time")
+	}
+	if payload.Timeout != nil && *payload.Timeout < 0 {
+		return errors.New("Invalid timeout value. Value must be a positive number of seconds")
+	}
+	return nil
+}
+
+// POST request on /api/endpoints/:id/containers/bulk
+func (handler *Handler) containerBulk(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	endpointID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid endpoint identifier route variable", err}
+	}
+
+	var payload containerBulkPayload
+	err = request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	endpoint, err := handler.DataStore.Endpoint().Endpoint(portainer.EndpointID(endpointID))
+	if err == bolterrors.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find an endpoint with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find an endpoint with the specified identifier inside the database", err}
+	}
+
+	err = handler.requestBouncer.AuthorizedEndpointOperation(r, endpoint)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusForbidden, "Permission denied to access endpoint", err}
+	}
+
+	securityContext, err := security.RetrieveRestrictedRequestContext(r)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve info from request context", err}
+	}
+
+	resourceControls, err := handler.DataStore.ResourceControl().ResourceControls()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve resource controls from the database", err}
+	}
+
+	cli, err := handler.DockerClientFactory.CreateClient(endpoint, payload.NodeName)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to create Docker client", err}
+	}
+	defer cli.Close()
+
+	hiddenContainers, err := handler.hiddenContainerRules(securityContext)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve settings from the database", err}
+	}
+
+	containers, results, err := resolveBulkContainers(cli, &payload, hiddenContainers)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve the containers targeted by the operation", err}
+	}
+
+	handler.orderBulkContainers(containers, endpoint.ID, payload.Action)
+
+	for _, container := range containers {
+		result := containerBulkResult{ID: container.ID, Name: containerName(container)}
+
+		resourceControl := docker.ResourceControlForResource(container.ID, portainer.ContainerResourceControl, container.Labels, resourceControls)
+		if !authorization.AdministratorOrUserCanAccessResource(securityContext.IsAdmin, securityContext.UserID, securityContext.UserMemberships, resourceControl) {
+			result.Error = httperrors.ErrResourceAccessDenied.Error()
+			results = append(results, result)
+			continue
+		}
+
+		err := executeContainerBulkAction(cli, &payload, container.ID)
+		if err != nil {
+			result.Error = err.Error()
+			results = append(results, result)
+			continue
+		}
+
+		if payload.Action == containerBulkActionRemove {
+			handler.removeContainerResourceControl(container.ID, resourceControls)
+		}
+
+		result.Success = true
+		results = append(results, result)
+	}
+
+	return response.JSON(w, results)
+}
+
+// resolveBulkContainers returns the containers targeted by a bulk operation. Containers referenced
+// by an identifier that cannot be found are returned as failed results. The hidden containers are
+// ignored when they match the filters and reported as not found when they are referenced.
+func resolveBulkContainers(cli *client.Client, payload *containerBulkPayload, hiddenContainers *docker.HiddenContainerRules) ([]types.Container, []containerBulkResult, error) {
+	results := make([]containerBulkResult, 0)
+
+	if len(payload.Filters) > 0 {
+		args := filters.NewArgs()
+		for key, values := range payload.Filters {
+			for _, value := range values {
+				args.Add(key, value)
+			}
+		}
+
+		containers, err := cli.ContainerList(context.Background(), types.ContainerListOptions{All: true, Filters: args})
+		if err != nil {
+			return nil, nil, err
+		}
+
+		visibleContainers := make([]types.Container, 0, len(containers))
+		for _, container := range containers {
+			if hiddenContainers == nil || !hiddenContainers.IsHidden(container.Names, container.Labels) {
+				visibleContainers = append(visibleContainers, container)
+			}
+		}
+
+		return visibleContainers, results, nil
+	}
+
+	containers := make([]types.Container, 0)
+	for _, containerID := range payload.ContainerIDs {
+		container, err := cli.ContainerInspect(context.Background(), containerID)
+		if err != nil {
+			results = append(results, containerBulkResult{ID: containerID, Error: err.Error()})
+			continue
+		}
+
+		if hiddenContainers != nil && hiddenContainers.IsHidden([]string{container.Name}, container.Config.Labels) {
+			results = append(results, containerBulkResult{ID: containerID, Error: errContainerNotFound.Error()})
+			continue
+		}
+
+		containers = append(containers, types.Container{
+			ID:     container.ID,
+			Names:  []string{container.Name},
+			Labels: container.Config.Labels,
+		})
+	}
+
+	return containers, results, nil
+}
+
+func executeContainerBulkAction(cli *client.Client, payload *containerBulkPayload, containerID string) error {
+	var timeout *time.Duration
+	if payload.Timeout != nil {
+		duration := time.Duration(*payload.Timeout) * time.Second
+		timeout = &duration
+	}
+
+	switch payload.Action {
+	case containerBulkActionStart:
+		return cli.ContainerStart(context.Background(), containerID, types.ContainerStartOptions{})
+	case containerBulkActionStop:
+		return cli.ContainerStop(context.Background(), containerID, timeout)
+	case containerBulkActionRestart:
+		return cli.ContainerRestart(context.Background(), containerID, timeout)
+	case containerBulkActionRemove:
+		return cli.ContainerRemove(context.Background(), containerID, types.ContainerRemoveOptions{Force: payload.Force, RemoveVolumes: payload.RemoveVolumes})
+	}
+
+	return nil
+}
+
+func (handler *Handler) removeContainerResourceControl(containerID string, resourceControls []portainer.ResourceControl) {
+	for _, resourceControl := range resourceControls {
+		if resourceControl.ResourceID == containerID && resourceControl.Type == portainer.ContainerResourceControl {
+			err := handler.DataStore.ResourceControl().DeleteResourceControl(resourceControl.ID)
+			if err != nil {
+				log.Printf("[WARN] [http,containers] [message: unable to remove the resource control associated to the container] [container: %s] [error: %s]", containerID, err)
+			}
+			return
+		}
+	}
+}
+
+func containerName(container types.Container) string {
+	if len(container.Names) > 0 {
+		return strings.TrimPrefix(container.Names[0], "/")
+	}
+	return ""
+}
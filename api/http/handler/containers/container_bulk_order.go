@@ -0,0 +1,80 @@
+package containers
+
+import (
+	"log"
+	"path"
+	"sort"
+
+	"github.com/docker/docker/api/types"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/docker"
+	"github.com/portainer/portainer/api/internal/stackfile"
+)
+
+// orderBulkContainers sorts the containers of the Compose stacks deployed by Portainer according to the
+// dependencies between their services: the containers are started and restarted after the containers
+// they depend on, and stopped and removed before them. The order of the other containers is preserved.
+func (handler *Handler) orderBulkContainers(containers []types.Container, endpointID portainer.EndpointID, action string) {
+	graphs := make(map[string]*stackfile.Graph)
+	levels := make(map[string]int)
+
+	for _, container := range containers {
+		stackName := container.Labels[docker.ComposeStackNameLabel]
+		service := container.Labels[docker.ComposeServiceLabel]
+		if stackName == "" || service == "" {
+			continue
+		}
+
+		graph, ok := graphs[stackName]
+		if !ok {
+			graph = handler.composeStackGraph(stackName, endpointID)
+			graphs[stackName] = graph
+		}
+
+		if graph == nil || len(graph.Cycle) > 0 {
+			continue
+		}
+
+		level := graph.Levels()[service]
+		if action == containerBulkActionStop || action == containerBulkActionRemove {
+			level = -level
+		}
+		levels[container.ID] = level
+	}
+
+	sort.SliceStable(containers, func(i, j int) bool {
+		return levels[containers[i].ID] < levels[containers[j].ID]
+	})
+}
+
+// composeStackGraph returns the dependency graph of a Compose stack deployed by Portainer on the endpoint,
+// or nil when the stack is not managed by Portainer or its file cannot be parsed
+func (handler *Handler) composeStackGraph(stackName string, endpointID portainer.EndpointID) *stackfile.Graph {
+	stacks, err := handler.DataStore.Stack().Stacks()
+	if err != nil {
+		log.Printf("[WARN] [http,containers] [message: unable to retrieve stacks from the database] [error: %s]", err)
+		return nil
+	}
+
+	for _, stack := range stacks {
+		if stack.Name != stackName || stack.EndpointID != endpointID || stack.Type != portainer.DockerComposeStack {
+			continue
+		}
+
+		stackFileContent, err := handler.FileService.GetFileContent(path.Join(stack.ProjectPath, stack.EntryPoint))
+		if err != nil {
+			log.Printf("[WARN] [http,containers] [message: unable to read the stack file] [stack: %s] [error: %s]", stackName, err)
+			return nil
+		}
+
+		graph, err := stackfile.DependencyGraph(stackFileContent)
+		if err != nil {
+			log.Printf("[WARN] [http,containers] [message: unable to parse the dependencies of the stack services] [stack: %s] [error: %s]", stackName, err)
+			return nil
+		}
+
+		return graph
+	}
+
+	return nil
+}
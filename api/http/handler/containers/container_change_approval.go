@@ -0,0 +1,34 @@
+package containers
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/portainer/api"
+)
+
+// containerBulkRemovalEndpoint returns the endpoint of a bulk operation removing containers. The body is
+// restored after reading the action so that it can be decoded by the operation.
+func containerBulkRemovalEndpoint(r *http.Request) (portainer.EndpointID, bool) {
+	endpointID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil || r.Body == nil {
+		return 0, false
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return 0, false
+	}
+
+	var payload containerBulkPayload
+	err = json.Unmarshal(body, &payload)
+	if err != nil || payload.Action != containerBulkActionRemove {
+		return 0, false
+	}
+
+	return portainer.EndpointID(endpointID), true
+}
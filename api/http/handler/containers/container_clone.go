@@ -0,0 +1,195 @@
+package containers
+
+import (
+	"context"
+	"log"
+	"net/http"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/client"
+	"github.com/docker/go-connections/nat"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/docker"
+	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/authorization"
+	"github.com/portainer/portainer/api/internal/organization"
+)
+
+type containerClonePayload struct {
+	// Name of the new container, a random name is generated by Docker when empty
+	Name string
+	// PortBindings replaces the port bindings of the existing container when specified
+	PortBindings nat.PortMap
+	// Healthcheck replaces the healthcheck of the existing container when specified
+	Healthcheck *docker.HealthcheckDefinition
+	// Start the new container once created
+	Start bool
+}
+
+type containerCloneResponse struct {
+	ID              string                     `json:"Id"`
+	Warnings        []string                   `json:"Warnings"`
+	ResourceControl *portainer.ResourceControl `json:"ResourceControl"`
+}
+
+func (payload *containerClonePayload) Validate(r *http.Request) error {
+	if payload.Healthcheck != nil {
+		_, err := payload.Healthcheck.HealthConfig()
+		return err
+	}
+	return nil
+}
+
+// POST request on /api/endpoints/:id/containers/:containerId/clone?nodeName=<nodeName>
+func (handler *Handler) containerClone(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	var payload containerClonePayload
+	err := request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	endpoint, source, httpErr := handler.retrieveAccessibleContainer(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	securityContext, err := security.RetrieveRestrictedRequestContext(r)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve info from request context", err}
+	}
+
+	template := newContainerTemplate(source)
+	template.Name = payload.Name
+
+	if payload.PortBindings != nil {
+		template.HostConfig.PortBindings = payload.PortBindings
+		if template.Config.ExposedPorts == nil {
+			template.Config.ExposedPorts = nat.PortSet{}
+		}
+		for port := range payload.PortBindings {
+			template.Config.ExposedPorts[port] = struct{}{}
+		}
+	}
+
+	if payload.Healthcheck != nil {
+		template.Config.Healthcheck, _ = payload.Healthcheck.HealthConfig()
+	}
+
+	if !securityContext.IsAdmin {
+		settings, err := handler.DataStore.Settings().Settings()
+		if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve settings from the database", err}
+		}
+
+		err = validateHostConfigForRegularUsers(template.HostConfig, settings)
+		if err != nil {
+			return &httperror.HandlerError{http.StatusForbidden, "Permission denied to create the container", err}
+		}
+	}
+
+	nodeName, _ := request.RetrieveQueryParameter(r, "nodeName", true)
+
+	cli, err := handler.DockerClientFactory.CreateClient(endpoint, nodeName)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to create Docker client", err}
+	}
+	defer cli.Close()
+
+	created, err := createContainerFromTemplate(cli, template)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to create the container", err}
+	}
+
+	resourceControl, err := handler.createContainerResourceControl(created.ID, endpoint, securityContext)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist the resource control associated to the container", err}
+	}
+
+	if payload.Start {
+		err = cli.ContainerStart(context.Background(), created.ID, types.ContainerStartOptions{})
+		if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Container created but unable to start it", err}
+		}
+	}
+
+	return response.JSON(w, &containerCloneResponse{
+		ID:              created.ID,
+		Warnings:        created.Warnings,
+		ResourceControl: resourceControl,
+	})
+}
+
+// createContainerFromTemplate creates a container connected to the primary network of the template
+// and then connects it to the additional networks, as the Docker API only supports a single network
+// at creation time. The container is removed if it cannot be connected to one of the networks.
+func createContainerFromTemplate(cli *client.Client, template *containerTemplate) (*container.ContainerCreateCreatedBody, error) {
+	primaryNetwork := string(template.HostConfig.NetworkMode)
+	if template.HostConfig.NetworkMode.IsDefault() {
+		primaryNetwork = "bridge"
+	}
+
+	networkingConfig := &network.NetworkingConfig{
+		EndpointsConfig: make(map[string]*network.EndpointSettings),
+	}
+	if settings, ok := template.NetworkingConfig.EndpointsConfig[primaryNetwork]; ok {
+		networkingConfig.EndpointsConfig[primaryNetwork] = settings
+	}
+
+	created, err := cli.ContainerCreate(context.Background(), template.Config, template.HostConfig, networkingConfig, template.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if !template.HostConfig.NetworkMode.IsUserDefined() && !template.HostConfig.NetworkMode.IsBridge() && !template.HostConfig.NetworkMode.IsDefault() {
+		return &created, nil
+	}
+
+	for networkName, settings := range template.NetworkingConfig.EndpointsConfig {
+		if networkName == primaryNetwork {
+			continue
+		}
+
+		err = cli.NetworkConnect(context.Background(), networkName, created.ID, settings)
+		if err != nil {
+			removeErr := cli.ContainerRemove(context.Background(), created.ID, types.ContainerRemoveOptions{Force: true})
+			if removeErr != nil {
+				log.Printf("[WARN] [http,containers] [message: unable to remove the container after a failed clone operation] [container: %s] [error: %s]", created.ID, removeErr)
+			}
+			return nil, err
+		}
+	}
+
+	return &created, nil
+}
+
+func (handler *Handler) createContainerResourceControl(containerID string, endpoint *portainer.Endpoint, securityContext *security.RestrictedRequestContext) (*portainer.ResourceControl, error) {
+	settings, err := handler.DataStore.Settings().Settings()
+	if err != nil {
+		return nil, err
+	}
+
+	userTeamIDs := make([]portainer.TeamID, 0)
+	for _, membership := range securityContext.UserMemberships {
+		userTeamIDs = append(userTeamIDs, membership.TeamID)
+	}
+
+	settings, err = organization.EffectiveSettings(handler.DataStore, settings, endpoint.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	policy := authorization.EffectiveResourceOwnershipPolicy(endpoint, settings)
+	resourceControl := authorization.NewDefaultResourceControl(containerID, portainer.ContainerResourceControl, securityContext.UserID, userTeamIDs, policy)
+
+	err = handler.DataStore.ResourceControl().CreateResourceControl(resourceControl)
+	if err != nil {
+		return nil, err
+	}
+
+	return resourceControl, nil
+}
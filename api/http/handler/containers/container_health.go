@@ -0,0 +1,79 @@
+package containers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+)
+
+type containerHealthResponse struct {
+	// Config is the healthcheck of the container, inherited from its image when not overridden
+	Config *container.HealthConfig `json:"Config"`
+	// Inherited is true when the healthcheck is defined by the image of the container
+	Inherited bool `json:"Inherited"`
+	// Status is empty when the container has no healthcheck
+	Status        string `json:"Status"`
+	FailingStreak int    `json:"FailingStreak"`
+	// Log contains the latest probe results, as kept by the Docker daemon
+	Log []*types.HealthcheckResult `json:"Log"`
+}
+
+// GET request on /api/endpoints/:id/containers/:containerId/health?nodeName=<nodeName>
+//
+// Returns the healthcheck configuration of a container and the results of its latest probes.
+func (handler *Handler) containerHealthInspect(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	endpoint, source, httpErr := handler.retrieveAccessibleContainer(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	health := &containerHealthResponse{
+		Config: source.Config.Healthcheck,
+		Log:    []*types.HealthcheckResult{},
+	}
+
+	if source.State != nil && source.State.Health != nil {
+		health.Status = source.State.Health.Status
+		health.FailingStreak = source.State.Health.FailingStreak
+		if source.State.Health.Log != nil {
+			health.Log = source.State.Health.Log
+		}
+	}
+
+	nodeName, _ := request.RetrieveQueryParameter(r, "nodeName", true)
+
+	cli, err := handler.DockerClientFactory.CreateClient(endpoint, nodeName)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to create Docker client", err}
+	}
+	defer cli.Close()
+
+	image, _, err := cli.ImageInspectWithRaw(context.Background(), source.Image)
+	if err == nil && image.Config != nil && image.Config.Healthcheck != nil {
+		health.Inherited = healthConfigEqual(source.Config.Healthcheck, image.Config.Healthcheck)
+	}
+
+	return response.JSON(w, health)
+}
+
+func healthConfigEqual(a, b *container.HealthConfig) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	if len(a.Test) != len(b.Test) {
+		return false
+	}
+	for idx := range a.Test {
+		if a.Test[idx] != b.Test[idx] {
+			return false
+		}
+	}
+
+	return a.Interval == b.Interval && a.Timeout == b.Timeout && a.StartPeriod == b.StartPeriod && a.Retries == b.Retries
+}
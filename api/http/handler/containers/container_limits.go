@@ -0,0 +1,206 @@
+package containers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api/docker"
+)
+
+const (
+	// minimumMemoryLimit is the lowest memory limit accepted by the Docker daemon
+	minimumMemoryLimit = 6 * 1024 * 1024
+	// cpuPeriod is the CFS period used to express a CPU limit as a quota
+	cpuPeriod = 100000
+)
+
+type containerLimitsPayload struct {
+	// CPUs is the number of CPUs the container can use
+	CPUs *float64
+	// Memory is the memory limit in bytes
+	Memory            *int64
+	MemoryReservation *int64
+	// MemorySwap is the memory and swap limit in bytes, -1 allows an unlimited swap
+	MemorySwap    *int64
+	RestartPolicy *container.RestartPolicy
+}
+
+type containerLimitsResponse struct {
+	CPUs              float64                 `json:"CPUs"`
+	Memory            int64                   `json:"Memory"`
+	MemoryReservation int64                   `json:"MemoryReservation"`
+	MemorySwap        int64                   `json:"MemorySwap"`
+	RestartPolicy     container.RestartPolicy `json:"RestartPolicy"`
+	Warnings          []string                `json:"Warnings"`
+}
+
+func (payload *containerLimitsPayload) Validate(r *http.Request) error {
+	if payload.CPUs == nil && payload.Memory == nil && payload.MemoryReservation == nil && payload.MemorySwap == nil && payload.RestartPolicy == nil {
+		return errors.New("No limit to update")
+	}
+	if payload.CPUs != nil && *payload.CPUs < 0.01 {
+		return errors.New("Invalid CPU limit. Value must be at least 0.01")
+	}
+	if payload.Memory != nil && *payload.Memory < minimumMemoryLimit {
+		return errors.New("Invalid memory limit. Value must be at least 6MB")
+	}
+	if payload.MemoryReservation != nil && *payload.MemoryReservation < 0 {
+		return errors.New("Invalid memory reservation. Value must be positive")
+	}
+	if payload.MemorySwap != nil && *payload.MemorySwap < -1 {
+		return errors.New("Invalid memory swap limit. Value must be -1 or positive")
+	}
+	if payload.RestartPolicy != nil {
+		policy := payload.RestartPolicy
+		if !policy.IsNone() && !policy.IsAlways() && !policy.IsOnFailure() && !policy.IsUnlessStopped() {
+			return errors.New("Invalid restart policy. Value must be one of: no, always, on-failure or unless-stopped")
+		}
+		if policy.MaximumRetryCount < 0 || policy.MaximumRetryCount > 0 && !policy.IsOnFailure() {
+			return errors.New("Invalid restart policy maximum retry count. A positive count can only be used with the on-failure policy")
+		}
+	}
+	return nil
+}
+
+// POST request on /api/endpoints/:id/containers/:containerId/limits?nodeName=<nodeName>
+//
+// Updates the CPU and memory limits and the restart policy of a container without recreating it.
+// The limits are validated against the capacity of the host and the limits supported by its daemon.
+// The limits cannot be removed, the Docker daemon ignores the updates of the limits to 0.
+func (handler *Handler) containerLimitsUpdate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	var payload containerLimitsPayload
+	err := request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	endpoint, source, httpErr := handler.retrieveAccessibleContainer(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	nodeName, _ := request.RetrieveQueryParameter(r, "nodeName", true)
+
+	cli, err := handler.DockerClientFactory.CreateClient(endpoint, nodeName)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to create Docker client", err}
+	}
+	defer cli.Close()
+
+	info, err := cli.Info(context.Background())
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve engine information", err}
+	}
+
+	updateConfig := newContainerUpdateConfig(&payload, source.HostConfig)
+
+	err = validateContainerLimits(&payload, &updateConfig.Resources, source.HostConfig, &info)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid container limits", err}
+	}
+
+	body, err := cli.ContainerUpdate(context.Background(), source.ID, *updateConfig)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to update the container limits", err}
+	}
+
+	updated, err := cli.ContainerInspect(context.Background(), source.ID)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to inspect the updated container", err}
+	}
+
+	warnings := body.Warnings
+	if warnings == nil {
+		warnings = []string{}
+	}
+
+	return response.JSON(w, &containerLimitsResponse{
+		CPUs:              docker.ContainerCPULimit(updated.HostConfig.Resources, info.NCPU),
+		Memory:            updated.HostConfig.Memory,
+		MemoryReservation: updated.HostConfig.MemoryReservation,
+		MemorySwap:        updated.HostConfig.MemorySwap,
+		RestartPolicy:     updated.HostConfig.RestartPolicy,
+		Warnings:          warnings,
+	})
+}
+
+// newContainerUpdateConfig creates the update of the limits of a container. A CPU limit is expressed
+// as a CFS quota when the container already uses a CFS period, as the Docker daemon rejects a number
+// of CPUs combined with a period.
+func newContainerUpdateConfig(payload *containerLimitsPayload, hostConfig *container.HostConfig) *container.UpdateConfig {
+	updateConfig := &container.UpdateConfig{}
+
+	if payload.CPUs != nil {
+		if hostConfig.CPUPeriod > 0 || hostConfig.CPUQuota > 0 {
+			updateConfig.CPUPeriod = cpuPeriod
+			updateConfig.CPUQuota = int64(*payload.CPUs * cpuPeriod)
+		} else {
+			updateConfig.NanoCPUs = int64(*payload.CPUs * 1e9)
+		}
+	}
+
+	if payload.Memory != nil {
+		updateConfig.Memory = *payload.Memory
+	}
+
+	if payload.MemoryReservation != nil {
+		updateConfig.MemoryReservation = *payload.MemoryReservation
+	}
+
+	if payload.MemorySwap != nil {
+		updateConfig.MemorySwap = *payload.MemorySwap
+	}
+
+	if payload.RestartPolicy != nil {
+		updateConfig.RestartPolicy = *payload.RestartPolicy
+	}
+
+	return updateConfig
+}
+
+// validateContainerLimits ensures that the limits fit in the capacity of the host, are supported by its daemon
+// and are consistent with the current limits of the container
+func validateContainerLimits(payload *containerLimitsPayload, resources *container.Resources, hostConfig *container.HostConfig, info *types.Info) error {
+	hasResources := payload.CPUs != nil || payload.Memory != nil || payload.MemoryReservation != nil || payload.MemorySwap != nil
+	if hasResources && info.OSType == docker.OSTypeWindows {
+		return errors.New("the resource limits of the containers of Windows hosts cannot be updated")
+	}
+
+	if payload.CPUs != nil && *payload.CPUs > float64(info.NCPU) {
+		return fmt.Errorf("the CPU limit exceeds the %d CPUs of the host", info.NCPU)
+	}
+
+	if payload.Memory != nil && *payload.Memory > info.MemTotal {
+		return fmt.Errorf("the memory limit exceeds the %d bytes of memory of the host", info.MemTotal)
+	}
+
+	memory := hostConfig.Memory
+	if payload.Memory != nil {
+		memory = *payload.Memory
+	}
+
+	reservation := hostConfig.MemoryReservation
+	if payload.MemoryReservation != nil {
+		reservation = *payload.MemoryReservation
+	}
+	if memory > 0 && reservation > memory {
+		return errors.New("the memory reservation must be lower than the memory limit")
+	}
+
+	swap := hostConfig.MemorySwap
+	if payload.MemorySwap != nil {
+		swap = *payload.MemorySwap
+	}
+	if swap > 0 && (memory == 0 || swap < memory) {
+		return errors.New("the memory swap limit includes the memory limit, it must be greater than the memory limit")
+	}
+
+	return docker.ValidateResourceLimits(resources, docker.RuntimeCapabilities(info))
+}
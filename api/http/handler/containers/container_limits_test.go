@@ -0,0 +1,53 @@
+package containers
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+)
+
+func TestValidateContainerLimits(t *testing.T) {
+	cpus := func(value float64) *float64 { return &value }
+	bytes := func(value int64) *int64 { return &value }
+
+	info := &types.Info{OSType: "linux", NCPU: 4, MemTotal: 8 << 30, MemoryLimit: true, SwapLimit: true, CPUCfsPeriod: true, CPUCfsQuota: true}
+
+	tests := []struct {
+		name       string
+		payload    containerLimitsPayload
+		hostConfig container.HostConfig
+		valid      bool
+	}{
+		{"CPU limit", containerLimitsPayload{CPUs: cpus(2)}, container.HostConfig{}, true},
+		{"CPU limit above the host CPUs", containerLimitsPayload{CPUs: cpus(8)}, container.HostConfig{}, false},
+		{"memory limit above the host memory", containerLimitsPayload{Memory: bytes(16 << 30)}, container.HostConfig{}, false},
+		{"memory limit above the current reservation", containerLimitsPayload{Memory: bytes(1 << 30)}, container.HostConfig{Resources: container.Resources{MemoryReservation: 512 << 20}}, true},
+		{"memory limit below the current reservation", containerLimitsPayload{Memory: bytes(256 << 20)}, container.HostConfig{Resources: container.Resources{MemoryReservation: 512 << 20}}, false},
+		{"memory limit above the current swap limit", containerLimitsPayload{Memory: bytes(2 << 30)}, container.HostConfig{Resources: container.Resources{Memory: 1 << 30, MemorySwap: 1536 << 20}}, false},
+		{"memory and swap limits", containerLimitsPayload{Memory: bytes(2 << 30), MemorySwap: bytes(3 << 30)}, container.HostConfig{Resources: container.Resources{Memory: 1 << 30, MemorySwap: 1536 << 20}}, true},
+	}
+
+	for _, test := range tests {
+		updateConfig := newContainerUpdateConfig(&test.payload, &test.hostConfig)
+		err := validateContainerLimits(&test.payload, &updateConfig.Resources, &test.hostConfig, info)
+		if (err == nil) != test.valid {
+			t.Errorf("%s: got error %v, want valid %t", test.name, err, test.valid)
+		}
+	}
+}
+
+func TestNewContainerUpdateConfig(t *testing.T) {
+	cpus := 1.5
+
+	updateConfig := newContainerUpdateConfig(&containerLimitsPayload{CPUs: &cpus}, &container.HostConfig{})
+	if updateConfig.NanoCPUs != 1500000000 || updateConfig.CPUQuota != 0 {
+		t.Errorf("expected the CPU limit to be expressed in nano CPUs, got %+v", updateConfig.Resources)
+	}
+
+	hostConfig := &container.HostConfig{Resources: container.Resources{CPUPeriod: 50000, CPUQuota: 25000}}
+	updateConfig = newContainerUpdateConfig(&containerLimitsPayload{CPUs: &cpus}, hostConfig)
+	if updateConfig.NanoCPUs != 0 || updateConfig.CPUPeriod != 100000 || updateConfig.CPUQuota != 150000 {
+		t.Errorf("expected the CPU limit to be expressed as a CFS quota, got %+v", updateConfig.Resources)
+	}
+}
@@ -0,0 +1,35 @@
+package containers
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api/docker"
+)
+
+// GET request on /api/endpoints/:id/containers/:containerId/stats?nodeName=<nodeName>
+//
+// Returns a single sample of the resource usage of the container, normalized across Linux and Windows hosts.
+func (handler *Handler) containerStats(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	endpoint, source, httpErr := handler.retrieveAccessibleContainer(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	nodeName, _ := request.RetrieveQueryParameter(r, "nodeName", true)
+
+	cli, err := handler.DockerClientFactory.CreateClient(endpoint, nodeName)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to create Docker client", err}
+	}
+	defer cli.Close()
+
+	stats, err := docker.RetrieveContainerStats(cli, source.ID)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve the container statistics", err}
+	}
+
+	return response.JSON(w, stats)
+}
@@ -0,0 +1,160 @@
+package containers
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+	"github.com/portainer/portainer/api/docker"
+	httperrors "github.com/portainer/portainer/api/http/errors"
+	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/authorization"
+)
+
+// containerTemplate represents a payload that can be used to create a container
+// based on the configuration of an existing container
+type containerTemplate struct {
+	Name             string                    `json:"Name"`
+	Config           *container.Config         `json:"Config"`
+	HostConfig       *container.HostConfig     `json:"HostConfig"`
+	NetworkingConfig *network.NetworkingConfig `json:"NetworkingConfig"`
+}
+
+// GET request on /api/endpoints/:id/containers/:containerId/template?nodeName=<nodeName>
+func (handler *Handler) containerTemplate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	_, source, httpErr := handler.retrieveAccessibleContainer(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	return response.JSON(w, newContainerTemplate(source))
+}
+
+// retrieveAccessibleContainer inspects the container referenced by the request route variables
+// and ensures that the user can access it.
+func (handler *Handler) retrieveAccessibleContainer(r *http.Request) (*portainer.Endpoint, *types.ContainerJSON, *httperror.HandlerError) {
+	endpointID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return nil, nil, &httperror.HandlerError{http.StatusBadRequest, "Invalid endpoint identifier route variable", err}
+	}
+
+	containerID, err := request.RetrieveRouteVariableValue(r, "containerId")
+	if err != nil {
+		return nil, nil, &httperror.HandlerError{http.StatusBadRequest, "Invalid container identifier route variable", err}
+	}
+
+	nodeName, _ := request.RetrieveQueryParameter(r, "nodeName", true)
+
+	endpoint, err := handler.DataStore.Endpoint().Endpoint(portainer.EndpointID(endpointID))
+	if err == bolterrors.ErrObjectNotFound {
+		return nil, nil, &httperror.HandlerError{http.StatusNotFound, "Unable to find an endpoint with the specified identifier inside the database", err}
+	} else if err != nil {
+		return nil, nil, &httperror.HandlerError{http.StatusInternalServerError, "Unable to find an endpoint with the specified identifier inside the database", err}
+	}
+
+	err = handler.requestBouncer.AuthorizedEndpointOperation(r, endpoint)
+	if err != nil {
+		return nil, nil, &httperror.HandlerError{http.StatusForbidden, "Permission denied to access endpoint", err}
+	}
+
+	securityContext, err := security.RetrieveRestrictedRequestContext(r)
+	if err != nil {
+		return nil, nil, &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve info from request context", err}
+	}
+
+	cli, err := handler.DockerClientFactory.CreateClient(endpoint, nodeName)
+	if err != nil {
+		return nil, nil, &httperror.HandlerError{http.StatusInternalServerError, "Unable to create Docker client", err}
+	}
+	defer cli.Close()
+
+	source, err := cli.ContainerInspect(context.Background(), containerID)
+	if err != nil {
+		return nil, nil, &httperror.HandlerError{http.StatusNotFound, "Unable to find a container with the specified identifier", err}
+	}
+
+	hiddenContainers, err := handler.hiddenContainerRules(securityContext)
+	if err != nil {
+		return nil, nil, &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve settings from the database", err}
+	}
+
+	if hiddenContainers != nil && hiddenContainers.IsHidden([]string{source.Name}, source.Config.Labels) {
+		return nil, nil, &httperror.HandlerError{http.StatusNotFound, "Unable to find a container with the specified identifier", errContainerNotFound}
+	}
+
+	resourceControls, err := handler.DataStore.ResourceControl().ResourceControls()
+	if err != nil {
+		return nil, nil, &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve resource controls from the database", err}
+	}
+
+	resourceControl := docker.ResourceControlForResource(source.ID, portainer.ContainerResourceControl, source.Config.Labels, resourceControls)
+	if !authorization.AdministratorOrUserCanAccessResource(securityContext.IsAdmin, securityContext.UserID, securityContext.UserMemberships, resourceControl) {
+		return nil, nil, &httperror.HandlerError{http.StatusForbidden, "Access denied to resource", httperrors.ErrResourceAccessDenied}
+	}
+
+	return endpoint, &source, nil
+}
+
+// newContainerTemplate creates a container template from the configuration of an existing container.
+// Settings that are specific to the existing container instance (default hostname, MAC address, static IP addresses,
+// Compose project labels) are removed so that the template can be used to create a new container.
+func newContainerTemplate(source *types.ContainerJSON) *containerTemplate {
+	config := *source.Config
+	hostConfig := *source.HostConfig
+
+	if config.Hostname == shortContainerID(source.ID) {
+		config.Hostname = ""
+	}
+	config.MacAddress = ""
+
+	labels := make(map[string]string)
+	for key, value := range config.Labels {
+		if strings.HasPrefix(key, "com.docker.compose.") {
+			continue
+		}
+		labels[key] = value
+	}
+	config.Labels = labels
+
+	networkingConfig := &network.NetworkingConfig{
+		EndpointsConfig: make(map[string]*network.EndpointSettings),
+	}
+
+	if source.NetworkSettings != nil {
+		for networkName, settings := range source.NetworkSettings.Networks {
+			aliases := make([]string, 0)
+			for _, alias := range settings.Aliases {
+				if alias != shortContainerID(source.ID) {
+					aliases = append(aliases, alias)
+				}
+			}
+
+			networkingConfig.EndpointsConfig[networkName] = &network.EndpointSettings{
+				Links:   settings.Links,
+				Aliases: aliases,
+			}
+		}
+	}
+
+	return &containerTemplate{
+		Name:             strings.TrimPrefix(source.Name, "/"),
+		Config:           &config,
+		HostConfig:       &hostConfig,
+		NetworkingConfig: networkingConfig,
+	}
+}
+
+func shortContainerID(containerID string) string {
+	if len(containerID) > 12 {
+		return containerID[:12]
+	}
+	return containerID
+}
@@ -0,0 +1,93 @@
+package containers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/gorilla/mux"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/docker"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+// errContainerNotFound is returned for the containers hidden from the user, as the Docker API proxy does
+var errContainerNotFound = errors.New("No such container")
+
+// Handler is the HTTP handler used to handle container operations that cannot be
+// expressed as a single Docker API call.
+type Handler struct {
+	*mux.Router
+	requestBouncer      *security.RequestBouncer
+	DataStore           portainer.DataStore
+	DockerClientFactory *docker.ClientFactory
+	FileService         portainer.FileService
+}
+
+// NewHandler creates a handler to manage container operations.
+func NewHandler(bouncer *security.RequestBouncer) *Handler {
+	h := &Handler{
+		Router:         mux.NewRouter(),
+		requestBouncer: bouncer,
+	}
+	h.Handle("/endpoints/{id}/containers/bulk",
+		bouncer.RestrictedAccess(bouncer.ChangeApproval(portainer.ChangeRequestContainerDeletion, containerBulkRemovalEndpoint,
+			httperror.LoggerHandler(h.containerBulk)))).Methods(http.MethodPost)
+	h.Handle("/endpoints/{id}/containers/{containerId}/template",
+		bouncer.RestrictedAccess(httperror.LoggerHandler(h.containerTemplate))).Methods(http.MethodGet)
+	h.Handle("/endpoints/{id}/containers/{containerId}/clone",
+		bouncer.RestrictedAccess(httperror.LoggerHandler(h.containerClone))).Methods(http.MethodPost)
+	h.Handle("/endpoints/{id}/containers/{containerId}/health",
+		bouncer.RestrictedAccess(httperror.LoggerHandler(h.containerHealthInspect))).Methods(http.MethodGet)
+	h.Handle("/endpoints/{id}/containers/{containerId}/limits",
+		bouncer.RestrictedAccess(httperror.LoggerHandler(h.containerLimitsUpdate))).Methods(http.MethodPost)
+	h.Handle("/endpoints/{id}/containers/{containerId}/stats",
+		bouncer.RestrictedAccess(httperror.LoggerHandler(h.containerStats))).Methods(http.MethodGet)
+	return h
+}
+
+// hiddenContainerRules returns the rules hiding containers from the user, nil is returned for the
+// administrators and when no container is hidden
+func (handler *Handler) hiddenContainerRules(securityContext *security.RestrictedRequestContext) (*docker.HiddenContainerRules, error) {
+	if securityContext.IsAdmin {
+		return nil, nil
+	}
+
+	settings, err := handler.DataStore.Settings().Settings()
+	if err != nil {
+		return nil, err
+	}
+
+	rules := docker.NewHiddenContainerRules(settings)
+	if rules.Empty() {
+		return nil, nil
+	}
+	return rules, nil
+}
+
+// validateHostConfigForRegularUsers ensures that the host configuration of a container only
+// uses the features that regular users are allowed to use, as defined in the settings.
+func validateHostConfigForRegularUsers(hostConfig *container.HostConfig, settings *portainer.Settings) error {
+	if !settings.AllowPrivilegedModeForRegularUsers && hostConfig.Privileged {
+		return errors.New("forbidden to use privileged mode")
+	}
+
+	if !settings.AllowHostNamespaceForRegularUsers && hostConfig.PidMode.IsHost() {
+		return errors.New("forbidden to use pid host namespace")
+	}
+
+	if !settings.AllowDeviceMappingForRegularUsers && len(hostConfig.Devices) > 0 {
+		return errors.New("forbidden to use device mapping")
+	}
+
+	if !settings.AllowContainerCapabilitiesForRegularUsers && (len(hostConfig.CapAdd) > 0 || len(hostConfig.CapDrop) > 0) {
+		return errors.New("forbidden to use container capabilities")
+	}
+
+	if !settings.AllowBindMountsForRegularUsers && len(hostConfig.Binds) > 0 {
+		return errors.New("forbidden to use bind mounts")
+	}
+
+	return nil
+}
@@ -46,6 +46,11 @@ func (handler *Handler) customTemplateDelete(w http.ResponseWriter, r *http.Requ
 		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to remove the custom template from the database", err}
 	}
 
+	err = handler.DataStore.CustomTemplateRevision().DeleteCustomTemplateRevisions(customTemplate.ID)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to remove the revisions of the custom template from the database", err}
+	}
+
 	err = handler.FileService.RemoveDirectory(customTemplate.ProjectPath)
 	if err != nil {
 		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to remove custom template files from disk", err}
@@ -36,7 +36,7 @@ func (handler *Handler) customTemplateInspect(w http.ResponseWriter, r *http.Req
 		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve a resource control associated to the custom template", err}
 	}
 
-	access := userCanEditTemplate(customTemplate, securityContext)
+	access := userCanAccessTemplate(*customTemplate, securityContext, resourceControl)
 	if !access {
 		return &httperror.HandlerError{http.StatusForbidden, "Access denied to resource", httperrors.ErrResourceAccessDenied}
 	}
@@ -11,6 +11,9 @@ import (
 	"github.com/portainer/portainer/api/internal/authorization"
 )
 
+// GET request on /api/custom_templates?type=<type>&publicationStatus=<status>
+// The publicationStatus query parameter lists the templates with a publication in the specified status,
+// such as the publications waiting for a review.
 func (handler *Handler) customTemplateList(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
 	customTemplates, err := handler.DataStore.CustomTemplate().CustomTemplates()
 	if err != nil {
@@ -28,11 +31,16 @@ func (handler *Handler) customTemplateList(w http.ResponseWriter, r *http.Reques
 
 	customTemplates = filterTemplatesByEngineType(customTemplates, portainer.StackType(stackType))
 
+	publicationStatus, _ := request.RetrieveNumericQueryParameter(r, "publicationStatus", true)
+	customTemplates = filterTemplatesByPublicationStatus(customTemplates, portainer.CustomTemplatePublicationStatus(publicationStatus))
+
 	securityContext, err := security.RetrieveRestrictedRequestContext(r)
 	if err != nil {
 		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve info from request context", err}
 	}
 
+	customTemplates = filterTemplatesByOrganization(customTemplates, securityContext)
+
 	if !securityContext.IsAdmin {
 		user, err := handler.DataStore.User().User(securityContext.UserID)
 		if err != nil {
@@ -50,6 +58,21 @@ func (handler *Handler) customTemplateList(w http.ResponseWriter, r *http.Reques
 	return response.JSON(w, customTemplates)
 }
 
+func filterTemplatesByOrganization(templates []portainer.CustomTemplate, securityContext *security.RestrictedRequestContext) []portainer.CustomTemplate {
+	if security.IsInstanceAdmin(securityContext) {
+		return templates
+	}
+
+	filteredTemplates := []portainer.CustomTemplate{}
+	for _, template := range templates {
+		if security.AuthorizedOrganizationAccess(template.OrganizationID, securityContext.OrganizationID, securityContext.IsAdmin) {
+			filteredTemplates = append(filteredTemplates, template)
+		}
+	}
+
+	return filteredTemplates
+}
+
 func filterTemplatesByEngineType(templates []portainer.CustomTemplate, stackType portainer.StackType) []portainer.CustomTemplate {
 	if stackType == 0 {
 		return templates
@@ -65,3 +88,19 @@ func filterTemplatesByEngineType(templates []portainer.CustomTemplate, stackType
 
 	return filteredTemplates
 }
+
+func filterTemplatesByPublicationStatus(templates []portainer.CustomTemplate, status portainer.CustomTemplatePublicationStatus) []portainer.CustomTemplate {
+	if status == 0 {
+		return templates
+	}
+
+	filteredTemplates := []portainer.CustomTemplate{}
+
+	for _, template := range templates {
+		if template.Publication != nil && template.Publication.Status == status {
+			filteredTemplates = append(filteredTemplates, template)
+		}
+	}
+
+	return filteredTemplates
+}
@@ -0,0 +1,200 @@
+package customtemplates
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+var (
+	errPublicationNotSubmitted = errors.New("The custom template was not submitted for publication")
+	errPublicationNotPending   = errors.New("The publication of the custom template is not waiting for a review")
+	errNotTeamMember           = errors.New("The user is not a member of the team")
+)
+
+type customTemplatePublicationSubmitPayload struct {
+	// TeamID is the team the template is submitted on behalf of, it is optional
+	TeamID portainer.TeamID
+	Note   string
+}
+
+func (payload *customTemplatePublicationSubmitPayload) Validate(r *http.Request) error {
+	return nil
+}
+
+type customTemplatePublicationReviewPayload struct {
+	Approved bool
+	Comment  string
+}
+
+func (payload *customTemplatePublicationReviewPayload) Validate(r *http.Request) error {
+	if !payload.Approved && payload.Comment == "" {
+		return errors.New("A comment is required to reject a publication")
+	}
+	return nil
+}
+
+// POST request on /api/custom_templates/:id/publication
+//
+// Submits a custom template for global visibility. The template is listed for all the users once
+// the publication is approved by an administrator.
+func (handler *Handler) customTemplatePublicationSubmit(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	customTemplate, handlerErr := handler.retrieveEditableTemplate(r)
+	if handlerErr != nil {
+		return handlerErr
+	}
+
+	var payload customTemplatePublicationSubmitPayload
+	err := request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	securityContext, err := security.RetrieveRestrictedRequestContext(r)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve info from request context", err}
+	}
+
+	tokenData, err := security.RetrieveTokenData(r)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve user details from authentication token", err}
+	}
+
+	publication := &portainer.CustomTemplatePublication{
+		Status:         portainer.CustomTemplatePublicationPending,
+		SubmittedBy:    tokenData.ID,
+		SubmitterName:  tokenData.Username,
+		SubmissionDate: time.Now().Unix(),
+		Note:           payload.Note,
+	}
+
+	if payload.TeamID != 0 {
+		team, err := handler.DataStore.Team().Team(payload.TeamID)
+		if err == bolterrors.ErrObjectNotFound {
+			return &httperror.HandlerError{http.StatusNotFound, "Unable to find a team with the specified identifier inside the database", err}
+		} else if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a team with the specified identifier inside the database", err}
+		}
+
+		if !securityContext.IsAdmin && !isTeamMember(securityContext, team.ID) {
+			return &httperror.HandlerError{http.StatusForbidden, "Templates can only be submitted on behalf of the teams of the user", errNotTeamMember}
+		}
+
+		publication.TeamID = team.ID
+		publication.TeamName = team.Name
+	}
+
+	customTemplate.Publication = publication
+
+	err = handler.DataStore.CustomTemplate().UpdateCustomTemplate(customTemplate.ID, customTemplate)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist custom template changes inside the database", err}
+	}
+
+	return response.JSON(w, customTemplate)
+}
+
+// PUT request on /api/custom_templates/:id/publication
+// Approves or rejects a publication waiting for a review.
+func (handler *Handler) customTemplatePublicationReview(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	customTemplateID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid Custom template identifier route variable", err}
+	}
+
+	var payload customTemplatePublicationReviewPayload
+	err = request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	customTemplate, err := handler.DataStore.CustomTemplate().CustomTemplate(portainer.CustomTemplateID(customTemplateID))
+	if err == bolterrors.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find a custom template with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a custom template with the specified identifier inside the database", err}
+	}
+
+	if customTemplate.Publication == nil {
+		return &httperror.HandlerError{http.StatusConflict, "The custom template was not submitted for publication", errPublicationNotSubmitted}
+	}
+	if customTemplate.Publication.Status != portainer.CustomTemplatePublicationPending {
+		return &httperror.HandlerError{http.StatusConflict, "The publication of the custom template is not waiting for a review", errPublicationNotPending}
+	}
+
+	tokenData, err := security.RetrieveTokenData(r)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve user details from authentication token", err}
+	}
+
+	publication := customTemplate.Publication
+	publication.Status = portainer.CustomTemplatePublicationRejected
+	if payload.Approved {
+		publication.Status = portainer.CustomTemplatePublicationApproved
+	}
+	publication.ReviewedBy = tokenData.ID
+	publication.ReviewerName = tokenData.Username
+	publication.ReviewDate = time.Now().Unix()
+	publication.ReviewComment = payload.Comment
+
+	err = handler.DataStore.CustomTemplate().UpdateCustomTemplate(customTemplate.ID, customTemplate)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist custom template changes inside the database", err}
+	}
+
+	return response.JSON(w, customTemplate)
+}
+
+// DELETE request on /api/custom_templates/:id/publication
+// Withdraws the publication of a custom template, the template is no longer visible to all the users.
+func (handler *Handler) customTemplatePublicationDelete(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	customTemplate, handlerErr := handler.retrieveEditableTemplate(r)
+	if handlerErr != nil {
+		return handlerErr
+	}
+
+	if customTemplate.Publication == nil {
+		return &httperror.HandlerError{http.StatusNotFound, "The custom template was not submitted for publication", errPublicationNotSubmitted}
+	}
+
+	customTemplate.Publication = nil
+
+	err := handler.DataStore.CustomTemplate().UpdateCustomTemplate(customTemplate.ID, customTemplate)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist custom template changes inside the database", err}
+	}
+
+	return response.Empty(w)
+}
+
+// resubmitPublication puts an approved publication back in review when the template is changed by a
+// non-administrator user, so that the changes of a published template are always reviewed
+func resubmitPublication(customTemplate *portainer.CustomTemplate, securityContext *security.RestrictedRequestContext) {
+	publication := customTemplate.Publication
+	if securityContext.IsAdmin || publication == nil || publication.Status != portainer.CustomTemplatePublicationApproved {
+		return
+	}
+
+	publication.Status = portainer.CustomTemplatePublicationPending
+	publication.SubmissionDate = time.Now().Unix()
+	publication.ReviewedBy = 0
+	publication.ReviewerName = ""
+	publication.ReviewDate = 0
+	publication.ReviewComment = ""
+}
+
+func isTeamMember(securityContext *security.RestrictedRequestContext, teamID portainer.TeamID) bool {
+	for _, membership := range securityContext.UserMemberships {
+		if membership.TeamID == teamID {
+			return true
+		}
+	}
+	return false
+}
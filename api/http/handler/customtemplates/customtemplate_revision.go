@@ -0,0 +1,268 @@
+package customtemplates
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"strconv"
+	"time"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+	httperrors "github.com/portainer/portainer/api/http/errors"
+	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/textdiff"
+)
+
+var errRevisionNotFound = errors.New("Unable to find the revision of the custom template")
+
+type revisionDiffResponse struct {
+	From int    `json:"From"`
+	To   int    `json:"To"`
+	Diff string `json:"Diff"`
+}
+
+// GET request on /api/custom_templates/:id/revisions
+// The revisions are returned without their file content, the most recent revision first.
+func (handler *Handler) customTemplateRevisionList(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	customTemplate, handlerErr := handler.retrieveEditableTemplate(r)
+	if handlerErr != nil {
+		return handlerErr
+	}
+
+	revisions, err := handler.DataStore.CustomTemplateRevision().CustomTemplateRevisions(customTemplate.ID)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve the revisions of the custom template from the database", err}
+	}
+
+	for idx := range revisions {
+		revisions[idx].FileContent = ""
+	}
+
+	return response.JSON(w, revisions)
+}
+
+// GET request on /api/custom_templates/:id/revisions/:revision
+func (handler *Handler) customTemplateRevisionInspect(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	customTemplate, handlerErr := handler.retrieveEditableTemplate(r)
+	if handlerErr != nil {
+		return handlerErr
+	}
+
+	revision, handlerErr := handler.retrieveRevision(r, customTemplate)
+	if handlerErr != nil {
+		return handlerErr
+	}
+
+	return response.JSON(w, revision)
+}
+
+// GET request on /api/custom_templates/:id/revisions/:revision/diff?compareTo=<revision>
+//
+// Returns the differences between the file of a revision and the file of the revision specified
+// by the compareTo query parameter, or the current file of the template when it is not specified.
+func (handler *Handler) customTemplateRevisionDiff(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	customTemplate, handlerErr := handler.retrieveEditableTemplate(r)
+	if handlerErr != nil {
+		return handlerErr
+	}
+
+	from, handlerErr := handler.retrieveRevision(r, customTemplate)
+	if handlerErr != nil {
+		return handlerErr
+	}
+
+	compareTo, err := request.RetrieveNumericQueryParameter(r, "compareTo", true)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid query parameter: compareTo", err}
+	}
+
+	diff := &revisionDiffResponse{From: from.Revision, To: compareTo}
+	toLabel := fmt.Sprintf("revision %d", compareTo)
+	toContent := ""
+
+	if compareTo == 0 {
+		fileContent, err := handler.FileService.GetFileContent(path.Join(customTemplate.ProjectPath, customTemplate.EntryPoint))
+		if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve custom template file from disk", err}
+		}
+		toLabel = "current"
+		toContent = string(fileContent)
+	} else {
+		to, err := handler.findRevision(customTemplate.ID, compareTo)
+		if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve the revisions of the custom template from the database", err}
+		}
+		if to == nil {
+			return &httperror.HandlerError{http.StatusNotFound, "Unable to find the revision to compare to", errRevisionNotFound}
+		}
+		toContent = to.FileContent
+	}
+
+	diff.Diff = textdiff.Unified(fmt.Sprintf("revision %d", from.Revision), toLabel, from.FileContent, toContent)
+	return response.JSON(w, diff)
+}
+
+// POST request on /api/custom_templates/:id/revisions/:revision/restore
+// The file of the revision becomes the file of the template, the restoration is recorded as a new revision.
+func (handler *Handler) customTemplateRevisionRestore(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	customTemplate, handlerErr := handler.retrieveEditableTemplate(r)
+	if handlerErr != nil {
+		return handlerErr
+	}
+
+	revision, handlerErr := handler.retrieveRevision(r, customTemplate)
+	if handlerErr != nil {
+		return handlerErr
+	}
+
+	tokenData, err := security.RetrieveTokenData(r)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve user details from authentication token", err}
+	}
+
+	_, err = handler.FileService.StoreCustomTemplateFileFromBytes(strconv.Itoa(int(customTemplate.ID)), customTemplate.EntryPoint, []byte(revision.FileContent))
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist updated custom template file on disk", err}
+	}
+
+	restored, err := handler.recordRevision(customTemplate.ID, revision.FileContent, tokenData, revision.Revision)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist the revision of the custom template inside the database", err}
+	}
+
+	securityContext, err := security.RetrieveRestrictedRequestContext(r)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve info from request context", err}
+	}
+
+	if customTemplate.Publication != nil {
+		resubmitPublication(customTemplate, securityContext)
+
+		err = handler.DataStore.CustomTemplate().UpdateCustomTemplate(customTemplate.ID, customTemplate)
+		if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist custom template changes inside the database", err}
+		}
+	}
+
+	return response.JSON(w, restored)
+}
+
+func (handler *Handler) retrieveEditableTemplate(r *http.Request) (*portainer.CustomTemplate, *httperror.HandlerError) {
+	customTemplateID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return nil, &httperror.HandlerError{http.StatusBadRequest, "Invalid Custom template identifier route variable", err}
+	}
+
+	customTemplate, err := handler.DataStore.CustomTemplate().CustomTemplate(portainer.CustomTemplateID(customTemplateID))
+	if err == bolterrors.ErrObjectNotFound {
+		return nil, &httperror.HandlerError{http.StatusNotFound, "Unable to find a custom template with the specified identifier inside the database", err}
+	} else if err != nil {
+		return nil, &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a custom template with the specified identifier inside the database", err}
+	}
+
+	securityContext, err := security.RetrieveRestrictedRequestContext(r)
+	if err != nil {
+		return nil, &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve info from request context", err}
+	}
+
+	if !userCanEditTemplate(customTemplate, securityContext) {
+		return nil, &httperror.HandlerError{http.StatusForbidden, "Access denied to resource", httperrors.ErrResourceAccessDenied}
+	}
+
+	return customTemplate, nil
+}
+
+func (handler *Handler) retrieveRevision(r *http.Request, customTemplate *portainer.CustomTemplate) (*portainer.CustomTemplateRevision, *httperror.HandlerError) {
+	number, err := request.RetrieveNumericRouteVariableValue(r, "revision")
+	if err != nil {
+		return nil, &httperror.HandlerError{http.StatusBadRequest, "Invalid revision route variable", err}
+	}
+
+	revision, err := handler.findRevision(customTemplate.ID, number)
+	if err != nil {
+		return nil, &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve the revisions of the custom template from the database", err}
+	}
+	if revision == nil {
+		return nil, &httperror.HandlerError{http.StatusNotFound, "Unable to find a revision with the specified number", errRevisionNotFound}
+	}
+
+	return revision, nil
+}
+
+// findRevision returns a revision of a custom template by number, nil is returned when the revision does not exist
+func (handler *Handler) findRevision(templateID portainer.CustomTemplateID, number int) (*portainer.CustomTemplateRevision, error) {
+	revisions, err := handler.DataStore.CustomTemplateRevision().CustomTemplateRevisions(templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	for idx := range revisions {
+		if revisions[idx].Revision == number {
+			return &revisions[idx], nil
+		}
+	}
+
+	return nil, nil
+}
+
+// recordRevision records a new revision of a custom template file. No revision is recorded when the
+// file content is the same as the content of the latest revision, unless it is a restoration.
+func (handler *Handler) recordRevision(templateID portainer.CustomTemplateID, fileContent string, tokenData *portainer.TokenData, restoredFrom int) (*portainer.CustomTemplateRevision, error) {
+	revisions, err := handler.DataStore.CustomTemplateRevision().CustomTemplateRevisions(templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	number := 1
+	if len(revisions) > 0 {
+		if restoredFrom == 0 && revisions[0].FileContent == fileContent {
+			return &revisions[0], nil
+		}
+		number = revisions[0].Revision + 1
+	}
+
+	revision := &portainer.CustomTemplateRevision{
+		CustomTemplateID: templateID,
+		Revision:         number,
+		UserID:           tokenData.ID,
+		Username:         tokenData.Username,
+		Time:             time.Now().Unix(),
+		RestoredFrom:     restoredFrom,
+		FileContent:      fileContent,
+	}
+
+	err = handler.DataStore.CustomTemplateRevision().CreateCustomTemplateRevision(revision)
+	if err != nil {
+		return nil, err
+	}
+
+	return revision, nil
+}
+
+// recordInitialRevision records the current file of a template created before the revisions were kept,
+// the revision is attributed to the author of the template
+func (handler *Handler) recordInitialRevision(customTemplate *portainer.CustomTemplate) error {
+	revisions, err := handler.DataStore.CustomTemplateRevision().CustomTemplateRevisions(customTemplate.ID)
+	if err != nil || len(revisions) > 0 {
+		return err
+	}
+
+	fileContent, err := handler.FileService.GetFileContent(path.Join(customTemplate.ProjectPath, customTemplate.EntryPoint))
+	if err != nil {
+		return err
+	}
+
+	author := &portainer.TokenData{ID: customTemplate.CreatedByUserID}
+	user, err := handler.DataStore.User().User(customTemplate.CreatedByUserID)
+	if err == nil {
+		author.Username = user.Username
+	}
+
+	_, err = handler.recordRevision(customTemplate.ID, string(fileContent), author, 0)
+	return err
+}
@@ -35,15 +35,36 @@ func NewHandler(bouncer *security.RequestBouncer) *Handler {
 		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.customTemplateUpdate))).Methods(http.MethodPut)
 	h.Handle("/custom_templates/{id}",
 		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.customTemplateDelete))).Methods(http.MethodDelete)
+	h.Handle("/custom_templates/{id}/publication",
+		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.customTemplatePublicationSubmit))).Methods(http.MethodPost)
+	h.Handle("/custom_templates/{id}/publication",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.customTemplatePublicationReview))).Methods(http.MethodPut)
+	h.Handle("/custom_templates/{id}/publication",
+		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.customTemplatePublicationDelete))).Methods(http.MethodDelete)
+	h.Handle("/custom_templates/{id}/revisions",
+		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.customTemplateRevisionList))).Methods(http.MethodGet)
+	h.Handle("/custom_templates/{id}/revisions/{revision}",
+		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.customTemplateRevisionInspect))).Methods(http.MethodGet)
+	h.Handle("/custom_templates/{id}/revisions/{revision}/diff",
+		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.customTemplateRevisionDiff))).Methods(http.MethodGet)
+	h.Handle("/custom_templates/{id}/revisions/{revision}/restore",
+		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.customTemplateRevisionRestore))).Methods(http.MethodPost)
 	return h
 }
 
 func userCanEditTemplate(customTemplate *portainer.CustomTemplate, securityContext *security.RestrictedRequestContext) bool {
+	if !security.AuthorizedOrganizationAccess(customTemplate.OrganizationID, securityContext.OrganizationID, securityContext.IsAdmin) {
+		return false
+	}
 	return securityContext.IsAdmin || customTemplate.CreatedByUserID == securityContext.UserID
 }
 
 func userCanAccessTemplate(customTemplate portainer.CustomTemplate, securityContext *security.RestrictedRequestContext, resourceControl *portainer.ResourceControl) bool {
-	if securityContext.IsAdmin || customTemplate.CreatedByUserID == securityContext.UserID {
+	if !security.AuthorizedOrganizationAccess(customTemplate.OrganizationID, securityContext.OrganizationID, securityContext.IsAdmin) {
+		return false
+	}
+
+	if securityContext.IsAdmin || customTemplate.CreatedByUserID == securityContext.UserID || authorization.CustomTemplatePublished(&customTemplate) {
 		return true
 	}
 
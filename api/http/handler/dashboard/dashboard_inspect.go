@@ -0,0 +1,161 @@
+package dashboard
+
+import (
+	"log"
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/docker"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+type (
+	dashboard struct {
+		Endpoints  endpointSummary  `json:"Endpoints"`
+		Containers containerSummary `json:"Containers"`
+		Services   int              `json:"Services"`
+		Stacks     int              `json:"Stacks"`
+		Volumes    int              `json:"Volumes"`
+		Images     int              `json:"Images"`
+		// ImagesSize is the total size in bytes of the images of all the endpoints
+		ImagesSize  int64 `json:"ImagesSize"`
+		Nodes       int   `json:"Nodes"`
+		TotalCPU    int64 `json:"TotalCPU"`
+		TotalMemory int64 `json:"TotalMemory"`
+		// UnhealthyEndpoints lists the endpoints running unhealthy containers
+		UnhealthyEndpoints []unhealthyEndpoint `json:"UnhealthyEndpoints"`
+		// OldestSnapshot is the time of the oldest snapshot used to compute the dashboard
+		OldestSnapshot int64 `json:"OldestSnapshot"`
+	}
+
+	endpointSummary struct {
+		Total      int `json:"Total"`
+		Up         int `json:"Up"`
+		Down       int `json:"Down"`
+		Docker     int `json:"Docker"`
+		Kubernetes int `json:"Kubernetes"`
+		Azure      int `json:"Azure"`
+		Edge       int `json:"Edge"`
+	}
+
+	containerSummary struct {
+		Total     int `json:"Total"`
+		Running   int `json:"Running"`
+		Stopped   int `json:"Stopped"`
+		Healthy   int `json:"Healthy"`
+		Unhealthy int `json:"Unhealthy"`
+	}
+
+	unhealthyEndpoint struct {
+		ID                      portainer.EndpointID `json:"Id"`
+		Name                    string               `json:"Name"`
+		UnhealthyContainerCount int                  `json:"UnhealthyContainerCount"`
+	}
+)
+
+// GET request on /api/dashboard
+//
+// Aggregates the latest snapshot of every endpoint the user can access so that the home view
+// does not need to query each endpoint.
+func (handler *Handler) dashboardInspect(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	securityContext, err := security.RetrieveRestrictedRequestContext(r)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve info from request context", err}
+	}
+
+	endpoints, err := handler.DataStore.Endpoint().Endpoints()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve endpoints from the database", err}
+	}
+
+	endpointGroups, err := handler.DataStore.EndpointGroup().EndpointGroups()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve endpoint groups from the database", err}
+	}
+
+	endpoints = security.FilterEndpoints(endpoints, endpointGroups, securityContext)
+
+	result := &dashboard{
+		UnhealthyEndpoints: make([]unhealthyEndpoint, 0),
+	}
+
+	for idx := range endpoints {
+		aggregateEndpoint(result, &endpoints[idx])
+	}
+
+	return response.JSON(w, result)
+}
+
+func aggregateEndpoint(result *dashboard, endpoint *portainer.Endpoint) {
+	result.Endpoints.Total++
+	if endpoint.Status == portainer.EndpointStatusUp {
+		result.Endpoints.Up++
+	} else {
+		result.Endpoints.Down++
+	}
+
+	switch endpoint.Type {
+	case portainer.AzureEnvironment:
+		result.Endpoints.Azure++
+	case portainer.EdgeAgentOnDockerEnvironment, portainer.EdgeAgentOnKubernetesEnvironment:
+		result.Endpoints.Edge++
+	case portainer.KubernetesLocalEnvironment, portainer.AgentOnKubernetesEnvironment, portainer.KubeconfigKubernetesEnvironment:
+		result.Endpoints.Kubernetes++
+	default:
+		result.Endpoints.Docker++
+	}
+
+	if len(endpoint.Kubernetes.Snapshots) > 0 {
+		snapshot := &endpoint.Kubernetes.Snapshots[0]
+		result.Nodes += snapshot.NodeCount
+		result.TotalCPU += snapshot.TotalCPU
+		result.TotalMemory += snapshot.TotalMemory
+		updateOldestSnapshot(result, snapshot.Time)
+	}
+
+	if len(endpoint.Snapshots) == 0 {
+		return
+	}
+
+	snapshot := &endpoint.Snapshots[0]
+	updateOldestSnapshot(result, snapshot.Time)
+
+	result.Containers.Running += snapshot.RunningContainerCount
+	result.Containers.Stopped += snapshot.StoppedContainerCount
+	result.Containers.Healthy += snapshot.HealthyContainerCount
+	result.Containers.Unhealthy += snapshot.UnhealthyContainerCount
+	result.Containers.Total += snapshot.RunningContainerCount + snapshot.StoppedContainerCount
+	result.Services += snapshot.ServiceCount
+	result.Stacks += snapshot.StackCount
+	result.Volumes += snapshot.VolumeCount
+	result.Images += snapshot.ImageCount
+	result.Nodes += snapshot.NodeCount
+	result.TotalCPU += int64(snapshot.TotalCPU)
+	result.TotalMemory += snapshot.TotalMemory
+
+	if snapshot.UnhealthyContainerCount > 0 {
+		result.UnhealthyEndpoints = append(result.UnhealthyEndpoints, unhealthyEndpoint{
+			ID:                      endpoint.ID,
+			Name:                    endpoint.Name,
+			UnhealthyContainerCount: snapshot.UnhealthyContainerCount,
+		})
+	}
+
+	images, err := docker.SnapshotImages(snapshot)
+	if err != nil {
+		log.Printf("[WARN] [http,dashboard] [message: unable to decode snapshot images] [endpoint: %s] [error: %s]", endpoint.Name, err)
+		return
+	}
+
+	for _, image := range images {
+		result.ImagesSize += image.Size
+	}
+}
+
+func updateOldestSnapshot(result *dashboard, snapshotTime int64) {
+	if result.OldestSnapshot == 0 || snapshotTime < result.OldestSnapshot {
+		result.OldestSnapshot = snapshotTime
+	}
+}
@@ -0,0 +1,28 @@
+package dashboard
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+// Handler is the HTTP handler used to aggregate the information of all the endpoints.
+type Handler struct {
+	*mux.Router
+	requestBouncer *security.RequestBouncer
+	DataStore      portainer.DataStore
+}
+
+// NewHandler creates a handler to aggregate the information of all the endpoints.
+func NewHandler(bouncer *security.RequestBouncer) *Handler {
+	h := &Handler{
+		Router:         mux.NewRouter(),
+		requestBouncer: bouncer,
+	}
+	h.Handle("/dashboard",
+		bouncer.RestrictedAccess(httperror.LoggerHandler(h.dashboardInspect))).Methods(http.MethodGet)
+	return h
+}
@@ -0,0 +1,36 @@
+package debug
+
+import (
+	"expvar"
+	"net/http"
+	"net/http/pprof"
+
+	"github.com/gorilla/mux"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+// Handler is the HTTP handler used to expose the runtime profiling and debugging data.
+type Handler struct {
+	*mux.Router
+}
+
+// NewHandler creates a handler to expose the runtime profiling and debugging data.
+func NewHandler(bouncer *security.RequestBouncer) *Handler {
+	h := &Handler{
+		Router: mux.NewRouter(),
+	}
+	h.Handle("/debug/vars",
+		bouncer.InstanceAdminAccess(expvar.Handler())).Methods(http.MethodGet)
+	h.Handle("/debug/pprof/cmdline",
+		bouncer.InstanceAdminAccess(http.HandlerFunc(pprof.Cmdline))).Methods(http.MethodGet)
+	h.Handle("/debug/pprof/profile",
+		bouncer.InstanceAdminAccess(http.HandlerFunc(pprof.Profile))).Methods(http.MethodGet)
+	h.Handle("/debug/pprof/symbol",
+		bouncer.InstanceAdminAccess(http.HandlerFunc(pprof.Symbol))).Methods(http.MethodGet, http.MethodPost)
+	h.Handle("/debug/pprof/trace",
+		bouncer.InstanceAdminAccess(http.HandlerFunc(pprof.Trace))).Methods(http.MethodGet)
+	h.PathPrefix("/debug/pprof/").Handler(
+		bouncer.InstanceAdminAccess(http.HandlerFunc(pprof.Index))).Methods(http.MethodGet)
+
+	return h
+}
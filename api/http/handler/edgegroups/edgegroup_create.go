@@ -9,6 +9,7 @@ import (
 	"github.com/portainer/libhttp/request"
 	"github.com/portainer/libhttp/response"
 	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/internal/tag"
 )
 
 type edgeGroupCreatePayload struct {
@@ -17,14 +18,26 @@ type edgeGroupCreatePayload struct {
 	TagIDs       []portainer.TagID
 	Endpoints    []portainer.EndpointID
 	PartialMatch bool
+	Metadata     map[string]string
+	Expression   string
 }
 
 func (payload *edgeGroupCreatePayload) Validate(r *http.Request) error {
 	if govalidator.IsNull(payload.Name) {
 		return errors.New("Invalid Edge group name")
 	}
-	if payload.Dynamic && (payload.TagIDs == nil || len(payload.TagIDs) == 0) {
-		return errors.New("TagIDs is mandatory for a dynamic Edge group")
+	if payload.Dynamic && len(payload.TagIDs) == 0 && len(payload.Metadata) == 0 && payload.Expression == "" {
+		return errors.New("TagIDs, Metadata or Expression is mandatory for a dynamic Edge group")
+	}
+	err := tag.ValidateMetadata(payload.Metadata)
+	if err != nil {
+		return err
+	}
+	if payload.Expression != "" {
+		_, err = tag.ParseExpression(payload.Expression)
+		if err != nil {
+			return err
+		}
 	}
 	if !payload.Dynamic && (payload.Endpoints == nil || len(payload.Endpoints) == 0) {
 		return errors.New("Endpoints is mandatory for a static Edge group")
@@ -60,6 +73,8 @@ func (handler *Handler) edgeGroupCreate(w http.ResponseWriter, r *http.Request)
 
 	if edgeGroup.Dynamic {
 		edgeGroup.TagIDs = payload.TagIDs
+		edgeGroup.Metadata = payload.Metadata
+		edgeGroup.Expression = payload.Expression
 	} else {
 		endpointIDs := []portainer.EndpointID{}
 		for _, endpointID := range payload.Endpoints {
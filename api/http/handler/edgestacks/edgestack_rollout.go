@@ -0,0 +1,291 @@
+package edgestacks
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"time"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+	"github.com/portainer/portainer/api/internal/edge"
+)
+
+const previousEntryPointPrefix = "previous."
+
+var (
+	errNoRollout         = errors.New("The Edge stack has no rollout")
+	errRolloutNotActive  = errors.New("The rollout of the Edge stack is over")
+	errRolloutInProgress = errors.New("A rollout of the Edge stack is in progress")
+)
+
+type edgeStackRolloutPayload struct {
+	// BatchSize is the number of endpoints of a batch, BatchPercentage is used when it is not defined
+	BatchSize       int
+	BatchPercentage int
+	// FailureThreshold is the percentage of the endpoints of a batch allowed to fail
+	FailureThreshold int
+	AutoContinue     bool
+	AutoRollback     bool
+}
+
+func (payload *edgeStackRolloutPayload) Validate() error {
+	if payload.BatchSize < 0 {
+		return errors.New("Invalid rollout batch size")
+	}
+	if payload.BatchSize == 0 && (payload.BatchPercentage <= 0 || payload.BatchPercentage > 100) {
+		return errors.New("Invalid rollout batch percentage. Value must be between 1 and 100")
+	}
+	if payload.FailureThreshold < 0 || payload.FailureThreshold > 100 {
+		return errors.New("Invalid rollout failure threshold. Value must be between 0 and 100")
+	}
+	return nil
+}
+
+// GET request on /api/edge_stacks/:id/rollout
+func (handler *Handler) edgeStackRolloutInspect(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	stack, handlerErr := handler.retrieveRolloutStack(r)
+	if handlerErr != nil {
+		return handlerErr
+	}
+
+	return response.JSON(w, stack.Rollout)
+}
+
+// POST request on /api/edge_stacks/:id/rollout/continue
+// Deploys the next batch of a paused rollout.
+func (handler *Handler) edgeStackRolloutContinue(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	return handler.updateRollout(w, r, func(stack *portainer.EdgeStack) error {
+		nextRolloutBatch(stack)
+		return nil
+	})
+}
+
+// POST request on /api/edge_stacks/:id/rollout/pause
+// The rollout stays on its current batch until it is continued or rolled back.
+func (handler *Handler) edgeStackRolloutPause(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	return handler.updateRollout(w, r, func(stack *portainer.EdgeStack) error {
+		stack.Rollout.Status = portainer.EdgeStackRolloutPaused
+		stack.Rollout.Message = "Paused by an administrator"
+		return nil
+	})
+}
+
+// POST request on /api/edge_stacks/:id/rollout/rollback
+// The previous version of the stack is deployed again on the endpoints of the deployed batches.
+func (handler *Handler) edgeStackRolloutRollback(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	return handler.updateRollout(w, r, func(stack *portainer.EdgeStack) error {
+		return handler.rollbackRollout(stack, "Rolled back by an administrator")
+	})
+}
+
+func (handler *Handler) retrieveRolloutStack(r *http.Request) (*portainer.EdgeStack, *httperror.HandlerError) {
+	stackID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return nil, &httperror.HandlerError{http.StatusBadRequest, "Invalid stack identifier route variable", err}
+	}
+
+	stack, err := handler.DataStore.EdgeStack().EdgeStack(portainer.EdgeStackID(stackID))
+	if err == bolterrors.ErrObjectNotFound {
+		return nil, &httperror.HandlerError{http.StatusNotFound, "Unable to find a stack with the specified identifier inside the database", err}
+	} else if err != nil {
+		return nil, &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a stack with the specified identifier inside the database", err}
+	}
+
+	if stack.Rollout == nil {
+		return nil, &httperror.HandlerError{http.StatusNotFound, "The Edge stack has no rollout", errNoRollout}
+	}
+
+	return stack, nil
+}
+
+func (handler *Handler) updateRollout(w http.ResponseWriter, r *http.Request, update func(stack *portainer.EdgeStack) error) *httperror.HandlerError {
+	handler.statusMutex.Lock()
+	defer handler.statusMutex.Unlock()
+
+	stack, handlerErr := handler.retrieveRolloutStack(r)
+	if handlerErr != nil {
+		return handlerErr
+	}
+
+	if !edge.RolloutActive(stack.Rollout) {
+		return &httperror.HandlerError{http.StatusConflict, "The rollout of the Edge stack is over", errRolloutNotActive}
+	}
+
+	err := update(stack)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to update the rollout of the Edge stack", err}
+	}
+	stack.Rollout.UpdateDate = time.Now().Unix()
+
+	err = handler.DataStore.EdgeStack().UpdateEdgeStack(stack.ID, stack)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist the stack changes inside the database", err}
+	}
+
+	return response.JSON(w, stack.Rollout)
+}
+
+// edgeStackEndpoints returns the endpoints related to the Edge groups of a stack
+func (handler *Handler) edgeStackEndpoints(stack *portainer.EdgeStack) ([]portainer.EndpointID, *httperror.HandlerError) {
+	endpoints, err := handler.DataStore.Endpoint().Endpoints()
+	if err != nil {
+		return nil, &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve endpoints from database", err}
+	}
+
+	endpointGroups, err := handler.DataStore.EndpointGroup().EndpointGroups()
+	if err != nil {
+		return nil, &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve endpoint groups from database", err}
+	}
+
+	edgeGroups, err := handler.DataStore.EdgeGroup().EdgeGroups()
+	if err != nil {
+		return nil, &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve edge groups from database", err}
+	}
+
+	endpointIDs, err := edge.EdgeStackRelatedEndpoints(stack.EdgeGroups, endpoints, endpointGroups, edgeGroups)
+	if err != nil {
+		return nil, &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve edge stack related endpoints from database", err}
+	}
+
+	return endpointIDs, nil
+}
+
+// startRollout keeps a copy of the current file of the stack as the previous version and deploys the first batch.
+// It must be called before the new file of the stack is stored.
+func (handler *Handler) startRollout(stack *portainer.EdgeStack, payload *edgeStackRolloutPayload, endpointIDs []portainer.EndpointID) error {
+	fileContent, err := handler.FileService.GetFileContent(path.Join(stack.ProjectPath, stack.EntryPoint))
+	if err != nil {
+		return err
+	}
+
+	previousEntryPoint := previousEntryPointPrefix + stack.EntryPoint
+	_, err = handler.FileService.StoreEdgeStackFileFromBytes(strconv.Itoa(int(stack.ID)), previousEntryPoint, fileContent)
+	if err != nil {
+		return err
+	}
+
+	endpoints := make([]portainer.EndpointID, 0, len(endpointIDs))
+	for endpointID := range EndpointSet(endpointIDs) {
+		endpoints = append(endpoints, endpointID)
+	}
+	sort.Slice(endpoints, func(i, j int) bool { return endpoints[i] < endpoints[j] })
+
+	batchSize := payload.BatchSize
+	if batchSize == 0 {
+		batchSize = (len(endpoints)*payload.BatchPercentage + 99) / 100
+	}
+	if batchSize == 0 {
+		batchSize = 1
+	}
+
+	now := time.Now().Unix()
+	stack.Rollout = &portainer.EdgeStackRollout{
+		BatchSize:          batchSize,
+		FailureThreshold:   payload.FailureThreshold,
+		AutoContinue:       payload.AutoContinue,
+		AutoRollback:       payload.AutoRollback,
+		Endpoints:          endpoints,
+		PreviousVersion:    stack.Version,
+		PreviousEntryPoint: previousEntryPoint,
+		StartDate:          now,
+		UpdateDate:         now,
+	}
+
+	nextRolloutBatch(stack)
+	return nil
+}
+
+// nextRolloutBatch deploys the next batch of endpoints, the rollout is completed when all the endpoints are deployed.
+// The status reported by the endpoints of the batch for the previous version is discarded.
+func nextRolloutBatch(stack *portainer.EdgeStack) {
+	rollout := stack.Rollout
+	if rollout.Deployed >= len(rollout.Endpoints) {
+		rollout.Status = portainer.EdgeStackRolloutCompleted
+		rollout.Message = ""
+		return
+	}
+
+	rollout.BatchStart = rollout.Deployed
+	rollout.Deployed += rollout.BatchSize
+	if rollout.Deployed > len(rollout.Endpoints) {
+		rollout.Deployed = len(rollout.Endpoints)
+	}
+
+	for _, endpointID := range edge.RolloutBatch(rollout) {
+		delete(stack.Status, endpointID)
+	}
+
+	rollout.Status = portainer.EdgeStackRolloutInProgress
+	rollout.Message = fmt.Sprintf("Deploying %d of %d endpoints", rollout.Deployed, len(rollout.Endpoints))
+}
+
+// evaluateRollout updates the rollout from the status reported by the endpoints of the current batch.
+// The rollout is paused or rolled back when too many endpoints failed, the next batch is deployed
+// once all the endpoints succeeded and the rollout continues automatically.
+func (handler *Handler) evaluateRollout(stack *portainer.EdgeStack) error {
+	rollout := stack.Rollout
+	if rollout == nil || rollout.Status != portainer.EdgeStackRolloutInProgress {
+		return nil
+	}
+
+	batch := edge.RolloutBatch(rollout)
+	succeeded, failed := edge.RolloutBatchStatus(stack)
+
+	if failed*100 > rollout.FailureThreshold*len(batch) {
+		message := fmt.Sprintf("%d of the %d endpoints of the batch failed to deploy the stack", failed, len(batch))
+		if rollout.AutoRollback {
+			return handler.rollbackRollout(stack, message)
+		}
+
+		rollout.Status = portainer.EdgeStackRolloutPaused
+		rollout.Message = message
+		rollout.UpdateDate = time.Now().Unix()
+		return nil
+	}
+
+	if succeeded+failed < len(batch) {
+		return nil
+	}
+
+	if rollout.AutoContinue || rollout.Deployed == len(rollout.Endpoints) {
+		nextRolloutBatch(stack)
+	} else {
+		rollout.Status = portainer.EdgeStackRolloutPaused
+		rollout.Message = fmt.Sprintf("%d of %d endpoints deployed, waiting to continue", rollout.Deployed, len(rollout.Endpoints))
+	}
+	rollout.UpdateDate = time.Now().Unix()
+
+	return nil
+}
+
+// rollbackRollout restores the previous file of the stack under a new version, so that the endpoints
+// of the deployed batches deploy the previous version again
+func (handler *Handler) rollbackRollout(stack *portainer.EdgeStack, message string) error {
+	rollout := stack.Rollout
+
+	fileContent, err := handler.FileService.GetFileContent(path.Join(stack.ProjectPath, rollout.PreviousEntryPoint))
+	if err != nil {
+		return err
+	}
+
+	_, err = handler.FileService.StoreEdgeStackFileFromBytes(strconv.Itoa(int(stack.ID)), stack.EntryPoint, fileContent)
+	if err != nil {
+		return err
+	}
+
+	stack.Version++
+	stack.Status = map[portainer.EndpointID]portainer.EdgeStackStatus{}
+
+	rollout.Status = portainer.EdgeStackRolloutRolledBack
+	rollout.Message = message
+	rollout.UpdateDate = time.Now().Unix()
+
+	return nil
+}
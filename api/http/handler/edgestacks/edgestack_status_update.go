@@ -10,6 +10,7 @@ import (
 	"github.com/portainer/libhttp/response"
 	"github.com/portainer/portainer/api"
 	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+	httperrors "github.com/portainer/portainer/api/http/errors"
 )
 
 type updateStatusPayload struct {
@@ -37,6 +38,9 @@ func (handler *Handler) edgeStackStatusUpdate(w http.ResponseWriter, r *http.Req
 		return &httperror.HandlerError{http.StatusBadRequest, "Invalid stack identifier route variable", err}
 	}
 
+	handler.statusMutex.Lock()
+	defer handler.statusMutex.Unlock()
+
 	stack, err := handler.DataStore.EdgeStack().EdgeStack(portainer.EdgeStackID(stackID))
 	if err == bolterrors.ErrObjectNotFound {
 		return &httperror.HandlerError{http.StatusNotFound, "Unable to find a stack with the specified identifier inside the database", err}
@@ -62,12 +66,26 @@ func (handler *Handler) edgeStackStatusUpdate(w http.ResponseWriter, r *http.Req
 		return &httperror.HandlerError{http.StatusForbidden, "Permission denied to access endpoint", err}
 	}
 
+	if endpoint.EdgePendingApproval {
+		return &httperror.HandlerError{http.StatusForbidden, "The Edge endpoint is waiting for approval", httperrors.ErrEdgeEndpointPendingApproval}
+	}
+
+	status, ok := stack.Status[*payload.EndpointID]
+	if ok && status.Type == *payload.Status && status.Error == payload.Error {
+		return response.JSON(w, stack)
+	}
+
 	stack.Status[*payload.EndpointID] = portainer.EdgeStackStatus{
 		Type:       *payload.Status,
 		Error:      payload.Error,
 		EndpointID: *payload.EndpointID,
 	}
 
+	err = handler.evaluateRollout(stack)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to update the rollout of the Edge stack", err}
+	}
+
 	err = handler.DataStore.EdgeStack().UpdateEdgeStack(stack.ID, stack)
 	if err != nil {
 		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist the stack changes inside the database", err}
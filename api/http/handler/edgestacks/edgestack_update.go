@@ -19,6 +19,8 @@ type updateEdgeStackPayload struct {
 	Version          *int
 	Prune            *bool
 	EdgeGroups       []portainer.EdgeGroupID
+	// Rollout deploys the new version of the stack by batches of endpoints
+	Rollout *edgeStackRolloutPayload
 }
 
 func (payload *updateEdgeStackPayload) Validate(r *http.Request) error {
@@ -28,6 +30,9 @@ func (payload *updateEdgeStackPayload) Validate(r *http.Request) error {
 	if payload.EdgeGroups != nil && len(payload.EdgeGroups) == 0 {
 		return errors.New("Edge Groups are mandatory for an Edge stack")
 	}
+	if payload.Rollout != nil {
+		return payload.Rollout.Validate()
+	}
 	return nil
 }
 
@@ -37,6 +42,9 @@ func (handler *Handler) edgeStackUpdate(w http.ResponseWriter, r *http.Request)
 		return &httperror.HandlerError{http.StatusBadRequest, "Invalid stack identifier route variable", err}
 	}
 
+	handler.statusMutex.Lock()
+	defer handler.statusMutex.Unlock()
+
 	stack, err := handler.DataStore.EdgeStack().EdgeStack(portainer.EdgeStackID(stackID))
 	if err == bolterrors.ErrObjectNotFound {
 		return &httperror.HandlerError{http.StatusNotFound, "Unable to find a stack with the specified identifier inside the database", err}
@@ -50,6 +58,10 @@ func (handler *Handler) edgeStackUpdate(w http.ResponseWriter, r *http.Request)
 		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
 	}
 
+	if edge.RolloutActive(stack.Rollout) {
+		return &httperror.HandlerError{http.StatusConflict, "A rollout of the Edge stack is in progress, it must be completed or rolled back before updating the stack", errRolloutInProgress}
+	}
+
 	if payload.EdgeGroups != nil {
 		endpoints, err := handler.DataStore.Endpoint().Endpoints()
 		if err != nil {
@@ -129,13 +141,30 @@ func (handler *Handler) edgeStackUpdate(w http.ResponseWriter, r *http.Request)
 		stack.Prune = *payload.Prune
 	}
 
+	versionChanged := payload.Version != nil && *payload.Version != stack.Version
+	if versionChanged {
+		stack.Rollout = nil
+	}
+
+	if versionChanged && payload.Rollout != nil {
+		endpointIDs, handlerErr := handler.edgeStackEndpoints(stack)
+		if handlerErr != nil {
+			return handlerErr
+		}
+
+		err = handler.startRollout(stack, payload.Rollout, endpointIDs)
+		if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to start the rollout of the Edge stack", err}
+		}
+	}
+
 	stackFolder := strconv.Itoa(int(stack.ID))
 	_, err = handler.FileService.StoreEdgeStackFileFromBytes(stackFolder, stack.EntryPoint, []byte(payload.StackFileContent))
 	if err != nil {
 		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist updated Compose file on disk", err}
 	}
 
-	if payload.Version != nil && *payload.Version != stack.Version {
+	if versionChanged {
 		stack.Version = *payload.Version
 		stack.Status = map[portainer.EndpointID]portainer.EdgeStackStatus{}
 	}
@@ -2,6 +2,7 @@ package edgestacks
 
 import (
 	"net/http"
+	"sync"
 
 	"github.com/gorilla/mux"
 	httperror "github.com/portainer/libhttp/error"
@@ -16,6 +17,7 @@ type Handler struct {
 	DataStore      portainer.DataStore
 	FileService    portainer.FileService
 	GitService     portainer.GitService
+	statusMutex    sync.Mutex
 }
 
 // NewHandler creates a handler to manage endpoint group operations.
@@ -25,18 +27,26 @@ func NewHandler(bouncer *security.RequestBouncer) *Handler {
 		requestBouncer: bouncer,
 	}
 	h.Handle("/edge_stacks",
-		bouncer.AdminAccess(bouncer.EdgeComputeOperation(httperror.LoggerHandler(h.edgeStackCreate)))).Methods(http.MethodPost)
+		bouncer.InstanceAdminAccess(bouncer.EdgeComputeOperation(httperror.LoggerHandler(h.edgeStackCreate)))).Methods(http.MethodPost)
 	h.Handle("/edge_stacks",
-		bouncer.AdminAccess(bouncer.EdgeComputeOperation(httperror.LoggerHandler(h.edgeStackList)))).Methods(http.MethodGet)
+		bouncer.InstanceAdminAccess(bouncer.EdgeComputeOperation(httperror.LoggerHandler(h.edgeStackList)))).Methods(http.MethodGet)
 	h.Handle("/edge_stacks/{id}",
-		bouncer.AdminAccess(bouncer.EdgeComputeOperation(httperror.LoggerHandler(h.edgeStackInspect)))).Methods(http.MethodGet)
+		bouncer.InstanceAdminAccess(bouncer.EdgeComputeOperation(httperror.LoggerHandler(h.edgeStackInspect)))).Methods(http.MethodGet)
 	h.Handle("/edge_stacks/{id}",
-		bouncer.AdminAccess(bouncer.EdgeComputeOperation(httperror.LoggerHandler(h.edgeStackUpdate)))).Methods(http.MethodPut)
+		bouncer.InstanceAdminAccess(bouncer.EdgeComputeOperation(httperror.LoggerHandler(h.edgeStackUpdate)))).Methods(http.MethodPut)
 	h.Handle("/edge_stacks/{id}",
-		bouncer.AdminAccess(bouncer.EdgeComputeOperation(httperror.LoggerHandler(h.edgeStackDelete)))).Methods(http.MethodDelete)
+		bouncer.InstanceAdminAccess(bouncer.EdgeComputeOperation(httperror.LoggerHandler(h.edgeStackDelete)))).Methods(http.MethodDelete)
 	h.Handle("/edge_stacks/{id}/file",
-		bouncer.AdminAccess(bouncer.EdgeComputeOperation(httperror.LoggerHandler(h.edgeStackFile)))).Methods(http.MethodGet)
+		bouncer.InstanceAdminAccess(bouncer.EdgeComputeOperation(httperror.LoggerHandler(h.edgeStackFile)))).Methods(http.MethodGet)
+	h.Handle("/edge_stacks/{id}/rollout",
+		bouncer.InstanceAdminAccess(bouncer.EdgeComputeOperation(httperror.LoggerHandler(h.edgeStackRolloutInspect)))).Methods(http.MethodGet)
+	h.Handle("/edge_stacks/{id}/rollout/continue",
+		bouncer.InstanceAdminAccess(bouncer.EdgeComputeOperation(httperror.LoggerHandler(h.edgeStackRolloutContinue)))).Methods(http.MethodPost)
+	h.Handle("/edge_stacks/{id}/rollout/pause",
+		bouncer.InstanceAdminAccess(bouncer.EdgeComputeOperation(httperror.LoggerHandler(h.edgeStackRolloutPause)))).Methods(http.MethodPost)
+	h.Handle("/edge_stacks/{id}/rollout/rollback",
+		bouncer.InstanceAdminAccess(bouncer.EdgeComputeOperation(httperror.LoggerHandler(h.edgeStackRolloutRollback)))).Methods(http.MethodPost)
 	h.Handle("/edge_stacks/{id}/status",
-		bouncer.PublicAccess(httperror.LoggerHandler(h.edgeStackStatusUpdate))).Methods(http.MethodPut)
+		bouncer.EdgeAgentAccess(httperror.LoggerHandler(h.edgeStackStatusUpdate))).Methods(http.MethodPut)
 	return h
 }
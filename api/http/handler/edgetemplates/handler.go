@@ -25,7 +25,7 @@ func NewHandler(bouncer *security.RequestBouncer) *Handler {
 	}
 
 	h.Handle("/edge_templates",
-		bouncer.AdminAccess(httperror.LoggerHandler(h.edgeTemplateList))).Methods(http.MethodGet)
+		bouncer.InstanceAdminAccess(httperror.LoggerHandler(h.edgeTemplateList))).Methods(http.MethodGet)
 
 	return h
 }
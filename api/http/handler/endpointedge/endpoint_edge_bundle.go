@@ -0,0 +1,116 @@
+package endpointedge
+
+import (
+	"log"
+	"net/http"
+	"path"
+	"sort"
+	"time"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+	httperrors "github.com/portainer/portainer/api/http/errors"
+	"github.com/portainer/portainer/api/internal/edge"
+	"github.com/portainer/portainer/api/internal/stackfile"
+)
+
+type (
+	edgeBundleResponse struct {
+		Version      string            `json:"Version"`
+		CreationDate int64             `json:"CreationDate"`
+		Stacks       []edgeBundleStack `json:"Stacks"`
+	}
+
+	edgeBundleStack struct {
+		ID               portainer.EdgeStackID `json:"Id"`
+		Name             string                `json:"Name"`
+		Version          int                   `json:"Version"`
+		Prune            bool                  `json:"Prune"`
+		StackFileContent string                `json:"StackFileContent"`
+		// Images are the images of the services of the stack, they are only listed when requested
+		Images []string `json:"Images,omitempty"`
+	}
+)
+
+// GET request on api/endpoints/:id/edge/bundle?images=<images>
+//
+// Returns the definitions of all the Edge stacks of the endpoint in a single versioned bundle. The agent
+// caches the bundle so that the stacks can be started when the device restarts without connectivity,
+// it downloads the bundle again when the version returned on check-in differs from its cached copy.
+// The images of the stacks are listed when the images query parameter is set, so that they can be pre-pulled.
+func (handler *Handler) endpointEdgeBundleInspect(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	endpointID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid endpoint identifier route variable", err}
+	}
+
+	includeImages, _ := request.RetrieveBooleanQueryParameter(r, "images", true)
+
+	endpoint, err := handler.DataStore.Endpoint().Endpoint(portainer.EndpointID(endpointID))
+	if err == bolterrors.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find an endpoint with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find an endpoint with the specified identifier inside the database", err}
+	}
+
+	err = handler.requestBouncer.AuthorizedEdgeEndpointOperation(r, endpoint)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusForbidden, "Permission denied to access endpoint", err}
+	}
+
+	if endpoint.EdgePendingApproval {
+		return &httperror.HandlerError{http.StatusForbidden, "The Edge endpoint is waiting for approval", httperrors.ErrEdgeEndpointPendingApproval}
+	}
+
+	relation, err := handler.DataStore.EndpointRelation().EndpointRelation(endpoint.ID)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve relation object from the database", err}
+	}
+
+	edgeStacks := []portainer.EdgeStack{}
+	bundle := edgeBundleResponse{
+		CreationDate: time.Now().Unix(),
+		Stacks:       []edgeBundleStack{},
+	}
+
+	for stackID := range relation.EdgeStacks {
+		edgeStack, err := handler.DataStore.EdgeStack().EdgeStack(stackID)
+		if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve edge stack from the database", err}
+		}
+
+		version, entryPoint := edge.EdgeStackEndpointVersion(edgeStack, endpoint.ID)
+
+		stackFileContent, err := handler.FileService.GetFileContent(path.Join(edgeStack.ProjectPath, entryPoint))
+		if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve Compose file from disk", err}
+		}
+
+		bundleStack := edgeBundleStack{
+			ID:               edgeStack.ID,
+			Name:             edgeStack.Name,
+			Version:          version,
+			Prune:            edgeStack.Prune,
+			StackFileContent: string(stackFileContent),
+		}
+
+		if includeImages {
+			images, err := stackfile.Images(stackFileContent, nil)
+			if err != nil {
+				log.Printf("[WARN] [http,edge,bundle] [message: unable to retrieve the images of the Edge stack] [stack: %s] [error: %s]", edgeStack.Name, err)
+			}
+			bundleStack.Images = images
+		}
+
+		edgeStacks = append(edgeStacks, *edgeStack)
+		bundle.Stacks = append(bundle.Stacks, bundleStack)
+	}
+
+	sort.Slice(bundle.Stacks, func(i, j int) bool { return bundle.Stacks[i].ID < bundle.Stacks[j].ID })
+	bundle.Version = edge.BundleVersion(edgeStacks, endpoint.ID)
+
+	return response.JSON(w, bundle)
+}
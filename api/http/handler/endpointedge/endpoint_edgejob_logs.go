@@ -9,6 +9,7 @@ import (
 	"github.com/portainer/libhttp/response"
 	"github.com/portainer/portainer/api"
 	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+	httperrors "github.com/portainer/portainer/api/http/errors"
 )
 
 type logsPayload struct {
@@ -38,6 +39,10 @@ func (handler *Handler) endpointEdgeJobsLogs(w http.ResponseWriter, r *http.Requ
 		return &httperror.HandlerError{http.StatusForbidden, "Permission denied to access endpoint", err}
 	}
 
+	if endpoint.EdgePendingApproval {
+		return &httperror.HandlerError{http.StatusForbidden, "The Edge endpoint is waiting for approval", httperrors.ErrEdgeEndpointPendingApproval}
+	}
+
 	edgeJobID, err := request.RetrieveNumericRouteVariableValue(r, "jobID")
 	if err != nil {
 		return &httperror.HandlerError{http.StatusBadRequest, "Invalid edge job identifier route variable", err}
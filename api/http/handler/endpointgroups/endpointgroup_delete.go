@@ -34,23 +34,21 @@ func (handler *Handler) endpointGroupDelete(w http.ResponseWriter, r *http.Reque
 		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to remove the endpoint group from the database", err}
 	}
 
-	endpoints, err := handler.DataStore.Endpoint().Endpoints()
+	endpoints, err := handler.DataStore.Endpoint().EndpointsByGroupID(portainer.EndpointGroupID(endpointGroupID))
 	if err != nil {
 		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve endpoints from the database", err}
 	}
 
 	for _, endpoint := range endpoints {
-		if endpoint.GroupID == portainer.EndpointGroupID(endpointGroupID) {
-			endpoint.GroupID = portainer.EndpointGroupID(1)
-			err = handler.DataStore.Endpoint().UpdateEndpoint(endpoint.ID, &endpoint)
-			if err != nil {
-				return &httperror.HandlerError{http.StatusInternalServerError, "Unable to update endpoint", err}
-			}
+		endpoint.GroupID = portainer.EndpointGroupID(1)
+		err = handler.DataStore.Endpoint().UpdateEndpoint(endpoint.ID, &endpoint)
+		if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to update endpoint", err}
+		}
 
-			err = handler.updateEndpointRelations(&endpoint, nil)
-			if err != nil {
-				return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist endpoint relations changes inside the database", err}
-			}
+		err = handler.updateEndpointRelations(&endpoint, nil)
+		if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist endpoint relations changes inside the database", err}
 		}
 	}
 
@@ -26,7 +26,8 @@ func NewHandler(bouncer *security.RequestBouncer) *Handler {
 	h.PathPrefix("/{id}/azure").Handler(
 		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.proxyRequestsToAzureAPI)))
 	h.PathPrefix("/{id}/docker").Handler(
-		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.proxyRequestsToDockerAPI)))
+		bouncer.AuthenticatedAccess(bouncer.ChangeApproval(portainer.ChangeRequestContainerDeletion, containerDeletionEndpoint,
+			httperror.LoggerHandler(h.proxyRequestsToDockerAPI))))
 	h.PathPrefix("/{id}/kubernetes").Handler(
 		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.proxyRequestsToKubernetesAPI)))
 	h.PathPrefix("/{id}/storidge").Handler(
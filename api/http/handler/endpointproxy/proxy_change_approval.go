@@ -0,0 +1,33 @@
+package endpointproxy
+
+import (
+	"net/http"
+	"regexp"
+
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/portainer/api"
+)
+
+// containerDeletionPath and containerPrunePath match the paths of the container deletions and of the
+// removal of the stopped containers proxied to the Docker API, with or without the version of the API
+var (
+	containerDeletionPath = regexp.MustCompile(`^/\d+/docker(/v[0-9.]+)?/containers/[^/]+/?$`)
+	containerPrunePath    = regexp.MustCompile(`^/\d+/docker(/v[0-9.]+)?/containers/prune/?$`)
+)
+
+// containerDeletionEndpoint returns the endpoint of a container deletion or of a removal of the stopped
+// containers proxied to the Docker API
+func containerDeletionEndpoint(r *http.Request) (portainer.EndpointID, bool) {
+	deletion := r.Method == http.MethodDelete && containerDeletionPath.MatchString(r.URL.Path)
+	prune := r.Method == http.MethodPost && containerPrunePath.MatchString(r.URL.Path)
+	if !deletion && !prune {
+		return 0, false
+	}
+
+	endpointID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return 0, false
+	}
+
+	return portainer.EndpointID(endpointID), true
+}
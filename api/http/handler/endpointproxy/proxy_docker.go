@@ -2,6 +2,7 @@ package endpointproxy
 
 import (
 	"errors"
+	"regexp"
 	"strconv"
 	"time"
 
@@ -9,16 +10,27 @@ import (
 	"github.com/portainer/libhttp/request"
 	"github.com/portainer/portainer/api"
 	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+	httperrors "github.com/portainer/portainer/api/http/errors"
 
 	"net/http"
 )
 
+// agentHostRequestRe matches the requests to the host actions of the agent, these requests are
+// only sent by the host actions API which verifies the actions granted to the user
+var agentHostRequestRe = regexp.MustCompile(`^/[0-9]+/docker(/v[0-9]\.[0-9]*)?/v2/host/actions(/|$)`)
+
+var errAgentHostRequest = errors.New("Host actions must be run through the host actions API")
+
 func (handler *Handler) proxyRequestsToDockerAPI(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
 	endpointID, err := request.RetrieveNumericRouteVariableValue(r, "id")
 	if err != nil {
 		return &httperror.HandlerError{http.StatusBadRequest, "Invalid endpoint identifier route variable", err}
 	}
 
+	if agentHostRequestRe.MatchString(r.URL.Path) {
+		return &httperror.HandlerError{http.StatusForbidden, "Permission denied to run a host action through the Docker API", errAgentHostRequest}
+	}
+
 	endpoint, err := handler.DataStore.Endpoint().Endpoint(portainer.EndpointID(endpointID))
 	if err == bolterrors.ErrObjectNotFound {
 		return &httperror.HandlerError{http.StatusNotFound, "Unable to find an endpoint with the specified identifier inside the database", err}
@@ -36,6 +48,10 @@ func (handler *Handler) proxyRequestsToDockerAPI(w http.ResponseWriter, r *http.
 			return &httperror.HandlerError{http.StatusInternalServerError, "No Edge agent registered with the endpoint", errors.New("No agent available")}
 		}
 
+		if endpoint.EdgePendingApproval {
+			return &httperror.HandlerError{http.StatusForbidden, "The Edge endpoint is waiting for approval", httperrors.ErrEdgeEndpointPendingApproval}
+		}
+
 		tunnel := handler.ReverseTunnelService.GetTunnelDetails(endpoint.ID)
 		if tunnel.Status == portainer.EdgeAgentIdle {
 			handler.ProxyManager.DeleteEndpointProxy(endpoint)
@@ -16,8 +16,12 @@ import (
 	"github.com/portainer/libhttp/response"
 	"github.com/portainer/portainer/api"
 	"github.com/portainer/portainer/api/crypto"
+	"github.com/portainer/portainer/api/docker"
 	"github.com/portainer/portainer/api/http/client"
+	"github.com/portainer/portainer/api/http/security"
 	"github.com/portainer/portainer/api/internal/edge"
+	"github.com/portainer/portainer/api/internal/organization"
+	"github.com/portainer/portainer/api/kubernetes/cli"
 )
 
 type endpointCreatePayload struct {
@@ -37,6 +41,13 @@ type endpointCreatePayload struct {
 	AzureAuthenticationKey string
 	TagIDs                 []portainer.TagID
 	EdgeCheckinInterval    int
+	KubeconfigFile         []byte
+	KubeconfigContext      string
+	SSHPrivateKeyFile      []byte
+	SSHSocketPath          string
+	EdgeTunnelZone         string
+	// OrganizationID is the organization of the user creating the endpoint, it is not read from the request
+	OrganizationID portainer.OrganizationID
 }
 
 type endpointCreationEnum int
@@ -48,6 +59,7 @@ const (
 	azureEnvironment
 	edgeAgentEnvironment
 	localKubernetesEnvironment
+	kubeconfigKubernetesEnvironment
 )
 
 func (payload *endpointCreatePayload) Validate(r *http.Request) error {
@@ -59,7 +71,7 @@ func (payload *endpointCreatePayload) Validate(r *http.Request) error {
 
 	endpointCreationType, err := request.RetrieveNumericMultiPartFormValue(r, "EndpointCreationType", false)
 	if err != nil || endpointCreationType == 0 {
-		return errors.New("Invalid endpoint type value. Value must be one of: 1 (Docker environment), 2 (Agent environment), 3 (Azure environment), 4 (Edge Agent environment) or 5 (Local Kubernetes environment)")
+		return errors.New("Invalid endpoint type value. Value must be one of: 1 (Docker environment), 2 (Agent environment), 3 (Azure environment), 4 (Edge Agent environment), 5 (Local Kubernetes environment) or 6 (Kubeconfig Kubernetes environment)")
 	}
 	payload.EndpointCreationType = endpointCreationEnum(endpointCreationType)
 
@@ -130,6 +142,18 @@ func (payload *endpointCreatePayload) Validate(r *http.Request) error {
 			return errors.New("Invalid Azure authentication key")
 		}
 		payload.AzureAuthenticationKey = azureAuthenticationKey
+	case kubeconfigKubernetesEnvironment:
+		kubeconfig, _, err := request.RetrieveMultiPartFormFile(r, "KubeconfigFile")
+		if err != nil {
+			return errors.New("Invalid kubeconfig file. Ensure that the file is uploaded correctly")
+		}
+		payload.KubeconfigFile = kubeconfig
+
+		kubeconfigContext, _ := request.RetrieveMultiPartFormValue(r, "KubeconfigContext", true)
+		payload.KubeconfigContext = kubeconfigContext
+
+		publicURL, _ := request.RetrieveMultiPartFormValue(r, "PublicURL", true)
+		payload.PublicURL = publicURL
 	default:
 		endpointURL, err := request.RetrieveMultiPartFormValue(r, "URL", true)
 		if err != nil {
@@ -139,11 +163,41 @@ func (payload *endpointCreatePayload) Validate(r *http.Request) error {
 
 		publicURL, _ := request.RetrieveMultiPartFormValue(r, "PublicURL", true)
 		payload.PublicURL = publicURL
+
+		if strings.HasPrefix(payload.URL, "ssh://") {
+			if payload.EndpointCreationType != localDockerEnvironment {
+				return errors.New("Invalid endpoint URL. The ssh:// protocol is only supported for Docker environments")
+			}
+
+			if payload.TLS {
+				return errors.New("TLS cannot be enabled on an endpoint reached over SSH")
+			}
+
+			_, _, err := docker.ParseSSHURL(payload.URL)
+			if err != nil {
+				return err
+			}
+
+			privateKey, _, err := request.RetrieveMultiPartFormFile(r, "SSHPrivateKeyFile")
+			if err != nil {
+				return errors.New("Invalid SSH private key file. Ensure that the file is uploaded correctly")
+			}
+			payload.SSHPrivateKeyFile = privateKey
+
+			socketPath, _ := request.RetrieveMultiPartFormValue(r, "SSHSocketPath", true)
+			if socketPath == "" {
+				socketPath = docker.DefaultSSHSocketPath
+			}
+			payload.SSHSocketPath = socketPath
+		}
 	}
 
 	checkinInterval, _ := request.RetrieveNumericMultiPartFormValue(r, "CheckinInterval", true)
 	payload.EdgeCheckinInterval = checkinInterval
 
+	edgeTunnelZone, _ := request.RetrieveMultiPartFormValue(r, "EdgeTunnelZone", true)
+	payload.EdgeTunnelZone = edgeTunnelZone
+
 	return nil
 }
 
@@ -155,11 +209,33 @@ func (handler *Handler) endpointCreate(w http.ResponseWriter, r *http.Request) *
 		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
 	}
 
+	securityContext, err := security.RetrieveRestrictedRequestContext(r)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve info from request context", err}
+	}
+	payload.OrganizationID = securityContext.OrganizationID
+
+	err = organization.CheckEndpointQuota(handler.DataStore, payload.OrganizationID, 1, payload.EndpointCreationType == edgeAgentEnvironment)
+	if quotaErr, ok := err.(*organization.QuotaExceededError); ok {
+		return &httperror.HandlerError{http.StatusForbidden, quotaErr.Error(), quotaErr}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to verify the quotas of the organization", err}
+	}
+
 	endpoint, endpointCreationError := handler.createEndpoint(payload)
 	if endpointCreationError != nil {
 		return endpointCreationError
 	}
 
+	handlerErr := handler.createEndpointRelation(endpoint)
+	if handlerErr != nil {
+		return handlerErr
+	}
+
+	return response.JSON(w, endpoint)
+}
+
+func (handler *Handler) createEndpointRelation(endpoint *portainer.Endpoint) *httperror.HandlerError {
 	endpointGroup, err := handler.DataStore.EndpointGroup().EndpointGroup(endpoint.GroupID)
 	if err != nil {
 		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find an endpoint group inside the database", err}
@@ -192,7 +268,7 @@ func (handler *Handler) endpointCreate(w http.ResponseWriter, r *http.Request) *
 		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist the relation object inside the database", err}
 	}
 
-	return response.JSON(w, endpoint)
+	return nil
 }
 
 func (handler *Handler) createEndpoint(payload *endpointCreatePayload) (*portainer.Endpoint, *httperror.HandlerError) {
@@ -205,6 +281,9 @@ func (handler *Handler) createEndpoint(payload *endpointCreatePayload) (*portain
 
 	case localKubernetesEnvironment:
 		return handler.createKubernetesEndpoint(payload)
+
+	case kubeconfigKubernetesEnvironment:
+		return handler.createKubeconfigKubernetesEndpoint(payload)
 	}
 
 	endpointType := portainer.DockerEnvironment
@@ -222,6 +301,10 @@ func (handler *Handler) createEndpoint(payload *endpointCreatePayload) (*portain
 		}
 	}
 
+	if strings.HasPrefix(payload.URL, "ssh://") {
+		return handler.createSSHEndpoint(payload)
+	}
+
 	if payload.TLS {
 		return handler.createTLSSecuredEndpoint(payload, endpointType)
 	}
@@ -257,6 +340,7 @@ func (handler *Handler) createAzureEndpoint(payload *endpointCreatePayload) (*po
 		Status:             portainer.EndpointStatusUp,
 		Snapshots:          []portainer.DockerSnapshot{},
 		Kubernetes:         portainer.KubernetesDefault(),
+		OrganizationID:     payload.OrganizationID,
 	}
 
 	err = handler.saveEndpointAndUpdateAuthorizations(endpoint)
@@ -284,7 +368,17 @@ func (handler *Handler) createEdgeAgentEndpoint(payload *endpointCreatePayload)
 		return nil, &httperror.HandlerError{http.StatusBadRequest, "Invalid endpoint URL", errors.New("cannot use localhost as endpoint URL")}
 	}
 
-	edgeKey := handler.ReverseTunnelService.GenerateEdgeKey(payload.URL, portainerHost, endpointID)
+	settings, err := handler.DataStore.Settings().Settings()
+	if err != nil {
+		return nil, &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve the settings from the database", err}
+	}
+
+	tunnelServerAddr, err := edgeTunnelServerAddress(settings, portainerHost, payload.EdgeTunnelZone)
+	if err != nil {
+		return nil, &httperror.HandlerError{http.StatusBadRequest, "Invalid Edge tunnel zone", err}
+	}
+
+	edgeKey := handler.ReverseTunnelService.GenerateEdgeKey(payload.URL, tunnelServerAddr, endpointID)
 
 	endpoint := &portainer.Endpoint{
 		ID:      portainer.EndpointID(endpointID),
@@ -303,7 +397,9 @@ func (handler *Handler) createEdgeAgentEndpoint(payload *endpointCreatePayload)
 		Snapshots:           []portainer.DockerSnapshot{},
 		EdgeKey:             edgeKey,
 		EdgeCheckinInterval: payload.EdgeCheckinInterval,
+		EdgeTunnelZone:      payload.EdgeTunnelZone,
 		Kubernetes:          portainer.KubernetesDefault(),
+		OrganizationID:      payload.OrganizationID,
 	}
 
 	err = handler.saveEndpointAndUpdateAuthorizations(endpoint)
@@ -314,6 +410,25 @@ func (handler *Handler) createEdgeAgentEndpoint(payload *endpointCreatePayload)
 	return endpoint, nil
 }
 
+// edgeTunnelServerAddress returns the tunnel server address advertised to the Edge agents of a network zone.
+// Outside of any zone, the address defined in the settings is used, or the host of the Portainer instance URL.
+func edgeTunnelServerAddress(settings *portainer.Settings, host, zone string) (string, error) {
+	if zone != "" {
+		for _, tunnelZone := range settings.EdgeTunnelZones {
+			if tunnelZone.Name == zone {
+				return tunnelZone.Address, nil
+			}
+		}
+		return "", fmt.Errorf("Unknown Edge tunnel zone: %s", zone)
+	}
+
+	if settings.EdgeTunnelServerAddress != "" {
+		return settings.EdgeTunnelServerAddress, nil
+	}
+
+	return host, nil
+}
+
 func (handler *Handler) createUnsecuredEndpoint(payload *endpointCreatePayload) (*portainer.Endpoint, *httperror.HandlerError) {
 	endpointType := portainer.DockerEnvironment
 
@@ -342,6 +457,7 @@ func (handler *Handler) createUnsecuredEndpoint(payload *endpointCreatePayload)
 		Status:             portainer.EndpointStatusUp,
 		Snapshots:          []portainer.DockerSnapshot{},
 		Kubernetes:         portainer.KubernetesDefault(),
+		OrganizationID:     payload.OrganizationID,
 	}
 
 	err := handler.snapshotAndPersistEndpoint(endpoint)
@@ -377,6 +493,7 @@ func (handler *Handler) createKubernetesEndpoint(payload *endpointCreatePayload)
 		Status:             portainer.EndpointStatusUp,
 		Snapshots:          []portainer.DockerSnapshot{},
 		Kubernetes:         portainer.KubernetesDefault(),
+		OrganizationID:     payload.OrganizationID,
 	}
 
 	err := handler.snapshotAndPersistEndpoint(endpoint)
@@ -387,6 +504,102 @@ func (handler *Handler) createKubernetesEndpoint(payload *endpointCreatePayload)
 	return endpoint, nil
 }
 
+func (handler *Handler) createKubeconfigKubernetesEndpoint(payload *endpointCreatePayload) (*portainer.Endpoint, *httperror.HandlerError) {
+	kubeconfig, err := cli.ParseKubeconfig(payload.KubeconfigFile, payload.KubeconfigContext)
+	if err != nil {
+		return nil, &httperror.HandlerError{http.StatusBadRequest, "Invalid kubeconfig file", err}
+	}
+
+	endpointID := handler.DataStore.Endpoint().GetNextIdentifier()
+	folder := strconv.Itoa(endpointID)
+
+	kubeconfigPath, err := handler.FileService.StoreKubeconfigFileFromBytes(folder, payload.KubeconfigFile)
+	if err != nil {
+		return nil, &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist kubeconfig file on disk", err}
+	}
+
+	endpoint := &portainer.Endpoint{
+		ID:        portainer.EndpointID(endpointID),
+		Name:      payload.Name,
+		URL:       kubeconfig.Server,
+		Type:      portainer.KubeconfigKubernetesEnvironment,
+		GroupID:   portainer.EndpointGroupID(payload.GroupID),
+		PublicURL: payload.PublicURL,
+		TLSConfig: portainer.TLSConfiguration{
+			TLS: false,
+		},
+		KubernetesCredentials: &portainer.KubernetesCredentials{
+			AuthenticationMethod: kubeconfig.AuthenticationMethod,
+			KubeconfigPath:       kubeconfigPath,
+			Context:              kubeconfig.Context,
+		},
+		UserAccessPolicies: portainer.UserAccessPolicies{},
+		TeamAccessPolicies: portainer.TeamAccessPolicies{},
+		Extensions:         []portainer.EndpointExtension{},
+		TagIDs:             payload.TagIDs,
+		Status:             portainer.EndpointStatusUp,
+		Snapshots:          []portainer.DockerSnapshot{},
+		Kubernetes:         portainer.KubernetesDefault(),
+		OrganizationID:     payload.OrganizationID,
+	}
+
+	httpErr := handler.snapshotAndPersistEndpoint(endpoint)
+	if httpErr != nil {
+		handler.FileService.DeleteKubeconfigFile(folder)
+		return nil, httpErr
+	}
+
+	return endpoint, nil
+}
+
+func (handler *Handler) createSSHEndpoint(payload *endpointCreatePayload) (*portainer.Endpoint, *httperror.HandlerError) {
+	hostKey, err := docker.ValidateSSHConnection(payload.URL, payload.SSHPrivateKeyFile)
+	if err != nil {
+		return nil, &httperror.HandlerError{http.StatusBadRequest, "Unable to establish an SSH connection with the endpoint", err}
+	}
+
+	endpointID := handler.DataStore.Endpoint().GetNextIdentifier()
+	folder := strconv.Itoa(endpointID)
+
+	privateKeyPath, err := handler.FileService.StoreSSHKeyFileFromBytes(folder, payload.SSHPrivateKeyFile)
+	if err != nil {
+		return nil, &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist SSH private key file on disk", err}
+	}
+
+	endpoint := &portainer.Endpoint{
+		ID:        portainer.EndpointID(endpointID),
+		Name:      payload.Name,
+		URL:       payload.URL,
+		Type:      portainer.DockerEnvironment,
+		GroupID:   portainer.EndpointGroupID(payload.GroupID),
+		PublicURL: payload.PublicURL,
+		TLSConfig: portainer.TLSConfiguration{
+			TLS: false,
+		},
+		SSHConfig: &portainer.SSHConfiguration{
+			PrivateKeyPath: privateKeyPath,
+			HostKey:        hostKey,
+			SocketPath:     payload.SSHSocketPath,
+		},
+		UserAccessPolicies: portainer.UserAccessPolicies{},
+		TeamAccessPolicies: portainer.TeamAccessPolicies{},
+		Extensions:         []portainer.EndpointExtension{},
+		TagIDs:             payload.TagIDs,
+		Status:             portainer.EndpointStatusUp,
+		Snapshots:          []portainer.DockerSnapshot{},
+		Kubernetes:         portainer.KubernetesDefault(),
+		OrganizationID:     payload.OrganizationID,
+	}
+
+	httpErr := handler.snapshotAndPersistEndpoint(endpoint)
+	if httpErr != nil {
+		handler.FileService.DeleteSSHKeyFile(folder)
+		return nil, httpErr
+	}
+
+	return endpoint, nil
+}
+
 func (handler *Handler) createTLSSecuredEndpoint(payload *endpointCreatePayload, endpointType portainer.EndpointType) (*portainer.Endpoint, *httperror.HandlerError) {
 	endpointID := handler.DataStore.Endpoint().GetNextIdentifier()
 	endpoint := &portainer.Endpoint{
@@ -407,6 +620,7 @@ func (handler *Handler) createTLSSecuredEndpoint(payload *endpointCreatePayload,
 		Status:             portainer.EndpointStatusUp,
 		Snapshots:          []portainer.DockerSnapshot{},
 		Kubernetes:         portainer.KubernetesDefault(),
+		OrganizationID:     payload.OrganizationID,
 	}
 
 	err := handler.storeTLSFiles(endpoint, payload)
@@ -25,6 +25,11 @@ func (handler *Handler) endpointDelete(w http.ResponseWriter, r *http.Request) *
 		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find an endpoint with the specified identifier inside the database", err}
 	}
 
+	err = handler.requestBouncer.AuthorizedEndpointOperation(r, endpoint)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusForbidden, "Permission denied to access endpoint", err}
+	}
+
 	if endpoint.TLSConfig.TLS {
 		folder := strconv.Itoa(endpointID)
 		err = handler.FileService.DeleteTLSFiles(folder)
@@ -33,6 +38,20 @@ func (handler *Handler) endpointDelete(w http.ResponseWriter, r *http.Request) *
 		}
 	}
 
+	if endpoint.KubernetesCredentials != nil {
+		err = handler.FileService.DeleteKubeconfigFile(strconv.Itoa(endpointID))
+		if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to remove kubeconfig file from disk", err}
+		}
+	}
+
+	if endpoint.SSHConfig != nil {
+		err = handler.FileService.DeleteSSHKeyFile(strconv.Itoa(endpointID))
+		if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to remove SSH private key file from disk", err}
+		}
+	}
+
 	err = handler.DataStore.Endpoint().DeleteEndpoint(portainer.EndpointID(endpointID))
 	if err != nil {
 		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to remove endpoint from the database", err}
@@ -40,6 +59,18 @@ func (handler *Handler) endpointDelete(w http.ResponseWriter, r *http.Request) *
 
 	handler.ProxyManager.DeleteEndpointProxy(endpoint)
 
+	integration, err := handler.DataStore.LoadBalancerIntegration().LoadBalancerIntegration(endpoint.ID)
+	if err == nil {
+		handler.deregisterLoadBalancerIntegration(endpoint, integration)
+
+		err = handler.DataStore.LoadBalancerIntegration().DeleteLoadBalancerIntegration(endpoint.ID)
+		if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to remove the load balancer integration from the database", err}
+		}
+	} else if err != errors.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a load balancer integration for the endpoint inside the database", err}
+	}
+
 	err = handler.DataStore.EndpointRelation().DeleteEndpointRelation(endpoint.ID)
 	if err != nil {
 		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to remove endpoint relation from the database", err}
@@ -0,0 +1,170 @@
+package endpoints
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+	"github.com/portainer/portainer/api/docker"
+)
+
+type (
+	endpointDevicesResponse struct {
+		// DeviceRequests is true when the Docker API of the endpoint supports the device requests used by --gpus
+		DeviceRequests bool     `json:"DeviceRequests"`
+		Runtimes       []string `json:"Runtimes"`
+		// GenericResources are the resources advertised by the engine to the Swarm scheduler, such as NVIDIA-GPU=0
+		GenericResources []genericResource `json:"GenericResources"`
+		// Devices are the PCI devices of the host reported by the agent inventory, they are only
+		// available for the agent endpoints with the host management features enabled on the agent
+		Devices []hostDevice `json:"Devices"`
+		GPUs    []hostDevice `json:"GPUs"`
+	}
+
+	genericResource struct {
+		Kind  string `json:"Kind"`
+		Value string `json:"Value"`
+	}
+
+	hostDevice struct {
+		Vendor string `json:"Vendor"`
+		Device string `json:"Device"`
+	}
+
+	agentHostInfo struct {
+		PCIDevices []hostDevice `json:"PCIDevices"`
+	}
+)
+
+// GET request on /api/endpoints/:id/devices
+//
+// Returns the devices and GPUs of the host of a Docker endpoint that can be requested when creating containers.
+func (handler *Handler) endpointDevicesInspect(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	endpointID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid endpoint identifier route variable", err}
+	}
+
+	endpoint, err := handler.DataStore.Endpoint().Endpoint(portainer.EndpointID(endpointID))
+	if err == bolterrors.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find an endpoint with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find an endpoint with the specified identifier inside the database", err}
+	}
+
+	err = handler.requestBouncer.AuthorizedEndpointOperation(r, endpoint)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusForbidden, "Permission denied to access endpoint", err}
+	}
+
+	if endpoint.Type != portainer.DockerEnvironment && endpoint.Type != portainer.AgentOnDockerEnvironment && endpoint.Type != portainer.EdgeAgentOnDockerEnvironment {
+		return &httperror.HandlerError{http.StatusBadRequest, "The devices are only available for Docker endpoints", errors.New("Invalid endpoint type")}
+	}
+
+	cli, err := handler.DockerClientFactory.CreateClient(endpoint, r.Header.Get(portainer.PortainerAgentTargetHeader))
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to create Docker client", err}
+	}
+	defer cli.Close()
+
+	info, err := cli.Info(context.Background())
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve engine information", err}
+	}
+
+	devices := &endpointDevicesResponse{
+		DeviceRequests:   docker.Capabilities(handler.DockerClientFactory.ServerAPIVersion(endpoint)).DeviceRequests,
+		Runtimes:         make([]string, 0, len(info.Runtimes)),
+		GenericResources: make([]genericResource, 0),
+		Devices:          make([]hostDevice, 0),
+		GPUs:             make([]hostDevice, 0),
+	}
+
+	for runtime := range info.Runtimes {
+		devices.Runtimes = append(devices.Runtimes, runtime)
+	}
+	sort.Strings(devices.Runtimes)
+
+	for _, resource := range info.GenericResources {
+		if resource.NamedResourceSpec != nil {
+			devices.GenericResources = append(devices.GenericResources, genericResource{Kind: resource.NamedResourceSpec.Kind, Value: resource.NamedResourceSpec.Value})
+		}
+		if resource.DiscreteResourceSpec != nil {
+			devices.GenericResources = append(devices.GenericResources, genericResource{Kind: resource.DiscreteResourceSpec.Kind, Value: fmt.Sprint(resource.DiscreteResourceSpec.Value)})
+		}
+	}
+
+	if handler.agentInventoryAvailable(endpoint) {
+		hostInfo, err := handler.agentHostInfo(r, endpoint)
+		if err != nil {
+			log.Printf("[WARN] [http,endpoints,devices] [endpoint: %s] [message: unable to retrieve the agent inventory] [error: %s]", endpoint.Name, err)
+		} else {
+			for _, device := range hostInfo.PCIDevices {
+				devices.Devices = append(devices.Devices, device)
+				if docker.IsGPUDevice(device.Vendor, device.Device) {
+					devices.GPUs = append(devices.GPUs, device)
+				}
+			}
+		}
+	}
+
+	return response.JSON(w, devices)
+}
+
+// agentInventoryAvailable returns true when the agent of an endpoint can be queried without waiting
+// for an Edge agent to open its tunnel
+func (handler *Handler) agentInventoryAvailable(endpoint *portainer.Endpoint) bool {
+	switch endpoint.Type {
+	case portainer.AgentOnDockerEnvironment:
+		return true
+	case portainer.EdgeAgentOnDockerEnvironment:
+		return handler.ReverseTunnelService.GetTunnelDetails(endpoint.ID).Status == portainer.EdgeAgentActive
+	}
+	return false
+}
+
+// agentHostInfo retrieves the host inventory of the agent through the endpoint proxy
+func (handler *Handler) agentHostInfo(r *http.Request, endpoint *portainer.Endpoint) (*agentHostInfo, error) {
+	proxy := handler.ProxyManager.GetEndpointProxy(endpoint)
+	if proxy == nil {
+		var err error
+		proxy, err = handler.ProxyManager.CreateAndRegisterEndpointProxy(endpoint)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	agentRequest, err := http.NewRequest(http.MethodGet, "/v2/host/info", nil)
+	if err != nil {
+		return nil, err
+	}
+	agentRequest = agentRequest.WithContext(r.Context())
+	if nodeName := r.Header.Get(portainer.PortainerAgentTargetHeader); nodeName != "" {
+		agentRequest.Header.Set(portainer.PortainerAgentTargetHeader, nodeName)
+	}
+
+	recorder := httptest.NewRecorder()
+	proxy.ServeHTTP(recorder, agentRequest)
+
+	if recorder.Code != http.StatusOK {
+		return nil, fmt.Errorf("the agent replied with status %d", recorder.Code)
+	}
+
+	var hostInfo agentHostInfo
+	err = json.NewDecoder(recorder.Body).Decode(&hostInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	return &hostInfo, nil
+}
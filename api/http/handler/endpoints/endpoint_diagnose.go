@@ -0,0 +1,518 @@
+package endpoints
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/versions"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+	"github.com/portainer/portainer/api/crypto"
+	"github.com/portainer/portainer/api/kubernetes/cli"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	diagnosticTimeout    = 5 * time.Second
+	diagnosticPingCount  = 3
+	diagnosticSuccess    = "success"
+	diagnosticFailure    = "failure"
+	diagnosticSkipped    = "skipped"
+	skippedCheckMessage  = "Skipped because a previous check failed"
+	defaultDockerPort    = "2375"
+	defaultDockerTLSPort = "2376"
+)
+
+type (
+	endpointDiagnosticReport struct {
+		EndpointID portainer.EndpointID `json:"EndpointId"`
+		URL        string               `json:"URL"`
+		// Reachable is true when all the checks succeeded
+		Reachable bool              `json:"Reachable"`
+		Checks    []diagnosticCheck `json:"Checks"`
+	}
+
+	diagnosticCheck struct {
+		Name   string `json:"Name"`
+		Status string `json:"Status"`
+		// Duration is the duration of the check in milliseconds
+		Duration int64       `json:"Duration"`
+		Message  string      `json:"Message,omitempty"`
+		Details  interface{} `json:"Details,omitempty"`
+	}
+
+	certificateDetails struct {
+		Subject           string   `json:"Subject"`
+		Issuer            string   `json:"Issuer"`
+		DNSNames          []string `json:"DNSNames"`
+		IPAddresses       []string `json:"IPAddresses"`
+		NotBefore         int64    `json:"NotBefore"`
+		NotAfter          int64    `json:"NotAfter"`
+		Expired           bool     `json:"Expired"`
+		SelfSigned        bool     `json:"SelfSigned"`
+		Verified          bool     `json:"Verified"`
+		VerificationError string   `json:"VerificationError,omitempty"`
+		TLSVersion        string   `json:"TLSVersion"`
+	}
+
+	apiVersionDetails struct {
+		ServerVersion string `json:"ServerVersion"`
+		ClientVersion string `json:"ClientVersion,omitempty"`
+		OSType        string `json:"OSType,omitempty"`
+	}
+
+	latencyDetails struct {
+		Min int64 `json:"Min"`
+		Avg int64 `json:"Avg"`
+		Max int64 `json:"Max"`
+	}
+
+	// diagnosticTarget represents the network location used to reach an endpoint
+	diagnosticTarget struct {
+		host      string
+		port      string
+		socket    string
+		useTLS    bool
+		tlsConfig *tls.Config
+	}
+
+	diagnostician struct {
+		report *endpointDiagnosticReport
+		failed bool
+	}
+)
+
+// POST request on /api/endpoints/:id/diagnose
+//
+// Runs a sequence of connectivity checks against the endpoint (name resolution, TCP connection,
+// TLS handshake, API version negotiation and ping latency). A check is skipped when a previous
+// check failed so that the first failing check identifies the root cause.
+func (handler *Handler) endpointDiagnose(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	endpointID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid endpoint identifier route variable", err}
+	}
+
+	endpoint, err := handler.DataStore.Endpoint().Endpoint(portainer.EndpointID(endpointID))
+	if err == bolterrors.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find an endpoint with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find an endpoint with the specified identifier inside the database", err}
+	}
+
+	err = handler.requestBouncer.AuthorizedEndpointOperation(r, endpoint)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusForbidden, "Permission denied to access endpoint", err}
+	}
+
+	d := &diagnostician{
+		report: &endpointDiagnosticReport{
+			EndpointID: endpoint.ID,
+			URL:        endpoint.URL,
+			Checks:     make([]diagnosticCheck, 0),
+		},
+	}
+
+	if endpoint.Type == portainer.EdgeAgentOnDockerEnvironment || endpoint.Type == portainer.EdgeAgentOnKubernetesEnvironment {
+		d.run("tunnel", func() (string, interface{}, error) {
+			return handler.checkEdgeTunnel(endpoint)
+		})
+	} else {
+		handler.runNetworkChecks(d, endpoint)
+	}
+
+	switch endpoint.Type {
+	case portainer.AzureEnvironment:
+		// the Azure API is only checked at the network level, the credentials are verified when the endpoint is created
+	case portainer.KubernetesLocalEnvironment, portainer.AgentOnKubernetesEnvironment, portainer.EdgeAgentOnKubernetesEnvironment, portainer.KubeconfigKubernetesEnvironment:
+		handler.runKubernetesAPIChecks(d, endpoint)
+	default:
+		handler.runDockerAPIChecks(d, endpoint)
+	}
+
+	d.report.Reachable = !d.failed
+	return response.JSON(w, d.report)
+}
+
+// run executes a check unless a previous check failed and records its result in the report
+func (d *diagnostician) run(name string, check func() (string, interface{}, error)) {
+	result := diagnosticCheck{Name: name, Status: diagnosticSkipped, Message: skippedCheckMessage}
+
+	if !d.failed {
+		start := time.Now()
+		message, details, err := check()
+
+		result.Duration = time.Since(start).Milliseconds()
+		result.Status = diagnosticSuccess
+		result.Message = message
+		result.Details = details
+
+		if err != nil {
+			d.failed = true
+			result.Status = diagnosticFailure
+			result.Message = err.Error()
+		}
+	}
+
+	d.report.Checks = append(d.report.Checks, result)
+}
+
+func (handler *Handler) runNetworkChecks(d *diagnostician, endpoint *portainer.Endpoint) {
+	target, err := newDiagnosticTarget(endpoint)
+	if err != nil {
+		d.run("url", func() (string, interface{}, error) {
+			return "", nil, err
+		})
+		return
+	}
+
+	if target.socket != "" {
+		d.run("socket", func() (string, interface{}, error) {
+			return checkSocket(target.socket)
+		})
+		return
+	}
+
+	d.run("dns", func() (string, interface{}, error) {
+		return checkDNS(target.host)
+	})
+
+	d.run("tcp", func() (string, interface{}, error) {
+		return checkTCP(target.host, target.port)
+	})
+
+	if target.useTLS {
+		d.run("tls", func() (string, interface{}, error) {
+			return checkTLS(target, endpoint.TLSConfig.TLSSkipVerify)
+		})
+	}
+}
+
+func (handler *Handler) runDockerAPIChecks(d *diagnostician, endpoint *portainer.Endpoint) {
+	var latencies []time.Duration
+
+	d.run("api_version", func() (string, interface{}, error) {
+		dockerClient, err := handler.DockerClientFactory.CreateClient(endpoint, "")
+		if err != nil {
+			return "", nil, err
+		}
+		defer dockerClient.Close()
+
+		var details *apiVersionDetails
+		for i := 0; i < diagnosticPingCount; i++ {
+			ctx, cancel := context.WithTimeout(context.Background(), diagnosticTimeout)
+			start := time.Now()
+			ping, err := dockerClient.Ping(ctx)
+			cancel()
+			if err != nil {
+				return "", nil, fmt.Errorf("Unable to ping the Docker API: %s", err)
+			}
+			latencies = append(latencies, time.Since(start))
+
+			if details == nil {
+				details = &apiVersionDetails{
+					ServerVersion: ping.APIVersion,
+					ClientVersion: dockerClient.ClientVersion(),
+					OSType:        ping.OSType,
+				}
+			}
+		}
+
+		if details.ServerVersion != "" && versions.LessThan(details.ServerVersion, details.ClientVersion) {
+			return "", details, fmt.Errorf("The Docker API version of the endpoint (%s) is older than the version required by Portainer (%s)", details.ServerVersion, details.ClientVersion)
+		}
+
+		return fmt.Sprintf("Docker API version %s, Portainer uses version %s", details.ServerVersion, details.ClientVersion), details, nil
+	})
+
+	d.run("ping", func() (string, interface{}, error) {
+		return latencyReport(latencies)
+	})
+}
+
+func (handler *Handler) runKubernetesAPIChecks(d *diagnostician, endpoint *portainer.Endpoint) {
+	var latencies []time.Duration
+
+	d.run("api_version", func() (string, interface{}, error) {
+		kubeClient, err := handler.KubernetesClientFactory.CreateClient(endpoint)
+		if err != nil {
+			return "", nil, err
+		}
+
+		var serverVersion string
+		for i := 0; i < diagnosticPingCount; i++ {
+			start := time.Now()
+			info, err := kubeClient.Discovery().ServerVersion()
+			if err != nil {
+				return "", nil, fmt.Errorf("Unable to retrieve the Kubernetes API version: %s", err)
+			}
+			latencies = append(latencies, time.Since(start))
+			serverVersion = info.GitVersion
+		}
+
+		return fmt.Sprintf("Kubernetes %s", serverVersion), &apiVersionDetails{ServerVersion: serverVersion}, nil
+	})
+
+	d.run("ping", func() (string, interface{}, error) {
+		return latencyReport(latencies)
+	})
+}
+
+func (handler *Handler) checkEdgeTunnel(endpoint *portainer.Endpoint) (string, interface{}, error) {
+	if endpoint.EdgeID == "" {
+		return "", nil, errors.New("The Edge agent has not been associated with this endpoint yet")
+	}
+
+	tunnel := handler.ReverseTunnelService.GetTunnelDetails(endpoint.ID)
+	if tunnel.Status != portainer.EdgeAgentActive {
+		return "", nil, fmt.Errorf("The tunnel is not active (status: %s). It will be opened when the Edge agent polls Portainer, every %d seconds", tunnel.Status, edgeCheckinInterval(handler, endpoint))
+	}
+
+	return fmt.Sprintf("Tunnel active on port %d", tunnel.Port), nil, nil
+}
+
+func edgeCheckinInterval(handler *Handler, endpoint *portainer.Endpoint) int {
+	if endpoint.EdgeCheckinInterval != 0 {
+		return endpoint.EdgeCheckinInterval
+	}
+
+	settings, err := handler.DataStore.Settings().Settings()
+	if err != nil {
+		return 0
+	}
+
+	return settings.EdgeAgentCheckinInterval
+}
+
+func newDiagnosticTarget(endpoint *portainer.Endpoint) (*diagnosticTarget, error) {
+	endpointURL := endpoint.URL
+	if !strings.Contains(endpointURL, "://") {
+		endpointURL = "tcp://" + endpointURL
+	}
+
+	u, err := url.Parse(endpointURL)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid endpoint URL: %s", err)
+	}
+
+	target := &diagnosticTarget{
+		host:   u.Hostname(),
+		port:   u.Port(),
+		useTLS: endpoint.TLSConfig.TLS,
+	}
+
+	switch u.Scheme {
+	case "unix":
+		target.socket = u.Path
+		return target, nil
+	case "npipe":
+		target.socket = u.Path
+		return target, nil
+	case "ssh":
+		target.useTLS = false
+		if target.port == "" {
+			target.port = "22"
+		}
+	case "https":
+		target.useTLS = true
+		if target.port == "" {
+			target.port = "443"
+		}
+	default:
+		if target.port == "" {
+			target.port = defaultDockerPort
+			if target.useTLS {
+				target.port = defaultDockerTLSPort
+			}
+		}
+	}
+
+	if target.host == "" {
+		return nil, errors.New("Invalid endpoint URL: missing host")
+	}
+
+	if target.useTLS {
+		tlsConfig := &tls.Config{}
+		if endpoint.Type == portainer.KubeconfigKubernetesEnvironment {
+			tlsConfig, err = kubeconfigTLSConfig(endpoint)
+			if err != nil {
+				return nil, fmt.Errorf("Unable to load the TLS configuration of the kubeconfig: %s", err)
+			}
+		} else if endpoint.TLSConfig.TLS && endpoint.Type != portainer.AzureEnvironment {
+			tlsConfig, err = crypto.CreateTLSConfigurationFromDisk(endpoint.TLSConfig.TLSCACertPath, endpoint.TLSConfig.TLSCertPath, endpoint.TLSConfig.TLSKeyPath, endpoint.TLSConfig.TLSSkipVerify)
+			if err != nil {
+				return nil, fmt.Errorf("Unable to load the TLS files of the endpoint: %s", err)
+			}
+		}
+		target.tlsConfig = tlsConfig
+	}
+
+	return target, nil
+}
+
+func kubeconfigTLSConfig(endpoint *portainer.Endpoint) (*tls.Config, error) {
+	config, err := cli.KubeconfigRESTConfig(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig, err := rest.TLSConfigFor(config)
+	if err != nil || tlsConfig == nil {
+		return &tls.Config{}, err
+	}
+
+	return tlsConfig, nil
+}
+
+func checkSocket(socketPath string) (string, interface{}, error) {
+	conn, err := createDiagnosticDial(socketPath)
+	if err != nil {
+		return "", nil, fmt.Errorf("Unable to connect to %s, ensure that it is bind-mounted inside the Portainer container: %s", socketPath, err)
+	}
+	conn.Close()
+
+	return fmt.Sprintf("Connected to %s", socketPath), nil, nil
+}
+
+func checkDNS(host string) (string, interface{}, error) {
+	if net.ParseIP(host) != nil {
+		return "IP address, no name resolution required", []string{host}, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), diagnosticTimeout)
+	defer cancel()
+
+	addresses, err := net.DefaultResolver.LookupHost(ctx, host)
+	if err != nil {
+		return "", nil, fmt.Errorf("Unable to resolve %s: %s", host, err)
+	}
+
+	return fmt.Sprintf("%s resolved to %s", host, strings.Join(addresses, ", ")), addresses, nil
+}
+
+func checkTCP(host, port string) (string, interface{}, error) {
+	address := net.JoinHostPort(host, port)
+
+	conn, err := net.DialTimeout("tcp", address, diagnosticTimeout)
+	if err != nil {
+		return "", nil, fmt.Errorf("Unable to open a TCP connection to %s: %s", address, err)
+	}
+	defer conn.Close()
+
+	return fmt.Sprintf("Connected to %s", conn.RemoteAddr().String()), nil, nil
+}
+
+// checkTLS executes a TLS handshake and reports the certificate presented by the server. The
+// certificate chain is verified separately from the handshake so that its details are reported
+// even when it is not trusted.
+func checkTLS(target *diagnosticTarget, skipVerify bool) (string, interface{}, error) {
+	config := target.tlsConfig.Clone()
+	config.InsecureSkipVerify = true
+	if config.ServerName == "" && net.ParseIP(target.host) == nil {
+		config.ServerName = target.host
+	}
+
+	dialer := &net.Dialer{Timeout: diagnosticTimeout}
+	conn, err := tls.DialWithDialer(dialer, "tcp", net.JoinHostPort(target.host, target.port), config)
+	if err != nil {
+		return "", nil, fmt.Errorf("TLS handshake failed: %s", err)
+	}
+	defer conn.Close()
+
+	state := conn.ConnectionState()
+	if len(state.PeerCertificates) == 0 {
+		return "", nil, errors.New("The server did not present any certificate")
+	}
+
+	certificate := state.PeerCertificates[0]
+	details := &certificateDetails{
+		Subject:     certificate.Subject.String(),
+		Issuer:      certificate.Issuer.String(),
+		DNSNames:    certificate.DNSNames,
+		IPAddresses: make([]string, 0),
+		NotBefore:   certificate.NotBefore.Unix(),
+		NotAfter:    certificate.NotAfter.Unix(),
+		Expired:     time.Now().After(certificate.NotAfter),
+		SelfSigned:  certificate.Subject.String() == certificate.Issuer.String(),
+		TLSVersion:  tlsVersionName(state.Version),
+	}
+	for _, ip := range certificate.IPAddresses {
+		details.IPAddresses = append(details.IPAddresses, ip.String())
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range state.PeerCertificates[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	_, err = certificate.Verify(x509.VerifyOptions{
+		DNSName:       target.host,
+		Roots:         target.tlsConfig.RootCAs,
+		Intermediates: intermediates,
+	})
+	details.Verified = err == nil
+	if err != nil {
+		details.VerificationError = err.Error()
+	}
+
+	if !details.Verified && !skipVerify {
+		return "", details, fmt.Errorf("The certificate presented by the server is not trusted: %s", details.VerificationError)
+	}
+
+	message := fmt.Sprintf("%s handshake completed, certificate issued to %s", details.TLSVersion, details.Subject)
+	if !details.Verified {
+		message += " (not verified, TLS verification is disabled for this endpoint)"
+	}
+
+	return message, details, nil
+}
+
+func latencyReport(latencies []time.Duration) (string, interface{}, error) {
+	if len(latencies) == 0 {
+		return "", nil, errors.New("No latency measurement available")
+	}
+
+	min, max, total := latencies[0], latencies[0], time.Duration(0)
+	for _, latency := range latencies {
+		if latency < min {
+			min = latency
+		}
+		if latency > max {
+			max = latency
+		}
+		total += latency
+	}
+
+	details := &latencyDetails{
+		Min: min.Milliseconds(),
+		Avg: (total / time.Duration(len(latencies))).Milliseconds(),
+		Max: max.Milliseconds(),
+	}
+
+	return fmt.Sprintf("Average latency of %dms over %d requests", details.Avg, len(latencies)), details, nil
+}
+
+func tlsVersionName(version uint16) string {
+	switch version {
+	case tls.VersionTLS10:
+		return "TLS 1.0"
+	case tls.VersionTLS11:
+		return "TLS 1.1"
+	case tls.VersionTLS12:
+		return "TLS 1.2"
+	case tls.VersionTLS13:
+		return "TLS 1.3"
+	}
+	return fmt.Sprintf("unknown (0x%04x)", version)
+}
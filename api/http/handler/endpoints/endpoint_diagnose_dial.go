@@ -0,0 +1,11 @@
+// +build !windows
+
+package endpoints
+
+import (
+	"net"
+)
+
+func createDiagnosticDial(socketPath string) (net.Conn, error) {
+	return net.DialTimeout("unix", socketPath, diagnosticTimeout)
+}
@@ -0,0 +1,14 @@
+// +build windows
+
+package endpoints
+
+import (
+	"net"
+
+	"github.com/Microsoft/go-winio"
+)
+
+func createDiagnosticDial(socketPath string) (net.Conn, error) {
+	timeout := diagnosticTimeout
+	return winio.DialPipe(socketPath, &timeout)
+}
@@ -0,0 +1,52 @@
+package endpoints
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+	"github.com/portainer/portainer/api/internal/dockercontext"
+)
+
+// GET request on /api/endpoints/:id/docker_context
+//
+// Exports a Docker endpoint as an archive that can be imported with the docker context import command.
+// The TLS files of the endpoint are embedded in the archive.
+func (handler *Handler) endpointDockerContextExport(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	endpointID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid endpoint identifier route variable", err}
+	}
+
+	endpoint, err := handler.DataStore.Endpoint().Endpoint(portainer.EndpointID(endpointID))
+	if err == bolterrors.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find an endpoint with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find an endpoint with the specified identifier inside the database", err}
+	}
+
+	err = handler.requestBouncer.AuthorizedEndpointOperation(r, endpoint)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusForbidden, "Permission denied to access endpoint", err}
+	}
+
+	context, err := dockercontext.NewContext(endpoint, handler.FileService)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Unable to export the endpoint as a Docker context", err}
+	}
+
+	var archive bytes.Buffer
+	err = dockercontext.WriteArchive(&archive, context)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to create the Docker context archive", err}
+	}
+
+	w.Header().Set("Content-Type", "application/x-tar")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s.dockercontext", context.Name))
+	w.Write(archive.Bytes())
+	return nil
+}
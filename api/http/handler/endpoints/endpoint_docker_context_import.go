@@ -0,0 +1,118 @@
+package endpoints
+
+import (
+	"errors"
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/dockercontext"
+	"github.com/portainer/portainer/api/internal/organization"
+)
+
+type endpointDockerContextImportPayload struct {
+	File []byte
+	// Name overrides the name of the context
+	Name    string
+	GroupID int
+	// SSHPrivateKeyFile is required for the contexts using SSH
+	SSHPrivateKeyFile []byte
+}
+
+var errEndpointNameConflict = errors.New("An endpoint with the same name already exists")
+
+func (payload *endpointDockerContextImportPayload) Validate(r *http.Request) error {
+	file, _, err := request.RetrieveMultiPartFormFile(r, "file")
+	if err != nil {
+		return errors.New("Invalid context archive. Ensure that the file exported with docker context export is uploaded correctly")
+	}
+	payload.File = file
+
+	name, _ := request.RetrieveMultiPartFormValue(r, "Name", true)
+	payload.Name = name
+
+	groupID, _ := request.RetrieveNumericMultiPartFormValue(r, "GroupID", true)
+	if groupID == 0 {
+		groupID = 1
+	}
+	payload.GroupID = groupID
+
+	privateKey, _, err := request.RetrieveMultiPartFormFile(r, "SSHPrivateKeyFile")
+	if err == nil {
+		payload.SSHPrivateKeyFile = privateKey
+	}
+
+	return nil
+}
+
+// POST request on /api/endpoints/import/docker_context
+//
+// Creates an endpoint from a context exported with the docker context export command,
+// including the TLS material embedded in the context.
+func (handler *Handler) endpointDockerContextImport(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	payload := &endpointDockerContextImportPayload{}
+	err := payload.Validate(r)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	securityContext, err := security.RetrieveRestrictedRequestContext(r)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve info from request context", err}
+	}
+
+	err = organization.CheckEndpointQuota(handler.DataStore, securityContext.OrganizationID, 1, false)
+	if quotaErr, ok := err.(*organization.QuotaExceededError); ok {
+		return &httperror.HandlerError{http.StatusForbidden, quotaErr.Error(), quotaErr}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to verify the quotas of the organization", err}
+	}
+
+	context, err := dockercontext.LoadArchive(payload.File)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Unable to read the Docker context", err}
+	}
+
+	if payload.Name != "" {
+		context.Name = payload.Name
+	}
+
+	endpoints, err := handler.DataStore.Endpoint().Endpoints()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve endpoints from the database", err}
+	}
+
+	for _, endpoint := range endpoints {
+		if endpoint.Name == context.Name {
+			return &httperror.HandlerError{http.StatusConflict, "Unable to import the Docker context", errEndpointNameConflict}
+		}
+	}
+
+	endpoint, err := dockercontext.NewEndpoint(context, handler.DataStore.Endpoint().GetNextIdentifier(), payload.SSHPrivateKeyFile, handler.FileService)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Unable to create an endpoint from the Docker context", err}
+	}
+	endpoint.GroupID = portainer.EndpointGroupID(payload.GroupID)
+
+	endpoint.OrganizationID = securityContext.OrganizationID
+
+	httpErr := handler.snapshotAndPersistEndpoint(endpoint)
+	if httpErr != nil {
+		dockercontext.DeleteEndpointFiles(endpoint, handler.FileService)
+		return httpErr
+	}
+
+	err = handler.DataStore.EndpointRelation().CreateEndpointRelation(&portainer.EndpointRelation{
+		EndpointID: endpoint.ID,
+		EdgeStacks: map[portainer.EdgeStackID]bool{},
+	})
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist the relation object inside the database", err}
+	}
+
+	hideFields(endpoint)
+	return response.JSON(w, endpoint)
+}
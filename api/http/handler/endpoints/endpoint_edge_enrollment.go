@@ -0,0 +1,316 @@
+package endpoints
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/asaskevich/govalidator"
+	"github.com/gorilla/securecookie"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/organization"
+	"github.com/portainer/portainer/api/internal/tag"
+)
+
+var (
+	errInvalidEnrollmentKey = errors.New("Invalid enrollment key")
+	errDeviceAlreadyClaimed = errors.New("The device already claimed its endpoint")
+)
+
+type (
+	edgeDevicesRegisterPayload struct {
+		// URL is the URL of the Portainer instance the devices connect to
+		URL     string
+		Devices []edgeDeviceRegistration
+	}
+
+	edgeDeviceRegistration struct {
+		Name string
+		// DeviceID is an optional hardware identifier the device must present to claim its endpoint
+		DeviceID            string
+		GroupID             portainer.EndpointGroupID
+		TagIDs              []portainer.TagID
+		Metadata            map[string]string
+		EdgeCheckinInterval int
+		// EdgeTunnelZone is the name of the network zone of the device, as defined in the settings
+		EdgeTunnelZone string
+	}
+
+	edgeDeviceEnrollmentResponse struct {
+		EndpointID    portainer.EndpointID `json:"EndpointId"`
+		Name          string               `json:"Name"`
+		DeviceID      string               `json:"DeviceID,omitempty"`
+		EnrollmentKey string               `json:"EnrollmentKey"`
+	}
+
+	edgeDeviceClaimPayload struct {
+		EnrollmentKey string
+		DeviceID      string
+	}
+
+	edgeDeviceClaimResponse struct {
+		EndpointID portainer.EndpointID `json:"EndpointId"`
+		EdgeKey    string               `json:"EdgeKey"`
+	}
+)
+
+func (payload *edgeDevicesRegisterPayload) Validate(r *http.Request) error {
+	if govalidator.IsNull(payload.URL) || !govalidator.IsURL(payload.URL) {
+		return errors.New("Invalid Portainer instance URL")
+	}
+	if len(payload.Devices) == 0 {
+		return errors.New("At least one device is required")
+	}
+
+	deviceIDs := make(map[string]bool)
+	for _, device := range payload.Devices {
+		if govalidator.IsNull(device.Name) {
+			return errors.New("Invalid device name")
+		}
+		if device.DeviceID != "" {
+			if deviceIDs[device.DeviceID] {
+				return fmt.Errorf("Duplicate device identifier: %s", device.DeviceID)
+			}
+			deviceIDs[device.DeviceID] = true
+		}
+		err := tag.ValidateMetadata(device.Metadata)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (payload *edgeDeviceClaimPayload) Validate(r *http.Request) error {
+	if govalidator.IsNull(payload.EnrollmentKey) {
+		return errInvalidEnrollmentKey
+	}
+	return nil
+}
+
+// POST request on /api/endpoints/edge_devices
+//
+// Pre-registers Edge devices: an Edge endpoint is created for each device with its group, tags and
+// metadata, along with an enrollment key. The enrollment keys are only returned by this request,
+// they are meant to be provisioned on the devices which claim their endpoint on their first start.
+func (handler *Handler) edgeDevicesRegister(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	var payload edgeDevicesRegisterPayload
+	err := request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	endpoints, err := handler.DataStore.Endpoint().Endpoints()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve endpoints from the database", err}
+	}
+
+	for _, endpoint := range endpoints {
+		for _, device := range payload.Devices {
+			if device.DeviceID != "" && endpoint.EdgeEnrollment != nil && endpoint.EdgeEnrollment.DeviceID == device.DeviceID {
+				return &httperror.HandlerError{http.StatusConflict, "A device with the same identifier is already registered", fmt.Errorf("device %s is already registered", device.DeviceID)}
+			}
+		}
+	}
+
+	securityContext, err := security.RetrieveRestrictedRequestContext(r)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve info from request context", err}
+	}
+
+	err = organization.CheckEndpointQuota(handler.DataStore, securityContext.OrganizationID, len(payload.Devices), true)
+	if quotaErr, ok := err.(*organization.QuotaExceededError); ok {
+		return &httperror.HandlerError{http.StatusForbidden, quotaErr.Error(), quotaErr}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to verify the quotas of the organization", err}
+	}
+
+	enrollments := make([]edgeDeviceEnrollmentResponse, 0, len(payload.Devices))
+	for _, device := range payload.Devices {
+		groupID := device.GroupID
+		if groupID == 0 {
+			groupID = 1
+		}
+
+		_, err := handler.DataStore.EndpointGroup().EndpointGroup(groupID)
+		if err == bolterrors.ErrObjectNotFound {
+			return &httperror.HandlerError{http.StatusNotFound, "Unable to find an endpoint group with the specified identifier inside the database", err}
+		} else if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find an endpoint group with the specified identifier inside the database", err}
+		}
+
+		tagIDs := device.TagIDs
+		if tagIDs == nil {
+			tagIDs = make([]portainer.TagID, 0)
+		}
+
+		endpoint, handlerErr := handler.createEdgeAgentEndpoint(&endpointCreatePayload{
+			Name:                device.Name,
+			URL:                 payload.URL,
+			GroupID:             int(groupID),
+			TagIDs:              tagIDs,
+			EdgeCheckinInterval: device.EdgeCheckinInterval,
+			EdgeTunnelZone:      device.EdgeTunnelZone,
+			OrganizationID:      securityContext.OrganizationID,
+		})
+		if handlerErr != nil {
+			return handlerErr
+		}
+
+		enrollmentKey := generateEnrollmentKey()
+		endpoint.Metadata = device.Metadata
+		endpoint.EdgeEnrollment = &portainer.EdgeEnrollment{
+			KeyHash:      hashEnrollmentKey(enrollmentKey),
+			DeviceID:     device.DeviceID,
+			CreationDate: time.Now().Unix(),
+		}
+
+		err = handler.DataStore.Endpoint().UpdateEndpoint(endpoint.ID, endpoint)
+		if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist endpoint changes inside the database", err}
+		}
+
+		handlerErr = handler.createEndpointRelation(endpoint)
+		if handlerErr != nil {
+			return handlerErr
+		}
+
+		enrollments = append(enrollments, edgeDeviceEnrollmentResponse{
+			EndpointID:    endpoint.ID,
+			Name:          endpoint.Name,
+			DeviceID:      device.DeviceID,
+			EnrollmentKey: enrollmentKey,
+		})
+	}
+
+	return response.JSON(w, enrollments)
+}
+
+// POST request on /api/endpoints/:id/enrollment_key
+// Generates a new enrollment key for a pre-registered device which did not claim its endpoint yet.
+func (handler *Handler) edgeDeviceEnrollmentKeyRenew(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	endpointID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid endpoint identifier route variable", err}
+	}
+
+	endpoint, err := handler.DataStore.Endpoint().Endpoint(portainer.EndpointID(endpointID))
+	if err == bolterrors.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find an endpoint with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find an endpoint with the specified identifier inside the database", err}
+	}
+
+	err = handler.requestBouncer.AuthorizedEndpointOperation(r, endpoint)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusForbidden, "Permission denied to access endpoint", err}
+	}
+
+	if endpoint.EdgeEnrollment == nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "The endpoint was not pre-registered", errors.New("The endpoint was not pre-registered")}
+	}
+	if endpoint.EdgeEnrollment.Claimed {
+		return &httperror.HandlerError{http.StatusConflict, "The device already claimed its endpoint", errDeviceAlreadyClaimed}
+	}
+
+	enrollmentKey := generateEnrollmentKey()
+	endpoint.EdgeEnrollment.KeyHash = hashEnrollmentKey(enrollmentKey)
+
+	err = handler.DataStore.Endpoint().UpdateEndpoint(endpoint.ID, endpoint)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist endpoint changes inside the database", err}
+	}
+
+	return response.JSON(w, edgeDeviceEnrollmentResponse{
+		EndpointID:    endpoint.ID,
+		Name:          endpoint.Name,
+		DeviceID:      endpoint.EdgeEnrollment.DeviceID,
+		EnrollmentKey: enrollmentKey,
+	})
+}
+
+// POST request on /api/endpoints/edge_devices/claim
+//
+// Called by a pre-registered device on its first start. The enrollment key is matched with the
+// pre-registered endpoint, which is bound to the Edge identifier of the agent. The enrollment key
+// cannot be used again, the device receives the Edge key used to connect to its endpoint.
+func (handler *Handler) edgeDeviceClaim(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	var payload edgeDeviceClaimPayload
+	err := request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	edgeIdentifier := r.Header.Get(portainer.PortainerAgentEdgeIDHeader)
+	if edgeIdentifier == "" {
+		return &httperror.HandlerError{http.StatusBadRequest, "Edge identifier header is missing", errors.New("missing Edge identifier")}
+	}
+
+	agentPlatformNumber, err := strconv.Atoi(r.Header.Get(portainer.HTTPResponseAgentPlatform))
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Unable to parse agent platform header", err}
+	}
+
+	handler.enrollmentMutex.Lock()
+	defer handler.enrollmentMutex.Unlock()
+
+	endpoints, err := handler.DataStore.Endpoint().Endpoints()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve endpoints from the database", err}
+	}
+
+	keyHash := hashEnrollmentKey(payload.EnrollmentKey)
+
+	var endpoint *portainer.Endpoint
+	for idx := range endpoints {
+		enrollment := endpoints[idx].EdgeEnrollment
+		if enrollment != nil && !enrollment.Claimed && enrollment.KeyHash == keyHash {
+			endpoint = &endpoints[idx]
+			break
+		}
+	}
+
+	if endpoint == nil {
+		return &httperror.HandlerError{http.StatusForbidden, "Invalid enrollment key", errInvalidEnrollmentKey}
+	}
+	if endpoint.EdgeEnrollment.DeviceID != "" && endpoint.EdgeEnrollment.DeviceID != payload.DeviceID {
+		return &httperror.HandlerError{http.StatusForbidden, "The device identifier does not match the pre-registered device", errInvalidEnrollmentKey}
+	}
+
+	if portainer.AgentPlatform(agentPlatformNumber) == portainer.AgentPlatformKubernetes {
+		endpoint.Type = portainer.EdgeAgentOnKubernetesEnvironment
+	} else {
+		endpoint.Type = portainer.EdgeAgentOnDockerEnvironment
+	}
+
+	endpoint.EdgeID = edgeIdentifier
+	endpoint.EdgeEnrollment.KeyHash = ""
+	endpoint.EdgeEnrollment.Claimed = true
+	endpoint.EdgeEnrollment.ClaimDate = time.Now().Unix()
+	endpoint.EdgeEnrollment.ClaimAddress = r.RemoteAddr
+
+	err = handler.DataStore.Endpoint().UpdateEndpoint(endpoint.ID, endpoint)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist endpoint changes inside the database", err}
+	}
+
+	return response.JSON(w, edgeDeviceClaimResponse{EndpointID: endpoint.ID, EdgeKey: endpoint.EdgeKey})
+}
+
+func generateEnrollmentKey() string {
+	return hex.EncodeToString(securecookie.GenerateRandomKey(32))
+}
+
+func hashEnrollmentKey(enrollmentKey string) string {
+	hash := sha256.Sum256([]byte(enrollmentKey))
+	return hex.EncodeToString(hash[:])
+}
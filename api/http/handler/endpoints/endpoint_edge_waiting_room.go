@@ -0,0 +1,121 @@
+package endpoints
+
+import (
+	"errors"
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+type (
+	waitingRoomPayload struct {
+		EndpointIDs []portainer.EndpointID
+	}
+
+	waitingRoomResponse struct {
+		// Updated are the endpoints approved or rejected
+		Updated []portainer.EndpointID `json:"Updated"`
+		// Ignored are the endpoints which were not waiting for approval
+		Ignored []portainer.EndpointID `json:"Ignored"`
+	}
+)
+
+func (payload *waitingRoomPayload) Validate(r *http.Request) error {
+	if len(payload.EndpointIDs) == 0 {
+		return errors.New("At least one endpoint identifier is required")
+	}
+	return nil
+}
+
+// GET request on /api/endpoints/waiting_room
+// Returns the Edge endpoints waiting for the approval of an administrator.
+func (handler *Handler) endpointWaitingRoomList(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	securityContext, err := security.RetrieveRestrictedRequestContext(r)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve info from request context", err}
+	}
+
+	endpoints, err := handler.DataStore.Endpoint().Endpoints()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve endpoints from the database", err}
+	}
+
+	pendingEndpoints := make([]portainer.Endpoint, 0)
+	for idx := range endpoints {
+		if endpoints[idx].EdgePendingApproval && security.AuthorizedOrganizationAccess(endpoints[idx].OrganizationID, securityContext.OrganizationID, securityContext.IsAdmin) {
+			hideFields(&endpoints[idx])
+			pendingEndpoints = append(pendingEndpoints, endpoints[idx])
+		}
+	}
+
+	return response.JSON(w, pendingEndpoints)
+}
+
+// POST request on /api/endpoints/waiting_room/approve
+// The approved endpoints can be managed, their agents receive the Edge stacks and jobs on their next check-in.
+func (handler *Handler) endpointWaitingRoomApprove(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	return handler.updateWaitingRoom(w, r, func(endpoint *portainer.Endpoint) {
+		endpoint.EdgePendingApproval = false
+	})
+}
+
+// POST request on /api/endpoints/waiting_room/reject
+//
+// The agents of the rejected endpoints are unbound from their endpoint and cannot check in again,
+// the endpoints are kept and wait for the check-in of another agent.
+func (handler *Handler) endpointWaitingRoomReject(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	return handler.updateWaitingRoom(w, r, func(endpoint *portainer.Endpoint) {
+		endpoint.EdgeRejectedIDs = append(endpoint.EdgeRejectedIDs, endpoint.EdgeID)
+		endpoint.EdgeID = ""
+		endpoint.EdgePendingApproval = false
+	})
+}
+
+func (handler *Handler) updateWaitingRoom(w http.ResponseWriter, r *http.Request, update func(endpoint *portainer.Endpoint)) *httperror.HandlerError {
+	var payload waitingRoomPayload
+	err := request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	securityContext, err := security.RetrieveRestrictedRequestContext(r)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve info from request context", err}
+	}
+
+	result := waitingRoomResponse{
+		Updated: make([]portainer.EndpointID, 0),
+		Ignored: make([]portainer.EndpointID, 0),
+	}
+
+	for _, endpointID := range payload.EndpointIDs {
+		endpoint, err := handler.DataStore.Endpoint().Endpoint(endpointID)
+		if err == bolterrors.ErrObjectNotFound {
+			result.Ignored = append(result.Ignored, endpointID)
+			continue
+		} else if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find an endpoint with the specified identifier inside the database", err}
+		}
+
+		if !endpoint.EdgePendingApproval || !security.AuthorizedOrganizationAccess(endpoint.OrganizationID, securityContext.OrganizationID, securityContext.IsAdmin) {
+			result.Ignored = append(result.Ignored, endpointID)
+			continue
+		}
+
+		update(endpoint)
+
+		err = handler.DataStore.Endpoint().UpdateEndpoint(endpoint.ID, endpoint)
+		if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist endpoint changes inside the database", err}
+		}
+
+		result.Updated = append(result.Updated, endpointID)
+	}
+
+	return response.JSON(w, result)
+}
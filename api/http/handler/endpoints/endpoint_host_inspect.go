@@ -0,0 +1,63 @@
+package endpoints
+
+import (
+	"errors"
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+	"github.com/portainer/portainer/api/internal/alerting"
+)
+
+type endpointHostResponse struct {
+	Time     int64                          `json:"Time"`
+	TotalCPU int                            `json:"TotalCPU"`
+	Host     *portainer.DockerHostSnapshot  `json:"Host"`
+	Pressure *alerting.HostPressure         `json:"Pressure"`
+	Settings portainer.HostAlertingSettings `json:"Settings"`
+}
+
+// GET request on /api/endpoints/:id/host
+//
+// Returns the host resource usage of a Docker endpoint as collected by the latest snapshot,
+// as well as the resources used above the alerting thresholds.
+func (handler *Handler) endpointHostInspect(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	endpointID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid endpoint identifier route variable", err}
+	}
+
+	endpoint, err := handler.DataStore.Endpoint().Endpoint(portainer.EndpointID(endpointID))
+	if err == bolterrors.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find an endpoint with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find an endpoint with the specified identifier inside the database", err}
+	}
+
+	err = handler.requestBouncer.AuthorizedEndpointOperation(r, endpoint)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusForbidden, "Permission denied to access endpoint", err}
+	}
+
+	if len(endpoint.Snapshots) == 0 || endpoint.Snapshots[0].Host == nil {
+		return &httperror.HandlerError{http.StatusNotFound, "No host resource usage available for this endpoint", errors.New("The endpoint snapshot does not contain host information")}
+	}
+
+	settings, err := handler.DataStore.Settings().Settings()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve the settings from the database", err}
+	}
+
+	snapshot := &endpoint.Snapshots[0]
+
+	return response.JSON(w, &endpointHostResponse{
+		Time:     snapshot.Time,
+		TotalCPU: snapshot.TotalCPU,
+		Host:     snapshot.Host,
+		Pressure: alerting.EvaluateHostPressure(snapshot.Host, snapshot.TotalCPU, &settings.HostAlerting),
+		Settings: settings.HostAlerting,
+	})
+}
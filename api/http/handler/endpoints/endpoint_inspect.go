@@ -10,6 +10,12 @@ import (
 	"github.com/portainer/portainer/api/bolt/errors"
 )
 
+type endpointInspectResponse struct {
+	*portainer.Endpoint
+	// EdgeTunnelStats are the statistics of the tunnel of an Edge endpoint since the start of Portainer
+	EdgeTunnelStats *portainer.EdgeTunnelStats `json:"EdgeTunnelStats,omitempty"`
+}
+
 // GET request on /api/endpoints/:id
 func (handler *Handler) endpointInspect(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
 	endpointID, err := request.RetrieveNumericRouteVariableValue(r, "id")
@@ -31,5 +37,11 @@ func (handler *Handler) endpointInspect(w http.ResponseWriter, r *http.Request)
 
 	hideFields(endpoint)
 
-	return response.JSON(w, endpoint)
+	inspectResponse := endpointInspectResponse{Endpoint: endpoint}
+	if endpoint.Type == portainer.EdgeAgentOnDockerEnvironment || endpoint.Type == portainer.EdgeAgentOnKubernetesEnvironment {
+		tunnelStats := handler.ReverseTunnelService.GetTunnelStats(endpoint.ID)
+		inspectResponse.EdgeTunnelStats = &tunnelStats
+	}
+
+	return response.JSON(w, inspectResponse)
 }
@@ -0,0 +1,239 @@
+package endpoints
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gofrs/uuid"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+	"github.com/portainer/portainer/api/docker"
+)
+
+const (
+	agentImage       = "portainer/agent:" + portainer.APIVersion
+	defaultAgentPort = "9001"
+	agentHelmRepo    = "https://portainer.github.io/k8s/"
+)
+
+// endpointInstallSnippets contains the commands deploying the agent of an endpoint,
+// only the snippets relevant to the endpoint type are defined
+type endpointInstallSnippets struct {
+	DockerRun     string `json:"DockerRun,omitempty"`
+	DockerService string `json:"DockerService,omitempty"`
+	Compose       string `json:"Compose,omitempty"`
+	Helm          string `json:"Helm,omitempty"`
+}
+
+// agentMount represents a host path or volume mounted in the agent container
+type agentMount struct {
+	source string
+	target string
+}
+
+var errNoAgentEndpoint = errors.New("The endpoint is not connected to a Portainer agent")
+
+// GET request on /api/endpoints/:id/install_snippets
+//
+// Returns the ready-to-run commands deploying the agent or Edge agent of an endpoint,
+// with the agent port, Edge identifier and Edge key of the endpoint pre-filled.
+func (handler *Handler) endpointInstallSnippets(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	endpointID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid endpoint identifier route variable", err}
+	}
+
+	endpoint, err := handler.DataStore.Endpoint().Endpoint(portainer.EndpointID(endpointID))
+	if err == bolterrors.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find an endpoint with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find an endpoint with the specified identifier inside the database", err}
+	}
+
+	err = handler.requestBouncer.AuthorizedEndpointOperation(r, endpoint)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusForbidden, "Permission denied to access endpoint", err}
+	}
+
+	switch endpoint.Type {
+	case portainer.AgentOnDockerEnvironment:
+		return response.JSON(w, agentDockerSnippets(endpoint))
+	case portainer.AgentOnKubernetesEnvironment:
+		return response.JSON(w, &endpointInstallSnippets{Helm: agentHelmSnippet(nil)})
+	case portainer.EdgeAgentOnDockerEnvironment, portainer.EdgeAgentOnKubernetesEnvironment:
+		edgeID := endpoint.EdgeID
+		if edgeID == "" {
+			id, err := uuid.NewV4()
+			if err != nil {
+				return &httperror.HandlerError{http.StatusInternalServerError, "Unable to generate the Edge identifier", err}
+			}
+			edgeID = id.String()
+		}
+
+		environment := [][2]string{{"EDGE", "1"}, {"EDGE_ID", edgeID}, {"EDGE_KEY", endpoint.EdgeKey}, {"CAP_HOST_MANAGEMENT", "1"}}
+		if endpoint.Type == portainer.EdgeAgentOnKubernetesEnvironment {
+			return response.JSON(w, &endpointInstallSnippets{Helm: agentHelmSnippet(environment)})
+		}
+		return response.JSON(w, edgeAgentDockerSnippets(endpoint, environment))
+	}
+
+	return &httperror.HandlerError{http.StatusBadRequest, "Unable to generate the agent install snippets", errNoAgentEndpoint}
+}
+
+// agentMounts returns the host paths mounted in the agent container, depending on the operating system of the host
+func agentMounts(endpoint *portainer.Endpoint) []agentMount {
+	if docker.EndpointOSType(endpoint) == docker.OSTypeWindows {
+		return []agentMount{
+			{`\\.\pipe\docker_engine`, `\\.\pipe\docker_engine`},
+			{`C:\ProgramData\docker\volumes`, `C:\ProgramData\docker\volumes`},
+		}
+	}
+
+	return []agentMount{
+		{"/var/run/docker.sock", "/var/run/docker.sock"},
+		{"/var/lib/docker/volumes", "/var/lib/docker/volumes"},
+	}
+}
+
+func agentPort(endpoint *portainer.Endpoint) string {
+	endpointURL, err := url.Parse(endpoint.URL)
+	if err != nil || endpointURL.Port() == "" {
+		return defaultAgentPort
+	}
+	return endpointURL.Port()
+}
+
+func agentDockerSnippets(endpoint *portainer.Endpoint) *endpointInstallSnippets {
+	port := agentPort(endpoint)
+	mounts := agentMounts(endpoint)
+
+	run := []string{"docker run -d", fmt.Sprintf("-p %s:9001", port), "--name portainer_agent", "--restart=always"}
+	service := []string{"docker network create --driver overlay portainer_agent_network &&", "docker service create",
+		"--name portainer_agent", "--network portainer_agent_network", fmt.Sprintf("-p %s:9001/tcp", port), "--mode global",
+		"-e AGENT_CLUSTER_ADDR=tasks.portainer_agent"}
+	for _, mount := range mounts {
+		run = append(run, fmt.Sprintf("-v %s:%s", mount.source, mount.target))
+		service = append(service, fmt.Sprintf("--mount type=%s,src=%s,dst=%s", mountType(mount), mount.source, mount.target))
+	}
+	run = append(run, agentImage)
+	service = append(service, agentImage)
+
+	compose := []string{
+		"version: '3.2'",
+		"services:",
+		"  agent:",
+		"    image: " + agentImage,
+		"    environment:",
+		"      AGENT_CLUSTER_ADDR: tasks.agent",
+		"    ports:",
+		fmt.Sprintf("      - \"%s:9001\"", port),
+		"    volumes:",
+	}
+	compose = append(compose, composeVolumes(mounts)...)
+	compose = append(compose,
+		"    networks:",
+		"      - agent_network",
+		"    deploy:",
+		"      mode: global",
+		"networks:",
+		"  agent_network:",
+		"    driver: overlay",
+		"    attachable: true",
+	)
+
+	return &endpointInstallSnippets{
+		DockerRun:     strings.Join(run, " \\\n  "),
+		DockerService: strings.Join(service, " \\\n  "),
+		Compose:       strings.Join(compose, "\n") + "\n",
+	}
+}
+
+func edgeAgentDockerSnippets(endpoint *portainer.Endpoint, environment [][2]string) *endpointInstallSnippets {
+	mounts := append(agentMounts(endpoint), agentMount{"portainer_agent_data", "/data"})
+	if docker.EndpointOSType(endpoint) != docker.OSTypeWindows {
+		mounts = append(mounts, agentMount{"/", "/host"})
+	}
+
+	run := []string{"docker run -d", "--name portainer_edge_agent", "--restart always"}
+	service := []string{"docker network create --driver overlay portainer_agent_network &&", "docker service create",
+		"--name portainer_edge_agent", "--network portainer_agent_network", "--mode global",
+		"-e AGENT_CLUSTER_ADDR=tasks.portainer_edge_agent"}
+	for _, variable := range environment {
+		run = append(run, fmt.Sprintf("-e %s=%s", variable[0], variable[1]))
+		service = append(service, fmt.Sprintf("-e %s=%s", variable[0], variable[1]))
+	}
+	for _, mount := range mounts {
+		run = append(run, fmt.Sprintf("-v %s:%s", mount.source, mount.target))
+		service = append(service, fmt.Sprintf("--mount type=%s,src=%s,dst=%s", mountType(mount), mount.source, mount.target))
+	}
+	run = append(run, agentImage)
+	service = append(service, agentImage)
+
+	compose := []string{
+		"version: '3.2'",
+		"services:",
+		"  edge_agent:",
+		"    image: " + agentImage,
+		"    restart: always",
+		"    environment:",
+	}
+	for _, variable := range environment {
+		compose = append(compose, fmt.Sprintf("      %s: \"%s\"", variable[0], variable[1]))
+	}
+	compose = append(compose, "    volumes:")
+	compose = append(compose, composeVolumes(mounts)...)
+	compose = append(compose,
+		"volumes:",
+		"  portainer_agent_data:",
+	)
+
+	return &endpointInstallSnippets{
+		DockerRun:     strings.Join(run, " \\\n  "),
+		DockerService: strings.Join(service, " \\\n  "),
+		Compose:       strings.Join(compose, "\n") + "\n",
+	}
+}
+
+func composeVolumes(mounts []agentMount) []string {
+	volumes := make([]string, 0, len(mounts))
+	for _, mount := range mounts {
+		volumes = append(volumes, fmt.Sprintf("      - type: %s\n        source: %s\n        target: %s", mountType(mount), mount.source, mount.target))
+	}
+	return volumes
+}
+
+func mountType(mount agentMount) string {
+	if strings.HasPrefix(mount.source, `\\.\pipe\`) {
+		return "npipe"
+	}
+	if !strings.ContainsAny(mount.source, `/\`) {
+		return "volume"
+	}
+	return "bind"
+}
+
+func agentHelmSnippet(environment [][2]string) string {
+	release := "portainer-agent"
+	if len(environment) > 0 {
+		release = "portainer-edge-agent"
+	}
+
+	lines := []string{
+		fmt.Sprintf("helm repo add portainer %s &&", agentHelmRepo),
+		"helm repo update &&",
+		fmt.Sprintf("helm install %s portainer/portainer-agent", release),
+		"--create-namespace -n portainer",
+		fmt.Sprintf("--set image.tag=%s", portainer.APIVersion),
+	}
+	for _, variable := range environment {
+		lines = append(lines, fmt.Sprintf("--set env.%s=%s", variable[0], variable[1]))
+	}
+
+	return strings.Join(lines, " \\\n  ")
+}
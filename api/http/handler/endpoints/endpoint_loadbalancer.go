@@ -0,0 +1,233 @@
+package endpoints
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+	"github.com/portainer/portainer/api/internal/loadbalancer"
+)
+
+var errLoadBalancerIntegrationDisabled = errors.New("The load balancer integration of the endpoint is disabled")
+
+type loadBalancerIntegrationUpdatePayload struct {
+	Type    portainer.LoadBalancerIntegrationType
+	Enabled bool
+	Address string
+	URL     string
+	// Token is kept unchanged when it is not specified
+	Token       string
+	FilePath    string
+	Domain      string
+	EntryPoints []string
+}
+
+func (payload *loadBalancerIntegrationUpdatePayload) Validate(r *http.Request) error {
+	return loadbalancer.ValidateIntegration(&portainer.LoadBalancerIntegration{
+		Type:        payload.Type,
+		URL:         payload.URL,
+		FilePath:    payload.FilePath,
+		Domain:      payload.Domain,
+		EntryPoints: payload.EntryPoints,
+	})
+}
+
+// GET request on /api/endpoints/:id/loadbalancer
+//
+// Returns the integration registering the published services of the endpoint into an external load balancer,
+// along with the services registered on the last synchronization.
+func (handler *Handler) endpointLoadBalancerInspect(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	endpoint, httpErr := handler.retrieveLoadBalancerEndpoint(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	integration, err := handler.DataStore.LoadBalancerIntegration().LoadBalancerIntegration(endpoint.ID)
+	if err == bolterrors.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find a load balancer integration for the endpoint inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a load balancer integration for the endpoint inside the database", err}
+	}
+
+	integration.Token = ""
+	return response.JSON(w, integration)
+}
+
+// PUT request on /api/endpoints/:id/loadbalancer
+//
+// Creates or replaces the load balancer integration of the endpoint. The services registered with the previous
+// definition are removed from the external service when the type or the target of the integration changes,
+// the services of the endpoint are then registered with the new definition.
+func (handler *Handler) endpointLoadBalancerUpdate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	endpoint, httpErr := handler.retrieveLoadBalancerEndpoint(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	var payload loadBalancerIntegrationUpdatePayload
+	err := request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	integration := &portainer.LoadBalancerIntegration{
+		EndpointID:    endpoint.ID,
+		Type:          payload.Type,
+		Enabled:       payload.Enabled,
+		Address:       payload.Address,
+		URL:           payload.URL,
+		Token:         payload.Token,
+		FilePath:      payload.FilePath,
+		Domain:        payload.Domain,
+		EntryPoints:   payload.EntryPoints,
+		Registrations: make([]portainer.LoadBalancerRegistration, 0),
+	}
+
+	previous, err := handler.DataStore.LoadBalancerIntegration().LoadBalancerIntegration(endpoint.ID)
+	if err != nil && err != bolterrors.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a load balancer integration for the endpoint inside the database", err}
+	}
+
+	if previous != nil {
+		if integration.Token == "" {
+			integration.Token = previous.Token
+		}
+
+		targetChanged := previous.Type != integration.Type || previous.URL != integration.URL || previous.FilePath != integration.FilePath
+		if targetChanged || !integration.Enabled {
+			handler.deregisterLoadBalancerIntegration(endpoint, previous)
+		} else {
+			integration.Registrations = previous.Registrations
+		}
+	}
+
+	err = handler.DataStore.LoadBalancerIntegration().UpdateLoadBalancerIntegration(endpoint.ID, integration)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist the load balancer integration inside the database", err}
+	}
+
+	if integration.Enabled {
+		err = handler.LoadBalancerService.Synchronize(endpoint.ID)
+		if err != nil {
+			log.Printf("[WARN] [http,endpoints,loadbalancer] [endpoint: %s] [message: unable to synchronize the load balancer integration] [error: %s]", endpoint.Name, err)
+		}
+
+		integration, err = handler.DataStore.LoadBalancerIntegration().LoadBalancerIntegration(endpoint.ID)
+		if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a load balancer integration for the endpoint inside the database", err}
+		}
+	}
+
+	integration.Token = ""
+	return response.JSON(w, integration)
+}
+
+// DELETE request on /api/endpoints/:id/loadbalancer
+//
+// Removes the load balancer integration of the endpoint along with the services it registered.
+func (handler *Handler) endpointLoadBalancerDelete(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	endpoint, httpErr := handler.retrieveLoadBalancerEndpoint(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	integration, err := handler.DataStore.LoadBalancerIntegration().LoadBalancerIntegration(endpoint.ID)
+	if err == bolterrors.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find a load balancer integration for the endpoint inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a load balancer integration for the endpoint inside the database", err}
+	}
+
+	handler.deregisterLoadBalancerIntegration(endpoint, integration)
+
+	err = handler.DataStore.LoadBalancerIntegration().DeleteLoadBalancerIntegration(endpoint.ID)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to remove the load balancer integration from the database", err}
+	}
+
+	return response.Empty(w)
+}
+
+// POST request on /api/endpoints/:id/loadbalancer/sync
+//
+// Synchronizes the services registered into the external load balancer without waiting for the next
+// background synchronization, the external service is updated even when the registrations are unchanged.
+func (handler *Handler) endpointLoadBalancerSync(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	endpoint, httpErr := handler.retrieveLoadBalancerEndpoint(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	integration, err := handler.DataStore.LoadBalancerIntegration().LoadBalancerIntegration(endpoint.ID)
+	if err == bolterrors.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find a load balancer integration for the endpoint inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a load balancer integration for the endpoint inside the database", err}
+	}
+
+	if !integration.Enabled {
+		return &httperror.HandlerError{http.StatusBadRequest, "The load balancer integration of the endpoint is disabled", errLoadBalancerIntegrationDisabled}
+	}
+
+	integration.LastSync = 0
+	err = handler.DataStore.LoadBalancerIntegration().UpdateLoadBalancerIntegration(endpoint.ID, integration)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist the load balancer integration inside the database", err}
+	}
+
+	err = handler.LoadBalancerService.Synchronize(endpoint.ID)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to synchronize the load balancer integration", err}
+	}
+
+	integration, err = handler.DataStore.LoadBalancerIntegration().LoadBalancerIntegration(endpoint.ID)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a load balancer integration for the endpoint inside the database", err}
+	}
+
+	integration.Token = ""
+	return response.JSON(w, integration)
+}
+
+func (handler *Handler) retrieveLoadBalancerEndpoint(r *http.Request) (*portainer.Endpoint, *httperror.HandlerError) {
+	endpointID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return nil, &httperror.HandlerError{http.StatusBadRequest, "Invalid endpoint identifier route variable", err}
+	}
+
+	endpoint, err := handler.DataStore.Endpoint().Endpoint(portainer.EndpointID(endpointID))
+	if err == bolterrors.ErrObjectNotFound {
+		return nil, &httperror.HandlerError{http.StatusNotFound, "Unable to find an endpoint with the specified identifier inside the database", err}
+	} else if err != nil {
+		return nil, &httperror.HandlerError{http.StatusInternalServerError, "Unable to find an endpoint with the specified identifier inside the database", err}
+	}
+
+	err = handler.requestBouncer.AuthorizedEndpointOperation(r, endpoint)
+	if err != nil {
+		return nil, &httperror.HandlerError{http.StatusForbidden, "Permission denied to access endpoint", err}
+	}
+
+	if !loadbalancer.SupportLoadBalancerIntegration(endpoint) {
+		return nil, &httperror.HandlerError{http.StatusBadRequest, "The load balancer integration is only supported on Docker endpoints", errors.New("Invalid endpoint type")}
+	}
+
+	return endpoint, nil
+}
+
+// deregisterLoadBalancerIntegration removes the services registered by an enabled integration, a failure
+// does not prevent the removal of the integration
+func (handler *Handler) deregisterLoadBalancerIntegration(endpoint *portainer.Endpoint, integration *portainer.LoadBalancerIntegration) {
+	if !integration.Enabled {
+		return
+	}
+
+	err := handler.LoadBalancerService.Deregister(integration)
+	if err != nil {
+		log.Printf("[WARN] [http,endpoints,loadbalancer] [endpoint: %s] [message: unable to remove the registered services] [error: %s]", endpoint.Name, err)
+	}
+}
@@ -0,0 +1,199 @@
+package endpoints
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/docker/docker/client"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+	"github.com/portainer/portainer/api/docker"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+type (
+	endpointPortsCheckPayload struct {
+		Ports []endpointPortRequest
+		// IgnoreStack excludes the ports published by a stack, used when a stack is updated
+		IgnoreStack string
+	}
+
+	endpointPortRequest struct {
+		HostIP        string
+		PublishedPort int
+		Protocol      string
+		// Ingress must be set for a port published on the Swarm routing mesh
+		Ingress bool
+	}
+
+	endpointPortConflict struct {
+		Port        docker.PublishedPort `json:"Port"`
+		PublishedBy docker.PublishedPort `json:"PublishedBy"`
+	}
+
+	endpointPortsCheckResponse struct {
+		Available bool                   `json:"Available"`
+		Conflicts []endpointPortConflict `json:"Conflicts"`
+	}
+)
+
+func (payload *endpointPortsCheckPayload) Validate(r *http.Request) error {
+	if len(payload.Ports) == 0 {
+		return errors.New("Invalid ports. At least one port must be specified")
+	}
+
+	for _, port := range payload.Ports {
+		if port.PublishedPort <= 0 || port.PublishedPort > 65535 {
+			return errors.New("Invalid published port. Value must be between 1 and 65535")
+		}
+
+		protocol := strings.ToLower(port.Protocol)
+		if protocol != "" && protocol != "tcp" && protocol != "udp" && protocol != "sctp" {
+			return errors.New("Invalid protocol. Value must be one of: tcp, udp or sctp")
+		}
+	}
+
+	return nil
+}
+
+// GET request on /api/endpoints/:id/ports
+//
+// Returns the host ports published by the containers and the Swarm services of a Docker endpoint.
+// The owner of a port is only reported to administrators.
+func (handler *Handler) endpointPortsInspect(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	ports, isAdmin, handlerErr := handler.retrievePublishedPorts(r)
+	if handlerErr != nil {
+		return handlerErr
+	}
+
+	if !isAdmin {
+		for idx := range ports {
+			hidePortOwner(&ports[idx])
+		}
+	}
+
+	return response.JSON(w, ports)
+}
+
+// POST request on /api/endpoints/:id/ports/check
+//
+// Checks whether a list of ports can be published on a Docker endpoint and reports
+// the ports already published that would conflict.
+func (handler *Handler) endpointPortsCheck(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	var payload endpointPortsCheckPayload
+	err := request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	publishedPorts, isAdmin, handlerErr := handler.retrievePublishedPorts(r)
+	if handlerErr != nil {
+		return handlerErr
+	}
+
+	requestedPorts := make([]docker.PublishedPort, 0, len(payload.Ports))
+	for _, port := range payload.Ports {
+		requestedPort := docker.PublishedPort{
+			HostIP:        port.HostIP,
+			PublishedPort: uint16(port.PublishedPort),
+			Protocol:      strings.ToLower(port.Protocol),
+			Mode:          docker.PortModeHost,
+		}
+		if requestedPort.Protocol == "" {
+			requestedPort.Protocol = "tcp"
+		}
+		if port.Ingress {
+			requestedPort.Mode = docker.PortModeIngress
+		}
+
+		requestedPorts = append(requestedPorts, requestedPort)
+	}
+
+	result := &endpointPortsCheckResponse{
+		Conflicts: make([]endpointPortConflict, 0),
+	}
+
+	for idx := range requestedPorts {
+		requestedPort := &requestedPorts[idx]
+
+		for _, publishedPort := range publishedPorts {
+			if payload.IgnoreStack != "" && publishedPort.Stack == payload.IgnoreStack {
+				continue
+			}
+
+			if docker.PortsConflict(requestedPort, &publishedPort) {
+				result.Conflicts = append(result.Conflicts, endpointPortConflict{Port: *requestedPort, PublishedBy: publishedPort})
+			}
+		}
+
+		for _, otherPort := range requestedPorts[:idx] {
+			if docker.PortsConflict(requestedPort, &otherPort) {
+				result.Conflicts = append(result.Conflicts, endpointPortConflict{Port: *requestedPort, PublishedBy: otherPort})
+			}
+		}
+	}
+
+	if !isAdmin {
+		for idx := range result.Conflicts {
+			hidePortOwner(&result.Conflicts[idx].PublishedBy)
+		}
+	}
+
+	result.Available = len(result.Conflicts) == 0
+	return response.JSON(w, result)
+}
+
+// retrievePublishedPorts returns the ports published on the endpoint of the request and whether the user
+// is an administrator
+func (handler *Handler) retrievePublishedPorts(r *http.Request) ([]docker.PublishedPort, bool, *httperror.HandlerError) {
+	endpointID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return nil, false, &httperror.HandlerError{http.StatusBadRequest, "Invalid endpoint identifier route variable", err}
+	}
+
+	endpoint, err := handler.DataStore.Endpoint().Endpoint(portainer.EndpointID(endpointID))
+	if err == bolterrors.ErrObjectNotFound {
+		return nil, false, &httperror.HandlerError{http.StatusNotFound, "Unable to find an endpoint with the specified identifier inside the database", err}
+	} else if err != nil {
+		return nil, false, &httperror.HandlerError{http.StatusInternalServerError, "Unable to find an endpoint with the specified identifier inside the database", err}
+	}
+
+	err = handler.requestBouncer.AuthorizedEndpointOperation(r, endpoint)
+	if err != nil {
+		return nil, false, &httperror.HandlerError{http.StatusForbidden, "Permission denied to access endpoint", err}
+	}
+
+	securityContext, err := security.RetrieveRestrictedRequestContext(r)
+	if err != nil {
+		return nil, false, &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve info from request context", err}
+	}
+
+	var cli *client.Client
+	switch endpoint.Type {
+	case portainer.DockerEnvironment, portainer.AgentOnDockerEnvironment, portainer.EdgeAgentOnDockerEnvironment:
+		cli, err = handler.DockerClientFactory.CreateClient(endpoint, "")
+		if err != nil {
+			return nil, false, &httperror.HandlerError{http.StatusInternalServerError, "Unable to create Docker client", err}
+		}
+		defer cli.Close()
+	default:
+		return nil, false, &httperror.HandlerError{http.StatusBadRequest, "Published ports are only available for Docker endpoints", errors.New("Invalid endpoint type")}
+	}
+
+	ports, err := docker.PublishedPorts(cli)
+	if err != nil {
+		return nil, false, &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve the published ports of the endpoint", err}
+	}
+
+	return ports, securityContext.IsAdmin, nil
+}
+
+func hidePortOwner(port *docker.PublishedPort) {
+	port.OwnerID = ""
+	port.OwnerName = ""
+	port.Stack = ""
+}
@@ -25,6 +25,11 @@ func (handler *Handler) endpointSnapshot(w http.ResponseWriter, r *http.Request)
 		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find an endpoint with the specified identifier inside the database", err}
 	}
 
+	err = handler.requestBouncer.AuthorizedEndpointOperation(r, endpoint)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusForbidden, "Permission denied to access endpoint", err}
+	}
+
 	if !snapshot.SupportDirectSnapshot(endpoint) {
 		return &httperror.HandlerError{http.StatusBadRequest, "Snapshots not supported for this endpoint", err}
 	}
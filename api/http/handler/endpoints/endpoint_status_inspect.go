@@ -3,14 +3,21 @@ package endpoints
 import (
 	"encoding/base64"
 	"errors"
+	"hash/fnv"
 	"net/http"
+	"sort"
 	"strconv"
+	"strings"
+	"time"
 
 	httperror "github.com/portainer/libhttp/error"
 	"github.com/portainer/libhttp/request"
 	"github.com/portainer/libhttp/response"
 	"github.com/portainer/portainer/api"
 	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+	"github.com/portainer/portainer/api/internal/edge"
+	"github.com/portainer/portainer/api/internal/maintenance"
+	"github.com/portainer/portainer/api/internal/organization"
 )
 
 type stackStatusResponse struct {
@@ -24,6 +31,8 @@ type edgeJobResponse struct {
 	CronExpression string              `json:"CronExpression"`
 	Script         string              `json:"Script"`
 	Version        int                 `json:"Version"`
+	// Cached is true when the script is not sent because the agent already knows this version of the job
+	Cached bool `json:"Cached,omitempty"`
 }
 
 type endpointStatusInspectResponse struct {
@@ -33,9 +42,18 @@ type endpointStatusInspectResponse struct {
 	CheckinInterval int                   `json:"checkin"`
 	Credentials     string                `json:"credentials"`
 	Stacks          []stackStatusResponse `json:"stacks"`
+	// BundleVersion is the version of the bundle of the Edge stacks of the endpoint, the agent downloads
+	// the bundle when the version of its cached copy is different
+	BundleVersion string `json:"bundleVersion"`
+	// KeepAlive is the interval in seconds of the keep-alive messages sent by the agent on its tunnel,
+	// the agent uses its default interval when it is not defined
+	KeepAlive int `json:"keepAlive,omitempty"`
 }
 
 // GET request on /api/endpoints/:id/status
+//
+// While the endpoint is in a maintenance window, the Edge jobs are not scheduled on the agent and the agent
+// keeps receiving the versions of the Edge stacks it received when the window started.
 func (handler *Handler) endpointStatusInspect(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
 	endpointID, err := request.RetrieveNumericRouteVariableValue(r, "id")
 	if err != nil {
@@ -54,10 +72,21 @@ func (handler *Handler) endpointStatusInspect(w http.ResponseWriter, r *http.Req
 		return &httperror.HandlerError{http.StatusForbidden, "Permission denied to access endpoint", err}
 	}
 
+	settings, err := handler.DataStore.Settings().Settings()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve settings from the database", err}
+	}
+
 	if endpoint.EdgeID == "" {
 		edgeIdentifier := r.Header.Get(portainer.PortainerAgentEdgeIDHeader)
 		endpoint.EdgeID = edgeIdentifier
 
+		// the pre-registered devices claiming their endpoint with an enrollment key are bound
+		// to their endpoint by the claim and do not go through the waiting room
+		if settings.EnableEdgeWaitingRoom {
+			endpoint.EdgePendingApproval = true
+		}
+
 		agentPlatformHeader := r.Header.Get(portainer.HTTPResponseAgentPlatform)
 		if agentPlatformHeader == "" {
 			return &httperror.HandlerError{http.StatusInternalServerError, "Agent Platform Header is missing", errors.New("Agent Platform Header is missing")}
@@ -82,20 +111,43 @@ func (handler *Handler) endpointStatusInspect(w http.ResponseWriter, r *http.Req
 		}
 	}
 
-	settings, err := handler.DataStore.Settings().Settings()
+	settings, err = organization.EffectiveSettings(handler.DataStore, settings, endpoint.OrganizationID)
 	if err != nil {
-		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve settings from the database", err}
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve the settings of the organization from the database", err}
 	}
 
-	tunnel := handler.ReverseTunnelService.GetTunnelDetails(endpoint.ID)
-
 	checkinInterval := settings.EdgeAgentCheckinInterval
 	if endpoint.EdgeCheckinInterval != 0 {
 		checkinInterval = endpoint.EdgeCheckinInterval
 	}
+	checkinInterval += checkinJitter(endpoint, settings.EdgeAgentCheckinJitter)
+
+	if endpoint.EdgePendingApproval {
+		return response.JSON(w, endpointStatusInspectResponse{
+			Status:          portainer.EdgeAgentIdle,
+			Schedules:       []edgeJobResponse{},
+			CheckinInterval: checkinInterval,
+			Stacks:          []stackStatusResponse{},
+		})
+	}
+
+	tunnelError := r.Header.Get(portainer.PortainerAgentTunnelErrorHeader)
+	if tunnelError != "" {
+		handler.ReverseTunnelService.SetTunnelError(endpoint.ID, tunnelError)
+	}
+
+	tunnel := handler.ReverseTunnelService.GetTunnelDetails(endpoint.ID)
+
+	knownJobs := parseKnownEdgeJobs(r.Header.Get(portainer.PortainerAgentEdgeJobsHeader))
+
+	inMaintenance := maintenance.InMaintenance(handler.DataStore, endpoint)
 
 	schedules := []edgeJobResponse{}
 	for _, job := range tunnel.Jobs {
+		if inMaintenance {
+			break
+		}
+
 		schedule := edgeJobResponse{
 			ID:             job.ID,
 			CronExpression: job.CronExpression,
@@ -103,6 +155,12 @@ func (handler *Handler) endpointStatusInspect(w http.ResponseWriter, r *http.Req
 			Version:        job.Version,
 		}
 
+		if version, ok := knownJobs[job.ID]; ok && version == job.Version {
+			schedule.Cached = true
+			schedules = append(schedules, schedule)
+			continue
+		}
+
 		file, err := handler.FileService.GetFileContent(job.ScriptPath)
 
 		if err != nil {
@@ -122,6 +180,11 @@ func (handler *Handler) endpointStatusInspect(w http.ResponseWriter, r *http.Req
 		Credentials:     tunnel.Credentials,
 	}
 
+	keepAlive, err := time.ParseDuration(settings.EdgeTunnel.KeepAliveInterval)
+	if err == nil {
+		statusResponse.KeepAlive = int(keepAlive.Seconds())
+	}
+
 	if tunnel.Status == portainer.EdgeAgentManagementRequired {
 		handler.ReverseTunnelService.SetTunnelStatusToActive(endpoint.ID)
 	}
@@ -131,6 +194,7 @@ func (handler *Handler) endpointStatusInspect(w http.ResponseWriter, r *http.Req
 		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve relation object from the database", err}
 	}
 
+	edgeStacks := []portainer.EdgeStack{}
 	edgeStacksStatus := []stackStatusResponse{}
 	for stackID := range relation.EdgeStacks {
 		stack, err := handler.DataStore.EdgeStack().EdgeStack(stackID)
@@ -138,15 +202,106 @@ func (handler *Handler) endpointStatusInspect(w http.ResponseWriter, r *http.Req
 			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve edge stack from the database", err}
 		}
 
+		version, _ := edge.EdgeStackEndpointVersion(stack, endpoint.ID)
+
 		stackStatus := stackStatusResponse{
 			ID:      stack.ID,
-			Version: stack.Version,
+			Version: version,
 		}
 
+		edgeStacks = append(edgeStacks, *stack)
 		edgeStacksStatus = append(edgeStacksStatus, stackStatus)
 	}
 
 	statusResponse.Stacks = edgeStacksStatus
+	statusResponse.BundleVersion = edge.BundleVersion(edgeStacks, endpoint.ID)
+
+	switch {
+	case inMaintenance && endpoint.EdgeDeferredDeployment == nil:
+		endpoint.EdgeDeferredDeployment = &portainer.EdgeDeferredDeployment{
+			Stacks:        make(map[portainer.EdgeStackID]int),
+			BundleVersion: statusResponse.BundleVersion,
+			Since:         time.Now().Unix(),
+		}
+		for _, stackStatus := range edgeStacksStatus {
+			endpoint.EdgeDeferredDeployment.Stacks[stackStatus.ID] = stackStatus.Version
+		}
+
+		err = handler.DataStore.Endpoint().UpdateEndpoint(endpoint.ID, endpoint)
+		if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist endpoint changes inside the database", err}
+		}
+	case inMaintenance:
+		statusResponse.Stacks = deferredStacksStatus(endpoint.EdgeDeferredDeployment)
+		statusResponse.BundleVersion = endpoint.EdgeDeferredDeployment.BundleVersion
+	case endpoint.EdgeDeferredDeployment != nil:
+		endpoint.EdgeDeferredDeployment = nil
+
+		err = handler.DataStore.Endpoint().UpdateEndpoint(endpoint.ID, endpoint)
+		if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist endpoint changes inside the database", err}
+		}
+	}
+
+	bundleVersion := r.Header.Get(portainer.PortainerAgentEdgeBundleVersionHeader)
+	if bundleVersion != "" && bundleVersion != endpoint.EdgeBundleVersion {
+		endpoint.EdgeBundleVersion = bundleVersion
+
+		err = handler.DataStore.Endpoint().UpdateEndpoint(endpoint.ID, endpoint)
+		if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist endpoint changes inside the database", err}
+		}
+	}
 
 	return response.JSON(w, statusResponse)
 }
+
+// deferredStacksStatus returns the versions of the Edge stacks recorded when the endpoint entered a maintenance window
+func deferredStacksStatus(deployment *portainer.EdgeDeferredDeployment) []stackStatusResponse {
+	stacksStatus := make([]stackStatusResponse, 0, len(deployment.Stacks))
+	for stackID, version := range deployment.Stacks {
+		stacksStatus = append(stacksStatus, stackStatusResponse{ID: stackID, Version: version})
+	}
+
+	sort.Slice(stacksStatus, func(i, j int) bool {
+		return stacksStatus[i].ID < stacksStatus[j].ID
+	})
+
+	return stacksStatus
+}
+
+// checkinJitter returns the number of seconds added to the check-in interval of an endpoint. The offset
+// is derived from the endpoint identifier so that it is stable across the check-ins of the agent.
+func checkinJitter(endpoint *portainer.Endpoint, jitter int) int {
+	if jitter <= 0 {
+		return 0
+	}
+
+	hash := fnv.New32a()
+	hash.Write([]byte(strconv.Itoa(int(endpoint.ID))))
+	return int(hash.Sum32() % uint32(jitter+1))
+}
+
+// parseKnownEdgeJobs parses the Edge jobs known by an agent, sent as a comma separated list of id:version
+func parseKnownEdgeJobs(header string) map[portainer.EdgeJobID]int {
+	knownJobs := make(map[portainer.EdgeJobID]int)
+	for _, entry := range strings.Split(header, ",") {
+		parts := strings.SplitN(strings.TrimSpace(entry), ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		jobID, err := strconv.Atoi(parts[0])
+		if err != nil {
+			continue
+		}
+
+		version, err := strconv.Atoi(parts[1])
+		if err != nil {
+			continue
+		}
+
+		knownJobs[portainer.EdgeJobID(jobID)] = version
+	}
+	return knownJobs
+}
@@ -0,0 +1,261 @@
+package endpoints
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/authorization"
+)
+
+const (
+	topologyContainerNode = "container"
+	topologyNetworkNode   = "network"
+	topologyServiceNode   = "service"
+
+	// topologyAttachmentEdge connects a container or a service to a network
+	topologyAttachmentEdge = "attachment"
+	// topologyTaskEdge connects a container to the Swarm service it is a task of
+	topologyTaskEdge = "task"
+
+	swarmServiceIDLabel = "com.docker.swarm.service.id"
+)
+
+type (
+	endpointTopology struct {
+		Nodes []topologyNode `json:"Nodes"`
+		Edges []topologyEdge `json:"Edges"`
+	}
+
+	topologyNode struct {
+		ID    string `json:"Id"`
+		Type  string `json:"Type"`
+		Name  string `json:"Name"`
+		State string `json:"State,omitempty"`
+		Image string `json:"Image,omitempty"`
+		Stack string `json:"Stack,omitempty"`
+		// Driver and Scope are only set for networks
+		Driver   string `json:"Driver,omitempty"`
+		Scope    string `json:"Scope,omitempty"`
+		Internal bool   `json:"Internal,omitempty"`
+	}
+
+	topologyEdge struct {
+		Source    string   `json:"Source"`
+		Target    string   `json:"Target"`
+		Type      string   `json:"Type"`
+		IPAddress string   `json:"IPAddress,omitempty"`
+		Aliases   []string `json:"Aliases,omitempty"`
+	}
+
+	topologyFilter struct {
+		securityContext  *security.RestrictedRequestContext
+		resourceControls []portainer.ResourceControl
+		userTeamIDs      []portainer.TeamID
+	}
+)
+
+// GET request on /api/endpoints/:id/topology
+//
+// Returns the graph of the containers, Swarm services and networks of a Docker endpoint.
+// Non administrator users only see the containers and services they can access and the networks
+// they are connected to.
+func (handler *Handler) endpointTopology(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	endpointID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid endpoint identifier route variable", err}
+	}
+
+	endpoint, err := handler.DataStore.Endpoint().Endpoint(portainer.EndpointID(endpointID))
+	if err == bolterrors.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find an endpoint with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find an endpoint with the specified identifier inside the database", err}
+	}
+
+	err = handler.requestBouncer.AuthorizedEndpointOperation(r, endpoint)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusForbidden, "Permission denied to access endpoint", err}
+	}
+
+	if endpoint.Type != portainer.DockerEnvironment && endpoint.Type != portainer.AgentOnDockerEnvironment && endpoint.Type != portainer.EdgeAgentOnDockerEnvironment {
+		return &httperror.HandlerError{http.StatusBadRequest, "The network topology is only available for Docker endpoints", errors.New("Invalid endpoint type")}
+	}
+
+	securityContext, err := security.RetrieveRestrictedRequestContext(r)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve info from request context", err}
+	}
+
+	filter := &topologyFilter{securityContext: securityContext}
+	if !securityContext.IsAdmin {
+		filter.resourceControls, err = handler.DataStore.ResourceControl().ResourceControls()
+		if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve resource controls from the database", err}
+		}
+
+		for _, membership := range securityContext.UserMemberships {
+			filter.userTeamIDs = append(filter.userTeamIDs, membership.TeamID)
+		}
+	}
+
+	cli, err := handler.DockerClientFactory.CreateClient(endpoint, "")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to create Docker client", err}
+	}
+	defer cli.Close()
+
+	networks, err := cli.NetworkList(context.Background(), types.NetworkListOptions{})
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve networks", err}
+	}
+
+	containers, err := cli.ContainerList(context.Background(), types.ContainerListOptions{All: true})
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve containers", err}
+	}
+
+	info, err := cli.Info(context.Background())
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve engine information", err}
+	}
+
+	services := make([]swarm.Service, 0)
+	if info.Swarm.ControlAvailable {
+		services, err = cli.ServiceList(context.Background(), types.ServiceListOptions{})
+		if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve services", err}
+		}
+	}
+
+	return response.JSON(w, buildTopology(networks, containers, services, filter))
+}
+
+func buildTopology(networks []types.NetworkResource, containers []types.Container, services []swarm.Service, filter *topologyFilter) *endpointTopology {
+	topology := &endpointTopology{
+		Nodes: make([]topologyNode, 0),
+		Edges: make([]topologyEdge, 0),
+	}
+
+	connectedNetworks := make(map[string]bool)
+	visibleServices := make(map[string]bool)
+
+	for _, service := range services {
+		if !filter.canAccess(service.ID, portainer.ServiceResourceControl, service.Spec.Labels) {
+			continue
+		}
+		visibleServices[service.ID] = true
+
+		image := ""
+		if service.Spec.TaskTemplate.ContainerSpec != nil {
+			image = strings.Split(service.Spec.TaskTemplate.ContainerSpec.Image, "@")[0]
+		}
+
+		topology.Nodes = append(topology.Nodes, topologyNode{
+			ID:    service.ID,
+			Type:  topologyServiceNode,
+			Name:  service.Spec.Name,
+			Image: image,
+			Stack: service.Spec.Labels["com.docker.stack.namespace"],
+		})
+
+		for _, virtualIP := range service.Endpoint.VirtualIPs {
+			connectedNetworks[virtualIP.NetworkID] = true
+			topology.Edges = append(topology.Edges, topologyEdge{
+				Source:    service.ID,
+				Target:    virtualIP.NetworkID,
+				Type:      topologyAttachmentEdge,
+				IPAddress: virtualIP.Addr,
+			})
+		}
+	}
+
+	for _, container := range containers {
+		if !filter.canAccess(container.ID, portainer.ContainerResourceControl, container.Labels) {
+			continue
+		}
+
+		name := container.ID
+		if len(container.Names) > 0 {
+			name = strings.TrimPrefix(container.Names[0], "/")
+		}
+
+		stack := container.Labels["com.docker.compose.project"]
+		if stack == "" {
+			stack = container.Labels["com.docker.stack.namespace"]
+		}
+
+		topology.Nodes = append(topology.Nodes, topologyNode{
+			ID:    container.ID,
+			Type:  topologyContainerNode,
+			Name:  name,
+			State: container.State,
+			Image: container.Image,
+			Stack: stack,
+		})
+
+		serviceID := container.Labels[swarmServiceIDLabel]
+		if visibleServices[serviceID] {
+			topology.Edges = append(topology.Edges, topologyEdge{
+				Source: container.ID,
+				Target: serviceID,
+				Type:   topologyTaskEdge,
+			})
+		}
+
+		if container.NetworkSettings == nil {
+			continue
+		}
+
+		for _, settings := range container.NetworkSettings.Networks {
+			if settings == nil || settings.NetworkID == "" {
+				continue
+			}
+
+			connectedNetworks[settings.NetworkID] = true
+			topology.Edges = append(topology.Edges, topologyEdge{
+				Source:    container.ID,
+				Target:    settings.NetworkID,
+				Type:      topologyAttachmentEdge,
+				IPAddress: settings.IPAddress,
+				Aliases:   settings.Aliases,
+			})
+		}
+	}
+
+	for _, network := range networks {
+		if !connectedNetworks[network.ID] && !filter.canAccess(network.ID, portainer.NetworkResourceControl, network.Labels) {
+			continue
+		}
+
+		topology.Nodes = append(topology.Nodes, topologyNode{
+			ID:       network.ID,
+			Type:     topologyNetworkNode,
+			Name:     network.Name,
+			Stack:    network.Labels["com.docker.stack.namespace"],
+			Driver:   network.Driver,
+			Scope:    network.Scope,
+			Internal: network.Internal,
+		})
+	}
+
+	return topology
+}
+
+func (filter *topologyFilter) canAccess(resourceID string, resourceType portainer.ResourceControlType, labels map[string]string) bool {
+	if filter.securityContext.IsAdmin {
+		return true
+	}
+
+	resourceControl := authorization.GetInheritedResourceControl(resourceID, resourceType, labels, filter.resourceControls)
+	return resourceControl != nil && authorization.UserCanAccessResource(filter.securityContext.UserID, filter.userTeamIDs, resourceControl)
+}
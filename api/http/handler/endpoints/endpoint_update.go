@@ -1,6 +1,7 @@
 package endpoints
 
 import (
+	"errors"
 	"net/http"
 	"reflect"
 	"strconv"
@@ -9,33 +10,42 @@ import (
 	"github.com/portainer/libhttp/request"
 	"github.com/portainer/libhttp/response"
 	"github.com/portainer/portainer/api"
-	"github.com/portainer/portainer/api/bolt/errors"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+	"github.com/portainer/portainer/api/docker"
 	"github.com/portainer/portainer/api/http/client"
+	"github.com/portainer/portainer/api/internal/authorization"
 	"github.com/portainer/portainer/api/internal/edge"
 	"github.com/portainer/portainer/api/internal/tag"
 )
 
 type endpointUpdatePayload struct {
-	Name                   *string
-	URL                    *string
-	PublicURL              *string
-	GroupID                *int
-	TLS                    *bool
-	TLSSkipVerify          *bool
-	TLSSkipClientVerify    *bool
-	Status                 *int
-	AzureApplicationID     *string
-	AzureTenantID          *string
-	AzureAuthenticationKey *string
-	TagIDs                 []portainer.TagID
-	UserAccessPolicies     portainer.UserAccessPolicies
-	TeamAccessPolicies     portainer.TeamAccessPolicies
-	EdgeCheckinInterval    *int
-	Kubernetes             *portainer.KubernetesData
+	Name                    *string
+	URL                     *string
+	PublicURL               *string
+	GroupID                 *int
+	TLS                     *bool
+	TLSSkipVerify           *bool
+	TLSSkipClientVerify     *bool
+	Status                  *int
+	AzureApplicationID      *string
+	AzureTenantID           *string
+	AzureAuthenticationKey  *string
+	TagIDs                  []portainer.TagID
+	UserAccessPolicies      portainer.UserAccessPolicies
+	TeamAccessPolicies      portainer.TeamAccessPolicies
+	EdgeCheckinInterval     *int
+	Kubernetes              *portainer.KubernetesData
+	ResourceOwnershipPolicy *int
+	Protected               *bool
+	// Metadata replaces the metadata of the endpoint, an empty object removes all the metadata
+	Metadata map[string]string
 }
 
 func (payload *endpointUpdatePayload) Validate(r *http.Request) error {
-	return nil
+	if payload.ResourceOwnershipPolicy != nil && *payload.ResourceOwnershipPolicy != 0 && !authorization.ValidResourceOwnershipPolicy(portainer.ResourceOwnershipPolicy(*payload.ResourceOwnershipPolicy)) {
+		return errors.New("Invalid resource ownership policy value. Value must be one of: 0 (use default), 1 (private), 2 (team) or 3 (public)")
+	}
+	return tag.ValidateMetadata(payload.Metadata)
 }
 
 // PUT request on /api/endpoints/:id
@@ -52,17 +62,36 @@ func (handler *Handler) endpointUpdate(w http.ResponseWriter, r *http.Request) *
 	}
 
 	endpoint, err := handler.DataStore.Endpoint().Endpoint(portainer.EndpointID(endpointID))
-	if err == errors.ErrObjectNotFound {
+	if err == bolterrors.ErrObjectNotFound {
 		return &httperror.HandlerError{http.StatusNotFound, "Unable to find an endpoint with the specified identifier inside the database", err}
 	} else if err != nil {
 		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find an endpoint with the specified identifier inside the database", err}
 	}
 
+	err = handler.requestBouncer.AuthorizedEndpointOperation(r, endpoint)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusForbidden, "Permission denied to access endpoint", err}
+	}
+
 	if payload.Name != nil {
 		endpoint.Name = *payload.Name
 	}
 
 	if payload.URL != nil {
+		if endpoint.SSHConfig != nil && *payload.URL != endpoint.URL {
+			privateKey, err := handler.FileService.GetFileContent(endpoint.SSHConfig.PrivateKeyPath)
+			if err != nil {
+				return &httperror.HandlerError{http.StatusInternalServerError, "Unable to read SSH private key file from disk", err}
+			}
+
+			// the host key is recorded again as the endpoint may now target a different host
+			hostKey, err := docker.ValidateSSHConnection(*payload.URL, privateKey)
+			if err != nil {
+				return &httperror.HandlerError{http.StatusBadRequest, "Unable to establish an SSH connection with the endpoint", err}
+			}
+			endpoint.SSHConfig.HostKey = hostKey
+		}
+
 		endpoint.URL = *payload.URL
 	}
 
@@ -122,10 +151,24 @@ func (handler *Handler) endpointUpdate(w http.ResponseWriter, r *http.Request) *
 		}
 	}
 
+	metadataChanged := false
+	if payload.Metadata != nil {
+		metadataChanged = !reflect.DeepEqual(payload.Metadata, endpoint.Metadata) && (len(payload.Metadata) > 0 || len(endpoint.Metadata) > 0)
+		endpoint.Metadata = payload.Metadata
+	}
+
 	if payload.Kubernetes != nil {
 		endpoint.Kubernetes = *payload.Kubernetes
 	}
 
+	if payload.ResourceOwnershipPolicy != nil {
+		endpoint.ResourceOwnershipPolicy = portainer.ResourceOwnershipPolicy(*payload.ResourceOwnershipPolicy)
+	}
+
+	if payload.Protected != nil {
+		endpoint.Protected = *payload.Protected
+	}
+
 	if payload.UserAccessPolicies != nil && !reflect.DeepEqual(payload.UserAccessPolicies, endpoint.UserAccessPolicies) {
 		endpoint.UserAccessPolicies = payload.UserAccessPolicies
 	}
@@ -223,7 +266,7 @@ func (handler *Handler) endpointUpdate(w http.ResponseWriter, r *http.Request) *
 		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist endpoint changes inside the database", err}
 	}
 
-	if (endpoint.Type == portainer.EdgeAgentOnDockerEnvironment || endpoint.Type == portainer.EdgeAgentOnKubernetesEnvironment) && (groupIDChanged || tagsChanged) {
+	if (endpoint.Type == portainer.EdgeAgentOnDockerEnvironment || endpoint.Type == portainer.EdgeAgentOnKubernetesEnvironment) && (groupIDChanged || tagsChanged || metadataChanged) {
 		relation, err := handler.DataStore.EndpointRelation().EndpointRelation(endpoint.ID)
 		if err != nil {
 			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find endpoint relation inside the database", err}
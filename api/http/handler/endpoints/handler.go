@@ -3,10 +3,13 @@ package endpoints
 import (
 	httperror "github.com/portainer/libhttp/error"
 	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/docker"
 	"github.com/portainer/portainer/api/http/proxy"
 	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/kubernetes/cli"
 
 	"net/http"
+	"sync"
 
 	"github.com/gorilla/mux"
 )
@@ -16,17 +19,24 @@ func hideFields(endpoint *portainer.Endpoint) {
 	if len(endpoint.Snapshots) > 0 {
 		endpoint.Snapshots[0].SnapshotRaw = portainer.DockerSnapshotRaw{}
 	}
+	if endpoint.EdgeEnrollment != nil {
+		endpoint.EdgeEnrollment.KeyHash = ""
+	}
 }
 
 // Handler is the HTTP handler used to handle endpoint operations.
 type Handler struct {
 	*mux.Router
-	requestBouncer       *security.RequestBouncer
-	DataStore            portainer.DataStore
-	FileService          portainer.FileService
-	ProxyManager         *proxy.Manager
-	ReverseTunnelService portainer.ReverseTunnelService
-	SnapshotService      portainer.SnapshotService
+	requestBouncer          *security.RequestBouncer
+	DataStore               portainer.DataStore
+	FileService             portainer.FileService
+	ProxyManager            *proxy.Manager
+	ReverseTunnelService    portainer.ReverseTunnelService
+	SnapshotService         portainer.SnapshotService
+	DockerClientFactory     *docker.ClientFactory
+	KubernetesClientFactory *cli.ClientFactory
+	LoadBalancerService     portainer.LoadBalancerService
+	enrollmentMutex         sync.Mutex
 }
 
 // NewHandler creates a handler to manage endpoint operations.
@@ -38,6 +48,18 @@ func NewHandler(bouncer *security.RequestBouncer) *Handler {
 
 	h.Handle("/endpoints",
 		bouncer.AdminAccess(httperror.LoggerHandler(h.endpointCreate))).Methods(http.MethodPost)
+	h.Handle("/endpoints/import/docker_context",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.endpointDockerContextImport))).Methods(http.MethodPost)
+	h.Handle("/endpoints/edge_devices",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.edgeDevicesRegister))).Methods(http.MethodPost)
+	h.Handle("/endpoints/edge_devices/claim",
+		bouncer.EdgeAgentAccess(httperror.LoggerHandler(h.edgeDeviceClaim))).Methods(http.MethodPost)
+	h.Handle("/endpoints/waiting_room",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.endpointWaitingRoomList))).Methods(http.MethodGet)
+	h.Handle("/endpoints/waiting_room/approve",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.endpointWaitingRoomApprove))).Methods(http.MethodPost)
+	h.Handle("/endpoints/waiting_room/reject",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.endpointWaitingRoomReject))).Methods(http.MethodPost)
 	h.Handle("/endpoints/snapshot",
 		bouncer.AdminAccess(httperror.LoggerHandler(h.endpointSnapshots))).Methods(http.MethodPost)
 	h.Handle("/endpoints",
@@ -48,13 +70,39 @@ func NewHandler(bouncer *security.RequestBouncer) *Handler {
 		bouncer.AdminAccess(httperror.LoggerHandler(h.endpointUpdate))).Methods(http.MethodPut)
 	h.Handle("/endpoints/{id}",
 		bouncer.AdminAccess(httperror.LoggerHandler(h.endpointDelete))).Methods(http.MethodDelete)
+	h.Handle("/endpoints/{id}/diagnose",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.endpointDiagnose))).Methods(http.MethodPost)
+	h.Handle("/endpoints/{id}/enrollment_key",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.edgeDeviceEnrollmentKeyRenew))).Methods(http.MethodPost)
 	h.Handle("/endpoints/{id}/extensions",
 		bouncer.RestrictedAccess(httperror.LoggerHandler(h.endpointExtensionAdd))).Methods(http.MethodPost)
 	h.Handle("/endpoints/{id}/extensions/{extensionType}",
 		bouncer.RestrictedAccess(httperror.LoggerHandler(h.endpointExtensionRemove))).Methods(http.MethodDelete)
+	h.Handle("/endpoints/{id}/devices",
+		bouncer.RestrictedAccess(httperror.LoggerHandler(h.endpointDevicesInspect))).Methods(http.MethodGet)
+	h.Handle("/endpoints/{id}/docker_context",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.endpointDockerContextExport))).Methods(http.MethodGet)
+	h.Handle("/endpoints/{id}/install_snippets",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.endpointInstallSnippets))).Methods(http.MethodGet)
+	h.Handle("/endpoints/{id}/host",
+		bouncer.RestrictedAccess(httperror.LoggerHandler(h.endpointHostInspect))).Methods(http.MethodGet)
+	h.Handle("/endpoints/{id}/loadbalancer",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.endpointLoadBalancerInspect))).Methods(http.MethodGet)
+	h.Handle("/endpoints/{id}/loadbalancer",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.endpointLoadBalancerUpdate))).Methods(http.MethodPut)
+	h.Handle("/endpoints/{id}/loadbalancer",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.endpointLoadBalancerDelete))).Methods(http.MethodDelete)
+	h.Handle("/endpoints/{id}/loadbalancer/sync",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.endpointLoadBalancerSync))).Methods(http.MethodPost)
+	h.Handle("/endpoints/{id}/ports",
+		bouncer.RestrictedAccess(httperror.LoggerHandler(h.endpointPortsInspect))).Methods(http.MethodGet)
+	h.Handle("/endpoints/{id}/ports/check",
+		bouncer.RestrictedAccess(httperror.LoggerHandler(h.endpointPortsCheck))).Methods(http.MethodPost)
 	h.Handle("/endpoints/{id}/snapshot",
 		bouncer.AdminAccess(httperror.LoggerHandler(h.endpointSnapshot))).Methods(http.MethodPost)
 	h.Handle("/endpoints/{id}/status",
-		bouncer.PublicAccess(httperror.LoggerHandler(h.endpointStatusInspect))).Methods(http.MethodGet)
+		bouncer.EdgeAgentAccess(httperror.LoggerHandler(h.endpointStatusInspect))).Methods(http.MethodGet)
+	h.Handle("/endpoints/{id}/topology",
+		bouncer.RestrictedAccess(httperror.LoggerHandler(h.endpointTopology))).Methods(http.MethodGet)
 	return h
 }
@@ -5,7 +5,14 @@ import (
 	"strings"
 
 	"github.com/portainer/portainer/api/http/handler/auth"
+	"github.com/portainer/portainer/api/http/handler/autohealrules"
+	"github.com/portainer/portainer/api/http/handler/azure"
+	"github.com/portainer/portainer/api/http/handler/branding"
+	"github.com/portainer/portainer/api/http/handler/changerequests"
+	"github.com/portainer/portainer/api/http/handler/containers"
 	"github.com/portainer/portainer/api/http/handler/customtemplates"
+	"github.com/portainer/portainer/api/http/handler/dashboard"
+	"github.com/portainer/portainer/api/http/handler/debug"
 	"github.com/portainer/portainer/api/http/handler/dockerhub"
 	"github.com/portainer/portainer/api/http/handler/edgegroups"
 	"github.com/portainer/portainer/api/http/handler/edgejobs"
@@ -16,61 +23,122 @@ import (
 	"github.com/portainer/portainer/api/http/handler/endpointproxy"
 	"github.com/portainer/portainer/api/http/handler/endpoints"
 	"github.com/portainer/portainer/api/http/handler/file"
+	"github.com/portainer/portainer/api/http/handler/health"
+	"github.com/portainer/portainer/api/http/handler/hostactions"
+	"github.com/portainer/portainer/api/http/handler/images"
+	"github.com/portainer/portainer/api/http/handler/ipam"
+	"github.com/portainer/portainer/api/http/handler/kubernetes"
+	"github.com/portainer/portainer/api/http/handler/maintenancewindows"
 	"github.com/portainer/portainer/api/http/handler/motd"
+	"github.com/portainer/portainer/api/http/handler/networks"
+	"github.com/portainer/portainer/api/http/handler/notificationchannels"
+	"github.com/portainer/portainer/api/http/handler/notifications"
+	"github.com/portainer/portainer/api/http/handler/organizations"
+	"github.com/portainer/portainer/api/http/handler/permissions"
+	"github.com/portainer/portainer/api/http/handler/proxylabels"
 	"github.com/portainer/portainer/api/http/handler/registries"
+	"github.com/portainer/portainer/api/http/handler/reports"
 	"github.com/portainer/portainer/api/http/handler/resourcecontrols"
 	"github.com/portainer/portainer/api/http/handler/roles"
+	"github.com/portainer/portainer/api/http/handler/scalingschedules"
+	"github.com/portainer/portainer/api/http/handler/search"
 	"github.com/portainer/portainer/api/http/handler/settings"
+	"github.com/portainer/portainer/api/http/handler/shadowusers"
 	"github.com/portainer/portainer/api/http/handler/stacks"
 	"github.com/portainer/portainer/api/http/handler/status"
+	"github.com/portainer/portainer/api/http/handler/swarm"
 	"github.com/portainer/portainer/api/http/handler/tags"
+	"github.com/portainer/portainer/api/http/handler/tasks"
 	"github.com/portainer/portainer/api/http/handler/teammemberships"
 	"github.com/portainer/portainer/api/http/handler/teams"
+	"github.com/portainer/portainer/api/http/handler/telemetry"
 	"github.com/portainer/portainer/api/http/handler/templates"
 	"github.com/portainer/portainer/api/http/handler/upload"
+	"github.com/portainer/portainer/api/http/handler/usage"
 	"github.com/portainer/portainer/api/http/handler/users"
+	"github.com/portainer/portainer/api/http/handler/volumes"
 	"github.com/portainer/portainer/api/http/handler/webhooks"
 	"github.com/portainer/portainer/api/http/handler/websocket"
 )
 
 // Handler is a collection of all the service handlers.
 type Handler struct {
-	AuthHandler            *auth.Handler
-	CustomTemplatesHandler *customtemplates.Handler
-	DockerHubHandler       *dockerhub.Handler
-	EdgeGroupsHandler      *edgegroups.Handler
-	EdgeJobsHandler        *edgejobs.Handler
-	EdgeStacksHandler      *edgestacks.Handler
-	EdgeTemplatesHandler   *edgetemplates.Handler
-	EndpointEdgeHandler    *endpointedge.Handler
-	EndpointGroupHandler   *endpointgroups.Handler
-	EndpointHandler        *endpoints.Handler
-	EndpointProxyHandler   *endpointproxy.Handler
-	FileHandler            *file.Handler
-	MOTDHandler            *motd.Handler
-	RegistryHandler        *registries.Handler
-	ResourceControlHandler *resourcecontrols.Handler
-	RoleHandler            *roles.Handler
-	SettingsHandler        *settings.Handler
-	StackHandler           *stacks.Handler
-	StatusHandler          *status.Handler
-	TagHandler             *tags.Handler
-	TeamMembershipHandler  *teammemberships.Handler
-	TeamHandler            *teams.Handler
-	TemplatesHandler       *templates.Handler
-	UploadHandler          *upload.Handler
-	UserHandler            *users.Handler
-	WebSocketHandler       *websocket.Handler
-	WebhookHandler         *webhooks.Handler
+	AuthHandler                *auth.Handler
+	AutoHealHandler            *autohealrules.Handler
+	AzureHandler               *azure.Handler
+	ContainerHandler           *containers.Handler
+	BrandingHandler            *branding.Handler
+	ChangeRequestHandler       *changerequests.Handler
+	CustomTemplatesHandler     *customtemplates.Handler
+	DashboardHandler           *dashboard.Handler
+	DebugHandler               *debug.Handler
+	DockerHubHandler           *dockerhub.Handler
+	EdgeGroupsHandler          *edgegroups.Handler
+	EdgeJobsHandler            *edgejobs.Handler
+	EdgeStacksHandler          *edgestacks.Handler
+	EdgeTemplatesHandler       *edgetemplates.Handler
+	EndpointEdgeHandler        *endpointedge.Handler
+	EndpointGroupHandler       *endpointgroups.Handler
+	EndpointHandler            *endpoints.Handler
+	EndpointProxyHandler       *endpointproxy.Handler
+	FileHandler                *file.Handler
+	HealthHandler              *health.Handler
+	HostActionHandler          *hostactions.Handler
+	ImageHandler               *images.Handler
+	IPAMHandler                *ipam.Handler
+	KubernetesHandler          *kubernetes.Handler
+	MaintenanceWindowHandler   *maintenancewindows.Handler
+	MOTDHandler                *motd.Handler
+	NetworkHandler             *networks.Handler
+	NotificationChannelHandler *notificationchannels.Handler
+	NotificationHandler        *notifications.Handler
+	OrganizationHandler        *organizations.Handler
+	PermissionHandler          *permissions.Handler
+	ProxyLabelsHandler         *proxylabels.Handler
+	RegistryHandler            *registries.Handler
+	ReportHandler              *reports.Handler
+	ResourceControlHandler     *resourcecontrols.Handler
+	RoleHandler                *roles.Handler
+	ScalingScheduleHandler     *scalingschedules.Handler
+	SearchHandler              *search.Handler
+	ShadowUserHandler          *shadowusers.Handler
+	SettingsHandler            *settings.Handler
+	StackHandler               *stacks.Handler
+	StatusHandler              *status.Handler
+	SwarmHandler               *swarm.Handler
+	TagHandler                 *tags.Handler
+	TaskHandler                *tasks.Handler
+	TeamMembershipHandler      *teammemberships.Handler
+	TeamHandler                *teams.Handler
+	TelemetryHandler           *telemetry.Handler
+	TemplatesHandler           *templates.Handler
+	UploadHandler              *upload.Handler
+	UsageHandler               *usage.Handler
+	UserHandler                *users.Handler
+	VolumeHandler              *volumes.Handler
+	WebSocketHandler           *websocket.Handler
+	WebhookHandler             *webhooks.Handler
 }
 
 // ServeHTTP delegates a request to the appropriate subhandler.
 func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	switch {
+	case strings.HasPrefix(r.URL.Path, "/api/applications"):
+		http.StripPrefix("/api", h.StackHandler).ServeHTTP(w, r)
 	case strings.HasPrefix(r.URL.Path, "/api/auth"):
 		http.StripPrefix("/api", h.AuthHandler).ServeHTTP(w, r)
+	case strings.HasPrefix(r.URL.Path, "/api/autoheal_"):
+		http.StripPrefix("/api", h.AutoHealHandler).ServeHTTP(w, r)
+	case strings.HasPrefix(r.URL.Path, "/api/azure"):
+		http.StripPrefix("/api", h.AzureHandler).ServeHTTP(w, r)
+	case strings.HasPrefix(r.URL.Path, "/api/dashboard"):
+		http.StripPrefix("/api", h.DashboardHandler).ServeHTTP(w, r)
 	case strings.HasPrefix(r.URL.Path, "/api/dockerhub"):
 		http.StripPrefix("/api", h.DockerHubHandler).ServeHTTP(w, r)
+	case strings.HasPrefix(r.URL.Path, "/api/branding"):
+		http.StripPrefix("/api", h.BrandingHandler).ServeHTTP(w, r)
+	case strings.HasPrefix(r.URL.Path, "/api/change_requests"):
+		http.StripPrefix("/api", h.ChangeRequestHandler).ServeHTTP(w, r)
 	case strings.HasPrefix(r.URL.Path, "/api/custom_templates"):
 		http.StripPrefix("/api", h.CustomTemplatesHandler).ServeHTTP(w, r)
 	case strings.HasPrefix(r.URL.Path, "/api/edge_stacks"):
@@ -97,17 +165,55 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			http.StripPrefix("/api/endpoints", h.EndpointProxyHandler).ServeHTTP(w, r)
 		case strings.Contains(r.URL.Path, "/edge/"):
 			http.StripPrefix("/api/endpoints", h.EndpointEdgeHandler).ServeHTTP(w, r)
+		case strings.Contains(r.URL.Path, "/containers/"):
+			http.StripPrefix("/api", h.ContainerHandler).ServeHTTP(w, r)
+		case strings.Contains(r.URL.Path, "/swarm/"):
+			http.StripPrefix("/api", h.SwarmHandler).ServeHTTP(w, r)
+		case strings.Contains(r.URL.Path, "/images/"):
+			http.StripPrefix("/api", h.ImageHandler).ServeHTTP(w, r)
+		case strings.Contains(r.URL.Path, "/volumes/"):
+			http.StripPrefix("/api", h.VolumeHandler).ServeHTTP(w, r)
+		case strings.Contains(r.URL.Path, "/networks/"):
+			http.StripPrefix("/api", h.NetworkHandler).ServeHTTP(w, r)
+		case strings.Contains(r.URL.Path, "/proxy_labels/"):
+			http.StripPrefix("/api", h.ProxyLabelsHandler).ServeHTTP(w, r)
 		default:
 			http.StripPrefix("/api", h.EndpointHandler).ServeHTTP(w, r)
 		}
+	case strings.HasPrefix(r.URL.Path, "/api/host_actions"):
+		http.StripPrefix("/api", h.HostActionHandler).ServeHTTP(w, r)
+	case strings.HasPrefix(r.URL.Path, "/api/ipam"):
+		http.StripPrefix("/api", h.IPAMHandler).ServeHTTP(w, r)
+	case strings.HasPrefix(r.URL.Path, "/api/kubernetes"):
+		http.StripPrefix("/api", h.KubernetesHandler).ServeHTTP(w, r)
+	case strings.HasPrefix(r.URL.Path, "/api/maintenance_windows"):
+		http.StripPrefix("/api", h.MaintenanceWindowHandler).ServeHTTP(w, r)
 	case strings.HasPrefix(r.URL.Path, "/api/motd"):
 		http.StripPrefix("/api", h.MOTDHandler).ServeHTTP(w, r)
+	case strings.HasPrefix(r.URL.Path, "/api/multi_endpoint_stacks"):
+		http.StripPrefix("/api", h.StackHandler).ServeHTTP(w, r)
+	case strings.HasPrefix(r.URL.Path, "/api/notification_channels"):
+		http.StripPrefix("/api", h.NotificationChannelHandler).ServeHTTP(w, r)
+	case strings.HasPrefix(r.URL.Path, "/api/notifications"):
+		http.StripPrefix("/api", h.NotificationHandler).ServeHTTP(w, r)
+	case strings.HasPrefix(r.URL.Path, "/api/organizations"):
+		http.StripPrefix("/api", h.OrganizationHandler).ServeHTTP(w, r)
+	case strings.HasPrefix(r.URL.Path, "/api/permissions"):
+		http.StripPrefix("/api", h.PermissionHandler).ServeHTTP(w, r)
 	case strings.HasPrefix(r.URL.Path, "/api/registries"):
 		http.StripPrefix("/api", h.RegistryHandler).ServeHTTP(w, r)
+	case strings.HasPrefix(r.URL.Path, "/api/reports"):
+		http.StripPrefix("/api", h.ReportHandler).ServeHTTP(w, r)
 	case strings.HasPrefix(r.URL.Path, "/api/resource_controls"):
 		http.StripPrefix("/api", h.ResourceControlHandler).ServeHTTP(w, r)
 	case strings.HasPrefix(r.URL.Path, "/api/roles"):
 		http.StripPrefix("/api", h.RoleHandler).ServeHTTP(w, r)
+	case strings.HasPrefix(r.URL.Path, "/api/scaling_schedules"):
+		http.StripPrefix("/api", h.ScalingScheduleHandler).ServeHTTP(w, r)
+	case strings.HasPrefix(r.URL.Path, "/api/search"):
+		http.StripPrefix("/api", h.SearchHandler).ServeHTTP(w, r)
+	case strings.HasPrefix(r.URL.Path, "/api/shadow_users"):
+		http.StripPrefix("/api", h.ShadowUserHandler).ServeHTTP(w, r)
 	case strings.HasPrefix(r.URL.Path, "/api/settings"):
 		http.StripPrefix("/api", h.SettingsHandler).ServeHTTP(w, r)
 	case strings.HasPrefix(r.URL.Path, "/api/stacks"):
@@ -116,10 +222,16 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		http.StripPrefix("/api", h.StatusHandler).ServeHTTP(w, r)
 	case strings.HasPrefix(r.URL.Path, "/api/tags"):
 		http.StripPrefix("/api", h.TagHandler).ServeHTTP(w, r)
+	case strings.HasPrefix(r.URL.Path, "/api/tasks"):
+		http.StripPrefix("/api", h.TaskHandler).ServeHTTP(w, r)
+	case strings.HasPrefix(r.URL.Path, "/api/telemetry"):
+		http.StripPrefix("/api", h.TelemetryHandler).ServeHTTP(w, r)
 	case strings.HasPrefix(r.URL.Path, "/api/templates"):
 		http.StripPrefix("/api", h.TemplatesHandler).ServeHTTP(w, r)
 	case strings.HasPrefix(r.URL.Path, "/api/upload"):
 		http.StripPrefix("/api", h.UploadHandler).ServeHTTP(w, r)
+	case strings.HasPrefix(r.URL.Path, "/api/usage"):
+		http.StripPrefix("/api", h.UsageHandler).ServeHTTP(w, r)
 	case strings.HasPrefix(r.URL.Path, "/api/users"):
 		http.StripPrefix("/api", h.UserHandler).ServeHTTP(w, r)
 	case strings.HasPrefix(r.URL.Path, "/api/teams"):
@@ -130,6 +242,10 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		http.StripPrefix("/api", h.WebSocketHandler).ServeHTTP(w, r)
 	case strings.HasPrefix(r.URL.Path, "/api/webhooks"):
 		http.StripPrefix("/api", h.WebhookHandler).ServeHTTP(w, r)
+	case r.URL.Path == "/healthz" || r.URL.Path == "/readyz":
+		h.HealthHandler.ServeHTTP(w, r)
+	case strings.HasPrefix(r.URL.Path, "/debug/") && h.DebugHandler != nil:
+		h.DebugHandler.ServeHTTP(w, r)
 	case strings.HasPrefix(r.URL.Path, "/"):
 		h.FileHandler.ServeHTTP(w, r)
 	}
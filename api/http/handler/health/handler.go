@@ -0,0 +1,29 @@
+package health
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/health"
+)
+
+// Handler is the HTTP handler used to serve the health and readiness probes.
+type Handler struct {
+	*mux.Router
+	HealthChecker *health.Checker
+}
+
+// NewHandler creates a handler to serve the health and readiness probes.
+func NewHandler(bouncer *security.RequestBouncer) *Handler {
+	h := &Handler{
+		Router: mux.NewRouter(),
+	}
+	h.Handle("/healthz",
+		bouncer.PublicAccess(httperror.LoggerHandler(h.healthz))).Methods(http.MethodGet)
+	h.Handle("/readyz",
+		bouncer.PublicAccess(httperror.LoggerHandler(h.readyz))).Methods(http.MethodGet)
+
+	return h
+}
@@ -0,0 +1,39 @@
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/portainer/api/internal/health"
+)
+
+// GET request on /healthz
+// Liveness probe, responds with a 503 status code when the database or a background scheduler is down.
+func (handler *Handler) healthz(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	return writeReport(w, handler.HealthChecker.Liveness())
+}
+
+// GET request on /readyz
+// Readiness probe, responds with a 503 status code when a component required to serve requests is down.
+func (handler *Handler) readyz(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	return writeReport(w, handler.HealthChecker.Readiness())
+}
+
+func writeReport(w http.ResponseWriter, report *health.Report) *httperror.HandlerError {
+	statusCode := http.StatusOK
+	if report.Status != health.StatusUp {
+		statusCode = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(statusCode)
+
+	err := json.NewEncoder(w).Encode(report)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to write JSON response", err}
+	}
+
+	return nil
+}
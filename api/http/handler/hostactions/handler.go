@@ -0,0 +1,103 @@
+package hostactions
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/http/proxy"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+var (
+	errHostActionNotAuthorized = errors.New("The host action is not granted to the user or its teams")
+	errEndpointNotAllowed      = errors.New("The host action cannot run on this endpoint")
+	errEndpointNotAgent        = errors.New("Host actions can only run on Docker endpoints managed through the Portainer agent")
+)
+
+// Handler is the HTTP handler used to handle host action operations.
+type Handler struct {
+	*mux.Router
+	requestBouncer       *security.RequestBouncer
+	DataStore            portainer.DataStore
+	ProxyManager         *proxy.Manager
+	ReverseTunnelService portainer.ReverseTunnelService
+}
+
+// NewHandler creates a handler to manage host action operations.
+func NewHandler(bouncer *security.RequestBouncer) *Handler {
+	h := &Handler{
+		Router:         mux.NewRouter(),
+		requestBouncer: bouncer,
+	}
+	h.Handle("/host_actions",
+		bouncer.InstanceAdminAccess(httperror.LoggerHandler(h.hostActionCreate))).Methods(http.MethodPost)
+	h.Handle("/host_actions",
+		bouncer.RestrictedAccess(httperror.LoggerHandler(h.hostActionList))).Methods(http.MethodGet)
+	h.Handle("/host_actions/{id}",
+		bouncer.RestrictedAccess(httperror.LoggerHandler(h.hostActionInspect))).Methods(http.MethodGet)
+	h.Handle("/host_actions/{id}",
+		bouncer.InstanceAdminAccess(httperror.LoggerHandler(h.hostActionUpdate))).Methods(http.MethodPut)
+	h.Handle("/host_actions/{id}",
+		bouncer.InstanceAdminAccess(httperror.LoggerHandler(h.hostActionDelete))).Methods(http.MethodDelete)
+	h.Handle("/host_actions/{id}/run",
+		bouncer.RestrictedAccess(httperror.LoggerHandler(h.hostActionRun))).Methods(http.MethodPost)
+	return h
+}
+
+// userCanRunAction returns true when the user is an administrator or when the action is granted
+// to the user or to one of its teams
+func userCanRunAction(context *security.RestrictedRequestContext, action *portainer.HostAction) bool {
+	if context.IsAdmin {
+		return true
+	}
+
+	for _, userID := range action.AuthorizedUsers {
+		if userID == context.UserID {
+			return true
+		}
+	}
+
+	for _, teamID := range action.AuthorizedTeams {
+		for _, membership := range context.UserMemberships {
+			if membership.TeamID == teamID {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// actionAllowedOnEndpoint returns true when the action is not restricted to specific endpoints
+// or when the endpoint is one of them
+func actionAllowedOnEndpoint(action *portainer.HostAction, endpointID portainer.EndpointID) bool {
+	if len(action.EndpointIDs) == 0 {
+		return true
+	}
+
+	for _, id := range action.EndpointIDs {
+		if id == endpointID {
+			return true
+		}
+	}
+	return false
+}
+
+// validateHostAction verifies the command and the timeout of an action, the default timeout is applied when it is not defined
+func validateHostAction(action *portainer.HostAction) error {
+	if len(action.Command) == 0 || action.Command[0] == "" {
+		return errors.New("Invalid host action command. The command must start with an executable")
+	}
+
+	if action.Timeout == 0 {
+		action.Timeout = defaultTimeout
+	}
+	if action.Timeout < 0 || action.Timeout > maxTimeout {
+		return errors.New("Invalid host action timeout. Value must be between 1 and 3600 seconds")
+	}
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package hostactions
+
+import (
+	"testing"
+
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+func TestUserCanRunAction(t *testing.T) {
+	action := &portainer.HostAction{
+		AuthorizedUsers: []portainer.UserID{2},
+		AuthorizedTeams: []portainer.TeamID{5},
+	}
+
+	tests := []struct {
+		name     string
+		context  *security.RestrictedRequestContext
+		expected bool
+	}{
+		{"administrator", &security.RestrictedRequestContext{IsAdmin: true, UserID: 1}, true},
+		{"authorized user", &security.RestrictedRequestContext{UserID: 2}, true},
+		{"member of an authorized team", &security.RestrictedRequestContext{UserID: 3, UserMemberships: []portainer.TeamMembership{{UserID: 3, TeamID: 5}}}, true},
+		{"unauthorized user", &security.RestrictedRequestContext{UserID: 4, UserMemberships: []portainer.TeamMembership{{UserID: 4, TeamID: 6}}}, false},
+	}
+
+	for _, test := range tests {
+		if result := userCanRunAction(test.context, action); result != test.expected {
+			t.Errorf("%s: got %t, want %t", test.name, result, test.expected)
+		}
+	}
+}
+
+func TestActionAllowedOnEndpoint(t *testing.T) {
+	if !actionAllowedOnEndpoint(&portainer.HostAction{}, 3) {
+		t.Error("an action without endpoints should be allowed on every endpoint")
+	}
+
+	action := &portainer.HostAction{EndpointIDs: []portainer.EndpointID{1, 2}}
+	if !actionAllowedOnEndpoint(action, 2) {
+		t.Error("the action should be allowed on one of its endpoints")
+	}
+	if actionAllowedOnEndpoint(action, 3) {
+		t.Error("the action should not be allowed outside of its endpoints")
+	}
+}
@@ -0,0 +1,75 @@
+package hostactions
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/asaskevich/govalidator"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+const (
+	// defaultTimeout is the timeout in seconds of the actions without timeout
+	defaultTimeout = 60
+	maxTimeout     = 3600
+)
+
+type hostActionCreatePayload struct {
+	Name            string
+	Description     string
+	Command         []string
+	Timeout         int
+	EndpointIDs     []portainer.EndpointID
+	AuthorizedUsers []portainer.UserID
+	AuthorizedTeams []portainer.TeamID
+}
+
+func (payload *hostActionCreatePayload) Validate(r *http.Request) error {
+	if govalidator.IsNull(payload.Name) {
+		return errors.New("Invalid host action name")
+	}
+	return nil
+}
+
+// POST request on /api/host_actions
+func (handler *Handler) hostActionCreate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	var payload hostActionCreatePayload
+	err := request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	tokenData, err := security.RetrieveTokenData(r)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve user authentication token", err}
+	}
+
+	action := &portainer.HostAction{
+		Name:            payload.Name,
+		Description:     payload.Description,
+		Command:         payload.Command,
+		Timeout:         payload.Timeout,
+		EndpointIDs:     payload.EndpointIDs,
+		AuthorizedUsers: payload.AuthorizedUsers,
+		AuthorizedTeams: payload.AuthorizedTeams,
+		CreatedBy:       tokenData.Username,
+		CreationDate:    time.Now().Unix(),
+	}
+
+	err = validateHostAction(action)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	err = handler.DataStore.HostAction().CreateHostAction(action)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist the host action inside the database", err}
+	}
+
+	return response.JSON(w, action)
+}
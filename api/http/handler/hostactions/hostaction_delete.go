@@ -0,0 +1,33 @@
+package hostactions
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+)
+
+// DELETE request on /api/host_actions/:id
+func (handler *Handler) hostActionDelete(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	actionID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid host action identifier route variable", err}
+	}
+
+	_, err = handler.DataStore.HostAction().HostAction(portainer.HostActionID(actionID))
+	if err == bolterrors.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find a host action with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a host action with the specified identifier inside the database", err}
+	}
+
+	err = handler.DataStore.HostAction().DeleteHostAction(portainer.HostActionID(actionID))
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to remove the host action from the database", err}
+	}
+
+	return response.Empty(w)
+}
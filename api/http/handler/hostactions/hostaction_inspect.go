@@ -0,0 +1,38 @@
+package hostactions
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+// GET request on /api/host_actions/:id
+func (handler *Handler) hostActionInspect(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	actionID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid host action identifier route variable", err}
+	}
+
+	action, err := handler.DataStore.HostAction().HostAction(portainer.HostActionID(actionID))
+	if err == bolterrors.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find a host action with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a host action with the specified identifier inside the database", err}
+	}
+
+	securityContext, err := security.RetrieveRestrictedRequestContext(r)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve info from request context", err}
+	}
+
+	if !userCanRunAction(securityContext, action) {
+		return &httperror.HandlerError{http.StatusForbidden, "Permission denied to access the host action", errHostActionNotAuthorized}
+	}
+
+	return response.JSON(w, action)
+}
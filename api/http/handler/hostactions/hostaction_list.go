@@ -0,0 +1,34 @@
+package hostactions
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+// GET request on /api/host_actions
+//
+// The administrators see all the actions, the other users only see the actions granted to them or to their teams.
+func (handler *Handler) hostActionList(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	actions, err := handler.DataStore.HostAction().HostActions()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve host actions from the database", err}
+	}
+
+	securityContext, err := security.RetrieveRestrictedRequestContext(r)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve info from request context", err}
+	}
+
+	authorizedActions := make([]portainer.HostAction, 0)
+	for idx := range actions {
+		if userCanRunAction(securityContext, &actions[idx]) {
+			authorizedActions = append(authorizedActions, actions[idx])
+		}
+	}
+
+	return response.JSON(w, authorizedActions)
+}
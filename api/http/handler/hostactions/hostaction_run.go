@@ -0,0 +1,213 @@
+package hostactions
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+	httperrors "github.com/portainer/portainer/api/http/errors"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+type hostActionRunPayload struct {
+	EndpointID portainer.EndpointID
+	// NodeName is the name of the Swarm node running the action, the agent receiving the request runs it when not defined
+	NodeName string
+}
+
+type (
+	agentHostActionRequest struct {
+		Command []string `json:"Command"`
+		Timeout int      `json:"Timeout"`
+	}
+
+	agentHostActionResponse struct {
+		Output   string `json:"Output"`
+		ExitCode int    `json:"ExitCode"`
+	}
+
+	hostActionRunResponse struct {
+		ActionID   portainer.HostActionID `json:"ActionId"`
+		EndpointID portainer.EndpointID   `json:"EndpointId"`
+		NodeName   string                 `json:"NodeName,omitempty"`
+		ExitCode   int                    `json:"ExitCode"`
+		Output     string                 `json:"Output"`
+	}
+)
+
+func (payload *hostActionRunPayload) Validate(r *http.Request) error {
+	if payload.EndpointID == 0 {
+		return errors.New("Invalid endpoint identifier")
+	}
+	return nil
+}
+
+// POST request on /api/host_actions/:id/run
+//
+// Runs the command of the action on the host of an agent endpoint and returns the captured output.
+// The action must be granted to the user or to one of its teams and the user must have access to the endpoint.
+func (handler *Handler) hostActionRun(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	actionID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid host action identifier route variable", err}
+	}
+
+	var payload hostActionRunPayload
+	err = request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	action, err := handler.DataStore.HostAction().HostAction(portainer.HostActionID(actionID))
+	if err == bolterrors.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find a host action with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a host action with the specified identifier inside the database", err}
+	}
+
+	securityContext, err := security.RetrieveRestrictedRequestContext(r)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve info from request context", err}
+	}
+
+	if !userCanRunAction(securityContext, action) {
+		return &httperror.HandlerError{http.StatusForbidden, "Permission denied to run the host action", errHostActionNotAuthorized}
+	}
+
+	if !actionAllowedOnEndpoint(action, payload.EndpointID) {
+		return &httperror.HandlerError{http.StatusForbidden, "The host action is not allowed on this endpoint", errEndpointNotAllowed}
+	}
+
+	endpoint, err := handler.DataStore.Endpoint().Endpoint(payload.EndpointID)
+	if err == bolterrors.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find an endpoint with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find an endpoint with the specified identifier inside the database", err}
+	}
+
+	err = handler.requestBouncer.AuthorizedEndpointOperation(r, endpoint)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusForbidden, "Permission denied to access endpoint", err}
+	}
+
+	if endpoint.Type != portainer.AgentOnDockerEnvironment && endpoint.Type != portainer.EdgeAgentOnDockerEnvironment {
+		return &httperror.HandlerError{http.StatusBadRequest, "The endpoint is not managed through the Portainer agent", errEndpointNotAgent}
+	}
+
+	if endpoint.Type == portainer.EdgeAgentOnDockerEnvironment {
+		handlerErr := handler.openEdgeTunnel(endpoint)
+		if handlerErr != nil {
+			return handlerErr
+		}
+	}
+
+	result, err := handler.runOnAgent(r, endpoint, action, payload.NodeName)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to run the host action on the endpoint", err}
+	}
+
+	tokenData, err := security.RetrieveTokenData(r)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve user authentication token", err}
+	}
+
+	log.Printf("[INFO] [http,hostactions] [message: host action executed] [user: %s] [action: %s] [endpoint: %s] [node: %s] [exit_code: %d]", tokenData.Username, action.Name, endpoint.Name, payload.NodeName, result.ExitCode)
+
+	return response.JSON(w, &hostActionRunResponse{
+		ActionID:   action.ID,
+		EndpointID: endpoint.ID,
+		NodeName:   payload.NodeName,
+		ExitCode:   result.ExitCode,
+		Output:     result.Output,
+	})
+}
+
+// openEdgeTunnel requests the Edge agent to open its reverse tunnel when the tunnel is idle
+// and waits for the agent to connect
+func (handler *Handler) openEdgeTunnel(endpoint *portainer.Endpoint) *httperror.HandlerError {
+	if endpoint.EdgeID == "" {
+		return &httperror.HandlerError{http.StatusInternalServerError, "No Edge agent registered with the endpoint", errors.New("No agent available")}
+	}
+
+	if endpoint.EdgePendingApproval {
+		return &httperror.HandlerError{http.StatusForbidden, "The Edge endpoint is waiting for approval", httperrors.ErrEdgeEndpointPendingApproval}
+	}
+
+	tunnel := handler.ReverseTunnelService.GetTunnelDetails(endpoint.ID)
+	if tunnel.Status != portainer.EdgeAgentIdle {
+		return nil
+	}
+
+	handler.ProxyManager.DeleteEndpointProxy(endpoint)
+
+	err := handler.ReverseTunnelService.SetTunnelStatusToRequired(endpoint.ID)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to update tunnel status", err}
+	}
+
+	settings, err := handler.DataStore.Settings().Settings()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve settings from the database", err}
+	}
+
+	waitForAgentToConnect := time.Duration(settings.EdgeAgentCheckinInterval) * time.Second
+	time.Sleep(waitForAgentToConnect * 2)
+
+	return nil
+}
+
+// runOnAgent sends the command of the action to the agent of the endpoint through the endpoint proxy
+func (handler *Handler) runOnAgent(r *http.Request, endpoint *portainer.Endpoint, action *portainer.HostAction, nodeName string) (*agentHostActionResponse, error) {
+	proxy := handler.ProxyManager.GetEndpointProxy(endpoint)
+	if proxy == nil {
+		var err error
+		proxy, err = handler.ProxyManager.CreateAndRegisterEndpointProxy(endpoint)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	body, err := json.Marshal(&agentHostActionRequest{Command: action.Command, Timeout: action.Timeout})
+	if err != nil {
+		return nil, err
+	}
+
+	agentRequest, err := http.NewRequest(http.MethodPost, "/v2/host/actions/run", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	agentRequest = agentRequest.WithContext(r.Context())
+	agentRequest.Header.Set("Content-Type", "application/json")
+	if nodeName != "" {
+		agentRequest.Header.Set(portainer.PortainerAgentTargetHeader, nodeName)
+	}
+
+	recorder := httptest.NewRecorder()
+	proxy.ServeHTTP(recorder, agentRequest)
+
+	switch recorder.Code {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, errors.New("The agent of the endpoint does not support host actions")
+	default:
+		return nil, fmt.Errorf("The agent replied with status %d: %s", recorder.Code, recorder.Body.String())
+	}
+
+	var result agentHostActionResponse
+	err = json.NewDecoder(recorder.Body).Decode(&result)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,90 @@
+package hostactions
+
+import (
+	"errors"
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+)
+
+type hostActionUpdatePayload struct {
+	Name            *string
+	Description     *string
+	Command         []string
+	Timeout         *int
+	EndpointIDs     []portainer.EndpointID
+	AuthorizedUsers []portainer.UserID
+	AuthorizedTeams []portainer.TeamID
+}
+
+func (payload *hostActionUpdatePayload) Validate(r *http.Request) error {
+	if payload.Name != nil && *payload.Name == "" {
+		return errors.New("Invalid host action name")
+	}
+	return nil
+}
+
+// PUT request on /api/host_actions/:id
+func (handler *Handler) hostActionUpdate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	actionID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid host action identifier route variable", err}
+	}
+
+	var payload hostActionUpdatePayload
+	err = request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	action, err := handler.DataStore.HostAction().HostAction(portainer.HostActionID(actionID))
+	if err == bolterrors.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find a host action with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a host action with the specified identifier inside the database", err}
+	}
+
+	if payload.Name != nil {
+		action.Name = *payload.Name
+	}
+
+	if payload.Description != nil {
+		action.Description = *payload.Description
+	}
+
+	if payload.Command != nil {
+		action.Command = payload.Command
+	}
+
+	if payload.Timeout != nil {
+		action.Timeout = *payload.Timeout
+	}
+
+	if payload.EndpointIDs != nil {
+		action.EndpointIDs = payload.EndpointIDs
+	}
+
+	if payload.AuthorizedUsers != nil {
+		action.AuthorizedUsers = payload.AuthorizedUsers
+	}
+
+	if payload.AuthorizedTeams != nil {
+		action.AuthorizedTeams = payload.AuthorizedTeams
+	}
+
+	err = validateHostAction(action)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	err = handler.DataStore.HostAction().UpdateHostAction(action.ID, action)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist the host action changes inside the database", err}
+	}
+
+	return response.JSON(w, action)
+}
@@ -0,0 +1,31 @@
+package images
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/docker"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+// Handler is the HTTP handler used to handle image operations executed in background tasks.
+type Handler struct {
+	*mux.Router
+	requestBouncer      *security.RequestBouncer
+	DataStore           portainer.DataStore
+	DockerClientFactory *docker.ClientFactory
+	TaskManager         portainer.TaskManager
+}
+
+// NewHandler creates a handler to manage image operations.
+func NewHandler(bouncer *security.RequestBouncer) *Handler {
+	h := &Handler{
+		Router:         mux.NewRouter(),
+		requestBouncer: bouncer,
+	}
+	h.Handle("/endpoints/{id}/images/pull",
+		bouncer.RestrictedAccess(httperror.LoggerHandler(h.imagePull))).Methods(http.MethodPost)
+	return h
+}
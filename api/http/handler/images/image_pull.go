@@ -0,0 +1,97 @@
+package images
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/asaskevich/govalidator"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+	"github.com/portainer/portainer/api/docker"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+type imagePullPayload struct {
+	// Image to pull, the latest tag is pulled when no tag is specified
+	Image string
+}
+
+func (payload *imagePullPayload) Validate(r *http.Request) error {
+	if govalidator.IsNull(payload.Image) {
+		return errors.New("Invalid image name")
+	}
+	return nil
+}
+
+// POST request on /api/endpoints/:id/images/pull?nodeName=<nodeName>
+// The image is pulled in a background task reporting the progress of each layer, the task is returned.
+func (handler *Handler) imagePull(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	endpointID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid endpoint identifier route variable", err}
+	}
+
+	var payload imagePullPayload
+	err = request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	endpoint, err := handler.DataStore.Endpoint().Endpoint(portainer.EndpointID(endpointID))
+	if err == bolterrors.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find an endpoint with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find an endpoint with the specified identifier inside the database", err}
+	}
+
+	err = handler.requestBouncer.AuthorizedEndpointOperation(r, endpoint)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusForbidden, "Permission denied to access endpoint", err}
+	}
+
+	securityContext, err := security.RetrieveRestrictedRequestContext(r)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve info from request context", err}
+	}
+
+	dockerhub, err := handler.DataStore.DockerHub().DockerHub()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve DockerHub details from the database", err}
+	}
+
+	registries, err := handler.DataStore.Registry().Registries()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve registries from the database", err}
+	}
+
+	registryAuth, err := docker.RegistryAuthentication(payload.Image, security.FilterRegistries(registries, securityContext), dockerhub)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to build the registry credentials", err}
+	}
+
+	cli, err := handler.DockerClientFactory.CreateClient(endpoint, r.FormValue("nodeName"))
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to create a Docker client", err}
+	}
+
+	task := &portainer.Task{
+		Type:       portainer.ImagePullTask,
+		UserID:     securityContext.UserID,
+		EndpointID: endpoint.ID,
+		ResourceID: payload.Image,
+	}
+
+	err = handler.TaskManager.Run(task, func(reporter portainer.TaskReporter) error {
+		defer cli.Close()
+		return docker.PullImage(cli, payload.Image, registryAuth, reporter)
+	})
+	if err != nil {
+		cli.Close()
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to start the image pull task", err}
+	}
+
+	return response.JSON(w, task)
+}
@@ -0,0 +1,28 @@
+package ipam
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+// Handler is the HTTP handler used to report the IP address management of the Docker networks.
+type Handler struct {
+	*mux.Router
+	requestBouncer *security.RequestBouncer
+	DataStore      portainer.DataStore
+}
+
+// NewHandler creates a handler to report the IP address management of the Docker networks.
+func NewHandler(bouncer *security.RequestBouncer) *Handler {
+	h := &Handler{
+		Router:         mux.NewRouter(),
+		requestBouncer: bouncer,
+	}
+	h.Handle("/ipam",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.ipamInspect))).Methods(http.MethodGet)
+	return h
+}
@@ -0,0 +1,208 @@
+package ipam
+
+import (
+	"log"
+	"net"
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/docker"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+const (
+	swarmNetworkScope = "swarm"
+
+	// sameHostConflict is an overlap between two networks of a standalone Docker host
+	sameHostConflict = "host"
+	// overlayConflict is an overlap between an overlay network and another network of the same Swarm cluster
+	overlayConflict = "overlay"
+)
+
+type (
+	ipamReport struct {
+		Subnets   []subnet   `json:"Subnets"`
+		Conflicts []conflict `json:"Conflicts"`
+	}
+
+	subnet struct {
+		EndpointID   portainer.EndpointID `json:"EndpointId"`
+		EndpointName string               `json:"EndpointName"`
+		NetworkID    string               `json:"NetworkId"`
+		NetworkName  string               `json:"NetworkName"`
+		Driver       string               `json:"Driver"`
+		Scope        string               `json:"Scope"`
+		Subnet       string               `json:"Subnet"`
+		Gateway      string               `json:"Gateway,omitempty"`
+		IPRange      string               `json:"IPRange,omitempty"`
+		// Cluster identifies the endpoints sharing overlay networks, endpoints that are not part
+		// of a Swarm cluster have their own cluster
+		Cluster int `json:"Cluster"`
+
+		network *net.IPNet
+		swarm   bool
+	}
+
+	conflict struct {
+		Type    string   `json:"Type"`
+		Subnets []subnet `json:"Subnets"`
+	}
+)
+
+// GET request on /api/ipam?endpointId=<endpointId>
+//
+// Lists the subnets of the Docker networks of all the endpoints, or of a single endpoint, and reports
+// the overlapping subnets. Networks are read from the latest snapshot of each endpoint.
+// Two networks conflict when they overlap on the same standalone host or when one of them is an overlay
+// network of a Swarm cluster the other network is part of. Endpoints sharing an overlay network are
+// considered part of the same Swarm cluster.
+func (handler *Handler) ipamInspect(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	endpointID, _ := request.RetrieveNumericQueryParameter(r, "endpointId", true)
+
+	securityContext, err := security.RetrieveRestrictedRequestContext(r)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve info from request context", err}
+	}
+
+	endpoints, err := handler.DataStore.Endpoint().Endpoints()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve endpoints from the database", err}
+	}
+
+	subnets := make([]subnet, 0)
+	for idx := range endpoints {
+		endpoint := &endpoints[idx]
+		if len(endpoint.Snapshots) == 0 || !security.AuthorizedOrganizationAccess(endpoint.OrganizationID, securityContext.OrganizationID, securityContext.IsAdmin) {
+			continue
+		}
+
+		subnets = append(subnets, endpointSubnets(endpoint)...)
+	}
+
+	assignClusters(subnets)
+
+	report := &ipamReport{
+		Subnets:   make([]subnet, 0),
+		Conflicts: make([]conflict, 0),
+	}
+
+	for i := range subnets {
+		if endpointID == 0 || subnets[i].EndpointID == portainer.EndpointID(endpointID) {
+			report.Subnets = append(report.Subnets, subnets[i])
+		}
+
+		for j := i + 1; j < len(subnets); j++ {
+			if endpointID != 0 && subnets[i].EndpointID != portainer.EndpointID(endpointID) && subnets[j].EndpointID != portainer.EndpointID(endpointID) {
+				continue
+			}
+
+			conflictType := subnetsConflict(&subnets[i], &subnets[j])
+			if conflictType != "" {
+				report.Conflicts = append(report.Conflicts, conflict{Type: conflictType, Subnets: []subnet{subnets[i], subnets[j]}})
+			}
+		}
+	}
+
+	return response.JSON(w, report)
+}
+
+func endpointSubnets(endpoint *portainer.Endpoint) []subnet {
+	subnets := make([]subnet, 0)
+
+	snapshot := &endpoint.Snapshots[0]
+	networks, err := docker.SnapshotNetworks(snapshot)
+	if err != nil {
+		log.Printf("[WARN] [http,ipam] [message: unable to decode snapshot networks] [endpoint: %s] [error: %s]", endpoint.Name, err)
+		return subnets
+	}
+
+	for _, network := range networks {
+		for _, config := range network.IPAM.Config {
+			_, ipNet, err := net.ParseCIDR(config.Subnet)
+			if err != nil {
+				continue
+			}
+
+			subnets = append(subnets, subnet{
+				EndpointID:   endpoint.ID,
+				EndpointName: endpoint.Name,
+				NetworkID:    network.ID,
+				NetworkName:  network.Name,
+				Driver:       network.Driver,
+				Scope:        network.Scope,
+				Subnet:       config.Subnet,
+				Gateway:      config.Gateway,
+				IPRange:      config.IPRange,
+				network:      ipNet,
+				swarm:        snapshot.Swarm,
+			})
+		}
+	}
+
+	return subnets
+}
+
+// assignClusters groups the endpoints sharing an overlay network, the cluster of an endpoint is
+// identified by the lowest endpoint identifier of the group
+func assignClusters(subnets []subnet) {
+	parents := make(map[portainer.EndpointID]portainer.EndpointID)
+
+	var find func(endpointID portainer.EndpointID) portainer.EndpointID
+	find = func(endpointID portainer.EndpointID) portainer.EndpointID {
+		parent, ok := parents[endpointID]
+		if !ok || parent == endpointID {
+			return endpointID
+		}
+		root := find(parent)
+		parents[endpointID] = root
+		return root
+	}
+
+	overlayEndpoints := make(map[string]portainer.EndpointID)
+	for idx := range subnets {
+		if subnets[idx].Scope != swarmNetworkScope {
+			continue
+		}
+
+		other, ok := overlayEndpoints[subnets[idx].NetworkID]
+		if !ok {
+			overlayEndpoints[subnets[idx].NetworkID] = subnets[idx].EndpointID
+			continue
+		}
+
+		left, right := find(subnets[idx].EndpointID), find(other)
+		if left < right {
+			parents[right] = left
+		} else if right < left {
+			parents[left] = right
+		}
+	}
+
+	for idx := range subnets {
+		subnets[idx].Cluster = int(find(subnets[idx].EndpointID))
+	}
+}
+
+func subnetsConflict(left, right *subnet) string {
+	if left.NetworkID == right.NetworkID || left.Cluster != right.Cluster {
+		return ""
+	}
+
+	if !left.network.Contains(right.network.IP) && !right.network.Contains(left.network.IP) {
+		return ""
+	}
+
+	if left.Scope == swarmNetworkScope || right.Scope == swarmNetworkScope {
+		return overlayConflict
+	}
+
+	// the local networks of a Swarm endpoint can be spread across several nodes
+	if left.EndpointID == right.EndpointID && !left.swarm {
+		return sameHostConflict
+	}
+
+	return ""
+}
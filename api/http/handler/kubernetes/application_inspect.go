@@ -0,0 +1,62 @@
+package kubernetes
+
+import (
+	"errors"
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// GET request on /api/kubernetes/:id/namespaces/:namespace/applications/:kind/:name
+func (handler *Handler) applicationInspect(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	kind, name, httpErr := retrieveApplicationReference(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	operationContext, namespace, httpErr := handler.newNamespacedOperationContext(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	application, err := operationContext.kubeClient.GetApplication(namespace, kind, name)
+	if k8serrors.IsNotFound(err) {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find the application", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve the application", err}
+	}
+
+	return response.JSON(w, application)
+}
+
+// retrieveApplicationReference retrieves the kind and the name of the application referenced by the request.
+// The kind route variable is one of deployment, statefulset or daemonset.
+func retrieveApplicationReference(r *http.Request) (portainer.KubernetesApplicationKind, string, *httperror.HandlerError) {
+	kindValue, err := request.RetrieveRouteVariableValue(r, "kind")
+	if err != nil {
+		return "", "", &httperror.HandlerError{http.StatusBadRequest, "Invalid application kind route variable", err}
+	}
+
+	var kind portainer.KubernetesApplicationKind
+	switch kindValue {
+	case "deployment":
+		kind = portainer.KubernetesApplicationDeployment
+	case "statefulset":
+		kind = portainer.KubernetesApplicationStatefulSet
+	case "daemonset":
+		kind = portainer.KubernetesApplicationDaemonSet
+	default:
+		return "", "", &httperror.HandlerError{http.StatusBadRequest, "Invalid application kind route variable", errors.New("Value must be one of: deployment, statefulset or daemonset")}
+	}
+
+	name, err := request.RetrieveRouteVariableValue(r, "name")
+	if err != nil {
+		return "", "", &httperror.HandlerError{http.StatusBadRequest, "Invalid application name route variable", err}
+	}
+
+	return kind, name, nil
+}
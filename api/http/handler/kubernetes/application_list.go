@@ -0,0 +1,54 @@
+package kubernetes
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+)
+
+// GET request on /api/kubernetes/:id/applications?namespace=<namespace>
+//
+// Returns the Deployments, StatefulSets and DaemonSets of the endpoint along with their pods and rollout status.
+// Only the applications of the namespaces accessible by the user are returned.
+func (handler *Handler) applicationList(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	namespace, _ := request.RetrieveQueryParameter(r, "namespace", true)
+
+	operationContext, httpErr := handler.newOperationContext(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	if namespace != "" {
+		httpErr = operationContext.authorizeNamespace(namespace)
+		if httpErr != nil {
+			return httpErr
+		}
+	}
+
+	applications, err := operationContext.kubeClient.GetApplications(namespace)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve applications", err}
+	}
+
+	if operationContext.securityContext.IsAdmin {
+		return response.JSON(w, applications)
+	}
+
+	canAccessNamespace := operationContext.namespaceFilter()
+	filteredApplications := make([]portainer.KubernetesApplication, 0)
+	for _, application := range applications {
+		allowed, err := canAccessNamespace(application.Namespace)
+		if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to verify namespace access", err}
+		}
+
+		if allowed {
+			filteredApplications = append(filteredApplications, application)
+		}
+	}
+
+	return response.JSON(w, filteredApplications)
+}
@@ -0,0 +1,102 @@
+package kubernetes
+
+import (
+	"errors"
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+type applicationRollbackPayload struct {
+	// Revision to roll back to, the application is rolled back to the previous revision when 0
+	Revision int64
+}
+
+func (payload *applicationRollbackPayload) Validate(r *http.Request) error {
+	if payload.Revision < 0 {
+		return errors.New("Invalid revision. Value must be a positive number")
+	}
+	return nil
+}
+
+// GET request on /api/kubernetes/:id/namespaces/:namespace/applications/:kind/:name/revisions
+func (handler *Handler) applicationRevisions(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	kind, name, httpErr := retrieveApplicationReference(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	operationContext, namespace, httpErr := handler.newNamespacedOperationContext(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	revisions, err := operationContext.kubeClient.GetApplicationRevisions(namespace, kind, name)
+	if k8serrors.IsNotFound(err) {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find the application", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve the application rollout history", err}
+	}
+
+	return response.JSON(w, revisions)
+}
+
+// POST request on /api/kubernetes/:id/namespaces/:namespace/applications/:kind/:name/pause
+func (handler *Handler) applicationPause(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	return handler.applicationRolloutOperation(w, r, func(kubeClient portainer.KubeClient, namespace string, kind portainer.KubernetesApplicationKind, name string) error {
+		return kubeClient.PauseApplicationRollout(namespace, kind, name)
+	})
+}
+
+// POST request on /api/kubernetes/:id/namespaces/:namespace/applications/:kind/:name/resume
+func (handler *Handler) applicationResume(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	return handler.applicationRolloutOperation(w, r, func(kubeClient portainer.KubeClient, namespace string, kind portainer.KubernetesApplicationKind, name string) error {
+		return kubeClient.ResumeApplicationRollout(namespace, kind, name)
+	})
+}
+
+// POST request on /api/kubernetes/:id/namespaces/:namespace/applications/:kind/:name/rollback
+func (handler *Handler) applicationRollback(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	var payload applicationRollbackPayload
+	err := request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	return handler.applicationRolloutOperation(w, r, func(kubeClient portainer.KubeClient, namespace string, kind portainer.KubernetesApplicationKind, name string) error {
+		return kubeClient.UndoApplicationRollout(namespace, kind, name, payload.Revision)
+	})
+}
+
+type rolloutOperation func(kubeClient portainer.KubeClient, namespace string, kind portainer.KubernetesApplicationKind, name string) error
+
+// applicationRolloutOperation executes a rollout operation and returns the updated application.
+func (handler *Handler) applicationRolloutOperation(w http.ResponseWriter, r *http.Request, operation rolloutOperation) *httperror.HandlerError {
+	kind, name, httpErr := retrieveApplicationReference(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	operationContext, namespace, httpErr := handler.newNamespacedOperationContext(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	err := operation(operationContext.kubeClient, namespace, kind, name)
+	if k8serrors.IsNotFound(err) {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find the application", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to execute the rollout operation", err}
+	}
+
+	application, err := operationContext.kubeClient.GetApplication(namespace, kind, name)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve the application", err}
+	}
+
+	return response.JSON(w, application)
+}
@@ -0,0 +1,200 @@
+package kubernetes
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/kubernetes/cli"
+)
+
+var errNamespaceAccessDenied = errors.New("Access denied to namespace")
+
+// Handler is the HTTP handler used to handle Kubernetes operations that aggregate
+// multiple Kubernetes API calls.
+type Handler struct {
+	*mux.Router
+	requestBouncer          *security.RequestBouncer
+	DataStore               portainer.DataStore
+	KubernetesClientFactory *cli.ClientFactory
+}
+
+// NewHandler creates a handler to manage Kubernetes operations.
+func NewHandler(bouncer *security.RequestBouncer) *Handler {
+	h := &Handler{
+		Router:         mux.NewRouter(),
+		requestBouncer: bouncer,
+	}
+	h.Handle("/kubernetes/{id}/applications",
+		bouncer.RestrictedAccess(httperror.LoggerHandler(h.applicationList))).Methods(http.MethodGet)
+	h.Handle("/kubernetes/{id}/namespaces/{namespace}/applications/{kind}/{name}",
+		bouncer.RestrictedAccess(httperror.LoggerHandler(h.applicationInspect))).Methods(http.MethodGet)
+	h.Handle("/kubernetes/{id}/namespaces/{namespace}/applications/{kind}/{name}/revisions",
+		bouncer.RestrictedAccess(httperror.LoggerHandler(h.applicationRevisions))).Methods(http.MethodGet)
+	h.Handle("/kubernetes/{id}/namespaces/{namespace}/applications/{kind}/{name}/pause",
+		bouncer.RestrictedAccess(httperror.LoggerHandler(h.applicationPause))).Methods(http.MethodPost)
+	h.Handle("/kubernetes/{id}/namespaces/{namespace}/applications/{kind}/{name}/resume",
+		bouncer.RestrictedAccess(httperror.LoggerHandler(h.applicationResume))).Methods(http.MethodPost)
+	h.Handle("/kubernetes/{id}/namespaces/{namespace}/applications/{kind}/{name}/rollback",
+		bouncer.RestrictedAccess(httperror.LoggerHandler(h.applicationRollback))).Methods(http.MethodPost)
+	h.Handle("/kubernetes/{id}/nodes/{name}",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.nodeUpdate))).Methods(http.MethodPut)
+	h.Handle("/kubernetes/{id}/nodes/{name}/cordon",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.nodeCordon))).Methods(http.MethodPost)
+	h.Handle("/kubernetes/{id}/nodes/{name}/uncordon",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.nodeUncordon))).Methods(http.MethodPost)
+	h.Handle("/kubernetes/{id}/nodes/{name}/drain",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.nodeDrain))).Methods(http.MethodPost)
+	h.Handle("/kubernetes/{id}/ingress_classes",
+		bouncer.RestrictedAccess(httperror.LoggerHandler(h.ingressClassList))).Methods(http.MethodGet)
+	h.Handle("/kubernetes/{id}/ingresses",
+		bouncer.RestrictedAccess(httperror.LoggerHandler(h.ingressList))).Methods(http.MethodGet)
+	h.Handle("/kubernetes/{id}/namespaces/{namespace}/ingresses",
+		bouncer.RestrictedAccess(httperror.LoggerHandler(h.ingressCreate))).Methods(http.MethodPost)
+	h.Handle("/kubernetes/{id}/namespaces/{namespace}/ingresses/{name}",
+		bouncer.RestrictedAccess(httperror.LoggerHandler(h.ingressInspect))).Methods(http.MethodGet)
+	h.Handle("/kubernetes/{id}/namespaces/{namespace}/ingresses/{name}",
+		bouncer.RestrictedAccess(httperror.LoggerHandler(h.ingressUpdate))).Methods(http.MethodPut)
+	h.Handle("/kubernetes/{id}/namespaces/{namespace}/ingresses/{name}",
+		bouncer.RestrictedAccess(httperror.LoggerHandler(h.ingressDelete))).Methods(http.MethodDelete)
+	h.Handle("/kubernetes/{id}/namespaces/{namespace}/tls_secrets",
+		bouncer.RestrictedAccess(httperror.LoggerHandler(h.tlsSecretCreate))).Methods(http.MethodPost)
+	h.Handle("/kubernetes/{id}/persistent_volume_claims",
+		bouncer.RestrictedAccess(httperror.LoggerHandler(h.persistentVolumeClaimList))).Methods(http.MethodGet)
+	h.Handle("/kubernetes/{id}/namespaces/{namespace}/persistent_volume_claims/{name}/resize",
+		bouncer.RestrictedAccess(httperror.LoggerHandler(h.persistentVolumeClaimResize))).Methods(http.MethodPost)
+	h.Handle("/kubernetes/{id}/persistent_volumes/orphaned",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.persistentVolumeOrphanedList))).Methods(http.MethodGet)
+	return h
+}
+
+// operationContext represents the information required to execute a Kubernetes operation on behalf of a user
+type operationContext struct {
+	endpoint        *portainer.Endpoint
+	kubeClient      portainer.KubeClient
+	securityContext *security.RestrictedRequestContext
+}
+
+// canAccessNamespace returns true if the user can access the specified namespace.
+// Administrators can access all the namespaces.
+func (operationContext *operationContext) canAccessNamespace(namespace string) (bool, error) {
+	if operationContext.securityContext.IsAdmin {
+		return true, nil
+	}
+
+	teamIDs := make([]int, 0)
+	for _, membership := range operationContext.securityContext.UserMemberships {
+		teamIDs = append(teamIDs, int(membership.TeamID))
+	}
+
+	return operationContext.kubeClient.UserCanAccessNamespace(int(operationContext.securityContext.UserID), teamIDs, namespace)
+}
+
+// authorizeNamespace returns an error if the user cannot access the specified namespace.
+func (operationContext *operationContext) authorizeNamespace(namespace string) *httperror.HandlerError {
+	allowed, err := operationContext.canAccessNamespace(namespace)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to verify namespace access", err}
+	}
+
+	if !allowed {
+		return &httperror.HandlerError{http.StatusForbidden, "Permission denied to access namespace", errNamespaceAccessDenied}
+	}
+
+	return nil
+}
+
+// namespaceFilter returns a function reporting whether the user can access a namespace.
+// Results are cached so that the access policies are only evaluated once per namespace.
+func (operationContext *operationContext) namespaceFilter() func(namespace string) (bool, error) {
+	namespaceAccesses := make(map[string]bool)
+
+	return func(namespace string) (bool, error) {
+		allowed, ok := namespaceAccesses[namespace]
+		if ok {
+			return allowed, nil
+		}
+
+		allowed, err := operationContext.canAccessNamespace(namespace)
+		if err != nil {
+			return false, err
+		}
+
+		namespaceAccesses[namespace] = allowed
+		return allowed, nil
+	}
+}
+
+// newOperationContext retrieves the Kubernetes endpoint referenced by the request, ensures that the user
+// can access it and retrieves a Kubernetes client targeting it.
+func (handler *Handler) newOperationContext(r *http.Request) (*operationContext, *httperror.HandlerError) {
+	endpointID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return nil, &httperror.HandlerError{http.StatusBadRequest, "Invalid endpoint identifier route variable", err}
+	}
+
+	endpoint, err := handler.DataStore.Endpoint().Endpoint(portainer.EndpointID(endpointID))
+	if err == bolterrors.ErrObjectNotFound {
+		return nil, &httperror.HandlerError{http.StatusNotFound, "Unable to find an endpoint with the specified identifier inside the database", err}
+	} else if err != nil {
+		return nil, &httperror.HandlerError{http.StatusInternalServerError, "Unable to find an endpoint with the specified identifier inside the database", err}
+	}
+
+	if !isKubernetesEndpoint(endpoint) {
+		return nil, &httperror.HandlerError{http.StatusBadRequest, "Invalid endpoint type", errors.New("The endpoint is not a Kubernetes endpoint")}
+	}
+
+	err = handler.requestBouncer.AuthorizedEndpointOperation(r, endpoint)
+	if err != nil {
+		return nil, &httperror.HandlerError{http.StatusForbidden, "Permission denied to access endpoint", err}
+	}
+
+	securityContext, err := security.RetrieveRestrictedRequestContext(r)
+	if err != nil {
+		return nil, &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve info from request context", err}
+	}
+
+	kubeClient, err := handler.KubernetesClientFactory.GetKubeClient(endpoint)
+	if err != nil {
+		return nil, &httperror.HandlerError{http.StatusInternalServerError, "Unable to create Kubernetes client", err}
+	}
+
+	return &operationContext{
+		endpoint:        endpoint,
+		kubeClient:      kubeClient,
+		securityContext: securityContext,
+	}, nil
+}
+
+// newNamespacedOperationContext creates an operation context and ensures that the user can access
+// the namespace referenced by the request.
+func (handler *Handler) newNamespacedOperationContext(r *http.Request) (*operationContext, string, *httperror.HandlerError) {
+	namespace, err := request.RetrieveRouteVariableValue(r, "namespace")
+	if err != nil {
+		return nil, "", &httperror.HandlerError{http.StatusBadRequest, "Invalid namespace route variable", err}
+	}
+
+	operationContext, httpErr := handler.newOperationContext(r)
+	if httpErr != nil {
+		return nil, "", httpErr
+	}
+
+	httpErr = operationContext.authorizeNamespace(namespace)
+	if httpErr != nil {
+		return nil, "", httpErr
+	}
+
+	return operationContext, namespace, nil
+}
+
+func isKubernetesEndpoint(endpoint *portainer.Endpoint) bool {
+	return endpoint.Type == portainer.KubernetesLocalEnvironment ||
+		endpoint.Type == portainer.AgentOnKubernetesEnvironment ||
+		endpoint.Type == portainer.EdgeAgentOnKubernetesEnvironment ||
+		endpoint.Type == portainer.KubeconfigKubernetesEnvironment
+}
@@ -0,0 +1,141 @@
+package kubernetes
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/asaskevich/govalidator"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+type ingressCertManagerPayload struct {
+	// IssuerName is the name of the cert-manager issuer used to sign the certificate
+	IssuerName string
+	// IssuerKind is one of Issuer or ClusterIssuer, defaults to ClusterIssuer
+	IssuerKind string
+	// SecretName is the name of the secret storing the certificate
+	SecretName string
+}
+
+type ingressCreatePayload struct {
+	Name         string
+	IngressClass string
+	Annotations  map[string]string
+	Rules        []portainer.KubernetesIngressRule
+	TLS          []portainer.KubernetesIngressTLS
+	// CertManager requests a certificate for all the hosts of the ingress to cert-manager
+	CertManager *ingressCertManagerPayload
+}
+
+func (payload *ingressCreatePayload) Validate(r *http.Request) error {
+	if govalidator.IsNull(payload.Name) {
+		return errors.New("Invalid ingress name")
+	}
+	if len(payload.Rules) == 0 {
+		return errors.New("Invalid ingress rules. At least one rule must be specified")
+	}
+	err := validateIngressRules(payload.Rules)
+	if err != nil {
+		return err
+	}
+	if payload.CertManager != nil {
+		if govalidator.IsNull(payload.CertManager.IssuerName) {
+			return errors.New("Invalid cert-manager issuer name")
+		}
+		if payload.CertManager.IssuerKind != "" && payload.CertManager.IssuerKind != "Issuer" && payload.CertManager.IssuerKind != "ClusterIssuer" {
+			return errors.New("Invalid cert-manager issuer kind. Value must be one of: Issuer or ClusterIssuer")
+		}
+	}
+	return nil
+}
+
+func validateIngressRules(rules []portainer.KubernetesIngressRule) error {
+	for _, rule := range rules {
+		if govalidator.IsNull(rule.ServiceName) {
+			return errors.New("Invalid ingress rule service name")
+		}
+		if rule.ServicePort <= 0 || rule.ServicePort > 65535 {
+			return errors.New("Invalid ingress rule service port")
+		}
+	}
+	return nil
+}
+
+// POST request on /api/kubernetes/:id/namespaces/:namespace/ingresses
+func (handler *Handler) ingressCreate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	var payload ingressCreatePayload
+	err := request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	operationContext, namespace, httpErr := handler.newNamespacedOperationContext(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	ingress := &portainer.KubernetesIngress{
+		Name:         payload.Name,
+		Namespace:    namespace,
+		IngressClass: payload.IngressClass,
+		Annotations:  payload.Annotations,
+		Rules:        payload.Rules,
+		TLS:          payload.TLS,
+	}
+
+	if payload.CertManager != nil {
+		certificate := newIngressCertificate(ingress, payload.CertManager)
+
+		err = operationContext.kubeClient.CreateCertificate(certificate)
+		if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to create the cert-manager certificate", err}
+		}
+
+		ingress.TLS = append(ingress.TLS, portainer.KubernetesIngressTLS{
+			Hosts:      certificate.DNSNames,
+			SecretName: certificate.SecretName,
+		})
+	}
+
+	err = operationContext.kubeClient.CreateIngress(ingress)
+	if k8serrors.IsAlreadyExists(err) {
+		return &httperror.HandlerError{http.StatusConflict, "An ingress with the same name already exists inside the namespace", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to create the ingress", err}
+	}
+
+	return response.JSON(w, ingress)
+}
+
+func newIngressCertificate(ingress *portainer.KubernetesIngress, payload *ingressCertManagerPayload) *portainer.KubernetesCertificate {
+	certificate := &portainer.KubernetesCertificate{
+		Name:       ingress.Name + "-tls",
+		Namespace:  ingress.Namespace,
+		SecretName: payload.SecretName,
+		DNSNames:   make([]string, 0),
+		IssuerName: payload.IssuerName,
+		IssuerKind: payload.IssuerKind,
+	}
+
+	if certificate.SecretName == "" {
+		certificate.SecretName = certificate.Name
+	}
+
+	if certificate.IssuerKind == "" {
+		certificate.IssuerKind = "ClusterIssuer"
+	}
+
+	hosts := make(map[string]bool)
+	for _, rule := range ingress.Rules {
+		if rule.Host != "" && !hosts[rule.Host] {
+			hosts[rule.Host] = true
+			certificate.DNSNames = append(certificate.DNSNames, rule.Host)
+		}
+	}
+
+	return certificate
+}
@@ -0,0 +1,32 @@
+package kubernetes
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// DELETE request on /api/kubernetes/:id/namespaces/:namespace/ingresses/:name
+func (handler *Handler) ingressDelete(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	name, err := request.RetrieveRouteVariableValue(r, "name")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid ingress name route variable", err}
+	}
+
+	operationContext, namespace, httpErr := handler.newNamespacedOperationContext(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	err = operationContext.kubeClient.DeleteIngress(namespace, name)
+	if k8serrors.IsNotFound(err) {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find the ingress", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to remove the ingress", err}
+	}
+
+	return response.Empty(w)
+}
@@ -0,0 +1,83 @@
+package kubernetes
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+)
+
+// GET request on /api/kubernetes/:id/ingresses?namespace=<namespace>
+//
+// Only the ingresses of the namespaces accessible by the user are returned.
+func (handler *Handler) ingressList(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	namespace, _ := request.RetrieveQueryParameter(r, "namespace", true)
+
+	operationContext, httpErr := handler.newOperationContext(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	if namespace != "" {
+		httpErr = operationContext.authorizeNamespace(namespace)
+		if httpErr != nil {
+			return httpErr
+		}
+	}
+
+	ingresses, err := operationContext.kubeClient.GetIngresses(namespace)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve ingresses", err}
+	}
+
+	if operationContext.securityContext.IsAdmin {
+		return response.JSON(w, ingresses)
+	}
+
+	canAccessNamespace := operationContext.namespaceFilter()
+	filteredIngresses := make([]portainer.KubernetesIngress, 0)
+	for _, ingress := range ingresses {
+		allowed, err := canAccessNamespace(ingress.Namespace)
+		if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to verify namespace access", err}
+		}
+
+		if allowed {
+			filteredIngresses = append(filteredIngresses, ingress)
+		}
+	}
+
+	return response.JSON(w, filteredIngresses)
+}
+
+// GET request on /api/kubernetes/:id/ingress_classes
+//
+// Returns the ingress classes configured for the endpoint along with the classes used by the existing ingresses.
+func (handler *Handler) ingressClassList(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	operationContext, httpErr := handler.newOperationContext(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	discoveredClasses, err := operationContext.kubeClient.GetIngressClasses()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve ingress classes", err}
+	}
+
+	ingressClasses := make([]portainer.KubernetesIngressClassConfig, 0)
+	knownClasses := make(map[string]bool)
+	for _, ingressClass := range operationContext.endpoint.Kubernetes.Configuration.IngressClasses {
+		ingressClasses = append(ingressClasses, ingressClass)
+		knownClasses[ingressClass.Name] = true
+	}
+
+	for _, class := range discoveredClasses {
+		if !knownClasses[class] {
+			ingressClasses = append(ingressClasses, portainer.KubernetesIngressClassConfig{Name: class})
+		}
+	}
+
+	return response.JSON(w, ingressClasses)
+}
@@ -0,0 +1,75 @@
+package kubernetes
+
+import (
+	"errors"
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+type ingressUpdatePayload struct {
+	IngressClass *string
+	Annotations  map[string]string
+	Rules        []portainer.KubernetesIngressRule
+	TLS          []portainer.KubernetesIngressTLS
+}
+
+func (payload *ingressUpdatePayload) Validate(r *http.Request) error {
+	if payload.Rules != nil && len(payload.Rules) == 0 {
+		return errors.New("Invalid ingress rules. At least one rule must be specified")
+	}
+	return validateIngressRules(payload.Rules)
+}
+
+// PUT request on /api/kubernetes/:id/namespaces/:namespace/ingresses/:name
+func (handler *Handler) ingressUpdate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	name, err := request.RetrieveRouteVariableValue(r, "name")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid ingress name route variable", err}
+	}
+
+	var payload ingressUpdatePayload
+	err = request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	operationContext, namespace, httpErr := handler.newNamespacedOperationContext(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	ingress, err := operationContext.kubeClient.GetIngress(namespace, name)
+	if k8serrors.IsNotFound(err) {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find the ingress", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve the ingress", err}
+	}
+
+	if payload.IngressClass != nil {
+		ingress.IngressClass = *payload.IngressClass
+	}
+
+	if payload.Annotations != nil {
+		ingress.Annotations = payload.Annotations
+	}
+
+	if payload.Rules != nil {
+		ingress.Rules = payload.Rules
+	}
+
+	if payload.TLS != nil {
+		ingress.TLS = payload.TLS
+	}
+
+	err = operationContext.kubeClient.UpdateIngress(ingress)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to update the ingress", err}
+	}
+
+	return response.JSON(w, ingress)
+}
@@ -0,0 +1,84 @@
+package kubernetes
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/portainer/api"
+)
+
+type nodeDrainPayload struct {
+	// Force evicts the pods that are not managed by a controller
+	Force bool
+	// DeleteLocalData evicts the pods using emptyDir volumes
+	DeleteLocalData bool
+	// GracePeriod is the number of seconds given to each pod to terminate, the pod default is used when not specified
+	GracePeriod *int64
+	// Timeout is the number of seconds to wait for the pods to be evicted
+	Timeout int
+}
+
+func (payload *nodeDrainPayload) Validate(r *http.Request) error {
+	if payload.GracePeriod != nil && *payload.GracePeriod < 0 {
+		return errors.New("Invalid grace period. Value must be a positive number of seconds")
+	}
+	if payload.Timeout < 0 {
+		return errors.New("Invalid timeout value. Value must be a positive number of seconds")
+	}
+	return nil
+}
+
+// POST request on /api/kubernetes/:id/nodes/:name/drain
+//
+// The eviction progress is streamed as a sequence of JSON objects, one per line.
+// The last object has the completed status when the drain succeeds, the error status otherwise.
+func (handler *Handler) nodeDrain(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	nodeName, err := request.RetrieveRouteVariableValue(r, "name")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid node name route variable", err}
+	}
+
+	var payload nodeDrainPayload
+	err = request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	operationContext, httpErr := handler.newOperationContext(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	options := portainer.KubernetesDrainOptions{
+		Force:           payload.Force,
+		DeleteLocalData: payload.DeleteLocalData,
+		GracePeriod:     -1,
+		Timeout:         payload.Timeout,
+	}
+	if payload.GracePeriod != nil {
+		options.GracePeriod = *payload.GracePeriod
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	encoder := json.NewEncoder(w)
+	flusher, _ := w.(http.Flusher)
+
+	writeEvent := func(event portainer.KubernetesDrainEvent) {
+		encoder.Encode(event)
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+
+	err = operationContext.kubeClient.DrainNode(nodeName, options, writeEvent)
+	if err != nil {
+		writeEvent(portainer.KubernetesDrainEvent{Status: "error", Message: err.Error()})
+	}
+
+	return nil
+}
@@ -0,0 +1,101 @@
+package kubernetes
+
+import (
+	"errors"
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+type nodeUpdatePayload struct {
+	Labels       map[string]string
+	RemoveLabels []string
+	Taints       []portainer.KubernetesNodeTaint
+}
+
+func (payload *nodeUpdatePayload) Validate(r *http.Request) error {
+	for _, taint := range payload.Taints {
+		if taint.Key == "" {
+			return errors.New("Invalid taint key")
+		}
+		if !validTaintEffect(taint.Effect) {
+			return errors.New("Invalid taint effect. Value must be one of: NoSchedule, PreferNoSchedule or NoExecute")
+		}
+	}
+	return nil
+}
+
+func validTaintEffect(effect string) bool {
+	switch effect {
+	case "NoSchedule", "PreferNoSchedule", "NoExecute":
+		return true
+	}
+	return false
+}
+
+// PUT request on /api/kubernetes/:id/nodes/:name
+func (handler *Handler) nodeUpdate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	nodeName, err := request.RetrieveRouteVariableValue(r, "name")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid node name route variable", err}
+	}
+
+	var payload nodeUpdatePayload
+	err = request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	operationContext, httpErr := handler.newOperationContext(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	err = operationContext.kubeClient.UpdateNode(nodeName, portainer.KubernetesNodeUpdate{
+		Labels:       payload.Labels,
+		RemoveLabels: payload.RemoveLabels,
+		Taints:       payload.Taints,
+	})
+	if k8serrors.IsNotFound(err) {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find the node", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to update the node", err}
+	}
+
+	return response.Empty(w)
+}
+
+// POST request on /api/kubernetes/:id/nodes/:name/cordon
+func (handler *Handler) nodeCordon(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	return handler.updateNodeSchedulability(w, r, true)
+}
+
+// POST request on /api/kubernetes/:id/nodes/:name/uncordon
+func (handler *Handler) nodeUncordon(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	return handler.updateNodeSchedulability(w, r, false)
+}
+
+func (handler *Handler) updateNodeSchedulability(w http.ResponseWriter, r *http.Request, cordon bool) *httperror.HandlerError {
+	nodeName, err := request.RetrieveRouteVariableValue(r, "name")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid node name route variable", err}
+	}
+
+	operationContext, httpErr := handler.newOperationContext(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	err = operationContext.kubeClient.CordonNode(nodeName, cordon)
+	if k8serrors.IsNotFound(err) {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find the node", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to update the node schedulability", err}
+	}
+
+	return response.Empty(w)
+}
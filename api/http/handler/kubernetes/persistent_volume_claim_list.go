@@ -0,0 +1,70 @@
+package kubernetes
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+)
+
+// GET request on /api/kubernetes/:id/persistent_volume_claims?namespace=<namespace>
+//
+// Only the claims of the namespaces accessible by the user are returned.
+func (handler *Handler) persistentVolumeClaimList(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	namespace, _ := request.RetrieveQueryParameter(r, "namespace", true)
+
+	operationContext, httpErr := handler.newOperationContext(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	if namespace != "" {
+		httpErr = operationContext.authorizeNamespace(namespace)
+		if httpErr != nil {
+			return httpErr
+		}
+	}
+
+	claims, err := operationContext.kubeClient.GetPersistentVolumeClaims(namespace)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve persistent volume claims", err}
+	}
+
+	if operationContext.securityContext.IsAdmin {
+		return response.JSON(w, claims)
+	}
+
+	canAccessNamespace := operationContext.namespaceFilter()
+	filteredClaims := make([]portainer.KubernetesPersistentVolumeClaim, 0)
+	for _, claim := range claims {
+		allowed, err := canAccessNamespace(claim.Namespace)
+		if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to verify namespace access", err}
+		}
+
+		if allowed {
+			filteredClaims = append(filteredClaims, claim)
+		}
+	}
+
+	return response.JSON(w, filteredClaims)
+}
+
+// GET request on /api/kubernetes/:id/persistent_volumes/orphaned
+//
+// Returns the persistent volumes that are not bound to an existing claim.
+func (handler *Handler) persistentVolumeOrphanedList(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	operationContext, httpErr := handler.newOperationContext(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	volumes, err := operationContext.kubeClient.GetOrphanedPersistentVolumes()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve persistent volumes", err}
+	}
+
+	return response.JSON(w, volumes)
+}
@@ -0,0 +1,53 @@
+package kubernetes
+
+import (
+	"errors"
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+type persistentVolumeClaimResizePayload struct {
+	// Size is the new storage request of the claim, using the Kubernetes quantity format (e.g. 20Gi)
+	Size string
+}
+
+func (payload *persistentVolumeClaimResizePayload) Validate(r *http.Request) error {
+	_, err := resource.ParseQuantity(payload.Size)
+	if err != nil {
+		return errors.New("Invalid size. Value must use the Kubernetes quantity format (e.g. 20Gi)")
+	}
+	return nil
+}
+
+// POST request on /api/kubernetes/:id/namespaces/:namespace/persistent_volume_claims/:name/resize
+func (handler *Handler) persistentVolumeClaimResize(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	name, err := request.RetrieveRouteVariableValue(r, "name")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid persistent volume claim name route variable", err}
+	}
+
+	var payload persistentVolumeClaimResizePayload
+	err = request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	operationContext, namespace, httpErr := handler.newNamespacedOperationContext(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	err = operationContext.kubeClient.ResizePersistentVolumeClaim(namespace, name, payload.Size)
+	if k8serrors.IsNotFound(err) {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find the persistent volume claim", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to resize the persistent volume claim", err}
+	}
+
+	return response.Empty(w)
+}
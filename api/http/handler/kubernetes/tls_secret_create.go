@@ -0,0 +1,71 @@
+package kubernetes
+
+import (
+	"crypto/tls"
+	"errors"
+	"net/http"
+
+	"github.com/asaskevich/govalidator"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+type tlsSecretCreatePayload struct {
+	Name        string
+	Certificate []byte
+	Key         []byte
+}
+
+func (payload *tlsSecretCreatePayload) Validate(r *http.Request) error {
+	name, err := request.RetrieveMultiPartFormValue(r, "Name", false)
+	if err != nil || govalidator.IsNull(name) {
+		return errors.New("Invalid secret name")
+	}
+	payload.Name = name
+
+	certificate, _, err := request.RetrieveMultiPartFormFile(r, "Certificate")
+	if err != nil {
+		return errors.New("Invalid certificate file. Ensure that the file is uploaded correctly")
+	}
+	payload.Certificate = certificate
+
+	key, _, err := request.RetrieveMultiPartFormFile(r, "Key")
+	if err != nil {
+		return errors.New("Invalid key file. Ensure that the file is uploaded correctly")
+	}
+	payload.Key = key
+
+	_, err = tls.X509KeyPair(certificate, key)
+	if err != nil {
+		return errors.New("Invalid certificate and key pair. Both files must be PEM encoded and the key must match the certificate")
+	}
+
+	return nil
+}
+
+// POST request on /api/kubernetes/:id/namespaces/:namespace/tls_secrets
+//
+// Creates a TLS secret from an uploaded certificate and key, the secret can then be referenced by ingresses.
+func (handler *Handler) tlsSecretCreate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	payload := &tlsSecretCreatePayload{}
+	err := payload.Validate(r)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	operationContext, namespace, httpErr := handler.newNamespacedOperationContext(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	err = operationContext.kubeClient.CreateTLSSecret(namespace, payload.Name, payload.Certificate, payload.Key)
+	if k8serrors.IsAlreadyExists(err) {
+		return &httperror.HandlerError{http.StatusConflict, "A secret with the same name already exists inside the namespace", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to create the TLS secret", err}
+	}
+
+	return response.Empty(w)
+}
@@ -0,0 +1,34 @@
+package maintenancewindows
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+// Handler is the HTTP handler used to handle maintenance window operations.
+type Handler struct {
+	*mux.Router
+	DataStore portainer.DataStore
+}
+
+// NewHandler creates a handler to manage maintenance window operations.
+func NewHandler(bouncer *security.RequestBouncer) *Handler {
+	h := &Handler{
+		Router: mux.NewRouter(),
+	}
+	h.Handle("/maintenance_windows",
+		bouncer.InstanceAdminAccess(httperror.LoggerHandler(h.maintenanceWindowCreate))).Methods(http.MethodPost)
+	h.Handle("/maintenance_windows",
+		bouncer.InstanceAdminAccess(httperror.LoggerHandler(h.maintenanceWindowList))).Methods(http.MethodGet)
+	h.Handle("/maintenance_windows/{id}",
+		bouncer.InstanceAdminAccess(httperror.LoggerHandler(h.maintenanceWindowInspect))).Methods(http.MethodGet)
+	h.Handle("/maintenance_windows/{id}",
+		bouncer.InstanceAdminAccess(httperror.LoggerHandler(h.maintenanceWindowUpdate))).Methods(http.MethodPut)
+	h.Handle("/maintenance_windows/{id}",
+		bouncer.InstanceAdminAccess(httperror.LoggerHandler(h.maintenanceWindowDelete))).Methods(http.MethodDelete)
+	return h
+}
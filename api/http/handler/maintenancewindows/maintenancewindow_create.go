@@ -0,0 +1,79 @@
+package maintenancewindows
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/asaskevich/govalidator"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/maintenance"
+)
+
+type maintenanceWindowCreatePayload struct {
+	Name             string
+	EndpointIDs      []int
+	EndpointGroupIDs []int
+	Start            int64
+	End              int64
+	Timezone         string
+	Rules            []portainer.MaintenanceRule
+	Enabled          bool
+}
+
+func (payload *maintenanceWindowCreatePayload) Validate(r *http.Request) error {
+	if govalidator.IsNull(payload.Name) {
+		return errors.New("Invalid maintenance window name")
+	}
+	return nil
+}
+
+// POST request on /api/maintenance_windows
+func (handler *Handler) maintenanceWindowCreate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	var payload maintenanceWindowCreatePayload
+	err := request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	tokenData, err := security.RetrieveTokenData(r)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve user details from authentication token", err}
+	}
+
+	window := &portainer.MaintenanceWindow{
+		Name:         payload.Name,
+		Start:        payload.Start,
+		End:          payload.End,
+		Timezone:     payload.Timezone,
+		Rules:        payload.Rules,
+		Enabled:      payload.Enabled,
+		CreatedBy:    tokenData.ID,
+		CreationDate: time.Now().Unix(),
+	}
+
+	if window.Rules == nil {
+		window.Rules = []portainer.MaintenanceRule{}
+	}
+
+	handlerErr := handler.setWindowTargets(window, payload.EndpointIDs, payload.EndpointGroupIDs)
+	if handlerErr != nil {
+		return handlerErr
+	}
+
+	err = maintenance.ValidateWindow(window)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	err = handler.DataStore.MaintenanceWindow().CreateMaintenanceWindow(window)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist the maintenance window inside the database", err}
+	}
+
+	return response.JSON(w, window)
+}
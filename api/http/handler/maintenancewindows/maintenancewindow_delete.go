@@ -0,0 +1,33 @@
+package maintenancewindows
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+)
+
+// DELETE request on /api/maintenance_windows/:id
+func (handler *Handler) maintenanceWindowDelete(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	windowID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid maintenance window identifier route variable", err}
+	}
+
+	_, err = handler.DataStore.MaintenanceWindow().MaintenanceWindow(portainer.MaintenanceWindowID(windowID))
+	if err == bolterrors.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find a maintenance window with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a maintenance window with the specified identifier inside the database", err}
+	}
+
+	err = handler.DataStore.MaintenanceWindow().DeleteMaintenanceWindow(portainer.MaintenanceWindowID(windowID))
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to remove the maintenance window from the database", err}
+	}
+
+	return response.Empty(w)
+}
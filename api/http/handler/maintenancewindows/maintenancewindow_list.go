@@ -0,0 +1,37 @@
+package maintenancewindows
+
+import (
+	"net/http"
+	"time"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/internal/maintenance"
+)
+
+type maintenanceWindowListItem struct {
+	portainer.MaintenanceWindow
+	Active bool `json:"Active"`
+}
+
+// GET request on /api/maintenance_windows
+//
+// Returns the maintenance windows, Active is true for the windows in progress.
+func (handler *Handler) maintenanceWindowList(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	windows, err := handler.DataStore.MaintenanceWindow().MaintenanceWindows()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve maintenance windows from the database", err}
+	}
+
+	now := time.Now()
+	items := make([]maintenanceWindowListItem, 0, len(windows))
+	for idx := range windows {
+		items = append(items, maintenanceWindowListItem{
+			MaintenanceWindow: windows[idx],
+			Active:            maintenance.IsActive(&windows[idx], now),
+		})
+	}
+
+	return response.JSON(w, items)
+}
@@ -0,0 +1,109 @@
+package maintenancewindows
+
+import (
+	"errors"
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+	"github.com/portainer/portainer/api/internal/maintenance"
+)
+
+type maintenanceWindowUpdatePayload struct {
+	Name             *string
+	EndpointIDs      []int
+	EndpointGroupIDs []int
+	Start            *int64
+	End              *int64
+	Timezone         *string
+	Rules            []portainer.MaintenanceRule
+	Enabled          *bool
+}
+
+func (payload *maintenanceWindowUpdatePayload) Validate(r *http.Request) error {
+	if payload.Name != nil && *payload.Name == "" {
+		return errors.New("Invalid maintenance window name")
+	}
+	return nil
+}
+
+// PUT request on /api/maintenance_windows/:id
+func (handler *Handler) maintenanceWindowUpdate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	windowID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid maintenance window identifier route variable", err}
+	}
+
+	var payload maintenanceWindowUpdatePayload
+	err = request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	window, err := handler.DataStore.MaintenanceWindow().MaintenanceWindow(portainer.MaintenanceWindowID(windowID))
+	if err == bolterrors.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find a maintenance window with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a maintenance window with the specified identifier inside the database", err}
+	}
+
+	if payload.Name != nil {
+		window.Name = *payload.Name
+	}
+
+	if payload.EndpointIDs != nil || payload.EndpointGroupIDs != nil {
+		endpointIDs := payload.EndpointIDs
+		if endpointIDs == nil {
+			for _, id := range window.EndpointIDs {
+				endpointIDs = append(endpointIDs, int(id))
+			}
+		}
+
+		endpointGroupIDs := payload.EndpointGroupIDs
+		if endpointGroupIDs == nil {
+			for _, id := range window.EndpointGroupIDs {
+				endpointGroupIDs = append(endpointGroupIDs, int(id))
+			}
+		}
+
+		handlerErr := handler.setWindowTargets(window, endpointIDs, endpointGroupIDs)
+		if handlerErr != nil {
+			return handlerErr
+		}
+	}
+
+	if payload.Start != nil {
+		window.Start = *payload.Start
+	}
+
+	if payload.End != nil {
+		window.End = *payload.End
+	}
+
+	if payload.Timezone != nil {
+		window.Timezone = *payload.Timezone
+	}
+
+	if payload.Rules != nil {
+		window.Rules = payload.Rules
+	}
+
+	if payload.Enabled != nil {
+		window.Enabled = *payload.Enabled
+	}
+
+	err = maintenance.ValidateWindow(window)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	err = handler.DataStore.MaintenanceWindow().UpdateMaintenanceWindow(window.ID, window)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist the maintenance window changes inside the database", err}
+	}
+
+	return response.JSON(w, window)
+}
@@ -0,0 +1,36 @@
+package maintenancewindows
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+)
+
+// setWindowTargets ensures that the endpoints and endpoint groups targeted by a maintenance window exist
+func (handler *Handler) setWindowTargets(window *portainer.MaintenanceWindow, endpointIDs, endpointGroupIDs []int) *httperror.HandlerError {
+	window.EndpointIDs = make([]portainer.EndpointID, 0, len(endpointIDs))
+	for _, id := range endpointIDs {
+		_, err := handler.DataStore.Endpoint().Endpoint(portainer.EndpointID(id))
+		if err == bolterrors.ErrObjectNotFound {
+			return &httperror.HandlerError{http.StatusNotFound, "Unable to find an endpoint with the specified identifier inside the database", err}
+		} else if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find an endpoint with the specified identifier inside the database", err}
+		}
+		window.EndpointIDs = append(window.EndpointIDs, portainer.EndpointID(id))
+	}
+
+	window.EndpointGroupIDs = make([]portainer.EndpointGroupID, 0, len(endpointGroupIDs))
+	for _, id := range endpointGroupIDs {
+		_, err := handler.DataStore.EndpointGroup().EndpointGroup(portainer.EndpointGroupID(id))
+		if err == bolterrors.ErrObjectNotFound {
+			return &httperror.HandlerError{http.StatusNotFound, "Unable to find an endpoint group with the specified identifier inside the database", err}
+		} else if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find an endpoint group with the specified identifier inside the database", err}
+		}
+		window.EndpointGroupIDs = append(window.EndpointGroupIDs, portainer.EndpointGroupID(id))
+	}
+
+	return nil
+}
@@ -0,0 +1,81 @@
+package networks
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/docker/docker/client"
+	"github.com/gorilla/mux"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+	"github.com/portainer/portainer/api/docker"
+	"github.com/portainer/portainer/api/http/proxy"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+var errUnsupportedEndpoint = errors.New("Macvlan and ipvlan networks are only supported on Linux Docker endpoints")
+
+// Handler is the HTTP handler used to handle network operations that cannot be
+// expressed as a single Docker API call.
+type Handler struct {
+	*mux.Router
+	requestBouncer       *security.RequestBouncer
+	DataStore            portainer.DataStore
+	DockerClientFactory  *docker.ClientFactory
+	ProxyManager         *proxy.Manager
+	ReverseTunnelService portainer.ReverseTunnelService
+}
+
+// NewHandler creates a handler to manage network operations.
+func NewHandler(bouncer *security.RequestBouncer) *Handler {
+	h := &Handler{
+		Router:         mux.NewRouter(),
+		requestBouncer: bouncer,
+	}
+	h.Handle("/endpoints/{id}/networks/create",
+		bouncer.RestrictedAccess(httperror.LoggerHandler(h.networkCreate))).Methods(http.MethodPost)
+	h.Handle("/endpoints/{id}/networks/interfaces",
+		bouncer.RestrictedAccess(httperror.LoggerHandler(h.parentInterfaceList))).Methods(http.MethodGet)
+	return h
+}
+
+// retrieveNetworkEndpoint retrieves the endpoint referenced by the request route variables, ensures that
+// the user can access it and that it is a Linux Docker endpoint, and creates a Docker client for the node
+// specified in the request.
+func (handler *Handler) retrieveNetworkEndpoint(r *http.Request) (*portainer.Endpoint, *client.Client, *httperror.HandlerError) {
+	endpointID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return nil, nil, &httperror.HandlerError{http.StatusBadRequest, "Invalid endpoint identifier route variable", err}
+	}
+
+	nodeName, _ := request.RetrieveQueryParameter(r, "nodeName", true)
+
+	endpoint, err := handler.DataStore.Endpoint().Endpoint(portainer.EndpointID(endpointID))
+	if err == bolterrors.ErrObjectNotFound {
+		return nil, nil, &httperror.HandlerError{http.StatusNotFound, "Unable to find an endpoint with the specified identifier inside the database", err}
+	} else if err != nil {
+		return nil, nil, &httperror.HandlerError{http.StatusInternalServerError, "Unable to find an endpoint with the specified identifier inside the database", err}
+	}
+
+	err = handler.requestBouncer.AuthorizedEndpointOperation(r, endpoint)
+	if err != nil {
+		return nil, nil, &httperror.HandlerError{http.StatusForbidden, "Permission denied to access endpoint", err}
+	}
+
+	if endpoint.Type != portainer.DockerEnvironment && endpoint.Type != portainer.AgentOnDockerEnvironment && endpoint.Type != portainer.EdgeAgentOnDockerEnvironment {
+		return nil, nil, &httperror.HandlerError{http.StatusBadRequest, "Macvlan and ipvlan networks are only supported on Linux Docker endpoints", errUnsupportedEndpoint}
+	}
+
+	if docker.EndpointOSType(endpoint) == docker.OSTypeWindows {
+		return nil, nil, &httperror.HandlerError{http.StatusBadRequest, "Macvlan and ipvlan networks are only supported on Linux Docker endpoints", errUnsupportedEndpoint}
+	}
+
+	cli, err := handler.DockerClientFactory.CreateClient(endpoint, nodeName)
+	if err != nil {
+		return nil, nil, &httperror.HandlerError{http.StatusInternalServerError, "Unable to create Docker client", err}
+	}
+
+	return endpoint, cli, nil
+}
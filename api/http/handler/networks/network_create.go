@@ -0,0 +1,216 @@
+package networks
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"regexp"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/client"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/docker"
+	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/authorization"
+	"github.com/portainer/portainer/api/internal/organization"
+)
+
+var (
+	errNetworkAlreadyExists = errors.New("A network with the same name already exists")
+	errSubnetOverlap        = errors.New("The subnet overlaps with the subnet of an existing network")
+	errInvalidConfigNetwork = errors.New("The configuration network must be a macvlan or ipvlan configuration-only network")
+	errSwarmManagerRequired = errors.New("Swarm networks must be created on a manager node")
+)
+
+// networkNameRe matches the names accepted by the Docker daemon for the networks
+var networkNameRe = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+type networkCreatePayload struct {
+	Name string
+	// Config is the definition of the network, it must not be specified when the network is created from
+	// the configuration networks of the nodes
+	Config *docker.HostNetworkDefinition
+	// ConfigOnly creates a configuration-only network holding the definition of a swarm network on a node,
+	// the configuration networks cannot be used by containers
+	ConfigOnly bool
+	// ConfigFrom is the name of the configuration-only networks the network is created from,
+	// a configuration network with this name must exist on each node using the network
+	ConfigFrom string
+	// Swarm creates a network scoped to the swarm cluster, usable by the services
+	Swarm      bool
+	Attachable bool
+	Internal   bool
+	Labels     map[string]string
+}
+
+type networkCreateResponse struct {
+	ID        string `json:"Id"`
+	Warning   string `json:"Warning,omitempty"`
+	Portainer struct {
+		ResourceControl *portainer.ResourceControl `json:"ResourceControl,omitempty"`
+	} `json:"Portainer"`
+}
+
+func (payload *networkCreatePayload) Validate(r *http.Request) error {
+	if !networkNameRe.MatchString(payload.Name) {
+		return errors.New("Invalid network name")
+	}
+
+	if payload.ConfigFrom != "" {
+		if payload.Config != nil {
+			return errors.New("Invalid network definition: a network created from configuration networks cannot define its own configuration")
+		}
+		if payload.ConfigOnly {
+			return errors.New("Invalid network definition: a configuration network cannot be created from configuration networks")
+		}
+		if !networkNameRe.MatchString(payload.ConfigFrom) {
+			return errors.New("Invalid configuration network name")
+		}
+		return nil
+	}
+
+	if payload.Config == nil {
+		return errors.New("Invalid network definition: one of Config or ConfigFrom must be specified")
+	}
+
+	if payload.ConfigOnly && (payload.Swarm || payload.Attachable || payload.Internal) {
+		return errors.New("Invalid network definition: a configuration network cannot be scoped to the swarm, attachable or internal")
+	}
+
+	return payload.Config.Validate()
+}
+
+// POST request on /api/endpoints/:id/networks/create?nodeName=<nodeName>
+//
+// Creates a macvlan or ipvlan network attached to a parent interface of the host from a typed definition.
+// A network spanning a swarm cluster is created in two steps: a configuration-only network is created on
+// each node with the parent interface and the IP range of the node, then the swarm network is created on
+// a manager node from the configuration networks with ConfigFrom.
+func (handler *Handler) networkCreate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	var payload networkCreatePayload
+	err := request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	securityContext, err := security.RetrieveRestrictedRequestContext(r)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve info from request context", err}
+	}
+
+	endpoint, cli, httpErr := handler.retrieveNetworkEndpoint(r)
+	if httpErr != nil {
+		return httpErr
+	}
+	defer cli.Close()
+
+	networks, err := cli.NetworkList(context.Background(), types.NetworkListOptions{})
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve the networks of the endpoint", err}
+	}
+
+	networkCreate := types.NetworkCreate{
+		CheckDuplicate: true,
+		Attachable:     payload.Attachable,
+		Internal:       payload.Internal,
+		ConfigOnly:     payload.ConfigOnly,
+		Labels:         payload.Labels,
+	}
+
+	if payload.ConfigFrom != "" {
+		configNetwork, err := cli.NetworkInspect(context.Background(), payload.ConfigFrom, types.NetworkInspectOptions{})
+		if client.IsErrNotFound(err) {
+			return &httperror.HandlerError{http.StatusBadRequest, "Unable to find the configuration network on the node", err}
+		} else if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to inspect the configuration network", err}
+		}
+
+		if !configNetwork.ConfigOnly || !docker.IsHostNetworkDriver(configNetwork.Driver) {
+			return &httperror.HandlerError{http.StatusBadRequest, "Invalid configuration network", errInvalidConfigNetwork}
+		}
+
+		networkCreate.Driver = configNetwork.Driver
+		networkCreate.ConfigFrom = &network.ConfigReference{Network: payload.ConfigFrom}
+	} else {
+		for _, existingNetwork := range networks {
+			if existingNetwork.ConfigOnly || existingNetwork.ConfigFrom.Network != "" {
+				continue
+			}
+
+			for _, config := range existingNetwork.IPAM.Config {
+				if docker.SubnetsOverlap(config.Subnet, payload.Config.Subnet) {
+					return &httperror.HandlerError{http.StatusConflict, "The subnet overlaps with the subnet of the network " + existingNetwork.Name, errSubnetOverlap}
+				}
+			}
+		}
+
+		networkCreate.Driver = payload.Config.Driver
+		networkCreate.Options = payload.Config.DriverOptions()
+		networkCreate.IPAM = payload.Config.IPAM()
+		networkCreate.EnableIPv6 = net.ParseIP(payload.Config.Gateway).To4() == nil
+	}
+
+	for _, existingNetwork := range networks {
+		if existingNetwork.Name == payload.Name {
+			return &httperror.HandlerError{http.StatusConflict, "A network with the same name already exists", errNetworkAlreadyExists}
+		}
+	}
+
+	if payload.Swarm || payload.ConfigFrom != "" {
+		info, err := cli.Info(context.Background())
+		if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve engine information", err}
+		}
+
+		if !info.Swarm.ControlAvailable {
+			return &httperror.HandlerError{http.StatusBadRequest, "Swarm networks must be created on a manager node", errSwarmManagerRequired}
+		}
+
+		networkCreate.Scope = "swarm"
+	}
+
+	created, err := cli.NetworkCreate(context.Background(), payload.Name, networkCreate)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to create the network", err}
+	}
+
+	createResponse := &networkCreateResponse{ID: created.ID, Warning: created.Warning}
+	createResponse.Portainer.ResourceControl, err = handler.createNetworkResourceControl(created.ID, endpoint, securityContext)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist the resource control associated to the network", err}
+	}
+
+	return response.JSON(w, createResponse)
+}
+
+func (handler *Handler) createNetworkResourceControl(resourceID string, endpoint *portainer.Endpoint, securityContext *security.RestrictedRequestContext) (*portainer.ResourceControl, error) {
+	settings, err := handler.DataStore.Settings().Settings()
+	if err != nil {
+		return nil, err
+	}
+
+	userTeamIDs := make([]portainer.TeamID, 0)
+	for _, membership := range securityContext.UserMemberships {
+		userTeamIDs = append(userTeamIDs, membership.TeamID)
+	}
+
+	settings, err = organization.EffectiveSettings(handler.DataStore, settings, endpoint.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	policy := authorization.EffectiveResourceOwnershipPolicy(endpoint, settings)
+	resourceControl := authorization.NewDefaultResourceControl(resourceID, portainer.NetworkResourceControl, securityContext.UserID, userTeamIDs, policy)
+
+	err = handler.DataStore.ResourceControl().CreateResourceControl(resourceControl)
+	if err != nil {
+		return nil, err
+	}
+
+	return resourceControl, nil
+}
@@ -0,0 +1,157 @@
+package networks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+
+	"github.com/docker/docker/api/types"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/docker"
+)
+
+const (
+	interfaceSourceAgent   = "agent"
+	interfaceSourceNetwork = "network"
+)
+
+type (
+	parentInterface struct {
+		Name      string   `json:"Name"`
+		Addresses []string `json:"Addresses"`
+		// Networks are the macvlan and ipvlan networks of the node attached to the interface
+		Networks []string `json:"Networks"`
+		// Source is agent when the interface is reported by the agent inventory and network when it is
+		// only known as the parent of an existing network
+		Source string `json:"Source"`
+	}
+
+	agentHostInfo struct {
+		NetworkInterfaces []agentNetworkInterface `json:"NetworkInterfaces"`
+	}
+
+	agentNetworkInterface struct {
+		Name      string   `json:"Name"`
+		Addresses []string `json:"Addresses"`
+	}
+)
+
+// GET request on /api/endpoints/:id/networks/interfaces?nodeName=<nodeName>
+//
+// Returns the interfaces of the host of a node that can be used as the parent of a macvlan or ipvlan network.
+// The interfaces are reported by the agent inventory on the agent endpoints, the parents of the existing
+// macvlan and ipvlan networks of the node are returned as well so that they can be reused on any endpoint.
+func (handler *Handler) parentInterfaceList(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	endpoint, cli, httpErr := handler.retrieveNetworkEndpoint(r)
+	if httpErr != nil {
+		return httpErr
+	}
+	defer cli.Close()
+
+	interfaces := make(map[string]*parentInterface)
+
+	if handler.agentInventoryAvailable(endpoint) {
+		hostInfo, err := handler.agentHostInfo(r, endpoint)
+		if err != nil {
+			log.Printf("[WARN] [http,networks,interfaces] [endpoint: %s] [message: unable to retrieve the agent inventory] [error: %s]", endpoint.Name, err)
+		} else {
+			for _, networkInterface := range hostInfo.NetworkInterfaces {
+				interfaces[networkInterface.Name] = &parentInterface{
+					Name:      networkInterface.Name,
+					Addresses: append([]string{}, networkInterface.Addresses...),
+					Networks:  make([]string, 0),
+					Source:    interfaceSourceAgent,
+				}
+			}
+		}
+	}
+
+	networks, err := cli.NetworkList(context.Background(), types.NetworkListOptions{})
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve the networks of the endpoint", err}
+	}
+
+	for _, network := range networks {
+		parent := network.Options["parent"]
+		if !docker.IsHostNetworkDriver(network.Driver) || parent == "" {
+			continue
+		}
+
+		networkInterface, ok := interfaces[parent]
+		if !ok {
+			networkInterface = &parentInterface{
+				Name:      parent,
+				Addresses: make([]string, 0),
+				Networks:  make([]string, 0),
+				Source:    interfaceSourceNetwork,
+			}
+			interfaces[parent] = networkInterface
+		}
+		networkInterface.Networks = append(networkInterface.Networks, network.Name)
+	}
+
+	parentInterfaces := make([]*parentInterface, 0, len(interfaces))
+	for _, networkInterface := range interfaces {
+		parentInterfaces = append(parentInterfaces, networkInterface)
+	}
+	sort.Slice(parentInterfaces, func(i, j int) bool {
+		return parentInterfaces[i].Name < parentInterfaces[j].Name
+	})
+
+	return response.JSON(w, parentInterfaces)
+}
+
+// agentInventoryAvailable returns true when the agent of an endpoint can be queried without waiting
+// for an Edge agent to open its tunnel
+func (handler *Handler) agentInventoryAvailable(endpoint *portainer.Endpoint) bool {
+	switch endpoint.Type {
+	case portainer.AgentOnDockerEnvironment:
+		return true
+	case portainer.EdgeAgentOnDockerEnvironment:
+		return handler.ReverseTunnelService.GetTunnelDetails(endpoint.ID).Status == portainer.EdgeAgentActive
+	}
+	return false
+}
+
+// agentHostInfo retrieves the host inventory of the agent of the node specified in the request
+// through the endpoint proxy
+func (handler *Handler) agentHostInfo(r *http.Request, endpoint *portainer.Endpoint) (*agentHostInfo, error) {
+	proxy := handler.ProxyManager.GetEndpointProxy(endpoint)
+	if proxy == nil {
+		var err error
+		proxy, err = handler.ProxyManager.CreateAndRegisterEndpointProxy(endpoint)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	agentRequest, err := http.NewRequest(http.MethodGet, "/v2/host/info", nil)
+	if err != nil {
+		return nil, err
+	}
+	agentRequest = agentRequest.WithContext(r.Context())
+	if nodeName := r.URL.Query().Get("nodeName"); nodeName != "" {
+		agentRequest.Header.Set(portainer.PortainerAgentTargetHeader, nodeName)
+	}
+
+	recorder := httptest.NewRecorder()
+	proxy.ServeHTTP(recorder, agentRequest)
+
+	if recorder.Code != http.StatusOK {
+		return nil, fmt.Errorf("the agent replied with status %d", recorder.Code)
+	}
+
+	var hostInfo agentHostInfo
+	err = json.NewDecoder(recorder.Body).Decode(&hostInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	return &hostInfo, nil
+}
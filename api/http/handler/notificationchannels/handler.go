@@ -0,0 +1,40 @@
+package notificationchannels
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+// Handler is the HTTP handler used to handle notification channel operations.
+type Handler struct {
+	*mux.Router
+	DataStore portainer.DataStore
+}
+
+// NewHandler creates a handler to manage notification channel operations.
+func NewHandler(bouncer *security.RequestBouncer) *Handler {
+	h := &Handler{
+		Router: mux.NewRouter(),
+	}
+	h.Handle("/notification_channels",
+		bouncer.InstanceAdminAccess(httperror.LoggerHandler(h.notificationChannelCreate))).Methods(http.MethodPost)
+	h.Handle("/notification_channels",
+		bouncer.InstanceAdminAccess(httperror.LoggerHandler(h.notificationChannelList))).Methods(http.MethodGet)
+	h.Handle("/notification_channels/{id}",
+		bouncer.InstanceAdminAccess(httperror.LoggerHandler(h.notificationChannelInspect))).Methods(http.MethodGet)
+	h.Handle("/notification_channels/{id}",
+		bouncer.InstanceAdminAccess(httperror.LoggerHandler(h.notificationChannelUpdate))).Methods(http.MethodPut)
+	h.Handle("/notification_channels/{id}",
+		bouncer.InstanceAdminAccess(httperror.LoggerHandler(h.notificationChannelDelete))).Methods(http.MethodDelete)
+	h.Handle("/notification_channels/{id}/check",
+		bouncer.InstanceAdminAccess(httperror.LoggerHandler(h.notificationChannelCheck))).Methods(http.MethodPost)
+	return h
+}
+
+func hideFields(channel *portainer.NotificationChannel) {
+	channel.RoutingKey = ""
+}
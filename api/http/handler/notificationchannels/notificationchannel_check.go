@@ -0,0 +1,49 @@
+package notificationchannels
+
+import (
+	"net/http"
+	"time"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+	"github.com/portainer/portainer/api/internal/notification"
+)
+
+// POST request on /api/notification_channels/:id/check
+func (handler *Handler) notificationChannelCheck(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	channelID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid notification channel identifier route variable", err}
+	}
+
+	channel, err := handler.DataStore.NotificationChannel().NotificationChannel(portainer.NotificationChannelID(channelID))
+	if err == bolterrors.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find a notification channel with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a notification channel with the specified identifier inside the database", err}
+	}
+
+	testNotification := &portainer.Notification{
+		Type:     portainer.EndpointUpNotification,
+		Severity: portainer.InfoNotificationSeverity,
+		Title:    "Test notification",
+		Message:  "This is a test notification sent from Portainer",
+		Time:     time.Now().Unix(),
+	}
+
+	settings, err := handler.DataStore.Settings().Settings()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve the settings from the database", err}
+	}
+
+	localizedNotification := notification.Localize(*testNotification, settings.DefaultLocale)
+	err = notification.SendToChannel(channel, &localizedNotification, "")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadGateway, "Unable to send the test notification", err}
+	}
+
+	return response.Empty(w)
+}
@@ -0,0 +1,64 @@
+package notificationchannels
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/asaskevich/govalidator"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/internal/notification"
+)
+
+type notificationChannelCreatePayload struct {
+	Name       string
+	Type       string
+	Enabled    bool
+	URL        string
+	RoutingKey string
+	Routes     []portainer.NotificationRoute
+}
+
+func (payload *notificationChannelCreatePayload) Validate(r *http.Request) error {
+	if govalidator.IsNull(payload.Name) {
+		return errors.New("Invalid notification channel name")
+	}
+	return nil
+}
+
+// POST request on /api/notification_channels
+func (handler *Handler) notificationChannelCreate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	var payload notificationChannelCreatePayload
+	err := request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	channel := &portainer.NotificationChannel{
+		Name:       payload.Name,
+		Type:       portainer.NotificationChannelType(payload.Type),
+		Enabled:    payload.Enabled,
+		URL:        payload.URL,
+		RoutingKey: payload.RoutingKey,
+		Routes:     payload.Routes,
+	}
+
+	if channel.Routes == nil {
+		channel.Routes = []portainer.NotificationRoute{}
+	}
+
+	err = notification.ValidateChannel(channel)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	err = handler.DataStore.NotificationChannel().CreateNotificationChannel(channel)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist the notification channel inside the database", err}
+	}
+
+	hideFields(channel)
+	return response.JSON(w, channel)
+}
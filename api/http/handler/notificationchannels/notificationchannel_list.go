@@ -0,0 +1,22 @@
+package notificationchannels
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/response"
+)
+
+// GET request on /api/notification_channels
+func (handler *Handler) notificationChannelList(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	channels, err := handler.DataStore.NotificationChannel().NotificationChannels()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve notification channels from the database", err}
+	}
+
+	for idx := range channels {
+		hideFields(&channels[idx])
+	}
+
+	return response.JSON(w, channels)
+}
@@ -0,0 +1,83 @@
+package notificationchannels
+
+import (
+	"errors"
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+	"github.com/portainer/portainer/api/internal/notification"
+)
+
+type notificationChannelUpdatePayload struct {
+	Name       *string
+	Enabled    *bool
+	URL        *string
+	RoutingKey *string
+	Routes     []portainer.NotificationRoute
+}
+
+func (payload *notificationChannelUpdatePayload) Validate(r *http.Request) error {
+	if payload.Name != nil && *payload.Name == "" {
+		return errors.New("Invalid notification channel name")
+	}
+	return nil
+}
+
+// PUT request on /api/notification_channels/:id
+func (handler *Handler) notificationChannelUpdate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	channelID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid notification channel identifier route variable", err}
+	}
+
+	var payload notificationChannelUpdatePayload
+	err = request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	channel, err := handler.DataStore.NotificationChannel().NotificationChannel(portainer.NotificationChannelID(channelID))
+	if err == bolterrors.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find a notification channel with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a notification channel with the specified identifier inside the database", err}
+	}
+
+	if payload.Name != nil {
+		channel.Name = *payload.Name
+	}
+
+	if payload.Enabled != nil {
+		channel.Enabled = *payload.Enabled
+	}
+
+	if payload.URL != nil {
+		channel.URL = *payload.URL
+	}
+
+	// the routing key is never returned by the API, an empty value keeps the current one
+	if payload.RoutingKey != nil && *payload.RoutingKey != "" {
+		channel.RoutingKey = *payload.RoutingKey
+	}
+
+	if payload.Routes != nil {
+		channel.Routes = payload.Routes
+	}
+
+	err = notification.ValidateChannel(channel)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	err = handler.DataStore.NotificationChannel().UpdateNotificationChannel(channel.ID, channel)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist the notification channel changes inside the database", err}
+	}
+
+	hideFields(channel)
+	return response.JSON(w, channel)
+}
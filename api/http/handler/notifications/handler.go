@@ -0,0 +1,61 @@
+package notifications
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+	httperrors "github.com/portainer/portainer/api/http/errors"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+// Handler is the HTTP handler used to handle the notification inbox of the users.
+type Handler struct {
+	*mux.Router
+	DataStore portainer.DataStore
+}
+
+// NewHandler creates a handler to manage the notification inbox of the users.
+func NewHandler(bouncer *security.RequestBouncer) *Handler {
+	h := &Handler{
+		Router: mux.NewRouter(),
+	}
+	h.Handle("/notifications",
+		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.notificationList))).Methods(http.MethodGet)
+	h.Handle("/notifications/read",
+		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.notificationReadAll))).Methods(http.MethodPost)
+	h.Handle("/notifications/{id}",
+		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.notificationUpdate))).Methods(http.MethodPut)
+	h.Handle("/notifications/{id}",
+		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.notificationDelete))).Methods(http.MethodDelete)
+	return h
+}
+
+// retrieveUserNotification returns the notification specified in the route, it must belong to the current user
+func (handler *Handler) retrieveUserNotification(r *http.Request) (*portainer.UserNotification, *httperror.HandlerError) {
+	notificationID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return nil, &httperror.HandlerError{http.StatusBadRequest, "Invalid notification identifier route variable", err}
+	}
+
+	tokenData, err := security.RetrieveTokenData(r)
+	if err != nil {
+		return nil, &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve user authentication token", err}
+	}
+
+	notification, err := handler.DataStore.UserNotification().UserNotification(portainer.UserNotificationID(notificationID))
+	if err == bolterrors.ErrObjectNotFound {
+		return nil, &httperror.HandlerError{http.StatusNotFound, "Unable to find a notification with the specified identifier inside the database", err}
+	} else if err != nil {
+		return nil, &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a notification with the specified identifier inside the database", err}
+	}
+
+	if notification.UserID != tokenData.ID {
+		return nil, &httperror.HandlerError{http.StatusForbidden, "Permission denied to access notification", httperrors.ErrResourceAccessDenied}
+	}
+
+	return notification, nil
+}
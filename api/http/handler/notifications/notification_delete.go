@@ -0,0 +1,23 @@
+package notifications
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/response"
+)
+
+// DELETE request on /api/notifications/:id
+func (handler *Handler) notificationDelete(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	notification, handlerErr := handler.retrieveUserNotification(r)
+	if handlerErr != nil {
+		return handlerErr
+	}
+
+	err := handler.DataStore.UserNotification().DeleteUserNotification(notification.ID)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to remove the notification from the database", err}
+	}
+
+	return response.Empty(w)
+}
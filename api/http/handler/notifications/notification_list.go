@@ -0,0 +1,48 @@
+package notifications
+
+import (
+	"net/http"
+	"sort"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/notification"
+)
+
+// GET request on /api/notifications?unread=<unread>
+// Returns the notifications of the current user, most recent first.
+// When the unread query parameter is set to true, only the unread notifications are returned.
+// The notifications are translated in the locale of the user.
+func (handler *Handler) notificationList(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	unreadOnly, _ := request.RetrieveBooleanQueryParameter(r, "unread", true)
+
+	tokenData, err := security.RetrieveTokenData(r)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve user authentication token", err}
+	}
+
+	notifications, err := handler.DataStore.UserNotification().UserNotificationsByUserID(tokenData.ID)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve notifications from the database", err}
+	}
+
+	locale := security.RetrieveLocale(r)
+
+	filteredNotifications := make([]portainer.UserNotification, 0, len(notifications))
+	for _, userNotification := range notifications {
+		if unreadOnly && userNotification.Read {
+			continue
+		}
+		userNotification.Notification = notification.Localize(userNotification.Notification, locale)
+		filteredNotifications = append(filteredNotifications, userNotification)
+	}
+
+	sort.Slice(filteredNotifications, func(i, j int) bool {
+		return filteredNotifications[i].ID > filteredNotifications[j].ID
+	})
+
+	return response.JSON(w, filteredNotifications)
+}
@@ -0,0 +1,37 @@
+package notifications
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+// POST request on /api/notifications/read
+// Marks all the notifications of the current user as read.
+func (handler *Handler) notificationReadAll(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	tokenData, err := security.RetrieveTokenData(r)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve user authentication token", err}
+	}
+
+	notifications, err := handler.DataStore.UserNotification().UserNotificationsByUserID(tokenData.ID)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve notifications from the database", err}
+	}
+
+	for _, notification := range notifications {
+		if notification.Read {
+			continue
+		}
+
+		notification.Read = true
+		err = handler.DataStore.UserNotification().UpdateUserNotification(notification.ID, &notification)
+		if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist the notification changes inside the database", err}
+		}
+	}
+
+	return response.Empty(w)
+}
@@ -0,0 +1,40 @@
+package notifications
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+)
+
+type notificationUpdatePayload struct {
+	Read bool
+}
+
+func (payload *notificationUpdatePayload) Validate(r *http.Request) error {
+	return nil
+}
+
+// PUT request on /api/notifications/:id
+func (handler *Handler) notificationUpdate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	var payload notificationUpdatePayload
+	err := request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	notification, handlerErr := handler.retrieveUserNotification(r)
+	if handlerErr != nil {
+		return handlerErr
+	}
+
+	notification.Read = payload.Read
+
+	err = handler.DataStore.UserNotification().UpdateUserNotification(notification.ID, notification)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist the notification changes inside the database", err}
+	}
+
+	return response.JSON(w, notification)
+}
@@ -0,0 +1,79 @@
+package organizations
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/authorization"
+)
+
+var (
+	errOrganizationAlreadyExists = errors.New("An organization with the same name already exists")
+	errOrganizationNotEmpty      = errors.New("The organization still has resources")
+)
+
+// Handler is the HTTP handler used to handle organization operations.
+type Handler struct {
+	*mux.Router
+	DataStore portainer.DataStore
+}
+
+// NewHandler creates a handler to manage organization operations.
+func NewHandler(bouncer *security.RequestBouncer) *Handler {
+	h := &Handler{
+		Router: mux.NewRouter(),
+	}
+	h.Handle("/organizations",
+		bouncer.InstanceAdminAccess(httperror.LoggerHandler(h.organizationCreate))).Methods(http.MethodPost)
+	h.Handle("/organizations",
+		bouncer.InstanceAdminAccess(httperror.LoggerHandler(h.organizationList))).Methods(http.MethodGet)
+	h.Handle("/organizations/{id}",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.organizationInspect))).Methods(http.MethodGet)
+	h.Handle("/organizations/{id}",
+		bouncer.InstanceAdminAccess(httperror.LoggerHandler(h.organizationUpdate))).Methods(http.MethodPut)
+	h.Handle("/organizations/{id}",
+		bouncer.InstanceAdminAccess(httperror.LoggerHandler(h.organizationDelete))).Methods(http.MethodDelete)
+	h.Handle("/organizations/{id}/quotas",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.organizationQuotasInspect))).Methods(http.MethodGet)
+	h.Handle("/organizations/{id}/resources",
+		bouncer.InstanceAdminAccess(httperror.LoggerHandler(h.organizationResourcesAssign))).Methods(http.MethodPost)
+	h.Handle("/organizations/{id}/resources/release",
+		bouncer.InstanceAdminAccess(httperror.LoggerHandler(h.organizationResourcesRelease))).Methods(http.MethodPost)
+	return h
+}
+
+func validateSettings(settings *portainer.OrganizationSettings) error {
+	if settings.EdgeAgentCheckinInterval < 0 {
+		return errors.New("Invalid Edge agent check-in interval. Value must be positive")
+	}
+	if settings.DefaultResourceOwnershipPolicy != 0 && !authorization.ValidResourceOwnershipPolicy(settings.DefaultResourceOwnershipPolicy) {
+		return errors.New("Invalid default resource ownership policy value. Value must be one of: 1 (private), 2 (team) or 3 (public)")
+	}
+	return nil
+}
+
+func validateQuotas(quotas *portainer.OrganizationQuotas) error {
+	if quotas.MaxEndpoints < 0 || quotas.MaxEdgeDevices < 0 || quotas.MaxUsers < 0 || quotas.MaxStacks < 0 {
+		return errors.New("Invalid quota. Value must be positive, 0 for an unlimited quota")
+	}
+	return nil
+}
+
+func (handler *Handler) organizationNameTaken(name string, organizationID portainer.OrganizationID) (bool, error) {
+	organizations, err := handler.DataStore.Organization().Organizations()
+	if err != nil {
+		return false, err
+	}
+
+	for _, organization := range organizations {
+		if organization.Name == name && organization.ID != organizationID {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,61 @@
+package organizations
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/asaskevich/govalidator"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+)
+
+type organizationCreatePayload struct {
+	Name        string
+	Description string
+	Settings    portainer.OrganizationSettings
+	Quotas      portainer.OrganizationQuotas
+}
+
+func (payload *organizationCreatePayload) Validate(r *http.Request) error {
+	if govalidator.IsNull(payload.Name) {
+		return errors.New("Invalid organization name")
+	}
+	err := validateSettings(&payload.Settings)
+	if err != nil {
+		return err
+	}
+	return validateQuotas(&payload.Quotas)
+}
+
+// POST request on /api/organizations
+func (handler *Handler) organizationCreate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	var payload organizationCreatePayload
+	err := request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	nameTaken, err := handler.organizationNameTaken(payload.Name, 0)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve organizations from the database", err}
+	}
+	if nameTaken {
+		return &httperror.HandlerError{http.StatusConflict, "An organization with the same name already exists", errOrganizationAlreadyExists}
+	}
+
+	organization := &portainer.Organization{
+		Name:        payload.Name,
+		Description: payload.Description,
+		Settings:    payload.Settings,
+		Quotas:      payload.Quotas,
+	}
+
+	err = handler.DataStore.Organization().CreateOrganization(organization)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist the organization inside the database", err}
+	}
+
+	return response.JSON(w, organization)
+}
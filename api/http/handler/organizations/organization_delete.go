@@ -0,0 +1,96 @@
+package organizations
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+)
+
+// DELETE request on /api/organizations/:id
+// An organization can only be removed once all its resources are removed or released.
+func (handler *Handler) organizationDelete(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	organizationID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid organization identifier route variable", err}
+	}
+
+	organization, err := handler.DataStore.Organization().Organization(portainer.OrganizationID(organizationID))
+	if err == bolterrors.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find an organization with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find an organization with the specified identifier inside the database", err}
+	}
+
+	empty, err := handler.organizationEmpty(organization.ID)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve the resources of the organization from the database", err}
+	}
+	if !empty {
+		return &httperror.HandlerError{http.StatusConflict, "The organization still has users, teams, endpoints, registries or custom templates", errOrganizationNotEmpty}
+	}
+
+	err = handler.DataStore.Organization().DeleteOrganization(organization.ID)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to remove the organization from the database", err}
+	}
+
+	return response.Empty(w)
+}
+
+func (handler *Handler) organizationEmpty(organizationID portainer.OrganizationID) (bool, error) {
+	users, err := handler.DataStore.User().Users()
+	if err != nil {
+		return false, err
+	}
+	for _, user := range users {
+		if user.OrganizationID == organizationID {
+			return false, nil
+		}
+	}
+
+	teams, err := handler.DataStore.Team().Teams()
+	if err != nil {
+		return false, err
+	}
+	for _, team := range teams {
+		if team.OrganizationID == organizationID {
+			return false, nil
+		}
+	}
+
+	endpoints, err := handler.DataStore.Endpoint().Endpoints()
+	if err != nil {
+		return false, err
+	}
+	for _, endpoint := range endpoints {
+		if endpoint.OrganizationID == organizationID {
+			return false, nil
+		}
+	}
+
+	registries, err := handler.DataStore.Registry().Registries()
+	if err != nil {
+		return false, err
+	}
+	for _, registry := range registries {
+		if registry.OrganizationID == organizationID {
+			return false, nil
+		}
+	}
+
+	customTemplates, err := handler.DataStore.CustomTemplate().CustomTemplates()
+	if err != nil {
+		return false, err
+	}
+	for _, customTemplate := range customTemplates {
+		if customTemplate.OrganizationID == organizationID {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
@@ -0,0 +1,18 @@
+package organizations
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/response"
+)
+
+// GET request on /api/organizations
+func (handler *Handler) organizationList(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	organizations, err := handler.DataStore.Organization().Organizations()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve organizations from the database", err}
+	}
+
+	return response.JSON(w, organizations)
+}
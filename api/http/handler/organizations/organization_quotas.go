@@ -0,0 +1,51 @@
+package organizations
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+	httperrors "github.com/portainer/portainer/api/http/errors"
+	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/organization"
+)
+
+type organizationQuotasResponse struct {
+	Quotas portainer.OrganizationQuotas     `json:"Quotas"`
+	Usage  portainer.OrganizationQuotaUsage `json:"Usage"`
+}
+
+// GET request on /api/organizations/:id/quotas
+// Returns the quotas of the organization along with the number of resources counted against them.
+func (handler *Handler) organizationQuotasInspect(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	organizationID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid organization identifier route variable", err}
+	}
+
+	securityContext, err := security.RetrieveRestrictedRequestContext(r)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve info from request context", err}
+	}
+
+	if !security.AuthorizedOrganizationAccess(portainer.OrganizationID(organizationID), securityContext.OrganizationID, securityContext.IsAdmin) {
+		return &httperror.HandlerError{http.StatusForbidden, "Permission denied to access organization", httperrors.ErrResourceAccessDenied}
+	}
+
+	tenant, err := handler.DataStore.Organization().Organization(portainer.OrganizationID(organizationID))
+	if err == bolterrors.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find an organization with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find an organization with the specified identifier inside the database", err}
+	}
+
+	usage, err := organization.Usage(handler.DataStore, tenant.ID)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to compute the resource usage of the organization", err}
+	}
+
+	return response.JSON(w, organizationQuotasResponse{Quotas: tenant.Quotas, Usage: *usage})
+}
@@ -0,0 +1,194 @@
+package organizations
+
+import (
+	"errors"
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+type organizationResourcesPayload struct {
+	UserIDs           []portainer.UserID
+	TeamIDs           []portainer.TeamID
+	EndpointIDs       []portainer.EndpointID
+	RegistryIDs       []portainer.RegistryID
+	CustomTemplateIDs []portainer.CustomTemplateID
+}
+
+func (payload *organizationResourcesPayload) Validate(r *http.Request) error {
+	if len(payload.UserIDs)+len(payload.TeamIDs)+len(payload.EndpointIDs)+len(payload.RegistryIDs)+len(payload.CustomTemplateIDs) == 0 {
+		return errors.New("At least one resource identifier is required")
+	}
+	return nil
+}
+
+// organizationChange returns the organization of a resource after the change, and false when the resource is not changed
+type organizationChange func(current portainer.OrganizationID) (portainer.OrganizationID, bool)
+
+// POST request on /api/organizations/:id/resources
+//
+// Moves users, teams, endpoints, registries and custom templates to the organization. The resources are
+// taken from their current organization, the administrators moved to an organization become administrators
+// of the organization.
+func (handler *Handler) organizationResourcesAssign(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	organization, payload, handlerErr := handler.retrieveResourcesRequest(r)
+	if handlerErr != nil {
+		return handlerErr
+	}
+
+	securityContext, err := security.RetrieveRestrictedRequestContext(r)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve info from request context", err}
+	}
+
+	for _, userID := range payload.UserIDs {
+		if userID == securityContext.UserID {
+			return &httperror.HandlerError{http.StatusBadRequest, "Administrators of the instance cannot move themselves to an organization", errors.New("Cannot move the current user to an organization")}
+		}
+	}
+
+	return handler.changeResources(w, payload, func(current portainer.OrganizationID) (portainer.OrganizationID, bool) {
+		return organization.ID, current != organization.ID
+	})
+}
+
+// POST request on /api/organizations/:id/resources/release
+//
+// Releases resources of the organization, they no longer belong to any organization. The administrators
+// of the organization must be demoted before being released, they would become administrators of the instance.
+func (handler *Handler) organizationResourcesRelease(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	organization, payload, handlerErr := handler.retrieveResourcesRequest(r)
+	if handlerErr != nil {
+		return handlerErr
+	}
+
+	for _, userID := range payload.UserIDs {
+		user, err := handler.DataStore.User().User(userID)
+		if err == bolterrors.ErrObjectNotFound {
+			return &httperror.HandlerError{http.StatusNotFound, "Unable to find a user with the specified identifier inside the database", err}
+		} else if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a user with the specified identifier inside the database", err}
+		}
+
+		if user.Role == portainer.AdministratorRole && user.OrganizationID == organization.ID {
+			return &httperror.HandlerError{http.StatusBadRequest, "The administrators of the organization must be demoted before being released", errors.New("Cannot release an administrator of the organization")}
+		}
+	}
+
+	return handler.changeResources(w, payload, func(current portainer.OrganizationID) (portainer.OrganizationID, bool) {
+		return 0, current == organization.ID
+	})
+}
+
+func (handler *Handler) retrieveResourcesRequest(r *http.Request) (*portainer.Organization, *organizationResourcesPayload, *httperror.HandlerError) {
+	organizationID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return nil, nil, &httperror.HandlerError{http.StatusBadRequest, "Invalid organization identifier route variable", err}
+	}
+
+	var payload organizationResourcesPayload
+	err = request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return nil, nil, &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	organization, err := handler.DataStore.Organization().Organization(portainer.OrganizationID(organizationID))
+	if err == bolterrors.ErrObjectNotFound {
+		return nil, nil, &httperror.HandlerError{http.StatusNotFound, "Unable to find an organization with the specified identifier inside the database", err}
+	} else if err != nil {
+		return nil, nil, &httperror.HandlerError{http.StatusInternalServerError, "Unable to find an organization with the specified identifier inside the database", err}
+	}
+
+	return organization, &payload, nil
+}
+
+func (handler *Handler) changeResources(w http.ResponseWriter, payload *organizationResourcesPayload, change organizationChange) *httperror.HandlerError {
+	for _, userID := range payload.UserIDs {
+		user, err := handler.DataStore.User().User(userID)
+		if err != nil {
+			return resourceError("user", err)
+		}
+
+		if organizationID, changed := change(user.OrganizationID); changed {
+			user.OrganizationID = organizationID
+			err = handler.DataStore.User().UpdateUser(user.ID, user)
+			if err != nil {
+				return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist user changes inside the database", err}
+			}
+		}
+	}
+
+	for _, teamID := range payload.TeamIDs {
+		team, err := handler.DataStore.Team().Team(teamID)
+		if err != nil {
+			return resourceError("team", err)
+		}
+
+		if organizationID, changed := change(team.OrganizationID); changed {
+			team.OrganizationID = organizationID
+			err = handler.DataStore.Team().UpdateTeam(team.ID, team)
+			if err != nil {
+				return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist team changes inside the database", err}
+			}
+		}
+	}
+
+	for _, endpointID := range payload.EndpointIDs {
+		endpoint, err := handler.DataStore.Endpoint().Endpoint(endpointID)
+		if err != nil {
+			return resourceError("endpoint", err)
+		}
+
+		if organizationID, changed := change(endpoint.OrganizationID); changed {
+			endpoint.OrganizationID = organizationID
+			err = handler.DataStore.Endpoint().UpdateEndpoint(endpoint.ID, endpoint)
+			if err != nil {
+				return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist endpoint changes inside the database", err}
+			}
+		}
+	}
+
+	for _, registryID := range payload.RegistryIDs {
+		registry, err := handler.DataStore.Registry().Registry(registryID)
+		if err != nil {
+			return resourceError("registry", err)
+		}
+
+		if organizationID, changed := change(registry.OrganizationID); changed {
+			registry.OrganizationID = organizationID
+			err = handler.DataStore.Registry().UpdateRegistry(registry.ID, registry)
+			if err != nil {
+				return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist registry changes inside the database", err}
+			}
+		}
+	}
+
+	for _, templateID := range payload.CustomTemplateIDs {
+		customTemplate, err := handler.DataStore.CustomTemplate().CustomTemplate(templateID)
+		if err != nil {
+			return resourceError("custom template", err)
+		}
+
+		if organizationID, changed := change(customTemplate.OrganizationID); changed {
+			customTemplate.OrganizationID = organizationID
+			err = handler.DataStore.CustomTemplate().UpdateCustomTemplate(customTemplate.ID, customTemplate)
+			if err != nil {
+				return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist custom template changes inside the database", err}
+			}
+		}
+	}
+
+	return response.Empty(w)
+}
+
+func resourceError(resourceName string, err error) *httperror.HandlerError {
+	if err == bolterrors.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find a " + resourceName + " with the specified identifier inside the database", err}
+	}
+	return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a " + resourceName + " with the specified identifier inside the database", err}
+}
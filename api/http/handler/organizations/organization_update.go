@@ -0,0 +1,87 @@
+package organizations
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/asaskevich/govalidator"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+)
+
+type organizationUpdatePayload struct {
+	Name        *string
+	Description *string
+	Settings    *portainer.OrganizationSettings
+	Quotas      *portainer.OrganizationQuotas
+}
+
+func (payload *organizationUpdatePayload) Validate(r *http.Request) error {
+	if payload.Name != nil && govalidator.IsNull(*payload.Name) {
+		return errors.New("Invalid organization name")
+	}
+	if payload.Settings != nil {
+		err := validateSettings(payload.Settings)
+		if err != nil {
+			return err
+		}
+	}
+	if payload.Quotas != nil {
+		return validateQuotas(payload.Quotas)
+	}
+	return nil
+}
+
+// PUT request on /api/organizations/:id
+func (handler *Handler) organizationUpdate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	organizationID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid organization identifier route variable", err}
+	}
+
+	var payload organizationUpdatePayload
+	err = request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	organization, err := handler.DataStore.Organization().Organization(portainer.OrganizationID(organizationID))
+	if err == bolterrors.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find an organization with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find an organization with the specified identifier inside the database", err}
+	}
+
+	if payload.Name != nil {
+		nameTaken, err := handler.organizationNameTaken(*payload.Name, organization.ID)
+		if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve organizations from the database", err}
+		}
+		if nameTaken {
+			return &httperror.HandlerError{http.StatusConflict, "An organization with the same name already exists", errOrganizationAlreadyExists}
+		}
+		organization.Name = *payload.Name
+	}
+
+	if payload.Description != nil {
+		organization.Description = *payload.Description
+	}
+
+	if payload.Settings != nil {
+		organization.Settings = *payload.Settings
+	}
+
+	if payload.Quotas != nil {
+		organization.Quotas = *payload.Quotas
+	}
+
+	err = handler.DataStore.Organization().UpdateOrganization(organization.ID, organization)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist organization changes inside the database", err}
+	}
+
+	return response.JSON(w, organization)
+}
@@ -0,0 +1,26 @@
+package permissions
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+// Handler is the HTTP handler used to evaluate the permissions of the users.
+type Handler struct {
+	*mux.Router
+	DataStore portainer.DataStore
+}
+
+// NewHandler creates a handler to evaluate the permissions of the users.
+func NewHandler(bouncer *security.RequestBouncer) *Handler {
+	h := &Handler{
+		Router: mux.NewRouter(),
+	}
+	h.Handle("/permissions/check",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.permissionCheck))).Methods(http.MethodGet)
+	return h
+}
@@ -0,0 +1,412 @@
+package permissions
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+	httperrors "github.com/portainer/portainer/api/http/errors"
+	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/authorization"
+)
+
+const (
+	stepOrganization  = "organization"
+	stepEndpoint      = "endpoint"
+	stepAuthorization = "authorization"
+	stepResource      = "resource"
+)
+
+var (
+	errInvalidResource = errors.New("Invalid resource. Value must be one of: endpoint:<id>, stack:<id>, registry:<id>, template:<id> or <container|service|volume|network|secret|config>:<endpoint id>:<id>")
+
+	dockerResourceTypes = map[string]portainer.ResourceControlType{
+		"container": portainer.ContainerResourceControl,
+		"service":   portainer.ServiceResourceControl,
+		"volume":    portainer.VolumeResourceControl,
+		"network":   portainer.NetworkResourceControl,
+		"secret":    portainer.SecretResourceControl,
+		"config":    portainer.ConfigResourceControl,
+	}
+)
+
+type (
+	permissionCheckResponse struct {
+		Allowed bool `json:"Allowed"`
+		// DecidingRule is the rule granting the access when it is allowed, the step denying it otherwise
+		DecidingRule string                `json:"DecidingRule"`
+		Reason       string                `json:"Reason"`
+		Chain        []permissionCheckStep `json:"Chain"`
+	}
+
+	// permissionCheckStep represents a step of the authorization chain, the steps are evaluated in order
+	// and the evaluation stops at the first denied step
+	permissionCheckStep struct {
+		Step    string                     `json:"Step"`
+		Allowed bool                       `json:"Allowed"`
+		Grant   *authorization.AccessGrant `json:"Grant,omitempty"`
+		Reason  string                     `json:"Reason"`
+	}
+
+	// permissionCheck holds the state of the evaluation of a permission
+	permissionCheck struct {
+		user     *portainer.User
+		subject  *authorization.AccessSubject
+		action   portainer.Authorization
+		response *permissionCheckResponse
+	}
+)
+
+// GET request on /api/permissions/check?user=<id|username>&action=<authorization>&resource=<resource>
+//
+// Evaluates the authorization chain of a user for an action on a resource: the organization of the resource,
+// the access to its endpoint, the role granting the action and the access control of the resource. The response
+// tells whether the action is allowed along with the deciding rule and the evaluated steps.
+func (handler *Handler) permissionCheck(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	userParameter, err := request.RetrieveQueryParameter(r, "user", false)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid query parameter: user", err}
+	}
+
+	action, err := request.RetrieveQueryParameter(r, "action", false)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid query parameter: action", err}
+	}
+
+	resource, err := request.RetrieveQueryParameter(r, "resource", false)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid query parameter: resource", err}
+	}
+
+	resourceType, resourceParts := parseResource(resource)
+	if resourceType == "" {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid query parameter: resource", errInvalidResource}
+	}
+
+	securityContext, err := security.RetrieveRestrictedRequestContext(r)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve info from request context", err}
+	}
+
+	check, handlerErr := handler.newPermissionCheck(userParameter, portainer.Authorization(action))
+	if handlerErr != nil {
+		return handlerErr
+	}
+
+	if !security.AuthorizedOrganizationAccess(check.user.OrganizationID, securityContext.OrganizationID, securityContext.IsAdmin) {
+		return &httperror.HandlerError{http.StatusForbidden, "Permission denied to check the permissions of a user of another organization", httperrors.ErrResourceAccessDenied}
+	}
+
+	switch resourceType {
+	case "endpoint":
+		handlerErr = handler.checkEndpoint(check, resourceParts[0])
+	case "stack":
+		handlerErr = handler.checkStack(check, resourceParts[0])
+	case "registry":
+		handlerErr = handler.checkRegistry(check, resourceParts[0])
+	case "template":
+		handlerErr = handler.checkCustomTemplate(check, resourceParts[0])
+	default:
+		handlerErr = handler.checkDockerResource(check, dockerResourceTypes[resourceType], resourceParts[0], resourceParts[1])
+	}
+	if handlerErr != nil {
+		return handlerErr
+	}
+
+	return response.JSON(w, check.result())
+}
+
+// parseResource returns the type of a resource and its identifiers, the type is empty when the resource is invalid
+func parseResource(resource string) (string, []string) {
+	parts := strings.SplitN(resource, ":", 3)
+	if len(parts) < 2 {
+		return "", nil
+	}
+
+	_, dockerResource := dockerResourceTypes[parts[0]]
+	switch {
+	case dockerResource && len(parts) == 3 && parts[2] != "":
+		return parts[0], parts[1:]
+	case !dockerResource && len(parts) == 2 && (parts[0] == "endpoint" || parts[0] == "stack" || parts[0] == "registry" || parts[0] == "template"):
+		return parts[0], parts[1:]
+	}
+	return "", nil
+}
+
+func (handler *Handler) newPermissionCheck(userParameter string, action portainer.Authorization) (*permissionCheck, *httperror.HandlerError) {
+	var user *portainer.User
+	var err error
+
+	userID, convErr := strconv.Atoi(userParameter)
+	if convErr == nil {
+		user, err = handler.DataStore.User().User(portainer.UserID(userID))
+	} else {
+		user, err = handler.DataStore.User().UserByUsername(userParameter)
+	}
+	if err == bolterrors.ErrObjectNotFound {
+		return nil, &httperror.HandlerError{http.StatusNotFound, "Unable to find a user with the specified identifier inside the database", err}
+	} else if err != nil {
+		return nil, &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a user with the specified identifier inside the database", err}
+	}
+
+	memberships, err := handler.DataStore.TeamMembership().TeamMembershipsByUserID(user.ID)
+	if err != nil {
+		return nil, &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve the team memberships of the user from the database", err}
+	}
+
+	subject := &authorization.AccessSubject{UserID: user.ID, IsAdmin: user.Role == portainer.AdministratorRole}
+	for _, membership := range memberships {
+		subject.TeamIDs = append(subject.TeamIDs, membership.TeamID)
+	}
+
+	return &permissionCheck{
+		user:     user,
+		subject:  subject,
+		action:   action,
+		response: &permissionCheckResponse{Allowed: true, Chain: []permissionCheckStep{}},
+	}, nil
+}
+
+func (handler *Handler) checkEndpoint(check *permissionCheck, endpointIdentifier string) *httperror.HandlerError {
+	_, handlerErr := handler.checkEndpointAccess(check, endpointIdentifier)
+	return handlerErr
+}
+
+func (handler *Handler) checkStack(check *permissionCheck, stackIdentifier string) *httperror.HandlerError {
+	stackID, err := strconv.Atoi(stackIdentifier)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid query parameter: resource", errInvalidResource}
+	}
+
+	stack, err := handler.DataStore.Stack().Stack(portainer.StackID(stackID))
+	if err == bolterrors.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find a stack with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a stack with the specified identifier inside the database", err}
+	}
+
+	allowed, handlerErr := handler.checkEndpointAccess(check, strconv.Itoa(int(stack.EndpointID)))
+	if handlerErr != nil || !allowed {
+		return handlerErr
+	}
+
+	resourceControl, err := handler.DataStore.ResourceControl().ResourceControlByResourceIDAndType(stack.Name, portainer.StackResourceControl)
+	if err != nil && err != bolterrors.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve the resource control of the stack from the database", err}
+	}
+
+	check.addResourceControlStep(resourceControl)
+	return nil
+}
+
+func (handler *Handler) checkDockerResource(check *permissionCheck, resourceType portainer.ResourceControlType, endpointIdentifier, resourceID string) *httperror.HandlerError {
+	allowed, handlerErr := handler.checkEndpointAccess(check, endpointIdentifier)
+	if handlerErr != nil || !allowed {
+		return handlerErr
+	}
+
+	resourceControls, err := handler.DataStore.ResourceControl().ResourceControls()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve resource controls from the database", err}
+	}
+
+	resourceControl := authorization.NewResourceControlIndex(resourceControls).Get(resourceID, resourceType)
+	check.addResourceControlStep(resourceControl)
+	return nil
+}
+
+func (handler *Handler) checkRegistry(check *permissionCheck, registryIdentifier string) *httperror.HandlerError {
+	registryID, err := strconv.Atoi(registryIdentifier)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid query parameter: resource", errInvalidResource}
+	}
+
+	registry, err := handler.DataStore.Registry().Registry(portainer.RegistryID(registryID))
+	if err == bolterrors.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find a registry with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a registry with the specified identifier inside the database", err}
+	}
+
+	if !check.addOrganizationStep(registry.OrganizationID) || !check.addPortainerAuthorizationStep() {
+		return nil
+	}
+
+	grant := authorization.RegistryAccessGrant(check.subject, registry)
+	check.addStep(stepResource, grant, "The registry is not shared with the user or its teams")
+	return nil
+}
+
+func (handler *Handler) checkCustomTemplate(check *permissionCheck, templateIdentifier string) *httperror.HandlerError {
+	templateID, err := strconv.Atoi(templateIdentifier)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid query parameter: resource", errInvalidResource}
+	}
+
+	customTemplate, err := handler.DataStore.CustomTemplate().CustomTemplate(portainer.CustomTemplateID(templateID))
+	if err == bolterrors.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find a custom template with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a custom template with the specified identifier inside the database", err}
+	}
+
+	if !check.addOrganizationStep(customTemplate.OrganizationID) || !check.addPortainerAuthorizationStep() {
+		return nil
+	}
+
+	resourceControl, err := handler.DataStore.ResourceControl().ResourceControlByResourceIDAndType(strconv.Itoa(int(customTemplate.ID)), portainer.CustomTemplateResourceControl)
+	if err != nil && err != bolterrors.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve the resource control of the custom template from the database", err}
+	}
+	customTemplate.ResourceControl = resourceControl
+
+	grant := authorization.CustomTemplateAccessGrant(check.subject, customTemplate)
+	check.addStep(stepResource, grant, "The custom template is not shared with the user or its teams and is not published")
+	return nil
+}
+
+// checkEndpointAccess evaluates the organization of the endpoint, the access of the user to the endpoint
+// and the role granting the action on the endpoint. It returns false when one of these steps is denied.
+func (handler *Handler) checkEndpointAccess(check *permissionCheck, endpointIdentifier string) (bool, *httperror.HandlerError) {
+	endpointID, err := strconv.Atoi(endpointIdentifier)
+	if err != nil {
+		return false, &httperror.HandlerError{http.StatusBadRequest, "Invalid query parameter: resource", errInvalidResource}
+	}
+
+	endpoint, err := handler.DataStore.Endpoint().Endpoint(portainer.EndpointID(endpointID))
+	if err == bolterrors.ErrObjectNotFound {
+		return false, &httperror.HandlerError{http.StatusNotFound, "Unable to find an endpoint with the specified identifier inside the database", err}
+	} else if err != nil {
+		return false, &httperror.HandlerError{http.StatusInternalServerError, "Unable to find an endpoint with the specified identifier inside the database", err}
+	}
+
+	if !check.addOrganizationStep(endpoint.OrganizationID) {
+		return false, nil
+	}
+
+	endpointGroup, err := handler.DataStore.EndpointGroup().EndpointGroup(endpoint.GroupID)
+	if err != nil && err != bolterrors.ErrObjectNotFound {
+		return false, &httperror.HandlerError{http.StatusInternalServerError, "Unable to find the endpoint group of the endpoint inside the database", err}
+	}
+
+	grant := authorization.EndpointAccessGrant(check.subject, endpoint, endpointGroup)
+	if !check.addStep(stepEndpoint, grant, fmt.Sprintf("The user and its teams have no access to the endpoint %s or to its group", endpoint.Name)) {
+		return false, nil
+	}
+
+	if grant.Rule == authorization.AccessRuleAdministrator {
+		return true, nil
+	}
+
+	role, err := handler.DataStore.Role().Role(grant.RoleID)
+	if err != nil && err != bolterrors.ErrObjectNotFound {
+		return false, &httperror.HandlerError{http.StatusInternalServerError, "Unable to find the role of the access policy inside the database", err}
+	}
+
+	if role == nil || !role.Authorizations[check.action] {
+		check.deny(stepAuthorization, fmt.Sprintf("The role granted on the endpoint does not include the %s authorization", check.action))
+		return false, nil
+	}
+
+	check.allow(stepAuthorization, grant, fmt.Sprintf("The %s role includes the %s authorization", role.Name, check.action))
+	if role.Authorizations[portainer.EndpointResourcesAccess] {
+		check.allow(stepResource, grant, fmt.Sprintf("The %s role grants access to all the resources of the endpoint", role.Name))
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// addOrganizationStep evaluates the organization of the resource, it returns false when the step is denied
+func (check *permissionCheck) addOrganizationStep(organizationID portainer.OrganizationID) bool {
+	if security.AuthorizedOrganizationAccess(organizationID, check.user.OrganizationID, check.subject.IsAdmin) {
+		check.allow(stepOrganization, nil, "The resource belongs to the organization of the user")
+		return true
+	}
+
+	check.deny(stepOrganization, "The resource belongs to another organization")
+	return false
+}
+
+// addPortainerAuthorizationStep evaluates the authorizations of the user outside of the endpoints
+func (check *permissionCheck) addPortainerAuthorizationStep() bool {
+	if check.subject.IsAdmin {
+		check.allow(stepAuthorization, &authorization.AccessGrant{Rule: authorization.AccessRuleAdministrator}, "The administrators are granted all the authorizations")
+		return true
+	}
+
+	authorizations := check.user.PortainerAuthorizations
+	if authorizations == nil {
+		authorizations = authorization.DefaultPortainerAuthorizations()
+	}
+
+	if !authorizations[check.action] {
+		check.deny(stepAuthorization, fmt.Sprintf("The %s authorization is not granted to the user", check.action))
+		return false
+	}
+
+	check.allow(stepAuthorization, nil, fmt.Sprintf("The %s authorization is granted to the user", check.action))
+	return true
+}
+
+func (check *permissionCheck) addResourceControlStep(resourceControl *portainer.ResourceControl) {
+	reason := "The resource is not shared with the user or its teams"
+	if resourceControl == nil {
+		reason = "The resource has no access control, it is restricted to the administrators"
+	} else if resourceControl.AdministratorsOnly {
+		reason = "The resource is restricted to the administrators"
+	}
+
+	grant := authorization.ResourceControlAccessGrant(check.subject, resourceControl)
+	check.addStep(stepResource, grant, reason)
+}
+
+// addStep allows the step when the access is granted and denies it with the reason otherwise
+func (check *permissionCheck) addStep(step string, grant *authorization.AccessGrant, deniedReason string) bool {
+	if grant == nil {
+		check.deny(step, deniedReason)
+		return false
+	}
+
+	check.allow(step, grant, fmt.Sprintf("Access granted by the %s rule", grant.Rule))
+	return true
+}
+
+func (check *permissionCheck) allow(step string, grant *authorization.AccessGrant, reason string) {
+	check.response.Chain = append(check.response.Chain, permissionCheckStep{Step: step, Allowed: true, Grant: grant, Reason: reason})
+}
+
+func (check *permissionCheck) deny(step string, reason string) {
+	check.response.Allowed = false
+	check.response.Chain = append(check.response.Chain, permissionCheckStep{Step: step, Allowed: false, Reason: reason})
+}
+
+// result returns the response with the deciding rule: the first denied step, or the rule granting the access
+// to the resource itself when all the steps are allowed
+func (check *permissionCheck) result() *permissionCheckResponse {
+	chain := check.response.Chain
+	for idx := len(chain) - 1; idx >= 0; idx-- {
+		step := chain[idx]
+		if !step.Allowed {
+			check.response.DecidingRule = step.Step
+			check.response.Reason = step.Reason
+			return check.response
+		}
+	}
+
+	for idx := len(chain) - 1; idx >= 0; idx-- {
+		if chain[idx].Grant != nil {
+			check.response.DecidingRule = string(chain[idx].Grant.Rule)
+			check.response.Reason = chain[idx].Reason
+			break
+		}
+	}
+	return check.response
+}
@@ -0,0 +1,29 @@
+package permissions
+
+import (
+	"testing"
+)
+
+func TestParseResource(t *testing.T) {
+	tests := []struct {
+		resource     string
+		expectedType string
+		expectedIDs  int
+	}{
+		{"endpoint:1", "endpoint", 1},
+		{"stack:3", "stack", 1},
+		{"container:1:abcdef", "container", 2},
+		{"volume:2:data:backup", "volume", 2},
+		{"container:1", "", 0},
+		{"endpoint:1:2", "", 0},
+		{"image:1:abcdef", "", 0},
+		{"endpoint", "", 0},
+	}
+
+	for _, test := range tests {
+		resourceType, ids := parseResource(test.resource)
+		if resourceType != test.expectedType || len(ids) != test.expectedIDs {
+			t.Errorf("parseResource(%q) = %q, %v; want %q with %d identifiers", test.resource, resourceType, ids, test.expectedType, test.expectedIDs)
+		}
+	}
+}
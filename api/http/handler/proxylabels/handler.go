@@ -0,0 +1,71 @@
+package proxylabels
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/docker/docker/client"
+	"github.com/gorilla/mux"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+	"github.com/portainer/portainer/api/docker"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+var errUnsupportedEndpoint = errors.New("Reverse proxy labels are only supported on Docker endpoints")
+
+// Handler is the HTTP handler used to validate and generate the reverse proxy labels of the containers
+// and the services of an endpoint.
+type Handler struct {
+	*mux.Router
+	requestBouncer      *security.RequestBouncer
+	DataStore           portainer.DataStore
+	DockerClientFactory *docker.ClientFactory
+}
+
+// NewHandler creates a handler to manage reverse proxy labels operations.
+func NewHandler(bouncer *security.RequestBouncer) *Handler {
+	h := &Handler{
+		Router:         mux.NewRouter(),
+		requestBouncer: bouncer,
+	}
+	h.Handle("/endpoints/{id}/proxy_labels/validate",
+		bouncer.RestrictedAccess(httperror.LoggerHandler(h.proxyLabelsValidate))).Methods(http.MethodPost)
+	h.Handle("/endpoints/{id}/proxy_labels/generate",
+		bouncer.RestrictedAccess(httperror.LoggerHandler(h.proxyLabelsGenerate))).Methods(http.MethodPost)
+	return h
+}
+
+// retrieveDockerClient retrieves the endpoint referenced by the request route variables, ensures that
+// the user can access it and creates a Docker client for this endpoint.
+func (handler *Handler) retrieveDockerClient(r *http.Request) (*client.Client, *httperror.HandlerError) {
+	endpointID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return nil, &httperror.HandlerError{http.StatusBadRequest, "Invalid endpoint identifier route variable", err}
+	}
+
+	endpoint, err := handler.DataStore.Endpoint().Endpoint(portainer.EndpointID(endpointID))
+	if err == bolterrors.ErrObjectNotFound {
+		return nil, &httperror.HandlerError{http.StatusNotFound, "Unable to find an endpoint with the specified identifier inside the database", err}
+	} else if err != nil {
+		return nil, &httperror.HandlerError{http.StatusInternalServerError, "Unable to find an endpoint with the specified identifier inside the database", err}
+	}
+
+	err = handler.requestBouncer.AuthorizedEndpointOperation(r, endpoint)
+	if err != nil {
+		return nil, &httperror.HandlerError{http.StatusForbidden, "Permission denied to access endpoint", err}
+	}
+
+	if endpoint.Type != portainer.DockerEnvironment && endpoint.Type != portainer.AgentOnDockerEnvironment && endpoint.Type != portainer.EdgeAgentOnDockerEnvironment {
+		return nil, &httperror.HandlerError{http.StatusBadRequest, "Reverse proxy labels are only supported on Docker endpoints", errUnsupportedEndpoint}
+	}
+
+	cli, err := handler.DockerClientFactory.CreateClient(endpoint, "")
+	if err != nil {
+		return nil, &httperror.HandlerError{http.StatusInternalServerError, "Unable to create Docker client", err}
+	}
+
+	return cli, nil
+}
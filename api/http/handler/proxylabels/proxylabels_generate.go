@@ -0,0 +1,72 @@
+package proxylabels
+
+import (
+	"errors"
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api/internal/proxylabels"
+)
+
+type proxyLabelsGeneratePayload struct {
+	proxylabels.RouteDefinition
+	Swarm        bool
+	ResourceName string
+	StackName    string
+}
+
+type proxyLabelsGenerateResponse struct {
+	Labels map[string]string   `json:"Labels"`
+	Env    []string            `json:"Env"`
+	Issues []proxylabels.Issue `json:"Issues"`
+}
+
+func (payload *proxyLabelsGeneratePayload) Validate(r *http.Request) error {
+	if payload.Provider != proxylabels.TraefikProvider && payload.Provider != proxylabels.NginxProxyProvider {
+		return errors.New("Invalid reverse proxy. Value must be one of: traefik or nginx-proxy")
+	}
+	return nil
+}
+
+// POST request on /api/endpoints/:id/proxy_labels/generate
+//
+// Generates the Traefik labels or the nginx-proxy environment variables routing one or several host names
+// to a container or a service, and reports the routes already used by the other resources of the endpoint.
+func (handler *Handler) proxyLabelsGenerate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	var payload proxyLabelsGeneratePayload
+	err := request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	cli, handlerErr := handler.retrieveDockerClient(r)
+	if handlerErr != nil {
+		return handlerErr
+	}
+	defer cli.Close()
+
+	labels, env, err := proxylabels.Generate(&payload.RouteDefinition)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid route definition", err}
+	}
+
+	envMap := proxylabels.EnvMap(env)
+	issues := proxylabels.Validate(labels, envMap, payload.Swarm)
+
+	resource := proxylabels.NewResource(payload.ResourceName, payload.StackName, labels, envMap)
+
+	existing, err := proxylabels.EndpointResources(cli)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve the containers and the services of the endpoint", err}
+	}
+
+	issues = append(issues, proxylabels.Conflicts(&resource, existing)...)
+
+	return response.JSON(w, &proxyLabelsGenerateResponse{
+		Labels: labels,
+		Env:    env,
+		Issues: issues,
+	})
+}
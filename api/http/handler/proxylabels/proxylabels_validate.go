@@ -0,0 +1,74 @@
+package proxylabels
+
+import (
+	"errors"
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api/internal/proxylabels"
+)
+
+type proxyLabelsValidatePayload struct {
+	Labels map[string]string
+	// Env is the list of the environment variables of the container, in the KEY=value format
+	Env []string
+	// Swarm must be set when the labels are the labels of a Swarm service
+	Swarm bool
+	// ResourceName is the name of the container or the service, the existing resource with the same name
+	// is replaced and is not reported as a conflict
+	ResourceName string
+	// StackName is the name of the stack the container or the service belongs to, the resources of the
+	// same stack are not reported as conflicts
+	StackName string
+}
+
+type proxyLabelsValidateResponse struct {
+	Valid  bool                `json:"Valid"`
+	Issues []proxylabels.Issue `json:"Issues"`
+	Routes []proxylabels.Route `json:"Routes"`
+}
+
+func (payload *proxyLabelsValidatePayload) Validate(r *http.Request) error {
+	if len(payload.Labels) == 0 && len(payload.Env) == 0 {
+		return errors.New("Invalid payload: labels or environment variables must be specified")
+	}
+	return nil
+}
+
+// POST request on /api/endpoints/:id/proxy_labels/validate
+//
+// Validates the Traefik labels and the nginx-proxy environment variables of a container or a service
+// and reports the routes already used by the other containers and services of the endpoint.
+func (handler *Handler) proxyLabelsValidate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	var payload proxyLabelsValidatePayload
+	err := request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	cli, handlerErr := handler.retrieveDockerClient(r)
+	if handlerErr != nil {
+		return handlerErr
+	}
+	defer cli.Close()
+
+	env := proxylabels.EnvMap(payload.Env)
+	issues := proxylabels.Validate(payload.Labels, env, payload.Swarm)
+
+	resource := proxylabels.NewResource(payload.ResourceName, payload.StackName, payload.Labels, env)
+
+	existing, err := proxylabels.EndpointResources(cli)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve the containers and the services of the endpoint", err}
+	}
+
+	issues = append(issues, proxylabels.Conflicts(&resource, existing)...)
+
+	return response.JSON(w, &proxyLabelsValidateResponse{
+		Valid:  !proxylabels.HasErrors(issues),
+		Issues: issues,
+		Routes: resource.Routes,
+	})
+}
@@ -0,0 +1,37 @@
+package reports
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+// Handler is the HTTP handler used to manage the periodic reports of the administrators.
+type Handler struct {
+	*mux.Router
+	requestBouncer *security.RequestBouncer
+	DataStore      portainer.DataStore
+	ReportService  portainer.ReportService
+}
+
+// NewHandler creates a handler to manage the periodic reports of the administrators.
+func NewHandler(bouncer *security.RequestBouncer) *Handler {
+	h := &Handler{
+		Router:         mux.NewRouter(),
+		requestBouncer: bouncer,
+	}
+	h.Handle("/reports/subscription",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.reportSubscriptionInspect))).Methods(http.MethodGet)
+	h.Handle("/reports/subscription",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.reportSubscriptionUpdate))).Methods(http.MethodPut)
+	h.Handle("/reports/preview",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.reportPreview))).Methods(http.MethodGet)
+	h.Handle("/reports/access",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.reportAccess))).Methods(http.MethodGet)
+	h.Handle("/reports/send",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.reportSend))).Methods(http.MethodPost)
+	return h
+}
@@ -0,0 +1,327 @@
+package reports
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+	httperrors "github.com/portainer/portainer/api/http/errors"
+	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/authorization"
+)
+
+const (
+	accessResourceEndpoint       = "endpoint"
+	accessResourceStack          = "stack"
+	accessResourceVolume         = "volume"
+	accessResourceRegistry       = "registry"
+	accessResourceCustomTemplate = "template"
+)
+
+type (
+	accessReview struct {
+		UserID         portainer.UserID `json:"UserId,omitempty"`
+		TeamID         portainer.TeamID `json:"TeamId,omitempty"`
+		Name           string           `json:"Name"`
+		GenerationDate int64            `json:"GenerationDate"`
+		Entries        []accessEntry    `json:"Entries"`
+	}
+
+	// accessEntry represents a resource the user or the team can access, along with the rule granting the access
+	accessEntry struct {
+		ResourceType      string               `json:"ResourceType"`
+		ResourceID        string               `json:"ResourceId"`
+		ResourceName      string               `json:"ResourceName"`
+		EndpointID        portainer.EndpointID `json:"EndpointId,omitempty"`
+		TeamName          string               `json:"TeamName,omitempty"`
+		EndpointGroupName string               `json:"EndpointGroupName,omitempty"`
+		authorization.AccessGrant
+	}
+
+	accessReviewData struct {
+		subject        *authorization.AccessSubject
+		organizationID portainer.OrganizationID
+		teamNames      map[portainer.TeamID]string
+		endpointGroups map[portainer.EndpointGroupID]portainer.EndpointGroup
+	}
+)
+
+// GET request on /api/reports/access?userId=<id>&teamId=<id>&format=<json|csv>
+//
+// Lists every endpoint, stack, volume, registry and custom template a user or a team can access, and the rule
+// granting the access: administrator, direct, team, group inheritance or public. The report is meant for the
+// periodic access reviews, it can be exported as CSV.
+func (handler *Handler) reportAccess(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	userID, _ := request.RetrieveNumericQueryParameter(r, "userId", true)
+	teamID, _ := request.RetrieveNumericQueryParameter(r, "teamId", true)
+	if (userID == 0) == (teamID == 0) {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid query parameters", errors.New("Exactly one of userId or teamId must be specified")}
+	}
+
+	format, _ := request.RetrieveQueryParameter(r, "format", true)
+	if format != "" && format != "json" && format != "csv" {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid query parameter: format", errors.New("Value must be one of: json or csv")}
+	}
+
+	securityContext, err := security.RetrieveRestrictedRequestContext(r)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve info from request context", err}
+	}
+
+	review := &accessReview{
+		UserID:         portainer.UserID(userID),
+		TeamID:         portainer.TeamID(teamID),
+		GenerationDate: time.Now().Unix(),
+		Entries:        []accessEntry{},
+	}
+
+	data, handlerErr := handler.accessReviewSubject(review)
+	if handlerErr != nil {
+		return handlerErr
+	}
+
+	if !security.AuthorizedOrganizationAccess(data.organizationID, securityContext.OrganizationID, securityContext.IsAdmin) {
+		return &httperror.HandlerError{http.StatusForbidden, "Permission denied to review the accesses of another organization", httperrors.ErrResourceAccessDenied}
+	}
+
+	err = handler.collectAccessEntries(review, data)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve the resources from the database", err}
+	}
+
+	sort.SliceStable(review.Entries, func(i, j int) bool {
+		return review.Entries[i].ResourceType < review.Entries[j].ResourceType
+	})
+
+	if format == "csv" {
+		return writeAccessReviewCSV(w, review)
+	}
+
+	return response.JSON(w, review)
+}
+
+func (handler *Handler) accessReviewSubject(review *accessReview) (*accessReviewData, *httperror.HandlerError) {
+	teams, err := handler.DataStore.Team().Teams()
+	if err != nil {
+		return nil, &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve teams from the database", err}
+	}
+
+	data := &accessReviewData{
+		subject:   &authorization.AccessSubject{},
+		teamNames: make(map[portainer.TeamID]string),
+	}
+	for _, team := range teams {
+		data.teamNames[team.ID] = team.Name
+	}
+
+	if review.TeamID != 0 {
+		team, err := handler.DataStore.Team().Team(review.TeamID)
+		if err == bolterrors.ErrObjectNotFound {
+			return nil, &httperror.HandlerError{http.StatusNotFound, "Unable to find a team with the specified identifier inside the database", err}
+		} else if err != nil {
+			return nil, &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a team with the specified identifier inside the database", err}
+		}
+
+		review.Name = team.Name
+		data.subject.TeamIDs = []portainer.TeamID{team.ID}
+		data.organizationID = team.OrganizationID
+		return data, nil
+	}
+
+	user, err := handler.DataStore.User().User(review.UserID)
+	if err == bolterrors.ErrObjectNotFound {
+		return nil, &httperror.HandlerError{http.StatusNotFound, "Unable to find a user with the specified identifier inside the database", err}
+	} else if err != nil {
+		return nil, &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a user with the specified identifier inside the database", err}
+	}
+
+	memberships, err := handler.DataStore.TeamMembership().TeamMembershipsByUserID(user.ID)
+	if err != nil {
+		return nil, &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve the team memberships of the user from the database", err}
+	}
+
+	review.Name = user.Username
+	data.subject.UserID = user.ID
+	data.subject.IsAdmin = user.Role == portainer.AdministratorRole
+	for _, membership := range memberships {
+		data.subject.TeamIDs = append(data.subject.TeamIDs, membership.TeamID)
+	}
+	data.organizationID = user.OrganizationID
+
+	return data, nil
+}
+
+func (handler *Handler) collectAccessEntries(review *accessReview, data *accessReviewData) error {
+	endpointGroups, err := handler.DataStore.EndpointGroup().EndpointGroups()
+	if err != nil {
+		return err
+	}
+
+	data.endpointGroups = make(map[portainer.EndpointGroupID]portainer.EndpointGroup)
+	for _, endpointGroup := range endpointGroups {
+		data.endpointGroups[endpointGroup.ID] = endpointGroup
+	}
+
+	endpoints, err := handler.DataStore.Endpoint().Endpoints()
+	if err != nil {
+		return err
+	}
+
+	accessibleEndpoints := make(map[portainer.EndpointID]bool)
+	for _, endpoint := range endpoints {
+		if !data.organizationAccess(endpoint.OrganizationID) {
+			continue
+		}
+
+		var endpointGroup *portainer.EndpointGroup
+		if group, ok := data.endpointGroups[endpoint.GroupID]; ok {
+			endpointGroup = &group
+		}
+
+		grant := authorization.EndpointAccessGrant(data.subject, &endpoint, endpointGroup)
+		if grant != nil {
+			accessibleEndpoints[endpoint.ID] = true
+			review.addEntry(data, accessResourceEndpoint, strconv.Itoa(int(endpoint.ID)), endpoint.Name, endpoint.ID, grant)
+		}
+	}
+
+	resourceControls, err := handler.DataStore.ResourceControl().ResourceControls()
+	if err != nil {
+		return err
+	}
+
+	stacks, err := handler.DataStore.Stack().Stacks()
+	if err != nil {
+		return err
+	}
+
+	stacks = authorization.DecorateStacks(stacks, resourceControls)
+	for _, stack := range stacks {
+		if !accessibleEndpoints[stack.EndpointID] {
+			continue
+		}
+
+		grant := authorization.ResourceControlAccessGrant(data.subject, stack.ResourceControl)
+		if grant != nil {
+			review.addEntry(data, accessResourceStack, strconv.Itoa(int(stack.ID)), stack.Name, stack.EndpointID, grant)
+		}
+	}
+
+	for idx := range resourceControls {
+		resourceControl := &resourceControls[idx]
+		if resourceControl.Type != portainer.VolumeResourceControl {
+			continue
+		}
+
+		grant := authorization.ResourceControlAccessGrant(data.subject, resourceControl)
+		if grant != nil {
+			review.addEntry(data, accessResourceVolume, resourceControl.ResourceID, resourceControl.ResourceID, 0, grant)
+		}
+	}
+
+	registries, err := handler.DataStore.Registry().Registries()
+	if err != nil {
+		return err
+	}
+
+	for _, registry := range registries {
+		if !data.organizationAccess(registry.OrganizationID) {
+			continue
+		}
+
+		grant := authorization.RegistryAccessGrant(data.subject, &registry)
+		if grant != nil {
+			review.addEntry(data, accessResourceRegistry, strconv.Itoa(int(registry.ID)), registry.Name, 0, grant)
+		}
+	}
+
+	customTemplates, err := handler.DataStore.CustomTemplate().CustomTemplates()
+	if err != nil {
+		return err
+	}
+
+	customTemplates = authorization.DecorateCustomTemplates(customTemplates, resourceControls)
+	for _, customTemplate := range customTemplates {
+		if !data.organizationAccess(customTemplate.OrganizationID) {
+			continue
+		}
+
+		grant := authorization.CustomTemplateAccessGrant(data.subject, &customTemplate)
+		if grant != nil {
+			review.addEntry(data, accessResourceCustomTemplate, strconv.Itoa(int(customTemplate.ID)), customTemplate.Title, 0, grant)
+		}
+	}
+
+	return nil
+}
+
+// organizationAccess returns true when the resources of an organization can be accessed by the subject of the review
+func (data *accessReviewData) organizationAccess(organizationID portainer.OrganizationID) bool {
+	return security.AuthorizedOrganizationAccess(organizationID, data.organizationID, data.subject.IsAdmin)
+}
+
+func (review *accessReview) addEntry(data *accessReviewData, resourceType, resourceID, resourceName string, endpointID portainer.EndpointID, grant *authorization.AccessGrant) {
+	entry := accessEntry{
+		ResourceType: resourceType,
+		ResourceID:   resourceID,
+		ResourceName: resourceName,
+		EndpointID:   endpointID,
+		TeamName:     data.teamNames[grant.TeamID],
+		AccessGrant:  *grant,
+	}
+
+	if grant.EndpointGroupID != 0 {
+		entry.EndpointGroupName = data.endpointGroups[grant.EndpointGroupID].Name
+	}
+
+	review.Entries = append(review.Entries, entry)
+}
+
+func writeAccessReviewCSV(w http.ResponseWriter, review *accessReview) *httperror.HandlerError {
+	subject := fmt.Sprintf("user-%d", review.UserID)
+	if review.TeamID != 0 {
+		subject = fmt.Sprintf("team-%d", review.TeamID)
+	}
+
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=access-review-%s-%d.csv", subject, review.GenerationDate))
+
+	records := [][]string{{"ResourceType", "ResourceId", "ResourceName", "EndpointId", "Rule", "TeamId", "TeamName", "EndpointGroupId", "EndpointGroupName", "RoleId"}}
+	for _, entry := range review.Entries {
+		records = append(records, []string{
+			entry.ResourceType,
+			entry.ResourceID,
+			entry.ResourceName,
+			formatOptionalID(int(entry.EndpointID)),
+			string(entry.Rule),
+			formatOptionalID(int(entry.TeamID)),
+			entry.TeamName,
+			formatOptionalID(int(entry.EndpointGroupID)),
+			entry.EndpointGroupName,
+			formatOptionalID(int(entry.RoleID)),
+		})
+	}
+
+	err := csv.NewWriter(w).WriteAll(records)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to write the access review", err}
+	}
+
+	return nil
+}
+
+func formatOptionalID(id int) string {
+	if id == 0 {
+		return ""
+	}
+	return strconv.Itoa(id)
+}
@@ -0,0 +1,37 @@
+package reports
+
+import (
+	"net/http"
+	"time"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api/internal/report"
+)
+
+type reportPreviewResponse struct {
+	Subject string `json:"Subject"`
+	Body    string `json:"Body"`
+}
+
+// GET request on /api/reports/preview
+//
+// Renders the report of the current user as it would be sent now.
+func (handler *Handler) reportPreview(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	subscription, handlerErr := handler.retrieveReportSubscription(r)
+	if handlerErr != nil {
+		return handlerErr
+	}
+
+	since := time.Now().Add(-report.Period(subscription.Frequency)).Unix()
+	if subscription.LastSent > since {
+		since = subscription.LastSent
+	}
+
+	subject, body, err := handler.ReportService.Render(subscription, since)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to render the report", err}
+	}
+
+	return response.JSON(w, &reportPreviewResponse{Subject: subject, Body: body})
+}
@@ -0,0 +1,25 @@
+package reports
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/response"
+)
+
+// POST request on /api/reports/send
+//
+// Sends the report of the current user immediately, the next scheduled report covers the period since then.
+func (handler *Handler) reportSend(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	subscription, handlerErr := handler.retrieveReportSubscription(r)
+	if handlerErr != nil {
+		return handlerErr
+	}
+
+	err := handler.ReportService.Send(subscription)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to send the report", err}
+	}
+
+	return response.Empty(w)
+}
@@ -0,0 +1,48 @@
+package reports
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+// GET request on /api/reports/subscription
+func (handler *Handler) reportSubscriptionInspect(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	subscription, handlerErr := handler.retrieveReportSubscription(r)
+	if handlerErr != nil {
+		return handlerErr
+	}
+
+	return response.JSON(w, subscription)
+}
+
+// retrieveReportSubscription returns the report subscription of the current user. When the user did not
+// subscribe yet, a disabled daily subscription including all the sections is returned.
+func (handler *Handler) retrieveReportSubscription(r *http.Request) (*portainer.ReportSubscription, *httperror.HandlerError) {
+	tokenData, err := security.RetrieveTokenData(r)
+	if err != nil {
+		return nil, &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve user authentication token", err}
+	}
+
+	subscription, err := handler.DataStore.ReportSubscription().ReportSubscription(tokenData.ID)
+	if err == bolterrors.ErrObjectNotFound {
+		return &portainer.ReportSubscription{
+			UserID:    tokenData.ID,
+			Frequency: portainer.DailyReport,
+			Sections: []portainer.ReportSection{
+				portainer.EndpointHealthReportSection,
+				portainer.ContainerHealthReportSection,
+				portainer.HostPressureReportSection,
+				portainer.SettingsChangesReportSection,
+			},
+		}, nil
+	} else if err != nil {
+		return nil, &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve the report subscription from the database", err}
+	}
+
+	return subscription, nil
+}
@@ -0,0 +1,65 @@
+package reports
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/asaskevich/govalidator"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+)
+
+type reportSubscriptionUpdatePayload struct {
+	Enabled   bool
+	Email     string
+	Frequency portainer.ReportFrequency
+	Sections  []portainer.ReportSection
+}
+
+func (payload *reportSubscriptionUpdatePayload) Validate(r *http.Request) error {
+	if payload.Enabled && !govalidator.IsEmail(payload.Email) {
+		return errors.New("Invalid email address")
+	}
+	if payload.Frequency != portainer.DailyReport && payload.Frequency != portainer.WeeklyReport {
+		return errors.New("Invalid report frequency. Value must be one of: daily or weekly")
+	}
+	for _, section := range payload.Sections {
+		switch section {
+		case portainer.EndpointHealthReportSection, portainer.ContainerHealthReportSection, portainer.HostPressureReportSection, portainer.SettingsChangesReportSection:
+		default:
+			return errors.New("Invalid report section: " + string(section))
+		}
+	}
+	return nil
+}
+
+// PUT request on /api/reports/subscription
+func (handler *Handler) reportSubscriptionUpdate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	var payload reportSubscriptionUpdatePayload
+	err := request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	subscription, handlerErr := handler.retrieveReportSubscription(r)
+	if handlerErr != nil {
+		return handlerErr
+	}
+
+	subscription.Enabled = payload.Enabled
+	subscription.Email = payload.Email
+	subscription.Frequency = payload.Frequency
+	subscription.Sections = payload.Sections
+	if subscription.Sections == nil {
+		subscription.Sections = make([]portainer.ReportSection, 0)
+	}
+
+	err = handler.DataStore.ReportSubscription().UpdateReportSubscription(subscription.UserID, subscription)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist the report subscription inside the database", err}
+	}
+
+	return response.JSON(w, subscription)
+}
@@ -12,7 +12,8 @@ import (
 // Handler is the HTTP handler used to handle resource control operations.
 type Handler struct {
 	*mux.Router
-	DataStore portainer.DataStore
+	DataStore            portainer.DataStore
+	OrphanCleanupService portainer.OrphanCleanupService
 }
 
 // NewHandler creates a handler to manage resource control operations.
@@ -21,10 +22,12 @@ func NewHandler(bouncer *security.RequestBouncer) *Handler {
 		Router: mux.NewRouter(),
 	}
 	h.Handle("/resource_controls",
-		bouncer.AdminAccess(httperror.LoggerHandler(h.resourceControlCreate))).Methods(http.MethodPost)
+		bouncer.InstanceAdminAccess(httperror.LoggerHandler(h.resourceControlCreate))).Methods(http.MethodPost)
+	h.Handle("/resource_controls/cleanup",
+		bouncer.InstanceAdminAccess(httperror.LoggerHandler(h.resourceControlCleanup))).Methods(http.MethodPost)
 	h.Handle("/resource_controls/{id}",
 		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.resourceControlUpdate))).Methods(http.MethodPut)
 	h.Handle("/resource_controls/{id}",
-		bouncer.AdminAccess(httperror.LoggerHandler(h.resourceControlDelete))).Methods(http.MethodDelete)
+		bouncer.InstanceAdminAccess(httperror.LoggerHandler(h.resourceControlDelete))).Methods(http.MethodDelete)
 	return h
 }
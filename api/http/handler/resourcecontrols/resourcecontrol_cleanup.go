@@ -0,0 +1,23 @@
+package resourcecontrols
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+)
+
+// POST request on /api/resource_controls/cleanup?dryRun=<dryRun>
+// The cleanup is a dry run unless dryRun=false is specified.
+func (handler *Handler) resourceControlCleanup(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	dryRunParameter, _ := request.RetrieveQueryParameter(r, "dryRun", true)
+	dryRun := dryRunParameter != "false"
+
+	report, err := handler.OrphanCleanupService.Cleanup(dryRun)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to cleanup orphaned access control entries", err}
+	}
+
+	return response.JSON(w, report)
+}
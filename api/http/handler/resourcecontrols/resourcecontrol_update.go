@@ -56,6 +56,12 @@ func (handler *Handler) resourceControlUpdate(w http.ResponseWriter, r *http.Req
 		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve info from request context", err}
 	}
 
+	// the resource controls are not part of an organization, only the administrators of the instance can
+	// manage them as administrators
+	if securityContext.IsAdmin && !security.IsInstanceAdmin(securityContext) {
+		return &httperror.HandlerError{http.StatusForbidden, "Permission denied to access the resource control", httperrors.ErrResourceAccessDenied}
+	}
+
 	if !security.AuthorizedResourceControlAccess(resourceControl, securityContext) {
 		return &httperror.HandlerError{http.StatusForbidden, "Permission denied to access the resource control", httperrors.ErrResourceAccessDenied}
 	}
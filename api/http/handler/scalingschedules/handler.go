@@ -0,0 +1,37 @@
+package scalingschedules
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+// Handler is the HTTP handler used to handle scaling schedule operations.
+type Handler struct {
+	*mux.Router
+	DataStore        portainer.DataStore
+	ScalingScheduler portainer.ScalingScheduler
+}
+
+// NewHandler creates a handler to manage scaling schedule operations.
+func NewHandler(bouncer *security.RequestBouncer) *Handler {
+	h := &Handler{
+		Router: mux.NewRouter(),
+	}
+	h.Handle("/scaling_schedules",
+		bouncer.InstanceAdminAccess(httperror.LoggerHandler(h.scalingScheduleCreate))).Methods(http.MethodPost)
+	h.Handle("/scaling_schedules",
+		bouncer.InstanceAdminAccess(httperror.LoggerHandler(h.scalingScheduleList))).Methods(http.MethodGet)
+	h.Handle("/scaling_schedules/{id}",
+		bouncer.InstanceAdminAccess(httperror.LoggerHandler(h.scalingScheduleInspect))).Methods(http.MethodGet)
+	h.Handle("/scaling_schedules/{id}",
+		bouncer.InstanceAdminAccess(httperror.LoggerHandler(h.scalingScheduleUpdate))).Methods(http.MethodPut)
+	h.Handle("/scaling_schedules/{id}",
+		bouncer.InstanceAdminAccess(httperror.LoggerHandler(h.scalingScheduleDelete))).Methods(http.MethodDelete)
+	h.Handle("/scaling_schedules/{id}/execute",
+		bouncer.InstanceAdminAccess(httperror.LoggerHandler(h.scalingScheduleExecute))).Methods(http.MethodPost)
+	return h
+}
@@ -0,0 +1,90 @@
+package scalingschedules
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/asaskevich/govalidator"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+	"github.com/portainer/portainer/api/internal/scaling"
+)
+
+type scalingScheduleCreatePayload struct {
+	Name            string
+	EndpointID      int
+	TargetType      int
+	TargetName      string
+	Namespace       string
+	Timezone        string
+	Rules           []portainer.ScalingRule
+	DefaultReplicas int
+	Enabled         bool
+}
+
+func (payload *scalingScheduleCreatePayload) Validate(r *http.Request) error {
+	if govalidator.IsNull(payload.Name) {
+		return errors.New("Invalid scaling schedule name")
+	}
+	if payload.EndpointID == 0 {
+		return errors.New("Invalid endpoint identifier")
+	}
+	if payload.TargetType != int(portainer.ScalingTargetSwarmService) && payload.TargetType != int(portainer.ScalingTargetKubernetesDeployment) {
+		return errors.New("Invalid target type. Value must be one of: 1 (Swarm service) or 2 (Kubernetes deployment)")
+	}
+	if govalidator.IsNull(payload.TargetName) {
+		return errors.New("Invalid target name")
+	}
+	if payload.TargetType == int(portainer.ScalingTargetKubernetesDeployment) && govalidator.IsNull(payload.Namespace) {
+		return errors.New("Invalid namespace")
+	}
+	return nil
+}
+
+// POST request on /api/scaling_schedules
+func (handler *Handler) scalingScheduleCreate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	var payload scalingScheduleCreatePayload
+	err := request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	_, err = handler.DataStore.Endpoint().Endpoint(portainer.EndpointID(payload.EndpointID))
+	if err == bolterrors.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find an endpoint with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find an endpoint with the specified identifier inside the database", err}
+	}
+
+	schedule := &portainer.ScalingSchedule{
+		Name:            payload.Name,
+		EndpointID:      portainer.EndpointID(payload.EndpointID),
+		TargetType:      portainer.ScalingTargetType(payload.TargetType),
+		TargetName:      payload.TargetName,
+		Namespace:       payload.Namespace,
+		Timezone:        payload.Timezone,
+		Rules:           payload.Rules,
+		DefaultReplicas: payload.DefaultReplicas,
+		Enabled:         payload.Enabled,
+		AppliedReplicas: -1,
+	}
+
+	if schedule.Rules == nil {
+		schedule.Rules = []portainer.ScalingRule{}
+	}
+
+	err = scaling.ValidateSchedule(schedule)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	err = handler.DataStore.ScalingSchedule().CreateScalingSchedule(schedule)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist the scaling schedule inside the database", err}
+	}
+
+	return response.JSON(w, schedule)
+}
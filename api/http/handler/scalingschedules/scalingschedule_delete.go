@@ -0,0 +1,33 @@
+package scalingschedules
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+)
+
+// DELETE request on /api/scaling_schedules/:id
+func (handler *Handler) scalingScheduleDelete(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	scheduleID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid scaling schedule identifier route variable", err}
+	}
+
+	_, err = handler.DataStore.ScalingSchedule().ScalingSchedule(portainer.ScalingScheduleID(scheduleID))
+	if err == bolterrors.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find a scaling schedule with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a scaling schedule with the specified identifier inside the database", err}
+	}
+
+	err = handler.DataStore.ScalingSchedule().DeleteScalingSchedule(portainer.ScalingScheduleID(scheduleID))
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to remove the scaling schedule from the database", err}
+	}
+
+	return response.Empty(w)
+}
@@ -0,0 +1,28 @@
+package scalingschedules
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+)
+
+// GET request on /api/scaling_schedules/:id
+func (handler *Handler) scalingScheduleInspect(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	scheduleID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid scaling schedule identifier route variable", err}
+	}
+
+	schedule, err := handler.DataStore.ScalingSchedule().ScalingSchedule(portainer.ScalingScheduleID(scheduleID))
+	if err == bolterrors.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find a scaling schedule with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a scaling schedule with the specified identifier inside the database", err}
+	}
+
+	return response.JSON(w, schedule)
+}
@@ -0,0 +1,18 @@
+package scalingschedules
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/response"
+)
+
+// GET request on /api/scaling_schedules
+func (handler *Handler) scalingScheduleList(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	schedules, err := handler.DataStore.ScalingSchedule().ScalingSchedules()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve scaling schedules from the database", err}
+	}
+
+	return response.JSON(w, schedules)
+}
@@ -0,0 +1,97 @@
+package scalingschedules
+
+import (
+	"errors"
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+	"github.com/portainer/portainer/api/internal/scaling"
+)
+
+type scalingScheduleUpdatePayload struct {
+	Name            *string
+	TargetName      *string
+	Namespace       *string
+	Timezone        *string
+	Rules           []portainer.ScalingRule
+	DefaultReplicas *int
+	Enabled         *bool
+}
+
+func (payload *scalingScheduleUpdatePayload) Validate(r *http.Request) error {
+	if payload.Name != nil && *payload.Name == "" {
+		return errors.New("Invalid scaling schedule name")
+	}
+	if payload.TargetName != nil && *payload.TargetName == "" {
+		return errors.New("Invalid target name")
+	}
+	return nil
+}
+
+// PUT request on /api/scaling_schedules/:id
+func (handler *Handler) scalingScheduleUpdate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	scheduleID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid scaling schedule identifier route variable", err}
+	}
+
+	var payload scalingScheduleUpdatePayload
+	err = request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	schedule, err := handler.DataStore.ScalingSchedule().ScalingSchedule(portainer.ScalingScheduleID(scheduleID))
+	if err == bolterrors.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find a scaling schedule with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a scaling schedule with the specified identifier inside the database", err}
+	}
+
+	if payload.Name != nil {
+		schedule.Name = *payload.Name
+	}
+
+	if payload.TargetName != nil {
+		schedule.TargetName = *payload.TargetName
+	}
+
+	if payload.Namespace != nil {
+		schedule.Namespace = *payload.Namespace
+	}
+
+	if payload.Timezone != nil {
+		schedule.Timezone = *payload.Timezone
+	}
+
+	if payload.Rules != nil {
+		schedule.Rules = payload.Rules
+	}
+
+	if payload.DefaultReplicas != nil {
+		schedule.DefaultReplicas = *payload.DefaultReplicas
+	}
+
+	if payload.Enabled != nil {
+		schedule.Enabled = *payload.Enabled
+	}
+
+	err = scaling.ValidateSchedule(schedule)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	// the schedule is applied again during the next execution
+	schedule.AppliedReplicas = -1
+
+	err = handler.DataStore.ScalingSchedule().UpdateScalingSchedule(schedule.ID, schedule)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist the scaling schedule changes inside the database", err}
+	}
+
+	return response.JSON(w, schedule)
+}
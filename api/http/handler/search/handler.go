@@ -0,0 +1,30 @@
+package search
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/search"
+)
+
+// Handler is the HTTP handler used to search resources across endpoints.
+type Handler struct {
+	*mux.Router
+	requestBouncer *security.RequestBouncer
+	DataStore      portainer.DataStore
+	SearchService  *search.Service
+}
+
+// NewHandler creates a handler to search resources across endpoints.
+func NewHandler(bouncer *security.RequestBouncer) *Handler {
+	h := &Handler{
+		Router:         mux.NewRouter(),
+		requestBouncer: bouncer,
+	}
+	h.Handle("/search",
+		bouncer.RestrictedAccess(httperror.LoggerHandler(h.search))).Methods(http.MethodGet)
+	return h
+}
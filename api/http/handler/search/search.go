@@ -0,0 +1,117 @@
+package search
+
+import (
+	"errors"
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/authorization"
+	"github.com/portainer/portainer/api/internal/search"
+)
+
+const (
+	defaultSearchLimit = 50
+	maxSearchLimit     = 500
+)
+
+// GET request on /api/search?q=<query>&limit=<limit>
+//
+// Searches endpoints, stacks, containers, images and volumes across all the endpoints the user can
+// access. Docker resources are searched in the latest snapshot of each endpoint.
+func (handler *Handler) search(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	query, err := request.RetrieveQueryParameter(r, "q", false)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid query parameter: q", err}
+	}
+
+	limit, _ := request.RetrieveNumericQueryParameter(r, "limit", true)
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	if limit > maxSearchLimit {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid query parameter: limit", errors.New("Limit cannot exceed 500")}
+	}
+
+	securityContext, err := security.RetrieveRestrictedRequestContext(r)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve info from request context", err}
+	}
+
+	endpoints, err := handler.DataStore.Endpoint().Endpoints()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve endpoints from the database", err}
+	}
+
+	endpointGroups, err := handler.DataStore.EndpointGroup().EndpointGroups()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve endpoint groups from the database", err}
+	}
+
+	stacks, err := handler.DataStore.Stack().Stacks()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve stacks from the database", err}
+	}
+
+	filter := &resourceFilter{
+		securityContext:     securityContext,
+		accessibleEndpoints: make(map[portainer.EndpointID]bool),
+	}
+
+	for _, endpoint := range security.FilterEndpoints(endpoints, endpointGroups, securityContext) {
+		filter.accessibleEndpoints[endpoint.ID] = true
+	}
+
+	if !securityContext.IsAdmin {
+		filter.resourceControls, err = handler.DataStore.ResourceControl().ResourceControls()
+		if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve resource controls from the database", err}
+		}
+
+		for _, membership := range securityContext.UserMemberships {
+			filter.userTeamIDs = append(filter.userTeamIDs, membership.TeamID)
+		}
+	}
+
+	index := handler.SearchService.Index(endpoints, stacks)
+	results := index.Search(query, filter.canAccess, limit)
+
+	return response.JSON(w, results)
+}
+
+// resourceFilter applies the same access rules as the Docker API proxy: non administrator users
+// can only see the resources associated to a resource control they are authorized on, either directly
+// or inherited from the service or the stack of the resource.
+type resourceFilter struct {
+	securityContext     *security.RestrictedRequestContext
+	accessibleEndpoints map[portainer.EndpointID]bool
+	resourceControls    []portainer.ResourceControl
+	userTeamIDs         []portainer.TeamID
+}
+
+func (filter *resourceFilter) canAccess(document *search.Document) bool {
+	if !filter.accessibleEndpoints[document.EndpointID] {
+		return false
+	}
+
+	if filter.securityContext.IsAdmin {
+		return true
+	}
+
+	var resourceControl *portainer.ResourceControl
+	switch document.Type {
+	case search.EndpointDocument, search.ImageDocument:
+		return true
+	case search.StackDocument:
+		resourceControl = authorization.GetResourceControlByResourceIDAndType(document.Name, portainer.StackResourceControl, filter.resourceControls)
+	case search.ContainerDocument:
+		resourceControl = authorization.GetInheritedResourceControl(document.ID, portainer.ContainerResourceControl, document.Labels, filter.resourceControls)
+	case search.VolumeDocument:
+		resourceControl = authorization.GetInheritedResourceControl(document.ID, portainer.VolumeResourceControl, document.Labels, filter.resourceControls)
+	}
+
+	return resourceControl != nil && authorization.UserCanAccessResource(filter.securityContext.UserID, filter.userTeamIDs, resourceControl)
+}
@@ -10,18 +10,26 @@ import (
 )
 
 func hideFields(settings *portainer.Settings) {
+	if settings == nil {
+		return
+	}
 	settings.LDAPSettings.Password = ""
 	settings.OAuthSettings.ClientSecret = ""
+	settings.SMTPSettings.Password = ""
 }
 
 // Handler is the HTTP handler used to handle settings operations.
 type Handler struct {
 	*mux.Router
-	DataStore       portainer.DataStore
-	FileService     portainer.FileService
-	JWTService      portainer.JWTService
-	LDAPService     portainer.LDAPService
-	SnapshotService portainer.SnapshotService
+	DataStore          portainer.DataStore
+	FeatureFlagService portainer.FeatureFlagService
+	FileService        portainer.FileService
+	JWTService         portainer.JWTService
+	LDAPService        portainer.LDAPService
+	MailService        portainer.MailService
+	RetentionService   portainer.RetentionService
+	SnapshotService    portainer.SnapshotService
+	TelemetryService   portainer.TelemetryService
 }
 
 // NewHandler creates a handler to manage settings operations.
@@ -32,11 +40,23 @@ func NewHandler(bouncer *security.RequestBouncer) *Handler {
 	h.Handle("/settings",
 		bouncer.AdminAccess(httperror.LoggerHandler(h.settingsInspect))).Methods(http.MethodGet)
 	h.Handle("/settings",
-		bouncer.AdminAccess(httperror.LoggerHandler(h.settingsUpdate))).Methods(http.MethodPut)
+		bouncer.InstanceAdminAccess(httperror.LoggerHandler(h.settingsUpdate))).Methods(http.MethodPut)
+	h.Handle("/settings/history",
+		bouncer.InstanceAdminAccess(httperror.LoggerHandler(h.settingsHistoryList))).Methods(http.MethodGet)
+	h.Handle("/settings/history/{id}",
+		bouncer.InstanceAdminAccess(httperror.LoggerHandler(h.settingsHistoryInspect))).Methods(http.MethodGet)
+	h.Handle("/settings/history/{id}/rollback",
+		bouncer.InstanceAdminAccess(httperror.LoggerHandler(h.settingsHistoryRollback))).Methods(http.MethodPost)
+	h.Handle("/settings/retention/purge",
+		bouncer.InstanceAdminAccess(httperror.LoggerHandler(h.settingsRetentionPurge))).Methods(http.MethodPost)
+	h.Handle("/settings/retention/report",
+		bouncer.InstanceAdminAccess(httperror.LoggerHandler(h.settingsRetentionReport))).Methods(http.MethodGet)
 	h.Handle("/settings/public",
 		bouncer.PublicAccess(httperror.LoggerHandler(h.settingsPublic))).Methods(http.MethodGet)
 	h.Handle("/settings/authentication/checkLDAP",
-		bouncer.AdminAccess(httperror.LoggerHandler(h.settingsLDAPCheck))).Methods(http.MethodPut)
+		bouncer.InstanceAdminAccess(httperror.LoggerHandler(h.settingsLDAPCheck))).Methods(http.MethodPut)
+	h.Handle("/settings/notifications/email/check",
+		bouncer.InstanceAdminAccess(httperror.LoggerHandler(h.settingsEmailCheck))).Methods(http.MethodPut)
 
 	return h
 }
@@ -0,0 +1,71 @@
+package settings
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/asaskevich/govalidator"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/internal/notification"
+)
+
+type settingsEmailCheckPayload struct {
+	SMTPSettings       portainer.SMTPSettings
+	EmailNotifications portainer.EmailNotificationSettings
+}
+
+func (payload *settingsEmailCheckPayload) Validate(r *http.Request) error {
+	if len(payload.EmailNotifications.Recipients) == 0 {
+		return errors.New("At least one recipient is required")
+	}
+	for _, recipient := range payload.EmailNotifications.Recipients {
+		if !govalidator.IsEmail(recipient) {
+			return errors.New("Invalid recipient email address: " + recipient)
+		}
+	}
+	return notification.ValidateTemplates(payload.EmailNotifications.Templates)
+}
+
+// PUT request on /settings/notifications/email/check
+//
+// Sends a test notification rendered with the templates of the payload, using the SMTP settings of the payload.
+// The stored SMTP password is used when the payload does not specify one.
+// The notification is translated in the default locale defined in the settings.
+func (handler *Handler) settingsEmailCheck(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	var payload settingsEmailCheckPayload
+	err := request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	settings, err := handler.DataStore.Settings().Settings()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve the settings from the database", err}
+	}
+
+	if payload.SMTPSettings.Password == "" {
+		payload.SMTPSettings.Password = settings.SMTPSettings.Password
+	}
+
+	testNotification := &portainer.Notification{
+		Title:   "Test notification",
+		Message: "This is a test notification sent from Portainer.",
+		Time:    time.Now().Unix(),
+	}
+
+	subject, body, err := notification.RenderEmail(&payload.EmailNotifications, testNotification, "", settings.DefaultLocale)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Unable to render the notification templates", err}
+	}
+
+	err = handler.MailService.Send(&payload.SMTPSettings, payload.EmailNotifications.Recipients, subject, body)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to send email", err}
+	}
+
+	return response.Empty(w)
+}
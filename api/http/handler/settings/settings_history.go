@@ -0,0 +1,230 @@
+package settings
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"reflect"
+	"sort"
+	"time"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+const hiddenSettingsValue = "********"
+
+// secretSettingsFields are the settings fields whose values are never exposed in the history
+var secretSettingsFields = map[string]bool{
+	"LDAPSettings.Password":      true,
+	"OAuthSettings.ClientSecret": true,
+	"SMTPSettings.Password":      true,
+}
+
+// GET request on /api/settings/history
+func (handler *Handler) settingsHistoryList(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	changes, err := handler.DataStore.SettingsHistory().SettingsChanges()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve the settings history from the database", err}
+	}
+
+	for idx := range changes {
+		changes[idx].Before = nil
+		changes[idx].After = nil
+	}
+
+	return response.JSON(w, changes)
+}
+
+// GET request on /api/settings/history/:id
+func (handler *Handler) settingsHistoryInspect(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	change, handlerErr := handler.retrieveSettingsChange(r)
+	if handlerErr != nil {
+		return handlerErr
+	}
+
+	hideFields(change.Before)
+	hideFields(change.After)
+	return response.JSON(w, change)
+}
+
+// POST request on /api/settings/history/:id/rollback
+//
+// Restores the settings as they were before the specified change. The rollback is recorded
+// as a new change of the history.
+func (handler *Handler) settingsHistoryRollback(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	change, handlerErr := handler.retrieveSettingsChange(r)
+	if handlerErr != nil {
+		return handlerErr
+	}
+
+	settings, err := handler.DataStore.Settings().Settings()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve the settings from the database", err}
+	}
+	before := *settings
+
+	restoredSettings := change.Before
+
+	if restoredSettings.SnapshotInterval != settings.SnapshotInterval {
+		err := handler.updateSnapshotInterval(restoredSettings, restoredSettings.SnapshotInterval)
+		if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to update snapshot interval", err}
+		}
+	}
+
+	if restoredSettings.UserSessionTimeout != settings.UserSessionTimeout {
+		userSessionDuration, err := time.ParseDuration(restoredSettings.UserSessionTimeout)
+		if err == nil {
+			handler.JWTService.SetUserSessionDuration(userSessionDuration)
+		}
+	}
+
+	tlsError := handler.updateTLS(restoredSettings)
+	if tlsError != nil {
+		return tlsError
+	}
+
+	err = handler.DataStore.Settings().UpdateSettings(restoredSettings)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist settings changes inside the database", err}
+	}
+
+	if restoredSettings.Telemetry.Mode != before.Telemetry.Mode {
+		handler.TelemetryService.SetMode(restoredSettings.Telemetry.Mode)
+	}
+
+	handler.recordSettingsChange(r, &before, restoredSettings, change.ID)
+
+	hideFields(restoredSettings)
+	return response.JSON(w, restoredSettings)
+}
+
+func (handler *Handler) retrieveSettingsChange(r *http.Request) (*portainer.SettingsChange, *httperror.HandlerError) {
+	changeID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return nil, &httperror.HandlerError{http.StatusBadRequest, "Invalid settings change identifier route variable", err}
+	}
+
+	change, err := handler.DataStore.SettingsHistory().SettingsChange(portainer.SettingsChangeID(changeID))
+	if err == bolterrors.ErrObjectNotFound {
+		return nil, &httperror.HandlerError{http.StatusNotFound, "Unable to find a settings change with the specified identifier inside the database", err}
+	} else if err != nil {
+		return nil, &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a settings change with the specified identifier inside the database", err}
+	}
+
+	return change, nil
+}
+
+// recordSettingsChange stores a settings change in the history. Changes that do not modify
+// any field are not recorded. Failing to record a change does not revert it.
+func (handler *Handler) recordSettingsChange(r *http.Request, before, after *portainer.Settings, rollbackOf portainer.SettingsChangeID) {
+	diff, err := diffSettings(before, after)
+	if err != nil {
+		log.Printf("[WARN] [http,settings] [message: unable to compute settings changes] [error: %s]", err)
+		return
+	}
+
+	if len(diff) == 0 {
+		return
+	}
+
+	change := &portainer.SettingsChange{
+		Time:       time.Now().Unix(),
+		RollbackOf: rollbackOf,
+		Diff:       diff,
+		Before:     before,
+		After:      after,
+	}
+
+	tokenData, err := security.RetrieveTokenData(r)
+	if err == nil {
+		change.UserID = tokenData.ID
+		change.Username = tokenData.Username
+	}
+
+	err = handler.DataStore.SettingsHistory().CreateSettingsChange(change)
+	if err != nil {
+		log.Printf("[WARN] [http,settings] [message: unable to record settings change] [error: %s]", err)
+	}
+}
+
+// diffSettings returns the fields that differ between two settings objects, ordered by field path
+func diffSettings(before, after *portainer.Settings) ([]portainer.SettingsFieldChange, error) {
+	beforeFields, err := flattenSettings(before)
+	if err != nil {
+		return nil, err
+	}
+
+	afterFields, err := flattenSettings(after)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make(map[string]bool)
+	for field := range beforeFields {
+		fields[field] = true
+	}
+	for field := range afterFields {
+		fields[field] = true
+	}
+
+	diff := make([]portainer.SettingsFieldChange, 0)
+	for field := range fields {
+		beforeValue, afterValue := beforeFields[field], afterFields[field]
+		if reflect.DeepEqual(beforeValue, afterValue) {
+			continue
+		}
+
+		if secretSettingsFields[field] {
+			beforeValue, afterValue = hiddenSettingsValue, hiddenSettingsValue
+		}
+
+		diff = append(diff, portainer.SettingsFieldChange{Field: field, Before: beforeValue, After: afterValue})
+	}
+
+	sort.Slice(diff, func(i, j int) bool {
+		return diff[i].Field < diff[j].Field
+	})
+
+	return diff, nil
+}
+
+// flattenSettings returns the JSON representation of the settings as a map of field paths to values.
+// Objects are flattened, arrays are kept as single values.
+func flattenSettings(settings *portainer.Settings) (map[string]interface{}, error) {
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return nil, err
+	}
+
+	var object map[string]interface{}
+	err = json.Unmarshal(data, &object)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make(map[string]interface{})
+	flattenObject("", object, fields)
+	return fields, nil
+}
+
+func flattenObject(prefix string, object map[string]interface{}, fields map[string]interface{}) {
+	for key, value := range object {
+		field := key
+		if prefix != "" {
+			field = prefix + "." + key
+		}
+
+		if child, ok := value.(map[string]interface{}); ok {
+			flattenObject(field, child, fields)
+			continue
+		}
+
+		fields[field] = value
+	}
+}
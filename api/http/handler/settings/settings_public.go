@@ -7,6 +7,7 @@ import (
 	httperror "github.com/portainer/libhttp/error"
 	"github.com/portainer/libhttp/response"
 	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/internal/i18n"
 )
 
 type publicSettingsResponse struct {
@@ -23,6 +24,10 @@ type publicSettingsResponse struct {
 	EnableEdgeComputeFeatures                 bool                           `json:"EnableEdgeComputeFeatures"`
 	OAuthLoginURI                             string                         `json:"OAuthLoginURI"`
 	EnableTelemetry                           bool                           `json:"EnableTelemetry"`
+	FeatureFlags                              []portainer.FeatureFlag        `json:"FeatureFlags"`
+	DefaultLocale                             string                         `json:"DefaultLocale"`
+	Locales                                   []string                       `json:"Locales"`
+	PasswordPolicy                            portainer.PasswordPolicy       `json:"PasswordPolicy"`
 }
 
 // GET request on /api/settings/public
@@ -45,6 +50,10 @@ func (handler *Handler) settingsPublic(w http.ResponseWriter, r *http.Request) *
 		EnableHostManagementFeatures:              settings.EnableHostManagementFeatures,
 		EnableEdgeComputeFeatures:                 settings.EnableEdgeComputeFeatures,
 		EnableTelemetry:                           settings.EnableTelemetry,
+		FeatureFlags:                              handler.FeatureFlagService.EnabledFlags(),
+		DefaultLocale:                             settings.DefaultLocale,
+		Locales:                                   i18n.Locales(),
+		PasswordPolicy:                            settings.PasswordPolicy,
 		OAuthLoginURI: fmt.Sprintf("%s?response_type=code&client_id=%s&redirect_uri=%s&scope=%s&prompt=login",
 			settings.OAuthSettings.AuthorizationURI,
 			settings.OAuthSettings.ClientID,
@@ -0,0 +1,34 @@
+package settings
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/response"
+)
+
+// POST request on /api/settings/retention/purge
+//
+// Removes the records older than the retention defined in the settings for their category
+// and returns the number of removed records and the space reclaimed in the database.
+func (handler *Handler) settingsRetentionPurge(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	report, err := handler.RetentionService.Purge()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to purge the expired records", err}
+	}
+
+	return response.JSON(w, report)
+}
+
+// GET request on /api/settings/retention/report
+//
+// Returns the report of the last scheduled or manual purge, the report is empty when no purge
+// was executed since the start of the server.
+func (handler *Handler) settingsRetentionReport(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	report := handler.RetentionService.LastReport()
+	if report == nil {
+		return response.Empty(w)
+	}
+
+	return response.JSON(w, report)
+}
@@ -2,7 +2,13 @@ package settings
 
 import (
 	"errors"
+	"fmt"
+	"net"
 	"net/http"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/asaskevich/govalidator"
@@ -11,14 +17,30 @@ import (
 	"github.com/portainer/libhttp/response"
 	portainer "github.com/portainer/portainer/api"
 	"github.com/portainer/portainer/api/filesystem"
+	"github.com/portainer/portainer/api/internal/authorization"
+	"github.com/portainer/portainer/api/internal/featureflag"
+	"github.com/portainer/portainer/api/internal/i18n"
+	"github.com/portainer/portainer/api/internal/notification"
 )
 
+const (
+	maxBrandingTitleLength = 64
+	maxBrandingTextLength  = 4096
+)
+
+var colorPattern = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
 type settingsUpdatePayload struct {
 	LogoURL                                   *string
 	BlackListedLabels                         []portainer.Pair
+	HiddenContainerNames                      []string
+	HiddenComposeProjects                     []string
+	FeatureFlags                              map[portainer.FeatureFlag]bool
 	AuthenticationMethod                      *int
 	LDAPSettings                              *portainer.LDAPSettings
 	OAuthSettings                             *portainer.OAuthSettings
+	SMTPSettings                              *portainer.SMTPSettings
+	EmailNotifications                        *portainer.EmailNotificationSettings
 	AllowBindMountsForRegularUsers            *bool
 	AllowPrivilegedModeForRegularUsers        *bool
 	AllowHostNamespaceForRegularUsers         *bool
@@ -30,9 +52,27 @@ type settingsUpdatePayload struct {
 	SnapshotInterval                          *string
 	TemplatesURL                              *string
 	EdgeAgentCheckinInterval                  *int
+	EdgeAgentCheckinJitter                    *int
 	EnableEdgeComputeFeatures                 *bool
 	UserSessionTimeout                        *string
 	EnableTelemetry                           *bool
+	EnableUserDirectoryReadThrough            *bool
+	PasswordPolicy                            *portainer.PasswordPolicy
+	EnableProxyRequestLogging                 *bool
+	EnableEdgeWaitingRoom                     *bool
+	EdgeTunnelServerAddress                   *string
+	EdgeTunnelZones                           []portainer.EdgeTunnelZone
+	EdgeTunnel                                *portainer.EdgeTunnelSettings
+	DefaultResourceOwnershipPolicy            *int
+	ContainerAlerting                         *portainer.ContainerAlertingSettings
+	HostAlerting                              *portainer.HostAlertingSettings
+	ContainerLabelPolicy                      *portainer.ContainerLabelPolicy
+	Branding                                  *portainer.BrandingSettings
+	DefaultLocale                             *string
+	Telemetry                                 *portainer.TelemetrySettings
+	UserActivity                              *portainer.UserActivitySettings
+	Retention                                 *portainer.RetentionSettings
+	HostBrowser                               *portainer.HostBrowserSettings
 }
 
 func (payload *settingsUpdatePayload) Validate(r *http.Request) error {
@@ -45,16 +85,192 @@ func (payload *settingsUpdatePayload) Validate(r *http.Request) error {
 	if payload.TemplatesURL != nil && *payload.TemplatesURL != "" && !govalidator.IsURL(*payload.TemplatesURL) {
 		return errors.New("Invalid external templates URL. Must correspond to a valid URL format")
 	}
+	if payload.EdgeAgentCheckinJitter != nil && *payload.EdgeAgentCheckinJitter < 0 {
+		return errors.New("Invalid Edge agent check-in jitter. Value must be positive")
+	}
+	if payload.EdgeTunnelServerAddress != nil && *payload.EdgeTunnelServerAddress != "" && !validTunnelServerAddress(*payload.EdgeTunnelServerAddress) {
+		return errors.New("Invalid Edge tunnel server address. Must be formatted as host:port")
+	}
+	if payload.EdgeTunnelZones != nil {
+		zoneNames := make(map[string]bool)
+		for _, zone := range payload.EdgeTunnelZones {
+			if govalidator.IsNull(zone.Name) || zoneNames[zone.Name] {
+				return errors.New("Invalid Edge tunnel zone name. Names must be defined and unique")
+			}
+			zoneNames[zone.Name] = true
+
+			if !validTunnelServerAddress(zone.Address) {
+				return errors.New("Invalid Edge tunnel zone address. Must be formatted as host:port")
+			}
+		}
+	}
+	if payload.EdgeTunnel != nil {
+		for _, duration := range []string{payload.EdgeTunnel.KeepAliveInterval, payload.EdgeTunnel.RequiredTimeout, payload.EdgeTunnel.InactivityTimeout} {
+			if duration == "" {
+				continue
+			}
+			value, err := time.ParseDuration(duration)
+			if err != nil || value <= 0 {
+				return errors.New("Invalid Edge tunnel settings. Durations must be positive, e.g. 30s or 5m")
+			}
+		}
+	}
 	if payload.UserSessionTimeout != nil {
 		_, err := time.ParseDuration(*payload.UserSessionTimeout)
 		if err != nil {
 			return errors.New("Invalid user session timeout")
 		}
 	}
+	if payload.PasswordPolicy != nil {
+		if payload.PasswordPolicy.MinLength < 0 || payload.PasswordPolicy.MinLength > 128 {
+			return errors.New("Invalid password policy minimum length. Value must be between 0 and 128")
+		}
+		if payload.PasswordPolicy.HistorySize < 0 || payload.PasswordPolicy.HistorySize > 25 {
+			return errors.New("Invalid password policy history size. Value must be between 0 and 25")
+		}
+		if payload.PasswordPolicy.MaxAge < 0 {
+			return errors.New("Invalid password policy maximum age. Value must be positive")
+		}
+	}
+	if payload.Retention != nil {
+		for _, days := range []int{payload.Retention.UserActivityDays, payload.Retention.SettingsHistoryDays, payload.Retention.TaskDays, payload.Retention.NotificationDays, payload.Retention.ResourceSampleDays, payload.Retention.AutoHealActionDays} {
+			if days < 0 && days != portainer.RetentionForever {
+				return errors.New("Invalid retention settings. Values must be positive, or -1 to keep the records forever")
+			}
+		}
+	}
+	if payload.HostBrowser != nil {
+		for _, allowedPath := range payload.HostBrowser.AllowedPaths {
+			if !path.IsAbs(allowedPath) {
+				return errors.New("Invalid host browser path. Paths must be absolute")
+			}
+		}
+	}
+	if payload.DefaultResourceOwnershipPolicy != nil && !authorization.ValidResourceOwnershipPolicy(portainer.ResourceOwnershipPolicy(*payload.DefaultResourceOwnershipPolicy)) {
+		return errors.New("Invalid default resource ownership policy value. Value must be one of: 1 (private), 2 (team) or 3 (public)")
+	}
+	if payload.ContainerAlerting != nil {
+		_, err := time.ParseDuration(payload.ContainerAlerting.DeduplicationInterval)
+		if err != nil {
+			return errors.New("Invalid container alerting deduplication interval")
+		}
+		for _, silence := range payload.ContainerAlerting.Silences {
+			if silence.Until <= 0 {
+				return errors.New("Invalid container alerting silence. The end of the silence window is mandatory")
+			}
+		}
+	}
+	if payload.HostAlerting != nil {
+		if payload.HostAlerting.DiskUsageThreshold < 0 || payload.HostAlerting.DiskUsageThreshold > 100 {
+			return errors.New("Invalid host disk usage threshold. Value must be a percentage")
+		}
+		if payload.HostAlerting.MemoryUsageThreshold < 0 || payload.HostAlerting.MemoryUsageThreshold > 100 {
+			return errors.New("Invalid host memory usage threshold. Value must be a percentage")
+		}
+		if payload.HostAlerting.LoadAverageThreshold < 0 {
+			return errors.New("Invalid host load average threshold. Value must be positive")
+		}
+	}
+	for _, pattern := range payload.HiddenContainerNames {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return errors.New("Invalid hidden container name pattern: " + pattern)
+		}
+	}
+	if payload.SMTPSettings != nil {
+		if payload.SMTPSettings.Port < 0 || payload.SMTPSettings.Port > 65535 {
+			return errors.New("Invalid SMTP port")
+		}
+		if payload.SMTPSettings.TLS && payload.SMTPSettings.StartTLS {
+			return errors.New("Invalid SMTP settings. TLS and StartTLS cannot be enabled together")
+		}
+		if payload.SMTPSettings.From != "" && !govalidator.IsEmail(payload.SMTPSettings.From) {
+			return errors.New("Invalid SMTP sender email address")
+		}
+	}
+	if payload.EmailNotifications != nil {
+		if payload.EmailNotifications.Enabled && len(payload.EmailNotifications.Recipients) == 0 {
+			return errors.New("Invalid email notification settings. At least one recipient is required")
+		}
+		for _, recipient := range payload.EmailNotifications.Recipients {
+			if !govalidator.IsEmail(recipient) {
+				return errors.New("Invalid email notification recipient: " + recipient)
+			}
+		}
+		if err := notification.ValidateTemplates(payload.EmailNotifications.Templates); err != nil {
+			return errors.New("Invalid email notification template: " + err.Error())
+		}
+	}
+	for flag := range payload.FeatureFlags {
+		if err := featureflag.ValidateFlag(string(flag)); err != nil {
+			return err
+		}
+	}
+	if payload.ContainerLabelPolicy != nil {
+		for _, label := range payload.ContainerLabelPolicy.Labels {
+			if strings.TrimSpace(label.Name) == "" {
+				return errors.New("Invalid container label policy. Injected labels must have a name")
+			}
+		}
+		for _, label := range payload.ContainerLabelPolicy.RequiredLabels {
+			if strings.TrimSpace(label) == "" {
+				return errors.New("Invalid container label policy. Required labels must have a name")
+			}
+		}
+	}
+	if payload.Branding != nil {
+		err := validateBranding(payload.Branding)
+		if err != nil {
+			return err
+		}
+	}
+	if payload.Telemetry != nil {
+		switch payload.Telemetry.Mode {
+		case portainer.TelemetryDisabled, portainer.TelemetryOffline, portainer.TelemetryOnline:
+		default:
+			return errors.New("Invalid telemetry mode. Value must be one of: disabled, offline or online")
+		}
+		if payload.Telemetry.Mode == portainer.TelemetryOnline && !govalidator.IsURL(payload.Telemetry.URL) {
+			return errors.New("Invalid telemetry URL. Must correspond to a valid URL format")
+		}
+	}
+	if payload.DefaultLocale != nil && !i18n.IsSupported(*payload.DefaultLocale) {
+		return fmt.Errorf("Invalid default locale. Value must be one of: %s", strings.Join(i18n.Locales(), ", "))
+	}
 
 	return nil
 }
 
+func validateBranding(branding *portainer.BrandingSettings) error {
+	if len(branding.Title) > maxBrandingTitleLength {
+		return errors.New("Invalid branding title. The title cannot exceed 64 characters")
+	}
+	for _, color := range []string{branding.PrimaryColor, branding.AccentColor} {
+		if color != "" && !colorPattern.MatchString(color) {
+			return errors.New("Invalid branding color. Colors must use the hexadecimal format, e.g. #337ab7")
+		}
+	}
+	for _, text := range []string{branding.LoginMessage, branding.LoginBanner, branding.LegalBanner} {
+		if len(text) > maxBrandingTextLength {
+			return errors.New("Invalid branding text. Messages and banners cannot exceed 4096 characters")
+		}
+	}
+	if branding.RequireLoginBannerAcknowledgement && strings.TrimSpace(branding.LoginBanner) == "" {
+		return errors.New("Invalid branding settings. A login banner is required to request its acknowledgement")
+	}
+	return nil
+}
+
+// validTunnelServerAddress returns true when the address is formatted as host:port
+func validTunnelServerAddress(address string) bool {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil || host == "" {
+		return false
+	}
+
+	portNumber, err := strconv.Atoi(port)
+	return err == nil && portNumber > 0 && portNumber <= 65535
+}
+
 // PUT request on /api/settings
 func (handler *Handler) settingsUpdate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
 	var payload settingsUpdatePayload
@@ -67,6 +283,7 @@ func (handler *Handler) settingsUpdate(w http.ResponseWriter, r *http.Request) *
 	if err != nil {
 		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve the settings from the database", err}
 	}
+	before := *settings
 
 	if payload.AuthenticationMethod != nil {
 		settings.AuthenticationMethod = portainer.AuthenticationMethod(*payload.AuthenticationMethod)
@@ -84,6 +301,18 @@ func (handler *Handler) settingsUpdate(w http.ResponseWriter, r *http.Request) *
 		settings.BlackListedLabels = payload.BlackListedLabels
 	}
 
+	if payload.HiddenContainerNames != nil {
+		settings.HiddenContainerNames = payload.HiddenContainerNames
+	}
+
+	if payload.HiddenComposeProjects != nil {
+		settings.HiddenComposeProjects = payload.HiddenComposeProjects
+	}
+
+	if payload.FeatureFlags != nil {
+		settings.FeatureFlags = payload.FeatureFlags
+	}
+
 	if payload.LDAPSettings != nil {
 		ldapReaderDN := settings.LDAPSettings.ReaderDN
 		ldapPassword := settings.LDAPSettings.Password
@@ -98,6 +327,28 @@ func (handler *Handler) settingsUpdate(w http.ResponseWriter, r *http.Request) *
 		settings.LDAPSettings.Password = ldapPassword
 	}
 
+	if payload.SMTPSettings != nil {
+		smtpPassword := payload.SMTPSettings.Password
+		if smtpPassword == "" {
+			smtpPassword = settings.SMTPSettings.Password
+		}
+		settings.SMTPSettings = *payload.SMTPSettings
+		settings.SMTPSettings.Password = smtpPassword
+	}
+
+	if payload.EmailNotifications != nil {
+		settings.EmailNotifications = *payload.EmailNotifications
+		if settings.EmailNotifications.Recipients == nil {
+			settings.EmailNotifications.Recipients = make([]string, 0)
+		}
+		if settings.EmailNotifications.Types == nil {
+			settings.EmailNotifications.Types = make([]portainer.NotificationType, 0)
+		}
+		if settings.EmailNotifications.Templates == nil {
+			settings.EmailNotifications.Templates = make([]portainer.NotificationTemplate, 0)
+		}
+	}
+
 	if payload.OAuthSettings != nil {
 		clientSecret := payload.OAuthSettings.ClientSecret
 		if clientSecret == "" {
@@ -150,6 +401,10 @@ func (handler *Handler) settingsUpdate(w http.ResponseWriter, r *http.Request) *
 		settings.EdgeAgentCheckinInterval = *payload.EdgeAgentCheckinInterval
 	}
 
+	if payload.EdgeAgentCheckinJitter != nil {
+		settings.EdgeAgentCheckinJitter = *payload.EdgeAgentCheckinJitter
+	}
+
 	if payload.UserSessionTimeout != nil {
 		settings.UserSessionTimeout = *payload.UserSessionTimeout
 
@@ -166,6 +421,83 @@ func (handler *Handler) settingsUpdate(w http.ResponseWriter, r *http.Request) *
 		settings.EnableTelemetry = *payload.EnableTelemetry
 	}
 
+	if payload.EnableUserDirectoryReadThrough != nil {
+		settings.EnableUserDirectoryReadThrough = *payload.EnableUserDirectoryReadThrough
+	}
+
+	if payload.PasswordPolicy != nil {
+		settings.PasswordPolicy = *payload.PasswordPolicy
+	}
+
+	if payload.EnableProxyRequestLogging != nil {
+		settings.EnableProxyRequestLogging = *payload.EnableProxyRequestLogging
+	}
+
+	if payload.EnableEdgeWaitingRoom != nil {
+		settings.EnableEdgeWaitingRoom = *payload.EnableEdgeWaitingRoom
+	}
+
+	if payload.EdgeTunnelServerAddress != nil {
+		settings.EdgeTunnelServerAddress = *payload.EdgeTunnelServerAddress
+	}
+
+	if payload.EdgeTunnelZones != nil {
+		settings.EdgeTunnelZones = payload.EdgeTunnelZones
+	}
+
+	if payload.EdgeTunnel != nil {
+		settings.EdgeTunnel = *payload.EdgeTunnel
+	}
+
+	if payload.UserActivity != nil {
+		settings.UserActivity = *payload.UserActivity
+	}
+
+	if payload.Retention != nil {
+		settings.Retention = *payload.Retention
+	}
+
+	if payload.HostBrowser != nil {
+		settings.HostBrowser = *payload.HostBrowser
+	}
+
+	if payload.DefaultResourceOwnershipPolicy != nil {
+		settings.DefaultResourceOwnershipPolicy = portainer.ResourceOwnershipPolicy(*payload.DefaultResourceOwnershipPolicy)
+	}
+
+	if payload.ContainerAlerting != nil {
+		settings.ContainerAlerting = *payload.ContainerAlerting
+		if settings.ContainerAlerting.Silences == nil {
+			settings.ContainerAlerting.Silences = make([]portainer.AlertingSilence, 0)
+		}
+	}
+
+	if payload.HostAlerting != nil {
+		settings.HostAlerting = *payload.HostAlerting
+	}
+
+	if payload.ContainerLabelPolicy != nil {
+		settings.ContainerLabelPolicy = *payload.ContainerLabelPolicy
+		if settings.ContainerLabelPolicy.Labels == nil {
+			settings.ContainerLabelPolicy.Labels = make([]portainer.Pair, 0)
+		}
+		if settings.ContainerLabelPolicy.RequiredLabels == nil {
+			settings.ContainerLabelPolicy.RequiredLabels = make([]string, 0)
+		}
+	}
+
+	if payload.Branding != nil {
+		settings.Branding = *payload.Branding
+	}
+
+	if payload.DefaultLocale != nil {
+		settings.DefaultLocale = *payload.DefaultLocale
+	}
+
+	if payload.Telemetry != nil {
+		settings.Telemetry = *payload.Telemetry
+	}
+
 	tlsError := handler.updateTLS(settings)
 	if tlsError != nil {
 		return tlsError
@@ -176,6 +508,12 @@ func (handler *Handler) settingsUpdate(w http.ResponseWriter, r *http.Request) *
 		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist settings changes inside the database", err}
 	}
 
+	if settings.Telemetry.Mode != before.Telemetry.Mode {
+		handler.TelemetryService.SetMode(settings.Telemetry.Mode)
+	}
+
+	handler.recordSettingsChange(r, &before, settings, 0)
+
 	return response.JSON(w, settings)
 }
 
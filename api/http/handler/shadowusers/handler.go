@@ -0,0 +1,100 @@
+package shadowusers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+var (
+	errReadThroughDisabled = errors.New("User directory read-through mode is not enabled or the authentication method is not LDAP or OAuth")
+	errUserAlreadyExists   = errors.New("User already exists")
+)
+
+// Handler is the HTTP handler used to handle shadow user operations.
+type Handler struct {
+	*mux.Router
+	DataStore   portainer.DataStore
+	LDAPService portainer.LDAPService
+}
+
+// NewHandler creates a handler to manage shadow user operations.
+func NewHandler(bouncer *security.RequestBouncer) *Handler {
+	h := &Handler{
+		Router: mux.NewRouter(),
+	}
+	h.Handle("/shadow_users",
+		bouncer.InstanceAdminAccess(httperror.LoggerHandler(h.shadowUserCreate))).Methods(http.MethodPost)
+	h.Handle("/shadow_users",
+		bouncer.InstanceAdminAccess(httperror.LoggerHandler(h.shadowUserList))).Methods(http.MethodGet)
+	h.Handle("/shadow_users/directory",
+		bouncer.InstanceAdminAccess(httperror.LoggerHandler(h.shadowUserDirectorySearch))).Methods(http.MethodGet)
+	h.Handle("/shadow_users/{id}",
+		bouncer.InstanceAdminAccess(httperror.LoggerHandler(h.shadowUserInspect))).Methods(http.MethodGet)
+	h.Handle("/shadow_users/{id}",
+		bouncer.InstanceAdminAccess(httperror.LoggerHandler(h.shadowUserUpdate))).Methods(http.MethodPut)
+	h.Handle("/shadow_users/{id}",
+		bouncer.InstanceAdminAccess(httperror.LoggerHandler(h.shadowUserDelete))).Methods(http.MethodDelete)
+
+	return h
+}
+
+// readThroughSettings returns the settings when the user directory read-through mode can be used
+func (handler *Handler) readThroughSettings() (*portainer.Settings, *httperror.HandlerError) {
+	settings, err := handler.DataStore.Settings().Settings()
+	if err != nil {
+		return nil, &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve settings from the database", err}
+	}
+
+	if !settings.EnableUserDirectoryReadThrough || (settings.AuthenticationMethod != portainer.AuthenticationLDAP && settings.AuthenticationMethod != portainer.AuthenticationOAuth) {
+		return nil, &httperror.HandlerError{http.StatusForbidden, "User directory read-through mode is not enabled", errReadThroughDisabled}
+	}
+
+	return settings, nil
+}
+
+// validateGrants ensures that the endpoints, endpoint groups and teams referenced by a shadow user exist
+func (handler *Handler) validateGrants(shadowUser *portainer.ShadowUser) *httperror.HandlerError {
+	for endpointID := range shadowUser.EndpointAccesses {
+		_, err := handler.DataStore.Endpoint().Endpoint(endpointID)
+		if err == bolterrors.ErrObjectNotFound {
+			return &httperror.HandlerError{http.StatusBadRequest, "Unable to find an endpoint with the specified identifier inside the database", err}
+		} else if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find an endpoint with the specified identifier inside the database", err}
+		}
+	}
+
+	for endpointGroupID := range shadowUser.EndpointGroupAccesses {
+		_, err := handler.DataStore.EndpointGroup().EndpointGroup(endpointGroupID)
+		if err == bolterrors.ErrObjectNotFound {
+			return &httperror.HandlerError{http.StatusBadRequest, "Unable to find an endpoint group with the specified identifier inside the database", err}
+		} else if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find an endpoint group with the specified identifier inside the database", err}
+		}
+	}
+
+	for _, membership := range shadowUser.TeamMemberships {
+		_, err := handler.DataStore.Team().Team(membership.TeamID)
+		if err == bolterrors.ErrObjectNotFound {
+			return &httperror.HandlerError{http.StatusBadRequest, "Unable to find a team with the specified identifier inside the database", err}
+		} else if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a team with the specified identifier inside the database", err}
+		}
+	}
+
+	return nil
+}
+
+func validateTeamMemberships(memberships []portainer.ShadowUserTeamMembership) error {
+	for _, membership := range memberships {
+		if membership.Role != portainer.TeamLeader && membership.Role != portainer.TeamMember {
+			return errors.New("Invalid team membership role value. Value must be one of: 1 (leader) or 2 (member)")
+		}
+	}
+	return nil
+}
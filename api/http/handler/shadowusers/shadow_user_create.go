@@ -0,0 +1,108 @@
+package shadowusers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/asaskevich/govalidator"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+type shadowUserCreatePayload struct {
+	Username              string
+	Role                  int
+	EndpointAccesses      map[portainer.EndpointID]portainer.AccessPolicy
+	EndpointGroupAccesses map[portainer.EndpointGroupID]portainer.AccessPolicy
+	TeamMemberships       []portainer.ShadowUserTeamMembership
+}
+
+func (payload *shadowUserCreatePayload) Validate(r *http.Request) error {
+	if govalidator.IsNull(payload.Username) || govalidator.Contains(payload.Username, " ") {
+		return errors.New("Invalid username. Must not contain any whitespace")
+	}
+
+	if payload.Role == 0 {
+		payload.Role = int(portainer.StandardUserRole)
+	}
+	if payload.Role != 1 && payload.Role != 2 {
+		return errors.New("Invalid role value. Value must be one of: 1 (administrator) or 2 (regular user)")
+	}
+
+	return validateTeamMemberships(payload.TeamMemberships)
+}
+
+// POST request on /api/shadow_users
+//
+// A shadow user pre-grants accesses and team memberships to a LDAP/OAuth user that has not logged in yet.
+// The user is created with these grants on its first login and the shadow user is removed.
+func (handler *Handler) shadowUserCreate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	var payload shadowUserCreatePayload
+	err := request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	_, handlerErr := handler.readThroughSettings()
+	if handlerErr != nil {
+		return handlerErr
+	}
+
+	tokenData, err := security.RetrieveTokenData(r)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve user authentication token", err}
+	}
+
+	user, err := handler.DataStore.User().UserByUsername(payload.Username)
+	if err != nil && err != bolterrors.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve users from the database", err}
+	}
+	if user != nil {
+		return &httperror.HandlerError{http.StatusConflict, "A user with the same username already exists", errUserAlreadyExists}
+	}
+
+	shadowUser, err := handler.DataStore.ShadowUser().ShadowUserByUsername(payload.Username)
+	if err != nil && err != bolterrors.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve shadow users from the database", err}
+	}
+	if shadowUser != nil {
+		return &httperror.HandlerError{http.StatusConflict, "A shadow user with the same username already exists", errUserAlreadyExists}
+	}
+
+	shadowUser = &portainer.ShadowUser{
+		Username:              payload.Username,
+		Role:                  portainer.UserRole(payload.Role),
+		EndpointAccesses:      payload.EndpointAccesses,
+		EndpointGroupAccesses: payload.EndpointGroupAccesses,
+		TeamMemberships:       payload.TeamMemberships,
+		CreatedBy:             tokenData.Username,
+		CreationDate:          time.Now().Unix(),
+	}
+
+	if shadowUser.EndpointAccesses == nil {
+		shadowUser.EndpointAccesses = map[portainer.EndpointID]portainer.AccessPolicy{}
+	}
+	if shadowUser.EndpointGroupAccesses == nil {
+		shadowUser.EndpointGroupAccesses = map[portainer.EndpointGroupID]portainer.AccessPolicy{}
+	}
+	if shadowUser.TeamMemberships == nil {
+		shadowUser.TeamMemberships = []portainer.ShadowUserTeamMembership{}
+	}
+
+	handlerErr = handler.validateGrants(shadowUser)
+	if handlerErr != nil {
+		return handlerErr
+	}
+
+	err = handler.DataStore.ShadowUser().CreateShadowUser(shadowUser)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist the shadow user inside the database", err}
+	}
+
+	return response.JSON(w, shadowUser)
+}
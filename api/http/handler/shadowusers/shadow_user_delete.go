@@ -0,0 +1,33 @@
+package shadowusers
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+)
+
+// DELETE request on /api/shadow_users/:id
+func (handler *Handler) shadowUserDelete(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	shadowUserID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid shadow user identifier route variable", err}
+	}
+
+	_, err = handler.DataStore.ShadowUser().ShadowUser(portainer.ShadowUserID(shadowUserID))
+	if err == bolterrors.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find a shadow user with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a shadow user with the specified identifier inside the database", err}
+	}
+
+	err = handler.DataStore.ShadowUser().DeleteShadowUser(portainer.ShadowUserID(shadowUserID))
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to remove the shadow user from the database", err}
+	}
+
+	return response.Empty(w)
+}
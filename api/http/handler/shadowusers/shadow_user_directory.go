@@ -0,0 +1,102 @@
+package shadowusers
+
+import (
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+)
+
+const (
+	directorySearchDefaultLimit = 20
+	directorySearchMaxLimit     = 100
+
+	directoryEntrySourceUser      = "user"
+	directoryEntrySourceShadow    = "shadow"
+	directoryEntrySourceDirectory = "directory"
+)
+
+type directoryEntry struct {
+	Username string `json:"Username"`
+	// Source is user for existing users, shadow for shadow users and directory for
+	// LDAP users that can be granted access through a shadow user
+	Source       string                 `json:"Source"`
+	UserID       portainer.UserID       `json:"UserId,omitempty"`
+	ShadowUserID portainer.ShadowUserID `json:"ShadowUserId,omitempty"`
+}
+
+// GET request on /api/shadow_users/directory?search=<search>&limit=<limit>
+//
+// Search for principals matching the search query among the users, the shadow users and,
+// when using LDAP authentication, the users of the LDAP directory.
+// OAuth providers cannot be searched, a shadow user must be created with the exact username.
+func (handler *Handler) shadowUserDirectorySearch(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	search, _ := request.RetrieveQueryParameter(r, "search", true)
+	search = strings.TrimSpace(search)
+
+	limit, _ := request.RetrieveNumericQueryParameter(r, "limit", true)
+	if limit <= 0 || limit > directorySearchMaxLimit {
+		limit = directorySearchDefaultLimit
+	}
+
+	settings, handlerErr := handler.readThroughSettings()
+	if handlerErr != nil {
+		return handlerErr
+	}
+
+	users, err := handler.DataStore.User().Users()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve users from the database", err}
+	}
+
+	shadowUsers, err := handler.DataStore.ShadowUser().ShadowUsers()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve shadow users from the database", err}
+	}
+
+	entries := make([]directoryEntry, 0)
+	known := make(map[string]bool)
+	query := strings.ToLower(search)
+
+	for _, user := range users {
+		if strings.Contains(strings.ToLower(user.Username), query) {
+			entries = append(entries, directoryEntry{Username: user.Username, Source: directoryEntrySourceUser, UserID: user.ID})
+		}
+		known[strings.ToLower(user.Username)] = true
+	}
+
+	for _, shadowUser := range shadowUsers {
+		if strings.Contains(strings.ToLower(shadowUser.Username), query) {
+			entries = append(entries, directoryEntry{Username: shadowUser.Username, Source: directoryEntrySourceShadow, ShadowUserID: shadowUser.ID})
+		}
+		known[strings.ToLower(shadowUser.Username)] = true
+	}
+
+	if settings.AuthenticationMethod == portainer.AuthenticationLDAP && search != "" {
+		usernames, err := handler.LDAPService.SearchUsers(search, limit, &settings.LDAPSettings)
+		if err != nil {
+			log.Printf("[WARN] [http,shadowusers] [message: unable to search the LDAP directory] [error: %s]", err)
+		}
+
+		for _, username := range usernames {
+			if !known[strings.ToLower(username)] {
+				entries = append(entries, directoryEntry{Username: username, Source: directoryEntrySourceDirectory})
+			}
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return strings.ToLower(entries[i].Username) < strings.ToLower(entries[j].Username)
+	})
+
+	if len(entries) > limit {
+		entries = entries[:limit]
+	}
+
+	return response.JSON(w, entries)
+}
@@ -0,0 +1,28 @@
+package shadowusers
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+)
+
+// GET request on /api/shadow_users/:id
+func (handler *Handler) shadowUserInspect(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	shadowUserID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid shadow user identifier route variable", err}
+	}
+
+	shadowUser, err := handler.DataStore.ShadowUser().ShadowUser(portainer.ShadowUserID(shadowUserID))
+	if err == bolterrors.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find a shadow user with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a shadow user with the specified identifier inside the database", err}
+	}
+
+	return response.JSON(w, shadowUser)
+}
@@ -0,0 +1,18 @@
+package shadowusers
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/response"
+)
+
+// GET request on /api/shadow_users
+func (handler *Handler) shadowUserList(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	shadowUsers, err := handler.DataStore.ShadowUser().ShadowUsers()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve shadow users from the database", err}
+	}
+
+	return response.JSON(w, shadowUsers)
+}
@@ -0,0 +1,76 @@
+package shadowusers
+
+import (
+	"errors"
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+)
+
+type shadowUserUpdatePayload struct {
+	Role                  int
+	EndpointAccesses      map[portainer.EndpointID]portainer.AccessPolicy
+	EndpointGroupAccesses map[portainer.EndpointGroupID]portainer.AccessPolicy
+	TeamMemberships       []portainer.ShadowUserTeamMembership
+}
+
+func (payload *shadowUserUpdatePayload) Validate(r *http.Request) error {
+	if payload.Role != 0 && payload.Role != 1 && payload.Role != 2 {
+		return errors.New("Invalid role value. Value must be one of: 1 (administrator) or 2 (regular user)")
+	}
+
+	return validateTeamMemberships(payload.TeamMemberships)
+}
+
+// PUT request on /api/shadow_users/:id
+func (handler *Handler) shadowUserUpdate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	shadowUserID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid shadow user identifier route variable", err}
+	}
+
+	var payload shadowUserUpdatePayload
+	err = request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	shadowUser, err := handler.DataStore.ShadowUser().ShadowUser(portainer.ShadowUserID(shadowUserID))
+	if err == bolterrors.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find a shadow user with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a shadow user with the specified identifier inside the database", err}
+	}
+
+	if payload.Role != 0 {
+		shadowUser.Role = portainer.UserRole(payload.Role)
+	}
+
+	if payload.EndpointAccesses != nil {
+		shadowUser.EndpointAccesses = payload.EndpointAccesses
+	}
+
+	if payload.EndpointGroupAccesses != nil {
+		shadowUser.EndpointGroupAccesses = payload.EndpointGroupAccesses
+	}
+
+	if payload.TeamMemberships != nil {
+		shadowUser.TeamMemberships = payload.TeamMemberships
+	}
+
+	handlerErr := handler.validateGrants(shadowUser)
+	if handlerErr != nil {
+		return handlerErr
+	}
+
+	err = handler.DataStore.ShadowUser().UpdateShadowUser(shadowUser.ID, shadowUser)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist shadow user changes inside the database", err}
+	}
+
+	return response.JSON(w, shadowUser)
+}
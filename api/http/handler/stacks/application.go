@@ -0,0 +1,317 @@
+package stacks
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+	"github.com/portainer/portainer/api/filesystem"
+	"github.com/portainer/portainer/api/internal/stackfile"
+	"github.com/portainer/portainer/api/internal/task"
+)
+
+var (
+	errApplicationDeploying   = errors.New("A deployment of the application is in progress")
+	errApplicationComponent   = errors.New("The stack is a component of an application, it can only be removed with the application")
+	errApplicationAlreadyUsed = errors.New("An application already exists with this name")
+)
+
+// applicationStatus represents the combined deployment status of the components of an application
+type applicationStatus struct {
+	Total    int `json:"Total"`
+	Pending  int `json:"Pending"`
+	Deployed int `json:"Deployed"`
+	Failed   int `json:"Failed"`
+	Skipped  int `json:"Skipped"`
+}
+
+type applicationResponse struct {
+	*portainer.Application
+	Status applicationStatus `json:"Status"`
+}
+
+func newApplicationResponse(application *portainer.Application) *applicationResponse {
+	status := applicationStatus{Total: len(application.Components)}
+	for _, component := range application.Components {
+		switch component.Status {
+		case portainer.ApplicationComponentDeployed:
+			status.Deployed++
+		case portainer.ApplicationComponentFailed:
+			status.Failed++
+		case portainer.ApplicationComponentSkipped:
+			status.Skipped++
+		default:
+			status.Pending++
+		}
+	}
+
+	return &applicationResponse{Application: application, Status: status}
+}
+
+// applicationStackName returns the name of the stack created for a component of an application
+func applicationStackName(applicationName string, component *portainer.ApplicationComponent) string {
+	if component.Type == portainer.DockerComposeStack {
+		return normalizeStackName(applicationName + component.Name)
+	}
+	return applicationName + "_" + component.Name
+}
+
+// isApplicationDeploying returns true while the deployment task of the application is not finished
+func (handler *Handler) isApplicationDeploying(application *portainer.Application) (bool, error) {
+	if application.TaskID == 0 {
+		return false, nil
+	}
+
+	deployment, err := handler.DataStore.Task().Task(application.TaskID)
+	if err == bolterrors.ErrObjectNotFound {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	return !task.IsFinished(deployment), nil
+}
+
+// authorizedApplicationEndpoint ensures that the user can access the endpoint the application is deployed on
+func (handler *Handler) authorizedApplicationEndpoint(r *http.Request, application *portainer.Application) *httperror.HandlerError {
+	endpoint, err := handler.DataStore.Endpoint().Endpoint(application.EndpointID)
+	if err == bolterrors.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find the endpoint associated to the application inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find the endpoint associated to the application inside the database", err}
+	}
+
+	err = handler.requestBouncer.AuthorizedEndpointOperation(r, endpoint)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusForbidden, "Permission denied to access endpoint", err}
+	}
+
+	return nil
+}
+
+// stackApplication returns the application the stack is a component of, nil is returned when the stack
+// does not belong to an application
+func (handler *Handler) stackApplication(stackID portainer.StackID) (*portainer.Application, error) {
+	applications, err := handler.DataStore.Application().Applications()
+	if err != nil {
+		return nil, err
+	}
+
+	for idx := range applications {
+		for _, component := range applications[idx].Components {
+			if component.StackID == stackID {
+				return &applications[idx], nil
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// deployApplication starts a task deploying the components of the application one after the other.
+// The deployment stops at the first failure, the following components are marked as skipped.
+// Each deployed component is persisted as a regular stack owned by the user.
+func (handler *Handler) deployApplication(application *portainer.Application, stackFiles []string, userID portainer.UserID) error {
+	dockerhub, err := handler.DataStore.DockerHub().DockerHub()
+	if err != nil {
+		return err
+	}
+
+	registries, err := handler.DataStore.Registry().Registries()
+	if err != nil {
+		return err
+	}
+
+	endpoint, err := handler.DataStore.Endpoint().Endpoint(application.EndpointID)
+	if err != nil {
+		return err
+	}
+
+	state := *application
+	state.Components = make([]portainer.ApplicationComponent, len(application.Components))
+	copy(state.Components, application.Components)
+
+	deployment := &portainer.Task{
+		Type:       portainer.ApplicationDeployTask,
+		UserID:     userID,
+		ResourceID: application.Name,
+		Progress:   0,
+	}
+
+	handler.applicationMutex.Lock()
+	defer handler.applicationMutex.Unlock()
+
+	err = handler.TaskManager.Run(deployment, func(reporter portainer.TaskReporter) error {
+		var deployErr error
+
+		for idx := range state.Components {
+			component := &state.Components[idx]
+
+			if deployErr != nil {
+				handler.updateApplicationComponent(state.ID, component.Name, portainer.ApplicationComponentSkipped, 0, nil)
+				continue
+			}
+
+			reporter.SetProgress(idx*100/len(state.Components), fmt.Sprintf("Deploying component %s", component.Name))
+
+			stackID, err := handler.deployApplicationComponent(&state, component, stackFiles[idx], endpoint, dockerhub, registries, userID)
+			if err != nil {
+				deployErr = fmt.Errorf("deployment of component %s failed: %s", component.Name, err)
+				reporter.Log(deployErr.Error())
+				handler.updateApplicationComponent(state.ID, component.Name, portainer.ApplicationComponentFailed, stackID, err)
+				continue
+			}
+
+			reporter.Log(fmt.Sprintf("Component %s deployed", component.Name))
+			handler.updateApplicationComponent(state.ID, component.Name, portainer.ApplicationComponentDeployed, stackID, nil)
+		}
+
+		if deployErr == nil {
+			reporter.SetProgress(100, "Application deployed")
+		}
+		return deployErr
+	})
+	if err != nil {
+		return err
+	}
+
+	application.TaskID = deployment.ID
+	return handler.DataStore.Application().UpdateApplication(application.ID, application)
+}
+
+func (handler *Handler) deployApplicationComponent(application *portainer.Application, component *portainer.ApplicationComponent, stackFile string, endpoint *portainer.Endpoint, dockerhub *portainer.DockerHub, registries []portainer.Registry, userID portainer.UserID) (portainer.StackID, error) {
+	handler.stackCreationMutex.Lock()
+	defer handler.stackCreationMutex.Unlock()
+
+	stack := &portainer.Stack{
+		ID:         portainer.StackID(handler.DataStore.Stack().GetNextIdentifier()),
+		Name:       applicationStackName(application.Name, component),
+		Type:       component.Type,
+		EndpointID: endpoint.ID,
+		EntryPoint: filesystem.ComposeFileDefaultName,
+		Env:        stackfile.MergeEnv(application.Env, component.Env),
+		Status:     portainer.StackStatusActive,
+	}
+
+	if stack.Type == portainer.DockerSwarmStack {
+		stack.SwarmID = application.SwarmID
+	}
+
+	projectPath, err := handler.FileService.StoreStackFileFromBytes(strconv.Itoa(int(stack.ID)), stack.EntryPoint, []byte(stackFile))
+	if err != nil {
+		return 0, err
+	}
+	stack.ProjectPath = projectPath
+
+	handler.SwarmStackManager.Login(dockerhub, registries, endpoint)
+
+	if stack.Type == portainer.DockerSwarmStack {
+		err = handler.SwarmStackManager.Deploy(stack, false, endpoint)
+	} else {
+		err = handler.ComposeStackManager.Up(stack, endpoint)
+	}
+
+	handler.SwarmStackManager.Logout(endpoint)
+
+	if err != nil {
+		handler.FileService.RemoveDirectory(stack.ProjectPath)
+		return 0, err
+	}
+
+	stack.DeploymentDate = time.Now().Unix()
+	err = handler.DataStore.Stack().CreateStack(stack)
+	if err != nil {
+		return 0, err
+	}
+
+	resourceControl, err := handler.newStackResourceControl(stack, userID)
+	if err != nil {
+		return stack.ID, err
+	}
+
+	err = handler.syncStackResourceControl(stack, endpoint, resourceControl)
+	if err != nil {
+		log.Printf("[WARN] [http,stacks] [message: unable to propagate the stack resource control to the application resources] [error: %s]", err)
+	}
+
+	return stack.ID, handler.DataStore.ResourceControl().CreateResourceControl(resourceControl)
+}
+
+// removeApplicationComponent removes the stack of a component from the endpoint, the database and the disk
+func (handler *Handler) removeApplicationComponent(component *portainer.ApplicationComponent, endpoint *portainer.Endpoint) error {
+	if component.StackID == 0 {
+		return nil
+	}
+
+	stack, err := handler.DataStore.Stack().Stack(component.StackID)
+	if err == bolterrors.ErrObjectNotFound {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	err = handler.deleteStack(stack, endpoint)
+	if err != nil {
+		return err
+	}
+
+	err = handler.DataStore.Stack().DeleteStack(stack.ID)
+	if err != nil {
+		return err
+	}
+
+	resourceControl, err := handler.DataStore.ResourceControl().ResourceControlByResourceIDAndType(stack.Name, portainer.StackResourceControl)
+	if err != nil {
+		return err
+	}
+	if resourceControl != nil {
+		err = handler.DataStore.ResourceControl().DeleteResourceControl(resourceControl.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = handler.FileService.RemoveDirectory(stack.ProjectPath)
+	if err != nil {
+		return err
+	}
+
+	return handler.FileService.DeleteStackEnvFiles(strconv.Itoa(int(stack.ID)))
+}
+
+// updateApplicationComponent persists the outcome of the deployment of a component
+func (handler *Handler) updateApplicationComponent(applicationID portainer.ApplicationID, name string, status portainer.ApplicationComponentStatus, stackID portainer.StackID, deployErr error) {
+	handler.applicationMutex.Lock()
+	defer handler.applicationMutex.Unlock()
+
+	application, err := handler.DataStore.Application().Application(applicationID)
+	if err != nil {
+		log.Printf("[WARN] [http,stacks] [message: unable to retrieve application] [error: %s]", err)
+		return
+	}
+
+	for idx := range application.Components {
+		component := &application.Components[idx]
+		if component.Name != name {
+			continue
+		}
+
+		component.Status = status
+		component.StackID = stackID
+		component.Error = ""
+		if deployErr != nil {
+			component.Error = deployErr.Error()
+		}
+	}
+
+	err = handler.DataStore.Application().UpdateApplication(application.ID, application)
+	if err != nil {
+		log.Printf("[WARN] [http,stacks] [message: unable to persist application] [error: %s]", err)
+	}
+}
@@ -0,0 +1,161 @@
+package stacks
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/asaskevich/govalidator"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+type applicationComponentPayload struct {
+	Name             string
+	Type             portainer.StackType
+	StackFileContent string
+	Env              []portainer.Pair
+}
+
+type applicationCreatePayload struct {
+	Name       string
+	EndpointID portainer.EndpointID
+	// SwarmID is required when one of the components is a Swarm stack
+	SwarmID string
+	Env     []portainer.Pair
+	// Components are deployed in the order of the list
+	Components []applicationComponentPayload
+}
+
+func (payload *applicationCreatePayload) Validate(r *http.Request) error {
+	if govalidator.IsNull(payload.Name) {
+		return errors.New("Invalid application name")
+	}
+	payload.Name = normalizeStackName(payload.Name)
+	if payload.EndpointID == 0 {
+		return errors.New("Invalid endpoint identifier")
+	}
+	if len(payload.Components) == 0 {
+		return errors.New("Invalid components. At least one component must be specified")
+	}
+
+	known := make(map[string]bool)
+	for idx := range payload.Components {
+		component := &payload.Components[idx]
+		component.Name = normalizeStackName(component.Name)
+		if component.Name == "" {
+			return errors.New("Invalid component name")
+		}
+		if known[component.Name] {
+			return errors.New("Invalid components. Each component name must be unique")
+		}
+		known[component.Name] = true
+
+		if component.Type != portainer.DockerSwarmStack && component.Type != portainer.DockerComposeStack {
+			return errors.New("Invalid component type. Value must be one of: 1 (Swarm stack) or 2 (Compose stack)")
+		}
+		if component.Type == portainer.DockerSwarmStack && govalidator.IsNull(payload.SwarmID) {
+			return errors.New("Invalid Swarm ID. It is required by the Swarm stack components")
+		}
+		if govalidator.IsNull(component.StackFileContent) {
+			return errors.New("Invalid stack file content of component " + component.Name)
+		}
+	}
+
+	return nil
+}
+
+// POST request on /api/applications
+// The components are deployed in the background, one after the other. Each component is deployed
+// as a stack named after the application and the component, with the variables of the application
+// overridden by the variables of the component.
+func (handler *Handler) applicationCreate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	var payload applicationCreatePayload
+	err := request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	securityContext, err := security.RetrieveRestrictedRequestContext(r)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve info from request context", err}
+	}
+
+	endpoint, err := handler.DataStore.Endpoint().Endpoint(payload.EndpointID)
+	if err == bolterrors.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find an endpoint with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find an endpoint with the specified identifier inside the database", err}
+	}
+
+	err = handler.requestBouncer.AuthorizedEndpointOperation(r, endpoint)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusForbidden, "Permission denied to access endpoint", err}
+	}
+
+	if endpoint.Type != portainer.DockerEnvironment && endpoint.Type != portainer.AgentOnDockerEnvironment {
+		return &httperror.HandlerError{http.StatusBadRequest, "Applications can only be deployed on Docker endpoints", errInvalidTargetEndpoint}
+	}
+
+	handlerErr := handler.checkStackQuota(endpoint.OrganizationID, len(payload.Components))
+	if handlerErr != nil {
+		return handlerErr
+	}
+
+	applications, err := handler.DataStore.Application().Applications()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve applications from the database", err}
+	}
+
+	for _, application := range applications {
+		if strings.EqualFold(application.Name, payload.Name) {
+			return &httperror.HandlerError{http.StatusConflict, "An application with this name already exists", errApplicationAlreadyUsed}
+		}
+	}
+
+	application := &portainer.Application{
+		ID:           portainer.ApplicationID(handler.DataStore.Application().GetNextIdentifier()),
+		Name:         payload.Name,
+		EndpointID:   endpoint.ID,
+		SwarmID:      payload.SwarmID,
+		Env:          payload.Env,
+		Components:   make([]portainer.ApplicationComponent, 0, len(payload.Components)),
+		CreatedBy:    securityContext.UserID,
+		CreationDate: time.Now().Unix(),
+	}
+
+	stackFiles := make([]string, 0, len(payload.Components))
+	for _, componentPayload := range payload.Components {
+		component := portainer.ApplicationComponent{
+			Name:   componentPayload.Name,
+			Type:   componentPayload.Type,
+			Env:    componentPayload.Env,
+			Status: portainer.ApplicationComponentPending,
+		}
+
+		handlerErr = handler.checkUniqueMultiEndpointStackName(applicationStackName(application.Name, &component))
+		if handlerErr != nil {
+			return handlerErr
+		}
+
+		application.Components = append(application.Components, component)
+		stackFiles = append(stackFiles, componentPayload.StackFileContent)
+	}
+
+	err = handler.DataStore.Application().CreateApplication(application)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist the application inside the database", err}
+	}
+
+	err = handler.deployApplication(application, stackFiles, securityContext.UserID)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to start the application deployment task", err}
+	}
+
+	return response.JSON(w, newApplicationResponse(application))
+}
@@ -0,0 +1,71 @@
+package stacks
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+)
+
+// DELETE request on /api/applications/:id
+// The components are removed in the reverse order of their deployment. The application is kept when
+// a component cannot be removed so that the teardown can be retried.
+func (handler *Handler) applicationDelete(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	applicationID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid application identifier route variable", err}
+	}
+
+	application, err := handler.DataStore.Application().Application(portainer.ApplicationID(applicationID))
+	if err == bolterrors.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find an application with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find an application with the specified identifier inside the database", err}
+	}
+
+	handlerErr := handler.authorizedApplicationEndpoint(r, application)
+	if handlerErr != nil {
+		return handlerErr
+	}
+
+	deploying, err := handler.isApplicationDeploying(application)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve the deployment task of the application", err}
+	}
+	if deploying {
+		return &httperror.HandlerError{http.StatusConflict, "A deployment of the application is in progress", errApplicationDeploying}
+	}
+
+	endpoint, err := handler.DataStore.Endpoint().Endpoint(application.EndpointID)
+	if err == bolterrors.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find the endpoint associated to the application inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find the endpoint associated to the application inside the database", err}
+	}
+
+	for idx := len(application.Components) - 1; idx >= 0; idx-- {
+		component := &application.Components[idx]
+
+		err := handler.removeApplicationComponent(component, endpoint)
+		if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to remove the component " + component.Name + " of the application", err}
+		}
+
+		component.StackID = 0
+		component.Status = portainer.ApplicationComponentPending
+		err = handler.DataStore.Application().UpdateApplication(application.ID, application)
+		if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist the application changes inside the database", err}
+		}
+	}
+
+	err = handler.DataStore.Application().DeleteApplication(application.ID)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to remove the application from the database", err}
+	}
+
+	return response.Empty(w)
+}
@@ -0,0 +1,33 @@
+package stacks
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+)
+
+// GET request on /api/applications/:id
+func (handler *Handler) applicationInspect(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	applicationID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid application identifier route variable", err}
+	}
+
+	application, err := handler.DataStore.Application().Application(portainer.ApplicationID(applicationID))
+	if err == bolterrors.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find an application with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find an application with the specified identifier inside the database", err}
+	}
+
+	handlerErr := handler.authorizedApplicationEndpoint(r, application)
+	if handlerErr != nil {
+		return handlerErr
+	}
+
+	return response.JSON(w, newApplicationResponse(application))
+}
@@ -0,0 +1,45 @@
+package stacks
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+// GET request on /api/applications
+func (handler *Handler) applicationList(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	securityContext, err := security.RetrieveRestrictedRequestContext(r)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve info from request context", err}
+	}
+
+	applications, err := handler.DataStore.Application().Applications()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve applications from the database", err}
+	}
+
+	endpoints, err := handler.DataStore.Endpoint().Endpoints()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve endpoints from the database", err}
+	}
+
+	endpointOrganizations := make(map[portainer.EndpointID]portainer.OrganizationID, len(endpoints))
+	for _, endpoint := range endpoints {
+		endpointOrganizations[endpoint.ID] = endpoint.OrganizationID
+	}
+
+	responses := make([]*applicationResponse, 0, len(applications))
+	for idx := range applications {
+		organizationID, ok := endpointOrganizations[applications[idx].EndpointID]
+		if !ok || !security.AuthorizedOrganizationAccess(organizationID, securityContext.OrganizationID, securityContext.IsAdmin) {
+			continue
+		}
+
+		responses = append(responses, newApplicationResponse(&applications[idx]))
+	}
+
+	return response.JSON(w, responses)
+}
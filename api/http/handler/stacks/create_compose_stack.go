@@ -7,6 +7,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/asaskevich/govalidator"
 	httperror "github.com/portainer/libhttp/error"
@@ -27,6 +28,7 @@ type composeStackFromFileContentPayload struct {
 	Name             string
 	StackFileContent string
 	Env              []portainer.Pair
+	CustomTemplateID portainer.CustomTemplateID
 }
 
 func (payload *composeStackFromFileContentPayload) Validate(r *http.Request) error {
@@ -60,13 +62,14 @@ func (handler *Handler) createComposeStackFromFileContent(w http.ResponseWriter,
 
 	stackID := handler.DataStore.Stack().GetNextIdentifier()
 	stack := &portainer.Stack{
-		ID:         portainer.StackID(stackID),
-		Name:       payload.Name,
-		Type:       portainer.DockerComposeStack,
-		EndpointID: endpoint.ID,
-		EntryPoint: filesystem.ComposeFileDefaultName,
-		Env:        payload.Env,
-		Status:     portainer.StackStatusActive,
+		ID:               portainer.StackID(stackID),
+		Name:             payload.Name,
+		Type:             portainer.DockerComposeStack,
+		EndpointID:       endpoint.ID,
+		EntryPoint:       filesystem.ComposeFileDefaultName,
+		Env:              payload.Env,
+		CustomTemplateID: payload.CustomTemplateID,
+		Status:           portainer.StackStatusActive,
 	}
 
 	stackFolder := strconv.Itoa(int(stack.ID))
@@ -365,9 +368,11 @@ func (handler *Handler) deployComposeStack(config *composeStackDeploymentConfig)
 	handler.SwarmStackManager.Login(config.dockerhub, config.registries, config.endpoint)
 
 	err = handler.ComposeStackManager.Up(config.stack, config.endpoint)
+	handler.notifyDeployment(config.user, config.stack, config.endpoint, err)
 	if err != nil {
 		return err
 	}
+	config.stack.DeploymentDate = time.Now().Unix()
 
 	return handler.SwarmStackManager.Logout(config.endpoint)
 }
@@ -6,6 +6,7 @@ import (
 	"path"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/asaskevich/govalidator"
 	httperror "github.com/portainer/libhttp/error"
@@ -20,6 +21,7 @@ type swarmStackFromFileContentPayload struct {
 	SwarmID          string
 	StackFileContent string
 	Env              []portainer.Pair
+	CustomTemplateID portainer.CustomTemplateID
 }
 
 func (payload *swarmStackFromFileContentPayload) Validate(r *http.Request) error {
@@ -55,14 +57,15 @@ func (handler *Handler) createSwarmStackFromFileContent(w http.ResponseWriter, r
 
 	stackID := handler.DataStore.Stack().GetNextIdentifier()
 	stack := &portainer.Stack{
-		ID:         portainer.StackID(stackID),
-		Name:       payload.Name,
-		Type:       portainer.DockerSwarmStack,
-		SwarmID:    payload.SwarmID,
-		EndpointID: endpoint.ID,
-		EntryPoint: filesystem.ComposeFileDefaultName,
-		Env:        payload.Env,
-		Status:     portainer.StackStatusActive,
+		ID:               portainer.StackID(stackID),
+		Name:             payload.Name,
+		Type:             portainer.DockerSwarmStack,
+		SwarmID:          payload.SwarmID,
+		EndpointID:       endpoint.ID,
+		EntryPoint:       filesystem.ComposeFileDefaultName,
+		Env:              payload.Env,
+		CustomTemplateID: payload.CustomTemplateID,
+		Status:           portainer.StackStatusActive,
 	}
 
 	stackFolder := strconv.Itoa(int(stack.ID))
@@ -364,9 +367,11 @@ func (handler *Handler) deploySwarmStack(config *swarmStackDeploymentConfig) err
 	handler.SwarmStackManager.Login(config.dockerhub, config.registries, config.endpoint)
 
 	err = handler.SwarmStackManager.Deploy(config.stack, config.prune, config.endpoint)
+	handler.notifyDeployment(config.user, config.stack, config.endpoint, err)
 	if err != nil {
 		return err
 	}
+	config.stack.DeploymentDate = time.Now().Unix()
 
 	err = handler.SwarmStackManager.Logout(config.endpoint)
 	if err != nil {
@@ -8,6 +8,7 @@ import (
 	"github.com/gorilla/mux"
 	httperror "github.com/portainer/libhttp/error"
 	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/docker"
 	"github.com/portainer/portainer/api/http/security"
 	"github.com/portainer/portainer/api/internal/authorization"
 )
@@ -21,7 +22,11 @@ var (
 type Handler struct {
 	stackCreationMutex *sync.Mutex
 	stackDeletionMutex *sync.Mutex
-	requestBouncer     *security.RequestBouncer
+	// multiEndpointStackMutex serializes the updates of the targets of the multi-endpoint stacks
+	multiEndpointStackMutex *sync.Mutex
+	// applicationMutex serializes the updates of the components of the applications
+	applicationMutex *sync.Mutex
+	requestBouncer   *security.RequestBouncer
 	*mux.Router
 	DataStore           portainer.DataStore
 	FileService         portainer.FileService
@@ -29,34 +34,86 @@ type Handler struct {
 	SwarmStackManager   portainer.SwarmStackManager
 	ComposeStackManager portainer.ComposeStackManager
 	KubernetesDeployer  portainer.KubernetesDeployer
+	DockerClientFactory *docker.ClientFactory
+	NotificationService portainer.NotificationService
+	TaskManager         portainer.TaskManager
 }
 
 // NewHandler creates a handler to manage stack operations.
 func NewHandler(bouncer *security.RequestBouncer) *Handler {
 	h := &Handler{
-		Router:             mux.NewRouter(),
-		stackCreationMutex: &sync.Mutex{},
-		stackDeletionMutex: &sync.Mutex{},
-		requestBouncer:     bouncer,
+		Router:                  mux.NewRouter(),
+		stackCreationMutex:      &sync.Mutex{},
+		stackDeletionMutex:      &sync.Mutex{},
+		multiEndpointStackMutex: &sync.Mutex{},
+		applicationMutex:        &sync.Mutex{},
+		requestBouncer:          bouncer,
 	}
 	h.Handle("/stacks",
-		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.stackCreate))).Methods(http.MethodPost)
+		bouncer.AuthenticatedAccess(bouncer.ChangeApproval(portainer.ChangeRequestStackDeployment, stackDeploymentEndpoint,
+			httperror.LoggerHandler(h.stackCreate)))).Methods(http.MethodPost)
 	h.Handle("/stacks",
 		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.stackList))).Methods(http.MethodGet)
+	h.Handle("/stacks/import",
+		bouncer.AuthenticatedAccess(bouncer.ChangeApproval(portainer.ChangeRequestStackDeployment, stackDeploymentEndpoint,
+			httperror.LoggerHandler(h.stackImport)))).Methods(http.MethodPost)
+	h.Handle("/stacks/validate",
+		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.stackValidate))).Methods(http.MethodPost)
 	h.Handle("/stacks/{id}",
 		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.stackInspect))).Methods(http.MethodGet)
 	h.Handle("/stacks/{id}",
-		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.stackDelete))).Methods(http.MethodDelete)
+		bouncer.AuthenticatedAccess(bouncer.ChangeApproval(portainer.ChangeRequestStackDeletion, h.stackDeletionEndpoint,
+			httperror.LoggerHandler(h.stackDelete)))).Methods(http.MethodDelete)
 	h.Handle("/stacks/{id}",
-		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.stackUpdate))).Methods(http.MethodPut)
+		bouncer.AuthenticatedAccess(bouncer.ChangeApproval(portainer.ChangeRequestStackUpdate, h.stackUpdateEndpoint,
+			httperror.LoggerHandler(h.stackUpdate)))).Methods(http.MethodPut)
 	h.Handle("/stacks/{id}/file",
 		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.stackFile))).Methods(http.MethodGet)
+	h.Handle("/stacks/{id}/resources",
+		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.stackResources))).Methods(http.MethodGet)
+	h.Handle("/stacks/{id}/export",
+		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.stackExport))).Methods(http.MethodGet)
+	h.Handle("/stacks/{id}/status",
+		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.stackStatus))).Methods(http.MethodGet)
+	h.Handle("/stacks/{id}/dependencies",
+		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.stackDependencies))).Methods(http.MethodGet)
+	h.Handle("/stacks/{id}/lifecycle/{action}",
+		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.stackLifecycle))).Methods(http.MethodPost)
+	h.Handle("/stacks/{id}/stats",
+		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.stackStats))).Methods(http.MethodGet)
+	h.Handle("/stacks/{id}/envfiles",
+		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.stackEnvFileList))).Methods(http.MethodGet)
+	h.Handle("/stacks/{id}/envfiles/{name}",
+		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.stackEnvFileUpdate))).Methods(http.MethodPut)
+	h.Handle("/stacks/{id}/envfiles/{name}",
+		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.stackEnvFileDelete))).Methods(http.MethodDelete)
 	h.Handle("/stacks/{id}/migrate",
-		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.stackMigrate))).Methods(http.MethodPost)
+		bouncer.AuthenticatedAccess(bouncer.ChangeApproval(portainer.ChangeRequestStackDeployment, payloadEndpoint,
+			httperror.LoggerHandler(h.stackMigrate)))).Methods(http.MethodPost)
 	h.Handle("/stacks/{id}/start",
-		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.stackStart))).Methods(http.MethodPost)
+		bouncer.AuthenticatedAccess(bouncer.ChangeApproval(portainer.ChangeRequestStackDeployment, h.stackEndpoint,
+			httperror.LoggerHandler(h.stackStart)))).Methods(http.MethodPost)
 	h.Handle("/stacks/{id}/stop",
 		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.stackStop))).Methods(http.MethodPost)
+	h.Handle("/applications",
+		bouncer.AdminAccess(bouncer.ChangeApproval(portainer.ChangeRequestStackDeployment, payloadEndpoint,
+			httperror.LoggerHandler(h.applicationCreate)))).Methods(http.MethodPost)
+	h.Handle("/applications",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.applicationList))).Methods(http.MethodGet)
+	h.Handle("/applications/{id}",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.applicationInspect))).Methods(http.MethodGet)
+	h.Handle("/applications/{id}",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.applicationDelete))).Methods(http.MethodDelete)
+	h.Handle("/multi_endpoint_stacks",
+		bouncer.InstanceAdminAccess(httperror.LoggerHandler(h.multiEndpointStackCreate))).Methods(http.MethodPost)
+	h.Handle("/multi_endpoint_stacks",
+		bouncer.InstanceAdminAccess(httperror.LoggerHandler(h.multiEndpointStackList))).Methods(http.MethodGet)
+	h.Handle("/multi_endpoint_stacks/{id}",
+		bouncer.InstanceAdminAccess(httperror.LoggerHandler(h.multiEndpointStackInspect))).Methods(http.MethodGet)
+	h.Handle("/multi_endpoint_stacks/{id}",
+		bouncer.InstanceAdminAccess(httperror.LoggerHandler(h.multiEndpointStackUpdate))).Methods(http.MethodPut)
+	h.Handle("/multi_endpoint_stacks/{id}",
+		bouncer.InstanceAdminAccess(httperror.LoggerHandler(h.multiEndpointStackDelete))).Methods(http.MethodDelete)
 	return h
 }
 
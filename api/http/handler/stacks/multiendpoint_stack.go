@@ -0,0 +1,315 @@
+package stacks
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+	"github.com/portainer/portainer/api/internal/stackfile"
+	"github.com/portainer/portainer/api/internal/task"
+)
+
+var (
+	errMultiEndpointStackDeploying = errors.New("A deployment of the stack is in progress")
+	errInvalidTargetEndpoint       = errors.New("Multi-endpoint stacks can only be deployed on Docker endpoints")
+	errProtectedTargetEndpoint     = errors.New("Multi-endpoint stacks cannot be deployed on protected endpoints, their changes must be approved")
+)
+
+type multiEndpointStackTargetPayload struct {
+	EndpointID portainer.EndpointID
+	Env        []portainer.Pair
+}
+
+// multiEndpointStackStatus represents the aggregated deployment status of a multi-endpoint stack
+type multiEndpointStackStatus struct {
+	Total    int `json:"Total"`
+	Pending  int `json:"Pending"`
+	Deployed int `json:"Deployed"`
+	Failed   int `json:"Failed"`
+}
+
+type multiEndpointStackResponse struct {
+	*portainer.MultiEndpointStack
+	Status multiEndpointStackStatus `json:"Status"`
+}
+
+func newMultiEndpointStackResponse(stack *portainer.MultiEndpointStack) *multiEndpointStackResponse {
+	status := multiEndpointStackStatus{Total: len(stack.Targets)}
+	for _, target := range stack.Targets {
+		switch target.Status {
+		case portainer.MultiEndpointStackTargetDeployed:
+			status.Deployed++
+		case portainer.MultiEndpointStackTargetFailed:
+			status.Failed++
+		default:
+			status.Pending++
+		}
+	}
+
+	return &multiEndpointStackResponse{MultiEndpointStack: stack, Status: status}
+}
+
+func validateMultiEndpointStackTargets(targets []multiEndpointStackTargetPayload) error {
+	if len(targets) == 0 {
+		return errors.New("Invalid targets. At least one endpoint must be specified")
+	}
+
+	known := make(map[portainer.EndpointID]bool)
+	for _, target := range targets {
+		if target.EndpointID == 0 {
+			return errors.New("Invalid target endpoint identifier")
+		}
+		if known[target.EndpointID] {
+			return errors.New("Invalid targets. Each endpoint can only be targeted once")
+		}
+		known[target.EndpointID] = true
+	}
+
+	return nil
+}
+
+// checkMultiEndpointStackTargets verifies that the targets exist and are not Edge, Kubernetes or Azure endpoints,
+// and that the stack quotas of the organizations of the new targets are not exceeded
+func (handler *Handler) checkMultiEndpointStackTargets(targets []multiEndpointStackTargetPayload, existingTargets []portainer.MultiEndpointStackTarget) *httperror.HandlerError {
+	existing := make(map[portainer.EndpointID]bool)
+	for _, target := range existingTargets {
+		existing[target.EndpointID] = true
+	}
+
+	organizationTargets := make(map[portainer.OrganizationID]int)
+	for _, target := range targets {
+		endpoint, err := handler.DataStore.Endpoint().Endpoint(target.EndpointID)
+		if err == bolterrors.ErrObjectNotFound {
+			return &httperror.HandlerError{http.StatusNotFound, "Unable to find an endpoint with the specified identifier inside the database", err}
+		} else if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find an endpoint with the specified identifier inside the database", err}
+		}
+
+		if endpoint.Type != portainer.DockerEnvironment && endpoint.Type != portainer.AgentOnDockerEnvironment {
+			return &httperror.HandlerError{http.StatusBadRequest, fmt.Sprintf("Invalid target endpoint: %s", endpoint.Name), errInvalidTargetEndpoint}
+		}
+
+		if !existing[endpoint.ID] {
+			organizationTargets[endpoint.OrganizationID]++
+		}
+	}
+
+	for organizationID, count := range organizationTargets {
+		handlerErr := handler.checkStackQuota(organizationID, count)
+		if handlerErr != nil {
+			return handlerErr
+		}
+	}
+	return nil
+}
+
+// checkUnprotectedTargets prevents the changes of a multi-endpoint stack on protected endpoints. The changes
+// of a protected endpoint are approved one endpoint at a time, a multi-endpoint stack cannot be held for approval.
+func (handler *Handler) checkUnprotectedTargets(endpointIDs []portainer.EndpointID) *httperror.HandlerError {
+	for _, endpointID := range endpointIDs {
+		endpoint, err := handler.DataStore.Endpoint().Endpoint(endpointID)
+		if err == bolterrors.ErrObjectNotFound {
+			continue
+		} else if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find an endpoint with the specified identifier inside the database", err}
+		}
+
+		if endpoint.Protected {
+			return &httperror.HandlerError{http.StatusForbidden, fmt.Sprintf("Protected target endpoint: %s", endpoint.Name), errProtectedTargetEndpoint}
+		}
+	}
+	return nil
+}
+
+// isMultiEndpointStackDeploying returns true while the last deployment task of the stack is not finished
+func (handler *Handler) isMultiEndpointStackDeploying(stack *portainer.MultiEndpointStack) (bool, error) {
+	if stack.TaskID == 0 {
+		return false, nil
+	}
+
+	deployment, err := handler.DataStore.Task().Task(stack.TaskID)
+	if err == bolterrors.ErrObjectNotFound {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	return !task.IsFinished(deployment), nil
+}
+
+// deployMultiEndpointStack starts a task deploying the stack on all its targets at the same time and
+// removing it from the endpoints that are no longer targeted. The status of each target is persisted
+// as soon as its deployment completes.
+func (handler *Handler) deployMultiEndpointStack(stack *portainer.MultiEndpointStack, removedTargets []portainer.MultiEndpointStackTarget, userID portainer.UserID, prune bool) error {
+	dockerhub, err := handler.DataStore.DockerHub().DockerHub()
+	if err != nil {
+		return err
+	}
+
+	registries, err := handler.DataStore.Registry().Registries()
+	if err != nil {
+		return err
+	}
+
+	for idx := range stack.Targets {
+		stack.Targets[idx].Status = portainer.MultiEndpointStackTargetPending
+		stack.Targets[idx].Error = ""
+	}
+
+	state := *stack
+	state.Targets = make([]portainer.MultiEndpointStackTarget, len(stack.Targets))
+	copy(state.Targets, stack.Targets)
+
+	deployment := &portainer.Task{
+		Type:       portainer.MultiEndpointStackDeployTask,
+		UserID:     userID,
+		ResourceID: stack.Name,
+		Progress:   0,
+	}
+
+	handler.multiEndpointStackMutex.Lock()
+	defer handler.multiEndpointStackMutex.Unlock()
+
+	err = handler.TaskManager.Run(deployment, func(reporter portainer.TaskReporter) error {
+		for _, target := range removedTargets {
+			err := handler.removeMultiEndpointStackTarget(&state, target.EndpointID)
+			if err != nil {
+				reporter.Log(fmt.Sprintf("Unable to remove the stack from endpoint %d: %s", target.EndpointID, err))
+			}
+		}
+
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+		done, failed := 0, 0
+
+		for _, target := range state.Targets {
+			wg.Add(1)
+			go func(target portainer.MultiEndpointStackTarget) {
+				defer wg.Done()
+
+				err := handler.deployMultiEndpointStackTarget(&state, &target, dockerhub, registries, prune)
+
+				mu.Lock()
+				defer mu.Unlock()
+
+				done++
+				if err != nil {
+					failed++
+					reporter.Log(fmt.Sprintf("Deployment failed on endpoint %d: %s", target.EndpointID, err))
+				} else {
+					reporter.Log(fmt.Sprintf("Deployment succeeded on endpoint %d", target.EndpointID))
+				}
+				reporter.SetProgress(done*100/len(state.Targets), fmt.Sprintf("Deployed on %d of %d endpoints", done-failed, len(state.Targets)))
+
+				handler.updateMultiEndpointStackTarget(state.ID, target.EndpointID, err)
+			}(target)
+		}
+
+		wg.Wait()
+
+		if failed > 0 {
+			return fmt.Errorf("deployment failed on %d of %d endpoints", failed, len(state.Targets))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	stack.TaskID = deployment.ID
+	return handler.DataStore.MultiEndpointStack().UpdateMultiEndpointStack(stack.ID, stack)
+}
+
+func (handler *Handler) deployMultiEndpointStackTarget(stack *portainer.MultiEndpointStack, target *portainer.MultiEndpointStackTarget, dockerhub *portainer.DockerHub, registries []portainer.Registry, prune bool) error {
+	endpoint, err := handler.DataStore.Endpoint().Endpoint(target.EndpointID)
+	if err != nil {
+		return err
+	}
+
+	deployed := targetStack(stack, endpoint.ID)
+	deployed.Env = stackfile.MergeEnv(stack.Env, target.Env)
+
+	handler.stackCreationMutex.Lock()
+	defer handler.stackCreationMutex.Unlock()
+
+	handler.SwarmStackManager.Login(dockerhub, registries, endpoint)
+
+	if stack.Type == portainer.DockerSwarmStack {
+		err = handler.SwarmStackManager.Deploy(deployed, prune, endpoint)
+	} else {
+		err = handler.ComposeStackManager.Up(deployed, endpoint)
+	}
+	if err != nil {
+		return err
+	}
+
+	return handler.SwarmStackManager.Logout(endpoint)
+}
+
+func (handler *Handler) removeMultiEndpointStackTarget(stack *portainer.MultiEndpointStack, endpointID portainer.EndpointID) error {
+	endpoint, err := handler.DataStore.Endpoint().Endpoint(endpointID)
+	if err != nil {
+		return err
+	}
+
+	removed := targetStack(stack, endpoint.ID)
+
+	handler.stackCreationMutex.Lock()
+	defer handler.stackCreationMutex.Unlock()
+
+	if stack.Type == portainer.DockerSwarmStack {
+		return handler.SwarmStackManager.Remove(removed, endpoint)
+	}
+	return handler.ComposeStackManager.Down(removed, endpoint)
+}
+
+// updateMultiEndpointStackTarget persists the outcome of the deployment on a target. The stack is read again
+// so that the outcomes of the deployments completing at the same time are all kept.
+func (handler *Handler) updateMultiEndpointStackTarget(stackID portainer.MultiEndpointStackID, endpointID portainer.EndpointID, deployErr error) {
+	handler.multiEndpointStackMutex.Lock()
+	defer handler.multiEndpointStackMutex.Unlock()
+
+	stack, err := handler.DataStore.MultiEndpointStack().MultiEndpointStack(stackID)
+	if err != nil {
+		log.Printf("[WARN] [http,stacks] [message: unable to retrieve multi-endpoint stack] [error: %s]", err)
+		return
+	}
+
+	for idx := range stack.Targets {
+		target := &stack.Targets[idx]
+		if target.EndpointID != endpointID {
+			continue
+		}
+
+		target.Status = portainer.MultiEndpointStackTargetDeployed
+		target.Error = ""
+		if deployErr != nil {
+			target.Status = portainer.MultiEndpointStackTargetFailed
+			target.Error = deployErr.Error()
+		}
+		target.DeploymentDate = time.Now().Unix()
+	}
+
+	err = handler.DataStore.MultiEndpointStack().UpdateMultiEndpointStack(stack.ID, stack)
+	if err != nil {
+		log.Printf("[WARN] [http,stacks] [message: unable to persist multi-endpoint stack] [error: %s]", err)
+	}
+}
+
+// targetStack returns the stack deployed by the stack managers on a target endpoint
+func targetStack(stack *portainer.MultiEndpointStack, endpointID portainer.EndpointID) *portainer.Stack {
+	return &portainer.Stack{
+		Name:        stack.Name,
+		Type:        stack.Type,
+		EndpointID:  endpointID,
+		EntryPoint:  stack.EntryPoint,
+		Env:         stack.Env,
+		ProjectPath: stack.ProjectPath,
+	}
+}
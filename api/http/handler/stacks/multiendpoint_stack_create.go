@@ -0,0 +1,142 @@
+package stacks
+
+import (
+	"errors"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/asaskevich/govalidator"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/filesystem"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+// multiEndpointStackFolder is the folder of the file store where the files of the multi-endpoint stacks are stored
+const multiEndpointStackFolder = "multi_endpoint"
+
+type multiEndpointStackCreatePayload struct {
+	Name             string
+	Type             portainer.StackType
+	StackFileContent string
+	Env              []portainer.Pair
+	Targets          []multiEndpointStackTargetPayload
+}
+
+func (payload *multiEndpointStackCreatePayload) Validate(r *http.Request) error {
+	if payload.Type != portainer.DockerSwarmStack && payload.Type != portainer.DockerComposeStack {
+		return errors.New("Invalid stack type. Value must be one of: 1 (Swarm stack) or 2 (Compose stack)")
+	}
+	if govalidator.IsNull(payload.Name) {
+		return errors.New("Invalid stack name")
+	}
+	if payload.Type == portainer.DockerComposeStack {
+		payload.Name = normalizeStackName(payload.Name)
+	}
+	if govalidator.IsNull(payload.StackFileContent) {
+		return errors.New("Invalid stack file content")
+	}
+	return validateMultiEndpointStackTargets(payload.Targets)
+}
+
+// POST request on /api/multi_endpoint_stacks
+// The stack is deployed in the background, the status of each target is available once its deployment completes.
+func (handler *Handler) multiEndpointStackCreate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	var payload multiEndpointStackCreatePayload
+	err := request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	securityContext, err := security.RetrieveRestrictedRequestContext(r)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve info from request context", err}
+	}
+
+	handlerErr := handler.checkMultiEndpointStackTargets(payload.Targets, nil)
+	if handlerErr != nil {
+		return handlerErr
+	}
+
+	endpointIDs := make([]portainer.EndpointID, 0, len(payload.Targets))
+	for _, target := range payload.Targets {
+		endpointIDs = append(endpointIDs, target.EndpointID)
+	}
+
+	handlerErr = handler.checkUnprotectedTargets(endpointIDs)
+	if handlerErr != nil {
+		return handlerErr
+	}
+
+	handlerErr = handler.checkUniqueMultiEndpointStackName(payload.Name)
+	if handlerErr != nil {
+		return handlerErr
+	}
+
+	stack := &portainer.MultiEndpointStack{
+		ID:           portainer.MultiEndpointStackID(handler.DataStore.MultiEndpointStack().GetNextIdentifier()),
+		Name:         payload.Name,
+		Type:         payload.Type,
+		EntryPoint:   filesystem.ComposeFileDefaultName,
+		Env:          payload.Env,
+		Targets:      make([]portainer.MultiEndpointStackTarget, 0, len(payload.Targets)),
+		CreatedBy:    securityContext.UserID,
+		CreationDate: time.Now().Unix(),
+	}
+
+	for _, target := range payload.Targets {
+		stack.Targets = append(stack.Targets, portainer.MultiEndpointStackTarget{EndpointID: target.EndpointID, Env: target.Env})
+	}
+
+	stackFolder := path.Join(multiEndpointStackFolder, strconv.Itoa(int(stack.ID)))
+	projectPath, err := handler.FileService.StoreStackFileFromBytes(stackFolder, stack.EntryPoint, []byte(payload.StackFileContent))
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist Compose file on disk", err}
+	}
+	stack.ProjectPath = projectPath
+
+	err = handler.DataStore.MultiEndpointStack().CreateMultiEndpointStack(stack)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist the stack inside the database", err}
+	}
+
+	err = handler.deployMultiEndpointStack(stack, nil, securityContext.UserID, false)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to start the stack deployment task", err}
+	}
+
+	return response.JSON(w, newMultiEndpointStackResponse(stack))
+}
+
+// checkUniqueMultiEndpointStackName prevents the deployment of a multi-endpoint stack or of an application component
+// over an existing stack
+func (handler *Handler) checkUniqueMultiEndpointStackName(name string) *httperror.HandlerError {
+	stacks, err := handler.DataStore.Stack().Stacks()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve stacks from the database", err}
+	}
+
+	for _, stack := range stacks {
+		if strings.EqualFold(stack.Name, name) {
+			return &httperror.HandlerError{http.StatusConflict, "A stack with this name already exists", errStackAlreadyExists}
+		}
+	}
+
+	multiEndpointStacks, err := handler.DataStore.MultiEndpointStack().MultiEndpointStacks()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve multi-endpoint stacks from the database", err}
+	}
+
+	for _, stack := range multiEndpointStacks {
+		if strings.EqualFold(stack.Name, name) {
+			return &httperror.HandlerError{http.StatusConflict, "A stack with this name already exists", errStackAlreadyExists}
+		}
+	}
+
+	return nil
+}
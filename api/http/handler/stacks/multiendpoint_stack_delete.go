@@ -0,0 +1,65 @@
+package stacks
+
+import (
+	"log"
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+)
+
+// DELETE request on /api/multi_endpoint_stacks/:id
+// The stack is removed from all its targets, the endpoints that cannot be reached are only reported in the logs.
+func (handler *Handler) multiEndpointStackDelete(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	stackID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid multi-endpoint stack identifier route variable", err}
+	}
+
+	stack, err := handler.DataStore.MultiEndpointStack().MultiEndpointStack(portainer.MultiEndpointStackID(stackID))
+	if err == bolterrors.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find a multi-endpoint stack with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a multi-endpoint stack with the specified identifier inside the database", err}
+	}
+
+	deploying, err := handler.isMultiEndpointStackDeploying(stack)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve the deployment task of the stack", err}
+	}
+	if deploying {
+		return &httperror.HandlerError{http.StatusConflict, "A deployment of the stack is in progress", errMultiEndpointStackDeploying}
+	}
+
+	endpointIDs := make([]portainer.EndpointID, 0, len(stack.Targets))
+	for _, target := range stack.Targets {
+		endpointIDs = append(endpointIDs, target.EndpointID)
+	}
+
+	handlerErr := handler.checkUnprotectedTargets(endpointIDs)
+	if handlerErr != nil {
+		return handlerErr
+	}
+
+	for _, target := range stack.Targets {
+		err := handler.removeMultiEndpointStackTarget(stack, target.EndpointID)
+		if err != nil {
+			log.Printf("[WARN] [http,stacks] [message: unable to remove multi-endpoint stack from endpoint] [endpoint: %d] [error: %s]", target.EndpointID, err)
+		}
+	}
+
+	err = handler.DataStore.MultiEndpointStack().DeleteMultiEndpointStack(stack.ID)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to remove the stack from the database", err}
+	}
+
+	err = handler.FileService.RemoveDirectory(stack.ProjectPath)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to remove stack files from disk", err}
+	}
+
+	return response.Empty(w)
+}
@@ -0,0 +1,28 @@
+package stacks
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+)
+
+// GET request on /api/multi_endpoint_stacks/:id
+func (handler *Handler) multiEndpointStackInspect(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	stackID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid multi-endpoint stack identifier route variable", err}
+	}
+
+	stack, err := handler.DataStore.MultiEndpointStack().MultiEndpointStack(portainer.MultiEndpointStackID(stackID))
+	if err == bolterrors.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find a multi-endpoint stack with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a multi-endpoint stack with the specified identifier inside the database", err}
+	}
+
+	return response.JSON(w, newMultiEndpointStackResponse(stack))
+}
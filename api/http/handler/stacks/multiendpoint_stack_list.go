@@ -0,0 +1,23 @@
+package stacks
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/response"
+)
+
+// GET request on /api/multi_endpoint_stacks
+func (handler *Handler) multiEndpointStackList(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	stacks, err := handler.DataStore.MultiEndpointStack().MultiEndpointStacks()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve multi-endpoint stacks from the database", err}
+	}
+
+	responses := make([]*multiEndpointStackResponse, 0, len(stacks))
+	for idx := range stacks {
+		responses = append(responses, newMultiEndpointStackResponse(&stacks[idx]))
+	}
+
+	return response.JSON(w, responses)
+}
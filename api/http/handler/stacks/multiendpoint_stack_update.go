@@ -0,0 +1,111 @@
+package stacks
+
+import (
+	"net/http"
+	"path"
+	"strconv"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+type multiEndpointStackUpdatePayload struct {
+	StackFileContent string
+	Env              []portainer.Pair
+	Targets          []multiEndpointStackTargetPayload
+	Prune            bool
+}
+
+func (payload *multiEndpointStackUpdatePayload) Validate(r *http.Request) error {
+	return validateMultiEndpointStackTargets(payload.Targets)
+}
+
+// PUT request on /api/multi_endpoint_stacks/:id
+// The stack is redeployed on all its targets and removed from the endpoints that are no longer targeted.
+// The stack file is kept when no content is specified.
+func (handler *Handler) multiEndpointStackUpdate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	stackID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid multi-endpoint stack identifier route variable", err}
+	}
+
+	var payload multiEndpointStackUpdatePayload
+	err = request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	securityContext, err := security.RetrieveRestrictedRequestContext(r)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve info from request context", err}
+	}
+
+	stack, err := handler.DataStore.MultiEndpointStack().MultiEndpointStack(portainer.MultiEndpointStackID(stackID))
+	if err == bolterrors.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find a multi-endpoint stack with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a multi-endpoint stack with the specified identifier inside the database", err}
+	}
+
+	deploying, err := handler.isMultiEndpointStackDeploying(stack)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve the deployment task of the stack", err}
+	}
+	if deploying {
+		return &httperror.HandlerError{http.StatusConflict, "A deployment of the stack is in progress", errMultiEndpointStackDeploying}
+	}
+
+	handlerErr := handler.checkMultiEndpointStackTargets(payload.Targets, stack.Targets)
+	if handlerErr != nil {
+		return handlerErr
+	}
+
+	endpointIDs := make([]portainer.EndpointID, 0, len(payload.Targets)+len(stack.Targets))
+	for _, target := range payload.Targets {
+		endpointIDs = append(endpointIDs, target.EndpointID)
+	}
+	for _, target := range stack.Targets {
+		endpointIDs = append(endpointIDs, target.EndpointID)
+	}
+
+	handlerErr = handler.checkUnprotectedTargets(endpointIDs)
+	if handlerErr != nil {
+		return handlerErr
+	}
+
+	if payload.StackFileContent != "" {
+		stackFolder := path.Join(multiEndpointStackFolder, strconv.Itoa(int(stack.ID)))
+		_, err = handler.FileService.StoreStackFileFromBytes(stackFolder, stack.EntryPoint, []byte(payload.StackFileContent))
+		if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist updated Compose file on disk", err}
+		}
+	}
+
+	targeted := make(map[portainer.EndpointID]bool)
+	targets := make([]portainer.MultiEndpointStackTarget, 0, len(payload.Targets))
+	for _, target := range payload.Targets {
+		targeted[target.EndpointID] = true
+		targets = append(targets, portainer.MultiEndpointStackTarget{EndpointID: target.EndpointID, Env: target.Env})
+	}
+
+	removedTargets := make([]portainer.MultiEndpointStackTarget, 0)
+	for _, target := range stack.Targets {
+		if !targeted[target.EndpointID] {
+			removedTargets = append(removedTargets, target)
+		}
+	}
+
+	stack.Env = payload.Env
+	stack.Targets = targets
+
+	err = handler.deployMultiEndpointStack(stack, removedTargets, securityContext.UserID, payload.Prune)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to start the stack deployment task", err}
+	}
+
+	return response.JSON(w, newMultiEndpointStackResponse(stack))
+}
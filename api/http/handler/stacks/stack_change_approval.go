@@ -0,0 +1,93 @@
+package stacks
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/portainer/api"
+)
+
+// stackDeploymentEndpoint returns the endpoint a stack is deployed to. The query is read directly as reading
+// the form values would consume the stack file uploaded with the request. The dry runs do not require an approval.
+func stackDeploymentEndpoint(r *http.Request) (portainer.EndpointID, bool) {
+	if dryRun, _ := strconv.ParseBool(r.URL.Query().Get("dryRun")); dryRun {
+		return 0, false
+	}
+
+	endpointID, err := strconv.Atoi(r.URL.Query().Get("endpointId"))
+	if err != nil {
+		return 0, false
+	}
+	return portainer.EndpointID(endpointID), true
+}
+
+// stackUpdateEndpoint returns the endpoint of the updated stack, the endpointId query parameter overrides the
+// endpoint of the stack as in the update. The dry runs do not require an approval as they do not change anything.
+func (handler *Handler) stackUpdateEndpoint(r *http.Request) (portainer.EndpointID, bool) {
+	if dryRun, _ := strconv.ParseBool(r.URL.Query().Get("dryRun")); dryRun {
+		return 0, false
+	}
+
+	if endpointID, err := strconv.Atoi(r.URL.Query().Get("endpointId")); err == nil {
+		return portainer.EndpointID(endpointID), true
+	}
+
+	return handler.stackEndpoint(r)
+}
+
+// stackDeletionEndpoint returns the endpoint of the removed stack, the endpointId query parameter overrides the
+// endpoint of the stack as in the deletion and is required to remove an external stack.
+func (handler *Handler) stackDeletionEndpoint(r *http.Request) (portainer.EndpointID, bool) {
+	if endpointID, err := strconv.Atoi(r.URL.Query().Get("endpointId")); err == nil {
+		return portainer.EndpointID(endpointID), true
+	}
+
+	if external, _ := strconv.ParseBool(r.URL.Query().Get("external")); external {
+		return 0, false
+	}
+
+	return handler.stackEndpoint(r)
+}
+
+// stackEndpoint returns the endpoint of the stack of the request
+func (handler *Handler) stackEndpoint(r *http.Request) (portainer.EndpointID, bool) {
+	stackID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return 0, false
+	}
+
+	stack, err := handler.DataStore.Stack().Stack(portainer.StackID(stackID))
+	if err != nil {
+		return 0, false
+	}
+
+	return stack.EndpointID, true
+}
+
+// payloadEndpoint returns the endpoint a stack or an application is deployed to, as defined by the EndpointID
+// property of the payload. The body is restored after being read so that it can be decoded by the operation.
+func payloadEndpoint(r *http.Request) (portainer.EndpointID, bool) {
+	if r.Body == nil {
+		return 0, false
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return 0, false
+	}
+
+	var payload struct {
+		EndpointID int
+	}
+	err = json.Unmarshal(body, &payload)
+	if err != nil || payload.EndpointID == 0 {
+		return 0, false
+	}
+
+	return portainer.EndpointID(payload.EndpointID), true
+}
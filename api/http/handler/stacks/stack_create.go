@@ -15,6 +15,8 @@ import (
 	httperrors "github.com/portainer/portainer/api/http/errors"
 	"github.com/portainer/portainer/api/http/security"
 	"github.com/portainer/portainer/api/internal/authorization"
+	"github.com/portainer/portainer/api/internal/organization"
+	"github.com/portainer/portainer/api/internal/stackfile"
 )
 
 func (handler *Handler) cleanUp(stack *portainer.Stack, doCleanUp *bool) error {
@@ -29,7 +31,9 @@ func (handler *Handler) cleanUp(stack *portainer.Stack, doCleanUp *bool) error {
 	return nil
 }
 
-// POST request on /api/stacks?type=<type>&method=<method>&endpointId=<endpointId>
+// POST request on /api/stacks?type=<type>&method=<method>&endpointId=<endpointId>&dryRun=<dryRun>
+// When the dryRun query parameter is set to true, the stack file is validated and the resources that
+// would be created are returned without deploying the stack. Dry-run is not available for Kubernetes stacks.
 func (handler *Handler) stackCreate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
 	stackType, err := request.RetrieveNumericQueryParameter(r, "type", false)
 	if err != nil {
@@ -46,60 +50,91 @@ func (handler *Handler) stackCreate(w http.ResponseWriter, r *http.Request) *htt
 		return &httperror.HandlerError{http.StatusBadRequest, "Invalid query parameter: endpointId", err}
 	}
 
+	endpoint, handlerErr := handler.retrieveStackCreationEndpoint(r, portainer.EndpointID(endpointID))
+	if handlerErr != nil {
+		return handlerErr
+	}
+
+	tokenData, err := security.RetrieveTokenData(r)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve user details from authentication token", err}
+	}
+
+	dryRun, _ := request.RetrieveBooleanQueryParameter(r, "dryRun", true)
+	if dryRun && (portainer.StackType(stackType) == portainer.DockerSwarmStack || portainer.StackType(stackType) == portainer.DockerComposeStack) {
+		return handler.planStackCreate(w, r, portainer.StackType(stackType), method, endpoint)
+	}
+
+	handlerErr = handler.checkStackQuota(endpoint.OrganizationID, 1)
+	if handlerErr != nil {
+		return handlerErr
+	}
+
+	switch portainer.StackType(stackType) {
+	case portainer.DockerSwarmStack:
+		return handler.createSwarmStack(w, r, method, endpoint, tokenData.ID)
+	case portainer.DockerComposeStack:
+		return handler.createComposeStack(w, r, method, endpoint, tokenData.ID)
+	case portainer.KubernetesStack:
+		if tokenData.Role != portainer.AdministratorRole {
+			return &httperror.HandlerError{http.StatusForbidden, "Access denied", httperrors.ErrUnauthorized}
+		}
+
+		return handler.createKubernetesStack(w, r, endpoint)
+	}
+
+	return &httperror.HandlerError{http.StatusBadRequest, "Invalid value for query parameter: type. Value must be one of: 1 (Swarm stack) or 2 (Compose stack)", errors.New(request.ErrInvalidQueryParameter)}
+}
+
+// checkStackQuota verifies that count stacks can be created on the endpoints of an organization
+func (handler *Handler) checkStackQuota(organizationID portainer.OrganizationID, count int) *httperror.HandlerError {
+	err := organization.CheckQuota(handler.DataStore, organizationID, organization.QuotaStacks, count)
+	if quotaErr, ok := err.(*organization.QuotaExceededError); ok {
+		return &httperror.HandlerError{http.StatusForbidden, quotaErr.Error(), quotaErr}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to verify the quotas of the organization", err}
+	}
+	return nil
+}
+
+// retrieveStackCreationEndpoint returns the endpoint a stack is created on when the user is allowed to create stacks on it
+func (handler *Handler) retrieveStackCreationEndpoint(r *http.Request, endpointID portainer.EndpointID) (*portainer.Endpoint, *httperror.HandlerError) {
 	settings, err := handler.DataStore.Settings().Settings()
 	if err != nil {
-		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve settings from the database", err}
+		return nil, &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve settings from the database", err}
 	}
 
 	if !settings.AllowStackManagementForRegularUsers {
 		securityContext, err := security.RetrieveRestrictedRequestContext(r)
 		if err != nil {
-			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve user info from request context", err}
+			return nil, &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve user info from request context", err}
 		}
 
-		canCreate, err := handler.userCanCreateStack(securityContext, portainer.EndpointID(endpointID))
+		canCreate, err := handler.userCanCreateStack(securityContext, endpointID)
 
 		if err != nil {
-			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to verify user authorizations to validate stack creation", err}
+			return nil, &httperror.HandlerError{http.StatusInternalServerError, "Unable to verify user authorizations to validate stack creation", err}
 		}
 
 		if !canCreate {
 			errMsg := "Stack creation is disabled for non-admin users"
-			return &httperror.HandlerError{http.StatusForbidden, errMsg, errors.New(errMsg)}
+			return nil, &httperror.HandlerError{http.StatusForbidden, errMsg, errors.New(errMsg)}
 		}
 	}
 
-	endpoint, err := handler.DataStore.Endpoint().Endpoint(portainer.EndpointID(endpointID))
+	endpoint, err := handler.DataStore.Endpoint().Endpoint(endpointID)
 	if err == bolterrors.ErrObjectNotFound {
-		return &httperror.HandlerError{http.StatusNotFound, "Unable to find an endpoint with the specified identifier inside the database", err}
+		return nil, &httperror.HandlerError{http.StatusNotFound, "Unable to find an endpoint with the specified identifier inside the database", err}
 	} else if err != nil {
-		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find an endpoint with the specified identifier inside the database", err}
+		return nil, &httperror.HandlerError{http.StatusInternalServerError, "Unable to find an endpoint with the specified identifier inside the database", err}
 	}
 
 	err = handler.requestBouncer.AuthorizedEndpointOperation(r, endpoint)
 	if err != nil {
-		return &httperror.HandlerError{http.StatusForbidden, "Permission denied to access endpoint", err}
+		return nil, &httperror.HandlerError{http.StatusForbidden, "Permission denied to access endpoint", err}
 	}
 
-	tokenData, err := security.RetrieveTokenData(r)
-	if err != nil {
-		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve user details from authentication token", err}
-	}
-
-	switch portainer.StackType(stackType) {
-	case portainer.DockerSwarmStack:
-		return handler.createSwarmStack(w, r, method, endpoint, tokenData.ID)
-	case portainer.DockerComposeStack:
-		return handler.createComposeStack(w, r, method, endpoint, tokenData.ID)
-	case portainer.KubernetesStack:
-		if tokenData.Role != portainer.AdministratorRole {
-			return &httperror.HandlerError{http.StatusForbidden, "Access denied", httperrors.ErrUnauthorized}
-		}
-
-		return handler.createKubernetesStack(w, r, endpoint)
-	}
-
-	return &httperror.HandlerError{http.StatusBadRequest, "Invalid value for query parameter: type. Value must be one of: 1 (Swarm stack) or 2 (Compose stack)", errors.New(request.ErrInvalidQueryParameter)}
+	return endpoint, nil
 }
 
 func (handler *Handler) createComposeStack(w http.ResponseWriter, r *http.Request, method string, endpoint *portainer.Endpoint, userID portainer.UserID) *httperror.HandlerError {
@@ -179,13 +214,38 @@ func (handler *Handler) isValidStackFile(stackFileContent []byte, settings *port
 		}
 	}
 
+	// the device reservations are not decoded by the Compose loader
+	if !settings.AllowDeviceMappingForRegularUsers {
+		services, err := stackfile.ServicesWithDeviceReservations(stackFileContent)
+		if err != nil {
+			return err
+		}
+
+		if len(services) > 0 {
+			return errors.New("device mapping disabled for non administrator users")
+		}
+	}
+
 	return nil
 }
 
 func (handler *Handler) decorateStackResponse(w http.ResponseWriter, stack *portainer.Stack, userID portainer.UserID) *httperror.HandlerError {
-	resourceControl := authorization.NewPrivateResourceControl(stack.Name, portainer.StackResourceControl, userID)
+	resourceControl, err := handler.newStackResourceControl(stack, userID)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to create the resource control associated to the stack", err}
+	}
+
+	endpoint, err := handler.DataStore.Endpoint().Endpoint(stack.EndpointID)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find the endpoint associated to the stack inside the database", err}
+	}
 
-	err := handler.DataStore.ResourceControl().CreateResourceControl(resourceControl)
+	err = handler.syncStackResourceControl(stack, endpoint, resourceControl)
+	if err != nil {
+		log.Printf("http error: Unable to propagate the stack resource control to the stack resources (err=%s)\n", err)
+	}
+
+	err = handler.DataStore.ResourceControl().CreateResourceControl(resourceControl)
 	if err != nil {
 		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist resource control inside the database", err}
 	}
@@ -193,3 +253,33 @@ func (handler *Handler) decorateStackResponse(w http.ResponseWriter, stack *port
 	stack.ResourceControl = resourceControl
 	return response.JSON(w, stack)
 }
+
+func (handler *Handler) newStackResourceControl(stack *portainer.Stack, userID portainer.UserID) (*portainer.ResourceControl, error) {
+	settings, err := handler.DataStore.Settings().Settings()
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint, err := handler.DataStore.Endpoint().Endpoint(stack.EndpointID)
+	if err != nil {
+		return nil, err
+	}
+
+	memberships, err := handler.DataStore.TeamMembership().TeamMembershipsByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	userTeamIDs := make([]portainer.TeamID, 0)
+	for _, membership := range memberships {
+		userTeamIDs = append(userTeamIDs, membership.TeamID)
+	}
+
+	settings, err = organization.EffectiveSettings(handler.DataStore, settings, endpoint.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	policy := authorization.EffectiveResourceOwnershipPolicy(endpoint, settings)
+	return authorization.NewDefaultResourceControl(stack.Name, portainer.StackResourceControl, userID, userTeamIDs, policy), nil
+}
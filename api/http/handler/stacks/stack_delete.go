@@ -45,6 +45,14 @@ func (handler *Handler) stackDelete(w http.ResponseWriter, r *http.Request) *htt
 		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a stack with the specified identifier inside the database", err}
 	}
 
+	application, err := handler.stackApplication(stack.ID)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve applications from the database", err}
+	}
+	if application != nil {
+		return &httperror.HandlerError{http.StatusConflict, "The stack is a component of the application " + application.Name, errApplicationComponent}
+	}
+
 	// TODO: this is a work-around for stacks created with Portainer version >= 1.17.1
 	// The EndpointID property is not available for these stacks, this API endpoint
 	// can use the optional EndpointID query parameter to set a valid endpoint identifier to be
@@ -105,6 +113,11 @@ func (handler *Handler) stackDelete(w http.ResponseWriter, r *http.Request) *htt
 		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to remove stack files from disk", err}
 	}
 
+	err = handler.FileService.DeleteStackEnvFiles(strconv.Itoa(int(stack.ID)))
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to remove stack variable files from disk", err}
+	}
+
 	return response.Empty(w)
 }
 
@@ -0,0 +1,45 @@
+package stacks
+
+import (
+	"errors"
+	"net/http"
+	"path"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/internal/stackfile"
+)
+
+var errStackDependenciesUnsupported = errors.New("The dependencies are only available for Docker stacks")
+
+// GET request on /api/stacks/:id/dependencies
+//
+// Returns the dependencies between the services of the stack declared with depends_on, links, network_mode
+// and volumes_from, the networks the services are connected to and the order the services are started in.
+func (handler *Handler) stackDependencies(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	stack, _, _, handlerErr := handler.retrieveAccessibleStack(r)
+	if handlerErr != nil {
+		return handlerErr
+	}
+
+	if stack.Type != portainer.DockerSwarmStack && stack.Type != portainer.DockerComposeStack {
+		return &httperror.HandlerError{http.StatusBadRequest, "The dependencies are only available for Docker stacks", errStackDependenciesUnsupported}
+	}
+
+	graph, err := handler.stackDependencyGraph(stack)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to parse the dependencies of the stack services", err}
+	}
+
+	return response.JSON(w, graph)
+}
+
+func (handler *Handler) stackDependencyGraph(stack *portainer.Stack) (*stackfile.Graph, error) {
+	stackFileContent, err := handler.FileService.GetFileContent(path.Join(stack.ProjectPath, stack.EntryPoint))
+	if err != nil {
+		return nil, err
+	}
+
+	return stackfile.DependencyGraph(stackFileContent)
+}
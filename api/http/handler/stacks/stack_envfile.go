@@ -0,0 +1,113 @@
+package stacks
+
+import (
+	"errors"
+	"net/http"
+	"regexp"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+	httperrors "github.com/portainer/portainer/api/http/errors"
+	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/stackfile"
+)
+
+var (
+	envFileNamePattern   = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+	errEnvFileNotFound   = errors.New("Unable to find a variable file with the specified name")
+	errSecretEnvFileEdit = errors.New("Only administrators can change the secret variable files of a stack")
+)
+
+type stackEnvFileResponse struct {
+	Name   string           `json:"Name"`
+	Secret bool             `json:"Secret"`
+	Env    []portainer.Pair `json:"Env"`
+}
+
+// GET request on /api/stacks/:id/envfiles
+// The values of the secret variable files are only returned to administrators.
+func (handler *Handler) stackEnvFileList(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	stack, _, isAdmin, handlerErr := handler.retrieveAccessibleStack(r)
+	if handlerErr != nil {
+		return handlerErr
+	}
+
+	envFiles := make([]stackEnvFileResponse, 0, len(stack.EnvFiles))
+	for _, envFile := range stack.EnvFiles {
+		data, err := handler.FileService.GetFileContent(envFile.Path)
+		if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve variable file from disk", err}
+		}
+
+		env := stackfile.ParseEnvFile(data)
+		if envFile.Secret && !isAdmin {
+			for idx := range env {
+				env[idx].Value = ""
+			}
+		}
+
+		envFiles = append(envFiles, stackEnvFileResponse{Name: envFile.Name, Secret: envFile.Secret, Env: env})
+	}
+
+	return response.JSON(w, envFiles)
+}
+
+// retrieveAccessibleStack returns the stack matching the id route variable and its endpoint when the user
+// can access it and whether the user is an administrator
+func (handler *Handler) retrieveAccessibleStack(r *http.Request) (*portainer.Stack, *portainer.Endpoint, bool, *httperror.HandlerError) {
+	stackID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return nil, nil, false, &httperror.HandlerError{http.StatusBadRequest, "Invalid stack identifier route variable", err}
+	}
+
+	stack, err := handler.DataStore.Stack().Stack(portainer.StackID(stackID))
+	if err == bolterrors.ErrObjectNotFound {
+		return nil, nil, false, &httperror.HandlerError{http.StatusNotFound, "Unable to find a stack with the specified identifier inside the database", err}
+	} else if err != nil {
+		return nil, nil, false, &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a stack with the specified identifier inside the database", err}
+	}
+
+	endpoint, err := handler.DataStore.Endpoint().Endpoint(stack.EndpointID)
+	if err == bolterrors.ErrObjectNotFound {
+		return nil, nil, false, &httperror.HandlerError{http.StatusNotFound, "Unable to find an endpoint with the specified identifier inside the database", err}
+	} else if err != nil {
+		return nil, nil, false, &httperror.HandlerError{http.StatusInternalServerError, "Unable to find an endpoint with the specified identifier inside the database", err}
+	}
+
+	err = handler.requestBouncer.AuthorizedEndpointOperation(r, endpoint)
+	if err != nil {
+		return nil, nil, false, &httperror.HandlerError{http.StatusForbidden, "Permission denied to access endpoint", err}
+	}
+
+	resourceControl, err := handler.DataStore.ResourceControl().ResourceControlByResourceIDAndType(stack.Name, portainer.StackResourceControl)
+	if err != nil {
+		return nil, nil, false, &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve a resource control associated to the stack", err}
+	}
+
+	securityContext, err := security.RetrieveRestrictedRequestContext(r)
+	if err != nil {
+		return nil, nil, false, &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve info from request context", err}
+	}
+
+	access, err := handler.userCanAccessStack(securityContext, endpoint.ID, resourceControl)
+	if err != nil {
+		return nil, nil, false, &httperror.HandlerError{http.StatusInternalServerError, "Unable to verify user authorizations to validate stack access", err}
+	}
+	if !access {
+		return nil, nil, false, &httperror.HandlerError{http.StatusForbidden, "Access denied to resource", httperrors.ErrResourceAccessDenied}
+	}
+
+	return stack, endpoint, securityContext.IsAdmin, nil
+}
+
+func findStackEnvFile(stack *portainer.Stack, name string) int {
+	for idx, envFile := range stack.EnvFiles {
+		if envFile.Name == name {
+			return idx
+		}
+	}
+	return -1
+}
@@ -0,0 +1,48 @@
+package stacks
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+)
+
+// DELETE request on /api/stacks/:id/envfiles/:name
+// The variables of the file are no longer available the next time the stack is deployed.
+func (handler *Handler) stackEnvFileDelete(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	name, err := request.RetrieveRouteVariableValue(r, "name")
+	if err != nil || !envFileNamePattern.MatchString(name) {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid variable file name route variable", errors.New("Invalid variable file name")}
+	}
+
+	stack, _, isAdmin, handlerErr := handler.retrieveAccessibleStack(r)
+	if handlerErr != nil {
+		return handlerErr
+	}
+
+	idx := findStackEnvFile(stack, name)
+	if idx == -1 {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find a variable file with the specified name", errEnvFileNotFound}
+	}
+
+	if stack.EnvFiles[idx].Secret && !isAdmin {
+		return &httperror.HandlerError{http.StatusForbidden, "Permission denied to remove the secret variable files of the stack", errSecretEnvFileEdit}
+	}
+
+	stack.EnvFiles = append(stack.EnvFiles[:idx], stack.EnvFiles[idx+1:]...)
+
+	err = handler.DataStore.Stack().UpdateStack(stack.ID, stack)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist the stack changes inside the database", err}
+	}
+
+	err = handler.FileService.DeleteStackEnvFile(strconv.Itoa(int(stack.ID)), name)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to remove the variable file from disk", err}
+	}
+
+	return response.Empty(w)
+}
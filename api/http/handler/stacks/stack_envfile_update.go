@@ -0,0 +1,109 @@
+package stacks
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/internal/stackfile"
+)
+
+type stackEnvFileUpdatePayload struct {
+	Env    []portainer.Pair
+	Remove []string
+	Secret *bool
+}
+
+func (payload *stackEnvFileUpdatePayload) Validate(r *http.Request) error {
+	for _, pair := range payload.Env {
+		if pair.Name == "" || strings.ContainsAny(pair.Name, "= \t\r\n#") {
+			return errors.New("Invalid variable name")
+		}
+		if strings.ContainsAny(pair.Value, "\r\n") {
+			return errors.New("Invalid variable value. Values cannot span multiple lines")
+		}
+	}
+	return nil
+}
+
+// PUT request on /api/stacks/:id/envfiles/:name
+// Creates the variable file or updates its variables: the variables of the payload replace the existing
+// ones with the same name and the variables listed in Remove are deleted. The values of a secret file
+// can be changed by any user with access to the stack but only administrators can read them back.
+func (handler *Handler) stackEnvFileUpdate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	name, err := request.RetrieveRouteVariableValue(r, "name")
+	if err != nil || !envFileNamePattern.MatchString(name) {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid variable file name route variable", errors.New("Invalid variable file name")}
+	}
+
+	var payload stackEnvFileUpdatePayload
+	err = request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	stack, _, isAdmin, handlerErr := handler.retrieveAccessibleStack(r)
+	if handlerErr != nil {
+		return handlerErr
+	}
+
+	envFile := portainer.StackEnvFile{Name: name}
+	env := make([]portainer.Pair, 0)
+
+	idx := findStackEnvFile(stack, name)
+	if idx != -1 {
+		envFile = stack.EnvFiles[idx]
+
+		data, err := handler.FileService.GetFileContent(envFile.Path)
+		if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve variable file from disk", err}
+		}
+		env = stackfile.ParseEnvFile(data)
+	}
+
+	if payload.Secret != nil && *payload.Secret != envFile.Secret {
+		if !isAdmin {
+			return &httperror.HandlerError{http.StatusForbidden, "Permission denied to change the secret variable files of the stack", errSecretEnvFileEdit}
+		}
+		envFile.Secret = *payload.Secret
+	}
+
+	env = stackfile.MergeEnv(env, payload.Env)
+	for _, removed := range payload.Remove {
+		for i := range env {
+			if env[i].Name == removed {
+				env = append(env[:i], env[i+1:]...)
+				break
+			}
+		}
+	}
+
+	envFile.Path, err = handler.FileService.StoreStackEnvFileFromBytes(strconv.Itoa(int(stack.ID)), name, stackfile.FormatEnvFile(env))
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist the variable file on disk", err}
+	}
+
+	if idx == -1 {
+		stack.EnvFiles = append(stack.EnvFiles, envFile)
+	} else {
+		stack.EnvFiles[idx] = envFile
+	}
+
+	err = handler.DataStore.Stack().UpdateStack(stack.ID, stack)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist the stack changes inside the database", err}
+	}
+
+	if envFile.Secret && !isAdmin {
+		for i := range env {
+			env[i].Value = ""
+		}
+	}
+
+	return response.JSON(w, &stackEnvFileResponse{Name: envFile.Name, Secret: envFile.Secret, Env: env})
+}
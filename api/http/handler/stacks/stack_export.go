@@ -0,0 +1,123 @@
+package stacks
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"time"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+	"github.com/portainer/portainer/api/internal/stackfile"
+)
+
+// stackBundleVersion is the version of the format of the stack bundles
+const stackBundleVersion = 1
+
+var errStackExportUnsupported = errors.New("Only Docker stacks can be exported")
+
+type (
+	// stackBundle represents a stack exported from a Portainer instance. It contains everything required
+	// to deploy the stack again: the stack file, the variables, the variable files and the custom template.
+	stackBundle struct {
+		Version          int                  `json:"Version"`
+		ExportDate       int64                `json:"ExportDate"`
+		Name             string               `json:"Name"`
+		Type             portainer.StackType  `json:"Type"`
+		StackFileContent string               `json:"StackFileContent"`
+		Env              []portainer.Pair     `json:"Env"`
+		EnvFiles         []stackBundleEnvFile `json:"EnvFiles"`
+		CustomTemplate   *stackBundleTemplate `json:"CustomTemplate,omitempty"`
+	}
+
+	stackBundleEnvFile struct {
+		Name   string           `json:"Name"`
+		Secret bool             `json:"Secret"`
+		Env    []portainer.Pair `json:"Env"`
+	}
+
+	stackBundleTemplate struct {
+		Title       string                           `json:"Title"`
+		Description string                           `json:"Description"`
+		Note        string                           `json:"Note"`
+		Logo        string                           `json:"Logo"`
+		Platform    portainer.CustomTemplatePlatform `json:"Platform"`
+		Type        portainer.StackType              `json:"Type"`
+		FileContent string                           `json:"FileContent"`
+	}
+)
+
+// GET request on /api/stacks/:id/export
+// Returns a bundle that can be imported into another endpoint or Portainer instance.
+// The values of the secret variable files are only exported for administrators.
+func (handler *Handler) stackExport(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	stack, _, isAdmin, handlerErr := handler.retrieveAccessibleStack(r)
+	if handlerErr != nil {
+		return handlerErr
+	}
+
+	if stack.Type != portainer.DockerSwarmStack && stack.Type != portainer.DockerComposeStack {
+		return &httperror.HandlerError{http.StatusBadRequest, "Only Docker stacks can be exported", errStackExportUnsupported}
+	}
+
+	stackFileContent, err := handler.FileService.GetFileContent(path.Join(stack.ProjectPath, stack.EntryPoint))
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve Compose file from disk", err}
+	}
+
+	bundle := &stackBundle{
+		Version:          stackBundleVersion,
+		ExportDate:       time.Now().Unix(),
+		Name:             stack.Name,
+		Type:             stack.Type,
+		StackFileContent: string(stackFileContent),
+		Env:              stack.Env,
+		EnvFiles:         make([]stackBundleEnvFile, 0, len(stack.EnvFiles)),
+	}
+
+	for _, envFile := range stack.EnvFiles {
+		data, err := handler.FileService.GetFileContent(envFile.Path)
+		if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve variable file from disk", err}
+		}
+
+		env := stackfile.ParseEnvFile(data)
+		if envFile.Secret && !isAdmin {
+			for idx := range env {
+				env[idx].Value = ""
+			}
+		}
+
+		bundle.EnvFiles = append(bundle.EnvFiles, stackBundleEnvFile{Name: envFile.Name, Secret: envFile.Secret, Env: env})
+	}
+
+	if stack.CustomTemplateID != 0 {
+		customTemplate, err := handler.DataStore.CustomTemplate().CustomTemplate(stack.CustomTemplateID)
+		if err != nil && err != bolterrors.ErrObjectNotFound {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve the custom template of the stack from the database", err}
+		}
+
+		if customTemplate != nil {
+			templateFileContent, err := handler.FileService.GetFileContent(path.Join(customTemplate.ProjectPath, customTemplate.EntryPoint))
+			if err != nil {
+				return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve custom template file from disk", err}
+			}
+
+			bundle.CustomTemplate = &stackBundleTemplate{
+				Title:       customTemplate.Title,
+				Description: customTemplate.Description,
+				Note:        customTemplate.Note,
+				Logo:        customTemplate.Logo,
+				Platform:    customTemplate.Platform,
+				Type:        customTemplate.Type,
+				FileContent: string(templateFileContent),
+			}
+		}
+	}
+
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s.stack.json", stack.Name))
+	return response.JSON(w, bundle)
+}
@@ -0,0 +1,223 @@
+package stacks
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/asaskevich/govalidator"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/filesystem"
+	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/authorization"
+	"github.com/portainer/portainer/api/internal/stackfile"
+)
+
+type stackImportPayload struct {
+	Bundle  stackBundle
+	Name    string
+	SwarmID string
+	Env     []portainer.Pair
+}
+
+func (payload *stackImportPayload) Validate(r *http.Request) error {
+	if payload.Bundle.Version != stackBundleVersion {
+		return errors.New("Invalid bundle. Unsupported bundle version")
+	}
+	if payload.Bundle.Type != portainer.DockerSwarmStack && payload.Bundle.Type != portainer.DockerComposeStack {
+		return errors.New("Invalid bundle. Only Swarm and Compose stacks can be imported")
+	}
+	if govalidator.IsNull(payload.Bundle.StackFileContent) {
+		return errors.New("Invalid bundle. Missing stack file content")
+	}
+	if govalidator.IsNull(payload.Name) {
+		payload.Name = payload.Bundle.Name
+	}
+	if govalidator.IsNull(payload.Name) {
+		return errors.New("Invalid stack name")
+	}
+	if payload.Bundle.Type == portainer.DockerComposeStack {
+		payload.Name = normalizeStackName(payload.Name)
+	}
+	if payload.Bundle.Type == portainer.DockerSwarmStack && govalidator.IsNull(payload.SwarmID) {
+		return errors.New("Invalid Swarm ID")
+	}
+	for _, envFile := range payload.Bundle.EnvFiles {
+		if !envFileNamePattern.MatchString(envFile.Name) {
+			return errors.New("Invalid bundle. Invalid variable file name")
+		}
+	}
+	return nil
+}
+
+// POST request on /api/stacks/import?endpointId=<endpointId>
+// Deploys a stack exported with /api/stacks/:id/export on the specified endpoint. The stack can be renamed
+// and the variables of the payload override the variables of the bundle. The custom template of the bundle
+// is created when no custom template with the same title exists.
+func (handler *Handler) stackImport(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	endpointID, err := request.RetrieveNumericQueryParameter(r, "endpointId", false)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid query parameter: endpointId", err}
+	}
+
+	var payload stackImportPayload
+	err = request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	endpoint, handlerErr := handler.retrieveStackCreationEndpoint(r, portainer.EndpointID(endpointID))
+	if handlerErr != nil {
+		return handlerErr
+	}
+
+	handlerErr = handler.checkStackQuota(endpoint.OrganizationID, 1)
+	if handlerErr != nil {
+		return handlerErr
+	}
+
+	stacks, err := handler.DataStore.Stack().Stacks()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve stacks from the database", err}
+	}
+
+	for _, stack := range stacks {
+		if strings.EqualFold(stack.Name, payload.Name) {
+			return &httperror.HandlerError{http.StatusConflict, "A stack with this name already exists", errStackAlreadyExists}
+		}
+	}
+
+	securityContext, err := security.RetrieveRestrictedRequestContext(r)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve info from request context", err}
+	}
+
+	stackID := handler.DataStore.Stack().GetNextIdentifier()
+	stack := &portainer.Stack{
+		ID:         portainer.StackID(stackID),
+		Name:       payload.Name,
+		Type:       payload.Bundle.Type,
+		EndpointID: endpoint.ID,
+		EntryPoint: filesystem.ComposeFileDefaultName,
+		Env:        stackfile.MergeEnv(payload.Bundle.Env, payload.Env),
+		EnvFiles:   make([]portainer.StackEnvFile, 0, len(payload.Bundle.EnvFiles)),
+		Status:     portainer.StackStatusActive,
+	}
+	if stack.Type == portainer.DockerSwarmStack {
+		stack.SwarmID = payload.SwarmID
+	}
+
+	stackFolder := strconv.Itoa(int(stack.ID))
+	projectPath, err := handler.FileService.StoreStackFileFromBytes(stackFolder, stack.EntryPoint, []byte(payload.Bundle.StackFileContent))
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist Compose file on disk", err}
+	}
+	stack.ProjectPath = projectPath
+
+	doCleanUp := true
+	defer handler.cleanUp(stack, &doCleanUp)
+
+	for _, envFile := range payload.Bundle.EnvFiles {
+		envFilePath, err := handler.FileService.StoreStackEnvFileFromBytes(stackFolder, envFile.Name, stackfile.FormatEnvFile(envFile.Env))
+		if err != nil {
+			handler.FileService.DeleteStackEnvFiles(stackFolder)
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist the variable files on disk", err}
+		}
+		stack.EnvFiles = append(stack.EnvFiles, portainer.StackEnvFile{Name: envFile.Name, Secret: envFile.Secret, Path: envFilePath})
+	}
+
+	handlerErr = handler.deployImportedStack(r, stack, endpoint)
+	if handlerErr != nil {
+		handler.FileService.DeleteStackEnvFiles(stackFolder)
+		return handlerErr
+	}
+
+	if payload.Bundle.CustomTemplate != nil {
+		stack.CustomTemplateID, err = handler.importCustomTemplate(payload.Bundle.CustomTemplate, securityContext.UserID, securityContext.OrganizationID)
+		if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to import the custom template of the stack", err}
+		}
+	}
+
+	err = handler.DataStore.Stack().CreateStack(stack)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist the stack inside the database", err}
+	}
+
+	doCleanUp = false
+	return handler.decorateStackResponse(w, stack, securityContext.UserID)
+}
+
+func (handler *Handler) deployImportedStack(r *http.Request, stack *portainer.Stack, endpoint *portainer.Endpoint) *httperror.HandlerError {
+	var err error
+
+	if stack.Type == portainer.DockerSwarmStack {
+		config, configErr := handler.createSwarmDeployConfig(r, stack, endpoint, false)
+		if configErr != nil {
+			return configErr
+		}
+		err = handler.deploySwarmStack(config)
+	} else {
+		config, configErr := handler.createComposeDeployConfig(r, stack, endpoint)
+		if configErr != nil {
+			return configErr
+		}
+		err = handler.deployComposeStack(config)
+	}
+
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, err.Error(), err}
+	}
+	return nil
+}
+
+// importCustomTemplate returns the identifier of the custom template with the title of the imported template,
+// the template is created with a private resource control when it does not exist
+func (handler *Handler) importCustomTemplate(template *stackBundleTemplate, userID portainer.UserID, organizationID portainer.OrganizationID) (portainer.CustomTemplateID, error) {
+	customTemplates, err := handler.DataStore.CustomTemplate().CustomTemplates()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, customTemplate := range customTemplates {
+		if customTemplate.Title == template.Title {
+			return customTemplate.ID, nil
+		}
+	}
+
+	customTemplate := &portainer.CustomTemplate{
+		ID:              portainer.CustomTemplateID(handler.DataStore.CustomTemplate().GetNextIdentifier()),
+		Title:           template.Title,
+		Description:     template.Description,
+		Note:            template.Note,
+		Logo:            template.Logo,
+		Platform:        template.Platform,
+		Type:            template.Type,
+		EntryPoint:      filesystem.ComposeFileDefaultName,
+		CreatedByUserID: userID,
+		OrganizationID:  organizationID,
+	}
+
+	templateFolder := strconv.Itoa(int(customTemplate.ID))
+	projectPath, err := handler.FileService.StoreCustomTemplateFileFromBytes(templateFolder, customTemplate.EntryPoint, []byte(template.FileContent))
+	if err != nil {
+		return 0, err
+	}
+	customTemplate.ProjectPath = projectPath
+
+	err = handler.DataStore.CustomTemplate().CreateCustomTemplate(customTemplate)
+	if err != nil {
+		return 0, err
+	}
+
+	resourceControl := authorization.NewPrivateResourceControl(templateFolder, portainer.CustomTemplateResourceControl, userID)
+	err = handler.DataStore.ResourceControl().CreateResourceControl(resourceControl)
+	if err != nil {
+		return 0, err
+	}
+
+	return customTemplate.ID, nil
+}
@@ -0,0 +1,361 @@
+package stacks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/docker"
+	"github.com/portainer/portainer/api/internal/stackfile"
+)
+
+const (
+	stackLifecycleStart   = "start"
+	stackLifecycleStop    = "stop"
+	stackLifecycleRestart = "restart"
+	stackLifecyclePause   = "pause"
+	stackLifecycleUnpause = "unpause"
+
+	// stoppedReplicasLabel stores the number of replicas of a Swarm service stopped with its stack,
+	// they are restored when the stack is started
+	stoppedReplicasLabel = "io.portainer.stack.replicas"
+)
+
+var (
+	errStackLifecycleUnsupported = errors.New("Lifecycle operations are only available for Docker stacks")
+	errSwarmStackPause           = errors.New("The services of a Swarm stack cannot be paused")
+)
+
+type (
+	stackLifecycleResult struct {
+		ID      string `json:"Id"`
+		Name    string `json:"Name"`
+		Service string `json:"Service"`
+		Success bool   `json:"Success"`
+		Skipped bool   `json:"Skipped,omitempty"`
+		Error   string `json:"Error,omitempty"`
+	}
+
+	stackLifecycleResponse struct {
+		Action    string                 `json:"Action"`
+		Success   bool                   `json:"Success"`
+		Succeeded int                    `json:"Succeeded"`
+		Failed    int                    `json:"Failed"`
+		Skipped   int                    `json:"Skipped"`
+		Order     [][]string             `json:"Order"`
+		Results   []stackLifecycleResult `json:"Results"`
+	}
+
+	// stackLifecycleTarget is a container of a Compose stack or a service of a Swarm stack
+	stackLifecycleTarget struct {
+		id      string
+		name    string
+		service string
+		state   string
+	}
+)
+
+// POST request on /api/stacks/:id/lifecycle/:action?timeout=<seconds>
+//
+// Starts, stops, restarts, pauses or unpauses the containers of a Compose stack or the services of a Swarm stack
+// without redeploying or removing them. The services are processed in the order of their dependencies: a service
+// is started after the services it depends on, and stopped before them. The services depending on a service that
+// failed to start are skipped. The Swarm services are stopped by scaling them to 0, they cannot be paused.
+func (handler *Handler) stackLifecycle(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	action, err := request.RetrieveRouteVariableValue(r, "action")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid action route variable", err}
+	}
+
+	switch action {
+	case stackLifecycleStart, stackLifecycleStop, stackLifecycleRestart, stackLifecyclePause, stackLifecycleUnpause:
+	default:
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid action route variable", errors.New("Invalid action. Value must be one of: start, stop, restart, pause or unpause")}
+	}
+
+	timeoutSeconds, _ := request.RetrieveNumericQueryParameter(r, "timeout", true)
+	if timeoutSeconds < 0 {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid timeout query parameter", errors.New("Invalid timeout value. Value must be a positive number of seconds")}
+	}
+
+	var timeout *time.Duration
+	if timeoutSeconds > 0 {
+		duration := time.Duration(timeoutSeconds) * time.Second
+		timeout = &duration
+	}
+
+	stack, endpoint, _, handlerErr := handler.retrieveAccessibleStack(r)
+	if handlerErr != nil {
+		return handlerErr
+	}
+
+	if stack.Type != portainer.DockerSwarmStack && stack.Type != portainer.DockerComposeStack {
+		return &httperror.HandlerError{http.StatusBadRequest, "Lifecycle operations are only available for Docker stacks", errStackLifecycleUnsupported}
+	}
+
+	if stack.Type == portainer.DockerSwarmStack && (action == stackLifecyclePause || action == stackLifecycleUnpause) {
+		return &httperror.HandlerError{http.StatusBadRequest, "The services of a Swarm stack cannot be paused", errSwarmStackPause}
+	}
+
+	var graph *stackfile.Graph
+	if stack.ProjectPath != "" {
+		graph, err = handler.stackDependencyGraph(stack)
+		if err != nil {
+			log.Printf("[WARN] [http,stacks] [message: unable to parse the dependencies of the stack services, the services are processed without ordering] [stack: %s] [error: %s]", stack.Name, err)
+			graph = nil
+		}
+	}
+
+	if graph != nil && len(graph.Cycle) > 0 {
+		return &httperror.HandlerError{http.StatusBadRequest, fmt.Sprintf("The services %s depend on each other", strings.Join(graph.Cycle, ", ")), stackfile.ErrDependencyCycle}
+	}
+
+	cli, err := handler.DockerClientFactory.CreateClient(endpoint, "")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to create Docker client", err}
+	}
+	defer cli.Close()
+
+	var targets []stackLifecycleTarget
+	if stack.Type == portainer.DockerSwarmStack {
+		targets, err = swarmStackTargets(cli, stack)
+	} else {
+		targets, err = composeStackTargets(cli, stack)
+	}
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve the resources of the stack", err}
+	}
+
+	order := lifecycleOrder(graph, targets, action)
+	failed := make(map[string]bool)
+
+	resp := &stackLifecycleResponse{Action: action, Order: order, Results: make([]stackLifecycleResult, 0, len(targets))}
+	for _, batch := range order {
+		for _, service := range batch {
+			blockedBy := failedDependency(graph, service, failed)
+
+			for _, target := range targets {
+				if target.service != service {
+					continue
+				}
+
+				result := stackLifecycleResult{ID: target.id, Name: target.name, Service: service}
+
+				if blockedBy != "" && action != stackLifecycleStop && action != stackLifecyclePause {
+					result.Skipped = true
+					result.Error = fmt.Sprintf("The service %s it depends on could not be processed", blockedBy)
+					failed[service] = true
+					resp.Skipped++
+					resp.Results = append(resp.Results, result)
+					continue
+				}
+
+				if stack.Type == portainer.DockerSwarmStack {
+					err = executeServiceLifecycleAction(cli, target.id, action)
+				} else {
+					err = executeContainerLifecycleAction(cli, &target, action, timeout)
+				}
+
+				if err != nil {
+					result.Error = err.Error()
+					failed[service] = true
+					resp.Failed++
+				} else {
+					result.Success = true
+					resp.Succeeded++
+				}
+				resp.Results = append(resp.Results, result)
+			}
+		}
+	}
+
+	resp.Success = resp.Failed == 0 && resp.Skipped == 0
+
+	return response.JSON(w, resp)
+}
+
+func composeStackTargets(cli *client.Client, stack *portainer.Stack) ([]stackLifecycleTarget, error) {
+	containers, err := cli.ContainerList(context.Background(), types.ContainerListOptions{
+		All:     true,
+		Filters: filters.NewArgs(filters.Arg("label", docker.ComposeStackNameLabel+"="+stack.Name)),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	targets := make([]stackLifecycleTarget, 0, len(containers))
+	for _, container := range containers {
+		name := ""
+		if len(container.Names) > 0 {
+			name = strings.TrimPrefix(container.Names[0], "/")
+		}
+		targets = append(targets, stackLifecycleTarget{id: container.ID, name: name, service: container.Labels[docker.ComposeServiceLabel], state: container.State})
+	}
+
+	sort.Slice(targets, func(i, j int) bool {
+		return targets[i].name < targets[j].name
+	})
+
+	return targets, nil
+}
+
+func swarmStackTargets(cli *client.Client, stack *portainer.Stack) ([]stackLifecycleTarget, error) {
+	services, err := cli.ServiceList(context.Background(), types.ServiceListOptions{
+		Filters: filters.NewArgs(filters.Arg("label", docker.SwarmStackNameLabel+"="+stack.Name)),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	targets := make([]stackLifecycleTarget, 0, len(services))
+	for _, service := range services {
+		targets = append(targets, stackLifecycleTarget{id: service.ID, name: service.Spec.Name, service: strings.TrimPrefix(service.Spec.Name, stack.Name+"_")})
+	}
+
+	sort.Slice(targets, func(i, j int) bool {
+		return targets[i].name < targets[j].name
+	})
+
+	return targets, nil
+}
+
+// lifecycleOrder returns the services of the targets in the order they must be processed, the services
+// that are not part of the stack file are processed last
+func lifecycleOrder(graph *stackfile.Graph, targets []stackLifecycleTarget, action string) [][]string {
+	order := make([][]string, 0)
+	known := make(map[string]bool)
+
+	if graph != nil {
+		batches := graph.Order
+		if action == stackLifecycleStop || action == stackLifecyclePause {
+			batches, _ = graph.StopOrder()
+		}
+
+		for _, batch := range batches {
+			order = append(order, batch)
+			for _, service := range batch {
+				known[service] = true
+			}
+		}
+	}
+
+	remaining := make([]string, 0)
+	for _, target := range targets {
+		if !known[target.service] {
+			known[target.service] = true
+			remaining = append(remaining, target.service)
+		}
+	}
+
+	if len(remaining) > 0 {
+		sort.Strings(remaining)
+		order = append(order, remaining)
+	}
+
+	return order
+}
+
+// failedDependency returns the first dependency of a service that could not be processed
+func failedDependency(graph *stackfile.Graph, service string, failed map[string]bool) string {
+	if graph == nil {
+		return ""
+	}
+
+	for _, dependency := range graph.Dependencies {
+		if dependency.Service == service && failed[dependency.DependsOn] {
+			return dependency.DependsOn
+		}
+	}
+	return ""
+}
+
+func executeContainerLifecycleAction(cli *client.Client, target *stackLifecycleTarget, action string, timeout *time.Duration) error {
+	switch action {
+	case stackLifecycleStart:
+		if target.state == "paused" {
+			return cli.ContainerUnpause(context.Background(), target.id)
+		}
+		return cli.ContainerStart(context.Background(), target.id, types.ContainerStartOptions{})
+	case stackLifecycleStop:
+		return cli.ContainerStop(context.Background(), target.id, timeout)
+	case stackLifecycleRestart:
+		return cli.ContainerRestart(context.Background(), target.id, timeout)
+	case stackLifecyclePause:
+		if target.state != "running" {
+			return nil
+		}
+		return cli.ContainerPause(context.Background(), target.id)
+	case stackLifecycleUnpause:
+		if target.state != "paused" {
+			return nil
+		}
+		return cli.ContainerUnpause(context.Background(), target.id)
+	}
+	return nil
+}
+
+// executeServiceLifecycleAction stops a replicated service by scaling it to 0 and stores its number of replicas
+// in a label, the replicas are restored when the service is started. The services are restarted with a forced update.
+func executeServiceLifecycleAction(cli *client.Client, serviceID string, action string) error {
+	service, _, err := cli.ServiceInspectWithRaw(context.Background(), serviceID, types.ServiceInspectOptions{})
+	if err != nil {
+		return err
+	}
+
+	switch action {
+	case stackLifecycleStart:
+		value, ok := service.Spec.Labels[stoppedReplicasLabel]
+		if !ok || service.Spec.Mode.Replicated == nil {
+			return nil
+		}
+
+		replicas, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid number of replicas %s in the label %s", value, stoppedReplicasLabel)
+		}
+
+		service.Spec.Mode.Replicated.Replicas = &replicas
+		delete(service.Spec.Labels, stoppedReplicasLabel)
+	case stackLifecycleStop:
+		if service.Spec.Mode.Replicated == nil {
+			return fmt.Errorf("service %s is not a replicated service and cannot be scaled to 0", service.Spec.Name)
+		}
+
+		if service.Spec.Mode.Replicated.Replicas != nil && *service.Spec.Mode.Replicated.Replicas == 0 {
+			return nil
+		}
+
+		replicas := uint64(1)
+		if service.Spec.Mode.Replicated.Replicas != nil {
+			replicas = *service.Spec.Mode.Replicated.Replicas
+		}
+
+		if service.Spec.Labels == nil {
+			service.Spec.Labels = make(map[string]string)
+		}
+		service.Spec.Labels[stoppedReplicasLabel] = strconv.FormatUint(replicas, 10)
+
+		stopped := uint64(0)
+		service.Spec.Mode.Replicated.Replicas = &stopped
+	case stackLifecycleRestart:
+		service.Spec.TaskTemplate.ForceUpdate++
+	default:
+		return nil
+	}
+
+	_, err = cli.ServiceUpdate(context.Background(), service.ID, service.Version, service.Spec, types.ServiceUpdateOptions{})
+	return err
+}
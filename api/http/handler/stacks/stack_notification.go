@@ -0,0 +1,30 @@
+package stacks
+
+import (
+	"fmt"
+
+	"github.com/portainer/portainer/api"
+)
+
+// notifyDeployment reports the outcome of a stack deployment in the inbox of the user who started it
+func (handler *Handler) notifyDeployment(user *portainer.User, stack *portainer.Stack, endpoint *portainer.Endpoint, deployErr error) {
+	if handler.NotificationService == nil {
+		return
+	}
+
+	notification := &portainer.Notification{
+		Type:       portainer.StackDeployedNotification,
+		EndpointID: endpoint.ID,
+		ResourceID: stack.Name,
+		Title:      "Stack deployed",
+		Message:    fmt.Sprintf("Stack %s was successfully deployed on endpoint %s", stack.Name, endpoint.Name),
+	}
+
+	if deployErr != nil {
+		notification.Type = portainer.StackDeploymentFailedNotification
+		notification.Title = "Stack deployment failed"
+		notification.Message = fmt.Sprintf("Deployment of stack %s on endpoint %s failed: %s", stack.Name, endpoint.Name, deployErr)
+	}
+
+	handler.NotificationService.NotifyUser(user.ID, notification)
+}
@@ -0,0 +1,582 @@
+package stacks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/docker/cli/cli/compose/loader"
+	composetypes "github.com/docker/cli/cli/compose/types"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/docker"
+	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/stackfile"
+)
+
+const (
+	composeProjectLabel = "com.docker.compose.project"
+	composeServiceLabel = "com.docker.compose.service"
+	swarmNamespaceLabel = "com.docker.stack.namespace"
+)
+
+const (
+	planActionCreate = "create"
+	planActionUpdate = "update"
+	planActionRemove = "remove"
+	planActionKeep   = "unchanged"
+	// planActionOrphan is used for the resources of the stack that are not defined in the stack file anymore
+	// but that are not removed by the deployment
+	planActionOrphan = "orphan"
+)
+
+var errDryRunUnsupportedMethod = errors.New("Dry-run is only supported for the string and file methods")
+
+type (
+	// stackPlan describes the changes that a deployment of a stack would apply on an endpoint
+	stackPlan struct {
+		Valid     bool                `json:"Valid"`
+		Errors    []string            `json:"Errors"`
+		Conflicts []string            `json:"Conflicts"`
+		Warnings  []string            `json:"Warnings"`
+		Services  []stackPlanResource `json:"Services"`
+		Networks  []stackPlanResource `json:"Networks"`
+		Volumes   []stackPlanResource `json:"Volumes"`
+	}
+
+	stackPlanResource struct {
+		Name   string   `json:"Name"`
+		Action string   `json:"Action"`
+		Image  string   `json:"Image,omitempty"`
+		Digest string   `json:"Digest,omitempty"`
+		Ports  []string `json:"Ports,omitempty"`
+	}
+
+	// stackPlanRequest represents the stack deployment being planned
+	stackPlanRequest struct {
+		name             string
+		stackType        portainer.StackType
+		stackFileContent []byte
+		env              []portainer.Pair
+		prune            bool
+		isNew            bool
+		endpoint         *portainer.Endpoint
+	}
+
+	stackPlanPayload struct {
+		Name             string
+		StackFileContent string
+		Env              []portainer.Pair
+		Prune            bool
+	}
+)
+
+// planStackCreate handles the stack creation requests using the dryRun query parameter
+func (handler *Handler) planStackCreate(w http.ResponseWriter, r *http.Request, stackType portainer.StackType, method string, endpoint *portainer.Endpoint) *httperror.HandlerError {
+	planRequest := &stackPlanRequest{
+		stackType: stackType,
+		isNew:     true,
+		endpoint:  endpoint,
+	}
+
+	switch method {
+	case "string":
+		var payload stackPlanPayload
+		err := request.DecodeAndValidateJSONPayload(r, &payload)
+		if err != nil {
+			return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+		}
+		planRequest.name = payload.Name
+		planRequest.stackFileContent = []byte(payload.StackFileContent)
+		planRequest.env = payload.Env
+	case "file":
+		payload := &composeStackFromFileUploadPayload{}
+		err := payload.Validate(r)
+		if err != nil {
+			return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+		}
+		planRequest.name = payload.Name
+		planRequest.stackFileContent = payload.StackFileContent
+		planRequest.env = payload.Env
+	default:
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid value for query parameter: method", errDryRunUnsupportedMethod}
+	}
+
+	if planRequest.name == "" {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", errors.New("Invalid stack name")}
+	}
+
+	if stackType == portainer.DockerComposeStack {
+		planRequest.name = normalizeStackName(planRequest.name)
+	}
+
+	return handler.respondStackPlan(w, r, planRequest)
+}
+
+// planStackUpdate handles the stack update requests using the dryRun query parameter
+func (handler *Handler) planStackUpdate(w http.ResponseWriter, r *http.Request, stack *portainer.Stack, endpoint *portainer.Endpoint) *httperror.HandlerError {
+	var payload stackPlanPayload
+	err := request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	env, err := stackfile.Environment(&portainer.Stack{EnvFiles: stack.EnvFiles, Env: payload.Env})
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to read the variable files of the stack", err}
+	}
+
+	return handler.respondStackPlan(w, r, &stackPlanRequest{
+		name:             stack.Name,
+		stackType:        stack.Type,
+		stackFileContent: []byte(payload.StackFileContent),
+		env:              env,
+		prune:            payload.Prune,
+		endpoint:         endpoint,
+	})
+}
+
+func (payload *stackPlanPayload) Validate(r *http.Request) error {
+	if payload.StackFileContent == "" {
+		return errors.New("Invalid stack file content")
+	}
+	return nil
+}
+
+func (handler *Handler) respondStackPlan(w http.ResponseWriter, r *http.Request, planRequest *stackPlanRequest) *httperror.HandlerError {
+	securityContext, err := security.RetrieveRestrictedRequestContext(r)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve info from request context", err}
+	}
+
+	user, err := handler.DataStore.User().User(securityContext.UserID)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to load user information from the database", err}
+	}
+
+	dockerhub, err := handler.DataStore.DockerHub().DockerHub()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve DockerHub details from the database", err}
+	}
+
+	registries, err := handler.DataStore.Registry().Registries()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve registries from the database", err}
+	}
+	registries = security.FilterRegistries(registries, securityContext)
+
+	plan := &stackPlan{
+		Errors:    []string{},
+		Conflicts: []string{},
+		Warnings:  []string{},
+		Services:  []stackPlanResource{},
+		Networks:  []stackPlanResource{},
+		Volumes:   []stackPlanResource{},
+	}
+
+	composeConfig, err := loadStackConfig(planRequest.stackFileContent, planRequest.env)
+	if err != nil {
+		plan.Errors = append(plan.Errors, err.Error())
+		return response.JSON(w, plan)
+	}
+
+	isAdminOrEndpointAdmin, err := handler.userIsAdminOrEndpointAdmin(user, planRequest.endpoint.ID)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to verify user authorizations", err}
+	}
+
+	if !isAdminOrEndpointAdmin {
+		settings, err := handler.DataStore.Settings().Settings()
+		if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve settings from the database", err}
+		}
+
+		err = handler.isValidStackFile(planRequest.stackFileContent, settings)
+		if err != nil {
+			plan.Errors = append(plan.Errors, err.Error())
+		}
+	}
+
+	if planRequest.isNew {
+		stacks, err := handler.DataStore.Stack().Stacks()
+		if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve stacks from the database", err}
+		}
+
+		for _, stack := range stacks {
+			if strings.EqualFold(stack.Name, planRequest.name) {
+				plan.Conflicts = append(plan.Conflicts, fmt.Sprintf("A stack named %s already exists", stack.Name))
+			}
+		}
+	}
+
+	cli, err := handler.DockerClientFactory.CreateClient(planRequest.endpoint, "")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to create a Docker client", err}
+	}
+	defer cli.Close()
+
+	if planRequest.stackType == portainer.DockerSwarmStack {
+		err = planSwarmServices(cli, planRequest, composeConfig, plan)
+	} else {
+		err = planComposeServices(cli, planRequest, composeConfig, plan)
+	}
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve the resources of the endpoint", err}
+	}
+
+	err = planNetworksAndVolumes(cli, planRequest, composeConfig, plan)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve the resources of the endpoint", err}
+	}
+
+	resolveImageDigests(cli, registries, dockerhub, plan)
+
+	plan.Valid = len(plan.Errors) == 0 && len(plan.Conflicts) == 0
+	return response.JSON(w, plan)
+}
+
+// loadStackConfig parses and validates a stack file, the variables defined in the stack environment are interpolated
+func loadStackConfig(stackFileContent []byte, env []portainer.Pair) (*composetypes.Config, error) {
+	composeConfigYAML, err := loader.ParseYAML(stackFileContent)
+	if err != nil {
+		return nil, err
+	}
+
+	environment := make(map[string]string)
+	for _, pair := range env {
+		environment[pair.Name] = pair.Value
+	}
+
+	return loader.Load(composetypes.ConfigDetails{
+		ConfigFiles: []composetypes.ConfigFile{{Config: composeConfigYAML}},
+		Environment: environment,
+	})
+}
+
+func servicePorts(service *composetypes.ServiceConfig) []string {
+	ports := make([]string, 0)
+	for _, port := range service.Ports {
+		if port.Published == 0 {
+			continue
+		}
+		ports = append(ports, publishedPort(port.Published, port.Protocol))
+	}
+	return ports
+}
+
+func publishedPort(port uint32, protocol string) string {
+	if protocol == "" {
+		protocol = "tcp"
+	}
+	return fmt.Sprintf("%d/%s", port, strings.ToLower(protocol))
+}
+
+// checkDuplicatePorts reports the ports published by several services of the stack file
+func checkDuplicatePorts(plan *stackPlan) map[string]string {
+	owners := make(map[string]string)
+	for _, service := range plan.Services {
+		for _, port := range service.Ports {
+			if owner, ok := owners[port]; ok {
+				plan.Conflicts = append(plan.Conflicts, fmt.Sprintf("Port %s is published by services %s and %s", port, owner, service.Name))
+				continue
+			}
+			owners[port] = service.Name
+		}
+	}
+	return owners
+}
+
+func planComposeServices(cli *client.Client, planRequest *stackPlanRequest, composeConfig *composetypes.Config, plan *stackPlan) error {
+	containers, err := cli.ContainerList(context.Background(), types.ContainerListOptions{All: true})
+	if err != nil {
+		return err
+	}
+
+	existingServices := make(map[string]bool)
+	for _, container := range containers {
+		if container.Labels[composeProjectLabel] == planRequest.name {
+			existingServices[container.Labels[composeServiceLabel]] = true
+		}
+	}
+
+	for idx := range composeConfig.Services {
+		service := &composeConfig.Services[idx]
+
+		action := planActionCreate
+		if existingServices[service.Name] {
+			action = planActionUpdate
+		}
+
+		plan.Services = append(plan.Services, stackPlanResource{
+			Name:   service.Name,
+			Action: action,
+			Image:  service.Image,
+			Ports:  servicePorts(service),
+		})
+
+		if service.ContainerName == "" {
+			continue
+		}
+
+		for _, container := range containers {
+			if container.Labels[composeProjectLabel] == planRequest.name {
+				continue
+			}
+			for _, name := range container.Names {
+				if strings.TrimPrefix(name, "/") == service.ContainerName {
+					plan.Conflicts = append(plan.Conflicts, fmt.Sprintf("Container name %s of service %s is already used", service.ContainerName, service.Name))
+				}
+			}
+		}
+	}
+
+	planOrphanServices(existingServices, planActionOrphan, plan)
+
+	owners := checkDuplicatePorts(plan)
+	for _, container := range containers {
+		if container.Labels[composeProjectLabel] == planRequest.name || container.State != "running" {
+			continue
+		}
+
+		for _, port := range container.Ports {
+			if port.PublicPort == 0 {
+				continue
+			}
+
+			if owner, ok := owners[publishedPort(uint32(port.PublicPort), port.Type)]; ok {
+				plan.Conflicts = append(plan.Conflicts, fmt.Sprintf("Port %s of service %s is already published by container %s", publishedPort(uint32(port.PublicPort), port.Type), owner, strings.TrimPrefix(container.Names[0], "/")))
+			}
+		}
+	}
+
+	return nil
+}
+
+func planSwarmServices(cli *client.Client, planRequest *stackPlanRequest, composeConfig *composetypes.Config, plan *stackPlan) error {
+	services, err := cli.ServiceList(context.Background(), types.ServiceListOptions{})
+	if err != nil {
+		return err
+	}
+
+	existingServices := make(map[string]bool)
+	for _, service := range services {
+		if service.Spec.Labels[swarmNamespaceLabel] == planRequest.name {
+			existingServices[strings.TrimPrefix(service.Spec.Name, planRequest.name+"_")] = true
+		}
+	}
+
+	for idx := range composeConfig.Services {
+		service := &composeConfig.Services[idx]
+
+		action := planActionCreate
+		if existingServices[service.Name] {
+			action = planActionUpdate
+		}
+
+		plan.Services = append(plan.Services, stackPlanResource{
+			Name:   service.Name,
+			Action: action,
+			Image:  service.Image,
+			Ports:  servicePorts(service),
+		})
+	}
+
+	orphanAction := planActionOrphan
+	if planRequest.prune {
+		orphanAction = planActionRemove
+	}
+	planOrphanServices(existingServices, orphanAction, plan)
+
+	owners := checkDuplicatePorts(plan)
+	for _, service := range services {
+		if service.Spec.Labels[swarmNamespaceLabel] == planRequest.name {
+			continue
+		}
+
+		for _, port := range service.Endpoint.Ports {
+			if port.PublishedPort == 0 {
+				continue
+			}
+
+			if owner, ok := owners[publishedPort(port.PublishedPort, string(port.Protocol))]; ok {
+				plan.Conflicts = append(plan.Conflicts, fmt.Sprintf("Port %s of service %s is already published by service %s", publishedPort(port.PublishedPort, string(port.Protocol)), owner, service.Spec.Name))
+			}
+		}
+	}
+
+	return nil
+}
+
+func planOrphanServices(existingServices map[string]bool, action string, plan *stackPlan) {
+	defined := make(map[string]bool)
+	for _, service := range plan.Services {
+		defined[service.Name] = true
+	}
+
+	orphans := make([]string, 0)
+	for name := range existingServices {
+		if !defined[name] {
+			orphans = append(orphans, name)
+		}
+	}
+	sort.Strings(orphans)
+
+	for _, name := range orphans {
+		plan.Services = append(plan.Services, stackPlanResource{Name: name, Action: action})
+	}
+}
+
+func planNetworksAndVolumes(cli *client.Client, planRequest *stackPlanRequest, composeConfig *composetypes.Config, plan *stackPlan) error {
+	networks, err := cli.NetworkList(context.Background(), types.NetworkListOptions{})
+	if err != nil {
+		return err
+	}
+
+	existingNetworks := make(map[string]bool)
+	for _, network := range networks {
+		existingNetworks[network.Name] = true
+	}
+
+	networkConfigs := composeConfig.Networks
+	if usesDefaultNetwork(composeConfig) {
+		if networkConfigs == nil {
+			networkConfigs = make(map[string]composetypes.NetworkConfig)
+		}
+		if _, ok := networkConfigs["default"]; !ok {
+			networkConfigs["default"] = composetypes.NetworkConfig{}
+		}
+	}
+
+	for _, key := range sortedKeys(networkConfigs) {
+		network := networkConfigs[key]
+		name := resourceName(planRequest.name, key, network.Name, network.External)
+		resource, errMessage := planExternalResource("network", name, network.External.External, existingNetworks[name])
+		if errMessage != "" {
+			plan.Errors = append(plan.Errors, errMessage)
+		}
+		plan.Networks = append(plan.Networks, resource)
+	}
+
+	volumes, err := cli.VolumeList(context.Background(), filters.Args{})
+	if err != nil {
+		return err
+	}
+
+	existingVolumes := make(map[string]bool)
+	for _, volume := range volumes.Volumes {
+		existingVolumes[volume.Name] = true
+	}
+
+	volumeConfigs := composeConfig.Volumes
+	for _, key := range sortedVolumeKeys(volumeConfigs) {
+		volume := volumeConfigs[key]
+		name := resourceName(planRequest.name, key, volume.Name, volume.External)
+		resource, errMessage := planExternalResource("volume", name, volume.External.External, existingVolumes[name])
+		if errMessage != "" {
+			plan.Errors = append(plan.Errors, errMessage)
+		}
+		plan.Volumes = append(plan.Volumes, resource)
+	}
+
+	return nil
+}
+
+// planExternalResource returns the planned action for a network or a volume. The existing networks and volumes
+// are never updated by a deployment, external resources must exist before the deployment.
+func planExternalResource(resourceType, name string, external, exists bool) (stackPlanResource, string) {
+	resource := stackPlanResource{Name: name, Action: planActionCreate}
+	if exists {
+		resource.Action = planActionKeep
+		return resource, ""
+	}
+
+	if external {
+		resource.Action = planActionKeep
+		return resource, fmt.Sprintf("External %s %s does not exist", resourceType, name)
+	}
+
+	return resource, ""
+}
+
+func resourceName(stackName, key, name string, external composetypes.External) string {
+	if name != "" {
+		return name
+	}
+	if external.External {
+		if external.Name != "" {
+			return external.Name
+		}
+		return key
+	}
+	return stackName + "_" + key
+}
+
+func usesDefaultNetwork(composeConfig *composetypes.Config) bool {
+	for _, service := range composeConfig.Services {
+		if len(service.Networks) == 0 && service.NetworkMode == "" {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys(networks map[string]composetypes.NetworkConfig) []string {
+	keys := make([]string, 0, len(networks))
+	for key := range networks {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedVolumeKeys(volumes map[string]composetypes.VolumeConfig) []string {
+	keys := make([]string, 0, len(volumes))
+	for key := range volumes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// resolveImageDigests resolves the digest of the images of the services from their registry,
+// the images that cannot be resolved are reported as warnings
+func resolveImageDigests(cli *client.Client, registries []portainer.Registry, dockerhub *portainer.DockerHub, plan *stackPlan) {
+	digests := make(map[string]string)
+
+	for idx := range plan.Services {
+		service := &plan.Services[idx]
+		if service.Image == "" {
+			continue
+		}
+
+		if digest, ok := digests[service.Image]; ok {
+			service.Digest = digest
+			continue
+		}
+
+		registryAuth, err := docker.RegistryAuthentication(service.Image, registries, dockerhub)
+		if err != nil {
+			plan.Warnings = append(plan.Warnings, fmt.Sprintf("Unable to resolve image %s: %s", service.Image, err))
+			continue
+		}
+
+		distribution, err := cli.DistributionInspect(context.Background(), service.Image, registryAuth)
+		if err != nil {
+			plan.Warnings = append(plan.Warnings, fmt.Sprintf("Unable to resolve image %s: %s", service.Image, err))
+			digests[service.Image] = ""
+			continue
+		}
+
+		service.Digest = string(distribution.Descriptor.Digest)
+		digests[service.Image] = service.Digest
+	}
+}
@@ -0,0 +1,56 @@
+package stacks
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/docker"
+	"github.com/portainer/portainer/api/internal/stackfile"
+)
+
+// stackImages returns the images referenced by the services of a stack, the variables
+// defined in the environment of the stack are interpolated
+func (handler *Handler) stackImages(stack *portainer.Stack) ([]string, error) {
+	stackFileContent, err := handler.FileService.GetFileContent(path.Join(stack.ProjectPath, stack.EntryPoint))
+	if err != nil {
+		return nil, err
+	}
+
+	stackEnv, err := stackfile.Environment(stack)
+	if err != nil {
+		return nil, err
+	}
+
+	return stackfile.Images(stackFileContent, stackEnv)
+}
+
+// pullComposeStackImages pulls the images of a Compose stack before its deployment so that the progress
+// of each pull can be reported. A failed pull is only logged, the deployment reports the actual error
+// when the image is not available on the endpoint.
+func (handler *Handler) pullComposeStackImages(config *composeStackDeploymentConfig, reporter portainer.TaskReporter) error {
+	images, err := handler.stackImages(config.stack)
+	if err != nil {
+		return err
+	}
+
+	cli, err := handler.DockerClientFactory.CreateClient(config.endpoint, "")
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	for _, image := range images {
+		registryAuth, err := docker.RegistryAuthentication(image, config.registries, config.dockerhub)
+		if err != nil {
+			return err
+		}
+
+		err = docker.PullImage(cli, image, registryAuth, reporter)
+		if err != nil {
+			reporter.Log(fmt.Sprintf("Unable to pull image %s: %s", image, err))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,160 @@
+package stacks
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+	"github.com/portainer/portainer/api/docker"
+	httperrors "github.com/portainer/portainer/api/http/errors"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+type (
+	stackResourcesResponse struct {
+		ResourceControl *portainer.ResourceControl `json:"ResourceControl"`
+		Resources       []stackResource            `json:"Resources"`
+	}
+
+	stackResource struct {
+		docker.StackResource
+		ResourceControl *portainer.ResourceControl `json:"ResourceControl"`
+		Inherited       bool                       `json:"Inherited"`
+	}
+)
+
+// GET request on /api/stacks/:id/resources
+func (handler *Handler) stackResources(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	stackID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid stack identifier route variable", err}
+	}
+
+	stack, err := handler.DataStore.Stack().Stack(portainer.StackID(stackID))
+	if err == bolterrors.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find a stack with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a stack with the specified identifier inside the database", err}
+	}
+
+	endpoint, err := handler.DataStore.Endpoint().Endpoint(stack.EndpointID)
+	if err == bolterrors.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find an endpoint with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find an endpoint with the specified identifier inside the database", err}
+	}
+
+	err = handler.requestBouncer.AuthorizedEndpointOperation(r, endpoint)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusForbidden, "Permission denied to access endpoint", err}
+	}
+
+	securityContext, err := security.RetrieveRestrictedRequestContext(r)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve user info from request context", err}
+	}
+
+	resourceControl, err := handler.DataStore.ResourceControl().ResourceControlByResourceIDAndType(stack.Name, portainer.StackResourceControl)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve a resource control associated to the stack", err}
+	}
+
+	access, err := handler.userCanAccessStack(securityContext, endpoint.ID, resourceControl)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to verify user authorizations to validate stack access", err}
+	}
+	if !access {
+		return &httperror.HandlerError{http.StatusForbidden, "Access denied to resource", httperrors.ErrResourceAccessDenied}
+	}
+
+	resources, err := handler.retrieveStackResources(stack, endpoint)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve the resources associated to the stack", err}
+	}
+
+	resourceControls, err := handler.DataStore.ResourceControl().ResourceControls()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve resource controls from the database", err}
+	}
+
+	stackResources := make([]stackResource, 0)
+	for _, resource := range resources {
+		ownership := stackResource{
+			StackResource:   resource,
+			ResourceControl: findResourceControl(resource.ID, resource.Type, resourceControls),
+		}
+
+		if ownership.ResourceControl == nil && resourceControl != nil {
+			ownership.ResourceControl = resourceControl
+			ownership.Inherited = true
+		}
+
+		stackResources = append(stackResources, ownership)
+	}
+
+	return response.JSON(w, &stackResourcesResponse{
+		ResourceControl: resourceControl,
+		Resources:       stackResources,
+	})
+}
+
+func (handler *Handler) retrieveStackResources(stack *portainer.Stack, endpoint *portainer.Endpoint) ([]docker.StackResource, error) {
+	cli, err := handler.DockerClientFactory.CreateClient(endpoint, "")
+	if err != nil {
+		return nil, err
+	}
+	defer cli.Close()
+
+	return docker.StackResources(cli, stack)
+}
+
+// syncStackResourceControl propagates the resource control of a stack to the resources created by the stack.
+// The identifiers of these resources are stored as sub resources of the stack resource control. The resources with
+// a resource control created specifically for them keep it, they are reported with their own access control by
+// GET /api/stacks/:id/resources. Identifiers of resources that are no longer part of the stack are discarded.
+func (handler *Handler) syncStackResourceControl(stack *portainer.Stack, endpoint *portainer.Endpoint, resourceControl *portainer.ResourceControl) error {
+	if resourceControl == nil || stack.Type == portainer.KubernetesStack {
+		return nil
+	}
+
+	resources, err := handler.retrieveStackResources(stack, endpoint)
+	if err != nil {
+		return err
+	}
+
+	resourceControls, err := handler.DataStore.ResourceControl().ResourceControls()
+	if err != nil {
+		return err
+	}
+
+	subResourceIDs := make([]string, 0)
+	for _, resource := range resources {
+		childResourceControl := findResourceControl(resource.ID, resource.Type, resourceControls)
+		if childResourceControl != nil && childResourceControl.ID != resourceControl.ID {
+			continue
+		}
+
+		subResourceIDs = append(subResourceIDs, resource.ID)
+	}
+
+	resourceControl.SubResourceIDs = subResourceIDs
+	if resourceControl.ID == 0 {
+		return nil
+	}
+
+	return handler.DataStore.ResourceControl().UpdateResourceControl(resourceControl.ID, resourceControl)
+}
+
+// findResourceControl returns the resource control specifically created for a resource, ignoring
+// resource controls that only reference the resource as a sub resource.
+func findResourceControl(resourceID string, resourceType portainer.ResourceControlType, resourceControls []portainer.ResourceControl) *portainer.ResourceControl {
+	for idx := range resourceControls {
+		if resourceControls[idx].ResourceID == resourceID && resourceControls[idx].Type == resourceType {
+			return &resourceControls[idx]
+		}
+	}
+	return nil
+}
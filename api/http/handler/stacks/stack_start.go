@@ -2,6 +2,7 @@ package stacks
 
 import (
 	"errors"
+	"log"
 	"net/http"
 
 	httperrors "github.com/portainer/portainer/api/http/errors"
@@ -73,6 +74,11 @@ func (handler *Handler) stackStart(w http.ResponseWriter, r *http.Request) *http
 		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to update stack status", err}
 	}
 
+	err = handler.syncStackResourceControl(stack, endpoint, resourceControl)
+	if err != nil {
+		log.Printf("http error: Unable to propagate the stack resource control to the stack resources (err=%s)\n", err)
+	}
+
 	return response.JSON(w, stack)
 }
 
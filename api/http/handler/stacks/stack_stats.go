@@ -0,0 +1,318 @@
+package stacks
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/client"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/docker"
+)
+
+const (
+	defaultStackStatsHistory = 24 * time.Hour
+	// stackStatsConcurrency is the maximum number of container statistics retrieved at the same time,
+	// the Docker daemon takes about a second to compute each sample
+	stackStatsConcurrency = 8
+)
+
+var errStackStatsUnsupported = errors.New("The statistics are only available for Docker stacks")
+
+type (
+	// stackUsage is the sum of the resource usage of the containers of a stack or of one of its services
+	stackUsage struct {
+		Containers  int     `json:"Containers"`
+		CPUPercent  float64 `json:"CPUPercent"`
+		MemoryUsage uint64  `json:"MemoryUsage"`
+		MemoryLimit uint64  `json:"MemoryLimit"`
+		NetworkRx   uint64  `json:"NetworkRx"`
+		NetworkTx   uint64  `json:"NetworkTx"`
+		BlockRead   uint64  `json:"BlockRead"`
+		BlockWrite  uint64  `json:"BlockWrite"`
+	}
+
+	stackContainerStats struct {
+		ID      string                 `json:"Id"`
+		Name    string                 `json:"Name"`
+		Service string                 `json:"Service"`
+		Node    string                 `json:"Node,omitempty"`
+		Stats   *docker.ContainerStats `json:"Stats,omitempty"`
+		Error   string                 `json:"Error,omitempty"`
+	}
+
+	// stackReservationPoint is the CPU and memory reserved by the containers and services of a stack
+	// at the time of a snapshot
+	stackReservationPoint struct {
+		Time     int64   `json:"Time"`
+		CPU      float64 `json:"CPU"`
+		Memory   int64   `json:"Memory"`
+		Replicas int     `json:"Replicas"`
+	}
+
+	stackStatsResponse struct {
+		StackID portainer.StackID `json:"StackId"`
+		Time    int64             `json:"Time"`
+		Usage   stackUsage        `json:"Usage"`
+		// Unavailable is the number of running containers whose statistics could not be retrieved
+		Unavailable int                     `json:"Unavailable"`
+		Services    map[string]*stackUsage  `json:"Services"`
+		Containers  []stackContainerStats   `json:"Containers"`
+		History     []stackReservationPoint `json:"History"`
+	}
+
+	// stackStatsTarget is a running container of a stack and the node it runs on
+	stackStatsTarget struct {
+		id      string
+		name    string
+		service string
+		node    string
+	}
+)
+
+// GET request on /api/stacks/:id/stats?from=<timestamp>
+//
+// Returns the current resource usage of the running containers of the stack, aggregated for the stack and for
+// each service, and the history of the CPU and memory reserved by the stack since the from timestamp, as recorded
+// by the snapshots in the resource samples. The history of the last 24 hours is returned by default.
+func (handler *Handler) stackStats(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	now := time.Now().Unix()
+
+	from, _ := request.RetrieveNumericQueryParameter(r, "from", true)
+	if from == 0 {
+		from = int(now - int64(defaultStackStatsHistory.Seconds()))
+	}
+
+	if int64(from) >= now {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid query parameter: from", errors.New("The start of the history must be in the past")}
+	}
+
+	stack, endpoint, _, handlerErr := handler.retrieveAccessibleStack(r)
+	if handlerErr != nil {
+		return handlerErr
+	}
+
+	if stack.Type != portainer.DockerSwarmStack && stack.Type != portainer.DockerComposeStack {
+		return &httperror.HandlerError{http.StatusBadRequest, "The statistics are only available for Docker stacks", errStackStatsUnsupported}
+	}
+
+	cli, err := handler.DockerClientFactory.CreateClient(endpoint, "")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to create Docker client", err}
+	}
+	defer cli.Close()
+
+	var targets []stackStatsTarget
+	if stack.Type == portainer.DockerSwarmStack {
+		targets, err = swarmStackStatsTargets(cli, stack)
+	} else {
+		targets, err = composeStackStatsTargets(cli, stack)
+	}
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve the containers of the stack", err}
+	}
+
+	samples, err := handler.DataStore.ResourceSample().ResourceSamples(int64(from), now)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve resource samples from the database", err}
+	}
+
+	resp := &stackStatsResponse{
+		StackID:    stack.ID,
+		Time:       now,
+		Services:   make(map[string]*stackUsage),
+		Containers: handler.retrieveStackContainerStats(endpoint, targets),
+		History:    stackReservationHistory(samples, stack),
+	}
+
+	for _, container := range resp.Containers {
+		if container.Stats == nil {
+			resp.Unavailable++
+			continue
+		}
+
+		service, ok := resp.Services[container.Service]
+		if !ok {
+			service = &stackUsage{}
+			resp.Services[container.Service] = service
+		}
+
+		service.add(container.Stats)
+		resp.Usage.add(container.Stats)
+	}
+
+	return response.JSON(w, resp)
+}
+
+func (usage *stackUsage) add(stats *docker.ContainerStats) {
+	usage.Containers++
+	usage.CPUPercent += stats.CPUPercent
+	usage.MemoryUsage += stats.MemoryUsage
+	usage.MemoryLimit += stats.MemoryLimit
+	usage.NetworkRx += stats.NetworkRx
+	usage.NetworkTx += stats.NetworkTx
+	usage.BlockRead += stats.BlockRead
+	usage.BlockWrite += stats.BlockWrite
+}
+
+// retrieveStackContainerStats retrieves the statistics of the containers concurrently. The containers of the
+// Swarm services running on other nodes are reached through the agent.
+func (handler *Handler) retrieveStackContainerStats(endpoint *portainer.Endpoint, targets []stackStatsTarget) []stackContainerStats {
+	results := make([]stackContainerStats, len(targets))
+	semaphore := make(chan struct{}, stackStatsConcurrency)
+
+	var wg sync.WaitGroup
+	for idx, target := range targets {
+		results[idx] = stackContainerStats{ID: target.id, Name: target.name, Service: target.service, Node: target.node}
+
+		wg.Add(1)
+		go func(result *stackContainerStats) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			nodeName := ""
+			if endpoint.Type == portainer.AgentOnDockerEnvironment || endpoint.Type == portainer.EdgeAgentOnDockerEnvironment {
+				nodeName = result.Node
+			}
+
+			cli, err := handler.DockerClientFactory.CreateClient(endpoint, nodeName)
+			if err != nil {
+				result.Error = err.Error()
+				return
+			}
+			defer cli.Close()
+
+			stats, err := docker.RetrieveContainerStats(cli, result.ID)
+			if err != nil {
+				result.Error = err.Error()
+				return
+			}
+			result.Stats = stats
+		}(&results[idx])
+	}
+	wg.Wait()
+
+	return results
+}
+
+func composeStackStatsTargets(cli *client.Client, stack *portainer.Stack) ([]stackStatsTarget, error) {
+	containers, err := cli.ContainerList(context.Background(), types.ContainerListOptions{
+		Filters: filters.NewArgs(filters.Arg("label", docker.ComposeStackNameLabel+"="+stack.Name)),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	targets := make([]stackStatsTarget, 0, len(containers))
+	for _, container := range containers {
+		name := ""
+		if len(container.Names) > 0 {
+			name = strings.TrimPrefix(container.Names[0], "/")
+		}
+		targets = append(targets, stackStatsTarget{id: container.ID, name: name, service: container.Labels[docker.ComposeServiceLabel]})
+	}
+
+	sort.Slice(targets, func(i, j int) bool {
+		return targets[i].name < targets[j].name
+	})
+
+	return targets, nil
+}
+
+// swarmStackStatsTargets returns the containers of the running tasks of the services of a Swarm stack
+func swarmStackStatsTargets(cli *client.Client, stack *portainer.Stack) ([]stackStatsTarget, error) {
+	tasks, err := cli.TaskList(context.Background(), types.TaskListOptions{
+		Filters: filters.NewArgs(
+			filters.Arg("label", docker.SwarmStackNameLabel+"="+stack.Name),
+			filters.Arg("desired-state", string(swarm.TaskStateRunning)),
+		),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	nodes, err := cli.NodeList(context.Background(), types.NodeListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	nodeNames := make(map[string]string)
+	for _, node := range nodes {
+		nodeNames[node.ID] = node.Description.Hostname
+	}
+
+	services, err := cli.ServiceList(context.Background(), types.ServiceListOptions{
+		Filters: filters.NewArgs(filters.Arg("label", docker.SwarmStackNameLabel+"="+stack.Name)),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	serviceNames := make(map[string]string)
+	for _, service := range services {
+		serviceNames[service.ID] = strings.TrimPrefix(service.Spec.Name, stack.Name+"_")
+	}
+
+	targets := make([]stackStatsTarget, 0, len(tasks))
+	for _, task := range tasks {
+		if task.Status.State != swarm.TaskStateRunning || task.Status.ContainerStatus == nil || task.Status.ContainerStatus.ContainerID == "" {
+			continue
+		}
+
+		targets = append(targets, stackStatsTarget{
+			id:      task.Status.ContainerStatus.ContainerID,
+			name:    task.ID,
+			service: serviceNames[task.ServiceID],
+			node:    nodeNames[task.NodeID],
+		})
+	}
+
+	sort.Slice(targets, func(i, j int) bool {
+		if targets[i].service != targets[j].service {
+			return targets[i].service < targets[j].service
+		}
+		return targets[i].name < targets[j].name
+	})
+
+	return targets, nil
+}
+
+// stackReservationHistory sums the reservations of the stack recorded in each resource sample of its endpoint
+func stackReservationHistory(samples []portainer.ResourceSample, stack *portainer.Stack) []stackReservationPoint {
+	history := make([]stackReservationPoint, 0)
+
+	for _, sample := range samples {
+		if sample.EndpointID != stack.EndpointID {
+			continue
+		}
+
+		point := stackReservationPoint{Time: sample.Time}
+		for _, reservation := range sample.Reservations {
+			if reservation.Stack != stack.Name {
+				continue
+			}
+
+			point.CPU += reservation.CPU * float64(reservation.Replicas)
+			point.Memory += reservation.Memory * int64(reservation.Replicas)
+			point.Replicas += reservation.Replicas
+		}
+		history = append(history, point)
+	}
+
+	sort.Slice(history, func(i, j int) bool {
+		return history[i].Time < history[j].Time
+	})
+
+	return history
+}
@@ -0,0 +1,50 @@
+package stacks
+
+import (
+	"errors"
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/docker"
+)
+
+var errStackStatusUnsupported = errors.New("The status is only available for Docker stacks")
+
+type stackStatusResponse struct {
+	*docker.StackHealth
+	StackID        portainer.StackID `json:"StackId"`
+	DeploymentDate int64             `json:"DeploymentDate"`
+}
+
+// GET request on /api/stacks/:id/status
+// Aggregates the state of the containers and services of the stack. The Status field of the response
+// is one of healthy, starting, unhealthy or down.
+func (handler *Handler) stackStatus(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	stack, endpoint, _, handlerErr := handler.retrieveAccessibleStack(r)
+	if handlerErr != nil {
+		return handlerErr
+	}
+
+	if stack.Type != portainer.DockerSwarmStack && stack.Type != portainer.DockerComposeStack {
+		return &httperror.HandlerError{http.StatusBadRequest, "The status is only available for Docker stacks", errStackStatusUnsupported}
+	}
+
+	cli, err := handler.DockerClientFactory.CreateClient(endpoint, "")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to create Docker client", err}
+	}
+	defer cli.Close()
+
+	health, err := docker.StackStatus(cli, stack)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve the state of the stack resources", err}
+	}
+
+	return response.JSON(w, &stackStatusResponse{
+		StackHealth:    health,
+		StackID:        stack.ID,
+		DeploymentDate: stack.DeploymentDate,
+	})
+}
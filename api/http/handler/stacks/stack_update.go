@@ -2,6 +2,8 @@ package stacks
 
 import (
 	"errors"
+	"fmt"
+	"log"
 	"net/http"
 	"strconv"
 
@@ -40,7 +42,11 @@ func (payload *updateSwarmStackPayload) Validate(r *http.Request) error {
 	return nil
 }
 
-// PUT request on /api/stacks/:id?endpointId=<endpointId>
+// PUT request on /api/stacks/:id?endpointId=<endpointId>&async=<async>&dryRun=<dryRun>
+// When the async query parameter is set to true, the stack is deployed in a background task
+// and the task is returned instead of the stack.
+// When the dryRun query parameter is set to true, the changes that the update would apply are returned
+// and nothing is persisted nor deployed.
 func (handler *Handler) stackUpdate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
 	stackID, err := request.RetrieveNumericRouteVariableValue(r, "id")
 	if err != nil {
@@ -95,12 +101,48 @@ func (handler *Handler) stackUpdate(w http.ResponseWriter, r *http.Request) *htt
 		return &httperror.HandlerError{http.StatusForbidden, "Access denied to resource", httperrors.ErrResourceAccessDenied}
 	}
 
-	updateError := handler.updateAndDeployStack(r, stack, endpoint)
+	dryRun, _ := request.RetrieveBooleanQueryParameter(r, "dryRun", true)
+	if dryRun {
+		return handler.planStackUpdate(w, r, stack, endpoint)
+	}
+
+	async, _ := request.RetrieveBooleanQueryParameter(r, "async", true)
+
+	deploy, updateError := handler.updateStack(r, stack, endpoint)
 	if updateError != nil {
 		return updateError
 	}
 
-	err = handler.DataStore.Stack().UpdateStack(stack.ID, stack)
+	if async {
+		task := &portainer.Task{
+			Type:       portainer.StackDeployTask,
+			UserID:     securityContext.UserID,
+			EndpointID: endpoint.ID,
+			ResourceID: stack.Name,
+			Progress:   -1,
+		}
+
+		err = handler.TaskManager.Run(task, func(reporter portainer.TaskReporter) error {
+			err := deploy(reporter)
+			if err != nil {
+				return err
+			}
+
+			return handler.persistUpdatedStack(stack, endpoint, resourceControl)
+		})
+		if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to start the stack deployment task", err}
+		}
+
+		return response.JSON(w, task)
+	}
+
+	err = deploy(nil)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, err.Error(), err}
+	}
+
+	err = handler.persistUpdatedStack(stack, endpoint, resourceControl)
 	if err != nil {
 		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist the stack changes inside the database", err}
 	}
@@ -108,18 +150,36 @@ func (handler *Handler) stackUpdate(w http.ResponseWriter, r *http.Request) *htt
 	return response.JSON(w, stack)
 }
 
-func (handler *Handler) updateAndDeployStack(r *http.Request, stack *portainer.Stack, endpoint *portainer.Endpoint) *httperror.HandlerError {
+func (handler *Handler) persistUpdatedStack(stack *portainer.Stack, endpoint *portainer.Endpoint, resourceControl *portainer.ResourceControl) error {
+	err := handler.DataStore.Stack().UpdateStack(stack.ID, stack)
+	if err != nil {
+		return err
+	}
+
+	err = handler.syncStackResourceControl(stack, endpoint, resourceControl)
+	if err != nil {
+		log.Printf("http error: Unable to propagate the stack resource control to the stack resources (err=%s)\n", err)
+	}
+
+	return nil
+}
+
+// stackDeployFunc deploys a stack, the reporter is only specified when the deployment runs in a task
+type stackDeployFunc func(reporter portainer.TaskReporter) error
+
+// updateStack stores the updated stack file and returns the function deploying the updated stack
+func (handler *Handler) updateStack(r *http.Request, stack *portainer.Stack, endpoint *portainer.Endpoint) (stackDeployFunc, *httperror.HandlerError) {
 	if stack.Type == portainer.DockerSwarmStack {
 		return handler.updateSwarmStack(r, stack, endpoint)
 	}
 	return handler.updateComposeStack(r, stack, endpoint)
 }
 
-func (handler *Handler) updateComposeStack(r *http.Request, stack *portainer.Stack, endpoint *portainer.Endpoint) *httperror.HandlerError {
+func (handler *Handler) updateComposeStack(r *http.Request, stack *portainer.Stack, endpoint *portainer.Endpoint) (stackDeployFunc, *httperror.HandlerError) {
 	var payload updateComposeStackPayload
 	err := request.DecodeAndValidateJSONPayload(r, &payload)
 	if err != nil {
-		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+		return nil, &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
 	}
 
 	stack.Env = payload.Env
@@ -127,27 +187,31 @@ func (handler *Handler) updateComposeStack(r *http.Request, stack *portainer.Sta
 	stackFolder := strconv.Itoa(int(stack.ID))
 	_, err = handler.FileService.StoreStackFileFromBytes(stackFolder, stack.EntryPoint, []byte(payload.StackFileContent))
 	if err != nil {
-		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist updated Compose file on disk", err}
+		return nil, &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist updated Compose file on disk", err}
 	}
 
 	config, configErr := handler.createComposeDeployConfig(r, stack, endpoint)
 	if configErr != nil {
-		return configErr
-	}
-
-	err = handler.deployComposeStack(config)
-	if err != nil {
-		return &httperror.HandlerError{http.StatusInternalServerError, err.Error(), err}
-	}
-
-	return nil
+		return nil, configErr
+	}
+
+	return func(reporter portainer.TaskReporter) error {
+		if reporter != nil {
+			err := handler.pullComposeStackImages(config, reporter)
+			if err != nil {
+				reporter.Log(fmt.Sprintf("Unable to pull the stack images: %s", err))
+			}
+			reporter.SetProgress(-1, "Deploying stack "+stack.Name)
+		}
+		return handler.deployComposeStack(config)
+	}, nil
 }
 
-func (handler *Handler) updateSwarmStack(r *http.Request, stack *portainer.Stack, endpoint *portainer.Endpoint) *httperror.HandlerError {
+func (handler *Handler) updateSwarmStack(r *http.Request, stack *portainer.Stack, endpoint *portainer.Endpoint) (stackDeployFunc, *httperror.HandlerError) {
 	var payload updateSwarmStackPayload
 	err := request.DecodeAndValidateJSONPayload(r, &payload)
 	if err != nil {
-		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+		return nil, &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
 	}
 
 	stack.Env = payload.Env
@@ -155,18 +219,19 @@ func (handler *Handler) updateSwarmStack(r *http.Request, stack *portainer.Stack
 	stackFolder := strconv.Itoa(int(stack.ID))
 	_, err = handler.FileService.StoreStackFileFromBytes(stackFolder, stack.EntryPoint, []byte(payload.StackFileContent))
 	if err != nil {
-		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist updated Compose file on disk", err}
+		return nil, &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist updated Compose file on disk", err}
 	}
 
 	config, configErr := handler.createSwarmDeployConfig(r, stack, endpoint, payload.Prune)
 	if configErr != nil {
-		return configErr
-	}
-
-	err = handler.deploySwarmStack(config)
-	if err != nil {
-		return &httperror.HandlerError{http.StatusInternalServerError, err.Error(), err}
+		return nil, configErr
 	}
 
-	return nil
+	// the images of a Swarm stack are pulled by each node running its tasks
+	return func(reporter portainer.TaskReporter) error {
+		if reporter != nil {
+			reporter.SetProgress(-1, "Deploying stack "+stack.Name)
+		}
+		return handler.deploySwarmStack(config)
+	}, nil
 }
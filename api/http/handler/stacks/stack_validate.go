@@ -0,0 +1,50 @@
+package stacks
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/asaskevich/govalidator"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/internal/stackfile"
+)
+
+type stackValidatePayload struct {
+	StackFileContent string
+	Env              []portainer.Pair
+	Type             portainer.StackType
+}
+
+func (payload *stackValidatePayload) Validate(r *http.Request) error {
+	if govalidator.IsNull(payload.StackFileContent) {
+		return errors.New("Invalid stack file content")
+	}
+	if payload.Type != 0 && payload.Type != portainer.DockerSwarmStack && payload.Type != portainer.DockerComposeStack {
+		return errors.New("Invalid stack type. Value must be one of: 1 (Swarm stack) or 2 (Compose stack)")
+	}
+	return nil
+}
+
+type stackValidateResponse struct {
+	Valid    bool                `json:"Valid"`
+	Problems []stackfile.Problem `json:"Problems"`
+}
+
+// POST request on /api/stacks/validate
+func (handler *Handler) stackValidate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	var payload stackValidatePayload
+	err := request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	problems := stackfile.Lint([]byte(payload.StackFileContent), payload.Env, payload.Type)
+
+	return response.JSON(w, &stackValidateResponse{
+		Valid:    !stackfile.HasErrors(problems),
+		Problems: problems,
+	})
+}
@@ -7,12 +7,14 @@ import (
 	httperror "github.com/portainer/libhttp/error"
 	"github.com/portainer/portainer/api"
 	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/diagnostics"
 )
 
 // Handler is the HTTP handler used to handle status operations.
 type Handler struct {
 	*mux.Router
-	Status *portainer.Status
+	Status             *portainer.Status
+	DiagnosticsService *diagnostics.Service
 }
 
 // NewHandler creates a handler to manage status operations.
@@ -25,6 +27,8 @@ func NewHandler(bouncer *security.RequestBouncer, status *portainer.Status) *Han
 		bouncer.PublicAccess(httperror.LoggerHandler(h.statusInspect))).Methods(http.MethodGet)
 	h.Handle("/status/version",
 		bouncer.AuthenticatedAccess(http.HandlerFunc(h.statusInspectVersion))).Methods(http.MethodGet)
+	h.Handle("/status/diagnostics",
+		bouncer.InstanceAdminAccess(httperror.LoggerHandler(h.statusDiagnostics))).Methods(http.MethodGet)
 
 	return h
 }
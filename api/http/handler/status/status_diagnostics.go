@@ -0,0 +1,24 @@
+package status
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	httperror "github.com/portainer/libhttp/error"
+)
+
+// GET request on /api/status/diagnostics
+func (handler *Handler) statusDiagnostics(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	filename := fmt.Sprintf("portainer-support-%s.zip", time.Now().UTC().Format("20060102-150405"))
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+
+	err := handler.DiagnosticsService.WriteBundle(w)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to generate the support bundle", err}
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package swarm
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	httperrors "github.com/portainer/portainer/api/http/errors"
+)
+
+// POST request on /api/endpoints/:id/swarm/configs/:configId/rotate
+func (handler *Handler) configRotate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	configID, err := request.RetrieveRouteVariableValue(r, "configId")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid config identifier route variable", err}
+	}
+
+	var payload rotationPayload
+	err = request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	operationContext, httpErr := handler.newOperationContext(r)
+	if httpErr != nil {
+		return httpErr
+	}
+	defer operationContext.cli.Close()
+
+	cli := operationContext.cli
+
+	config, _, err := cli.ConfigInspectWithRaw(context.Background(), configID)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find a config with the specified identifier", err}
+	}
+
+	rot := &rotation{
+		cli:              cli,
+		dataStore:        handler.DataStore,
+		resourceType:     portainer.ConfigResourceControl,
+		previousID:       config.ID,
+		previousName:     config.Spec.Name,
+		newName:          newRotationName(config.Spec.Name, &payload),
+		remove:           func(id string) error { return cli.ConfigRemove(context.Background(), id) },
+		timeout:          rotationTimeout(&payload),
+		resourceControls: operationContext.resourceControls,
+	}
+
+	services, err := rot.referencingServices(payload.ServiceOrder)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve the services referencing the config", err}
+	}
+
+	if !userCanRotate(rot, config.Spec.Labels, services, operationContext.canAccess) {
+		return &httperror.HandlerError{http.StatusForbidden, "Access denied to the config or to one of the services referencing it", httperrors.ErrResourceAccessDenied}
+	}
+
+	data, _ := base64.StdEncoding.DecodeString(payload.Data)
+
+	spec := config.Spec
+	spec.Name = rot.newName
+	spec.Data = data
+
+	created, err := cli.ConfigCreate(context.Background(), spec)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to create the new version of the config", err}
+	}
+	rot.newID = created.ID
+
+	result, err := rot.execute(services, removePrevious(&payload))
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to rotate the config, changes were rolled back", err}
+	}
+
+	return response.JSON(w, result)
+}
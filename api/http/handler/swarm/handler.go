@@ -0,0 +1,101 @@
+package swarm
+
+import (
+	"net/http"
+
+	"github.com/docker/docker/client"
+	"github.com/gorilla/mux"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+	"github.com/portainer/portainer/api/docker"
+	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/authorization"
+)
+
+// Handler is the HTTP handler used to handle Swarm operations that cannot be
+// expressed as a single Docker API call.
+type Handler struct {
+	*mux.Router
+	requestBouncer      *security.RequestBouncer
+	DataStore           portainer.DataStore
+	DockerClientFactory *docker.ClientFactory
+}
+
+// NewHandler creates a handler to manage Swarm operations.
+func NewHandler(bouncer *security.RequestBouncer) *Handler {
+	h := &Handler{
+		Router:         mux.NewRouter(),
+		requestBouncer: bouncer,
+	}
+	h.Handle("/endpoints/{id}/swarm/secrets/{secretId}/rotate",
+		bouncer.RestrictedAccess(httperror.LoggerHandler(h.secretRotate))).Methods(http.MethodPost)
+	h.Handle("/endpoints/{id}/swarm/configs/{configId}/rotate",
+		bouncer.RestrictedAccess(httperror.LoggerHandler(h.configRotate))).Methods(http.MethodPost)
+	h.Handle("/endpoints/{id}/swarm/nodes",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.nodeUpdate))).Methods(http.MethodPut)
+	h.Handle("/endpoints/{id}/swarm/nodes/{nodeId}/drain",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.nodeDrain))).Methods(http.MethodPost)
+	h.Handle("/endpoints/{id}/swarm/nodes/{nodeId}/activate",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.nodeActivate))).Methods(http.MethodPost)
+	h.Handle("/endpoints/{id}/swarm/services/rebalance",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.serviceRebalance))).Methods(http.MethodPost)
+	return h
+}
+
+// operationContext represents the information required to execute a Swarm operation on behalf of a user
+type operationContext struct {
+	endpoint         *portainer.Endpoint
+	cli              *client.Client
+	securityContext  *security.RestrictedRequestContext
+	resourceControls []portainer.ResourceControl
+}
+
+func (operationContext *operationContext) canAccess(resourceControl *portainer.ResourceControl) bool {
+	securityContext := operationContext.securityContext
+	return authorization.AdministratorOrUserCanAccessResource(securityContext.IsAdmin, securityContext.UserID, securityContext.UserMemberships, resourceControl)
+}
+
+// newOperationContext retrieves the endpoint referenced by the request, ensures that the user can access it
+// and creates a Docker client targeting it. The client must be closed by the caller.
+func (handler *Handler) newOperationContext(r *http.Request) (*operationContext, *httperror.HandlerError) {
+	endpointID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return nil, &httperror.HandlerError{http.StatusBadRequest, "Invalid endpoint identifier route variable", err}
+	}
+
+	endpoint, err := handler.DataStore.Endpoint().Endpoint(portainer.EndpointID(endpointID))
+	if err == bolterrors.ErrObjectNotFound {
+		return nil, &httperror.HandlerError{http.StatusNotFound, "Unable to find an endpoint with the specified identifier inside the database", err}
+	} else if err != nil {
+		return nil, &httperror.HandlerError{http.StatusInternalServerError, "Unable to find an endpoint with the specified identifier inside the database", err}
+	}
+
+	err = handler.requestBouncer.AuthorizedEndpointOperation(r, endpoint)
+	if err != nil {
+		return nil, &httperror.HandlerError{http.StatusForbidden, "Permission denied to access endpoint", err}
+	}
+
+	securityContext, err := security.RetrieveRestrictedRequestContext(r)
+	if err != nil {
+		return nil, &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve info from request context", err}
+	}
+
+	resourceControls, err := handler.DataStore.ResourceControl().ResourceControls()
+	if err != nil {
+		return nil, &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve resource controls from the database", err}
+	}
+
+	cli, err := handler.DockerClientFactory.CreateClient(endpoint, "")
+	if err != nil {
+		return nil, &httperror.HandlerError{http.StatusInternalServerError, "Unable to create Docker client", err}
+	}
+
+	return &operationContext{
+		endpoint:         endpoint,
+		cli:              cli,
+		securityContext:  securityContext,
+		resourceControls: resourceControls,
+	}, nil
+}
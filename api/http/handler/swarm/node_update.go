@@ -0,0 +1,143 @@
+package swarm
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/client"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+)
+
+type nodeUpdate struct {
+	// ID of the node to update
+	ID string
+	// Availability is one of active, pause or drain. The current availability is kept when empty.
+	Availability string
+	// Labels are added to the node labels, existing labels with the same key are overridden
+	Labels map[string]string
+	// RemoveLabels is a list of label keys to remove from the node labels
+	RemoveLabels []string
+}
+
+type nodeUpdatePayload struct {
+	Nodes []nodeUpdate
+}
+
+type nodeUpdateResult struct {
+	ID      string `json:"Id"`
+	Success bool   `json:"Success"`
+	Error   string `json:"Error,omitempty"`
+}
+
+func (payload *nodeUpdatePayload) Validate(r *http.Request) error {
+	if len(payload.Nodes) == 0 {
+		return errors.New("Invalid node list. At least one node must be specified")
+	}
+	for _, node := range payload.Nodes {
+		if node.ID == "" {
+			return errors.New("Invalid node identifier")
+		}
+		if !validNodeAvailability(node.Availability) {
+			return errors.New("Invalid node availability. Value must be one of: active, pause or drain")
+		}
+	}
+	return nil
+}
+
+func validNodeAvailability(availability string) bool {
+	switch swarm.NodeAvailability(availability) {
+	case "", swarm.NodeAvailabilityActive, swarm.NodeAvailabilityPause, swarm.NodeAvailabilityDrain:
+		return true
+	}
+	return false
+}
+
+// PUT request on /api/endpoints/:id/swarm/nodes
+func (handler *Handler) nodeUpdate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	var payload nodeUpdatePayload
+	err := request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	operationContext, httpErr := handler.newOperationContext(r)
+	if httpErr != nil {
+		return httpErr
+	}
+	defer operationContext.cli.Close()
+
+	results := make([]nodeUpdateResult, 0)
+	for _, update := range payload.Nodes {
+		result := nodeUpdateResult{ID: update.ID, Success: true}
+
+		err := updateNode(operationContext.cli, &update)
+		if err != nil {
+			result.Success = false
+			result.Error = err.Error()
+		}
+
+		results = append(results, result)
+	}
+
+	return response.JSON(w, results)
+}
+
+// POST request on /api/endpoints/:id/swarm/nodes/:nodeId/drain
+func (handler *Handler) nodeDrain(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	return handler.updateNodeAvailability(w, r, swarm.NodeAvailabilityDrain)
+}
+
+// POST request on /api/endpoints/:id/swarm/nodes/:nodeId/activate
+func (handler *Handler) nodeActivate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	return handler.updateNodeAvailability(w, r, swarm.NodeAvailabilityActive)
+}
+
+func (handler *Handler) updateNodeAvailability(w http.ResponseWriter, r *http.Request, availability swarm.NodeAvailability) *httperror.HandlerError {
+	nodeID, err := request.RetrieveRouteVariableValue(r, "nodeId")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid node identifier route variable", err}
+	}
+
+	operationContext, httpErr := handler.newOperationContext(r)
+	if httpErr != nil {
+		return httpErr
+	}
+	defer operationContext.cli.Close()
+
+	err = updateNode(operationContext.cli, &nodeUpdate{ID: nodeID, Availability: string(availability)})
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to update the node availability", err}
+	}
+
+	return response.Empty(w)
+}
+
+func updateNode(cli *client.Client, update *nodeUpdate) error {
+	node, _, err := cli.NodeInspectWithRaw(context.Background(), update.ID)
+	if err != nil {
+		return err
+	}
+
+	spec := node.Spec
+	if update.Availability != "" {
+		spec.Availability = swarm.NodeAvailability(update.Availability)
+	}
+
+	labels := make(map[string]string)
+	for key, value := range spec.Labels {
+		labels[key] = value
+	}
+	for key, value := range update.Labels {
+		labels[key] = value
+	}
+	for _, key := range update.RemoveLabels {
+		delete(labels, key)
+	}
+	spec.Labels = labels
+
+	return cli.NodeUpdate(context.Background(), node.ID, node.Version, spec)
+}
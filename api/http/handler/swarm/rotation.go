@@ -0,0 +1,391 @@
+package swarm
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/client"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/docker"
+)
+
+const (
+	defaultRotationTimeout    = 300
+	rotationPollInterval      = 2 * time.Second
+	rotationUpdateGracePeriod = 10 * time.Second
+)
+
+type rotationPayload struct {
+	// Data is the base64 encoded content of the new version
+	Data string
+	// Name of the new version, defaults to the name of the previous version suffixed with a timestamp
+	Name string
+	// ServiceOrder is a list of service identifiers or names that must be updated first, in the specified order.
+	// Services that are not part of this list are updated afterwards, in alphabetical order.
+	ServiceOrder []string
+	// RemovePrevious removes the previous version once all the services are updated, defaults to true
+	RemovePrevious *bool
+	// Timeout is the number of seconds to wait for each service update to complete
+	Timeout int
+}
+
+type rotationResponse struct {
+	ID              string   `json:"Id"`
+	Name            string   `json:"Name"`
+	PreviousID      string   `json:"PreviousId"`
+	UpdatedServices []string `json:"UpdatedServices"`
+	PreviousRemoved bool     `json:"PreviousRemoved"`
+}
+
+func (payload *rotationPayload) Validate(r *http.Request) error {
+	if payload.Data == "" {
+		return errors.New("Invalid data. Data must be base64 encoded")
+	}
+	_, err := base64.StdEncoding.DecodeString(payload.Data)
+	if err != nil {
+		return errors.New("Invalid data. Data must be base64 encoded")
+	}
+	if payload.Timeout < 0 {
+		return errors.New("Invalid timeout value. Value must be a positive number of seconds")
+	}
+	return nil
+}
+
+// rotation represents the replacement of a Swarm secret or config by a new version
+type rotation struct {
+	cli              *client.Client
+	dataStore        portainer.DataStore
+	resourceType     portainer.ResourceControlType
+	previousID       string
+	previousName     string
+	newID            string
+	newName          string
+	remove           func(id string) error
+	timeout          time.Duration
+	updatedServices  []swarm.Service
+	resourceControls []portainer.ResourceControl
+}
+
+func newRotationName(previousName string, payload *rotationPayload) string {
+	if payload.Name != "" {
+		return payload.Name
+	}
+	return fmt.Sprintf("%s-%d", previousName, time.Now().Unix())
+}
+
+// referencingServices returns the services referencing the previous version, sorted in update order.
+func (rot *rotation) referencingServices(serviceOrder []string) ([]swarm.Service, error) {
+	services, err := rot.cli.ServiceList(context.Background(), types.ServiceListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	referencingServices := make([]swarm.Service, 0)
+	for _, service := range services {
+		if rot.referencedBy(&service.Spec) {
+			referencingServices = append(referencingServices, service)
+		}
+	}
+
+	position := func(service swarm.Service) int {
+		for idx, reference := range serviceOrder {
+			if reference == service.ID || reference == service.Spec.Name {
+				return idx
+			}
+		}
+		return len(serviceOrder)
+	}
+
+	sort.SliceStable(referencingServices, func(i, j int) bool {
+		pi, pj := position(referencingServices[i]), position(referencingServices[j])
+		if pi != pj {
+			return pi < pj
+		}
+		return referencingServices[i].Spec.Name < referencingServices[j].Spec.Name
+	})
+
+	return referencingServices, nil
+}
+
+func (rot *rotation) referencedBy(spec *swarm.ServiceSpec) bool {
+	containerSpec := spec.TaskTemplate.ContainerSpec
+	if containerSpec == nil {
+		return false
+	}
+
+	if rot.resourceType == portainer.SecretResourceControl {
+		for _, reference := range containerSpec.Secrets {
+			if reference.SecretID == rot.previousID {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, reference := range containerSpec.Configs {
+		if reference.ConfigID == rot.previousID {
+			return true
+		}
+	}
+	return false
+}
+
+// replaceReferences returns a copy of the container specification where the references
+// to the previous version are replaced by references to the new version.
+func (rot *rotation) replaceReferences(spec swarm.ServiceSpec) swarm.ServiceSpec {
+	containerSpec := *spec.TaskTemplate.ContainerSpec
+
+	if rot.resourceType == portainer.SecretResourceControl {
+		secrets := make([]*swarm.SecretReference, 0)
+		for _, reference := range containerSpec.Secrets {
+			updatedReference := *reference
+			if reference.SecretID == rot.previousID {
+				updatedReference.SecretID = rot.newID
+				updatedReference.SecretName = rot.newName
+			}
+			secrets = append(secrets, &updatedReference)
+		}
+		containerSpec.Secrets = secrets
+	} else {
+		configs := make([]*swarm.ConfigReference, 0)
+		for _, reference := range containerSpec.Configs {
+			updatedReference := *reference
+			if reference.ConfigID == rot.previousID {
+				updatedReference.ConfigID = rot.newID
+				updatedReference.ConfigName = rot.newName
+			}
+			configs = append(configs, &updatedReference)
+		}
+		containerSpec.Configs = configs
+	}
+
+	spec.TaskTemplate.ContainerSpec = &containerSpec
+	return spec
+}
+
+// updateServices updates the services one after the other, waiting for each update to complete
+// before moving to the next service.
+func (rot *rotation) updateServices(services []swarm.Service) error {
+	for _, service := range services {
+		rot.updatedServices = append(rot.updatedServices, service)
+
+		updateStartedAt := time.Now()
+		_, err := rot.cli.ServiceUpdate(context.Background(), service.ID, service.Version, rot.replaceReferences(service.Spec), types.ServiceUpdateOptions{})
+		if err != nil {
+			return fmt.Errorf("unable to update service %s: %s", service.Spec.Name, err)
+		}
+
+		err = rot.waitForServiceUpdate(service.ID, updateStartedAt)
+		if err != nil {
+			return fmt.Errorf("unable to update service %s: %s", service.Spec.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func (rot *rotation) waitForServiceUpdate(serviceID string, updateStartedAt time.Time) error {
+	deadline := updateStartedAt.Add(rot.timeout)
+
+	for {
+		service, _, err := rot.cli.ServiceInspectWithRaw(context.Background(), serviceID, types.ServiceInspectOptions{})
+		if err != nil {
+			return err
+		}
+
+		status := service.UpdateStatus
+		pending := status == nil || status.StartedAt == nil || status.StartedAt.Before(updateStartedAt)
+		if pending && time.Since(updateStartedAt) > rotationUpdateGracePeriod {
+			// services without any task to update might never report an update status,
+			// the update of the other services is still expected to start before the timeout
+			running, err := rot.hasRunningTasks(serviceID)
+			if err != nil {
+				return err
+			}
+
+			if !running {
+				return nil
+			}
+		}
+
+		if !pending {
+			switch status.State {
+			case swarm.UpdateStateCompleted:
+				return nil
+			case swarm.UpdateStatePaused, swarm.UpdateStateRollbackStarted, swarm.UpdateStateRollbackPaused, swarm.UpdateStateRollbackCompleted:
+				return fmt.Errorf("update did not complete (state=%s): %s", status.State, status.Message)
+			}
+		}
+
+		if time.Now().After(deadline) {
+			return errors.New("timeout reached while waiting for the update to complete")
+		}
+
+		time.Sleep(rotationPollInterval)
+	}
+}
+
+// hasRunningTasks returns true when at least one task of the service is expected to be running
+func (rot *rotation) hasRunningTasks(serviceID string) (bool, error) {
+	tasks, err := rot.cli.TaskList(context.Background(), types.TaskListOptions{
+		Filters: filters.NewArgs(filters.Arg("service", serviceID), filters.Arg("desired-state", string(swarm.TaskStateRunning))),
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return len(tasks) > 0, nil
+}
+
+// rollback restores the previous specification of the updated services and removes the new version.
+func (rot *rotation) rollback() {
+	for idx := len(rot.updatedServices) - 1; idx >= 0; idx-- {
+		previous := rot.updatedServices[idx]
+
+		current, _, err := rot.cli.ServiceInspectWithRaw(context.Background(), previous.ID, types.ServiceInspectOptions{})
+		if err != nil {
+			log.Printf("[WARN] [http,swarm,rotation] [message: unable to inspect service during rollback] [service: %s] [error: %s]", previous.Spec.Name, err)
+			continue
+		}
+
+		_, err = rot.cli.ServiceUpdate(context.Background(), previous.ID, current.Version, previous.Spec, types.ServiceUpdateOptions{})
+		if err != nil {
+			log.Printf("[WARN] [http,swarm,rotation] [message: unable to restore service during rollback] [service: %s] [error: %s]", previous.Spec.Name, err)
+		}
+	}
+
+	err := rot.remove(rot.newID)
+	if err != nil {
+		log.Printf("[WARN] [http,swarm,rotation] [message: unable to remove the new version during rollback] [id: %s] [error: %s]", rot.newID, err)
+	}
+
+	resourceControl := rot.directResourceControl(rot.newID)
+	if resourceControl != nil {
+		err = rot.dataStore.ResourceControl().DeleteResourceControl(resourceControl.ID)
+		if err != nil {
+			log.Printf("[WARN] [http,swarm,rotation] [message: unable to remove resource control during rollback] [id: %s] [error: %s]", rot.newID, err)
+		}
+	}
+}
+
+// copyResourceControl associates a copy of the resource control of the previous version to the new version.
+// Versions inheriting their access control from a stack keep inheriting it as labels are preserved.
+func (rot *rotation) copyResourceControl() error {
+	previous := rot.directResourceControl(rot.previousID)
+	if previous == nil {
+		return nil
+	}
+
+	resourceControl := &portainer.ResourceControl{
+		ResourceID:         rot.newID,
+		SubResourceIDs:     []string{},
+		Type:               previous.Type,
+		UserAccesses:       previous.UserAccesses,
+		TeamAccesses:       previous.TeamAccesses,
+		Public:             previous.Public,
+		AdministratorsOnly: previous.AdministratorsOnly,
+		System:             previous.System,
+	}
+
+	err := rot.dataStore.ResourceControl().CreateResourceControl(resourceControl)
+	if err != nil {
+		return err
+	}
+
+	rot.resourceControls = append(rot.resourceControls, *resourceControl)
+	return nil
+}
+
+func (rot *rotation) directResourceControl(resourceID string) *portainer.ResourceControl {
+	for idx := range rot.resourceControls {
+		if rot.resourceControls[idx].ResourceID == resourceID && rot.resourceControls[idx].Type == rot.resourceType {
+			return &rot.resourceControls[idx]
+		}
+	}
+	return nil
+}
+
+// execute updates the referencing services to use the new version and optionally removes the previous version.
+// All the changes are rolled back if one of the services cannot be updated.
+func (rot *rotation) execute(services []swarm.Service, removePrevious bool) (*rotationResponse, error) {
+	err := rot.copyResourceControl()
+	if err != nil {
+		rot.rollback()
+		return nil, err
+	}
+
+	err = rot.updateServices(services)
+	if err != nil {
+		rot.rollback()
+		return nil, err
+	}
+
+	response := &rotationResponse{
+		ID:              rot.newID,
+		Name:            rot.newName,
+		PreviousID:      rot.previousID,
+		UpdatedServices: make([]string, 0),
+	}
+
+	for _, service := range rot.updatedServices {
+		response.UpdatedServices = append(response.UpdatedServices, service.Spec.Name)
+	}
+
+	if !removePrevious {
+		return response, nil
+	}
+
+	err = rot.remove(rot.previousID)
+	if err != nil {
+		log.Printf("[WARN] [http,swarm,rotation] [message: unable to remove the previous version] [id: %s] [error: %s]", rot.previousID, err)
+		return response, nil
+	}
+	response.PreviousRemoved = true
+
+	resourceControl := rot.directResourceControl(rot.previousID)
+	if resourceControl != nil {
+		err = rot.dataStore.ResourceControl().DeleteResourceControl(resourceControl.ID)
+		if err != nil {
+			log.Printf("[WARN] [http,swarm,rotation] [message: unable to remove the resource control of the previous version] [id: %s] [error: %s]", rot.previousID, err)
+		}
+	}
+
+	return response, nil
+}
+
+// userCanRotate ensures that the user can access the previous version as well as all the services referencing it.
+func userCanRotate(rot *rotation, previousLabels map[string]string, services []swarm.Service, canAccess func(*portainer.ResourceControl) bool) bool {
+	resourceControl := docker.ResourceControlForResource(rot.previousID, rot.resourceType, previousLabels, rot.resourceControls)
+	if !canAccess(resourceControl) {
+		return false
+	}
+
+	for _, service := range services {
+		resourceControl := docker.ResourceControlForResource(service.ID, portainer.ServiceResourceControl, service.Spec.Labels, rot.resourceControls)
+		if !canAccess(resourceControl) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func rotationTimeout(payload *rotationPayload) time.Duration {
+	if payload.Timeout == 0 {
+		return defaultRotationTimeout * time.Second
+	}
+	return time.Duration(payload.Timeout) * time.Second
+}
+
+func removePrevious(payload *rotationPayload) bool {
+	return payload.RemovePrevious == nil || *payload.RemovePrevious
+}
@@ -0,0 +1,80 @@
+package swarm
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	httperrors "github.com/portainer/portainer/api/http/errors"
+)
+
+// POST request on /api/endpoints/:id/swarm/secrets/:secretId/rotate
+func (handler *Handler) secretRotate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	secretID, err := request.RetrieveRouteVariableValue(r, "secretId")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid secret identifier route variable", err}
+	}
+
+	var payload rotationPayload
+	err = request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	operationContext, httpErr := handler.newOperationContext(r)
+	if httpErr != nil {
+		return httpErr
+	}
+	defer operationContext.cli.Close()
+
+	cli := operationContext.cli
+
+	secret, _, err := cli.SecretInspectWithRaw(context.Background(), secretID)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find a secret with the specified identifier", err}
+	}
+
+	rot := &rotation{
+		cli:              cli,
+		dataStore:        handler.DataStore,
+		resourceType:     portainer.SecretResourceControl,
+		previousID:       secret.ID,
+		previousName:     secret.Spec.Name,
+		newName:          newRotationName(secret.Spec.Name, &payload),
+		remove:           func(id string) error { return cli.SecretRemove(context.Background(), id) },
+		timeout:          rotationTimeout(&payload),
+		resourceControls: operationContext.resourceControls,
+	}
+
+	services, err := rot.referencingServices(payload.ServiceOrder)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve the services referencing the secret", err}
+	}
+
+	if !userCanRotate(rot, secret.Spec.Labels, services, operationContext.canAccess) {
+		return &httperror.HandlerError{http.StatusForbidden, "Access denied to the secret or to one of the services referencing it", httperrors.ErrResourceAccessDenied}
+	}
+
+	data, _ := base64.StdEncoding.DecodeString(payload.Data)
+
+	spec := secret.Spec
+	spec.Name = rot.newName
+	spec.Data = data
+
+	created, err := cli.SecretCreate(context.Background(), spec)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to create the new version of the secret", err}
+	}
+	rot.newID = created.ID
+
+	result, err := rot.execute(services, removePrevious(&payload))
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to rotate the secret, changes were rolled back", err}
+	}
+
+	return response.JSON(w, result)
+}
@@ -0,0 +1,83 @@
+package swarm
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/docker/docker/api/types"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+)
+
+type serviceRebalancePayload struct {
+	// ServiceIDs is the list of services to rebalance, all the replicated services are rebalanced when empty
+	ServiceIDs []string
+}
+
+type serviceRebalanceResult struct {
+	ID      string `json:"Id"`
+	Name    string `json:"Name"`
+	Success bool   `json:"Success"`
+	Error   string `json:"Error,omitempty"`
+}
+
+func (payload *serviceRebalancePayload) Validate(r *http.Request) error {
+	return nil
+}
+
+// POST request on /api/endpoints/:id/swarm/services/rebalance
+//
+// Swarm does not move running tasks when a node becomes available again. Rebalancing forces
+// an update of the replicated services so that their tasks are rescheduled across the available nodes.
+func (handler *Handler) serviceRebalance(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	var payload serviceRebalancePayload
+	err := request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	operationContext, httpErr := handler.newOperationContext(r)
+	if httpErr != nil {
+		return httpErr
+	}
+	defer operationContext.cli.Close()
+
+	cli := operationContext.cli
+
+	services, err := cli.ServiceList(context.Background(), types.ServiceListOptions{})
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve services", err}
+	}
+
+	targets := make(map[string]bool)
+	for _, serviceID := range payload.ServiceIDs {
+		targets[serviceID] = true
+	}
+
+	results := make([]serviceRebalanceResult, 0)
+	for _, service := range services {
+		if service.Spec.Mode.Replicated == nil {
+			continue
+		}
+
+		if len(targets) > 0 && !targets[service.ID] && !targets[service.Spec.Name] {
+			continue
+		}
+
+		result := serviceRebalanceResult{ID: service.ID, Name: service.Spec.Name, Success: true}
+
+		spec := service.Spec
+		spec.TaskTemplate.ForceUpdate++
+
+		_, err := cli.ServiceUpdate(context.Background(), service.ID, service.Version, spec, types.ServiceUpdateOptions{})
+		if err != nil {
+			result.Success = false
+			result.Error = err.Error()
+		}
+
+		results = append(results, result)
+	}
+
+	return response.JSON(w, results)
+}
@@ -0,0 +1,59 @@
+package tasks
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+	httperrors "github.com/portainer/portainer/api/http/errors"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+// Handler is the HTTP handler used to handle task operations.
+type Handler struct {
+	*mux.Router
+	DataStore portainer.DataStore
+}
+
+// NewHandler creates a handler to manage task operations.
+func NewHandler(bouncer *security.RequestBouncer) *Handler {
+	h := &Handler{
+		Router: mux.NewRouter(),
+	}
+	h.Handle("/tasks",
+		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.taskList))).Methods(http.MethodGet)
+	h.Handle("/tasks/{id}",
+		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.taskInspect))).Methods(http.MethodGet)
+	h.Handle("/tasks/{id}",
+		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.taskDelete))).Methods(http.MethodDelete)
+	return h
+}
+
+// retrieveTask returns the task specified in the route, non administrator users can only access their own tasks
+func (handler *Handler) retrieveTask(r *http.Request) (*portainer.Task, *httperror.HandlerError) {
+	taskID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return nil, &httperror.HandlerError{http.StatusBadRequest, "Invalid task identifier route variable", err}
+	}
+
+	tokenData, err := security.RetrieveTokenData(r)
+	if err != nil {
+		return nil, &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve user authentication token", err}
+	}
+
+	task, err := handler.DataStore.Task().Task(portainer.TaskID(taskID))
+	if err == bolterrors.ErrObjectNotFound {
+		return nil, &httperror.HandlerError{http.StatusNotFound, "Unable to find a task with the specified identifier inside the database", err}
+	} else if err != nil {
+		return nil, &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a task with the specified identifier inside the database", err}
+	}
+
+	if tokenData.Role != portainer.AdministratorRole && task.UserID != tokenData.ID {
+		return nil, &httperror.HandlerError{http.StatusForbidden, "Permission denied to access task", httperrors.ErrResourceAccessDenied}
+	}
+
+	return task, nil
+}
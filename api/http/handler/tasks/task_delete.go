@@ -0,0 +1,32 @@
+package tasks
+
+import (
+	"errors"
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api/internal/task"
+)
+
+var errTaskNotFinished = errors.New("The task is still running")
+
+// DELETE request on /api/tasks/:id
+// Only finished tasks can be removed.
+func (handler *Handler) taskDelete(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	t, handlerErr := handler.retrieveTask(r)
+	if handlerErr != nil {
+		return handlerErr
+	}
+
+	if !task.IsFinished(t) {
+		return &httperror.HandlerError{http.StatusConflict, "Unable to remove a running task", errTaskNotFinished}
+	}
+
+	err := handler.DataStore.Task().DeleteTask(t.ID)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to remove the task from the database", err}
+	}
+
+	return response.Empty(w)
+}
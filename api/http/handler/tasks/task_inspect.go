@@ -0,0 +1,18 @@
+package tasks
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/response"
+)
+
+// GET request on /api/tasks/:id
+func (handler *Handler) taskInspect(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	task, handlerErr := handler.retrieveTask(r)
+	if handlerErr != nil {
+		return handlerErr
+	}
+
+	return response.JSON(w, task)
+}
@@ -0,0 +1,43 @@
+package tasks
+
+import (
+	"net/http"
+	"sort"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+// GET request on /api/tasks
+// Administrators can list all the tasks, the other users can only list their own tasks.
+// The logs and the layers are not included, they are available when inspecting a task.
+func (handler *Handler) taskList(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	tokenData, err := security.RetrieveTokenData(r)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve user authentication token", err}
+	}
+
+	tasks, err := handler.DataStore.Task().Tasks()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve tasks from the database", err}
+	}
+
+	filteredTasks := make([]portainer.Task, 0, len(tasks))
+	for _, task := range tasks {
+		if tokenData.Role != portainer.AdministratorRole && task.UserID != tokenData.ID {
+			continue
+		}
+
+		task.Logs = nil
+		task.Layers = nil
+		filteredTasks = append(filteredTasks, task)
+	}
+
+	sort.Slice(filteredTasks, func(i, j int) bool {
+		return filteredTasks[i].ID > filteredTasks[j].ID
+	})
+
+	return response.JSON(w, filteredTasks)
+}
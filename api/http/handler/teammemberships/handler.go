@@ -3,6 +3,8 @@ package teammemberships
 import (
 	httperror "github.com/portainer/libhttp/error"
 	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+	httperrors "github.com/portainer/portainer/api/http/errors"
 	"github.com/portainer/portainer/api/http/security"
 
 	"net/http"
@@ -32,3 +34,32 @@ func NewHandler(bouncer *security.RequestBouncer) *Handler {
 
 	return h
 }
+
+// authorizedMembershipOrganization returns an error when the team or the user of a membership are part of an
+// organization the request cannot access, or when they are not part of the same organization
+func (handler *Handler) authorizedMembershipOrganization(securityContext *security.RestrictedRequestContext, teamID portainer.TeamID, userID portainer.UserID) *httperror.HandlerError {
+	team, err := handler.DataStore.Team().Team(teamID)
+	if err == bolterrors.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find a team with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a team with the specified identifier inside the database", err}
+	}
+
+	user, err := handler.DataStore.User().User(userID)
+	if err == bolterrors.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find a user with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a user with the specified identifier inside the database", err}
+	}
+
+	if !security.AuthorizedOrganizationAccess(team.OrganizationID, securityContext.OrganizationID, securityContext.IsAdmin) ||
+		!security.AuthorizedOrganizationAccess(user.OrganizationID, securityContext.OrganizationID, securityContext.IsAdmin) {
+		return &httperror.HandlerError{http.StatusForbidden, "Permission denied to manage the memberships of another organization", httperrors.ErrResourceAccessDenied}
+	}
+
+	if team.OrganizationID != user.OrganizationID {
+		return &httperror.HandlerError{http.StatusForbidden, "A user can only be a member of the teams of its organization", httperrors.ErrResourceAccessDenied}
+	}
+
+	return nil
+}
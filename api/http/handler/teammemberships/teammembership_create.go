@@ -48,6 +48,11 @@ func (handler *Handler) teamMembershipCreate(w http.ResponseWriter, r *http.Requ
 		return &httperror.HandlerError{http.StatusForbidden, "Permission denied to manage team memberships", httperrors.ErrResourceAccessDenied}
 	}
 
+	handlerErr := handler.authorizedMembershipOrganization(securityContext, portainer.TeamID(payload.TeamID), portainer.UserID(payload.UserID))
+	if handlerErr != nil {
+		return handlerErr
+	}
+
 	memberships, err := handler.DataStore.TeamMembership().TeamMembershipsByUserID(portainer.UserID(payload.UserID))
 	if err != nil {
 		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve team memberships from the database", err}
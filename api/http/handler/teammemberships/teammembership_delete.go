@@ -35,6 +35,11 @@ func (handler *Handler) teamMembershipDelete(w http.ResponseWriter, r *http.Requ
 		return &httperror.HandlerError{http.StatusForbidden, "Permission denied to delete the membership", errors.ErrResourceAccessDenied}
 	}
 
+	handlerErr := handler.authorizedMembershipOrganization(securityContext, membership.TeamID, membership.UserID)
+	if handlerErr != nil {
+		return handlerErr
+	}
+
 	err = handler.DataStore.TeamMembership().DeleteTeamMembership(portainer.TeamMembershipID(membershipID))
 	if err != nil {
 		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to remove the team membership from the database", err}
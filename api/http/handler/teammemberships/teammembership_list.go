@@ -5,6 +5,7 @@ import (
 
 	httperror "github.com/portainer/libhttp/error"
 	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
 	"github.com/portainer/portainer/api/http/errors"
 	"github.com/portainer/portainer/api/http/security"
 )
@@ -25,5 +26,26 @@ func (handler *Handler) teamMembershipList(w http.ResponseWriter, r *http.Reques
 		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve team memberships from the database", err}
 	}
 
-	return response.JSON(w, memberships)
+	if security.IsInstanceAdmin(securityContext) {
+		return response.JSON(w, memberships)
+	}
+
+	teams, err := handler.DataStore.Team().Teams()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve teams from the database", err}
+	}
+
+	authorizedTeams := make(map[portainer.TeamID]bool)
+	for _, team := range teams {
+		authorizedTeams[team.ID] = security.AuthorizedOrganizationAccess(team.OrganizationID, securityContext.OrganizationID, securityContext.IsAdmin)
+	}
+
+	filteredMemberships := make([]portainer.TeamMembership, 0)
+	for _, membership := range memberships {
+		if authorizedTeams[membership.TeamID] {
+			filteredMemberships = append(filteredMemberships, membership)
+		}
+	}
+
+	return response.JSON(w, filteredMemberships)
 }
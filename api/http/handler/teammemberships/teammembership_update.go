@@ -61,6 +61,16 @@ func (handler *Handler) teamMembershipUpdate(w http.ResponseWriter, r *http.Requ
 		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a team membership with the specified identifier inside the database", err}
 	}
 
+	handlerErr := handler.authorizedMembershipOrganization(securityContext, membership.TeamID, membership.UserID)
+	if handlerErr != nil {
+		return handlerErr
+	}
+
+	handlerErr = handler.authorizedMembershipOrganization(securityContext, portainer.TeamID(payload.TeamID), portainer.UserID(payload.UserID))
+	if handlerErr != nil {
+		return handlerErr
+	}
+
 	if securityContext.IsTeamLeader && membership.Role != portainer.MembershipRole(payload.Role) {
 		return &httperror.HandlerError{http.StatusForbidden, "Permission denied to update the role of membership", httperrors.ErrResourceAccessDenied}
 	}
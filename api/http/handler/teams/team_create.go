@@ -10,6 +10,7 @@ import (
 	"github.com/portainer/libhttp/response"
 	"github.com/portainer/portainer/api"
 	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+	"github.com/portainer/portainer/api/http/security"
 )
 
 type teamCreatePayload struct {
@@ -38,8 +39,14 @@ func (handler *Handler) teamCreate(w http.ResponseWriter, r *http.Request) *http
 		return &httperror.HandlerError{http.StatusConflict, "A team with the same name already exists", errors.New("Team already exists")}
 	}
 
+	securityContext, err := security.RetrieveRestrictedRequestContext(r)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve info from request context", err}
+	}
+
 	team = &portainer.Team{
-		Name: payload.Name,
+		Name:           payload.Name,
+		OrganizationID: securityContext.OrganizationID,
 	}
 
 	err = handler.DataStore.Team().CreateTeam(team)
@@ -17,13 +17,17 @@ func (handler *Handler) teamDelete(w http.ResponseWriter, r *http.Request) *http
 		return &httperror.HandlerError{http.StatusBadRequest, "Invalid team identifier route variable", err}
 	}
 
-	_, err = handler.DataStore.Team().Team(portainer.TeamID(teamID))
+	team, err := handler.DataStore.Team().Team(portainer.TeamID(teamID))
 	if err == errors.ErrObjectNotFound {
 		return &httperror.HandlerError{http.StatusNotFound, "Unable to find a team with the specified identifier inside the database", err}
 	} else if err != nil {
 		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a team with the specified identifier inside the database", err}
 	}
 
+	if handlerErr := authorizedTeamOrganization(r, team); handlerErr != nil {
+		return handlerErr
+	}
+
 	err = handler.DataStore.Team().DeleteTeam(portainer.TeamID(teamID))
 	if err != nil {
 		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to delete the team from the database", err}
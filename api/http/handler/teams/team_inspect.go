@@ -35,5 +35,9 @@ func (handler *Handler) teamInspect(w http.ResponseWriter, r *http.Request) *htt
 		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a team with the specified identifier inside the database", err}
 	}
 
+	if handlerErr := authorizedTeamOrganization(r, team); handlerErr != nil {
+		return handlerErr
+	}
+
 	return response.JSON(w, team)
 }
@@ -38,6 +38,10 @@ func (handler *Handler) teamUpdate(w http.ResponseWriter, r *http.Request) *http
 		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a team with the specified identifier inside the database", err}
 	}
 
+	if handlerErr := authorizedTeamOrganization(r, team); handlerErr != nil {
+		return handlerErr
+	}
+
 	if payload.Name != "" {
 		team.Name = payload.Name
 	}
@@ -0,0 +1,29 @@
+package telemetry
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+// Handler is the HTTP handler used to inspect and send the telemetry reports.
+type Handler struct {
+	*mux.Router
+	TelemetryService portainer.TelemetryService
+}
+
+// NewHandler creates a handler to inspect and send the telemetry reports.
+func NewHandler(bouncer *security.RequestBouncer) *Handler {
+	h := &Handler{
+		Router: mux.NewRouter(),
+	}
+	h.Handle("/telemetry/report",
+		bouncer.InstanceAdminAccess(httperror.LoggerHandler(h.telemetryReport))).Methods(http.MethodGet)
+	h.Handle("/telemetry/send",
+		bouncer.InstanceAdminAccess(httperror.LoggerHandler(h.telemetrySend))).Methods(http.MethodPost)
+
+	return h
+}
@@ -0,0 +1,20 @@
+package telemetry
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/response"
+)
+
+// GET request on /api/telemetry/report
+// Returns the exact payload of the next telemetry report. In offline mode, this is the only way
+// to access the aggregated counters.
+func (handler *Handler) telemetryReport(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	report, err := handler.TelemetryService.Report()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to build the telemetry report", err}
+	}
+
+	return response.JSON(w, report)
+}
@@ -0,0 +1,22 @@
+package telemetry
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api/internal/telemetry"
+)
+
+// POST request on /api/telemetry/send
+// Sends the telemetry report immediately, the telemetry must be in online mode.
+func (handler *Handler) telemetrySend(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	err := handler.TelemetryService.Send()
+	if err == telemetry.ErrTelemetryNotOnline {
+		return &httperror.HandlerError{http.StatusConflict, "Unable to send the telemetry report", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusBadGateway, "Unable to send the telemetry report", err}
+	}
+
+	return response.Empty(w)
+}
@@ -6,26 +6,32 @@ import (
 	"github.com/gorilla/mux"
 	httperror "github.com/portainer/libhttp/error"
 	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/docker"
 	"github.com/portainer/portainer/api/http/security"
 )
 
 // Handler represents an HTTP API handler for managing templates.
 type Handler struct {
 	*mux.Router
-	DataStore   portainer.DataStore
-	GitService  portainer.GitService
-	FileService portainer.FileService
+	requestBouncer      *security.RequestBouncer
+	DataStore           portainer.DataStore
+	GitService          portainer.GitService
+	FileService         portainer.FileService
+	DockerClientFactory *docker.ClientFactory
 }
 
 // NewHandler returns a new instance of Handler.
 func NewHandler(bouncer *security.RequestBouncer) *Handler {
 	h := &Handler{
-		Router: mux.NewRouter(),
+		Router:         mux.NewRouter(),
+		requestBouncer: bouncer,
 	}
 
 	h.Handle("/templates",
 		bouncer.RestrictedAccess(httperror.LoggerHandler(h.templateList))).Methods(http.MethodGet)
 	h.Handle("/templates/file",
 		bouncer.RestrictedAccess(httperror.LoggerHandler(h.templateFile))).Methods(http.MethodPost)
+	h.Handle("/templates/preflight",
+		bouncer.RestrictedAccess(httperror.LoggerHandler(h.templatePreflight))).Methods(http.MethodPost)
 	return h
 }
@@ -0,0 +1,341 @@
+package templates
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+	"github.com/portainer/portainer/api/docker"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+const (
+	preflightSeverityError   = "error"
+	preflightSeverityWarning = "warning"
+	preflightSeverityInfo    = "info"
+
+	preflightCheckPorts    = "ports"
+	preflightCheckVolumes  = "volumes"
+	preflightCheckNetwork  = "network"
+	preflightCheckPlatform = "platform"
+)
+
+type (
+	// templatePreflightPayload describes the deployment of a container template,
+	// the fields use the format of the app templates definitions
+	templatePreflightPayload struct {
+		EndpointID int
+		Image      string
+		// Ports are defined as [hostPort:]containerPort[/protocol]
+		Ports   []string
+		Volumes []templatePreflightVolume
+		Network string
+	}
+
+	templatePreflightVolume struct {
+		Container string
+		// Bind is a host path or a volume name, an anonymous volume is created when empty
+		Bind string
+	}
+
+	templatePreflightResult struct {
+		// Ready is false when one of the warnings prevents the deployment of the template
+		Ready    bool                       `json:"Ready"`
+		Warnings []templatePreflightWarning `json:"Warnings"`
+	}
+
+	templatePreflightWarning struct {
+		Check    string `json:"Check"`
+		Severity string `json:"Severity"`
+		Message  string `json:"Message"`
+	}
+)
+
+var predefinedNetworks = map[string]bool{"bridge": true, "host": true, "none": true, "nat": true}
+
+func (payload *templatePreflightPayload) Validate(r *http.Request) error {
+	if payload.EndpointID == 0 {
+		return errors.New("Invalid endpoint identifier")
+	}
+	if payload.Image == "" {
+		return errors.New("Invalid image")
+	}
+	return nil
+}
+
+func (result *templatePreflightResult) add(check, severity, format string, args ...interface{}) {
+	result.Warnings = append(result.Warnings, templatePreflightWarning{
+		Check:    check,
+		Severity: severity,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// POST request on /api/templates/preflight
+//
+// Validates that a container template can be deployed on an endpoint before creating any resource:
+// the published ports must be free, the volumes must be valid for the host, the network must exist
+// and the image must be available for the platform of the host. The issues are reported as warnings,
+// the stack templates are validated with the dry-run of the stack deployment.
+func (handler *Handler) templatePreflight(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	var payload templatePreflightPayload
+	err := request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	endpoint, err := handler.DataStore.Endpoint().Endpoint(portainer.EndpointID(payload.EndpointID))
+	if err == bolterrors.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find an endpoint with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find an endpoint with the specified identifier inside the database", err}
+	}
+
+	err = handler.requestBouncer.AuthorizedEndpointOperation(r, endpoint)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusForbidden, "Permission denied to access endpoint", err}
+	}
+
+	securityContext, err := security.RetrieveRestrictedRequestContext(r)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve info from request context", err}
+	}
+
+	settings, err := handler.DataStore.Settings().Settings()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve settings from the database", err}
+	}
+
+	cli, err := handler.DockerClientFactory.CreateClient(endpoint, "")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to create Docker client", err}
+	}
+	defer cli.Close()
+
+	result := &templatePreflightResult{
+		Warnings: make([]templatePreflightWarning, 0),
+	}
+
+	err = checkTemplatePorts(cli, payload.Ports, securityContext.IsAdmin, result)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve the published ports of the endpoint", err}
+	}
+
+	allowBindMounts := securityContext.IsAdmin || settings.AllowBindMountsForRegularUsers
+	err = checkTemplateVolumes(cli, payload.Volumes, docker.EndpointOSType(endpoint), allowBindMounts, result)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve the volumes of the endpoint", err}
+	}
+
+	err = checkTemplateNetwork(cli, payload.Network, result)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve the networks of the endpoint", err}
+	}
+
+	handler.checkTemplatePlatform(cli, payload.Image, securityContext, result)
+
+	result.Ready = true
+	for _, warning := range result.Warnings {
+		if warning.Severity == preflightSeverityError {
+			result.Ready = false
+		}
+	}
+
+	return response.JSON(w, result)
+}
+
+func checkTemplatePorts(cli *client.Client, ports []string, isAdmin bool, result *templatePreflightResult) error {
+	requestedPorts := make([]docker.PublishedPort, 0)
+	for _, port := range ports {
+		requestedPort, err := parseTemplatePort(port)
+		if err != nil {
+			result.add(preflightCheckPorts, preflightSeverityError, "Invalid port %s: %s", port, err)
+			continue
+		}
+		if requestedPort != nil {
+			requestedPorts = append(requestedPorts, *requestedPort)
+		}
+	}
+
+	if len(requestedPorts) == 0 {
+		return nil
+	}
+
+	publishedPorts, err := docker.PublishedPorts(cli)
+	if err != nil {
+		return err
+	}
+
+	for idx := range requestedPorts {
+		requestedPort := &requestedPorts[idx]
+		for _, publishedPort := range publishedPorts {
+			if !docker.PortsConflict(requestedPort, &publishedPort) {
+				continue
+			}
+
+			if isAdmin && publishedPort.OwnerName != "" {
+				result.add(preflightCheckPorts, preflightSeverityError, "Port %d/%s is already published by the %s %s", requestedPort.PublishedPort, requestedPort.Protocol, publishedPort.OwnerType, publishedPort.OwnerName)
+			} else {
+				result.add(preflightCheckPorts, preflightSeverityError, "Port %d/%s is already published on the endpoint", requestedPort.PublishedPort, requestedPort.Protocol)
+			}
+			break
+		}
+	}
+
+	return nil
+}
+
+// parseTemplatePort parses a port of a template, nil is returned when the port is not published on the host
+func parseTemplatePort(port string) (*docker.PublishedPort, error) {
+	protocol := "tcp"
+	if idx := strings.LastIndex(port, "/"); idx != -1 {
+		protocol = strings.ToLower(port[idx+1:])
+		port = port[:idx]
+	}
+
+	if protocol != "tcp" && protocol != "udp" && protocol != "sctp" {
+		return nil, errors.New("protocol must be one of: tcp, udp or sctp")
+	}
+
+	parts := strings.Split(port, ":")
+	if len(parts) == 1 {
+		return nil, nil
+	}
+
+	hostIP := ""
+	if len(parts) == 3 {
+		hostIP = parts[0]
+	}
+
+	hostPort := parts[len(parts)-2]
+	if hostPort == "" {
+		return nil, nil
+	}
+
+	publishedPort, err := strconv.ParseUint(hostPort, 10, 16)
+	if err != nil || publishedPort == 0 {
+		return nil, errors.New("the host port must be between 1 and 65535")
+	}
+
+	return &docker.PublishedPort{
+		HostIP:        hostIP,
+		PublishedPort: uint16(publishedPort),
+		Protocol:      protocol,
+		Mode:          docker.PortModeHost,
+	}, nil
+}
+
+func checkTemplateVolumes(cli *client.Client, volumes []templatePreflightVolume, osType string, allowBindMounts bool, result *templatePreflightResult) error {
+	var existingVolumes map[string]bool
+
+	for _, volume := range volumes {
+		if volume.Bind == "" {
+			continue
+		}
+
+		mount, err := docker.ParseBind(volume.Bind+":"+volume.Container, osType)
+		if err != nil {
+			result.add(preflightCheckVolumes, preflightSeverityError, "%s", err)
+			continue
+		}
+
+		if mount.HostPath {
+			if !allowBindMounts {
+				result.add(preflightCheckVolumes, preflightSeverityError, "Bind mounts are not allowed for regular users, %s cannot be mounted", mount.Source)
+			}
+			continue
+		}
+
+		if existingVolumes == nil {
+			list, err := cli.VolumeList(context.Background(), filters.NewArgs())
+			if err != nil {
+				return err
+			}
+
+			existingVolumes = make(map[string]bool)
+			for _, existingVolume := range list.Volumes {
+				existingVolumes[existingVolume.Name] = true
+			}
+		}
+
+		if !existingVolumes[mount.Source] {
+			result.add(preflightCheckVolumes, preflightSeverityInfo, "The volume %s does not exist and will be created", mount.Source)
+		}
+	}
+
+	return nil
+}
+
+func checkTemplateNetwork(cli *client.Client, network string, result *templatePreflightResult) error {
+	if network == "" || predefinedNetworks[network] {
+		return nil
+	}
+
+	networks, err := cli.NetworkList(context.Background(), types.NetworkListOptions{})
+	if err != nil {
+		return err
+	}
+
+	for _, existingNetwork := range networks {
+		if existingNetwork.Name == network {
+			return nil
+		}
+	}
+
+	result.add(preflightCheckNetwork, preflightSeverityError, "The network %s does not exist on the endpoint", network)
+	return nil
+}
+
+// checkTemplatePlatform verifies that the image is available for the platform of the host,
+// the check is skipped with a warning when the image or the host cannot be inspected
+func (handler *Handler) checkTemplatePlatform(cli *client.Client, image string, securityContext *security.RestrictedRequestContext, result *templatePreflightResult) {
+	host, err := docker.HostPlatform(cli)
+	if err != nil {
+		result.add(preflightCheckPlatform, preflightSeverityWarning, "Unable to retrieve the platform of the endpoint: %s", err)
+		return
+	}
+
+	registries, err := handler.DataStore.Registry().Registries()
+	if err != nil {
+		result.add(preflightCheckPlatform, preflightSeverityWarning, "Unable to retrieve the registries: %s", err)
+		return
+	}
+	registries = security.FilterRegistries(registries, securityContext)
+
+	dockerhub, err := handler.DataStore.DockerHub().DockerHub()
+	if err != nil {
+		result.add(preflightCheckPlatform, preflightSeverityWarning, "Unable to retrieve the DockerHub details: %s", err)
+		return
+	}
+
+	registryAuth, err := docker.RegistryAuthentication(image, registries, dockerhub)
+	if err != nil {
+		result.add(preflightCheckPlatform, preflightSeverityWarning, "Unable to verify the platforms of the image %s: %s", image, err)
+		return
+	}
+
+	platforms, err := docker.ImagePlatforms(cli, image, registryAuth)
+	if err != nil {
+		result.add(preflightCheckPlatform, preflightSeverityWarning, "Unable to verify the platforms of the image %s: %s", image, err)
+		return
+	}
+
+	if !docker.PlatformSupported(host, platforms) {
+		available := make([]string, 0, len(platforms))
+		for _, platform := range platforms {
+			available = append(available, platform.String())
+		}
+		result.add(preflightCheckPlatform, preflightSeverityError, "The image %s is not available for %s, available platforms: %s", image, host, strings.Join(available, ", "))
+	}
+}
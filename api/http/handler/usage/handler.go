@@ -0,0 +1,28 @@
+package usage
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+// Handler is the HTTP handler used to report the resource usage per team and per stack.
+type Handler struct {
+	*mux.Router
+	requestBouncer *security.RequestBouncer
+	DataStore      portainer.DataStore
+}
+
+// NewHandler creates a handler to report the resource usage per team and per stack.
+func NewHandler(bouncer *security.RequestBouncer) *Handler {
+	h := &Handler{
+		Router:         mux.NewRouter(),
+		requestBouncer: bouncer,
+	}
+	h.Handle("/usage",
+		bouncer.InstanceAdminAccess(httperror.LoggerHandler(h.usageReport))).Methods(http.MethodGet)
+	return h
+}
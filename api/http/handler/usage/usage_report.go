@@ -0,0 +1,271 @@
+package usage
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+)
+
+const (
+	defaultReportRange = 30 * 24 * time.Hour
+	groupByTeam        = "team"
+	groupByStack       = "stack"
+	unassignedTeamName = "Unassigned"
+	bytesPerGB         = 1 << 30
+)
+
+type (
+	usageReport struct {
+		From    int64        `json:"From"`
+		To      int64        `json:"To"`
+		GroupBy string       `json:"GroupBy"`
+		Entries []usageEntry `json:"Entries"`
+	}
+
+	// usageEntry represents the resources reserved by a team or a stack over the time range of the report.
+	// CPUHours and MemoryGBHours are the reservations integrated over time, the averages are these values
+	// divided by the duration of the report.
+	usageEntry struct {
+		TeamID          portainer.TeamID     `json:"TeamId,omitempty"`
+		TeamName        string               `json:"TeamName,omitempty"`
+		EndpointID      portainer.EndpointID `json:"EndpointId,omitempty"`
+		EndpointName    string               `json:"EndpointName,omitempty"`
+		Stack           string               `json:"Stack,omitempty"`
+		Resources       int                  `json:"Resources"`
+		CPUHours        float64              `json:"CPUHours"`
+		MemoryGBHours   float64              `json:"MemoryGBHours"`
+		AverageCPU      float64              `json:"AverageCPU"`
+		AverageMemoryGB float64              `json:"AverageMemoryGB"`
+
+		resources map[string]bool
+	}
+)
+
+// GET request on /api/usage?from=<timestamp>&to=<timestamp>&groupBy=<team|stack>&endpointId=<id>&format=<json|csv>
+//
+// Aggregates the CPU and memory reserved by the containers and services over a time range, based on the
+// resource samples recorded at each endpoint snapshot. Resources shared with several teams are split evenly
+// between these teams, resources not shared with any team are reported as unassigned.
+func (handler *Handler) usageReport(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	to, _ := request.RetrieveNumericQueryParameter(r, "to", true)
+	if to == 0 {
+		to = int(time.Now().Unix())
+	}
+
+	from, _ := request.RetrieveNumericQueryParameter(r, "from", true)
+	if from == 0 {
+		from = to - int(defaultReportRange.Seconds())
+	}
+
+	if from >= to {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid query parameters: from, to", errors.New("The start of the time range must be before its end")}
+	}
+
+	groupBy, _ := request.RetrieveQueryParameter(r, "groupBy", true)
+	if groupBy == "" {
+		groupBy = groupByTeam
+	}
+	if groupBy != groupByTeam && groupBy != groupByStack {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid query parameter: groupBy", errors.New("Value must be one of: team or stack")}
+	}
+
+	format, _ := request.RetrieveQueryParameter(r, "format", true)
+	if format != "" && format != "json" && format != "csv" {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid query parameter: format", errors.New("Value must be one of: json or csv")}
+	}
+
+	endpointID, _ := request.RetrieveNumericQueryParameter(r, "endpointId", true)
+
+	samples, err := handler.DataStore.ResourceSample().ResourceSamples(int64(from), int64(to))
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve resource samples from the database", err}
+	}
+
+	if endpointID != 0 {
+		filteredSamples := make([]portainer.ResourceSample, 0)
+		for _, sample := range samples {
+			if sample.EndpointID == portainer.EndpointID(endpointID) {
+				filteredSamples = append(filteredSamples, sample)
+			}
+		}
+		samples = filteredSamples
+	}
+
+	report := &usageReport{
+		From:    int64(from),
+		To:      int64(to),
+		GroupBy: groupBy,
+	}
+
+	report.Entries = aggregateSamples(samples, report.From, report.To, groupBy)
+
+	err = handler.decorateEntries(report.Entries)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve teams and endpoints from the database", err}
+	}
+
+	if format == "csv" {
+		return writeCSV(w, report)
+	}
+
+	return response.JSON(w, report)
+}
+
+// aggregateSamples integrates the reservations of the samples over time. Each sample is accounted for
+// until the next sample of the same endpoint, at most for the snapshot interval recorded in the sample.
+func aggregateSamples(samples []portainer.ResourceSample, from, to int64, groupBy string) []usageEntry {
+	sort.Slice(samples, func(i, j int) bool {
+		if samples[i].EndpointID != samples[j].EndpointID {
+			return samples[i].EndpointID < samples[j].EndpointID
+		}
+		return samples[i].Time < samples[j].Time
+	})
+
+	entries := make(map[string]*usageEntry)
+	entry := func(key string, template usageEntry) *usageEntry {
+		if entries[key] == nil {
+			template.resources = make(map[string]bool)
+			entries[key] = &template
+		}
+		return entries[key]
+	}
+
+	for idx, sample := range samples {
+		end := to
+		if idx+1 < len(samples) && samples[idx+1].EndpointID == sample.EndpointID {
+			end = samples[idx+1].Time
+		}
+
+		duration := end - sample.Time
+		if sample.Interval > 0 && duration > sample.Interval {
+			duration = sample.Interval
+		}
+		hours := float64(duration) / 3600
+
+		for _, reservation := range sample.Reservations {
+			cpuHours := reservation.CPU * float64(reservation.Replicas) * hours
+			memoryGBHours := float64(reservation.Memory) * float64(reservation.Replicas) * hours / bytesPerGB
+			resourceKey := fmt.Sprintf("%d/%s", sample.EndpointID, reservation.ResourceID)
+
+			if groupBy == groupByStack {
+				stackEntry := entry(fmt.Sprintf("%d/%s", sample.EndpointID, reservation.Stack), usageEntry{EndpointID: sample.EndpointID, Stack: reservation.Stack})
+				stackEntry.CPUHours += cpuHours
+				stackEntry.MemoryGBHours += memoryGBHours
+				stackEntry.resources[resourceKey] = true
+				continue
+			}
+
+			teamIDs := reservation.TeamIDs
+			if len(teamIDs) == 0 {
+				teamIDs = []portainer.TeamID{0}
+			}
+
+			share := float64(len(teamIDs))
+			for _, teamID := range teamIDs {
+				teamEntry := entry(strconv.Itoa(int(teamID)), usageEntry{TeamID: teamID})
+				teamEntry.CPUHours += cpuHours / share
+				teamEntry.MemoryGBHours += memoryGBHours / share
+				teamEntry.resources[resourceKey] = true
+			}
+		}
+	}
+
+	reportHours := float64(to-from) / 3600
+
+	result := make([]usageEntry, 0, len(entries))
+	for _, entry := range entries {
+		entry.Resources = len(entry.resources)
+		entry.AverageCPU = entry.CPUHours / reportHours
+		entry.AverageMemoryGB = entry.MemoryGBHours / reportHours
+		result = append(result, *entry)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CPUHours > result[j].CPUHours
+	})
+
+	return result
+}
+
+func (handler *Handler) decorateEntries(entries []usageEntry) error {
+	teams, err := handler.DataStore.Team().Teams()
+	if err != nil {
+		return err
+	}
+
+	teamNames := make(map[portainer.TeamID]string)
+	for _, team := range teams {
+		teamNames[team.ID] = team.Name
+	}
+
+	endpoints, err := handler.DataStore.Endpoint().Endpoints()
+	if err != nil {
+		return err
+	}
+
+	endpointNames := make(map[portainer.EndpointID]string)
+	for _, endpoint := range endpoints {
+		endpointNames[endpoint.ID] = endpoint.Name
+	}
+
+	for idx := range entries {
+		entry := &entries[idx]
+		if entry.EndpointID != 0 {
+			entry.EndpointName = endpointNames[entry.EndpointID]
+			continue
+		}
+
+		entry.TeamName = teamNames[entry.TeamID]
+		if entry.TeamID == 0 {
+			entry.TeamName = unassignedTeamName
+		}
+	}
+
+	return nil
+}
+
+func writeCSV(w http.ResponseWriter, report *usageReport) *httperror.HandlerError {
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=usage-%s-%d-%d.csv", report.GroupBy, report.From, report.To))
+
+	writer := csv.NewWriter(w)
+
+	header := []string{"TeamId", "Team"}
+	if report.GroupBy == groupByStack {
+		header = []string{"EndpointId", "Endpoint", "Stack"}
+	}
+	header = append(header, "Resources", "CPUHours", "MemoryGBHours", "AverageCPU", "AverageMemoryGB")
+
+	records := [][]string{header}
+	for _, entry := range report.Entries {
+		record := []string{strconv.Itoa(int(entry.TeamID)), entry.TeamName}
+		if report.GroupBy == groupByStack {
+			record = []string{strconv.Itoa(int(entry.EndpointID)), entry.EndpointName, entry.Stack}
+		}
+
+		record = append(record,
+			strconv.Itoa(entry.Resources),
+			strconv.FormatFloat(entry.CPUHours, 'f', 3, 64),
+			strconv.FormatFloat(entry.MemoryGBHours, 'f', 3, 64),
+			strconv.FormatFloat(entry.AverageCPU, 'f', 3, 64),
+			strconv.FormatFloat(entry.AverageMemoryGB, 'f', 3, 64),
+		)
+		records = append(records, record)
+	}
+
+	err := writer.WriteAll(records)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to write the usage report", err}
+	}
+
+	return nil
+}
@@ -1,19 +1,25 @@
 package users
 
 import (
+	"crypto/subtle"
 	"errors"
+	"log"
 	"net/http"
+	"time"
 
 	"github.com/asaskevich/govalidator"
 	httperror "github.com/portainer/libhttp/error"
 	"github.com/portainer/libhttp/request"
 	"github.com/portainer/libhttp/response"
 	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/internal/passwordpolicy"
 )
 
 type adminInitPayload struct {
 	Username string
 	Password string
+	// InitToken is the token unlocking the initialization of a locked instance
+	InitToken string
 }
 
 func (payload *adminInitPayload) Validate(r *http.Request) error {
@@ -34,6 +40,25 @@ func (handler *Handler) adminInit(w http.ResponseWriter, r *http.Request) *httpe
 		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
 	}
 
+	locked, err := handler.FileService.InitLocked()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve the initialization lock", err}
+	}
+
+	if !locked && !handler.AdminInitDeadline.IsZero() && time.Now().After(handler.AdminInitDeadline) {
+		if !handler.AdminInitLockdown {
+			return &httperror.HandlerError{http.StatusForbidden, "Unable to create administrator user", errAdminInitDisabled}
+		}
+		locked = true
+	}
+
+	if locked {
+		handlerErr := handler.checkInitToken(payload.InitToken)
+		if handlerErr != nil {
+			return handlerErr
+		}
+	}
+
 	users, err := handler.DataStore.User().UsersByRole(portainer.AdministratorRole)
 	if err != nil {
 		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve users from the database", err}
@@ -48,15 +73,52 @@ func (handler *Handler) adminInit(w http.ResponseWriter, r *http.Request) *httpe
 		Role:     portainer.AdministratorRole,
 	}
 
-	user.Password, err = handler.CryptoService.Hash(payload.Password)
+	settings, err := handler.DataStore.Settings().Settings()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve settings from the database", err}
+	}
+
+	err = passwordpolicy.Validate(payload.Password, &settings.PasswordPolicy)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Password does not meet the password policy requirements", err}
+	}
+
+	hash, err := handler.CryptoService.Hash(payload.Password)
 	if err != nil {
 		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to hash user password", errCryptoHashFailure}
 	}
+	passwordpolicy.SetPassword(user, hash, &settings.PasswordPolicy)
 
 	err = handler.DataStore.User().CreateUser(user)
 	if err != nil {
 		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist user inside the database", err}
 	}
 
+	if locked {
+		err = handler.FileService.DeleteInitToken()
+		if err != nil {
+			log.Printf("[WARN] [http,users] [message: unable to remove the init token] [error: %s]", err)
+		}
+
+		err = handler.FileService.DeleteInitLock()
+		if err != nil {
+			log.Printf("[WARN] [http,users] [message: unable to remove the initialization lock] [error: %s]", err)
+		}
+	}
+
 	return response.JSON(w, user)
 }
+
+// checkInitToken verifies the token unlocking the initialization of a locked instance
+func (handler *Handler) checkInitToken(token string) *httperror.HandlerError {
+	initToken, err := handler.FileService.GetInitToken()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve the init token", err}
+	}
+
+	if initToken == "" || subtle.ConstantTimeCompare([]byte(initToken), []byte(token)) != 1 {
+		return &httperror.HandlerError{http.StatusForbidden, "Unable to create administrator user", errAdminInitLocked}
+	}
+
+	return nil
+}
@@ -2,9 +2,11 @@ package users
 
 import (
 	"errors"
+	"time"
 
 	httperror "github.com/portainer/libhttp/error"
 	"github.com/portainer/portainer/api"
+	httperrors "github.com/portainer/portainer/api/http/errors"
 	"github.com/portainer/portainer/api/http/security"
 
 	"net/http"
@@ -17,37 +19,74 @@ var (
 	errAdminAlreadyInitialized    = errors.New("An administrator user already exists")
 	errAdminCannotRemoveSelf      = errors.New("Cannot remove your own user account. Contact another administrator")
 	errCannotRemoveLastLocalAdmin = errors.New("Cannot remove the last local administrator account")
+	errAdminCannotChangeOwnRole   = errors.New("Cannot change the role of your own user account")
 	errCryptoHashFailure          = errors.New("Unable to hash data")
+	errAdminInitDisabled          = errors.New("The initial administrator creation is disabled, restart the Portainer instance to create the administrator")
+	errAdminInitLocked            = errors.New("The initialization of the instance is locked, a token issued with portainer --generate-init-token is required to create the administrator")
 )
 
 func hideFields(user *portainer.User) {
 	user.Password = ""
+	user.PasswordHistory = nil
+}
+
+// authorizedUserOrganization returns an error when the user is part of an organization the request cannot access
+func authorizedUserOrganization(r *http.Request, user *portainer.User) *httperror.HandlerError {
+	securityContext, err := security.RetrieveRestrictedRequestContext(r)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve info from request context", err}
+	}
+
+	if !security.AuthorizedOrganizationAccess(user.OrganizationID, securityContext.OrganizationID, securityContext.IsAdmin) {
+		return &httperror.HandlerError{http.StatusForbidden, "Permission denied to access a user of another organization", httperrors.ErrResourceAccessDenied}
+	}
+	return nil
 }
 
 // Handler is the HTTP handler used to handle user operations.
 type Handler struct {
 	*mux.Router
-	DataStore     portainer.DataStore
-	CryptoService portainer.CryptoService
+	requestBouncer *security.RequestBouncer
+	DataStore      portainer.DataStore
+	CryptoService  portainer.CryptoService
+	FileService    portainer.FileService
+	// AdminInitDeadline is the time after which the initial administrator cannot be created anymore
+	AdminInitDeadline time.Time
+	// AdminInitLockdown allows the creation of the initial administrator after the deadline
+	// with a token issued through the CLI
+	AdminInitLockdown bool
 }
 
 // NewHandler creates a handler to manage user operations.
 func NewHandler(bouncer *security.RequestBouncer, rateLimiter *security.RateLimiter) *Handler {
 	h := &Handler{
-		Router: mux.NewRouter(),
+		Router:         mux.NewRouter(),
+		requestBouncer: bouncer,
 	}
 	h.Handle("/users",
 		bouncer.AdminAccess(httperror.LoggerHandler(h.userCreate))).Methods(http.MethodPost)
 	h.Handle("/users",
 		bouncer.RestrictedAccess(httperror.LoggerHandler(h.userList))).Methods(http.MethodGet)
+	h.Handle("/users/import",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.userImport))).Methods(http.MethodPost)
+	h.Handle("/users/role",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.userUpdateRole))).Methods(http.MethodPut)
+	h.Handle("/users/me",
+		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.userProfile))).Methods(http.MethodGet)
 	h.Handle("/users/{id}",
 		bouncer.RestrictedAccess(httperror.LoggerHandler(h.userInspect))).Methods(http.MethodGet)
 	h.Handle("/users/{id}",
 		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.userUpdate))).Methods(http.MethodPut)
 	h.Handle("/users/{id}",
 		bouncer.AdminAccess(httperror.LoggerHandler(h.userDelete))).Methods(http.MethodDelete)
+	h.Handle("/users/{id}/activity",
+		bouncer.RestrictedAccess(httperror.LoggerHandler(h.userActivity))).Methods(http.MethodGet)
 	h.Handle("/users/{id}/memberships",
 		bouncer.RestrictedAccess(httperror.LoggerHandler(h.userMemberships))).Methods(http.MethodGet)
+	h.Handle("/users/{id}/preferences",
+		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.userPreferencesInspect))).Methods(http.MethodGet)
+	h.Handle("/users/{id}/preferences",
+		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.userPreferencesUpdate))).Methods(http.MethodPut)
 	h.Handle("/users/{id}/passwd",
 		rateLimiter.LimitAccess(bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.userUpdatePassword)))).Methods(http.MethodPut)
 	h.Handle("/users/admin/check",
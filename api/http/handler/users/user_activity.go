@@ -0,0 +1,90 @@
+package users
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+	"github.com/portainer/portainer/api/http/errors"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+// defaultActivityLimit is the number of activity entries returned when no limit is specified
+const defaultActivityLimit = 50
+
+// userProfileResponse represents the profile of the current user, with its most recent activity
+type userProfileResponse struct {
+	*portainer.User
+	RecentActivity []portainer.UserActivity `json:"RecentActivity"`
+}
+
+// GET request on /api/users/:id/activity?limit=<limit>
+//
+// Returns the logins, failed logins and requests of a user, the most recent entry first.
+// Only the administrators and the user itself can access the activity of a user.
+func (handler *Handler) userActivity(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	userID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid user identifier route variable", err}
+	}
+
+	limit, _ := request.RetrieveNumericQueryParameter(r, "limit", true)
+	if limit <= 0 {
+		limit = defaultActivityLimit
+	}
+
+	securityContext, err := security.RetrieveRestrictedRequestContext(r)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve info from request context", err}
+	}
+
+	if !securityContext.IsAdmin && securityContext.UserID != portainer.UserID(userID) {
+		return &httperror.HandlerError{http.StatusForbidden, "Permission denied to access user activity", errors.ErrResourceAccessDenied}
+	}
+
+	user, err := handler.DataStore.User().User(portainer.UserID(userID))
+	if err == bolterrors.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find a user with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a user with the specified identifier inside the database", err}
+	}
+
+	if handlerErr := authorizedUserOrganization(r, user); handlerErr != nil {
+		return handlerErr
+	}
+
+	activities, err := handler.DataStore.UserActivity().UserActivities(user.ID, limit)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve the user activity from the database", err}
+	}
+
+	return response.JSON(w, activities)
+}
+
+// GET request on /api/users/me
+//
+// Returns the current user with its last login and its most recent activity.
+func (handler *Handler) userProfile(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	tokenData, err := security.RetrieveTokenData(r)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve user authentication token", err}
+	}
+
+	user, err := handler.DataStore.User().User(tokenData.ID)
+	if err == bolterrors.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find a user with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a user with the specified identifier inside the database", err}
+	}
+
+	activities, err := handler.DataStore.UserActivity().UserActivities(user.ID, defaultActivityLimit)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve the user activity from the database", err}
+	}
+
+	hideFields(user)
+	return response.JSON(w, &userProfileResponse{User: user, RecentActivity: activities})
+}
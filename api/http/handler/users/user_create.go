@@ -12,6 +12,8 @@ import (
 	bolterrors "github.com/portainer/portainer/api/bolt/errors"
 	httperrors "github.com/portainer/portainer/api/http/errors"
 	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/organization"
+	"github.com/portainer/portainer/api/internal/passwordpolicy"
 )
 
 type userCreatePayload struct {
@@ -60,9 +62,17 @@ func (handler *Handler) userCreate(w http.ResponseWriter, r *http.Request) *http
 		return &httperror.HandlerError{http.StatusConflict, "Another user with the same username already exists", errUserAlreadyExists}
 	}
 
+	err = organization.CheckQuota(handler.DataStore, securityContext.OrganizationID, organization.QuotaUsers, 1)
+	if quotaErr, ok := err.(*organization.QuotaExceededError); ok {
+		return &httperror.HandlerError{http.StatusForbidden, quotaErr.Error(), quotaErr}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to verify the quotas of the organization", err}
+	}
+
 	user = &portainer.User{
-		Username: payload.Username,
-		Role:     portainer.UserRole(payload.Role),
+		Username:       payload.Username,
+		Role:           portainer.UserRole(payload.Role),
+		OrganizationID: securityContext.OrganizationID,
 	}
 
 	settings, err := handler.DataStore.Settings().Settings()
@@ -71,10 +81,18 @@ func (handler *Handler) userCreate(w http.ResponseWriter, r *http.Request) *http
 	}
 
 	if settings.AuthenticationMethod == portainer.AuthenticationInternal {
-		user.Password, err = handler.CryptoService.Hash(payload.Password)
+		err = passwordpolicy.Validate(payload.Password, &settings.PasswordPolicy)
+		if err != nil {
+			return &httperror.HandlerError{http.StatusBadRequest, "Password does not meet the password policy requirements", err}
+		}
+
+		hash, err := handler.CryptoService.Hash(payload.Password)
 		if err != nil {
 			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to hash user password", errCryptoHashFailure}
 		}
+
+		passwordpolicy.SetPassword(user, hash, &settings.PasswordPolicy)
+		user.PasswordChangeRequired = settings.PasswordPolicy.ForceChangeOnFirstLogin
 	}
 
 	err = handler.DataStore.User().CreateUser(user)
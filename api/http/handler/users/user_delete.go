@@ -39,6 +39,10 @@ func (handler *Handler) userDelete(w http.ResponseWriter, r *http.Request) *http
 		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a user with the specified identifier inside the database", err}
 	}
 
+	if handlerErr := authorizedUserOrganization(r, user); handlerErr != nil {
+		return handlerErr
+	}
+
 	if user.Role == portainer.AdministratorRole {
 		return handler.deleteAdminUser(w, user)
 	}
@@ -81,5 +85,20 @@ func (handler *Handler) deleteUser(w http.ResponseWriter, user *portainer.User)
 		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to remove user memberships from the database", err}
 	}
 
+	err = handler.DataStore.ReportSubscription().DeleteReportSubscription(user.ID)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to remove user report subscription from the database", err}
+	}
+
+	err = handler.DataStore.UserNotification().DeleteUserNotificationsByUserID(user.ID)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to remove user notifications from the database", err}
+	}
+
+	err = handler.DataStore.UserPreferences().DeleteUserPreferences(user.ID)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to remove user preferences from the database", err}
+	}
+
 	return response.Empty(w)
 }
@@ -0,0 +1,373 @@
+package users
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/asaskevich/govalidator"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/organization"
+	"github.com/portainer/portainer/api/internal/passwordpolicy"
+)
+
+const (
+	// userImportMaxRows is the maximum number of users that can be imported at once
+	userImportMaxRows = 5000
+
+	userImportStatusCreated  = "created"
+	userImportStatusExisting = "existing"
+	userImportStatusFailed   = "failed"
+)
+
+type (
+	userImportPayload struct {
+		File   []byte
+		DryRun bool
+	}
+
+	userImportTeam struct {
+		name string
+		role portainer.MembershipRole
+	}
+
+	userImportRow struct {
+		line     int
+		username string
+		password string
+		role     portainer.UserRole
+		teams    []userImportTeam
+	}
+
+	// userImportResult is the outcome of the import of a single CSV row
+	userImportResult struct {
+		Line     int
+		Username string
+		Status   string
+		UserID   portainer.UserID `json:",omitempty"`
+		// Teams contains the names of the teams the user was added to
+		Teams   []string `json:",omitempty"`
+		Message string   `json:",omitempty"`
+	}
+)
+
+func (payload *userImportPayload) Validate(r *http.Request) error {
+	file, _, err := request.RetrieveMultiPartFormFile(r, "file")
+	if err != nil {
+		return errors.New("Invalid CSV file. Ensure that the file is uploaded correctly")
+	}
+	payload.File = file
+
+	dryRun, _ := request.RetrieveBooleanMultiPartFormValue(r, "DryRun", true)
+	payload.DryRun = dryRun
+
+	return nil
+}
+
+// POST request on /api/users/import
+//
+// The uploaded CSV file must start with a header line containing the following columns, in any order:
+// username (required), password, role (administrator or user, defaults to user) and teams.
+// The teams column contains a semicolon separated list of team names, a name can be suffixed with
+// :leader to add the user as a team leader. Existing users are not modified but are added to the teams.
+// Each row is validated and imported independently, the response contains the result of each row.
+func (handler *Handler) userImport(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	payload := &userImportPayload{}
+	err := payload.Validate(r)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	rows, err := parseUserImportFile(payload.File)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid CSV file", err}
+	}
+
+	settings, err := handler.DataStore.Settings().Settings()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve settings from the database", err}
+	}
+
+	securityContext, err := security.RetrieveRestrictedRequestContext(r)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve info from request context", err}
+	}
+
+	teams, err := handler.DataStore.Team().Teams()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve teams from the database", err}
+	}
+	teams = security.FilterOrganizationTeams(teams, securityContext)
+
+	teamsByName := make(map[string]portainer.TeamID)
+	for _, team := range teams {
+		teamsByName[team.Name] = team.ID
+	}
+
+	handlerErr := handler.checkUserImportQuota(rows, securityContext.OrganizationID)
+	if handlerErr != nil {
+		return handlerErr
+	}
+
+	results := make([]userImportResult, 0, len(rows))
+	usernames := make(map[string]int)
+	for _, row := range rows {
+		result := userImportResult{Line: row.line, Username: row.username}
+
+		err := validateUserImportRow(row, settings, teamsByName)
+		if err == nil && usernames[row.username] != 0 {
+			err = fmt.Errorf("Duplicate username, already defined on line %d", usernames[row.username])
+		}
+		if err != nil {
+			result.Status = userImportStatusFailed
+			result.Message = err.Error()
+			results = append(results, result)
+			continue
+		}
+		usernames[row.username] = row.line
+
+		err = handler.importUserRow(row, settings, teamsByName, securityContext.OrganizationID, payload.DryRun, &result)
+		if err != nil {
+			result.Status = userImportStatusFailed
+			result.Message = err.Error()
+		}
+
+		results = append(results, result)
+	}
+
+	return response.JSON(w, results)
+}
+
+func parseUserImportFile(file []byte) ([]userImportRow, error) {
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(file, []byte("\xef\xbb\xbf"))))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, errors.New("The file is empty")
+	} else if err != nil {
+		return nil, err
+	}
+
+	columns := make(map[string]int)
+	for idx, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = idx
+	}
+
+	if _, ok := columns["username"]; !ok {
+		return nil, errors.New("Missing username column in header line")
+	}
+
+	column := func(record []string, name string) string {
+		idx, ok := columns[name]
+		if !ok || idx >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[idx])
+	}
+
+	rows := make([]userImportRow, 0)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		if len(rows) == userImportMaxRows {
+			return nil, fmt.Errorf("The file contains more than %d users", userImportMaxRows)
+		}
+
+		row := userImportRow{
+			// the header is the first line of the file
+			line:     len(rows) + 2,
+			username: column(record, "username"),
+			password: column(record, "password"),
+			role:     parseUserImportRole(column(record, "role")),
+			teams:    parseUserImportTeams(column(record, "teams")),
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+func parseUserImportRole(value string) portainer.UserRole {
+	switch strings.ToLower(value) {
+	case "", "user", "standard", strconv.Itoa(int(portainer.StandardUserRole)):
+		return portainer.StandardUserRole
+	case "administrator", "admin", strconv.Itoa(int(portainer.AdministratorRole)):
+		return portainer.AdministratorRole
+	}
+	return 0
+}
+
+func parseUserImportTeams(value string) []userImportTeam {
+	teams := make([]userImportTeam, 0)
+
+	for _, entry := range strings.Split(value, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		team := userImportTeam{name: entry, role: portainer.TeamMember}
+		if strings.HasSuffix(strings.ToLower(entry), ":leader") {
+			team.name = strings.TrimSpace(entry[:len(entry)-len(":leader")])
+			team.role = portainer.TeamLeader
+		}
+
+		teams = append(teams, team)
+	}
+
+	return teams
+}
+
+func validateUserImportRow(row userImportRow, settings *portainer.Settings, teamsByName map[string]portainer.TeamID) error {
+	if govalidator.IsNull(row.username) || govalidator.Contains(row.username, " ") {
+		return errors.New("Invalid username. Must not contain any whitespace")
+	}
+
+	if row.role == 0 {
+		return errors.New("Invalid role value. Value must be one of: administrator or user")
+	}
+
+	if settings.AuthenticationMethod == portainer.AuthenticationInternal && row.password == "" {
+		return errors.New("Missing password, required when using internal authentication")
+	}
+
+	if settings.AuthenticationMethod == portainer.AuthenticationInternal {
+		err := passwordpolicy.Validate(row.password, &settings.PasswordPolicy)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, team := range row.teams {
+		if _, ok := teamsByName[team.name]; !ok {
+			return fmt.Errorf("Unknown team: %s", team.name)
+		}
+	}
+
+	return nil
+}
+
+// checkUserImportQuota rejects the whole import when the users it creates would exceed the quota of the organization
+func (handler *Handler) checkUserImportQuota(rows []userImportRow, organizationID portainer.OrganizationID) *httperror.HandlerError {
+	if organizationID == 0 {
+		return nil
+	}
+
+	newUsernames := make(map[string]bool)
+	for _, row := range rows {
+		_, err := handler.DataStore.User().UserByUsername(row.username)
+		if err == bolterrors.ErrObjectNotFound {
+			newUsernames[row.username] = true
+		} else if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve users from the database", err}
+		}
+	}
+
+	err := organization.CheckQuota(handler.DataStore, organizationID, organization.QuotaUsers, len(newUsernames))
+	if quotaErr, ok := err.(*organization.QuotaExceededError); ok {
+		return &httperror.HandlerError{http.StatusForbidden, quotaErr.Error(), quotaErr}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to verify the quotas of the organization", err}
+	}
+
+	return nil
+}
+
+func (handler *Handler) importUserRow(row userImportRow, settings *portainer.Settings, teamsByName map[string]portainer.TeamID, organizationID portainer.OrganizationID, dryRun bool, result *userImportResult) error {
+	user, err := handler.DataStore.User().UserByUsername(row.username)
+	if err != nil && err != bolterrors.ErrObjectNotFound {
+		return err
+	}
+
+	if user != nil && organizationID != 0 && user.OrganizationID != organizationID {
+		return errors.New("The user belongs to another organization")
+	}
+
+	result.Status = userImportStatusExisting
+	if user == nil {
+		result.Status = userImportStatusCreated
+		user = &portainer.User{
+			Username:       row.username,
+			Role:           row.role,
+			OrganizationID: organizationID,
+		}
+
+		if settings.AuthenticationMethod == portainer.AuthenticationInternal {
+			hash, err := handler.CryptoService.Hash(row.password)
+			if err != nil {
+				return errCryptoHashFailure
+			}
+
+			passwordpolicy.SetPassword(user, hash, &settings.PasswordPolicy)
+			user.PasswordChangeRequired = settings.PasswordPolicy.ForceChangeOnFirstLogin
+		}
+
+		if !dryRun {
+			err = handler.DataStore.User().CreateUser(user)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	result.UserID = user.ID
+
+	memberships := make([]portainer.TeamMembership, 0)
+	if user.ID != 0 {
+		memberships, err = handler.DataStore.TeamMembership().TeamMembershipsByUserID(user.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, team := range row.teams {
+		teamID := teamsByName[team.name]
+		if isTeamMember(memberships, teamID) {
+			continue
+		}
+
+		membership := &portainer.TeamMembership{
+			UserID: user.ID,
+			TeamID: teamID,
+			Role:   team.role,
+		}
+
+		if !dryRun {
+			err = handler.DataStore.TeamMembership().CreateTeamMembership(membership)
+			if err != nil {
+				return err
+			}
+		}
+
+		memberships = append(memberships, *membership)
+		result.Teams = append(result.Teams, team.name)
+	}
+
+	return nil
+}
+
+func isTeamMember(memberships []portainer.TeamMembership, teamID portainer.TeamID) bool {
+	for _, membership := range memberships {
+		if membership.TeamID == teamID {
+			return true
+		}
+	}
+	return false
+}
@@ -35,6 +35,10 @@ func (handler *Handler) userInspect(w http.ResponseWriter, r *http.Request) *htt
 		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a user with the specified identifier inside the database", err}
 	}
 
+	if handlerErr := authorizedUserOrganization(r, user); handlerErr != nil {
+		return handlerErr
+	}
+
 	hideFields(user)
 	return response.JSON(w, user)
 }
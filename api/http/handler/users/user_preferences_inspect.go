@@ -0,0 +1,64 @@
+package users
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+	"github.com/portainer/portainer/api/http/errors"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+// GET request on /api/users/:id/preferences
+func (handler *Handler) userPreferencesInspect(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	preferences, handlerErr := handler.retrieveUserPreferences(r)
+	if handlerErr != nil {
+		return handlerErr
+	}
+
+	return response.JSON(w, preferences)
+}
+
+// retrieveUserPreferences returns the preferences of the user specified in the route, which must be
+// the current user unless the current user is an administrator. When the user did not save any
+// preferences yet, the default preferences are returned.
+func (handler *Handler) retrieveUserPreferences(r *http.Request) (*portainer.UserPreferences, *httperror.HandlerError) {
+	userID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return nil, &httperror.HandlerError{http.StatusBadRequest, "Invalid user identifier route variable", err}
+	}
+
+	tokenData, err := security.RetrieveTokenData(r)
+	if err != nil {
+		return nil, &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve user authentication token", err}
+	}
+
+	if tokenData.Role != portainer.AdministratorRole && tokenData.ID != portainer.UserID(userID) {
+		return nil, &httperror.HandlerError{http.StatusForbidden, "Permission denied to access user preferences", errors.ErrResourceAccessDenied}
+	}
+
+	_, err = handler.DataStore.User().User(portainer.UserID(userID))
+	if err == bolterrors.ErrObjectNotFound {
+		return nil, &httperror.HandlerError{http.StatusNotFound, "Unable to find a user with the specified identifier inside the database", err}
+	} else if err != nil {
+		return nil, &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a user with the specified identifier inside the database", err}
+	}
+
+	preferences, err := handler.DataStore.UserPreferences().UserPreferences(portainer.UserID(userID))
+	if err == bolterrors.ErrObjectNotFound {
+		return &portainer.UserPreferences{
+			UserID:            portainer.UserID(userID),
+			Theme:             portainer.LightTheme,
+			ItemsPerPage:      portainer.DefaultItemsPerPage,
+			ConsoleKeybinding: portainer.DefaultConsoleKeybinding,
+			DismissedWarnings: make([]string, 0),
+		}, nil
+	} else if err != nil {
+		return nil, &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve the user preferences from the database", err}
+	}
+
+	return preferences, nil
+}
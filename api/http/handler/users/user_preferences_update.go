@@ -0,0 +1,133 @@
+package users
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+const (
+	maxItemsPerPage      = 500
+	maxDismissedWarnings = 200
+)
+
+type userPreferencesUpdatePayload struct {
+	Theme             *portainer.UserTheme
+	DefaultEndpointID *portainer.EndpointID
+	ItemsPerPage      *int
+	ConsoleKeybinding *portainer.ConsoleKeybinding
+	// DismissedWarnings replaces the list of the dismissed warnings
+	DismissedWarnings []string
+}
+
+func (payload *userPreferencesUpdatePayload) Validate(r *http.Request) error {
+	if payload.Theme != nil {
+		switch *payload.Theme {
+		case portainer.LightTheme, portainer.DarkTheme, portainer.HighContrastTheme, portainer.AutoTheme:
+		default:
+			return errors.New("Invalid theme. Value must be one of: light, dark, highcontrast or auto")
+		}
+	}
+	if payload.ItemsPerPage != nil && (*payload.ItemsPerPage < 1 || *payload.ItemsPerPage > maxItemsPerPage) {
+		return errors.New("Invalid items per page. Value must be between 1 and 500")
+	}
+	if payload.ConsoleKeybinding != nil {
+		switch *payload.ConsoleKeybinding {
+		case portainer.DefaultConsoleKeybinding, portainer.VimConsoleKeybinding, portainer.EmacsConsoleKeybinding:
+		default:
+			return errors.New("Invalid console keybinding. Value must be one of: default, vim or emacs")
+		}
+	}
+	if len(payload.DismissedWarnings) > maxDismissedWarnings {
+		return errors.New("Too many dismissed warnings")
+	}
+	for _, warning := range payload.DismissedWarnings {
+		if strings.TrimSpace(warning) == "" {
+			return errors.New("Invalid dismissed warning. Identifier cannot be empty")
+		}
+	}
+	return nil
+}
+
+// PUT request on /api/users/:id/preferences
+// Only the fields specified in the payload are updated.
+func (handler *Handler) userPreferencesUpdate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	var payload userPreferencesUpdatePayload
+	err := request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	preferences, handlerErr := handler.retrieveUserPreferences(r)
+	if handlerErr != nil {
+		return handlerErr
+	}
+
+	if payload.DefaultEndpointID != nil && *payload.DefaultEndpointID != 0 {
+		handlerErr = handler.checkDefaultEndpoint(r, preferences.UserID, *payload.DefaultEndpointID)
+		if handlerErr != nil {
+			return handlerErr
+		}
+	}
+
+	if payload.Theme != nil {
+		preferences.Theme = *payload.Theme
+	}
+
+	if payload.DefaultEndpointID != nil {
+		preferences.DefaultEndpointID = *payload.DefaultEndpointID
+	}
+
+	if payload.ItemsPerPage != nil {
+		preferences.ItemsPerPage = *payload.ItemsPerPage
+	}
+
+	if payload.ConsoleKeybinding != nil {
+		preferences.ConsoleKeybinding = *payload.ConsoleKeybinding
+	}
+
+	if payload.DismissedWarnings != nil {
+		preferences.DismissedWarnings = payload.DismissedWarnings
+	}
+
+	err = handler.DataStore.UserPreferences().UpdateUserPreferences(preferences.UserID, preferences)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist the user preferences inside the database", err}
+	}
+
+	return response.JSON(w, preferences)
+}
+
+// checkDefaultEndpoint verifies that the endpoint exists and, when the user updates their own
+// preferences, that they can access it
+func (handler *Handler) checkDefaultEndpoint(r *http.Request, userID portainer.UserID, endpointID portainer.EndpointID) *httperror.HandlerError {
+	endpoint, err := handler.DataStore.Endpoint().Endpoint(endpointID)
+	if err == bolterrors.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusBadRequest, "Unable to find an endpoint with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find an endpoint with the specified identifier inside the database", err}
+	}
+
+	tokenData, err := security.RetrieveTokenData(r)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve user authentication token", err}
+	}
+
+	if tokenData.ID != userID {
+		return nil
+	}
+
+	err = handler.requestBouncer.AuthorizedEndpointOperation(r, endpoint)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusForbidden, "Permission denied to access endpoint", err}
+	}
+
+	return nil
+}
@@ -2,7 +2,9 @@ package users
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/asaskevich/govalidator"
 	httperror "github.com/portainer/libhttp/error"
@@ -12,12 +14,16 @@ import (
 	bolterrors "github.com/portainer/portainer/api/bolt/errors"
 	httperrors "github.com/portainer/portainer/api/http/errors"
 	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/i18n"
+	"github.com/portainer/portainer/api/internal/passwordpolicy"
 )
 
 type userUpdatePayload struct {
 	Username string
 	Password string
 	Role     int
+	// Locale is the locale of the messages sent to the user, an empty value resets it
+	Locale *string
 }
 
 func (payload *userUpdatePayload) Validate(r *http.Request) error {
@@ -28,6 +34,10 @@ func (payload *userUpdatePayload) Validate(r *http.Request) error {
 	if payload.Role != 0 && payload.Role != 1 && payload.Role != 2 {
 		return errors.New("Invalid role value. Value must be one of: 1 (administrator) or 2 (regular user)")
 	}
+
+	if payload.Locale != nil && *payload.Locale != "" && !i18n.IsSupported(*payload.Locale) {
+		return fmt.Errorf("Invalid locale. Value must be one of: %s", strings.Join(i18n.Locales(), ", "))
+	}
 	return nil
 }
 
@@ -64,6 +74,10 @@ func (handler *Handler) userUpdate(w http.ResponseWriter, r *http.Request) *http
 		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a user with the specified identifier inside the database", err}
 	}
 
+	if handlerErr := authorizedUserOrganization(r, user); handlerErr != nil {
+		return handlerErr
+	}
+
 	if payload.Username != "" && payload.Username != user.Username {
 		sameNameUser, err := handler.DataStore.User().UserByUsername(payload.Username)
 		if err != nil && err != bolterrors.ErrObjectNotFound {
@@ -77,20 +91,43 @@ func (handler *Handler) userUpdate(w http.ResponseWriter, r *http.Request) *http
 	}
 
 	if payload.Password != "" {
-		user.Password, err = handler.CryptoService.Hash(payload.Password)
+		settings, err := handler.DataStore.Settings().Settings()
+		if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve settings from the database", err}
+		}
+
+		err = passwordpolicy.Validate(payload.Password, &settings.PasswordPolicy)
+		if err != nil {
+			return &httperror.HandlerError{http.StatusBadRequest, "Password does not meet the password policy requirements", err}
+		}
+
+		if passwordpolicy.IsReused(handler.CryptoService, user, payload.Password, &settings.PasswordPolicy) {
+			return &httperror.HandlerError{http.StatusBadRequest, "Password does not meet the password policy requirements", passwordpolicy.ErrPasswordReused}
+		}
+
+		hash, err := handler.CryptoService.Hash(payload.Password)
 		if err != nil {
 			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to hash user password", errCryptoHashFailure}
 		}
+
+		passwordpolicy.SetPassword(user, hash, &settings.PasswordPolicy)
+		// a password set by an administrator on behalf of another user is a temporary password
+		user.PasswordChangeRequired = tokenData.ID != user.ID && settings.PasswordPolicy.ForceChangeOnFirstLogin
 	}
 
 	if payload.Role != 0 {
 		user.Role = portainer.UserRole(payload.Role)
 	}
 
+	if payload.Locale != nil {
+		user.Locale = *payload.Locale
+	}
+
 	err = handler.DataStore.User().UpdateUser(user.ID, user)
 	if err != nil {
 		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist user changes inside the database", err}
 	}
 
+	hideFields(user)
 	return response.JSON(w, user)
 }
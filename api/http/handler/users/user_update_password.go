@@ -12,6 +12,7 @@ import (
 	bolterrors "github.com/portainer/portainer/api/bolt/errors"
 	httperrors "github.com/portainer/portainer/api/http/errors"
 	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/passwordpolicy"
 )
 
 type userUpdatePasswordPayload struct {
@@ -45,6 +46,10 @@ func (handler *Handler) userUpdatePassword(w http.ResponseWriter, r *http.Reques
 		return &httperror.HandlerError{http.StatusForbidden, "Permission denied to update user", httperrors.ErrUnauthorized}
 	}
 
+	if security.IsImpersonation(tokenData) {
+		return &httperror.HandlerError{http.StatusForbidden, "The password cannot be changed while impersonating a user", httperrors.ErrResourceAccessDenied}
+	}
+
 	var payload userUpdatePasswordPayload
 	err = request.DecodeAndValidateJSONPayload(r, &payload)
 	if err != nil {
@@ -63,11 +68,28 @@ func (handler *Handler) userUpdatePassword(w http.ResponseWriter, r *http.Reques
 		return &httperror.HandlerError{http.StatusForbidden, "Specified password do not match actual password", httperrors.ErrUnauthorized}
 	}
 
-	user.Password, err = handler.CryptoService.Hash(payload.NewPassword)
+	settings, err := handler.DataStore.Settings().Settings()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve settings from the database", err}
+	}
+
+	err = passwordpolicy.Validate(payload.NewPassword, &settings.PasswordPolicy)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Password does not meet the password policy requirements", err}
+	}
+
+	if passwordpolicy.IsReused(handler.CryptoService, user, payload.NewPassword, &settings.PasswordPolicy) {
+		return &httperror.HandlerError{http.StatusBadRequest, "Password does not meet the password policy requirements", passwordpolicy.ErrPasswordReused}
+	}
+
+	hash, err := handler.CryptoService.Hash(payload.NewPassword)
 	if err != nil {
 		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to hash user password", errCryptoHashFailure}
 	}
 
+	passwordpolicy.SetPassword(user, hash, &settings.PasswordPolicy)
+	user.PasswordChangeRequired = tokenData.ID != user.ID && settings.PasswordPolicy.ForceChangeOnFirstLogin
+
 	err = handler.DataStore.User().UpdateUser(user.ID, user)
 	if err != nil {
 		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist user changes inside the database", err}
@@ -0,0 +1,111 @@
+package users
+
+import (
+	"errors"
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+type userUpdateRolePayload struct {
+	UserIDs []portainer.UserID
+	Role    int
+}
+
+func (payload *userUpdateRolePayload) Validate(r *http.Request) error {
+	if len(payload.UserIDs) == 0 {
+		return errors.New("Invalid user identifiers. At least one user must be specified")
+	}
+
+	if payload.Role != 1 && payload.Role != 2 {
+		return errors.New("Invalid role value. Value must be one of: 1 (administrator) or 2 (regular user)")
+	}
+	return nil
+}
+
+// PUT request on /api/users/role
+func (handler *Handler) userUpdateRole(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	var payload userUpdateRolePayload
+	err := request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	tokenData, err := security.RetrieveTokenData(r)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve user authentication token", err}
+	}
+
+	role := portainer.UserRole(payload.Role)
+	users := make([]*portainer.User, 0, len(payload.UserIDs))
+	for _, userID := range payload.UserIDs {
+		if userID == tokenData.ID {
+			return &httperror.HandlerError{http.StatusForbidden, "Cannot change the role of your own user account", errAdminCannotChangeOwnRole}
+		}
+
+		user, err := handler.DataStore.User().User(userID)
+		if err == bolterrors.ErrObjectNotFound {
+			return &httperror.HandlerError{http.StatusNotFound, "Unable to find a user with the specified identifier inside the database", err}
+		} else if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a user with the specified identifier inside the database", err}
+		}
+
+		if handlerErr := authorizedUserOrganization(r, user); handlerErr != nil {
+			return handlerErr
+		}
+
+		users = append(users, user)
+	}
+
+	if role != portainer.AdministratorRole {
+		err = handler.ensureLocalAdministratorRemains(payload.UserIDs)
+		if err != nil {
+			return &httperror.HandlerError{http.StatusConflict, "Cannot remove the administrator role of all the local administrators", err}
+		}
+	}
+
+	for _, user := range users {
+		if user.Role == role {
+			continue
+		}
+
+		user.Role = role
+		err = handler.DataStore.User().UpdateUser(user.ID, user)
+		if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist user changes inside the database", err}
+		}
+	}
+
+	for _, user := range users {
+		hideFields(user)
+	}
+
+	return response.JSON(w, users)
+}
+
+// ensureLocalAdministratorRemains returns an error when no local administrator would remain
+// after the administrator role is removed from the specified users.
+func (handler *Handler) ensureLocalAdministratorRemains(demotedUserIDs []portainer.UserID) error {
+	users, err := handler.DataStore.User().Users()
+	if err != nil {
+		return err
+	}
+
+	demoted := make(map[portainer.UserID]bool)
+	for _, userID := range demotedUserIDs {
+		demoted[userID] = true
+	}
+
+	for _, user := range users {
+		if user.Role == portainer.AdministratorRole && user.Password != "" && !demoted[user.ID] {
+			return nil
+		}
+	}
+
+	return errCannotRemoveLastLocalAdmin
+}
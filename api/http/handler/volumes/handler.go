@@ -0,0 +1,110 @@
+package volumes
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"github.com/gorilla/mux"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+	"github.com/portainer/portainer/api/docker"
+	httperrors "github.com/portainer/portainer/api/http/errors"
+	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/authorization"
+)
+
+var errUnsupportedEndpoint = errors.New("Volume operations are only supported on Linux Docker endpoints")
+
+// Handler is the HTTP handler used to handle volume operations that cannot be
+// expressed as a single Docker API call.
+type Handler struct {
+	*mux.Router
+	requestBouncer      *security.RequestBouncer
+	DataStore           portainer.DataStore
+	DockerClientFactory *docker.ClientFactory
+}
+
+// NewHandler creates a handler to manage volume operations.
+func NewHandler(bouncer *security.RequestBouncer) *Handler {
+	h := &Handler{
+		Router:         mux.NewRouter(),
+		requestBouncer: bouncer,
+	}
+	h.Handle("/endpoints/{id}/volumes/create",
+		bouncer.RestrictedAccess(httperror.LoggerHandler(h.volumeCreate))).Methods(http.MethodPost)
+	h.Handle("/endpoints/{id}/volumes/unused",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.volumeUnusedList))).Methods(http.MethodGet)
+	h.Handle("/endpoints/{id}/volumes/{name}/backup",
+		bouncer.RestrictedAccess(httperror.LoggerHandler(h.volumeBackup))).Methods(http.MethodGet)
+	h.Handle("/endpoints/{id}/volumes/{name}/restore",
+		bouncer.RestrictedAccess(httperror.LoggerHandler(h.volumeRestore))).Methods(http.MethodPost)
+	return h
+}
+
+// retrieveVolumeEndpoint retrieves the endpoint referenced by the request route variables, ensures that
+// the user can access it and that it is a Linux Docker endpoint, and creates a Docker client for the node
+// specified in the request.
+func (handler *Handler) retrieveVolumeEndpoint(r *http.Request) (*portainer.Endpoint, *client.Client, *httperror.HandlerError) {
+	endpointID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return nil, nil, &httperror.HandlerError{http.StatusBadRequest, "Invalid endpoint identifier route variable", err}
+	}
+
+	nodeName, _ := request.RetrieveQueryParameter(r, "nodeName", true)
+
+	endpoint, err := handler.DataStore.Endpoint().Endpoint(portainer.EndpointID(endpointID))
+	if err == bolterrors.ErrObjectNotFound {
+		return nil, nil, &httperror.HandlerError{http.StatusNotFound, "Unable to find an endpoint with the specified identifier inside the database", err}
+	} else if err != nil {
+		return nil, nil, &httperror.HandlerError{http.StatusInternalServerError, "Unable to find an endpoint with the specified identifier inside the database", err}
+	}
+
+	err = handler.requestBouncer.AuthorizedEndpointOperation(r, endpoint)
+	if err != nil {
+		return nil, nil, &httperror.HandlerError{http.StatusForbidden, "Permission denied to access endpoint", err}
+	}
+
+	if docker.EndpointOSType(endpoint) == docker.OSTypeWindows {
+		return nil, nil, &httperror.HandlerError{http.StatusBadRequest, "Volume operations are only supported on Linux Docker endpoints", errUnsupportedEndpoint}
+	}
+
+	cli, err := handler.DockerClientFactory.CreateClient(endpoint, nodeName)
+	if err != nil {
+		return nil, nil, &httperror.HandlerError{http.StatusInternalServerError, "Unable to create Docker client", err}
+	}
+
+	return endpoint, cli, nil
+}
+
+// userCanAccessVolume ensures that the user can access an existing volume, the resource control of
+// a volume is identified by the name and the creation date of the volume.
+func (handler *Handler) userCanAccessVolume(securityContext *security.RestrictedRequestContext, volume *types.Volume) *httperror.HandlerError {
+	if securityContext.IsAdmin {
+		return nil
+	}
+
+	resourceControls, err := handler.DataStore.ResourceControl().ResourceControls()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve resource controls from the database", err}
+	}
+
+	resourceControl := docker.ResourceControlForResource(volume.Name+volume.CreatedAt, portainer.VolumeResourceControl, volume.Labels, resourceControls)
+	if resourceControl == nil {
+		return &httperror.HandlerError{http.StatusForbidden, "Access denied to resource", httperrors.ErrResourceAccessDenied}
+	}
+
+	userTeamIDs := make([]portainer.TeamID, 0)
+	for _, membership := range securityContext.UserMemberships {
+		userTeamIDs = append(userTeamIDs, membership.TeamID)
+	}
+
+	if !authorization.UserCanAccessResource(securityContext.UserID, userTeamIDs, resourceControl) {
+		return &httperror.HandlerError{http.StatusForbidden, "Access denied to resource", httperrors.ErrResourceAccessDenied}
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package volumes
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/portainer/api/docker"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+// GET request on /api/endpoints/:id/volumes/:name/backup?nodeName=<nodeName>
+//
+// Streams the content of a volume as a tar.gz archive whose entries are relative to the root of the volume.
+// The content is copied through a helper container mounting the volume in read-only mode, the helper
+// container is never started and is removed once the archive is sent.
+func (handler *Handler) volumeBackup(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	volumeName, err := request.RetrieveRouteVariableValue(r, "name")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid volume name route variable", err}
+	}
+
+	securityContext, err := security.RetrieveRestrictedRequestContext(r)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve info from request context", err}
+	}
+
+	_, cli, httpErr := handler.retrieveVolumeEndpoint(r)
+	if httpErr != nil {
+		return httpErr
+	}
+	defer cli.Close()
+
+	volume, err := cli.VolumeInspect(context.Background(), volumeName)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find a volume with the specified name", err}
+	}
+
+	httpErr = handler.userCanAccessVolume(securityContext, &volume)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	helper, err := docker.NewVolumeHelper(cli, volume.Name, true)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to create the volume helper container", err}
+	}
+	defer removeVolumeHelper(helper)
+
+	archive, err := helper.Export()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to export the content of the volume", err}
+	}
+	defer archive.Close()
+
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s.tar.gz", volume.Name))
+
+	// the response is already started, an error can only be logged
+	err = docker.CompressVolumeArchive(archive, w)
+	if err != nil {
+		log.Printf("[WARN] [http,volumes] [message: unable to stream the volume archive] [volume: %s] [error: %s]", volume.Name, err)
+	}
+
+	return nil
+}
+
+func removeVolumeHelper(helper *docker.VolumeHelper) {
+	err := helper.Remove()
+	if err != nil {
+		log.Printf("[WARN] [http,volumes] [message: unable to remove the volume helper container] [error: %s]", err)
+	}
+}
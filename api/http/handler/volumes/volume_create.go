@@ -0,0 +1,142 @@
+package volumes
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"regexp"
+
+	"github.com/docker/docker/api/types"
+	volumetypes "github.com/docker/docker/api/types/volume"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/docker"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+var errDeviceMappingForbidden = errors.New("forbidden to use device mapping")
+
+// volumeNameRe matches the names accepted by the Docker daemon for the volumes
+var volumeNameRe = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]+$`)
+
+type volumeCreatePayload struct {
+	Name string
+	// Type is one of nfs, cifs or device, the definition of the matching type must be specified
+	Type   string
+	NFS    *docker.NFSVolumeOptions
+	CIFS   *docker.CIFSVolumeOptions
+	Device *docker.DeviceVolumeOptions
+	Labels map[string]string
+}
+
+type volumeCreateResponse struct {
+	*types.Volume
+	Portainer struct {
+		ResourceControl *portainer.ResourceControl `json:"ResourceControl,omitempty"`
+	} `json:"Portainer"`
+}
+
+func (payload *volumeCreatePayload) Validate(r *http.Request) error {
+	if !volumeNameRe.MatchString(payload.Name) {
+		return errors.New("Invalid volume name")
+	}
+
+	switch payload.Type {
+	case docker.NFSVolumeType:
+		if payload.NFS == nil {
+			return errors.New("Invalid NFS volume definition")
+		}
+	case docker.CIFSVolumeType:
+		if payload.CIFS == nil {
+			return errors.New("Invalid CIFS volume definition")
+		}
+	case docker.DeviceVolumeType:
+		if payload.Device == nil {
+			return errors.New("Invalid device volume definition")
+		}
+	default:
+		return errors.New("Invalid volume type. Value must be one of: nfs, cifs or device")
+	}
+
+	_, err := payload.driverOptions()
+	return err
+}
+
+func (payload *volumeCreatePayload) driverOptions() (map[string]string, error) {
+	switch payload.Type {
+	case docker.NFSVolumeType:
+		return payload.NFS.DriverOptions()
+	case docker.CIFSVolumeType:
+		return payload.CIFS.DriverOptions()
+	default:
+		return payload.Device.DriverOptions()
+	}
+}
+
+// POST request on /api/endpoints/:id/volumes/create?nodeName=<nodeName>
+//
+// Creates a volume of the local driver mounting an NFS export, a CIFS share or a device of the host from
+// a typed definition, the mount options of the driver are generated from the definition. The volume is
+// created without being mounted: the share is only mounted when a container uses the volume.
+// The password of a CIFS share is stored in the options of the volume by the Docker daemon, it is masked
+// in the response as well as in the volumes returned by the Docker API.
+func (handler *Handler) volumeCreate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	var payload volumeCreatePayload
+	err := request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	securityContext, err := security.RetrieveRestrictedRequestContext(r)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve info from request context", err}
+	}
+
+	if !securityContext.IsAdmin && payload.Type == docker.DeviceVolumeType {
+		settings, err := handler.DataStore.Settings().Settings()
+		if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve settings from the database", err}
+		}
+
+		if !settings.AllowDeviceMappingForRegularUsers {
+			return &httperror.HandlerError{http.StatusForbidden, "Permission denied to create the volume", errDeviceMappingForbidden}
+		}
+	}
+
+	endpoint, cli, httpErr := handler.retrieveVolumeEndpoint(r)
+	if httpErr != nil {
+		return httpErr
+	}
+	defer cli.Close()
+
+	_, err = cli.VolumeInspect(context.Background(), payload.Name)
+	if err == nil {
+		return &httperror.HandlerError{http.StatusConflict, "A volume with the same name already exists", errVolumeAlreadyExists}
+	}
+
+	driverOptions, _ := payload.driverOptions()
+
+	volume, err := cli.VolumeCreate(context.Background(), volumetypes.VolumeCreateBody{
+		Name:       payload.Name,
+		Driver:     docker.LocalVolumeDriver,
+		DriverOpts: driverOptions,
+		Labels:     payload.Labels,
+	})
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to create the volume", err}
+	}
+
+	createResponse := &volumeCreateResponse{Volume: &volume}
+	createResponse.Portainer.ResourceControl, err = handler.createVolumeResourceControl(volume.Name+volume.CreatedAt, endpoint, securityContext)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist the resource control associated to the volume", err}
+	}
+
+	if mountOptions, ok := volume.Options["o"]; ok {
+		volume.Options["o"] = docker.MaskMountCredentials(mountOptions)
+	}
+
+	return response.JSON(w, createResponse)
+}
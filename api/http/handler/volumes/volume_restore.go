@@ -0,0 +1,123 @@
+package volumes
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/client"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/docker"
+	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/authorization"
+	"github.com/portainer/portainer/api/internal/organization"
+)
+
+var errVolumeAlreadyExists = errors.New("A volume with the same name already exists")
+
+type volumeRestoreResponse struct {
+	Name string `json:"Name"`
+	// Created is true when the volume was created by the restore operation
+	Created         bool                       `json:"Created"`
+	ResourceControl *portainer.ResourceControl `json:"ResourceControl,omitempty"`
+}
+
+// POST request on /api/endpoints/:id/volumes/:name/restore?nodeName=<nodeName>&overwrite=<overwrite>
+//
+// Extracts the archive sent as the request body into a volume. The archive is a tar archive, optionally
+// compressed with gzip, bzip2 or xz, such as the archives returned by the backup operation.
+// The volume is created when it does not exist, an existing volume is only restored when the overwrite
+// query parameter is set: the archive is then extracted over the existing content of the volume.
+func (handler *Handler) volumeRestore(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	volumeName, err := request.RetrieveRouteVariableValue(r, "name")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid volume name route variable", err}
+	}
+
+	overwrite, _ := request.RetrieveBooleanQueryParameter(r, "overwrite", true)
+
+	securityContext, err := security.RetrieveRestrictedRequestContext(r)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve info from request context", err}
+	}
+
+	endpoint, cli, httpErr := handler.retrieveVolumeEndpoint(r)
+	if httpErr != nil {
+		return httpErr
+	}
+	defer cli.Close()
+
+	restoreResponse := &volumeRestoreResponse{Name: volumeName}
+
+	existingVolume, err := cli.VolumeInspect(context.Background(), volumeName)
+	switch {
+	case err == nil:
+		if !overwrite {
+			return &httperror.HandlerError{http.StatusConflict, "A volume with the same name already exists, use the overwrite parameter to restore the archive into it", errVolumeAlreadyExists}
+		}
+
+		httpErr = handler.userCanAccessVolume(securityContext, &existingVolume)
+		if httpErr != nil {
+			return httpErr
+		}
+
+	case client.IsErrNotFound(err):
+		created, err := cli.VolumeCreate(context.Background(), volume.VolumeCreateBody{Name: volumeName})
+		if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to create the volume", err}
+		}
+		restoreResponse.Created = true
+
+		restoreResponse.ResourceControl, err = handler.createVolumeResourceControl(created.Name+created.CreatedAt, endpoint, securityContext)
+		if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist the resource control associated to the volume", err}
+		}
+
+	default:
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to inspect the volume", err}
+	}
+
+	helper, err := docker.NewVolumeHelper(cli, volumeName, false)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to create the volume helper container", err}
+	}
+	defer removeVolumeHelper(helper)
+
+	err = helper.Import(r.Body)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to restore the archive into the volume", err}
+	}
+
+	return response.JSON(w, restoreResponse)
+}
+
+func (handler *Handler) createVolumeResourceControl(resourceID string, endpoint *portainer.Endpoint, securityContext *security.RestrictedRequestContext) (*portainer.ResourceControl, error) {
+	settings, err := handler.DataStore.Settings().Settings()
+	if err != nil {
+		return nil, err
+	}
+
+	userTeamIDs := make([]portainer.TeamID, 0)
+	for _, membership := range securityContext.UserMemberships {
+		userTeamIDs = append(userTeamIDs, membership.TeamID)
+	}
+
+	settings, err = organization.EffectiveSettings(handler.DataStore, settings, endpoint.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	policy := authorization.EffectiveResourceOwnershipPolicy(endpoint, settings)
+	resourceControl := authorization.NewDefaultResourceControl(resourceID, portainer.VolumeResourceControl, securityContext.UserID, userTeamIDs, policy)
+
+	err = handler.DataStore.ResourceControl().CreateResourceControl(resourceControl)
+	if err != nil {
+		return nil, err
+	}
+
+	return resourceControl, nil
+}
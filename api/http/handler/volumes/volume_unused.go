@@ -0,0 +1,66 @@
+package volumes
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+	"github.com/portainer/portainer/api/docker"
+)
+
+var errNoSnapshot = errors.New("The endpoint has no snapshot")
+
+type unusedVolumesResponse struct {
+	Volumes []portainer.DockerVolumeUsage `json:"Volumes"`
+	// ReclaimableSize is the disk space in bytes used by the unused volumes of known size
+	ReclaimableSize int64 `json:"ReclaimableSize"`
+	SnapshotTime    int64 `json:"SnapshotTime"`
+}
+
+// GET request on /api/endpoints/:id/volumes/unused?unusedFor=<unusedFor>
+//
+// Returns the volumes of the last snapshot of the endpoint that are not mounted by any container, along
+// with the disk space that removing them would reclaim. When unusedFor is specified, only the volumes
+// that were not used by a running container for at least this number of days are returned.
+func (handler *Handler) volumeUnusedList(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	endpointID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid endpoint identifier route variable", err}
+	}
+
+	unusedFor, _ := request.RetrieveNumericQueryParameter(r, "unusedFor", true)
+	if unusedFor < 0 {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid unusedFor query parameter. Value must be a positive number of days", errors.New("Invalid unusedFor query parameter")}
+	}
+
+	endpoint, err := handler.DataStore.Endpoint().Endpoint(portainer.EndpointID(endpointID))
+	if err == bolterrors.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find an endpoint with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find an endpoint with the specified identifier inside the database", err}
+	}
+
+	err = handler.requestBouncer.AuthorizedEndpointOperation(r, endpoint)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusForbidden, "Permission denied to access endpoint", err}
+	}
+
+	if len(endpoint.Snapshots) == 0 {
+		return &httperror.HandlerError{http.StatusNotFound, "The endpoint has no snapshot", errNoSnapshot}
+	}
+	snapshot := &endpoint.Snapshots[0]
+
+	unusedSince := time.Now().AddDate(0, 0, -unusedFor).Unix()
+	volumes, reclaimable := docker.UnusedVolumes(snapshot, unusedSince)
+
+	return response.JSON(w, &unusedVolumesResponse{
+		Volumes:         volumes,
+		ReclaimableSize: reclaimable,
+		SnapshotTime:    snapshot.Time,
+	})
+}
@@ -75,11 +75,11 @@ func (handler *Handler) handleAttachRequest(w http.ResponseWriter, r *http.Reque
 	}
 	defer websocketConn.Close()
 
-	return hijackAttachStartOperation(websocketConn, params.endpoint, params.ID)
+	return handler.hijackAttachStartOperation(websocketConn, params.endpoint, params.ID)
 }
 
-func hijackAttachStartOperation(websocketConn *websocket.Conn, endpoint *portainer.Endpoint, attachID string) error {
-	dial, err := initDial(endpoint)
+func (handler *Handler) hijackAttachStartOperation(websocketConn *websocket.Conn, endpoint *portainer.Endpoint, attachID string) error {
+	dial, err := handler.initDial(endpoint)
 	if err != nil {
 		return err
 	}
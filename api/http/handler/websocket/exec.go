@@ -81,11 +81,11 @@ func (handler *Handler) handleExecRequest(w http.ResponseWriter, r *http.Request
 	}
 	defer websocketConn.Close()
 
-	return hijackExecStartOperation(websocketConn, params.endpoint, params.ID)
+	return handler.hijackExecStartOperation(websocketConn, params.endpoint, params.ID)
 }
 
-func hijackExecStartOperation(websocketConn *websocket.Conn, endpoint *portainer.Endpoint, execID string) error {
-	dial, err := initDial(endpoint)
+func (handler *Handler) hijackExecStartOperation(websocketConn *websocket.Conn, endpoint *portainer.Endpoint, execID string) error {
+	dial, err := handler.initDial(endpoint)
 	if err != nil {
 		return err
 	}
@@ -5,6 +5,7 @@ import (
 	"github.com/gorilla/websocket"
 	httperror "github.com/portainer/libhttp/error"
 	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/docker"
 	"github.com/portainer/portainer/api/http/security"
 	"github.com/portainer/portainer/api/kubernetes/cli"
 )
@@ -15,7 +16,10 @@ type Handler struct {
 	DataStore               portainer.DataStore
 	SignatureService        portainer.DigitalSignatureService
 	ReverseTunnelService    portainer.ReverseTunnelService
+	DockerClientFactory     *docker.ClientFactory
 	KubernetesClientFactory *cli.ClientFactory
+	NotificationService     portainer.NotificationService
+	TaskManager             portainer.TaskManager
 	requestBouncer          *security.RequestBouncer
 	connectionUpgrader      websocket.Upgrader
 }
@@ -33,5 +37,9 @@ func NewHandler(bouncer *security.RequestBouncer) *Handler {
 		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.websocketAttach)))
 	h.PathPrefix("/websocket/pod").Handler(
 		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.websocketPodExec)))
+	h.PathPrefix("/websocket/notifications").Handler(
+		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.websocketNotifications)))
+	h.PathPrefix("/websocket/task").Handler(
+		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.websocketTask)))
 	return h
 }
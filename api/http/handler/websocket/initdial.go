@@ -8,12 +8,16 @@ import (
 	"net/url"
 )
 
-func initDial(endpoint *portainer.Endpoint) (net.Conn, error) {
+func (handler *Handler) initDial(endpoint *portainer.Endpoint) (net.Conn, error) {
 	url, err := url.Parse(endpoint.URL)
 	if err != nil {
 		return nil, err
 	}
 
+	if url.Scheme == "ssh" {
+		return handler.DockerClientFactory.DialSSH(endpoint)
+	}
+
 	host := url.Host
 
 	if url.Scheme == "unix" || url.Scheme == "npipe" {
@@ -0,0 +1,70 @@
+package websocket
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/notification"
+)
+
+// pingPeriod is the interval between two ping messages sent on the long-lived websocket connections
+const pingPeriod = 30 * time.Second
+
+// websocketNotifications handles GET requests on /websocket/notifications?token=<token>
+// The request is upgraded to the websocket protocol and each notification added to the inbox
+// of the authenticated user is pushed as a JSON message, translated in the locale of the user.
+// Authentication is controled via the mandatory token query parameter.
+func (handler *Handler) websocketNotifications(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	tokenData, err := security.RetrieveTokenData(r)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve user authentication token", err}
+	}
+
+	r.Header.Del("Origin")
+
+	websocketConn, err := handler.connectionUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "An error occured during websocket upgrade", err}
+	}
+	defer websocketConn.Close()
+
+	locale := security.RetrieveLocale(r)
+
+	notifications, unsubscribe := handler.NotificationService.Subscribe(tokenData.ID)
+	defer unsubscribe()
+
+	// the messages sent by the client are discarded, reading is required to process the control messages
+	// and to detect when the connection is closed
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			_, _, err := websocketConn.ReadMessage()
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(pingPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case userNotification := <-notifications:
+			userNotification.Notification = notification.Localize(userNotification.Notification, locale)
+			err = websocketConn.WriteJSON(userNotification)
+		case <-ticker.C:
+			err = websocketConn.WriteMessage(websocket.PingMessage, nil)
+		case <-closed:
+			return nil
+		}
+
+		if err != nil {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package websocket
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+	httperrors "github.com/portainer/portainer/api/http/errors"
+	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/task"
+)
+
+// websocketTask handles GET requests on /websocket/task?id=<taskID>&token=<token>
+// The request is upgraded to the websocket protocol, the current state of the task and each of its
+// updates are pushed as JSON messages. The connection is closed once the task is finished.
+// Authentication and access is controled via the mandatory token query parameter.
+func (handler *Handler) websocketTask(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	taskID, err := request.RetrieveNumericQueryParameter(r, "id", false)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid query parameter: id", err}
+	}
+
+	tokenData, err := security.RetrieveTokenData(r)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve user authentication token", err}
+	}
+
+	// subscribe before reading the current state so that no update is missed
+	updates, unsubscribe := handler.TaskManager.Subscribe(portainer.TaskID(taskID))
+	defer unsubscribe()
+
+	current, err := handler.DataStore.Task().Task(portainer.TaskID(taskID))
+	if err == bolterrors.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find a task with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a task with the specified identifier inside the database", err}
+	}
+
+	if tokenData.Role != portainer.AdministratorRole && current.UserID != tokenData.ID {
+		return &httperror.HandlerError{http.StatusForbidden, "Permission denied to access task", httperrors.ErrResourceAccessDenied}
+	}
+
+	r.Header.Del("Origin")
+
+	websocketConn, err := handler.connectionUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "An error occured during websocket upgrade", err}
+	}
+	defer websocketConn.Close()
+
+	err = websocketConn.WriteJSON(current)
+	if err != nil || task.IsFinished(current) {
+		return nil
+	}
+
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			_, _, err := websocketConn.ReadMessage()
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(pingPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case update := <-updates:
+			err = websocketConn.WriteJSON(update)
+			if err == nil && task.IsFinished(&update) {
+				websocketConn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+				return nil
+			}
+		case <-ticker.C:
+			err = websocketConn.WriteMessage(websocket.PingMessage, nil)
+		case <-closed:
+			return nil
+		}
+
+		if err != nil {
+			return nil
+		}
+	}
+}
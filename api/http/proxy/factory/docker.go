@@ -17,11 +17,38 @@ import (
 func (factory *ProxyFactory) newDockerProxy(endpoint *portainer.Endpoint) (http.Handler, error) {
 	if strings.HasPrefix(endpoint.URL, "unix://") || strings.HasPrefix(endpoint.URL, "npipe://") {
 		return factory.newDockerLocalProxy(endpoint)
+	} else if strings.HasPrefix(endpoint.URL, "ssh://") {
+		return factory.newDockerSSHProxy(endpoint)
 	}
 
 	return factory.newDockerHTTPProxy(endpoint)
 }
 
+func (factory *ProxyFactory) newDockerSSHProxy(endpoint *portainer.Endpoint) (http.Handler, error) {
+	httpTransport, err := factory.dockerClientFactory.SSHTransport(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	transportParameters := &docker.TransportParameters{
+		Endpoint:             endpoint,
+		DataStore:            factory.dataStore,
+		ReverseTunnelService: factory.reverseTunnelService,
+		SignatureService:     factory.signatureService,
+		DockerClientFactory:  factory.dockerClientFactory,
+	}
+
+	dockerTransport, err := docker.NewTransport(transportParameters, httpTransport)
+	if err != nil {
+		return nil, err
+	}
+
+	// the host is only used to build the request URLs, connections are opened through the SSH tunnel
+	proxy := newSingleHostReverseProxyWithHostHeader(&url.URL{Scheme: "http", Host: "docker"})
+	proxy.Transport = dockerTransport
+	return proxy, nil
+}
+
 func (factory *ProxyFactory) newDockerLocalProxy(endpoint *portainer.Endpoint) (http.Handler, error) {
 	endpointURL, err := url.Parse(endpoint.URL)
 	if err != nil {
@@ -7,6 +7,7 @@ import (
 
 	"github.com/portainer/portainer/api/http/proxy/factory/responseutils"
 	"github.com/portainer/portainer/api/internal/authorization"
+	"github.com/portainer/portainer/api/internal/organization"
 
 	"github.com/portainer/portainer/api"
 )
@@ -90,10 +91,38 @@ func (transport *Transport) newResourceControlFromPortainerLabels(labelsObject m
 	return nil, nil
 }
 
-func (transport *Transport) createPrivateResourceControl(resourceIdentifier string, resourceType portainer.ResourceControlType, userID portainer.UserID) (*portainer.ResourceControl, error) {
-	resourceControl := authorization.NewPrivateResourceControl(resourceIdentifier, resourceType, userID)
+// createDefaultResourceControl creates and persists the resource control associated to a newly created resource,
+// based on the resource ownership policy that applies to the endpoint.
+func (transport *Transport) createDefaultResourceControl(resourceIdentifier string, resourceType portainer.ResourceControlType, userID portainer.UserID) (*portainer.ResourceControl, error) {
+	settings, err := transport.dataStore.Settings().Settings()
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint, err := transport.dataStore.Endpoint().Endpoint(transport.endpoint.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	teamMemberships, err := transport.dataStore.TeamMembership().TeamMembershipsByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	userTeamIDs := make([]portainer.TeamID, 0)
+	for _, membership := range teamMemberships {
+		userTeamIDs = append(userTeamIDs, membership.TeamID)
+	}
+
+	settings, err = organization.EffectiveSettings(transport.dataStore, settings, endpoint.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	policy := authorization.EffectiveResourceOwnershipPolicy(endpoint, settings)
+	resourceControl := authorization.NewDefaultResourceControl(resourceIdentifier, resourceType, userID, userTeamIDs, policy)
 
-	err := transport.dataStore.ResourceControl().CreateResourceControl(resourceControl)
+	err = transport.dataStore.ResourceControl().CreateResourceControl(resourceControl)
 	if err != nil {
 		log.Printf("[ERROR] [http,proxy,docker,transport] [message: unable to persist resource control] [resource: %s] [err: %s]", resourceIdentifier, err)
 		return nil, err
@@ -175,7 +204,7 @@ func (transport *Transport) applyAccessControlOnResourceList(parameters *resourc
 	return transport.filterResourceList(parameters, resourceData, executor.operationContext)
 }
 
-func (transport *Transport) decorateResourceList(parameters *resourceOperationParameters, resourceData []interface{}, resourceControls []portainer.ResourceControl) ([]interface{}, error) {
+func (transport *Transport) decorateResourceList(parameters *resourceOperationParameters, resourceData []interface{}, resourceControls *authorization.ResourceControlIndex) ([]interface{}, error) {
 	decoratedResourceData := make([]interface{}, 0)
 
 	for _, resource := range resourceData {
@@ -256,8 +285,8 @@ func (transport *Transport) filterResourceList(parameters *resourceOperationPara
 	return filteredResourceData, nil
 }
 
-func (transport *Transport) findResourceControl(resourceIdentifier string, resourceType portainer.ResourceControlType, resourceLabelsObject map[string]interface{}, resourceControls []portainer.ResourceControl) (*portainer.ResourceControl, error) {
-	resourceControl := authorization.GetResourceControlByResourceIDAndType(resourceIdentifier, resourceType, resourceControls)
+func (transport *Transport) findResourceControl(resourceIdentifier string, resourceType portainer.ResourceControlType, resourceLabelsObject map[string]interface{}, resourceControls *authorization.ResourceControlIndex) (*portainer.ResourceControl, error) {
+	resourceControl := resourceControls.Get(resourceIdentifier, resourceType)
 	if resourceControl != nil {
 		return resourceControl, nil
 	}
@@ -265,7 +294,7 @@ func (transport *Transport) findResourceControl(resourceIdentifier string, resou
 	if resourceLabelsObject != nil {
 		if resourceLabelsObject[resourceLabelForDockerServiceID] != nil {
 			inheritedServiceIdentifier := resourceLabelsObject[resourceLabelForDockerServiceID].(string)
-			resourceControl = authorization.GetResourceControlByResourceIDAndType(inheritedServiceIdentifier, portainer.ServiceResourceControl, resourceControls)
+			resourceControl = resourceControls.Get(inheritedServiceIdentifier, portainer.ServiceResourceControl)
 
 			if resourceControl != nil {
 				return resourceControl, nil
@@ -274,7 +303,7 @@ func (transport *Transport) findResourceControl(resourceIdentifier string, resou
 
 		if resourceLabelsObject[resourceLabelForDockerSwarmStackName] != nil {
 			inheritedSwarmStackIdentifier := resourceLabelsObject[resourceLabelForDockerSwarmStackName].(string)
-			resourceControl = authorization.GetResourceControlByResourceIDAndType(inheritedSwarmStackIdentifier, portainer.StackResourceControl, resourceControls)
+			resourceControl = resourceControls.Get(inheritedSwarmStackIdentifier, portainer.StackResourceControl)
 
 			if resourceControl != nil {
 				return resourceControl, nil
@@ -283,7 +312,7 @@ func (transport *Transport) findResourceControl(resourceIdentifier string, resou
 
 		if resourceLabelsObject[resourceLabelForDockerComposeStackName] != nil {
 			inheritedComposeStackIdentifier := resourceLabelsObject[resourceLabelForDockerComposeStackName].(string)
-			resourceControl = authorization.GetResourceControlByResourceIDAndType(inheritedComposeStackIdentifier, portainer.StackResourceControl, resourceControls)
+			resourceControl = resourceControls.Get(inheritedComposeStackIdentifier, portainer.StackResourceControl)
 
 			if resourceControl != nil {
 				return resourceControl, nil
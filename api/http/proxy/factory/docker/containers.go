@@ -8,8 +8,10 @@ import (
 	"io/ioutil"
 	"net/http"
 
+	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/client"
 	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/docker"
 	"github.com/portainer/portainer/api/http/proxy/factory/responseutils"
 	"github.com/portainer/portainer/api/http/security"
 	"github.com/portainer/portainer/api/internal/authorization"
@@ -46,35 +48,17 @@ func getInheritedResourceControlFromContainerLabels(dockerClient *client.Client,
 	return nil, nil
 }
 
-// containerListOperation extracts the response as a JSON array, loop through the containers array
-// decorate and/or filter the containers based on resource controls before rewriting the response.
+// containerListOperation streams the response JSON array, decorate and/or filter the containers
+// based on resource controls and the hidden container rules before rewriting the response.
 func (transport *Transport) containerListOperation(response *http.Response, executor *operationExecutor) error {
 	// ContainerList response is a JSON array
 	// https://docs.docker.com/engine/api/v1.28/#operation/ContainerList
-	responseArray, err := responseutils.GetResponseAsJSONArray(response)
-	if err != nil {
-		return err
-	}
-
-	resourceOperationParameters := &resourceOperationParameters{
-		resourceIdentifierAttribute: containerObjectIdentifier,
-		resourceType:                portainer.ContainerResourceControl,
-		labelsObjectSelector:        selectorContainerLabelsFromContainerListOperation,
-	}
-
-	responseArray, err = transport.applyAccessControlOnResourceList(resourceOperationParameters, responseArray, executor)
-	if err != nil {
-		return err
-	}
-
-	if executor.labelBlackList != nil {
-		responseArray, err = filterContainersWithBlackListedLabels(responseArray, executor.labelBlackList)
-		if err != nil {
-			return err
-		}
+	resourceListParameters := &resourceListParameters{
+		resourceType:  portainer.ContainerResourceControl,
+		decodeElement: decodeContainerListElement,
 	}
 
-	return responseutils.RewriteResponse(response, responseArray, http.StatusOK)
+	return responseutils.RewriteResponseArray(response, transport.rewriteResourceList(resourceListParameters, executor))
 }
 
 // containerInspectOperation extracts the response as a JSON object, verify that the user
@@ -87,6 +71,13 @@ func (transport *Transport) containerInspectOperation(response *http.Response, e
 		return err
 	}
 
+	if !executor.operationContext.isAdmin && executor.hiddenContainers != nil {
+		containerName, _ := responseObject["Name"].(string)
+		if executor.hiddenContainers.IsHidden([]string{containerName}, containerLabels(selectorContainerLabelsFromContainerInspectOperation(responseObject))) {
+			return responseutils.RewriteNotFoundResponse(response, "No such container")
+		}
+	}
+
 	resourceOperationParameters := &resourceOperationParameters{
 		resourceIdentifierAttribute: containerObjectIdentifier,
 		resourceType:                portainer.ContainerResourceControl,
@@ -118,51 +109,40 @@ func selectorContainerLabelsFromContainerListOperation(responseObject map[string
 	return containerLabelsObject
 }
 
-// filterContainersWithLabels loops through a list of containers, and filters containers that do not contains
-// any labels in the labels black list.
-func filterContainersWithBlackListedLabels(containerData []interface{}, labelBlackList []portainer.Pair) ([]interface{}, error) {
-	filteredContainerData := make([]interface{}, 0)
-
-	for _, container := range containerData {
-		containerObject := container.(map[string]interface{})
-
-		containerLabels := selectorContainerLabelsFromContainerListOperation(containerObject)
-		if containerLabels != nil {
-			if !containerHasBlackListedLabel(containerLabels, labelBlackList) {
-				filteredContainerData = append(filteredContainerData, containerObject)
-			}
-		} else {
-			filteredContainerData = append(filteredContainerData, containerObject)
-		}
+// decodeContainerListElement decodes the properties of a container list element used by the access control.
+// This decoder is specific to the containerList Docker operation.
+// API schema reference: https://docs.docker.com/engine/api/v1.28/#operation/ContainerList
+func decodeContainerListElement(data json.RawMessage) (*resourceListElement, error) {
+	var container struct {
+		ID     string                 `json:"Id"`
+		Names  []string               `json:"Names"`
+		Labels map[string]interface{} `json:"Labels"`
 	}
 
-	return filteredContainerData, nil
-}
-
-func containerHasBlackListedLabel(containerLabels map[string]interface{}, labelBlackList []portainer.Pair) bool {
-	for key, value := range containerLabels {
-		labelName := key
-		labelValue := value.(string)
-
-		for _, blackListedLabel := range labelBlackList {
-			if blackListedLabel.Name == labelName && blackListedLabel.Value == labelValue {
-				return true
-			}
-		}
+	err := json.Unmarshal(data, &container)
+	if err != nil {
+		return nil, err
 	}
 
-	return false
+	return &resourceListElement{
+		identifier: container.ID,
+		names:      container.Names,
+		labels:     container.Labels,
+	}, nil
 }
 
 func (transport *Transport) decorateContainerCreationOperation(request *http.Request, resourceIdentifierAttribute string, resourceType portainer.ResourceControlType) (*http.Response, error) {
 	type PartialContainer struct {
-		HostConfig struct {
+		Healthcheck *container.HealthConfig `json:"Healthcheck"`
+		HostConfig  struct {
+			container.Resources
 			Privileged bool          `json:"Privileged"`
 			PidMode    string        `json:"PidMode"`
 			Devices    []interface{} `json:"Devices"`
 			CapAdd     []string      `json:"CapAdd"`
 			CapDrop    []string      `json:"CapDrop"`
 			Binds      []string      `json:"Binds"`
+			Isolation  string        `json:"Isolation"`
 		} `json:"HostConfig"`
 	}
 
@@ -180,19 +160,48 @@ func (transport *Transport) decorateContainerCreationOperation(request *http.Req
 		return nil, err
 	}
 
-	if !isAdminOrEndpointAdmin {
-		settings, err := transport.dataStore.Settings().Settings()
-		if err != nil {
-			return nil, err
-		}
+	body, err := ioutil.ReadAll(request.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	partialContainer := &PartialContainer{}
+	err = json.Unmarshal(body, partialContainer)
+	if err != nil {
+		return nil, err
+	}
 
-		body, err := ioutil.ReadAll(request.Body)
+	// reject the configurations that do not match the operating system of the host with an explicit error
+	osType := docker.EndpointOSType(transport.endpoint)
+	err = docker.ValidateIsolation(container.Isolation(partialContainer.HostConfig.Isolation), osType)
+	if err != nil {
+		return responseutils.WriteBadRequestResponse(err.Error())
+	}
+
+	for _, bind := range partialContainer.HostConfig.Binds {
+		_, err = docker.ParseBind(bind, osType)
 		if err != nil {
-			return nil, err
+			return responseutils.WriteBadRequestResponse(err.Error())
 		}
+	}
 
-		partialContainer := &PartialContainer{}
-		err = json.Unmarshal(body, partialContainer)
+	err = docker.ValidateResourceLimits(&partialContainer.HostConfig.Resources, docker.EndpointRuntimeCapabilities(transport.endpoint))
+	if err != nil {
+		return responseutils.WriteBadRequestResponse(err.Error())
+	}
+
+	err = docker.ValidateHealthConfig(partialContainer.Healthcheck)
+	if err != nil {
+		return responseutils.WriteBadRequestResponse(err.Error())
+	}
+
+	err = docker.ValidateDeviceRequests(partialContainer.HostConfig.DeviceRequests, transport.dockerClientFactory.ServerAPIVersion(transport.endpoint))
+	if err != nil {
+		return responseutils.WriteBadRequestResponse(err.Error())
+	}
+
+	if !isAdminOrEndpointAdmin {
+		settings, err := transport.dataStore.Settings().Settings()
 		if err != nil {
 			return nil, err
 		}
@@ -205,7 +214,7 @@ func (transport *Transport) decorateContainerCreationOperation(request *http.Req
 			return forbiddenResponse, errors.New("forbidden to use pid host namespace")
 		}
 
-		if !settings.AllowDeviceMappingForRegularUsers && len(partialContainer.HostConfig.Devices) > 0 {
+		if !settings.AllowDeviceMappingForRegularUsers && (len(partialContainer.HostConfig.Devices) > 0 || len(partialContainer.HostConfig.DeviceRequests) > 0) {
 			return forbiddenResponse, errors.New("forbidden to use device mapping")
 		}
 
@@ -216,10 +225,18 @@ func (transport *Transport) decorateContainerCreationOperation(request *http.Req
 		if !settings.AllowBindMountsForRegularUsers && (len(partialContainer.HostConfig.Binds) > 0) {
 			return forbiddenResponse, errors.New("forbidden to use bind mounts")
 		}
+	}
 
-		request.Body = ioutil.NopCloser(bytes.NewBuffer(body))
+	body, err = transport.applyContainerLabelPolicy(request, body, []string{"Labels"})
+	if labelsErr, ok := err.(*missingLabelsError); ok {
+		return responseutils.WriteBadRequestResponse(labelsErr.Error())
+	} else if err != nil {
+		return nil, err
 	}
 
+	request.Body = ioutil.NopCloser(bytes.NewBuffer(body))
+	request.ContentLength = int64(len(body))
+
 	response, err := transport.executeDockerRequest(request)
 	if err != nil {
 		return response, err
@@ -0,0 +1,67 @@
+package docker
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/docker/docker/client"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/docker"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+// containerLabels returns the labels of a container decoded from a Docker API response
+func containerLabels(labels map[string]interface{}) map[string]string {
+	containerLabels := make(map[string]string)
+	for key, value := range labels {
+		if labelValue, ok := value.(string); ok {
+			containerLabels[key] = labelValue
+		}
+	}
+	return containerLabels
+}
+
+// isHiddenContainer returns true when the container targeted by a request must be hidden from the user
+func (transport *Transport) isHiddenContainer(request *http.Request, containerID string) (bool, error) {
+	tokenData, err := security.RetrieveTokenData(request)
+	if err != nil {
+		return false, err
+	}
+
+	if tokenData.Role == portainer.AdministratorRole {
+		return false, nil
+	}
+
+	settings, err := transport.dataStore.Settings().Settings()
+	if err != nil {
+		return false, err
+	}
+
+	rules := docker.NewHiddenContainerRules(settings)
+	if rules.Empty() {
+		return false, nil
+	}
+
+	dockerClient := transport.dockerClient
+	if nodeName := request.Header.Get(portainer.PortainerAgentTargetHeader); nodeName != "" {
+		dockerClient, err = transport.dockerClientFactory.CreateClient(transport.endpoint, nodeName)
+		if err != nil {
+			return false, err
+		}
+		defer dockerClient.Close()
+	}
+
+	container, err := dockerClient.ContainerInspect(context.Background(), containerID)
+	if client.IsErrNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	labels := make(map[string]string)
+	if container.Config != nil {
+		labels = container.Config.Labels
+	}
+
+	return rules.IsHidden([]string{container.Name}, labels), nil
+}
@@ -0,0 +1,67 @@
+package docker
+
+import (
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/http/proxy/factory/responseutils"
+)
+
+// DefaultHostBrowserPaths are the host paths allowed when no path is defined in the settings
+var DefaultHostBrowserPaths = []string{"/etc/docker", "/var/log"}
+
+// hostBrowserReadOperations are the agent browse operations allowed on the host filesystem
+var hostBrowserReadOperations = map[string]bool{
+	"/browse/ls":  true,
+	"/browse/get": true,
+}
+
+// HostBrowserAllowedPaths returns the host paths allowed by the settings
+func HostBrowserAllowedPaths(settings *portainer.HostBrowserSettings) []string {
+	if len(settings.AllowedPaths) == 0 {
+		return DefaultHostBrowserPaths
+	}
+	return settings.AllowedPaths
+}
+
+// HostPathAllowed returns true when the path is one of the allowed paths or is located under one of them.
+// The path is cleaned first so that it cannot leave an allowed path with ".." elements.
+func HostPathAllowed(hostPath string, allowedPaths []string) bool {
+	if hostPath == "" {
+		return false
+	}
+	hostPath = path.Clean("/" + hostPath)
+
+	for _, allowedPath := range allowedPaths {
+		allowedPath = path.Clean("/" + allowedPath)
+		if allowedPath == "/" || hostPath == allowedPath || strings.HasPrefix(hostPath, allowedPath+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// hostBrowserOperation restricts the browsing of the host filesystem to the administrators, to the read
+// operations and to the allowed paths
+func (transport *Transport) hostBrowserOperation(request *http.Request, operation string) (*http.Response, error) {
+	if !hostBrowserReadOperations[operation] || request.Method != http.MethodGet {
+		return responseutils.WriteAccessDeniedResponse()
+	}
+
+	settings, err := transport.dataStore.Settings().Settings()
+	if err != nil {
+		return nil, err
+	}
+
+	allowedPaths := HostBrowserAllowedPaths(&settings.HostBrowser)
+	if !HostPathAllowed(request.URL.Query().Get("path"), allowedPaths) {
+		return responseutils.WriteAccessDeniedResponse()
+	}
+
+	// the allowed paths are also sent to the agent so that it can sandbox the browsing on its side
+	request.Header.Set(portainer.PortainerAgentHostBrowserPathsHeader, strings.Join(allowedPaths, ","))
+
+	return transport.administratorOperation(request)
+}
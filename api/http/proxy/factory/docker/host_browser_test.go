@@ -0,0 +1,28 @@
+package docker
+
+import "testing"
+
+func TestHostPathAllowed(t *testing.T) {
+	allowedPaths := []string{"/etc/docker", "/var/log/"}
+
+	tests := []struct {
+		path     string
+		expected bool
+	}{
+		{"/etc/docker", true},
+		{"/etc/docker/daemon.json", true},
+		{"/var/log/syslog", true},
+		{"var/log/syslog", true},
+		{"/etc/dockerd", false},
+		{"/etc/docker/../shadow", false},
+		{"/var/log/../../etc/shadow", false},
+		{"/", false},
+		{"", false},
+	}
+
+	for _, test := range tests {
+		if result := HostPathAllowed(test.path, allowedPaths); result != test.expected {
+			t.Errorf("HostPathAllowed(%q): got %t, want %t", test.path, result, test.expected)
+		}
+	}
+}
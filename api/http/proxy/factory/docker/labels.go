@@ -0,0 +1,166 @@
+package docker
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+// missingLabelsError is returned when a container is created without the labels required by the label policy
+type missingLabelsError struct {
+	labels []string
+}
+
+func (err *missingLabelsError) Error() string {
+	return fmt.Sprintf("missing required label(s): %s", strings.Join(err.labels, ", "))
+}
+
+// applyContainerLabelPolicy injects the labels of the label policy in the labels of a container and ensures
+// that the required labels are defined. The labels are injected in the object located at the path parameter
+// of the JSON body, the required labels are also searched in the additional paths.
+func (transport *Transport) applyContainerLabelPolicy(request *http.Request, body []byte, path []string, additionalPaths ...[]string) ([]byte, error) {
+	settings, err := transport.dataStore.Settings().Settings()
+	if err != nil {
+		return nil, err
+	}
+
+	policy := &settings.ContainerLabelPolicy
+	if !policy.InjectOwner && !policy.InjectStack && !policy.InjectTimestamp && len(policy.Labels) == 0 && len(policy.RequiredLabels) == 0 {
+		return body, nil
+	}
+
+	var object map[string]interface{}
+	err = json.Unmarshal(body, &object)
+	if err != nil {
+		return nil, err
+	}
+
+	labels := jsonLabels(object, path, true)
+
+	missingLabels := make([]string, 0)
+	for _, requiredLabel := range policy.RequiredLabels {
+		if hasLabel(labels, requiredLabel) {
+			continue
+		}
+
+		found := false
+		for _, additionalPath := range additionalPaths {
+			if hasLabel(jsonLabels(object, additionalPath, false), requiredLabel) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			missingLabels = append(missingLabels, requiredLabel)
+		}
+	}
+
+	if len(missingLabels) > 0 {
+		return nil, &missingLabelsError{labels: missingLabels}
+	}
+
+	injectedLabels, err := transport.containerPolicyLabels(request, policy, labels)
+	if err != nil {
+		return nil, err
+	}
+
+	for key, value := range injectedLabels {
+		if _, ok := labels[key]; !ok {
+			labels[key] = value
+		}
+	}
+
+	return json.Marshal(object)
+}
+
+func (transport *Transport) containerPolicyLabels(request *http.Request, policy *portainer.ContainerLabelPolicy, labels map[string]interface{}) (map[string]string, error) {
+	prefix := policy.Prefix
+	if prefix == "" {
+		prefix = portainer.DefaultContainerLabelPrefix
+	}
+
+	injectedLabels := make(map[string]string)
+	for _, label := range policy.Labels {
+		injectedLabels[label.Name] = label.Value
+	}
+
+	if policy.InjectOwner {
+		tokenData, err := security.RetrieveTokenData(request)
+		if err != nil {
+			return nil, err
+		}
+		injectedLabels[prefix+".owner"] = tokenData.Username
+
+		teamNames, err := transport.userTeamNames(tokenData.ID)
+		if err != nil {
+			return nil, err
+		}
+		if len(teamNames) > 0 {
+			injectedLabels[prefix+".owner.teams"] = strings.Join(teamNames, ",")
+		}
+	}
+
+	if policy.InjectStack {
+		for _, stackLabel := range []string{"com.docker.compose.project", "com.docker.stack.namespace"} {
+			if stack, ok := labels[stackLabel].(string); ok && stack != "" {
+				injectedLabels[prefix+".stack"] = stack
+				break
+			}
+		}
+	}
+
+	if policy.InjectTimestamp {
+		injectedLabels[prefix+".created_at"] = time.Now().UTC().Format(time.RFC3339)
+	}
+
+	return injectedLabels, nil
+}
+
+func (transport *Transport) userTeamNames(userID portainer.UserID) ([]string, error) {
+	memberships, err := transport.dataStore.TeamMembership().TeamMembershipsByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	teamNames := make([]string, 0, len(memberships))
+	for _, membership := range memberships {
+		team, err := transport.dataStore.Team().Team(membership.TeamID)
+		if err != nil {
+			continue
+		}
+		teamNames = append(teamNames, team.Name)
+	}
+
+	sort.Strings(teamNames)
+	return teamNames, nil
+}
+
+// jsonLabels returns the labels object located at the path of a JSON object. When create is true,
+// the missing objects of the path are created.
+func jsonLabels(object map[string]interface{}, path []string, create bool) map[string]interface{} {
+	current := object
+	for _, key := range path {
+		next, ok := current[key].(map[string]interface{})
+		if !ok {
+			if !create {
+				return nil
+			}
+			next = make(map[string]interface{})
+			current[key] = next
+		}
+		current = next
+	}
+	return current
+}
+
+func hasLabel(labels map[string]interface{}, label string) bool {
+	value, ok := labels[label].(string)
+	return ok && value != ""
+}
@@ -0,0 +1,75 @@
+package docker
+
+import (
+	"encoding/json"
+	"log"
+
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/http/proxy/factory/responseutils"
+	"github.com/portainer/portainer/api/internal/authorization"
+)
+
+type (
+	// resourceListElement is the partial representation of a resource list element, it only contains the
+	// properties required to apply the access control and the hidden container rules.
+	resourceListElement struct {
+		identifier string
+		names      []string
+		labels     map[string]interface{}
+		// properties are appended to the element when it is written back to the response
+		properties map[string]interface{}
+	}
+
+	resourceListElementDecoder func(data json.RawMessage) (*resourceListElement, error)
+
+	resourceListParameters struct {
+		resourceType  portainer.ResourceControlType
+		decodeElement resourceListElementDecoder
+	}
+)
+
+// rewriteResourceList returns the JSON element rewriter used to decorate and/or filter the elements of a resource list
+// based on resource controls. It is the streaming counterpart of applyAccessControlOnResourceList: the elements
+// are never fully unmarshaled and the resource controls are looked up in the precomputed index of the operation context.
+func (transport *Transport) rewriteResourceList(parameters *resourceListParameters, executor *operationExecutor) responseutils.JSONElementRewriter {
+	context := executor.operationContext
+
+	return func(data json.RawMessage) (json.RawMessage, error) {
+		element, err := parameters.decodeElement(data)
+		if err != nil {
+			return nil, err
+		}
+
+		if element.identifier == "" {
+			log.Printf("[WARN] [http,proxy,docker,filter] [message: unable to find resource identifier property in resource list element] [resource_type: %d]", parameters.resourceType)
+			return nil, nil
+		}
+
+		if !context.isAdmin && executor.hiddenContainers != nil && executor.hiddenContainers.IsHidden(element.names, containerLabels(element.labels)) {
+			return nil, nil
+		}
+
+		resourceControl, err := transport.findResourceControl(element.identifier, parameters.resourceType, element.labels, context.resourceControls)
+		if err != nil {
+			return nil, err
+		}
+
+		if resourceControl == nil && !context.isAdmin {
+			return nil, nil
+		}
+
+		if resourceControl != nil && !context.isAdmin && !authorization.UserCanAccessResource(context.userID, context.userTeamIDs, resourceControl) {
+			return nil, nil
+		}
+
+		properties := element.properties
+		if resourceControl != nil {
+			if properties == nil {
+				properties = make(map[string]interface{})
+			}
+			properties["Portainer"] = map[string]interface{}{"ResourceControl": resourceControl}
+		}
+
+		return responseutils.AppendJSONObjectProperties(data, properties)
+	}
+}
@@ -0,0 +1,225 @@
+package docker
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/docker"
+	"github.com/portainer/portainer/api/internal/authorization"
+)
+
+const largeContainerListSize = 10000
+
+// buildLargeContainerList returns a container list response and the associated resource controls.
+// The containers are split in five categories based on their index:
+// 0: restricted to user 2, 1: public, 2: restricted to team 7, 3: no resource control,
+// 4: inherited from the "web" compose stack, restricted to user 3.
+// Every 1000th container is named hidden_<index>.
+func buildLargeContainerList(size int) ([]byte, []portainer.ResourceControl) {
+	containers := make([]map[string]interface{}, 0, size)
+	resourceControls := make([]portainer.ResourceControl, 0)
+
+	stackResourceControl := authorization.NewRestrictedResourceControl("web", portainer.StackResourceControl, []portainer.UserID{3}, nil)
+	resourceControls = append(resourceControls, *stackResourceControl)
+
+	for idx := 0; idx < size; idx++ {
+		containerID := fmt.Sprintf("container%06d", idx)
+		name := fmt.Sprintf("/app_%d", idx)
+		if idx%1000 == 0 {
+			name = fmt.Sprintf("/hidden_%d", idx)
+		}
+
+		labels := map[string]interface{}{"maintainer": "portainer"}
+
+		switch idx % 5 {
+		case 0:
+			resourceControls = append(resourceControls, *authorization.NewRestrictedResourceControl(containerID, portainer.ContainerResourceControl, []portainer.UserID{2}, nil))
+		case 1:
+			resourceControls = append(resourceControls, *authorization.NewPublicResourceControl(containerID, portainer.ContainerResourceControl))
+		case 2:
+			resourceControls = append(resourceControls, *authorization.NewRestrictedResourceControl(containerID, portainer.ContainerResourceControl, nil, []portainer.TeamID{7}))
+		case 4:
+			labels[resourceLabelForDockerComposeStackName] = "web"
+		}
+
+		containers = append(containers, map[string]interface{}{
+			"Id":     containerID,
+			"Names":  []string{name},
+			"Image":  "nginx:latest",
+			"State":  "running",
+			"Labels": labels,
+			"Ports":  []interface{}{map[string]interface{}{"PrivatePort": 80, "Type": "tcp"}},
+		})
+	}
+
+	for idx := range resourceControls {
+		resourceControls[idx].ID = portainer.ResourceControlID(idx + 1)
+	}
+
+	data, _ := json.Marshal(containers)
+	return data, resourceControls
+}
+
+func newContainerListResponse(data []byte) *http.Response {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       ioutil.NopCloser(bytes.NewReader(data)),
+		Header:     make(http.Header),
+	}
+}
+
+func decodeContainerListResponse(t *testing.T, response *http.Response) []map[string]interface{} {
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var containers []map[string]interface{}
+	err = json.Unmarshal(body, &containers)
+	if err != nil {
+		t.Fatalf("the rewritten response is not a valid JSON array: %s", err)
+	}
+
+	return containers
+}
+
+func TestContainerListOperationWithLargePayload(t *testing.T) {
+	data, resourceControls := buildLargeContainerList(largeContainerListSize)
+	transport := &Transport{}
+
+	t.Run("Administrator", func(t *testing.T) {
+		response := newContainerListResponse(data)
+		executor := &operationExecutor{
+			operationContext: &restrictedDockerOperationContext{
+				isAdmin:          true,
+				userID:           1,
+				resourceControls: authorization.NewResourceControlIndex(resourceControls),
+			},
+			hiddenContainers: &docker.HiddenContainerRules{NamePatterns: []string{"hidden_*"}},
+		}
+
+		err := transport.containerListOperation(response, executor)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		containers := decodeContainerListResponse(t, response)
+		if len(containers) != largeContainerListSize {
+			t.Fatalf("expected %d containers, got %d", largeContainerListSize, len(containers))
+		}
+
+		for idx, container := range containers {
+			_, decorated := container["Portainer"]
+			if decorated != (idx%5 != 3) {
+				t.Fatalf("unexpected decoration for container %s: %v", container["Id"], container["Portainer"])
+			}
+			if container["Image"] != "nginx:latest" || container["Ports"] == nil {
+				t.Fatalf("container %s was not copied verbatim: %v", container["Id"], container)
+			}
+		}
+	})
+
+	t.Run("Standard user", func(t *testing.T) {
+		response := newContainerListResponse(data)
+		executor := &operationExecutor{
+			operationContext: &restrictedDockerOperationContext{
+				isAdmin:          false,
+				userID:           2,
+				userTeamIDs:      []portainer.TeamID{7},
+				resourceControls: authorization.NewResourceControlIndex(resourceControls),
+			},
+			hiddenContainers: &docker.HiddenContainerRules{NamePatterns: []string{"hidden_*"}},
+		}
+
+		err := transport.containerListOperation(response, executor)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		containers := decodeContainerListResponse(t, response)
+
+		// categories 0, 1 and 2 are accessible, the hidden containers all belong to category 0
+		expected := largeContainerListSize*3/5 - largeContainerListSize/1000
+		if len(containers) != expected {
+			t.Fatalf("expected %d containers, got %d", expected, len(containers))
+		}
+
+		for _, container := range containers {
+			var idx int
+			fmt.Sscanf(container["Id"].(string), "container%06d", &idx)
+			if idx%5 > 2 || idx%1000 == 0 {
+				t.Fatalf("container %s should have been filtered", container["Id"])
+			}
+
+			metadata, ok := container["Portainer"].(map[string]interface{})
+			if !ok || metadata["ResourceControl"] == nil {
+				t.Fatalf("container %s is not decorated with its resource control", container["Id"])
+			}
+		}
+	})
+}
+
+func TestVolumeListOperationAddsIdentifier(t *testing.T) {
+	data := []byte(`{"Volumes":[{"Name":"data","CreatedAt":"2020-01-01T00:00:00Z","Labels":{}},{"Name":"cache","CreatedAt":"2020-01-02T00:00:00Z","Labels":null}],"Warnings":null}`)
+	resourceControls := []portainer.ResourceControl{
+		*authorization.NewPublicResourceControl("data2020-01-01T00:00:00Z", portainer.VolumeResourceControl),
+	}
+
+	response := newContainerListResponse(data)
+	executor := &operationExecutor{
+		operationContext: &restrictedDockerOperationContext{
+			isAdmin:          false,
+			userID:           2,
+			resourceControls: authorization.NewResourceControlIndex(resourceControls),
+		},
+	}
+
+	err := (&Transport{}).volumeListOperation(response, executor)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var responseObject struct {
+		Volumes []map[string]interface{}
+	}
+	body, _ := ioutil.ReadAll(response.Body)
+	err = json.Unmarshal(body, &responseObject)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(responseObject.Volumes) != 1 {
+		t.Fatalf("expected 1 volume, got %d", len(responseObject.Volumes))
+	}
+
+	if responseObject.Volumes[0]["ID"] != "data2020-01-01T00:00:00Z" {
+		t.Fatalf("expected the volume identifier to be added, got %v", responseObject.Volumes[0]["ID"])
+	}
+}
+
+func BenchmarkContainerListOperation(b *testing.B) {
+	data, resourceControls := buildLargeContainerList(largeContainerListSize)
+	transport := &Transport{}
+	index := authorization.NewResourceControlIndex(resourceControls)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		executor := &operationExecutor{
+			operationContext: &restrictedDockerOperationContext{
+				userID:           2,
+				userTeamIDs:      []portainer.TeamID{7},
+				resourceControls: index,
+			},
+		}
+
+		err := transport.containerListOperation(newContainerListResponse(data), executor)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -34,28 +34,17 @@ func getInheritedResourceControlFromServiceLabels(dockerClient *client.Client, s
 	return nil, nil
 }
 
-// serviceListOperation extracts the response as a JSON array, loop through the service array
-// decorate and/or filter the services based on resource controls before rewriting the response.
+// serviceListOperation streams the response JSON array, decorate and/or filter the services
+// based on resource controls before rewriting the response.
 func (transport *Transport) serviceListOperation(response *http.Response, executor *operationExecutor) error {
 	// ServiceList response is a JSON array
 	// https://docs.docker.com/engine/api/v1.28/#operation/ServiceList
-	responseArray, err := responseutils.GetResponseAsJSONArray(response)
-	if err != nil {
-		return err
-	}
-
-	resourceOperationParameters := &resourceOperationParameters{
-		resourceIdentifierAttribute: serviceObjectIdentifier,
-		resourceType:                portainer.ServiceResourceControl,
-		labelsObjectSelector:        selectorServiceLabels,
+	resourceListParameters := &resourceListParameters{
+		resourceType:  portainer.ServiceResourceControl,
+		decodeElement: decodeServiceListElement,
 	}
 
-	responseArray, err = transport.applyAccessControlOnResourceList(resourceOperationParameters, responseArray, executor)
-	if err != nil {
-		return err
-	}
-
-	return responseutils.RewriteResponse(response, responseArray, http.StatusOK)
+	return responseutils.RewriteResponseArray(response, transport.rewriteResourceList(resourceListParameters, executor))
 }
 
 // serviceInspectOperation extracts the response as a JSON object, verify that the user
@@ -90,6 +79,28 @@ func selectorServiceLabels(responseObject map[string]interface{}) map[string]int
 	return nil
 }
 
+// decodeServiceListElement decodes the properties of a service list element used by the access control.
+// Labels are available under the "Spec.Labels" property.
+// API schema reference: https://docs.docker.com/engine/api/v1.28/#operation/ServiceList
+func decodeServiceListElement(data json.RawMessage) (*resourceListElement, error) {
+	var service struct {
+		ID   string `json:"ID"`
+		Spec struct {
+			Labels map[string]interface{} `json:"Labels"`
+		} `json:"Spec"`
+	}
+
+	err := json.Unmarshal(data, &service)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resourceListElement{
+		identifier: service.ID,
+		labels:     service.Spec.Labels,
+	}, nil
+}
+
 func (transport *Transport) decorateServiceCreationOperation(request *http.Request) (*http.Response, error) {
 	type PartialService struct {
 		TaskTemplate struct {
@@ -110,17 +121,17 @@ func (transport *Transport) decorateServiceCreationOperation(request *http.Reque
 		return nil, err
 	}
 
+	body, err := ioutil.ReadAll(request.Body)
+	if err != nil {
+		return nil, err
+	}
+
 	if !isAdminOrEndpointAdmin {
 		settings, err := transport.dataStore.Settings().Settings()
 		if err != nil {
 			return nil, err
 		}
 
-		body, err := ioutil.ReadAll(request.Body)
-		if err != nil {
-			return nil, err
-		}
-
 		partialService := &PartialService{}
 		err = json.Unmarshal(body, partialService)
 		if err != nil {
@@ -134,9 +145,17 @@ func (transport *Transport) decorateServiceCreationOperation(request *http.Reque
 				}
 			}
 		}
+	}
 
-		request.Body = ioutil.NopCloser(bytes.NewBuffer(body))
+	body, err = transport.applyContainerLabelPolicy(request, body, []string{"TaskTemplate", "ContainerSpec", "Labels"}, []string{"Labels"})
+	if labelsErr, ok := err.(*missingLabelsError); ok {
+		return responseutils.WriteBadRequestResponse(labelsErr.Error())
+	} else if err != nil {
+		return nil, err
 	}
 
+	request.Body = ioutil.NopCloser(bytes.NewBuffer(body))
+	request.ContentLength = int64(len(body))
+
 	return transport.replaceRegistryAuthenticationHeader(request)
 }
@@ -4,6 +4,7 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"log"
 	"net/http"
 	"path"
@@ -46,12 +47,12 @@ type (
 		isAdmin          bool
 		userID           portainer.UserID
 		userTeamIDs      []portainer.TeamID
-		resourceControls []portainer.ResourceControl
+		resourceControls *authorization.ResourceControlIndex
 	}
 
 	operationExecutor struct {
 		operationContext *restrictedDockerOperationContext
-		labelBlackList   []portainer.Pair
+		hiddenContainers *docker.HiddenContainerRules
 	}
 	restrictedOperationRequest func(*http.Response, *operationExecutor) error
 	operationRequest           func(*http.Request) error
@@ -98,6 +99,11 @@ func (transport *Transport) ProxyDockerRequest(request *http.Request) (*http.Res
 		request.Header.Set(portainer.PortainerAgentSignatureHeader, signature)
 	}
 
+	unsupported := transport.unsupportedFeature(requestPath)
+	if unsupported != "" {
+		return responseutils.WriteNotImplementedResponse(unsupported)
+	}
+
 	switch {
 	case strings.HasPrefix(requestPath, "/configs"):
 		return transport.proxyConfigRequest(request)
@@ -128,6 +134,30 @@ func (transport *Transport) ProxyDockerRequest(request *http.Request) (*http.Res
 	}
 }
 
+// unsupportedFeature returns a message describing why the Docker daemon of the endpoint cannot handle
+// the request when it targets a feature that is not supported by the daemon API version.
+// An empty string is returned when the feature is supported or when the daemon API version is unknown.
+func (transport *Transport) unsupportedFeature(requestPath string) string {
+	apiVersion := transport.dockerClientFactory.ServerAPIVersion(transport.endpoint)
+	if apiVersion == "" {
+		return ""
+	}
+
+	capabilities := docker.Capabilities(apiVersion)
+
+	feature, minVersion := "", ""
+	switch {
+	case strings.HasPrefix(requestPath, "/configs") && !capabilities.Configs:
+		feature, minVersion = "configs", "1.30"
+	case strings.HasPrefix(requestPath, "/secrets") && !capabilities.Secrets:
+		feature, minVersion = "secrets", "1.25"
+	default:
+		return ""
+	}
+
+	return fmt.Sprintf("%s are not supported by the Docker API version %s of this endpoint, version %s or later is required", feature, apiVersion, minVersion)
+}
+
 func (transport *Transport) executeDockerRequest(request *http.Request) (*http.Response, error) {
 	response, err := transport.HTTPTransport.RoundTrip(request)
 
@@ -152,7 +182,7 @@ func (transport *Transport) proxyAgentRequest(r *http.Request) (*http.Response,
 		// host file browser request
 		volumeIDParameter, found := r.URL.Query()["volumeID"]
 		if !found || len(volumeIDParameter) < 1 {
-			return transport.administratorOperation(r)
+			return transport.hostBrowserOperation(r, requestPath)
 		}
 
 		agentTargetHeader := r.Header.Get(portainer.PortainerAgentTargetHeader)
@@ -209,13 +239,27 @@ func (transport *Transport) proxyContainerRequest(request *http.Request) (*http.
 			action := path.Base(requestPath)
 
 			if action == "json" {
-				return transport.rewriteOperation(request, transport.containerInspectOperation)
+				return transport.rewriteOperationWithLabelFiltering(request, transport.containerInspectOperation)
+			}
+
+			hidden, err := transport.isHiddenContainer(request, containerID)
+			if err != nil {
+				return nil, err
+			} else if hidden {
+				return responseutils.WriteNotFoundResponse("No such container: " + containerID)
 			}
 			return transport.restrictedResourceOperation(request, containerID, portainer.ContainerResourceControl, false)
 		} else if match, _ := path.Match("/containers/*", requestPath); match {
 			// Handle /containers/{id} requests
 			containerID := path.Base(requestPath)
 
+			hidden, err := transport.isHiddenContainer(request, containerID)
+			if err != nil {
+				return nil, err
+			} else if hidden {
+				return responseutils.WriteNotFoundResponse("No such container: " + containerID)
+			}
+
 			if request.Method == http.MethodDelete {
 				return transport.executeGenericResourceDeletionOperation(request, containerID, portainer.ContainerResourceControl)
 			}
@@ -457,8 +501,8 @@ func (transport *Transport) restrictedResourceOperation(request *http.Request, r
 }
 
 // rewriteOperationWithLabelFiltering will create a new operation context with data that will be used
-// to decorate the original request's response as well as retrieve all the black listed labels
-// to filter the resources.
+// to decorate the original request's response as well as retrieve the rules used to hide containers
+// from non administrator users.
 func (transport *Transport) rewriteOperationWithLabelFiltering(request *http.Request, operation restrictedOperationRequest) (*http.Response, error) {
 	operationContext, err := transport.createOperationContext(request)
 	if err != nil {
@@ -472,7 +516,7 @@ func (transport *Transport) rewriteOperationWithLabelFiltering(request *http.Req
 
 	executor := &operationExecutor{
 		operationContext: operationContext,
-		labelBlackList:   settings.BlackListedLabels,
+		hiddenContainers: docker.NewHiddenContainerRules(settings),
 	}
 
 	return transport.executeRequestAndRewriteResponse(request, operation, executor)
@@ -525,7 +569,7 @@ func (transport *Transport) decorateGenericResourceCreationResponse(response *ht
 
 	resourceID := responseObject[resourceIdentifierAttribute].(string)
 
-	resourceControl, err := transport.createPrivateResourceControl(resourceID, resourceType, userID)
+	resourceControl, err := transport.createDefaultResourceControl(resourceID, resourceType, userID)
 	if err != nil {
 		return err
 	}
@@ -653,7 +697,7 @@ func (transport *Transport) createOperationContext(request *http.Request) (*rest
 	operationContext := &restrictedDockerOperationContext{
 		isAdmin:          true,
 		userID:           tokenData.ID,
-		resourceControls: resourceControls,
+		resourceControls: authorization.NewResourceControlIndex(resourceControls),
 	}
 
 	if tokenData.Role != portainer.AdministratorRole {
@@ -1,7 +1,9 @@
 package docker
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"net/http"
 	"path"
@@ -9,6 +11,7 @@ import (
 	"github.com/docker/docker/client"
 
 	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/docker"
 	"github.com/portainer/portainer/api/http/proxy/factory/responseutils"
 	"github.com/portainer/portainer/api/http/security"
 	"github.com/portainer/portainer/api/internal/authorization"
@@ -32,35 +35,38 @@ func getInheritedResourceControlFromVolumeLabels(dockerClient *client.Client, vo
 	return nil, nil
 }
 
-// volumeListOperation extracts the response as a JSON object, loop through the volume array
+// volumeListOperation extracts the response as a JSON object, streams the volume array
 // decorate and/or filter the volumes based on resource controls before rewriting the response.
 func (transport *Transport) volumeListOperation(response *http.Response, executor *operationExecutor) error {
 	// VolumeList response is a JSON object
 	// https://docs.docker.com/engine/api/v1.28/#operation/VolumeList
-	responseObject, err := responseutils.GetResponseAsJSONOBject(response)
+	body, err := responseutils.GetResponseBody(response)
 	if err != nil {
 		return err
 	}
 
-	// The "Volumes" field contains the list of volumes as an array of JSON objects
-	if responseObject["Volumes"] != nil {
-		volumeData := responseObject["Volumes"].([]interface{})
-
-		for _, volumeObject := range volumeData {
-			volume := volumeObject.(map[string]interface{})
-			if volume["Name"] == nil || volume["CreatedAt"] == nil {
-				return errors.New("missing identifier in Docker resource list response")
-			}
-			volume[volumeObjectIdentifier] = volume["Name"].(string) + volume["CreatedAt"].(string)
-		}
+	var responseObject map[string]json.RawMessage
+	err = json.Unmarshal(body, &responseObject)
+	if err != nil {
+		return err
+	}
 
-		resourceOperationParameters := &resourceOperationParameters{
-			resourceIdentifierAttribute: volumeObjectIdentifier,
-			resourceType:                portainer.VolumeResourceControl,
-			labelsObjectSelector:        selectorVolumeLabels,
+	// The "Volumes" field contains the list of volumes as an array of JSON objects
+	volumeData := responseObject["Volumes"]
+	if volumeData != nil && string(volumeData) != "null" {
+		resourceListParameters := &resourceListParameters{
+			resourceType:  portainer.VolumeResourceControl,
+			decodeElement: decodeVolumeListElement,
 		}
 
-		volumeData, err = transport.applyAccessControlOnResourceList(resourceOperationParameters, volumeData, executor)
+		rewriteElement := transport.rewriteResourceList(resourceListParameters, executor)
+		volumeData, err = responseutils.RewriteJSONArray(volumeData, func(data json.RawMessage) (json.RawMessage, error) {
+			data, err := maskVolumeListElementCredentials(data)
+			if err != nil {
+				return nil, err
+			}
+			return rewriteElement(data)
+		})
 		if err != nil {
 			return err
 		}
@@ -86,6 +92,11 @@ func (transport *Transport) volumeInspectOperation(response *http.Response, exec
 	}
 	responseObject[volumeObjectIdentifier] = responseObject["Name"].(string) + responseObject["CreatedAt"].(string)
 
+	options := responseutils.GetJSONObject(responseObject, "Options")
+	if mountOptions, ok := options["o"].(string); ok {
+		options["o"] = docker.MaskMountCredentials(mountOptions)
+	}
+
 	resourceOperationParameters := &resourceOperationParameters{
 		resourceIdentifierAttribute: volumeObjectIdentifier,
 		resourceType:                portainer.VolumeResourceControl,
@@ -95,6 +106,33 @@ func (transport *Transport) volumeInspectOperation(response *http.Response, exec
 	return transport.applyAccessControlOnResource(resourceOperationParameters, responseObject, response, executor)
 }
 
+// maskVolumeListElementCredentials masks the passwords of the mount options of a volume list element,
+// such as the password of a CIFS share. The elements without password are returned as is.
+func maskVolumeListElementCredentials(data json.RawMessage) (json.RawMessage, error) {
+	if !bytes.Contains(data, []byte("pass")) {
+		return data, nil
+	}
+
+	var volume map[string]json.RawMessage
+	err := json.Unmarshal(data, &volume)
+	if err != nil {
+		return nil, err
+	}
+
+	var options map[string]string
+	if volume["Options"] == nil || json.Unmarshal(volume["Options"], &options) != nil || options["o"] == "" {
+		return data, nil
+	}
+
+	options["o"] = docker.MaskMountCredentials(options["o"])
+	volume["Options"], err = json.Marshal(options)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(volume)
+}
+
 // selectorVolumeLabels retrieve the labels object associated to the volume object.
 // Labels are available under the "Labels" property.
 // API schema references:
@@ -104,6 +142,35 @@ func selectorVolumeLabels(responseObject map[string]interface{}) map[string]inte
 	return responseutils.GetJSONObject(responseObject, "Labels")
 }
 
+// decodeVolumeListElement decodes the properties of a volume list element used by the access control.
+// Volumes do not have an identifier, it is computed from the name and the creation date of the volume
+// and added to the element under the "ID" property.
+// API schema reference: https://docs.docker.com/engine/api/v1.28/#operation/VolumeList
+func decodeVolumeListElement(data json.RawMessage) (*resourceListElement, error) {
+	var volume struct {
+		Name      *string                `json:"Name"`
+		CreatedAt *string                `json:"CreatedAt"`
+		Labels    map[string]interface{} `json:"Labels"`
+	}
+
+	err := json.Unmarshal(data, &volume)
+	if err != nil {
+		return nil, err
+	}
+
+	if volume.Name == nil || volume.CreatedAt == nil {
+		return nil, errors.New("missing identifier in Docker resource list response")
+	}
+
+	identifier := *volume.Name + *volume.CreatedAt
+
+	return &resourceListElement{
+		identifier: identifier,
+		labels:     volume.Labels,
+		properties: map[string]interface{}{volumeObjectIdentifier: identifier},
+	}, nil
+}
+
 func (transport *Transport) decorateVolumeResourceCreationOperation(request *http.Request, resourceIdentifierAttribute string, resourceType portainer.ResourceControlType) (*http.Response, error) {
 	tokenData, err := security.RetrieveTokenData(request)
 	if err != nil {
@@ -152,7 +219,7 @@ func (transport *Transport) decorateVolumeCreationResponse(response *http.Respon
 	}
 	resourceID := responseObject["Name"].(string) + responseObject["CreatedAt"].(string)
 
-	resourceControl, err := transport.createPrivateResourceControl(resourceID, resourceType, userID)
+	resourceControl, err := transport.createDefaultResourceControl(resourceID, resourceType, userID)
 	if err != nil {
 		return err
 	}
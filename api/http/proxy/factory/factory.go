@@ -53,14 +53,23 @@ func (factory *ProxyFactory) NewLegacyExtensionProxy(extensionAPIURL string) (ht
 
 // NewEndpointProxy returns a new reverse proxy (filesystem based or HTTP) to an endpoint API server
 func (factory *ProxyFactory) NewEndpointProxy(endpoint *portainer.Endpoint) (http.Handler, error) {
+	var proxy http.Handler
+	var err error
+
 	switch endpoint.Type {
 	case portainer.AzureEnvironment:
 		return newAzureProxy(endpoint)
-	case portainer.EdgeAgentOnKubernetesEnvironment, portainer.AgentOnKubernetesEnvironment, portainer.KubernetesLocalEnvironment:
-		return factory.newKubernetesProxy(endpoint)
+	case portainer.EdgeAgentOnKubernetesEnvironment, portainer.AgentOnKubernetesEnvironment, portainer.KubernetesLocalEnvironment, portainer.KubeconfigKubernetesEnvironment:
+		proxy, err = factory.newKubernetesProxy(endpoint)
+	default:
+		proxy, err = factory.newDockerProxy(endpoint)
+	}
+
+	if err != nil {
+		return nil, err
 	}
 
-	return factory.newDockerProxy(endpoint)
+	return newLoggingProxy(proxy, endpoint, factory.dataStore), nil
 }
 
 // NewGitlabProxy returns a new HTTP proxy to a Gitlab API server
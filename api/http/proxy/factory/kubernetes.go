@@ -9,6 +9,7 @@ import (
 
 	portainer "github.com/portainer/portainer/api"
 	"github.com/portainer/portainer/api/crypto"
+	"github.com/portainer/portainer/api/kubernetes/cli"
 )
 
 func (factory *ProxyFactory) newKubernetesProxy(endpoint *portainer.Endpoint) (http.Handler, error) {
@@ -17,6 +18,8 @@ func (factory *ProxyFactory) newKubernetesProxy(endpoint *portainer.Endpoint) (h
 		return factory.newKubernetesLocalProxy(endpoint)
 	case portainer.EdgeAgentOnKubernetesEnvironment:
 		return factory.newKubernetesEdgeHTTPProxy(endpoint)
+	case portainer.KubeconfigKubernetesEnvironment:
+		return factory.newKubernetesKubeconfigProxy(endpoint)
 	}
 
 	return factory.newKubernetesAgentHTTPSProxy(endpoint)
@@ -50,6 +53,39 @@ func (factory *ProxyFactory) newKubernetesLocalProxy(endpoint *portainer.Endpoin
 	return proxy, nil
 }
 
+func (factory *ProxyFactory) newKubernetesKubeconfigProxy(endpoint *portainer.Endpoint) (http.Handler, error) {
+	config, err := cli.KubeconfigRESTConfig(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	remoteURL, err := url.Parse(config.Host)
+	if err != nil {
+		return nil, err
+	}
+
+	kubecli, err := factory.kubernetesClientFactory.GetKubeClient(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	tokenCache := factory.kubernetesTokenCacheManager.CreateTokenCache(int(endpoint.ID))
+	tokenManager, err := kubernetes.NewTokenManager(kubecli, factory.dataStore, tokenCache, false)
+	if err != nil {
+		return nil, err
+	}
+
+	transport, err := kubernetes.NewKubeconfigTransport(config, tokenManager)
+	if err != nil {
+		return nil, err
+	}
+
+	proxy := newSingleHostReverseProxyWithHostHeader(remoteURL)
+	proxy.Transport = transport
+
+	return proxy, nil
+}
+
 func (factory *ProxyFactory) newKubernetesEdgeHTTPProxy(endpoint *portainer.Endpoint) (http.Handler, error) {
 	tunnel := factory.reverseTunnelService.GetTunnelDetails(endpoint.ID)
 	endpoint.URL = fmt.Sprintf("http://localhost:%d", tunnel.Port)
@@ -9,6 +9,7 @@ import (
 
 	portainer "github.com/portainer/portainer/api"
 	"github.com/portainer/portainer/api/crypto"
+	"k8s.io/client-go/rest"
 )
 
 type (
@@ -23,6 +24,12 @@ type (
 		signatureService portainer.DigitalSignatureService
 	}
 
+	kubeconfigTransport struct {
+		adminTransport http.RoundTripper
+		userTransport  *http.Transport
+		tokenManager   *tokenManager
+	}
+
 	edgeTransport struct {
 		httpTransport        *http.Transport
 		tokenManager         *tokenManager
@@ -154,3 +161,53 @@ func (transport *edgeTransport) RoundTrip(request *http.Request) (*http.Response
 
 	return response, err
 }
+
+// NewKubeconfigTransport returns a new transport that can be used to send requests to a Kubernetes API
+// using the credentials of a kubeconfig. Requests of administrators are authenticated with the kubeconfig
+// credentials, requests of other users are authenticated with the token of their service account.
+func NewKubeconfigTransport(config *rest.Config, tokenManager *tokenManager) (*kubeconfigTransport, error) {
+	adminTransport, err := rest.TransportFor(config)
+	if err != nil {
+		return nil, err
+	}
+
+	// the user transport must not present the kubeconfig client certificate, the API server
+	// would otherwise authenticate the requests as the kubeconfig user
+	anonymousConfig := rest.AnonymousClientConfig(config)
+	tlsConfig, err := rest.TLSConfigFor(anonymousConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	transport := &kubeconfigTransport{
+		adminTransport: adminTransport,
+		userTransport: &http.Transport{
+			TLSClientConfig: tlsConfig,
+		},
+		tokenManager: tokenManager,
+	}
+
+	return transport, nil
+}
+
+// RoundTrip is the implementation of the the http.RoundTripper interface
+func (transport *kubeconfigTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	tokenData, err := security.RetrieveTokenData(request)
+	if err != nil {
+		return nil, err
+	}
+
+	if tokenData.Role == portainer.AdministratorRole {
+		request.Header.Del("Authorization")
+		return transport.adminTransport.RoundTrip(request)
+	}
+
+	token, err := transport.tokenManager.getUserServiceAccountToken(int(tokenData.ID))
+	if err != nil {
+		return nil, err
+	}
+
+	request.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+
+	return transport.userTransport.RoundTrip(request)
+}
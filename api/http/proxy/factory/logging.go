@@ -0,0 +1,137 @@
+package factory
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+const redactedValue = "<redacted>"
+
+// redactedHeaders are the request headers containing credentials, their value is never logged
+var redactedHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+	"X-Registry-Auth":     true,
+	"X-Registry-Config":   true,
+	http.CanonicalHeaderKey(portainer.PortainerAgentSignatureHeader):         true,
+	http.CanonicalHeaderKey(portainer.PortainerAgentPublicKeyHeader):         true,
+	http.CanonicalHeaderKey(portainer.PortainerAgentKubernetesSATokenHeader): true,
+}
+
+type (
+	// loggingProxy logs the requests proxied to an endpoint when the proxy request logging is enabled in the settings
+	loggingProxy struct {
+		handler    http.Handler
+		endpointID portainer.EndpointID
+		dataStore  portainer.DataStore
+	}
+
+	statusRecorder struct {
+		http.ResponseWriter
+		statusCode int
+	}
+)
+
+func newLoggingProxy(handler http.Handler, endpoint *portainer.Endpoint, dataStore portainer.DataStore) http.Handler {
+	return &loggingProxy{
+		handler:    handler,
+		endpointID: endpoint.ID,
+		dataStore:  dataStore,
+	}
+}
+
+// ServeHTTP is the http.Handler interface implementation
+func (proxy *loggingProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	settings, err := proxy.dataStore.Settings().Settings()
+	if err != nil || !settings.EnableProxyRequestLogging {
+		proxy.handler.ServeHTTP(w, r)
+		return
+	}
+
+	// the request is modified by the proxy, the logged values are retrieved beforehand
+	method := r.Method
+	path := redactURL(r.URL)
+	headers := redactHeaders(r.Header)
+	username := ""
+	if tokenData, err := security.RetrieveTokenData(r); err == nil {
+		username = tokenData.Username
+	}
+
+	recorder := &statusRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+	start := time.Now()
+
+	proxy.handler.ServeHTTP(recorder, r)
+
+	log.Printf("[DEBUG] [http,proxy] [endpoint_id: %d] [user: %s] [method: %s] [path: %s] [status: %d] [duration: %s] [headers: %s]",
+		proxy.endpointID, username, method, path, recorder.statusCode, time.Since(start), headers)
+}
+
+// redactURL returns the path and the query of the URL, the values of the query parameters holding credentials are redacted
+func redactURL(u *url.URL) string {
+	if u.RawQuery == "" {
+		return u.Path
+	}
+
+	query := u.Query()
+	for key := range query {
+		lowerKey := strings.ToLower(key)
+		if strings.Contains(lowerKey, "token") || strings.Contains(lowerKey, "auth") || strings.Contains(lowerKey, "password") {
+			query.Set(key, redactedValue)
+		}
+	}
+
+	return u.Path + "?" + query.Encode()
+}
+
+func redactHeaders(header http.Header) string {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	values := make([]string, 0, len(names))
+	for _, name := range names {
+		value := strings.Join(header[name], ",")
+		if redactedHeaders[http.CanonicalHeaderKey(name)] {
+			value = redactedValue
+		}
+		values = append(values, fmt.Sprintf("%s=%s", name, value))
+	}
+
+	return strings.Join(values, " ")
+}
+
+func (w *statusRecorder) WriteHeader(statusCode int) {
+	w.statusCode = statusCode
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Flush implements http.Flusher, it is used when streaming responses
+func (w *statusRecorder) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack implements http.Hijacker, it is used when upgrading connections
+func (w *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("Response writer does not support hijacking")
+	}
+	w.statusCode = http.StatusSwitchingProtocols
+	return hijacker.Hijack()
+}
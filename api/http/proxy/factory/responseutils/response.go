@@ -44,27 +44,37 @@ func GetResponseAsJSONArray(response *http.Response) ([]interface{}, error) {
 }
 
 func getResponseBodyAsGenericJSON(response *http.Response) (interface{}, error) {
-	if response.Body == nil {
-		return nil, errors.New("unable to parse response: empty response body")
+	body, err := GetResponseBody(response)
+	if err != nil {
+		return nil, err
 	}
 
 	var data interface{}
-	body, err := ioutil.ReadAll(response.Body)
+	err = json.Unmarshal(body, &data)
 	if err != nil {
 		return nil, err
 	}
 
-	err = response.Body.Close()
+	return data, nil
+}
+
+// GetResponseBody reads and closes the response body
+func GetResponseBody(response *http.Response) ([]byte, error) {
+	if response.Body == nil {
+		return nil, errors.New("unable to parse response: empty response body")
+	}
+
+	body, err := ioutil.ReadAll(response.Body)
 	if err != nil {
 		return nil, err
 	}
 
-	err = json.Unmarshal(body, &data)
+	err = response.Body.Close()
 	if err != nil {
 		return nil, err
 	}
 
-	return data, nil
+	return body, nil
 }
 
 type dockerErrorResponse struct {
@@ -78,6 +88,32 @@ func WriteAccessDeniedResponse() (*http.Response, error) {
 	return response, err
 }
 
+// WriteBadRequestResponse will create a new bad request response with the specified message
+func WriteBadRequestResponse(message string) (*http.Response, error) {
+	response := &http.Response{}
+	err := RewriteResponse(response, dockerErrorResponse{Message: message}, http.StatusBadRequest)
+	return response, err
+}
+
+// WriteNotImplementedResponse will create a new not implemented response with the specified message
+func WriteNotImplementedResponse(message string) (*http.Response, error) {
+	response := &http.Response{}
+	err := RewriteResponse(response, dockerErrorResponse{Message: message}, http.StatusNotImplemented)
+	return response, err
+}
+
+// RewriteNotFoundResponse will overwrite the existing response with a not found response
+func RewriteNotFoundResponse(response *http.Response, message string) error {
+	return RewriteResponse(response, dockerErrorResponse{Message: message}, http.StatusNotFound)
+}
+
+// WriteNotFoundResponse will create a new not found response with the specified message
+func WriteNotFoundResponse(message string) (*http.Response, error) {
+	response := &http.Response{}
+	err := RewriteNotFoundResponse(response, message)
+	return response, err
+}
+
 // RewriteAccessDeniedResponse will overwrite the existing response with an access denied response
 func RewriteAccessDeniedResponse(response *http.Response) error {
 	return RewriteResponse(response, dockerErrorResponse{Message: "access denied to resource"}, http.StatusForbidden)
@@ -91,6 +127,13 @@ func RewriteResponse(response *http.Response, newResponseData interface{}, statu
 		return err
 	}
 
+	RewriteResponseBody(response, jsonData, statusCode)
+	return nil
+}
+
+// RewriteResponseBody will replace the existing response body and status code with the one specified
+// in parameters
+func RewriteResponseBody(response *http.Response, jsonData []byte, statusCode int) {
 	body := ioutil.NopCloser(bytes.NewReader(jsonData))
 
 	response.StatusCode = statusCode
@@ -101,6 +144,4 @@ func RewriteResponse(response *http.Response, newResponseData interface{}, statu
 		response.Header = make(http.Header)
 	}
 	response.Header.Set("Content-Length", strconv.Itoa(len(jsonData)))
-
-	return nil
 }
@@ -0,0 +1,142 @@
+package responseutils
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"sort"
+)
+
+// JSONElementRewriter returns the rewritten version of a JSON array element, or nil to remove it.
+type JSONElementRewriter func(element json.RawMessage) (json.RawMessage, error)
+
+// RewriteResponseArray rewrites the JSON array contained in the response body element by element.
+// See RewriteJSONArray.
+func RewriteResponseArray(response *http.Response, rewrite JSONElementRewriter) error {
+	body, err := GetResponseBody(response)
+	if err != nil {
+		return err
+	}
+
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 || trimmed[0] != '[' {
+		var responseObject map[string]interface{}
+		if json.Unmarshal(trimmed, &responseObject) == nil && responseObject["message"] != nil {
+			if message, ok := responseObject["message"].(string); ok {
+				return errors.New(message)
+			}
+		}
+		log.Printf("[ERROR] [http,proxy,response] [message: invalid response format, expecting JSON array] [response: %s]", trimmed)
+		return errors.New("unable to parse response: expected JSON array")
+	}
+
+	data, err := RewriteJSONArray(trimmed, rewrite)
+	if err != nil {
+		return err
+	}
+
+	RewriteResponseBody(response, data, response.StatusCode)
+	return nil
+}
+
+// RewriteJSONArray decodes a JSON array one element at a time and returns a new array made of the
+// rewritten elements. The elements are handled as raw JSON: the rewrite function only unmarshals the
+// properties it needs and unchanged elements are copied verbatim, which keeps the processing of large
+// arrays cheap compared to a generic unmarshal/marshal of the whole document.
+func RewriteJSONArray(data []byte, rewrite JSONElementRewriter) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	if delimiter, ok := token.(json.Delim); !ok || delimiter != '[' {
+		return nil, errors.New("unable to parse response: expected JSON array")
+	}
+
+	output := bytes.NewBuffer(make([]byte, 0, len(data)))
+	output.WriteByte('[')
+
+	count := 0
+	for decoder.More() {
+		var element json.RawMessage
+		err := decoder.Decode(&element)
+		if err != nil {
+			return nil, err
+		}
+
+		rewritten, err := rewrite(element)
+		if err != nil {
+			return nil, err
+		}
+
+		if rewritten == nil {
+			continue
+		}
+
+		if count > 0 {
+			output.WriteByte(',')
+		}
+		output.Write(rewritten)
+		count++
+	}
+
+	_, err = decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	output.WriteByte(']')
+	return output.Bytes(), nil
+}
+
+// AppendJSONObjectProperties adds properties to a raw JSON object without unmarshaling it.
+// The properties are appended in key order and must not already exist in the object.
+func AppendJSONObjectProperties(object json.RawMessage, properties map[string]interface{}) (json.RawMessage, error) {
+	trimmed := bytes.TrimSpace(object)
+	if len(trimmed) < 2 || trimmed[0] != '{' || trimmed[len(trimmed)-1] != '}' {
+		return nil, errors.New("unable to decorate element: expected JSON object")
+	}
+
+	if len(properties) == 0 {
+		return trimmed, nil
+	}
+
+	keys := make([]string, 0, len(properties))
+	for key := range properties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	body := bytes.TrimSpace(trimmed[1 : len(trimmed)-1])
+
+	output := bytes.NewBuffer(make([]byte, 0, len(trimmed)+128))
+	output.WriteByte('{')
+	output.Write(body)
+
+	for idx, key := range keys {
+		if idx > 0 || len(body) > 0 {
+			output.WriteByte(',')
+		}
+
+		encodedKey, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+
+		encodedValue, err := json.Marshal(properties[key])
+		if err != nil {
+			return nil, err
+		}
+
+		output.Write(encodedKey)
+		output.WriteByte(':')
+		output.Write(encodedValue)
+	}
+
+	output.WriteByte('}')
+	return output.Bytes(), nil
+}
@@ -0,0 +1,124 @@
+package security
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/portainer/api"
+)
+
+// apiRateLimiterIdleTimeout is the duration after which the bucket of an inactive client is released
+const apiRateLimiterIdleTimeout = 10 * time.Minute
+
+var (
+	errTooManyRequests = errors.New("Too many requests, retry later")
+
+	// edgeRoutePattern matches the routes polled by the Edge agents, which are not rate limited
+	edgeRoutePattern = regexp.MustCompile(`^/api/endpoints/\d+/(status|edge/)`)
+)
+
+type (
+	// APIRateLimiter represents an entity that limits the rate of the API requests of each client using
+	// token buckets. The clients are identified by their user when authenticated and by their IP address otherwise.
+	APIRateLimiter struct {
+		mu         sync.Mutex
+		rate       float64
+		burst      float64
+		buckets    map[string]*tokenBucket
+		lastSweep  time.Time
+		jwtService portainer.JWTService
+	}
+
+	tokenBucket struct {
+		tokens     float64
+		lastUpdate time.Time
+	}
+)
+
+// NewAPIRateLimiter initializes a new APIRateLimiter allowing rate requests per second to each client,
+// with bursts up to burst requests.
+func NewAPIRateLimiter(rate float64, burst int, jwtService portainer.JWTService) *APIRateLimiter {
+	return &APIRateLimiter{
+		rate:       rate,
+		burst:      float64(burst),
+		buckets:    make(map[string]*tokenBucket),
+		lastSweep:  time.Now(),
+		jwtService: jwtService,
+	}
+}
+
+// Limit rejects the requests of the clients exceeding their rate with a 429 response including
+// a Retry-After header. The requests of the Edge agents and the requests outside of the API are not limited.
+func (limiter *APIRateLimiter) Limit(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if limiter.rate <= 0 || !strings.HasPrefix(r.URL.Path, "/api/") || edgeRoutePattern.MatchString(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		allowed, retryAfter := limiter.take(limiter.clientKey(r), time.Now())
+		if !allowed {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			httperror.WriteError(w, http.StatusTooManyRequests, "Too many requests", errTooManyRequests)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (limiter *APIRateLimiter) clientKey(r *http.Request) string {
+	token := extractToken(r)
+	if token != "" {
+		tokenData, err := limiter.jwtService.ParseAndVerifyToken(token)
+		if err == nil {
+			return "user:" + strconv.Itoa(int(tokenData.ID))
+		}
+	}
+
+	return "ip:" + StripAddrPort(r.RemoteAddr)
+}
+
+// take consumes a token from the bucket of the client, it returns false and the duration after
+// which a token will be available when the bucket is empty.
+func (limiter *APIRateLimiter) take(key string, now time.Time) (bool, time.Duration) {
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+
+	if now.Sub(limiter.lastSweep) > apiRateLimiterIdleTimeout {
+		limiter.sweep(now)
+	}
+
+	bucket, ok := limiter.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: limiter.burst, lastUpdate: now}
+		limiter.buckets[key] = bucket
+	}
+
+	bucket.tokens = math.Min(limiter.burst, bucket.tokens+now.Sub(bucket.lastUpdate).Seconds()*limiter.rate)
+	bucket.lastUpdate = now
+
+	if bucket.tokens < 1 {
+		return false, time.Duration((1 - bucket.tokens) / limiter.rate * float64(time.Second))
+	}
+
+	bucket.tokens--
+	return true, 0
+}
+
+// sweep releases the buckets of the clients inactive for longer than the idle timeout
+func (limiter *APIRateLimiter) sweep(now time.Time) {
+	for key, bucket := range limiter.buckets {
+		if now.Sub(bucket.lastUpdate) > apiRateLimiterIdleTimeout {
+			delete(limiter.buckets, key)
+		}
+	}
+	limiter.lastSweep = now
+}
@@ -0,0 +1,71 @@
+package security
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestAPIRateLimiterLimit(t *testing.T) {
+	testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	t.Run("Requests above the burst", func(t *testing.T) {
+		handler := NewAPIRateLimiter(1, 2, nil).Limit(testHandler)
+
+		codes := make([]int, 0)
+		for i := 0; i < 3; i++ {
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, httptest.NewRequest("GET", "/api/endpoints", nil))
+			codes = append(codes, rr.Code)
+
+			if i == 2 && rr.Header().Get("Retry-After") != "1" {
+				t.Errorf("Expected Retry-After header to be '1', but it was '%s' instead", rr.Header().Get("Retry-After"))
+			}
+		}
+
+		if codes[0] != http.StatusOK || codes[1] != http.StatusOK || codes[2] != http.StatusTooManyRequests {
+			t.Errorf("handler returned wrong status codes: got %v want [200 200 429]", codes)
+		}
+	})
+
+	t.Run("Edge agent requests are not limited", func(t *testing.T) {
+		handler := NewAPIRateLimiter(1, 1, nil).Limit(testHandler)
+
+		for i := 0; i < 3; i++ {
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, httptest.NewRequest("GET", "/api/endpoints/1/status", nil))
+
+			if rr.Code != http.StatusOK {
+				t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusOK)
+			}
+		}
+	})
+}
+
+func TestAPIRateLimiterRefill(t *testing.T) {
+	limiter := NewAPIRateLimiter(2, 1, nil)
+	now := time.Now()
+
+	allowed, _ := limiter.take("ip:127.0.0.1", now)
+	if !allowed {
+		t.Fatal("Expected the first request to be allowed")
+	}
+
+	allowed, retryAfter := limiter.take("ip:127.0.0.1", now)
+	if allowed || retryAfter != 500*time.Millisecond {
+		t.Errorf("Expected the second request to be rejected with a retry after 500ms, got %v and %s", allowed, retryAfter)
+	}
+
+	allowed, _ = limiter.take("ip:127.0.0.1", now.Add(500*time.Millisecond))
+	if !allowed {
+		t.Error("Expected the request to be allowed once the bucket is refilled")
+	}
+
+	allowed, _ = limiter.take("ip:127.0.0.2", now)
+	if !allowed {
+		t.Error("Expected the requests of another client to be allowed")
+	}
+}
@@ -91,6 +91,22 @@ func AuthorizedTeamManagement(teamID portainer.TeamID, context *RestrictedReques
 	return false
 }
 
+// IsInstanceAdmin returns true when the user is an administrator of the instance, an administrator
+// without organization managing all the organizations.
+func IsInstanceAdmin(context *RestrictedRequestContext) bool {
+	return context.IsAdmin && context.OrganizationID == 0
+}
+
+// AuthorizedOrganizationAccess ensure that a user can access a resource of an organization.
+// The administrators of the instance can access the resources of all the organizations, the other users
+// can only access the resources of their organization, or the resources without organization when they have none.
+func AuthorizedOrganizationAccess(resourceOrganizationID, userOrganizationID portainer.OrganizationID, isAdmin bool) bool {
+	if isAdmin && userOrganizationID == 0 {
+		return true
+	}
+	return resourceOrganizationID == userOrganizationID
+}
+
 // authorizedEndpointAccess ensure that the user can access the specified endpoint.
 // It will check if the user is part of the authorized users or part of a team that is
 // listed in the authorized teams of the endpoint and the associated group.
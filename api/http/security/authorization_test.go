@@ -0,0 +1,32 @@
+package security
+
+import (
+	"testing"
+
+	"github.com/portainer/portainer/api"
+)
+
+func TestAuthorizedOrganizationAccess(t *testing.T) {
+	tests := []struct {
+		name                   string
+		resourceOrganizationID portainer.OrganizationID
+		userOrganizationID     portainer.OrganizationID
+		isAdmin                bool
+		expected               bool
+	}{
+		{"instance administrator, resource of an organization", 1, 0, true, true},
+		{"organization administrator, resource of the organization", 1, 1, true, true},
+		{"organization administrator, resource of another organization", 2, 1, true, false},
+		{"organization administrator, resource without organization", 0, 1, true, false},
+		{"user without organization, resource of an organization", 1, 0, false, false},
+		{"user without organization, resource without organization", 0, 0, false, true},
+		{"user of an organization, resource of the organization", 1, 1, false, true},
+	}
+
+	for _, test := range tests {
+		authorized := AuthorizedOrganizationAccess(test.resourceOrganizationID, test.userOrganizationID, test.isAdmin)
+		if authorized != test.expected {
+			t.Errorf("%s: got %v want %v", test.name, authorized, test.expected)
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/gorilla/mux"
 	httperror "github.com/portainer/libhttp/error"
 	"github.com/portainer/portainer/api"
 	bolterrors "github.com/portainer/portainer/api/bolt/errors"
@@ -14,8 +15,10 @@ import (
 type (
 	// RequestBouncer represents an entity that manages API request accesses
 	RequestBouncer struct {
-		dataStore  portainer.DataStore
-		jwtService portainer.JWTService
+		dataStore        portainer.DataStore
+		jwtService       portainer.JWTService
+		telemetryService portainer.TelemetryService
+		activityLogger   portainer.UserActivityLogger
 	}
 
 	// RestrictedRequestContext is a data structure containing information
@@ -25,20 +28,26 @@ type (
 		IsTeamLeader    bool
 		UserID          portainer.UserID
 		UserMemberships []portainer.TeamMembership
+		// OrganizationID is the organization of the user, the administrators without
+		// organization are the administrators of the instance
+		OrganizationID portainer.OrganizationID
 	}
 )
 
 // NewRequestBouncer initializes a new RequestBouncer
-func NewRequestBouncer(dataStore portainer.DataStore, jwtService portainer.JWTService) *RequestBouncer {
+func NewRequestBouncer(dataStore portainer.DataStore, jwtService portainer.JWTService, telemetryService portainer.TelemetryService, activityLogger portainer.UserActivityLogger) *RequestBouncer {
 	return &RequestBouncer{
-		dataStore:  dataStore,
-		jwtService: jwtService,
+		dataStore:        dataStore,
+		jwtService:       jwtService,
+		telemetryService: telemetryService,
+		activityLogger:   activityLogger,
 	}
 }
 
 // PublicAccess defines a security check for public API endpoints.
 // No authentication is required to access these endpoints.
 func (bouncer *RequestBouncer) PublicAccess(h http.Handler) http.Handler {
+	h = bouncer.mwRecordUsage(h)
 	h = mwSecureHeaders(h)
 	return h
 }
@@ -50,18 +59,28 @@ func (bouncer *RequestBouncer) PublicAccess(h http.Handler) http.Handler {
 // that might be used later to inside the API operation for extra authorization validation
 // and resource filtering.
 func (bouncer *RequestBouncer) AdminAccess(h http.Handler) http.Handler {
+	h = bouncer.mwRecordUsage(h)
 	h = bouncer.mwUpgradeToRestrictedRequest(h)
 	h = bouncer.mwCheckPortainerAuthorizations(h, true)
 	h = bouncer.mwAuthenticatedUser(h)
 	return h
 }
 
+// InstanceAdminAccess defines a security check for the API endpoints managing the whole instance.
+// Authentication is required to access these endpoints.
+// The administrator role is required and the administrator must not belong to an organization.
+func (bouncer *RequestBouncer) InstanceAdminAccess(h http.Handler) http.Handler {
+	h = mwInstanceAdministrator(h)
+	return bouncer.AdminAccess(h)
+}
+
 // RestrictedAccess defines a security check for restricted API endpoints.
 // Authentication is required to access these endpoints.
 // The request context will be enhanced with a RestrictedRequestContext object
 // that might be used later to inside the API operation for extra authorization validation
 // and resource filtering.
 func (bouncer *RequestBouncer) RestrictedAccess(h http.Handler) http.Handler {
+	h = bouncer.mwRecordUsage(h)
 	h = bouncer.mwUpgradeToRestrictedRequest(h)
 	h = bouncer.mwCheckPortainerAuthorizations(h, false)
 	h = bouncer.mwAuthenticatedUser(h)
@@ -74,6 +93,7 @@ func (bouncer *RequestBouncer) RestrictedAccess(h http.Handler) http.Handler {
 // that might be used later to inside the API operation for extra authorization validation
 // and resource filtering.
 func (bouncer *RequestBouncer) AuthenticatedAccess(h http.Handler) http.Handler {
+	h = bouncer.mwRecordUsage(h)
 	h = bouncer.mwUpgradeToRestrictedRequest(h)
 	h = bouncer.mwAuthenticatedUser(h)
 	return h
@@ -89,6 +109,14 @@ func (bouncer *RequestBouncer) AuthorizedEndpointOperation(r *http.Request, endp
 		return err
 	}
 
+	authorized, err := bouncer.authorizedOrganizationOperation(tokenData, endpoint.OrganizationID)
+	if err != nil {
+		return err
+	}
+	if !authorized {
+		return httperrors.ErrEndpointAccessDenied
+	}
+
 	if tokenData.Role == portainer.AdministratorRole {
 		return nil
 	}
@@ -125,6 +153,12 @@ func (bouncer *RequestBouncer) AuthorizedEdgeEndpointOperation(r *http.Request,
 		return errors.New("invalid Edge identifier")
 	}
 
+	for _, rejectedIdentifier := range endpoint.EdgeRejectedIDs {
+		if rejectedIdentifier == edgeIdentifier {
+			return errors.New("rejected Edge identifier")
+		}
+	}
+
 	return nil
 }
 
@@ -137,6 +171,14 @@ func (bouncer *RequestBouncer) RegistryAccess(r *http.Request, registry *portain
 		return err
 	}
 
+	authorized, err := bouncer.authorizedOrganizationOperation(tokenData, registry.OrganizationID)
+	if err != nil {
+		return err
+	}
+	if !authorized {
+		return httperrors.ErrEndpointAccessDenied
+	}
+
 	if tokenData.Role == portainer.AdministratorRole {
 		return nil
 	}
@@ -153,6 +195,16 @@ func (bouncer *RequestBouncer) RegistryAccess(r *http.Request, registry *portain
 	return nil
 }
 
+// authorizedOrganizationOperation verifies that the user can access the resources of the specified organization
+func (bouncer *RequestBouncer) authorizedOrganizationOperation(tokenData *portainer.TokenData, organizationID portainer.OrganizationID) (bool, error) {
+	user, err := bouncer.dataStore.User().User(tokenData.ID)
+	if err != nil {
+		return false, err
+	}
+
+	return AuthorizedOrganizationAccess(organizationID, user.OrganizationID, tokenData.Role == portainer.AdministratorRole), nil
+}
+
 func (bouncer *RequestBouncer) mwAuthenticatedUser(h http.Handler) http.Handler {
 	h = bouncer.mwCheckAuthentication(h)
 	h = mwSecureHeaders(h)
@@ -194,6 +246,25 @@ func (bouncer *RequestBouncer) mwCheckPortainerAuthorizations(next http.Handler,
 	})
 }
 
+// mwInstanceAdministrator will prevent the administrators of an organization from accessing the endpoint.
+// It must be used after mwUpgradeToRestrictedRequest.
+func mwInstanceAdministrator(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestContext, err := RetrieveRestrictedRequestContext(r)
+		if err != nil {
+			httperror.WriteError(w, http.StatusForbidden, "Access denied", httperrors.ErrResourceAccessDenied)
+			return
+		}
+
+		if !IsInstanceAdmin(requestContext) {
+			httperror.WriteError(w, http.StatusForbidden, "Access denied", httperrors.ErrUnauthorized)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
 // mwUpgradeToRestrictedRequest will enhance the current request with
 // a new RestrictedRequestContext object.
 func (bouncer *RequestBouncer) mwUpgradeToRestrictedRequest(next http.Handler) http.Handler {
@@ -219,19 +290,8 @@ func (bouncer *RequestBouncer) mwUpgradeToRestrictedRequest(next http.Handler) h
 func (bouncer *RequestBouncer) mwCheckAuthentication(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		var tokenData *portainer.TokenData
-		var token string
-
-		// Optionally, token might be set via the "token" query parameter.
-		// For example, in websocket requests
-		token = r.URL.Query().Get("token")
-
-		// Get token from the Authorization header
-		tokens, ok := r.Header["Authorization"]
-		if ok && len(tokens) >= 1 {
-			token = tokens[0]
-			token = strings.TrimPrefix(token, "Bearer ")
-		}
 
+		token := extractToken(r)
 		if token == "" {
 			httperror.WriteError(w, http.StatusUnauthorized, "Unauthorized", httperrors.ErrUnauthorized)
 			return
@@ -244,7 +304,7 @@ func (bouncer *RequestBouncer) mwCheckAuthentication(next http.Handler) http.Han
 			return
 		}
 
-		_, err = bouncer.dataStore.User().User(tokenData.ID)
+		user, err := bouncer.dataStore.User().User(tokenData.ID)
 		if err != nil && err == bolterrors.ErrObjectNotFound {
 			httperror.WriteError(w, http.StatusUnauthorized, "Unauthorized", httperrors.ErrUnauthorized)
 			return
@@ -253,9 +313,71 @@ func (bouncer *RequestBouncer) mwCheckAuthentication(next http.Handler) http.Han
 			return
 		}
 
+		if user.PasswordChangeRequired && !passwordChangeAllowed(r) {
+			httperror.WriteError(w, http.StatusForbidden, "Password change required", ErrPasswordChangeRequired)
+			return
+		}
+
+		setUserLocale(r, user)
+
 		ctx := storeTokenData(r, tokenData)
-		next.ServeHTTP(w, r.WithContext(ctx))
-		return
+		bouncer.serveRecordedRequest(next, w, r.WithContext(ctx), tokenData)
+	})
+}
+
+// extractToken returns the JWT token of the request, retrieved from the Authorization header
+// or from the "token" query parameter
+func extractToken(r *http.Request) string {
+	// Optionally, token might be set via the "token" query parameter.
+	// For example, in websocket requests
+	token := r.URL.Query().Get("token")
+
+	// Get token from the Authorization header
+	tokens, ok := r.Header["Authorization"]
+	if ok && len(tokens) >= 1 {
+		token = tokens[0]
+		token = strings.TrimPrefix(token, "Bearer ")
+	}
+
+	return token
+}
+
+// passwordChangeAllowedOperations are the only operations available to the users that must change their password
+var passwordChangeAllowedOperations = map[string]bool{
+	http.MethodGet + " /users/{id}":        true,
+	http.MethodPut + " /users/{id}/passwd": true,
+	http.MethodPost + " /auth/logout":      true,
+}
+
+func passwordChangeAllowed(r *http.Request) bool {
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return false
+	}
+
+	template, err := route.GetPathTemplate()
+	if err != nil {
+		return false
+	}
+
+	return passwordChangeAllowedOperations[r.Method+" "+template]
+}
+
+// mwRecordUsage records the use of the API operation in the telemetry counters. The operation is identified
+// by the method and the route template of the request so that no identifier or name is recorded.
+func (bouncer *RequestBouncer) mwRecordUsage(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if bouncer.telemetryService != nil {
+			route := mux.CurrentRoute(r)
+			if route != nil {
+				template, err := route.GetPathTemplate()
+				if err == nil {
+					bouncer.telemetryService.Record(r.Method + " " + template)
+				}
+			}
+		}
+
+		next.ServeHTTP(w, r)
 	})
 }
 
@@ -274,6 +396,13 @@ func (bouncer *RequestBouncer) newRestrictedContextRequest(userID portainer.User
 		UserID:  userID,
 	}
 
+	user, err := bouncer.dataStore.User().User(userID)
+	if err != nil && err != bolterrors.ErrObjectNotFound {
+		return nil, err
+	} else if user != nil {
+		requestContext.OrganizationID = user.OrganizationID
+	}
+
 	if userRole != portainer.AdministratorRole {
 		requestContext.IsAdmin = false
 		memberships, err := bouncer.dataStore.TeamMembership().TeamMembershipsByUserID(userID)
@@ -0,0 +1,161 @@
+package security
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+)
+
+// ErrChangeRequestNotApproved is returned when a request executes a change request which is not approved
+// or which does not match the request
+var ErrChangeRequestNotApproved = errors.New("The change request is not approved for this operation")
+
+// ChangeRequestExecutionContext returns a context identifying the approved change request executed by a request.
+// It is only set on the requests replayed by the reviewer, the clients cannot execute a change request themselves.
+func ChangeRequestExecutionContext(ctx context.Context, changeRequestID portainer.ChangeRequestID) context.Context {
+	return context.WithValue(ctx, contextChangeRequest, changeRequestID)
+}
+
+// changeRequestLock ensures that an approved change request is only executed once
+var changeRequestLock sync.Mutex
+
+// ChangeEndpointResolver returns the endpoint changed by a request, the second value is false when the
+// request does not make a change requiring an approval
+type ChangeEndpointResolver func(r *http.Request) (portainer.EndpointID, bool)
+
+// ChangeApproval holds the requests changing a protected endpoint: a pending change request recording the
+// request is created and returned instead, the request is executed on behalf of its author once a second user
+// approves it. It must be used behind an authenticated access.
+func (bouncer *RequestBouncer) ChangeApproval(changeType portainer.ChangeRequestType, resolve ChangeEndpointResolver, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		endpointID, ok := resolve(r)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		endpoint, err := bouncer.dataStore.Endpoint().Endpoint(endpointID)
+		if err == bolterrors.ErrObjectNotFound {
+			next.ServeHTTP(w, r)
+			return
+		} else if err != nil {
+			httperror.WriteError(w, http.StatusInternalServerError, "Unable to find an endpoint with the specified identifier inside the database", err)
+			return
+		}
+
+		if !endpoint.Protected {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		err = bouncer.AuthorizedEndpointOperation(r, endpoint)
+		if err != nil {
+			httperror.WriteError(w, http.StatusForbidden, "Permission denied to access endpoint", err)
+			return
+		}
+
+		tokenData, err := RetrieveTokenData(r)
+		if err != nil {
+			httperror.WriteError(w, http.StatusInternalServerError, "Unable to retrieve user details from authentication token", err)
+			return
+		}
+
+		if changeRequestID, ok := r.Context().Value(contextChangeRequest).(portainer.ChangeRequestID); ok {
+			err = bouncer.startChangeRequestExecution(r, changeRequestID, tokenData, endpoint.ID)
+			if err != nil {
+				httperror.WriteError(w, http.StatusForbidden, "The change must be approved before it is executed", err)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		changeRequest := &portainer.ChangeRequest{
+			EndpointID:              endpoint.ID,
+			Type:                    changeType,
+			Status:                  portainer.ChangeRequestPending,
+			Method:                  r.Method,
+			RequestURI:              changeRequestURI(r),
+			ContentType:             r.Header.Get("Content-Type"),
+			RequestedBy:             tokenData.ID,
+			RequesterName:           tokenData.Username,
+			RequestedByImpersonator: tokenData.ImpersonatorID,
+			CreationDate:            time.Now().Unix(),
+		}
+
+		if r.Body != nil {
+			changeRequest.Body, err = ioutil.ReadAll(r.Body)
+			if err != nil {
+				httperror.WriteError(w, http.StatusBadRequest, "Unable to read the request body", err)
+				return
+			}
+		}
+
+		err = bouncer.dataStore.ChangeRequest().CreateChangeRequest(changeRequest)
+		if err != nil {
+			httperror.WriteError(w, http.StatusInternalServerError, "Unable to persist the change request inside the database", err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(changeRequest)
+	})
+}
+
+// startChangeRequestExecution verifies that the change request executed by a request is approved and matches
+// the request. The change request is marked as executed before the request is served so that it is only
+// executed once, the reviewer records the outcome of the execution.
+func (bouncer *RequestBouncer) startChangeRequestExecution(r *http.Request, changeRequestID portainer.ChangeRequestID, tokenData *portainer.TokenData, endpointID portainer.EndpointID) error {
+	changeRequestLock.Lock()
+	defer changeRequestLock.Unlock()
+
+	changeRequest, err := bouncer.dataStore.ChangeRequest().ChangeRequest(changeRequestID)
+	if err == bolterrors.ErrObjectNotFound {
+		return ErrChangeRequestNotApproved
+	} else if err != nil {
+		return err
+	}
+
+	if changeRequest.Status != portainer.ChangeRequestApproved ||
+		changeRequest.RequestedBy != tokenData.ID ||
+		changeRequest.EndpointID != endpointID ||
+		changeRequest.Method != r.Method ||
+		changeRequest.RequestURI != changeRequestURI(r) {
+		return ErrChangeRequestNotApproved
+	}
+
+	changeRequest.Status = portainer.ChangeRequestExecuted
+	return bouncer.dataStore.ChangeRequest().UpdateChangeRequest(changeRequest.ID, changeRequest)
+}
+
+// changeRequestURI returns the original URI of a request, without the authentication token
+func changeRequestURI(r *http.Request) string {
+	requestURI := r.RequestURI
+	if requestURI == "" {
+		requestURI = r.URL.RequestURI()
+	}
+
+	u, err := url.ParseRequestURI(requestURI)
+	if err != nil {
+		return requestURI
+	}
+
+	query := u.Query()
+	if _, ok := query["token"]; ok {
+		query.Del("token")
+		u.RawQuery = query.Encode()
+	}
+
+	return u.RequestURI()
+}
@@ -0,0 +1,35 @@
+package security
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestChangeRequestURI(t *testing.T) {
+	cases := []struct {
+		requestURI string
+		strippedTo string
+		expected   string
+	}{
+		{"/api/stacks?type=2&method=string&endpointId=1", "/stacks", "/api/stacks?type=2&method=string&endpointId=1"},
+		{"/api/stacks/3?endpointId=1&token=secret", "/stacks/3", "/api/stacks/3?endpointId=1"},
+		{"/api/endpoints/1/docker/containers/abc?force=true", "/1/docker/containers/abc", "/api/endpoints/1/docker/containers/abc?force=true"},
+	}
+
+	for _, c := range cases {
+		r := httptest.NewRequest(http.MethodPost, c.requestURI, nil)
+		r.URL.Path = c.strippedTo
+
+		uri := changeRequestURI(r)
+		if uri != c.expected {
+			t.Errorf("changeRequestURI(%q): got %q, want %q", c.requestURI, uri, c.expected)
+		}
+
+		// the URI recorded in a change request must match the URI of the executed request
+		replayed := httptest.NewRequest(http.MethodPost, uri, nil)
+		if changeRequestURI(replayed) != uri {
+			t.Errorf("changeRequestURI(%q): the recorded URI does not match the executed request", uri)
+		}
+	}
+}
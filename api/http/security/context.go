@@ -15,6 +15,8 @@ type (
 const (
 	contextAuthenticationKey contextKey = iota
 	contextRestrictedRequest
+	contextLocale
+	contextChangeRequest
 )
 
 // storeTokenData stores a TokenData object inside the request context and returns the enhanced context.
@@ -49,3 +51,14 @@ func RetrieveRestrictedRequestContext(request *http.Request) (*RestrictedRequest
 	requestContext := contextData.(*RestrictedRequestContext)
 	return requestContext, nil
 }
+
+// storeRequestLocale stores the locale of a request inside the request context and returns the enhanced request.
+func storeRequestLocale(request *http.Request, locale *requestLocale) *http.Request {
+	return request.WithContext(context.WithValue(request.Context(), contextLocale, locale))
+}
+
+// retrieveRequestLocale returns the locale stored in the request context or nil when none is found.
+func retrieveRequestLocale(request *http.Request) *requestLocale {
+	locale, _ := request.Context().Value(contextLocale).(*requestLocale)
+	return locale
+}
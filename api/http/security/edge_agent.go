@@ -0,0 +1,148 @@
+package security
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+)
+
+// gzipResponseWriter compresses the body of the responses, the responses without body are not compressed
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	writer      *gzip.Writer
+	wroteHeader bool
+	compress    bool
+}
+
+// etagResponseWriter holds the body of a successful response until the handler returns, so that
+// the response can be replaced by a 304 Not Modified response when the client already has it
+type etagResponseWriter struct {
+	http.ResponseWriter
+	statusCode int
+	buffer     bytes.Buffer
+}
+
+// EdgeAgentAccess defines a security check for the API endpoints used by the Edge agents.
+// No authentication is required to access these endpoints, the Edge identifier of the agent is
+// verified by the operations. The exchanged data are reduced for the agents connected through
+// metered networks: the request and response bodies can be compressed with gzip and the unchanged
+// responses are not sent again to the agents providing the ETag of their cached copy.
+func (bouncer *RequestBouncer) EdgeAgentAccess(h http.Handler) http.Handler {
+	h = mwConditionalResponse(h)
+	h = mwCompression(h)
+	return bouncer.PublicAccess(h)
+}
+
+// mwCompression decompresses the request bodies sent with the gzip content encoding and compresses
+// the responses when the gzip encoding is accepted by the client
+func mwCompression(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.EqualFold(r.Header.Get("Content-Encoding"), "gzip") {
+			reader, err := gzip.NewReader(r.Body)
+			if err != nil {
+				http.Error(w, "Invalid gzip request body", http.StatusBadRequest)
+				return
+			}
+			defer reader.Close()
+
+			r.Body = reader
+			r.Header.Del("Content-Encoding")
+			r.Header.Del("Content-Length")
+			r.ContentLength = -1
+		}
+
+		if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		writer := &gzipResponseWriter{ResponseWriter: w}
+		defer writer.close()
+
+		w.Header().Add("Vary", "Accept-Encoding")
+		next.ServeHTTP(writer, r)
+	})
+}
+
+func (writer *gzipResponseWriter) WriteHeader(statusCode int) {
+	if writer.wroteHeader {
+		return
+	}
+	writer.wroteHeader = true
+
+	if statusCode != http.StatusNoContent && statusCode != http.StatusNotModified {
+		writer.compress = true
+		writer.Header().Set("Content-Encoding", "gzip")
+		writer.Header().Del("Content-Length")
+	}
+
+	writer.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (writer *gzipResponseWriter) Write(data []byte) (int, error) {
+	if !writer.wroteHeader {
+		writer.WriteHeader(http.StatusOK)
+	}
+
+	if !writer.compress {
+		return writer.ResponseWriter.Write(data)
+	}
+
+	if writer.writer == nil {
+		writer.writer = gzip.NewWriter(writer.ResponseWriter)
+	}
+	return writer.writer.Write(data)
+}
+
+func (writer *gzipResponseWriter) close() {
+	if writer.writer != nil {
+		writer.writer.Close()
+	}
+}
+
+// mwConditionalResponse adds an ETag computed from the body to the successful responses to GET requests
+// and replies with 304 Not Modified when the ETag matches the If-None-Match header of the request
+func mwConditionalResponse(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		writer := &etagResponseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+		next.ServeHTTP(writer, r)
+
+		if writer.statusCode != http.StatusOK {
+			return
+		}
+
+		hash := sha256.Sum256(writer.buffer.Bytes())
+		etag := `"` + hex.EncodeToString(hash[:16]) + `"`
+		w.Header().Set("ETag", etag)
+
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		w.Write(writer.buffer.Bytes())
+	})
+}
+
+func (writer *etagResponseWriter) WriteHeader(statusCode int) {
+	writer.statusCode = statusCode
+	if statusCode != http.StatusOK {
+		writer.ResponseWriter.WriteHeader(statusCode)
+	}
+}
+
+func (writer *etagResponseWriter) Write(data []byte) (int, error) {
+	if writer.statusCode != http.StatusOK {
+		return writer.ResponseWriter.Write(data)
+	}
+	return writer.buffer.Write(data)
+}
@@ -0,0 +1,63 @@
+package security
+
+import (
+	"compress/gzip"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestEdgeAgentResponses(t *testing.T) {
+	testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status":"IDLE"}`))
+	})
+
+	t.Run("Compressed response", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		rr := httptest.NewRecorder()
+
+		mwCompression(mwConditionalResponse(testHandler)).ServeHTTP(rr, req)
+
+		if encoding := rr.Header().Get("Content-Encoding"); encoding != "gzip" {
+			t.Fatalf("handler returned wrong content encoding: got %q want %q", encoding, "gzip")
+		}
+
+		reader, err := gzip.NewReader(rr.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		body, err := ioutil.ReadAll(reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(body) != `{"status":"IDLE"}` {
+			t.Errorf("handler returned wrong body: got %s", body)
+		}
+	})
+
+	t.Run("Unchanged response", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		mwConditionalResponse(testHandler).ServeHTTP(rr, httptest.NewRequest("GET", "/", nil))
+
+		etag := rr.Header().Get("ETag")
+		if etag == "" {
+			t.Fatal("handler returned no ETag")
+		}
+
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("If-None-Match", etag)
+		rr = httptest.NewRecorder()
+		mwConditionalResponse(testHandler).ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusNotModified {
+			t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusNotModified)
+		}
+		if rr.Body.Len() != 0 {
+			t.Errorf("handler returned a body with the Not Modified response")
+		}
+	})
+}
@@ -3,5 +3,6 @@ package security
 import "errors"
 
 var (
-	ErrAuthorizationRequired = errors.New("Authorization required for this operation")
+	ErrAuthorizationRequired  = errors.New("Authorization required for this operation")
+	ErrPasswordChangeRequired = errors.New("Password change required. Change your password to use this operation")
 )
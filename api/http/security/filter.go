@@ -7,7 +7,7 @@ import (
 // FilterUserTeams filters teams based on user role.
 // non-administrator users only have access to team they are member of.
 func FilterUserTeams(teams []portainer.Team, context *RestrictedRequestContext) []portainer.Team {
-	filteredTeams := teams
+	filteredTeams := FilterOrganizationTeams(teams, context)
 
 	if !context.IsAdmin {
 		filteredTeams = make([]portainer.Team, 0)
@@ -49,11 +49,15 @@ func FilterLeaderTeams(teams []portainer.Team, context *RestrictedRequestContext
 func FilterUsers(users []portainer.User, context *RestrictedRequestContext) []portainer.User {
 	filteredUsers := users
 
-	if !context.IsAdmin {
+	if !IsInstanceAdmin(context) {
 		filteredUsers = make([]portainer.User, 0)
 
 		for _, user := range users {
-			if user.Role != portainer.AdministratorRole {
+			if !AuthorizedOrganizationAccess(user.OrganizationID, context.OrganizationID, context.IsAdmin) {
+				continue
+			}
+
+			if context.IsAdmin || user.Role != portainer.AdministratorRole {
 				filteredUsers = append(filteredUsers, user)
 			}
 		}
@@ -66,11 +70,15 @@ func FilterUsers(users []portainer.User, context *RestrictedRequestContext) []po
 // Non administrator users only have access to authorized registries.
 func FilterRegistries(registries []portainer.Registry, context *RestrictedRequestContext) []portainer.Registry {
 	filteredRegistries := registries
-	if !context.IsAdmin {
+	if !IsInstanceAdmin(context) {
 		filteredRegistries = make([]portainer.Registry, 0)
 
 		for _, registry := range registries {
-			if AuthorizedRegistryAccess(&registry, context.UserID, context.UserMemberships) {
+			if !AuthorizedOrganizationAccess(registry.OrganizationID, context.OrganizationID, context.IsAdmin) {
+				continue
+			}
+
+			if context.IsAdmin || AuthorizedRegistryAccess(&registry, context.UserID, context.UserMemberships) {
 				filteredRegistries = append(filteredRegistries, registry)
 			}
 		}
@@ -84,13 +92,17 @@ func FilterRegistries(registries []portainer.Registry, context *RestrictedReques
 func FilterEndpoints(endpoints []portainer.Endpoint, groups []portainer.EndpointGroup, context *RestrictedRequestContext) []portainer.Endpoint {
 	filteredEndpoints := endpoints
 
-	if !context.IsAdmin {
+	if !IsInstanceAdmin(context) {
 		filteredEndpoints = make([]portainer.Endpoint, 0)
 
 		for _, endpoint := range endpoints {
+			if !AuthorizedOrganizationAccess(endpoint.OrganizationID, context.OrganizationID, context.IsAdmin) {
+				continue
+			}
+
 			endpointGroup := getAssociatedGroup(&endpoint, groups)
 
-			if authorizedEndpointAccess(&endpoint, endpointGroup, context.UserID, context.UserMemberships) {
+			if context.IsAdmin || authorizedEndpointAccess(&endpoint, endpointGroup, context.UserID, context.UserMemberships) {
 				filteredEndpoints = append(filteredEndpoints, endpoint)
 			}
 		}
@@ -99,6 +111,23 @@ func FilterEndpoints(endpoints []portainer.Endpoint, groups []portainer.Endpoint
 	return filteredEndpoints
 }
 
+// FilterOrganizationTeams filters teams based on the organization of the user.
+// The administrators of the instance have access to the teams of all the organizations.
+func FilterOrganizationTeams(teams []portainer.Team, context *RestrictedRequestContext) []portainer.Team {
+	if IsInstanceAdmin(context) {
+		return teams
+	}
+
+	filteredTeams := make([]portainer.Team, 0)
+	for _, team := range teams {
+		if AuthorizedOrganizationAccess(team.OrganizationID, context.OrganizationID, context.IsAdmin) {
+			filteredTeams = append(filteredTeams, team)
+		}
+	}
+
+	return filteredTeams
+}
+
 // FilterEndpointGroups filters endpoint groups based on user role and team memberships.
 // Non administrator users only have access to authorized endpoint groups.
 func FilterEndpointGroups(endpointGroups []portainer.EndpointGroup, context *RestrictedRequestContext) []portainer.EndpointGroup {
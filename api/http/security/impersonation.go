@@ -0,0 +1,72 @@
+package security
+
+import (
+	"bufio"
+	"errors"
+	"log"
+	"net"
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+)
+
+// PortainerImpersonatorHeader is the header added to the responses to the requests made with an impersonation token
+const PortainerImpersonatorHeader = "X-Portainer-Impersonator"
+
+// ErrImpersonationRevoked is returned when the administrator of an impersonation token is not an administrator anymore
+var ErrImpersonationRevoked = errors.New("The impersonation is not allowed anymore")
+
+// auditResponseWriter records the status code of a response
+type auditResponseWriter struct {
+	http.ResponseWriter
+	statusCode int
+}
+
+// IsImpersonation returns true when the request is made by an administrator impersonating a user
+func IsImpersonation(tokenData *portainer.TokenData) bool {
+	return tokenData.ImpersonatorID != 0
+}
+
+// serveImpersonatedRequest verifies that the impersonator of the token is still an administrator and audits
+// the requests made with the token, with the identities of both the administrator and the impersonated user
+func (bouncer *RequestBouncer) serveImpersonatedRequest(next http.Handler, w http.ResponseWriter, r *http.Request, tokenData *portainer.TokenData) {
+	impersonator, err := bouncer.dataStore.User().User(tokenData.ImpersonatorID)
+	if err != nil && err != bolterrors.ErrObjectNotFound {
+		httperror.WriteError(w, http.StatusInternalServerError, "Unable to retrieve user details from the database", err)
+		return
+	}
+	if impersonator == nil || impersonator.Role != portainer.AdministratorRole {
+		httperror.WriteError(w, http.StatusUnauthorized, "The impersonation is not allowed anymore", ErrImpersonationRevoked)
+		return
+	}
+
+	w.Header().Set(PortainerImpersonatorHeader, tokenData.ImpersonatorUsername)
+	writer := &auditResponseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+	next.ServeHTTP(writer, r)
+
+	log.Printf("[INFO] [http,security,impersonation] [impersonator: %s] [impersonator_id: %d] [user: %s] [user_id: %d] [method: %s] [path: %s] [status: %d]",
+		tokenData.ImpersonatorUsername, tokenData.ImpersonatorID, tokenData.Username, tokenData.ID, r.Method, r.URL.Path, writer.statusCode)
+}
+
+func (w *auditResponseWriter) WriteHeader(statusCode int) {
+	w.statusCode = statusCode
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Flush implements http.Flusher, it is used when streaming responses
+func (w *auditResponseWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack implements http.Hijacker, it is used when upgrading websocket connections
+func (w *auditResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("Response writer does not support hijacking")
+	}
+	return hijacker.Hijack()
+}
@@ -0,0 +1,140 @@
+package security
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/internal/i18n"
+)
+
+// requestLocale holds the locales used to translate the messages sent in response to a request.
+// The user locale is only known once the request is authenticated.
+type requestLocale struct {
+	dataStore portainer.DataStore
+	requested string
+	user      string
+}
+
+// localizedResponseWriter holds the JSON error responses until the handler returns
+// so that their messages can be translated
+type localizedResponseWriter struct {
+	http.ResponseWriter
+	statusCode int
+	buffer     *bytes.Buffer
+}
+
+// Localize translates the messages of the JSON error responses in the locale of the user
+// or, when the user has no locale, in the locale negotiated from the Accept-Language header
+// or the default locale defined in the settings.
+func (bouncer *RequestBouncer) Localize(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		locale := &requestLocale{
+			dataStore: bouncer.dataStore,
+			requested: i18n.Negotiate(r.Header.Get("Accept-Language")),
+		}
+
+		writer := &localizedResponseWriter{ResponseWriter: w}
+		next.ServeHTTP(writer, storeRequestLocale(r, locale))
+
+		if writer.buffer != nil {
+			writer.writeTranslatedError(locale.resolve())
+		}
+	})
+}
+
+// RetrieveLocale returns the locale used to translate the messages sent in response to a request
+func RetrieveLocale(r *http.Request) string {
+	locale := retrieveRequestLocale(r)
+	if locale == nil {
+		return i18n.DefaultLocale
+	}
+	return locale.resolve()
+}
+
+// setUserLocale records the locale preferred by the authenticated user
+func setUserLocale(r *http.Request, user *portainer.User) {
+	locale := retrieveRequestLocale(r)
+	if locale != nil && i18n.IsSupported(user.Locale) {
+		locale.user = user.Locale
+	}
+}
+
+func (locale *requestLocale) resolve() string {
+	if locale.user != "" {
+		return locale.user
+	}
+
+	if locale.requested != "" {
+		return locale.requested
+	}
+
+	settings, err := locale.dataStore.Settings().Settings()
+	if err == nil && i18n.IsSupported(settings.DefaultLocale) {
+		return settings.DefaultLocale
+	}
+
+	return i18n.DefaultLocale
+}
+
+func (w *localizedResponseWriter) WriteHeader(statusCode int) {
+	if statusCode >= http.StatusBadRequest && strings.HasPrefix(w.Header().Get("Content-Type"), "application/json") {
+		w.statusCode = statusCode
+		w.buffer = &bytes.Buffer{}
+		return
+	}
+
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *localizedResponseWriter) Write(data []byte) (int, error) {
+	if w.buffer != nil {
+		return w.buffer.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+// Flush implements http.Flusher, it is used when streaming responses
+func (w *localizedResponseWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok && w.buffer == nil {
+		flusher.Flush()
+	}
+}
+
+// Hijack implements http.Hijacker, it is used when upgrading websocket connections
+func (w *localizedResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("Response writer does not support hijacking")
+	}
+	return hijacker.Hijack()
+}
+
+func (w *localizedResponseWriter) writeTranslatedError(locale string) {
+	body := w.buffer.Bytes()
+
+	var content map[string]interface{}
+	if locale != i18n.DefaultLocale && json.Unmarshal(body, &content) == nil {
+		for _, key := range []string{"message", "details"} {
+			if message, ok := content[key].(string); ok {
+				content[key] = i18n.Translate(locale, message)
+			}
+		}
+
+		translated, err := json.Marshal(content)
+		if err == nil {
+			body = translated
+			w.Header().Set("Content-Language", locale)
+		}
+	}
+
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.ResponseWriter.WriteHeader(w.statusCode)
+	w.ResponseWriter.Write(body)
+}
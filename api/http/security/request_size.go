@@ -0,0 +1,102 @@
+package security
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	httperror "github.com/portainer/libhttp/error"
+)
+
+// RequestSizeLimits represents the maximum sizes in bytes of the request bodies, a limit of 0 disables the limit
+type RequestSizeLimits struct {
+	// Default is applied to all the requests not covered by a more specific limit
+	Default int64
+	// StackFile is applied to the requests uploading stack, custom template and Edge job files
+	StackFile int64
+	// TLSFile is applied to the requests uploading TLS files
+	TLSFile int64
+}
+
+// stackFileRoutes are the API routes that accept the upload of stack files
+var stackFileRoutes = []string{
+	"/api/stacks",
+	"/api/edge_stacks",
+	"/api/custom_templates",
+	"/api/edge_jobs",
+}
+
+// proxiedRoutes are the parts of the endpoint routes that are proxied to the endpoints and are not limited,
+// the Docker and Kubernetes APIs accept large uploads such as images and build contexts
+var proxiedRoutes = []string{
+	"/docker/",
+	"/kubernetes/",
+	"/storidge/",
+	"/azure/",
+}
+
+// archiveRoutes are the parts of the endpoint routes that accept the upload of volume archives, which are not limited
+var archiveRoutes = []string{
+	"/volumes/",
+}
+
+// LimitRequestSize rejects the requests with a body larger than the limit of the route. The declared
+// content length is verified before reading the body, which is also limited while being read.
+func LimitRequestSize(next http.Handler, limits RequestSizeLimits) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limit := limits.routeLimit(r)
+		if limit <= 0 || r.Body == nil || r.Body == http.NoBody {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if r.ContentLength > limit {
+			err := fmt.Errorf("The request body exceeds the maximum size of %s for this operation", formatSize(limit))
+			httperror.WriteError(w, http.StatusRequestEntityTooLarge, "Request body too large", err)
+			return
+		}
+
+		r.Body = http.MaxBytesReader(w, r.Body, limit)
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (limits RequestSizeLimits) routeLimit(r *http.Request) int64 {
+	path := r.URL.Path
+
+	if strings.HasPrefix(path, "/api/endpoints/") {
+		for _, route := range proxiedRoutes {
+			if strings.Contains(path, route) {
+				return 0
+			}
+		}
+
+		for _, route := range archiveRoutes {
+			if strings.Contains(path, route) {
+				return 0
+			}
+		}
+	}
+
+	if strings.HasPrefix(path, "/api/upload/tls/") || (r.Method == http.MethodPost && (path == "/api/endpoints" || path == "/api/endpoints/import/docker_context")) {
+		return limits.TLSFile
+	}
+
+	for _, route := range stackFileRoutes {
+		if path == route || strings.HasPrefix(path, route+"/") {
+			return limits.StackFile
+		}
+	}
+
+	return limits.Default
+}
+
+func formatSize(size int64) string {
+	if size%(1<<20) == 0 {
+		return fmt.Sprintf("%d MB", size>>20)
+	}
+	if size%(1<<10) == 0 {
+		return fmt.Sprintf("%d KB", size>>10)
+	}
+	return fmt.Sprintf("%d bytes", size)
+}
@@ -0,0 +1,40 @@
+package security
+
+import (
+	"net/http"
+
+	"github.com/portainer/portainer/api"
+)
+
+// serveRecordedRequest serves an authenticated request and records the requests changing the state
+// of Portainer or of an endpoint in the activity log of the user
+func (bouncer *RequestBouncer) serveRecordedRequest(next http.Handler, w http.ResponseWriter, r *http.Request, tokenData *portainer.TokenData) {
+	serve := next.ServeHTTP
+	if IsImpersonation(tokenData) {
+		serve = func(w http.ResponseWriter, r *http.Request) {
+			bouncer.serveImpersonatedRequest(next, w, r, tokenData)
+		}
+	}
+
+	if !recordedMethod(r.Method) {
+		serve(w, r)
+		return
+	}
+
+	writer := &auditResponseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+	serve(writer, r)
+
+	bouncer.activityLogger.RecordRequest(&portainer.UserActivity{
+		UserID:       tokenData.ID,
+		SourceIP:     StripAddrPort(r.RemoteAddr),
+		Method:       r.Method,
+		Path:         r.URL.Path,
+		StatusCode:   writer.statusCode,
+		Impersonator: tokenData.ImpersonatorUsername,
+	})
+}
+
+// recordedMethod returns true for the methods of the requests changing a state
+func recordedMethod(method string) bool {
+	return method != http.MethodGet && method != http.MethodHead && method != http.MethodOptions
+}
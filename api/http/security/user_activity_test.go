@@ -0,0 +1,54 @@
+package security
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/portainer/portainer/api"
+)
+
+type testActivityLogger struct {
+	activities []portainer.UserActivity
+}
+
+func (logger *testActivityLogger) Start() {}
+
+func (logger *testActivityLogger) RecordLogin(user *portainer.User, sourceIP string, method portainer.AuthenticationMethod) error {
+	return nil
+}
+
+func (logger *testActivityLogger) RecordLoginFailure(user *portainer.User, sourceIP string, method portainer.AuthenticationMethod) {
+}
+
+func (logger *testActivityLogger) RecordRequest(activity *portainer.UserActivity) {
+	logger.activities = append(logger.activities, *activity)
+}
+
+func TestServeRecordedRequest(t *testing.T) {
+	logger := &testActivityLogger{}
+	bouncer := &RequestBouncer{activityLogger: logger}
+	tokenData := &portainer.TokenData{ID: 2, Username: "bob"}
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/stacks", nil)
+	bouncer.serveRecordedRequest(handler, httptest.NewRecorder(), req, tokenData)
+	if len(logger.activities) != 0 {
+		t.Fatalf("GET request was recorded: %+v", logger.activities)
+	}
+
+	req = httptest.NewRequest(http.MethodDelete, "/stacks/1", nil)
+	req.RemoteAddr = "10.0.0.1:51234"
+	bouncer.serveRecordedRequest(handler, httptest.NewRecorder(), req, tokenData)
+	if len(logger.activities) != 1 {
+		t.Fatalf("expected 1 recorded request, got %d", len(logger.activities))
+	}
+
+	activity := logger.activities[0]
+	if activity.UserID != 2 || activity.Method != http.MethodDelete || activity.Path != "/stacks/1" || activity.StatusCode != http.StatusNoContent || activity.SourceIP != "10.0.0.1" {
+		t.Errorf("unexpected recorded request: %+v", activity)
+	}
+}
@@ -6,11 +6,19 @@ import (
 	"time"
 
 	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/azure"
 	"github.com/portainer/portainer/api/crypto"
 	"github.com/portainer/portainer/api/docker"
 	"github.com/portainer/portainer/api/http/handler"
 	"github.com/portainer/portainer/api/http/handler/auth"
+	"github.com/portainer/portainer/api/http/handler/autohealrules"
+	azurehandler "github.com/portainer/portainer/api/http/handler/azure"
+	"github.com/portainer/portainer/api/http/handler/branding"
+	"github.com/portainer/portainer/api/http/handler/changerequests"
+	"github.com/portainer/portainer/api/http/handler/containers"
 	"github.com/portainer/portainer/api/http/handler/customtemplates"
+	"github.com/portainer/portainer/api/http/handler/dashboard"
+	"github.com/portainer/portainer/api/http/handler/debug"
 	"github.com/portainer/portainer/api/http/handler/dockerhub"
 	"github.com/portainer/portainer/api/http/handler/edgegroups"
 	"github.com/portainer/portainer/api/http/handler/edgejobs"
@@ -21,24 +29,48 @@ import (
 	"github.com/portainer/portainer/api/http/handler/endpointproxy"
 	"github.com/portainer/portainer/api/http/handler/endpoints"
 	"github.com/portainer/portainer/api/http/handler/file"
+	healthhandler "github.com/portainer/portainer/api/http/handler/health"
+	"github.com/portainer/portainer/api/http/handler/hostactions"
+	"github.com/portainer/portainer/api/http/handler/images"
+	"github.com/portainer/portainer/api/http/handler/ipam"
+	kubehandler "github.com/portainer/portainer/api/http/handler/kubernetes"
+	"github.com/portainer/portainer/api/http/handler/maintenancewindows"
 	"github.com/portainer/portainer/api/http/handler/motd"
+	"github.com/portainer/portainer/api/http/handler/networks"
+	"github.com/portainer/portainer/api/http/handler/notificationchannels"
+	"github.com/portainer/portainer/api/http/handler/notifications"
+	"github.com/portainer/portainer/api/http/handler/organizations"
+	"github.com/portainer/portainer/api/http/handler/permissions"
+	"github.com/portainer/portainer/api/http/handler/proxylabels"
 	"github.com/portainer/portainer/api/http/handler/registries"
+	"github.com/portainer/portainer/api/http/handler/reports"
 	"github.com/portainer/portainer/api/http/handler/resourcecontrols"
 	"github.com/portainer/portainer/api/http/handler/roles"
+	"github.com/portainer/portainer/api/http/handler/scalingschedules"
+	searchhandler "github.com/portainer/portainer/api/http/handler/search"
 	"github.com/portainer/portainer/api/http/handler/settings"
+	"github.com/portainer/portainer/api/http/handler/shadowusers"
 	"github.com/portainer/portainer/api/http/handler/stacks"
 	"github.com/portainer/portainer/api/http/handler/status"
+	"github.com/portainer/portainer/api/http/handler/swarm"
 	"github.com/portainer/portainer/api/http/handler/tags"
+	"github.com/portainer/portainer/api/http/handler/tasks"
 	"github.com/portainer/portainer/api/http/handler/teammemberships"
 	"github.com/portainer/portainer/api/http/handler/teams"
+	"github.com/portainer/portainer/api/http/handler/telemetry"
 	"github.com/portainer/portainer/api/http/handler/templates"
 	"github.com/portainer/portainer/api/http/handler/upload"
+	"github.com/portainer/portainer/api/http/handler/usage"
 	"github.com/portainer/portainer/api/http/handler/users"
+	"github.com/portainer/portainer/api/http/handler/volumes"
 	"github.com/portainer/portainer/api/http/handler/webhooks"
 	"github.com/portainer/portainer/api/http/handler/websocket"
 	"github.com/portainer/portainer/api/http/proxy"
 	"github.com/portainer/portainer/api/http/proxy/factory/kubernetes"
 	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/diagnostics"
+	"github.com/portainer/portainer/api/internal/health"
+	"github.com/portainer/portainer/api/internal/search"
 	"github.com/portainer/portainer/api/kubernetes/cli"
 )
 
@@ -46,19 +78,38 @@ import (
 type Server struct {
 	BindAddress             string
 	AssetsPath              string
+	DataPath                string
+	LogBuffer               *diagnostics.LogBuffer
+	EnableDebugEndpoints    bool
+	AdminInitTimeout        time.Duration
+	AdminInitLockdown       bool
+	RequestSizeLimits       security.RequestSizeLimits
+	APIRateLimit            float64
+	APIRateLimitBurst       int
 	Status                  *portainer.Status
 	ReverseTunnelService    portainer.ReverseTunnelService
 	ComposeStackManager     portainer.ComposeStackManager
 	CryptoService           portainer.CryptoService
 	SignatureService        portainer.DigitalSignatureService
 	SnapshotService         portainer.SnapshotService
+	TelemetryService        portainer.TelemetryService
+	UserActivityLogger      portainer.UserActivityLogger
+	RetentionService        portainer.RetentionService
+	OrphanCleanupService    portainer.OrphanCleanupService
+	ScalingScheduler        portainer.ScalingScheduler
+	FeatureFlagService      portainer.FeatureFlagService
 	FileService             portainer.FileService
 	DataStore               portainer.DataStore
 	GitService              portainer.GitService
 	JWTService              portainer.JWTService
 	LDAPService             portainer.LDAPService
+	LoadBalancerService     portainer.LoadBalancerService
+	MailService             portainer.MailService
+	NotificationService     portainer.NotificationService
 	OAuthService            portainer.OAuthService
+	ReportService           portainer.ReportService
 	SwarmStackManager       portainer.SwarmStackManager
+	TaskManager             portainer.TaskManager
 	Handler                 *handler.Handler
 	SSL                     bool
 	SSLCert                 string
@@ -73,9 +124,10 @@ func (server *Server) Start() error {
 	kubernetesTokenCacheManager := kubernetes.NewTokenCacheManager()
 	proxyManager := proxy.NewManager(server.DataStore, server.SignatureService, server.ReverseTunnelService, server.DockerClientFactory, server.KubernetesClientFactory, kubernetesTokenCacheManager)
 
-	requestBouncer := security.NewRequestBouncer(server.DataStore, server.JWTService)
+	requestBouncer := security.NewRequestBouncer(server.DataStore, server.JWTService, server.TelemetryService, server.UserActivityLogger)
 
 	rateLimiter := security.NewRateLimiter(10, 1*time.Second, 1*time.Hour)
+	apiRateLimiter := security.NewAPIRateLimiter(server.APIRateLimit, server.APIRateLimitBurst, server.JWTService)
 
 	var authHandler = auth.NewHandler(requestBouncer, rateLimiter)
 	authHandler.DataStore = server.DataStore
@@ -85,15 +137,69 @@ func (server *Server) Start() error {
 	authHandler.ProxyManager = proxyManager
 	authHandler.KubernetesTokenCacheManager = kubernetesTokenCacheManager
 	authHandler.OAuthService = server.OAuthService
+	authHandler.UserActivityLogger = server.UserActivityLogger
+
+	var autoHealHandler = autohealrules.NewHandler(requestBouncer)
+	autoHealHandler.DataStore = server.DataStore
+
+	var azureHandler = azurehandler.NewHandler(requestBouncer)
+	azureHandler.DataStore = server.DataStore
+	azureHandler.AzureClientFactory = azure.NewClientFactory()
+
+	var kubernetesHandler = kubehandler.NewHandler(requestBouncer)
+	kubernetesHandler.DataStore = server.DataStore
+	kubernetesHandler.KubernetesClientFactory = server.KubernetesClientFactory
+
+	var dashboardHandler = dashboard.NewHandler(requestBouncer)
+	dashboardHandler.DataStore = server.DataStore
+
+	var searchHandler = searchhandler.NewHandler(requestBouncer)
+	searchHandler.DataStore = server.DataStore
+	searchHandler.SearchService = search.NewService()
 
 	var roleHandler = roles.NewHandler(requestBouncer)
 	roleHandler.DataStore = server.DataStore
 
+	var scalingScheduleHandler = scalingschedules.NewHandler(requestBouncer)
+	scalingScheduleHandler.DataStore = server.DataStore
+	scalingScheduleHandler.ScalingScheduler = server.ScalingScheduler
+
+	var imageHandler = images.NewHandler(requestBouncer)
+	imageHandler.DataStore = server.DataStore
+	imageHandler.DockerClientFactory = server.DockerClientFactory
+	imageHandler.TaskManager = server.TaskManager
+
+	var notificationChannelHandler = notificationchannels.NewHandler(requestBouncer)
+	notificationChannelHandler.DataStore = server.DataStore
+
+	var notificationHandler = notifications.NewHandler(requestBouncer)
+	notificationHandler.DataStore = server.DataStore
+
+	var organizationHandler = organizations.NewHandler(requestBouncer)
+	organizationHandler.DataStore = server.DataStore
+
+	var containerHandler = containers.NewHandler(requestBouncer)
+	containerHandler.DataStore = server.DataStore
+	containerHandler.DockerClientFactory = server.DockerClientFactory
+	containerHandler.FileService = server.FileService
+
+	var brandingHandler = branding.NewHandler(requestBouncer)
+	brandingHandler.DataStore = server.DataStore
+
+	var changeRequestHandler = changerequests.NewHandler(requestBouncer)
+	changeRequestHandler.DataStore = server.DataStore
+	changeRequestHandler.JWTService = server.JWTService
+
 	var customTemplatesHandler = customtemplates.NewHandler(requestBouncer)
 	customTemplatesHandler.DataStore = server.DataStore
 	customTemplatesHandler.FileService = server.FileService
 	customTemplatesHandler.GitService = server.GitService
 
+	var debugHandler *debug.Handler
+	if server.EnableDebugEndpoints {
+		debugHandler = debug.NewHandler(requestBouncer)
+	}
+
 	var dockerHubHandler = dockerhub.NewHandler(requestBouncer)
 	dockerHubHandler.DataStore = server.DataStore
 
@@ -120,6 +226,9 @@ func (server *Server) Start() error {
 	endpointHandler.SnapshotService = server.SnapshotService
 	endpointHandler.ProxyManager = proxyManager
 	endpointHandler.ReverseTunnelService = server.ReverseTunnelService
+	endpointHandler.DockerClientFactory = server.DockerClientFactory
+	endpointHandler.KubernetesClientFactory = server.KubernetesClientFactory
+	endpointHandler.LoadBalancerService = server.LoadBalancerService
 
 	var endpointEdgeHandler = endpointedge.NewHandler(requestBouncer)
 	endpointEdgeHandler.DataStore = server.DataStore
@@ -136,6 +245,20 @@ func (server *Server) Start() error {
 
 	var fileHandler = file.NewHandler(filepath.Join(server.AssetsPath, "public"))
 
+	var healthHandler = healthhandler.NewHandler(requestBouncer)
+	healthHandler.HealthChecker = health.NewChecker(server.DataStore, server.SnapshotService, server.ScalingScheduler, server.ReverseTunnelService, server.DataPath)
+
+	var hostActionHandler = hostactions.NewHandler(requestBouncer)
+	hostActionHandler.DataStore = server.DataStore
+	hostActionHandler.ProxyManager = proxyManager
+	hostActionHandler.ReverseTunnelService = server.ReverseTunnelService
+
+	var ipamHandler = ipam.NewHandler(requestBouncer)
+	ipamHandler.DataStore = server.DataStore
+
+	var maintenanceWindowHandler = maintenancewindows.NewHandler(requestBouncer)
+	maintenanceWindowHandler.DataStore = server.DataStore
+
 	var motdHandler = motd.NewHandler(requestBouncer)
 
 	var registryHandler = registries.NewHandler(requestBouncer)
@@ -145,13 +268,22 @@ func (server *Server) Start() error {
 
 	var resourceControlHandler = resourcecontrols.NewHandler(requestBouncer)
 	resourceControlHandler.DataStore = server.DataStore
+	resourceControlHandler.OrphanCleanupService = server.OrphanCleanupService
+
+	var shadowUserHandler = shadowusers.NewHandler(requestBouncer)
+	shadowUserHandler.DataStore = server.DataStore
+	shadowUserHandler.LDAPService = server.LDAPService
 
 	var settingsHandler = settings.NewHandler(requestBouncer)
 	settingsHandler.DataStore = server.DataStore
+	settingsHandler.FeatureFlagService = server.FeatureFlagService
 	settingsHandler.FileService = server.FileService
 	settingsHandler.JWTService = server.JWTService
 	settingsHandler.LDAPService = server.LDAPService
+	settingsHandler.MailService = server.MailService
 	settingsHandler.SnapshotService = server.SnapshotService
+	settingsHandler.TelemetryService = server.TelemetryService
+	settingsHandler.RetentionService = server.RetentionService
 
 	var stackHandler = stacks.NewHandler(requestBouncer)
 	stackHandler.DataStore = server.DataStore
@@ -160,10 +292,16 @@ func (server *Server) Start() error {
 	stackHandler.ComposeStackManager = server.ComposeStackManager
 	stackHandler.KubernetesDeployer = server.KubernetesDeployer
 	stackHandler.GitService = server.GitService
+	stackHandler.DockerClientFactory = server.DockerClientFactory
+	stackHandler.NotificationService = server.NotificationService
+	stackHandler.TaskManager = server.TaskManager
 
 	var tagHandler = tags.NewHandler(requestBouncer)
 	tagHandler.DataStore = server.DataStore
 
+	var taskHandler = tasks.NewHandler(requestBouncer)
+	taskHandler.DataStore = server.DataStore
+
 	var teamHandler = teams.NewHandler(requestBouncer)
 	teamHandler.DataStore = server.DataStore
 
@@ -171,62 +309,129 @@ func (server *Server) Start() error {
 	teamMembershipHandler.DataStore = server.DataStore
 
 	var statusHandler = status.NewHandler(requestBouncer, server.Status)
+	statusHandler.DiagnosticsService = diagnostics.NewService(server.DataStore, server.ReverseTunnelService, server.LogBuffer)
+
+	var swarmHandler = swarm.NewHandler(requestBouncer)
+	swarmHandler.DataStore = server.DataStore
+	swarmHandler.DockerClientFactory = server.DockerClientFactory
+
+	var telemetryHandler = telemetry.NewHandler(requestBouncer)
+	telemetryHandler.TelemetryService = server.TelemetryService
 
 	var templatesHandler = templates.NewHandler(requestBouncer)
 	templatesHandler.DataStore = server.DataStore
 	templatesHandler.FileService = server.FileService
 	templatesHandler.GitService = server.GitService
+	templatesHandler.DockerClientFactory = server.DockerClientFactory
 
 	var uploadHandler = upload.NewHandler(requestBouncer)
 	uploadHandler.FileService = server.FileService
 
+	var networkHandler = networks.NewHandler(requestBouncer)
+	networkHandler.DataStore = server.DataStore
+	networkHandler.DockerClientFactory = server.DockerClientFactory
+	networkHandler.ProxyManager = proxyManager
+	networkHandler.ReverseTunnelService = server.ReverseTunnelService
+
+	var permissionHandler = permissions.NewHandler(requestBouncer)
+	permissionHandler.DataStore = server.DataStore
+
+	var proxyLabelsHandler = proxylabels.NewHandler(requestBouncer)
+	proxyLabelsHandler.DataStore = server.DataStore
+	proxyLabelsHandler.DockerClientFactory = server.DockerClientFactory
+
+	var reportHandler = reports.NewHandler(requestBouncer)
+	reportHandler.DataStore = server.DataStore
+	reportHandler.ReportService = server.ReportService
+
+	var usageHandler = usage.NewHandler(requestBouncer)
+	usageHandler.DataStore = server.DataStore
+
 	var userHandler = users.NewHandler(requestBouncer, rateLimiter)
 	userHandler.DataStore = server.DataStore
 	userHandler.CryptoService = server.CryptoService
+	userHandler.AdminInitDeadline = time.Now().Add(server.AdminInitTimeout)
+	userHandler.AdminInitLockdown = server.AdminInitLockdown
+	userHandler.FileService = server.FileService
+
+	var volumeHandler = volumes.NewHandler(requestBouncer)
+	volumeHandler.DataStore = server.DataStore
+	volumeHandler.DockerClientFactory = server.DockerClientFactory
 
 	var websocketHandler = websocket.NewHandler(requestBouncer)
 	websocketHandler.DataStore = server.DataStore
 	websocketHandler.SignatureService = server.SignatureService
 	websocketHandler.ReverseTunnelService = server.ReverseTunnelService
 	websocketHandler.KubernetesClientFactory = server.KubernetesClientFactory
+	websocketHandler.DockerClientFactory = server.DockerClientFactory
+	websocketHandler.NotificationService = server.NotificationService
+	websocketHandler.TaskManager = server.TaskManager
 
 	var webhookHandler = webhooks.NewHandler(requestBouncer)
 	webhookHandler.DataStore = server.DataStore
 	webhookHandler.DockerClientFactory = server.DockerClientFactory
 
 	server.Handler = &handler.Handler{
-		RoleHandler:            roleHandler,
-		AuthHandler:            authHandler,
-		CustomTemplatesHandler: customTemplatesHandler,
-		DockerHubHandler:       dockerHubHandler,
-		EdgeGroupsHandler:      edgeGroupsHandler,
-		EdgeJobsHandler:        edgeJobsHandler,
-		EdgeStacksHandler:      edgeStacksHandler,
-		EdgeTemplatesHandler:   edgeTemplatesHandler,
-		EndpointGroupHandler:   endpointGroupHandler,
-		EndpointHandler:        endpointHandler,
-		EndpointEdgeHandler:    endpointEdgeHandler,
-		EndpointProxyHandler:   endpointProxyHandler,
-		FileHandler:            fileHandler,
-		MOTDHandler:            motdHandler,
-		RegistryHandler:        registryHandler,
-		ResourceControlHandler: resourceControlHandler,
-		SettingsHandler:        settingsHandler,
-		StatusHandler:          statusHandler,
-		StackHandler:           stackHandler,
-		TagHandler:             tagHandler,
-		TeamHandler:            teamHandler,
-		TeamMembershipHandler:  teamMembershipHandler,
-		TemplatesHandler:       templatesHandler,
-		UploadHandler:          uploadHandler,
-		UserHandler:            userHandler,
-		WebSocketHandler:       websocketHandler,
-		WebhookHandler:         webhookHandler,
+		RoleHandler:                roleHandler,
+		ScalingScheduleHandler:     scalingScheduleHandler,
+		NotificationChannelHandler: notificationChannelHandler,
+		NotificationHandler:        notificationHandler,
+		OrganizationHandler:        organizationHandler,
+		SearchHandler:              searchHandler,
+		AuthHandler:                authHandler,
+		AutoHealHandler:            autoHealHandler,
+		AzureHandler:               azureHandler,
+		ContainerHandler:           containerHandler,
+		BrandingHandler:            brandingHandler,
+		ChangeRequestHandler:       changeRequestHandler,
+		CustomTemplatesHandler:     customTemplatesHandler,
+		DashboardHandler:           dashboardHandler,
+		DebugHandler:               debugHandler,
+		DockerHubHandler:           dockerHubHandler,
+		EdgeGroupsHandler:          edgeGroupsHandler,
+		EdgeJobsHandler:            edgeJobsHandler,
+		EdgeStacksHandler:          edgeStacksHandler,
+		EdgeTemplatesHandler:       edgeTemplatesHandler,
+		EndpointGroupHandler:       endpointGroupHandler,
+		EndpointHandler:            endpointHandler,
+		EndpointEdgeHandler:        endpointEdgeHandler,
+		EndpointProxyHandler:       endpointProxyHandler,
+		FileHandler:                fileHandler,
+		HealthHandler:              healthHandler,
+		HostActionHandler:          hostActionHandler,
+		ImageHandler:               imageHandler,
+		IPAMHandler:                ipamHandler,
+		KubernetesHandler:          kubernetesHandler,
+		MaintenanceWindowHandler:   maintenanceWindowHandler,
+		MOTDHandler:                motdHandler,
+		NetworkHandler:             networkHandler,
+		RegistryHandler:            registryHandler,
+		PermissionHandler:          permissionHandler,
+		ProxyLabelsHandler:         proxyLabelsHandler,
+		ReportHandler:              reportHandler,
+		ResourceControlHandler:     resourceControlHandler,
+		ShadowUserHandler:          shadowUserHandler,
+		SettingsHandler:            settingsHandler,
+		StatusHandler:              statusHandler,
+		SwarmHandler:               swarmHandler,
+		StackHandler:               stackHandler,
+		TagHandler:                 tagHandler,
+		TaskHandler:                taskHandler,
+		TeamHandler:                teamHandler,
+		TeamMembershipHandler:      teamMembershipHandler,
+		TelemetryHandler:           telemetryHandler,
+		TemplatesHandler:           templatesHandler,
+		UploadHandler:              uploadHandler,
+		UsageHandler:               usageHandler,
+		UserHandler:                userHandler,
+		VolumeHandler:              volumeHandler,
+		WebSocketHandler:           websocketHandler,
+		WebhookHandler:             webhookHandler,
 	}
 
 	httpServer := &http.Server{
 		Addr:    server.BindAddress,
-		Handler: server.Handler,
+		Handler: requestBouncer.Localize(apiRateLimiter.Limit(security.LimitRequestSize(server.Handler, server.RequestSizeLimits))),
 	}
 
 	if server.SSL {
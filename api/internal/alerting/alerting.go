@@ -0,0 +1,181 @@
+package alerting
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/docker"
+	"github.com/portainer/portainer/api/internal/maintenance"
+)
+
+// Service represents a service used to raise notifications when the containers of an endpoint
+// become unhealthy or enter a restart loop, or when the resources of its host are under pressure.
+// A notification is raised when a container or a host resource transitions to one of these states
+// between two snapshots, at most once per deduplication interval and never during a silence window.
+type Service struct {
+	dataStore           portainer.DataStore
+	notificationService portainer.NotificationService
+	mutex               sync.Mutex
+	lastNotifications   map[string]time.Time
+}
+
+// NewService returns a new instance of a container alerting service
+func NewService(dataStore portainer.DataStore, notificationService portainer.NotificationService) *Service {
+	return &Service{
+		dataStore:           dataStore,
+		notificationService: notificationService,
+		lastNotifications:   make(map[string]time.Time),
+	}
+}
+
+// ProcessSnapshot compares two snapshots of an endpoint and raises a notification for each container
+// that became unhealthy or started restarting and for each host resource that went above its threshold.
+// No notification is raised while the endpoint is in a maintenance window.
+func (service *Service) ProcessSnapshot(endpoint *portainer.Endpoint, previous, current *portainer.DockerSnapshot) {
+	if maintenance.InMaintenance(service.dataStore, endpoint) {
+		return
+	}
+
+	settings, err := service.dataStore.Settings().Settings()
+	if err != nil {
+		log.Printf("[WARN] [internal,alerting] [message: unable to retrieve settings] [error: %s]", err)
+		return
+	}
+
+	deduplicationInterval, err := time.ParseDuration(settings.ContainerAlerting.DeduplicationInterval)
+	if err != nil {
+		deduplicationInterval, _ = time.ParseDuration(portainer.DefaultContainerAlertingDeduplicationInterval)
+	}
+
+	if settings.ContainerAlerting.Enabled {
+		service.processContainers(settings, endpoint, previous, current, deduplicationInterval)
+	}
+
+	if settings.HostAlerting.Enabled && current.Host != nil {
+		service.processHost(settings, endpoint, previous, current, deduplicationInterval)
+	}
+}
+
+func (service *Service) processContainers(settings *portainer.Settings, endpoint *portainer.Endpoint, previous, current *portainer.DockerSnapshot, deduplicationInterval time.Duration) {
+	currentContainers, err := docker.SnapshotContainers(current)
+	if err != nil {
+		log.Printf("[WARN] [internal,alerting] [message: unable to decode snapshot containers] [endpoint: %s] [error: %s]", endpoint.Name, err)
+		return
+	}
+
+	previousStates := make(map[string]portainer.NotificationType)
+	if previous != nil {
+		previousContainers, err := docker.SnapshotContainers(previous)
+		if err != nil {
+			log.Printf("[WARN] [internal,alerting] [message: unable to decode snapshot containers] [endpoint: %s] [error: %s]", endpoint.Name, err)
+		}
+
+		for _, container := range previousContainers {
+			previousStates[container.ID] = containerAlertType(&container)
+		}
+	}
+
+	now := time.Now()
+
+	for _, container := range currentContainers {
+		alertType := containerAlertType(&container)
+		if alertType == 0 || previousStates[container.ID] == alertType {
+			continue
+		}
+
+		name := containerName(&container)
+		if isSilenced(settings.ContainerAlerting.Silences, endpoint.ID, name, now) {
+			continue
+		}
+
+		if !service.shouldNotify(fmt.Sprintf("%d/%s/%d", endpoint.ID, name, alertType), now, deduplicationInterval) {
+			continue
+		}
+
+		service.notificationService.Notify(newNotification(endpoint, &container, name, alertType, now))
+	}
+}
+
+// shouldNotify records the notification identified by key and reports whether it can be sent,
+// the same notification is sent at most once per deduplication interval.
+func (service *Service) shouldNotify(key string, now time.Time, deduplicationInterval time.Duration) bool {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	for existingKey, notifiedAt := range service.lastNotifications {
+		if now.Sub(notifiedAt) >= deduplicationInterval {
+			delete(service.lastNotifications, existingKey)
+		}
+	}
+
+	if _, ok := service.lastNotifications[key]; ok {
+		return false
+	}
+
+	service.lastNotifications[key] = now
+	return true
+}
+
+func isSilenced(silences []portainer.AlertingSilence, endpointID portainer.EndpointID, containerName string, now time.Time) bool {
+	for _, silence := range silences {
+		if silence.Until < now.Unix() {
+			continue
+		}
+
+		if silence.EndpointID != 0 && silence.EndpointID != endpointID {
+			continue
+		}
+
+		if silence.ContainerName != "" && silence.ContainerName != containerName {
+			continue
+		}
+
+		return true
+	}
+
+	return false
+}
+
+func containerAlertType(container *types.Container) portainer.NotificationType {
+	if container.State == "restarting" {
+		return portainer.ContainerRestartLoopNotification
+	}
+
+	if strings.Contains(container.Status, "(unhealthy)") {
+		return portainer.ContainerUnhealthyNotification
+	}
+
+	return 0
+}
+
+func containerName(container *types.Container) string {
+	if len(container.Names) > 0 {
+		return strings.TrimPrefix(container.Names[0], "/")
+	}
+	return container.ID
+}
+
+func newNotification(endpoint *portainer.Endpoint, container *types.Container, name string, alertType portainer.NotificationType, now time.Time) *portainer.Notification {
+	notification := &portainer.Notification{
+		Type:       alertType,
+		EndpointID: endpoint.ID,
+		ResourceID: container.ID,
+		Time:       now.Unix(),
+	}
+
+	switch alertType {
+	case portainer.ContainerRestartLoopNotification:
+		notification.Title = "Container restart loop"
+		notification.Message = fmt.Sprintf("Container %s (%s) on endpoint %s is restarting: %s", name, container.Image, endpoint.Name, container.Status)
+	default:
+		notification.Title = "Unhealthy container"
+		notification.Message = fmt.Sprintf("Container %s (%s) on endpoint %s is unhealthy: %s", name, container.Image, endpoint.Name, container.Status)
+	}
+
+	return notification
+}
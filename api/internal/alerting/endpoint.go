@@ -0,0 +1,56 @@
+package alerting
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/internal/maintenance"
+)
+
+// ProcessEndpointStatus raises a notification when an endpoint becomes unreachable or reachable again.
+// Notifications of a flapping endpoint are limited by the deduplication interval, and silenced while
+// the endpoint is in a maintenance window.
+func (service *Service) ProcessEndpointStatus(endpoint *portainer.Endpoint, previous portainer.EndpointStatus) {
+	if endpoint.Status == previous || previous == 0 {
+		return
+	}
+
+	if maintenance.InMaintenance(service.dataStore, endpoint) {
+		return
+	}
+
+	settings, err := service.dataStore.Settings().Settings()
+	if err != nil {
+		log.Printf("[WARN] [internal,alerting] [message: unable to retrieve settings] [error: %s]", err)
+		return
+	}
+
+	deduplicationInterval, err := time.ParseDuration(settings.ContainerAlerting.DeduplicationInterval)
+	if err != nil {
+		deduplicationInterval, _ = time.ParseDuration(portainer.DefaultContainerAlertingDeduplicationInterval)
+	}
+
+	now := time.Now()
+
+	notification := &portainer.Notification{
+		Type:       portainer.EndpointUpNotification,
+		EndpointID: endpoint.ID,
+		Title:      "Endpoint recovered",
+		Message:    fmt.Sprintf("Endpoint %s (%s) is reachable again", endpoint.Name, endpoint.URL),
+		Time:       now.Unix(),
+	}
+
+	if endpoint.Status == portainer.EndpointStatusDown {
+		notification.Type = portainer.EndpointDownNotification
+		notification.Title = "Endpoint down"
+		notification.Message = fmt.Sprintf("Endpoint %s (%s) is unreachable", endpoint.Name, endpoint.URL)
+	}
+
+	if !service.shouldNotify(fmt.Sprintf("%d/endpoint/%d", endpoint.ID, notification.Type), now, deduplicationInterval) {
+		return
+	}
+
+	service.notificationService.Notify(notification)
+}
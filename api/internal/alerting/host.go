@@ -0,0 +1,107 @@
+package alerting
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/portainer/portainer/api"
+)
+
+// HostPressure represents the resources of a host used above the configured thresholds
+type HostPressure struct {
+	// Filesystems are the filesystems used above the disk usage threshold
+	Filesystems []portainer.HostFilesystemUsage `json:"Filesystems"`
+	Memory      bool                            `json:"Memory"`
+	Load        bool                            `json:"Load"`
+}
+
+// EvaluateHostPressure compares the resource usage of a host against the thresholds of the settings.
+// Resources that are not reported by the host are never under pressure.
+func EvaluateHostPressure(host *portainer.DockerHostSnapshot, cpus int, settings *portainer.HostAlertingSettings) *HostPressure {
+	pressure := &HostPressure{
+		Filesystems: make([]portainer.HostFilesystemUsage, 0),
+	}
+
+	if host == nil {
+		return pressure
+	}
+
+	if settings.DiskUsageThreshold > 0 {
+		for _, filesystem := range host.Filesystems {
+			if filesystem.Total > 0 && percent(filesystem.Used, filesystem.Total) >= settings.DiskUsageThreshold {
+				pressure.Filesystems = append(pressure.Filesystems, filesystem)
+			}
+		}
+	}
+
+	if settings.MemoryUsageThreshold > 0 && host.MemoryTotal > 0 {
+		pressure.Memory = percent(host.MemoryTotal-host.MemoryAvailable, host.MemoryTotal) >= settings.MemoryUsageThreshold
+	}
+
+	if settings.LoadAverageThreshold > 0 && len(host.LoadAverage) > 1 && cpus > 0 {
+		pressure.Load = host.LoadAverage[1]/float64(cpus) >= settings.LoadAverageThreshold
+	}
+
+	return pressure
+}
+
+func (service *Service) processHost(settings *portainer.Settings, endpoint *portainer.Endpoint, previous, current *portainer.DockerSnapshot, deduplicationInterval time.Duration) {
+	currentPressure := EvaluateHostPressure(current.Host, current.TotalCPU, &settings.HostAlerting)
+
+	previousPressure := &HostPressure{}
+	if previous != nil {
+		previousPressure = EvaluateHostPressure(previous.Host, previous.TotalCPU, &settings.HostAlerting)
+	}
+
+	now := time.Now()
+	if isSilenced(settings.ContainerAlerting.Silences, endpoint.ID, "", now) {
+		return
+	}
+
+	previousFilesystems := make(map[string]bool)
+	for _, filesystem := range previousPressure.Filesystems {
+		previousFilesystems[filesystem.MountPoint] = true
+	}
+
+	for _, filesystem := range currentPressure.Filesystems {
+		if previousFilesystems[filesystem.MountPoint] {
+			continue
+		}
+
+		service.notifyHost(endpoint, portainer.HostDiskPressureNotification, filesystem.MountPoint, "Host disk pressure",
+			fmt.Sprintf("Filesystem %s (%s) on endpoint %s is %.1f%% full", filesystem.MountPoint, filesystem.Device, endpoint.Name, percent(filesystem.Used, filesystem.Total)),
+			now, deduplicationInterval)
+	}
+
+	if currentPressure.Memory && !previousPressure.Memory {
+		host := current.Host
+		service.notifyHost(endpoint, portainer.HostMemoryPressureNotification, "memory", "Host memory pressure",
+			fmt.Sprintf("Memory usage on endpoint %s is %.1f%%", endpoint.Name, percent(host.MemoryTotal-host.MemoryAvailable, host.MemoryTotal)),
+			now, deduplicationInterval)
+	}
+
+	if currentPressure.Load && !previousPressure.Load {
+		service.notifyHost(endpoint, portainer.HostLoadNotification, "load", "Host load",
+			fmt.Sprintf("Load average on endpoint %s is %.2f for %d CPUs", endpoint.Name, current.Host.LoadAverage[1], current.TotalCPU),
+			now, deduplicationInterval)
+	}
+}
+
+func (service *Service) notifyHost(endpoint *portainer.Endpoint, notificationType portainer.NotificationType, resourceID, title, message string, now time.Time, deduplicationInterval time.Duration) {
+	if !service.shouldNotify(fmt.Sprintf("%d/host/%s/%d", endpoint.ID, resourceID, notificationType), now, deduplicationInterval) {
+		return
+	}
+
+	service.notificationService.Notify(&portainer.Notification{
+		Type:       notificationType,
+		EndpointID: endpoint.ID,
+		ResourceID: resourceID,
+		Title:      title,
+		Message:    message,
+		Time:       now.Unix(),
+	})
+}
+
+func percent(value, total int64) float64 {
+	return float64(value) * 100 / float64(total)
+}
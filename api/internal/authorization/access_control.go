@@ -132,11 +132,12 @@ func FilterAuthorizedStacks(stacks []portainer.Stack, user *portainer.User, user
 }
 
 // FilterAuthorizedCustomTemplates returns a list of decorated custom templates filtered through resource control access checks.
+// The templates with an approved publication are visible to all the users.
 func FilterAuthorizedCustomTemplates(customTemplates []portainer.CustomTemplate, user *portainer.User, userTeamIDs []portainer.TeamID) []portainer.CustomTemplate {
 	authorizedTemplates := make([]portainer.CustomTemplate, 0)
 
 	for _, customTemplate := range customTemplates {
-		if customTemplate.CreatedByUserID == user.ID || (customTemplate.ResourceControl != nil && UserCanAccessResource(user.ID, userTeamIDs, customTemplate.ResourceControl)) {
+		if customTemplate.CreatedByUserID == user.ID || CustomTemplatePublished(&customTemplate) || (customTemplate.ResourceControl != nil && UserCanAccessResource(user.ID, userTeamIDs, customTemplate.ResourceControl)) {
 			authorizedTemplates = append(authorizedTemplates, customTemplate)
 		}
 	}
@@ -144,6 +145,11 @@ func FilterAuthorizedCustomTemplates(customTemplates []portainer.CustomTemplate,
 	return authorizedTemplates
 }
 
+// CustomTemplatePublished returns true when the publication of a custom template was approved
+func CustomTemplatePublished(customTemplate *portainer.CustomTemplate) bool {
+	return customTemplate.Publication != nil && customTemplate.Publication.Status == portainer.CustomTemplatePublicationApproved
+}
+
 // UserCanAccessResource will valide that a user has permissions defined in the specified resource control
 // based on its identifier and the team(s) he is part of.
 func UserCanAccessResource(userID portainer.UserID, userTeamIDs []portainer.TeamID, resourceControl *portainer.ResourceControl) bool {
@@ -164,6 +170,26 @@ func UserCanAccessResource(userID portainer.UserID, userTeamIDs []portainer.Team
 	return resourceControl.Public
 }
 
+// AdministratorOrUserCanAccessResource returns true when the user is an administrator or when the user has
+// permissions defined in the specified resource control. Resources without resource control are only
+// accessible to the administrators.
+func AdministratorOrUserCanAccessResource(isAdmin bool, userID portainer.UserID, userMemberships []portainer.TeamMembership, resourceControl *portainer.ResourceControl) bool {
+	if isAdmin {
+		return true
+	}
+
+	if resourceControl == nil {
+		return false
+	}
+
+	userTeamIDs := make([]portainer.TeamID, 0)
+	for _, membership := range userMemberships {
+		userTeamIDs = append(userTeamIDs, membership.TeamID)
+	}
+
+	return UserCanAccessResource(userID, userTeamIDs, resourceControl)
+}
+
 // GetResourceControlByResourceIDAndType retrieves the first matching resource control in a set of resource controls
 // based on the specified id and resource type parameters.
 func GetResourceControlByResourceIDAndType(resourceID string, resourceType portainer.ResourceControlType, resourceControls []portainer.ResourceControl) *portainer.ResourceControl {
@@ -179,3 +205,74 @@ func GetResourceControlByResourceIDAndType(resourceID string, resourceType porta
 	}
 	return nil
 }
+
+// GetInheritedResourceControl retrieves the resource control associated to a Docker resource, either directly or
+// inherited from the Swarm service or the stack the resource belongs to, based on the labels of the resource.
+func GetInheritedResourceControl(resourceID string, resourceType portainer.ResourceControlType, labels map[string]string, resourceControls []portainer.ResourceControl) *portainer.ResourceControl {
+	resourceControl := GetResourceControlByResourceIDAndType(resourceID, resourceType, resourceControls)
+	if resourceControl != nil {
+		return resourceControl
+	}
+
+	inheritedLabels := []struct {
+		label        string
+		resourceType portainer.ResourceControlType
+	}{
+		{"com.docker.swarm.service.id", portainer.ServiceResourceControl},
+		{"com.docker.stack.namespace", portainer.StackResourceControl},
+		{"com.docker.compose.project", portainer.StackResourceControl},
+	}
+
+	for _, inherited := range inheritedLabels {
+		if labels[inherited.label] == "" {
+			continue
+		}
+
+		resourceControl = GetResourceControlByResourceIDAndType(labels[inherited.label], inherited.resourceType, resourceControls)
+		if resourceControl != nil {
+			return resourceControl
+		}
+	}
+
+	return nil
+}
+
+// NewDefaultResourceControl will create a new resource control associated to the resource specified by the
+// identifier and type parameters. The accesses of the resource control are defined by the ownership policy:
+// private to the user specified by the userID parameter, shared with the teams of that user or public.
+// When the team policy is used and the user is not part of any team, a private resource control is created.
+func NewDefaultResourceControl(resourceIdentifier string, resourceType portainer.ResourceControlType, userID portainer.UserID, userTeamIDs []portainer.TeamID, policy portainer.ResourceOwnershipPolicy) *portainer.ResourceControl {
+	switch policy {
+	case portainer.ResourceOwnershipPolicyPublic:
+		return NewPublicResourceControl(resourceIdentifier, resourceType)
+	case portainer.ResourceOwnershipPolicyTeam:
+		if len(userTeamIDs) > 0 {
+			return NewRestrictedResourceControl(resourceIdentifier, resourceType, []portainer.UserID{userID}, userTeamIDs)
+		}
+	}
+
+	return NewPrivateResourceControl(resourceIdentifier, resourceType, userID)
+}
+
+// EffectiveResourceOwnershipPolicy returns the ownership policy that must be applied to resources
+// created inside the specified endpoint. The endpoint policy takes precedence over the default
+// policy defined in the settings, the private policy is used when none is defined.
+func EffectiveResourceOwnershipPolicy(endpoint *portainer.Endpoint, settings *portainer.Settings) portainer.ResourceOwnershipPolicy {
+	if endpoint != nil && endpoint.ResourceOwnershipPolicy != 0 {
+		return endpoint.ResourceOwnershipPolicy
+	}
+
+	if settings != nil && settings.DefaultResourceOwnershipPolicy != 0 {
+		return settings.DefaultResourceOwnershipPolicy
+	}
+
+	return portainer.ResourceOwnershipPolicyPrivate
+}
+
+// ValidResourceOwnershipPolicy returns true if the specified value matches one of the
+// supported resource ownership policies.
+func ValidResourceOwnershipPolicy(policy portainer.ResourceOwnershipPolicy) bool {
+	return policy == portainer.ResourceOwnershipPolicyPrivate ||
+		policy == portainer.ResourceOwnershipPolicyTeam ||
+		policy == portainer.ResourceOwnershipPolicyPublic
+}
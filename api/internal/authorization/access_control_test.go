@@ -0,0 +1,35 @@
+package authorization
+
+import (
+	"testing"
+
+	"github.com/portainer/portainer/api"
+)
+
+func TestAdministratorOrUserCanAccessResource(t *testing.T) {
+	resourceControl := &portainer.ResourceControl{
+		UserAccesses: []portainer.UserResourceAccess{{UserID: 2}},
+		TeamAccesses: []portainer.TeamResourceAccess{{TeamID: 7}},
+	}
+
+	cases := []struct {
+		isAdmin         bool
+		userID          portainer.UserID
+		memberships     []portainer.TeamMembership
+		resourceControl *portainer.ResourceControl
+		expected        bool
+	}{
+		{true, 1, nil, nil, true},
+		{false, 1, nil, nil, false},
+		{false, 2, nil, resourceControl, true},
+		{false, 3, []portainer.TeamMembership{{UserID: 3, TeamID: 7}}, resourceControl, true},
+		{false, 3, []portainer.TeamMembership{{UserID: 3, TeamID: 8}}, resourceControl, false},
+	}
+
+	for _, c := range cases {
+		access := AdministratorOrUserCanAccessResource(c.isAdmin, c.userID, c.memberships, c.resourceControl)
+		if access != c.expected {
+			t.Errorf("expected access %t for user %d (admin: %t), got %t", c.expected, c.userID, c.isAdmin, access)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package authorization
+
+import "github.com/portainer/portainer/api"
+
+// AccessRule represents the rule through which a user or a team is granted access to a resource
+type AccessRule string
+
+const (
+	// AccessRuleAdministrator grants access to all the resources to the administrators
+	AccessRuleAdministrator AccessRule = "administrator"
+	// AccessRuleDirect grants access to a user listed in the access policies of the resource, or to its owner
+	AccessRuleDirect AccessRule = "direct"
+	// AccessRuleTeam grants access to the members of a team listed in the access policies of the resource
+	AccessRuleTeam AccessRule = "team"
+	// AccessRuleGroupInheritance grants access to an endpoint through the access policies of its endpoint group
+	AccessRuleGroupInheritance AccessRule = "group"
+	// AccessRulePublic grants access to a public resource, or to a published custom template
+	AccessRulePublic AccessRule = "public"
+)
+
+type (
+	// AccessSubject represents the user or the team whose accesses are evaluated, the accesses of a team
+	// are evaluated with the team identifier only
+	AccessSubject struct {
+		UserID  portainer.UserID
+		TeamIDs []portainer.TeamID
+		IsAdmin bool
+	}
+
+	// AccessGrant represents the rule granting access to a resource. The team is set when the access
+	// is granted to a team, the endpoint group when it is inherited from the group of an endpoint.
+	AccessGrant struct {
+		Rule            AccessRule                `json:"Rule"`
+		TeamID          portainer.TeamID          `json:"TeamId,omitempty"`
+		EndpointGroupID portainer.EndpointGroupID `json:"EndpointGroupId,omitempty"`
+		RoleID          portainer.RoleID          `json:"RoleId,omitempty"`
+	}
+)
+
+// EndpointAccessGrant returns the rule granting access to an endpoint, or nil when the subject cannot access it.
+// The access policies of the endpoint take precedence over the access policies of its group.
+func EndpointAccessGrant(subject *AccessSubject, endpoint *portainer.Endpoint, endpointGroup *portainer.EndpointGroup) *AccessGrant {
+	if subject.IsAdmin {
+		return &AccessGrant{Rule: AccessRuleAdministrator}
+	}
+
+	grant := accessPoliciesGrant(subject, endpoint.UserAccessPolicies, endpoint.TeamAccessPolicies)
+	if grant != nil || endpointGroup == nil {
+		return grant
+	}
+
+	grant = accessPoliciesGrant(subject, endpointGroup.UserAccessPolicies, endpointGroup.TeamAccessPolicies)
+	if grant != nil {
+		grant.Rule = AccessRuleGroupInheritance
+		grant.EndpointGroupID = endpointGroup.ID
+	}
+	return grant
+}
+
+// RegistryAccessGrant returns the rule granting access to a registry, or nil when the subject cannot access it
+func RegistryAccessGrant(subject *AccessSubject, registry *portainer.Registry) *AccessGrant {
+	if subject.IsAdmin {
+		return &AccessGrant{Rule: AccessRuleAdministrator}
+	}
+
+	return accessPoliciesGrant(subject, registry.UserAccessPolicies, registry.TeamAccessPolicies)
+}
+
+// ResourceControlAccessGrant returns the rule granting access to a resource protected by a resource control,
+// or nil when the subject cannot access it. The resources without resource control are restricted to the administrators.
+func ResourceControlAccessGrant(subject *AccessSubject, resourceControl *portainer.ResourceControl) *AccessGrant {
+	if subject.IsAdmin {
+		return &AccessGrant{Rule: AccessRuleAdministrator}
+	}
+
+	if resourceControl == nil || resourceControl.AdministratorsOnly {
+		return nil
+	}
+
+	for _, access := range resourceControl.UserAccesses {
+		if subject.UserID != 0 && access.UserID == subject.UserID {
+			return &AccessGrant{Rule: AccessRuleDirect}
+		}
+	}
+
+	for _, access := range resourceControl.TeamAccesses {
+		for _, teamID := range subject.TeamIDs {
+			if access.TeamID == teamID {
+				return &AccessGrant{Rule: AccessRuleTeam, TeamID: teamID}
+			}
+		}
+	}
+
+	if resourceControl.Public {
+		return &AccessGrant{Rule: AccessRulePublic}
+	}
+
+	return nil
+}
+
+// CustomTemplateAccessGrant returns the rule granting access to a custom template decorated with its
+// resource control, or nil when the subject cannot access it
+func CustomTemplateAccessGrant(subject *AccessSubject, customTemplate *portainer.CustomTemplate) *AccessGrant {
+	if subject.IsAdmin {
+		return &AccessGrant{Rule: AccessRuleAdministrator}
+	}
+
+	if subject.UserID != 0 && customTemplate.CreatedByUserID == subject.UserID {
+		return &AccessGrant{Rule: AccessRuleDirect}
+	}
+
+	grant := ResourceControlAccessGrant(subject, customTemplate.ResourceControl)
+	if grant == nil && CustomTemplatePublished(customTemplate) {
+		return &AccessGrant{Rule: AccessRulePublic}
+	}
+	return grant
+}
+
+func accessPoliciesGrant(subject *AccessSubject, userAccessPolicies portainer.UserAccessPolicies, teamAccessPolicies portainer.TeamAccessPolicies) *AccessGrant {
+	if subject.UserID != 0 {
+		if policy, ok := userAccessPolicies[subject.UserID]; ok {
+			return &AccessGrant{Rule: AccessRuleDirect, RoleID: policy.RoleID}
+		}
+	}
+
+	for _, teamID := range subject.TeamIDs {
+		if policy, ok := teamAccessPolicies[teamID]; ok {
+			return &AccessGrant{Rule: AccessRuleTeam, TeamID: teamID, RoleID: policy.RoleID}
+		}
+	}
+
+	return nil
+}
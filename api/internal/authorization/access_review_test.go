@@ -0,0 +1,59 @@
+package authorization
+
+import (
+	"testing"
+
+	"github.com/portainer/portainer/api"
+)
+
+func TestEndpointAccessGrant(t *testing.T) {
+	endpoint := &portainer.Endpoint{
+		ID:                 1,
+		UserAccessPolicies: portainer.UserAccessPolicies{2: {RoleID: 3}},
+		TeamAccessPolicies: portainer.TeamAccessPolicies{},
+	}
+	endpointGroup := &portainer.EndpointGroup{
+		ID:                 5,
+		UserAccessPolicies: portainer.UserAccessPolicies{2: {RoleID: 4}},
+		TeamAccessPolicies: portainer.TeamAccessPolicies{7: {RoleID: 4}},
+	}
+
+	cases := []struct {
+		subject  AccessSubject
+		expected *AccessGrant
+	}{
+		{AccessSubject{UserID: 1, IsAdmin: true}, &AccessGrant{Rule: AccessRuleAdministrator}},
+		{AccessSubject{UserID: 2, TeamIDs: []portainer.TeamID{7}}, &AccessGrant{Rule: AccessRuleDirect, RoleID: 3}},
+		{AccessSubject{UserID: 3, TeamIDs: []portainer.TeamID{7}}, &AccessGrant{Rule: AccessRuleGroupInheritance, TeamID: 7, EndpointGroupID: 5, RoleID: 4}},
+		{AccessSubject{TeamIDs: []portainer.TeamID{8}}, nil},
+	}
+
+	for _, c := range cases {
+		grant := EndpointAccessGrant(&c.subject, endpoint, endpointGroup)
+		if (grant == nil) != (c.expected == nil) || (grant != nil && *grant != *c.expected) {
+			t.Errorf("wrong grant for subject %+v: got %+v want %+v", c.subject, grant, c.expected)
+		}
+	}
+}
+
+func TestResourceControlAccessGrant(t *testing.T) {
+	resourceControl := &portainer.ResourceControl{
+		UserAccesses: []portainer.UserResourceAccess{{UserID: 2}},
+		TeamAccesses: []portainer.TeamResourceAccess{{TeamID: 7}},
+	}
+
+	if grant := ResourceControlAccessGrant(&AccessSubject{UserID: 2}, resourceControl); grant == nil || grant.Rule != AccessRuleDirect {
+		t.Errorf("wrong grant for a user of the resource control: got %+v", grant)
+	}
+	if grant := ResourceControlAccessGrant(&AccessSubject{TeamIDs: []portainer.TeamID{7}}, resourceControl); grant == nil || grant.Rule != AccessRuleTeam || grant.TeamID != 7 {
+		t.Errorf("wrong grant for a team of the resource control: got %+v", grant)
+	}
+	if grant := ResourceControlAccessGrant(&AccessSubject{UserID: 3}, resourceControl); grant != nil {
+		t.Errorf("wrong grant for a user without access: got %+v", grant)
+	}
+
+	resourceControl.Public = true
+	if grant := ResourceControlAccessGrant(&AccessSubject{UserID: 3}, resourceControl); grant == nil || grant.Rule != AccessRulePublic {
+		t.Errorf("wrong grant for a public resource: got %+v", grant)
+	}
+}
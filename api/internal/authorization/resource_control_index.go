@@ -0,0 +1,66 @@
+package authorization
+
+import "github.com/portainer/portainer/api"
+
+type resourceControlKey struct {
+	resourceID   string
+	resourceType portainer.ResourceControlType
+}
+
+// ResourceControlIndex is a precomputed lookup table over a set of resource controls. It returns the same
+// results as GetResourceControlByResourceIDAndType in constant time and is used when filtering large
+// resource lists.
+type ResourceControlIndex struct {
+	resourceControls []portainer.ResourceControl
+	byResource       map[resourceControlKey]int
+	bySubResource    map[string]int
+}
+
+// NewResourceControlIndex builds the lookup table of the specified resource controls.
+func NewResourceControlIndex(resourceControls []portainer.ResourceControl) *ResourceControlIndex {
+	index := &ResourceControlIndex{
+		resourceControls: resourceControls,
+		byResource:       make(map[resourceControlKey]int, len(resourceControls)),
+		bySubResource:    make(map[string]int),
+	}
+
+	for idx, resourceControl := range resourceControls {
+		key := resourceControlKey{resourceID: resourceControl.ResourceID, resourceType: resourceControl.Type}
+		if _, ok := index.byResource[key]; !ok {
+			index.byResource[key] = idx
+		}
+
+		for _, subResourceID := range resourceControl.SubResourceIDs {
+			if _, ok := index.bySubResource[subResourceID]; !ok {
+				index.bySubResource[subResourceID] = idx
+			}
+		}
+	}
+
+	return index
+}
+
+// ResourceControls returns the indexed resource controls.
+func (index *ResourceControlIndex) ResourceControls() []portainer.ResourceControl {
+	return index.resourceControls
+}
+
+// Get retrieves the first matching resource control based on the specified id and resource type parameters.
+func (index *ResourceControlIndex) Get(resourceID string, resourceType portainer.ResourceControlType) *portainer.ResourceControl {
+	match := -1
+
+	if idx, ok := index.byResource[resourceControlKey{resourceID: resourceID, resourceType: resourceType}]; ok {
+		match = idx
+	}
+
+	if idx, ok := index.bySubResource[resourceID]; ok && (match == -1 || idx < match) {
+		match = idx
+	}
+
+	if match == -1 {
+		return nil
+	}
+
+	resourceControl := index.resourceControls[match]
+	return &resourceControl
+}
@@ -0,0 +1,42 @@
+package authorization
+
+import (
+	"testing"
+
+	"github.com/portainer/portainer/api"
+)
+
+func TestResourceControlIndexGet(t *testing.T) {
+	resourceControls := []portainer.ResourceControl{
+		{ID: 1, ResourceID: "service", Type: portainer.ServiceResourceControl, SubResourceIDs: []string{"task1", "task2"}},
+		{ID: 2, ResourceID: "container", Type: portainer.ContainerResourceControl},
+		{ID: 3, ResourceID: "container", Type: portainer.ContainerResourceControl},
+		{ID: 4, ResourceID: "task1", Type: portainer.ContainerResourceControl},
+		{ID: 5, ResourceID: "volume", Type: portainer.VolumeResourceControl, SubResourceIDs: []string{"container"}},
+	}
+
+	index := NewResourceControlIndex(resourceControls)
+
+	cases := []struct {
+		resourceID   string
+		resourceType portainer.ResourceControlType
+	}{
+		{"service", portainer.ServiceResourceControl},
+		{"service", portainer.ContainerResourceControl},
+		{"task1", portainer.ContainerResourceControl},
+		{"task2", portainer.NetworkResourceControl},
+		{"container", portainer.ContainerResourceControl},
+		{"container", portainer.NetworkResourceControl},
+		{"volume", portainer.VolumeResourceControl},
+		{"unknown", portainer.VolumeResourceControl},
+	}
+
+	for _, c := range cases {
+		expected := GetResourceControlByResourceIDAndType(c.resourceID, c.resourceType, resourceControls)
+		result := index.Get(c.resourceID, c.resourceType)
+
+		if (expected == nil) != (result == nil) || (expected != nil && expected.ID != result.ID) {
+			t.Errorf("lookup of %s (type %d) returned %v, expected %v", c.resourceID, c.resourceType, result, expected)
+		}
+	}
+}
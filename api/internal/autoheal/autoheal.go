@@ -0,0 +1,216 @@
+package autoheal
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/docker"
+	"github.com/portainer/portainer/api/internal/maintenance"
+)
+
+const (
+	serviceInterval = 30 * time.Second
+	restartTimeout  = 10 * time.Second
+
+	// DisableLabel excludes a container from all the auto-heal rules when its value is false
+	DisableLabel = "io.portainer.autoheal"
+)
+
+// Service represents a service used to restart the containers stuck in the unhealthy state.
+// The unhealthy containers of the endpoints with an enabled rule are evaluated every 30 seconds,
+// a container is restarted once its failing streak reaches the streak of the rule and is not
+// restarted again before the cooldown of the rule expires.
+type Service struct {
+	dataStore           portainer.DataStore
+	dockerClientFactory *docker.ClientFactory
+	notificationService portainer.NotificationService
+	refreshSignal       chan struct{}
+	mutex               sync.Mutex
+	lastRestarts        map[string]time.Time
+}
+
+// NewService creates a new instance of a service
+func NewService(dataStore portainer.DataStore, dockerClientFactory *docker.ClientFactory, notificationService portainer.NotificationService) *Service {
+	return &Service{
+		dataStore:           dataStore,
+		dockerClientFactory: dockerClientFactory,
+		notificationService: notificationService,
+		lastRestarts:        make(map[string]time.Time),
+	}
+}
+
+// Start will start a background routine to evaluate the auto-heal rules
+func (service *Service) Start() {
+	if service.refreshSignal != nil {
+		return
+	}
+
+	service.refreshSignal = make(chan struct{})
+
+	ticker := time.NewTicker(serviceInterval)
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				err := service.evaluateRules()
+				if err != nil {
+					log.Printf("[ERROR] [internal,autoheal] [message: background schedule error (auto-heal rules).] [error: %s]", err)
+				}
+
+			case <-service.refreshSignal:
+				log.Println("[DEBUG] [internal,autoheal] [message: shutting down auto-heal service]")
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+}
+
+func (service *Service) evaluateRules() error {
+	rules, err := service.dataStore.AutoHealRule().AutoHealRules()
+	if err != nil {
+		return err
+	}
+
+	endpointRules := make(map[portainer.EndpointID][]portainer.AutoHealRule)
+	for _, rule := range rules {
+		if rule.Enabled {
+			endpointRules[rule.EndpointID] = append(endpointRules[rule.EndpointID], rule)
+		}
+	}
+
+	for endpointID, rules := range endpointRules {
+		endpoint, err := service.dataStore.Endpoint().Endpoint(endpointID)
+		if err != nil {
+			log.Printf("[WARN] [internal,autoheal] [message: unable to retrieve endpoint] [endpoint_id: %d] [error: %s]", endpointID, err)
+			continue
+		}
+
+		if !SupportAutoHeal(endpoint) || endpoint.Status == portainer.EndpointStatusDown {
+			continue
+		}
+
+		// the containers restarted during a maintenance are expected to be unhealthy
+		if maintenance.InMaintenance(service.dataStore, endpoint) {
+			continue
+		}
+
+		err = service.healEndpoint(endpoint, rules)
+		if err != nil {
+			log.Printf("[WARN] [internal,autoheal] [message: unable to evaluate auto-heal rules] [endpoint: %s] [error: %s]", endpoint.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// SupportAutoHeal checks whether the containers of an endpoint can be healed. The Edge endpoints are not
+// supported as their Docker API is only reachable while their tunnel is open.
+func SupportAutoHeal(endpoint *portainer.Endpoint) bool {
+	return endpoint.Type == portainer.DockerEnvironment || endpoint.Type == portainer.AgentOnDockerEnvironment
+}
+
+func (service *Service) healEndpoint(endpoint *portainer.Endpoint, rules []portainer.AutoHealRule) error {
+	dockerClient, err := service.dockerClientFactory.CreateClient(endpoint, "")
+	if err != nil {
+		return err
+	}
+	defer dockerClient.Close()
+
+	containers, err := dockerClient.ContainerList(context.Background(), types.ContainerListOptions{
+		Filters: filters.NewArgs(filters.Arg("health", types.Unhealthy)),
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, container := range containers {
+		rule := MatchingRule(rules, container.Labels)
+		if rule == nil {
+			continue
+		}
+
+		containerJSON, err := dockerClient.ContainerInspect(context.Background(), container.ID)
+		if err != nil {
+			log.Printf("[WARN] [internal,autoheal] [message: unable to inspect container] [container: %s] [error: %s]", container.ID, err)
+			continue
+		}
+
+		if containerJSON.State == nil || containerJSON.State.Health == nil || containerJSON.State.Health.FailingStreak < rule.FailingStreak {
+			continue
+		}
+
+		now := time.Now()
+		if !service.shouldRestart(fmt.Sprintf("%d/%s", endpoint.ID, container.ID), now, time.Duration(rule.Cooldown)*time.Second) {
+			continue
+		}
+
+		timeout := restartTimeout
+		err = dockerClient.ContainerRestart(context.Background(), container.ID, &timeout)
+
+		action := &portainer.AutoHealAction{
+			RuleID:        rule.ID,
+			EndpointID:    endpoint.ID,
+			ContainerID:   container.ID,
+			ContainerName: strings.TrimPrefix(containerJSON.Name, "/"),
+			FailingStreak: containerJSON.State.Health.FailingStreak,
+			Time:          now.Unix(),
+			Success:       err == nil,
+		}
+		if err != nil {
+			action.Error = err.Error()
+		}
+
+		service.recordAction(endpoint, rule, action)
+	}
+
+	return nil
+}
+
+func (service *Service) recordAction(endpoint *portainer.Endpoint, rule *portainer.AutoHealRule, action *portainer.AutoHealAction) {
+	log.Printf("[INFO] [internal,autoheal] [message: auto-heal action executed] [rule: %s] [endpoint: %s] [container: %s] [success: %t]", rule.Name, endpoint.Name, action.ContainerName, action.Success)
+
+	err := service.dataStore.AutoHealAction().CreateAutoHealAction(action)
+	if err != nil {
+		log.Printf("[WARN] [internal,autoheal] [message: unable to persist the auto-heal action] [error: %s]", err)
+	}
+
+	if !rule.Notify || service.notificationService == nil {
+		return
+	}
+
+	message := fmt.Sprintf("Container %s on endpoint %s was restarted by the auto-heal rule %s after %d failed health checks", action.ContainerName, endpoint.Name, rule.Name, action.FailingStreak)
+	if !action.Success {
+		message = fmt.Sprintf("Auto-heal rule %s failed to restart the unhealthy container %s on endpoint %s: %s", rule.Name, action.ContainerName, endpoint.Name, action.Error)
+	}
+
+	service.notificationService.Notify(&portainer.Notification{
+		Type:       portainer.ContainerAutoHealedNotification,
+		EndpointID: endpoint.ID,
+		ResourceID: action.ContainerID,
+		Title:      "Container auto-healed",
+		Message:    message,
+		Time:       action.Time,
+	})
+}
+
+// shouldRestart records the restart of the container identified by key and reports whether it can be
+// executed, the same container is restarted at most once per cooldown.
+func (service *Service) shouldRestart(key string, now time.Time, cooldown time.Duration) bool {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	if restartedAt, ok := service.lastRestarts[key]; ok && now.Sub(restartedAt) < cooldown {
+		return false
+	}
+
+	service.lastRestarts[key] = now
+	return true
+}
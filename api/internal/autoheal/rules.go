@@ -0,0 +1,75 @@
+package autoheal
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/docker"
+)
+
+// ValidateRule returns an error if the failing streak, the cooldown or the exclusion labels of a rule are invalid
+func ValidateRule(rule *portainer.AutoHealRule) error {
+	if rule.FailingStreak < 1 {
+		return errors.New("Invalid failing streak. Value must be greater than 0")
+	}
+
+	if rule.Cooldown < 0 {
+		return errors.New("Invalid cooldown. Value must be a positive number of seconds")
+	}
+
+	for _, label := range rule.ExcludeLabels {
+		if strings.SplitN(label, "=", 2)[0] == "" {
+			return errors.New("Invalid exclusion label. Value must use the key or key=value format")
+		}
+	}
+
+	return nil
+}
+
+// MatchingRule returns the first rule applying to a container of the endpoint, nil when the container
+// is not covered by any rule or is excluded by its labels
+func MatchingRule(rules []portainer.AutoHealRule, labels map[string]string) *portainer.AutoHealRule {
+	if labels[DisableLabel] == "false" {
+		return nil
+	}
+
+	stackName := labels[docker.ComposeStackNameLabel]
+	if stackName == "" {
+		stackName = labels[docker.SwarmStackNameLabel]
+	}
+
+	for idx := range rules {
+		rule := &rules[idx]
+
+		if rule.StackName != "" && rule.StackName != stackName {
+			continue
+		}
+
+		if excluded(rule.ExcludeLabels, labels) {
+			continue
+		}
+
+		return rule
+	}
+
+	return nil
+}
+
+// excluded reports whether the labels match one of the exclusion labels written as key or key=value
+func excluded(excludeLabels []string, labels map[string]string) bool {
+	for _, excludeLabel := range excludeLabels {
+		parts := strings.SplitN(excludeLabel, "=", 2)
+
+		value, ok := labels[parts[0]]
+		if !ok {
+			continue
+		}
+
+		if len(parts) == 1 || parts[1] == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,58 @@
+package autoheal
+
+import (
+	"testing"
+
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/docker"
+)
+
+func TestMatchingRule(t *testing.T) {
+	rules := []portainer.AutoHealRule{
+		{ID: 1, StackName: "web", ExcludeLabels: []string{"tier=db"}},
+		{ID: 2, ExcludeLabels: []string{"maintenance"}},
+	}
+
+	tests := []struct {
+		name   string
+		labels map[string]string
+		want   portainer.AutoHealRuleID
+	}{
+		{"compose stack", map[string]string{docker.ComposeStackNameLabel: "web"}, 1},
+		{"swarm stack", map[string]string{docker.SwarmStackNameLabel: "web"}, 1},
+		{"excluded by value falls back to endpoint rule", map[string]string{docker.ComposeStackNameLabel: "web", "tier": "db"}, 2},
+		{"other value not excluded", map[string]string{docker.ComposeStackNameLabel: "web", "tier": "front"}, 1},
+		{"standalone container", map[string]string{}, 2},
+		{"excluded by key", map[string]string{"maintenance": ""}, 0},
+		{"disabled", map[string]string{DisableLabel: "false"}, 0},
+	}
+
+	for _, test := range tests {
+		rule := MatchingRule(rules, test.labels)
+
+		var got portainer.AutoHealRuleID
+		if rule != nil {
+			got = rule.ID
+		}
+
+		if got != test.want {
+			t.Errorf("%s: got rule %d, want %d", test.name, got, test.want)
+		}
+	}
+}
+
+func TestValidateRule(t *testing.T) {
+	if err := ValidateRule(&portainer.AutoHealRule{FailingStreak: 3, Cooldown: 300, ExcludeLabels: []string{"a", "b=c"}}); err != nil {
+		t.Errorf("valid rule rejected: %s", err)
+	}
+
+	for _, rule := range []portainer.AutoHealRule{
+		{FailingStreak: 0},
+		{FailingStreak: 1, Cooldown: -1},
+		{FailingStreak: 1, ExcludeLabels: []string{"=value"}},
+	} {
+		if err := ValidateRule(&rule); err == nil {
+			t.Errorf("invalid rule accepted: %+v", rule)
+		}
+	}
+}
@@ -0,0 +1,528 @@
+package cleanup
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/docker"
+	"github.com/portainer/portainer/api/internal/snapshot"
+)
+
+const cleanupInterval = 24 * time.Hour
+
+var errEndpointNotReachable = errors.New("Endpoint cannot be reached directly")
+
+// Service represents a service used to remove the database entries referencing users, teams
+// or Docker resources that do not exist anymore. It provides an interface to start a periodic
+// background cleanup as well as to trigger a cleanup on demand.
+type Service struct {
+	dataStore           portainer.DataStore
+	dockerClientFactory *docker.ClientFactory
+	refreshSignal       chan struct{}
+}
+
+// NewService creates a new instance of a service
+func NewService(dataStore portainer.DataStore, dockerClientFactory *docker.ClientFactory) *Service {
+	return &Service{
+		dataStore:           dataStore,
+		dockerClientFactory: dockerClientFactory,
+	}
+}
+
+// Start will start a background routine to execute a periodic cleanup
+func (service *Service) Start() {
+	if service.refreshSignal != nil {
+		return
+	}
+
+	service.refreshSignal = make(chan struct{})
+
+	ticker := time.NewTicker(cleanupInterval)
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				report, err := service.Cleanup(false)
+				if err != nil {
+					log.Printf("[ERROR] [internal,cleanup] [message: background schedule error (orphan cleanup).] [error: %s]", err)
+					continue
+				}
+				logReport(report)
+
+			case <-service.refreshSignal:
+				log.Println("[DEBUG] [internal,cleanup] [message: shutting down cleanup service]")
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+}
+
+// Cleanup removes the references to users and teams that do not exist anymore from the resource controls,
+// registries, endpoints and endpoint groups. It also removes the resource controls associated to Docker resources
+// that do not exist anymore. Docker resources are only checked when all the Docker endpoints can be reached, to avoid
+// removing the resource controls of resources that are only temporarily unreachable. The Docker resources of a Swarm
+// cluster that cannot be fully listed through one of the endpoints are not checked.
+// When dryRun is set to true, the report is generated but no change is persisted.
+func (service *Service) Cleanup(dryRun bool) (*portainer.OrphanCleanupReport, error) {
+	report := &portainer.OrphanCleanupReport{
+		DryRun:                  dryRun,
+		RemovedResourceControls: []portainer.ResourceControlID{},
+		UpdatedResourceControls: []portainer.ResourceControlID{},
+		UpdatedRegistries:       []portainer.RegistryID{},
+		UpdatedEndpoints:        []portainer.EndpointID{},
+		UpdatedEndpointGroups:   []portainer.EndpointGroupID{},
+		UnreachableEndpoints:    []portainer.EndpointID{},
+	}
+
+	users, err := service.dataStore.User().Users()
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs := make(map[portainer.UserID]bool)
+	for _, user := range users {
+		userIDs[user.ID] = true
+	}
+
+	teams, err := service.dataStore.Team().Teams()
+	if err != nil {
+		return nil, err
+	}
+
+	teamIDs := make(map[portainer.TeamID]bool)
+	for _, team := range teams {
+		teamIDs[team.ID] = true
+	}
+
+	inventory, err := service.dockerInventory(report)
+	if err != nil {
+		return nil, err
+	}
+	report.DockerResourcesChecked = inventory != nil
+
+	err = service.cleanupResourceControls(report, userIDs, teamIDs, inventory)
+	if err != nil {
+		return nil, err
+	}
+
+	err = service.cleanupRegistries(report, userIDs, teamIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	err = service.cleanupEndpoints(report, userIDs, teamIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	err = service.cleanupEndpointGroups(report, userIDs, teamIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+func (service *Service) cleanupResourceControls(report *portainer.OrphanCleanupReport, userIDs map[portainer.UserID]bool, teamIDs map[portainer.TeamID]bool, inventory *dockerInventory) error {
+	resourceControls, err := service.dataStore.ResourceControl().ResourceControls()
+	if err != nil {
+		return err
+	}
+
+	for idx := range resourceControls {
+		resourceControl := &resourceControls[idx]
+
+		if inventory != nil && inventory.checked(resourceControl.Type) && !inventory.contains(resourceControl.ResourceID, resourceControl.Type) {
+			report.RemovedResourceControls = append(report.RemovedResourceControls, resourceControl.ID)
+			if !report.DryRun {
+				err := service.dataStore.ResourceControl().DeleteResourceControl(resourceControl.ID)
+				if err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		updated := false
+
+		userAccesses := make([]portainer.UserResourceAccess, 0)
+		for _, access := range resourceControl.UserAccesses {
+			if userIDs[access.UserID] {
+				userAccesses = append(userAccesses, access)
+			}
+		}
+		if len(userAccesses) != len(resourceControl.UserAccesses) {
+			resourceControl.UserAccesses = userAccesses
+			updated = true
+		}
+
+		teamAccesses := make([]portainer.TeamResourceAccess, 0)
+		for _, access := range resourceControl.TeamAccesses {
+			if teamIDs[access.TeamID] {
+				teamAccesses = append(teamAccesses, access)
+			}
+		}
+		if len(teamAccesses) != len(resourceControl.TeamAccesses) {
+			resourceControl.TeamAccesses = teamAccesses
+			updated = true
+		}
+
+		if inventory != nil && inventory.checked(portainer.ContainerResourceControl) {
+			subResourceIDs := make([]string, 0)
+			for _, subResourceID := range resourceControl.SubResourceIDs {
+				if inventory.containsAny(subResourceID) {
+					subResourceIDs = append(subResourceIDs, subResourceID)
+				}
+			}
+			if len(subResourceIDs) != len(resourceControl.SubResourceIDs) {
+				resourceControl.SubResourceIDs = subResourceIDs
+				updated = true
+			}
+		}
+
+		if !updated {
+			continue
+		}
+
+		report.UpdatedResourceControls = append(report.UpdatedResourceControls, resourceControl.ID)
+		if !report.DryRun {
+			err := service.dataStore.ResourceControl().UpdateResourceControl(resourceControl.ID, resourceControl)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (service *Service) cleanupRegistries(report *portainer.OrphanCleanupReport, userIDs map[portainer.UserID]bool, teamIDs map[portainer.TeamID]bool) error {
+	registries, err := service.dataStore.Registry().Registries()
+	if err != nil {
+		return err
+	}
+
+	for idx := range registries {
+		registry := &registries[idx]
+
+		if !cleanupAccessPolicies(registry.UserAccessPolicies, registry.TeamAccessPolicies, userIDs, teamIDs) {
+			continue
+		}
+
+		report.UpdatedRegistries = append(report.UpdatedRegistries, registry.ID)
+		if !report.DryRun {
+			err := service.dataStore.Registry().UpdateRegistry(registry.ID, registry)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (service *Service) cleanupEndpoints(report *portainer.OrphanCleanupReport, userIDs map[portainer.UserID]bool, teamIDs map[portainer.TeamID]bool) error {
+	endpoints, err := service.dataStore.Endpoint().Endpoints()
+	if err != nil {
+		return err
+	}
+
+	for idx := range endpoints {
+		endpoint := &endpoints[idx]
+
+		if !cleanupAccessPolicies(endpoint.UserAccessPolicies, endpoint.TeamAccessPolicies, userIDs, teamIDs) {
+			continue
+		}
+
+		report.UpdatedEndpoints = append(report.UpdatedEndpoints, endpoint.ID)
+		if !report.DryRun {
+			// the endpoint is fetched again to avoid overriding a snapshot written in the meantime
+			latestEndpointReference, err := service.dataStore.Endpoint().Endpoint(endpoint.ID)
+			if err != nil {
+				return err
+			}
+
+			latestEndpointReference.UserAccessPolicies = endpoint.UserAccessPolicies
+			latestEndpointReference.TeamAccessPolicies = endpoint.TeamAccessPolicies
+
+			err = service.dataStore.Endpoint().UpdateEndpoint(latestEndpointReference.ID, latestEndpointReference)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (service *Service) cleanupEndpointGroups(report *portainer.OrphanCleanupReport, userIDs map[portainer.UserID]bool, teamIDs map[portainer.TeamID]bool) error {
+	endpointGroups, err := service.dataStore.EndpointGroup().EndpointGroups()
+	if err != nil {
+		return err
+	}
+
+	for idx := range endpointGroups {
+		endpointGroup := &endpointGroups[idx]
+
+		if !cleanupAccessPolicies(endpointGroup.UserAccessPolicies, endpointGroup.TeamAccessPolicies, userIDs, teamIDs) {
+			continue
+		}
+
+		report.UpdatedEndpointGroups = append(report.UpdatedEndpointGroups, endpointGroup.ID)
+		if !report.DryRun {
+			err := service.dataStore.EndpointGroup().UpdateEndpointGroup(endpointGroup.ID, endpointGroup)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// cleanupAccessPolicies removes the policies associated to users and teams that do not exist anymore.
+// It returns true if at least one policy was removed.
+func cleanupAccessPolicies(userPolicies portainer.UserAccessPolicies, teamPolicies portainer.TeamAccessPolicies, userIDs map[portainer.UserID]bool, teamIDs map[portainer.TeamID]bool) bool {
+	updated := false
+
+	for userID := range userPolicies {
+		if !userIDs[userID] {
+			delete(userPolicies, userID)
+			updated = true
+		}
+	}
+
+	for teamID := range teamPolicies {
+		if !teamIDs[teamID] {
+			delete(teamPolicies, teamID)
+			updated = true
+		}
+	}
+
+	return updated
+}
+
+// dockerResourceTypes are the types of the resource controls associated to Docker resources,
+// the other resource controls are never removed by the cleanup
+var dockerResourceTypes = map[portainer.ResourceControlType]bool{
+	portainer.ContainerResourceControl: true,
+	portainer.ServiceResourceControl:   true,
+	portainer.VolumeResourceControl:    true,
+	portainer.NetworkResourceControl:   true,
+	portainer.SecretResourceControl:    true,
+	portainer.StackResourceControl:     true,
+	portainer.ConfigResourceControl:    true,
+}
+
+// dockerInventory represents the identifiers of all the Docker resources available
+// across the Docker endpoints, indexed by resource control type.
+// The types listed in incomplete could not be fully listed and are not checked.
+type dockerInventory struct {
+	resources  map[portainer.ResourceControlType]map[string]bool
+	incomplete map[portainer.ResourceControlType]bool
+}
+
+func (inventory *dockerInventory) add(resourceType portainer.ResourceControlType, resourceID string) {
+	if inventory.resources[resourceType] == nil {
+		inventory.resources[resourceType] = make(map[string]bool)
+	}
+	inventory.resources[resourceType][resourceID] = true
+}
+
+// checked returns true when the resources of the specified type were fully listed
+func (inventory *dockerInventory) checked(resourceType portainer.ResourceControlType) bool {
+	return dockerResourceTypes[resourceType] && !inventory.incomplete[resourceType]
+}
+
+func (inventory *dockerInventory) contains(resourceID string, resourceType portainer.ResourceControlType) bool {
+	return inventory.resources[resourceType][resourceID]
+}
+
+func (inventory *dockerInventory) containsAny(resourceID string) bool {
+	for _, resources := range inventory.resources {
+		if resources[resourceID] {
+			return true
+		}
+	}
+	return false
+}
+
+// dockerInventory lists the Docker resources of all the Docker endpoints. It returns nil
+// if at least one of the Docker endpoints cannot be reached.
+func (service *Service) dockerInventory(report *portainer.OrphanCleanupReport) (*dockerInventory, error) {
+	inventory := &dockerInventory{
+		resources:  make(map[portainer.ResourceControlType]map[string]bool),
+		incomplete: make(map[portainer.ResourceControlType]bool),
+	}
+
+	stacks, err := service.dataStore.Stack().Stacks()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, stack := range stacks {
+		inventory.add(portainer.StackResourceControl, stack.Name)
+	}
+
+	endpoints, err := service.dataStore.Endpoint().Endpoints()
+	if err != nil {
+		return nil, err
+	}
+
+	for idx := range endpoints {
+		endpoint := &endpoints[idx]
+
+		switch endpoint.Type {
+		case portainer.DockerEnvironment, portainer.AgentOnDockerEnvironment, portainer.EdgeAgentOnDockerEnvironment:
+		default:
+			continue
+		}
+
+		err := service.inventoryEndpoint(endpoint, inventory)
+		if err != nil {
+			log.Printf("[WARN] [internal,cleanup] [message: unable to list Docker resources, skipping Docker resources cleanup] [endpoint: %s] [error: %s]", endpoint.Name, err)
+			report.UnreachableEndpoints = append(report.UnreachableEndpoints, endpoint.ID)
+		}
+	}
+
+	if len(report.UnreachableEndpoints) > 0 {
+		return nil, nil
+	}
+
+	return inventory, nil
+}
+
+func (service *Service) inventoryEndpoint(endpoint *portainer.Endpoint, inventory *dockerInventory) error {
+	if !snapshot.SupportDirectSnapshot(endpoint) {
+		return errEndpointNotReachable
+	}
+
+	cli, err := service.dockerClientFactory.CreateClient(endpoint, "")
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	info, err := cli.Info(context.Background())
+	if err != nil {
+		return err
+	}
+
+	incomplete := incompleteResourceTypes(endpoint.Type, info)
+	for resourceType := range incomplete {
+		inventory.incomplete[resourceType] = true
+	}
+
+	if !incomplete[portainer.ContainerResourceControl] {
+		containers, err := cli.ContainerList(context.Background(), types.ContainerListOptions{All: true})
+		if err != nil {
+			return err
+		}
+
+		for _, container := range containers {
+			inventory.add(portainer.ContainerResourceControl, container.ID)
+			addStackLabels(inventory, container.Labels)
+		}
+	}
+
+	networks, err := cli.NetworkList(context.Background(), types.NetworkListOptions{})
+	if err != nil {
+		return err
+	}
+
+	for _, network := range networks {
+		inventory.add(portainer.NetworkResourceControl, network.ID)
+	}
+
+	volumes, err := cli.VolumeList(context.Background(), filters.NewArgs())
+	if err != nil {
+		return err
+	}
+
+	for _, volume := range volumes.Volumes {
+		inventory.add(portainer.VolumeResourceControl, volume.Name+volume.CreatedAt)
+	}
+
+	if info.Swarm.ControlAvailable {
+		return inventorySwarm(cli, inventory)
+	}
+
+	return nil
+}
+
+// incompleteResourceTypes returns the types of the resources of a Swarm cluster that cannot be fully
+// listed through an endpoint. Without an agent, only the containers of the node Portainer is connected
+// to are listed. The services, configs and secrets can only be listed on a manager node.
+func incompleteResourceTypes(endpointType portainer.EndpointType, info types.Info) map[portainer.ResourceControlType]bool {
+	incomplete := make(map[portainer.ResourceControlType]bool)
+	if info.Swarm.NodeID == "" {
+		return incomplete
+	}
+
+	if endpointType == portainer.DockerEnvironment {
+		incomplete[portainer.ContainerResourceControl] = true
+		incomplete[portainer.StackResourceControl] = true
+	}
+
+	if !info.Swarm.ControlAvailable {
+		incomplete[portainer.ServiceResourceControl] = true
+		incomplete[portainer.ConfigResourceControl] = true
+		incomplete[portainer.SecretResourceControl] = true
+		incomplete[portainer.StackResourceControl] = true
+	}
+
+	return incomplete
+}
+
+func inventorySwarm(cli *client.Client, inventory *dockerInventory) error {
+	services, err := cli.ServiceList(context.Background(), types.ServiceListOptions{})
+	if err != nil {
+		return err
+	}
+
+	for _, service := range services {
+		inventory.add(portainer.ServiceResourceControl, service.ID)
+		addStackLabels(inventory, service.Spec.Labels)
+	}
+
+	configs, err := cli.ConfigList(context.Background(), types.ConfigListOptions{})
+	if err != nil {
+		return err
+	}
+
+	for _, config := range configs {
+		inventory.add(portainer.ConfigResourceControl, config.ID)
+	}
+
+	secrets, err := cli.SecretList(context.Background(), types.SecretListOptions{})
+	if err != nil {
+		return err
+	}
+
+	for _, secret := range secrets {
+		inventory.add(portainer.SecretResourceControl, secret.ID)
+	}
+
+	return nil
+}
+
+func addStackLabels(inventory *dockerInventory, labels map[string]string) {
+	if stackName, ok := labels[docker.SwarmStackNameLabel]; ok {
+		inventory.add(portainer.StackResourceControl, stackName)
+	}
+	if stackName, ok := labels[docker.ComposeStackNameLabel]; ok {
+		inventory.add(portainer.StackResourceControl, stackName)
+	}
+}
+
+func logReport(report *portainer.OrphanCleanupReport) {
+	log.Printf("[INFO] [internal,cleanup] [message: orphan cleanup completed] [removed_resource_controls: %d] [updated_resource_controls: %d] [updated_registries: %d] [updated_endpoints: %d] [updated_endpoint_groups: %d] [docker_resources_checked: %t]",
+		len(report.RemovedResourceControls), len(report.UpdatedResourceControls), len(report.UpdatedRegistries), len(report.UpdatedEndpoints), len(report.UpdatedEndpointGroups), report.DockerResourcesChecked)
+}
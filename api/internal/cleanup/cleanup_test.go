@@ -0,0 +1,39 @@
+package cleanup
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/portainer/portainer/api"
+)
+
+func TestIncompleteResourceTypes(t *testing.T) {
+	manager := types.Info{Swarm: swarm.Info{NodeID: "manager", ControlAvailable: true}}
+	worker := types.Info{Swarm: swarm.Info{NodeID: "worker"}}
+
+	cases := []struct {
+		name         string
+		endpointType portainer.EndpointType
+		info         types.Info
+		expected     []portainer.ResourceControlType
+	}{
+		{"standalone", portainer.DockerEnvironment, types.Info{}, nil},
+		{"agent on a manager", portainer.AgentOnDockerEnvironment, manager, nil},
+		{"direct connection to a manager", portainer.DockerEnvironment, manager, []portainer.ResourceControlType{portainer.ContainerResourceControl, portainer.StackResourceControl}},
+		{"agent on a worker", portainer.AgentOnDockerEnvironment, worker, []portainer.ResourceControlType{portainer.ServiceResourceControl, portainer.ConfigResourceControl, portainer.SecretResourceControl, portainer.StackResourceControl}},
+		{"direct connection to a worker", portainer.DockerEnvironment, worker, []portainer.ResourceControlType{portainer.ContainerResourceControl, portainer.ServiceResourceControl, portainer.ConfigResourceControl, portainer.SecretResourceControl, portainer.StackResourceControl}},
+	}
+
+	for _, c := range cases {
+		incomplete := incompleteResourceTypes(c.endpointType, c.info)
+		if len(incomplete) != len(c.expected) {
+			t.Errorf("%s: expected %d incomplete resource types, got %d", c.name, len(c.expected), len(incomplete))
+		}
+		for _, resourceType := range c.expected {
+			if !incomplete[resourceType] {
+				t.Errorf("%s: expected resource type %d to be incomplete", c.name, resourceType)
+			}
+		}
+	}
+}
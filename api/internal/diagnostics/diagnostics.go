@@ -0,0 +1,219 @@
+package diagnostics
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"io"
+	"runtime"
+	"runtime/pprof"
+	"strings"
+	"time"
+
+	"github.com/portainer/portainer/api"
+)
+
+type (
+	versionInfo struct {
+		Version    string
+		DBVersion  int
+		InstanceID string
+		GoVersion  string
+		OS         string
+		Arch       string
+		StartTime  int64
+		Goroutines int
+		Time       int64
+	}
+
+	endpointSummary struct {
+		ID            portainer.EndpointID
+		Name          string
+		Type          portainer.EndpointType
+		Status        portainer.EndpointStatus
+		LastSnapshot  int64  `json:",omitempty"`
+		DockerVersion string `json:",omitempty"`
+		Swarm         bool   `json:",omitempty"`
+		TunnelStatus  string `json:",omitempty"`
+		EdgeCheckedIn bool   `json:",omitempty"`
+		NodeCount     int    `json:",omitempty"`
+	}
+)
+
+// Service represents a service used to build the support bundles
+type Service struct {
+	dataStore            portainer.DataStore
+	reverseTunnelService portainer.ReverseTunnelService
+	logBuffer            *LogBuffer
+	startTime            time.Time
+}
+
+// NewService returns a new instance of a diagnostics service
+func NewService(dataStore portainer.DataStore, reverseTunnelService portainer.ReverseTunnelService, logBuffer *LogBuffer) *Service {
+	return &Service{
+		dataStore:            dataStore,
+		reverseTunnelService: reverseTunnelService,
+		logBuffer:            logBuffer,
+		startTime:            time.Now(),
+	}
+}
+
+// WriteBundle writes a zip archive containing the version information, the settings without
+// any credential, a summary of the endpoint reachability, the recent logs, the goroutine and heap
+// profiles and the database statistics.
+func (service *Service) WriteBundle(w io.Writer) error {
+	archive := zip.NewWriter(w)
+
+	version, err := service.versionInfo()
+	if err != nil {
+		return err
+	}
+	err = writeJSON(archive, "version.json", version)
+	if err != nil {
+		return err
+	}
+
+	settings, err := service.dataStore.Settings().Settings()
+	if err != nil {
+		return err
+	}
+	err = writeJSON(archive, "settings.json", sanitizeSettings(settings))
+	if err != nil {
+		return err
+	}
+
+	endpoints, err := service.endpointSummaries()
+	if err != nil {
+		return err
+	}
+	err = writeJSON(archive, "endpoints.json", endpoints)
+	if err != nil {
+		return err
+	}
+
+	statistics, err := service.dataStore.Statistics()
+	if err != nil {
+		return err
+	}
+	err = writeJSON(archive, "database.json", statistics)
+	if err != nil {
+		return err
+	}
+
+	err = writeFile(archive, "portainer.log", func(w io.Writer) error {
+		_, err := io.WriteString(w, strings.Join(service.logBuffer.Lines(), "\n"))
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	err = writeFile(archive, "goroutine.txt", func(w io.Writer) error {
+		return pprof.Lookup("goroutine").WriteTo(w, 2)
+	})
+	if err != nil {
+		return err
+	}
+
+	err = writeFile(archive, "heap.pprof", func(w io.Writer) error {
+		return pprof.Lookup("heap").WriteTo(w, 0)
+	})
+	if err != nil {
+		return err
+	}
+
+	return archive.Close()
+}
+
+func (service *Service) versionInfo() (*versionInfo, error) {
+	dbVersion, err := service.dataStore.Version().DBVersion()
+	if err != nil {
+		return nil, err
+	}
+
+	instanceID, err := service.dataStore.Version().InstanceID()
+	if err != nil {
+		return nil, err
+	}
+
+	return &versionInfo{
+		Version:    portainer.APIVersion,
+		DBVersion:  dbVersion,
+		InstanceID: instanceID,
+		GoVersion:  runtime.Version(),
+		OS:         runtime.GOOS,
+		Arch:       runtime.GOARCH,
+		StartTime:  service.startTime.Unix(),
+		Goroutines: runtime.NumGoroutine(),
+		Time:       time.Now().Unix(),
+	}, nil
+}
+
+func (service *Service) endpointSummaries() ([]endpointSummary, error) {
+	endpoints, err := service.dataStore.Endpoint().Endpoints()
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]endpointSummary, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		summary := endpointSummary{
+			ID:     endpoint.ID,
+			Name:   endpoint.Name,
+			Type:   endpoint.Type,
+			Status: endpoint.Status,
+		}
+
+		if len(endpoint.Snapshots) > 0 {
+			snapshot := endpoint.Snapshots[0]
+			summary.LastSnapshot = snapshot.Time
+			summary.DockerVersion = snapshot.DockerVersion
+			summary.Swarm = snapshot.Swarm
+		}
+
+		if len(endpoint.Kubernetes.Snapshots) > 0 {
+			summary.LastSnapshot = endpoint.Kubernetes.Snapshots[0].Time
+			summary.NodeCount = endpoint.Kubernetes.Snapshots[0].NodeCount
+		}
+
+		if endpoint.Type == portainer.EdgeAgentOnDockerEnvironment || endpoint.Type == portainer.EdgeAgentOnKubernetesEnvironment {
+			summary.EdgeCheckedIn = endpoint.EdgeID != ""
+			summary.TunnelStatus = service.reverseTunnelService.GetTunnelDetails(endpoint.ID).Status
+		}
+
+		summaries = append(summaries, summary)
+	}
+
+	return summaries, nil
+}
+
+// sanitizeSettings returns a copy of the settings without any credential
+func sanitizeSettings(settings *portainer.Settings) *portainer.Settings {
+	sanitized := *settings
+	sanitized.LDAPSettings.Password = ""
+	sanitized.OAuthSettings.ClientSecret = ""
+	sanitized.SMTPSettings.Password = ""
+	return &sanitized
+}
+
+func writeJSON(archive *zip.Writer, name string, object interface{}) error {
+	return writeFile(archive, name, func(w io.Writer) error {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(object)
+	})
+}
+
+func writeFile(archive *zip.Writer, name string, write func(w io.Writer) error) error {
+	header := &zip.FileHeader{
+		Name:   name,
+		Method: zip.Deflate,
+	}
+	header.Modified = time.Now()
+
+	w, err := archive.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+
+	return write(w)
+}
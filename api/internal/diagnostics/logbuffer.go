@@ -0,0 +1,59 @@
+package diagnostics
+
+import (
+	"bytes"
+	"sync"
+)
+
+// LogBuffer retains the most recent lines written to the application logs so that they can be
+// included in the support bundles. It is used as an additional output of the standard logger.
+type LogBuffer struct {
+	mu      sync.Mutex
+	lines   []string
+	next    int
+	full    bool
+	partial bytes.Buffer
+}
+
+// NewLogBuffer returns a buffer retaining the specified number of lines
+func NewLogBuffer(size int) *LogBuffer {
+	return &LogBuffer{
+		lines: make([]string, size),
+	}
+}
+
+// Write implements io.Writer
+func (buffer *LogBuffer) Write(data []byte) (int, error) {
+	buffer.mu.Lock()
+	defer buffer.mu.Unlock()
+
+	for _, b := range data {
+		if b != '\n' {
+			buffer.partial.WriteByte(b)
+			continue
+		}
+
+		buffer.lines[buffer.next] = buffer.partial.String()
+		buffer.partial.Reset()
+		buffer.next = (buffer.next + 1) % len(buffer.lines)
+		if buffer.next == 0 {
+			buffer.full = true
+		}
+	}
+
+	return len(data), nil
+}
+
+// Lines returns the retained lines, oldest first
+func (buffer *LogBuffer) Lines() []string {
+	buffer.mu.Lock()
+	defer buffer.mu.Unlock()
+
+	if !buffer.full {
+		return append([]string(nil), buffer.lines[:buffer.next]...)
+	}
+
+	lines := make([]string, 0, len(buffer.lines))
+	lines = append(lines, buffer.lines[buffer.next:]...)
+	return append(lines, buffer.lines[:buffer.next]...)
+}
@@ -0,0 +1,413 @@
+package dockercontext
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/docker"
+)
+
+const (
+	metaFile          = "meta.json"
+	dockerEndpoint    = "docker"
+	maxArchiveEntries = 64
+)
+
+var (
+	errMissingDockerEndpoint = errors.New("The context does not define a Docker endpoint")
+	errMissingSSHKey         = errors.New("A private key is required to import a context using SSH")
+	errMissingMetadata       = errors.New("The archive does not contain a meta.json file")
+)
+
+type (
+	// Context represents a Docker CLI context, as stored by the docker context command
+	Context struct {
+		Name          string
+		Description   string
+		Host          string
+		SkipTLSVerify bool
+		TLSCACert     []byte
+		TLSCert       []byte
+		TLSKey        []byte
+	}
+
+	contextMetadata struct {
+		Name     string
+		Metadata struct {
+			Description string
+		}
+		Endpoints map[string]struct {
+			Host          string
+			SkipTLSVerify bool
+		}
+	}
+)
+
+// TLS returns true when the Docker CLI uses TLS to reach the Docker endpoint of the context
+func (context *Context) TLS() bool {
+	return context.SkipTLSVerify || len(context.TLSCACert) > 0 || len(context.TLSCert) > 0
+}
+
+// LoadDirectory loads the contexts stored in a Docker CLI configuration directory (~/.docker)
+// or in its contexts sub-directory. Contexts that do not define a Docker endpoint are ignored.
+func LoadDirectory(directory string) ([]Context, error) {
+	if _, err := os.Stat(filepath.Join(directory, "contexts", "meta")); err == nil {
+		directory = filepath.Join(directory, "contexts")
+	}
+
+	metaDirectories, err := ioutil.ReadDir(filepath.Join(directory, "meta"))
+	if err != nil {
+		return nil, err
+	}
+
+	contexts := make([]Context, 0)
+	for _, metaDirectory := range metaDirectories {
+		if !metaDirectory.IsDir() {
+			continue
+		}
+
+		data, err := ioutil.ReadFile(filepath.Join(directory, "meta", metaDirectory.Name(), metaFile))
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		context, err := parseMetadata(data)
+		if err == errMissingDockerEndpoint {
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("Invalid context %s: %s", metaDirectory.Name(), err)
+		}
+
+		tlsDirectory := filepath.Join(directory, "tls", metaDirectory.Name(), dockerEndpoint)
+		context.TLSCACert, context.TLSCert, context.TLSKey, err = readTLSFiles(func(name string) ([]byte, error) {
+			return ioutil.ReadFile(filepath.Join(tlsDirectory, name))
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		contexts = append(contexts, *context)
+	}
+
+	return contexts, nil
+}
+
+// LoadArchive loads a context exported with the docker context export command, the archive can be gzip compressed
+func LoadArchive(data []byte) (*Context, error) {
+	var reader io.Reader = bytes.NewReader(data)
+	if bytes.HasPrefix(data, []byte{0x1f, 0x8b}) {
+		gzipReader, err := gzip.NewReader(reader)
+		if err != nil {
+			return nil, err
+		}
+		defer gzipReader.Close()
+		reader = gzipReader
+	}
+
+	files := make(map[string][]byte)
+	tarReader := tar.NewReader(reader)
+	for len(files) < maxArchiveEntries {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		content, err := ioutil.ReadAll(tarReader)
+		if err != nil {
+			return nil, err
+		}
+		files[path.Clean(strings.TrimPrefix(header.Name, "./"))] = content
+	}
+
+	metadata, ok := files[metaFile]
+	if !ok {
+		return nil, errMissingMetadata
+	}
+
+	context, err := parseMetadata(metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	context.TLSCACert, context.TLSCert, context.TLSKey, err = readTLSFiles(func(name string) ([]byte, error) {
+		content, ok := files[path.Join("tls", dockerEndpoint, name)]
+		if !ok {
+			return nil, os.ErrNotExist
+		}
+		return content, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return context, nil
+}
+
+func parseMetadata(data []byte) (*Context, error) {
+	var metadata contextMetadata
+	err := json.Unmarshal(data, &metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint, ok := metadata.Endpoints[dockerEndpoint]
+	if !ok || endpoint.Host == "" {
+		return nil, errMissingDockerEndpoint
+	}
+
+	return &Context{
+		Name:          metadata.Name,
+		Description:   metadata.Metadata.Description,
+		Host:          endpoint.Host,
+		SkipTLSVerify: endpoint.SkipTLSVerify,
+	}, nil
+}
+
+func readTLSFiles(readFile func(name string) ([]byte, error)) ([]byte, []byte, []byte, error) {
+	files := make([][]byte, 3)
+	for idx, name := range []string{"ca.pem", "cert.pem", "key.pem"} {
+		content, err := readFile(name)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, nil, nil, err
+		}
+		files[idx] = content
+	}
+
+	if (len(files[1]) > 0) != (len(files[2]) > 0) {
+		return nil, nil, nil, errors.New("The TLS certificate and key must be defined together")
+	}
+
+	return files[0], files[1], files[2], nil
+}
+
+// NewEndpoint creates a Docker endpoint reaching the Docker endpoint of a context. The TLS files of the context
+// and the SSH private key are stored on disk, the endpoint is neither snapshotted nor persisted.
+// The sshPrivateKey parameter is required for contexts using SSH, as the Docker CLI relies on the SSH configuration
+// of the user which is not part of the context.
+func NewEndpoint(context *Context, endpointID int, sshPrivateKey []byte, fileService portainer.FileService) (*portainer.Endpoint, error) {
+	endpoint := &portainer.Endpoint{
+		ID:                 portainer.EndpointID(endpointID),
+		Name:               context.Name,
+		URL:                context.Host,
+		Type:               portainer.DockerEnvironment,
+		GroupID:            portainer.EndpointGroupID(1),
+		UserAccessPolicies: portainer.UserAccessPolicies{},
+		TeamAccessPolicies: portainer.TeamAccessPolicies{},
+		Extensions:         []portainer.EndpointExtension{},
+		TagIDs:             []portainer.TagID{},
+		Status:             portainer.EndpointStatusUp,
+		Snapshots:          []portainer.DockerSnapshot{},
+		Kubernetes:         portainer.KubernetesDefault(),
+	}
+
+	folder := strconv.Itoa(endpointID)
+
+	switch {
+	case strings.HasPrefix(context.Host, "ssh://"):
+		if len(sshPrivateKey) == 0 {
+			return nil, errMissingSSHKey
+		}
+
+		_, _, err := docker.ParseSSHURL(context.Host)
+		if err != nil {
+			return nil, err
+		}
+
+		hostKey, err := docker.ValidateSSHConnection(context.Host, sshPrivateKey)
+		if err != nil {
+			return nil, err
+		}
+
+		privateKeyPath, err := fileService.StoreSSHKeyFileFromBytes(folder, sshPrivateKey)
+		if err != nil {
+			return nil, err
+		}
+
+		endpoint.SSHConfig = &portainer.SSHConfiguration{
+			PrivateKeyPath: privateKeyPath,
+			HostKey:        hostKey,
+			SocketPath:     docker.DefaultSSHSocketPath,
+		}
+
+	case context.TLS():
+		endpoint.TLSConfig = portainer.TLSConfiguration{
+			TLS:           true,
+			TLSSkipVerify: context.SkipTLSVerify,
+		}
+
+		files := []struct {
+			fileType portainer.TLSFileType
+			content  []byte
+			path     *string
+		}{
+			{portainer.TLSFileCA, context.TLSCACert, &endpoint.TLSConfig.TLSCACertPath},
+			{portainer.TLSFileCert, context.TLSCert, &endpoint.TLSConfig.TLSCertPath},
+			{portainer.TLSFileKey, context.TLSKey, &endpoint.TLSConfig.TLSKeyPath},
+		}
+
+		for _, file := range files {
+			if len(file.content) == 0 || (file.fileType == portainer.TLSFileCA && context.SkipTLSVerify) {
+				continue
+			}
+
+			filePath, err := fileService.StoreTLSFileFromBytes(folder, file.fileType, file.content)
+			if err != nil {
+				return nil, err
+			}
+			*file.path = filePath
+		}
+	}
+
+	return endpoint, nil
+}
+
+// DeleteEndpointFiles removes the files stored by NewEndpoint, it is used when the endpoint cannot be persisted
+func DeleteEndpointFiles(endpoint *portainer.Endpoint, fileService portainer.FileService) {
+	folder := strconv.Itoa(int(endpoint.ID))
+	if endpoint.SSHConfig != nil {
+		fileService.DeleteSSHKeyFile(folder)
+	}
+	if endpoint.TLSConfig.TLS {
+		fileService.DeleteTLSFiles(folder)
+	}
+}
+
+var invalidNameCharacters = regexp.MustCompile(`[^a-zA-Z0-9_.+-]`)
+
+// NewContext creates the context reaching a Docker endpoint that is directly accessible by the Docker CLI,
+// the TLS files of the endpoint are embedded in the context.
+func NewContext(endpoint *portainer.Endpoint, fileService portainer.FileService) (*Context, error) {
+	if endpoint.Type != portainer.DockerEnvironment {
+		return nil, errors.New("Only the Docker endpoints reached without an agent can be exported as a Docker context")
+	}
+
+	if !strings.HasPrefix(endpoint.URL, "tcp://") && !strings.HasPrefix(endpoint.URL, "ssh://") {
+		return nil, errors.New("The endpoint is local to the Portainer host and cannot be exported as a Docker context")
+	}
+
+	name := strings.Trim(invalidNameCharacters.ReplaceAllString(endpoint.Name, "-"), "-._+")
+	if name == "" {
+		name = fmt.Sprintf("portainer-endpoint-%d", endpoint.ID)
+	}
+
+	context := &Context{
+		Name:        name,
+		Description: fmt.Sprintf("Portainer endpoint %s", endpoint.Name),
+		Host:        endpoint.URL,
+	}
+
+	if !endpoint.TLSConfig.TLS {
+		return context, nil
+	}
+
+	context.SkipTLSVerify = endpoint.TLSConfig.TLSSkipVerify
+
+	files := []struct {
+		path    string
+		content *[]byte
+	}{
+		{endpoint.TLSConfig.TLSCACertPath, &context.TLSCACert},
+		{endpoint.TLSConfig.TLSCertPath, &context.TLSCert},
+		{endpoint.TLSConfig.TLSKeyPath, &context.TLSKey},
+	}
+
+	for _, file := range files {
+		if file.path == "" {
+			continue
+		}
+
+		content, err := fileService.GetFileContent(file.path)
+		if err != nil {
+			return nil, err
+		}
+		*file.content = content
+	}
+
+	return context, nil
+}
+
+// WriteArchive writes a context using the format of the docker context export command,
+// the archive can be imported with the docker context import command.
+func WriteArchive(w io.Writer, context *Context) error {
+	metadata := contextMetadata{
+		Name: context.Name,
+		Endpoints: map[string]struct {
+			Host          string
+			SkipTLSVerify bool
+		}{
+			dockerEndpoint: {Host: context.Host, SkipTLSVerify: context.SkipTLSVerify},
+		},
+	}
+	metadata.Metadata.Description = context.Description
+
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return err
+	}
+
+	tarWriter := tar.NewWriter(w)
+	err = writeArchiveFile(tarWriter, metaFile, data)
+	if err != nil {
+		return err
+	}
+
+	tlsFiles := []struct {
+		name    string
+		content []byte
+	}{
+		{"ca.pem", context.TLSCACert},
+		{"cert.pem", context.TLSCert},
+		{"key.pem", context.TLSKey},
+	}
+
+	for _, file := range tlsFiles {
+		if len(file.content) == 0 {
+			continue
+		}
+
+		err = writeArchiveFile(tarWriter, path.Join("tls", dockerEndpoint, file.name), file.content)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tarWriter.Close()
+}
+
+func writeArchiveFile(tarWriter *tar.Writer, name string, content []byte) error {
+	err := tarWriter.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Mode:     0600,
+		Size:     int64(len(content)),
+		ModTime:  time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = tarWriter.Write(content)
+	return err
+}
@@ -0,0 +1,29 @@
+package edge
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+
+	"github.com/portainer/portainer/api"
+)
+
+// BundleVersion returns the version of the bundle of the Edge stacks deployed on an endpoint. The version
+// changes when a stack is added to or removed from the endpoint or when the version deployed on the
+// endpoint changes, so that the agents only download the bundle when their cached copy is outdated.
+func BundleVersion(stacks []portainer.EdgeStack, endpointID portainer.EndpointID) string {
+	entries := make([]string, 0, len(stacks))
+	for idx := range stacks {
+		version, _ := EdgeStackEndpointVersion(&stacks[idx], endpointID)
+		entries = append(entries, fmt.Sprintf("%d:%d:%t", stacks[idx].ID, version, stacks[idx].Prune))
+	}
+	sort.Strings(entries)
+
+	hash := sha256.New()
+	for _, entry := range entries {
+		hash.Write([]byte(entry + "\n"))
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))[:16]
+}
@@ -44,6 +44,10 @@ func edgeGroupRelatedToEndpoint(edgeGroup *portainer.EdgeGroup, endpoint *portai
 		return false
 	}
 
+	if edgeGroup.Expression != "" {
+		return tag.ExpressionMatches(edgeGroup.Expression, endpoint.Metadata)
+	}
+
 	endpointTags := tag.Set(endpoint.TagIDs)
 	if endpointGroup.TagIDs != nil {
 		endpointTags = tag.Union(endpointTags, tag.Set(endpointGroup.TagIDs))
@@ -52,8 +56,8 @@ func edgeGroupRelatedToEndpoint(edgeGroup *portainer.EdgeGroup, endpoint *portai
 
 	if edgeGroup.PartialMatch {
 		intersection := tag.Intersection(endpointTags, edgeGroupTags)
-		return len(intersection) != 0
+		return len(intersection) != 0 || tag.MetadataMatches(endpoint.Metadata, edgeGroup.Metadata) != 0
 	}
 
-	return tag.Contains(edgeGroupTags, endpointTags)
+	return tag.Contains(edgeGroupTags, endpointTags) && tag.MetadataContains(endpoint.Metadata, edgeGroup.Metadata)
 }
@@ -0,0 +1,50 @@
+package edge
+
+import "github.com/portainer/portainer/api"
+
+// RolloutActive returns true while the endpoints of an Edge stack run different versions of the stack
+func RolloutActive(rollout *portainer.EdgeStackRollout) bool {
+	return rollout != nil && (rollout.Status == portainer.EdgeStackRolloutInProgress || rollout.Status == portainer.EdgeStackRolloutPaused)
+}
+
+// EdgeStackEndpointVersion returns the version and the entry point of the Edge stack deployed on an endpoint.
+// During a rollout, the endpoints outside of the deployed batches receive the previous version of the stack.
+func EdgeStackEndpointVersion(stack *portainer.EdgeStack, endpointID portainer.EndpointID) (int, string) {
+	rollout := stack.Rollout
+	if !RolloutActive(rollout) {
+		return stack.Version, stack.EntryPoint
+	}
+
+	for _, deployedEndpointID := range rollout.Endpoints[:rollout.Deployed] {
+		if deployedEndpointID == endpointID {
+			return stack.Version, stack.EntryPoint
+		}
+	}
+
+	return rollout.PreviousVersion, rollout.PreviousEntryPoint
+}
+
+// RolloutBatch returns the endpoints of the current batch of a rollout
+func RolloutBatch(rollout *portainer.EdgeStackRollout) []portainer.EndpointID {
+	return rollout.Endpoints[rollout.BatchStart:rollout.Deployed]
+}
+
+// RolloutBatchStatus returns the number of endpoints of the current batch which reported a successful
+// deployment and the number of endpoints which reported an error
+func RolloutBatchStatus(stack *portainer.EdgeStack) (succeeded, failed int) {
+	for _, endpointID := range RolloutBatch(stack.Rollout) {
+		status, ok := stack.Status[endpointID]
+		if !ok {
+			continue
+		}
+
+		switch status.Type {
+		case portainer.StatusOk:
+			succeeded++
+		case portainer.StatusError:
+			failed++
+		}
+	}
+
+	return succeeded, failed
+}
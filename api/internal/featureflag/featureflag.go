@@ -0,0 +1,89 @@
+package featureflag
+
+import (
+	"errors"
+	"log"
+	"regexp"
+	"sort"
+
+	"github.com/portainer/portainer/api"
+)
+
+var flagNamePattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// ErrInvalidFeatureFlag is returned when a feature flag name is not valid
+var ErrInvalidFeatureFlag = errors.New("Invalid feature flag name. Names must only contain lowercase alphanumeric characters separated by dashes")
+
+// Service represents a service used to check whether experimental features are enabled.
+// A feature is enabled when it is enabled in the settings or when it was enabled
+// on the command line. Features enabled on the command line cannot be disabled at runtime.
+type Service struct {
+	dataStore    portainer.DataStore
+	enabledByCLI map[portainer.FeatureFlag]bool
+}
+
+// NewService returns a new instance of a feature flag service
+func NewService(dataStore portainer.DataStore, cliFlags []string) *Service {
+	enabledByCLI := make(map[portainer.FeatureFlag]bool)
+	for _, flag := range cliFlags {
+		enabledByCLI[portainer.FeatureFlag(flag)] = true
+	}
+
+	return &Service{
+		dataStore:    dataStore,
+		enabledByCLI: enabledByCLI,
+	}
+}
+
+// ValidateFlag returns an error if the specified feature flag name is not valid
+func ValidateFlag(flag string) error {
+	if !flagNamePattern.MatchString(flag) {
+		return ErrInvalidFeatureFlag
+	}
+	return nil
+}
+
+// IsEnabled returns true if the specified feature is enabled
+func (service *Service) IsEnabled(flag portainer.FeatureFlag) bool {
+	if service.enabledByCLI[flag] {
+		return true
+	}
+
+	settings, err := service.dataStore.Settings().Settings()
+	if err != nil {
+		log.Printf("[WARN] [internal,featureflag] [message: unable to retrieve settings] [error: %s]", err)
+		return false
+	}
+
+	return settings.FeatureFlags[flag]
+}
+
+// EnabledFlags returns the list of enabled features, sorted by name
+func (service *Service) EnabledFlags() []portainer.FeatureFlag {
+	enabled := make(map[portainer.FeatureFlag]bool)
+	for flag := range service.enabledByCLI {
+		enabled[flag] = true
+	}
+
+	settings, err := service.dataStore.Settings().Settings()
+	if err != nil {
+		log.Printf("[WARN] [internal,featureflag] [message: unable to retrieve settings] [error: %s]", err)
+	} else {
+		for flag, isEnabled := range settings.FeatureFlags {
+			if isEnabled {
+				enabled[flag] = true
+			}
+		}
+	}
+
+	flags := make([]portainer.FeatureFlag, 0, len(enabled))
+	for flag := range enabled {
+		flags = append(flags, flag)
+	}
+
+	sort.Slice(flags, func(i, j int) bool {
+		return flags[i] < flags[j]
+	})
+
+	return flags
+}
@@ -0,0 +1,17 @@
+// +build !windows
+
+package health
+
+import "syscall"
+
+// diskSpace returns the free and total space, in bytes, of the filesystem containing a path
+func diskSpace(path string) (uint64, uint64, error) {
+	var stat syscall.Statfs_t
+
+	err := syscall.Statfs(path, &stat)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return stat.Bavail * uint64(stat.Bsize), stat.Blocks * uint64(stat.Bsize), nil
+}
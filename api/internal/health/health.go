@@ -0,0 +1,170 @@
+package health
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/portainer/portainer/api"
+)
+
+const (
+	// StatusUp represents a component working as expected
+	StatusUp = "up"
+	// StatusDown represents a failing component
+	StatusDown = "down"
+
+	// minFreeDiskSpace is the free space required in the data directory, in bytes
+	minFreeDiskSpace = 100 * 1024 * 1024
+	// maxMissedHeartbeats is the number of iterations a background routine can miss before being reported as down
+	maxMissedHeartbeats = 3
+)
+
+type (
+	// Report represents the result of the checks of a probe
+	Report struct {
+		Status     string            `json:"Status"`
+		Components []ComponentReport `json:"Components"`
+	}
+
+	// ComponentReport represents the result of the check of a single component
+	ComponentReport struct {
+		Name    string                 `json:"Name"`
+		Status  string                 `json:"Status"`
+		Message string                 `json:"Message,omitempty"`
+		Details map[string]interface{} `json:"Details,omitempty"`
+	}
+
+	heartbeatSource interface {
+		Heartbeat() (time.Time, time.Duration)
+	}
+)
+
+// Checker verifies the health of the components Portainer depends on
+type Checker struct {
+	dataStore            portainer.DataStore
+	snapshotService      portainer.SnapshotService
+	scalingScheduler     portainer.ScalingScheduler
+	reverseTunnelService portainer.ReverseTunnelService
+	dataPath             string
+}
+
+// NewChecker returns a new instance of a checker
+func NewChecker(dataStore portainer.DataStore, snapshotService portainer.SnapshotService, scalingScheduler portainer.ScalingScheduler, reverseTunnelService portainer.ReverseTunnelService, dataPath string) *Checker {
+	return &Checker{
+		dataStore:            dataStore,
+		snapshotService:      snapshotService,
+		scalingScheduler:     scalingScheduler,
+		reverseTunnelService: reverseTunnelService,
+		dataPath:             dataPath,
+	}
+}
+
+// Liveness checks the components whose failure requires a restart of the instance:
+// the database and the background schedulers
+func (checker *Checker) Liveness() *Report {
+	return newReport(
+		checker.checkDatabase(),
+		checkHeartbeat("snapshot_scheduler", checker.snapshotService),
+		checkHeartbeat("scaling_scheduler", checker.scalingScheduler),
+	)
+}
+
+// Readiness checks all the components required to serve requests: the components checked
+// by the liveness probe, the tunnel server and the disk space in the data directory
+func (checker *Checker) Readiness() *Report {
+	return newReport(
+		checker.checkDatabase(),
+		checkHeartbeat("snapshot_scheduler", checker.snapshotService),
+		checkHeartbeat("scaling_scheduler", checker.scalingScheduler),
+		checker.checkTunnelServer(),
+		checker.checkDiskSpace(),
+	)
+}
+
+func newReport(components ...ComponentReport) *Report {
+	report := &Report{
+		Status:     StatusUp,
+		Components: components,
+	}
+
+	for _, component := range components {
+		if component.Status != StatusUp {
+			report.Status = StatusDown
+		}
+	}
+
+	return report
+}
+
+func (checker *Checker) checkDatabase() ComponentReport {
+	component := ComponentReport{Name: "database", Status: StatusUp}
+
+	version, err := checker.dataStore.Version().DBVersion()
+	if err != nil {
+		component.Status = StatusDown
+		component.Message = err.Error()
+		return component
+	}
+
+	component.Details = map[string]interface{}{"Version": version}
+	return component
+}
+
+func checkHeartbeat(name string, source heartbeatSource) ComponentReport {
+	component := ComponentReport{Name: name, Status: StatusUp}
+
+	last, interval := source.Heartbeat()
+	if last.IsZero() {
+		component.Status = StatusDown
+		component.Message = "Scheduler is not started"
+		return component
+	}
+
+	component.Details = map[string]interface{}{
+		"LastRun":  last.Unix(),
+		"Interval": interval.String(),
+	}
+
+	if time.Since(last) > maxMissedHeartbeats*interval {
+		component.Status = StatusDown
+		component.Message = fmt.Sprintf("No activity since %s", last.Format(time.RFC3339))
+	}
+
+	return component
+}
+
+func (checker *Checker) checkTunnelServer() ComponentReport {
+	component := ComponentReport{Name: "tunnel_server", Status: StatusUp}
+
+	err := checker.reverseTunnelService.TunnelServerStatus()
+	if err != nil {
+		component.Status = StatusDown
+		component.Message = err.Error()
+	}
+
+	return component
+}
+
+func (checker *Checker) checkDiskSpace() ComponentReport {
+	component := ComponentReport{Name: "disk_space", Status: StatusUp}
+
+	free, total, err := diskSpace(checker.dataPath)
+	if err != nil {
+		component.Status = StatusDown
+		component.Message = err.Error()
+		return component
+	}
+
+	component.Details = map[string]interface{}{
+		"Path":  checker.dataPath,
+		"Free":  free,
+		"Total": total,
+	}
+
+	if free < minFreeDiskSpace {
+		component.Status = StatusDown
+		component.Message = "Not enough free space in the data directory"
+	}
+
+	return component
+}
@@ -0,0 +1,154 @@
+package i18n
+
+// catalogs contains the translations of the server messages, indexed by locale and by the
+// message in the default locale. Messages built with fmt.Sprintf are indexed by their format,
+// the arguments are captured as strings and must be rendered with %s or %[n]s verbs.
+var catalogs = map[string]map[string]string{
+	"de": {
+		// HTTP errors
+		"Unauthorized":                                                                 "Nicht autorisiert",
+		"Access denied":                                                                "Zugriff verweigert",
+		"Access denied to resource":                                                    "Zugriff auf die Ressource verweigert",
+		"Invalid JWT token":                                                            "Ungültiges JWT-Token",
+		"Invalid credentials":                                                          "Ungültige Anmeldedaten",
+		"Invalid request payload":                                                      "Ungültiger Anfrageinhalt",
+		"Permission denied to access endpoint":                                         "Zugriff auf den Endpunkt verweigert",
+		"Edge compute features are disabled":                                           "Die Edge-Compute-Funktionen sind deaktiviert",
+		"OAuth authentication is not enabled":                                          "Die OAuth-Authentifizierung ist nicht aktiviert",
+		"Unable to authenticate through OAuth":                                         "Authentifizierung über OAuth nicht möglich",
+		"Unable to retrieve settings from the database":                                "Die Einstellungen konnten nicht aus der Datenbank gelesen werden",
+		"Unable to retrieve user details from the database":                            "Die Benutzerdaten konnten nicht aus der Datenbank gelesen werden",
+		"Unable to retrieve user authentication token":                                 "Das Authentifizierungstoken des Benutzers konnte nicht gelesen werden",
+		"Unable to retrieve info from request context":                                 "Die Informationen konnten nicht aus dem Anfragekontext gelesen werden",
+		"Unable to create Docker client":                                               "Der Docker-Client konnte nicht erstellt werden",
+		"Invalid endpoint identifier route variable":                                   "Ungültige Endpunktkennung",
+		"Invalid stack identifier route variable":                                      "Ungültige Stack-Kennung",
+		"Invalid user identifier route variable":                                       "Ungültige Benutzerkennung",
+		"Invalid query parameter: endpointId":                                          "Ungültiger Abfrageparameter: endpointId",
+		"A stack with this name already exists":                                        "Ein Stack mit diesem Namen existiert bereits",
+		"Unable to find an endpoint with the specified identifier inside the database": "Kein Endpunkt mit der angegebenen Kennung in der Datenbank gefunden",
+		"Unable to find a stack with the specified identifier inside the database":     "Kein Stack mit der angegebenen Kennung in der Datenbank gefunden",
+		"Unable to find a user with the specified identifier inside the database":      "Kein Benutzer mit der angegebenen Kennung in der Datenbank gefunden",
+		"Unable to find a team with the specified identifier inside the database":      "Kein Team mit der angegebenen Kennung in der Datenbank gefunden",
+		"Unable to find a registry with the specified identifier inside the database":  "Keine Registry mit der angegebenen Kennung in der Datenbank gefunden",
+		"Unable to find the endpoint associated to the stack inside the database":      "Der dem Stack zugeordnete Endpunkt wurde nicht in der Datenbank gefunden",
+		"Unable to persist the stack inside the database":                              "Der Stack konnte nicht in der Datenbank gespeichert werden",
+
+		// Notifications
+		"Test notification": "Testbenachrichtigung",
+		"This is a test notification sent from Portainer.": "Dies ist eine von Portainer gesendete Testbenachrichtigung.",
+		"This is a test notification sent from Portainer":  "Dies ist eine von Portainer gesendete Testbenachrichtigung",
+		"Stack deployed": "Stack bereitgestellt",
+		"Stack %s was successfully deployed on endpoint %s": "Stack %s wurde erfolgreich auf dem Endpunkt %s bereitgestellt",
+		"Stack deployment failed":                           "Bereitstellung des Stacks fehlgeschlagen",
+		"Deployment of stack %s on endpoint %s failed: %s":  "Die Bereitstellung des Stacks %s auf dem Endpunkt %s ist fehlgeschlagen: %s",
+		"Endpoint recovered":                                "Endpunkt wieder erreichbar",
+		"Endpoint %s (%s) is reachable again":               "Der Endpunkt %s (%s) ist wieder erreichbar",
+		"Host disk pressure":                                "Speicherplatz des Hosts knapp",
+		"Filesystem %s (%s) on endpoint %s is %.1f%% full":  "Das Dateisystem %s (%s) auf dem Endpunkt %s ist zu %s%% belegt",
+		"Host memory pressure":                              "Arbeitsspeicher des Hosts knapp",
+		"Memory usage on endpoint %s is %.1f%%":             "Die Speicherauslastung auf dem Endpunkt %s beträgt %s%%",
+		"Host load":                                         "Hostauslastung",
+		"Load average on endpoint %s is %.2f for %d CPUs":   "Die durchschnittliche Last auf dem Endpunkt %s beträgt %s für %s CPUs",
+		"Scaling schedule failed":                           "Skalierungsplan fehlgeschlagen",
+		"Scaling schedule %s failed to scale %s: %s":        "Der Skalierungsplan %s konnte %s nicht skalieren: %s",
+	},
+	"es": {
+		// HTTP errors
+		"Unauthorized":                                                                 "No autorizado",
+		"Access denied":                                                                "Acceso denegado",
+		"Access denied to resource":                                                    "Acceso denegado al recurso",
+		"Invalid JWT token":                                                            "Token JWT no válido",
+		"Invalid credentials":                                                          "Credenciales no válidas",
+		"Invalid request payload":                                                      "Contenido de la solicitud no válido",
+		"Permission denied to access endpoint":                                         "Permiso denegado para acceder al endpoint",
+		"Edge compute features are disabled":                                           "Las funciones de Edge compute están desactivadas",
+		"OAuth authentication is not enabled":                                          "La autenticación OAuth no está activada",
+		"Unable to authenticate through OAuth":                                         "No se puede autenticar mediante OAuth",
+		"Unable to retrieve settings from the database":                                "No se pueden recuperar los ajustes de la base de datos",
+		"Unable to retrieve user details from the database":                            "No se pueden recuperar los datos del usuario de la base de datos",
+		"Unable to retrieve user authentication token":                                 "No se puede recuperar el token de autenticación del usuario",
+		"Unable to retrieve info from request context":                                 "No se puede recuperar la información del contexto de la solicitud",
+		"Unable to create Docker client":                                               "No se puede crear el cliente Docker",
+		"Invalid endpoint identifier route variable":                                   "Identificador de endpoint no válido",
+		"Invalid stack identifier route variable":                                      "Identificador de stack no válido",
+		"Invalid user identifier route variable":                                       "Identificador de usuario no válido",
+		"Invalid query parameter: endpointId":                                          "Parámetro de consulta no válido: endpointId",
+		"A stack with this name already exists":                                        "Ya existe un stack con este nombre",
+		"Unable to find an endpoint with the specified identifier inside the database": "No se encuentra ningún endpoint con el identificador especificado en la base de datos",
+		"Unable to find a stack with the specified identifier inside the database":     "No se encuentra ningún stack con el identificador especificado en la base de datos",
+		"Unable to find a user with the specified identifier inside the database":      "No se encuentra ningún usuario con el identificador especificado en la base de datos",
+		"Unable to find a team with the specified identifier inside the database":      "No se encuentra ningún equipo con el identificador especificado en la base de datos",
+		"Unable to find a registry with the specified identifier inside the database":  "No se encuentra ningún registro con el identificador especificado en la base de datos",
+		"Unable to find the endpoint associated to the stack inside the database":      "No se encuentra en la base de datos el endpoint asociado al stack",
+		"Unable to persist the stack inside the database":                              "No se puede guardar el stack en la base de datos",
+
+		// Notifications
+		"Test notification": "Notificación de prueba",
+		"This is a test notification sent from Portainer.": "Esta es una notificación de prueba enviada desde Portainer.",
+		"This is a test notification sent from Portainer":  "Esta es una notificación de prueba enviada desde Portainer",
+		"Stack deployed": "Stack desplegado",
+		"Stack %s was successfully deployed on endpoint %s": "El stack %s se desplegó correctamente en el endpoint %s",
+		"Stack deployment failed":                           "Error en el despliegue del stack",
+		"Deployment of stack %s on endpoint %s failed: %s":  "El despliegue del stack %s en el endpoint %s ha fallado: %s",
+		"Endpoint recovered":                                "Endpoint recuperado",
+		"Endpoint %s (%s) is reachable again":               "El endpoint %s (%s) vuelve a estar accesible",
+		"Host disk pressure":                                "Poco espacio en disco en el host",
+		"Filesystem %s (%s) on endpoint %s is %.1f%% full":  "El sistema de archivos %s (%s) del endpoint %s está lleno al %s%%",
+		"Host memory pressure":                              "Poca memoria en el host",
+		"Memory usage on endpoint %s is %.1f%%":             "El uso de memoria en el endpoint %s es del %s%%",
+		"Host load":                                         "Carga del host",
+		"Load average on endpoint %s is %.2f for %d CPUs":   "La carga media en el endpoint %s es %s para %s CPUs",
+		"Scaling schedule failed":                           "Error en la programación de escalado",
+		"Scaling schedule %s failed to scale %s: %s":        "La programación de escalado %s no pudo escalar %s: %s",
+	},
+	"fr": {
+		// HTTP errors
+		"Unauthorized":                                                                 "Non autorisé",
+		"Access denied":                                                                "Accès refusé",
+		"Access denied to resource":                                                    "Accès à la ressource refusé",
+		"Invalid JWT token":                                                            "Jeton JWT invalide",
+		"Invalid credentials":                                                          "Identifiants invalides",
+		"Invalid request payload":                                                      "Contenu de la requête invalide",
+		"Permission denied to access endpoint":                                         "Accès à l'endpoint refusé",
+		"Edge compute features are disabled":                                           "Les fonctionnalités Edge compute sont désactivées",
+		"OAuth authentication is not enabled":                                          "L'authentification OAuth n'est pas activée",
+		"Unable to authenticate through OAuth":                                         "Impossible de s'authentifier via OAuth",
+		"Unable to retrieve settings from the database":                                "Impossible de récupérer les paramètres depuis la base de données",
+		"Unable to retrieve user details from the database":                            "Impossible de récupérer les informations de l'utilisateur depuis la base de données",
+		"Unable to retrieve user authentication token":                                 "Impossible de récupérer le jeton d'authentification de l'utilisateur",
+		"Unable to retrieve info from request context":                                 "Impossible de récupérer les informations du contexte de la requête",
+		"Unable to create Docker client":                                               "Impossible de créer le client Docker",
+		"Invalid endpoint identifier route variable":                                   "Identifiant d'endpoint invalide",
+		"Invalid stack identifier route variable":                                      "Identifiant de stack invalide",
+		"Invalid user identifier route variable":                                       "Identifiant d'utilisateur invalide",
+		"Invalid query parameter: endpointId":                                          "Paramètre de requête invalide : endpointId",
+		"A stack with this name already exists":                                        "Une stack portant ce nom existe déjà",
+		"Unable to find an endpoint with the specified identifier inside the database": "Aucun endpoint avec l'identifiant spécifié n'a été trouvé dans la base de données",
+		"Unable to find a stack with the specified identifier inside the database":     "Aucune stack avec l'identifiant spécifié n'a été trouvée dans la base de données",
+		"Unable to find a user with the specified identifier inside the database":      "Aucun utilisateur avec l'identifiant spécifié n'a été trouvé dans la base de données",
+		"Unable to find a team with the specified identifier inside the database":      "Aucune équipe avec l'identifiant spécifié n'a été trouvée dans la base de données",
+		"Unable to find a registry with the specified identifier inside the database":  "Aucun registre avec l'identifiant spécifié n'a été trouvé dans la base de données",
+		"Unable to find the endpoint associated to the stack inside the database":      "L'endpoint associé à la stack n'a pas été trouvé dans la base de données",
+		"Unable to persist the stack inside the database":                              "Impossible d'enregistrer la stack dans la base de données",
+
+		// Notifications
+		"Test notification": "Notification de test",
+		"This is a test notification sent from Portainer.": "Ceci est une notification de test envoyée par Portainer.",
+		"This is a test notification sent from Portainer":  "Ceci est une notification de test envoyée par Portainer",
+		"Stack deployed": "Stack déployée",
+		"Stack %s was successfully deployed on endpoint %s": "La stack %s a été déployée avec succès sur l'endpoint %s",
+		"Stack deployment failed":                           "Échec du déploiement de la stack",
+		"Deployment of stack %s on endpoint %s failed: %s":  "Le déploiement de la stack %s sur l'endpoint %s a échoué : %s",
+		"Endpoint recovered":                                "Endpoint rétabli",
+		"Endpoint %s (%s) is reachable again":               "L'endpoint %s (%s) est de nouveau joignable",
+		"Host disk pressure":                                "Espace disque de l'hôte insuffisant",
+		"Filesystem %s (%s) on endpoint %s is %.1f%% full":  "Le système de fichiers %s (%s) de l'endpoint %s est plein à %s %%",
+		"Host memory pressure":                              "Mémoire de l'hôte insuffisante",
+		"Memory usage on endpoint %s is %.1f%%":             "L'utilisation mémoire de l'endpoint %s est de %s %%",
+		"Host load":                                         "Charge de l'hôte",
+		"Load average on endpoint %s is %.2f for %d CPUs":   "La charge moyenne de l'endpoint %s est de %s pour %s CPU",
+		"Scaling schedule failed":                           "Échec de la planification de mise à l'échelle",
+		"Scaling schedule %s failed to scale %s: %s":        "La planification %s n'a pas pu mettre à l'échelle %s : %s",
+	},
+}
@@ -0,0 +1,192 @@
+package i18n
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultLocale is the locale of the messages written in the source code,
+// it is used when no supported locale can be negotiated
+const DefaultLocale = "en"
+
+// verbPattern matches the formatting verbs of a message format
+var verbPattern = regexp.MustCompile(`%(\[\d+\])?[-+# 0]*\d*(\.\d+)?[a-zA-Z]`)
+
+// pattern is a message format of the default locale compiled to a regular expression
+// capturing the values of its arguments
+type pattern struct {
+	format string
+	regexp *regexp.Regexp
+}
+
+var patterns = compilePatterns()
+
+// Locales returns the supported locales, sorted
+func Locales() []string {
+	locales := []string{DefaultLocale}
+	for locale := range catalogs {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// IsSupported reports whether a locale is supported
+func IsSupported(locale string) bool {
+	if locale == DefaultLocale {
+		return true
+	}
+	_, ok := catalogs[locale]
+	return ok
+}
+
+// Translate returns the translation of a message in a locale. The message is either looked up
+// as is in the catalog of the locale or matched against the message formats of the catalog,
+// in which case the arguments extracted from the message are rendered in the translated format.
+// The message is returned unchanged when no translation is available.
+func Translate(locale, message string) string {
+	catalog, ok := catalogs[locale]
+	if !ok || message == "" {
+		return message
+	}
+
+	if translation, ok := catalog[message]; ok {
+		return translation
+	}
+
+	for _, p := range patterns {
+		translation, ok := catalog[p.format]
+		if !ok {
+			continue
+		}
+
+		matches := p.regexp.FindStringSubmatch(message)
+		if matches == nil {
+			continue
+		}
+
+		args := make([]interface{}, 0, len(matches)-1)
+		for _, match := range matches[1:] {
+			args = append(args, match)
+		}
+		return fmt.Sprintf(translation, args...)
+	}
+
+	return message
+}
+
+// Negotiate returns the supported locale preferred in the value of an Accept-Language header,
+// or an empty string when none of the requested locales is supported
+func Negotiate(acceptLanguage string) string {
+	type languageRange struct {
+		locale  string
+		quality float64
+	}
+
+	ranges := make([]languageRange, 0)
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		if fields[0] == "" {
+			continue
+		}
+
+		quality := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				q, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64)
+				if err == nil {
+					quality = q
+				}
+			}
+		}
+
+		if quality > 0 {
+			ranges = append(ranges, languageRange{locale: strings.TrimSpace(fields[0]), quality: quality})
+		}
+	}
+
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return ranges[i].quality > ranges[j].quality
+	})
+
+	for _, r := range ranges {
+		if locale := Normalize(r.locale); locale != "" {
+			return locale
+		}
+	}
+
+	return ""
+}
+
+// Normalize returns the supported locale matching a language tag (e.g. fr-CA matches fr),
+// or an empty string when the language is not supported
+func Normalize(tag string) string {
+	language := strings.ToLower(strings.SplitN(strings.Replace(tag, "_", "-", -1), "-", 2)[0])
+	if language == "*" {
+		return DefaultLocale
+	}
+
+	if IsSupported(language) {
+		return language
+	}
+
+	return ""
+}
+
+// compilePatterns compiles the message formats found in the catalogs. Formats with more
+// literal text are tried first so that the most specific format matches a message.
+func compilePatterns() []pattern {
+	formats := make(map[string]bool)
+	for _, catalog := range catalogs {
+		for message := range catalog {
+			if verbPattern.MatchString(strings.Replace(message, "%%", "", -1)) {
+				formats[message] = true
+			}
+		}
+	}
+
+	compiled := make([]pattern, 0, len(formats))
+	for format := range formats {
+		compiled = append(compiled, pattern{format: format, regexp: compilePattern(format)})
+	}
+
+	sort.Slice(compiled, func(i, j int) bool {
+		li, lj := literalLength(compiled[i].format), literalLength(compiled[j].format)
+		if li != lj {
+			return li > lj
+		}
+		return compiled[i].format < compiled[j].format
+	})
+
+	return compiled
+}
+
+func compilePattern(format string) *regexp.Regexp {
+	var expression strings.Builder
+	expression.WriteString("^")
+
+	for i, literal := range strings.Split(format, "%%") {
+		if i > 0 {
+			expression.WriteString("%")
+		}
+
+		last := 0
+		for _, location := range verbPattern.FindAllStringIndex(literal, -1) {
+			expression.WriteString(regexp.QuoteMeta(literal[last:location[0]]))
+			expression.WriteString("(.*?)")
+			last = location[1]
+		}
+		expression.WriteString(regexp.QuoteMeta(literal[last:]))
+	}
+
+	expression.WriteString("$")
+	return regexp.MustCompile(expression.String())
+}
+
+func literalLength(format string) int {
+	return len(verbPattern.ReplaceAllString(format, ""))
+}
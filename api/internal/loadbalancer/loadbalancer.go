@@ -0,0 +1,228 @@
+package loadbalancer
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/docker"
+)
+
+const serviceInterval = 30 * time.Second
+
+var (
+	domainRe     = regexp.MustCompile(`^([a-zA-Z0-9]([a-zA-Z0-9-]*[a-zA-Z0-9])?\.)*[a-zA-Z0-9]([a-zA-Z0-9-]*[a-zA-Z0-9])?$`)
+	entryPointRe = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+)
+
+// Service represents a service registering the ports published by the containers and the services of the
+// endpoints into external load balancers. The registrations of the endpoints with an enabled integration are
+// synchronized every 30 seconds, the external service is only updated when the registrations change.
+type Service struct {
+	dataStore           portainer.DataStore
+	dockerClientFactory *docker.ClientFactory
+	refreshSignal       chan struct{}
+	mutex               sync.Mutex
+}
+
+// NewService creates a new instance of a service
+func NewService(dataStore portainer.DataStore, dockerClientFactory *docker.ClientFactory) *Service {
+	return &Service{
+		dataStore:           dataStore,
+		dockerClientFactory: dockerClientFactory,
+	}
+}
+
+// ValidateIntegration ensures that an integration defines the parameters required by its type
+func ValidateIntegration(integration *portainer.LoadBalancerIntegration) error {
+	switch integration.Type {
+	case portainer.TraefikLoadBalancerIntegration:
+		extension := filepath.Ext(integration.FilePath)
+		if !filepath.IsAbs(integration.FilePath) || (extension != ".yml" && extension != ".yaml") {
+			return errors.New("Invalid Traefik configuration file path: value must be an absolute path to a .yml or .yaml file")
+		}
+		if integration.Domain != "" && !domainRe.MatchString(integration.Domain) {
+			return errors.New("Invalid Traefik domain")
+		}
+		for _, entryPoint := range integration.EntryPoints {
+			if !entryPointRe.MatchString(entryPoint) {
+				return errors.New("Invalid Traefik entry point name")
+			}
+		}
+	case portainer.ConsulLoadBalancerIntegration, portainer.WebhookLoadBalancerIntegration:
+		parsedURL, err := url.Parse(integration.URL)
+		if err != nil || (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") || parsedURL.Host == "" {
+			return errors.New("Invalid load balancer integration URL")
+		}
+	default:
+		return errors.New("Invalid load balancer integration type. Value must be one of: traefik, consul or webhook")
+	}
+
+	return nil
+}
+
+// SupportLoadBalancerIntegration checks whether the published services of an endpoint can be registered. The
+// Edge endpoints are not supported as their Docker API is only reachable while their tunnel is open.
+func SupportLoadBalancerIntegration(endpoint *portainer.Endpoint) bool {
+	return endpoint.Type == portainer.DockerEnvironment || endpoint.Type == portainer.AgentOnDockerEnvironment
+}
+
+// Start will start a background routine to synchronize the registrations of the endpoints
+func (service *Service) Start() {
+	if service.refreshSignal != nil {
+		return
+	}
+
+	service.refreshSignal = make(chan struct{})
+
+	ticker := time.NewTicker(serviceInterval)
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				err := service.synchronizeIntegrations()
+				if err != nil {
+					log.Printf("[ERROR] [internal,loadbalancer] [message: background schedule error (load balancer integrations).] [error: %s]", err)
+				}
+
+			case <-service.refreshSignal:
+				log.Println("[DEBUG] [internal,loadbalancer] [message: shutting down load balancer integration service]")
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+}
+
+func (service *Service) synchronizeIntegrations() error {
+	integrations, err := service.dataStore.LoadBalancerIntegration().LoadBalancerIntegrations()
+	if err != nil {
+		return err
+	}
+
+	for _, integration := range integrations {
+		if !integration.Enabled {
+			continue
+		}
+
+		err := service.Synchronize(integration.EndpointID)
+		if err != nil {
+			log.Printf("[WARN] [internal,loadbalancer] [message: unable to synchronize the load balancer integration] [endpoint_id: %d] [error: %s]", integration.EndpointID, err)
+		}
+	}
+
+	return nil
+}
+
+// Synchronize registers the published services of an endpoint into the external service of its integration.
+// The outcome of the synchronization is persisted in the integration.
+func (service *Service) Synchronize(endpointID portainer.EndpointID) error {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	integration, err := service.dataStore.LoadBalancerIntegration().LoadBalancerIntegration(endpointID)
+	if err != nil {
+		return err
+	}
+
+	registrations, err := service.endpointRegistrations(integration)
+	if err == nil {
+		added, removed := diff(integration.Registrations, registrations)
+		if len(added) > 0 || len(removed) > 0 || integration.LastError != "" || integration.LastSync == 0 {
+			err = apply(integration, registrations)
+		}
+	}
+
+	integration.LastSync = time.Now().Unix()
+	integration.LastError = ""
+	if err != nil {
+		integration.LastError = err.Error()
+	} else {
+		integration.Registrations = registrations
+	}
+
+	updateErr := service.dataStore.LoadBalancerIntegration().UpdateLoadBalancerIntegration(endpointID, integration)
+	if updateErr != nil {
+		return updateErr
+	}
+
+	return err
+}
+
+// Deregister removes all the registrations of an integration from its external service
+func (service *Service) Deregister(integration *portainer.LoadBalancerIntegration) error {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	return remove(integration)
+}
+
+func (service *Service) endpointRegistrations(integration *portainer.LoadBalancerIntegration) ([]portainer.LoadBalancerRegistration, error) {
+	endpoint, err := service.dataStore.Endpoint().Endpoint(integration.EndpointID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !SupportLoadBalancerIntegration(endpoint) {
+		return nil, errors.New("the load balancer integration is only supported on Docker endpoints")
+	}
+
+	options := &collectOptions{
+		endpointID: endpoint.ID,
+		address:    EndpointAddress(integration, endpoint),
+		stackNames: make(map[string]bool),
+	}
+	if integration.Type == portainer.TraefikLoadBalancerIntegration {
+		options.domain = integration.Domain
+	}
+
+	if options.address == "" {
+		return nil, errors.New("unable to determine the address of the endpoint, an address must be defined in the integration")
+	}
+
+	stacks, err := service.dataStore.Stack().Stacks()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, stack := range stacks {
+		if stack.EndpointID == endpoint.ID {
+			options.stackNames[stack.Name] = true
+		}
+	}
+
+	dockerClient, err := service.dockerClientFactory.CreateClient(endpoint, "")
+	if err != nil {
+		return nil, err
+	}
+	defer dockerClient.Close()
+
+	containers, err := dockerClient.ContainerList(context.Background(), types.ContainerListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	sources := containerSources(containers)
+
+	info, err := dockerClient.Info(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	if info.Swarm.LocalNodeState == swarm.LocalNodeStateActive && info.Swarm.ControlAvailable {
+		services, err := dockerClient.ServiceList(context.Background(), types.ServiceListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, serviceSources(services)...)
+	}
+
+	return buildRegistrations(sources, options), nil
+}
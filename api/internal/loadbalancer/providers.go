@@ -0,0 +1,293 @@
+package loadbalancer
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/portainer/portainer/api"
+)
+
+const (
+	providerTimeout = 10 * time.Second
+
+	// SignatureHeader is the header of the webhook requests containing the HMAC-SHA256 signature of the payload
+	SignatureHeader = "X-Portainer-Signature"
+)
+
+var httpClient = &http.Client{Timeout: providerTimeout}
+
+// traefikConfiguration represents the dynamic configuration read by the Traefik file provider. The file
+// is written as JSON, which is valid YAML: Traefik loads it as long as the file has a .yml or .yaml extension.
+type traefikConfiguration struct {
+	HTTP *traefikHTTPConfiguration `json:"http,omitempty"`
+}
+
+type traefikHTTPConfiguration struct {
+	Routers  map[string]traefikRouter  `json:"routers"`
+	Services map[string]traefikService `json:"services"`
+}
+
+type traefikRouter struct {
+	Rule        string   `json:"rule"`
+	Service     string   `json:"service"`
+	EntryPoints []string `json:"entryPoints,omitempty"`
+}
+
+type traefikService struct {
+	LoadBalancer traefikLoadBalancer `json:"loadBalancer"`
+}
+
+type traefikLoadBalancer struct {
+	Servers []traefikServer `json:"servers"`
+}
+
+type traefikServer struct {
+	URL string `json:"url"`
+}
+
+// consulService represents a service registered into the catalog of a Consul agent
+type consulService struct {
+	ID      string            `json:"ID"`
+	Name    string            `json:"Name"`
+	Address string            `json:"Address"`
+	Port    int               `json:"Port"`
+	Tags    []string          `json:"Tags"`
+	Meta    map[string]string `json:"Meta"`
+}
+
+// webhookPayload represents the JSON document posted to the generic webhooks on each change of the registrations
+type webhookPayload struct {
+	EndpointID    portainer.EndpointID                 `json:"EndpointId"`
+	Registrations []portainer.LoadBalancerRegistration `json:"Registrations"`
+	Added         []portainer.LoadBalancerRegistration `json:"Added"`
+	Removed       []portainer.LoadBalancerRegistration `json:"Removed"`
+	Time          int64                                `json:"Time"`
+}
+
+// apply registers the current registrations of an endpoint into the external service of the integration
+// and removes the previous registrations that no longer exist
+func apply(integration *portainer.LoadBalancerIntegration, registrations []portainer.LoadBalancerRegistration) error {
+	switch integration.Type {
+	case portainer.TraefikLoadBalancerIntegration:
+		return writeTraefikConfiguration(integration.FilePath, traefikConfigurationFor(registrations, integration.EntryPoints))
+	case portainer.ConsulLoadBalancerIntegration:
+		return applyConsul(integration, registrations)
+	case portainer.WebhookLoadBalancerIntegration:
+		return postWebhook(integration, registrations)
+	}
+	return fmt.Errorf("unsupported load balancer integration type: %s", integration.Type)
+}
+
+// remove removes all the registrations of an endpoint from the external service of the integration
+func remove(integration *portainer.LoadBalancerIntegration) error {
+	if integration.Type == portainer.TraefikLoadBalancerIntegration {
+		err := os.Remove(integration.FilePath)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	return apply(integration, []portainer.LoadBalancerRegistration{})
+}
+
+// diff returns the registrations added to and removed from the previous registrations
+func diff(previous, current []portainer.LoadBalancerRegistration) ([]portainer.LoadBalancerRegistration, []portainer.LoadBalancerRegistration) {
+	previousRegistrations := make(map[string]portainer.LoadBalancerRegistration)
+	for _, registration := range previous {
+		previousRegistrations[registration.ID] = registration
+	}
+
+	added := make([]portainer.LoadBalancerRegistration, 0)
+	for _, registration := range current {
+		if existing, ok := previousRegistrations[registration.ID]; !ok || existing != registration {
+			added = append(added, registration)
+		}
+		delete(previousRegistrations, registration.ID)
+	}
+
+	removed := make([]portainer.LoadBalancerRegistration, 0)
+	for _, registration := range previous {
+		if _, ok := previousRegistrations[registration.ID]; ok {
+			removed = append(removed, registration)
+		}
+	}
+
+	return added, removed
+}
+
+// traefikConfigurationFor generates a router per host name, the registrations sharing the same host name
+// such as the replicas of a Compose service are load balanced by the same Traefik service
+func traefikConfigurationFor(registrations []portainer.LoadBalancerRegistration, entryPoints []string) *traefikConfiguration {
+	configuration := &traefikHTTPConfiguration{
+		Routers:  make(map[string]traefikRouter),
+		Services: make(map[string]traefikService),
+	}
+
+	for _, registration := range registrations {
+		if registration.Hostname == "" || registration.Protocol != "tcp" {
+			continue
+		}
+
+		name := "portainer-" + strings.Replace(registration.Hostname, ".", "-", -1)
+		if _, ok := configuration.Routers[name]; !ok {
+			configuration.Routers[name] = traefikRouter{
+				Rule:        fmt.Sprintf("Host(`%s`)", registration.Hostname),
+				Service:     name,
+				EntryPoints: entryPoints,
+			}
+		}
+
+		service := configuration.Services[name]
+		service.LoadBalancer.Servers = append(service.LoadBalancer.Servers, traefikServer{URL: fmt.Sprintf("http://%s:%d", registration.Address, registration.Port)})
+		configuration.Services[name] = service
+	}
+
+	if len(configuration.Routers) == 0 {
+		return &traefikConfiguration{}
+	}
+	return &traefikConfiguration{HTTP: configuration}
+}
+
+// writeTraefikConfiguration replaces the dynamic configuration file atomically so that Traefik never
+// loads a partially written file
+func writeTraefikConfiguration(filePath string, configuration *traefikConfiguration) error {
+	data, err := json.MarshalIndent(configuration, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmpFile, err := ioutil.TempFile(filepath.Dir(filePath), ".portainer-traefik-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpFile.Name())
+
+	_, err = tmpFile.Write(data)
+	if err != nil {
+		tmpFile.Close()
+		return err
+	}
+
+	err = tmpFile.Chmod(0644)
+	if err != nil {
+		tmpFile.Close()
+		return err
+	}
+
+	err = tmpFile.Close()
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmpFile.Name(), filePath)
+}
+
+func applyConsul(integration *portainer.LoadBalancerIntegration, registrations []portainer.LoadBalancerRegistration) error {
+	added, removed := diff(integration.Registrations, registrations)
+
+	for _, registration := range removed {
+		err := consulRequest(integration, "/v1/agent/service/deregister/"+url.PathEscape(registration.ID), nil, true)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, registration := range added {
+		service := &consulService{
+			ID:      registration.ID,
+			Name:    registration.Name,
+			Address: registration.Address,
+			Port:    registration.Port,
+			Tags:    []string{"portainer", registration.Protocol},
+			Meta: map[string]string{
+				"portainer_endpoint_id": fmt.Sprint(integration.EndpointID),
+				"portainer_stack":       registration.StackName,
+				"portainer_hostname":    registration.Hostname,
+			},
+		}
+
+		err := consulRequest(integration, "/v1/agent/service/register", service, false)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// consulRequest sends a request to the agent API of Consul, the services already deregistered are
+// ignored when ignoreNotFound is true
+func consulRequest(integration *portainer.LoadBalancerIntegration, path string, body interface{}, ignoreNotFound bool) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPut, strings.TrimSuffix(integration.URL, "/")+path, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if integration.Token != "" {
+		req.Header.Set("X-Consul-Token", integration.Token)
+	}
+
+	return send(req, ignoreNotFound)
+}
+
+func postWebhook(integration *portainer.LoadBalancerIntegration, registrations []portainer.LoadBalancerRegistration) error {
+	added, removed := diff(integration.Registrations, registrations)
+
+	data, err := json.Marshal(&webhookPayload{
+		EndpointID:    integration.EndpointID,
+		Registrations: registrations,
+		Added:         added,
+		Removed:       removed,
+		Time:          time.Now().Unix(),
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, integration.URL, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if integration.Token != "" {
+		mac := hmac.New(sha256.New, []byte(integration.Token))
+		mac.Write(data)
+		req.Header.Set(SignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	return send(req, false)
+}
+
+func send(req *http.Request, ignoreNotFound bool) error {
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if ignoreNotFound && resp.StatusCode == http.StatusNotFound {
+		return nil
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s responded with status %d", req.URL.Host, resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,241 @@
+package loadbalancer
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/docker"
+)
+
+const (
+	// ExposeLabel registers the published ports of a container or a service deployed outside of a stack
+	// when its value is true, and excludes the members of a stack when its value is false
+	ExposeLabel = "io.portainer.loadbalancer"
+	// HostnameLabel overrides the host name generated for the main port of a container or a service
+	HostnameLabel = "io.portainer.loadbalancer.host"
+	// PortLabel selects the main port of a container or a service, the lowest published TCP port is used by default
+	PortLabel = "io.portainer.loadbalancer.port"
+
+	composeServiceLabel = "com.docker.compose.service"
+)
+
+var hostnameInvalidCharsRe = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// publishedPort represents a port of a container or a service published on the hosts of an endpoint
+type publishedPort struct {
+	port     int
+	protocol string
+}
+
+// registrationSource represents a container or a service publishing ports on an endpoint
+type registrationSource struct {
+	name      string
+	stackName string
+	labels    map[string]string
+	ports     []publishedPort
+}
+
+// collectOptions represents the parameters used to build the registrations of an endpoint
+type collectOptions struct {
+	endpointID portainer.EndpointID
+	address    string
+	domain     string
+	// stackNames are the names of the stacks deployed on the endpoint through Portainer
+	stackNames map[string]bool
+}
+
+// EndpointAddress returns the address the published ports of an endpoint are reachable on
+func EndpointAddress(integration *portainer.LoadBalancerIntegration, endpoint *portainer.Endpoint) string {
+	if integration.Address != "" {
+		return integration.Address
+	}
+
+	for _, endpointURL := range []string{endpoint.PublicURL, endpoint.URL} {
+		if endpointURL == "" {
+			continue
+		}
+
+		if strings.Contains(endpointURL, "://") {
+			parsedURL, err := url.Parse(endpointURL)
+			if err != nil || parsedURL.Scheme == "unix" || parsedURL.Scheme == "npipe" {
+				continue
+			}
+			endpointURL = parsedURL.Host
+		}
+
+		if host, _, err := net.SplitHostPort(endpointURL); err == nil {
+			endpointURL = host
+		}
+
+		if endpointURL != "" {
+			return endpointURL
+		}
+	}
+
+	return ""
+}
+
+// containerSources returns the containers publishing ports, the containers of the Swarm services are
+// ignored as their ports are registered with the services
+func containerSources(containers []types.Container) []registrationSource {
+	sources := make([]registrationSource, 0)
+
+	for _, container := range containers {
+		if _, ok := container.Labels[docker.ServiceIDLabel]; ok {
+			continue
+		}
+
+		source := registrationSource{
+			stackName: container.Labels[docker.ComposeStackNameLabel],
+			labels:    container.Labels,
+		}
+
+		if service, ok := container.Labels[composeServiceLabel]; ok && source.stackName != "" {
+			source.name = source.stackName + "-" + service
+		} else if len(container.Names) > 0 {
+			source.name = strings.TrimPrefix(container.Names[0], "/")
+		}
+
+		for _, port := range container.Ports {
+			if port.PublicPort == 0 || port.IP == "127.0.0.1" || port.IP == "::1" {
+				continue
+			}
+			source.ports = append(source.ports, publishedPort{port: int(port.PublicPort), protocol: port.Type})
+		}
+
+		if len(source.ports) > 0 {
+			sources = append(sources, source)
+		}
+	}
+
+	return sources
+}
+
+// serviceSources returns the Swarm services publishing ports
+func serviceSources(services []swarm.Service) []registrationSource {
+	sources := make([]registrationSource, 0)
+
+	for _, service := range services {
+		source := registrationSource{
+			name:      service.Spec.Name,
+			stackName: service.Spec.Labels[docker.SwarmStackNameLabel],
+			labels:    service.Spec.Labels,
+		}
+
+		for _, port := range service.Endpoint.Ports {
+			if port.PublishedPort == 0 {
+				continue
+			}
+			source.ports = append(source.ports, publishedPort{port: int(port.PublishedPort), protocol: string(port.Protocol)})
+		}
+
+		if len(source.ports) > 0 {
+			sources = append(sources, source)
+		}
+	}
+
+	return sources
+}
+
+// exposed returns true when the published ports of the source must be registered: the members of the
+// stacks deployed through Portainer are registered unless they opt out with the expose label, the other
+// containers and services must opt in
+func exposed(source *registrationSource, stackNames map[string]bool) bool {
+	switch source.labels[ExposeLabel] {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	return source.stackName != "" && stackNames[source.stackName]
+}
+
+// buildRegistrations returns the registrations of the published ports of the exposed sources, sorted by identifier
+func buildRegistrations(sources []registrationSource, options *collectOptions) []portainer.LoadBalancerRegistration {
+	registrations := make([]portainer.LoadBalancerRegistration, 0)
+	identifiers := make(map[string]bool)
+
+	for _, source := range sources {
+		if source.name == "" || !exposed(&source, options.stackNames) {
+			continue
+		}
+
+		mainPort := mainPort(&source)
+
+		for _, port := range source.ports {
+			protocol := port.protocol
+			if protocol == "" {
+				protocol = "tcp"
+			}
+
+			id := fmt.Sprintf("portainer-%d-%s-%d", options.endpointID, source.name, port.port)
+			if protocol != "tcp" {
+				id += "-" + protocol
+			}
+			if identifiers[id] {
+				continue
+			}
+			identifiers[id] = true
+
+			registration := portainer.LoadBalancerRegistration{
+				ID:        id,
+				Name:      source.name,
+				StackName: source.stackName,
+				Address:   options.address,
+				Port:      port.port,
+				Protocol:  protocol,
+			}
+
+			if protocol == "tcp" && port.port == mainPort {
+				registration.Hostname = hostname(&source, options.domain)
+			}
+
+			registrations = append(registrations, registration)
+		}
+	}
+
+	sort.Slice(registrations, func(i, j int) bool {
+		return registrations[i].ID < registrations[j].ID
+	})
+
+	return registrations
+}
+
+// mainPort returns the port selected with the port label, or the lowest published TCP port of the source
+func mainPort(source *registrationSource) int {
+	if value, ok := source.labels[PortLabel]; ok {
+		port, err := strconv.Atoi(value)
+		if err == nil {
+			return port
+		}
+	}
+
+	lowest := 0
+	for _, port := range source.ports {
+		if (port.protocol == "tcp" || port.protocol == "") && (lowest == 0 || port.port < lowest) {
+			lowest = port.port
+		}
+	}
+	return lowest
+}
+
+func hostname(source *registrationSource, domain string) string {
+	if value := source.labels[HostnameLabel]; value != "" {
+		return value
+	}
+
+	if domain == "" {
+		return ""
+	}
+
+	name := strings.Trim(hostnameInvalidCharsRe.ReplaceAllString(strings.ToLower(source.name), "-"), "-")
+	return name + "." + domain
+}
@@ -0,0 +1,116 @@
+package loadbalancer
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/portainer/portainer/api"
+)
+
+func TestBuildRegistrations(t *testing.T) {
+	containers := []types.Container{
+		{
+			Names:  []string{"/shop_web_1"},
+			Labels: map[string]string{"com.docker.compose.project": "shop", "com.docker.compose.service": "web"},
+			Ports: []types.Port{
+				{IP: "0.0.0.0", PrivatePort: 80, PublicPort: 8080, Type: "tcp"},
+				{IP: "::", PrivatePort: 80, PublicPort: 8080, Type: "tcp"},
+				{IP: "0.0.0.0", PrivatePort: 9090, PublicPort: 9090, Type: "tcp"},
+				{IP: "127.0.0.1", PrivatePort: 6060, PublicPort: 6060, Type: "tcp"},
+			},
+		},
+		{
+			Names:  []string{"/shop_worker_1"},
+			Labels: map[string]string{"com.docker.compose.project": "shop", "com.docker.compose.service": "worker", ExposeLabel: "false"},
+			Ports:  []types.Port{{IP: "0.0.0.0", PrivatePort: 80, PublicPort: 8081, Type: "tcp"}},
+		},
+		{
+			Names: []string{"/standalone"},
+			Ports: []types.Port{{IP: "0.0.0.0", PrivatePort: 80, PublicPort: 8082, Type: "tcp"}},
+		},
+		{
+			Names:  []string{"/dns"},
+			Labels: map[string]string{ExposeLabel: "true", HostnameLabel: "dns.internal"},
+			Ports:  []types.Port{{IP: "0.0.0.0", PrivatePort: 53, PublicPort: 53, Type: "udp"}},
+		},
+	}
+
+	services := []swarm.Service{
+		{
+			Spec: swarm.ServiceSpec{Annotations: swarm.Annotations{Name: "blog_wordpress", Labels: map[string]string{"com.docker.stack.namespace": "blog"}}},
+			Endpoint: swarm.Endpoint{Ports: []swarm.PortConfig{
+				{Protocol: swarm.PortConfigProtocolTCP, TargetPort: 80, PublishedPort: 8000},
+			}},
+		},
+	}
+
+	options := &collectOptions{
+		endpointID: 1,
+		address:    "10.0.0.10",
+		domain:     "apps.example.com",
+		stackNames: map[string]bool{"shop": true, "blog": true},
+	}
+
+	sources := append(containerSources(containers), serviceSources(services)...)
+	registrations := buildRegistrations(sources, options)
+
+	expected := []portainer.LoadBalancerRegistration{
+		{ID: "portainer-1-blog_wordpress-8000", Name: "blog_wordpress", StackName: "blog", Address: "10.0.0.10", Port: 8000, Protocol: "tcp", Hostname: "blog-wordpress.apps.example.com"},
+		{ID: "portainer-1-dns-53-udp", Name: "dns", Address: "10.0.0.10", Port: 53, Protocol: "udp"},
+		{ID: "portainer-1-shop-web-8080", Name: "shop-web", StackName: "shop", Address: "10.0.0.10", Port: 8080, Protocol: "tcp", Hostname: "shop-web.apps.example.com"},
+		{ID: "portainer-1-shop-web-9090", Name: "shop-web", StackName: "shop", Address: "10.0.0.10", Port: 9090, Protocol: "tcp"},
+	}
+
+	if len(registrations) != len(expected) {
+		t.Fatalf("got %d registrations, want %d: %v", len(registrations), len(expected), registrations)
+	}
+
+	for i := range expected {
+		if registrations[i] != expected[i] {
+			t.Errorf("registration %d: got %+v, want %+v", i, registrations[i], expected[i])
+		}
+	}
+}
+
+func TestTraefikConfigurationFor(t *testing.T) {
+	registrations := []portainer.LoadBalancerRegistration{
+		{ID: "portainer-1-shop-web-8080", Address: "10.0.0.10", Port: 8080, Protocol: "tcp", Hostname: "shop.example.com"},
+		{ID: "portainer-1-shop-web-8081", Address: "10.0.0.10", Port: 8081, Protocol: "tcp", Hostname: "shop.example.com"},
+		{ID: "portainer-1-shop-web-9090", Address: "10.0.0.10", Port: 9090, Protocol: "tcp"},
+	}
+
+	configuration := traefikConfigurationFor(registrations, []string{"websecure"})
+	if configuration.HTTP == nil || len(configuration.HTTP.Routers) != 1 {
+		t.Fatalf("expected a single router, got %+v", configuration.HTTP)
+	}
+
+	router := configuration.HTTP.Routers["portainer-shop-example-com"]
+	if router.Rule != "Host(`shop.example.com`)" || router.Service != "portainer-shop-example-com" {
+		t.Errorf("unexpected router: %+v", router)
+	}
+
+	servers := configuration.HTTP.Services["portainer-shop-example-com"].LoadBalancer.Servers
+	if len(servers) != 2 || servers[0].URL != "http://10.0.0.10:8080" || servers[1].URL != "http://10.0.0.10:8081" {
+		t.Errorf("unexpected servers: %+v", servers)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	previous := []portainer.LoadBalancerRegistration{
+		{ID: "a", Port: 80},
+		{ID: "b", Port: 81},
+	}
+	current := []portainer.LoadBalancerRegistration{
+		{ID: "b", Port: 82},
+		{ID: "c", Port: 83},
+	}
+
+	added, removed := diff(previous, current)
+	if len(added) != 2 || added[0].ID != "b" || added[1].ID != "c" {
+		t.Errorf("unexpected added registrations: %+v", added)
+	}
+	if len(removed) != 1 || removed[0].ID != "a" {
+		t.Errorf("unexpected removed registrations: %+v", removed)
+	}
+}
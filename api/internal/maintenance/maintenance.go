@@ -0,0 +1,176 @@
+package maintenance
+
+import (
+	"errors"
+	"time"
+
+	"github.com/portainer/portainer/api"
+)
+
+const timeOfDayLayout = "15:04"
+
+// ValidateWindow ensures that a maintenance window targets at least one endpoint or endpoint group and
+// that it defines either a time range or valid recurring rules.
+func ValidateWindow(window *portainer.MaintenanceWindow) error {
+	if len(window.EndpointIDs) == 0 && len(window.EndpointGroupIDs) == 0 {
+		return errors.New("At least one endpoint or endpoint group must be specified")
+	}
+
+	if window.Start != 0 || window.End != 0 {
+		if window.Start == 0 || window.End <= window.Start {
+			return errors.New("Invalid time range. The end of the window must be after its start")
+		}
+		if len(window.Rules) > 0 {
+			return errors.New("A window cannot define both a time range and recurring rules")
+		}
+		return nil
+	}
+
+	if len(window.Rules) == 0 {
+		return errors.New("A time range or at least one recurring rule must be specified")
+	}
+
+	_, err := time.LoadLocation(window.Timezone)
+	if err != nil {
+		return errors.New("Invalid timezone")
+	}
+
+	for _, rule := range window.Rules {
+		for _, day := range rule.Days {
+			if day < 0 || day > 6 {
+				return errors.New("Invalid rule day. Value must be between 0 (Sunday) and 6 (Saturday)")
+			}
+		}
+
+		start, err := minutesOfDay(rule.StartTime)
+		if err != nil {
+			return errors.New("Invalid rule start time. Value must use the HH:MM format")
+		}
+
+		end, err := minutesOfDay(rule.EndTime)
+		if err != nil {
+			return errors.New("Invalid rule end time. Value must use the HH:MM format")
+		}
+
+		if start == end {
+			return errors.New("Invalid rule. The start and end times must be different")
+		}
+	}
+
+	return nil
+}
+
+// IsActive returns true if a maintenance window is enabled and in progress at a specific time
+func IsActive(window *portainer.MaintenanceWindow, now time.Time) bool {
+	if !window.Enabled {
+		return false
+	}
+
+	if window.Start != 0 {
+		return now.Unix() >= window.Start && now.Unix() < window.End
+	}
+
+	location, err := time.LoadLocation(window.Timezone)
+	if err != nil {
+		return false
+	}
+
+	now = now.In(location)
+	currentMinutes := now.Hour()*60 + now.Minute()
+	today := int(now.Weekday())
+	yesterday := (today + 6) % 7
+
+	for _, rule := range window.Rules {
+		start, err := minutesOfDay(rule.StartTime)
+		if err != nil {
+			continue
+		}
+
+		end, err := minutesOfDay(rule.EndTime)
+		if err != nil {
+			continue
+		}
+
+		if start <= end {
+			if currentMinutes >= start && currentMinutes < end && ruleAppliesOnDay(&rule, today) {
+				return true
+			}
+			continue
+		}
+
+		// the window spans over midnight, the days of the rule refer to the day the window starts
+		if currentMinutes >= start && ruleAppliesOnDay(&rule, today) {
+			return true
+		}
+		if currentMinutes < end && ruleAppliesOnDay(&rule, yesterday) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Covers returns true if a maintenance window applies to an endpoint, directly or through its group
+func Covers(window *portainer.MaintenanceWindow, endpoint *portainer.Endpoint) bool {
+	for _, endpointID := range window.EndpointIDs {
+		if endpointID == endpoint.ID {
+			return true
+		}
+	}
+
+	for _, groupID := range window.EndpointGroupIDs {
+		if groupID == endpoint.GroupID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ActiveWindow returns the maintenance window an endpoint is in at a specific time, or nil when the endpoint
+// is not in maintenance.
+func ActiveWindow(dataStore portainer.DataStore, endpoint *portainer.Endpoint, now time.Time) (*portainer.MaintenanceWindow, error) {
+	windows, err := dataStore.MaintenanceWindow().MaintenanceWindows()
+	if err != nil {
+		return nil, err
+	}
+
+	for idx := range windows {
+		window := &windows[idx]
+		if Covers(window, endpoint) && IsActive(window, now) {
+			return window, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// InMaintenance returns true if an endpoint is in a maintenance window. The endpoint is considered outside
+// of any maintenance window when the windows cannot be retrieved, so that the alerts are not lost.
+func InMaintenance(dataStore portainer.DataStore, endpoint *portainer.Endpoint) bool {
+	window, err := ActiveWindow(dataStore, endpoint, time.Now())
+	return err == nil && window != nil
+}
+
+func ruleAppliesOnDay(rule *portainer.MaintenanceRule, day int) bool {
+	if len(rule.Days) == 0 {
+		return true
+	}
+
+	for _, ruleDay := range rule.Days {
+		if ruleDay == day {
+			return true
+		}
+	}
+
+	return false
+}
+
+func minutesOfDay(value string) (int, error) {
+	t, err := time.Parse(timeOfDayLayout, value)
+	if err != nil {
+		return 0, err
+	}
+
+	return t.Hour()*60 + t.Minute(), nil
+}
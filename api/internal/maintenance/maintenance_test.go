@@ -0,0 +1,81 @@
+package maintenance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/portainer/portainer/api"
+)
+
+func TestIsActive_OverMidnight(t *testing.T) {
+	window := &portainer.MaintenanceWindow{
+		Enabled:  true,
+		Timezone: "UTC",
+		Rules:    []portainer.MaintenanceRule{{Days: []int{int(time.Saturday)}, StartTime: "22:00", EndTime: "02:00"}},
+	}
+
+	cases := []struct {
+		now    time.Time
+		active bool
+	}{
+		{time.Date(2020, 6, 6, 23, 0, 0, 0, time.UTC), true},
+		{time.Date(2020, 6, 7, 1, 30, 0, 0, time.UTC), true},
+		{time.Date(2020, 6, 7, 2, 0, 0, 0, time.UTC), false},
+		{time.Date(2020, 6, 7, 23, 0, 0, 0, time.UTC), false},
+		{time.Date(2020, 6, 6, 1, 0, 0, 0, time.UTC), false},
+	}
+
+	for _, c := range cases {
+		if IsActive(window, c.now) != c.active {
+			t.Errorf("IsActive at %s: expected %v", c.now, c.active)
+		}
+	}
+}
+
+func TestIsActive_TimeRange(t *testing.T) {
+	start := time.Date(2020, 6, 6, 10, 0, 0, 0, time.UTC)
+	window := &portainer.MaintenanceWindow{Enabled: true, Start: start.Unix(), End: start.Add(time.Hour).Unix()}
+
+	if !IsActive(window, start.Add(30*time.Minute)) {
+		t.Error("expected the window to be active within its time range")
+	}
+	if IsActive(window, start.Add(time.Hour)) {
+		t.Error("expected the window to be inactive at its end")
+	}
+
+	window.Enabled = false
+	if IsActive(window, start.Add(30*time.Minute)) {
+		t.Error("expected a disabled window to be inactive")
+	}
+}
+
+func TestCovers(t *testing.T) {
+	window := &portainer.MaintenanceWindow{EndpointIDs: []portainer.EndpointID{1}, EndpointGroupIDs: []portainer.EndpointGroupID{2}}
+
+	if !Covers(window, &portainer.Endpoint{ID: 1, GroupID: 1}) {
+		t.Error("expected the window to cover the endpoint")
+	}
+	if !Covers(window, &portainer.Endpoint{ID: 3, GroupID: 2}) {
+		t.Error("expected the window to cover the endpoint of the group")
+	}
+	if Covers(window, &portainer.Endpoint{ID: 3, GroupID: 1}) {
+		t.Error("expected the window not to cover the endpoint")
+	}
+}
+
+func TestValidateWindow(t *testing.T) {
+	window := &portainer.MaintenanceWindow{EndpointIDs: []portainer.EndpointID{1}, Timezone: "UTC"}
+	if ValidateWindow(window) == nil {
+		t.Error("expected an error for a window without time range nor rules")
+	}
+
+	window.Rules = []portainer.MaintenanceRule{{StartTime: "25:00", EndTime: "02:00"}}
+	if ValidateWindow(window) == nil {
+		t.Error("expected an error for an invalid start time")
+	}
+
+	window.Rules[0].StartTime = "22:00"
+	if err := ValidateWindow(window); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+}
@@ -0,0 +1,263 @@
+package notification
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/portainer/portainer/api"
+)
+
+const (
+	defaultPagerDutyURL = "https://events.pagerduty.com/v2/enqueue"
+	channelTimeout      = 10 * time.Second
+)
+
+var defaultSeverities = map[portainer.NotificationType]portainer.NotificationSeverity{
+	portainer.ContainerUnhealthyNotification:    portainer.WarningNotificationSeverity,
+	portainer.ContainerRestartLoopNotification:  portainer.CriticalNotificationSeverity,
+	portainer.HostDiskPressureNotification:      portainer.WarningNotificationSeverity,
+	portainer.HostMemoryPressureNotification:    portainer.WarningNotificationSeverity,
+	portainer.HostLoadNotification:              portainer.WarningNotificationSeverity,
+	portainer.EndpointDownNotification:          portainer.CriticalNotificationSeverity,
+	portainer.EndpointUpNotification:            portainer.InfoNotificationSeverity,
+	portainer.JobFailedNotification:             portainer.WarningNotificationSeverity,
+	portainer.StackDeployedNotification:         portainer.InfoNotificationSeverity,
+	portainer.StackDeploymentFailedNotification: portainer.WarningNotificationSeverity,
+	portainer.ContainerAutoHealedNotification:   portainer.WarningNotificationSeverity,
+}
+
+var channelColors = map[portainer.NotificationSeverity]string{
+	portainer.InfoNotificationSeverity:     "#2eb886",
+	portainer.WarningNotificationSeverity:  "#daa038",
+	portainer.CriticalNotificationSeverity: "#a30200",
+}
+
+var httpClient = &http.Client{Timeout: channelTimeout}
+
+// webhookPayload represents the JSON document posted to the generic webhooks
+type webhookPayload struct {
+	Type         portainer.NotificationType     `json:"Type"`
+	TypeName     string                         `json:"TypeName"`
+	Severity     portainer.NotificationSeverity `json:"Severity"`
+	Title        string                         `json:"Title"`
+	Message      string                         `json:"Message"`
+	EndpointID   portainer.EndpointID           `json:"EndpointId"`
+	EndpointName string                         `json:"EndpointName"`
+	ResourceID   string                         `json:"ResourceId"`
+	Time         int64                          `json:"Time"`
+}
+
+// DefaultSeverity returns the severity of the notifications of the specified type
+func DefaultSeverity(notificationType portainer.NotificationType) portainer.NotificationSeverity {
+	severity, ok := defaultSeverities[notificationType]
+	if !ok {
+		return portainer.InfoNotificationSeverity
+	}
+	return severity
+}
+
+func channelColor(severity portainer.NotificationSeverity) string {
+	color, ok := channelColors[severity]
+	if !ok {
+		return channelColors[portainer.InfoNotificationSeverity]
+	}
+	return color
+}
+
+// ValidateChannel returns an error if a notification channel is not valid
+func ValidateChannel(channel *portainer.NotificationChannel) error {
+	switch channel.Type {
+	case portainer.SlackNotificationChannel, portainer.TeamsNotificationChannel, portainer.WebhookNotificationChannel:
+		if channel.URL == "" {
+			return errors.New("Invalid notification channel URL")
+		}
+	case portainer.PagerDutyNotificationChannel:
+		if channel.RoutingKey == "" {
+			return errors.New("Invalid PagerDuty routing key")
+		}
+	default:
+		return errors.New("Invalid notification channel type. Value must be one of: slack, teams, pagerduty or webhook")
+	}
+
+	if channel.URL != "" {
+		parsedURL, err := url.Parse(channel.URL)
+		if err != nil || (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") || parsedURL.Host == "" {
+			return errors.New("Invalid notification channel URL")
+		}
+	}
+
+	for _, route := range channel.Routes {
+		for _, severity := range route.Severities {
+			if severity != portainer.InfoNotificationSeverity && severity != portainer.WarningNotificationSeverity && severity != portainer.CriticalNotificationSeverity {
+				return errors.New("Invalid notification route severity. Value must be one of: info, warning or critical")
+			}
+		}
+	}
+
+	return nil
+}
+
+// MatchRoutes returns true if the notification matches one of the routes of the channel
+func MatchRoutes(channel *portainer.NotificationChannel, notification *portainer.Notification) bool {
+	for _, route := range channel.Routes {
+		if matchRoute(&route, notification) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchRoute(route *portainer.NotificationRoute, notification *portainer.Notification) bool {
+	typeMatches := len(route.Types) == 0
+	for _, t := range route.Types {
+		if t == notification.Type {
+			typeMatches = true
+			break
+		}
+	}
+
+	severityMatches := len(route.Severities) == 0
+	for _, severity := range route.Severities {
+		if severity == notification.Severity {
+			severityMatches = true
+			break
+		}
+	}
+
+	return typeMatches && severityMatches
+}
+
+// SendToChannel forwards a notification to a notification channel
+func SendToChannel(channel *portainer.NotificationChannel, notification *portainer.Notification, endpointName string) error {
+	var destination string
+	var payload interface{}
+
+	switch channel.Type {
+	case portainer.SlackNotificationChannel:
+		destination, payload = channel.URL, slackPayload(notification, endpointName)
+	case portainer.TeamsNotificationChannel:
+		destination, payload = channel.URL, teamsPayload(notification, endpointName)
+	case portainer.PagerDutyNotificationChannel:
+		destination = channel.URL
+		if destination == "" {
+			destination = defaultPagerDutyURL
+		}
+		payload = pagerDutyPayload(channel.RoutingKey, notification, endpointName)
+	case portainer.WebhookNotificationChannel:
+		destination = channel.URL
+		payload = &webhookPayload{
+			Type:         notification.Type,
+			TypeName:     TypeName(notification.Type),
+			Severity:     notification.Severity,
+			Title:        notification.Title,
+			Message:      notification.Message,
+			EndpointID:   notification.EndpointID,
+			EndpointName: endpointName,
+			ResourceID:   notification.ResourceID,
+			Time:         notification.Time,
+		}
+	default:
+		return fmt.Errorf("unsupported notification channel type: %s", channel.Type)
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	resp, err := httpClient.Post(destination, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("notification channel responded with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+func slackPayload(notification *portainer.Notification, endpointName string) map[string]interface{} {
+	fields := []map[string]interface{}{
+		{"title": "Severity", "value": string(notification.Severity), "short": true},
+	}
+	if endpointName != "" {
+		fields = append(fields, map[string]interface{}{"title": "Endpoint", "value": endpointName, "short": true})
+	}
+
+	return map[string]interface{}{
+		"text": notification.Title,
+		"attachments": []map[string]interface{}{
+			{
+				"color":  channelColor(notification.Severity),
+				"text":   notification.Message,
+				"fields": fields,
+				"ts":     notification.Time,
+			},
+		},
+	}
+}
+
+func teamsPayload(notification *portainer.Notification, endpointName string) map[string]interface{} {
+	facts := []map[string]string{
+		{"name": "Severity", "value": string(notification.Severity)},
+	}
+	if endpointName != "" {
+		facts = append(facts, map[string]string{"name": "Endpoint", "value": endpointName})
+	}
+
+	return map[string]interface{}{
+		"@type":      "MessageCard",
+		"@context":   "https://schema.org/extensions",
+		"summary":    notification.Title,
+		"themeColor": strings.TrimPrefix(channelColor(notification.Severity), "#"),
+		"title":      notification.Title,
+		"sections": []map[string]interface{}{
+			{
+				"text":  notification.Message,
+				"facts": facts,
+			},
+		},
+	}
+}
+
+// pagerDutyPayload builds an Events API v2 event. Endpoint recoveries resolve the alert raised
+// when the endpoint went down, all the other notifications trigger an alert.
+func pagerDutyPayload(routingKey string, notification *portainer.Notification, endpointName string) map[string]interface{} {
+	source := endpointName
+	if source == "" {
+		source = "portainer"
+	}
+
+	severity := string(notification.Severity)
+	if severity == "" {
+		severity = string(portainer.InfoNotificationSeverity)
+	}
+
+	eventAction := "trigger"
+	dedupKey := fmt.Sprintf("portainer/%d/%s/%d", notification.EndpointID, notification.ResourceID, notification.Type)
+	if notification.Type == portainer.EndpointUpNotification {
+		eventAction = "resolve"
+		dedupKey = fmt.Sprintf("portainer/%d/%s/%d", notification.EndpointID, notification.ResourceID, portainer.EndpointDownNotification)
+	}
+
+	return map[string]interface{}{
+		"routing_key":  routingKey,
+		"event_action": eventAction,
+		"dedup_key":    dedupKey,
+		"payload": map[string]interface{}{
+			"summary":   notification.Title + ": " + notification.Message,
+			"source":    source,
+			"severity":  severity,
+			"timestamp": time.Unix(notification.Time, 0).UTC().Format(time.RFC3339),
+			"class":     TypeName(notification.Type),
+		},
+	}
+}
@@ -0,0 +1,170 @@
+package notification
+
+import (
+	"log"
+	"sort"
+	"sync"
+
+	"github.com/portainer/portainer/api"
+)
+
+const (
+	// maxInboxSize is the number of notifications kept in the inbox of a user,
+	// the oldest notifications are removed first
+	maxInboxSize = 100
+	// subscriberBufferSize is the number of notifications buffered for a subscriber,
+	// notifications are dropped for slow subscribers
+	subscriberBufferSize = 16
+)
+
+// inbox stores the notifications of the users and pushes them to their subscribers
+type inbox struct {
+	dataStore   portainer.DataStore
+	mu          sync.Mutex
+	subscribers map[portainer.UserID]map[chan portainer.UserNotification]struct{}
+}
+
+func newInbox(dataStore portainer.DataStore) *inbox {
+	return &inbox{
+		dataStore:   dataStore,
+		subscribers: make(map[portainer.UserID]map[chan portainer.UserNotification]struct{}),
+	}
+}
+
+// subscribe returns a channel receiving the notifications added to the inbox of a user
+// and a function that must be called to stop receiving them
+func (inbox *inbox) subscribe(userID portainer.UserID) (<-chan portainer.UserNotification, func()) {
+	ch := make(chan portainer.UserNotification, subscriberBufferSize)
+
+	inbox.mu.Lock()
+	if inbox.subscribers[userID] == nil {
+		inbox.subscribers[userID] = make(map[chan portainer.UserNotification]struct{})
+	}
+	inbox.subscribers[userID][ch] = struct{}{}
+	inbox.mu.Unlock()
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			inbox.mu.Lock()
+			delete(inbox.subscribers[userID], ch)
+			if len(inbox.subscribers[userID]) == 0 {
+				delete(inbox.subscribers, userID)
+			}
+			inbox.mu.Unlock()
+			close(ch)
+		})
+	}
+
+	return ch, unsubscribe
+}
+
+func (inbox *inbox) publish(notification portainer.UserNotification) {
+	inbox.mu.Lock()
+	defer inbox.mu.Unlock()
+
+	for ch := range inbox.subscribers[notification.UserID] {
+		select {
+		case ch <- notification:
+		default:
+		}
+	}
+}
+
+// deliver stores a notification in the inbox of each recipient
+func (inbox *inbox) deliver(recipients []portainer.UserID, notification *portainer.Notification) {
+	for _, userID := range recipients {
+		userNotification := &portainer.UserNotification{
+			UserID:       userID,
+			Notification: *notification,
+		}
+
+		err := inbox.dataStore.UserNotification().CreateUserNotification(userNotification)
+		if err != nil {
+			log.Printf("[WARN] [internal,notification] [message: unable to store user notification] [user: %d] [error: %s]", userID, err)
+			continue
+		}
+
+		inbox.prune(userID)
+		inbox.publish(*userNotification)
+	}
+}
+
+func (inbox *inbox) prune(userID portainer.UserID) {
+	notifications, err := inbox.dataStore.UserNotification().UserNotificationsByUserID(userID)
+	if err != nil || len(notifications) <= maxInboxSize {
+		return
+	}
+
+	sort.Slice(notifications, func(i, j int) bool {
+		return notifications[i].ID < notifications[j].ID
+	})
+
+	for _, notification := range notifications[:len(notifications)-maxInboxSize] {
+		err := inbox.dataStore.UserNotification().DeleteUserNotification(notification.ID)
+		if err != nil {
+			log.Printf("[WARN] [internal,notification] [message: unable to remove user notification] [user: %d] [error: %s]", userID, err)
+		}
+	}
+}
+
+// recipients returns the users notified of an event: the administrators and, for the events
+// related to an endpoint, the users authorized to access that endpoint
+func (inbox *inbox) recipients(endpointID portainer.EndpointID) ([]portainer.UserID, error) {
+	users, err := inbox.dataStore.User().Users()
+	if err != nil {
+		return nil, err
+	}
+
+	var endpoint *portainer.Endpoint
+	var endpointGroup *portainer.EndpointGroup
+	if endpointID != 0 {
+		endpoint, err = inbox.dataStore.Endpoint().Endpoint(endpointID)
+		if err != nil {
+			return nil, err
+		}
+
+		endpointGroup, err = inbox.dataStore.EndpointGroup().EndpointGroup(endpoint.GroupID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	recipients := make([]portainer.UserID, 0)
+	for _, user := range users {
+		if user.Role == portainer.AdministratorRole {
+			recipients = append(recipients, user.ID)
+			continue
+		}
+
+		if endpoint == nil {
+			continue
+		}
+
+		memberships, err := inbox.dataStore.TeamMembership().TeamMembershipsByUserID(user.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		if authorizedAccess(user.ID, memberships, endpointGroup.UserAccessPolicies, endpointGroup.TeamAccessPolicies) ||
+			authorizedAccess(user.ID, memberships, endpoint.UserAccessPolicies, endpoint.TeamAccessPolicies) {
+			recipients = append(recipients, user.ID)
+		}
+	}
+
+	return recipients, nil
+}
+
+func authorizedAccess(userID portainer.UserID, memberships []portainer.TeamMembership, userAccessPolicies portainer.UserAccessPolicies, teamAccessPolicies portainer.TeamAccessPolicies) bool {
+	if _, ok := userAccessPolicies[userID]; ok {
+		return true
+	}
+
+	for _, membership := range memberships {
+		if _, ok := teamAccessPolicies[membership.TeamID]; ok {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,46 @@
+package notification
+
+import (
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/internal/i18n"
+)
+
+// defaultBodyTemplates are the translations of the default body template
+var defaultBodyTemplates = map[string]string{
+	"de": `{{ .Message }}
+
+Ereignis: {{ .TypeName }} ({{ .Severity }})
+{{ if .EndpointName }}Endpunkt: {{ .EndpointName }}
+{{ end }}{{ if .ResourceID }}Ressource: {{ .ResourceID }}
+{{ end }}Zeit: {{ .Time }}
+`,
+	"es": `{{ .Message }}
+
+Evento: {{ .TypeName }} ({{ .Severity }})
+{{ if .EndpointName }}Endpoint: {{ .EndpointName }}
+{{ end }}{{ if .ResourceID }}Recurso: {{ .ResourceID }}
+{{ end }}Hora: {{ .Time }}
+`,
+	"fr": `{{ .Message }}
+
+Événement : {{ .TypeName }} ({{ .Severity }})
+{{ if .EndpointName }}Endpoint : {{ .EndpointName }}
+{{ end }}{{ if .ResourceID }}Ressource : {{ .ResourceID }}
+{{ end }}Date : {{ .Time }}
+`,
+}
+
+// Localize returns a copy of a notification with its title and message translated in a locale.
+// Notifications are stored in the default locale and translated when they are delivered.
+func Localize(notification portainer.Notification, locale string) portainer.Notification {
+	notification.Title = i18n.Translate(locale, notification.Title)
+	notification.Message = i18n.Translate(locale, notification.Message)
+	return notification
+}
+
+func defaultBodyTemplate(locale string) string {
+	if template, ok := defaultBodyTemplates[locale]; ok {
+		return template
+	}
+	return DefaultBodyTemplate
+}
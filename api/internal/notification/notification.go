@@ -0,0 +1,136 @@
+package notification
+
+import (
+	"log"
+	"time"
+
+	"github.com/portainer/portainer/api"
+)
+
+// Service represents a service used to send notifications.
+// Notifications are reported in the application logs, stored in the inbox of the users they concern,
+// sent by email when enabled in the settings and forwarded to the notification channels they are routed to.
+type Service struct {
+	dataStore   portainer.DataStore
+	mailService portainer.MailService
+	inbox       *inbox
+}
+
+// NewService returns a new instance of a notification service
+func NewService(dataStore portainer.DataStore, mailService portainer.MailService) *Service {
+	return &Service{
+		dataStore:   dataStore,
+		mailService: mailService,
+		inbox:       newInbox(dataStore),
+	}
+}
+
+// Notify sends a notification
+func (service *Service) Notify(notification *portainer.Notification) {
+	setDefaults(notification)
+
+	log.Printf("[WARN] [internal,notification] [title: %s] [endpoint: %d] [resource: %s] [message: %s]", notification.Title, notification.EndpointID, notification.ResourceID, notification.Message)
+
+	recipients, err := service.inbox.recipients(notification.EndpointID)
+	if err != nil {
+		log.Printf("[WARN] [internal,notification] [message: unable to retrieve notification recipients] [error: %s]", err)
+	} else {
+		service.inbox.deliver(recipients, notification)
+	}
+
+	settings, err := service.dataStore.Settings().Settings()
+	if err != nil {
+		log.Printf("[WARN] [internal,notification] [message: unable to retrieve settings] [error: %s]", err)
+		return
+	}
+
+	if settings.EmailNotifications.Enabled && isEmailNotificationType(&settings.EmailNotifications, notification.Type) {
+		go service.sendEmail(settings, notification)
+	}
+
+	channels, err := service.dataStore.NotificationChannel().NotificationChannels()
+	if err != nil {
+		log.Printf("[WARN] [internal,notification] [message: unable to retrieve notification channels] [error: %s]", err)
+		return
+	}
+
+	localized := Localize(*notification, settings.DefaultLocale)
+	for idx := range channels {
+		channel := &channels[idx]
+		if channel.Enabled && MatchRoutes(channel, notification) {
+			go service.sendToChannel(channel, &localized)
+		}
+	}
+}
+
+// NotifyUser stores a notification in the inbox of a single user. It is used to report the outcome
+// of the operations started by that user and is not sent by email nor forwarded to the notification channels.
+func (service *Service) NotifyUser(userID portainer.UserID, notification *portainer.Notification) {
+	setDefaults(notification)
+	service.inbox.deliver([]portainer.UserID{userID}, notification)
+}
+
+// Subscribe returns a channel receiving the notifications added to the inbox of a user
+// and a function that must be called to stop receiving them
+func (service *Service) Subscribe(userID portainer.UserID) (<-chan portainer.UserNotification, func()) {
+	return service.inbox.subscribe(userID)
+}
+
+func setDefaults(notification *portainer.Notification) {
+	if notification.Time == 0 {
+		notification.Time = time.Now().Unix()
+	}
+
+	if notification.Severity == "" {
+		notification.Severity = DefaultSeverity(notification.Type)
+	}
+}
+
+func (service *Service) sendToChannel(channel *portainer.NotificationChannel, notification *portainer.Notification) {
+	err := SendToChannel(channel, notification, service.endpointName(notification.EndpointID))
+	if err != nil {
+		log.Printf("[WARN] [internal,notification] [message: unable to forward notification] [channel: %s] [error: %s]", channel.Name, err)
+	}
+}
+
+func (service *Service) endpointName(endpointID portainer.EndpointID) string {
+	if endpointID == 0 {
+		return ""
+	}
+
+	endpoint, err := service.dataStore.Endpoint().Endpoint(endpointID)
+	if err != nil {
+		return ""
+	}
+
+	return endpoint.Name
+}
+
+func (service *Service) sendEmail(settings *portainer.Settings, notification *portainer.Notification) {
+	endpointName := service.endpointName(notification.EndpointID)
+
+	subject, body, err := RenderEmail(&settings.EmailNotifications, notification, endpointName, settings.DefaultLocale)
+	if err != nil {
+		log.Printf("[WARN] [internal,notification] [message: unable to render email notification] [error: %s]", err)
+		return
+	}
+
+	err = service.mailService.Send(&settings.SMTPSettings, settings.EmailNotifications.Recipients, subject, body)
+	if err != nil {
+		log.Printf("[WARN] [internal,notification] [message: unable to send email notification] [error: %s]", err)
+	}
+}
+
+func isEmailNotificationType(settings *portainer.EmailNotificationSettings, notificationType portainer.NotificationType) bool {
+	if len(settings.Types) == 0 {
+		return true
+	}
+
+	for _, t := range settings.Types {
+		if t == notificationType {
+			return true
+		}
+	}
+
+	return false
+}
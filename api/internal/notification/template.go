@@ -0,0 +1,145 @@
+package notification
+
+import (
+	"bytes"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/portainer/portainer/api"
+)
+
+const (
+	// DefaultSubjectTemplate is the template used to render the subject of the emails when no template is defined
+	DefaultSubjectTemplate = "[Portainer] {{ .Title }}{{ if .EndpointName }} - {{ .EndpointName }}{{ end }}"
+	// DefaultBodyTemplate is the template used to render the body of the emails when no template is defined
+	DefaultBodyTemplate = `{{ .Message }}
+
+Event: {{ .TypeName }} ({{ .Severity }})
+{{ if .EndpointName }}Endpoint: {{ .EndpointName }}
+{{ end }}{{ if .ResourceID }}Resource: {{ .ResourceID }}
+{{ end }}Time: {{ .Time }}
+`
+)
+
+var notificationTypeNames = map[portainer.NotificationType]string{
+	portainer.ContainerUnhealthyNotification:    "container_unhealthy",
+	portainer.ContainerRestartLoopNotification:  "container_restart_loop",
+	portainer.HostDiskPressureNotification:      "host_disk_pressure",
+	portainer.HostMemoryPressureNotification:    "host_memory_pressure",
+	portainer.HostLoadNotification:              "host_load",
+	portainer.EndpointDownNotification:          "endpoint_down",
+	portainer.EndpointUpNotification:            "endpoint_up",
+	portainer.JobFailedNotification:             "job_failed",
+	portainer.StackDeployedNotification:         "stack_deployed",
+	portainer.StackDeploymentFailedNotification: "stack_deployment_failed",
+	portainer.ContainerAutoHealedNotification:   "container_autohealed",
+}
+
+// templateData represents the data available in the notification templates
+type templateData struct {
+	Type         portainer.NotificationType
+	TypeName     string
+	Severity     portainer.NotificationSeverity
+	Title        string
+	Message      string
+	EndpointID   portainer.EndpointID
+	EndpointName string
+	ResourceID   string
+	Time         string
+}
+
+// TypeName returns the name of a notification type
+func TypeName(notificationType portainer.NotificationType) string {
+	name, ok := notificationTypeNames[notificationType]
+	if !ok {
+		return "unknown"
+	}
+	return name
+}
+
+// ValidateTemplates returns an error if one of the templates cannot be parsed
+func ValidateTemplates(templates []portainer.NotificationTemplate) error {
+	for _, t := range templates {
+		_, err := template.New("subject").Parse(t.Subject)
+		if err != nil {
+			return err
+		}
+
+		_, err = template.New("body").Parse(t.Body)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RenderEmail renders the subject and the body of the email sent for a notification, using the template
+// defined for the type of the notification, the template without type or the default templates.
+// The title and the message of the notification, as well as the default body template, are translated in the locale.
+func RenderEmail(settings *portainer.EmailNotificationSettings, notification *portainer.Notification, endpointName, locale string) (string, string, error) {
+	subjectTemplate, bodyTemplate := DefaultSubjectTemplate, defaultBodyTemplate(locale)
+	localized := Localize(*notification, locale)
+
+	for _, t := range settings.Templates {
+		if t.Type == 0 && t.Subject != "" {
+			subjectTemplate = t.Subject
+		}
+		if t.Type == 0 && t.Body != "" {
+			bodyTemplate = t.Body
+		}
+	}
+
+	for _, t := range settings.Templates {
+		if t.Type == notification.Type && t.Type != 0 {
+			if t.Subject != "" {
+				subjectTemplate = t.Subject
+			}
+			if t.Body != "" {
+				bodyTemplate = t.Body
+			}
+		}
+	}
+
+	data := &templateData{
+		Type:         notification.Type,
+		TypeName:     TypeName(notification.Type),
+		Severity:     notification.Severity,
+		Title:        localized.Title,
+		Message:      localized.Message,
+		EndpointID:   notification.EndpointID,
+		EndpointName: endpointName,
+		ResourceID:   notification.ResourceID,
+		Time:         time.Unix(notification.Time, 0).Format(time.RFC1123),
+	}
+
+	subject, err := render(subjectTemplate, data)
+	if err != nil {
+		return "", "", err
+	}
+
+	body, err := render(bodyTemplate, data)
+	if err != nil {
+		return "", "", err
+	}
+
+	// header injection is prevented by keeping the subject on a single line
+	subject = strings.Join(strings.Fields(subject), " ")
+
+	return subject, body, nil
+}
+
+func render(text string, data *templateData) (string, error) {
+	t, err := template.New("notification").Parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	var buffer bytes.Buffer
+	err = t.Execute(&buffer, data)
+	if err != nil {
+		return "", err
+	}
+
+	return buffer.String(), nil
+}
@@ -0,0 +1,150 @@
+package organization
+
+import (
+	"fmt"
+
+	"github.com/portainer/portainer/api"
+)
+
+// QuotaResource represents a kind of resource limited by the quotas of an organization
+type QuotaResource string
+
+const (
+	// QuotaEndpoints is the quota of endpoints of an organization, including the Edge devices
+	QuotaEndpoints QuotaResource = "endpoints"
+	// QuotaEdgeDevices is the quota of Edge endpoints of an organization
+	QuotaEdgeDevices QuotaResource = "Edge devices"
+	// QuotaUsers is the quota of users of an organization
+	QuotaUsers QuotaResource = "users"
+	// QuotaStacks is the quota of stacks deployed on the endpoints of an organization
+	QuotaStacks QuotaResource = "stacks"
+)
+
+// QuotaExceededError is returned when the creation of resources would exceed a quota of an organization
+type QuotaExceededError struct {
+	Resource QuotaResource
+	Quota    int
+}
+
+func (err *QuotaExceededError) Error() string {
+	return fmt.Sprintf("The organization has reached its quota of %d %s", err.Quota, err.Resource)
+}
+
+// Usage returns the number of resources of an organization counted against its quotas
+func Usage(dataStore portainer.DataStore, organizationID portainer.OrganizationID) (*portainer.OrganizationQuotaUsage, error) {
+	usage := &portainer.OrganizationQuotaUsage{}
+
+	endpoints, err := dataStore.Endpoint().Endpoints()
+	if err != nil {
+		return nil, err
+	}
+
+	organizationEndpoints := make(map[portainer.EndpointID]bool)
+	for _, endpoint := range endpoints {
+		if endpoint.OrganizationID != organizationID {
+			continue
+		}
+
+		organizationEndpoints[endpoint.ID] = true
+		usage.Endpoints++
+		if isEdgeEndpointType(endpoint.Type) {
+			usage.EdgeDevices++
+		}
+	}
+
+	users, err := dataStore.User().Users()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, user := range users {
+		if user.OrganizationID == organizationID {
+			usage.Users++
+		}
+	}
+
+	stacks, err := dataStore.Stack().Stacks()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, stack := range stacks {
+		if organizationEndpoints[stack.EndpointID] {
+			usage.Stacks++
+		}
+	}
+
+	return usage, nil
+}
+
+// CheckQuota returns a QuotaExceededError when the creation of count resources would exceed the quota
+// of the organization. The resources without organization are not limited.
+func CheckQuota(dataStore portainer.DataStore, organizationID portainer.OrganizationID, resource QuotaResource, count int) error {
+	if organizationID == 0 {
+		return nil
+	}
+
+	organization, err := dataStore.Organization().Organization(organizationID)
+	if err != nil {
+		return err
+	}
+
+	quota := resourceQuota(&organization.Quotas, resource)
+	if quota == 0 {
+		return nil
+	}
+
+	usage, err := Usage(dataStore, organizationID)
+	if err != nil {
+		return err
+	}
+
+	if resourceUsage(usage, resource)+count > quota {
+		return &QuotaExceededError{Resource: resource, Quota: quota}
+	}
+
+	return nil
+}
+
+// CheckEndpointQuota verifies the quota of endpoints of an organization, and its quota of Edge devices
+// when Edge endpoints are created
+func CheckEndpointQuota(dataStore portainer.DataStore, organizationID portainer.OrganizationID, count int, edge bool) error {
+	err := CheckQuota(dataStore, organizationID, QuotaEndpoints, count)
+	if err != nil || !edge {
+		return err
+	}
+
+	return CheckQuota(dataStore, organizationID, QuotaEdgeDevices, count)
+}
+
+func resourceQuota(quotas *portainer.OrganizationQuotas, resource QuotaResource) int {
+	switch resource {
+	case QuotaEndpoints:
+		return quotas.MaxEndpoints
+	case QuotaEdgeDevices:
+		return quotas.MaxEdgeDevices
+	case QuotaUsers:
+		return quotas.MaxUsers
+	case QuotaStacks:
+		return quotas.MaxStacks
+	}
+	return 0
+}
+
+func resourceUsage(usage *portainer.OrganizationQuotaUsage, resource QuotaResource) int {
+	switch resource {
+	case QuotaEndpoints:
+		return usage.Endpoints
+	case QuotaEdgeDevices:
+		return usage.EdgeDevices
+	case QuotaUsers:
+		return usage.Users
+	case QuotaStacks:
+		return usage.Stacks
+	}
+	return 0
+}
+
+func isEdgeEndpointType(endpointType portainer.EndpointType) bool {
+	return endpointType == portainer.EdgeAgentOnDockerEnvironment || endpointType == portainer.EdgeAgentOnKubernetesEnvironment
+}
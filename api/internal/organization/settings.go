@@ -0,0 +1,41 @@
+package organization
+
+import (
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+)
+
+// ApplySettings returns a copy of the settings of the instance with the settings overridden by an organization
+func ApplySettings(settings *portainer.Settings, organization *portainer.Organization) *portainer.Settings {
+	effectiveSettings := *settings
+
+	overrides := organization.Settings
+	if overrides.LogoURL != "" {
+		effectiveSettings.LogoURL = overrides.LogoURL
+	}
+	if overrides.EdgeAgentCheckinInterval != 0 {
+		effectiveSettings.EdgeAgentCheckinInterval = overrides.EdgeAgentCheckinInterval
+	}
+	if overrides.DefaultResourceOwnershipPolicy != 0 {
+		effectiveSettings.DefaultResourceOwnershipPolicy = overrides.DefaultResourceOwnershipPolicy
+	}
+
+	return &effectiveSettings
+}
+
+// EffectiveSettings returns the settings applied to the resources of an organization. The settings
+// of the instance are returned for the resources without organization or when the organization does not exist.
+func EffectiveSettings(dataStore portainer.DataStore, settings *portainer.Settings, organizationID portainer.OrganizationID) (*portainer.Settings, error) {
+	if organizationID == 0 {
+		return settings, nil
+	}
+
+	organization, err := dataStore.Organization().Organization(organizationID)
+	if err == bolterrors.ErrObjectNotFound {
+		return settings, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	return ApplySettings(settings, organization), nil
+}
@@ -0,0 +1,117 @@
+package passwordpolicy
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/portainer/portainer/api"
+)
+
+// maxHistorySize is the maximum number of previous passwords kept for a user
+const maxHistorySize = 24
+
+var (
+	// ErrPasswordReused is returned when a password is one of the most recent passwords of a user
+	ErrPasswordReused = errors.New("The password has already been used recently. Choose a different password")
+)
+
+// Validate returns an error describing the requirements of the policy that the password does not meet
+func Validate(password string, policy *portainer.PasswordPolicy) error {
+	if password == "" {
+		return errors.New("Invalid password. The password cannot be empty")
+	}
+
+	var upper, lower, digit, special bool
+	for _, c := range password {
+		switch {
+		case unicode.IsUpper(c):
+			upper = true
+		case unicode.IsLower(c):
+			lower = true
+		case unicode.IsDigit(c):
+			digit = true
+		case unicode.IsPunct(c) || unicode.IsSymbol(c) || unicode.IsSpace(c):
+			special = true
+		}
+	}
+
+	requirements := make([]string, 0)
+	if len([]rune(password)) < policy.MinLength {
+		requirements = append(requirements, fmt.Sprintf("at least %d characters", policy.MinLength))
+	}
+	if policy.RequireUppercase && !upper {
+		requirements = append(requirements, "an uppercase letter")
+	}
+	if policy.RequireLowercase && !lower {
+		requirements = append(requirements, "a lowercase letter")
+	}
+	if policy.RequireDigit && !digit {
+		requirements = append(requirements, "a digit")
+	}
+	if policy.RequireSpecialCharacter && !special {
+		requirements = append(requirements, "a special character")
+	}
+
+	if len(requirements) != 0 {
+		return fmt.Errorf("Invalid password. The password must contain %s", strings.Join(requirements, ", "))
+	}
+
+	return nil
+}
+
+// IsReused returns true when the password matches one of the passwords of the user that cannot be reused
+func IsReused(cryptoService portainer.CryptoService, user *portainer.User, password string, policy *portainer.PasswordPolicy) bool {
+	if policy.HistorySize <= 0 {
+		return false
+	}
+
+	hashes := append([]string{user.Password}, user.PasswordHistory...)
+	if len(hashes) > policy.HistorySize {
+		hashes = hashes[:policy.HistorySize]
+	}
+
+	for _, hash := range hashes {
+		if hash != "" && cryptoService.CompareHashAndData(hash, password) == nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SetPassword replaces the password hash of the user, the previous hash is kept in the password history
+// when the policy prevents password reuse
+func SetPassword(user *portainer.User, hash string, policy *portainer.PasswordPolicy) {
+	historySize := policy.HistorySize - 1
+	if historySize > maxHistorySize {
+		historySize = maxHistorySize
+	}
+
+	history := make([]string, 0)
+	if historySize > 0 {
+		if user.Password != "" {
+			history = append(history, user.Password)
+		}
+		history = append(history, user.PasswordHistory...)
+		if len(history) > historySize {
+			history = history[:historySize]
+		}
+	}
+
+	user.Password = hash
+	user.PasswordHistory = history
+	user.PasswordChangedDate = time.Now().Unix()
+}
+
+// IsExpired returns true when the password of the user is older than the maximum age of the policy
+func IsExpired(user *portainer.User, policy *portainer.PasswordPolicy) bool {
+	if policy.MaxAge <= 0 || user.PasswordChangedDate == 0 {
+		return false
+	}
+
+	maxAge := time.Duration(policy.MaxAge) * 24 * time.Hour
+	return time.Since(time.Unix(user.PasswordChangedDate, 0)) > maxAge
+}
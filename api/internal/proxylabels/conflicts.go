@@ -0,0 +1,159 @@
+package proxylabels
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/client"
+	"github.com/portainer/portainer/api/docker"
+)
+
+// Resource represents the reverse proxy configuration of a container or a service of an endpoint
+type Resource struct {
+	Name      string
+	StackName string
+	Routes    []Route
+	// Routers are the names of the Traefik routers, they are shared by all the containers of the endpoint
+	Routers []string
+}
+
+// NewResource builds the reverse proxy configuration of a container or a service from its labels and environment
+func NewResource(name, stackName string, labels map[string]string, env map[string]string) Resource {
+	resource := Resource{
+		Name:      name,
+		StackName: stackName,
+		Routes:    Routes(labels, env),
+		Routers:   make([]string, 0),
+	}
+
+	for i := range resource.Routes {
+		resource.Routes[i].Resource = name
+		resource.Routes[i].StackName = stackName
+	}
+
+	for _, key := range traefikRouterNames(labels) {
+		resource.Routers = append(resource.Routers, strings.SplitN(key, ".", 2)[1])
+	}
+
+	return resource
+}
+
+// EndpointResources returns the reverse proxy configuration of the running containers and of the Swarm services
+// of an endpoint. The containers of the Swarm services are ignored as they share the configuration of their service,
+// the replicas of a Compose service are reported once.
+func EndpointResources(cli *client.Client) ([]Resource, error) {
+	resources := make([]Resource, 0)
+
+	containers, err := cli.ContainerList(context.Background(), types.ContainerListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	known := make(map[string]bool)
+	for _, container := range containers {
+		if _, ok := container.Labels[docker.ServiceIDLabel]; ok {
+			continue
+		}
+
+		name := strings.TrimPrefix(firstName(container.Names), "/")
+		stackName := container.Labels[docker.ComposeStackNameLabel]
+		if service, ok := container.Labels[docker.ComposeServiceLabel]; ok && stackName != "" {
+			name = stackName + "_" + service
+		}
+
+		if known[name] {
+			continue
+		}
+		known[name] = true
+
+		containerJSON, err := cli.ContainerInspect(context.Background(), container.ID)
+		if err != nil {
+			if client.IsErrNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+
+		env := map[string]string{}
+		if containerJSON.Config != nil {
+			env = EnvMap(containerJSON.Config.Env)
+		}
+
+		resources = append(resources, NewResource(name, stackName, container.Labels, env))
+	}
+
+	info, err := cli.Info(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	if info.Swarm.LocalNodeState != swarm.LocalNodeStateActive || !info.Swarm.ControlAvailable {
+		return resources, nil
+	}
+
+	services, err := cli.ServiceList(context.Background(), types.ServiceListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, service := range services {
+		env := map[string]string{}
+		if service.Spec.TaskTemplate.ContainerSpec != nil {
+			env = EnvMap(service.Spec.TaskTemplate.ContainerSpec.Env)
+		}
+
+		resources = append(resources, NewResource(service.Spec.Name, service.Spec.Labels[docker.SwarmStackNameLabel], service.Spec.Labels, env))
+	}
+
+	return resources, nil
+}
+
+// Conflicts reports the routes of a resource already routed to another resource of the endpoint, and the
+// Traefik routers already declared by another resource. The resources of the same stack are not compared
+// to the existing resources of the stack, as they are replaced when the stack is deployed.
+func Conflicts(resource *Resource, existing []Resource) []Issue {
+	issues := make([]Issue, 0)
+
+	for _, other := range existing {
+		if other.Name == resource.Name || (resource.StackName != "" && other.StackName == resource.StackName) {
+			continue
+		}
+
+		for _, route := range resource.Routes {
+			if route.Host == "*" || strings.HasPrefix(route.Host, "~") {
+				continue
+			}
+
+			for _, otherRoute := range other.Routes {
+				if route.Host == otherRoute.Host && route.Path == otherRoute.Path {
+					issues = append(issues, errorIssue(routeKey(&route), fmt.Sprintf("%s%s is already routed to %s by %s", route.Host, route.Path, other.Name, otherRoute.Provider)))
+				}
+			}
+		}
+
+		for _, router := range resource.Routers {
+			if contains(other.Routers, router) {
+				issues = append(issues, errorIssue("traefik.http.routers."+router, fmt.Sprintf("The Traefik router %s is already declared by %s", router, other.Name)))
+			}
+		}
+	}
+
+	return issues
+}
+
+func routeKey(route *Route) string {
+	if route.Provider == NginxProxyProvider {
+		return nginxVirtualHost
+	}
+	return "traefik.http.routers." + route.Router + ".rule"
+}
+
+func firstName(names []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+	return names[0]
+}
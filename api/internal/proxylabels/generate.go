@@ -0,0 +1,138 @@
+package proxylabels
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var routeNameRe = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// RouteDefinition represents the routing of the requests of one or several host names to a port of
+// a container or a service, used to generate the configuration of a reverse proxy
+type RouteDefinition struct {
+	// Provider is one of traefik or nginx-proxy
+	Provider string `json:"Provider"`
+	// Name is the name of the Traefik router and service, the name of the container or of the service is recommended
+	Name  string   `json:"Name"`
+	Hosts []string `json:"Hosts"`
+	// PathPrefix restricts the route to the requests whose path starts with the prefix
+	PathPrefix string `json:"PathPrefix,omitempty"`
+	// Port is the port of the container the requests are forwarded to
+	Port int `json:"Port"`
+	// EntryPoints are the Traefik entry points the router listens on, all the entry points are used when empty
+	EntryPoints []string `json:"EntryPoints,omitempty"`
+	// TLS terminates TLS on the reverse proxy, the certificate is requested from CertResolver with Traefik
+	// and from Let's Encrypt with the companion of nginx-proxy
+	TLS          bool     `json:"TLS"`
+	CertResolver string   `json:"CertResolver,omitempty"`
+	Email        string   `json:"Email,omitempty"`
+	Middlewares  []string `json:"Middlewares,omitempty"`
+	// Network is the network the reverse proxy reaches the container on, required when the container
+	// is connected to several networks
+	Network string `json:"Network,omitempty"`
+}
+
+// Generate returns the labels and the environment variables configuring the reverse proxy of the definition
+func Generate(definition *RouteDefinition) (map[string]string, []string, error) {
+	if len(definition.Hosts) == 0 {
+		return nil, nil, errors.New("At least one host name must be defined")
+	}
+
+	for _, host := range definition.Hosts {
+		if !nginxHostRe.MatchString(host) {
+			return nil, nil, fmt.Errorf("Invalid host name %s", host)
+		}
+	}
+
+	if definition.PathPrefix != "" && (!strings.HasPrefix(definition.PathPrefix, "/") || strings.ContainsAny(definition.PathPrefix, "` ")) {
+		return nil, nil, errors.New("Invalid path prefix: value must start with /")
+	}
+
+	if definition.Port < 1 || definition.Port > 65535 {
+		return nil, nil, errors.New("Invalid port: value must be between 1 and 65535")
+	}
+
+	switch definition.Provider {
+	case TraefikProvider:
+		labels, err := generateTraefikLabels(definition)
+		return labels, []string{}, err
+	case NginxProxyProvider:
+		return map[string]string{}, generateNginxProxyEnv(definition), nil
+	}
+
+	return nil, nil, errors.New("Invalid reverse proxy. Value must be one of: traefik or nginx-proxy")
+}
+
+func generateTraefikLabels(definition *RouteDefinition) (map[string]string, error) {
+	if !routeNameRe.MatchString(definition.Name) {
+		return nil, errors.New("Invalid router name")
+	}
+
+	for _, name := range append(append([]string{}, definition.EntryPoints...), definition.Middlewares...) {
+		if !routeNameRe.MatchString(strings.Replace(name, "@", "-", 1)) {
+			return nil, fmt.Errorf("Invalid entry point or middleware name %s", name)
+		}
+	}
+
+	hosts := make([]string, 0, len(definition.Hosts))
+	for _, host := range definition.Hosts {
+		hosts = append(hosts, "`"+host+"`")
+	}
+
+	rule := "Host(" + strings.Join(hosts, ", ") + ")"
+	if definition.PathPrefix != "" {
+		rule += " && PathPrefix(`" + definition.PathPrefix + "`)"
+	}
+
+	router := "traefik.http.routers." + definition.Name + "."
+	labels := map[string]string{
+		"traefik.enable":   "true",
+		router + "rule":    rule,
+		router + "service": definition.Name,
+		"traefik.http.services." + definition.Name + ".loadbalancer.server.port": strconv.Itoa(definition.Port),
+	}
+
+	if len(definition.EntryPoints) > 0 {
+		labels[router+"entrypoints"] = strings.Join(definition.EntryPoints, ",")
+	}
+
+	if len(definition.Middlewares) > 0 {
+		labels[router+"middlewares"] = strings.Join(definition.Middlewares, ",")
+	}
+
+	if definition.TLS {
+		labels[router+"tls"] = "true"
+		if definition.CertResolver != "" {
+			labels[router+"tls.certresolver"] = definition.CertResolver
+		}
+	}
+
+	if definition.Network != "" {
+		labels["traefik.docker.network"] = definition.Network
+	}
+
+	return labels, nil
+}
+
+func generateNginxProxyEnv(definition *RouteDefinition) []string {
+	env := []string{
+		nginxVirtualHost + "=" + strings.Join(definition.Hosts, ","),
+		nginxVirtualPort + "=" + strconv.Itoa(definition.Port),
+	}
+
+	if definition.PathPrefix != "" {
+		env = append(env, nginxVirtualPath+"="+definition.PathPrefix)
+	}
+
+	if definition.TLS {
+		env = append(env, nginxLetsEncryptHost+"="+strings.Join(definition.Hosts, ","))
+		if definition.Email != "" {
+			env = append(env, nginxLetsEncryptEmail+"="+definition.Email)
+		}
+	}
+
+	return env
+}
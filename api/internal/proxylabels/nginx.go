@@ -0,0 +1,91 @@
+package proxylabels
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	nginxVirtualHost      = "VIRTUAL_HOST"
+	nginxVirtualPort      = "VIRTUAL_PORT"
+	nginxVirtualProto     = "VIRTUAL_PROTO"
+	nginxVirtualPath      = "VIRTUAL_PATH"
+	nginxLetsEncryptHost  = "LETSENCRYPT_HOST"
+	nginxLetsEncryptEmail = "LETSENCRYPT_EMAIL"
+)
+
+var (
+	nginxHostRe   = regexp.MustCompile(`^(\*\.)?([a-zA-Z0-9]([a-zA-Z0-9-]*[a-zA-Z0-9])?\.)*[a-zA-Z0-9]([a-zA-Z0-9-]*[a-zA-Z0-9])?(\.\*)?$`)
+	nginxProtocol = []string{"http", "https", "uwsgi", "fastcgi", "grpc", "grpcs"}
+)
+
+// ValidateNginxProxyEnv validates the environment variables read by nginx-proxy and its Let's Encrypt companion.
+// The host names starting with ~ are regular expressions and are not validated.
+func ValidateNginxProxyEnv(env map[string]string) []Issue {
+	issues := make([]Issue, 0)
+
+	virtualHost, ok := env[nginxVirtualHost]
+	if !ok {
+		for _, key := range []string{nginxVirtualPort, nginxVirtualProto, nginxVirtualPath, nginxLetsEncryptHost} {
+			if _, ok := env[key]; ok {
+				issues = append(issues, warningIssue(key, fmt.Sprintf("%s is ignored by nginx-proxy when %s is not defined", key, nginxVirtualHost)))
+			}
+		}
+		return issues
+	}
+
+	hosts := splitList(virtualHost)
+	if len(hosts) == 0 {
+		issues = append(issues, errorIssue(nginxVirtualHost, "At least one host name must be defined"))
+	}
+	for _, host := range hosts {
+		if !strings.HasPrefix(host, "~") && !nginxHostRe.MatchString(host) {
+			issues = append(issues, errorIssue(nginxVirtualHost, fmt.Sprintf("Invalid host name %s", host)))
+		}
+	}
+
+	if value, ok := env[nginxVirtualPort]; ok {
+		port, err := strconv.Atoi(value)
+		if err != nil || port < 1 || port > 65535 {
+			issues = append(issues, errorIssue(nginxVirtualPort, "The port must be a number between 1 and 65535"))
+		}
+	}
+
+	if value, ok := env[nginxVirtualProto]; ok && !contains(nginxProtocol, value) {
+		issues = append(issues, errorIssue(nginxVirtualProto, fmt.Sprintf("The protocol must be one of %s", strings.Join(nginxProtocol, ", "))))
+	}
+
+	if value, ok := env[nginxVirtualPath]; ok && !strings.HasPrefix(value, "/") {
+		issues = append(issues, errorIssue(nginxVirtualPath, "The path must start with /"))
+	}
+
+	if value, ok := env[nginxLetsEncryptHost]; ok {
+		for _, host := range splitList(value) {
+			if !contains(hosts, host) {
+				issues = append(issues, warningIssue(nginxLetsEncryptHost, fmt.Sprintf("The certificate of %s is requested but the host is not part of %s", host, nginxVirtualHost)))
+			}
+			if strings.Contains(host, "*") {
+				issues = append(issues, errorIssue(nginxLetsEncryptHost, fmt.Sprintf("The wildcard host name %s cannot be validated by Let's Encrypt with the HTTP challenge", host)))
+			}
+		}
+
+		if email, ok := env[nginxLetsEncryptEmail]; ok && !strings.Contains(email, "@") {
+			issues = append(issues, errorIssue(nginxLetsEncryptEmail, "Invalid email address"))
+		}
+	}
+
+	return issues
+}
+
+// nginxProxyRoutes returns the host names and the path routed to a container by nginx-proxy
+func nginxProxyRoutes(env map[string]string) []Route {
+	routes := make([]Route, 0)
+
+	for _, host := range splitList(env[nginxVirtualHost]) {
+		routes = append(routes, Route{Provider: NginxProxyProvider, Host: strings.ToLower(host), Path: env[nginxVirtualPath]})
+	}
+
+	return routes
+}
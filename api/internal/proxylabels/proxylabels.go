@@ -0,0 +1,109 @@
+package proxylabels
+
+import (
+	"sort"
+	"strings"
+)
+
+const (
+	// TraefikProvider represents the Docker provider of Traefik v2, configured with labels
+	TraefikProvider = "traefik"
+	// NginxProxyProvider represents nginx-proxy, configured with the environment variables of the containers
+	NginxProxyProvider = "nginx-proxy"
+
+	// ErrorSeverity is used for the problems preventing the reverse proxy from routing the requests
+	ErrorSeverity = "error"
+	// WarningSeverity is used for the problems that may prevent the reverse proxy from routing the requests
+	WarningSeverity = "warning"
+)
+
+// Issue represents a problem found in the reverse proxy configuration of a container or a service,
+// Key is the label or the environment variable the problem relates to
+type Issue struct {
+	Severity string `json:"Severity"`
+	Key      string `json:"Key,omitempty"`
+	Message  string `json:"Message"`
+}
+
+// Route represents a host name and an optional path routed by a reverse proxy to a container or a service
+type Route struct {
+	Provider string `json:"Provider"`
+	// Router is the name of the Traefik router declaring the route
+	Router string `json:"Router,omitempty"`
+	Host   string `json:"Host"`
+	Path   string `json:"Path,omitempty"`
+	// Resource is the name of the container or the service the route belongs to
+	Resource  string `json:"Resource,omitempty"`
+	StackName string `json:"StackName,omitempty"`
+}
+
+// Validate validates the reverse proxy configuration declared by the labels and the environment
+// variables of a container or a service
+func Validate(labels map[string]string, env map[string]string, swarm bool) []Issue {
+	issues := ValidateTraefikLabels(labels, swarm)
+	return append(issues, ValidateNginxProxyEnv(env)...)
+}
+
+// Routes returns the routes declared by the labels and the environment variables of a container or a service
+func Routes(labels map[string]string, env map[string]string) []Route {
+	routes := traefikRoutes(labels)
+	return append(routes, nginxProxyRoutes(env)...)
+}
+
+// HasErrors returns true if one of the issues prevents the reverse proxy from routing the requests
+func HasErrors(issues []Issue) bool {
+	for _, issue := range issues {
+		if issue.Severity == ErrorSeverity {
+			return true
+		}
+	}
+	return false
+}
+
+// EnvMap converts a list of KEY=value environment variables to a map
+func EnvMap(env []string) map[string]string {
+	values := make(map[string]string)
+	for _, variable := range env {
+		parts := strings.SplitN(variable, "=", 2)
+		if len(parts) == 2 {
+			values[parts[0]] = parts[1]
+		}
+	}
+	return values
+}
+
+func errorIssue(key, message string) Issue {
+	return Issue{Severity: ErrorSeverity, Key: key, Message: message}
+}
+
+func warningIssue(key, message string) Issue {
+	return Issue{Severity: WarningSeverity, Key: key, Message: message}
+}
+
+func splitList(value string) []string {
+	values := make([]string, 0)
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			values = append(values, item)
+		}
+	}
+	return values
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedStringKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
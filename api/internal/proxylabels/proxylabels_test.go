@@ -0,0 +1,157 @@
+package proxylabels
+
+import (
+	"strings"
+	"testing"
+)
+
+func issueKeys(issues []Issue, severity string) []string {
+	keys := make([]string, 0)
+	for _, issue := range issues {
+		if issue.Severity == severity {
+			keys = append(keys, issue.Key)
+		}
+	}
+	return keys
+}
+
+func TestValidateTraefikLabels(t *testing.T) {
+	tests := []struct {
+		name   string
+		labels map[string]string
+		swarm  bool
+		errors []string
+	}{
+		{
+			name: "valid router",
+			labels: map[string]string{
+				"traefik.enable":                                     "true",
+				"traefik.http.routers.web.rule":                      "Host(`example.com`) && PathPrefix(`/api`)",
+				"traefik.http.routers.web.tls":                       "true",
+				"traefik.http.routers.web.service":                   "web",
+				"traefik.http.services.web.loadbalancer.server.port": "8080",
+			},
+			swarm:  true,
+			errors: []string{},
+		},
+		{
+			name: "case insensitive options",
+			labels: map[string]string{
+				"traefik.http.routers.web.Rule":        "Host(`example.com`)",
+				"traefik.http.routers.web.EntryPoints": "websecure",
+			},
+			errors: []string{},
+		},
+		{
+			name: "unknown option and unbalanced rule",
+			labels: map[string]string{
+				"traefik.http.routers.web.rules": "Host(`example.com`)",
+				"traefik.http.routers.api.rule":  "Host(`example.com`",
+			},
+			errors: []string{"traefik.http.routers.api.rule", "traefik.http.routers.web.rules"},
+		},
+		{
+			name: "unknown matcher",
+			labels: map[string]string{
+				"traefik.tcp.routers.db.rule": "Host(`db.example.com`)",
+			},
+			errors: []string{"traefik.tcp.routers.db.rule"},
+		},
+		{
+			name: "missing port in swarm mode",
+			labels: map[string]string{
+				"traefik.http.routers.web.rule": "Host(`example.com`)",
+			},
+			swarm:  true,
+			errors: []string{"traefik.http.services"},
+		},
+		{
+			name: "disabled",
+			labels: map[string]string{
+				"traefik.enable":                "false",
+				"traefik.http.routers.web.rule": "Host(`example.com`",
+			},
+			errors: []string{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			errors := issueKeys(ValidateTraefikLabels(test.labels, test.swarm), ErrorSeverity)
+			if strings.Join(errors, " ") != strings.Join(test.errors, " ") {
+				t.Errorf("expected errors %v, got %v", test.errors, errors)
+			}
+		})
+	}
+}
+
+func TestValidateNginxProxyEnv(t *testing.T) {
+	env := map[string]string{
+		"VIRTUAL_HOST":     "example.com,*.example.org",
+		"VIRTUAL_PORT":     "http",
+		"LETSENCRYPT_HOST": "*.example.org",
+	}
+
+	errors := issueKeys(ValidateNginxProxyEnv(env), ErrorSeverity)
+	expected := []string{"VIRTUAL_PORT", "LETSENCRYPT_HOST"}
+	if strings.Join(errors, " ") != strings.Join(expected, " ") {
+		t.Errorf("expected errors %v, got %v", expected, errors)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	definition := &RouteDefinition{
+		Provider:     TraefikProvider,
+		Name:         "web",
+		Hosts:        []string{"example.com", "www.example.com"},
+		PathPrefix:   "/app",
+		Port:         8080,
+		TLS:          true,
+		CertResolver: "letsencrypt",
+	}
+
+	labels, _, err := Generate(definition)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if rule := labels["traefik.http.routers.web.rule"]; rule != "Host(`example.com`, `www.example.com`) && PathPrefix(`/app`)" {
+		t.Errorf("unexpected rule %s", rule)
+	}
+
+	if issues := ValidateTraefikLabels(labels, true); HasErrors(issues) {
+		t.Errorf("the generated labels are invalid: %v", issues)
+	}
+
+	definition.Provider = NginxProxyProvider
+	_, env, err := Generate(definition)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if issues := ValidateNginxProxyEnv(EnvMap(env)); len(issues) != 0 {
+		t.Errorf("the generated environment is invalid: %v", issues)
+	}
+}
+
+func TestConflicts(t *testing.T) {
+	existing := []Resource{
+		NewResource("blog", "", map[string]string{"traefik.http.routers.blog.rule": "Host(`example.com`)"}, nil),
+		NewResource("shop_web", "shop", nil, map[string]string{"VIRTUAL_HOST": "shop.example.com"}),
+	}
+
+	resource := NewResource("web", "", map[string]string{"traefik.http.routers.blog.rule": "Host(`Example.com`)"}, nil)
+	if issues := Conflicts(&resource, existing); len(issues) != 2 {
+		t.Errorf("expected a route and a router conflict, got %v", issues)
+	}
+
+	resource = NewResource("shop_web", "shop", nil, map[string]string{"VIRTUAL_HOST": "shop.example.com"})
+	if issues := Conflicts(&resource, existing); len(issues) != 0 {
+		t.Errorf("the resources of the same stack must not conflict, got %v", issues)
+	}
+
+	resource = NewResource("api", "", nil, map[string]string{"VIRTUAL_HOST": "shop.example.com", "VIRTUAL_PATH": "/api"})
+	if issues := Conflicts(&resource, existing); len(issues) != 0 {
+		t.Errorf("the routes of different paths must not conflict, got %v", issues)
+	}
+}
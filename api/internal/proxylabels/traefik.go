@@ -0,0 +1,370 @@
+package proxylabels
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// TraefikLabelPrefix is the prefix of the labels read by the Docker provider of Traefik
+const TraefikLabelPrefix = "traefik."
+
+var (
+	traefikRouterKeyRe     = regexp.MustCompile(`(?i)^traefik\.(http|tcp|udp)\.routers\.([a-zA-Z0-9_-]+)\.(.+)$`)
+	traefikServiceKeyRe    = regexp.MustCompile(`(?i)^traefik\.(http|tcp|udp)\.services\.([a-zA-Z0-9_-]+)\.(.+)$`)
+	traefikMiddlewareKeyRe = regexp.MustCompile(`(?i)^traefik\.(http|tcp)\.middlewares\.([a-zA-Z0-9_-]+)\.([a-z]+)(\..+)?$`)
+	traefikTLSDomainKeyRe  = regexp.MustCompile(`^tls\.domains\[[0-9]+\]\.(main|sans)$`)
+	traefikMatcherRe       = regexp.MustCompile(`([A-Za-z]+)\(`)
+	traefikHostRe          = regexp.MustCompile("Host(?:SNI|Header)?\\(([^)]*)\\)")
+	traefikPathRe          = regexp.MustCompile("Path(?:Prefix)?\\(([^)]*)\\)")
+	traefikArgumentRe      = regexp.MustCompile("`([^`]*)`")
+)
+
+var traefikRouterKeys = map[string]map[string]string{
+	"http": {
+		"rule": "rule", "entrypoints": "list", "middlewares": "list", "service": "name", "priority": "int",
+		"tls": "bool", "tls.certresolver": "name", "tls.options": "name",
+	},
+	"tcp": {
+		"rule": "rule", "entrypoints": "list", "middlewares": "list", "service": "name", "priority": "int",
+		"tls": "bool", "tls.certresolver": "name", "tls.options": "name", "tls.passthrough": "bool",
+	},
+	"udp": {
+		"entrypoints": "list", "service": "name",
+	},
+}
+
+var traefikMatchers = map[string][]string{
+	"http": {"Host", "HostHeader", "HostRegexp", "Method", "Path", "PathPrefix", "Headers", "HeadersRegexp", "Query", "ClientIP"},
+	"tcp":  {"HostSNI", "ClientIP"},
+}
+
+var traefikMiddlewareTypes = map[string][]string{
+	"http": {
+		"addprefix", "basicauth", "buffering", "chain", "circuitbreaker", "compress", "contenttype", "digestauth",
+		"errors", "forwardauth", "headers", "ipwhitelist", "inflightreq", "passtlsclientcert", "plugin", "ratelimit",
+		"redirectregex", "redirectscheme", "replacepath", "replacepathregex", "retry", "stripprefix", "stripprefixregex",
+	},
+	"tcp": {"inflightconn", "ipwhitelist"},
+}
+
+// traefikConfiguration represents the routers, services and middlewares declared by the labels of a resource
+type traefikConfiguration struct {
+	enabled     *bool
+	routers     map[string]map[string]string
+	services    map[string]map[string]string
+	middlewares map[string]bool
+}
+
+// routerKey identifies a router of a protocol, the routers of the different protocols are distinct
+func routerKey(protocol, name string) string {
+	return protocol + "." + name
+}
+
+// ValidateTraefikLabels validates the labels read by the Docker provider of Traefik v2: the keys must be
+// part of the configuration schema, the values must have the expected type, the rules must use the known
+// matchers and the routers must reference services and middlewares Traefik can resolve.
+// The Swarm mode of the Docker provider cannot detect the port of the services, it must be declared.
+func ValidateTraefikLabels(labels map[string]string, swarm bool) []Issue {
+	issues := make([]Issue, 0)
+	configuration := parseTraefikLabels(labels, &issues)
+
+	// the labels of the disabled containers are ignored by Traefik
+	if configuration.enabled != nil && !*configuration.enabled {
+		return make([]Issue, 0)
+	}
+
+	for _, key := range sortedKeys(configuration.routers) {
+		router := configuration.routers[key]
+		protocol := strings.SplitN(key, ".", 2)[0]
+		name := strings.SplitN(key, ".", 2)[1]
+		prefix := fmt.Sprintf("traefik.%s.routers.%s.", protocol, name)
+
+		if protocol != "udp" {
+			if _, ok := router["rule"]; !ok {
+				issues = append(issues, errorIssue(prefix+"rule", fmt.Sprintf("The router %s has no rule", name)))
+			}
+		}
+
+		serviceName, ok := router["service"]
+		servicesOfProtocol := servicesOf(configuration, protocol)
+		switch {
+		case !ok && len(servicesOfProtocol) > 1:
+			issues = append(issues, errorIssue(prefix+"service", fmt.Sprintf("The router %s must reference one of the services %s", name, strings.Join(servicesOfProtocol, ", "))))
+		case ok && !strings.Contains(serviceName, "@") && !contains(servicesOfProtocol, serviceName):
+			issues = append(issues, warningIssue(prefix+"service", fmt.Sprintf("The service %s is not declared by the labels, it must be declared by another provider", serviceName)))
+		}
+
+		if middlewares, ok := router["middlewares"]; ok {
+			for _, middleware := range splitList(middlewares) {
+				if !strings.Contains(middleware, "@") && !configuration.middlewares[routerKey(protocol, middleware)] {
+					issues = append(issues, warningIssue(prefix+"middlewares", fmt.Sprintf("The middleware %s is not declared by the labels, it must be declared by another container", middleware)))
+				}
+			}
+		}
+	}
+
+	if swarm {
+		for _, key := range sortedKeys(configuration.services) {
+			protocol := strings.SplitN(key, ".", 2)[0]
+			name := strings.SplitN(key, ".", 2)[1]
+			if _, ok := configuration.services[key]["loadbalancer.server.port"]; !ok {
+				issues = append(issues, errorIssue(fmt.Sprintf("traefik.%s.services.%s.loadbalancer.server.port", protocol, name), "The port of the services must be declared in Swarm mode"))
+			}
+		}
+
+		if len(configuration.services) == 0 && len(configuration.routers) > 0 {
+			issues = append(issues, errorIssue("traefik.http.services", "The port of the services must be declared with loadbalancer.server.port in Swarm mode"))
+		}
+	}
+
+	return issues
+}
+
+func parseTraefikLabels(labels map[string]string, issues *[]Issue) *traefikConfiguration {
+	configuration := &traefikConfiguration{
+		routers:     make(map[string]map[string]string),
+		services:    make(map[string]map[string]string),
+		middlewares: make(map[string]bool),
+	}
+
+	for _, key := range sortedStringKeys(labels) {
+		if !strings.HasPrefix(strings.ToLower(key), TraefikLabelPrefix) {
+			continue
+		}
+		value := labels[key]
+
+		// the options are case insensitive, the names of the routers, services and middlewares are not
+		switch strings.ToLower(key) {
+		case "traefik.enable":
+			enabled, err := strconv.ParseBool(value)
+			if err != nil {
+				*issues = append(*issues, errorIssue(key, "The value must be true or false"))
+				continue
+			}
+			configuration.enabled = &enabled
+			continue
+		case "traefik.docker.network", "traefik.docker.lbswarm", "traefik.tags":
+			continue
+		}
+
+		if match := traefikRouterKeyRe.FindStringSubmatch(key); match != nil {
+			protocol, name, option := strings.ToLower(match[1]), match[2], strings.ToLower(match[3])
+
+			kind, ok := traefikRouterKeys[protocol][option]
+			if !ok && traefikTLSDomainKeyRe.MatchString(option) && protocol != "udp" {
+				kind, ok = "list", true
+			}
+			if !ok {
+				*issues = append(*issues, errorIssue(key, fmt.Sprintf("Unknown option %s of the %s routers", option, protocol)))
+				continue
+			}
+
+			if message := validateTraefikValue(kind, protocol, value); message != "" {
+				*issues = append(*issues, errorIssue(key, message))
+				continue
+			}
+
+			router, ok := configuration.routers[routerKey(protocol, name)]
+			if !ok {
+				router = make(map[string]string)
+				configuration.routers[routerKey(protocol, name)] = router
+			}
+			router[option] = value
+			continue
+		}
+
+		if match := traefikServiceKeyRe.FindStringSubmatch(key); match != nil {
+			protocol, name, option := strings.ToLower(match[1]), match[2], strings.ToLower(match[3])
+
+			if !strings.HasPrefix(option, "loadbalancer.") && !(protocol == "http" && (strings.HasPrefix(option, "weighted.") || strings.HasPrefix(option, "mirroring."))) {
+				*issues = append(*issues, errorIssue(key, fmt.Sprintf("Unknown option %s of the %s services", option, protocol)))
+				continue
+			}
+
+			switch option {
+			case "loadbalancer.server.port":
+				port, err := strconv.Atoi(value)
+				if err != nil || port < 1 || port > 65535 {
+					*issues = append(*issues, errorIssue(key, "The port must be a number between 1 and 65535"))
+					continue
+				}
+			case "loadbalancer.server.scheme":
+				if value != "http" && value != "https" && value != "h2c" {
+					*issues = append(*issues, errorIssue(key, "The scheme must be one of http, https or h2c"))
+					continue
+				}
+			case "loadbalancer.passhostheader":
+				if _, err := strconv.ParseBool(value); err != nil {
+					*issues = append(*issues, errorIssue(key, "The value must be true or false"))
+					continue
+				}
+			}
+
+			service, ok := configuration.services[routerKey(protocol, name)]
+			if !ok {
+				service = make(map[string]string)
+				configuration.services[routerKey(protocol, name)] = service
+			}
+			service[option] = value
+			continue
+		}
+
+		if match := traefikMiddlewareKeyRe.FindStringSubmatch(key); match != nil {
+			protocol, name, middlewareType := strings.ToLower(match[1]), match[2], strings.ToLower(match[3])
+			if !contains(traefikMiddlewareTypes[protocol], middlewareType) {
+				*issues = append(*issues, errorIssue(key, fmt.Sprintf("Unknown %s middleware type %s", protocol, middlewareType)))
+				continue
+			}
+			configuration.middlewares[routerKey(protocol, name)] = true
+			continue
+		}
+
+		*issues = append(*issues, errorIssue(key, "Unknown Traefik label, the configuration of the container is ignored by Traefik"))
+	}
+
+	return configuration
+}
+
+func validateTraefikValue(kind, protocol, value string) string {
+	switch kind {
+	case "bool":
+		if _, err := strconv.ParseBool(value); err != nil {
+			return "The value must be true or false"
+		}
+	case "int":
+		if _, err := strconv.Atoi(value); err != nil {
+			return "The value must be a number"
+		}
+	case "list", "name":
+		if strings.TrimSpace(value) == "" {
+			return "The value cannot be empty"
+		}
+	case "rule":
+		return validateTraefikRule(protocol, value)
+	}
+	return ""
+}
+
+// validateTraefikRule ensures that the parentheses and the backticks of a rule are balanced and that
+// the rule only uses the matchers of the protocol of the router
+func validateTraefikRule(protocol, rule string) string {
+	if strings.TrimSpace(rule) == "" {
+		return "The rule cannot be empty"
+	}
+
+	if strings.Count(rule, "`")%2 != 0 {
+		return "The rule has an unterminated backtick"
+	}
+
+	depth := 0
+	quoted := false
+	for _, c := range rule {
+		switch {
+		case c == '`':
+			quoted = !quoted
+		case c == '(' && !quoted:
+			depth++
+		case c == ')' && !quoted:
+			depth--
+			if depth < 0 {
+				return "The rule has unbalanced parentheses"
+			}
+		}
+	}
+	if depth != 0 {
+		return "The rule has unbalanced parentheses"
+	}
+
+	unquoted := traefikArgumentRe.ReplaceAllString(rule, "``")
+	for _, match := range traefikMatcherRe.FindAllStringSubmatch(unquoted, -1) {
+		if !contains(traefikMatchers[protocol], match[1]) {
+			return fmt.Sprintf("Unknown matcher %s, the %s rules support %s", match[1], protocol, strings.Join(traefikMatchers[protocol], ", "))
+		}
+	}
+
+	if strings.Contains(unquoted, "\"") {
+		return "The arguments of the matchers must be enclosed in backticks"
+	}
+
+	return ""
+}
+
+// traefikRoutes returns the host names and the paths routed to a resource by the HTTP and TCP routers
+func traefikRoutes(labels map[string]string) []Route {
+	routes := make([]Route, 0)
+
+	issues := make([]Issue, 0)
+	configuration := parseTraefikLabels(labels, &issues)
+	if configuration.enabled != nil && !*configuration.enabled {
+		return routes
+	}
+
+	for _, key := range sortedKeys(configuration.routers) {
+		rule, ok := configuration.routers[key]["rule"]
+		if !ok {
+			continue
+		}
+		name := strings.SplitN(key, ".", 2)[1]
+
+		paths := []string{""}
+		for _, match := range traefikPathRe.FindAllStringSubmatch(rule, -1) {
+			if arguments := traefikArguments(match[1]); len(arguments) > 0 {
+				if paths[0] == "" {
+					paths = paths[:0]
+				}
+				paths = append(paths, arguments...)
+			}
+		}
+
+		for _, match := range traefikHostRe.FindAllStringSubmatch(rule, -1) {
+			for _, host := range traefikArguments(match[1]) {
+				for _, path := range paths {
+					routes = append(routes, Route{Provider: TraefikProvider, Router: name, Host: strings.ToLower(host), Path: path})
+				}
+			}
+		}
+	}
+
+	return routes
+}
+
+// traefikRouterNames returns the names of the routers declared by the labels of a resource
+func traefikRouterNames(labels map[string]string) []string {
+	issues := make([]Issue, 0)
+	configuration := parseTraefikLabels(labels, &issues)
+	if configuration.enabled != nil && !*configuration.enabled {
+		return nil
+	}
+	return sortedKeys(configuration.routers)
+}
+
+func traefikArguments(arguments string) []string {
+	values := make([]string, 0)
+	for _, match := range traefikArgumentRe.FindAllStringSubmatch(arguments, -1) {
+		values = append(values, match[1])
+	}
+	return values
+}
+
+func servicesOf(configuration *traefikConfiguration, protocol string) []string {
+	names := make([]string, 0)
+	for key := range configuration.services {
+		if strings.HasPrefix(key, protocol+".") {
+			names = append(names, strings.SplitN(key, ".", 2)[1])
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func sortedKeys(values map[string]map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,332 @@
+package report
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/internal/alerting"
+)
+
+const checkInterval = 15 * time.Minute
+
+var errNoRecipient = errors.New("The report subscription does not define an email address")
+
+var reportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"join": strings.Join,
+}).Parse(`Portainer {{ .Frequency }} report
+Period: {{ .Since }} - {{ .Until }}
+{{ if .Endpoints }}
+Endpoint health
+---------------
+{{ .Endpoints.Up }} endpoint(s) up, {{ .Endpoints.Down }} endpoint(s) down.
+{{ range .Endpoints.DownEndpoints }}  - {{ . }} is down
+{{ end }}{{ end }}{{ if .Containers }}
+Container health
+----------------
+{{ .Containers.Running }} running, {{ .Containers.Stopped }} stopped, {{ .Containers.Unhealthy }} unhealthy container(s).
+{{ range .Containers.UnhealthyEndpoints }}  - {{ .Name }}: {{ .Count }} unhealthy container(s)
+{{ end }}{{ end }}{{ if .HostPressure }}
+Host resource pressure
+----------------------
+{{ range .HostPressure.Hosts }}  - {{ .Endpoint }}:{{ if .Filesystems }} disk usage above threshold on {{ join .Filesystems ", " }};{{ end }}{{ if .Memory }} memory usage above threshold;{{ end }}{{ if .Load }} load average above threshold;{{ end }}
+{{ else }}No host under resource pressure.
+{{ end }}{{ end }}{{ if .SettingsChanges }}
+Settings changes
+----------------
+{{ range .SettingsChanges.Changes }}  - {{ .Time }} by {{ .Username }}: {{ join .Fields ", " }}
+{{ else }}No settings change.
+{{ end }}{{ end }}`))
+
+type (
+	reportData struct {
+		Frequency       portainer.ReportFrequency
+		Since           string
+		Until           string
+		Endpoints       *endpointHealth
+		Containers      *containerHealth
+		HostPressure    *hostPressureReport
+		SettingsChanges *settingsChangesReport
+	}
+
+	endpointHealth struct {
+		Up            int
+		Down          int
+		DownEndpoints []string
+	}
+
+	containerHealth struct {
+		Running            int
+		Stopped            int
+		Unhealthy          int
+		UnhealthyEndpoints []endpointCount
+	}
+
+	endpointCount struct {
+		Name  string
+		Count int
+	}
+
+	hostPressureReport struct {
+		Hosts []hostPressure
+	}
+
+	hostPressure struct {
+		Endpoint    string
+		Filesystems []string
+		Memory      bool
+		Load        bool
+	}
+
+	settingsChangesReport struct {
+		Changes []settingsChange
+	}
+
+	settingsChange struct {
+		Time     string
+		Username string
+		Fields   []string
+	}
+)
+
+// Service represents a service used to generate periodic reports and send them by email
+// to the administrators who subscribed to them.
+type Service struct {
+	dataStore   portainer.DataStore
+	mailService portainer.MailService
+}
+
+// NewService returns a new instance of a report service
+func NewService(dataStore portainer.DataStore, mailService portainer.MailService) *Service {
+	return &Service{
+		dataStore:   dataStore,
+		mailService: mailService,
+	}
+}
+
+// Start starts a background routine sending the reports when they are due
+func (service *Service) Start() {
+	go func() {
+		ticker := time.NewTicker(checkInterval)
+		for range ticker.C {
+			service.sendDueReports()
+		}
+	}()
+}
+
+// Period returns the duration covered by a report sent at the specified frequency
+func Period(frequency portainer.ReportFrequency) time.Duration {
+	if frequency == portainer.WeeklyReport {
+		return 7 * 24 * time.Hour
+	}
+	return 24 * time.Hour
+}
+
+func (service *Service) sendDueReports() {
+	subscriptions, err := service.dataStore.ReportSubscription().ReportSubscriptions()
+	if err != nil {
+		log.Printf("[WARN] [internal,report] [message: unable to retrieve report subscriptions] [error: %s]", err)
+		return
+	}
+
+	now := time.Now()
+	for idx := range subscriptions {
+		subscription := &subscriptions[idx]
+		if !subscription.Enabled {
+			continue
+		}
+
+		if time.Unix(subscription.LastSent, 0).Add(Period(subscription.Frequency)).After(now) {
+			continue
+		}
+
+		user, err := service.dataStore.User().User(subscription.UserID)
+		if err != nil || user.Role != portainer.AdministratorRole {
+			continue
+		}
+
+		err = service.Send(subscription)
+		if err != nil {
+			log.Printf("[WARN] [internal,report] [message: unable to send report] [user: %s] [error: %s]", user.Username, err)
+		}
+	}
+}
+
+// Send renders the report of a subscription covering the period since the last report and sends it by email
+func (service *Service) Send(subscription *portainer.ReportSubscription) error {
+	if subscription.Email == "" {
+		return errNoRecipient
+	}
+
+	settings, err := service.dataStore.Settings().Settings()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	since := now.Add(-Period(subscription.Frequency)).Unix()
+	if subscription.LastSent > since {
+		since = subscription.LastSent
+	}
+
+	subject, body, err := service.Render(subscription, since)
+	if err != nil {
+		return err
+	}
+
+	err = service.mailService.Send(&settings.SMTPSettings, []string{subscription.Email}, subject, body)
+	if err != nil {
+		return err
+	}
+
+	subscription.LastSent = now.Unix()
+	return service.dataStore.ReportSubscription().UpdateReportSubscription(subscription.UserID, subscription)
+}
+
+// Render returns the subject and the body of the report of a subscription covering the period since
+// the specified time
+func (service *Service) Render(subscription *portainer.ReportSubscription, since int64) (string, string, error) {
+	now := time.Now()
+
+	data := &reportData{
+		Frequency: subscription.Frequency,
+		Since:     time.Unix(since, 0).Format(time.RFC1123),
+		Until:     now.Format(time.RFC1123),
+	}
+
+	endpoints, err := service.dataStore.Endpoint().Endpoints()
+	if err != nil {
+		return "", "", err
+	}
+
+	for _, section := range subscription.Sections {
+		switch section {
+		case portainer.EndpointHealthReportSection:
+			data.Endpoints = endpointHealthSection(endpoints)
+		case portainer.ContainerHealthReportSection:
+			data.Containers = containerHealthSection(endpoints)
+		case portainer.HostPressureReportSection:
+			settings, err := service.dataStore.Settings().Settings()
+			if err != nil {
+				return "", "", err
+			}
+			data.HostPressure = hostPressureSection(endpoints, &settings.HostAlerting)
+		case portainer.SettingsChangesReportSection:
+			changes, err := service.dataStore.SettingsHistory().SettingsChanges()
+			if err != nil {
+				return "", "", err
+			}
+			data.SettingsChanges = settingsChangesSection(changes, since)
+		}
+	}
+
+	var body bytes.Buffer
+	err = reportTemplate.Execute(&body, data)
+	if err != nil {
+		return "", "", err
+	}
+
+	subject := fmt.Sprintf("Portainer %s report - %s", subscription.Frequency, now.Format("2006-01-02"))
+	return subject, body.String(), nil
+}
+
+func endpointHealthSection(endpoints []portainer.Endpoint) *endpointHealth {
+	section := &endpointHealth{
+		DownEndpoints: make([]string, 0),
+	}
+
+	for _, endpoint := range endpoints {
+		if endpoint.Status == portainer.EndpointStatusDown {
+			section.Down++
+			section.DownEndpoints = append(section.DownEndpoints, endpoint.Name)
+			continue
+		}
+		section.Up++
+	}
+
+	return section
+}
+
+func containerHealthSection(endpoints []portainer.Endpoint) *containerHealth {
+	section := &containerHealth{
+		UnhealthyEndpoints: make([]endpointCount, 0),
+	}
+
+	for _, endpoint := range endpoints {
+		if len(endpoint.Snapshots) == 0 {
+			continue
+		}
+
+		snapshot := endpoint.Snapshots[0]
+		section.Running += snapshot.RunningContainerCount
+		section.Stopped += snapshot.StoppedContainerCount
+		section.Unhealthy += snapshot.UnhealthyContainerCount
+
+		if snapshot.UnhealthyContainerCount > 0 {
+			section.UnhealthyEndpoints = append(section.UnhealthyEndpoints, endpointCount{Name: endpoint.Name, Count: snapshot.UnhealthyContainerCount})
+		}
+	}
+
+	return section
+}
+
+func hostPressureSection(endpoints []portainer.Endpoint, settings *portainer.HostAlertingSettings) *hostPressureReport {
+	section := &hostPressureReport{
+		Hosts: make([]hostPressure, 0),
+	}
+
+	for _, endpoint := range endpoints {
+		if len(endpoint.Snapshots) == 0 {
+			continue
+		}
+
+		snapshot := endpoint.Snapshots[0]
+		pressure := alerting.EvaluateHostPressure(snapshot.Host, snapshot.TotalCPU, settings)
+		if len(pressure.Filesystems) == 0 && !pressure.Memory && !pressure.Load {
+			continue
+		}
+
+		host := hostPressure{
+			Endpoint: endpoint.Name,
+			Memory:   pressure.Memory,
+			Load:     pressure.Load,
+		}
+		for _, filesystem := range pressure.Filesystems {
+			host.Filesystems = append(host.Filesystems, filesystem.MountPoint)
+		}
+
+		section.Hosts = append(section.Hosts, host)
+	}
+
+	return section
+}
+
+func settingsChangesSection(changes []portainer.SettingsChange, since int64) *settingsChangesReport {
+	section := &settingsChangesReport{
+		Changes: make([]settingsChange, 0),
+	}
+
+	for _, change := range changes {
+		if change.Time < since {
+			continue
+		}
+
+		fields := make([]string, 0, len(change.Diff))
+		for _, field := range change.Diff {
+			fields = append(fields, field.Field)
+		}
+
+		section.Changes = append(section.Changes, settingsChange{
+			Time:     time.Unix(change.Time, 0).Format(time.RFC1123),
+			Username: change.Username,
+			Fields:   fields,
+		})
+	}
+
+	return section
+}
@@ -0,0 +1,137 @@
+package retention
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/portainer/portainer/api"
+)
+
+// purgeInterval is the interval between two scheduled purges
+const purgeInterval = time.Hour
+
+// defaultRetentionDays are the retentions applied to the categories without retention in the settings
+var defaultRetentionDays = map[portainer.RetentionCategory]int{
+	portainer.RetentionUserActivity:    90,
+	portainer.RetentionSettingsHistory: 365,
+	portainer.RetentionTasks:           1,
+	portainer.RetentionNotifications:   30,
+	portainer.RetentionResourceSamples: 90,
+	portainer.RetentionAutoHealActions: 90,
+}
+
+// Service represents a service used to remove the records older than the retention defined
+// in the settings for their category. It provides an interface to start a periodic background
+// purge as well as to trigger a purge on demand.
+type Service struct {
+	dataStore  portainer.DataStore
+	mu         sync.Mutex
+	lastReport *portainer.RetentionReport
+}
+
+// NewService creates a new instance of a service
+func NewService(dataStore portainer.DataStore) *Service {
+	return &Service{
+		dataStore: dataStore,
+	}
+}
+
+// Start will start a background routine to execute a periodic purge
+func (service *Service) Start() {
+	go func() {
+		ticker := time.NewTicker(purgeInterval)
+		for range ticker.C {
+			report, err := service.purge(false)
+			if err != nil {
+				log.Printf("[ERROR] [internal,retention] [message: background schedule error (retention purge).] [error: %s]", err)
+				continue
+			}
+			logReport(report)
+		}
+	}()
+}
+
+// Purge removes the records older than their retention and returns a report of the removed records
+func (service *Service) Purge() (*portainer.RetentionReport, error) {
+	return service.purge(true)
+}
+
+// LastReport returns the report of the last purge, it is nil when no purge was executed since the start of the server
+func (service *Service) LastReport() *portainer.RetentionReport {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+
+	return service.lastReport
+}
+
+func (service *Service) purge(manual bool) (*portainer.RetentionReport, error) {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+
+	settings, err := service.dataStore.Settings().Settings()
+	if err != nil {
+		return nil, err
+	}
+
+	purges := []struct {
+		category portainer.RetentionCategory
+		days     int
+		delete   func(time int64) (int, int64, error)
+	}{
+		{portainer.RetentionUserActivity, settings.Retention.UserActivityDays, service.dataStore.UserActivity().DeleteUserActivitiesBefore},
+		{portainer.RetentionSettingsHistory, settings.Retention.SettingsHistoryDays, service.dataStore.SettingsHistory().DeleteSettingsChangesBefore},
+		{portainer.RetentionTasks, settings.Retention.TaskDays, service.dataStore.Task().DeleteTasksFinishedBefore},
+		{portainer.RetentionNotifications, settings.Retention.NotificationDays, service.dataStore.UserNotification().DeleteUserNotificationsBefore},
+		{portainer.RetentionResourceSamples, settings.Retention.ResourceSampleDays, service.dataStore.ResourceSample().DeleteResourceSamplesBefore},
+		{portainer.RetentionAutoHealActions, settings.Retention.AutoHealActionDays, service.dataStore.AutoHealAction().DeleteAutoHealActionsBefore},
+	}
+
+	now := time.Now()
+	report := &portainer.RetentionReport{
+		Date:       now.Unix(),
+		Manual:     manual,
+		Categories: make([]portainer.RetentionCategoryReport, 0, len(purges)),
+	}
+
+	for _, purge := range purges {
+		days := RetentionDays(purge.category, purge.days)
+
+		deleted, size := 0, int64(0)
+		if days != portainer.RetentionForever {
+			deleted, size, err = purge.delete(now.AddDate(0, 0, -days).Unix())
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		report.Categories = append(report.Categories, portainer.RetentionCategoryReport{
+			Category:       purge.category,
+			RetentionDays:  days,
+			Deleted:        deleted,
+			ReclaimedBytes: size,
+		})
+		report.ReclaimedBytes += size
+	}
+
+	service.lastReport = report
+	return report, nil
+}
+
+// RetentionDays returns the retention of a category, the default retention of the category
+// is returned when the retention defined in the settings is 0
+func RetentionDays(category portainer.RetentionCategory, days int) int {
+	if days > 0 || days == portainer.RetentionForever {
+		return days
+	}
+	return defaultRetentionDays[category]
+}
+
+func logReport(report *portainer.RetentionReport) {
+	for _, category := range report.Categories {
+		if category.Deleted == 0 {
+			continue
+		}
+		log.Printf("[INFO] [internal,retention] [message: expired records removed] [category: %s] [deleted: %d] [reclaimed_bytes: %d]", category.Category, category.Deleted, category.ReclaimedBytes)
+	}
+}
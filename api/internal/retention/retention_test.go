@@ -0,0 +1,22 @@
+package retention
+
+import (
+	"testing"
+
+	"github.com/portainer/portainer/api"
+)
+
+func TestRetentionDays(t *testing.T) {
+	if days := RetentionDays(portainer.RetentionTasks, 0); days != 1 {
+		t.Errorf("expected the default task retention of 1 day, got %d", days)
+	}
+	if days := RetentionDays(portainer.RetentionResourceSamples, 0); days != 90 {
+		t.Errorf("expected the default resource sample retention of 90 days, got %d", days)
+	}
+	if days := RetentionDays(portainer.RetentionUserActivity, 30); days != 30 {
+		t.Errorf("expected the configured retention of 30 days, got %d", days)
+	}
+	if days := RetentionDays(portainer.RetentionUserActivity, portainer.RetentionForever); days != portainer.RetentionForever {
+		t.Errorf("expected the user activity to be kept forever, got %d", days)
+	}
+}
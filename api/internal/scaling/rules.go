@@ -0,0 +1,112 @@
+package scaling
+
+import (
+	"errors"
+	"time"
+
+	"github.com/portainer/portainer/api"
+)
+
+const timeOfDayLayout = "15:04"
+
+// ValidateSchedule ensures that the timezone and the rules of a schedule are valid.
+func ValidateSchedule(schedule *portainer.ScalingSchedule) error {
+	_, err := time.LoadLocation(schedule.Timezone)
+	if err != nil {
+		return errors.New("Invalid timezone")
+	}
+
+	if schedule.DefaultReplicas < 0 {
+		return errors.New("Invalid default replicas value. Value must be a positive number")
+	}
+
+	for _, rule := range schedule.Rules {
+		for _, day := range rule.Days {
+			if day < 0 || day > 6 {
+				return errors.New("Invalid rule day. Value must be between 0 (Sunday) and 6 (Saturday)")
+			}
+		}
+
+		_, err := minutesOfDay(rule.StartTime)
+		if err != nil {
+			return errors.New("Invalid rule start time. Value must use the HH:MM format")
+		}
+
+		_, err = minutesOfDay(rule.EndTime)
+		if err != nil {
+			return errors.New("Invalid rule end time. Value must use the HH:MM format")
+		}
+
+		if rule.Replicas < 0 {
+			return errors.New("Invalid rule replicas value. Value must be a positive number")
+		}
+	}
+
+	return nil
+}
+
+// DesiredReplicas returns the number of replicas required by a schedule at a specific time.
+// The first matching rule is used, the default number of replicas is used when no rule matches.
+func DesiredReplicas(schedule *portainer.ScalingSchedule, now time.Time) (int, error) {
+	location, err := time.LoadLocation(schedule.Timezone)
+	if err != nil {
+		return 0, err
+	}
+
+	now = now.In(location)
+	currentMinutes := now.Hour()*60 + now.Minute()
+	today := int(now.Weekday())
+	yesterday := (today + 6) % 7
+
+	for _, rule := range schedule.Rules {
+		start, err := minutesOfDay(rule.StartTime)
+		if err != nil {
+			return 0, err
+		}
+
+		end, err := minutesOfDay(rule.EndTime)
+		if err != nil {
+			return 0, err
+		}
+
+		if start <= end {
+			if currentMinutes >= start && currentMinutes < end && ruleAppliesOnDay(&rule, today) {
+				return rule.Replicas, nil
+			}
+			continue
+		}
+
+		// the window spans over midnight, the days of the rule refer to the day the window starts
+		if currentMinutes >= start && ruleAppliesOnDay(&rule, today) {
+			return rule.Replicas, nil
+		}
+		if currentMinutes < end && ruleAppliesOnDay(&rule, yesterday) {
+			return rule.Replicas, nil
+		}
+	}
+
+	return schedule.DefaultReplicas, nil
+}
+
+func ruleAppliesOnDay(rule *portainer.ScalingRule, day int) bool {
+	if len(rule.Days) == 0 {
+		return true
+	}
+
+	for _, ruleDay := range rule.Days {
+		if ruleDay == day {
+			return true
+		}
+	}
+
+	return false
+}
+
+func minutesOfDay(value string) (int, error) {
+	t, err := time.Parse(timeOfDayLayout, value)
+	if err != nil {
+		return 0, err
+	}
+
+	return t.Hour()*60 + t.Minute(), nil
+}
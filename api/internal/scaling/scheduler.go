@@ -0,0 +1,208 @@
+package scaling
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/docker"
+	"github.com/portainer/portainer/api/internal/maintenance"
+	"github.com/portainer/portainer/api/kubernetes/cli"
+)
+
+const schedulerInterval = time.Minute
+
+var errUnsupportedTargetType = errors.New("Unsupported scaling target type")
+
+// Scheduler represents a service used to execute scaling schedules.
+// Schedules are evaluated every minute and the target is only scaled when the number of
+// replicas required by the schedule changes, so that manual scaling operations are preserved
+// until the next time window starts.
+type Scheduler struct {
+	dataStore               portainer.DataStore
+	dockerClientFactory     *docker.ClientFactory
+	kubernetesClientFactory *cli.ClientFactory
+	notificationService     portainer.NotificationService
+	refreshSignal           chan struct{}
+	heartbeatMutex          sync.Mutex
+	lastHeartbeat           time.Time
+}
+
+// NewScheduler creates a new instance of a scheduler
+func NewScheduler(dataStore portainer.DataStore, dockerClientFactory *docker.ClientFactory, kubernetesClientFactory *cli.ClientFactory, notificationService portainer.NotificationService) *Scheduler {
+	return &Scheduler{
+		dataStore:               dataStore,
+		dockerClientFactory:     dockerClientFactory,
+		kubernetesClientFactory: kubernetesClientFactory,
+		notificationService:     notificationService,
+	}
+}
+
+// Start will start a background routine to execute the scaling schedules
+func (scheduler *Scheduler) Start() {
+	if scheduler.refreshSignal != nil {
+		return
+	}
+
+	scheduler.refreshSignal = make(chan struct{})
+	scheduler.beat()
+
+	ticker := time.NewTicker(schedulerInterval)
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				scheduler.beat()
+				err := scheduler.executeSchedules()
+				if err != nil {
+					log.Printf("[ERROR] [internal,scaling] [message: background schedule error (scaling schedules).] [error: %s]", err)
+				}
+
+			case <-scheduler.refreshSignal:
+				log.Println("[DEBUG] [internal,scaling] [message: shutting down scaling scheduler]")
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+}
+
+// Heartbeat returns the time the scheduler last evaluated the schedules and the interval between two evaluations
+func (scheduler *Scheduler) Heartbeat() (time.Time, time.Duration) {
+	scheduler.heartbeatMutex.Lock()
+	defer scheduler.heartbeatMutex.Unlock()
+	return scheduler.lastHeartbeat, schedulerInterval
+}
+
+func (scheduler *Scheduler) beat() {
+	scheduler.heartbeatMutex.Lock()
+	scheduler.lastHeartbeat = time.Now()
+	scheduler.heartbeatMutex.Unlock()
+}
+
+func (scheduler *Scheduler) executeSchedules() error {
+	schedules, err := scheduler.dataStore.ScalingSchedule().ScalingSchedules()
+	if err != nil {
+		return err
+	}
+
+	for idx := range schedules {
+		schedule := &schedules[idx]
+		if !schedule.Enabled {
+			continue
+		}
+
+		// the schedule is executed at the end of the maintenance window, as the replicas it requires are not applied yet
+		endpoint, err := scheduler.dataStore.Endpoint().Endpoint(schedule.EndpointID)
+		if err == nil && maintenance.InMaintenance(scheduler.dataStore, endpoint) {
+			continue
+		}
+
+		err = scheduler.ExecuteSchedule(schedule)
+		if err != nil {
+			log.Printf("[WARN] [internal,scaling] [message: unable to execute scaling schedule] [schedule: %s] [error: %s]", schedule.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// ExecuteSchedule scales the target of the schedule if the number of replicas required at the current time
+// differs from the number of replicas applied during the last execution. The execution result is persisted.
+func (scheduler *Scheduler) ExecuteSchedule(schedule *portainer.ScalingSchedule) error {
+	replicas, err := DesiredReplicas(schedule, time.Now())
+	if err != nil {
+		return scheduler.saveExecution(schedule, err)
+	}
+
+	if replicas == schedule.AppliedReplicas {
+		return nil
+	}
+
+	endpoint, err := scheduler.dataStore.Endpoint().Endpoint(schedule.EndpointID)
+	if err != nil {
+		return scheduler.saveExecution(schedule, err)
+	}
+
+	switch schedule.TargetType {
+	case portainer.ScalingTargetSwarmService:
+		err = scheduler.scaleService(endpoint, schedule.TargetName, replicas)
+	case portainer.ScalingTargetKubernetesDeployment:
+		err = scheduler.scaleDeployment(endpoint, schedule.Namespace, schedule.TargetName, replicas)
+	default:
+		err = errUnsupportedTargetType
+	}
+
+	if err == nil {
+		schedule.AppliedReplicas = replicas
+	}
+
+	return scheduler.saveExecution(schedule, err)
+}
+
+// saveExecution persists the result of an execution. A notification is raised when the execution fails
+// with an error different from the one of the previous execution.
+func (scheduler *Scheduler) saveExecution(schedule *portainer.ScalingSchedule, executionError error) error {
+	previousError := schedule.LastError
+
+	schedule.LastExecution = time.Now().Unix()
+	schedule.LastError = ""
+	if executionError != nil {
+		schedule.LastError = executionError.Error()
+	}
+
+	if schedule.LastError != "" && schedule.LastError != previousError && scheduler.notificationService != nil {
+		scheduler.notificationService.Notify(&portainer.Notification{
+			Type:       portainer.JobFailedNotification,
+			EndpointID: schedule.EndpointID,
+			ResourceID: schedule.TargetName,
+			Title:      "Scaling schedule failed",
+			Message:    fmt.Sprintf("Scaling schedule %s failed to scale %s: %s", schedule.Name, schedule.TargetName, schedule.LastError),
+			Time:       schedule.LastExecution,
+		})
+	}
+
+	err := scheduler.dataStore.ScalingSchedule().UpdateScalingSchedule(schedule.ID, schedule)
+	if err != nil {
+		return err
+	}
+
+	return executionError
+}
+
+func (scheduler *Scheduler) scaleService(endpoint *portainer.Endpoint, serviceID string, replicas int) error {
+	dockerClient, err := scheduler.dockerClientFactory.CreateClient(endpoint, "")
+	if err != nil {
+		return err
+	}
+	defer dockerClient.Close()
+
+	service, _, err := dockerClient.ServiceInspectWithRaw(context.Background(), serviceID, types.ServiceInspectOptions{})
+	if err != nil {
+		return err
+	}
+
+	if service.Spec.Mode.Replicated == nil {
+		return fmt.Errorf("service %s is not a replicated service", service.Spec.Name)
+	}
+
+	serviceReplicas := uint64(replicas)
+	service.Spec.Mode.Replicated.Replicas = &serviceReplicas
+
+	_, err = dockerClient.ServiceUpdate(context.Background(), service.ID, service.Version, service.Spec, types.ServiceUpdateOptions{})
+	return err
+}
+
+func (scheduler *Scheduler) scaleDeployment(endpoint *portainer.Endpoint, namespace, name string, replicas int) error {
+	kubeClient, err := scheduler.kubernetesClientFactory.GetKubeClient(endpoint)
+	if err != nil {
+		return err
+	}
+
+	return kubeClient.ScaleDeployment(namespace, name, int32(replicas))
+}
@@ -0,0 +1,185 @@
+package search
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	portainer "github.com/portainer/portainer/api"
+)
+
+// DocumentType represents the type of resource referenced by a search document
+type DocumentType string
+
+const (
+	// EndpointDocument represents an endpoint
+	EndpointDocument DocumentType = "endpoint"
+	// StackDocument represents a stack
+	StackDocument DocumentType = "stack"
+	// ContainerDocument represents a container
+	ContainerDocument DocumentType = "container"
+	// ImageDocument represents an image
+	ImageDocument DocumentType = "image"
+	// VolumeDocument represents a volume
+	VolumeDocument DocumentType = "volume"
+)
+
+var documentTypeOrder = map[DocumentType]int{
+	EndpointDocument:  0,
+	StackDocument:     1,
+	ContainerDocument: 2,
+	ImageDocument:     3,
+	VolumeDocument:    4,
+}
+
+// Document represents a searchable resource
+type Document struct {
+	Type         DocumentType         `json:"Type"`
+	ID           string               `json:"Id"`
+	Name         string               `json:"Name"`
+	EndpointID   portainer.EndpointID `json:"EndpointId"`
+	EndpointName string               `json:"EndpointName"`
+	// State is the state of a container
+	State string `json:"State,omitempty"`
+	// Image is the image used by a container
+	Image string `json:"Image,omitempty"`
+	// Stack is the name of the stack a container belongs to
+	Stack string `json:"Stack,omitempty"`
+	// Labels are the labels of a container or a volume, used to evaluate access control
+	Labels map[string]string `json:"-"`
+
+	terms []string
+}
+
+// Index is an inverted index of documents. Each term of a document is split into lowercase
+// tokens, a query matches the documents containing a token starting with each of the query tokens.
+type Index struct {
+	documents []Document
+	postings  map[string][]int
+	tokens    []string
+}
+
+// NewIndex builds an index from a list of documents
+func NewIndex(documents []Document) *Index {
+	index := &Index{
+		documents: documents,
+		postings:  make(map[string][]int),
+	}
+
+	for idx := range documents {
+		seen := make(map[string]bool)
+		terms := append([]string{documents[idx].Name}, documents[idx].terms...)
+		for _, term := range terms {
+			for _, token := range tokenize(term) {
+				if seen[token] {
+					continue
+				}
+				seen[token] = true
+				index.postings[token] = append(index.postings[token], idx)
+			}
+		}
+	}
+
+	for token := range index.postings {
+		index.tokens = append(index.tokens, token)
+	}
+	sort.Strings(index.tokens)
+
+	return index
+}
+
+// Search returns the documents matching a query and accepted by the filter, ordered by relevance.
+// At most limit documents are returned when limit is greater than zero.
+func (index *Index) Search(query string, filter func(document *Document) bool, limit int) []Document {
+	results := make([]Document, 0)
+
+	queryTokens := tokenize(query)
+	if len(queryTokens) == 0 {
+		return results
+	}
+
+	var matches map[int]bool
+	for _, queryToken := range queryTokens {
+		tokenMatches := index.prefixMatches(queryToken)
+
+		if matches == nil {
+			matches = tokenMatches
+			continue
+		}
+
+		for idx := range matches {
+			if !tokenMatches[idx] {
+				delete(matches, idx)
+			}
+		}
+	}
+
+	normalizedQuery := strings.ToLower(strings.TrimSpace(query))
+	scores := make(map[int]int)
+	candidates := make([]int, 0, len(matches))
+	for idx := range matches {
+		document := &index.documents[idx]
+		if filter != nil && !filter(document) {
+			continue
+		}
+
+		candidates = append(candidates, idx)
+		scores[idx] = relevance(document, normalizedQuery)
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		left, right := &index.documents[candidates[i]], &index.documents[candidates[j]]
+		if scores[candidates[i]] != scores[candidates[j]] {
+			return scores[candidates[i]] > scores[candidates[j]]
+		}
+		if left.Type != right.Type {
+			return documentTypeOrder[left.Type] < documentTypeOrder[right.Type]
+		}
+		if left.Name != right.Name {
+			return left.Name < right.Name
+		}
+		return left.EndpointID < right.EndpointID
+	})
+
+	for _, idx := range candidates {
+		if limit > 0 && len(results) >= limit {
+			break
+		}
+		results = append(results, index.documents[idx])
+	}
+
+	return results
+}
+
+func (index *Index) prefixMatches(prefix string) map[int]bool {
+	matches := make(map[int]bool)
+
+	position := sort.SearchStrings(index.tokens, prefix)
+	for ; position < len(index.tokens) && strings.HasPrefix(index.tokens[position], prefix); position++ {
+		for _, idx := range index.postings[index.tokens[position]] {
+			matches[idx] = true
+		}
+	}
+
+	return matches
+}
+
+// relevance ranks exact name matches first, then names starting with the query and names containing it
+func relevance(document *Document, query string) int {
+	name := strings.ToLower(document.Name)
+	switch {
+	case name == query:
+		return 3
+	case strings.HasPrefix(name, query):
+		return 2
+	case strings.Contains(name, query):
+		return 1
+	}
+	return 0
+}
+
+func tokenize(value string) []string {
+	return strings.FieldsFunc(strings.ToLower(value), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
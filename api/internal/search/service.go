@@ -0,0 +1,180 @@
+package search
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/docker"
+)
+
+const (
+	composeProjectLabel = "com.docker.compose.project"
+	swarmStackLabel     = "com.docker.stack.namespace"
+)
+
+// Service represents a service used to search resources across endpoints.
+// The index is built from the latest snapshot of each endpoint and is only rebuilt
+// when a snapshot, an endpoint or a stack changes.
+type Service struct {
+	mutex     sync.Mutex
+	signature string
+	index     *Index
+}
+
+// NewService returns a new instance of a search service
+func NewService() *Service {
+	return &Service{}
+}
+
+// Index returns the index of the resources of the specified endpoints and stacks
+func (service *Service) Index(endpoints []portainer.Endpoint, stacks []portainer.Stack) *Index {
+	signature := indexSignature(endpoints, stacks)
+
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	if service.index == nil || service.signature != signature {
+		service.index = NewIndex(buildDocuments(endpoints, stacks))
+		service.signature = signature
+	}
+
+	return service.index
+}
+
+// indexSignature identifies the data used to build an index, the snapshot time of an endpoint changes
+// every time a new snapshot is created.
+func indexSignature(endpoints []portainer.Endpoint, stacks []portainer.Stack) string {
+	var signature strings.Builder
+
+	for _, endpoint := range endpoints {
+		var snapshotTime int64
+		if len(endpoint.Snapshots) > 0 {
+			snapshotTime = endpoint.Snapshots[0].Time
+		}
+		fmt.Fprintf(&signature, "e%d:%s:%d;", endpoint.ID, endpoint.Name, snapshotTime)
+	}
+
+	for _, stack := range stacks {
+		fmt.Fprintf(&signature, "s%d:%d;", stack.ID, stack.EndpointID)
+	}
+
+	return signature.String()
+}
+
+func buildDocuments(endpoints []portainer.Endpoint, stacks []portainer.Stack) []Document {
+	documents := make([]Document, 0)
+	endpointNames := make(map[portainer.EndpointID]string)
+
+	for idx := range endpoints {
+		endpoint := &endpoints[idx]
+		endpointNames[endpoint.ID] = endpoint.Name
+
+		documents = append(documents, Document{
+			Type:         EndpointDocument,
+			ID:           fmt.Sprint(endpoint.ID),
+			Name:         endpoint.Name,
+			EndpointID:   endpoint.ID,
+			EndpointName: endpoint.Name,
+			terms:        []string{endpoint.URL},
+		})
+
+		if len(endpoint.Snapshots) > 0 {
+			documents = append(documents, snapshotDocuments(endpoint, &endpoint.Snapshots[0])...)
+		}
+	}
+
+	for _, stack := range stacks {
+		endpointName, ok := endpointNames[stack.EndpointID]
+		if !ok {
+			continue
+		}
+
+		documents = append(documents, Document{
+			Type:         StackDocument,
+			ID:           fmt.Sprint(stack.ID),
+			Name:         stack.Name,
+			EndpointID:   stack.EndpointID,
+			EndpointName: endpointName,
+		})
+	}
+
+	return documents
+}
+
+func snapshotDocuments(endpoint *portainer.Endpoint, snapshot *portainer.DockerSnapshot) []Document {
+	documents := make([]Document, 0)
+
+	containers, err := docker.SnapshotContainers(snapshot)
+	if err != nil {
+		log.Printf("[WARN] [internal,search] [message: unable to decode snapshot containers] [endpoint: %s] [error: %s]", endpoint.Name, err)
+	}
+	for _, container := range containers {
+		name := container.ID
+		if len(container.Names) > 0 {
+			name = strings.TrimPrefix(container.Names[0], "/")
+		}
+
+		stack := stackNameFromLabels(container.Labels)
+
+		documents = append(documents, Document{
+			Type:         ContainerDocument,
+			ID:           container.ID,
+			Name:         name,
+			EndpointID:   endpoint.ID,
+			EndpointName: endpoint.Name,
+			State:        container.State,
+			Image:        container.Image,
+			Stack:        stack,
+			Labels:       container.Labels,
+			terms:        []string{container.Image, stack},
+		})
+	}
+
+	images, err := docker.SnapshotImages(snapshot)
+	if err != nil {
+		log.Printf("[WARN] [internal,search] [message: unable to decode snapshot images] [endpoint: %s] [error: %s]", endpoint.Name, err)
+	}
+	for _, image := range images {
+		name := image.ID
+		if len(image.RepoTags) > 0 {
+			name = image.RepoTags[0]
+		}
+
+		documents = append(documents, Document{
+			Type:         ImageDocument,
+			ID:           image.ID,
+			Name:         name,
+			EndpointID:   endpoint.ID,
+			EndpointName: endpoint.Name,
+			terms:        image.RepoTags,
+		})
+	}
+
+	volumes, err := docker.SnapshotVolumes(snapshot)
+	if err != nil {
+		log.Printf("[WARN] [internal,search] [message: unable to decode snapshot volumes] [endpoint: %s] [error: %s]", endpoint.Name, err)
+	}
+	for _, volume := range volumes {
+		documents = append(documents, Document{
+			Type:         VolumeDocument,
+			ID:           volume.Name,
+			Name:         volume.Name,
+			EndpointID:   endpoint.ID,
+			EndpointName: endpoint.Name,
+			Stack:        stackNameFromLabels(volume.Labels),
+			Labels:       volume.Labels,
+		})
+	}
+
+	return documents
+}
+
+func stackNameFromLabels(labels map[string]string) string {
+	if labels[composeProjectLabel] != "" {
+		return labels[composeProjectLabel]
+	}
+	return labels[swarmStackLabel]
+}
@@ -0,0 +1,52 @@
+package snapshot
+
+import (
+	"log"
+
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/internal/authorization"
+)
+
+// recordResourceSample stores the resource reservations of a Docker snapshot in the resource samples.
+// The teams of each reservation are resolved from the resource controls at the time of the snapshot.
+func (service *Service) recordResourceSample(endpoint *portainer.Endpoint, snapshot *portainer.DockerSnapshot) {
+	if snapshot.Reservations == nil {
+		return
+	}
+
+	resourceControls, err := service.dataStore.ResourceControl().ResourceControls()
+	if err != nil {
+		log.Printf("[WARN] [internal,snapshot] [message: unable to retrieve resource controls] [error: %s]", err)
+		return
+	}
+
+	for idx := range snapshot.Reservations {
+		reservation := &snapshot.Reservations[idx]
+
+		resourceControlType := portainer.ContainerResourceControl
+		if reservation.Type == portainer.ServiceResourceReservation {
+			resourceControlType = portainer.ServiceResourceControl
+		}
+
+		resourceControl := authorization.GetInheritedResourceControl(reservation.ResourceID, resourceControlType, reservation.Labels, resourceControls)
+		if resourceControl == nil {
+			continue
+		}
+
+		for _, access := range resourceControl.TeamAccesses {
+			reservation.TeamIDs = append(reservation.TeamIDs, access.TeamID)
+		}
+	}
+
+	sample := &portainer.ResourceSample{
+		EndpointID:   endpoint.ID,
+		Time:         snapshot.Time,
+		Interval:     int64(service.snapshotIntervalInSeconds),
+		Reservations: snapshot.Reservations,
+	}
+
+	err = service.dataStore.ResourceSample().CreateResourceSample(sample)
+	if err != nil {
+		log.Printf("[WARN] [internal,snapshot] [message: unable to record resource sample] [endpoint: %s] [error: %s]", endpoint.Name, err)
+	}
+}
@@ -2,6 +2,7 @@ package snapshot
 
 import (
 	"log"
+	"sync"
 	"time"
 
 	"github.com/portainer/portainer/api"
@@ -16,10 +17,13 @@ type Service struct {
 	snapshotIntervalInSeconds float64
 	dockerSnapshotter         portainer.DockerSnapshotter
 	kubernetesSnapshotter     portainer.KubernetesSnapshotter
+	alertService              portainer.AlertService
+	heartbeatMutex            sync.Mutex
+	lastHeartbeat             time.Time
 }
 
 // NewService creates a new instance of a service
-func NewService(snapshotInterval string, dataStore portainer.DataStore, dockerSnapshotter portainer.DockerSnapshotter, kubernetesSnapshotter portainer.KubernetesSnapshotter) (*Service, error) {
+func NewService(snapshotInterval string, dataStore portainer.DataStore, dockerSnapshotter portainer.DockerSnapshotter, kubernetesSnapshotter portainer.KubernetesSnapshotter, alertService portainer.AlertService) (*Service, error) {
 	snapshotFrequency, err := time.ParseDuration(snapshotInterval)
 	if err != nil {
 		return nil, err
@@ -30,6 +34,7 @@ func NewService(snapshotInterval string, dataStore portainer.DataStore, dockerSn
 		snapshotIntervalInSeconds: snapshotFrequency.Seconds(),
 		dockerSnapshotter:         dockerSnapshotter,
 		kubernetesSnapshotter:     kubernetesSnapshotter,
+		alertService:              alertService,
 	}, nil
 }
 
@@ -82,7 +87,7 @@ func (service *Service) SnapshotEndpoint(endpoint *portainer.Endpoint) error {
 	switch endpoint.Type {
 	case portainer.AzureEnvironment:
 		return nil
-	case portainer.KubernetesLocalEnvironment, portainer.AgentOnKubernetesEnvironment, portainer.EdgeAgentOnKubernetesEnvironment:
+	case portainer.KubernetesLocalEnvironment, portainer.AgentOnKubernetesEnvironment, portainer.EdgeAgentOnKubernetesEnvironment, portainer.KubeconfigKubernetesEnvironment:
 		return service.snapshotKubernetesEndpoint(endpoint)
 	}
 
@@ -109,15 +114,88 @@ func (service *Service) snapshotDockerEndpoint(endpoint *portainer.Endpoint) err
 	}
 
 	if snapshot != nil {
+		var previous *portainer.DockerSnapshot
+		if len(endpoint.Snapshots) > 0 {
+			previous = &endpoint.Snapshots[0]
+		}
+		trackNodeStatusChanges(endpoint, previous, snapshot)
+		trackVolumeUsage(previous, snapshot)
+		if service.alertService != nil {
+			service.alertService.ProcessSnapshot(endpoint, previous, snapshot)
+		}
+		service.recordResourceSample(endpoint, snapshot)
 		endpoint.Snapshots = []portainer.DockerSnapshot{*snapshot}
 	}
 
 	return nil
 }
 
+// trackNodeStatusChanges reports the changes of state and availability of the Swarm nodes between two snapshots.
+// The time of the last status change of a node is kept as long as its status does not change.
+func trackNodeStatusChanges(endpoint *portainer.Endpoint, previous, current *portainer.DockerSnapshot) {
+	previousNodes := make(map[string]portainer.DockerNodeStatus)
+	if previous != nil {
+		for _, node := range previous.Nodes {
+			previousNodes[node.ID] = node
+		}
+	}
+
+	for idx := range current.Nodes {
+		node := &current.Nodes[idx]
+		node.StatusChangedAt = current.Time
+
+		previousNode, ok := previousNodes[node.ID]
+		if !ok {
+			continue
+		}
+
+		if previousNode.State == node.State && previousNode.Availability == node.Availability {
+			node.StatusChangedAt = previousNode.StatusChangedAt
+			continue
+		}
+
+		log.Printf("[INFO] [internal,snapshot] [message: Swarm node status changed] [endpoint: %s] [node: %s] [state: %s -> %s] [availability: %s -> %s]", endpoint.Name, node.Hostname, previousNode.State, node.State, previousNode.Availability, node.Availability)
+	}
+}
+
+// trackVolumeUsage keeps the time a volume was last used from the previous snapshot when the volume
+// is not used by a running container anymore
+func trackVolumeUsage(previous, current *portainer.DockerSnapshot) {
+	if previous == nil {
+		return
+	}
+
+	lastUsed := make(map[string]int64)
+	for _, volume := range previous.VolumeUsage {
+		lastUsed[volume.Name] = volume.LastUsed
+	}
+
+	for idx := range current.VolumeUsage {
+		volume := &current.VolumeUsage[idx]
+		if volume.LastUsed == 0 {
+			volume.LastUsed = lastUsed[volume.Name]
+		}
+	}
+}
+
+// Heartbeat returns the time the snapshot loop last started to snapshot the endpoints and
+// the interval between two iterations of the loop
+func (service *Service) Heartbeat() (time.Time, time.Duration) {
+	service.heartbeatMutex.Lock()
+	defer service.heartbeatMutex.Unlock()
+	return service.lastHeartbeat, time.Duration(service.snapshotIntervalInSeconds) * time.Second
+}
+
+func (service *Service) beat() {
+	service.heartbeatMutex.Lock()
+	service.lastHeartbeat = time.Now()
+	service.heartbeatMutex.Unlock()
+}
+
 func (service *Service) startSnapshotLoop() error {
 	ticker := time.NewTicker(time.Duration(service.snapshotIntervalInSeconds) * time.Second)
 	go func() {
+		service.beat()
 		err := service.snapshotEndpoints()
 		if err != nil {
 			log.Printf("[ERROR] [internal,snapshot] [message: background schedule error (endpoint snapshot).] [error: %s]", err)
@@ -126,6 +204,7 @@ func (service *Service) startSnapshotLoop() error {
 		for {
 			select {
 			case <-ticker.C:
+				service.beat()
 				err := service.snapshotEndpoints()
 				if err != nil {
 					log.Printf("[ERROR] [internal,snapshot] [message: background schedule error (endpoint snapshot).] [error: %s]", err)
@@ -161,12 +240,17 @@ func (service *Service) snapshotEndpoints() error {
 			continue
 		}
 
+		previousStatus := latestEndpointReference.Status
 		latestEndpointReference.Status = portainer.EndpointStatusUp
 		if snapshotError != nil {
 			log.Printf("background schedule error (endpoint snapshot). Unable to create snapshot (endpoint=%s, URL=%s) (err=%s)\n", endpoint.Name, endpoint.URL, snapshotError)
 			latestEndpointReference.Status = portainer.EndpointStatusDown
 		}
 
+		if service.alertService != nil {
+			service.alertService.ProcessEndpointStatus(latestEndpointReference, previousStatus)
+		}
+
 		latestEndpointReference.Snapshots = endpoint.Snapshots
 		latestEndpointReference.Kubernetes.Snapshots = endpoint.Kubernetes.Snapshots
 
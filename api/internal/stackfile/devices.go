@@ -0,0 +1,109 @@
+package stackfile
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/docker/cli/cli/compose/loader"
+	"github.com/portainer/portainer/api"
+)
+
+// ServicesWithDeviceReservations returns the names of the services of a stack file reserving devices,
+// such as GPUs, with deploy.resources.reservations.devices
+func ServicesWithDeviceReservations(content []byte) ([]string, error) {
+	config, err := loader.ParseYAML(content)
+	if err != nil {
+		return nil, err
+	}
+
+	services := config
+	if _, hasVersion := config["version"]; hasVersion {
+		services, _ = config["services"].(map[string]interface{})
+	}
+
+	names := make([]string, 0)
+	for name, service := range services {
+		service, ok := service.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		if _, ok := deviceReservations(service); ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// deviceReservations returns the devices reserved by a service
+func deviceReservations(service map[string]interface{}) ([]interface{}, bool) {
+	value := interface{}(service)
+	for _, key := range []string{"deploy", "resources", "reservations", "devices"} {
+		section, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		value, ok = section[key]
+		if !ok {
+			return nil, false
+		}
+	}
+
+	devices, ok := value.([]interface{})
+	return devices, ok
+}
+
+// deviceReservationSchemaError returns true when the only schema error of a stack file is a device reservation.
+// The schema of the Compose file format 3 does not define the device reservations supported by docker-compose.
+func deviceReservationSchemaError(err error) bool {
+	message := err.Error()
+	return !strings.Contains(message, "\n") && strings.HasSuffix(message, "reservations Additional property devices is not allowed")
+}
+
+// lintDeviceReservations reports the device reservations that cannot be resolved by a device driver,
+// and the device reservations of Swarm stacks which are not supported by Swarm services
+func lintDeviceReservations(services map[string]interface{}, prefix string, stackType portainer.StackType, lines lineIndex) []Problem {
+	problems := make([]Problem, 0)
+
+	for _, name := range sortedKeys(services) {
+		service, ok := services[name].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		devices, ok := deviceReservations(service)
+		if !ok {
+			continue
+		}
+
+		path := prefix + name + ".deploy.resources.reservations.devices"
+		if stackType == portainer.DockerSwarmStack {
+			problems = append(problems, lines.problem(ErrorSeverity, path, "Device reservations are not supported by Swarm services, use generic_resources instead"))
+			continue
+		}
+
+		for idx, device := range devices {
+			device, ok := device.(map[string]interface{})
+			if !ok {
+				problems = append(problems, lines.problem(ErrorSeverity, path, fmt.Sprintf("Invalid device reservation %d of service %s", idx, name)))
+				continue
+			}
+
+			_, hasCount := device["count"]
+			_, hasDeviceIDs := device["device_ids"]
+			if hasCount && hasDeviceIDs {
+				problems = append(problems, lines.problem(ErrorSeverity, path, fmt.Sprintf("A device reservation of service %s defines both count and device_ids", name)))
+			}
+
+			capabilities, _ := device["capabilities"].([]interface{})
+			if _, hasDriver := device["driver"]; !hasDriver && len(capabilities) == 0 {
+				problems = append(problems, lines.problem(ErrorSeverity, path, fmt.Sprintf("A device reservation of service %s must define a driver or capabilities, such as gpu", name)))
+			}
+		}
+	}
+
+	return problems
+}
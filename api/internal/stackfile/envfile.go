@@ -0,0 +1,88 @@
+package stackfile
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+
+	"github.com/portainer/portainer/api"
+)
+
+// ParseEnvFile parses the content of a variable file. Each line defines a variable using the NAME=value format,
+// empty lines and lines starting with # are ignored. Values are used as is, quotes are not removed.
+func ParseEnvFile(data []byte) []portainer.Pair {
+	pairs := make([]portainer.Pair, 0)
+
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimRight(line, "\r")
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		parts := strings.SplitN(strings.TrimLeft(line, " \t"), "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		name := strings.TrimSpace(strings.TrimPrefix(parts[0], "export "))
+		if name == "" {
+			continue
+		}
+
+		pairs = append(pairs, portainer.Pair{Name: name, Value: parts[1]})
+	}
+
+	return pairs
+}
+
+// FormatEnvFile returns the content of a variable file defining the specified variables
+func FormatEnvFile(pairs []portainer.Pair) []byte {
+	var buffer bytes.Buffer
+	for _, pair := range pairs {
+		buffer.WriteString(pair.Name)
+		buffer.WriteString("=")
+		buffer.WriteString(pair.Value)
+		buffer.WriteString("\n")
+	}
+	return buffer.Bytes()
+}
+
+// MergeEnv returns the variables of base overridden by the variables of overrides, the order of
+// the variables of base is preserved and the new variables are appended
+func MergeEnv(base, overrides []portainer.Pair) []portainer.Pair {
+	merged := make([]portainer.Pair, len(base))
+	copy(merged, base)
+
+	positions := make(map[string]int)
+	for idx, pair := range merged {
+		positions[pair.Name] = idx
+	}
+
+	for _, pair := range overrides {
+		if idx, ok := positions[pair.Name]; ok {
+			merged[idx].Value = pair.Value
+			continue
+		}
+		positions[pair.Name] = len(merged)
+		merged = append(merged, pair)
+	}
+
+	return merged
+}
+
+// Environment returns the variables available when a stack is deployed: the variables of its
+// variable files, in order, overridden by the variables defined on the stack
+func Environment(stack *portainer.Stack) ([]portainer.Pair, error) {
+	env := make([]portainer.Pair, 0)
+
+	for _, envFile := range stack.EnvFiles {
+		data, err := ioutil.ReadFile(envFile.Path)
+		if err != nil {
+			return nil, err
+		}
+		env = MergeEnv(env, ParseEnvFile(data))
+	}
+
+	return MergeEnv(env, stack.Env), nil
+}
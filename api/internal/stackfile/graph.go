@@ -0,0 +1,246 @@
+package stackfile
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/docker/cli/cli/compose/loader"
+)
+
+const (
+	// DependsOnDependency is a dependency declared with depends_on
+	DependsOnDependency = "depends_on"
+	// LinkDependency is a dependency declared with links
+	LinkDependency = "links"
+	// NetworkModeDependency is a dependency on the service whose network stack is shared with network_mode: service:name
+	NetworkModeDependency = "network_mode"
+	// VolumesFromDependency is a dependency on the service whose volumes are mounted with volumes_from
+	VolumesFromDependency = "volumes_from"
+
+	defaultNetwork = "default"
+)
+
+// ErrDependencyCycle is returned when the services of a stack depend on each other
+var ErrDependencyCycle = errors.New("The services of the stack have a circular dependency")
+
+// Dependency represents a service requiring another service of the same stack to be started first.
+// Condition is the condition of the depends_on entries declared in the long syntax, such as service_healthy.
+type Dependency struct {
+	Service   string `json:"Service"`
+	DependsOn string `json:"DependsOn"`
+	Type      string `json:"Type"`
+	Condition string `json:"Condition,omitempty"`
+}
+
+// ServiceNode represents a service of a stack and the networks it is connected to
+type ServiceNode struct {
+	Name     string   `json:"Name"`
+	Networks []string `json:"Networks"`
+}
+
+// Graph represents the dependencies between the services of a stack. Order lists the services in
+// batches, the services of a batch only depend on the services of the previous batches.
+// Cycle lists the services depending on each other, they cannot be ordered.
+type Graph struct {
+	Services     []ServiceNode       `json:"Services"`
+	Dependencies []Dependency        `json:"Dependencies"`
+	Networks     map[string][]string `json:"Networks"`
+	Order        [][]string          `json:"Order"`
+	Cycle        []string            `json:"Cycle,omitempty"`
+}
+
+// DependencyGraph parses the depends_on, links, network_mode, volumes_from and networks sections of the
+// services of a stack file. The dependencies on services that are not part of the stack are ignored.
+func DependencyGraph(content []byte) (*Graph, error) {
+	config, err := loader.ParseYAML(content)
+	if err != nil {
+		return nil, err
+	}
+
+	services := config
+	if _, ok := config["version"]; ok {
+		services, _ = config["services"].(map[string]interface{})
+	}
+
+	graph := &Graph{
+		Services:     make([]ServiceNode, 0),
+		Dependencies: make([]Dependency, 0),
+		Networks:     make(map[string][]string),
+	}
+
+	for _, name := range sortedKeys(services) {
+		service, ok := services[name].(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("Invalid definition of service %s", name)
+		}
+
+		node := ServiceNode{Name: name, Networks: serviceNetworks(service)}
+		for _, network := range node.Networks {
+			graph.Networks[network] = append(graph.Networks[network], name)
+		}
+		graph.Services = append(graph.Services, node)
+
+		for _, dependency := range serviceDependencies(name, service) {
+			if _, ok := services[dependency.DependsOn]; ok && dependency.DependsOn != name {
+				graph.Dependencies = append(graph.Dependencies, dependency)
+			}
+		}
+	}
+
+	graph.Order, graph.Cycle = graph.order()
+
+	return graph, nil
+}
+
+// StartOrder returns the services in the order they must be started, in batches
+func (graph *Graph) StartOrder() ([][]string, error) {
+	if len(graph.Cycle) > 0 {
+		return nil, ErrDependencyCycle
+	}
+	return graph.Order, nil
+}
+
+// StopOrder returns the services in the order they must be stopped, in batches: the services are stopped
+// before the services they depend on
+func (graph *Graph) StopOrder() ([][]string, error) {
+	order, err := graph.StartOrder()
+	if err != nil {
+		return nil, err
+	}
+
+	reversed := make([][]string, 0, len(order))
+	for i := len(order) - 1; i >= 0; i-- {
+		reversed = append(reversed, order[i])
+	}
+	return reversed, nil
+}
+
+// Levels returns the index of the start batch of each service
+func (graph *Graph) Levels() map[string]int {
+	levels := make(map[string]int)
+	for level, batch := range graph.Order {
+		for _, name := range batch {
+			levels[name] = level
+		}
+	}
+	return levels
+}
+
+// order sorts the services topologically and returns the services that cannot be sorted
+func (graph *Graph) order() ([][]string, []string) {
+	pending := make(map[string]map[string]bool)
+	for _, service := range graph.Services {
+		pending[service.Name] = make(map[string]bool)
+	}
+	for _, dependency := range graph.Dependencies {
+		pending[dependency.Service][dependency.DependsOn] = true
+	}
+
+	order := make([][]string, 0)
+	for len(pending) > 0 {
+		batch := make([]string, 0)
+		for name, dependencies := range pending {
+			if len(dependencies) == 0 {
+				batch = append(batch, name)
+			}
+		}
+
+		if len(batch) == 0 {
+			break
+		}
+
+		sort.Strings(batch)
+		for _, name := range batch {
+			delete(pending, name)
+			for _, dependencies := range pending {
+				delete(dependencies, name)
+			}
+		}
+		order = append(order, batch)
+	}
+
+	cycle := make([]string, 0, len(pending))
+	for name := range pending {
+		cycle = append(cycle, name)
+	}
+	sort.Strings(cycle)
+
+	return order, cycle
+}
+
+// serviceDependencies returns the dependencies of a service, depends_on accepts a list of services
+// or a mapping of the services to their condition
+func serviceDependencies(name string, service map[string]interface{}) []Dependency {
+	dependencies := make([]Dependency, 0)
+	known := make(map[string]bool)
+
+	add := func(dependsOn, dependencyType, condition string) {
+		if dependsOn == "" || known[dependsOn] {
+			return
+		}
+		known[dependsOn] = true
+		dependencies = append(dependencies, Dependency{Service: name, DependsOn: dependsOn, Type: dependencyType, Condition: condition})
+	}
+
+	switch dependsOn := service["depends_on"].(type) {
+	case []interface{}:
+		for _, item := range dependsOn {
+			add(fmt.Sprintf("%v", item), DependsOnDependency, "")
+		}
+	case map[string]interface{}:
+		for _, key := range sortedKeys(dependsOn) {
+			condition := ""
+			if options, ok := dependsOn[key].(map[string]interface{}); ok {
+				condition, _ = options["condition"].(string)
+			}
+			add(key, DependsOnDependency, condition)
+		}
+	}
+
+	if links, ok := service["links"].([]interface{}); ok {
+		for _, link := range links {
+			add(strings.SplitN(fmt.Sprintf("%v", link), ":", 2)[0], LinkDependency, "")
+		}
+	}
+
+	if networkMode, ok := service["network_mode"].(string); ok && strings.HasPrefix(networkMode, "service:") {
+		add(strings.TrimPrefix(networkMode, "service:"), NetworkModeDependency, "")
+	}
+
+	if volumesFrom, ok := service["volumes_from"].([]interface{}); ok {
+		for _, item := range volumesFrom {
+			source := fmt.Sprintf("%v", item)
+			if strings.HasPrefix(source, "container:") {
+				continue
+			}
+			source = strings.TrimPrefix(source, "service:")
+			add(strings.SplitN(source, ":", 2)[0], VolumesFromDependency, "")
+		}
+	}
+
+	return dependencies
+}
+
+// serviceNetworks returns the networks of a service, the services without networks are connected
+// to the default network of the stack unless they use another network mode
+func serviceNetworks(service map[string]interface{}) []string {
+	networks := make([]string, 0)
+
+	switch value := service["networks"].(type) {
+	case []interface{}:
+		for _, network := range value {
+			networks = append(networks, fmt.Sprintf("%v", network))
+		}
+	case map[string]interface{}:
+		networks = append(networks, sortedKeys(value)...)
+	}
+
+	if _, ok := service["network_mode"]; len(networks) == 0 && !ok {
+		networks = append(networks, defaultNetwork)
+	}
+
+	sort.Strings(networks)
+	return networks
+}
@@ -0,0 +1,101 @@
+package stackfile
+
+import (
+	"fmt"
+	"testing"
+)
+
+const graphStackFile = `
+version: "3.8"
+services:
+  proxy:
+    image: nginx
+    links:
+      - "web:frontend"
+  web:
+    image: app
+    depends_on:
+      db:
+        condition: service_healthy
+      cache:
+        condition: service_started
+    networks:
+      - front
+      - back
+  worker:
+    image: app
+    network_mode: "service:web"
+    volumes_from:
+      - container:data
+  db:
+    image: postgres
+    networks:
+      back:
+  cache:
+    image: redis
+    depends_on:
+      - missing
+`
+
+func TestDependencyGraph(t *testing.T) {
+	graph, err := DependencyGraph([]byte(graphStackFile))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(graph.Dependencies) != 4 {
+		t.Errorf("expected 4 dependencies, got %v", graph.Dependencies)
+	}
+
+	startOrder, err := graph.StartOrder()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := "[[cache db] [web] [proxy worker]]"
+	if order := fmt.Sprintf("%v", startOrder); order != expected {
+		t.Errorf("expected start order %s, got %s", expected, order)
+	}
+
+	stopOrder, _ := graph.StopOrder()
+	expected = "[[proxy worker] [web] [cache db]]"
+	if order := fmt.Sprintf("%v", stopOrder); order != expected {
+		t.Errorf("expected stop order %s, got %s", expected, order)
+	}
+
+	expected = "map[back:[db web] default:[cache proxy] front:[web]]"
+	if networks := fmt.Sprintf("%v", graph.Networks); networks != expected {
+		t.Errorf("expected networks %s, got %s", expected, networks)
+	}
+}
+
+func TestDependencyGraphCycle(t *testing.T) {
+	content := `
+version: "2.4"
+services:
+  a:
+    image: app
+    depends_on: [b]
+  b:
+    image: app
+    depends_on: [c]
+  c:
+    image: app
+    links: [a]
+  d:
+    image: app
+`
+
+	graph, err := DependencyGraph([]byte(content))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if cycle := fmt.Sprintf("%v", graph.Cycle); cycle != "[a b c]" {
+		t.Errorf("expected the cycle [a b c], got %s", cycle)
+	}
+
+	if _, err := graph.StartOrder(); err != ErrDependencyCycle {
+		t.Errorf("expected ErrDependencyCycle, got %v", err)
+	}
+}
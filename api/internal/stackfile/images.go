@@ -0,0 +1,43 @@
+package stackfile
+
+import (
+	"github.com/docker/cli/cli/compose/loader"
+	"github.com/docker/cli/cli/compose/types"
+	"github.com/portainer/portainer/api"
+)
+
+// Images returns the images referenced by the services of a stack file, the variables
+// defined in the environment are interpolated
+func Images(content []byte, env []portainer.Pair) ([]string, error) {
+	composeConfigYAML, err := loader.ParseYAML(content)
+	if err != nil {
+		return nil, err
+	}
+
+	environment := make(map[string]string)
+	for _, pair := range env {
+		environment[pair.Name] = pair.Value
+	}
+
+	composeConfig, err := loader.Load(types.ConfigDetails{
+		ConfigFiles: []types.ConfigFile{{Config: composeConfigYAML}},
+		Environment: environment,
+	}, func(options *loader.Options) {
+		options.SkipValidation = true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	images := make([]string, 0)
+	known := make(map[string]bool)
+	for _, service := range composeConfig.Services {
+		if service.Image == "" || known[service.Image] {
+			continue
+		}
+		known[service.Image] = true
+		images = append(images, service.Image)
+	}
+
+	return images, nil
+}
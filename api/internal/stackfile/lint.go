@@ -0,0 +1,387 @@
+package stackfile
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/docker/cli/cli/compose/loader"
+	"github.com/docker/cli/cli/compose/types"
+	"github.com/portainer/portainer/api"
+)
+
+const (
+	// ErrorSeverity is used for the problems preventing the deployment of a stack
+	ErrorSeverity = "error"
+	// WarningSeverity is used for the problems that do not prevent the deployment of a stack
+	WarningSeverity = "warning"
+)
+
+// Problem represents an issue found in a stack file. Line is set to 0 when the issue cannot be located.
+type Problem struct {
+	Line     int    `json:"Line"`
+	Severity string `json:"Severity"`
+	Path     string `json:"Path,omitempty"`
+	Message  string `json:"Message"`
+}
+
+var (
+	yamlLinePattern       = regexp.MustCompile(`line (\d+)`)
+	keyPattern            = regexp.MustCompile(`^("[^"]*"|'[^']*'|[^\s:#"'][^:#]*?)\s*:(\s|$)`)
+	variablePattern       = regexp.MustCompile(`\$(\$|\{([^}]*)\}|([a-zA-Z_][a-zA-Z0-9_]*))`)
+	additionalPropPattern = regexp.MustCompile(`Additional property (\S+) is not allowed`)
+)
+
+var topLevelKeys = map[string]bool{
+	"version":  true,
+	"services": true,
+	"networks": true,
+	"volumes":  true,
+	"secrets":  true,
+	"configs":  true,
+}
+
+var serviceKeys = map[string]bool{
+	"blkio_config": true, "build": true, "cap_add": true, "cap_drop": true, "cgroup_parent": true,
+	"command": true, "configs": true, "container_name": true, "cpu_count": true, "cpu_percent": true,
+	"cpu_period": true, "cpu_quota": true, "cpu_rt_period": true, "cpu_rt_runtime": true, "cpu_shares": true,
+	"cpus": true, "cpuset": true, "credential_spec": true, "depends_on": true, "deploy": true,
+	"device_cgroup_rules": true, "devices": true, "dns": true, "dns_opt": true, "dns_search": true,
+	"domainname": true, "entrypoint": true, "env_file": true, "environment": true, "expose": true,
+	"extends": true, "external_links": true, "extra_hosts": true, "group_add": true, "healthcheck": true,
+	"hostname": true, "image": true, "init": true, "ipc": true, "isolation": true, "labels": true,
+	"links": true, "logging": true, "mac_address": true, "mem_limit": true, "mem_reservation": true,
+	"mem_swappiness": true, "memswap_limit": true, "net": true, "network_mode": true, "networks": true,
+	"oom_kill_disable": true, "oom_score_adj": true, "pid": true, "pids_limit": true, "platform": true,
+	"ports": true, "privileged": true, "read_only": true, "restart": true, "runtime": true, "scale": true,
+	"secrets": true, "security_opt": true, "shm_size": true, "stdin_open": true, "stop_grace_period": true,
+	"stop_signal": true, "storage_opt": true, "sysctls": true, "tmpfs": true, "tty": true, "ulimits": true,
+	"user": true, "userns_mode": true, "volume_driver": true, "volumes": true, "volumes_from": true,
+	"working_dir": true,
+}
+
+var deprecatedServiceKeys = map[string]string{
+	"links":          "links is a legacy feature, services of a stack can reach each other on a shared network",
+	"external_links": "external_links is a legacy feature, use a shared external network instead",
+	"volumes_from":   "volumes_from is not supported by the Compose file format 3, use named volumes instead",
+	"net":            "net is deprecated, use network_mode instead",
+	"extends":        "extends is not supported by the Compose file format 3",
+	"volume_driver":  "volume_driver is deprecated, declare the driver in the volumes section instead",
+}
+
+var swarmIgnoredServiceKeys = []string{
+	"build", "cgroup_parent", "container_name", "devices", "external_links", "links",
+	"network_mode", "restart", "security_opt", "tmpfs", "userns_mode",
+}
+
+// Lint parses a stack file and reports syntax errors, schema errors, unknown keys, deprecated syntax
+// and variables that are not defined in the environment of the stack
+func Lint(content []byte, env []portainer.Pair, stackType portainer.StackType) []Problem {
+	problems := make([]Problem, 0)
+
+	config, err := loader.ParseYAML(content)
+	if err != nil {
+		problem := Problem{Severity: ErrorSeverity, Message: err.Error()}
+		if match := yamlLinePattern.FindStringSubmatch(err.Error()); match != nil {
+			problem.Line, _ = strconv.Atoi(match[1])
+		}
+		return append(problems, problem)
+	}
+
+	lines := indexLines(content)
+	environment := make(map[string]string)
+	for _, pair := range env {
+		environment[pair.Name] = pair.Value
+	}
+
+	problems = append(problems, lintVariables(content, environment)...)
+
+	version, hasVersion := config["version"]
+	versionString := fmt.Sprintf("%v", version)
+	services := config
+	if hasVersion {
+		services, _ = config["services"].(map[string]interface{})
+		for key := range config {
+			if !topLevelKeys[key] && !strings.HasPrefix(key, "x-") {
+				problems = append(problems, lines.problem(WarningSeverity, key, fmt.Sprintf("Unknown top-level key %s", key)))
+			}
+		}
+	} else {
+		problems = append(problems, Problem{Line: 1, Severity: WarningSeverity, Message: "The Compose file format 1 is deprecated, add a version and a services section"})
+	}
+
+	if stackType == portainer.DockerSwarmStack && !strings.HasPrefix(versionString, "3") {
+		problems = append(problems, lines.problem(ErrorSeverity, "version", "Swarm stacks require the Compose file format 3"))
+	}
+
+	problems = append(problems, lintServices(services, hasVersion, stackType, lines)...)
+	if hasVersion {
+		problems = append(problems, lintDeviceReservations(services, "services.", stackType, lines)...)
+		problems = append(problems, lintProxyLabels(services, "services.", stackType, environment, lines)...)
+	} else {
+		problems = append(problems, lintDeviceReservations(services, "", stackType, lines)...)
+		problems = append(problems, lintProxyLabels(services, "", stackType, environment, lines)...)
+	}
+	problems = append(problems, lintExternals(config, lines)...)
+
+	// the schemas of the Compose file formats 1 and 2 are not available, these files are only validated when deployed
+	if hasVersion && strings.HasPrefix(versionString, "3") {
+		details := types.ConfigDetails{
+			ConfigFiles: []types.ConfigFile{{Config: config}},
+			Environment: environment,
+		}
+
+		_, err = loader.Load(details)
+		if err != nil && strings.HasPrefix(err.Error(), "invalid interpolation format") {
+			// interpolation errors are already reported as unresolved variables
+			_, err = loader.Load(details, func(options *loader.Options) {
+				options.SkipInterpolation = true
+			})
+		}
+		if err != nil && !(stackType != portainer.DockerSwarmStack && deviceReservationSchemaError(err)) {
+			problems = append(problems, lines.schemaProblem(err.Error()))
+		}
+	}
+
+	sort.SliceStable(problems, func(i, j int) bool {
+		return problems[i].Line < problems[j].Line
+	})
+
+	return problems
+}
+
+// HasErrors returns true if one of the problems prevents the deployment of the stack
+func HasErrors(problems []Problem) bool {
+	for _, problem := range problems {
+		if problem.Severity == ErrorSeverity {
+			return true
+		}
+	}
+	return false
+}
+
+func lintServices(services map[string]interface{}, hasVersion bool, stackType portainer.StackType, lines lineIndex) []Problem {
+	problems := make([]Problem, 0)
+
+	prefix := ""
+	if hasVersion {
+		prefix = "services."
+	}
+
+	for _, name := range sortedKeys(services) {
+		service, ok := services[name].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		for _, key := range sortedKeys(service) {
+			path := prefix + name + "." + key
+
+			if !serviceKeys[key] && !strings.HasPrefix(key, "x-") {
+				problems = append(problems, lines.problem(WarningSeverity, path, fmt.Sprintf("Unknown key %s in service %s", key, name)))
+				continue
+			}
+
+			if message, deprecated := deprecatedServiceKeys[key]; deprecated {
+				problems = append(problems, lines.problem(WarningSeverity, path, message))
+			}
+		}
+
+		if stackType != portainer.DockerSwarmStack {
+			continue
+		}
+
+		for _, key := range swarmIgnoredServiceKeys {
+			if _, ok := service[key]; ok {
+				problems = append(problems, lines.problem(WarningSeverity, prefix+name+"."+key, fmt.Sprintf("%s is ignored when deploying a Swarm stack", key)))
+			}
+		}
+	}
+
+	return problems
+}
+
+func lintExternals(config map[string]interface{}, lines lineIndex) []Problem {
+	problems := make([]Problem, 0)
+
+	for _, section := range []string{"networks", "volumes"} {
+		resources, ok := config[section].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		for _, name := range sortedKeys(resources) {
+			resource, ok := resources[name].(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			if external, ok := resource["external"].(map[string]interface{}); ok {
+				if _, ok := external["name"]; ok {
+					problems = append(problems, lines.problem(WarningSeverity, section+"."+name+".external.name", "external.name is deprecated, use name instead"))
+				}
+			}
+		}
+	}
+
+	return problems
+}
+
+// lintVariables reports the variables that are not defined in the environment. Variables using a default
+// value are ignored, required variables that are not defined are reported as errors.
+func lintVariables(content []byte, environment map[string]string) []Problem {
+	problems := make([]Problem, 0)
+
+	for idx, line := range strings.Split(string(content), "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+
+		for _, match := range variablePattern.FindAllStringSubmatch(line, -1) {
+			if match[1] == "$" {
+				continue
+			}
+
+			name, operator := match[3], ""
+			if strings.HasPrefix(match[1], "{") {
+				name = match[2]
+				if idx := strings.IndexAny(name, ":-?"); idx != -1 {
+					name, operator = name[:idx], strings.TrimPrefix(name[idx:], ":")
+				}
+			}
+
+			// variables with a default value are always resolved
+			if strings.HasPrefix(operator, "-") {
+				continue
+			}
+
+			severity := WarningSeverity
+			if strings.HasPrefix(operator, "?") {
+				severity = ErrorSeverity
+			}
+
+			if _, ok := environment[name]; ok {
+				continue
+			}
+
+			message := fmt.Sprintf("Variable %s is not defined, an empty string is used", name)
+			if severity == ErrorSeverity {
+				message = fmt.Sprintf("Required variable %s is not defined", name)
+			}
+			problems = append(problems, Problem{Line: idx + 1, Severity: severity, Message: message})
+		}
+	}
+
+	return problems
+}
+
+func sortedKeys(values map[string]interface{}) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// lineIndex associates the dotted path of each key of a YAML document to the line where it is declared
+type lineIndex map[string]int
+
+// indexLines builds the line index of a YAML document. Only the block style is indexed,
+// the keys declared inside flow mappings are located on the line of their parent.
+func indexLines(content []byte) lineIndex {
+	type frame struct {
+		indent int
+		key    string
+	}
+
+	index := make(lineIndex)
+	stack := make([]frame, 0)
+
+	for idx, line := range strings.Split(string(content), "\n") {
+		text := strings.TrimLeft(line, " ")
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		indent := len(line) - len(text)
+		for strings.HasPrefix(text, "- ") {
+			indent += 2
+			text = strings.TrimLeft(text[2:], " ")
+		}
+
+		for len(stack) > 0 && stack[len(stack)-1].indent >= indent {
+			stack = stack[:len(stack)-1]
+		}
+
+		match := keyPattern.FindStringSubmatch(text)
+		if match == nil {
+			continue
+		}
+
+		key := strings.Trim(match[1], `"'`)
+		path := key
+		if len(stack) > 0 {
+			keys := make([]string, 0, len(stack)+1)
+			for _, f := range stack {
+				keys = append(keys, f.key)
+			}
+			path = strings.Join(append(keys, key), ".")
+		}
+
+		if _, ok := index[path]; !ok {
+			index[path] = idx + 1
+		}
+		stack = append(stack, frame{indent: indent, key: key})
+	}
+
+	return index
+}
+
+// line returns the line of the deepest known parent of a path, list indexes are ignored
+func (index lineIndex) line(path string) int {
+	components := make([]string, 0)
+	for _, component := range strings.Split(path, ".") {
+		if _, err := strconv.Atoi(component); err == nil {
+			continue
+		}
+		components = append(components, component)
+	}
+
+	for len(components) > 0 {
+		if line, ok := index[strings.Join(components, ".")]; ok {
+			return line
+		}
+		components = components[:len(components)-1]
+	}
+
+	return 0
+}
+
+func (index lineIndex) problem(severity, path, message string) Problem {
+	return Problem{Line: index.line(path), Severity: severity, Path: path, Message: message}
+}
+
+// schemaProblem locates a schema validation error, these errors start with the path of the invalid field
+func (index lineIndex) schemaProblem(message string) Problem {
+	path := strings.SplitN(message, " ", 2)[0]
+	if path == "(root)" || !strings.Contains(path, ".") && !topLevelKeys[path] {
+		path = ""
+	}
+
+	if match := additionalPropPattern.FindStringSubmatch(message); match != nil {
+		if path == "" {
+			path = match[1]
+		} else {
+			path = path + "." + match[1]
+		}
+	}
+
+	problem := Problem{Severity: ErrorSeverity, Path: path, Message: message}
+	if path != "" {
+		problem.Line = index.line(path)
+	}
+	return problem
+}
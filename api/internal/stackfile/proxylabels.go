@@ -0,0 +1,104 @@
+package stackfile
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/internal/proxylabels"
+)
+
+// lintProxyLabels validates the Traefik labels and the nginx-proxy environment variables of the services.
+// The labels of the Swarm services are read by Traefik from deploy.labels, the labels of the service
+// are applied to the containers of the service and are ignored by the Swarm provider of Traefik.
+func lintProxyLabels(services map[string]interface{}, prefix string, stackType portainer.StackType, environment map[string]string, lines lineIndex) []Problem {
+	problems := make([]Problem, 0)
+	swarm := stackType == portainer.DockerSwarmStack
+
+	for _, name := range sortedKeys(services) {
+		service, ok := services[name].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		path := prefix + name + ".labels"
+		labels := stringMap(service["labels"], environment)
+		if swarm {
+			if hasTraefikLabels(labels) {
+				problems = append(problems, lines.problem(WarningSeverity, path, fmt.Sprintf("The Traefik labels of service %s must be defined in deploy.labels to be read on a Swarm cluster", name)))
+			}
+
+			path = prefix + name + ".deploy.labels"
+			deploy, _ := service["deploy"].(map[string]interface{})
+			labels = stringMap(deploy["labels"], environment)
+		}
+
+		env := stringMap(service["environment"], environment)
+		for _, issue := range proxylabels.Validate(labels, env, swarm) {
+			issuePath := path
+			if !strings.HasPrefix(issue.Key, proxylabels.TraefikLabelPrefix) {
+				issuePath = prefix + name + ".environment"
+			}
+			problems = append(problems, lines.problem(issue.Severity, issuePath, fmt.Sprintf("Service %s: %s", name, issue.Message)))
+		}
+	}
+
+	return problems
+}
+
+func hasTraefikLabels(labels map[string]string) bool {
+	for key := range labels {
+		if strings.HasPrefix(key, proxylabels.TraefikLabelPrefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// stringMap converts the labels or the environment of a service, in the list or the mapping form, to a map
+// and interpolates the variables defined in the environment of the stack
+func stringMap(value interface{}, environment map[string]string) map[string]string {
+	values := make(map[string]string)
+
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for key, item := range value {
+			if item != nil {
+				values[key] = interpolate(fmt.Sprintf("%v", item), environment)
+			}
+		}
+	case []interface{}:
+		for _, item := range value {
+			parts := strings.SplitN(fmt.Sprintf("%v", item), "=", 2)
+			if len(parts) == 2 {
+				values[parts[0]] = interpolate(parts[1], environment)
+			}
+		}
+	}
+
+	return values
+}
+
+func interpolate(value string, environment map[string]string) string {
+	return os.Expand(value, func(name string) string {
+		idx := strings.IndexAny(name, ":-?")
+		if idx <= 0 {
+			return environment[name]
+		}
+
+		operator, fallback := name[idx:idx+1], name[idx+1:]
+		if operator == ":" && len(fallback) > 0 {
+			operator, fallback = name[idx:idx+2], name[idx+2:]
+		}
+
+		resolved, ok := environment[name[:idx]]
+		if ok && (resolved != "" || !strings.HasPrefix(operator, ":")) {
+			return resolved
+		}
+		if strings.HasSuffix(operator, "-") {
+			return fallback
+		}
+		return ""
+	})
+}
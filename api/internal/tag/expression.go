@@ -0,0 +1,285 @@
+package tag
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Expression is a boolean expression evaluated against the metadata of an endpoint, such as
+// region=eu AND (tier=prod OR tier=staging) AND NOT deprecated.
+//
+// The supported terms are key=value, key!=value and key, the latter matching the endpoints
+// defining the key. The terms are combined with AND, OR, NOT (or &&, || and !) and parentheses,
+// NOT has the highest precedence and OR the lowest. Values containing spaces or operators must be quoted.
+type Expression struct {
+	root expressionNode
+}
+
+type expressionNode interface {
+	matches(metadata map[string]string) bool
+}
+
+type (
+	andNode struct{ left, right expressionNode }
+	orNode  struct{ left, right expressionNode }
+	notNode struct{ operand expressionNode }
+
+	termNode struct {
+		key string
+		// value is empty for the terms matching the existence of the key
+		value    string
+		negation bool
+	}
+)
+
+func (node *andNode) matches(metadata map[string]string) bool {
+	return node.left.matches(metadata) && node.right.matches(metadata)
+}
+
+func (node *orNode) matches(metadata map[string]string) bool {
+	return node.left.matches(metadata) || node.right.matches(metadata)
+}
+
+func (node *notNode) matches(metadata map[string]string) bool {
+	return !node.operand.matches(metadata)
+}
+
+func (node *termNode) matches(metadata map[string]string) bool {
+	value, ok := metadata[node.key]
+	if node.value == "" {
+		return ok
+	}
+	return (ok && value == node.value) != node.negation
+}
+
+// ParseExpression parses a tag expression
+func ParseExpression(expression string) (*Expression, error) {
+	tokens, err := tokenizeExpression(expression)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, errors.New("Invalid tag expression: the expression is empty")
+	}
+
+	parser := &expressionParser{tokens: tokens}
+	root, err := parser.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if parser.position < len(parser.tokens) {
+		return nil, fmt.Errorf("Invalid tag expression: unexpected %s", parser.tokens[parser.position])
+	}
+
+	return &Expression{root: root}, nil
+}
+
+// Matches returns true when the metadata of an endpoint satisfies the expression
+func (expression *Expression) Matches(metadata map[string]string) bool {
+	return expression.root.matches(metadata)
+}
+
+// ExpressionMatches parses and evaluates an expression, an invalid expression never matches
+func ExpressionMatches(expression string, metadata map[string]string) bool {
+	parsed, err := ParseExpression(expression)
+	if err != nil {
+		return false
+	}
+	return parsed.Matches(metadata)
+}
+
+type tokenType int
+
+const (
+	tokenWord tokenType = iota
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenEqual
+	tokenNotEqual
+	tokenOpen
+	tokenClose
+)
+
+type expressionToken struct {
+	kind  tokenType
+	value string
+}
+
+func (token expressionToken) String() string {
+	if token.kind == tokenWord {
+		return fmt.Sprintf("'%s'", token.value)
+	}
+	return token.value
+}
+
+func tokenizeExpression(expression string) ([]expressionToken, error) {
+	tokens := make([]expressionToken, 0)
+
+	for idx := 0; idx < len(expression); {
+		char := expression[idx]
+
+		switch {
+		case char == ' ' || char == '\t' || char == '\n':
+			idx++
+		case char == '(':
+			tokens = append(tokens, expressionToken{tokenOpen, "("})
+			idx++
+		case char == ')':
+			tokens = append(tokens, expressionToken{tokenClose, ")"})
+			idx++
+		case strings.HasPrefix(expression[idx:], "&&"):
+			tokens = append(tokens, expressionToken{tokenAnd, "&&"})
+			idx += 2
+		case strings.HasPrefix(expression[idx:], "||"):
+			tokens = append(tokens, expressionToken{tokenOr, "||"})
+			idx += 2
+		case strings.HasPrefix(expression[idx:], "!="):
+			tokens = append(tokens, expressionToken{tokenNotEqual, "!="})
+			idx += 2
+		case strings.HasPrefix(expression[idx:], "=="):
+			tokens = append(tokens, expressionToken{tokenEqual, "=="})
+			idx += 2
+		case char == '=':
+			tokens = append(tokens, expressionToken{tokenEqual, "="})
+			idx++
+		case char == '!':
+			tokens = append(tokens, expressionToken{tokenNot, "!"})
+			idx++
+		case char == '"' || char == '\'':
+			end := strings.IndexByte(expression[idx+1:], char)
+			if end == -1 {
+				return nil, errors.New("Invalid tag expression: unterminated quoted value")
+			}
+			tokens = append(tokens, expressionToken{tokenWord, expression[idx+1 : idx+1+end]})
+			idx += end + 2
+		default:
+			end := idx
+			for end < len(expression) && !strings.ContainsRune(" \t\n()=!&|\"'", rune(expression[end])) {
+				end++
+			}
+			if end == idx {
+				return nil, fmt.Errorf("Invalid tag expression: unexpected character '%c'", char)
+			}
+
+			word := expression[idx:end]
+			switch strings.ToUpper(word) {
+			case "AND":
+				tokens = append(tokens, expressionToken{tokenAnd, word})
+			case "OR":
+				tokens = append(tokens, expressionToken{tokenOr, word})
+			case "NOT":
+				tokens = append(tokens, expressionToken{tokenNot, word})
+			default:
+				tokens = append(tokens, expressionToken{tokenWord, word})
+			}
+			idx = end
+		}
+	}
+
+	return tokens, nil
+}
+
+type expressionParser struct {
+	tokens   []expressionToken
+	position int
+}
+
+func (parser *expressionParser) accept(kind tokenType) bool {
+	if parser.position < len(parser.tokens) && parser.tokens[parser.position].kind == kind {
+		parser.position++
+		return true
+	}
+	return false
+}
+
+func (parser *expressionParser) parseOr() (expressionNode, error) {
+	left, err := parser.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for parser.accept(tokenOr) {
+		right, err := parser.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &orNode{left, right}
+	}
+
+	return left, nil
+}
+
+func (parser *expressionParser) parseAnd() (expressionNode, error) {
+	left, err := parser.parseNot()
+	if err != nil {
+		return nil, err
+	}
+
+	for parser.accept(tokenAnd) {
+		right, err := parser.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &andNode{left, right}
+	}
+
+	return left, nil
+}
+
+func (parser *expressionParser) parseNot() (expressionNode, error) {
+	if parser.accept(tokenNot) {
+		operand, err := parser.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{operand}, nil
+	}
+
+	return parser.parsePrimary()
+}
+
+func (parser *expressionParser) parsePrimary() (expressionNode, error) {
+	if parser.position >= len(parser.tokens) {
+		return nil, errors.New("Invalid tag expression: unexpected end of expression")
+	}
+
+	if parser.accept(tokenOpen) {
+		node, err := parser.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !parser.accept(tokenClose) {
+			return nil, errors.New("Invalid tag expression: missing closing parenthesis")
+		}
+		return node, nil
+	}
+
+	token := parser.tokens[parser.position]
+	if token.kind != tokenWord {
+		return nil, fmt.Errorf("Invalid tag expression: unexpected %s", token)
+	}
+	parser.position++
+
+	if !metadataKeyPattern.MatchString(token.value) {
+		return nil, fmt.Errorf("Invalid tag expression: invalid key %s", token)
+	}
+	term := &termNode{key: token.value}
+
+	negation := false
+	if parser.accept(tokenNotEqual) {
+		negation = true
+	} else if !parser.accept(tokenEqual) {
+		return term, nil
+	}
+
+	if parser.position >= len(parser.tokens) || parser.tokens[parser.position].kind != tokenWord || parser.tokens[parser.position].value == "" {
+		return nil, fmt.Errorf("Invalid tag expression: missing value for key %s", token)
+	}
+	term.value = parser.tokens[parser.position].value
+	term.negation = negation
+	parser.position++
+
+	return term, nil
+}
@@ -0,0 +1,57 @@
+package tag
+
+import "testing"
+
+func TestExpressionMatches(t *testing.T) {
+	metadata := map[string]string{"region": "eu", "tier": "prod", "gpu": "nvidia"}
+
+	cases := []struct {
+		expression string
+		expected   bool
+	}{
+		{"region=eu", true},
+		{"region=us", false},
+		{"region!=us", true},
+		{"zone!=a", true},
+		{"gpu", true},
+		{"NOT gpu", false},
+		{"region=eu AND tier=prod", true},
+		{"region=eu and tier=staging", false},
+		{"region=us OR tier=prod", true},
+		{"region=us || tier=staging", false},
+		{"region=eu && !(tier=staging || tier=dev)", true},
+		{"region=us OR region=eu AND tier=staging", false},
+		{"(region=us OR region=eu) AND tier=prod", true},
+		{`region="eu"`, true},
+	}
+
+	for _, c := range cases {
+		expression, err := ParseExpression(c.expression)
+		if err != nil {
+			t.Fatalf("unable to parse %q: %s", c.expression, err)
+		}
+		if expression.Matches(metadata) != c.expected {
+			t.Errorf("expected %q to evaluate to %t", c.expression, c.expected)
+		}
+	}
+}
+
+func TestParseExpressionErrors(t *testing.T) {
+	invalid := []string{
+		"",
+		"region=",
+		"region=eu AND",
+		"(region=eu",
+		"region=eu)",
+		"=eu",
+		"region=eu tier=prod",
+		`region="eu`,
+	}
+
+	for _, expression := range invalid {
+		_, err := ParseExpression(expression)
+		if err == nil {
+			t.Errorf("expected %q to be invalid", expression)
+		}
+	}
+}
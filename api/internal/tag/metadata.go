@@ -0,0 +1,44 @@
+package tag
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+const maxMetadataLength = 63
+
+var metadataKeyPattern = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9._-]*[a-zA-Z0-9])?$`)
+
+// ValidateMetadata verifies that the keys of the metadata are valid identifiers, such as datacenter or
+// topology.region, and that the keys and values are not longer than 63 characters
+func ValidateMetadata(metadata map[string]string) error {
+	for key, value := range metadata {
+		if !metadataKeyPattern.MatchString(key) || len(key) > maxMetadataLength {
+			return fmt.Errorf("Invalid metadata key: %s. Keys must be composed of alphanumeric characters, '.', '-' or '_'", key)
+		}
+		if value == "" {
+			return fmt.Errorf("Invalid metadata value for key %s. Value cannot be empty", key)
+		}
+		if len(value) > maxMetadataLength {
+			return errors.New("Invalid metadata value. Values cannot be longer than 63 characters")
+		}
+	}
+	return nil
+}
+
+// MetadataMatches returns the number of key/value pairs of the selector found in the metadata
+func MetadataMatches(metadata, selector map[string]string) int {
+	matches := 0
+	for key, value := range selector {
+		if metadata[key] == value {
+			matches++
+		}
+	}
+	return matches
+}
+
+// MetadataContains returns true if the metadata contains all the key/value pairs of the selector
+func MetadataContains(metadata, selector map[string]string) bool {
+	return MetadataMatches(metadata, selector) == len(selector)
+}
@@ -0,0 +1,221 @@
+package task
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/portainer/portainer/api"
+)
+
+const (
+	// maxLogLines is the number of log lines kept for a task, the oldest lines are removed first
+	maxLogLines = 1000
+	// persistInterval is the minimum interval between two writes of the state of a running task
+	persistInterval = time.Second
+	// subscriberBufferSize is the number of updates buffered for a subscriber,
+	// updates are dropped for slow subscribers
+	subscriberBufferSize = 32
+)
+
+const interruptedTaskError = "The task was interrupted by a restart of the server"
+
+// Manager represents a service used to run tasks in the background.
+// The state of the tasks is persisted while they run and each update is pushed to the subscribers of the task.
+type Manager struct {
+	dataStore   portainer.DataStore
+	mu          sync.Mutex
+	subscribers map[portainer.TaskID]map[chan portainer.Task]struct{}
+}
+
+// NewManager returns a new instance of a task manager
+func NewManager(dataStore portainer.DataStore) *Manager {
+	return &Manager{
+		dataStore:   dataStore,
+		subscribers: make(map[portainer.TaskID]map[chan portainer.Task]struct{}),
+	}
+}
+
+// Start marks the tasks that were running before a restart of the server as interrupted.
+// The finished tasks are removed by the retention purge.
+func (manager *Manager) Start() error {
+	tasks, err := manager.dataStore.Task().Tasks()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().Unix()
+	for idx := range tasks {
+		task := &tasks[idx]
+		if task.Status != portainer.TaskPending && task.Status != portainer.TaskRunning {
+			continue
+		}
+
+		task.Status = portainer.TaskInterrupted
+		task.Error = interruptedTaskError
+		task.Finished = now
+
+		err = manager.dataStore.Task().UpdateTask(task.ID, task)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// IsFinished returns true if a task is not pending nor running
+func IsFinished(task *portainer.Task) bool {
+	return task.Status != portainer.TaskPending && task.Status != portainer.TaskRunning
+}
+
+// Run persists a task and executes the specified function in the background
+func (manager *Manager) Run(task *portainer.Task, fn portainer.TaskFunc) error {
+	task.Status = portainer.TaskPending
+	task.Created = time.Now().Unix()
+	task.Logs = []string{}
+
+	err := manager.dataStore.Task().CreateTask(task)
+	if err != nil {
+		return err
+	}
+
+	state := *task
+	go manager.execute(&state, fn)
+
+	return nil
+}
+
+func (manager *Manager) execute(task *portainer.Task, fn portainer.TaskFunc) {
+	reporter := &reporter{
+		manager: manager,
+		task:    task,
+	}
+
+	reporter.update(func(task *portainer.Task) {
+		task.Status = portainer.TaskRunning
+		task.Started = time.Now().Unix()
+	}, true)
+
+	err := fn(reporter)
+
+	reporter.update(func(task *portainer.Task) {
+		task.Finished = time.Now().Unix()
+		if err != nil {
+			task.Status = portainer.TaskFailed
+			task.Error = err.Error()
+			return
+		}
+		task.Status = portainer.TaskSucceeded
+		task.Progress = 100
+	}, true)
+}
+
+// Subscribe returns a channel receiving the state of a task each time it is updated
+// and a function that must be called to stop receiving them
+func (manager *Manager) Subscribe(ID portainer.TaskID) (<-chan portainer.Task, func()) {
+	ch := make(chan portainer.Task, subscriberBufferSize)
+
+	manager.mu.Lock()
+	if manager.subscribers[ID] == nil {
+		manager.subscribers[ID] = make(map[chan portainer.Task]struct{})
+	}
+	manager.subscribers[ID][ch] = struct{}{}
+	manager.mu.Unlock()
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			manager.mu.Lock()
+			delete(manager.subscribers[ID], ch)
+			if len(manager.subscribers[ID]) == 0 {
+				delete(manager.subscribers, ID)
+			}
+			manager.mu.Unlock()
+			close(ch)
+		})
+	}
+
+	return ch, unsubscribe
+}
+
+func (manager *Manager) publish(task portainer.Task) {
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+
+	for ch := range manager.subscribers[task.ID] {
+		select {
+		case ch <- task:
+		default:
+		}
+	}
+}
+
+// reporter is the TaskReporter given to the function executed by a task
+type reporter struct {
+	manager       *Manager
+	mu            sync.Mutex
+	task          *portainer.Task
+	lastPersisted time.Time
+}
+
+// SetProgress updates the progress of the task, a negative progress means that it cannot be estimated
+func (reporter *reporter) SetProgress(progress int, message string) {
+	if progress > 100 {
+		progress = 100
+	} else if progress < 0 {
+		progress = -1
+	}
+
+	reporter.update(func(task *portainer.Task) {
+		task.Progress = progress
+		task.Message = message
+	}, false)
+}
+
+// SetLayer updates the progress of an image layer pulled by the task
+func (reporter *reporter) SetLayer(layer portainer.TaskLayer) {
+	reporter.update(func(task *portainer.Task) {
+		for idx := range task.Layers {
+			if task.Layers[idx].ID == layer.ID {
+				task.Layers[idx] = layer
+				return
+			}
+		}
+		task.Layers = append(task.Layers, layer)
+	}, false)
+}
+
+// Log appends a line to the logs of the task
+func (reporter *reporter) Log(line string) {
+	reporter.update(func(task *portainer.Task) {
+		task.Logs = append(task.Logs, line)
+		if len(task.Logs) > maxLogLines {
+			task.Logs = task.Logs[len(task.Logs)-maxLogLines:]
+		}
+	}, false)
+}
+
+// update applies a change to the task and publishes the new state to the subscribers. Intermediate states
+// are persisted at most once per persistInterval, forced updates are always persisted.
+func (reporter *reporter) update(change func(task *portainer.Task), force bool) {
+	reporter.mu.Lock()
+	defer reporter.mu.Unlock()
+
+	change(reporter.task)
+
+	state := *reporter.task
+	state.Logs = make([]string, len(reporter.task.Logs))
+	copy(state.Logs, reporter.task.Logs)
+	state.Layers = append([]portainer.TaskLayer(nil), reporter.task.Layers...)
+
+	if force || time.Since(reporter.lastPersisted) >= persistInterval {
+		err := reporter.manager.dataStore.Task().UpdateTask(state.ID, &state)
+		if err != nil {
+			log.Printf("[WARN] [internal,task] [message: unable to persist task state] [task: %d] [error: %s]", state.ID, err)
+		}
+		reporter.lastPersisted = time.Now()
+	}
+
+	reporter.manager.publish(state)
+}
@@ -0,0 +1,300 @@
+package telemetry
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/portainer/portainer/api"
+	bolterrors "github.com/portainer/portainer/api/bolt/errors"
+)
+
+const (
+	// flushInterval is the interval between two writes of the counters in the database
+	flushInterval = 5 * time.Minute
+	// reportPeriod is the minimum duration between two reports sent in online mode
+	reportPeriod = 24 * time.Hour
+	sendTimeout  = 30 * time.Second
+)
+
+var (
+	// ErrTelemetryNotOnline is returned when a report is sent while the telemetry is not in online mode
+	ErrTelemetryNotOnline = errors.New("Telemetry is not in online mode")
+	errNoTelemetryURL     = errors.New("No telemetry URL defined")
+)
+
+var endpointTypeNames = map[portainer.EndpointType]string{
+	portainer.DockerEnvironment:                "docker",
+	portainer.AgentOnDockerEnvironment:         "agent_docker",
+	portainer.AzureEnvironment:                 "azure",
+	portainer.EdgeAgentOnDockerEnvironment:     "edge_agent_docker",
+	portainer.KubernetesLocalEnvironment:       "kubernetes_local",
+	portainer.AgentOnKubernetesEnvironment:     "agent_kubernetes",
+	portainer.EdgeAgentOnKubernetesEnvironment: "edge_agent_kubernetes",
+	portainer.KubeconfigKubernetesEnvironment:  "kubeconfig_kubernetes",
+}
+
+var httpClient = &http.Client{Timeout: sendTimeout}
+
+// Service represents a service aggregating anonymized feature usage counters.
+// The counters are only aggregated when the telemetry is enabled in the settings, they are kept
+// in memory and periodically added to the counters stored in the database.
+type Service struct {
+	dataStore portainer.DataStore
+	mu        sync.Mutex
+	mode      portainer.TelemetryMode
+	pending   map[string]int64
+}
+
+// NewService returns a new instance of a telemetry service
+func NewService(dataStore portainer.DataStore) *Service {
+	return &Service{
+		dataStore: dataStore,
+		mode:      portainer.TelemetryDisabled,
+		pending:   make(map[string]int64),
+	}
+}
+
+// Start loads the telemetry mode from the settings and starts a background routine writing the counters
+// in the database and sending the reports when they are due
+func (service *Service) Start() {
+	settings, err := service.dataStore.Settings().Settings()
+	if err != nil {
+		log.Printf("[WARN] [internal,telemetry] [message: unable to retrieve settings] [error: %s]", err)
+	} else {
+		service.SetMode(settings.Telemetry.Mode)
+	}
+
+	go func() {
+		ticker := time.NewTicker(flushInterval)
+		for range ticker.C {
+			err := service.flush()
+			if err != nil {
+				log.Printf("[WARN] [internal,telemetry] [message: unable to persist telemetry counters] [error: %s]", err)
+				continue
+			}
+
+			service.sendIfDue()
+		}
+	}()
+}
+
+// SetMode updates the telemetry mode. The counters are discarded when the telemetry is disabled.
+func (service *Service) SetMode(mode portainer.TelemetryMode) {
+	if mode == "" {
+		mode = portainer.TelemetryDisabled
+	}
+
+	service.mu.Lock()
+	service.mode = mode
+	if mode == portainer.TelemetryDisabled {
+		service.pending = make(map[string]int64)
+	}
+	service.mu.Unlock()
+
+	if mode == portainer.TelemetryDisabled {
+		err := service.dataStore.TelemetryCounter().UpdateCounters(newCounters())
+		if err != nil {
+			log.Printf("[WARN] [internal,telemetry] [message: unable to reset telemetry counters] [error: %s]", err)
+		}
+	}
+}
+
+// Record increments the usage counter of a feature. It is a no-op when the telemetry is disabled.
+func (service *Service) Record(feature string) {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+
+	if service.mode == portainer.TelemetryDisabled {
+		return
+	}
+	service.pending[feature]++
+}
+
+// Report returns the exact payload of the next telemetry report
+func (service *Service) Report() (*portainer.TelemetryReport, error) {
+	err := service.flush()
+	if err != nil {
+		return nil, err
+	}
+
+	counters, err := service.counters()
+	if err != nil {
+		return nil, err
+	}
+
+	instanceID, err := service.dataStore.Version().InstanceID()
+	if err != nil {
+		return nil, err
+	}
+	hash := sha256.Sum256([]byte(instanceID))
+
+	report := &portainer.TelemetryReport{
+		InstanceHash: hex.EncodeToString(hash[:]),
+		Version:      portainer.APIVersion,
+		OS:           runtime.GOOS,
+		Arch:         runtime.GOARCH,
+		Since:        counters.Since,
+		Until:        time.Now().Unix(),
+		Endpoints:    make(map[string]int),
+		Usage:        counters.Usage,
+	}
+
+	endpoints, err := service.dataStore.Endpoint().Endpoints()
+	if err != nil {
+		return nil, err
+	}
+	for _, endpoint := range endpoints {
+		report.Endpoints[endpointTypeName(endpoint.Type)]++
+	}
+
+	stacks, err := service.dataStore.Stack().Stacks()
+	if err != nil {
+		return nil, err
+	}
+	report.Stacks = len(stacks)
+
+	users, err := service.dataStore.User().Users()
+	if err != nil {
+		return nil, err
+	}
+	report.Users = len(users)
+
+	teams, err := service.dataStore.Team().Teams()
+	if err != nil {
+		return nil, err
+	}
+	report.Teams = len(teams)
+
+	return report, nil
+}
+
+// Send sends the telemetry report to the URL defined in the settings and resets the counters.
+// It returns an error when the telemetry is not in online mode.
+func (service *Service) Send() error {
+	settings, err := service.dataStore.Settings().Settings()
+	if err != nil {
+		return err
+	}
+
+	if settings.Telemetry.Mode != portainer.TelemetryOnline {
+		return ErrTelemetryNotOnline
+	}
+
+	if settings.Telemetry.URL == "" {
+		return errNoTelemetryURL
+	}
+
+	report, err := service.Report()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+
+	resp, err := httpClient.Post(settings.Telemetry.URL, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("telemetry server responded with status %d", resp.StatusCode)
+	}
+
+	counters := newCounters()
+	counters.Since = report.Until
+	counters.LastSent = report.Until
+
+	service.mu.Lock()
+	defer service.mu.Unlock()
+	return service.dataStore.TelemetryCounter().UpdateCounters(counters)
+}
+
+// flush adds the pending counters to the counters stored in the database
+func (service *Service) flush() error {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+
+	if len(service.pending) == 0 {
+		return nil
+	}
+
+	counters, err := service.counters()
+	if err != nil {
+		return err
+	}
+
+	for feature, count := range service.pending {
+		counters.Usage[feature] += count
+	}
+
+	err = service.dataStore.TelemetryCounter().UpdateCounters(counters)
+	if err != nil {
+		return err
+	}
+
+	service.pending = make(map[string]int64)
+	return nil
+}
+
+func (service *Service) sendIfDue() {
+	counters, err := service.counters()
+	if err != nil {
+		return
+	}
+
+	last := counters.LastSent
+	if last == 0 {
+		last = counters.Since
+	}
+	if time.Unix(last, 0).Add(reportPeriod).After(time.Now()) {
+		return
+	}
+
+	err = service.Send()
+	if err != nil && err != ErrTelemetryNotOnline {
+		log.Printf("[WARN] [internal,telemetry] [message: unable to send telemetry report] [error: %s]", err)
+	}
+}
+
+// counters returns the counters stored in the database, initializing them when none is found
+func (service *Service) counters() (*portainer.TelemetryCounters, error) {
+	counters, err := service.dataStore.TelemetryCounter().Counters()
+	if err == bolterrors.ErrObjectNotFound {
+		return newCounters(), nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if counters.Usage == nil {
+		counters.Usage = make(map[string]int64)
+	}
+	return counters, nil
+}
+
+func newCounters() *portainer.TelemetryCounters {
+	return &portainer.TelemetryCounters{
+		Since: time.Now().Unix(),
+		Usage: make(map[string]int64),
+	}
+}
+
+func endpointTypeName(endpointType portainer.EndpointType) string {
+	name, ok := endpointTypeNames[endpointType]
+	if !ok {
+		return "unknown"
+	}
+	return name
+}
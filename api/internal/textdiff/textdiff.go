@@ -0,0 +1,169 @@
+package textdiff
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// contextLines is the number of unchanged lines displayed around the changes
+	contextLines = 3
+	// maxComparisons bounds the size of the table used to compare the texts, larger texts are
+	// reported as entirely replaced
+	maxComparisons = 25000000
+)
+
+type operation struct {
+	kind byte
+	line string
+	// from and to are the number of lines of the two texts before the operation
+	from int
+	to   int
+}
+
+// Unified returns the line differences between two texts in the unified diff format.
+// An empty string is returned when the texts are identical.
+func Unified(fromLabel, toLabel, from, to string) string {
+	operations := diffLines(splitLines(from), splitLines(to))
+
+	changed := false
+	for _, op := range operations {
+		if op.kind != ' ' {
+			changed = true
+			break
+		}
+	}
+	if !changed {
+		return ""
+	}
+
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "--- %s\n+++ %s\n", fromLabel, toLabel)
+
+	for start := 0; start < len(operations); {
+		first := nextChange(operations, start)
+		if first == -1 {
+			break
+		}
+
+		last := first
+		for {
+			next := nextChange(operations, last+1)
+			if next == -1 || next-last > 2*contextLines {
+				break
+			}
+			last = next
+		}
+
+		hunkStart := max(first-contextLines, 0)
+		hunkEnd := min(last+contextLines+1, len(operations))
+		writeHunk(&builder, operations[hunkStart:hunkEnd])
+
+		start = hunkEnd
+	}
+
+	return builder.String()
+}
+
+func writeHunk(builder *strings.Builder, operations []operation) {
+	fromCount, toCount := 0, 0
+	for _, op := range operations {
+		if op.kind != '+' {
+			fromCount++
+		}
+		if op.kind != '-' {
+			toCount++
+		}
+	}
+
+	fmt.Fprintf(builder, "@@ -%s +%s @@\n", hunkRange(operations[0].from, fromCount), hunkRange(operations[0].to, toCount))
+	for _, op := range operations {
+		builder.WriteByte(op.kind)
+		builder.WriteString(op.line)
+		builder.WriteByte('\n')
+	}
+}
+
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
+func nextChange(operations []operation, start int) int {
+	for idx := start; idx < len(operations); idx++ {
+		if operations[idx].kind != ' ' {
+			return idx
+		}
+	}
+	return -1
+}
+
+// diffLines computes the operations transforming a into b from their longest common subsequence
+func diffLines(a, b []string) []operation {
+	operations := make([]operation, 0, len(a)+len(b))
+
+	if len(a)*len(b) > maxComparisons {
+		for idx, line := range a {
+			operations = append(operations, operation{kind: '-', line: line, from: idx})
+		}
+		for idx, line := range b {
+			operations = append(operations, operation{kind: '+', line: line, from: len(a), to: idx})
+		}
+		return operations
+	}
+
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			operations = append(operations, operation{kind: ' ', line: a[i], from: i, to: j})
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			operations = append(operations, operation{kind: '-', line: a[i], from: i, to: j})
+			i++
+		default:
+			operations = append(operations, operation{kind: '+', line: b[j], from: i, to: j})
+			j++
+		}
+	}
+
+	return operations
+}
+
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
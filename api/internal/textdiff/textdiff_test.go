@@ -0,0 +1,57 @@
+package textdiff
+
+import "testing"
+
+func TestUnified(t *testing.T) {
+	from := "version: '2'\nservices:\n  web:\n    image: nginx:1.18\n    ports:\n      - 80:80\n"
+	to := "version: '2'\nservices:\n  web:\n    image: nginx:1.19\n    ports:\n      - 80:80\n      - 443:443\n"
+
+	expected := `--- revision 1
++++ revision 2
+@@ -1,6 +1,7 @@
+ version: '2'
+ services:
+   web:
+-    image: nginx:1.18
++    image: nginx:1.19
+     ports:
+       - 80:80
++      - 443:443
+`
+
+	diff := Unified("revision 1", "revision 2", from, to)
+	if diff != expected {
+		t.Errorf("unexpected diff:\n%s", diff)
+	}
+}
+
+func TestUnifiedSeparateHunks(t *testing.T) {
+	from := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\n"
+	to := "A\nb\nc\nd\ne\nf\ng\nh\ni\n"
+
+	expected := `--- a
++++ b
+@@ -1,4 +1,4 @@
+-a
++A
+ b
+ c
+ d
+@@ -7,4 +7,3 @@
+ g
+ h
+ i
+-j
+`
+
+	diff := Unified("a", "b", from, to)
+	if diff != expected {
+		t.Errorf("unexpected diff:\n%s", diff)
+	}
+}
+
+func TestUnifiedIdentical(t *testing.T) {
+	if diff := Unified("a", "b", "same\n", "same\n"); diff != "" {
+		t.Errorf("expected no diff, got:\n%s", diff)
+	}
+}
@@ -0,0 +1,80 @@
+package useractivity
+
+import (
+	"log"
+	"time"
+
+	"github.com/portainer/portainer/api"
+)
+
+// Service represents a service recording the logins and the activity of the users. The last login
+// of a user is stored with the user, the logins, failed logins and requests are stored in the activity log.
+type Service struct {
+	dataStore portainer.DataStore
+}
+
+// NewService returns a new instance of a user activity service
+func NewService(dataStore portainer.DataStore) *Service {
+	return &Service{
+		dataStore: dataStore,
+	}
+}
+
+// RecordLogin updates the last login of a user and records the login in the activity log
+func (service *Service) RecordLogin(user *portainer.User, sourceIP string, method portainer.AuthenticationMethod) error {
+	now := time.Now().Unix()
+
+	user.LastLogin = &portainer.UserLogin{
+		Date:                 now,
+		SourceIP:             sourceIP,
+		AuthenticationMethod: method,
+	}
+
+	err := service.dataStore.User().UpdateUser(user.ID, user)
+	if err != nil {
+		return err
+	}
+
+	return service.dataStore.UserActivity().CreateUserActivity(&portainer.UserActivity{
+		UserID:               user.ID,
+		Type:                 portainer.UserActivityLogin,
+		Date:                 now,
+		SourceIP:             sourceIP,
+		AuthenticationMethod: method,
+	})
+}
+
+// RecordLoginFailure records a login attempt of an existing user with invalid credentials
+func (service *Service) RecordLoginFailure(user *portainer.User, sourceIP string, method portainer.AuthenticationMethod) {
+	err := service.dataStore.UserActivity().CreateUserActivity(&portainer.UserActivity{
+		UserID:               user.ID,
+		Type:                 portainer.UserActivityLoginFailure,
+		Date:                 time.Now().Unix(),
+		SourceIP:             sourceIP,
+		AuthenticationMethod: method,
+	})
+	if err != nil {
+		log.Printf("[WARN] [internal,useractivity] [message: unable to record failed login] [user: %s] [error: %s]", user.Username, err)
+	}
+}
+
+// RecordRequest records a request made by a user, unless the request logging is disabled in the settings
+func (service *Service) RecordRequest(activity *portainer.UserActivity) {
+	settings, err := service.dataStore.Settings().Settings()
+	if err != nil {
+		log.Printf("[WARN] [internal,useractivity] [message: unable to retrieve settings] [error: %s]", err)
+		return
+	}
+
+	if settings.UserActivity.DisableRequestLogging {
+		return
+	}
+
+	activity.Type = portainer.UserActivityRequest
+	activity.Date = time.Now().Unix()
+
+	err = service.dataStore.UserActivity().CreateUserActivity(activity)
+	if err != nil {
+		log.Printf("[WARN] [internal,useractivity] [message: unable to record request] [user_id: %d] [error: %s]", activity.UserID, err)
+	}
+}
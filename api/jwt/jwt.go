@@ -19,12 +19,17 @@ type Service struct {
 }
 
 type claims struct {
-	UserID   int    `json:"id"`
-	Username string `json:"username"`
-	Role     int    `json:"role"`
+	UserID               int    `json:"id"`
+	Username             string `json:"username"`
+	Role                 int    `json:"role"`
+	ImpersonatorID       int    `json:"impersonatorId,omitempty"`
+	ImpersonatorUsername string `json:"impersonatorUsername,omitempty"`
 	jwt.StandardClaims
 }
 
+// impersonationSessionTimeout is the maximum duration of an impersonation token
+const impersonationSessionTimeout = time.Hour
+
 var (
 	errSecretGeneration = errors.New("Unable to generate secret key")
 	errInvalidJWTToken  = errors.New("Invalid JWT token")
@@ -51,11 +56,18 @@ func NewService(userSessionDuration string) (*Service, error) {
 
 // GenerateToken generates a new JWT token.
 func (service *Service) GenerateToken(data *portainer.TokenData) (string, error) {
-	expireToken := time.Now().Add(service.userSessionTimeout).Unix()
+	sessionTimeout := service.userSessionTimeout
+	if data.ImpersonatorID != 0 && sessionTimeout > impersonationSessionTimeout {
+		sessionTimeout = impersonationSessionTimeout
+	}
+
+	expireToken := time.Now().Add(sessionTimeout).Unix()
 	cl := claims{
-		UserID:   int(data.ID),
-		Username: data.Username,
-		Role:     int(data.Role),
+		UserID:               int(data.ID),
+		Username:             data.Username,
+		Role:                 int(data.Role),
+		ImpersonatorID:       int(data.ImpersonatorID),
+		ImpersonatorUsername: data.ImpersonatorUsername,
 		StandardClaims: jwt.StandardClaims{
 			ExpiresAt: expireToken,
 		},
@@ -82,9 +94,11 @@ func (service *Service) ParseAndVerifyToken(token string) (*portainer.TokenData,
 	if err == nil && parsedToken != nil {
 		if cl, ok := parsedToken.Claims.(*claims); ok && parsedToken.Valid {
 			tokenData := &portainer.TokenData{
-				ID:       portainer.UserID(cl.UserID),
-				Username: cl.Username,
-				Role:     portainer.UserRole(cl.Role),
+				ID:                   portainer.UserID(cl.UserID),
+				Username:             cl.Username,
+				Role:                 portainer.UserRole(cl.Role),
+				ImpersonatorID:       portainer.UserID(cl.ImpersonatorID),
+				ImpersonatorUsername: cl.ImpersonatorUsername,
 			}
 			return tokenData, nil
 		}
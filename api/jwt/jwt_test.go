@@ -0,0 +1,31 @@
+package jwt
+
+import (
+	"testing"
+
+	"github.com/portainer/portainer/api"
+)
+
+func TestImpersonationToken(t *testing.T) {
+	service, err := NewService("8h")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := service.GenerateToken(&portainer.TokenData{ID: 2, Username: "bob", Role: portainer.StandardUserRole, ImpersonatorID: 1, ImpersonatorUsername: "admin"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tokenData, err := service.ParseAndVerifyToken(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if tokenData.ID != 2 || tokenData.Username != "bob" {
+		t.Errorf("token returned wrong user: got %d %s want 2 bob", tokenData.ID, tokenData.Username)
+	}
+	if tokenData.ImpersonatorID != 1 || tokenData.ImpersonatorUsername != "admin" {
+		t.Errorf("token returned wrong impersonator: got %d %s want 1 admin", tokenData.ImpersonatorID, tokenData.ImpersonatorUsername)
+	}
+}
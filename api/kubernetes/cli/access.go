@@ -84,3 +84,36 @@ func hasUserAccessToNamespace(userID int, teamIDs []int, policies accessPolicies
 
 	return false
 }
+
+// UserCanAccessNamespace returns true if the namespace access policies grant access to the specified namespace
+// to the user or to one of its teams. The default namespace is accessible to every user.
+func (kcl *KubeClient) UserCanAccessNamespace(userID int, teamIDs []int, namespace string) (bool, error) {
+	if namespace == defaultNamespace {
+		return true, nil
+	}
+
+	configMap, err := kcl.cli.CoreV1().ConfigMaps(portainerNamespace).Get(portainerConfigMapName, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	accessData, ok := configMap.Data[portainerConfigMapAccessPoliciesKey]
+	if !ok {
+		return false, nil
+	}
+
+	var accessPolicies namespaceAccessPolicies
+	err = json.Unmarshal([]byte(accessData), &accessPolicies)
+	if err != nil {
+		return false, err
+	}
+
+	policies, ok := accessPolicies[namespace]
+	if !ok {
+		return false, nil
+	}
+
+	return hasUserAccessToNamespace(userID, teamIDs, policies), nil
+}
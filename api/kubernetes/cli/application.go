@@ -0,0 +1,219 @@
+package cli
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+
+	portainer "github.com/portainer/portainer/api"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const deploymentRevisionAnnotation = "deployment.kubernetes.io/revision"
+
+var errUnsupportedApplicationKind = errors.New("unsupported application kind")
+
+// GetApplications returns the Deployments, StatefulSets and DaemonSets of a namespace as applications.
+// Applications of all namespaces are returned when namespace is empty.
+func (kcl *KubeClient) GetApplications(namespace string) ([]portainer.KubernetesApplication, error) {
+	pods, err := kcl.cli.CoreV1().Pods(namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	applications := make([]portainer.KubernetesApplication, 0)
+
+	deployments, err := kcl.cli.AppsV1().Deployments(namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for idx := range deployments.Items {
+		application := deploymentApplication(&deployments.Items[idx])
+		application.Pods = applicationPods(pods.Items, application.Namespace, deployments.Items[idx].Spec.Selector)
+		applications = append(applications, *application)
+	}
+
+	statefulSets, err := kcl.cli.AppsV1().StatefulSets(namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for idx := range statefulSets.Items {
+		application := statefulSetApplication(&statefulSets.Items[idx])
+		application.Pods = applicationPods(pods.Items, application.Namespace, statefulSets.Items[idx].Spec.Selector)
+		applications = append(applications, *application)
+	}
+
+	daemonSets, err := kcl.cli.AppsV1().DaemonSets(namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for idx := range daemonSets.Items {
+		application := daemonSetApplication(&daemonSets.Items[idx])
+		application.Pods = applicationPods(pods.Items, application.Namespace, daemonSets.Items[idx].Spec.Selector)
+		applications = append(applications, *application)
+	}
+
+	sort.SliceStable(applications, func(i, j int) bool {
+		if applications[i].Namespace != applications[j].Namespace {
+			return applications[i].Namespace < applications[j].Namespace
+		}
+		return applications[i].Name < applications[j].Name
+	})
+
+	return applications, nil
+}
+
+// GetApplication returns a single application of the specified kind.
+func (kcl *KubeClient) GetApplication(namespace string, kind portainer.KubernetesApplicationKind, name string) (*portainer.KubernetesApplication, error) {
+	var application *portainer.KubernetesApplication
+	var selector *metav1.LabelSelector
+
+	switch kind {
+	case portainer.KubernetesApplicationDeployment:
+		deployment, err := kcl.cli.AppsV1().Deployments(namespace).Get(name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		application, selector = deploymentApplication(deployment), deployment.Spec.Selector
+	case portainer.KubernetesApplicationStatefulSet:
+		statefulSet, err := kcl.cli.AppsV1().StatefulSets(namespace).Get(name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		application, selector = statefulSetApplication(statefulSet), statefulSet.Spec.Selector
+	case portainer.KubernetesApplicationDaemonSet:
+		daemonSet, err := kcl.cli.AppsV1().DaemonSets(namespace).Get(name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		application, selector = daemonSetApplication(daemonSet), daemonSet.Spec.Selector
+	default:
+		return nil, errUnsupportedApplicationKind
+	}
+
+	listOptions, err := selectorListOptions(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	pods, err := kcl.cli.CoreV1().Pods(namespace).List(listOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	application.Pods = applicationPods(pods.Items, namespace, selector)
+
+	return application, nil
+}
+
+func deploymentApplication(deployment *appsv1.Deployment) *portainer.KubernetesApplication {
+	revision, _ := strconv.ParseInt(deployment.Annotations[deploymentRevisionAnnotation], 10, 64)
+
+	return &portainer.KubernetesApplication{
+		Name:              deployment.Name,
+		Namespace:         deployment.Namespace,
+		Kind:              portainer.KubernetesApplicationDeployment,
+		Images:            templateImages(&deployment.Spec.Template),
+		DesiredReplicas:   replicasOrDefault(deployment.Spec.Replicas),
+		ReadyReplicas:     deployment.Status.ReadyReplicas,
+		UpdatedReplicas:   deployment.Status.UpdatedReplicas,
+		AvailableReplicas: deployment.Status.AvailableReplicas,
+		Paused:            deployment.Spec.Paused,
+		Revision:          revision,
+		CreationDate:      deployment.CreationTimestamp.Unix(),
+		Rollout:           deploymentRolloutStatus(deployment),
+	}
+}
+
+func statefulSetApplication(statefulSet *appsv1.StatefulSet) *portainer.KubernetesApplication {
+	return &portainer.KubernetesApplication{
+		Name:              statefulSet.Name,
+		Namespace:         statefulSet.Namespace,
+		Kind:              portainer.KubernetesApplicationStatefulSet,
+		Images:            templateImages(&statefulSet.Spec.Template),
+		DesiredReplicas:   replicasOrDefault(statefulSet.Spec.Replicas),
+		ReadyReplicas:     statefulSet.Status.ReadyReplicas,
+		UpdatedReplicas:   statefulSet.Status.UpdatedReplicas,
+		AvailableReplicas: statefulSet.Status.ReadyReplicas,
+		CreationDate:      statefulSet.CreationTimestamp.Unix(),
+		Rollout:           statefulSetRolloutStatus(statefulSet),
+	}
+}
+
+func daemonSetApplication(daemonSet *appsv1.DaemonSet) *portainer.KubernetesApplication {
+	return &portainer.KubernetesApplication{
+		Name:              daemonSet.Name,
+		Namespace:         daemonSet.Namespace,
+		Kind:              portainer.KubernetesApplicationDaemonSet,
+		Images:            templateImages(&daemonSet.Spec.Template),
+		DesiredReplicas:   daemonSet.Status.DesiredNumberScheduled,
+		ReadyReplicas:     daemonSet.Status.NumberReady,
+		UpdatedReplicas:   daemonSet.Status.UpdatedNumberScheduled,
+		AvailableReplicas: daemonSet.Status.NumberAvailable,
+		CreationDate:      daemonSet.CreationTimestamp.Unix(),
+		Rollout:           daemonSetRolloutStatus(daemonSet),
+	}
+}
+
+func applicationPods(pods []v1.Pod, namespace string, selector *metav1.LabelSelector) []portainer.KubernetesPod {
+	applicationPods := make([]portainer.KubernetesPod, 0)
+
+	labelSelector, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil || labelSelector.Empty() {
+		return applicationPods
+	}
+
+	for _, pod := range pods {
+		if pod.Namespace != namespace || !labelSelector.Matches(labels.Set(pod.Labels)) {
+			continue
+		}
+
+		applicationPod := portainer.KubernetesPod{
+			Name:         pod.Name,
+			Node:         pod.Spec.NodeName,
+			Phase:        string(pod.Status.Phase),
+			CreationDate: pod.CreationTimestamp.Unix(),
+		}
+
+		for _, condition := range pod.Status.Conditions {
+			if condition.Type == v1.PodReady {
+				applicationPod.Ready = condition.Status == v1.ConditionTrue
+			}
+		}
+
+		for _, containerStatus := range pod.Status.ContainerStatuses {
+			applicationPod.Restarts += containerStatus.RestartCount
+		}
+
+		applicationPods = append(applicationPods, applicationPod)
+	}
+
+	return applicationPods
+}
+
+func selectorListOptions(selector *metav1.LabelSelector) (metav1.ListOptions, error) {
+	labelSelector, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return metav1.ListOptions{}, err
+	}
+
+	return metav1.ListOptions{LabelSelector: labelSelector.String()}, nil
+}
+
+func templateImages(template *v1.PodTemplateSpec) []string {
+	images := make([]string, 0)
+	for _, container := range template.Spec.Containers {
+		images = append(images, container.Image)
+	}
+	return images
+}
+
+func replicasOrDefault(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+	return *replicas
+}
@@ -81,6 +81,8 @@ func (factory *ClientFactory) CreateClient(endpoint *portainer.Endpoint) (*kuber
 		return factory.buildAgentClient(endpoint)
 	case portainer.EdgeAgentOnKubernetesEnvironment:
 		return factory.buildEdgeClient(endpoint)
+	case portainer.KubeconfigKubernetesEnvironment:
+		return buildKubeconfigClient(endpoint)
 	}
 
 	return nil, errors.New("unsupported endpoint type")
@@ -0,0 +1,21 @@
+package cli
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ScaleDeployment updates the number of replicas of a deployment.
+func (kcl *KubeClient) ScaleDeployment(namespace, name string, replicas int32) error {
+	scale, err := kcl.cli.AppsV1().Deployments(namespace).GetScale(name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	if scale.Spec.Replicas == replicas {
+		return nil
+	}
+
+	scale.Spec.Replicas = replicas
+	_, err = kcl.cli.AppsV1().Deployments(namespace).UpdateScale(name, scale)
+	return err
+}
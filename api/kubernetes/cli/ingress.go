@@ -0,0 +1,255 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	portainer "github.com/portainer/portainer/api"
+	v1 "k8s.io/api/core/v1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	ingressClassAnnotation  = "kubernetes.io/ingress.class"
+	certManagerGroupVersion = "cert-manager.io/v1alpha2"
+)
+
+var errCertManagerNotInstalled = errors.New("cert-manager is not installed inside the cluster")
+
+// GetIngresses returns the ingresses of a namespace. Ingresses of all namespaces are returned when namespace is empty.
+func (kcl *KubeClient) GetIngresses(namespace string) ([]portainer.KubernetesIngress, error) {
+	ingressList, err := kcl.cli.NetworkingV1beta1().Ingresses(namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	ingresses := make([]portainer.KubernetesIngress, 0)
+	for idx := range ingressList.Items {
+		ingresses = append(ingresses, *ingressFromResource(&ingressList.Items[idx]))
+	}
+
+	return ingresses, nil
+}
+
+// GetIngress returns a single ingress.
+func (kcl *KubeClient) GetIngress(namespace, name string) (*portainer.KubernetesIngress, error) {
+	ingress, err := kcl.cli.NetworkingV1beta1().Ingresses(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	return ingressFromResource(ingress), nil
+}
+
+// CreateIngress creates a new ingress.
+func (kcl *KubeClient) CreateIngress(ingress *portainer.KubernetesIngress) error {
+	resource := &networkingv1beta1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ingress.Name,
+			Namespace: ingress.Namespace,
+		},
+	}
+	applyIngressToResource(ingress, resource)
+
+	created, err := kcl.cli.NetworkingV1beta1().Ingresses(ingress.Namespace).Create(resource)
+	if err != nil {
+		return err
+	}
+
+	ingress.CreationDate = created.CreationTimestamp.Unix()
+	return nil
+}
+
+// UpdateIngress replaces the class, annotations, rules and TLS configuration of an existing ingress.
+func (kcl *KubeClient) UpdateIngress(ingress *portainer.KubernetesIngress) error {
+	resource, err := kcl.cli.NetworkingV1beta1().Ingresses(ingress.Namespace).Get(ingress.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	applyIngressToResource(ingress, resource)
+
+	_, err = kcl.cli.NetworkingV1beta1().Ingresses(ingress.Namespace).Update(resource)
+	return err
+}
+
+// DeleteIngress removes an ingress.
+func (kcl *KubeClient) DeleteIngress(namespace, name string) error {
+	return kcl.cli.NetworkingV1beta1().Ingresses(namespace).Delete(name, &metav1.DeleteOptions{})
+}
+
+// GetIngressClasses returns the ingress classes referenced by the existing ingresses of the cluster.
+func (kcl *KubeClient) GetIngressClasses() ([]string, error) {
+	ingressList, err := kcl.cli.NetworkingV1beta1().Ingresses(metav1.NamespaceAll).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	classes := make(map[string]bool)
+	for _, ingress := range ingressList.Items {
+		class := ingress.Annotations[ingressClassAnnotation]
+		if class != "" {
+			classes[class] = true
+		}
+	}
+
+	ingressClasses := make([]string, 0)
+	for class := range classes {
+		ingressClasses = append(ingressClasses, class)
+	}
+	sort.Strings(ingressClasses)
+
+	return ingressClasses, nil
+}
+
+// CreateTLSSecret creates a secret of type kubernetes.io/tls that can be referenced by ingresses.
+func (kcl *KubeClient) CreateTLSSecret(namespace, name string, certificate, key []byte) error {
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Type: v1.SecretTypeTLS,
+		Data: map[string][]byte{
+			v1.TLSCertKey:       certificate,
+			v1.TLSPrivateKeyKey: key,
+		},
+	}
+
+	_, err := kcl.cli.CoreV1().Secrets(namespace).Create(secret)
+	return err
+}
+
+// CreateCertificate creates a cert-manager Certificate resource. The certificate and its key are
+// stored by cert-manager inside the secret referenced by the certificate.
+func (kcl *KubeClient) CreateCertificate(certificate *portainer.KubernetesCertificate) error {
+	_, err := kcl.cli.Discovery().ServerResourcesForGroupVersion(certManagerGroupVersion)
+	if k8serrors.IsNotFound(err) {
+		return errCertManagerNotInstalled
+	} else if err != nil {
+		return err
+	}
+
+	resource := map[string]interface{}{
+		"apiVersion": certManagerGroupVersion,
+		"kind":       "Certificate",
+		"metadata": map[string]interface{}{
+			"name":      certificate.Name,
+			"namespace": certificate.Namespace,
+		},
+		"spec": map[string]interface{}{
+			"secretName": certificate.SecretName,
+			"dnsNames":   certificate.DNSNames,
+			"issuerRef": map[string]interface{}{
+				"name": certificate.IssuerName,
+				"kind": certificate.IssuerKind,
+			},
+		},
+	}
+
+	body, err := json.Marshal(resource)
+	if err != nil {
+		return err
+	}
+
+	return kcl.cli.RESTClient().Post().
+		AbsPath(fmt.Sprintf("/apis/%s/namespaces/%s/certificates", certManagerGroupVersion, certificate.Namespace)).
+		SetHeader("Content-Type", "application/json").
+		Body(body).
+		Do().
+		Error()
+}
+
+func ingressFromResource(resource *networkingv1beta1.Ingress) *portainer.KubernetesIngress {
+	ingress := &portainer.KubernetesIngress{
+		Name:         resource.Name,
+		Namespace:    resource.Namespace,
+		IngressClass: resource.Annotations[ingressClassAnnotation],
+		Annotations:  resource.Annotations,
+		Rules:        make([]portainer.KubernetesIngressRule, 0),
+		TLS:          make([]portainer.KubernetesIngressTLS, 0),
+		CreationDate: resource.CreationTimestamp.Unix(),
+	}
+
+	if ingress.Annotations == nil {
+		ingress.Annotations = make(map[string]string)
+	}
+
+	for _, rule := range resource.Spec.Rules {
+		if rule.HTTP == nil {
+			continue
+		}
+
+		for _, path := range rule.HTTP.Paths {
+			ingress.Rules = append(ingress.Rules, portainer.KubernetesIngressRule{
+				Host:        rule.Host,
+				Path:        path.Path,
+				ServiceName: path.Backend.ServiceName,
+				ServicePort: path.Backend.ServicePort.IntValue(),
+			})
+		}
+	}
+
+	for _, tls := range resource.Spec.TLS {
+		ingress.TLS = append(ingress.TLS, portainer.KubernetesIngressTLS{
+			Hosts:      tls.Hosts,
+			SecretName: tls.SecretName,
+		})
+	}
+
+	return ingress
+}
+
+// applyIngressToResource replaces the class, annotations, rules and TLS configuration of the resource.
+// Paths sharing the same host are grouped under a single rule.
+func applyIngressToResource(ingress *portainer.KubernetesIngress, resource *networkingv1beta1.Ingress) {
+	annotations := make(map[string]string)
+	for key, value := range ingress.Annotations {
+		annotations[key] = value
+	}
+	if ingress.IngressClass != "" {
+		annotations[ingressClassAnnotation] = ingress.IngressClass
+	} else {
+		delete(annotations, ingressClassAnnotation)
+	}
+	resource.Annotations = annotations
+
+	rules := make([]networkingv1beta1.IngressRule, 0)
+	ruleIndexes := make(map[string]int)
+	for _, rule := range ingress.Rules {
+		idx, ok := ruleIndexes[rule.Host]
+		if !ok {
+			rules = append(rules, networkingv1beta1.IngressRule{
+				Host: rule.Host,
+				IngressRuleValue: networkingv1beta1.IngressRuleValue{
+					HTTP: &networkingv1beta1.HTTPIngressRuleValue{},
+				},
+			})
+			idx = len(rules) - 1
+			ruleIndexes[rule.Host] = idx
+		}
+
+		rules[idx].HTTP.Paths = append(rules[idx].HTTP.Paths, networkingv1beta1.HTTPIngressPath{
+			Path: rule.Path,
+			Backend: networkingv1beta1.IngressBackend{
+				ServiceName: rule.ServiceName,
+				ServicePort: intstr.FromInt(rule.ServicePort),
+			},
+		})
+	}
+	resource.Spec.Rules = rules
+
+	tls := make([]networkingv1beta1.IngressTLS, 0)
+	for _, entry := range ingress.TLS {
+		tls = append(tls, networkingv1beta1.IngressTLS{
+			Hosts:      entry.Hosts,
+			SecretName: entry.SecretName,
+		})
+	}
+	resource.Spec.TLS = tls
+}
@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+
+	portainer "github.com/portainer/portainer/api"
+	"k8s.io/client-go/kubernetes"
+	// registers the OIDC authentication provider so that expired tokens are refreshed
+	_ "k8s.io/client-go/plugin/pkg/client/auth/oidc"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const oidcAuthProvider = "oidc"
+
+// KubeconfigDetails represents the information extracted from a kubeconfig for a specific context
+type KubeconfigDetails struct {
+	Context              string
+	Server               string
+	AuthenticationMethod portainer.KubernetesAuthenticationMethod
+}
+
+// ParseKubeconfig validates a kubeconfig and returns the details of the specified context.
+// The current context of the kubeconfig is used when context is empty.
+func ParseKubeconfig(data []byte, context string) (*KubeconfigDetails, error) {
+	config, err := clientcmd.Load(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if context == "" {
+		context = config.CurrentContext
+	}
+
+	kubeContext, ok := config.Contexts[context]
+	if !ok {
+		return nil, fmt.Errorf("unable to find context %q inside the kubeconfig", context)
+	}
+
+	cluster, ok := config.Clusters[kubeContext.Cluster]
+	if !ok || cluster.Server == "" {
+		return nil, fmt.Errorf("unable to find the cluster of context %q inside the kubeconfig", context)
+	}
+
+	authInfo, ok := config.AuthInfos[kubeContext.AuthInfo]
+	if !ok {
+		return nil, fmt.Errorf("unable to find the user of context %q inside the kubeconfig", context)
+	}
+
+	details := &KubeconfigDetails{
+		Context: context,
+		Server:  cluster.Server,
+	}
+
+	switch {
+	case authInfo.Exec != nil:
+		details.AuthenticationMethod = portainer.KubernetesAuthenticationExec
+	case authInfo.AuthProvider != nil:
+		if authInfo.AuthProvider.Name != oidcAuthProvider {
+			return nil, fmt.Errorf("authentication provider %q is not supported, use a credential plugin (exec) instead", authInfo.AuthProvider.Name)
+		}
+		details.AuthenticationMethod = portainer.KubernetesAuthenticationOIDC
+	case authInfo.Token != "" || authInfo.TokenFile != "":
+		details.AuthenticationMethod = portainer.KubernetesAuthenticationToken
+	case len(authInfo.ClientCertificateData) > 0 || authInfo.ClientCertificate != "":
+		details.AuthenticationMethod = portainer.KubernetesAuthenticationCertificate
+	default:
+		return nil, errors.New("unsupported authentication method, the user must use a token, a client certificate, a credential plugin or the OIDC authentication provider")
+	}
+
+	return details, nil
+}
+
+// KubeconfigRESTConfig returns the client configuration of an endpoint using the credentials of a kubeconfig.
+// The kubeconfig is loaded from disk so that the credentials refreshed by the authentication providers are
+// written back to the stored kubeconfig.
+func KubeconfigRESTConfig(endpoint *portainer.Endpoint) (*rest.Config, error) {
+	if endpoint.KubernetesCredentials == nil {
+		return nil, errors.New("missing Kubernetes credentials")
+	}
+
+	loadingRules := &clientcmd.ClientConfigLoadingRules{ExplicitPath: endpoint.KubernetesCredentials.KubeconfigPath}
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: endpoint.KubernetesCredentials.Context}
+
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides).ClientConfig()
+}
+
+func buildKubeconfigClient(endpoint *portainer.Endpoint) (*kubernetes.Clientset, error) {
+	config, err := KubeconfigRESTConfig(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return kubernetes.NewForConfig(config)
+}
@@ -0,0 +1,215 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	v1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	defaultDrainTimeout  = 300
+	drainPollInterval    = 2 * time.Second
+	mirrorPodAnnotation  = "kubernetes.io/config.mirror"
+	daemonSetOwnerKind   = "DaemonSet"
+	drainStatusEvicting  = "evicting"
+	drainStatusEvicted   = "evicted"
+	drainStatusSkipped   = "skipped"
+	drainStatusRetrying  = "retrying"
+	drainStatusCordoned  = "cordoned"
+	drainStatusCompleted = "completed"
+)
+
+// CordonNode marks a node as unschedulable when cordon is true, as schedulable otherwise.
+func (kcl *KubeClient) CordonNode(name string, cordon bool) error {
+	patch, err := json.Marshal(map[string]interface{}{"spec": map[string]interface{}{"unschedulable": cordon}})
+	if err != nil {
+		return err
+	}
+
+	_, err = kcl.cli.CoreV1().Nodes().Patch(name, types.StrategicMergePatchType, patch)
+	return err
+}
+
+// UpdateNode updates the labels and the taints of a node.
+func (kcl *KubeClient) UpdateNode(name string, update portainer.KubernetesNodeUpdate) error {
+	node, err := kcl.cli.CoreV1().Nodes().Get(name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	if node.Labels == nil {
+		node.Labels = make(map[string]string)
+	}
+	for key, value := range update.Labels {
+		node.Labels[key] = value
+	}
+	for _, key := range update.RemoveLabels {
+		delete(node.Labels, key)
+	}
+
+	if update.Taints != nil {
+		taints := make([]v1.Taint, 0)
+		for _, taint := range update.Taints {
+			taints = append(taints, v1.Taint{
+				Key:    taint.Key,
+				Value:  taint.Value,
+				Effect: v1.TaintEffect(taint.Effect),
+			})
+		}
+		node.Spec.Taints = taints
+	}
+
+	_, err = kcl.cli.CoreV1().Nodes().Update(node)
+	return err
+}
+
+// DrainNode cordons a node and evicts its pods, following the behavior of kubectl drain.
+// Pods managed by a DaemonSet and mirror pods are skipped. The progress function is called
+// for each step of the eviction of every pod.
+func (kcl *KubeClient) DrainNode(name string, options portainer.KubernetesDrainOptions, progress func(event portainer.KubernetesDrainEvent)) error {
+	err := kcl.CordonNode(name, true)
+	if err != nil {
+		return err
+	}
+	progress(portainer.KubernetesDrainEvent{Status: drainStatusCordoned})
+
+	podList, err := kcl.cli.CoreV1().Pods(metav1.NamespaceAll).List(metav1.ListOptions{
+		FieldSelector: fields.SelectorFromSet(fields.Set{"spec.nodeName": name}).String(),
+	})
+	if err != nil {
+		return err
+	}
+
+	pods := make([]v1.Pod, 0)
+	for _, pod := range podList.Items {
+		skip, err := skipPodEviction(&pod, options)
+		if err != nil {
+			return err
+		}
+
+		if skip {
+			progress(portainer.KubernetesDrainEvent{Pod: pod.Name, Namespace: pod.Namespace, Status: drainStatusSkipped})
+			continue
+		}
+
+		pods = append(pods, pod)
+	}
+
+	timeout := options.Timeout
+	if timeout == 0 {
+		timeout = defaultDrainTimeout
+	}
+	deadline := time.Now().Add(time.Duration(timeout) * time.Second)
+
+	for _, pod := range pods {
+		progress(portainer.KubernetesDrainEvent{Pod: pod.Name, Namespace: pod.Namespace, Status: drainStatusEvicting})
+
+		err := kcl.evictPod(&pod, options, deadline, progress)
+		if err != nil {
+			return fmt.Errorf("unable to evict pod %s/%s: %s", pod.Namespace, pod.Name, err)
+		}
+	}
+
+	for _, pod := range pods {
+		err := kcl.waitForPodDeletion(&pod, deadline)
+		if err != nil {
+			return fmt.Errorf("unable to evict pod %s/%s: %s", pod.Namespace, pod.Name, err)
+		}
+
+		progress(portainer.KubernetesDrainEvent{Pod: pod.Name, Namespace: pod.Namespace, Status: drainStatusEvicted})
+	}
+
+	progress(portainer.KubernetesDrainEvent{Status: drainStatusCompleted})
+	return nil
+}
+
+// skipPodEviction returns true for the pods that are not evicted during a drain. An error is returned
+// for the pods that would prevent the drain from completing unless forced.
+func skipPodEviction(pod *v1.Pod, options portainer.KubernetesDrainOptions) (bool, error) {
+	if _, ok := pod.Annotations[mirrorPodAnnotation]; ok {
+		return true, nil
+	}
+
+	if pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+		return false, nil
+	}
+
+	controller := metav1.GetControllerOf(pod)
+	if controller != nil && controller.Kind == daemonSetOwnerKind {
+		return true, nil
+	}
+
+	if controller == nil && !options.Force {
+		return false, fmt.Errorf("pod %s/%s is not managed by a controller, use the force option to evict it", pod.Namespace, pod.Name)
+	}
+
+	if !options.DeleteLocalData {
+		for _, volume := range pod.Spec.Volumes {
+			if volume.EmptyDir != nil {
+				return false, fmt.Errorf("pod %s/%s uses local storage, use the delete local data option to evict it", pod.Namespace, pod.Name)
+			}
+		}
+	}
+
+	return false, nil
+}
+
+// evictPod evicts a pod using the eviction API so that pod disruption budgets are honored.
+// Evictions refused because of a disruption budget are retried until the deadline is reached.
+func (kcl *KubeClient) evictPod(pod *v1.Pod, options portainer.KubernetesDrainOptions, deadline time.Time, progress func(event portainer.KubernetesDrainEvent)) error {
+	eviction := &policyv1beta1.Eviction{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pod.Name,
+			Namespace: pod.Namespace,
+		},
+	}
+
+	if options.GracePeriod >= 0 {
+		gracePeriod := options.GracePeriod
+		eviction.DeleteOptions = &metav1.DeleteOptions{GracePeriodSeconds: &gracePeriod}
+	}
+
+	for {
+		err := kcl.cli.CoreV1().Pods(pod.Namespace).Evict(eviction)
+		if err == nil || k8serrors.IsNotFound(err) {
+			return nil
+		}
+
+		if !k8serrors.IsTooManyRequests(err) {
+			return err
+		}
+
+		if time.Now().After(deadline) {
+			return errors.New("timeout reached while waiting for the pod disruption budget to allow the eviction")
+		}
+
+		progress(portainer.KubernetesDrainEvent{Pod: pod.Name, Namespace: pod.Namespace, Status: drainStatusRetrying, Message: err.Error()})
+		time.Sleep(drainPollInterval)
+	}
+}
+
+func (kcl *KubeClient) waitForPodDeletion(pod *v1.Pod, deadline time.Time) error {
+	for {
+		current, err := kcl.cli.CoreV1().Pods(pod.Namespace).Get(pod.Name, metav1.GetOptions{})
+		if k8serrors.IsNotFound(err) || (err == nil && current.UID != pod.UID) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		if time.Now().After(deadline) {
+			return errors.New("timeout reached while waiting for the pod to be deleted")
+		}
+
+		time.Sleep(drainPollInterval)
+	}
+}
@@ -0,0 +1,381 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+
+	portainer "github.com/portainer/portainer/api"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	changeCauseAnnotation          = "kubernetes.io/change-cause"
+	podTemplateHashLabel           = "pod-template-hash"
+	progressDeadlineExceededReason = "ProgressDeadlineExceeded"
+)
+
+var (
+	errRolloutPauseNotSupported = errors.New("pausing a rollout is only supported for deployments")
+	errRevisionNotFound         = errors.New("unable to find the specified revision")
+	errPausedDeploymentRollback = errors.New("unable to rollback a paused deployment, resume it first")
+)
+
+// The rollout status computations follow the logic used by kubectl rollout status.
+
+func deploymentRolloutStatus(deployment *appsv1.Deployment) portainer.KubernetesRolloutStatus {
+	if deployment.Generation > deployment.Status.ObservedGeneration {
+		return portainer.KubernetesRolloutStatus{Message: "Waiting for deployment spec update to be observed"}
+	}
+
+	for _, condition := range deployment.Status.Conditions {
+		if condition.Type == appsv1.DeploymentProgressing && condition.Reason == progressDeadlineExceededReason {
+			return portainer.KubernetesRolloutStatus{Failed: true, Message: fmt.Sprintf("Deployment %q exceeded its progress deadline", deployment.Name)}
+		}
+	}
+
+	replicas := replicasOrDefault(deployment.Spec.Replicas)
+	status := deployment.Status
+
+	switch {
+	case status.UpdatedReplicas < replicas:
+		return portainer.KubernetesRolloutStatus{Message: fmt.Sprintf("Waiting for rollout to finish: %d out of %d new replicas have been updated", status.UpdatedReplicas, replicas)}
+	case status.Replicas > status.UpdatedReplicas:
+		return portainer.KubernetesRolloutStatus{Message: fmt.Sprintf("Waiting for rollout to finish: %d old replicas are pending termination", status.Replicas-status.UpdatedReplicas)}
+	case status.AvailableReplicas < status.UpdatedReplicas:
+		return portainer.KubernetesRolloutStatus{Message: fmt.Sprintf("Waiting for rollout to finish: %d of %d updated replicas are available", status.AvailableReplicas, status.UpdatedReplicas)}
+	}
+
+	return portainer.KubernetesRolloutStatus{Complete: true, Message: "Rollout complete"}
+}
+
+func statefulSetRolloutStatus(statefulSet *appsv1.StatefulSet) portainer.KubernetesRolloutStatus {
+	if statefulSet.Spec.UpdateStrategy.Type != appsv1.RollingUpdateStatefulSetStrategyType {
+		return portainer.KubernetesRolloutStatus{Complete: true, Message: "Rollout status is only available for the RollingUpdate strategy"}
+	}
+
+	if statefulSet.Status.ObservedGeneration == 0 || statefulSet.Generation > statefulSet.Status.ObservedGeneration {
+		return portainer.KubernetesRolloutStatus{Message: "Waiting for statefulset spec update to be observed"}
+	}
+
+	replicas := replicasOrDefault(statefulSet.Spec.Replicas)
+	status := statefulSet.Status
+
+	if status.ReadyReplicas < replicas {
+		return portainer.KubernetesRolloutStatus{Message: fmt.Sprintf("Waiting for %d pods to be ready", replicas-status.ReadyReplicas)}
+	}
+
+	rollingUpdate := statefulSet.Spec.UpdateStrategy.RollingUpdate
+	if rollingUpdate != nil && rollingUpdate.Partition != nil && *rollingUpdate.Partition > 0 {
+		if status.UpdatedReplicas < replicas-*rollingUpdate.Partition {
+			return portainer.KubernetesRolloutStatus{Message: fmt.Sprintf("Waiting for partitioned roll out to finish: %d out of %d new pods have been updated", status.UpdatedReplicas, replicas-*rollingUpdate.Partition)}
+		}
+		return portainer.KubernetesRolloutStatus{Complete: true, Message: "Partitioned roll out complete"}
+	}
+
+	if status.UpdateRevision != status.CurrentRevision {
+		return portainer.KubernetesRolloutStatus{Message: fmt.Sprintf("Waiting for rollout to finish: %d pods at revision %s", status.UpdatedReplicas, status.UpdateRevision)}
+	}
+
+	return portainer.KubernetesRolloutStatus{Complete: true, Message: "Rollout complete"}
+}
+
+func daemonSetRolloutStatus(daemonSet *appsv1.DaemonSet) portainer.KubernetesRolloutStatus {
+	if daemonSet.Spec.UpdateStrategy.Type != appsv1.RollingUpdateDaemonSetStrategyType {
+		return portainer.KubernetesRolloutStatus{Complete: true, Message: "Rollout status is only available for the RollingUpdate strategy"}
+	}
+
+	if daemonSet.Generation > daemonSet.Status.ObservedGeneration {
+		return portainer.KubernetesRolloutStatus{Message: "Waiting for daemon set spec update to be observed"}
+	}
+
+	status := daemonSet.Status
+
+	switch {
+	case status.UpdatedNumberScheduled < status.DesiredNumberScheduled:
+		return portainer.KubernetesRolloutStatus{Message: fmt.Sprintf("Waiting for rollout to finish: %d out of %d new pods have been updated", status.UpdatedNumberScheduled, status.DesiredNumberScheduled)}
+	case status.NumberAvailable < status.DesiredNumberScheduled:
+		return portainer.KubernetesRolloutStatus{Message: fmt.Sprintf("Waiting for rollout to finish: %d of %d updated pods are available", status.NumberAvailable, status.DesiredNumberScheduled)}
+	}
+
+	return portainer.KubernetesRolloutStatus{Complete: true, Message: "Rollout complete"}
+}
+
+// PauseApplicationRollout pauses the rollout of a deployment.
+func (kcl *KubeClient) PauseApplicationRollout(namespace string, kind portainer.KubernetesApplicationKind, name string) error {
+	return kcl.setDeploymentPaused(namespace, kind, name, true)
+}
+
+// ResumeApplicationRollout resumes the rollout of a paused deployment.
+func (kcl *KubeClient) ResumeApplicationRollout(namespace string, kind portainer.KubernetesApplicationKind, name string) error {
+	return kcl.setDeploymentPaused(namespace, kind, name, false)
+}
+
+func (kcl *KubeClient) setDeploymentPaused(namespace string, kind portainer.KubernetesApplicationKind, name string, paused bool) error {
+	if kind != portainer.KubernetesApplicationDeployment {
+		return errRolloutPauseNotSupported
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{"spec": map[string]interface{}{"paused": paused}})
+	if err != nil {
+		return err
+	}
+
+	_, err = kcl.cli.AppsV1().Deployments(namespace).Patch(name, types.StrategicMergePatchType, patch)
+	return err
+}
+
+// GetApplicationRevisions returns the rollout history of an application, sorted by revision.
+// The history of a deployment is built from its ReplicaSets, the history of StatefulSets and DaemonSets
+// is built from their ControllerRevisions.
+func (kcl *KubeClient) GetApplicationRevisions(namespace string, kind portainer.KubernetesApplicationKind, name string) ([]portainer.KubernetesApplicationRevision, error) {
+	revisions := make([]portainer.KubernetesApplicationRevision, 0)
+
+	switch kind {
+	case portainer.KubernetesApplicationDeployment:
+		deployment, replicaSets, err := kcl.deploymentReplicaSets(namespace, name)
+		if err != nil {
+			return nil, err
+		}
+
+		currentRevision := deployment.Annotations[deploymentRevisionAnnotation]
+		for _, replicaSet := range replicaSets {
+			revision, err := strconv.ParseInt(replicaSet.Annotations[deploymentRevisionAnnotation], 10, 64)
+			if err != nil {
+				continue
+			}
+
+			revisions = append(revisions, portainer.KubernetesApplicationRevision{
+				Revision:     revision,
+				Images:       templateImages(&replicaSet.Spec.Template),
+				ChangeCause:  replicaSet.Annotations[changeCauseAnnotation],
+				CreationDate: replicaSet.CreationTimestamp.Unix(),
+				Current:      replicaSet.Annotations[deploymentRevisionAnnotation] == currentRevision,
+			})
+		}
+	case portainer.KubernetesApplicationStatefulSet, portainer.KubernetesApplicationDaemonSet:
+		currentRevisionName, controllerRevisions, err := kcl.controllerRevisions(namespace, kind, name)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, controllerRevision := range controllerRevisions {
+			revisions = append(revisions, portainer.KubernetesApplicationRevision{
+				Revision:     controllerRevision.Revision,
+				Images:       controllerRevisionImages(&controllerRevision),
+				ChangeCause:  controllerRevision.Annotations[changeCauseAnnotation],
+				CreationDate: controllerRevision.CreationTimestamp.Unix(),
+				Current:      controllerRevision.Name == currentRevisionName,
+			})
+		}
+	default:
+		return nil, errUnsupportedApplicationKind
+	}
+
+	sort.Slice(revisions, func(i, j int) bool {
+		return revisions[i].Revision < revisions[j].Revision
+	})
+
+	return revisions, nil
+}
+
+// UndoApplicationRollout rolls an application back to the specified revision.
+// The application is rolled back to the previous revision when revision is 0.
+func (kcl *KubeClient) UndoApplicationRollout(namespace string, kind portainer.KubernetesApplicationKind, name string, revision int64) error {
+	switch kind {
+	case portainer.KubernetesApplicationDeployment:
+		return kcl.undoDeploymentRollout(namespace, name, revision)
+	case portainer.KubernetesApplicationStatefulSet, portainer.KubernetesApplicationDaemonSet:
+		return kcl.undoControllerRollout(namespace, kind, name, revision)
+	}
+
+	return errUnsupportedApplicationKind
+}
+
+func (kcl *KubeClient) undoDeploymentRollout(namespace, name string, revision int64) error {
+	deployment, replicaSets, err := kcl.deploymentReplicaSets(namespace, name)
+	if err != nil {
+		return err
+	}
+
+	if deployment.Spec.Paused {
+		return errPausedDeploymentRollback
+	}
+
+	revisions := make(map[int64]*appsv1.ReplicaSet)
+	revisionNumbers := make([]int64, 0)
+	for idx := range replicaSets {
+		replicaSetRevision, err := strconv.ParseInt(replicaSets[idx].Annotations[deploymentRevisionAnnotation], 10, 64)
+		if err != nil {
+			continue
+		}
+		revisions[replicaSetRevision] = &replicaSets[idx]
+		revisionNumbers = append(revisionNumbers, replicaSetRevision)
+	}
+
+	currentRevision, _ := strconv.ParseInt(deployment.Annotations[deploymentRevisionAnnotation], 10, 64)
+	targetRevision, err := rollbackRevision(revisionNumbers, currentRevision, revision)
+	if err != nil {
+		return err
+	}
+
+	template := revisions[targetRevision].Spec.Template.DeepCopy()
+	delete(template.Labels, podTemplateHashLabel)
+
+	deployment.Spec.Template = *template
+	_, err = kcl.cli.AppsV1().Deployments(namespace).Update(deployment)
+	return err
+}
+
+func (kcl *KubeClient) undoControllerRollout(namespace string, kind portainer.KubernetesApplicationKind, name string, revision int64) error {
+	currentRevisionName, controllerRevisions, err := kcl.controllerRevisions(namespace, kind, name)
+	if err != nil {
+		return err
+	}
+
+	revisions := make(map[int64]*appsv1.ControllerRevision)
+	revisionNumbers := make([]int64, 0)
+	var currentRevision int64
+	for idx := range controllerRevisions {
+		revisions[controllerRevisions[idx].Revision] = &controllerRevisions[idx]
+		revisionNumbers = append(revisionNumbers, controllerRevisions[idx].Revision)
+		if controllerRevisions[idx].Name == currentRevisionName {
+			currentRevision = controllerRevisions[idx].Revision
+		}
+	}
+
+	targetRevision, err := rollbackRevision(revisionNumbers, currentRevision, revision)
+	if err != nil {
+		return err
+	}
+
+	// the data of a controller revision is a strategic merge patch restoring the pod template
+	patch := revisions[targetRevision].Data.Raw
+
+	if kind == portainer.KubernetesApplicationStatefulSet {
+		_, err = kcl.cli.AppsV1().StatefulSets(namespace).Patch(name, types.StrategicMergePatchType, patch)
+		return err
+	}
+
+	_, err = kcl.cli.AppsV1().DaemonSets(namespace).Patch(name, types.StrategicMergePatchType, patch)
+	return err
+}
+
+func (kcl *KubeClient) deploymentReplicaSets(namespace, name string) (*appsv1.Deployment, []appsv1.ReplicaSet, error) {
+	deployment, err := kcl.cli.AppsV1().Deployments(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	listOptions, err := selectorListOptions(deployment.Spec.Selector)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	replicaSetList, err := kcl.cli.AppsV1().ReplicaSets(namespace).List(listOptions)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	replicaSets := make([]appsv1.ReplicaSet, 0)
+	for _, replicaSet := range replicaSetList.Items {
+		if metav1.IsControlledBy(&replicaSet, deployment) {
+			replicaSets = append(replicaSets, replicaSet)
+		}
+	}
+
+	return deployment, replicaSets, nil
+}
+
+// controllerRevisions returns the name of the current revision of a StatefulSet or DaemonSet
+// along with the controller revisions it owns.
+func (kcl *KubeClient) controllerRevisions(namespace string, kind portainer.KubernetesApplicationKind, name string) (string, []appsv1.ControllerRevision, error) {
+	var owner metav1.Object
+	var selector *metav1.LabelSelector
+	currentRevisionName := ""
+
+	if kind == portainer.KubernetesApplicationStatefulSet {
+		statefulSet, err := kcl.cli.AppsV1().StatefulSets(namespace).Get(name, metav1.GetOptions{})
+		if err != nil {
+			return "", nil, err
+		}
+		owner, selector, currentRevisionName = statefulSet, statefulSet.Spec.Selector, statefulSet.Status.UpdateRevision
+	} else {
+		daemonSet, err := kcl.cli.AppsV1().DaemonSets(namespace).Get(name, metav1.GetOptions{})
+		if err != nil {
+			return "", nil, err
+		}
+		owner, selector = daemonSet, daemonSet.Spec.Selector
+	}
+
+	listOptions, err := selectorListOptions(selector)
+	if err != nil {
+		return "", nil, err
+	}
+
+	revisionList, err := kcl.cli.AppsV1().ControllerRevisions(namespace).List(listOptions)
+	if err != nil {
+		return "", nil, err
+	}
+
+	controllerRevisions := make([]appsv1.ControllerRevision, 0)
+	var latestRevision int64
+	for _, controllerRevision := range revisionList.Items {
+		if !metav1.IsControlledBy(&controllerRevision, owner) {
+			continue
+		}
+
+		controllerRevisions = append(controllerRevisions, controllerRevision)
+
+		// DaemonSets do not expose their current revision, the latest revision is the current one
+		if kind == portainer.KubernetesApplicationDaemonSet && controllerRevision.Revision > latestRevision {
+			latestRevision = controllerRevision.Revision
+			currentRevisionName = controllerRevision.Name
+		}
+	}
+
+	return currentRevisionName, controllerRevisions, nil
+}
+
+func controllerRevisionImages(controllerRevision *appsv1.ControllerRevision) []string {
+	var data struct {
+		Spec struct {
+			Template v1.PodTemplateSpec `json:"template"`
+		} `json:"spec"`
+	}
+
+	err := json.Unmarshal(controllerRevision.Data.Raw, &data)
+	if err != nil {
+		return []string{}
+	}
+
+	return templateImages(&data.Spec.Template)
+}
+
+// rollbackRevision returns the revision to roll back to. When requested is 0, the latest
+// revision preceding the current one is returned.
+func rollbackRevision(revisions []int64, current, requested int64) (int64, error) {
+	sort.Slice(revisions, func(i, j int) bool { return revisions[i] < revisions[j] })
+
+	if requested != 0 {
+		for _, revision := range revisions {
+			if revision == requested {
+				return revision, nil
+			}
+		}
+		return 0, errRevisionNotFound
+	}
+
+	for idx := len(revisions) - 1; idx >= 0; idx-- {
+		if revisions[idx] < current {
+			return revisions[idx], nil
+		}
+	}
+
+	return 0, errors.New("no previous revision to roll back to")
+}
@@ -0,0 +1,231 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+
+	portainer "github.com/portainer/portainer/api"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type (
+	kubeletStatsSummary struct {
+		Pods []struct {
+			Volumes []kubeletVolumeStats `json:"volume"`
+		} `json:"pods"`
+	}
+
+	kubeletVolumeStats struct {
+		UsedBytes      *uint64 `json:"usedBytes"`
+		CapacityBytes  *uint64 `json:"capacityBytes"`
+		AvailableBytes *uint64 `json:"availableBytes"`
+		PVCRef         *struct {
+			Name      string `json:"name"`
+			Namespace string `json:"namespace"`
+		} `json:"pvcRef"`
+	}
+)
+
+var (
+	errVolumeExpansionNotAllowed = errors.New("the storage class of the persistent volume claim does not allow volume expansion")
+	errVolumeShrinkNotSupported  = errors.New("the new size must be greater than the current size of the persistent volume claim")
+)
+
+// GetPersistentVolumeClaims returns the persistent volume claims of a namespace. Claims of all namespaces
+// are returned when namespace is empty. The usage of the claims is retrieved from the kubelet statistics
+// of each node when available.
+func (kcl *KubeClient) GetPersistentVolumeClaims(namespace string) ([]portainer.KubernetesPersistentVolumeClaim, error) {
+	claimList, err := kcl.cli.CoreV1().PersistentVolumeClaims(namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	expandableClasses, err := kcl.expandableStorageClasses()
+	if err != nil {
+		return nil, err
+	}
+
+	usages := kcl.volumeUsages()
+
+	claims := make([]portainer.KubernetesPersistentVolumeClaim, 0)
+	for _, claim := range claimList.Items {
+		storageClass := ""
+		if claim.Spec.StorageClassName != nil {
+			storageClass = *claim.Spec.StorageClassName
+		}
+
+		accessModes := make([]string, 0)
+		for _, accessMode := range claim.Spec.AccessModes {
+			accessModes = append(accessModes, string(accessMode))
+		}
+
+		requestedSize := claim.Spec.Resources.Requests[v1.ResourceStorage]
+		capacity := claim.Status.Capacity[v1.ResourceStorage]
+
+		claims = append(claims, portainer.KubernetesPersistentVolumeClaim{
+			Name:          claim.Name,
+			Namespace:     claim.Namespace,
+			StorageClass:  storageClass,
+			Phase:         string(claim.Status.Phase),
+			VolumeName:    claim.Spec.VolumeName,
+			AccessModes:   accessModes,
+			RequestedSize: requestedSize.Value(),
+			Capacity:      capacity.Value(),
+			Expandable:    expandableClasses[storageClass],
+			Usage:         usages[claim.Namespace+"/"+claim.Name],
+			CreationDate:  claim.CreationTimestamp.Unix(),
+		})
+	}
+
+	return claims, nil
+}
+
+// ResizePersistentVolumeClaim updates the storage request of a persistent volume claim.
+// The storage class of the claim must allow volume expansion and volumes cannot be shrunk.
+func (kcl *KubeClient) ResizePersistentVolumeClaim(namespace, name, size string) error {
+	quantity, err := resource.ParseQuantity(size)
+	if err != nil {
+		return err
+	}
+
+	claim, err := kcl.cli.CoreV1().PersistentVolumeClaims(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	if claim.Spec.StorageClassName == nil {
+		return errVolumeExpansionNotAllowed
+	}
+
+	expandableClasses, err := kcl.expandableStorageClasses()
+	if err != nil {
+		return err
+	}
+
+	if !expandableClasses[*claim.Spec.StorageClassName] {
+		return errVolumeExpansionNotAllowed
+	}
+
+	currentSize := claim.Spec.Resources.Requests[v1.ResourceStorage]
+	if quantity.Cmp(currentSize) <= 0 {
+		return errVolumeShrinkNotSupported
+	}
+
+	claim.Spec.Resources.Requests[v1.ResourceStorage] = quantity
+
+	_, err = kcl.cli.CoreV1().PersistentVolumeClaims(namespace).Update(claim)
+	return err
+}
+
+// GetOrphanedPersistentVolumes returns the persistent volumes that are not bound to any existing claim.
+func (kcl *KubeClient) GetOrphanedPersistentVolumes() ([]portainer.KubernetesPersistentVolume, error) {
+	volumeList, err := kcl.cli.CoreV1().PersistentVolumes().List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	claimList, err := kcl.cli.CoreV1().PersistentVolumeClaims(metav1.NamespaceAll).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	claims := make(map[string]bool)
+	for _, claim := range claimList.Items {
+		claims[string(claim.UID)] = true
+	}
+
+	volumes := make([]portainer.KubernetesPersistentVolume, 0)
+	for _, volume := range volumeList.Items {
+		if volume.Status.Phase == v1.VolumeBound && volume.Spec.ClaimRef != nil && claims[string(volume.Spec.ClaimRef.UID)] {
+			continue
+		}
+
+		if volume.Status.Phase == v1.VolumePending {
+			continue
+		}
+
+		capacity := volume.Spec.Capacity[v1.ResourceStorage]
+		orphanedVolume := portainer.KubernetesPersistentVolume{
+			Name:          volume.Name,
+			StorageClass:  volume.Spec.StorageClassName,
+			Capacity:      capacity.Value(),
+			Phase:         string(volume.Status.Phase),
+			ReclaimPolicy: string(volume.Spec.PersistentVolumeReclaimPolicy),
+			CreationDate:  volume.CreationTimestamp.Unix(),
+		}
+
+		if volume.Spec.ClaimRef != nil {
+			orphanedVolume.ClaimNamespace = volume.Spec.ClaimRef.Namespace
+			orphanedVolume.ClaimName = volume.Spec.ClaimRef.Name
+		}
+
+		volumes = append(volumes, orphanedVolume)
+	}
+
+	return volumes, nil
+}
+
+func (kcl *KubeClient) expandableStorageClasses() (map[string]bool, error) {
+	storageClassList, err := kcl.cli.StorageV1().StorageClasses().List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	expandableClasses := make(map[string]bool)
+	for _, storageClass := range storageClassList.Items {
+		expandableClasses[storageClass.Name] = storageClass.AllowVolumeExpansion != nil && *storageClass.AllowVolumeExpansion
+	}
+
+	return expandableClasses, nil
+}
+
+// volumeUsages returns the usage of the persistent volume claims mounted by running pods, indexed by
+// namespace/name. Nodes whose statistics cannot be retrieved are ignored.
+func (kcl *KubeClient) volumeUsages() map[string]*portainer.KubernetesVolumeUsage {
+	usages := make(map[string]*portainer.KubernetesVolumeUsage)
+
+	nodeList, err := kcl.cli.CoreV1().Nodes().List(metav1.ListOptions{})
+	if err != nil {
+		return usages
+	}
+
+	for _, node := range nodeList.Items {
+		data, err := kcl.cli.CoreV1().RESTClient().Get().
+			Resource("nodes").
+			Name(node.Name).
+			SubResource("proxy").
+			Suffix("stats/summary").
+			DoRaw()
+		if err != nil {
+			continue
+		}
+
+		var summary kubeletStatsSummary
+		err = json.Unmarshal(data, &summary)
+		if err != nil {
+			continue
+		}
+
+		for _, pod := range summary.Pods {
+			for _, volume := range pod.Volumes {
+				if volume.PVCRef == nil || volume.UsedBytes == nil || volume.CapacityBytes == nil {
+					continue
+				}
+
+				usage := &portainer.KubernetesVolumeUsage{
+					UsedBytes:     int64(*volume.UsedBytes),
+					CapacityBytes: int64(*volume.CapacityBytes),
+				}
+				if volume.AvailableBytes != nil {
+					usage.AvailableBytes = int64(*volume.AvailableBytes)
+				}
+
+				usages[volume.PVCRef.Namespace+"/"+volume.PVCRef.Name] = usage
+			}
+		}
+	}
+
+	return usages
+}
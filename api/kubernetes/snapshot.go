@@ -7,6 +7,7 @@ import (
 	"github.com/portainer/portainer/api/kubernetes/cli"
 
 	portainer "github.com/portainer/portainer/api"
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 )
@@ -71,13 +72,40 @@ func snapshotNodes(snapshot *portainer.KubernetesSnapshot, cli *kubernetes.Clien
 	}
 
 	var totalCPUs, totalMemory int64
+	nodes := make([]portainer.KubernetesNodeStatus, 0)
 	for _, node := range nodeList.Items {
 		totalCPUs += node.Status.Capacity.Cpu().Value()
 		totalMemory += node.Status.Capacity.Memory().Value()
+		nodes = append(nodes, nodeStatus(&node))
 	}
 
 	snapshot.TotalCPU = totalCPUs
 	snapshot.TotalMemory = totalMemory
 	snapshot.NodeCount = len(nodeList.Items)
+	snapshot.Nodes = nodes
 	return nil
 }
+
+func nodeStatus(node *v1.Node) portainer.KubernetesNodeStatus {
+	status := portainer.KubernetesNodeStatus{
+		Name:          node.Name,
+		Unschedulable: node.Spec.Unschedulable,
+	}
+
+	for _, condition := range node.Status.Conditions {
+		active := condition.Status == v1.ConditionTrue
+
+		switch condition.Type {
+		case v1.NodeReady:
+			status.Ready = active
+		case v1.NodeMemoryPressure:
+			status.MemoryPressure = active
+		case v1.NodeDiskPressure:
+			status.DiskPressure = active
+		case v1.NodePIDPressure:
+			status.PIDPressure = active
+		}
+	}
+
+	return status
+}
@@ -185,3 +185,56 @@ func (*Service) TestConnectivity(settings *portainer.LDAPSettings) error {
 	}
 	return nil
 }
+
+// SearchUsers is used to retrieve the usernames of the LDAP/AD users matching the specified query.
+// The query is matched as a substring of the username attribute, at most limit usernames are returned.
+func (*Service) SearchUsers(query string, limit int, settings *portainer.LDAPSettings) ([]string, error) {
+	connection, err := createConnection(settings)
+	if err != nil {
+		return nil, err
+	}
+	defer connection.Close()
+
+	if !settings.AnonymousMode {
+		err = connection.Bind(settings.ReaderDN, settings.Password)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	usernames := make([]string, 0)
+	found := make(map[string]bool)
+	queryEscaped := ldap.EscapeFilter(query)
+
+	for _, searchSettings := range settings.SearchSettings {
+		searchRequest := ldap.NewSearchRequest(
+			searchSettings.BaseDN,
+			ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, limit, 0, false,
+			fmt.Sprintf("(&%s(%s=*%s*))", searchSettings.Filter, searchSettings.UserNameAttribute, queryEscaped),
+			[]string{searchSettings.UserNameAttribute},
+			nil,
+		)
+
+		// Deliberately skip errors on the search request so that we can jump to other search settings
+		// if any issue arise with the current one. A size limit exceeded error still returns the entries.
+		sr, err := connection.Search(searchRequest)
+		if err != nil && (sr == nil || !ldap.IsErrorWithCode(err, ldap.LDAPResultSizeLimitExceeded)) {
+			continue
+		}
+
+		for _, entry := range sr.Entries {
+			username := entry.GetAttributeValue(searchSettings.UserNameAttribute)
+			if username == "" || found[username] {
+				continue
+			}
+
+			found[username] = true
+			usernames = append(usernames, username)
+			if len(usernames) == limit {
+				return usernames, nil
+			}
+		}
+	}
+
+	return usernames, nil
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/portainer/libcompose/project"
 	"github.com/portainer/libcompose/project/options"
 	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/internal/stackfile"
 )
 
 const (
@@ -66,8 +67,13 @@ func (manager *ComposeStackManager) Up(stack *portainer.Stack, endpoint *portain
 		return err
 	}
 
+	stackEnv, err := stackfile.Environment(stack)
+	if err != nil {
+		return err
+	}
+
 	env := make(map[string]string)
-	for _, envvar := range stack.Env {
+	for _, envvar := range stackEnv {
 		env[envvar.Name] = envvar.Value
 	}
 
@@ -0,0 +1,147 @@
+package mail
+
+import (
+	"bytes"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/portainer/portainer/api"
+)
+
+const dialTimeout = 10 * time.Second
+
+var (
+	errSMTPNotConfigured = errors.New("SMTP server is not configured")
+	errNoRecipient       = errors.New("No recipient specified")
+)
+
+// Service represents a service used to send emails through a SMTP server.
+type Service struct{}
+
+// NewService returns a new instance of a mail service
+func NewService() *Service {
+	return &Service{}
+}
+
+// Send sends a plain text email to the specified recipients
+func (service *Service) Send(settings *portainer.SMTPSettings, to []string, subject, body string) error {
+	if settings.Host == "" || settings.From == "" {
+		return errSMTPNotConfigured
+	}
+
+	if len(to) == 0 {
+		return errNoRecipient
+	}
+
+	client, err := dial(settings)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if settings.Username != "" {
+		err = client.Auth(smtp.PlainAuth("", settings.Username, settings.Password, settings.Host))
+		if err != nil {
+			return err
+		}
+	}
+
+	err = client.Mail(settings.From)
+	if err != nil {
+		return err
+	}
+
+	for _, recipient := range to {
+		err = client.Rcpt(recipient)
+		if err != nil {
+			return err
+		}
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+
+	_, err = writer.Write(buildMessage(settings.From, to, subject, body))
+	if err != nil {
+		return err
+	}
+
+	err = writer.Close()
+	if err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
+
+func dial(settings *portainer.SMTPSettings) (*smtp.Client, error) {
+	port := settings.Port
+	if port == 0 {
+		port = 25
+		if settings.TLS {
+			port = 465
+		}
+	}
+
+	address := net.JoinHostPort(settings.Host, strconv.Itoa(port))
+	tlsConfig := &tls.Config{
+		ServerName:         settings.Host,
+		InsecureSkipVerify: settings.SkipVerify,
+	}
+
+	if settings.TLS {
+		conn, err := tls.DialWithDialer(&net.Dialer{Timeout: dialTimeout}, "tcp", address, tlsConfig)
+		if err != nil {
+			return nil, err
+		}
+		return smtp.NewClient(conn, settings.Host)
+	}
+
+	conn, err := net.DialTimeout("tcp", address, dialTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := smtp.NewClient(conn, settings.Host)
+	if err != nil {
+		return nil, err
+	}
+
+	if settings.StartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			client.Close()
+			return nil, errors.New("SMTP server does not support STARTTLS")
+		}
+
+		err = client.StartTLS(tlsConfig)
+		if err != nil {
+			client.Close()
+			return nil, err
+		}
+	}
+
+	return client, nil
+}
+
+func buildMessage(from string, to []string, subject, body string) []byte {
+	var message bytes.Buffer
+
+	fmt.Fprintf(&message, "From: %s\r\n", from)
+	fmt.Fprintf(&message, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&message, "Subject: %s\r\n", subject)
+	fmt.Fprintf(&message, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	message.WriteString("MIME-Version: 1.0\r\n")
+	message.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	message.WriteString("\r\n")
+	message.WriteString(strings.Replace(body, "\n", "\r\n", -1))
+
+	return message.Bytes()
+}
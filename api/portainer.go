@@ -14,6 +14,50 @@ type (
 	// AgentPlatform represents a platform type for an Agent
 	AgentPlatform int
 
+	// AlertingSilence represents a time window during which no container alert is raised.
+	// An empty container name matches all the containers of the endpoint, an endpoint identifier
+	// set to 0 matches all the endpoints.
+	AlertingSilence struct {
+		EndpointID    EndpointID `json:"EndpointId"`
+		ContainerName string     `json:"ContainerName"`
+		Until         int64      `json:"Until"`
+		Comment       string     `json:"Comment"`
+	}
+
+	// Application represents a composite application: several stacks deployed in order on the same endpoint,
+	// sharing a set of variables and removed together
+	Application struct {
+		ID         ApplicationID `json:"Id"`
+		Name       string        `json:"Name"`
+		EndpointID EndpointID    `json:"EndpointId"`
+		// SwarmID is the identifier of the cluster the Swarm components are deployed on
+		SwarmID string `json:"SwarmId"`
+		Env     []Pair `json:"Env"`
+		// Components are deployed in order and removed in the reverse order
+		Components   []ApplicationComponent `json:"Components"`
+		TaskID       TaskID                 `json:"TaskId"`
+		CreatedBy    UserID                 `json:"CreatedBy"`
+		CreationDate int64                  `json:"CreationDate"`
+	}
+
+	// ApplicationComponent represents a stack of a composite application
+	ApplicationComponent struct {
+		Name string    `json:"Name"`
+		Type StackType `json:"Type"`
+		// Env overrides the variables of the application for this component
+		Env    []Pair                     `json:"Env"`
+		Status ApplicationComponentStatus `json:"Status"`
+		Error  string                     `json:"Error"`
+		// StackID is the identifier of the stack created by the deployment of the component
+		StackID StackID `json:"StackId"`
+	}
+
+	// ApplicationComponentStatus represents the deployment status of a component of a composite application
+	ApplicationComponentStatus int
+
+	// ApplicationID represents a composite application identifier
+	ApplicationID int
+
 	// AuthenticationMethod represents the authentication method used to authenticate a user
 	AuthenticationMethod int
 
@@ -23,6 +67,42 @@ type (
 	// Authorizations represents a set of authorizations associated to a role
 	Authorizations map[Authorization]bool
 
+	// AutoHealAction represents a restart of an unhealthy container executed by an auto-heal rule
+	AutoHealAction struct {
+		ID            AutoHealActionID `json:"Id"`
+		RuleID        AutoHealRuleID   `json:"RuleId"`
+		EndpointID    EndpointID       `json:"EndpointId"`
+		ContainerID   string           `json:"ContainerId"`
+		ContainerName string           `json:"ContainerName"`
+		FailingStreak int              `json:"FailingStreak"`
+		Time          int64            `json:"Time"`
+		Success       bool             `json:"Success"`
+		Error         string           `json:"Error,omitempty"`
+	}
+
+	// AutoHealActionID represents an auto-heal action identifier
+	AutoHealActionID int
+
+	// AutoHealRule represents a rule restarting the containers of an endpoint stuck in the unhealthy state.
+	// The rule applies to all the containers of the endpoint or only to the containers of a stack.
+	AutoHealRule struct {
+		ID         AutoHealRuleID `json:"Id"`
+		Name       string         `json:"Name"`
+		EndpointID EndpointID     `json:"EndpointId"`
+		StackName  string         `json:"StackName"`
+		// FailingStreak is the number of consecutive failed health checks after which a container is restarted
+		FailingStreak int `json:"FailingStreak"`
+		// Cooldown is the minimum number of seconds between two restarts of the same container
+		Cooldown int `json:"Cooldown"`
+		// ExcludeLabels are labels excluding the containers from the rule, written as key or key=value
+		ExcludeLabels []string `json:"ExcludeLabels"`
+		Notify        bool     `json:"Notify"`
+		Enabled       bool     `json:"Enabled"`
+	}
+
+	// AutoHealRuleID represents an auto-heal rule identifier
+	AutoHealRuleID int
+
 	// AzureCredentials represents the credentials used to connect to an Azure
 	// environment.
 	AzureCredentials struct {
@@ -36,13 +116,29 @@ type (
 		Addr                      *string
 		TunnelAddr                *string
 		TunnelPort                *string
+		TunnelPortRange           *string
 		AdminPassword             *string
 		AdminPasswordFile         *string
+		AdminPasswordOneTime      *bool
+		GenerateAdminPassword     *bool
+		AdminInitTimeout          *string
+		InitLockdown              *bool
+		GenerateInitToken         *bool
+		ImportDockerContexts      *string
+		MaxRequestSize            *int
+		MaxStackFileSize          *int
+		MaxTLSFileSize            *int
+		APIRateLimit              *float64
+		APIRateLimitBurst         *int
 		Assets                    *string
 		Data                      *string
+		EnableDebugEndpoints      *bool
 		EnableEdgeComputeFeatures *bool
 		EndpointURL               *string
 		Labels                    *[]Pair
+		HiddenContainerNames      *[]string
+		HiddenComposeProjects     *[]string
+		FeatureFlags              *[]string
 		Logo                      *string
 		NoAnalytics               *bool
 		Templates                 *string
@@ -64,6 +160,78 @@ type (
 		OauthUserKey              *string
 	}
 
+	// ChangeRequest represents a change of a protected endpoint held until it is approved by a second user.
+	// The request which made the change is recorded and executed on behalf of its author once approved.
+	ChangeRequest struct {
+		ID            ChangeRequestID     `json:"Id"`
+		EndpointID    EndpointID          `json:"EndpointId"`
+		Type          ChangeRequestType   `json:"Type"`
+		Status        ChangeRequestStatus `json:"Status"`
+		Method        string              `json:"Method"`
+		RequestURI    string              `json:"RequestURI"`
+		ContentType   string              `json:"ContentType,omitempty"`
+		Body          []byte              `json:"Body,omitempty"`
+		RequestedBy   UserID              `json:"RequestedBy"`
+		RequesterName string              `json:"RequesterName"`
+		// RequestedByImpersonator is the administrator who filed the change while impersonating its author
+		RequestedByImpersonator UserID `json:"RequestedByImpersonator,omitempty"`
+		CreationDate            int64  `json:"CreationDate"`
+		ReviewedBy              UserID `json:"ReviewedBy,omitempty"`
+		ReviewerName            string `json:"ReviewerName,omitempty"`
+		ReviewDate              int64  `json:"ReviewDate,omitempty"`
+		ReviewComment           string `json:"ReviewComment,omitempty"`
+		// ExecutionStatusCode and ExecutionError are the outcome of the request executed after the approval
+		ExecutionStatusCode int    `json:"ExecutionStatusCode,omitempty"`
+		ExecutionError      string `json:"ExecutionError,omitempty"`
+	}
+
+	// ChangeRequestID represents a change request identifier
+	ChangeRequestID int
+
+	// ChangeRequestStatus represents the review status of a change request
+	ChangeRequestStatus int
+
+	// ChangeRequestType represents the kind of change held by a change request
+	ChangeRequestType int
+
+	// ContainerAlertingSettings represents the settings used to raise notifications when containers
+	// become unhealthy or are stuck in a restart loop
+	ContainerAlertingSettings struct {
+		Enabled bool `json:"Enabled"`
+		// DeduplicationInterval is the minimum duration between two notifications for the same container and event
+		DeduplicationInterval string            `json:"DeduplicationInterval"`
+		Silences              []AlertingSilence `json:"Silences"`
+	}
+
+	// ContainerLabelPolicy represents the labels injected in and required on the containers created through Portainer
+	ContainerLabelPolicy struct {
+		// Prefix is the prefix of the injected labels, DefaultContainerLabelPrefix is used when empty
+		Prefix string `json:"Prefix"`
+		// InjectOwner injects the name of the user creating the container and the names of its teams
+		InjectOwner bool `json:"InjectOwner"`
+		// InjectStack injects the name of the stack the container is part of
+		InjectStack bool `json:"InjectStack"`
+		// InjectTimestamp injects the time at which the container was created
+		InjectTimestamp bool `json:"InjectTimestamp"`
+		// Labels are static labels injected on every container, existing labels are not overridden
+		Labels []Pair `json:"Labels"`
+		// RequiredLabels are the labels that must be defined when creating a container
+		RequiredLabels []string `json:"RequiredLabels"`
+	}
+
+	// BrandingSettings represents the customization of the UI. The login banner is displayed on the login page
+	// and must be acknowledged before signing in when RequireLoginBannerAcknowledgement is enabled,
+	// the legal banner is displayed on every page.
+	BrandingSettings struct {
+		Title                             string `json:"Title"`
+		PrimaryColor                      string `json:"PrimaryColor"`
+		AccentColor                       string `json:"AccentColor"`
+		LoginMessage                      string `json:"LoginMessage"`
+		LoginBanner                       string `json:"LoginBanner"`
+		RequireLoginBannerAcknowledgement bool   `json:"RequireLoginBannerAcknowledgement"`
+		LegalBanner                       string `json:"LegalBanner"`
+	}
+
 	// CustomTemplate represents a custom template
 	CustomTemplate struct {
 		ID              CustomTemplateID       `json:"Id"`
@@ -77,11 +245,51 @@ type (
 		Logo            string                 `json:"Logo"`
 		Type            StackType              `json:"Type"`
 		ResourceControl *ResourceControl       `json:"ResourceControl"`
+		// Publication is the request to make the template visible to all the users
+		Publication *CustomTemplatePublication `json:"Publication,omitempty"`
+		// OrganizationID is the organization the template belongs to
+		OrganizationID OrganizationID `json:"OrganizationId,omitempty"`
 	}
 
 	// CustomTemplateID represents a custom template identifier
 	CustomTemplateID int
 
+	// CustomTemplatePublication represents the submission of a custom template for global visibility and its review.
+	// An approved template is listed for all the users, with the details of its submission as provenance.
+	CustomTemplatePublication struct {
+		Status         CustomTemplatePublicationStatus `json:"Status"`
+		SubmittedBy    UserID                          `json:"SubmittedBy"`
+		SubmitterName  string                          `json:"SubmitterName"`
+		TeamID         TeamID                          `json:"TeamId,omitempty"`
+		TeamName       string                          `json:"TeamName,omitempty"`
+		SubmissionDate int64                           `json:"SubmissionDate"`
+		Note           string                          `json:"Note"`
+		ReviewedBy     UserID                          `json:"ReviewedBy,omitempty"`
+		ReviewerName   string                          `json:"ReviewerName,omitempty"`
+		ReviewDate     int64                           `json:"ReviewDate,omitempty"`
+		ReviewComment  string                          `json:"ReviewComment,omitempty"`
+	}
+
+	// CustomTemplatePublicationStatus represents the review status of a custom template publication
+	CustomTemplatePublicationStatus int
+
+	// CustomTemplateRevision represents a version of the file of a custom template
+	CustomTemplateRevision struct {
+		ID               CustomTemplateRevisionID `json:"Id"`
+		CustomTemplateID CustomTemplateID         `json:"CustomTemplateId"`
+		// Revision is the number of the version, starting at 1 for each template
+		Revision int    `json:"Revision"`
+		UserID   UserID `json:"UserId"`
+		Username string `json:"Username"`
+		Time     int64  `json:"Time"`
+		// RestoredFrom is the number of the revision restored by this revision
+		RestoredFrom int    `json:"RestoredFrom,omitempty"`
+		FileContent  string `json:"FileContent,omitempty"`
+	}
+
+	// CustomTemplateRevisionID represents a custom template revision identifier
+	CustomTemplateRevisionID int
+
 	// CustomTemplatePlatform represents a custom template platform
 	CustomTemplatePlatform int
 
@@ -95,20 +303,122 @@ type (
 
 	// DockerSnapshot represents a snapshot of a specific Docker endpoint at a specific time
 	DockerSnapshot struct {
-		Time                    int64             `json:"Time"`
-		DockerVersion           string            `json:"DockerVersion"`
-		Swarm                   bool              `json:"Swarm"`
-		TotalCPU                int               `json:"TotalCPU"`
-		TotalMemory             int64             `json:"TotalMemory"`
-		RunningContainerCount   int               `json:"RunningContainerCount"`
-		StoppedContainerCount   int               `json:"StoppedContainerCount"`
-		HealthyContainerCount   int               `json:"HealthyContainerCount"`
-		UnhealthyContainerCount int               `json:"UnhealthyContainerCount"`
-		VolumeCount             int               `json:"VolumeCount"`
-		ImageCount              int               `json:"ImageCount"`
-		ServiceCount            int               `json:"ServiceCount"`
-		StackCount              int               `json:"StackCount"`
-		SnapshotRaw             DockerSnapshotRaw `json:"DockerSnapshotRaw"`
+		Time                    int64               `json:"Time"`
+		DockerVersion           string              `json:"DockerVersion"`
+		Swarm                   bool                `json:"Swarm"`
+		TotalCPU                int                 `json:"TotalCPU"`
+		TotalMemory             int64               `json:"TotalMemory"`
+		RunningContainerCount   int                 `json:"RunningContainerCount"`
+		StoppedContainerCount   int                 `json:"StoppedContainerCount"`
+		HealthyContainerCount   int                 `json:"HealthyContainerCount"`
+		UnhealthyContainerCount int                 `json:"UnhealthyContainerCount"`
+		VolumeCount             int                 `json:"VolumeCount"`
+		ImageCount              int                 `json:"ImageCount"`
+		ServiceCount            int                 `json:"ServiceCount"`
+		StackCount              int                 `json:"StackCount"`
+		NodeCount               int                 `json:"NodeCount"`
+		Nodes                   []DockerNodeStatus  `json:"Nodes"`
+		Host                    *DockerHostSnapshot `json:"Host,omitempty"`
+		SnapshotRaw             DockerSnapshotRaw   `json:"DockerSnapshotRaw"`
+		// APIVersion is the highest Docker API version supported by the daemon
+		APIVersion string `json:"APIVersion,omitempty"`
+		// MinAPIVersion is the lowest Docker API version supported by the daemon
+		MinAPIVersion string             `json:"MinAPIVersion,omitempty"`
+		Capabilities  DockerCapabilities `json:"Capabilities"`
+		// OSType is the operating system of the Docker host, linux or windows
+		OSType string `json:"OSType,omitempty"`
+		// Isolation is the default isolation technology used for the containers of a Windows host
+		Isolation string                    `json:"Isolation,omitempty"`
+		Runtime   DockerRuntimeCapabilities `json:"Runtime"`
+		// VolumeUsage is the disk usage of the volumes and the last time they were used
+		VolumeUsage []DockerVolumeUsage `json:"VolumeUsage"`
+		// Reservations are recorded in the resource samples and not stored in the snapshot
+		Reservations []ResourceReservation `json:"-"`
+	}
+
+	// DockerCapabilities represents the optional features supported by the Docker API of an endpoint
+	DockerCapabilities struct {
+		Secrets         bool `json:"Secrets"`
+		Configs         bool `json:"Configs"`
+		ServiceRollback bool `json:"ServiceRollback"`
+		DeviceRequests  bool `json:"DeviceRequests"`
+	}
+
+	// DockerRuntimeCapabilities represents the cgroup version of the host of a Docker endpoint and the resource
+	// limits supported by its daemon. The rootless daemons running on cgroup v1 hosts cannot limit nor report
+	// the resource usage of the containers.
+	DockerRuntimeCapabilities struct {
+		// CgroupVersion is 0 when the cgroup version is unknown, on Windows hosts
+		CgroupVersion  int  `json:"CgroupVersion"`
+		Rootless       bool `json:"Rootless"`
+		MemoryLimit    bool `json:"MemoryLimit"`
+		SwapLimit      bool `json:"SwapLimit"`
+		KernelMemory   bool `json:"KernelMemory"`
+		CPUQuota       bool `json:"CPUQuota"`
+		CPUShares      bool `json:"CPUShares"`
+		CPUSet         bool `json:"CPUSet"`
+		PidsLimit      bool `json:"PidsLimit"`
+		OomKillDisable bool `json:"OomKillDisable"`
+		// Stats is false when the resource usage statistics of the containers are not available
+		Stats bool `json:"Stats"`
+	}
+
+	// DatabaseStatistics represents the statistics of the database
+	DatabaseStatistics struct {
+		// Size is the size of the database file, in bytes
+		Size int64 `json:"Size"`
+		// Buckets is the number of objects stored in each bucket
+		Buckets      map[string]int `json:"Buckets"`
+		FreePages    int            `json:"FreePages"`
+		PendingPages int            `json:"PendingPages"`
+		ReadTxs      int            `json:"ReadTxs"`
+		OpenReadTxs  int            `json:"OpenReadTxs"`
+	}
+
+	// DockerNodeStatus represents the status of a Swarm node at the time of a snapshot
+	DockerNodeStatus struct {
+		ID           string `json:"Id"`
+		Hostname     string `json:"Hostname"`
+		Role         string `json:"Role"`
+		Availability string `json:"Availability"`
+		State        string `json:"State"`
+		Leader       bool   `json:"Leader"`
+		// StatusChangedAt is the time of the first snapshot reporting the current state and availability of the node
+		StatusChangedAt int64 `json:"StatusChangedAt"`
+	}
+
+	// DockerVolumeUsage represents the disk usage of a volume at the time of a snapshot
+	DockerVolumeUsage struct {
+		Name      string `json:"Name"`
+		Driver    string `json:"Driver"`
+		CreatedAt string `json:"CreatedAt,omitempty"`
+		// Size is the disk space used by the volume in bytes, -1 when the daemon cannot compute it
+		Size int64 `json:"Size"`
+		// RefCount is the number of containers mounting the volume, running or not
+		RefCount int `json:"RefCount"`
+		// LastUsed is the time of the last snapshot where a running container mounted the volume,
+		// 0 when the volume was never seen in use
+		LastUsed int64 `json:"LastUsed"`
+	}
+
+	// DockerHostSnapshot represents the host level resource usage of a Docker endpoint.
+	// The disk usage of Docker is available for all the endpoints, the filesystems, memory and load
+	// average are only available for the endpoints running on the same host as Portainer.
+	DockerHostSnapshot struct {
+		DockerDiskUsage DockerDiskUsage       `json:"DockerDiskUsage"`
+		Filesystems     []HostFilesystemUsage `json:"Filesystems,omitempty"`
+		MemoryTotal     int64                 `json:"MemoryTotal,omitempty"`
+		MemoryAvailable int64                 `json:"MemoryAvailable,omitempty"`
+		// LoadAverage contains the 1, 5 and 15 minutes load averages
+		LoadAverage []float64 `json:"LoadAverage,omitempty"`
+	}
+
+	// DockerDiskUsage represents the disk space (in bytes) used by the Docker resources of an endpoint
+	DockerDiskUsage struct {
+		Images     int64 `json:"Images"`
+		Containers int64 `json:"Containers"`
+		Volumes    int64 `json:"Volumes"`
+		BuildCache int64 `json:"BuildCache"`
 	}
 
 	// DockerSnapshotRaw represents all the information related to a snapshot as returned by the Docker API
@@ -129,6 +439,11 @@ type (
 		TagIDs       []TagID      `json:"TagIds"`
 		Endpoints    []EndpointID `json:"Endpoints"`
 		PartialMatch bool         `json:"PartialMatch"`
+		// Metadata are the key/value pairs of the endpoints of a dynamic group, they are matched with the tags
+		Metadata map[string]string `json:"Metadata,omitempty"`
+		// Expression is a tag expression evaluated against the metadata of the endpoints, such as
+		// region=eu AND tier=prod. When defined, it replaces the tags and metadata of a dynamic group
+		Expression string `json:"Expression,omitempty"`
 	}
 
 	// EdgeGroupID represents an Edge group identifier
@@ -179,6 +494,8 @@ type (
 		EntryPoint   string                         `json:"EntryPoint"`
 		Version      int                            `json:"Version"`
 		Prune        bool                           `json:"Prune"`
+		// Rollout is the staggered deployment of the latest version of the stack
+		Rollout *EdgeStackRollout `json:"Rollout,omitempty"`
 	}
 
 	//EdgeStackID represents an edge stack id
@@ -194,27 +511,125 @@ type (
 	//EdgeStackStatusType represents an edge stack status type
 	EdgeStackStatusType int
 
+	// EdgeStackRollout represents the deployment of a new version of an Edge stack by batches of endpoints.
+	// The endpoints outside of the deployed batches keep running the previous version of the stack,
+	// the next batch is deployed once all the endpoints of the current batch reported a successful deployment.
+	EdgeStackRollout struct {
+		Status    EdgeStackRolloutStatus `json:"Status"`
+		BatchSize int                    `json:"BatchSize"`
+		// FailureThreshold is the percentage of the endpoints of a batch allowed to fail
+		FailureThreshold int  `json:"FailureThreshold"`
+		AutoContinue     bool `json:"AutoContinue"`
+		AutoRollback     bool `json:"AutoRollback"`
+		// Endpoints are the endpoints of the stack in the order of the deployment
+		Endpoints []EndpointID `json:"Endpoints"`
+		// Deployed is the number of endpoints of the list running the new version, the current batch
+		// is composed of the endpoints between BatchStart and Deployed
+		Deployed           int    `json:"Deployed"`
+		BatchStart         int    `json:"BatchStart"`
+		PreviousVersion    int    `json:"PreviousVersion"`
+		PreviousEntryPoint string `json:"PreviousEntryPoint"`
+		StartDate          int64  `json:"StartDate"`
+		UpdateDate         int64  `json:"UpdateDate"`
+		Message            string `json:"Message,omitempty"`
+	}
+
+	// EdgeStackRolloutStatus represents the status of the rollout of an Edge stack
+	EdgeStackRolloutStatus int
+
+	// EdgeTunnelSettings represents the settings of the tunnels opened by the Edge agents, the durations
+	// use the Go duration format and the default values are used when they are empty
+	EdgeTunnelSettings struct {
+		// KeepAliveInterval is the interval of the keep-alive messages sent by the agents on their tunnel
+		KeepAliveInterval string `json:"KeepAliveInterval"`
+		// RequiredTimeout is the maximum duration for an agent to open a requested tunnel
+		RequiredTimeout string `json:"RequiredTimeout"`
+		// InactivityTimeout is the duration after which an unused tunnel is closed
+		InactivityTimeout string `json:"InactivityTimeout"`
+	}
+
+	// EdgeTunnelStats represents the connection statistics of the tunnel of an Edge endpoint
+	// since the start of the tunnel server
+	EdgeTunnelStats struct {
+		// Connections is the number of tunnels requested to the agent
+		Connections int `json:"Connections"`
+		// Reconnections is the number of tunnels requested shortly after the previous tunnel was closed
+		Reconnections int `json:"Reconnections"`
+		// Timeouts is the number of tunnels the agent did not open in time
+		Timeouts              int    `json:"Timeouts"`
+		LastConnectionDate    int64  `json:"LastConnectionDate"`
+		LastDisconnectionDate int64  `json:"LastDisconnectionDate"`
+		LastError             string `json:"LastError"`
+		LastErrorDate         int64  `json:"LastErrorDate"`
+	}
+
+	// EdgeTunnelZone represents a network zone whose Edge agents reach the tunnel server through a specific address,
+	// such as a NAT gateway or a firewall only opening the tunnel port range
+	EdgeTunnelZone struct {
+		Name    string `json:"Name"`
+		Address string `json:"Address"`
+	}
+
+	// EmailNotificationSettings represents the settings used to send the notifications by email
+	EmailNotificationSettings struct {
+		Enabled    bool     `json:"Enabled"`
+		Recipients []string `json:"Recipients"`
+		// Types are the types of notification sent by email, all the notifications are sent when empty
+		Types []NotificationType `json:"Types"`
+		// Templates are Go templates used to render the subject and the body of the emails.
+		// The template without type is used for the types that do not define a template.
+		Templates []NotificationTemplate `json:"Templates"`
+	}
+
 	// Endpoint represents a Docker endpoint with all the info required
 	// to connect to it
 	Endpoint struct {
-		ID                  EndpointID          `json:"Id"`
-		Name                string              `json:"Name"`
-		Type                EndpointType        `json:"Type"`
-		URL                 string              `json:"URL"`
-		GroupID             EndpointGroupID     `json:"GroupId"`
-		PublicURL           string              `json:"PublicURL"`
-		TLSConfig           TLSConfiguration    `json:"TLSConfig"`
-		Extensions          []EndpointExtension `json:"Extensions"`
-		AzureCredentials    AzureCredentials    `json:"AzureCredentials,omitempty"`
-		TagIDs              []TagID             `json:"TagIds"`
-		Status              EndpointStatus      `json:"Status"`
-		Snapshots           []DockerSnapshot    `json:"Snapshots"`
-		UserAccessPolicies  UserAccessPolicies  `json:"UserAccessPolicies"`
-		TeamAccessPolicies  TeamAccessPolicies  `json:"TeamAccessPolicies"`
-		EdgeID              string              `json:"EdgeID,omitempty"`
-		EdgeKey             string              `json:"EdgeKey"`
-		EdgeCheckinInterval int                 `json:"EdgeCheckinInterval"`
-		Kubernetes          KubernetesData      `json:"Kubernetes"`
+		ID               EndpointID          `json:"Id"`
+		Name             string              `json:"Name"`
+		Type             EndpointType        `json:"Type"`
+		URL              string              `json:"URL"`
+		GroupID          EndpointGroupID     `json:"GroupId"`
+		PublicURL        string              `json:"PublicURL"`
+		TLSConfig        TLSConfiguration    `json:"TLSConfig"`
+		Extensions       []EndpointExtension `json:"Extensions"`
+		AzureCredentials AzureCredentials    `json:"AzureCredentials,omitempty"`
+		// KubernetesCredentials is only defined for endpoints using the credentials of a kubeconfig
+		KubernetesCredentials *KubernetesCredentials `json:"KubernetesCredentials,omitempty"`
+		// SSHConfig is only defined for Docker endpoints reached through an ssh:// URL
+		SSHConfig           *SSHConfiguration  `json:"SSHConfig,omitempty"`
+		TagIDs              []TagID            `json:"TagIds"`
+		Status              EndpointStatus     `json:"Status"`
+		Snapshots           []DockerSnapshot   `json:"Snapshots"`
+		UserAccessPolicies  UserAccessPolicies `json:"UserAccessPolicies"`
+		TeamAccessPolicies  TeamAccessPolicies `json:"TeamAccessPolicies"`
+		EdgeID              string             `json:"EdgeID,omitempty"`
+		EdgeKey             string             `json:"EdgeKey"`
+		EdgeCheckinInterval int                `json:"EdgeCheckinInterval"`
+		Kubernetes          KubernetesData     `json:"Kubernetes"`
+
+		// ResourceOwnershipPolicy overrides the default ownership policy defined in the settings
+		ResourceOwnershipPolicy ResourceOwnershipPolicy `json:"ResourceOwnershipPolicy"`
+
+		// Protected endpoints require the stack deployments and the container deletions to be approved by a second user
+		Protected bool `json:"Protected"`
+
+		// Metadata are key/value pairs describing the endpoint, such as datacenter=eu-1 or tier=prod
+		Metadata map[string]string `json:"Metadata,omitempty"`
+
+		// EdgeEnrollment is only defined for the Edge endpoints pre-registered to be claimed by a device
+		EdgeEnrollment *EdgeEnrollment `json:"EdgeEnrollment,omitempty"`
+		// EdgePendingApproval is true while the Edge agent which checked in is waiting for the approval of an administrator
+		EdgePendingApproval bool `json:"EdgePendingApproval,omitempty"`
+		// EdgeRejectedIDs are the identifiers of the Edge agents rejected by an administrator
+		EdgeRejectedIDs []string `json:"EdgeRejectedIDs,omitempty"`
+		// EdgeBundleVersion is the version of the Edge bundle cached by the agent, as reported on its last check-in
+		EdgeBundleVersion string `json:"EdgeBundleVersion,omitempty"`
+		// EdgeTunnelZone is the name of the network zone of the Edge agent, it defines the tunnel server address of its Edge key
+		EdgeTunnelZone string `json:"EdgeTunnelZone,omitempty"`
+		// EdgeDeferredDeployment is only defined while the Edge endpoint is in a maintenance window
+		EdgeDeferredDeployment *EdgeDeferredDeployment `json:"EdgeDeferredDeployment,omitempty"`
+		// OrganizationID is the organization the endpoint belongs to
+		OrganizationID OrganizationID `json:"OrganizationId,omitempty"`
 
 		// Deprecated fields
 		// Deprecated in DBVersion == 4
@@ -231,6 +646,27 @@ type (
 		Tags []string `json:"Tags"`
 	}
 
+	// EdgeEnrollment represents the enrollment of a pre-registered Edge device. The device claims
+	// its endpoint with the enrollment key, only the hash of the key is stored
+	EdgeEnrollment struct {
+		KeyHash string `json:"KeyHash,omitempty"`
+		// DeviceID is the optional hardware identifier (such as a serial number) the device must present
+		DeviceID     string `json:"DeviceID,omitempty"`
+		CreationDate int64  `json:"CreationDate"`
+		Claimed      bool   `json:"Claimed"`
+		ClaimDate    int64  `json:"ClaimDate,omitempty"`
+		ClaimAddress string `json:"ClaimAddress,omitempty"`
+	}
+
+	// EdgeDeferredDeployment represents the versions of the Edge stacks sent to the agent of an Edge endpoint when
+	// it entered a maintenance window. The agent keeps receiving these versions until the end of the window,
+	// the Edge stacks created or updated in the meantime are deployed after the window.
+	EdgeDeferredDeployment struct {
+		Stacks        map[EdgeStackID]int `json:"Stacks"`
+		BundleVersion string              `json:"BundleVersion"`
+		Since         int64               `json:"Since"`
+	}
+
 	// EndpointAuthorizations represents the authorizations associated to a set of endpoints
 	EndpointAuthorizations map[EndpointID]Authorizations
 
@@ -310,6 +746,9 @@ type (
 	// ExtensionID represents a extension identifier
 	ExtensionID int
 
+	// FeatureFlag represents the name of an experimental feature that can be enabled per installation
+	FeatureFlag string
+
 	// GitlabRegistryData represents data required for gitlab registry to work
 	GitlabRegistryData struct {
 		ProjectID   int    `json:"ProjectId"`
@@ -317,9 +756,95 @@ type (
 		ProjectPath string `json:"ProjectPath"`
 	}
 
+	// HostAlertingSettings represents the thresholds above which a notification is raised about
+	// the resource usage of a Docker host. A threshold set to 0 is disabled.
+	HostAlertingSettings struct {
+		Enabled bool `json:"Enabled"`
+		// DiskUsageThreshold is the used space of a filesystem, in percent
+		DiskUsageThreshold float64 `json:"DiskUsageThreshold"`
+		// MemoryUsageThreshold is the used memory, in percent
+		MemoryUsageThreshold float64 `json:"MemoryUsageThreshold"`
+		// LoadAverageThreshold is the 5 minutes load average per CPU
+		LoadAverageThreshold float64 `json:"LoadAverageThreshold"`
+	}
+
+	// HostAction represents a host command defined by an administrator, run on the host of a Docker
+	// endpoint by the Portainer agent. Only the administrators and the users and teams the action is
+	// granted to can run it.
+	HostAction struct {
+		ID          HostActionID `json:"Id"`
+		Name        string       `json:"Name"`
+		Description string       `json:"Description"`
+		// Command is the executable and its arguments, it is not interpreted by a shell
+		Command []string `json:"Command"`
+		// Timeout is the number of seconds after which the command is killed by the agent
+		Timeout int `json:"Timeout"`
+		// EndpointIDs restrict the endpoints the action can run on, it can run on all the agent endpoints when empty
+		EndpointIDs     []EndpointID `json:"EndpointIds"`
+		AuthorizedUsers []UserID     `json:"AuthorizedUsers"`
+		AuthorizedTeams []TeamID     `json:"AuthorizedTeams"`
+		CreatedBy       string       `json:"CreatedBy"`
+		CreationDate    int64        `json:"CreationDate"`
+	}
+
+	// HostActionID represents a host action identifier
+	HostActionID int
+
+	// HostFilesystemUsage represents the usage (in bytes) of a filesystem mounted on a host
+	HostFilesystemUsage struct {
+		Device     string `json:"Device"`
+		MountPoint string `json:"MountPoint"`
+		Type       string `json:"Type"`
+		Total      int64  `json:"Total"`
+		Used       int64  `json:"Used"`
+		Available  int64  `json:"Available"`
+	}
+
 	// JobType represents a job type
 	JobType int
 
+	// KubernetesApplication represents a Kubernetes workload (Deployment, StatefulSet or DaemonSet)
+	// aggregated with its pods and rollout status
+	KubernetesApplication struct {
+		Name              string                    `json:"Name"`
+		Namespace         string                    `json:"Namespace"`
+		Kind              KubernetesApplicationKind `json:"Kind"`
+		Images            []string                  `json:"Images"`
+		DesiredReplicas   int32                     `json:"DesiredReplicas"`
+		ReadyReplicas     int32                     `json:"ReadyReplicas"`
+		UpdatedReplicas   int32                     `json:"UpdatedReplicas"`
+		AvailableReplicas int32                     `json:"AvailableReplicas"`
+		Paused            bool                      `json:"Paused"`
+		Revision          int64                     `json:"Revision"`
+		CreationDate      int64                     `json:"CreationDate"`
+		Rollout           KubernetesRolloutStatus   `json:"Rollout"`
+		Pods              []KubernetesPod           `json:"Pods"`
+	}
+
+	// KubernetesApplicationKind represents the kind of workload backing a Kubernetes application
+	KubernetesApplicationKind string
+
+	// KubernetesApplicationRevision represents an entry of the rollout history of a Kubernetes application
+	KubernetesApplicationRevision struct {
+		Revision     int64    `json:"Revision"`
+		Images       []string `json:"Images"`
+		ChangeCause  string   `json:"ChangeCause"`
+		CreationDate int64    `json:"CreationDate"`
+		Current      bool     `json:"Current"`
+	}
+
+	// KubernetesAuthenticationMethod represents the method used to authenticate against a Kubernetes API
+	KubernetesAuthenticationMethod int
+
+	// KubernetesCredentials represents the credentials used to connect to a Kubernetes API.
+	// The kubeconfig is stored on disk so that the credentials refreshed by the authentication
+	// providers are persisted.
+	KubernetesCredentials struct {
+		AuthenticationMethod KubernetesAuthenticationMethod `json:"AuthenticationMethod"`
+		KubeconfigPath       string                         `json:"KubeconfigPath"`
+		Context              string                         `json:"Context"`
+	}
+
 	// KubernetesData contains all the Kubernetes related endpoint information
 	KubernetesData struct {
 		Snapshots     []KubernetesSnapshot    `json:"Snapshots"`
@@ -328,11 +853,22 @@ type (
 
 	// KubernetesSnapshot represents a snapshot of a specific Kubernetes endpoint at a specific time
 	KubernetesSnapshot struct {
-		Time              int64  `json:"Time"`
-		KubernetesVersion string `json:"KubernetesVersion"`
-		NodeCount         int    `json:"NodeCount"`
-		TotalCPU          int64  `json:"TotalCPU"`
-		TotalMemory       int64  `json:"TotalMemory"`
+		Time              int64                  `json:"Time"`
+		KubernetesVersion string                 `json:"KubernetesVersion"`
+		NodeCount         int                    `json:"NodeCount"`
+		TotalCPU          int64                  `json:"TotalCPU"`
+		TotalMemory       int64                  `json:"TotalMemory"`
+		Nodes             []KubernetesNodeStatus `json:"Nodes"`
+	}
+
+	// KubernetesCertificate represents a cert-manager Certificate resource
+	KubernetesCertificate struct {
+		Name       string   `json:"Name"`
+		Namespace  string   `json:"Namespace"`
+		SecretName string   `json:"SecretName"`
+		DNSNames   []string `json:"DNSNames"`
+		IssuerName string   `json:"IssuerName"`
+		IssuerKind string   `json:"IssuerKind"`
 	}
 
 	// KubernetesConfiguration represents the configuration of a Kubernetes endpoint
@@ -351,12 +887,137 @@ type (
 		AllowVolumeExpansion bool     `json:"AllowVolumeExpansion"`
 	}
 
+	// KubernetesDrainEvent represents the progress of the eviction of a pod during a node drain
+	KubernetesDrainEvent struct {
+		Pod       string `json:"Pod,omitempty"`
+		Namespace string `json:"Namespace,omitempty"`
+		Status    string `json:"Status"`
+		Message   string `json:"Message,omitempty"`
+	}
+
+	// KubernetesDrainOptions represents the options used to drain a Kubernetes node
+	KubernetesDrainOptions struct {
+		// Force evicts the pods that are not managed by a controller
+		Force bool
+		// DeleteLocalData evicts the pods using emptyDir volumes, the data of these volumes is lost
+		DeleteLocalData bool
+		// GracePeriod is the number of seconds given to each pod to terminate, the pod default is used when negative
+		GracePeriod int64
+		// Timeout is the number of seconds to wait for the pods to be evicted
+		Timeout int
+	}
+
+	// KubernetesIngress represents a Kubernetes Ingress resource
+	KubernetesIngress struct {
+		Name         string                  `json:"Name"`
+		Namespace    string                  `json:"Namespace"`
+		IngressClass string                  `json:"IngressClass"`
+		Annotations  map[string]string       `json:"Annotations"`
+		Rules        []KubernetesIngressRule `json:"Rules"`
+		TLS          []KubernetesIngressTLS  `json:"TLS"`
+		CreationDate int64                   `json:"CreationDate"`
+	}
+
 	// KubernetesIngressClassConfig represents a Kubernetes Ingress Class configuration
 	KubernetesIngressClassConfig struct {
 		Name string `json:"Name"`
 		Type string `json:"Type"`
 	}
 
+	// KubernetesIngressRule represents a path of an Ingress routed to a service
+	KubernetesIngressRule struct {
+		Host        string `json:"Host"`
+		Path        string `json:"Path"`
+		ServiceName string `json:"ServiceName"`
+		ServicePort int    `json:"ServicePort"`
+	}
+
+	// KubernetesIngressTLS represents the TLS configuration of a set of Ingress hosts
+	KubernetesIngressTLS struct {
+		Hosts      []string `json:"Hosts"`
+		SecretName string   `json:"SecretName"`
+	}
+
+	// KubernetesNodeStatus represents the status of a Kubernetes node at a specific time
+	KubernetesNodeStatus struct {
+		Name           string `json:"Name"`
+		Ready          bool   `json:"Ready"`
+		Unschedulable  bool   `json:"Unschedulable"`
+		MemoryPressure bool   `json:"MemoryPressure"`
+		DiskPressure   bool   `json:"DiskPressure"`
+		PIDPressure    bool   `json:"PIDPressure"`
+	}
+
+	// KubernetesNodeTaint represents a taint applied to a Kubernetes node
+	KubernetesNodeTaint struct {
+		Key    string `json:"Key"`
+		Value  string `json:"Value"`
+		Effect string `json:"Effect"`
+	}
+
+	// KubernetesNodeUpdate represents the changes applied to the labels and taints of a Kubernetes node
+	KubernetesNodeUpdate struct {
+		// Labels are added to the node labels, existing labels with the same key are overridden
+		Labels map[string]string
+		// RemoveLabels is a list of label keys to remove from the node labels
+		RemoveLabels []string
+		// Taints replaces the node taints when specified
+		Taints []KubernetesNodeTaint
+	}
+
+	// KubernetesPersistentVolume represents a Kubernetes persistent volume
+	KubernetesPersistentVolume struct {
+		Name           string `json:"Name"`
+		StorageClass   string `json:"StorageClass"`
+		Capacity       int64  `json:"Capacity"`
+		Phase          string `json:"Phase"`
+		ReclaimPolicy  string `json:"ReclaimPolicy"`
+		ClaimNamespace string `json:"ClaimNamespace"`
+		ClaimName      string `json:"ClaimName"`
+		CreationDate   int64  `json:"CreationDate"`
+	}
+
+	// KubernetesPersistentVolumeClaim represents a Kubernetes persistent volume claim
+	KubernetesPersistentVolumeClaim struct {
+		Name          string   `json:"Name"`
+		Namespace     string   `json:"Namespace"`
+		StorageClass  string   `json:"StorageClass"`
+		Phase         string   `json:"Phase"`
+		VolumeName    string   `json:"VolumeName"`
+		AccessModes   []string `json:"AccessModes"`
+		RequestedSize int64    `json:"RequestedSize"`
+		Capacity      int64    `json:"Capacity"`
+		// Expandable is true when the storage class of the claim allows volume expansion
+		Expandable bool `json:"Expandable"`
+		// Usage is only available when the kubelet reports volume statistics for the claim
+		Usage        *KubernetesVolumeUsage `json:"Usage"`
+		CreationDate int64                  `json:"CreationDate"`
+	}
+
+	// KubernetesPod represents a pod managed by a Kubernetes application
+	KubernetesPod struct {
+		Name         string `json:"Name"`
+		Node         string `json:"Node"`
+		Phase        string `json:"Phase"`
+		Ready        bool   `json:"Ready"`
+		Restarts     int32  `json:"Restarts"`
+		CreationDate int64  `json:"CreationDate"`
+	}
+
+	// KubernetesRolloutStatus represents the progress of the rollout of a Kubernetes application
+	KubernetesRolloutStatus struct {
+		Complete bool   `json:"Complete"`
+		Failed   bool   `json:"Failed"`
+		Message  string `json:"Message"`
+	}
+
+	// KubernetesVolumeUsage represents the disk usage of a volume as reported by the kubelet
+	KubernetesVolumeUsage struct {
+		UsedBytes      int64 `json:"UsedBytes"`
+		CapacityBytes  int64 `json:"CapacityBytes"`
+		AvailableBytes int64 `json:"AvailableBytes"`
+	}
+
 	// LDAPGroupSearchSettings represents settings used to search for groups in a LDAP server
 	LDAPGroupSearchSettings struct {
 		GroupBaseDN    string `json:"GroupBaseDN"`
@@ -392,9 +1053,108 @@ type (
 		Valid      bool   `json:"Valid,omitempty"`
 	}
 
+	// LoadBalancerIntegration represents the registration of the services published on an endpoint into an
+	// external load balancer or service registry, an endpoint has at most one integration
+	LoadBalancerIntegration struct {
+		EndpointID EndpointID                  `json:"EndpointId"`
+		Type       LoadBalancerIntegrationType `json:"Type"`
+		Enabled    bool                        `json:"Enabled"`
+		// Address is the address the published ports of the endpoint are reachable on, the host of the
+		// public URL of the endpoint is used when it is not specified
+		Address string `json:"Address"`
+		// URL is the address of the Consul agent or of the generic webhook
+		URL string `json:"URL,omitempty"`
+		// Token is the ACL token of the Consul agent or the secret signing the webhook payloads
+		Token string `json:"Token,omitempty"`
+		// FilePath is the path of the dynamic configuration file watched by the Traefik file provider
+		FilePath string `json:"FilePath,omitempty"`
+		// Domain is the domain of the host names generated for the Traefik routers, such as apps.example.com
+		Domain      string   `json:"Domain,omitempty"`
+		EntryPoints []string `json:"EntryPoints,omitempty"`
+		// Registrations are the services registered on the last successful synchronization
+		Registrations []LoadBalancerRegistration `json:"Registrations"`
+		LastSync      int64                      `json:"LastSync"`
+		LastError     string                     `json:"LastError,omitempty"`
+	}
+
+	// LoadBalancerIntegrationType represents the type of the external service the published services are registered into
+	LoadBalancerIntegrationType string
+
+	// LoadBalancerRegistration represents a published port of a container or a service registered into an external load balancer
+	LoadBalancerRegistration struct {
+		ID        string `json:"Id"`
+		Name      string `json:"Name"`
+		StackName string `json:"StackName,omitempty"`
+		Address   string `json:"Address"`
+		Port      int    `json:"Port"`
+		Protocol  string `json:"Protocol"`
+		// Hostname is the host name routed to the port by Traefik, it is only defined for the main port of a service
+		Hostname string `json:"Hostname,omitempty"`
+	}
+
+	// MaintenanceRule represents a recurring time window of a maintenance window
+	MaintenanceRule struct {
+		// Days of the week during which the rule applies (0 is Sunday), the rule applies every day when empty
+		Days []int `json:"Days"`
+		// StartTime and EndTime use the HH:MM format. A window ending before it starts spans over midnight.
+		StartTime string `json:"StartTime"`
+		EndTime   string `json:"EndTime"`
+	}
+
+	// MaintenanceWindow represents a period during which the alerts of a set of endpoints are silenced, their
+	// auto-heal rules are paused and their scaling schedules, Edge jobs and Edge stack deployments are deferred.
+	// A window spans from Start to End when they are defined, and recurs according to its rules otherwise.
+	MaintenanceWindow struct {
+		ID               MaintenanceWindowID `json:"Id"`
+		Name             string              `json:"Name"`
+		EndpointIDs      []EndpointID        `json:"EndpointIds"`
+		EndpointGroupIDs []EndpointGroupID   `json:"EndpointGroupIds"`
+		// Start and End are Unix timestamps
+		Start        int64             `json:"Start,omitempty"`
+		End          int64             `json:"End,omitempty"`
+		Timezone     string            `json:"Timezone"`
+		Rules        []MaintenanceRule `json:"Rules"`
+		Enabled      bool              `json:"Enabled"`
+		CreatedBy    UserID            `json:"CreatedBy"`
+		CreationDate int64             `json:"CreationDate"`
+	}
+
+	// MaintenanceWindowID represents a maintenance window identifier
+	MaintenanceWindowID int
+
 	// MembershipRole represents the role of a user within a team
 	MembershipRole int
 
+	// MultiEndpointStack represents a stack deployed with the same definition on several endpoints.
+	// Each target can override the variables of the stack.
+	MultiEndpointStack struct {
+		ID           MultiEndpointStackID       `json:"Id"`
+		Name         string                     `json:"Name"`
+		Type         StackType                  `json:"Type"`
+		EntryPoint   string                     `json:"EntryPoint"`
+		Env          []Pair                     `json:"Env"`
+		Targets      []MultiEndpointStackTarget `json:"Targets"`
+		TaskID       TaskID                     `json:"TaskId"`
+		CreatedBy    UserID                     `json:"CreatedBy"`
+		CreationDate int64                      `json:"CreationDate"`
+		ProjectPath  string
+	}
+
+	// MultiEndpointStackID represents a multi-endpoint stack identifier
+	MultiEndpointStackID int
+
+	// MultiEndpointStackTarget represents an endpoint a multi-endpoint stack is deployed on
+	MultiEndpointStackTarget struct {
+		EndpointID     EndpointID                     `json:"EndpointId"`
+		Env            []Pair                         `json:"Env"`
+		Status         MultiEndpointStackTargetStatus `json:"Status"`
+		Error          string                         `json:"Error"`
+		DeploymentDate int64                          `json:"DeploymentDate"`
+	}
+
+	// MultiEndpointStackTargetStatus represents the deployment status of a multi-endpoint stack on an endpoint
+	MultiEndpointStackTargetStatus int
+
 	// OAuthSettings represents the settings used to authorize with an authorization server
 	OAuthSettings struct {
 		ClientID             string `json:"ClientID"`
@@ -409,12 +1169,145 @@ type (
 		DefaultTeamID        TeamID `json:"DefaultTeamID"`
 	}
 
+	// Organization represents a tenant of the instance. The users, teams, endpoints, registries and
+	// custom templates of an organization are isolated from the other organizations, they are only
+	// managed by the administrators of the organization and by the administrators of the instance
+	Organization struct {
+		ID          OrganizationID       `json:"Id"`
+		Name        string               `json:"Name"`
+		Description string               `json:"Description"`
+		Settings    OrganizationSettings `json:"Settings"`
+		Quotas      OrganizationQuotas   `json:"Quotas"`
+	}
+
+	// OrganizationID represents an organization identifier, the resources which do not belong
+	// to an organization have no organization identifier
+	OrganizationID int
+
+	// OrganizationQuotas represents the maximum number of resources an organization can create,
+	// a quota without value is unlimited. The Edge devices are also counted as endpoints.
+	OrganizationQuotas struct {
+		MaxEndpoints   int `json:"MaxEndpoints"`
+		MaxEdgeDevices int `json:"MaxEdgeDevices"`
+		MaxUsers       int `json:"MaxUsers"`
+		MaxStacks      int `json:"MaxStacks"`
+	}
+
+	// OrganizationQuotaUsage represents the number of resources of an organization counted against its quotas
+	OrganizationQuotaUsage struct {
+		Endpoints   int `json:"Endpoints"`
+		EdgeDevices int `json:"EdgeDevices"`
+		Users       int `json:"Users"`
+		Stacks      int `json:"Stacks"`
+	}
+
+	// OrganizationSettings represents the settings of an organization overriding the settings
+	// of the instance, a setting without value is not overridden
+	OrganizationSettings struct {
+		LogoURL                        string                  `json:"LogoURL"`
+		EdgeAgentCheckinInterval       int                     `json:"EdgeAgentCheckinInterval"`
+		DefaultResourceOwnershipPolicy ResourceOwnershipPolicy `json:"DefaultResourceOwnershipPolicy"`
+	}
+
+	// OrphanCleanupReport represents the result of a cleanup of the database entries
+	// referencing users, teams or Docker resources that do not exist anymore
+	OrphanCleanupReport struct {
+		DryRun                  bool                `json:"DryRun"`
+		DockerResourcesChecked  bool                `json:"DockerResourcesChecked"`
+		RemovedResourceControls []ResourceControlID `json:"RemovedResourceControls"`
+		UpdatedResourceControls []ResourceControlID `json:"UpdatedResourceControls"`
+		UpdatedRegistries       []RegistryID        `json:"UpdatedRegistries"`
+		UpdatedEndpoints        []EndpointID        `json:"UpdatedEndpoints"`
+		UpdatedEndpointGroups   []EndpointGroupID   `json:"UpdatedEndpointGroups"`
+		UnreachableEndpoints    []EndpointID        `json:"UnreachableEndpoints"`
+	}
+
+	// Notification represents an event that must be reported to the administrators
+	Notification struct {
+		Type       NotificationType     `json:"Type"`
+		Severity   NotificationSeverity `json:"Severity"`
+		EndpointID EndpointID           `json:"EndpointId"`
+		ResourceID string               `json:"ResourceId"`
+		Title      string               `json:"Title"`
+		Message    string               `json:"Message"`
+		Time       int64                `json:"Time"`
+	}
+
+	// UserNotification represents a notification stored in the inbox of a user
+	UserNotification struct {
+		ID     UserNotificationID `json:"Id"`
+		UserID UserID             `json:"UserId"`
+		Notification
+		Read bool `json:"Read"`
+	}
+
+	// UserNotificationID represents a user notification identifier
+	UserNotificationID int
+
+	// NotificationChannel represents an external service notifications are forwarded to
+	NotificationChannel struct {
+		ID      NotificationChannelID   `json:"Id"`
+		Name    string                  `json:"Name"`
+		Type    NotificationChannelType `json:"Type"`
+		Enabled bool                    `json:"Enabled"`
+		// URL is the incoming webhook URL for Slack, Microsoft Teams and generic webhooks,
+		// it overrides the Events API URL for PagerDuty
+		URL string `json:"URL"`
+		// RoutingKey is the integration key of a PagerDuty service
+		RoutingKey string `json:"RoutingKey,omitempty"`
+		// Routes select the notifications forwarded to the channel, a notification is forwarded
+		// when it matches at least one route
+		Routes []NotificationRoute `json:"Routes"`
+	}
+
+	// NotificationChannelID represents a notification channel identifier
+	NotificationChannelID int
+
+	// NotificationChannelType represents the type of service a notification channel forwards notifications to
+	NotificationChannelType string
+
+	// NotificationRoute represents a rule matching notifications by type and severity.
+	// An empty list matches all the types or all the severities.
+	NotificationRoute struct {
+		Types      []NotificationType     `json:"Types"`
+		Severities []NotificationSeverity `json:"Severities"`
+	}
+
+	// NotificationSeverity represents the severity of a notification
+	NotificationSeverity string
+
+	// NotificationTemplate represents the Go templates used to render a notification
+	NotificationTemplate struct {
+		Type    NotificationType `json:"Type,omitempty"`
+		Subject string           `json:"Subject"`
+		Body    string           `json:"Body"`
+	}
+
+	// NotificationType represents the type of event reported by a notification
+	NotificationType int
+
 	// Pair defines a key/value string pair
 	Pair struct {
 		Name  string `json:"name"`
 		Value string `json:"value"`
 	}
 
+	// PasswordPolicy represents the requirements applied to the passwords of the users using internal authentication
+	PasswordPolicy struct {
+		MinLength               int  `json:"MinLength"`
+		RequireUppercase        bool `json:"RequireUppercase"`
+		RequireLowercase        bool `json:"RequireLowercase"`
+		RequireDigit            bool `json:"RequireDigit"`
+		RequireSpecialCharacter bool `json:"RequireSpecialCharacter"`
+		// HistorySize is the number of most recent passwords of a user, including the current one,
+		// that cannot be reused. Reuse is allowed when 0
+		HistorySize int `json:"HistorySize"`
+		// MaxAge is the number of days after which a password expires and must be changed. Passwords never expire when 0
+		MaxAge int `json:"MaxAge"`
+		// ForceChangeOnFirstLogin requires the users to change the password set by an administrator on their first login
+		ForceChangeOnFirstLogin bool `json:"ForceChangeOnFirstLogin"`
+	}
+
 	// Registry represents a Docker registry with all the info required
 	// to connect to it
 	Registry struct {
@@ -429,6 +1322,8 @@ type (
 		Gitlab                  GitlabRegistryData               `json:"Gitlab"`
 		UserAccessPolicies      UserAccessPolicies               `json:"UserAccessPolicies"`
 		TeamAccessPolicies      TeamAccessPolicies               `json:"TeamAccessPolicies"`
+		// OrganizationID is the organization the registry belongs to
+		OrganizationID OrganizationID `json:"OrganizationId,omitempty"`
 
 		// Deprecated fields
 		// Deprecated in DBVersion == 18
@@ -479,6 +1374,100 @@ type (
 	// ResourceControlType represents the type of resource associated to the resource control (volume, container, service...)
 	ResourceControlType int
 
+	// ResourceOwnershipPolicy represents the ownership applied by default to the resource control
+	// of a newly created resource
+	ResourceOwnershipPolicy int
+
+	// ResourceReservation represents the CPU and memory reserved by a container or a service
+	// at the time of a snapshot
+	ResourceReservation struct {
+		Type       ResourceReservationType `json:"Type"`
+		ResourceID string                  `json:"ResourceId"`
+		Name       string                  `json:"Name"`
+		Stack      string                  `json:"Stack,omitempty"`
+		// TeamIDs are the teams the resource is shared with at the time of the snapshot
+		TeamIDs []TeamID `json:"TeamIds,omitempty"`
+		// CPU is the number of CPU reserved by a single replica
+		CPU float64 `json:"CPU"`
+		// Memory is the memory (in bytes) reserved by a single replica
+		Memory   int64             `json:"Memory"`
+		Replicas int               `json:"Replicas"`
+		Labels   map[string]string `json:"-"`
+	}
+
+	// ResourceReservationType represents the type of resource a reservation is associated to
+	ResourceReservationType string
+
+	// ResourceSample represents the resource reservations of an endpoint at a point in time
+	ResourceSample struct {
+		ID         ResourceSampleID `json:"Id"`
+		EndpointID EndpointID       `json:"EndpointId"`
+		Time       int64            `json:"Time"`
+		// Interval is the snapshot interval (in seconds) at the time of the sample, it is
+		// the maximum duration a sample is accounted for
+		Interval     int64                 `json:"Interval"`
+		Reservations []ResourceReservation `json:"Reservations"`
+	}
+
+	// ResourceSampleID represents a resource sample identifier
+	ResourceSampleID int
+
+	// ReportFrequency represents how often a report is sent
+	ReportFrequency string
+
+	// ReportSection represents a section of a report
+	ReportSection string
+
+	// ReportSubscription represents the preferences of an administrator regarding the periodic reports
+	ReportSubscription struct {
+		UserID    UserID          `json:"UserId"`
+		Enabled   bool            `json:"Enabled"`
+		Email     string          `json:"Email"`
+		Frequency ReportFrequency `json:"Frequency"`
+		Sections  []ReportSection `json:"Sections"`
+		// LastSent is the time the last report was sent, the next report covers the events since that time
+		LastSent int64 `json:"LastSent"`
+	}
+
+	// RetentionCategory represents a category of records removed by the retention purge
+	RetentionCategory string
+
+	// RetentionCategoryReport represents the records of a category removed by a retention purge
+	RetentionCategoryReport struct {
+		Category RetentionCategory `json:"Category"`
+		// RetentionDays is the retention applied to the category
+		RetentionDays int `json:"RetentionDays"`
+		Deleted       int `json:"Deleted"`
+		// ReclaimedBytes is the size of the deleted records in the database
+		ReclaimedBytes int64 `json:"ReclaimedBytes"`
+	}
+
+	// RetentionReport represents the result of a retention purge
+	RetentionReport struct {
+		Date           int64                     `json:"Date"`
+		Manual         bool                      `json:"Manual"`
+		Categories     []RetentionCategoryReport `json:"Categories"`
+		ReclaimedBytes int64                     `json:"ReclaimedBytes"`
+	}
+
+	// RetentionSettings represents the number of days the records of each category are kept for,
+	// the default retention of the category is applied when a value is 0 and the records are kept
+	// forever when a value is RetentionForever
+	RetentionSettings struct {
+		UserActivityDays    int `json:"UserActivityDays"`
+		SettingsHistoryDays int `json:"SettingsHistoryDays"`
+		TaskDays            int `json:"TaskDays"`
+		NotificationDays    int `json:"NotificationDays"`
+		ResourceSampleDays  int `json:"ResourceSampleDays"`
+		AutoHealActionDays  int `json:"AutoHealActionDays"`
+	}
+
+	// HostBrowserSettings represents the host paths that can be browsed and downloaded through the agents.
+	// The host browser is read-only, the default paths are allowed when no path is defined
+	HostBrowserSettings struct {
+		AllowedPaths []string `json:"AllowedPaths"`
+	}
+
 	// Role represents a set of authorizations that can be associated to a user or
 	// to a team.
 	Role struct {
@@ -492,6 +1481,40 @@ type (
 	// RoleID represents a role identifier
 	RoleID int
 
+	// ScalingRule represents a time window during which a specific number of replicas is required
+	ScalingRule struct {
+		// Days of the week during which the rule applies (0 is Sunday), the rule applies every day when empty
+		Days []int `json:"Days"`
+		// StartTime and EndTime use the HH:MM format. A window ending before it starts spans over midnight.
+		StartTime string `json:"StartTime"`
+		EndTime   string `json:"EndTime"`
+		Replicas  int    `json:"Replicas"`
+	}
+
+	// ScalingSchedule represents a set of time based rules used to scale a Swarm service or a Kubernetes deployment
+	ScalingSchedule struct {
+		ID              ScalingScheduleID `json:"Id"`
+		Name            string            `json:"Name"`
+		EndpointID      EndpointID        `json:"EndpointId"`
+		TargetType      ScalingTargetType `json:"TargetType"`
+		TargetName      string            `json:"TargetName"`
+		Namespace       string            `json:"Namespace"`
+		Timezone        string            `json:"Timezone"`
+		Rules           []ScalingRule     `json:"Rules"`
+		DefaultReplicas int               `json:"DefaultReplicas"`
+		Enabled         bool              `json:"Enabled"`
+		// AppliedReplicas is the number of replicas applied during the last successful execution, -1 when never applied
+		AppliedReplicas int    `json:"AppliedReplicas"`
+		LastExecution   int64  `json:"LastExecution"`
+		LastError       string `json:"LastError"`
+	}
+
+	// ScalingScheduleID represents a scaling schedule identifier
+	ScalingScheduleID int
+
+	// ScalingTargetType represents the type of resource scaled by a scaling schedule
+	ScalingTargetType int
+
 	// Schedule represents a scheduled job.
 	// It only contains a pointer to one of the JobRunner implementations
 	// based on the JobType.
@@ -520,48 +1543,164 @@ type (
 		RetryInterval int
 	}
 
+	// SMTPSettings represents the settings used to send emails
+	SMTPSettings struct {
+		Host     string `json:"Host"`
+		Port     int    `json:"Port"`
+		Username string `json:"Username"`
+		Password string `json:"Password,omitempty"`
+		From     string `json:"From"`
+		// TLS enables implicit TLS, StartTLS upgrades a plain connection when the server supports it
+		TLS        bool `json:"TLS"`
+		StartTLS   bool `json:"StartTLS"`
+		SkipVerify bool `json:"SkipVerify"`
+	}
+
+	// SSHConfiguration represents the configuration used to tunnel the Docker API of an endpoint over SSH
+	SSHConfiguration struct {
+		PrivateKeyPath string `json:"PrivateKeyPath"`
+		// HostKey is the public key of the host in the authorized_keys format, it is recorded
+		// when the endpoint is created and verified on each connection
+		HostKey string `json:"HostKey"`
+		// SocketPath is the path of the Docker socket on the remote host
+		SocketPath string `json:"SocketPath"`
+	}
+
 	// Settings represents the application settings
 	Settings struct {
-		LogoURL                                   string               `json:"LogoURL"`
-		BlackListedLabels                         []Pair               `json:"BlackListedLabels"`
-		AuthenticationMethod                      AuthenticationMethod `json:"AuthenticationMethod"`
-		LDAPSettings                              LDAPSettings         `json:"LDAPSettings"`
-		OAuthSettings                             OAuthSettings        `json:"OAuthSettings"`
-		AllowBindMountsForRegularUsers            bool                 `json:"AllowBindMountsForRegularUsers"`
-		AllowPrivilegedModeForRegularUsers        bool                 `json:"AllowPrivilegedModeForRegularUsers"`
-		AllowVolumeBrowserForRegularUsers         bool                 `json:"AllowVolumeBrowserForRegularUsers"`
-		AllowHostNamespaceForRegularUsers         bool                 `json:"AllowHostNamespaceForRegularUsers"`
-		AllowDeviceMappingForRegularUsers         bool                 `json:"AllowDeviceMappingForRegularUsers"`
-		AllowStackManagementForRegularUsers       bool                 `json:"AllowStackManagementForRegularUsers"`
-		AllowContainerCapabilitiesForRegularUsers bool                 `json:"AllowContainerCapabilitiesForRegularUsers"`
-		SnapshotInterval                          string               `json:"SnapshotInterval"`
-		TemplatesURL                              string               `json:"TemplatesURL"`
-		EnableHostManagementFeatures              bool                 `json:"EnableHostManagementFeatures"`
-		EdgeAgentCheckinInterval                  int                  `json:"EdgeAgentCheckinInterval"`
-		EnableEdgeComputeFeatures                 bool                 `json:"EnableEdgeComputeFeatures"`
-		UserSessionTimeout                        string               `json:"UserSessionTimeout"`
-		EnableTelemetry                           bool                 `json:"EnableTelemetry"`
+		LogoURL                                   string                    `json:"LogoURL"`
+		BlackListedLabels                         []Pair                    `json:"BlackListedLabels"`
+		HiddenContainerNames                      []string                  `json:"HiddenContainerNames"`
+		HiddenComposeProjects                     []string                  `json:"HiddenComposeProjects"`
+		FeatureFlags                              map[FeatureFlag]bool      `json:"FeatureFlags"`
+		AuthenticationMethod                      AuthenticationMethod      `json:"AuthenticationMethod"`
+		LDAPSettings                              LDAPSettings              `json:"LDAPSettings"`
+		OAuthSettings                             OAuthSettings             `json:"OAuthSettings"`
+		AllowBindMountsForRegularUsers            bool                      `json:"AllowBindMountsForRegularUsers"`
+		AllowPrivilegedModeForRegularUsers        bool                      `json:"AllowPrivilegedModeForRegularUsers"`
+		AllowVolumeBrowserForRegularUsers         bool                      `json:"AllowVolumeBrowserForRegularUsers"`
+		AllowHostNamespaceForRegularUsers         bool                      `json:"AllowHostNamespaceForRegularUsers"`
+		AllowDeviceMappingForRegularUsers         bool                      `json:"AllowDeviceMappingForRegularUsers"`
+		AllowStackManagementForRegularUsers       bool                      `json:"AllowStackManagementForRegularUsers"`
+		AllowContainerCapabilitiesForRegularUsers bool                      `json:"AllowContainerCapabilitiesForRegularUsers"`
+		SnapshotInterval                          string                    `json:"SnapshotInterval"`
+		TemplatesURL                              string                    `json:"TemplatesURL"`
+		EnableHostManagementFeatures              bool                      `json:"EnableHostManagementFeatures"`
+		EdgeAgentCheckinInterval                  int                       `json:"EdgeAgentCheckinInterval"`
+		EdgeAgentCheckinJitter                    int                       `json:"EdgeAgentCheckinJitter"`
+		EnableEdgeComputeFeatures                 bool                      `json:"EnableEdgeComputeFeatures"`
+		UserSessionTimeout                        string                    `json:"UserSessionTimeout"`
+		EnableTelemetry                           bool                      `json:"EnableTelemetry"`
+		DefaultResourceOwnershipPolicy            ResourceOwnershipPolicy   `json:"DefaultResourceOwnershipPolicy"`
+		ContainerAlerting                         ContainerAlertingSettings `json:"ContainerAlerting"`
+		HostAlerting                              HostAlertingSettings      `json:"HostAlerting"`
+		ContainerLabelPolicy                      ContainerLabelPolicy      `json:"ContainerLabelPolicy"`
+		SMTPSettings                              SMTPSettings              `json:"SMTPSettings"`
+		EmailNotifications                        EmailNotificationSettings `json:"EmailNotifications"`
+		Branding                                  BrandingSettings          `json:"Branding"`
+		Telemetry                                 TelemetrySettings         `json:"Telemetry"`
+		// DefaultLocale is the locale of the emails and of the messages sent to the users
+		// without locale when no supported locale can be negotiated from their requests
+		DefaultLocale string `json:"DefaultLocale"`
+		// EnableUserDirectoryReadThrough allows administrators to grant access to LDAP/OAuth users
+		// that never logged in through shadow users, resolved into users on their first login
+		EnableUserDirectoryReadThrough bool           `json:"EnableUserDirectoryReadThrough"`
+		PasswordPolicy                 PasswordPolicy `json:"PasswordPolicy"`
+		// EnableProxyRequestLogging logs the requests proxied to the Docker and Kubernetes APIs of the endpoints
+		EnableProxyRequestLogging bool `json:"EnableProxyRequestLogging"`
+		// EnableEdgeWaitingRoom places the Edge agents checking in for the first time in a waiting room,
+		// they cannot be managed until their endpoint is approved by an administrator
+		EnableEdgeWaitingRoom bool `json:"EnableEdgeWaitingRoom"`
+		// EdgeTunnelServerAddress is the address (host:port) of the tunnel server advertised to the Edge agents,
+		// the host of the Portainer instance URL and the port of the tunnel server are used when it is empty
+		EdgeTunnelServerAddress string `json:"EdgeTunnelServerAddress"`
+		// EdgeTunnelZones are the tunnel server addresses advertised to the Edge agents of specific network zones
+		EdgeTunnelZones []EdgeTunnelZone     `json:"EdgeTunnelZones"`
+		EdgeTunnel      EdgeTunnelSettings   `json:"EdgeTunnel"`
+		UserActivity    UserActivitySettings `json:"UserActivity"`
+		// Retention defines how long the activity log, the settings history, the tasks, the notifications
+		// and the resource samples are kept for
+		Retention RetentionSettings `json:"Retention"`
+		// HostBrowser defines the host paths the administrators can browse through the agents
+		HostBrowser HostBrowserSettings `json:"HostBrowser"`
 
 		// Deprecated fields
 		DisplayDonationHeader       bool
 		DisplayExternalContributors bool
 	}
 
+	// SettingsChange represents a change of the application settings
+	SettingsChange struct {
+		ID       SettingsChangeID `json:"Id"`
+		UserID   UserID           `json:"UserId"`
+		Username string           `json:"Username"`
+		Time     int64            `json:"Time"`
+		// RollbackOf is the identifier of the change reverted by this change
+		RollbackOf SettingsChangeID      `json:"RollbackOf,omitempty"`
+		Diff       []SettingsFieldChange `json:"Diff"`
+		Before     *Settings             `json:"Before,omitempty"`
+		After      *Settings             `json:"After,omitempty"`
+	}
+
+	// SettingsChangeID represents a settings change identifier
+	SettingsChangeID int
+
+	// SettingsFieldChange represents the change of a single settings field, the field is
+	// identified by its path in the settings object (e.g. LDAPSettings.URL)
+	SettingsFieldChange struct {
+		Field  string      `json:"Field"`
+		Before interface{} `json:"Before"`
+		After  interface{} `json:"After"`
+	}
+
+	// ShadowUser represents a LDAP/OAuth user that has not logged in yet. The accesses and team memberships
+	// it holds are granted to the user created on the first login of the matching username
+	ShadowUser struct {
+		ID                    ShadowUserID                     `json:"Id"`
+		Username              string                           `json:"Username"`
+		Role                  UserRole                         `json:"Role"`
+		EndpointAccesses      map[EndpointID]AccessPolicy      `json:"EndpointAccesses"`
+		EndpointGroupAccesses map[EndpointGroupID]AccessPolicy `json:"EndpointGroupAccesses"`
+		TeamMemberships       []ShadowUserTeamMembership       `json:"TeamMemberships"`
+		CreatedBy             string                           `json:"CreatedBy"`
+		CreationDate          int64                            `json:"CreationDate"`
+	}
+
+	// ShadowUserID represents a shadow user identifier
+	ShadowUserID int
+
+	// ShadowUserTeamMembership represents a team membership granted to a shadow user
+	ShadowUserTeamMembership struct {
+		TeamID TeamID         `json:"TeamId"`
+		Role   MembershipRole `json:"Role"`
+	}
+
 	// SnapshotJob represents a scheduled job that can create endpoint snapshots
 	SnapshotJob struct{}
 
 	// Stack represents a Docker stack created via docker stack deploy
 	Stack struct {
-		ID              StackID          `json:"Id"`
-		Name            string           `json:"Name"`
-		Type            StackType        `json:"Type"`
-		EndpointID      EndpointID       `json:"EndpointId"`
-		SwarmID         string           `json:"SwarmId"`
-		EntryPoint      string           `json:"EntryPoint"`
-		Env             []Pair           `json:"Env"`
-		ResourceControl *ResourceControl `json:"ResourceControl"`
-		Status          StackStatus      `json:"Status"`
-		ProjectPath     string
+		ID               StackID          `json:"Id"`
+		Name             string           `json:"Name"`
+		Type             StackType        `json:"Type"`
+		EndpointID       EndpointID       `json:"EndpointId"`
+		SwarmID          string           `json:"SwarmId"`
+		EntryPoint       string           `json:"EntryPoint"`
+		Env              []Pair           `json:"Env"`
+		EnvFiles         []StackEnvFile   `json:"EnvFiles"`
+		CustomTemplateID CustomTemplateID `json:"CustomTemplateId"`
+		ResourceControl  *ResourceControl `json:"ResourceControl"`
+		Status           StackStatus      `json:"Status"`
+		DeploymentDate   int64            `json:"DeploymentDate"`
+		ProjectPath      string
+	}
+
+	// StackEnvFile represents a named variable file attached to a stack. The variables it defines are
+	// available when the stack is deployed, the values of a secret file are only visible to administrators.
+	StackEnvFile struct {
+		Name   string `json:"Name"`
+		Secret bool   `json:"Secret"`
+		Path   string `json:"Path"`
 	}
 
 	// StackID represents a stack identifier (it must be composed of Name + "_" + SwarmID to create a unique identifier)
@@ -589,10 +1728,90 @@ type (
 	// TagID represents a tag identifier
 	TagID int
 
+	// Task represents a long-running operation executed in the background.
+	// The state of a task is persisted so that it can be queried after a restart of the server.
+	Task struct {
+		ID         TaskID     `json:"Id"`
+		Type       TaskType   `json:"Type"`
+		Status     TaskStatus `json:"Status"`
+		UserID     UserID     `json:"UserId"`
+		EndpointID EndpointID `json:"EndpointId"`
+		ResourceID string     `json:"ResourceId"`
+		// Progress is a percentage, it is set to -1 when the progress cannot be estimated
+		Progress int      `json:"Progress"`
+		Message  string   `json:"Message"`
+		Logs     []string `json:"Logs"`
+		// Layers reports the progress of each layer of the images pulled by the task
+		Layers   []TaskLayer `json:"Layers,omitempty"`
+		Error    string      `json:"Error"`
+		Created  int64       `json:"Created"`
+		Started  int64       `json:"Started"`
+		Finished int64       `json:"Finished"`
+	}
+
+	// TaskID represents a task identifier
+	TaskID int
+
+	// TaskLayer represents the progress of the pull of an image layer
+	TaskLayer struct {
+		ID      string `json:"Id"`
+		Status  string `json:"Status"`
+		Current int64  `json:"Current"`
+		Total   int64  `json:"Total"`
+	}
+
+	// TaskType represents the type of operation executed by a task
+	TaskType string
+
+	// TaskStatus represents the status of a task
+	TaskStatus int
+
+	// TaskFunc represents the operation executed by a task
+	TaskFunc func(reporter TaskReporter) error
+
+	// TelemetryCounters represents the feature usage counters aggregated since the last telemetry report
+	TelemetryCounters struct {
+		// Since is the time the counters were last reset
+		Since int64 `json:"Since"`
+		// LastSent is the time the last report was sent, it is 0 when no report was sent
+		LastSent int64 `json:"LastSent"`
+		// Usage counts the API operations, indexed by method and route template (e.g. POST /stacks)
+		Usage map[string]int64 `json:"Usage"`
+	}
+
+	// TelemetryMode represents the collection mode of the telemetry
+	TelemetryMode string
+
+	// TelemetryReport represents the anonymized telemetry payload. It does not contain any name,
+	// identifier or address, the instance is identified by a hash of its identifier.
+	TelemetryReport struct {
+		InstanceHash string           `json:"InstanceHash"`
+		Version      string           `json:"Version"`
+		OS           string           `json:"OS"`
+		Arch         string           `json:"Arch"`
+		Since        int64            `json:"Since"`
+		Until        int64            `json:"Until"`
+		Endpoints    map[string]int   `json:"Endpoints"`
+		Stacks       int              `json:"Stacks"`
+		Users        int              `json:"Users"`
+		Teams        int              `json:"Teams"`
+		Usage        map[string]int64 `json:"Usage"`
+	}
+
+	// TelemetrySettings represents the telemetry settings. Telemetry is opt-in, in offline mode
+	// the counters are aggregated and exposed locally but never sent.
+	TelemetrySettings struct {
+		Mode TelemetryMode `json:"Mode"`
+		// URL is the address the reports are sent to in online mode
+		URL string `json:"URL"`
+	}
+
 	// Team represents a list of user accounts
 	Team struct {
 		ID   TeamID `json:"Id"`
 		Name string `json:"Name"`
+		// OrganizationID is the organization the team belongs to
+		OrganizationID OrganizationID `json:"OrganizationId,omitempty"`
 	}
 
 	// TeamAccessPolicies represent the association of an access policy and a team
@@ -713,6 +1932,9 @@ type (
 		ID       UserID
 		Username string
 		Role     UserRole
+		// ImpersonatorID is the administrator acting as the user, it is only set on impersonation tokens
+		ImpersonatorID       UserID
+		ImpersonatorUsername string
 	}
 
 	// TunnelDetails represents information associated to a tunnel
@@ -735,6 +1957,19 @@ type (
 		Username string   `json:"Username"`
 		Password string   `json:"Password,omitempty"`
 		Role     UserRole `json:"Role"`
+		// Locale is the locale of the messages sent to the user, the locale negotiated from
+		// the Accept-Language header of the requests is used when empty
+		Locale string `json:"Locale"`
+		// PasswordHistory contains the hashes of the previous passwords of the user
+		PasswordHistory     []string `json:"PasswordHistory,omitempty"`
+		PasswordChangedDate int64    `json:"PasswordChangedDate"`
+		// PasswordChangeRequired is set when the user must change its password before using the API
+		PasswordChangeRequired bool `json:"PasswordChangeRequired"`
+		// OrganizationID is the organization the user belongs to. The administrators without
+		// organization are the administrators of the instance, managing all the organizations
+		OrganizationID OrganizationID `json:"OrganizationId,omitempty"`
+		// LastLogin is the last successful login of the user
+		LastLogin *UserLogin `json:"LastLogin,omitempty"`
 
 		// Deprecated fields
 		// Deprecated in DBVersion == 25
@@ -742,6 +1977,64 @@ type (
 		EndpointAuthorizations  EndpointAuthorizations `json:"EndpointAuthorizations"`
 	}
 
+	// UserActivity represents an entry of the activity log of a user: a login, a failed login
+	// or a request changing the state of Portainer or of an endpoint
+	UserActivity struct {
+		ID                   UserActivityID       `json:"Id"`
+		UserID               UserID               `json:"UserId"`
+		Type                 UserActivityType     `json:"Type"`
+		Date                 int64                `json:"Date"`
+		SourceIP             string               `json:"SourceIP"`
+		AuthenticationMethod AuthenticationMethod `json:"AuthenticationMethod,omitempty"`
+		Method               string               `json:"Method,omitempty"`
+		Path                 string               `json:"Path,omitempty"`
+		StatusCode           int                  `json:"StatusCode,omitempty"`
+		// Impersonator is the username of the administrator who made the request while impersonating the user
+		Impersonator string `json:"Impersonator,omitempty"`
+	}
+
+	// UserActivityID represents a user activity identifier
+	UserActivityID int
+
+	// UserActivitySettings represents the settings of the activity log of the users, the retention
+	// of the activity entries is defined in the retention settings
+	UserActivitySettings struct {
+		// DisableRequestLogging only records the logins of the users in the activity log
+		DisableRequestLogging bool `json:"DisableRequestLogging"`
+
+		// Deprecated fields
+		// Deprecated in DBVersion == 26
+		RetentionDays int `json:"RetentionDays,omitempty"`
+	}
+
+	// UserActivityType represents the type of an entry of the activity log of a user
+	UserActivityType int
+
+	// UserLogin represents a successful login of a user
+	UserLogin struct {
+		Date                 int64                `json:"Date"`
+		SourceIP             string               `json:"SourceIP"`
+		AuthenticationMethod AuthenticationMethod `json:"AuthenticationMethod"`
+	}
+
+	// UserPreferences represents the preferences of a user regarding the user interface,
+	// they are stored server-side so that they follow the user across browsers
+	UserPreferences struct {
+		UserID            UserID            `json:"UserId"`
+		Theme             UserTheme         `json:"Theme"`
+		DefaultEndpointID EndpointID        `json:"DefaultEndpointId"`
+		ItemsPerPage      int               `json:"ItemsPerPage"`
+		ConsoleKeybinding ConsoleKeybinding `json:"ConsoleKeybinding"`
+		// DismissedWarnings are the identifiers of the warnings and hints the user chose not to display again
+		DismissedWarnings []string `json:"DismissedWarnings"`
+	}
+
+	// UserTheme represents the theme of the user interface
+	UserTheme string
+
+	// ConsoleKeybinding represents the keybinding mode of the container consoles
+	ConsoleKeybinding string
+
 	// UserAccessPolicies represent the association of an access policy and a user
 	UserAccessPolicies map[UserID]AccessPolicy
 
@@ -773,6 +2066,48 @@ type (
 	// WebhookType represents the type of resource a webhook is related to
 	WebhookType int
 
+	// AlertService represents a service used to raise notifications about the status of the endpoints
+	// and about their containers and hosts based on their snapshots
+	AlertService interface {
+		ProcessSnapshot(endpoint *Endpoint, previous, current *DockerSnapshot)
+		ProcessEndpointStatus(endpoint *Endpoint, previous EndpointStatus)
+	}
+
+	// ApplicationService represents a service for managing composite application data
+	ApplicationService interface {
+		Applications() ([]Application, error)
+		Application(ID ApplicationID) (*Application, error)
+		CreateApplication(application *Application) error
+		UpdateApplication(ID ApplicationID, application *Application) error
+		DeleteApplication(ID ApplicationID) error
+		GetNextIdentifier() int
+	}
+
+	// AutoHealActionService represents a service for managing the history of the auto-heal actions
+	AutoHealActionService interface {
+		AutoHealActions(filter func(action *AutoHealAction) bool, limit int) ([]AutoHealAction, error)
+		CreateAutoHealAction(action *AutoHealAction) error
+		DeleteAutoHealActionsBefore(time int64) (int, int64, error)
+	}
+
+	// AutoHealRuleService represents a service for managing auto-heal rule data
+	AutoHealRuleService interface {
+		AutoHealRules() ([]AutoHealRule, error)
+		AutoHealRule(ID AutoHealRuleID) (*AutoHealRule, error)
+		CreateAutoHealRule(rule *AutoHealRule) error
+		UpdateAutoHealRule(ID AutoHealRuleID, rule *AutoHealRule) error
+		DeleteAutoHealRule(ID AutoHealRuleID) error
+	}
+
+	// ChangeRequestService represents a service for managing change request data
+	ChangeRequestService interface {
+		ChangeRequests() ([]ChangeRequest, error)
+		ChangeRequest(ID ChangeRequestID) (*ChangeRequest, error)
+		CreateChangeRequest(changeRequest *ChangeRequest) error
+		UpdateChangeRequest(ID ChangeRequestID, changeRequest *ChangeRequest) error
+		DeleteChangeRequest(ID ChangeRequestID) error
+	}
+
 	// CLIService represents a service for managing CLI
 	CLIService interface {
 		ParseFlags(version string) (*CLIFlags, error)
@@ -801,6 +2136,13 @@ type (
 		DeleteCustomTemplate(ID CustomTemplateID) error
 	}
 
+	// CustomTemplateRevisionService represents a service for managing the revisions of the custom templates
+	CustomTemplateRevisionService interface {
+		CustomTemplateRevisions(templateID CustomTemplateID) ([]CustomTemplateRevision, error)
+		CreateCustomTemplateRevision(revision *CustomTemplateRevision) error
+		DeleteCustomTemplateRevisions(templateID CustomTemplateID) error
+	}
+
 	// DataStore defines the interface to manage the data
 	DataStore interface {
 		Open() error
@@ -808,25 +2150,47 @@ type (
 		Close() error
 		IsNew() bool
 		MigrateData() error
+		Statistics() (*DatabaseStatistics, error)
 
+		Application() ApplicationService
+		AutoHealAction() AutoHealActionService
+		AutoHealRule() AutoHealRuleService
+		ChangeRequest() ChangeRequestService
 		DockerHub() DockerHubService
 		CustomTemplate() CustomTemplateService
+		CustomTemplateRevision() CustomTemplateRevisionService
 		EdgeGroup() EdgeGroupService
 		EdgeJob() EdgeJobService
 		EdgeStack() EdgeStackService
 		Endpoint() EndpointService
 		EndpointGroup() EndpointGroupService
 		EndpointRelation() EndpointRelationService
+		HostAction() HostActionService
+		LoadBalancerIntegration() LoadBalancerIntegrationService
+		MaintenanceWindow() MaintenanceWindowService
+		MultiEndpointStack() MultiEndpointStackService
+		NotificationChannel() NotificationChannelService
+		Organization() OrganizationService
 		Registry() RegistryService
 		ResourceControl() ResourceControlService
+		ReportSubscription() ReportSubscriptionService
+		ResourceSample() ResourceSampleService
 		Role() RoleService
+		ScalingSchedule() ScalingScheduleService
+		ShadowUser() ShadowUserService
 		Settings() SettingsService
+		SettingsHistory() SettingsHistoryService
 		Stack() StackService
+		TelemetryCounter() TelemetryCounterService
 		Tag() TagService
+		Task() TaskService
 		TeamMembership() TeamMembershipService
 		Team() TeamService
 		TunnelServer() TunnelServerService
 		User() UserService
+		UserActivity() UserActivityService
+		UserNotification() UserNotificationService
+		UserPreferences() UserPreferencesService
 		Version() VersionService
 		Webhook() WebhookService
 	}
@@ -884,6 +2248,8 @@ type (
 	EndpointService interface {
 		Endpoint(ID EndpointID) (*Endpoint, error)
 		Endpoints() ([]Endpoint, error)
+		EndpointsByGroupID(groupID EndpointGroupID) ([]Endpoint, error)
+		EndpointsByTagID(tagID TagID) ([]Endpoint, error)
 		CreateEndpoint(endpoint *Endpoint) error
 		UpdateEndpoint(ID EndpointID, endpoint *Endpoint) error
 		DeleteEndpoint(ID EndpointID) error
@@ -908,6 +2274,12 @@ type (
 		DeleteEndpointRelation(EndpointID EndpointID) error
 	}
 
+	// FeatureFlagService represents a service used to check whether experimental features are enabled
+	FeatureFlagService interface {
+		IsEnabled(flag FeatureFlag) bool
+		EnabledFlags() []FeatureFlag
+	}
+
 	// FileService represents a service for managing files
 	FileService interface {
 		GetFileContent(filePath string) ([]byte, error)
@@ -919,12 +2291,21 @@ type (
 		DeleteTLSFiles(folder string) error
 		GetStackProjectPath(stackIdentifier string) string
 		StoreStackFileFromBytes(stackIdentifier, fileName string, data []byte) (string, error)
+		StoreStackEnvFileFromBytes(stackIdentifier, name string, data []byte) (string, error)
+		DeleteStackEnvFile(stackIdentifier, name string) error
+		DeleteStackEnvFiles(stackIdentifier string) error
 		GetEdgeStackProjectPath(edgeStackIdentifier string) string
 		StoreEdgeStackFileFromBytes(edgeStackIdentifier, fileName string, data []byte) (string, error)
 		StoreRegistryManagementFileFromBytes(folder, fileName string, data []byte) (string, error)
 		KeyPairFilesExist() (bool, error)
 		StoreKeyPair(private, public []byte, privatePEMHeader, publicPEMHeader string) error
 		LoadKeyPair() ([]byte, []byte, error)
+		StoreInitToken(token string) error
+		GetInitToken() (string, error)
+		DeleteInitToken() error
+		StoreInitLock() error
+		InitLocked() (bool, error)
+		DeleteInitLock() error
 		WriteJSONToFile(path string, content interface{}) error
 		FileExists(path string) (bool, error)
 		StoreEdgeJobFileFromBytes(identifier string, data []byte) (string, error)
@@ -936,6 +2317,10 @@ type (
 		StoreCustomTemplateFileFromBytes(identifier, fileName string, data []byte) (string, error)
 		GetCustomTemplateProjectPath(identifier string) string
 		GetTemporaryPath() (string, error)
+		StoreKubeconfigFileFromBytes(folder string, data []byte) (string, error)
+		DeleteKubeconfigFile(folder string) error
+		StoreSSHKeyFileFromBytes(folder string, data []byte) (string, error)
+		DeleteSSHKeyFile(folder string) error
 	}
 
 	// GitService represents a service for managing Git
@@ -944,6 +2329,15 @@ type (
 		ClonePrivateRepositoryWithBasicAuth(repositoryURL, referenceName string, destination, username, password string) error
 	}
 
+	// HostActionService represents a service for managing host action data
+	HostActionService interface {
+		HostActions() ([]HostAction, error)
+		HostAction(ID HostActionID) (*HostAction, error)
+		CreateHostAction(action *HostAction) error
+		UpdateHostAction(ID HostActionID, action *HostAction) error
+		DeleteHostAction(ID HostActionID) error
+	}
+
 	// JWTService represents a service for managing JWT tokens
 	JWTService interface {
 		GenerateToken(data *TokenData) (string, error)
@@ -956,6 +2350,28 @@ type (
 		SetupUserServiceAccount(userID int, teamIDs []int) error
 		GetServiceAccountBearerToken(userID int) (string, error)
 		StartExecProcess(namespace, podName, containerName string, command []string, stdin io.Reader, stdout io.Writer) error
+		ScaleDeployment(namespace, name string, replicas int32) error
+		UserCanAccessNamespace(userID int, teamIDs []int, namespace string) (bool, error)
+		GetApplications(namespace string) ([]KubernetesApplication, error)
+		GetApplication(namespace string, kind KubernetesApplicationKind, name string) (*KubernetesApplication, error)
+		GetApplicationRevisions(namespace string, kind KubernetesApplicationKind, name string) ([]KubernetesApplicationRevision, error)
+		PauseApplicationRollout(namespace string, kind KubernetesApplicationKind, name string) error
+		ResumeApplicationRollout(namespace string, kind KubernetesApplicationKind, name string) error
+		UndoApplicationRollout(namespace string, kind KubernetesApplicationKind, name string, revision int64) error
+		CordonNode(name string, cordon bool) error
+		DrainNode(name string, options KubernetesDrainOptions, progress func(event KubernetesDrainEvent)) error
+		UpdateNode(name string, update KubernetesNodeUpdate) error
+		GetIngresses(namespace string) ([]KubernetesIngress, error)
+		GetIngress(namespace, name string) (*KubernetesIngress, error)
+		CreateIngress(ingress *KubernetesIngress) error
+		UpdateIngress(ingress *KubernetesIngress) error
+		DeleteIngress(namespace, name string) error
+		GetIngressClasses() ([]string, error)
+		CreateTLSSecret(namespace, name string, certificate, key []byte) error
+		CreateCertificate(certificate *KubernetesCertificate) error
+		GetPersistentVolumeClaims(namespace string) ([]KubernetesPersistentVolumeClaim, error)
+		ResizePersistentVolumeClaim(namespace, name, size string) error
+		GetOrphanedPersistentVolumes() ([]KubernetesPersistentVolume, error)
 	}
 
 	// KubernetesDeployer represents a service to deploy a manifest inside a Kubernetes endpoint
@@ -973,6 +2389,63 @@ type (
 		AuthenticateUser(username, password string, settings *LDAPSettings) error
 		TestConnectivity(settings *LDAPSettings) error
 		GetUserGroups(username string, settings *LDAPSettings) ([]string, error)
+		SearchUsers(query string, limit int, settings *LDAPSettings) ([]string, error)
+	}
+
+	// MailService represents a service used to send emails
+	MailService interface {
+		Send(settings *SMTPSettings, to []string, subject, body string) error
+	}
+
+	// LoadBalancerIntegrationService represents a service for managing the load balancer integrations of the endpoints
+	LoadBalancerIntegrationService interface {
+		LoadBalancerIntegration(endpointID EndpointID) (*LoadBalancerIntegration, error)
+		LoadBalancerIntegrations() ([]LoadBalancerIntegration, error)
+		UpdateLoadBalancerIntegration(endpointID EndpointID, integration *LoadBalancerIntegration) error
+		DeleteLoadBalancerIntegration(endpointID EndpointID) error
+	}
+
+	// MaintenanceWindowService represents a service for managing maintenance window data
+	MaintenanceWindowService interface {
+		MaintenanceWindows() ([]MaintenanceWindow, error)
+		MaintenanceWindow(ID MaintenanceWindowID) (*MaintenanceWindow, error)
+		CreateMaintenanceWindow(window *MaintenanceWindow) error
+		UpdateMaintenanceWindow(ID MaintenanceWindowID, window *MaintenanceWindow) error
+		DeleteMaintenanceWindow(ID MaintenanceWindowID) error
+	}
+
+	// LoadBalancerService represents a service registering the published services of the endpoints into
+	// external load balancers
+	LoadBalancerService interface {
+		Start()
+		Synchronize(endpointID EndpointID) error
+		Deregister(integration *LoadBalancerIntegration) error
+	}
+
+	// MultiEndpointStackService represents a service for managing multi-endpoint stack data
+	MultiEndpointStackService interface {
+		MultiEndpointStacks() ([]MultiEndpointStack, error)
+		MultiEndpointStack(ID MultiEndpointStackID) (*MultiEndpointStack, error)
+		CreateMultiEndpointStack(stack *MultiEndpointStack) error
+		UpdateMultiEndpointStack(ID MultiEndpointStackID, stack *MultiEndpointStack) error
+		DeleteMultiEndpointStack(ID MultiEndpointStackID) error
+		GetNextIdentifier() int
+	}
+
+	// NotificationService represents a service used to send notifications
+	NotificationService interface {
+		Notify(notification *Notification)
+		NotifyUser(userID UserID, notification *Notification)
+		Subscribe(userID UserID) (<-chan UserNotification, func())
+	}
+
+	// NotificationChannelService represents a service for managing notification channel data
+	NotificationChannelService interface {
+		NotificationChannels() ([]NotificationChannel, error)
+		NotificationChannel(ID NotificationChannelID) (*NotificationChannel, error)
+		CreateNotificationChannel(channel *NotificationChannel) error
+		UpdateNotificationChannel(ID NotificationChannelID, channel *NotificationChannel) error
+		DeleteNotificationChannel(ID NotificationChannelID) error
 	}
 
 	// OAuthService represents a service used to authenticate users using OAuth
@@ -980,6 +2453,30 @@ type (
 		Authenticate(code string, configuration *OAuthSettings) (string, error)
 	}
 
+	// OrganizationService represents a service for managing organization data
+	OrganizationService interface {
+		Organizations() ([]Organization, error)
+		Organization(ID OrganizationID) (*Organization, error)
+		CreateOrganization(organization *Organization) error
+		UpdateOrganization(ID OrganizationID, organization *Organization) error
+		DeleteOrganization(ID OrganizationID) error
+	}
+
+	// OrphanCleanupService represents a service used to remove the database entries
+	// referencing users, teams or Docker resources that do not exist anymore
+	OrphanCleanupService interface {
+		Start()
+		Cleanup(dryRun bool) (*OrphanCleanupReport, error)
+	}
+
+	// RetentionService represents a service used to remove the records older than their retention,
+	// periodically and on demand
+	RetentionService interface {
+		Start()
+		Purge() (*RetentionReport, error)
+		LastReport() *RetentionReport
+	}
+
 	// RegistryService represents a service for managing registry data
 	RegistryService interface {
 		Registry(ID RegistryID) (*Registry, error)
@@ -999,6 +2496,13 @@ type (
 		DeleteResourceControl(ID ResourceControlID) error
 	}
 
+	// ResourceSampleService represents a service for managing the resource reservation samples
+	ResourceSampleService interface {
+		ResourceSamples(from, to int64) ([]ResourceSample, error)
+		CreateResourceSample(sample *ResourceSample) error
+		DeleteResourceSamplesBefore(time int64) (int, int64, error)
+	}
+
 	// ReverseTunnelService represensts a service used to manage reverse tunnel connections.
 	ReverseTunnelService interface {
 		StartTunnelServer(addr, port string, snapshotService SnapshotService) error
@@ -1007,8 +2511,26 @@ type (
 		SetTunnelStatusToRequired(endpointID EndpointID) error
 		SetTunnelStatusToIdle(endpointID EndpointID)
 		GetTunnelDetails(endpointID EndpointID) *TunnelDetails
+		GetTunnelStats(endpointID EndpointID) EdgeTunnelStats
+		SetTunnelError(endpointID EndpointID, message string)
 		AddEdgeJob(endpointID EndpointID, edgeJob *EdgeJob)
 		RemoveEdgeJob(edgeJobID EdgeJobID)
+		TunnelServerStatus() error
+	}
+
+	// ReportService represents a service used to generate and send the periodic reports
+	ReportService interface {
+		Start()
+		Render(subscription *ReportSubscription, since int64) (string, string, error)
+		Send(subscription *ReportSubscription) error
+	}
+
+	// ReportSubscriptionService represents a service for managing the report subscriptions
+	ReportSubscriptionService interface {
+		ReportSubscription(userID UserID) (*ReportSubscription, error)
+		ReportSubscriptions() ([]ReportSubscription, error)
+		UpdateReportSubscription(userID UserID, subscription *ReportSubscription) error
+		DeleteReportSubscription(userID UserID) error
 	}
 
 	// RoleService represents a service for managing user roles
@@ -1019,6 +2541,22 @@ type (
 		UpdateRole(ID RoleID, role *Role) error
 	}
 
+	// ScalingScheduleService represents a service for managing scaling schedule data
+	ScalingScheduleService interface {
+		ScalingSchedules() ([]ScalingSchedule, error)
+		ScalingSchedule(ID ScalingScheduleID) (*ScalingSchedule, error)
+		CreateScalingSchedule(schedule *ScalingSchedule) error
+		UpdateScalingSchedule(ID ScalingScheduleID, schedule *ScalingSchedule) error
+		DeleteScalingSchedule(ID ScalingScheduleID) error
+	}
+
+	// ScalingScheduler represents a service used to execute scaling schedules
+	ScalingScheduler interface {
+		Start()
+		ExecuteSchedule(schedule *ScalingSchedule) error
+		Heartbeat() (time.Time, time.Duration)
+	}
+
 	// SettingsService represents a service for managing application settings
 	SettingsService interface {
 		Settings() (*Settings, error)
@@ -1030,6 +2568,24 @@ type (
 		Start() error
 	}
 
+	// SettingsHistoryService represents a service for managing the history of the settings changes
+	SettingsHistoryService interface {
+		SettingsChanges() ([]SettingsChange, error)
+		SettingsChange(ID SettingsChangeID) (*SettingsChange, error)
+		CreateSettingsChange(change *SettingsChange) error
+		DeleteSettingsChangesBefore(time int64) (int, int64, error)
+	}
+
+	// ShadowUserService represents a service for managing shadow user data
+	ShadowUserService interface {
+		ShadowUsers() ([]ShadowUser, error)
+		ShadowUser(ID ShadowUserID) (*ShadowUser, error)
+		ShadowUserByUsername(username string) (*ShadowUser, error)
+		CreateShadowUser(shadowUser *ShadowUser) error
+		UpdateShadowUser(ID ShadowUserID, shadowUser *ShadowUser) error
+		DeleteShadowUser(ID ShadowUserID) error
+	}
+
 	// StackService represents a service for managing stack data
 	StackService interface {
 		Stack(ID StackID) (*Stack, error)
@@ -1046,6 +2602,7 @@ type (
 		Start()
 		SetSnapshotInterval(snapshotInterval string) error
 		SnapshotEndpoint(endpoint *Endpoint) error
+		Heartbeat() (time.Time, time.Duration)
 	}
 
 	// SwarmStackManager represents a service to manage Swarm stacks
@@ -1065,6 +2622,45 @@ type (
 		DeleteTag(ID TagID) error
 	}
 
+	// TelemetryCounterService represents a service for managing the telemetry counters
+	TelemetryCounterService interface {
+		Counters() (*TelemetryCounters, error)
+		UpdateCounters(counters *TelemetryCounters) error
+	}
+
+	// TelemetryService represents a service used to aggregate the feature usage counters
+	// and to send the telemetry reports
+	TelemetryService interface {
+		Start()
+		SetMode(mode TelemetryMode)
+		Record(feature string)
+		Report() (*TelemetryReport, error)
+		Send() error
+	}
+
+	// TaskService represents a service for managing task data
+	TaskService interface {
+		Tasks() ([]Task, error)
+		Task(ID TaskID) (*Task, error)
+		CreateTask(task *Task) error
+		UpdateTask(ID TaskID, task *Task) error
+		DeleteTask(ID TaskID) error
+		DeleteTasksFinishedBefore(time int64) (int, int64, error)
+	}
+
+	// TaskManager represents a service used to run tasks in the background
+	TaskManager interface {
+		Run(task *Task, fn TaskFunc) error
+		Subscribe(ID TaskID) (<-chan Task, func())
+	}
+
+	// TaskReporter represents a service used by a running task to report its progress
+	TaskReporter interface {
+		SetProgress(progress int, message string)
+		SetLayer(layer TaskLayer)
+		Log(line string)
+	}
+
 	// TeamService represents a service for managing user data
 	TeamService interface {
 		Team(ID TeamID) (*Team, error)
@@ -1105,6 +2701,38 @@ type (
 		DeleteUser(ID UserID) error
 	}
 
+	// UserActivityLogger represents a service recording the logins and the activity of the users
+	UserActivityLogger interface {
+		RecordLogin(user *User, sourceIP string, method AuthenticationMethod) error
+		RecordLoginFailure(user *User, sourceIP string, method AuthenticationMethod)
+		RecordRequest(activity *UserActivity)
+	}
+
+	// UserActivityService represents a service for managing the activity log of the users
+	UserActivityService interface {
+		UserActivities(userID UserID, limit int) ([]UserActivity, error)
+		CreateUserActivity(activity *UserActivity) error
+		DeleteUserActivitiesBefore(time int64) (int, int64, error)
+	}
+
+	// UserPreferencesService represents a service for managing the preferences of the users
+	UserPreferencesService interface {
+		UserPreferences(userID UserID) (*UserPreferences, error)
+		UpdateUserPreferences(userID UserID, preferences *UserPreferences) error
+		DeleteUserPreferences(userID UserID) error
+	}
+
+	// UserNotificationService represents a service for managing the notification inbox of the users
+	UserNotificationService interface {
+		UserNotification(ID UserNotificationID) (*UserNotification, error)
+		UserNotificationsByUserID(userID UserID) ([]UserNotification, error)
+		CreateUserNotification(notification *UserNotification) error
+		UpdateUserNotification(ID UserNotificationID, notification *UserNotification) error
+		DeleteUserNotification(ID UserNotificationID) error
+		DeleteUserNotificationsByUserID(userID UserID) error
+		DeleteUserNotificationsBefore(time int64) (int, int64, error)
+	}
+
 	// VersionService represents a service for managing version data
 	VersionService interface {
 		DBVersion() (int, error)
@@ -1128,7 +2756,7 @@ const (
 	// APIVersion is the version number of the Portainer API
 	APIVersion = "2.0.0"
 	// DBVersion is the version number of the Portainer database
-	DBVersion = 25
+	DBVersion = 26
 	// AssetsServerURL represents the URL of the Portainer asset server
 	AssetsServerURL = "https://portainer-io-assets.sfo2.digitaloceanspaces.com"
 	// MessageOfTheDayURL represents the URL where Portainer MOTD message can be retrieved
@@ -1139,6 +2767,12 @@ const (
 	PortainerAgentHeader = "Portainer-Agent"
 	// PortainerAgentEdgeIDHeader represent the name of the header containing the Edge ID associated to an agent/agent cluster
 	PortainerAgentEdgeIDHeader = "X-PortainerAgent-EdgeID"
+	// PortainerAgentEdgeBundleVersionHeader represent the name of the header containing the version of the Edge bundle cached by an agent
+	PortainerAgentEdgeBundleVersionHeader = "X-PortainerAgent-EdgeBundleVersion"
+	// PortainerAgentEdgeJobsHeader represent the name of the header containing the Edge jobs known by an agent, as a list of id:version
+	PortainerAgentEdgeJobsHeader = "X-PortainerAgent-EdgeJobs"
+	// PortainerAgentTunnelErrorHeader represent the name of the header containing the last error of the tunnel client of an agent
+	PortainerAgentTunnelErrorHeader = "X-PortainerAgent-TunnelError"
 	// HTTPResponseAgentPlatform represents the name of the header containing the Agent platform
 	HTTPResponseAgentPlatform = "Portainer-Agent-Platform"
 	// PortainerAgentTargetHeader represent the name of the header containing the target node name
@@ -1149,6 +2783,9 @@ const (
 	PortainerAgentPublicKeyHeader = "X-PortainerAgent-PublicKey"
 	// PortainerAgentKubernetesSATokenHeader represent the name of the header containing a Kubernetes SA token
 	PortainerAgentKubernetesSATokenHeader = "X-PortainerAgent-SA-Token"
+	// PortainerAgentHostBrowserPathsHeader represent the name of the header containing the host paths
+	// the agent is allowed to browse, separated by commas
+	PortainerAgentHostBrowserPathsHeader = "X-PortainerAgent-HostBrowserPaths"
 	// PortainerAgentSignatureMessage represents the message used to create a digital signature
 	// to be used when communicating with an agent
 	PortainerAgentSignatureMessage = "Portainer-App"
@@ -1158,6 +2795,13 @@ const (
 	DefaultTemplatesURL = "https://raw.githubusercontent.com/portainer/templates/master/templates-2.0.json"
 	// DefaultUserSessionTimeout represents the default timeout after which the user session is cleared
 	DefaultUserSessionTimeout = "8h"
+	// DefaultItemsPerPage represents the default number of items displayed per page in the datatables
+	DefaultItemsPerPage = 10
+	// DefaultContainerAlertingDeduplicationInterval represents the default minimum duration between two notifications
+	// for the same container and event
+	DefaultContainerAlertingDeduplicationInterval = "1h"
+	// DefaultContainerLabelPrefix represents the default prefix of the labels injected on containers
+	DefaultContainerLabelPrefix = "io.portainer"
 )
 
 const (
@@ -1188,6 +2832,32 @@ const (
 	EdgeJobLogsStatusCollected
 )
 
+const (
+	_ ChangeRequestStatus = iota
+	// ChangeRequestPending represents a change waiting for a review
+	ChangeRequestPending
+	// ChangeRequestApproved represents an approved change being executed
+	ChangeRequestApproved
+	// ChangeRequestRejected represents a rejected change, it is never executed
+	ChangeRequestRejected
+	// ChangeRequestExecuted represents an approved change executed successfully
+	ChangeRequestExecuted
+	// ChangeRequestFailed represents an approved change whose execution failed
+	ChangeRequestFailed
+)
+
+const (
+	_ ChangeRequestType = iota
+	// ChangeRequestStackDeployment represents the deployment of a new stack
+	ChangeRequestStackDeployment
+	// ChangeRequestStackUpdate represents the update of an existing stack
+	ChangeRequestStackUpdate
+	// ChangeRequestContainerDeletion represents the deletion of one or several containers
+	ChangeRequestContainerDeletion
+	// ChangeRequestStackDeletion represents the deletion of a stack and of its resources
+	ChangeRequestStackDeletion
+)
+
 const (
 	_ CustomTemplatePlatform = iota
 	// CustomTemplatePlatformLinux represents a custom template for linux
@@ -1196,6 +2866,16 @@ const (
 	CustomTemplatePlatformWindows
 )
 
+const (
+	_ CustomTemplatePublicationStatus = iota
+	// CustomTemplatePublicationPending represents a publication waiting for the review of an administrator
+	CustomTemplatePublicationPending
+	// CustomTemplatePublicationApproved represents a publication approved by an administrator
+	CustomTemplatePublicationApproved
+	// CustomTemplatePublicationRejected represents a publication rejected by an administrator
+	CustomTemplatePublicationRejected
+)
+
 const (
 	_ EdgeStackStatusType = iota
 	//StatusOk represents a successfully deployed edge stack
@@ -1206,6 +2886,18 @@ const (
 	StatusAcknowledged
 )
 
+const (
+	_ EdgeStackRolloutStatus = iota
+	// EdgeStackRolloutInProgress represents a rollout waiting for the status of the endpoints of the current batch
+	EdgeStackRolloutInProgress
+	// EdgeStackRolloutPaused represents a rollout waiting for an administrator to continue or roll back
+	EdgeStackRolloutPaused
+	// EdgeStackRolloutCompleted represents a rollout deployed on all the endpoints
+	EdgeStackRolloutCompleted
+	// EdgeStackRolloutRolledBack represents a rollout reverted to the previous version of the stack
+	EdgeStackRolloutRolledBack
+)
+
 const (
 	_ EndpointExtensionType = iota
 	// StoridgeEndpointExtension represents the Storidge extension
@@ -1236,6 +2928,8 @@ const (
 	AgentOnKubernetesEnvironment
 	// EdgeAgentOnKubernetesEnvironment represents an endpoint connected to an Edge agent deployed on a Kubernetes environment
 	EdgeAgentOnKubernetesEnvironment
+	// KubeconfigKubernetesEnvironment represents an endpoint connected to a Kubernetes API using the credentials of a kubeconfig
+	KubeconfigKubernetesEnvironment
 )
 
 const (
@@ -1244,6 +2938,27 @@ const (
 	SnapshotJobType = 2
 )
 
+const (
+	_ KubernetesAuthenticationMethod = iota
+	// KubernetesAuthenticationToken represents a bearer token authentication
+	KubernetesAuthenticationToken
+	// KubernetesAuthenticationCertificate represents a client certificate authentication
+	KubernetesAuthenticationCertificate
+	// KubernetesAuthenticationExec represents an authentication using a credential plugin (e.g. aws eks get-token, gke-gcloud-auth-plugin, kubelogin)
+	KubernetesAuthenticationExec
+	// KubernetesAuthenticationOIDC represents an authentication using the OIDC authentication provider
+	KubernetesAuthenticationOIDC
+)
+
+const (
+	// KubernetesApplicationDeployment represents an application backed by a Deployment
+	KubernetesApplicationDeployment KubernetesApplicationKind = "Deployment"
+	// KubernetesApplicationStatefulSet represents an application backed by a StatefulSet
+	KubernetesApplicationStatefulSet KubernetesApplicationKind = "StatefulSet"
+	// KubernetesApplicationDaemonSet represents an application backed by a DaemonSet
+	KubernetesApplicationDaemonSet KubernetesApplicationKind = "DaemonSet"
+)
+
 const (
 	_ MembershipRole = iota
 	// TeamLeader represents a leader role inside a team
@@ -1252,6 +2967,61 @@ const (
 	TeamMember
 )
 
+const (
+	// SlackNotificationChannel represents a Slack incoming webhook
+	SlackNotificationChannel NotificationChannelType = "slack"
+	// TeamsNotificationChannel represents a Microsoft Teams incoming webhook
+	TeamsNotificationChannel NotificationChannelType = "teams"
+	// PagerDutyNotificationChannel represents a PagerDuty service integrated with the Events API v2
+	PagerDutyNotificationChannel NotificationChannelType = "pagerduty"
+	// WebhookNotificationChannel represents a generic webhook receiving the notifications as JSON
+	WebhookNotificationChannel NotificationChannelType = "webhook"
+)
+
+const (
+	// TraefikLoadBalancerIntegration represents a dynamic configuration file watched by the Traefik file provider
+	TraefikLoadBalancerIntegration LoadBalancerIntegrationType = "traefik"
+	// ConsulLoadBalancerIntegration represents the service catalog of a Consul agent
+	ConsulLoadBalancerIntegration LoadBalancerIntegrationType = "consul"
+	// WebhookLoadBalancerIntegration represents a generic webhook receiving the registered services as JSON
+	WebhookLoadBalancerIntegration LoadBalancerIntegrationType = "webhook"
+)
+
+const (
+	// InfoNotificationSeverity represents a notification that does not require any action
+	InfoNotificationSeverity NotificationSeverity = "info"
+	// WarningNotificationSeverity represents a notification that may require an action
+	WarningNotificationSeverity NotificationSeverity = "warning"
+	// CriticalNotificationSeverity represents a notification that requires an immediate action
+	CriticalNotificationSeverity NotificationSeverity = "critical"
+)
+
+const (
+	_ NotificationType = iota
+	// ContainerUnhealthyNotification represents a container that became unhealthy
+	ContainerUnhealthyNotification
+	// ContainerRestartLoopNotification represents a container stuck in a restart loop
+	ContainerRestartLoopNotification
+	// HostDiskPressureNotification represents a host filesystem used above the configured threshold
+	HostDiskPressureNotification
+	// HostMemoryPressureNotification represents a host memory used above the configured threshold
+	HostMemoryPressureNotification
+	// HostLoadNotification represents a host load average above the configured threshold
+	HostLoadNotification
+	// EndpointDownNotification represents an endpoint that became unreachable
+	EndpointDownNotification
+	// EndpointUpNotification represents an endpoint that became reachable again
+	EndpointUpNotification
+	// JobFailedNotification represents a scheduled job that failed
+	JobFailedNotification
+	// StackDeployedNotification represents a stack deployment that succeeded
+	StackDeployedNotification
+	// StackDeploymentFailedNotification represents a stack deployment that failed
+	StackDeploymentFailedNotification
+	// ContainerAutoHealedNotification represents an unhealthy container restarted by an auto-heal rule
+	ContainerAutoHealedNotification
+)
+
 const (
 	_ RegistryType = iota
 	// QuayRegistry represents a Quay.io registry
@@ -1270,6 +3040,60 @@ const (
 	ReadWriteAccessLevel
 )
 
+const (
+	// DailyReport represents a report sent every day
+	DailyReport ReportFrequency = "daily"
+	// WeeklyReport represents a report sent every week
+	WeeklyReport ReportFrequency = "weekly"
+)
+
+const (
+	// TelemetryDisabled disables the telemetry, no counter is aggregated
+	TelemetryDisabled TelemetryMode = "disabled"
+	// TelemetryOffline aggregates the counters and only exposes them locally
+	TelemetryOffline TelemetryMode = "offline"
+	// TelemetryOnline aggregates the counters and sends them periodically
+	TelemetryOnline TelemetryMode = "online"
+)
+
+const (
+	// LightTheme represents the default light theme of the user interface
+	LightTheme UserTheme = "light"
+	// DarkTheme represents the dark theme of the user interface
+	DarkTheme UserTheme = "dark"
+	// HighContrastTheme represents the high contrast theme of the user interface
+	HighContrastTheme UserTheme = "highcontrast"
+	// AutoTheme represents a theme following the preference of the operating system
+	AutoTheme UserTheme = "auto"
+)
+
+const (
+	// DefaultConsoleKeybinding represents the default keybindings of the container consoles
+	DefaultConsoleKeybinding ConsoleKeybinding = "default"
+	// VimConsoleKeybinding represents the vim keybindings
+	VimConsoleKeybinding ConsoleKeybinding = "vim"
+	// EmacsConsoleKeybinding represents the emacs keybindings
+	EmacsConsoleKeybinding ConsoleKeybinding = "emacs"
+)
+
+const (
+	// EndpointHealthReportSection reports the endpoints that are down
+	EndpointHealthReportSection ReportSection = "endpoint_health"
+	// ContainerHealthReportSection reports the stopped and unhealthy containers
+	ContainerHealthReportSection ReportSection = "container_health"
+	// HostPressureReportSection reports the Docker hosts under resource pressure
+	HostPressureReportSection ReportSection = "host_pressure"
+	// SettingsChangesReportSection reports the changes of the settings
+	SettingsChangesReportSection ReportSection = "settings_changes"
+)
+
+const (
+	// ContainerResourceReservation represents the reservation of a standalone container
+	ContainerResourceReservation ResourceReservationType = "container"
+	// ServiceResourceReservation represents the reservation of a Swarm service
+	ServiceResourceReservation ResourceReservationType = "service"
+)
+
 const (
 	_ ResourceControlType = iota
 	// ContainerResourceControl represents a resource control associated to a Docker container
@@ -1290,6 +3114,24 @@ const (
 	CustomTemplateResourceControl
 )
 
+const (
+	_ ResourceOwnershipPolicy = iota
+	// ResourceOwnershipPolicyPrivate restricts access to a new resource to its creator
+	ResourceOwnershipPolicyPrivate
+	// ResourceOwnershipPolicyTeam grants access to a new resource to the teams of its creator
+	ResourceOwnershipPolicyTeam
+	// ResourceOwnershipPolicyPublic grants access to a new resource to every user
+	ResourceOwnershipPolicyPublic
+)
+
+const (
+	_ ScalingTargetType = iota
+	// ScalingTargetSwarmService represents a Swarm service
+	ScalingTargetSwarmService
+	// ScalingTargetKubernetesDeployment represents a Kubernetes deployment
+	ScalingTargetKubernetesDeployment
+)
+
 const (
 	_ StackType = iota
 	// DockerSwarmStack represents a stack managed via docker stack
@@ -1300,6 +3142,53 @@ const (
 	KubernetesStack
 )
 
+const (
+	_ TaskStatus = iota
+	// TaskPending represents a task waiting to be executed
+	TaskPending
+	// TaskRunning represents a task being executed
+	TaskRunning
+	// TaskSucceeded represents a task that completed successfully
+	TaskSucceeded
+	// TaskFailed represents a task that completed with an error
+	TaskFailed
+	// TaskInterrupted represents a task that was stopped by a restart of the server
+	TaskInterrupted
+)
+
+const (
+	// ImagePullTask represents the pull of an image
+	ImagePullTask TaskType = "image_pull"
+	// StackDeployTask represents the deployment of a stack
+	StackDeployTask TaskType = "stack_deploy"
+	// MultiEndpointStackDeployTask represents the deployment of a multi-endpoint stack on its targets
+	MultiEndpointStackDeployTask TaskType = "multi_endpoint_stack_deploy"
+	// ApplicationDeployTask represents the deployment of the components of a composite application
+	ApplicationDeployTask TaskType = "application_deploy"
+)
+
+const (
+	_ ApplicationComponentStatus = iota
+	// ApplicationComponentPending represents a component waiting for its deployment
+	ApplicationComponentPending
+	// ApplicationComponentDeployed represents a component that was successfully deployed
+	ApplicationComponentDeployed
+	// ApplicationComponentFailed represents a component the deployment failed for
+	ApplicationComponentFailed
+	// ApplicationComponentSkipped represents a component not deployed because a previous component failed
+	ApplicationComponentSkipped
+)
+
+const (
+	_ MultiEndpointStackTargetStatus = iota
+	// MultiEndpointStackTargetPending represents a target waiting for the deployment of the stack
+	MultiEndpointStackTargetPending
+	// MultiEndpointStackTargetDeployed represents a target the stack was successfully deployed on
+	MultiEndpointStackTargetDeployed
+	// MultiEndpointStackTargetFailed represents a target the deployment of the stack failed on
+	MultiEndpointStackTargetFailed
+)
+
 // StackStatus represents a status for a stack
 const (
 	_ StackStatus = iota
@@ -1336,6 +3225,34 @@ const (
 	StandardUserRole
 )
 
+const (
+	// RetentionUserActivity represents the activity log of the users
+	RetentionUserActivity RetentionCategory = "user_activity"
+	// RetentionSettingsHistory represents the history of the settings changes
+	RetentionSettingsHistory RetentionCategory = "settings_history"
+	// RetentionTasks represents the finished tasks
+	RetentionTasks RetentionCategory = "tasks"
+	// RetentionNotifications represents the notifications of the users
+	RetentionNotifications RetentionCategory = "notifications"
+	// RetentionResourceSamples represents the resource reservation samples recorded with the snapshots
+	RetentionResourceSamples RetentionCategory = "resource_samples"
+	// RetentionAutoHealActions represents the history of the auto-heal actions
+	RetentionAutoHealActions RetentionCategory = "autoheal_actions"
+)
+
+// RetentionForever is the retention of the categories whose records are never purged
+const RetentionForever = -1
+
+const (
+	_ UserActivityType = iota
+	// UserActivityLogin represents a successful login
+	UserActivityLogin
+	// UserActivityLoginFailure represents a login attempt with invalid credentials
+	UserActivityLoginFailure
+	// UserActivityRequest represents a request changing the state of Portainer or of an endpoint
+	UserActivityRequest
+)
+
 const (
 	_ WebhookType = iota
 	// ServiceWebhook is a webhook for restarting a docker service